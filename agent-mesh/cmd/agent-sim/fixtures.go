@@ -0,0 +1,155 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed fixtures/*.json
+var builtinFixtures embed.FS
+
+// Fixture is the canned behaviour of one simulated agent type
+type Fixture struct {
+	AgentType    string           `json:"agent_type" yaml:"agent_type"`
+	Capabilities []string         `json:"capabilities" yaml:"capabilities"` // Registered with the MCP Server
+	Responses    []CannedResponse `json:"responses" yaml:"responses"`       // First match wins
+	Default      *CannedResponse  `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// CannedResponse is returned for requests whose task and input context match
+type CannedResponse struct {
+	Task        string                 `json:"task" yaml:"task"`                     // Task (intent) to match, "*" or empty for any
+	When        map[string]interface{} `json:"when,omitempty" yaml:"when,omitempty"` // Input context values that must match
+	Status      string                 `json:"status" yaml:"status"`
+	Result      map[string]interface{} `json:"result" yaml:"result"` // {{request_id}}, {{task}}, {{now}} and {{input.<key>}} are substituted
+	RiskScore   float64                `json:"risk_score" yaml:"risk_score"`
+	Explanation string                 `json:"explanation" yaml:"explanation"`
+	Confidence  float64                `json:"confidence" yaml:"confidence"`
+	LatencyMs   int                    `json:"latency_ms,omitempty" yaml:"latency_ms,omitempty"` // Overrides -latency for this response
+}
+
+// loadFixture reads a fixture file (JSON, or YAML by .yaml/.yml extension), or the
+// built-in fixture for the agent type when path is empty
+func loadFixture(path, agentType string) (*Fixture, error) {
+	var data []byte
+	var err error
+	format := "json"
+	if path == "" {
+		data, err = builtinFixtures.ReadFile("fixtures/" + strings.ToLower(agentType) + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("no built-in fixture for agent type %s, pass -fixtures", agentType)
+		}
+	} else {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+	}
+
+	var fixture Fixture
+	if format == "yaml" {
+		err = yaml.Unmarshal(data, &fixture)
+	} else {
+		err = json.Unmarshal(data, &fixture)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	if fixture.AgentType == "" {
+		fixture.AgentType = agentType
+	}
+	if !strings.EqualFold(fixture.AgentType, agentType) {
+		return nil, fmt.Errorf("fixture is for agent type %s, not %s", fixture.AgentType, agentType)
+	}
+	if len(fixture.Responses) == 0 && fixture.Default == nil {
+		return nil, fmt.Errorf("fixture for %s has no responses", agentType)
+	}
+	for i, resp := range fixture.Responses {
+		if resp.Status == "" {
+			return nil, fmt.Errorf("fixture response %d (%s) has no status", i, resp.Task)
+		}
+	}
+	return &fixture, nil
+}
+
+// match returns the first canned response for the task and input context, or the default
+func (f *Fixture) match(task string, input map[string]interface{}) *CannedResponse {
+	for i := range f.Responses {
+		resp := &f.Responses[i]
+		if resp.Task != "" && resp.Task != "*" && !strings.EqualFold(resp.Task, task) {
+			continue
+		}
+		if matchesInput(resp.When, input) {
+			return resp
+		}
+	}
+	return f.Default
+}
+
+// matchesInput compares values as strings, so fixtures need not match JSON number types
+func matchesInput(when, input map[string]interface{}) bool {
+	for key, want := range when {
+		got, ok := input[key]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// render substitutes request values into a canned result. A string that is exactly one
+// placeholder takes the value's own type, so "{{input.amount}}" stays a number.
+func render(value interface{}, vars map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = render(item, vars)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = render(item, vars)
+		}
+		return out
+	case string:
+		if strings.HasPrefix(v, "{{") && strings.HasSuffix(v, "}}") && strings.Count(v, "{{") == 1 {
+			if val, ok := vars[strings.TrimSpace(v[2:len(v)-2])]; ok {
+				return val
+			}
+			return v
+		}
+		for name, val := range vars {
+			v = strings.ReplaceAll(v, "{{"+name+"}}", fmt.Sprint(val))
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// templateVars are the values a canned result can refer to
+func templateVars(task, requestID, sessionID string, input map[string]interface{}) map[string]interface{} {
+	vars := map[string]interface{}{
+		"task":       task,
+		"request_id": requestID,
+		"session_id": sessionID,
+		"now":        time.Now().Format(time.RFC3339),
+	}
+	for key, val := range input {
+		vars["input."+key] = val
+	}
+	return vars
+}
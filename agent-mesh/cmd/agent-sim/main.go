@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/aibanking/agent-mesh/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultPorts are the ports the real agents listen on
+var defaultPorts = map[string]string{
+	"BANKING":   "8001",
+	"FRAUD":     "8002",
+	"GUARDRAIL": "8003",
+	"CLEARANCE": "8004",
+	"SCORING":   "8005",
+	"INSIGHTS":  "8006",
+}
+
+// Agent simulator for development. It stands in for any agent type, answering
+// /api/v1/process with canned responses from a fixture after a configurable latency,
+// failing a configurable share of requests, and registering with the MCP Server like
+// a real agent, so the MCP Server can be run without the five real agents.
+func main() {
+	agentType := flag.String("type", env("AGENT_TYPE", "BANKING"), "Agent type to simulate")
+	port := flag.String("port", "", "Port to listen on (the real agent's port by default)")
+	name := flag.String("name", "", "Agent name registered with the MCP Server")
+	endpoint := flag.String("endpoint", "", "Endpoint registered with the MCP Server (http://localhost:<port> by default)")
+	fixturePath := flag.String("fixtures", "", "Fixture file (JSON or YAML); the built-in fixture for the type is used when empty")
+	latency := flag.Duration("latency", 50*time.Millisecond, "Simulated processing latency")
+	jitter := flag.Duration("jitter", 0, "Random latency added on top of -latency, up to this much")
+	failureRate := flag.Float64("failure-rate", 0, "Share of requests that fail, 0 to 1")
+	failureMode := flag.String("failure-mode", "error", "How requests fail: error (HTTP 500), timeout (no answer until the caller gives up) or reject (REJECTED response)")
+	register := flag.Bool("register", true, "Register with the MCP Server on startup")
+	mcpURL := flag.String("mcp-url", env("MCP_SERVER_URL", "http://localhost:8080"), "MCP Server base URL")
+	apiKey := flag.String("api-key", env("MCP_SERVER_API_KEY", "test-api-key"), "MCP Server API key")
+	seed := flag.Int64("seed", 0, "Random seed for failures and jitter (time-based when 0)")
+	flag.Parse()
+
+	utils.InitLogger(env("LOGGING_LEVEL", "info"), "console")
+
+	*agentType = strings.ToUpper(strings.TrimSpace(*agentType))
+	if *failureRate < 0 || *failureRate > 1 {
+		log.Fatal().Float64("failure_rate", *failureRate).Msg("-failure-rate must be between 0 and 1")
+	}
+	switch *failureMode {
+	case "error", "timeout", "reject":
+	default:
+		log.Fatal().Str("failure_mode", *failureMode).Msg("-failure-mode must be error, timeout or reject")
+	}
+
+	fixture, err := loadFixture(*fixturePath, *agentType)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load fixture")
+	}
+
+	if *port == "" {
+		*port = defaultPorts[*agentType]
+		if *port == "" {
+			*port = "8101"
+		}
+	}
+	if *name == "" {
+		*name = "Simulated " + *agentType + " Agent"
+	}
+	if *endpoint == "" {
+		*endpoint = fmt.Sprintf("http://localhost:%s", *port)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	sim := &simulator{
+		agentType:   *agentType,
+		fixture:     fixture,
+		latency:     *latency,
+		jitter:      *jitter,
+		failureRate: *failureRate,
+		failureMode: *failureMode,
+		rng:         rand.New(rand.NewSource(*seed)),
+		byTask:      make(map[string]int),
+	}
+
+	if *register {
+		base := service.NewAgentBase(*agentType, *name, *endpoint, &config.MCPServerConfig{
+			BaseURL: *mcpURL,
+			APIKey:  *apiKey,
+			Timeout: 5,
+		})
+		if err := base.RegisterWithMCP(context.Background(), fixture.Capabilities); err != nil {
+			log.Warn().Err(err).Msg("Failed to register simulated agent, continuing anyway")
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", sim.health)
+	mux.HandleFunc("/api/v1/health", sim.health)
+	mux.HandleFunc("/api/v1/process", sim.process)
+	mux.HandleFunc("/api/v1/warmup", sim.warmup)
+	mux.HandleFunc("/api/v1/sim/stats", sim.stats)
+
+	server := &http.Server{
+		Addr:    ":" + *port,
+		Handler: mux,
+	}
+
+	go func() {
+		log.Info().
+			Str("address", server.Addr).
+			Str("agent_type", *agentType).
+			Strs("capabilities", fixture.Capabilities).
+			Dur("latency", *latency).
+			Float64("failure_rate", *failureRate).
+			Str("failure_mode", *failureMode).
+			Msg("Agent simulator started")
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("Failed to start agent simulator")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(shutdownCtx)
+}
+
+// simulator answers agent requests from a fixture
+type simulator struct {
+	agentType   string
+	fixture     *Fixture
+	latency     time.Duration
+	jitter      time.Duration
+	failureRate float64
+	failureMode string
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	requests  int
+	failures  int
+	unmatched int
+	byTask    map[string]int
+}
+
+func (s *simulator) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "healthy",
+		"agent_type": s.agentType,
+		"simulated":  true,
+	})
+}
+
+// process handles POST /api/v1/process
+func (s *simulator) process(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req model.AgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	canned := s.fixture.match(req.Task, req.InputContext)
+	fail, delay := s.roll(req.Task, canned == nil)
+	if canned != nil && canned.LatencyMs > 0 {
+		delay += time.Duration(canned.LatencyMs)*time.Millisecond - s.latency
+	}
+
+	if fail && s.failureMode == "timeout" {
+		// Hold the request until the caller gives up
+		<-r.Context().Done()
+		return
+	}
+
+	select {
+	case <-time.After(delay):
+	case <-r.Context().Done():
+		return
+	}
+
+	if fail && s.failureMode == "error" {
+		writeError(w, http.StatusInternalServerError, "Failed to process request", errors.New("simulated agent failure"))
+		return
+	}
+	if canned == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Unsupported task", fmt.Errorf("no canned response for task %s", req.Task))
+		return
+	}
+
+	vars := templateVars(req.Task, req.RequestID, req.SessionID, req.InputContext)
+	result, _ := render(canned.Result, vars).(map[string]interface{})
+	response := &model.AgentResponse{
+		AgentID:     s.agentType,
+		AgentType:   s.agentType,
+		Status:      canned.Status,
+		Result:      result,
+		RiskScore:   canned.RiskScore,
+		Explanation: fmt.Sprint(render(canned.Explanation, vars)),
+		Confidence:  canned.Confidence,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+		Diagnostics: &model.AgentDiagnostics{
+			ProcessingMs: float64(delay.Microseconds()) / 1000,
+			FallbackUsed: true,
+			Fallbacks:    []string{"agent:simulated"},
+		},
+	}
+	if fail {
+		// reject mode: a well-formed refusal, as a real agent would send
+		response.Status = "REJECTED"
+		response.Result = map[string]interface{}{"status": "REJECTED", "reason": "simulated rejection"}
+		response.RiskScore = 1
+		response.Explanation = "Simulated rejection"
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// warmup handles POST /api/v1/warmup. The simulator has no downstream services, so
+// there is nothing to warm.
+func (s *simulator) warmup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, &model.WarmupResponse{
+		AgentType: s.agentType,
+		WarmedAt:  time.Now(),
+		Targets:   []model.WarmupTarget{},
+	})
+}
+
+// roll counts the request and decides whether it fails and how long it takes
+func (s *simulator) roll(task string, unmatched bool) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.byTask[task]++
+	if unmatched {
+		s.unmatched++
+	}
+	fail := s.failureRate > 0 && s.rng.Float64() < s.failureRate
+	if fail {
+		s.failures++
+	}
+	delay := s.latency
+	if s.jitter > 0 {
+		delay += time.Duration(s.rng.Int63n(int64(s.jitter) + 1))
+	}
+	return fail, delay
+}
+
+// stats handles GET /api/v1/sim/stats
+func (s *simulator) stats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"agent_type":   s.agentType,
+		"requests":     s.requests,
+		"failures":     s.failures,
+		"unmatched":    s.unmatched,
+		"by_task":      s.byTask,
+		"latency_ms":   s.latency.Milliseconds(),
+		"jitter_ms":    s.jitter.Milliseconds(),
+		"failure_rate": s.failureRate,
+		"failure_mode": s.failureMode,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the real agents' error shape
+func writeError(w http.ResponseWriter, code int, message string, err error) {
+	writeJSON(w, code, map[string]interface{}{
+		"error":   message,
+		"code":    code,
+		"details": err.Error(),
+	})
+}
+
+func env(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
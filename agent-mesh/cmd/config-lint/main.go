@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/shared/profile"
+)
+
+// Prints the effective configuration an agent would start with, where each
+// value came from, and the settings that are missing or unsafe for the environment.
+// Exits 1 when the service would refuse to start.
+func main() {
+	profile.Lint("agent-mesh", func() (string, []profile.Setting, []profile.Problem, error) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return cfg.Environment, config.Profile().Settings(), cfg.Validate(), nil
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+)
+
+// Runs rule scenario suites against guardrail rule packs and the fraud rules, so a
+// new pack or changed threshold can be checked against the scenario library before it
+// is rolled out. Exits 1 when a scenario fails and 2 when the packs or scenarios
+// cannot be loaded.
+func main() {
+	scenarios := flag.String("scenarios", "rule-scenarios", "Comma-separated scenario files or directories (JSON or YAML)")
+	packs := flag.String("packs", "", "Comma-separated guardrail rule pack files to check; the built-in RBI pack by default")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	flag.Parse()
+
+	rules, err := service.LoadGuardrailRules(splitList(*packs))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	suites, err := service.LoadRuleScenarios(splitList(*scenarios))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(suites) == 0 {
+		fmt.Fprintln(os.Stderr, "no rule scenarios found")
+		os.Exit(2)
+	}
+
+	report := service.NewRuleScenarioRunner(rules).Run(suites)
+	if *asJSON {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport prints each scenario's outcome, with what differed for those that failed
+func printReport(report *model.RuleScenarioReport) {
+	for _, result := range report.Results {
+		mark := "ok  "
+		if !result.Passed {
+			mark = "FAIL"
+		}
+		outcome := result.Status
+		if len(result.FailedRules) > 0 {
+			outcome += " (" + strings.Join(result.FailedRules, ", ") + ")"
+		}
+		if result.Score != nil {
+			outcome += fmt.Sprintf(" score %.2f", *result.Score)
+		}
+		fmt.Printf("%s %s/%s: %s\n", mark, result.Suite, result.Scenario, outcome)
+		for _, mismatch := range result.Mismatches {
+			fmt.Printf("       %s\n", mismatch)
+		}
+	}
+	fmt.Printf("\n%d scenarios, %d passed, %d failed\n", report.Scenarios, report.Passed, report.Failed)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
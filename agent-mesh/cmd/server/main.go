@@ -16,10 +16,17 @@ import (
 	"github.com/aibanking/agent-mesh/internal/router"
 	"github.com/aibanking/agent-mesh/internal/service"
 	"github.com/aibanking/agent-mesh/internal/utils"
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/tz"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	// Store and send every time in UTC, whatever time zone the host runs in
+	tz.UseUTC()
+
 	// Initialize configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -28,6 +35,15 @@ func main() {
 
 	utils.InitLogger(cfg.Logging.Level, cfg.Logging.Format)
 
+	// Fail fast on settings that are missing or unsafe for the environment
+	problems := cfg.Validate()
+	for _, p := range problems {
+		log.Warn().Str("setting", p.Key).Str("severity", p.Severity).Msg(p.Message)
+	}
+	if profile.HasErrors(problems) {
+		log.Fatal().Str("environment", cfg.Environment).Msg("Invalid configuration, run cmd/config-lint for details")
+	}
+
 	// Trim whitespace from agent type to handle trailing spaces
 	agentType := strings.TrimSpace(cfg.Agent.Type)
 	agentName := strings.TrimSpace(cfg.Agent.Name)
@@ -41,31 +57,106 @@ func main() {
 
 	// Create agent base
 	agentBase := service.NewAgentBase(agentType, agentName, endpoint, &cfg.MCPServer)
+	agentBase.SetTenant(cfg.Agent.TenantID)
 
 	// Create specific agent based on type
 	var agentProcessor service.ProcessRequest
 	var capabilities []string
+	var fraudController *controller.FraudController
+	var guardrailController *controller.GuardrailController
+	var scenarioController *controller.RuleScenarioController
+	var outboxController *controller.OutboxController
+	var outbox *service.Outbox
+
+	// The warmer pings the downstream services each agent calls, see POST /api/v1/warmup
+	warmer := service.NewWarmer(agentType, cfg.Agent.WarmupCooldown)
 
 	switch agentType {
 	case "BANKING":
-		agentProcessor = service.NewBankingAgent(agentBase)
-		capabilities = []string{"TRANSFER_NEFT", "TRANSFER_RTGS", "TRANSFER_IMPS", "TRANSFER_UPI", "CHECK_BALANCE", "GET_STATEMENT", "ADD_BENEFICIARY"}
+		preferences := service.NewPreferenceClient(&cfg.Banking)
+		paymentRequests := service.NewPaymentRequestClient(&cfg.Banking)
+		warmer.Add("banking:preferences", preferences)
+		receipts := service.NewReceiptClient(&cfg.Banking)
+		warmer.Add("banking:payment_requests", paymentRequests)
+		warmer.Add("banking:receipts", receipts)
+		exports := service.NewExportClient(&cfg.Banking)
+		warmer.Add("banking:exports", exports)
+		budgets := service.NewBudgetClient(&cfg.Banking)
+		warmer.Add("banking:budgets", budgets)
+		// Transfers are recorded in the outbox before they are sent, see OUTBOX_DIR
+		if cfg.Outbox.Enabled {
+			transfers := service.NewTransferClient(&cfg.Banking)
+			warmer.Add("banking:transfer", transfers)
+			if outbox, err = service.NewOutbox(&cfg.Outbox, transfers); err != nil {
+				log.Fatal().Err(err).Msg("Failed to open transfer outbox")
+			}
+			outboxController = controller.NewOutboxController(outbox)
+		}
+		agentProcessor = service.NewBankingAgent(agentBase, preferences, paymentRequests, receipts, exports, budgets, outbox)
+		capabilities = []string{"TRANSFER_NEFT", "TRANSFER_RTGS", "TRANSFER_IMPS", "TRANSFER_UPI", "CHECK_BALANCE", "GET_STATEMENT", "ADD_BENEFICIARY", "LIST_BENEFICIARIES", "REQUEST_MONEY", "SHARE_RECEIPT", "EXPORT_TRANSACTIONS", "SET_BUDGET", "BUDGET_STATUS"}
 	case "FRAUD":
-		agentProcessor = service.NewFraudAgent(agentBase)
+		scorer := newModelScorer(cfg)
+		addModelScorer(warmer, cfg, scorer)
+		fraudDecisions := service.NewFraudDecisionStore(cfg.Fraud.DecisionLimit)
+		fraudAgent := service.NewFraudAgent(agentBase, scorer, fraudDecisions)
+		if cfg.Fraud.ReportSignals {
+			accountStatus := service.NewAccountStatusClient(&cfg.Banking)
+			warmer.Add("banking:fraud_signals", accountStatus)
+			fraudAgent.SetAccountStatus(accountStatus)
+		}
+		var transferGraph *service.TransferGraph
+		if cfg.Fraud.GraphEnabled {
+			dwh := service.NewDWHClient(&cfg.Banking)
+			warmer.Add("banking:dwh", dwh)
+			transferGraph = service.NewTransferGraph(&cfg.Fraud, dwh)
+			fraudAgent.SetTransferGraph(transferGraph)
+		}
+		agentProcessor = fraudAgent
+		fraudController = controller.NewFraudController(fraudDecisions, service.NewFraudRescorer(fraudAgent, fraudDecisions), transferGraph)
+		scenarioController = newScenarioController(cfg, agentType, nil)
 		capabilities = []string{"FRAUD_CHECK", "RISK_ASSESSMENT"}
 	case "GUARDRAIL":
-		agentProcessor = service.NewGuardrailAgent(agentBase)
+		rules, err := service.LoadGuardrailRules(cfg.Guardrail.RulePacks)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load guardrail rule packs")
+		}
+		calendar := service.NewCalendarClient(&cfg.Banking)
+		warmer.Add("banking:calendar", calendar)
+		accountStatus := service.NewAccountStatusClient(&cfg.Banking)
+		warmer.Add("banking:account_status", accountStatus)
+		guardrailAgent := service.NewGuardrailAgent(agentBase, rules, calendar)
+		guardrailAgent.SetAccountStatus(accountStatus)
+		agentProcessor = guardrailAgent
+		guardrailController = controller.NewGuardrailController(rules)
+		scenarioController = newScenarioController(cfg, agentType, rules)
 		capabilities = []string{"GUARDRAIL_CHECK", "RULE_VALIDATION", "RBI_COMPLIANCE"}
+	case "INSIGHTS":
+		dwh := service.NewDWHClient(&cfg.Banking)
+		warmer.Add("banking:dwh", dwh)
+		agentProcessor = service.NewInsightsAgent(agentBase, &cfg.Insights, dwh)
+		capabilities = []string{"GET_INSIGHTS"}
 	case "CLEARANCE":
 		agentProcessor = service.NewClearanceAgent(agentBase)
 		capabilities = []string{"LOAN_APPROVAL", "CLEARANCE_DECISION"}
 	case "SCORING":
-		agentProcessor = service.NewScoringAgent(agentBase)
+		scorer := newModelScorer(cfg)
+		addModelScorer(warmer, cfg, scorer)
+		agentProcessor = service.NewScoringAgent(agentBase, scorer)
 		capabilities = []string{"CREDIT_SCORE", "FRAUD_SCORE", "RISK_SCORE"}
 	default:
 		log.Fatal().Str("agent_type", agentType).Msg("Unknown agent type")
 	}
 
+	// In demo mode, tasks playing a scenario get the outcome it scripts for this agent
+	if cfg.Demo.Enabled {
+		scenarios, err := demo.Load(cfg.Demo.ScenariosDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load demo scenarios")
+		}
+		agentProcessor = service.NewDemoAgent(agentProcessor, agentType, scenarios)
+		log.Warn().Int("scenarios", len(scenarios.List())).Msg("Demo mode is on; tasks playing a demo scenario get scripted outcomes")
+	}
+
 	// Register with MCP Server if enabled
 	if cfg.Agent.AutoRegister {
 		ctx := context.Background()
@@ -76,12 +167,19 @@ func main() {
 
 	// Initialize controller
 	agentController := controller.NewAgentController(agentProcessor, agentType)
+	warmupController := controller.NewWarmupController(warmer)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter()
+	recovery := middleware.NewRecovery(cfg.Recovery)
+	auditLogger, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatal().Err(err).Str("sink", cfg.Audit.Sink).Msg("Failed to open access log")
+	}
+	accessLog := middleware.NewAccessLog(auditLogger, cfg.Security.APIKeyHeader)
 
 	// Initialize router
-	appRouter := router.NewRouter(agentController, rateLimiter)
+	appRouter := router.NewRouter(agentController, fraudController, guardrailController, scenarioController, outboxController, warmupController, rateLimiter, recovery, accessLog)
 	r := appRouter.SetupRoutes()
 
 	// Create HTTP server - ensure port is trimmed
@@ -107,6 +205,18 @@ func main() {
 		}
 	}()
 
+	// Re-read the secrets provider for rotated secrets
+	secretsCtx, stopSecrets := context.WithCancel(context.Background())
+	defer stopSecrets()
+	go config.Profile().WatchSecrets(secretsCtx, cfg.Secrets.RefreshInterval)
+
+	// Settle transfers left unconfirmed by a crash, now and then periodically
+	if outbox != nil {
+		sweepCtx, stopSweeps := context.WithCancel(context.Background())
+		defer stopSweeps()
+		go outbox.Run(sweepCtx)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -122,6 +232,40 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// After the server, so the last requests it finished are recorded
+	if err := auditLogger.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close access log")
+	}
+
 	log.Info().Msg("Agent exited")
 }
 
+
+// newModelScorer loads the model registry for agents that score with ML models
+func newModelScorer(cfg *config.Config) *service.ModelScorer {
+	registry, err := service.LoadModelRegistry(cfg.ML.RegistryFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load model registry")
+	}
+	if cfg.ML.BaseURL == "" {
+		log.Warn().Msg("ML_SERVICE_URL not set, scoring with rules only")
+	}
+	return service.NewModelScorer(registry, &cfg.ML)
+}
+
+// newScenarioController loads the rule scenario library the agent runs on request.
+// Suites for other agents are left out; an invalid suite stops the agent.
+func newScenarioController(cfg *config.Config, agentType string, rules *service.GuardrailRules) *controller.RuleScenarioController {
+	library, err := service.LoadRuleScenarios(cfg.Agent.RuleScenarios)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load rule scenarios")
+	}
+	return controller.NewRuleScenarioController(service.NewRuleScenarioRunner(rules), agentType, library)
+}
+
+// addModelScorer warms the ML service when one is configured
+func addModelScorer(warmer *service.Warmer, cfg *config.Config, scorer *service.ModelScorer) {
+	if cfg.ML.BaseURL != "" {
+		warmer.Add("ml", scorer)
+	}
+}
@@ -1,21 +1,37 @@
 package config
 
 import (
-	"log"
-	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/recovery"
+	"github.com/aibanking/shared/secrets"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for agents
+
 type Config struct {
-	Server    ServerConfig
-	MCPServer MCPServerConfig
-	Agent     AgentConfig
-	Logging   LoggingConfig
-	Security  SecurityConfig
+	Environment string // dev, staging or prod; see Validate
+	Server      ServerConfig
+	MCPServer   MCPServerConfig
+	Banking     BankingIntegrationsConfig
+	Outbox      OutboxConfig
+	ML          MLConfig
+	Fraud       FraudConfig
+	Insights    InsightsConfig
+	Guardrail   GuardrailConfig
+	Agent       AgentConfig
+	Logging     LoggingConfig
+	Recovery    recovery.Config
+	Security    SecurityConfig
+	Secrets     secrets.Config
+	Audit       audit.Config
+	Demo        demo.Config
 }
 
 // ServerConfig holds server-related configuration
@@ -34,13 +50,88 @@ type MCPServerConfig struct {
 	Timeout int
 }
 
+// BankingIntegrationsConfig holds Banking Integrations (Layer 5) connection configuration
+type BankingIntegrationsConfig struct {
+	BaseURL string
+	APIKey  string
+	Timeout int
+	Replay  ReplayConfig
+}
+
+// OutboxConfig holds how the Banking Agent sends transfers to Banking Integrations.
+// Each is recorded in the outbox before it is sent and confirmed once answered; a
+// recovery sweep settles those left unconfirmed by a crash by their idempotency key.
+type OutboxConfig struct {
+	Enabled        bool   // Send transfers to Banking Integrations; off approves them in the agent as a mock
+	Dir            string // Where entries are kept, one JSON file each
+	SweepInterval  int    // Seconds between recovery sweeps
+	ReconcileAfter int    // Seconds an entry is left unconfirmed before the sweep looks it up
+	RetentionHours int    // Settled entries are removed after this long
+}
+
+// MLConfig holds ML service (Layer 4) and model registry configuration
+type MLConfig struct {
+	BaseURL      string // Empty disables ML calls; agents score with their rules
+	Timeout      int
+	RegistryFile string // Optional model registry JSON; the v1 models are used otherwise
+	Replay       ReplayConfig
+
+	// Failover: once the recent calls fail or run slow past a threshold, agents score
+	// with rules for the cool-down, then probe the ML service before going back
+	HealthWindow    int     // Recent calls the health is judged on
+	HealthMinCalls  int     // Calls in the window before the health is judged
+	MaxErrorRate    float64 // Share of failed calls that fails over, 0 to 1
+	MaxLatencyMs    int     // Average call latency that fails over
+	CooldownSeconds int     // Time scored with rules before probing
+	RecoveryProbes  int     // Probe calls in a row that must succeed to go back to ML
+}
+
+// ReplayConfig records downstream responses to fixture files, or answers from them
+type ReplayConfig struct {
+	Mode         string   // off, record or replay
+	Dir          string   // Fixture directory for this downstream service
+	IgnoreFields []string // JSON request body fields left out of the match, e.g. timestamps
+}
+
+// FraudConfig holds Fraud Agent configuration
+type FraudConfig struct {
+	DecisionLimit int  // Decisions kept for labeling; the oldest are dropped first
+	ReportSignals bool // Report rejections to Banking Integrations, which may freeze the account
+
+	// Transfer graph: sender→beneficiary edges from the DWH, scored for mule patterns
+	GraphEnabled        bool
+	GraphWindowDays     int // Days of transfers the graph covers
+	GraphRefreshMinutes int // A sender's edges are read from the DWH again after this long
+	GraphFanIn          int // Distinct senders to one beneficiary that count as a mule pattern
+	GraphFanOut         int // Distinct beneficiaries of one sender that count as fanning out
+	GraphClusterHours   int // A beneficiary first paid within this is new
+	GraphClusterSenders int // Distinct senders to a new beneficiary that make it a cluster
+}
+
+// InsightsConfig holds Insights Agent configuration
+type InsightsConfig struct {
+	HistoryDays  int     // Days of transactions spending patterns are drawn from
+	BufferMonths float64 // Months of spending kept in savings before suggesting a sweep
+	MinSweep     float64 // Smallest sweep worth suggesting
+	FDRate       float64 // Fixed deposit interest rate, percent a year
+	SavingsRate  float64 // Savings account interest rate, percent a year
+}
+
+// GuardrailConfig holds Guardrail Agent configuration
+type GuardrailConfig struct {
+	RulePacks []string // Rule pack files (JSON or YAML); the built-in RBI pack is used when empty
+}
+
 // AgentConfig holds agent-specific configuration
 type AgentConfig struct {
-	Type         string // BANKING, FRAUD, GUARDRAIL, CLEARANCE, SCORING
-	Name         string
-	Endpoint     string
-	Capabilities []string
-	AutoRegister bool
+	Type           string // BANKING, FRAUD, GUARDRAIL, CLEARANCE, SCORING, INSIGHTS
+	Name           string
+	Endpoint       string
+	Capabilities   []string
+	AutoRegister   bool
+	WarmupCooldown int      // Seconds a warmup result is reused before downstream services are pinged again
+	TenantID       string   // Tenant the agent is dedicated to; empty to join the shared pool
+	RuleScenarios  []string // Rule scenario files or directories the Guardrail and Fraud Agents run on request
 }
 
 // LoggingConfig holds logging configuration
@@ -60,26 +151,88 @@ var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	// Load .env.<APP_ENV> and .env if they exist
+	settings = profile.New()
+	environment := settings.LoadEnvironment(godotenv.Read, godotenv.Load)
+	if err := settings.LoadSecrets(); err != nil {
+		return nil, err
 	}
 
 	viper.SetDefault("SERVER_PORT", "8001")
 	viper.SetDefault("SERVER_HOST", "0.0.0.0")
 	viper.SetDefault("MCP_SERVER_URL", "http://localhost:8080")
 	viper.SetDefault("MCP_SERVER_API_KEY", "test-api-key")
+	viper.SetDefault("BANKING_INTEGRATIONS_URL", "http://localhost:7000")
+	viper.SetDefault("BANKING_INTEGRATIONS_API_KEY", "test-api-key")
+	viper.SetDefault("ML_SERVICE_URL", "")
+	viper.SetDefault("MODEL_REGISTRY_FILE", "")
+	viper.SetDefault("ML_HEALTH_WINDOW", "20")
+	viper.SetDefault("ML_HEALTH_MIN_CALLS", "5")
+	viper.SetDefault("ML_HEALTH_MAX_ERROR_RATE", "0.5")
+	viper.SetDefault("ML_HEALTH_MAX_LATENCY_MS", "2000")
+	viper.SetDefault("ML_FAILOVER_COOLDOWN_SECONDS", "60")
+	viper.SetDefault("ML_RECOVERY_PROBES", "3")
+	viper.SetDefault("FRAUD_DECISION_LIMIT", "100000")
+	viper.SetDefault("FRAUD_REPORT_SIGNALS", "true")
+	viper.SetDefault("FRAUD_GRAPH_ENABLED", "true")
+	viper.SetDefault("FRAUD_GRAPH_WINDOW_DAYS", "30")
+	viper.SetDefault("FRAUD_GRAPH_REFRESH_MINUTES", "15")
+	viper.SetDefault("FRAUD_GRAPH_FAN_IN", "5")
+	viper.SetDefault("FRAUD_GRAPH_FAN_OUT", "10")
+	viper.SetDefault("FRAUD_GRAPH_CLUSTER_HOURS", "48")
+	viper.SetDefault("FRAUD_GRAPH_CLUSTER_SENDERS", "3")
+	viper.SetDefault("GUARDRAIL_RULE_PACKS", "")
+	viper.SetDefault("INSIGHTS_HISTORY_DAYS", "90")
+	viper.SetDefault("INSIGHTS_BUFFER_MONTHS", "2")
+	viper.SetDefault("INSIGHTS_MIN_SWEEP", "25000")
+	viper.SetDefault("INSIGHTS_FD_RATE", "7.0")
+	viper.SetDefault("INSIGHTS_SAVINGS_RATE", "3.0")
+	viper.SetDefault("REPLAY_MODE", "off")
+	viper.SetDefault("REPLAY_DIR", "testdata/replay")
+	viper.SetDefault("REPLAY_IGNORE_FIELDS", "timestamp")
 	viper.SetDefault("AGENT_TYPE", "BANKING")
 	viper.SetDefault("AGENT_NAME", "Banking Agent")
 	viper.SetDefault("AGENT_ENDPOINT", "http://localhost:8001")
 	viper.SetDefault("AGENT_AUTO_REGISTER", "true")
+	viper.SetDefault("AGENT_WARMUP_COOLDOWN", "60")
+	viper.SetDefault("AGENT_TENANT_ID", "")
+	viper.SetDefault("RULE_SCENARIOS", "")
 	viper.SetDefault("LOGGING_LEVEL", "info")
 	viper.SetDefault("LOGGING_FORMAT", "json")
+	viper.SetDefault("RECOVERY_EXPOSE_DETAILS", "false")
+	viper.SetDefault("RECOVERY_STACK_LOG_INTERVAL", "300")
+	viper.SetDefault("RECOVERY_KEEP_FINGERPRINTS", "100")
 	viper.SetDefault("SECURITY_API_KEY_HEADER", "X-API-Key")
 	viper.SetDefault("SECURITY_RATE_LIMIT_RPS", "100")
+	viper.SetDefault("SECRETS_PROVIDER", "env")
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "300")
+	viper.SetDefault("AUDIT_SINK", "file")
+	viper.SetDefault("AUDIT_DIR", "audit")
+	viper.SetDefault("AUDIT_RETENTION_DAYS", "365")
+	viper.SetDefault("AUDIT_REDIS_ADDR", "localhost:6379")
+	viper.SetDefault("DEMO_MODE", "false")
+	viper.SetDefault("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios")
 
 	viper.AutomaticEnv()
 
+	replayDir := getEnv("REPLAY_DIR", "testdata/replay")
+	replay := func(service string) ReplayConfig {
+		return ReplayConfig{
+			Mode:         strings.ToLower(getEnv("REPLAY_MODE", "off")),
+			Dir:          filepath.Join(replayDir, service),
+			IgnoreFields: splitList(getEnv("REPLAY_IGNORE_FIELDS", "timestamp")),
+		}
+	}
+
+	// Each agent type runs as its own process, so each keeps its own access log
+	auditService := "agent-mesh-" + strings.ToLower(strings.TrimSpace(getEnv("AGENT_TYPE", "BANKING")))
+
 	AppConfig = &Config{
+		Environment: environment,
+		Secrets: secrets.Config{
+			Provider:        settings.SecretsProvider(),
+			RefreshInterval: getEnvInt("SECRETS_REFRESH_INTERVAL", 300),
+		},
 		Server: ServerConfig{
 			Port:         strings.TrimSpace(getEnv("SERVER_PORT", "8001")),
 			Host:         strings.TrimSpace(getEnv("SERVER_HOST", "0.0.0.0")),
@@ -92,31 +245,118 @@ func LoadConfig() (*Config, error) {
 			APIKey:  getEnv("MCP_SERVER_API_KEY", "test-api-key"),
 			Timeout: 30,
 		},
+		Banking: BankingIntegrationsConfig{
+			BaseURL: getEnv("BANKING_INTEGRATIONS_URL", "http://localhost:7000"),
+			APIKey:  getEnv("BANKING_INTEGRATIONS_API_KEY", "test-api-key"),
+			Timeout: 5,
+			Replay:  replay("banking"),
+		},
+		Outbox: OutboxConfig{
+			Enabled:        getEnv("BANKING_TRANSFERS_ENABLED", "false") == "true",
+			Dir:            getEnv("OUTBOX_DIR", "outbox"),
+			SweepInterval:  getEnvInt("OUTBOX_SWEEP_INTERVAL", 30),
+			ReconcileAfter: getEnvInt("OUTBOX_RECONCILE_AFTER", 60),
+			RetentionHours: getEnvInt("OUTBOX_RETENTION_HOURS", 72),
+		},
+		ML: MLConfig{
+			BaseURL:      getEnv("ML_SERVICE_URL", ""),
+			Timeout:      5,
+			RegistryFile: getEnv("MODEL_REGISTRY_FILE", ""),
+			Replay:       replay("ml"),
+
+			HealthWindow:    getEnvInt("ML_HEALTH_WINDOW", 20),
+			HealthMinCalls:  getEnvInt("ML_HEALTH_MIN_CALLS", 5),
+			MaxErrorRate:    getEnvFloat("ML_HEALTH_MAX_ERROR_RATE", 0.5),
+			MaxLatencyMs:    getEnvInt("ML_HEALTH_MAX_LATENCY_MS", 2000),
+			CooldownSeconds: getEnvInt("ML_FAILOVER_COOLDOWN_SECONDS", 60),
+			RecoveryProbes:  getEnvInt("ML_RECOVERY_PROBES", 3),
+		},
+		Fraud: FraudConfig{
+			DecisionLimit: getEnvInt("FRAUD_DECISION_LIMIT", 100000),
+			ReportSignals: getEnv("FRAUD_REPORT_SIGNALS", "true") == "true",
+
+			GraphEnabled:        getEnv("FRAUD_GRAPH_ENABLED", "true") == "true",
+			GraphWindowDays:     getEnvInt("FRAUD_GRAPH_WINDOW_DAYS", 30),
+			GraphRefreshMinutes: getEnvInt("FRAUD_GRAPH_REFRESH_MINUTES", 15),
+			GraphFanIn:          getEnvInt("FRAUD_GRAPH_FAN_IN", 5),
+			GraphFanOut:         getEnvInt("FRAUD_GRAPH_FAN_OUT", 10),
+			GraphClusterHours:   getEnvInt("FRAUD_GRAPH_CLUSTER_HOURS", 48),
+			GraphClusterSenders: getEnvInt("FRAUD_GRAPH_CLUSTER_SENDERS", 3),
+		},
+		Insights: InsightsConfig{
+			HistoryDays:  getEnvInt("INSIGHTS_HISTORY_DAYS", 90),
+			BufferMonths: getEnvFloat("INSIGHTS_BUFFER_MONTHS", 2),
+			MinSweep:     getEnvFloat("INSIGHTS_MIN_SWEEP", 25000),
+			FDRate:       getEnvFloat("INSIGHTS_FD_RATE", 7.0),
+			SavingsRate:  getEnvFloat("INSIGHTS_SAVINGS_RATE", 3.0),
+		},
+		Guardrail: GuardrailConfig{
+			RulePacks: splitList(getEnv("GUARDRAIL_RULE_PACKS", "")),
+		},
 		Agent: AgentConfig{
-			Type:         strings.TrimSpace(getEnv("AGENT_TYPE", "BANKING")),
-			Name:         strings.TrimSpace(getEnv("AGENT_NAME", "Banking Agent")),
-			Endpoint:     strings.TrimSpace(getEnv("AGENT_ENDPOINT", "http://localhost:8001")),
-			Capabilities: []string{}, // Will be set based on agent type
-			AutoRegister: getEnv("AGENT_AUTO_REGISTER", "true") == "true",
+			Type:           strings.TrimSpace(getEnv("AGENT_TYPE", "BANKING")),
+			Name:           strings.TrimSpace(getEnv("AGENT_NAME", "Banking Agent")),
+			Endpoint:       strings.TrimSpace(getEnv("AGENT_ENDPOINT", "http://localhost:8001")),
+			Capabilities:   []string{}, // Will be set based on agent type
+			AutoRegister:   getEnv("AGENT_AUTO_REGISTER", "true") == "true",
+			WarmupCooldown: getEnvInt("AGENT_WARMUP_COOLDOWN", 60),
+			TenantID:       strings.TrimSpace(getEnv("AGENT_TENANT_ID", "")),
+			RuleScenarios:  splitList(getEnv("RULE_SCENARIOS", "")),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOGGING_LEVEL", "info"),
 			Format: getEnv("LOGGING_FORMAT", "json"),
 		},
+		Recovery: recovery.Config{
+			ExposeDetails:    getEnv("RECOVERY_EXPOSE_DETAILS", "false") == "true",
+			StackLogInterval: getEnvInt("RECOVERY_STACK_LOG_INTERVAL", 300),
+			KeepFingerprints: getEnvInt("RECOVERY_KEEP_FINGERPRINTS", 100),
+		},
 		Security: SecurityConfig{
 			APIKeyHeader: getEnv("SECURITY_API_KEY_HEADER", "X-API-Key"),
 			JWTSecret:    getEnv("SECURITY_JWT_SECRET", "your-secret-key"),
 			RateLimitRPS: 100,
 		},
+		Audit: audit.Config{
+			Service:       auditService,
+			Sink:          strings.ToLower(getEnv("AUDIT_SINK", audit.SinkFile)),
+			Dir:           getEnv("AUDIT_DIR", "audit"),
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			RedisAddr:     getEnv("AUDIT_REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("AUDIT_REDIS_PASSWORD", ""),
+			RedisStream:   getEnv("AUDIT_REDIS_STREAM", "audit:access:"+auditService),
+			SyslogNetwork: getEnv("AUDIT_SYSLOG_NETWORK", ""),
+			SyslogAddr:    getEnv("AUDIT_SYSLOG_ADDR", ""),
+			SigningKey:    getEnv("AUDIT_SIGNING_KEY", ""),
+		},
+		Demo: demo.Config{
+			Enabled:      getEnv("DEMO_MODE", "false") == "true",
+			ScenariosDir: getEnv("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios"),
+		},
 	}
 
 	return AppConfig, nil
 }
 
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+	return settings.Get(key, defaultValue)
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	return settings.GetInt(key, defaultValue)
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	return settings.GetFloat(key, defaultValue)
 }
 
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/aibanking/shared/profile"
+)
+
+// settings holds every value LoadConfig read, by key
+var settings = profile.New()
+
+// Profile returns what LoadConfig read: every setting and where it came from, for
+// config-lint, and the secrets provider, whose rotations the service's clients follow
+func Profile() *profile.Profile {
+	return settings
+}
+
+// Validate checks the configuration against its environment. Errors should stop the
+// agent from starting; warnings are logged.
+func (c *Config) Validate() []profile.Problem {
+	v := profile.NewValidator(settings, c.Environment)
+	v.Required("MCP_SERVER_URL", "MCP_SERVER_API_KEY", "AGENT_ENDPOINT")
+	switch c.Agent.Type {
+	case "BANKING", "GUARDRAIL", "INSIGHTS":
+		v.Required("BANKING_INTEGRATIONS_URL", "BANKING_INTEGRATIONS_API_KEY")
+		v.Secrets("BANKING_INTEGRATIONS_API_KEY")
+	case "FRAUD", "SCORING":
+		if c.ML.BaseURL == "" && v.Strict() {
+			v.Add("ML_SERVICE_URL", profile.SeverityWarning, "not set; the agent scores with rules only")
+		}
+	}
+	if c.ML.HealthWindow < 1 || c.ML.HealthMinCalls < 1 || c.ML.HealthMinCalls > c.ML.HealthWindow {
+		v.Add("ML_HEALTH_MIN_CALLS", profile.SeverityError, fmt.Sprintf("must be between 1 and ML_HEALTH_WINDOW (%d), got %d", c.ML.HealthWindow, c.ML.HealthMinCalls))
+	}
+	if c.ML.MaxErrorRate <= 0 || c.ML.MaxErrorRate > 1 {
+		v.Add("ML_HEALTH_MAX_ERROR_RATE", profile.SeverityError, fmt.Sprintf("must be above 0 and at most 1, got %g", c.ML.MaxErrorRate))
+	}
+	if c.ML.MaxLatencyMs < 1 {
+		v.Add("ML_HEALTH_MAX_LATENCY_MS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.ML.MaxLatencyMs))
+	}
+	if c.ML.CooldownSeconds < 1 {
+		v.Add("ML_FAILOVER_COOLDOWN_SECONDS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.ML.CooldownSeconds))
+	}
+	if c.ML.RecoveryProbes < 1 {
+		v.Add("ML_RECOVERY_PROBES", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.ML.RecoveryProbes))
+	}
+	if c.Agent.Type == "FRAUD" && c.Fraud.GraphEnabled {
+		v.Required("BANKING_INTEGRATIONS_URL", "BANKING_INTEGRATIONS_API_KEY")
+		if c.Fraud.GraphWindowDays < 1 {
+			v.Add("FRAUD_GRAPH_WINDOW_DAYS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Fraud.GraphWindowDays))
+		}
+		if c.Fraud.GraphFanIn < 2 {
+			v.Add("FRAUD_GRAPH_FAN_IN", profile.SeverityError, fmt.Sprintf("must be at least 2 senders, got %d", c.Fraud.GraphFanIn))
+		}
+		if c.Fraud.GraphFanOut < 2 {
+			v.Add("FRAUD_GRAPH_FAN_OUT", profile.SeverityError, fmt.Sprintf("must be at least 2 beneficiaries, got %d", c.Fraud.GraphFanOut))
+		}
+		if c.Fraud.GraphClusterSenders < 2 {
+			v.Add("FRAUD_GRAPH_CLUSTER_SENDERS", profile.SeverityError, fmt.Sprintf("must be at least 2 senders, got %d", c.Fraud.GraphClusterSenders))
+		}
+		if c.Fraud.GraphClusterHours < 1 || c.Fraud.GraphClusterHours > c.Fraud.GraphWindowDays*24 {
+			v.Add("FRAUD_GRAPH_CLUSTER_HOURS", profile.SeverityError, fmt.Sprintf("must be from 1 to FRAUD_GRAPH_WINDOW_DAYS in hours, got %d", c.Fraud.GraphClusterHours))
+		}
+	}
+	if c.Agent.Type == "BANKING" && c.Outbox.Enabled {
+		if c.Outbox.Dir == "" {
+			v.Add("OUTBOX_DIR", profile.SeverityError, "is required with BANKING_TRANSFERS_ENABLED; transfers must be recorded before they are sent")
+		}
+		if c.Outbox.SweepInterval < 1 {
+			v.Add("OUTBOX_SWEEP_INTERVAL", profile.SeverityError, fmt.Sprintf("must be at least 1 second, got %d", c.Outbox.SweepInterval))
+		}
+		if c.Outbox.ReconcileAfter <= c.Banking.Timeout {
+			v.Add("OUTBOX_RECONCILE_AFTER", profile.SeverityError, fmt.Sprintf("must be longer than the %d-second Banking Integrations timeout, got %d", c.Banking.Timeout, c.Outbox.ReconcileAfter))
+		}
+		if c.Outbox.RetentionHours < 1 {
+			v.Add("OUTBOX_RETENTION_HOURS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Outbox.RetentionHours))
+		}
+	} else if c.Agent.Type == "BANKING" && v.Strict() {
+		v.Add("BANKING_TRANSFERS_ENABLED", profile.SeverityWarning, "is off; transfers are approved by the agent without moving money")
+	}
+	v.URLs("MCP_SERVER_URL", "AGENT_ENDPOINT", "BANKING_INTEGRATIONS_URL", "ML_SERVICE_URL")
+	v.Secrets("MCP_SERVER_API_KEY")
+
+	switch c.Banking.Replay.Mode {
+	case "off":
+	case "record", "replay":
+		if v.Strict() {
+			v.Add("REPLAY_MODE", v.Severity(profile.SeverityWarning, profile.SeverityError), fmt.Sprintf("is %s; downstream calls use fixture files", c.Banking.Replay.Mode))
+		}
+	default:
+		v.Add("REPLAY_MODE", v.Severity(profile.SeverityError, profile.SeverityError), fmt.Sprintf("unknown mode %q (use off, record or replay)", c.Banking.Replay.Mode))
+	}
+	if c.Recovery.ExposeDetails && v.Strict() {
+		v.Add("RECOVERY_EXPOSE_DETAILS", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; panic messages, which may hold customer data, are sent to callers")
+	}
+	if c.Recovery.KeepFingerprints < 1 {
+		v.Add("RECOVERY_KEEP_FINGERPRINTS", profile.SeverityError, "must be at least 1")
+	}
+	v.Placeholders("SECURITY_JWT_SECRET")
+	v.AuditSink(c.Audit)
+	v.DemoMode(c.Demo)
+	v.SecretSources(c.Secrets)
+	return v.Problems()
+}
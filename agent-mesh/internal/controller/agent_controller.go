@@ -43,6 +43,12 @@ func (ac *AgentController) ProcessRequest(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// A result that does not match its intent's schema is the agent's fault, not the caller's
+	if err := service.ConformResult(req.Task, response); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Agent result does not match its schema", err)
+		return
+	}
+
 	respondWithJSON(w, http.StatusOK, response)
 }
 
@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// FraudController handles labeling of the Fraud Agent's past decisions, the metrics
+// and training data built from the labels, shadow re-scoring of the decisions and
+// inspection of the transfer graph
+type FraudController struct {
+	decisions *service.FraudDecisionStore
+	rescorer  *service.FraudRescorer
+	graph     *service.TransferGraph // Nil when the transfer graph is off
+}
+
+// NewFraudController creates a new fraud controller
+func NewFraudController(decisions *service.FraudDecisionStore, rescorer *service.FraudRescorer, graph *service.TransferGraph) *FraudController {
+	return &FraudController{
+		decisions: decisions,
+		rescorer:  rescorer,
+		graph:     graph,
+	}
+}
+
+// LabelDecision handles POST /fraud/decisions/{requestID}/label
+func (fc *FraudController) LabelDecision(w http.ResponseWriter, r *http.Request) {
+	var req model.FraudLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	req.Label = strings.ToUpper(strings.TrimSpace(req.Label))
+
+	decision, err := fc.decisions.Label(mux.Vars(r)["requestID"], &req)
+	if errors.Is(err, service.ErrDecisionNotFound) {
+		respondWithError(w, http.StatusNotFound, "Fraud decision not found", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid label", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, decision)
+}
+
+// GetDecision handles GET /fraud/decisions/{requestID}
+func (fc *FraudController) GetDecision(w http.ResponseWriter, r *http.Request) {
+	decision, ok := fc.decisions.Get(mux.Vars(r)["requestID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Fraud decision not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, decision)
+}
+
+// ListDecisions handles GET /fraud/decisions
+func (fc *FraudController) ListDecisions(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeWindow(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid time window", err)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	decisions := fc.decisions.List(from, to, r.URL.Query().Get("labeled"), limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"decisions": decisions,
+		"count":     len(decisions),
+	})
+}
+
+// GetMetrics handles GET /fraud/metrics
+func (fc *FraudController) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeWindow(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid time window", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fc.decisions.Metrics(from, to, r.URL.Query().Get("period")))
+}
+
+// ExportTrainingData handles GET /fraud/training-data. It returns the labeled
+// decisions as CSV, one column per feature, or as JSON with format=json.
+func (fc *FraudController) ExportTrainingData(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeWindow(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid time window", err)
+		return
+	}
+
+	rows := fc.decisions.TrainingData(from, to)
+	if r.URL.Query().Get("format") == "json" {
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"rows":  rows,
+			"count": len(rows),
+		})
+		return
+	}
+
+	featureSet := make(map[string]bool)
+	for _, row := range rows {
+		for name := range row.Features {
+			featureSet[name] = true
+		}
+	}
+	features := make([]string, 0, len(featureSet))
+	for name := range featureSet {
+		features = append(features, name)
+	}
+	sort.Strings(features)
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="fraud_training_data.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	header := []string{"request_id", "decided_at", "user_id", "tenant_id", "model_version", "model_source", "fraud_score", "status", "flags"}
+	header = append(header, features...)
+	out.Write(append(header, "is_fraud"))
+
+	for _, row := range rows {
+		modelVersion, modelSource := "", ""
+		if row.Model != nil {
+			modelVersion = row.Model.Name + "@" + row.Model.Version
+			modelSource = row.Model.Source
+		}
+
+		record := []string{
+			row.RequestID,
+			row.DecidedAt.Format(time.RFC3339),
+			row.UserID,
+			row.TenantID,
+			modelVersion,
+			modelSource,
+			strconv.FormatFloat(row.FraudScore, 'f', -1, 64),
+			row.Status,
+			strings.Join(row.Flags, ";"),
+		}
+		for _, name := range features {
+			value := ""
+			if v, ok := row.Features[name]; ok {
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			record = append(record, value)
+		}
+
+		isFraud := "0"
+		if row.Label.Label == model.FraudLabelFraud {
+			isFraud = "1"
+		}
+		out.Write(append(record, isFraud))
+	}
+	out.Flush()
+}
+
+// StartRescore handles POST /fraud/rescore?from=&to=. It re-scores the decisions made
+// in the window with the current model and thresholds in the background and answers
+// 202 with the run; the body may name who asked, {"requested_by": "..."}.
+func (fc *FraudController) StartRescore(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeWindow(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid time window", err)
+		return
+	}
+	var req model.RescoreRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+	req.From, req.To = from, to
+
+	run, err := fc.rescorer.Start(&req)
+	if errors.Is(err, service.ErrRescoreInProgress) {
+		respondWithError(w, http.StatusConflict, "Re-scoring already running", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to start re-scoring", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, run)
+}
+
+// ListRescores handles GET /fraud/rescore, the recent runs without their changes
+func (fc *FraudController) ListRescores(w http.ResponseWriter, r *http.Request) {
+	runs := fc.rescorer.Runs()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"runs":  runs,
+		"count": len(runs),
+	})
+}
+
+// GetRescore handles GET /fraud/rescore/{runID}, the comparison report of a run
+func (fc *FraudController) GetRescore(w http.ResponseWriter, r *http.Request) {
+	run, ok := fc.rescorer.Run(mux.Vars(r)["runID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Re-scoring run not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// timeWindow reads the from and to query parameters (RFC 3339 or YYYY-MM-DD). The
+// window defaults to the last 30 days.
+func timeWindow(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = parseTime(v); err != nil {
+			return from, to, err
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = parseTime(v); err != nil {
+			return from, to, err
+		}
+	}
+	if !from.Before(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// GetTransferGraph handles GET /fraud/graph/{userID}: whom the user pays, and who
+// else pays each of them
+func (fc *FraudController) GetTransferGraph(w http.ResponseWriter, r *http.Request) {
+	if fc.graph == nil {
+		respondWithError(w, http.StatusNotFound, "Transfer graph is disabled", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fc.graph.UserGraph(r.Context(), mux.Vars(r)["userID"]))
+}
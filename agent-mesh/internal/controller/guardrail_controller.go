@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// GuardrailController handles the guardrail rule pack admin API
+type GuardrailController struct {
+	rules *service.GuardrailRules
+}
+
+// NewGuardrailController creates a new guardrail controller
+func NewGuardrailController(rules *service.GuardrailRules) *GuardrailController {
+	return &GuardrailController{
+		rules: rules,
+	}
+}
+
+// ListPacks handles GET /admin/guardrails/packs
+func (gc *GuardrailController) ListPacks(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"packs": gc.rules.Packs(),
+	})
+}
+
+// PutPack handles PUT /admin/guardrails/packs. The body is a rule pack in JSON, or
+// in YAML when the content type says so or format=yaml is given.
+func (gc *GuardrailController) PutPack(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	format := "json"
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") || r.URL.Query().Get("format") == "yaml" {
+		format = "yaml"
+	}
+
+	pack, err := service.ParseGuardrailRulePack(body, format)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid rule pack", err)
+		return
+	}
+	if err := gc.rules.Put(pack); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid rule pack", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Rule pack loaded",
+		"pack":    pack.Name,
+		"rules":   len(pack.Rules),
+	})
+}
+
+// DeletePack handles DELETE /admin/guardrails/packs/{name}
+func (gc *GuardrailController) DeletePack(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if !gc.rules.Delete(name) {
+		respondWithError(w, http.StatusNotFound, "Rule pack not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Rule pack removed",
+		"pack":    name,
+	})
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// OutboxController handles inspection of the Banking Agent's transfer outbox and
+// on-demand recovery sweeps
+type OutboxController struct {
+	outbox *service.Outbox
+}
+
+// NewOutboxController creates a new outbox controller
+func NewOutboxController(outbox *service.Outbox) *OutboxController {
+	return &OutboxController{
+		outbox: outbox,
+	}
+}
+
+// ListEntries handles GET /admin/outbox?status=PENDING&limit=100
+func (oc *OutboxController) ListEntries(w http.ResponseWriter, r *http.Request) {
+	status := model.OutboxStatus(strings.ToUpper(r.URL.Query().Get("status")))
+	switch status {
+	case "", model.OutboxPending, model.OutboxConfirmed, model.OutboxFailed, model.OutboxAbandoned:
+	default:
+		respondWithError(w, http.StatusBadRequest, "status must be PENDING, CONFIRMED, FAILED or ABANDONED", nil)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	entries := oc.outbox.List(status, limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"entries":    entries,
+		"count":      len(entries),
+		"last_sweep": oc.outbox.LastSweep(),
+	})
+}
+
+// GetEntry handles GET /admin/outbox/{key}
+func (oc *OutboxController) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := oc.outbox.Get(mux.Vars(r)["key"])
+	if errors.Is(err, service.ErrOutboxEntryNotFound) {
+		respondWithError(w, http.StatusNotFound, "Outbox entry not found", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entry)
+}
+
+// AbandonEntry handles POST /admin/outbox/{key}/abandon: an operator who found in the
+// DWH that a held transfer never went through releases it
+func (oc *OutboxController) AbandonEntry(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	entry, err := oc.outbox.Abandon(mux.Vars(r)["key"], req.Reason)
+	switch {
+	case errors.Is(err, service.ErrOutboxEntryNotFound):
+		respondWithError(w, http.StatusNotFound, "Outbox entry not found", err)
+		return
+	case errors.Is(err, service.ErrOutboxEntrySettled):
+		respondWithError(w, http.StatusConflict, "Outbox entry not abandoned", err)
+		return
+	case err != nil:
+		respondWithError(w, http.StatusBadRequest, "Outbox entry not abandoned", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entry)
+}
+
+// Sweep handles POST /admin/outbox/sweep: every unconfirmed entry not being sent is
+// reconciled now, however recent
+func (oc *OutboxController) Sweep(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, oc.outbox.Sweep(r.Context(), true))
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// RuleScenarioController runs rule scenario suites against the agent's rules
+type RuleScenarioController struct {
+	runner    *service.RuleScenarioRunner
+	agentType string
+	library   []*model.RuleScenarioSuite // The agent's suites from RULE_SCENARIOS
+}
+
+// NewRuleScenarioController creates a new rule scenario controller for an agent type,
+// with the library of suites it runs when none is given
+func NewRuleScenarioController(runner *service.RuleScenarioRunner, agentType string, library []*model.RuleScenarioSuite) *RuleScenarioController {
+	return &RuleScenarioController{
+		runner:    runner,
+		agentType: agentType,
+		library:   service.ScenarioSuitesFor(library, agentType),
+	}
+}
+
+// ListSuites handles GET /admin/rule-scenarios
+func (rc *RuleScenarioController) ListSuites(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"suites": rc.library,
+	})
+}
+
+// Run handles POST /admin/rule-scenarios/run. The body is a suite in JSON, or in YAML
+// when the content type says so or format=yaml is given; without a body the library
+// is run.
+func (rc *RuleScenarioController) Run(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	suites := rc.library
+	if len(strings.TrimSpace(string(body))) > 0 {
+		format := "json"
+		if strings.Contains(r.Header.Get("Content-Type"), "yaml") || r.URL.Query().Get("format") == "yaml" {
+			format = "yaml"
+		}
+		suite, err := service.ParseRuleScenarioSuite(body, format)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid rule scenarios", err)
+			return
+		}
+		if suite.Agent != rc.agentType {
+			respondWithError(w, http.StatusBadRequest, "Invalid rule scenarios",
+				fmt.Errorf("suite %s is for the %s agent, not %s", suite.Name, suite.Agent, rc.agentType))
+			return
+		}
+		suites = []*model.RuleScenarioSuite{suite}
+	}
+	if len(suites) == 0 {
+		respondWithError(w, http.StatusBadRequest, "No rule scenarios to run; post a suite or set RULE_SCENARIOS", nil)
+		return
+	}
+
+	report := rc.runner.Run(suites)
+	log.Info().
+		Int("scenarios", report.Scenarios).
+		Int("failed", report.Failed).
+		Msg("Rule scenarios run")
+
+	respondWithJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/agent-mesh/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// warmupTimeout bounds a warmup so a slow downstream service cannot hold the caller
+const warmupTimeout = 5 * time.Second
+
+// WarmupController handles pre-warm requests from the MCP Server
+type WarmupController struct {
+	warmer *service.Warmer
+}
+
+// NewWarmupController creates a new warmup controller
+func NewWarmupController(warmer *service.Warmer) *WarmupController {
+	return &WarmupController{
+		warmer: warmer,
+	}
+}
+
+// Warmup handles POST /warmup. The body is optional.
+func (wc *WarmupController) Warmup(w http.ResponseWriter, r *http.Request) {
+	var req model.WarmupRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&req); err != nil && err != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), warmupTimeout)
+	defer cancel()
+
+	response := wc.warmer.Warm(ctx, req.Force)
+	if req.Reason != "" && !response.Cached {
+		log.Debug().Str("reason", req.Reason).Msg("Warmup requested")
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
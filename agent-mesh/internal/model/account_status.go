@@ -0,0 +1,26 @@
+package model
+
+// AccountStatus is Banking Integrations' (Layer 5) view of whether money may move on
+// an account: ACTIVE, FROZEN (no debits) or LOCKED (no debits or credits)
+type AccountStatus struct {
+	UserID    string `json:"user_id,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	Status    string `json:"status"`
+}
+
+// FraudSignal reports a fraud outcome to Banking Integrations, which freezes or locks
+// the account when the score is high enough
+type FraudSignal struct {
+	UserID     string   `json:"user_id"`
+	AccountID  string   `json:"account_id,omitempty"`
+	FraudScore float64  `json:"fraud_score"`
+	Decision   string   `json:"decision"`
+	RequestID  string   `json:"request_id,omitempty"`
+	Flags      []string `json:"flags,omitempty"`
+}
+
+// FraudSignalResult says what Banking Integrations did with a fraud signal
+type FraudSignalResult struct {
+	Action      string                 `json:"action"` // none, frozen, locked or already_restricted
+	Restriction map[string]interface{} `json:"restriction,omitempty"`
+}
@@ -18,11 +18,14 @@ type AgentResponse struct {
 	AgentType   string                 `json:"agent_type"`
 	Status      string                 `json:"status"` // APPROVED, REJECTED, PENDING
 	Result      map[string]interface{} `json:"result"`
+	ResultSchema string                `json:"result_schema,omitempty"` // BalanceResult, TransferResult, StatementResult or ScoreResult
 	RiskScore   float64                `json:"risk_score"`
 	Explanation string                 `json:"explanation"`
 	Confidence  float64                `json:"confidence"`
 	Timestamp   time.Time              `json:"timestamp"`
 	RequestID   string                 `json:"request_id"`
+	Model       *ModelVersion          `json:"model,omitempty"` // ML model behind the score, if any
+	Diagnostics *AgentDiagnostics      `json:"diagnostics,omitempty"`
 }
 
 // BankingTransaction represents a banking transaction
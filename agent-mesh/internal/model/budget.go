@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// BudgetRequest sets a user's monthly spending budget with Banking Integrations
+type BudgetRequest struct {
+	UserID     string  `json:"user_id"`
+	Category   string  `json:"category,omitempty"` // ALL when empty
+	Limit      float64 `json:"limit"`
+	Thresholds []int   `json:"thresholds,omitempty"` // Percentages that send an alert; the bank's defaults when empty
+}
+
+// BudgetStatus is how one of a user's budgets stands this month
+type BudgetStatus struct {
+	BudgetID    string    `json:"budget_id"`
+	Category    string    `json:"category"`
+	Limit       float64   `json:"limit"`
+	Currency    string    `json:"currency"`
+	Thresholds  []int     `json:"thresholds"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Spent       float64   `json:"spent"`
+	Remaining   float64   `json:"remaining"`
+	PercentUsed float64   `json:"percent_used"`
+	Alerted     []int     `json:"alerted,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// SettlementEstimate is when Banking Integrations (Layer 5) expects a transfer on a
+// rail to be credited, given business hours, cutoffs and bank holidays
+type SettlementEstimate struct {
+	Rail               string    `json:"rail"`
+	RequestedAt        time.Time `json:"requested_at"`
+	WithinWindow       bool      `json:"within_window"`
+	ExpectedSettlement time.Time `json:"expected_settlement"`
+	Delayed            bool      `json:"delayed"`
+	Reason             string    `json:"reason,omitempty"`
+	Holiday            string    `json:"holiday,omitempty"`
+	Message            string    `json:"message"`
+}
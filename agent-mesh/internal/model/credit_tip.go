@@ -0,0 +1,39 @@
+package model
+
+// Credit score factors
+const (
+	CreditFactorAccountAge  = "account_age"
+	CreditFactorIncome      = "income"
+	CreditFactorDelinquency = "delinquency"
+	CreditFactorUtilization = "utilization"
+	CreditFactorLoanHistory = "loan_history"
+	CreditFactorBalance     = "balance"
+)
+
+// Credit tip actions
+const (
+	CreditTipAvoidDelinquency  = "AVOID_DELINQUENCY"
+	CreditTipReduceUtilization = "REDUCE_UTILIZATION"
+	CreditTipBuildHistory      = "BUILD_CREDIT_HISTORY"
+	CreditTipKeepAccount       = "KEEP_ACCOUNT_OPEN"
+	CreditTipRaiseBalance      = "RAISE_BALANCE"
+)
+
+// CreditFactor is one part of a credit score: the points it added or took away and
+// the most it can add
+type CreditFactor struct {
+	Factor    string  `json:"factor"`
+	Value     float64 `json:"value"`
+	Points    float64 `json:"points"`
+	MaxPoints float64 `json:"max_points"`
+}
+
+// CreditTip is a concrete action that would raise a user's credit score
+type CreditTip struct {
+	Action          string  `json:"action"`
+	Factor          string  `json:"factor"`
+	Title           string  `json:"title"`
+	Detail          string  `json:"detail"`
+	PotentialPoints float64 `json:"potential_points"` // Rough lift in score once acted on
+	Priority        int     `json:"priority"`         // 1 lifts the score the most
+}
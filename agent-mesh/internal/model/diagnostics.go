@@ -0,0 +1,20 @@
+package model
+
+// AgentDiagnostics reports how long an agent took and what it called to produce a
+// response, for latency and dependency SLO tracking
+type AgentDiagnostics struct {
+	ProcessingMs   float64          `json:"processing_ms"`
+	CallsAttempted int              `json:"downstream_calls_attempted"`
+	CallsSucceeded int              `json:"downstream_calls_succeeded"`
+	FallbackUsed   bool             `json:"fallback_used"`       // Rules or mock data stood in for a downstream system
+	Fallbacks      []string         `json:"fallbacks,omitempty"` // What stood in and why, e.g. "model:ml_unavailable"
+	Calls          []DownstreamCall `json:"calls,omitempty"`
+	ML             *MLHealth        `json:"ml,omitempty"` // The ML service's mode and health, for agents that score with models
+}
+
+// DownstreamCall is one call an agent made to another service
+type DownstreamCall struct {
+	Target     string  `json:"target"` // e.g. "ml:fraud_model", "banking:preferences"
+	Succeeded  bool    `json:"succeeded"`
+	DurationMs float64 `json:"duration_ms"`
+}
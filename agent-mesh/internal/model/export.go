@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ExportLinkRequest asks Banking Integrations for a link that downloads a user's
+// transactions between two dates as OFX, QIF or CSV
+type ExportLinkRequest struct {
+	UserID    string `json:"user_id"`
+	AccountID string `json:"account_id,omitempty"` // Every account of the user's when empty
+	Format    string `json:"format"`
+	From      string `json:"from"` // YYYY-MM-DD
+	To        string `json:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// ExportLink is a short-lived public link that downloads a transaction export
+type ExportLink struct {
+	URL       string    `json:"url"`
+	Format    string    `json:"format"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	FileName  string    `json:"file_name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
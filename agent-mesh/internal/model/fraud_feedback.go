@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// Fraud labels set by reviewers on past decisions
+const (
+	FraudLabelFraud      = "FRAUD"      // Confirmed fraud
+	FraudLabelLegitimate = "LEGITIMATE" // Confirmed genuine
+)
+
+// FraudDecision is a snapshot of a Fraud Agent decision, with the label a reviewer
+// later gave it, if any
+type FraudDecision struct {
+	RequestID  string             `json:"request_id"`
+	UserID     string             `json:"user_id,omitempty"`
+	TenantID   string             `json:"tenant_id,omitempty"`
+	Amount     float64            `json:"amount"`
+	ToAccount  string             `json:"to_account,omitempty"`
+	FraudScore float64            `json:"fraud_score"`
+	Status     string             `json:"status"` // APPROVED, PENDING, REJECTED
+	Flags      []string           `json:"flags,omitempty"`
+	Features   map[string]float64 `json:"features"` // Numeric inputs the decision was scored on
+	Model      *ModelVersion      `json:"model,omitempty"`
+	DecidedAt  time.Time          `json:"decided_at"`
+	Label      *FraudLabel        `json:"label,omitempty"`
+}
+
+// Flagged reports whether the decision held the transaction back
+func (d *FraudDecision) Flagged() bool {
+	return d.Status != "APPROVED"
+}
+
+// FraudLabel is a reviewer's verdict on a decision
+type FraudLabel struct {
+	Label     string    `json:"label"` // FRAUD or LEGITIMATE
+	LabeledBy string    `json:"labeled_by"`
+	Note      string    `json:"note,omitempty"`
+	LabeledAt time.Time `json:"labeled_at"`
+}
+
+// FraudLabelRequest labels a decision
+type FraudLabelRequest struct {
+	Label     string `json:"label"`
+	LabeledBy string `json:"labeled_by"`
+	Note      string `json:"note,omitempty"`
+}
+
+// FraudMetrics is the precision and recall of labeled decisions per period
+type FraudMetrics struct {
+	Period  string               `json:"period"` // day or week
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Total   FraudMetricsBucket   `json:"total"`
+	Buckets []FraudMetricsBucket `json:"buckets"`
+}
+
+// FraudMetricsBucket counts labeled decisions in one period. Flagged decisions
+// (PENDING or REJECTED) are the positives.
+type FraudMetricsBucket struct {
+	Start          time.Time `json:"start"`
+	Decisions      int       `json:"decisions"`
+	Labeled        int       `json:"labeled"`
+	TruePositives  int       `json:"true_positives"`  // Flagged, confirmed fraud
+	FalsePositives int       `json:"false_positives"` // Flagged, confirmed legitimate
+	FalseNegatives int       `json:"false_negatives"` // Approved, confirmed fraud
+	TrueNegatives  int       `json:"true_negatives"`  // Approved, confirmed legitimate
+	Precision      float64   `json:"precision"`
+	Recall         float64   `json:"recall"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Re-scoring run statuses
+const (
+	RescoreRunning   = "RUNNING"
+	RescoreCompleted = "COMPLETED"
+)
+
+// How a re-scored decision changed
+const (
+	RescoreNewlyFlagged = "NEWLY_FLAGGED" // Approved then, held back now
+	RescoreNewlyCleared = "NEWLY_CLEARED" // Held back then, approved now
+	RescoreEscalated    = "ESCALATED"     // PENDING then, REJECTED now
+	RescoreDeescalated  = "DEESCALATED"   // REJECTED then, PENDING now
+)
+
+// RescoreRequest asks for the decisions made in [from, to) to be scored again
+type RescoreRequest struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+}
+
+// RescoreRun replays past fraud decisions through the current model and thresholds in
+// shadow mode and compares the outcomes. The decisions themselves are not changed.
+type RescoreRun struct {
+	RunID        string     `json:"run_id"`
+	Status       string     `json:"status"`
+	From         time.Time  `json:"from"`
+	To           time.Time  `json:"to"`
+	RequestedBy  string     `json:"requested_by,omitempty"`
+	Decisions    int        `json:"decisions"` // Decisions in the window
+	Rescored     int        `json:"rescored"`
+	Unchanged    int        `json:"unchanged"` // Same status as decided, whatever the score
+	NewlyFlagged int        `json:"newly_flagged"`
+	NewlyCleared int        `json:"newly_cleared"`
+	Escalated    int        `json:"escalated"`
+	Deescalated  int        `json:"deescalated"`
+	MeanShift    float64    `json:"mean_shift"` // Mean of current minus decided scores
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+
+	// Precision and recall over the labeled decisions, as decided and as re-scored
+	Decided FraudMetricsBucket `json:"decided"`
+	Current FraudMetricsBucket `json:"current"`
+
+	Changes          []RescoreChange `json:"changes,omitempty"`           // Decisions whose status changed, oldest first
+	ChangesTruncated bool            `json:"changes_truncated,omitempty"` // More changed than are listed
+}
+
+// RescoreChange is a decision whose status the current model or thresholds change
+type RescoreChange struct {
+	RequestID     string        `json:"request_id"`
+	UserID        string        `json:"user_id,omitempty"`
+	Amount        float64       `json:"amount"`
+	DecidedAt     time.Time     `json:"decided_at"`
+	Change        string        `json:"change"`
+	DecidedScore  float64       `json:"decided_score"`
+	DecidedStatus string        `json:"decided_status"`
+	DecidedModel  *ModelVersion `json:"decided_model,omitempty"`
+	CurrentScore  float64       `json:"current_score"`
+	CurrentStatus string        `json:"current_status"`
+	CurrentModel  *ModelVersion `json:"current_model,omitempty"`
+	Label         string        `json:"label,omitempty"` // The reviewer's verdict, if any
+}
@@ -0,0 +1,43 @@
+package model
+
+// GuardrailRulePack is a named, versioned set of guardrail rules for one regulation
+// or policy, loaded from JSON or YAML
+type GuardrailRulePack struct {
+	Name        string          `json:"name" yaml:"name"`
+	Version     string          `json:"version,omitempty" yaml:"version,omitempty"`
+	Regulation  string          `json:"regulation,omitempty" yaml:"regulation,omitempty"` // e.g. RBI, bank policy
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Rules       []GuardrailRule `json:"rules" yaml:"rules"`
+}
+
+// GuardrailRule compares one metric of a request with a threshold. The request
+// passes the rule when "metric operator threshold" holds.
+type GuardrailRule struct {
+	Name      string                 `json:"name" yaml:"name"`
+	Metric    string                 `json:"metric" yaml:"metric"`
+	Operator  string                 `json:"operator" yaml:"operator"`   // lt, lte, gt, gte, eq, neq, in, not_in
+	Threshold interface{}            `json:"threshold" yaml:"threshold"` // Number, string, bool, or a list for in/not_in
+	AppliesTo GuardrailApplicability `json:"applies_to,omitempty" yaml:"applies_to,omitempty"`
+	IfMissing string                 `json:"if_missing,omitempty" yaml:"if_missing,omitempty"` // pass (default), fail or skip
+	Message   string                 `json:"message" yaml:"message"`
+}
+
+// GuardrailApplicability limits a rule to some requests. An empty list matches
+// every request.
+type GuardrailApplicability struct {
+	Tasks        []string `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Tenants      []string `json:"tenants,omitempty" yaml:"tenants,omitempty"`
+	AccountTypes []string `json:"account_types,omitempty" yaml:"account_types,omitempty"`
+}
+
+// GuardrailRuleResult is the outcome of one rule for a request
+type GuardrailRuleResult struct {
+	Pack      string      `json:"pack"`
+	Rule      string      `json:"rule"`
+	Passed    bool        `json:"passed"`
+	Metric    string      `json:"metric"`
+	Value     interface{} `json:"value"` // nil when the metric was missing
+	Operator  string      `json:"operator"`
+	Threshold interface{} `json:"threshold"`
+	Message   string      `json:"message,omitempty"` // Set when the rule failed
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Insight types
+const (
+	InsightSweepToFD   = "SWEEP_TO_FD"  // Idle balance that would earn more in a fixed deposit
+	InsightStartRD     = "START_RD"     // Monthly surplus that could go into a recurring deposit
+	InsightSpendingUp  = "SPENDING_UP"  // Spending this month well above the usual
+	InsightTopSpending = "TOP_SPENDING" // The rail most of the spending goes through
+)
+
+// Insight is one piece of advice drawn from a user's balances and spending
+type Insight struct {
+	Type          string  `json:"type"`
+	Title         string  `json:"title"`
+	Message       string  `json:"message"`
+	Amount        float64 `json:"amount,omitempty"`         // Suggested amount, e.g. to sweep
+	EstimatedGain float64 `json:"estimated_gain,omitempty"` // Extra interest a year, in rupees
+	Priority      int     `json:"priority"`                 // 1 is the most worth acting on
+}
+
+// DWHTransaction is a transaction in a user's DWH history
+type DWHTransaction struct {
+	TransactionID string    `json:"transaction_id"`
+	AccountID     string    `json:"account_id,omitempty"`
+	Type          string    `json:"type"`
+	Amount        float64   `json:"amount"`
+	Status        string    `json:"status"`
+	FromAccount   string    `json:"from_account,omitempty"`
+	ToAccount     string    `json:"to_account,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
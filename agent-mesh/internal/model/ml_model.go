@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// ModelSpec is one version of an ML model served by Layer 4
+type ModelSpec struct {
+	Name     string        `json:"name"`     // credit, fraud, risk
+	Version  string        `json:"version"`  // e.g. v1
+	Endpoint string        `json:"endpoint"` // Path on the ML service, e.g. /api/v1/scoring/credit
+	Features []FeatureSpec `json:"features"`
+}
+
+// FeatureSpec is one input of a model. Source is the input context key the value is
+// read from; it defaults to Name.
+type FeatureSpec struct {
+	Name     string `json:"name"`
+	Source   string `json:"source,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// ModelExperiment sends a share of users, optionally of some tenants only, to another
+// version of a model
+type ModelExperiment struct {
+	Name    string   `json:"name"`
+	Model   string   `json:"model"`
+	Version string   `json:"version"`
+	Percent int      `json:"percent"`           // 0-100 of users, bucketed by user ID
+	Tenants []string `json:"tenants,omitempty"` // Empty means every tenant
+}
+
+// ModelRegistryConfig lists the model versions and which one each request uses:
+// an experiment the user falls into, then the tenant's pinned version, then the default
+type ModelRegistryConfig struct {
+	Models      []ModelSpec                  `json:"models"`
+	Defaults    map[string]string            `json:"defaults"` // Model name -> version
+	Tenants     map[string]map[string]string `json:"tenants,omitempty"`
+	Experiments []ModelExperiment            `json:"experiments,omitempty"`
+}
+
+// ModelVersion records which model produced a score, or why the rules did instead
+type ModelVersion struct {
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	Experiment      string   `json:"experiment,omitempty"`
+	Source          string   `json:"source"`                     // ml or rules
+	Fallback        string   `json:"fallback,omitempty"`         // Why the rules were used: missing_features, ml_unavailable, ml_disabled, ml_not_allowed, ml_failover
+	MissingFeatures []string `json:"missing_features,omitempty"` // Required features absent from the request
+}
+
+// ML service modes. An agent fails over to rules when the ML service's recent calls
+// cross the health thresholds, and probes it once the cool-down is over.
+const (
+	MLModeML       = "ml"       // Models are called
+	MLModeFailover = "failover" // Scored with rules until the cool-down ends
+	MLModeProbing  = "probing"  // One call at a time goes to the ML service to test recovery
+)
+
+// MLHealth is the ML service's health as an agent sees it
+type MLHealth struct {
+	Mode         string     `json:"mode"`
+	Score        float64    `json:"score"` // 1 healthy, 0 at a threshold; the mean of the error and latency scores
+	ErrorRate    float64    `json:"error_rate"`
+	AvgLatencyMs float64    `json:"avg_latency_ms"`
+	Calls        int        `json:"calls"`                 // Calls in the window
+	Since        time.Time  `json:"since"`                 // When the mode was entered
+	ProbeAt      *time.Time `json:"probe_at,omitempty"`    // When a failed-over agent starts probing
+	Failovers    int64      `json:"failovers"`             // Times the agent failed over since it started
+	LastReason   string     `json:"last_reason,omitempty"` // Why it last failed over
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// TransferRequest asks Banking Integrations (Layer 5) to move money. The idempotency
+// key makes it safe to send again: a repeat gets the first transfer's response.
+type TransferRequest struct {
+	UserID         string  `json:"user_id"`
+	FromAccount    string  `json:"from_account"`
+	ToAccount      string  `json:"to_account"`
+	IFSC           string  `json:"ifsc,omitempty"`
+	PayeeName      string  `json:"payee_name,omitempty"`
+	Amount         float64 `json:"amount"`
+	Type           string  `json:"type"` // NEFT, RTGS, IMPS or UPI
+	Remarks        string  `json:"remarks,omitempty"`
+	Channel        string  `json:"channel"`
+	Sandbox        bool    `json:"sandbox,omitempty"`
+	IdempotencyKey string  `json:"idempotency_key"`
+}
+
+// TransferResponse is Banking Integrations' response to a transfer
+type TransferResponse struct {
+	TransactionID   string    `json:"transaction_id"`
+	Status          string    `json:"status"` // COMPLETED, PENDING, FAILED or REJECTED
+	Amount          float64   `json:"amount"`
+	FromAccount     string    `json:"from_account"`
+	ToAccount       string    `json:"to_account"`
+	ReferenceNumber string    `json:"reference_number"`
+	ProcessedAt     time.Time `json:"processed_at"`
+	Message         string    `json:"message"`
+	Simulated       bool      `json:"simulated,omitempty"`
+	Replayed        bool      `json:"replayed,omitempty"` // Answered from an earlier transfer with the same key
+}
+
+// OutboxStatus is where an outbox entry stands
+type OutboxStatus string
+
+// Outbox entry statuses
+const (
+	OutboxPending   OutboxStatus = "PENDING"   // Recorded; the call may or may not have reached Banking Integrations
+	OutboxConfirmed OutboxStatus = "CONFIRMED" // Banking Integrations answered with the transfer's response
+	OutboxFailed    OutboxStatus = "FAILED"    // Banking Integrations refused it; no money moved
+	OutboxAbandoned OutboxStatus = "ABANDONED" // Never reached Banking Integrations, as an operator confirmed
+)
+
+// OutboxEntry is a transfer the Banking Agent recorded before sending it to Banking
+// Integrations, so one it crashed in the middle of is not lost
+type OutboxEntry struct {
+	IdempotencyKey string            `json:"idempotency_key"`
+	RequestID      string            `json:"request_id,omitempty"`
+	Transfer       TransferRequest   `json:"transfer"`
+	Status         OutboxStatus      `json:"status"`
+	Result         *TransferResponse `json:"result,omitempty"`
+	Error          string            `json:"error,omitempty"`        // Why it failed, or the last call or lookup that did not settle it
+	Reconciles     int               `json:"reconciles,omitempty"`   // Lookups by the recovery sweep
+	RecoveredBy    string            `json:"recovered_by,omitempty"` // "sweep" or "operator" when settled after the call
+	Held           bool              `json:"held,omitempty"`         // Unknown to Banking Integrations; not sent again until an operator settles it
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	SettledAt      *time.Time        `json:"settled_at,omitempty"`
+}
+
+// Settled reports whether the entry needs no more reconciling
+func (e *OutboxEntry) Settled() bool {
+	return e.Status != OutboxPending
+}
+
+// OutboxSweep is the outcome of a recovery sweep
+type OutboxSweep struct {
+	Checked   int       `json:"checked"`
+	Confirmed int       `json:"confirmed"`
+	Held      int       `json:"held"`    // Unknown to Banking Integrations, held for manual review
+	Pending   int       `json:"pending"` // Still unsettled: Banking Integrations could not be asked, or the transfer is in progress
+	SweptAt   time.Time `json:"swept_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// PaymentRequest is a UPI request for money raised through Banking Integrations
+// (Layer 5), with the deep link and QR payload the payer uses to pay it
+type PaymentRequest struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	PayeeVPA  string    `json:"payee_vpa"`
+	PayeeName string    `json:"payee_name"`
+	PayerName string    `json:"payer_name,omitempty"`
+	PayerVPA  string    `json:"payer_vpa,omitempty"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Note      string    `json:"note,omitempty"`
+	Status    string    `json:"status"` // PENDING, FULFILLED, EXPIRED, CANCELLED
+	DeepLink  string    `json:"deep_link"`
+	QRPayload string    `json:"qr_payload"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Simulated bool      `json:"simulated,omitempty"`
+}
+
+// PaymentRequestCreate asks Banking Integrations to raise a payment request
+type PaymentRequestCreate struct {
+	UserID    string  `json:"user_id"`
+	AccountID string  `json:"account_id,omitempty"`
+	PayerName string  `json:"payer_name,omitempty"`
+	PayerVPA  string  `json:"payer_vpa,omitempty"`
+	Amount    float64 `json:"amount"`
+	Note      string  `json:"note,omitempty"`
+	Sandbox   bool    `json:"sandbox,omitempty"`
+}
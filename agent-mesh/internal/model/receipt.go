@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ReceiptLinkRequest asks Banking Integrations for a shareable link to the receipt
+// of one of a user's transfers
+type ReceiptLinkRequest struct {
+	UserID        string `json:"user_id"`
+	TransactionID string `json:"transaction_id,omitempty"` // The user's most recent transfer when empty
+}
+
+// ReceiptLink is a short-lived public link to a transfer's redacted receipt
+type ReceiptLink struct {
+	TransactionID string                 `json:"transaction_id"`
+	URL           string                 `json:"url"`
+	ExpiresAt     time.Time              `json:"expires_at"`
+	Receipt       map[string]interface{} `json:"receipt"`
+}
@@ -0,0 +1,54 @@
+package model
+
+// RuleScenarioSuite is a library of scenarios for one agent's rules, loaded from JSON
+// or YAML, so risk analysts can check how the rules decide without writing Go
+type RuleScenarioSuite struct {
+	Name        string         `json:"name" yaml:"name"`
+	Agent       string         `json:"agent" yaml:"agent"` // GUARDRAIL or FRAUD
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Scenarios   []RuleScenario `json:"scenarios" yaml:"scenarios"`
+}
+
+// RuleScenario is one request and the outcome the rules are expected to give it
+type RuleScenario struct {
+	Name        string                 `json:"name" yaml:"name"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Task        string                 `json:"task,omitempty" yaml:"task,omitempty"`       // Defaults to TRANSFER_NEFT
+	Context     map[string]interface{} `json:"context,omitempty" yaml:"context,omitempty"` // Input context values, e.g. device_risk, tenant_id
+	Data        map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`       // Transaction data, e.g. amount, to_account
+	Expect      RuleExpectation        `json:"expect" yaml:"expect"`
+}
+
+// RuleExpectation is what a scenario checks. Only what is set is checked.
+type RuleExpectation struct {
+	Status    string   `json:"status,omitempty" yaml:"status,omitempty"`         // APPROVED, PENDING or REJECTED
+	Fails     []string `json:"fails,omitempty" yaml:"fails,omitempty"`           // Guardrail rules that must fail
+	Passes    []string `json:"passes,omitempty" yaml:"passes,omitempty"`         // Guardrail rules that must pass
+	MinScore  *float64 `json:"min_score,omitempty" yaml:"min_score,omitempty"`   // Fraud score at least
+	MaxScore  *float64 `json:"max_score,omitempty" yaml:"max_score,omitempty"`   // Fraud score at most
+	RiskLevel string   `json:"risk_level,omitempty" yaml:"risk_level,omitempty"` // Fraud LOW, MEDIUM or HIGH
+	Flags     []string `json:"flags,omitempty" yaml:"flags,omitempty"`           // Fraud flags that must be raised
+	NotFlags  []string `json:"not_flags,omitempty" yaml:"not_flags,omitempty"`   // Fraud flags that must not be
+}
+
+// RuleScenarioResult is how the rules decided a scenario, and how that differed
+// from what was expected
+type RuleScenarioResult struct {
+	Suite       string   `json:"suite"`
+	Scenario    string   `json:"scenario"`
+	Passed      bool     `json:"passed"`
+	Status      string   `json:"status"`
+	FailedRules []string `json:"failed_rules,omitempty"` // Guardrail
+	Score       *float64 `json:"score,omitempty"`        // Fraud
+	RiskLevel   string   `json:"risk_level,omitempty"`   // Fraud
+	Flags       []string `json:"flags,omitempty"`        // Fraud
+	Mismatches  []string `json:"mismatches,omitempty"`   // Set when the scenario failed
+}
+
+// RuleScenarioReport is the outcome of running scenario suites against the rules
+type RuleScenarioReport struct {
+	Scenarios int                  `json:"scenarios"`
+	Passed    int                  `json:"passed"`
+	Failed    int                  `json:"failed"`
+	Results   []RuleScenarioResult `json:"results"`
+}
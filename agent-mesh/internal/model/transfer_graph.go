@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Transfer graph flags
+const (
+	FlagMuleFanIn             = "MULE_FAN_IN"             // Many distinct senders pay the beneficiary
+	FlagHighFanOut            = "HIGH_FAN_OUT"            // The sender pays many distinct beneficiaries
+	FlagNewBeneficiaryCluster = "NEW_BENEFICIARY_CLUSTER" // Several senders started paying a new beneficiary at once
+)
+
+// GraphSignals are what the transfer graph says about a transfer from a sender to a
+// beneficiary, counting the transfer itself
+type GraphSignals struct {
+	FanIn          int      `json:"fan_in"`          // Distinct senders to the beneficiary
+	FanOut         int      `json:"fan_out"`         // Distinct beneficiaries of the sender
+	ClusterSenders int      `json:"cluster_senders"` // Senders who started paying the beneficiary while it was new
+	NewCluster     bool     `json:"new_cluster"`
+	GraphRisk      float64  `json:"graph_risk"` // 0 to 1
+	Flags          []string `json:"flags,omitempty"`
+}
+
+// TransferEdge is what a sender has paid a beneficiary within the graph's window
+type TransferEdge struct {
+	Beneficiary    string    `json:"beneficiary"`
+	Transfers      int       `json:"transfers"`
+	Amount         float64   `json:"amount"`
+	FirstAt        time.Time `json:"first_at"`
+	LastAt         time.Time `json:"last_at"`
+	FanIn          int       `json:"fan_in"` // Distinct senders to the beneficiary, this one included
+	OtherSenders   []string  `json:"other_senders,omitempty"`
+	ClusterSenders int       `json:"cluster_senders"`
+	NewCluster     bool      `json:"new_cluster"`
+}
+
+// UserTransferGraph is a user's outgoing transfers by beneficiary, with who else pays
+// each of them
+type UserTransferGraph struct {
+	UserID     string         `json:"user_id"`
+	WindowDays int            `json:"window_days"`
+	FanOut     int            `json:"fan_out"`
+	Edges      []TransferEdge `json:"edges"`
+	Flags      []string       `json:"flags,omitempty"`
+	LoadedAt   *time.Time     `json:"loaded_at,omitempty"` // When the user's edges were last read from the DWH
+}
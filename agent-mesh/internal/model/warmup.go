@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// WarmupRequest is the optional body of POST /api/v1/warmup
+type WarmupRequest struct {
+	Reason string `json:"reason,omitempty"` // Why the caller expects traffic, e.g. "TRANSFER_NEFT at 09:00"
+	Force  bool   `json:"force,omitempty"`  // Ping downstream services even within the cooldown
+}
+
+// WarmupTarget is one downstream service an agent warmed
+type WarmupTarget struct {
+	Name      string  `json:"name"` // e.g. "banking:preferences", "ml"
+	OK        bool    `json:"ok"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// WarmupResponse reports what an agent did to get ready for traffic
+type WarmupResponse struct {
+	AgentType  string         `json:"agent_type"`
+	WarmedAt   time.Time      `json:"warmed_at"`
+	DurationMs float64        `json:"duration_ms"`
+	Targets    []WarmupTarget `json:"targets"`
+	Cached     bool           `json:"cached,omitempty"` // Within the cooldown, the last warmup is returned as is
+}
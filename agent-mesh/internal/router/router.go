@@ -8,18 +8,39 @@ import (
 
 // Router sets up all routes
 type Router struct {
-	agentController *controller.AgentController
-	rateLimiter     *middleware.RateLimiter
+	agentController     *controller.AgentController
+	fraudController     *controller.FraudController        // Only set for the Fraud Agent
+	guardrailController *controller.GuardrailController    // Only set for the Guardrail Agent
+	scenarioController  *controller.RuleScenarioController // Only set for the Guardrail and Fraud Agents
+	outboxController    *controller.OutboxController       // Only set for the Banking Agent sending transfers
+	warmupController    *controller.WarmupController
+	rateLimiter         *middleware.RateLimiter
+	recovery            *middleware.Recovery
+	accessLog           *middleware.AccessLog
 }
 
 // NewRouter creates a new router instance
 func NewRouter(
 	agentController *controller.AgentController,
+	fraudController *controller.FraudController,
+	guardrailController *controller.GuardrailController,
+	scenarioController *controller.RuleScenarioController,
+	outboxController *controller.OutboxController,
+	warmupController *controller.WarmupController,
 	rateLimiter *middleware.RateLimiter,
+	recovery *middleware.Recovery,
+	accessLog *middleware.AccessLog,
 ) *Router {
 	return &Router{
-		agentController: agentController,
-		rateLimiter:     rateLimiter,
+		agentController:     agentController,
+		fraudController:     fraudController,
+		guardrailController: guardrailController,
+		scenarioController:  scenarioController,
+		outboxController:    outboxController,
+		warmupController:    warmupController,
+		rateLimiter:         rateLimiter,
+		recovery:            recovery,
+		accessLog:           accessLog,
 	}
 }
 
@@ -33,11 +54,55 @@ func (r *Router) SetupRoutes() *mux.Router {
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/process", r.agentController.ProcessRequest).Methods("POST")
+	api.HandleFunc("/warmup", r.warmupController.Warmup).Methods("POST")
 
-	// Apply middleware
+	// Fraud decision feedback routes
+	if r.fraudController != nil {
+		api.HandleFunc("/fraud/decisions", r.fraudController.ListDecisions).Methods("GET")
+		api.HandleFunc("/fraud/decisions/{requestID}", r.fraudController.GetDecision).Methods("GET")
+		api.HandleFunc("/fraud/decisions/{requestID}/label", r.fraudController.LabelDecision).Methods("POST")
+		api.HandleFunc("/fraud/metrics", r.fraudController.GetMetrics).Methods("GET")
+		api.HandleFunc("/fraud/training-data", r.fraudController.ExportTrainingData).Methods("GET")
+		api.HandleFunc("/fraud/rescore", r.fraudController.StartRescore).Methods("POST")
+		api.HandleFunc("/fraud/rescore", r.fraudController.ListRescores).Methods("GET")
+		api.HandleFunc("/fraud/rescore/{runID}", r.fraudController.GetRescore).Methods("GET")
+		api.HandleFunc("/fraud/graph/{userID}", r.fraudController.GetTransferGraph).Methods("GET")
+	}
+
+	// Guardrail rule pack routes
+	if r.guardrailController != nil {
+		api.HandleFunc("/admin/guardrails/packs", r.guardrailController.ListPacks).Methods("GET")
+		api.HandleFunc("/admin/guardrails/packs", r.guardrailController.PutPack).Methods("PUT")
+		api.HandleFunc("/admin/guardrails/packs/{name}", r.guardrailController.DeletePack).Methods("DELETE")
+	}
+
+	// Rule scenario routes
+	if r.scenarioController != nil {
+		api.HandleFunc("/admin/rule-scenarios", r.scenarioController.ListSuites).Methods("GET")
+		api.HandleFunc("/admin/rule-scenarios/run", r.scenarioController.Run).Methods("POST")
+	}
+
+	// Transfer outbox routes
+	if r.outboxController != nil {
+		api.HandleFunc("/admin/outbox", r.outboxController.ListEntries).Methods("GET")
+		api.HandleFunc("/admin/outbox/sweep", r.outboxController.Sweep).Methods("POST")
+		api.HandleFunc("/admin/outbox/{key}", r.outboxController.GetEntry).Methods("GET")
+		api.HandleFunc("/admin/outbox/{key}/abandon", r.outboxController.AbandonEntry).Methods("POST")
+	}
+
+	// Panics recovered, per route and by fingerprint
+	api.HandleFunc("/admin/panics", r.recovery.Stats).Methods("GET")
+
+	// Apply middleware. Access records come first, so requests refused by any later
+	// middleware are recorded too.
+	router.Use(r.accessLog.Middleware)
+	router.NotFoundHandler = r.accessLog.NotFound()
+	router.MethodNotAllowedHandler = r.accessLog.MethodNotAllowed()
 	router.Use(middleware.LoggingMiddleware)
 	router.Use(middleware.AuthMiddleware)
 	router.Use(r.rateLimiter.RateLimitMiddleware)
+	// Last, so a panicking handler is answered before any middleware finishes its response
+	router.Use(r.recovery.Middleware)
 
 	return router
 }
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// AccountStatusClient reads account freezes and locks from Banking Integrations
+// (Layer 5) and reports the fraud outcomes that place them
+type AccountStatusClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewAccountStatusClient creates a new account status client
+func NewAccountStatusClient(cfg *config.BankingIntegrationsConfig) *AccountStatusClient {
+	return &AccountStatusClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (ac *AccountStatusClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, ac.httpClient, ac.baseURL)
+}
+
+// Status returns the status of a user's account, or of all their accounts when
+// accountID is empty
+func (ac *AccountStatusClient) Status(ctx context.Context, userID, accountID string) (status *model.AccountStatus, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:account_status", start, err) }(time.Now())
+
+	query := url.Values{}
+	if userID != "" {
+		query.Set("user_id", userID)
+	}
+	if accountID != "" {
+		query.Set("account_id", accountID)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v1/accounts/status?%s", ac.baseURL, query.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	status = &model.AccountStatus{}
+	if err := ac.do(httpReq, status); err != nil {
+		return nil, fmt.Errorf("failed to get account status: %w", err)
+	}
+	return status, nil
+}
+
+// ReportFraud sends a fraud outcome to Banking Integrations
+func (ac *AccountStatusClient) ReportFraud(ctx context.Context, signal *model.FraudSignal) (result *model.FraudSignalResult, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:fraud_signals", start, err) }(time.Now())
+
+	body, err := json.Marshal(signal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fraud signal: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ac.baseURL+"/api/v1/accounts/fraud-signals", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	result = &model.FraudSignalResult{}
+	if err := ac.do(httpReq, result); err != nil {
+		return nil, fmt.Errorf("failed to report fraud signal: %w", err)
+	}
+	return result, nil
+}
+
+func (ac *AccountStatusClient) do(httpReq *http.Request, out interface{}) error {
+	httpReq.Header.Set("X-API-Key", ac.apiKey.Get())
+
+	resp, err := ac.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("banking integrations error: %s", string(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/aibanking/agent-mesh/internal/config"
 	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,7 +21,8 @@ type AgentBase struct {
 	agentName   string
 	endpoint    string
 	mcpBaseURL  string
-	mcpAPIKey   string
+	mcpAPIKey   *secrets.Value
+	tenantID    string
 	httpClient  *http.Client
 }
 
@@ -31,13 +33,18 @@ func NewAgentBase(agentType, agentName, endpoint string, mcpConfig *config.MCPSe
 		agentName:  agentName,
 		endpoint:   endpoint,
 		mcpBaseURL: mcpConfig.BaseURL,
-		mcpAPIKey: mcpConfig.APIKey,
+		mcpAPIKey: config.Profile().RotatingSecret("MCP_SERVER_API_KEY", mcpConfig.APIKey),
 		httpClient: &http.Client{
 			Timeout: time.Duration(mcpConfig.Timeout) * time.Second,
 		},
 	}
 }
 
+// SetTenant dedicates the agent to one tenant's tasks when it registers
+func (ab *AgentBase) SetTenant(tenantID string) {
+	ab.tenantID = tenantID
+}
+
 // RegisterWithMCP registers this agent with the MCP Server
 func (ab *AgentBase) RegisterWithMCP(ctx context.Context, capabilities []string) error {
 	req := map[string]interface{}{
@@ -49,6 +56,9 @@ func (ab *AgentBase) RegisterWithMCP(ctx context.Context, capabilities []string)
 			"registered_at": time.Now(),
 		},
 	}
+	if ab.tenantID != "" {
+		req["tenant_id"] = ab.tenantID
+	}
 
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -62,7 +72,7 @@ func (ab *AgentBase) RegisterWithMCP(ctx context.Context, capabilities []string)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-API-Key", ab.mcpAPIKey)
+	httpReq.Header.Set("X-API-Key", ab.mcpAPIKey.Get())
 
 	resp, err := ab.httpClient.Do(httpReq)
 	if err != nil {
@@ -78,6 +88,7 @@ func (ab *AgentBase) RegisterWithMCP(ctx context.Context, capabilities []string)
 	log.Info().
 		Str("agent_type", ab.agentType).
 		Str("agent_name", ab.agentName).
+		Str("tenant_id", ab.tenantID).
 		Msg("Agent registered with MCP Server")
 
 	return nil
@@ -88,3 +99,9 @@ type ProcessRequest interface {
 	Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error)
 }
 
+
+// isSandbox reports whether the task was submitted in sandbox mode
+func isSandbox(inputCtx map[string]interface{}) bool {
+	sandbox, _ := inputCtx["sandbox"].(bool)
+	return sandbox
+}
@@ -2,28 +2,46 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aibanking/agent-mesh/internal/model"
-	"github.com/google/uuid"
+	"github.com/aibanking/shared/ids"
 	"github.com/rs/zerolog/log"
 )
 
 // BankingAgent handles banking operations
 type BankingAgent struct {
 	*AgentBase
+	preferences     *PreferenceClient
+	paymentRequests *PaymentRequestClient
+	receipts        *ReceiptClient
+	exports         *ExportClient
+	budgets         *BudgetClient
+	outbox          *Outbox // Nil unless BANKING_TRANSFERS_ENABLED; transfers are then mocked
 }
 
 // NewBankingAgent creates a new banking agent
-func NewBankingAgent(base *AgentBase) *BankingAgent {
+func NewBankingAgent(base *AgentBase, preferences *PreferenceClient, paymentRequests *PaymentRequestClient, receipts *ReceiptClient, exports *ExportClient, budgets *BudgetClient, outbox *Outbox) *BankingAgent {
 	return &BankingAgent{
-		AgentBase: base,
+		AgentBase:       base,
+		preferences:     preferences,
+		paymentRequests: paymentRequests,
+		receipts:        receipts,
+		exports:         exports,
+		budgets:         budgets,
+		outbox:          outbox,
 	}
 }
 
 // Process processes a banking request
-func (ba *BankingAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+func (ba *BankingAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
 	log.Info().
 		Str("task", req.Task).
 		Str("request_id", req.RequestID).
@@ -41,6 +59,18 @@ func (ba *BankingAgent) Process(ctx context.Context, req *model.AgentRequest) (*
 		return ba.getStatement(ctx, req, inputCtx)
 	case "ADD_BENEFICIARY":
 		return ba.addBeneficiary(ctx, req, inputCtx)
+	case "LIST_BENEFICIARIES":
+		return ba.listBeneficiaries(ctx, req, inputCtx)
+	case "REQUEST_MONEY":
+		return ba.requestMoney(ctx, req, inputCtx)
+	case "SHARE_RECEIPT":
+		return ba.shareReceipt(ctx, req, inputCtx)
+	case "EXPORT_TRANSACTIONS":
+		return ba.exportTransactions(ctx, req, inputCtx)
+	case "SET_BUDGET":
+		return ba.setBudget(ctx, req, inputCtx)
+	case "BUDGET_STATUS":
+		return ba.budgetStatus(ctx, req, inputCtx)
 	default:
 		return &model.AgentResponse{
 			AgentID:     ba.agentType,
@@ -73,8 +103,26 @@ func (ba *BankingAgent) processTransfer(ctx context.Context, req *model.AgentReq
 		return nil, fmt.Errorf("to_account not found")
 	}
 
+	// Fill in what the user left out from their saved preferences
+	userID, _ := inputCtx["user_id"].(string)
+	prefs := ba.loadPreferences(ctx, userID)
+	fromAccount, _ := data["from_account"].(string)
+	var applied []string
+	if fromAccount == "" && prefs != nil && prefs.DefaultAccount != "" {
+		fromAccount = prefs.DefaultAccount
+		applied = append(applied, "from_account")
+	}
+
+	if ba.outbox != nil {
+		return ba.sendTransfer(ctx, req, inputCtx, data, fromAccount, applied, prefs)
+	}
+
 	// Generate transaction ID
-	txnID := fmt.Sprintf("TXN_%s", uuid.New().String()[:8])
+	txnID := ids.Ref(ids.Transaction)
+	sandbox := isSandbox(inputCtx)
+	if sandbox {
+		txnID = ids.Ref(ids.Sandbox)
+	}
 
 	// Simulate transfer processing
 	log.Info().
@@ -84,6 +132,7 @@ func (ba *BankingAgent) processTransfer(ctx context.Context, req *model.AgentReq
 		Msg("Processing fund transfer")
 
 	// In production, this would call actual banking core system
+	recordFallback(ctx, "core_banking:mock")
 	result := map[string]interface{}{
 		"status":          "APPROVED",
 		"transaction_id":  txnID,
@@ -92,6 +141,21 @@ func (ba *BankingAgent) processTransfer(ctx context.Context, req *model.AgentReq
 		"message":         "Transfer processed successfully",
 		"processed_at":    time.Now(),
 	}
+	if fromAccount != "" {
+		result["from_account"] = fromAccount
+	}
+	if prefs != nil && prefs.NotificationChannel != "" {
+		result["notification_channel"] = prefs.NotificationChannel
+	}
+	if len(applied) > 0 {
+		result["preferences_applied"] = applied
+	}
+
+	explanation := "Fund transfer processed successfully within banking limits"
+	if sandbox {
+		result["simulated"] = true
+		explanation = "Fund transfer simulated in sandbox mode; no money was moved"
+	}
 
 	return &model.AgentResponse{
 		AgentID:     ba.agentType,
@@ -99,18 +163,131 @@ func (ba *BankingAgent) processTransfer(ctx context.Context, req *model.AgentReq
 		Status:      "APPROVED",
 		Result:      result,
 		RiskScore:   0.1,
-		Explanation: "Fund transfer processed successfully within banking limits",
+		Explanation: explanation,
 		Confidence:  0.95,
 		Timestamp:   time.Now(),
 		RequestID:   req.RequestID,
 	}, nil
 }
 
+// sendTransfer carries out a transfer in Banking Integrations through the outbox. The
+// task's request ID is the idempotency key, so a retried task gets the first
+// transfer's outcome instead of sending the money again. A transfer whose outcome is
+// unknown fails the task and is left to the recovery sweep.
+func (ba *BankingAgent) sendTransfer(ctx context.Context, req *model.AgentRequest, inputCtx, data map[string]interface{}, fromAccount string, applied []string, prefs *model.UserPreferences) (*model.AgentResponse, error) {
+	amount, _ := data["amount"].(float64)
+	toAccount, _ := data["to_account"].(string)
+	if fromAccount == "" {
+		return ba.rejectTransfer(req, "from_account is required", "No account to send the money from was given, and no default account is saved"), nil
+	}
+
+	userID, _ := inputCtx["user_id"].(string)
+	transferChannel, _ := inputCtx["channel"].(string)
+	if transferChannel == "" {
+		// Transfers made in conversation go out over mobile banking unless told otherwise
+		transferChannel = "MB"
+	}
+	key := req.RequestID
+	if key == "" {
+		key = ids.New(ids.Outbox)
+	}
+	transfer := &model.TransferRequest{
+		UserID:         userID,
+		FromAccount:    fromAccount,
+		ToAccount:      toAccount,
+		Amount:         amount,
+		Type:           strings.TrimPrefix(req.Task, "TRANSFER_"),
+		Channel:        transferChannel,
+		Sandbox:        isSandbox(inputCtx),
+		IdempotencyKey: key,
+	}
+	transfer.IFSC, _ = data["ifsc"].(string)
+	transfer.PayeeName, _ = data["payee_name"].(string)
+	transfer.Remarks, _ = data["remarks"].(string)
+
+	log.Info().
+		Float64("amount", amount).
+		Str("to_account", toAccount).
+		Str("idempotency_key", key).
+		Msg("Sending fund transfer")
+
+	resp, err := ba.outbox.Transfer(ctx, req.RequestID, transfer)
+	if errors.Is(err, ErrTransferRefused) {
+		return ba.rejectTransfer(req, err.Error(), "The bank refused the transfer; no money was moved"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transfer %s did not complete: %w", key, err)
+	}
+	if resp.Status == "FAILED" || resp.Status == "REJECTED" {
+		return ba.rejectTransfer(req, resp.Message, "The bank could not complete the transfer; no money was moved"), nil
+	}
+
+	status := "APPROVED"
+	if resp.Status == "PENDING" {
+		status = "PENDING"
+	}
+	result := map[string]interface{}{
+		"status":           status,
+		"transaction_id":   resp.TransactionID,
+		"reference_number": resp.ReferenceNumber,
+		"amount":           resp.Amount,
+		"from_account":     resp.FromAccount,
+		"to_account":       resp.ToAccount,
+		"message":          resp.Message,
+		"processed_at":     resp.ProcessedAt,
+		"idempotency_key":  key,
+	}
+	if resp.Replayed {
+		result["replayed"] = true
+	}
+	if prefs != nil && prefs.NotificationChannel != "" {
+		result["notification_channel"] = prefs.NotificationChannel
+	}
+	if len(applied) > 0 {
+		result["preferences_applied"] = applied
+	}
+
+	explanation := "Fund transfer processed successfully within banking limits"
+	if resp.Simulated {
+		result["simulated"] = true
+		explanation = "Fund transfer simulated in sandbox mode; no money was moved"
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      status,
+		Result:      result,
+		RiskScore:   0.1,
+		Explanation: explanation,
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// rejectTransfer answers a transfer that moved no money
+func (ba *BankingAgent) rejectTransfer(req *model.AgentRequest, reason, explanation string) *model.AgentResponse {
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "REJECTED",
+		Result:      map[string]interface{}{"status": "REJECTED", "error": reason},
+		RiskScore:   0.0,
+		Explanation: explanation,
+		Confidence:  1.0,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}
+}
+
 // checkBalance checks account balance
 func (ba *BankingAgent) checkBalance(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
 	userID, _ := inputCtx["user_id"].(string)
+	accountID, fromPreferences := ba.resolveAccount(ctx, inputCtx)
 
 	// Mock balance - in production would query database
+	recordFallback(ctx, "core_banking:mock")
 	balance := 150000.0
 
 	log.Info().
@@ -121,9 +298,12 @@ func (ba *BankingAgent) checkBalance(ctx context.Context, req *model.AgentReques
 	result := map[string]interface{}{
 		"balance":   balance,
 		"currency":  "INR",
-		"account_id": userID,
+		"account_id": accountID,
 		"checked_at": time.Now(),
 	}
+	if fromPreferences {
+		result["preferences_applied"] = []string{"account_id"}
+	}
 
 	return &model.AgentResponse{
 		AgentID:     ba.agentType,
@@ -140,9 +320,10 @@ func (ba *BankingAgent) checkBalance(ctx context.Context, req *model.AgentReques
 
 // getStatement retrieves account statement
 func (ba *BankingAgent) getStatement(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
-	userID, _ := inputCtx["user_id"].(string)
+	accountID, fromPreferences := ba.resolveAccount(ctx, inputCtx)
 
 	// Mock statement - in production would query database
+	recordFallback(ctx, "core_banking:mock")
 	transactions := []map[string]interface{}{
 		{
 			"transaction_id": "TXN_001",
@@ -161,11 +342,14 @@ func (ba *BankingAgent) getStatement(ctx context.Context, req *model.AgentReques
 	}
 
 	result := map[string]interface{}{
-		"account_id":    userID,
+		"account_id":    accountID,
 		"transactions":  transactions,
 		"count":         len(transactions),
 		"generated_at":  time.Now(),
 	}
+	if fromPreferences {
+		result["preferences_applied"] = []string{"account_id"}
+	}
 
 	return &model.AgentResponse{
 		AgentID:     ba.agentType,
@@ -196,25 +380,424 @@ func (ba *BankingAgent) addBeneficiary(ctx context.Context, req *model.AgentRequ
 		Str("name", name).
 		Msg("Adding beneficiary")
 
+	// In production, this would register the beneficiary with the core system
+	recordFallback(ctx, "core_banking:mock")
+
 	result := map[string]interface{}{
 		"status":         "APPROVED",
-		"beneficiary_id": fmt.Sprintf("BEN_%s", uuid.New().String()[:8]),
+		"beneficiary_id": ids.Ref(ids.Beneficiary),
 		"account":        account,
 		"name":           name,
 		"ifsc":           ifsc,
 		"added_at":       time.Now(),
 	}
 
+	explanation := "Beneficiary added successfully"
+	if isSandbox(inputCtx) {
+		result["simulated"] = true
+		explanation = "Beneficiary simulated in sandbox mode; nothing was registered"
+	}
+
 	return &model.AgentResponse{
 		AgentID:     ba.agentType,
 		AgentType:   "BANKING",
 		Status:      "APPROVED",
 		Result:      result,
 		RiskScore:   0.1,
-		Explanation: "Beneficiary added successfully",
+		Explanation: explanation,
 		Confidence:  0.9,
 		Timestamp:   time.Now(),
 		RequestID:   req.RequestID,
 	}, nil
 }
 
+// listBeneficiaries lists the user's registered beneficiaries
+func (ba *BankingAgent) listBeneficiaries(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	userID, _ := inputCtx["user_id"].(string)
+
+	log.Info().
+		Str("user_id", userID).
+		Msg("Listing beneficiaries")
+
+	// In production, this would query the beneficiary store
+	recordFallback(ctx, "core_banking:mock")
+	beneficiaries := []map[string]interface{}{
+		{"beneficiary_id": "BEN_001", "name": "Rahul Mehta", "account": "XXXX5678", "ifsc": "BANK0001234"},
+		{"beneficiary_id": "BEN_002", "name": "Priya Shah", "account": "XXXX9012", "ifsc": "BANK0005678"},
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      map[string]interface{}{"beneficiaries": beneficiaries, "count": len(beneficiaries)},
+		RiskScore:   0.0,
+		Explanation: "Beneficiaries retrieved successfully",
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// requestMoney raises a UPI payment request asking someone to pay the user. No money
+// moves until the payer pays through the deep link or QR code.
+func (ba *BankingAgent) requestMoney(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	data, ok := inputCtx["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid data in input context")
+	}
+
+	// The rule-based parser sends amounts as text
+	var amount float64
+	switch v := data["amount"].(type) {
+	case float64:
+		amount = v
+	case string:
+		amount, _ = strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount not found or invalid")
+	}
+
+	userID, _ := inputCtx["user_id"].(string)
+	payerName, _ := data["name"].(string)
+	payerVPA, _ := data["upi_id"].(string)
+	note, _ := data["remarks"].(string)
+	accountID, _ := data["account_id"].(string)
+
+	log.Info().
+		Str("user_id", userID).
+		Float64("amount", amount).
+		Str("payer", payerName).
+		Msg("Requesting money")
+
+	pr, err := ba.paymentRequests.Create(ctx, &model.PaymentRequestCreate{
+		UserID:    userID,
+		AccountID: accountID,
+		PayerName: payerName,
+		PayerVPA:  payerVPA,
+		Amount:    amount,
+		Note:      note,
+		Sandbox:   isSandbox(inputCtx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to raise payment request: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status":     "APPROVED",
+		"request_id": pr.ID,
+		"amount":     pr.Amount,
+		"payee_vpa":  pr.PayeeVPA,
+		"deep_link":  pr.DeepLink,
+		"qr_payload": pr.QRPayload,
+		"expires_at": pr.ExpiresAt,
+	}
+	if pr.PayerName != "" {
+		result["payer_name"] = pr.PayerName
+	}
+
+	explanation := fmt.Sprintf("Payment request for INR %.2f created; share the link or QR code to get paid", pr.Amount)
+	if pr.PayerName != "" {
+		explanation = fmt.Sprintf("Payment request for INR %.2f from %s created; share the link or QR code to get paid", pr.Amount, pr.PayerName)
+	}
+	if pr.Simulated {
+		result["simulated"] = true
+		explanation = "Payment request simulated in sandbox mode; only sandbox transfers can pay it"
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      result,
+		RiskScore:   0.0,
+		Explanation: explanation,
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// shareReceipt gets a shareable link to the receipt of one of the user's transfers,
+// their most recent one unless the request names it
+func (ba *BankingAgent) shareReceipt(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	userID, _ := inputCtx["user_id"].(string)
+	var transactionID string
+	if data, ok := inputCtx["data"].(map[string]interface{}); ok {
+		transactionID, _ = data["transaction_id"].(string)
+	}
+
+	log.Info().
+		Str("user_id", userID).
+		Str("transaction_id", transactionID).
+		Msg("Sharing receipt")
+
+	link, err := ba.receipts.CreateLink(ctx, &model.ReceiptLinkRequest{
+		UserID:        userID,
+		TransactionID: transactionID,
+	})
+	if errors.Is(err, ErrNoReceipt) {
+		return &model.AgentResponse{
+			AgentID:     ba.agentType,
+			AgentType:   "BANKING",
+			Status:      "REJECTED",
+			Result:      map[string]interface{}{"error": err.Error()},
+			RiskScore:   0.0,
+			Explanation: "There is no receipt to share for that transfer",
+			Confidence:  1.0,
+			Timestamp:   time.Now(),
+			RequestID:   req.RequestID,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receipt link: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status":         "APPROVED",
+		"transaction_id": link.TransactionID,
+		"receipt_link":   link.URL,
+		"expires_at":     link.ExpiresAt,
+		"receipt":        link.Receipt,
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      result,
+		RiskScore:   0.0,
+		Explanation: fmt.Sprintf("Receipt link for %s created; anyone with the link can see the receipt until %s", link.TransactionID, link.ExpiresAt.Format("02 Jan 15:04 MST")),
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// exportTransactions gets a link that downloads the user's transactions between two
+// dates as OFX, QIF or CSV, for their personal finance or accounting tool
+func (ba *BankingAgent) exportTransactions(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	userID, _ := inputCtx["user_id"].(string)
+	linkReq := &model.ExportLinkRequest{UserID: userID, Format: "CSV"}
+	if data, ok := inputCtx["data"].(map[string]interface{}); ok {
+		if format, _ := data["format"].(string); format != "" {
+			linkReq.Format = strings.ToUpper(format)
+		}
+		linkReq.From, _ = data["from"].(string)
+		linkReq.To, _ = data["to"].(string)
+		linkReq.AccountID, _ = data["account_id"].(string)
+	}
+
+	log.Info().
+		Str("user_id", userID).
+		Str("format", linkReq.Format).
+		Str("from", linkReq.From).
+		Str("to", linkReq.To).
+		Msg("Exporting transactions")
+
+	link, err := ba.exports.CreateLink(ctx, linkReq)
+	if errors.Is(err, ErrExportRefused) {
+		return &model.AgentResponse{
+			AgentID:     ba.agentType,
+			AgentType:   "BANKING",
+			Status:      "REJECTED",
+			Result:      map[string]interface{}{"error": err.Error()},
+			RiskScore:   0.0,
+			Explanation: "That export cannot be made",
+			Confidence:  1.0,
+			Timestamp:   time.Now(),
+			RequestID:   req.RequestID,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export link: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status":        "APPROVED",
+		"download_link": link.URL,
+		"format":        link.Format,
+		"from":          link.From,
+		"to":            link.To,
+		"file_name":     link.FileName,
+		"expires_at":    link.ExpiresAt,
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      result,
+		RiskScore:   0.0,
+		Explanation: fmt.Sprintf("%s export of your transactions from %s to %s is ready to download until %s", link.Format, link.From, link.To, link.ExpiresAt.Format("02 Jan 15:04 MST")),
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// setBudget sets the user's monthly spending budget, overall or for one category
+func (ba *BankingAgent) setBudget(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	data, ok := inputCtx["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid data in input context")
+	}
+
+	// The rule-based parser sends amounts as text
+	var limit float64
+	switch v := data["amount"].(type) {
+	case float64:
+		limit = v
+	case string:
+		limit, _ = strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("amount not found or invalid")
+	}
+
+	userID, _ := inputCtx["user_id"].(string)
+	category, _ := data["category"].(string)
+
+	log.Info().
+		Str("user_id", userID).
+		Str("category", category).
+		Float64("limit", limit).
+		Msg("Setting budget")
+
+	status, err := ba.budgets.Set(ctx, &model.BudgetRequest{
+		UserID:   userID,
+		Category: category,
+		Limit:    limit,
+	})
+	if errors.Is(err, ErrInvalidBudget) {
+		return &model.AgentResponse{
+			AgentID:     ba.agentType,
+			AgentType:   "BANKING",
+			Status:      "REJECTED",
+			Result:      map[string]interface{}{"error": err.Error()},
+			RiskScore:   0.0,
+			Explanation: "That budget could not be set",
+			Confidence:  1.0,
+			Timestamp:   time.Now(),
+			RequestID:   req.RequestID,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set budget: %w", err)
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      map[string]interface{}{"status": "APPROVED", "budget": status},
+		RiskScore:   0.0,
+		Explanation: fmt.Sprintf("%s set to INR %.2f; INR %.2f spent so far this month. You will be alerted at %s of it", budgetName(status.Category), status.Limit, status.Spent, percentList(status.Thresholds)),
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// budgetStatus reports how the user's budgets stand this month, or just the one for
+// data.category
+func (ba *BankingAgent) budgetStatus(ctx context.Context, req *model.AgentRequest, inputCtx map[string]interface{}) (*model.AgentResponse, error) {
+	userID, _ := inputCtx["user_id"].(string)
+	var category string
+	if data, ok := inputCtx["data"].(map[string]interface{}); ok {
+		category, _ = data["category"].(string)
+	}
+
+	budgets, err := ba.budgets.Status(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	if category != "" {
+		var matching []model.BudgetStatus
+		for _, b := range budgets {
+			if strings.EqualFold(b.Category, category) {
+				matching = append(matching, b)
+			}
+		}
+		budgets = matching
+	}
+
+	lines := make([]string, 0, len(budgets))
+	for _, b := range budgets {
+		line := fmt.Sprintf("%s: INR %.2f of INR %.2f spent (%.0f%%), INR %.2f left", budgetName(b.Category), b.Spent, b.Limit, b.PercentUsed, b.Remaining)
+		if b.Remaining < 0 {
+			line = fmt.Sprintf("%s: INR %.2f of INR %.2f spent (%.0f%%), INR %.2f over", budgetName(b.Category), b.Spent, b.Limit, b.PercentUsed, -b.Remaining)
+		}
+		lines = append(lines, line)
+	}
+	explanation := strings.Join(lines, "; ")
+	if len(budgets) == 0 {
+		explanation = "No budget is set; try \"alert me when I spend over 50000 a month\""
+	}
+
+	return &model.AgentResponse{
+		AgentID:     ba.agentType,
+		AgentType:   "BANKING",
+		Status:      "APPROVED",
+		Result:      map[string]interface{}{"status": "APPROVED", "budgets": budgets, "count": len(budgets)},
+		RiskScore:   0.0,
+		Explanation: explanation,
+		Confidence:  0.95,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// budgetName describes a budget category, e.g. "Monthly food budget"
+func budgetName(category string) string {
+	if category == "" || category == "ALL" {
+		return "Monthly budget"
+	}
+	return "Monthly " + strings.ToLower(category) + " budget"
+}
+
+// percentList renders thresholds as "80% and 100%"
+func percentList(thresholds []int) string {
+	parts := make([]string, len(thresholds))
+	for i, t := range thresholds {
+		parts[i] = fmt.Sprintf("%d%%", t)
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// loadPreferences returns the user's saved preferences, or nil when there are none or
+// they cannot be read; preferences only fill gaps, so a lookup failure is not fatal
+func (ba *BankingAgent) loadPreferences(ctx context.Context, userID string) *model.UserPreferences {
+	if ba.preferences == nil || userID == "" {
+		return nil
+	}
+
+	prefs, err := ba.preferences.Get(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to load user preferences, continuing without them")
+		return nil
+	}
+	return prefs
+}
+
+// resolveAccount returns the account named in the request, else the user's default
+// account, else the user ID; the flag reports whether the preference was used
+func (ba *BankingAgent) resolveAccount(ctx context.Context, inputCtx map[string]interface{}) (string, bool) {
+	userID, _ := inputCtx["user_id"].(string)
+	if data, ok := inputCtx["data"].(map[string]interface{}); ok {
+		for _, key := range []string{"account_id", "account", "from_account"} {
+			if account, ok := data[key].(string); ok && account != "" {
+				return account, false
+			}
+		}
+	}
+
+	if prefs := ba.loadPreferences(ctx, userID); prefs != nil && prefs.DefaultAccount != "" {
+		return prefs.DefaultAccount, true
+	}
+	return userID, false
+}
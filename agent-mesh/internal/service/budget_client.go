@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// ErrInvalidBudget is returned for a budget Banking Integrations will not accept,
+// such as an unknown category
+var ErrInvalidBudget = errors.New("invalid budget")
+
+// BudgetClient sets and reads users' spending budgets with Banking Integrations (Layer 5)
+type BudgetClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewBudgetClient creates a new budget client
+func NewBudgetClient(cfg *config.BankingIntegrationsConfig) *BudgetClient {
+	return &BudgetClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (bc *BudgetClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, bc.httpClient, bc.baseURL)
+}
+
+// Set creates or replaces a user's budget for a category and returns how it stands.
+// A budget the bank refuses is an ErrInvalidBudget, wrapped with its reason.
+func (bc *BudgetClient) Set(ctx context.Context, req *model.BudgetRequest) (status *model.BudgetStatus, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:budgets", start, err) }(time.Now())
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/budgets", bc.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	body, code, err := bc.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set budget: %w", err)
+	}
+	if code == http.StatusBadRequest {
+		var apiErr struct {
+			Details string `json:"details"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBudget, apiErr.Details)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	status = &model.BudgetStatus{}
+	if err := json.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("failed to parse budget: %w", err)
+	}
+	return status, nil
+}
+
+// Status returns how each of a user's budgets stands this month
+func (bc *BudgetClient) Status(ctx context.Context, userID string) (budgets []model.BudgetStatus, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:budgets", start, err) }(time.Now())
+
+	endpoint := fmt.Sprintf("%s/api/v1/budgets?user_id=%s", bc.baseURL, url.QueryEscape(userID))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, code, err := bc.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	var result struct {
+		Budgets []model.BudgetStatus `json:"budgets"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse budgets: %w", err)
+	}
+	return result.Budgets, nil
+}
+
+func (bc *BudgetClient) do(httpReq *http.Request) ([]byte, int, error) {
+	httpReq.Header.Set("X-API-Key", bc.apiKey.Get())
+	resp, err := bc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
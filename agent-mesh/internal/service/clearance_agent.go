@@ -22,7 +22,10 @@ func NewClearanceAgent(base *AgentBase) *ClearanceAgent {
 }
 
 // Process processes a clearance request
-func (ca *ClearanceAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+func (ca *ClearanceAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
 	log.Info().
 		Str("task", req.Task).
 		Str("request_id", req.RequestID).
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+)
+
+// Credit score bounds
+const (
+	creditBaseScore = 600.0
+	creditMinScore  = 300.0
+	creditMaxScore  = 850.0
+)
+
+// Points of the rule-based credit score
+const (
+	delinquencyPenalty  = 20  // Per missed payment on record
+	healthyUtilization  = 0.3 // Share of the credit limit in use that costs nothing
+	loanHistoryPoints   = 30
+	accountAgeMaxPoints = 50
+	incomeMaxPoints     = 100
+	balanceMaxPoints    = 50
+)
+
+// creditFactors breaks a user's profile down into the points each part of it adds
+// to the rule-based credit score, in the order the score adds them up. Utilization
+// only counts when the caller sends it, as a share of the limit or a percentage.
+func creditFactors(context map[string]interface{}) []model.CreditFactor {
+	accountAge, _ := context["account_age_days"].(float64)
+	income, _ := context["income"].(float64)
+	delinquency, _ := context["delinquency_count"].(float64)
+	loanHistory, _ := context["loan_history_count"].(float64)
+	balance, _ := context["balance"].(float64)
+
+	factors := []model.CreditFactor{
+		{Factor: model.CreditFactorAccountAge, Value: accountAge, Points: tierPoints(accountAge, accountAgeTiers), MaxPoints: accountAgeMaxPoints},
+		{Factor: model.CreditFactorIncome, Value: income, Points: tierPoints(income, incomeTiers), MaxPoints: incomeMaxPoints},
+		{Factor: model.CreditFactorDelinquency, Value: delinquency, Points: -delinquency * delinquencyPenalty},
+	}
+	if utilization, ok := context["credit_utilization"].(float64); ok {
+		if utilization > 1 {
+			utilization /= 100
+		}
+		factors = append(factors, model.CreditFactor{Factor: model.CreditFactorUtilization, Value: utilization, Points: utilizationPoints(utilization)})
+	}
+
+	loanPoints := 0.0
+	if loanHistory > 0 {
+		loanPoints = loanHistoryPoints
+	}
+	return append(factors,
+		model.CreditFactor{Factor: model.CreditFactorLoanHistory, Value: loanHistory, Points: loanPoints, MaxPoints: loanHistoryPoints},
+		model.CreditFactor{Factor: model.CreditFactorBalance, Value: balance, Points: tierPoints(balance, balanceTiers), MaxPoints: balanceMaxPoints},
+	)
+}
+
+// scoreTier is the points a value above a threshold adds
+type scoreTier struct {
+	above  float64
+	points float64
+}
+
+// Tiers from the highest threshold down
+var (
+	accountAgeTiers = []scoreTier{{365, 50}, {180, 30}, {90, 15}}
+	incomeTiers     = []scoreTier{{100000, 100}, {50000, 60}, {25000, 30}}
+	balanceTiers    = []scoreTier{{100000, 50}, {50000, 30}, {10000, 15}}
+)
+
+func tierPoints(value float64, tiers []scoreTier) float64 {
+	for _, tier := range tiers {
+		if value > tier.above {
+			return tier.points
+		}
+	}
+	return 0
+}
+
+// nextTier returns the threshold and points of the tier above the one value is in
+func nextTier(value float64, tiers []scoreTier) (scoreTier, bool) {
+	for i := len(tiers) - 1; i >= 0; i-- {
+		if value <= tiers[i].above {
+			return tiers[i], true
+		}
+	}
+	return scoreTier{}, false
+}
+
+func utilizationPoints(utilization float64) float64 {
+	switch {
+	case utilization > 0.75:
+		return -60
+	case utilization > 0.5:
+		return -40
+	case utilization > healthyUtilization:
+		return -20
+	}
+	return 0
+}
+
+// creditTips maps the factors that hold a score down to actions that would raise it,
+// the biggest lift first. No lift takes the score past the maximum.
+func creditTips(factors []model.CreditFactor, score float64) []model.CreditTip {
+	headroom := creditMaxScore - score
+	tips := []model.CreditTip{}
+	add := func(f model.CreditFactor, tip model.CreditTip) {
+		tip.Factor = f.Factor
+		tip.PotentialPoints = math.Min(tip.PotentialPoints, headroom)
+		if tip.PotentialPoints > 0 {
+			tips = append(tips, tip)
+		}
+	}
+
+	for _, f := range factors {
+		switch f.Factor {
+		case model.CreditFactorDelinquency:
+			if f.Value > 0 {
+				add(f, model.CreditTip{
+					Action: model.CreditTipAvoidDelinquency,
+					Title:  "Pay every EMI and card bill on time",
+					Detail: fmt.Sprintf("You have %.0f missed %s on record, each costing about %d points. Setting up auto-pay keeps new ones off your record while the old ones age out.",
+						f.Value, plural(f.Value, "payment", "payments"), delinquencyPenalty),
+					PotentialPoints: -f.Points,
+				})
+			}
+		case model.CreditFactorUtilization:
+			if f.Value > healthyUtilization {
+				add(f, model.CreditTip{
+					Action: model.CreditTipReduceUtilization,
+					Title:  "Use less of your credit limit",
+					Detail: fmt.Sprintf("You are using %.0f%% of your credit limit. Paying it down below %.0f%% shows lenders you are not stretched.",
+						f.Value*100, healthyUtilization*100),
+					PotentialPoints: -f.Points,
+				})
+			}
+		case model.CreditFactorLoanHistory:
+			if f.Points < f.MaxPoints {
+				add(f, model.CreditTip{
+					Action:          model.CreditTipBuildHistory,
+					Title:           "Build a repayment history",
+					Detail:          "You have no loans on record yet. A small loan or credit line repaid on time gives lenders a track record to score.",
+					PotentialPoints: f.MaxPoints - f.Points,
+				})
+			}
+		case model.CreditFactorAccountAge:
+			if next, ok := nextTier(f.Value, accountAgeTiers); ok {
+				add(f, model.CreditTip{
+					Action: model.CreditTipKeepAccount,
+					Title:  "Keep your account open and in good standing",
+					Detail: fmt.Sprintf("Your account is %.0f days old; older accounts score higher. Keeping it open past %.0f days adds points on its own.",
+						f.Value, next.above),
+					PotentialPoints: next.points - f.Points,
+				})
+			}
+		case model.CreditFactorBalance:
+			if next, ok := nextTier(f.Value, balanceTiers); ok {
+				add(f, model.CreditTip{
+					Action: model.CreditTipRaiseBalance,
+					Title:  "Keep a higher average balance",
+					Detail: fmt.Sprintf("Your balance is %s. Keeping more than %s in your account shows a steady cushion.",
+						rupees(f.Value), rupees(next.above)),
+					PotentialPoints: next.points - f.Points,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(tips, func(a, b int) bool { return tips[a].PotentialPoints > tips[b].PotentialPoints })
+	for i := range tips {
+		tips[i].Priority = i + 1
+	}
+	return tips
+}
+
+func plural(n float64, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/demo"
+	"github.com/rs/zerolog/log"
+)
+
+// DemoAgent answers tasks tagged with a demo scenario with the outcome the scenario
+// forces on this agent type, and passes every other task to the agent itself. It wraps
+// the agent only when DEMO_MODE is on.
+type DemoAgent struct {
+	agent     ProcessRequest
+	agentType string
+	scenarios *demo.Library
+}
+
+// NewDemoAgent wraps an agent with the demo scenarios
+func NewDemoAgent(agent ProcessRequest, agentType string, scenarios *demo.Library) *DemoAgent {
+	return &DemoAgent{agent: agent, agentType: agentType, scenarios: scenarios}
+}
+
+// Process returns the scenario's forced outcome, or the agent's own answer when the
+// task plays no scenario or the scenario leaves this agent type to decide
+func (da *DemoAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+	taskContext, _ := req.InputContext["context"].(map[string]interface{})
+	scenarioID := demo.ScenarioID(taskContext)
+	outcome, ok := da.scenarios.Outcome(scenarioID, da.agentType)
+	if !ok {
+		return da.agent.Process(ctx, req)
+	}
+
+	log.Info().
+		Str("scenario", scenarioID).
+		Str("task", req.Task).
+		Str("request_id", req.RequestID).
+		Str("status", outcome.Status).
+		Msg("Demo scenario forced the agent's outcome")
+
+	result := make(map[string]interface{}, len(outcome.Result)+1)
+	for k, v := range outcome.Result {
+		result[k] = v
+	}
+	result[demo.ContextKey] = scenarioID
+
+	confidence := outcome.Confidence
+	if confidence == 0 {
+		confidence = 1
+	}
+	return &model.AgentResponse{
+		AgentID:     da.agentType,
+		AgentType:   da.agentType,
+		Status:      outcome.Status,
+		Result:      result,
+		RiskScore:   outcome.RiskScore,
+		Explanation: outcome.Explanation,
+		Confidence:  confidence,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
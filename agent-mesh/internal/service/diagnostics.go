@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+)
+
+type diagnosticsKey struct{}
+
+// diagnosticsRecorder collects the downstream calls and fallbacks of one request.
+// Agents start one in Process and attach it to their response; clients record into
+// it through the request context.
+type diagnosticsRecorder struct {
+	mu          sync.Mutex
+	start       time.Time
+	diagnostics model.AgentDiagnostics
+}
+
+// startDiagnostics returns a context that records the downstream calls made with it
+func startDiagnostics(ctx context.Context) (context.Context, *diagnosticsRecorder) {
+	dr := &diagnosticsRecorder{start: time.Now()}
+	return context.WithValue(ctx, diagnosticsKey{}, dr), dr
+}
+
+// recordCall notes a downstream call that began at start and failed if err is set.
+// It does nothing when ctx carries no recorder.
+func recordCall(ctx context.Context, target string, start time.Time, err error) {
+	dr, ok := ctx.Value(diagnosticsKey{}).(*diagnosticsRecorder)
+	if !ok {
+		return
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.diagnostics.CallsAttempted++
+	if err == nil {
+		dr.diagnostics.CallsSucceeded++
+	}
+	dr.diagnostics.Calls = append(dr.diagnostics.Calls, model.DownstreamCall{
+		Target:     target,
+		Succeeded:  err == nil,
+		DurationMs: millisSince(start),
+	})
+}
+
+// recordFallback notes that rules or mock data stood in for a downstream system
+func recordFallback(ctx context.Context, reason string) {
+	dr, ok := ctx.Value(diagnosticsKey{}).(*diagnosticsRecorder)
+	if !ok {
+		return
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.diagnostics.FallbackUsed = true
+	dr.diagnostics.Fallbacks = append(dr.diagnostics.Fallbacks, reason)
+}
+
+// recordMLHealth notes the ML service's health as of the request's model call
+func recordMLHealth(ctx context.Context, health *model.MLHealth) {
+	dr, ok := ctx.Value(diagnosticsKey{}).(*diagnosticsRecorder)
+	if !ok {
+		return
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.diagnostics.ML = health
+}
+
+// attach sets the collected diagnostics on a response. A model that fell back to
+// rules counts as a fallback.
+func (dr *diagnosticsRecorder) attach(resp *model.AgentResponse) {
+	if resp == nil {
+		return
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	diagnostics := dr.diagnostics
+	if resp.Model != nil && resp.Model.Fallback != "" {
+		diagnostics.FallbackUsed = true
+		diagnostics.Fallbacks = append(diagnostics.Fallbacks, "model:"+resp.Model.Fallback)
+	}
+	diagnostics.ProcessingMs = millisSince(dr.start)
+	resp.Diagnostics = &diagnostics
+}
+
+func millisSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
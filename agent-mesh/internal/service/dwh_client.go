@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// DWHClient reads user profiles and transaction history from the data warehouse in
+// Banking Integrations (Layer 5)
+type DWHClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewDWHClient creates a new DWH client
+func NewDWHClient(cfg *config.BankingIntegrationsConfig) *DWHClient {
+	return &DWHClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (dc *DWHClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, dc.httpClient, dc.baseURL)
+}
+
+// Profile returns a user's DWH profile row: total_balance, monthly_income and the like
+func (dc *DWHClient) Profile(ctx context.Context, userID string) (profile map[string]interface{}, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:dwh_profile", start, err) }(time.Now())
+
+	body, err := json.Marshal(map[string]interface{}{"query_type": "USER_PROFILE", "user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", dc.baseURL+"/api/v1/dwh/query", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	var out struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := dc.do(httpReq, &out); err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if len(out.Data) == 0 {
+		return nil, fmt.Errorf("no profile for user %s", userID)
+	}
+	return out.Data[0], nil
+}
+
+// History returns a user's transactions over the last days
+func (dc *DWHClient) History(ctx context.Context, userID string, days int) (transactions []model.DWHTransaction, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:dwh_history", start, err) }(time.Now())
+
+	endpoint := fmt.Sprintf("%s/api/v1/dwh/history/%s?days=%d", dc.baseURL, url.PathEscape(userID), days)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var out struct {
+		Transactions []model.DWHTransaction `json:"transactions"`
+	}
+	if err := dc.do(httpReq, &out); err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	return out.Transactions, nil
+}
+
+func (dc *DWHClient) do(httpReq *http.Request, out interface{}) error {
+	httpReq.Header.Set("X-API-Key", dc.apiKey.Get())
+
+	resp, err := dc.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("banking integrations error: %s", string(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// ErrExportRefused is returned for an export Banking Integrations will not make, such
+// as one over too long a range or for another user's account
+var ErrExportRefused = errors.New("export refused")
+
+// ExportClient creates transaction export download links with Banking Integrations
+// (Layer 5)
+type ExportClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewExportClient creates a new export client
+func NewExportClient(cfg *config.BankingIntegrationsConfig) *ExportClient {
+	return &ExportClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (ec *ExportClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, ec.httpClient, ec.baseURL)
+}
+
+// CreateLink returns a link that downloads an export. An export the bank refuses is
+// an ErrExportRefused, wrapped with Banking Integrations' reason.
+func (ec *ExportClient) CreateLink(ctx context.Context, req *model.ExportLinkRequest) (link *model.ExportLink, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:exports", start, err) }(time.Now())
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/exports/links", ec.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", ec.apiKey.Get())
+
+	resp, err := ec.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound {
+		var apiErr struct {
+			Details string `json:"details"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return nil, fmt.Errorf("%w: %s", ErrExportRefused, apiErr.Details)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	link = &model.ExportLink{}
+	if err := json.Unmarshal(body, link); err != nil {
+		return nil, fmt.Errorf("failed to parse export link: %w", err)
+	}
+	return link, nil
+}
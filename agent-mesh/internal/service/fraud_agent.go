@@ -12,17 +12,38 @@ import (
 // FraudAgent handles fraud detection using ML models and pattern analysis
 type FraudAgent struct {
 	*AgentBase
+	scorer        *ModelScorer
+	decisions     *FraudDecisionStore
+	accountStatus *AccountStatusClient // Nil leaves accounts alone on rejections
+	graph         *TransferGraph       // Nil scores without the transfer graph
 }
 
 // NewFraudAgent creates a new fraud agent
-func NewFraudAgent(base *AgentBase) *FraudAgent {
+func NewFraudAgent(base *AgentBase, scorer *ModelScorer, decisions *FraudDecisionStore) *FraudAgent {
 	return &FraudAgent{
 		AgentBase: base,
+		scorer:    scorer,
+		decisions: decisions,
 	}
 }
 
+// SetAccountStatus reports rejected transactions to Banking Integrations, which
+// freezes or locks the account when the score is high enough
+func (fa *FraudAgent) SetAccountStatus(accountStatus *AccountStatusClient) {
+	fa.accountStatus = accountStatus
+}
+
+// SetTransferGraph scores transfers against who else pays the beneficiary and whom
+// else the sender pays, as the graph_risk feature
+func (fa *FraudAgent) SetTransferGraph(graph *TransferGraph) {
+	fa.graph = graph
+}
+
 // Process processes a fraud check request
-func (fa *FraudAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+func (fa *FraudAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
 	log.Info().
 		Str("task", req.Task).
 		Str("request_id", req.RequestID).
@@ -39,32 +60,84 @@ func (fa *FraudAgent) Process(ctx context.Context, req *model.AgentRequest) (*mo
 	toAccount, _ := data["to_account"].(string)
 	userID, _ := inputCtx["user_id"].(string)
 
-	// Perform fraud checks
-	fraudScore := fa.calculateFraudScore(ctx, amount, toAccount, userID, inputCtx)
-	
-	// Determine status based on fraud score
-	status := "APPROVED"
-	explanation := "No fraud patterns detected"
-	
-	if fraudScore > 0.7 {
-		status = "REJECTED"
-		explanation = "High fraud risk detected. Transaction flagged for manual review."
-	} else if fraudScore > 0.4 {
-		status = "PENDING"
-		explanation = "Moderate fraud risk. Additional verification required."
+	// Perform fraud checks with the registry's model, or the rules when it cannot be used
+	inputs := make(map[string]interface{}, len(inputCtx)+1)
+	for k, v := range inputCtx {
+		inputs[k] = v
+	}
+	if _, ok := data["amount"].(float64); ok {
+		inputs["amount"] = amount
+	}
+
+	// Mule patterns show in who pays whom, not in the sender's own counters
+	var graphSignals *model.GraphSignals
+	if fa.graph != nil && userID != "" && toAccount != "" {
+		graphSignals = fa.graph.Signals(ctx, userID, toAccount)
+		inputs["graph_risk"] = graphSignals.GraphRisk
+	}
+
+	fraudScore, version := fa.score(ctx, inputCtx, inputs, amount)
+	status, explanation := fraudStatus(fraudScore)
+
+	// Scores from rules because the request lacked what the model needs are less certain
+	confidence := 0.85
+	if len(version.MissingFeatures) > 0 {
+		confidence = 0.6
 	}
 
 	log.Info().
 		Float64("fraud_score", fraudScore).
 		Str("status", status).
+		Str("model_version", version.Version).
+		Str("model_source", version.Source).
 		Msg("Fraud check completed")
 
+	flags := fraudFlags(amount, inputCtx)
+	if graphSignals != nil {
+		flags = append(flags, graphSignals.Flags...)
+	}
 	result := map[string]interface{}{
 		"fraud_score":    fraudScore,
-		"risk_level":     fa.getRiskLevel(fraudScore),
-		"flags":          fa.getFraudFlags(ctx, amount, toAccount, userID, inputCtx),
+		"risk_level":     fraudRiskLevel(fraudScore),
+		"flags":          flags,
 		"recommendation": fa.getRecommendation(fraudScore),
 	}
+	if graphSignals != nil {
+		result["graph"] = graphSignals
+
+		// A transfer let through is an edge before the DWH has it
+		if status != "REJECTED" {
+			fa.graph.Record(userID, toAccount, amount, time.Now())
+		}
+	}
+
+	// Keep the decision so a reviewer can later confirm or overturn it
+	if req.RequestID != "" {
+		tenantID, _ := inputCtx["tenant_id"].(string)
+		fa.decisions.Record(&model.FraudDecision{
+			RequestID:  req.RequestID,
+			UserID:     userID,
+			TenantID:   tenantID,
+			Amount:     amount,
+			ToAccount:  toAccount,
+			FraudScore: fraudScore,
+			Status:     status,
+			Flags:      flags,
+			Features:   numericInputs(inputs),
+			Model:      version,
+			DecidedAt:  time.Now(),
+		})
+	}
+
+	if status == "REJECTED" && fa.accountStatus != nil {
+		fromAccount, _ := data["from_account"].(string)
+		if action := fa.reportFraud(ctx, req.RequestID, userID, fromAccount, fraudScore, flags); action != "" {
+			result["account_action"] = action
+			if action == "frozen" || action == "locked" {
+				explanation += fmt.Sprintf(" The account has been %s until the bank reviews it.", action)
+			}
+		}
+	}
 
 	return &model.AgentResponse{
 		AgentID:     fa.agentType,
@@ -73,14 +146,100 @@ func (fa *FraudAgent) Process(ctx context.Context, req *model.AgentRequest) (*mo
 		Result:      result,
 		RiskScore:   fraudScore,
 		Explanation: explanation,
-		Confidence:  0.85,
+		Confidence:  confidence,
 		Timestamp:   time.Now(),
 		RequestID:   req.RequestID,
+		Model:       version,
 	}, nil
 }
 
-// calculateFraudScore calculates fraud risk score using ML model simulation
-func (fa *FraudAgent) calculateFraudScore(ctx context.Context, amount float64, toAccount string, userID string, context map[string]interface{}) float64 {
+// Rescore scores a past decision again from its snapshot with the current model and
+// thresholds, in shadow: nothing is recorded, reported or added to the transfer graph.
+// The snapshot's graph_risk is used as it was.
+func (fa *FraudAgent) Rescore(ctx context.Context, decision *model.FraudDecision) (float64, string, *model.ModelVersion) {
+	inputCtx := map[string]interface{}{"user_id": decision.UserID}
+	if decision.TenantID != "" {
+		inputCtx["tenant_id"] = decision.TenantID
+	}
+	inputs := make(map[string]interface{}, len(decision.Features)+2)
+	for k, v := range inputCtx {
+		inputs[k] = v
+	}
+	for k, v := range decision.Features {
+		inputs[k] = v
+	}
+
+	fraudScore, version := fa.score(ctx, inputCtx, inputs, decision.Amount)
+	status, _ := fraudStatus(fraudScore)
+	return fraudScore, status, version
+}
+
+// score scores a transaction with the registry's model, or the rules when it cannot
+// be used
+func (fa *FraudAgent) score(ctx context.Context, inputCtx, inputs map[string]interface{}, amount float64) (float64, *model.ModelVersion) {
+	mlResult, version := fa.scorer.Predict(ctx, "fraud", inputCtx, inputs)
+	fraudScore, scored := mlResult["fraud_score"].(float64)
+	if mlResult != nil && !scored {
+		version.Source = "rules"
+		version.Fallback = "invalid_ml_result"
+	}
+	if !scored {
+		fraudScore = ruleFraudScore(amount, inputs)
+	}
+	return fraudScore, version
+}
+
+// fraudStatus decides a transaction by its fraud score
+func fraudStatus(fraudScore float64) (string, string) {
+	switch {
+	case fraudScore > 0.7:
+		return "REJECTED", "High fraud risk detected. Transaction flagged for manual review."
+	case fraudScore > 0.4:
+		return "PENDING", "Moderate fraud risk. Additional verification required."
+	}
+	return "APPROVED", "No fraud patterns detected"
+}
+
+// reportFraud tells Banking Integrations about a rejection and returns what it did
+// to the account, or "" when it did nothing or could not be told
+func (fa *FraudAgent) reportFraud(ctx context.Context, requestID, userID, accountID string, fraudScore float64, flags []string) string {
+	if userID == "" && accountID == "" {
+		return ""
+	}
+	result, err := fa.accountStatus.ReportFraud(ctx, &model.FraudSignal{
+		UserID:     userID,
+		AccountID:  accountID,
+		FraudScore: fraudScore,
+		Decision:   "REJECTED",
+		RequestID:  requestID,
+		Flags:      flags,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("request_id", requestID).Msg("Failed to report fraud signal, account not restricted")
+		return ""
+	}
+	if result.Action == "none" {
+		return ""
+	}
+	log.Warn().Str("request_id", requestID).Str("user_id", userID).Str("account_id", accountID).
+		Str("action", result.Action).Msg("Account restricted on fraud rejection")
+	return result.Action
+}
+
+// numericInputs returns the numeric values of the scoring inputs
+func numericInputs(inputs map[string]interface{}) map[string]float64 {
+	features := make(map[string]float64)
+	for k, v := range inputs {
+		if f, ok := v.(float64); ok {
+			features[k] = f
+		}
+	}
+	return features
+}
+
+// ruleFraudScore scores a transaction with the fraud rules, for when the ML model
+// cannot be used
+func ruleFraudScore(amount float64, context map[string]interface{}) float64 {
 	score := 0.0
 
 	// Amount-based risk
@@ -118,6 +277,11 @@ func (fa *FraudAgent) calculateFraudScore(ctx context.Context, amount float64, t
 		score += locationRisk * 0.15
 	}
 
+	// Mule patterns across senders and beneficiaries
+	if graphRisk, ok := context["graph_risk"].(float64); ok {
+		score += graphRisk * 0.35
+	}
+
 	// Velocity check (too many transactions)
 	if txnCount, ok := context["transaction_count_24h"].(float64); ok {
 		if txnCount > 10 {
@@ -135,8 +299,8 @@ func (fa *FraudAgent) calculateFraudScore(ctx context.Context, amount float64, t
 	return score
 }
 
-// getRiskLevel returns risk level based on score
-func (fa *FraudAgent) getRiskLevel(score float64) string {
+// fraudRiskLevel returns risk level based on score
+func fraudRiskLevel(score float64) string {
 	if score > 0.7 {
 		return "HIGH"
 	} else if score > 0.4 {
@@ -145,8 +309,8 @@ func (fa *FraudAgent) getRiskLevel(score float64) string {
 	return "LOW"
 }
 
-// getFraudFlags returns list of fraud flags
-func (fa *FraudAgent) getFraudFlags(ctx context.Context, amount float64, toAccount string, userID string, context map[string]interface{}) []string {
+// fraudFlags returns list of fraud flags
+func fraudFlags(amount float64, context map[string]interface{}) []string {
 	flags := []string{}
 
 	if amount > 100000 {
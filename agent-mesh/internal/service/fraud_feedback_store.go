@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+)
+
+// ErrDecisionNotFound is returned when a fraud decision is not in the store
+var ErrDecisionNotFound = errors.New("fraud decision not found")
+
+// FraudDecisionStore keeps the Fraud Agent's decisions so reviewers can label them
+// as confirmed fraud or legitimate. Once the limit is reached the oldest decisions
+// are dropped first.
+type FraudDecisionStore struct {
+	mu        sync.RWMutex
+	limit     int
+	decisions map[string]*model.FraudDecision // Keyed by request ID
+	order     []string                        // Request IDs, oldest first
+}
+
+// NewFraudDecisionStore creates an empty store keeping up to limit decisions
+func NewFraudDecisionStore(limit int) *FraudDecisionStore {
+	return &FraudDecisionStore{
+		limit:     limit,
+		decisions: make(map[string]*model.FraudDecision),
+	}
+}
+
+// Record stores a decision. A decision for a request ID already stored replaces it
+// but keeps its label.
+func (fs *FraudDecisionStore) Record(decision *model.FraudDecision) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if previous, ok := fs.decisions[decision.RequestID]; ok {
+		decision.Label = previous.Label
+		fs.decisions[decision.RequestID] = decision
+		return
+	}
+
+	fs.decisions[decision.RequestID] = decision
+	fs.order = append(fs.order, decision.RequestID)
+	for fs.limit > 0 && len(fs.order) > fs.limit {
+		delete(fs.decisions, fs.order[0])
+		fs.order = fs.order[1:]
+	}
+}
+
+// Label records a reviewer's verdict on a decision
+func (fs *FraudDecisionStore) Label(requestID string, req *model.FraudLabelRequest) (*model.FraudDecision, error) {
+	if req.Label != model.FraudLabelFraud && req.Label != model.FraudLabelLegitimate {
+		return nil, fmt.Errorf("label must be %s or %s", model.FraudLabelFraud, model.FraudLabelLegitimate)
+	}
+	if req.LabeledBy == "" {
+		return nil, fmt.Errorf("labeled_by is required")
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	decision, ok := fs.decisions[requestID]
+	if !ok {
+		return nil, ErrDecisionNotFound
+	}
+	decision.Label = &model.FraudLabel{
+		Label:     req.Label,
+		LabeledBy: req.LabeledBy,
+		Note:      req.Note,
+		LabeledAt: time.Now(),
+	}
+	return copyDecision(decision), nil
+}
+
+// Get returns a copy of a decision
+func (fs *FraudDecisionStore) Get(requestID string) (*model.FraudDecision, bool) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	decision, ok := fs.decisions[requestID]
+	if !ok {
+		return nil, false
+	}
+	return copyDecision(decision), true
+}
+
+// List returns decisions made in [from, to), newest first. labeled filters on
+// whether a decision has a label: "true", "false", or "" for all.
+func (fs *FraudDecisionStore) List(from, to time.Time, labeled string, limit int) []model.FraudDecision {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var decisions []model.FraudDecision
+	for i := len(fs.order) - 1; i >= 0; i-- {
+		decision := fs.decisions[fs.order[i]]
+		if !inWindow(decision.DecidedAt, from, to) {
+			continue
+		}
+		if (labeled == "true" && decision.Label == nil) || (labeled == "false" && decision.Label != nil) {
+			continue
+		}
+		decisions = append(decisions, *copyDecision(decision))
+		if limit > 0 && len(decisions) >= limit {
+			break
+		}
+	}
+	return decisions
+}
+
+// Metrics computes precision and recall over the labeled decisions made in
+// [from, to), per day or per week
+func (fs *FraudDecisionStore) Metrics(from, to time.Time, period string) *model.FraudMetrics {
+	step := 24 * time.Hour
+	if period == "week" {
+		step = 7 * 24 * time.Hour
+	} else {
+		period = "day"
+	}
+
+	start := from.Truncate(24 * time.Hour)
+	metrics := &model.FraudMetrics{Period: period, From: start, To: to}
+	metrics.Total.Start = start
+	for t := start; t.Before(to); t = t.Add(step) {
+		metrics.Buckets = append(metrics.Buckets, model.FraudMetricsBucket{Start: t})
+	}
+
+	fs.mu.RLock()
+	for _, decision := range fs.decisions {
+		if !inWindow(decision.DecidedAt, start, to) {
+			continue
+		}
+		i := int(decision.DecidedAt.Sub(start) / step)
+		countDecision(&metrics.Buckets[i], decision)
+		countDecision(&metrics.Total, decision)
+	}
+	fs.mu.RUnlock()
+
+	for i := range metrics.Buckets {
+		finishBucket(&metrics.Buckets[i])
+	}
+	finishBucket(&metrics.Total)
+	return metrics
+}
+
+// TrainingData returns the labeled decisions made in [from, to), oldest first
+func (fs *FraudDecisionStore) TrainingData(from, to time.Time) []model.FraudDecision {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var rows []model.FraudDecision
+	for _, requestID := range fs.order {
+		decision := fs.decisions[requestID]
+		if decision.Label != nil && inWindow(decision.DecidedAt, from, to) {
+			rows = append(rows, *copyDecision(decision))
+		}
+	}
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].DecidedAt.Before(rows[b].DecidedAt) })
+	return rows
+}
+
+// countDecision adds a decision to a bucket's confusion matrix
+func countDecision(bucket *model.FraudMetricsBucket, decision *model.FraudDecision) {
+	bucket.Decisions++
+	if decision.Label == nil {
+		return
+	}
+
+	bucket.Labeled++
+	fraud := decision.Label.Label == model.FraudLabelFraud
+	switch {
+	case decision.Flagged() && fraud:
+		bucket.TruePositives++
+	case decision.Flagged():
+		bucket.FalsePositives++
+	case fraud:
+		bucket.FalseNegatives++
+	default:
+		bucket.TrueNegatives++
+	}
+}
+
+// finishBucket computes precision and recall from the counts, leaving them 0 when
+// there is nothing to divide by
+func finishBucket(bucket *model.FraudMetricsBucket) {
+	if flagged := bucket.TruePositives + bucket.FalsePositives; flagged > 0 {
+		bucket.Precision = float64(bucket.TruePositives) / float64(flagged)
+	}
+	if fraud := bucket.TruePositives + bucket.FalseNegatives; fraud > 0 {
+		bucket.Recall = float64(bucket.TruePositives) / float64(fraud)
+	}
+}
+
+func inWindow(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+func copyDecision(decision *model.FraudDecision) *model.FraudDecision {
+	decisionCopy := *decision
+	if decision.Label != nil {
+		label := *decision.Label
+		decisionCopy.Label = &label
+	}
+	return &decisionCopy
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrRescoreInProgress is returned when a re-scoring run is started while one is running
+var ErrRescoreInProgress = errors.New("a re-scoring run is already running")
+
+// Re-scoring limits
+const (
+	rescoreRunLimit    = 20   // Runs kept, oldest dropped first
+	rescoreChangeLimit = 1000 // Changed decisions listed per run
+)
+
+// FraudRescorer replays the Fraud Agent's stored decisions through its current model
+// and thresholds, so risk teams can see how past transactions would score after a
+// model or threshold change. It runs in shadow mode: the stored decisions, their
+// labels and the accounts are left as they are.
+type FraudRescorer struct {
+	agent     *FraudAgent
+	decisions *FraudDecisionStore
+
+	mu      sync.Mutex
+	runs    []*model.RescoreRun // Oldest first
+	running bool
+}
+
+// NewFraudRescorer creates a re-scorer of the decisions in the store
+func NewFraudRescorer(agent *FraudAgent, decisions *FraudDecisionStore) *FraudRescorer {
+	return &FraudRescorer{
+		agent:     agent,
+		decisions: decisions,
+	}
+}
+
+// Start begins re-scoring the decisions made in [from, to) in the background and
+// returns the new run
+func (fr *FraudRescorer) Start(req *model.RescoreRequest) (*model.RescoreRun, error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if fr.running {
+		return nil, ErrRescoreInProgress
+	}
+
+	run := &model.RescoreRun{
+		RunID:       ids.New(ids.Rescore),
+		Status:      model.RescoreRunning,
+		From:        req.From,
+		To:          req.To,
+		RequestedBy: req.RequestedBy,
+		StartedAt:   time.Now(),
+		Decided:     model.FraudMetricsBucket{Start: req.From},
+		Current:     model.FraudMetricsBucket{Start: req.From},
+	}
+	fr.runs = append(fr.runs, run)
+	if len(fr.runs) > rescoreRunLimit {
+		fr.runs = fr.runs[1:]
+	}
+	fr.running = true
+
+	go fr.execute(context.Background(), run)
+
+	return copyRescoreRun(run), nil
+}
+
+// Run returns a copy of a run
+func (fr *FraudRescorer) Run(runID string) (*model.RescoreRun, bool) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	for _, run := range fr.runs {
+		if run.RunID == runID {
+			return copyRescoreRun(run), true
+		}
+	}
+	return nil, false
+}
+
+// Runs returns the recent runs, newest first, without their changes
+func (fr *FraudRescorer) Runs() []model.RescoreRun {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	runs := make([]model.RescoreRun, 0, len(fr.runs))
+	for i := len(fr.runs) - 1; i >= 0; i-- {
+		summary := *fr.runs[i]
+		summary.Changes = nil
+		runs = append(runs, summary)
+	}
+	return runs
+}
+
+// execute re-scores every decision in the run's window, oldest first, and completes
+// the run
+func (fr *FraudRescorer) execute(ctx context.Context, run *model.RescoreRun) {
+	defer func() {
+		fr.mu.Lock()
+		fr.running = false
+		fr.mu.Unlock()
+	}()
+
+	decisions := fr.decisions.List(run.From, run.To, "", 0)
+	fr.update(func() { run.Decisions = len(decisions) })
+	log.Info().Str("run_id", run.RunID).Int("decisions", len(decisions)).Msg("Fraud re-scoring started")
+
+	var shift float64
+	for i := len(decisions) - 1; i >= 0; i-- {
+		decided := &decisions[i]
+		score, status, version := fr.agent.Rescore(ctx, decided)
+		shift += score - decided.FraudScore
+
+		current := *decided
+		current.FraudScore = score
+		current.Status = status
+
+		fr.update(func() {
+			run.Rescored++
+			countDecision(&run.Decided, decided)
+			countDecision(&run.Current, &current)
+
+			change := rescoreChange(decided.Status, status)
+			switch change {
+			case "":
+				run.Unchanged++
+				return
+			case model.RescoreNewlyFlagged:
+				run.NewlyFlagged++
+			case model.RescoreNewlyCleared:
+				run.NewlyCleared++
+			case model.RescoreEscalated:
+				run.Escalated++
+			case model.RescoreDeescalated:
+				run.Deescalated++
+			}
+			if len(run.Changes) >= rescoreChangeLimit {
+				run.ChangesTruncated = true
+				return
+			}
+			entry := model.RescoreChange{
+				RequestID:     decided.RequestID,
+				UserID:        decided.UserID,
+				Amount:        decided.Amount,
+				DecidedAt:     decided.DecidedAt,
+				Change:        change,
+				DecidedScore:  decided.FraudScore,
+				DecidedStatus: decided.Status,
+				DecidedModel:  decided.Model,
+				CurrentScore:  score,
+				CurrentStatus: status,
+				CurrentModel:  version,
+			}
+			if decided.Label != nil {
+				entry.Label = decided.Label.Label
+			}
+			run.Changes = append(run.Changes, entry)
+		})
+	}
+
+	var final model.RescoreRun
+	fr.update(func() {
+		if run.Rescored > 0 {
+			run.MeanShift = shift / float64(run.Rescored)
+		}
+		finishBucket(&run.Decided)
+		finishBucket(&run.Current)
+		now := time.Now()
+		run.FinishedAt = &now
+		run.Status = model.RescoreCompleted
+		final = *run
+	})
+
+	log.Info().
+		Str("run_id", final.RunID).
+		Int("rescored", final.Rescored).
+		Int("newly_flagged", final.NewlyFlagged).
+		Int("newly_cleared", final.NewlyCleared).
+		Float64("mean_shift", final.MeanShift).
+		Dur("duration", final.FinishedAt.Sub(final.StartedAt)).
+		Msg("Fraud re-scoring finished")
+}
+
+// update changes a run under the lock, as runs are read while they execute
+func (fr *FraudRescorer) update(change func()) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	change()
+}
+
+// rescoreChange names how a decision's status changed, or returns "" when it did not
+func rescoreChange(decided, current string) string {
+	switch {
+	case decided == current:
+		return ""
+	case decided == "APPROVED":
+		return model.RescoreNewlyFlagged
+	case current == "APPROVED":
+		return model.RescoreNewlyCleared
+	case current == "REJECTED":
+		return model.RescoreEscalated
+	}
+	return model.RescoreDeescalated
+}
+
+func copyRescoreRun(run *model.RescoreRun) *model.RescoreRun {
+	runCopy := *run
+	runCopy.Changes = append([]model.RescoreChange(nil), run.Changes...)
+	return &runCopy
+}
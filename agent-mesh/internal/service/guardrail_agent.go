@@ -3,26 +3,42 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aibanking/agent-mesh/internal/model"
 	"github.com/rs/zerolog/log"
 )
 
-// GuardrailAgent handles RBI regulations and bank policy validation
+// GuardrailAgent handles RBI regulations and bank policy validation with the
+// loaded guardrail rule packs
 type GuardrailAgent struct {
 	*AgentBase
+	rules    *GuardrailRules
+	calendar *CalendarClient
+	accounts *AccountStatusClient // Source of the account_status metric
 }
 
 // NewGuardrailAgent creates a new guardrail agent
-func NewGuardrailAgent(base *AgentBase) *GuardrailAgent {
+func NewGuardrailAgent(base *AgentBase, rules *GuardrailRules, calendar *CalendarClient) *GuardrailAgent {
 	return &GuardrailAgent{
 		AgentBase: base,
+		rules:     rules,
+		calendar:  calendar,
 	}
 }
 
+// SetAccountStatus makes the account_status metric come from Banking Integrations,
+// so frozen and locked accounts fail the account_active rule
+func (ga *GuardrailAgent) SetAccountStatus(accounts *AccountStatusClient) {
+	ga.accounts = accounts
+}
+
 // Process processes a guardrail validation request
-func (ga *GuardrailAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+func (ga *GuardrailAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
 	log.Info().
 		Str("task", req.Task).
 		Str("request_id", req.RequestID).
@@ -36,27 +52,37 @@ func (ga *GuardrailAgent) Process(ctx context.Context, req *model.AgentRequest)
 
 	amount, _ := data["amount"].(float64)
 	userID, _ := inputCtx["user_id"].(string)
+	tenantID, _ := inputCtx["tenant_id"].(string)
+	accountType, _ := inputCtx["account_type"].(string)
 
-	// Perform guardrail checks
-	checks := ga.performGuardrailChecks(ctx, amount, userID, inputCtx)
-	
-	// Determine if all checks passed
-	allPassed := true
+	// Evaluate the rule packs
+	metrics := ga.guardrailMetrics(ctx, amount, userID, inputCtx, data)
+	results := ga.rules.Evaluate(req.Task, tenantID, accountType, metrics)
+
+	checks := make(map[string]bool, len(results))
 	failedChecks := []string{}
-	
-	for check, passed := range checks {
-		if !passed {
-			allPassed = false
-			failedChecks = append(failedChecks, check)
+	failedRules := []map[string]interface{}{}
+	messages := []string{}
+	for _, r := range results {
+		checks[r.Rule] = r.Passed
+		if !r.Passed {
+			failedChecks = append(failedChecks, r.Rule)
+			failedRules = append(failedRules, map[string]interface{}{
+				"pack":    r.Pack,
+				"rule":    r.Rule,
+				"message": r.Message,
+			})
+			messages = append(messages, fmt.Sprintf("%s (%s/%s)", r.Message, r.Pack, r.Rule))
 		}
 	}
+	allPassed := len(failedChecks) == 0
 
 	status := "APPROVED"
 	explanation := "All guardrail checks passed"
-	
+
 	if !allPassed {
 		status = "REJECTED"
-		explanation = fmt.Sprintf("Guardrail checks failed: %v", failedChecks)
+		explanation = "Guardrail checks failed: " + strings.Join(messages, "; ")
 	}
 
 	log.Info().
@@ -65,10 +91,25 @@ func (ga *GuardrailAgent) Process(ctx context.Context, req *model.AgentRequest)
 		Msg("Guardrail validation completed")
 
 	result := map[string]interface{}{
-		"checks":         checks,
-		"all_passed":     allPassed,
-		"failed_checks":  failedChecks,
+		"checks":          checks,
+		"all_passed":      allPassed,
+		"failed_checks":   failedChecks,
+		"failed_rules":    failedRules,
+		"rule_results":    results,
 		"validated_rules": ga.getValidatedRules(checks),
+		"limits":          ga.limitUsage(results, metrics),
+	}
+
+	// Transfers outside their rail's hours are not rejected, but the user is told
+	// when the money will actually arrive
+	if settlement := ga.settlementEstimate(ctx, req.Task, data); settlement != nil {
+		result["settlement"] = settlement
+		if settlement.Delayed {
+			result["warnings"] = []string{settlement.Message}
+			if allPassed {
+				explanation += ". " + settlement.Message
+			}
+		}
 	}
 
 	return &model.AgentResponse{
@@ -84,54 +125,113 @@ func (ga *GuardrailAgent) Process(ctx context.Context, req *model.AgentRequest)
 	}, nil
 }
 
-// performGuardrailChecks performs all guardrail validations
-func (ga *GuardrailAgent) performGuardrailChecks(ctx context.Context, amount float64, userID string, context map[string]interface{}) map[string]bool {
-	checks := make(map[string]bool)
+// settlementEstimate asks the banking calendar when a transfer will be credited. It
+// returns nil for tasks that are not transfers or when the calendar cannot be reached.
+func (ga *GuardrailAgent) settlementEstimate(ctx context.Context, task string, data map[string]interface{}) *model.SettlementEstimate {
+	rail, _ := data["transfer_type"].(string)
+	if rail == "" && strings.HasPrefix(task, "TRANSFER_") {
+		rail = strings.TrimPrefix(task, "TRANSFER_")
+	}
+	if rail == "" {
+		return nil
+	}
 
-	// Daily limit check (RBI regulation: 2 lakh for savings account)
-	dailyLimit := 200000.0
-	if dailyUsed, ok := context["daily_transaction_amount"].(float64); ok {
-		checks["daily_limit"] = (dailyUsed + amount) <= dailyLimit
-	} else {
-		checks["daily_limit"] = amount <= dailyLimit
+	estimate, err := ga.calendar.EstimateSettlement(ctx, strings.ToUpper(rail), time.Now())
+	if err != nil {
+		log.Warn().Err(err).Str("rail", rail).Msg("Settlement estimate unavailable")
+		return nil
 	}
+	return estimate
+}
+
+// untrustedDeviceRisk is the device risk from which a device is treated as untrusted
+const untrustedDeviceRisk = 0.7
 
-	// Single transaction limit
-	singleTxnLimit := 100000.0
-	checks["single_transaction_limit"] = amount <= singleTxnLimit
+// guardrailMetrics collects the values rules can refer to: the input context and
+// transaction data fields, and the derived amount, daily_total, blacklisted,
+// account_status and untrusted_device_amount
+func (ga *GuardrailAgent) guardrailMetrics(ctx context.Context, amount float64, userID string, inputCtx, data map[string]interface{}) map[string]interface{} {
+	metrics := derivedMetrics(amount, inputCtx, data)
+	metrics["blacklisted"] = ga.isBlacklisted(ctx, userID)
+	ga.accountStatus(ctx, metrics, userID, inputCtx, data)
+	return metrics
+}
 
-	// Velocity check (max 10 transactions per day)
-	if txnCount, ok := context["transaction_count_24h"].(float64); ok {
-		checks["velocity_limit"] = txnCount < 10
-	} else {
-		checks["velocity_limit"] = true
+// derivedMetrics collects the metrics that come from the request alone: the input
+// context and transaction data fields, amount, daily_total and
+// untrusted_device_amount
+func derivedMetrics(amount float64, inputCtx, data map[string]interface{}) map[string]interface{} {
+	metrics := make(map[string]interface{}, len(inputCtx)+len(data)+3)
+	for k, v := range inputCtx {
+		metrics[k] = v
 	}
+	for k, v := range data {
+		metrics[k] = v
+	}
+
+	dailyUsed, _ := inputCtx["daily_transaction_amount"].(float64)
+	metrics["amount"] = amount
+	metrics["daily_total"] = dailyUsed + amount
 
-	// Beneficiary age check (minimum 24 hours for NEFT/RTGS)
-	if beneficiaryAge, ok := context["beneficiary_age_days"].(float64); ok {
-		checks["beneficiary_age"] = beneficiaryAge >= 1 // At least 1 day old
-	} else {
-		checks["beneficiary_age"] = false // Unknown beneficiary age
+	// The amount counts against the untrusted device limit only from a device with
+	// little or no history
+	metrics["untrusted_device_amount"] = 0.0
+	if deviceRisk, ok := inputCtx["device_risk"].(float64); ok && deviceRisk >= untrustedDeviceRisk {
+		metrics["untrusted_device_amount"] = amount
 	}
+	return metrics
+}
 
-	// KYC status check
-	if kycStatus, ok := context["kyc_status"].(string); ok {
-		checks["kyc_verified"] = kycStatus == "VERIFIED"
-	} else {
-		checks["kyc_verified"] = true // Assume verified if not provided
+// accountStatus sets the account_status metric from Banking Integrations, replacing
+// any the caller sent. When the status cannot be read the caller's value, if any,
+// is kept and the account_active rule's if_missing decides.
+func (ga *GuardrailAgent) accountStatus(ctx context.Context, metrics map[string]interface{}, userID string, inputCtx, data map[string]interface{}) {
+	if ga.accounts == nil {
+		return
+	}
+	accountID, _ := data["from_account"].(string)
+	if accountID == "" {
+		accountID, _ = inputCtx["account_id"].(string)
+	}
+	if userID == "" && accountID == "" {
+		return
 	}
 
-	// Account status check
-	if accountStatus, ok := context["account_status"].(string); ok {
-		checks["account_active"] = accountStatus == "ACTIVE"
-	} else {
-		checks["account_active"] = true
+	status, err := ga.accounts.Status(ctx, userID, accountID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Str("account_id", accountID).Msg("Account status unavailable")
+		return
 	}
+	metrics["account_status"] = status.Status
+}
 
-	// RBI blacklist check
-	checks["rbi_blacklist"] = !ga.isBlacklisted(ctx, userID)
+// limitUsage reports the numbers behind the numeric limit rules, so a rejection can
+// be explained
+func (ga *GuardrailAgent) limitUsage(results []model.GuardrailRuleResult, metrics map[string]interface{}) map[string]interface{} {
+	limits := make(map[string]interface{})
+	for _, r := range results {
+		limit, ok := r.Threshold.(float64)
+		if !ok || (r.Operator != "lt" && r.Operator != "lte") {
+			continue
+		}
 
-	return checks
+		usage := map[string]interface{}{"limit": limit}
+		switch r.Metric {
+		case "daily_total":
+			usage["used"], _ = metrics["daily_transaction_amount"].(float64)
+			usage["requested"] = metrics["amount"]
+		case "amount":
+			usage["requested"] = metrics["amount"]
+		default:
+			if v, ok := r.Value.(float64); ok {
+				usage["used"] = v
+			} else {
+				usage["used"] = 0.0
+			}
+		}
+		limits[r.Rule] = usage
+	}
+	return limits
 }
 
 // getValidatedRules returns list of validated rules
@@ -166,6 +266,7 @@ func (ga *GuardrailAgent) calculateRiskScore(checks map[string]bool) float64 {
 // isBlacklisted checks if user/account is blacklisted
 func (ga *GuardrailAgent) isBlacklisted(ctx context.Context, userID string) bool {
 	// Mock implementation - in production would query RBI blacklist database
+	recordFallback(ctx, "rbi_blacklist:mock")
 	return false
 }
 
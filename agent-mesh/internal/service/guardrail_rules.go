@@ -0,0 +1,375 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Guardrail rule operators
+var guardrailOperators = map[string]bool{
+	"lt": true, "lte": true, "gt": true, "gte": true,
+	"eq": true, "neq": true, "in": true, "not_in": true,
+}
+
+// GuardrailRules holds the rule packs the Guardrail Agent evaluates. Rule names are
+// unique across packs, so a failed rule can be reported by name.
+type GuardrailRules struct {
+	mu    sync.RWMutex
+	packs []*model.GuardrailRulePack // In load order
+}
+
+// NewGuardrailRules creates a rule set from packs, validating each
+func NewGuardrailRules(packs []*model.GuardrailRulePack) (*GuardrailRules, error) {
+	gr := &GuardrailRules{}
+	for _, pack := range packs {
+		if err := gr.Put(pack); err != nil {
+			return nil, err
+		}
+	}
+	return gr, nil
+}
+
+// LoadGuardrailRules reads rule pack files (JSON, or YAML by .yaml/.yml extension),
+// or returns the built-in RBI pack when no paths are given
+func LoadGuardrailRules(paths []string) (*GuardrailRules, error) {
+	if len(paths) == 0 {
+		return NewGuardrailRules([]*model.GuardrailRulePack{defaultGuardrailPack()})
+	}
+
+	packs := make([]*model.GuardrailRulePack, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule pack: %w", err)
+		}
+
+		format := "json"
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+		pack, err := ParseGuardrailRulePack(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		packs = append(packs, pack)
+	}
+	return NewGuardrailRules(packs)
+}
+
+// ParseGuardrailRulePack decodes a rule pack from JSON or YAML
+func ParseGuardrailRulePack(data []byte, format string) (*model.GuardrailRulePack, error) {
+	var pack model.GuardrailRulePack
+	var err error
+	if format == "yaml" {
+		err = yaml.Unmarshal(data, &pack)
+	} else {
+		err = json.Unmarshal(data, &pack)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule pack: %w", err)
+	}
+
+	// YAML decodes whole numbers as int; thresholds are compared as float64
+	for i := range pack.Rules {
+		pack.Rules[i].Threshold = normalizeThreshold(pack.Rules[i].Threshold)
+	}
+	return &pack, nil
+}
+
+// Put validates a pack and adds it, replacing a pack with the same name
+func (gr *GuardrailRules) Put(pack *model.GuardrailRulePack) error {
+	if err := validateRulePack(pack); err != nil {
+		return err
+	}
+
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	for _, other := range gr.packs {
+		if other.Name == pack.Name {
+			continue
+		}
+		for _, rule := range pack.Rules {
+			if hasRule(other, rule.Name) {
+				return fmt.Errorf("rule %s is already defined by pack %s", rule.Name, other.Name)
+			}
+		}
+	}
+
+	for i, existing := range gr.packs {
+		if existing.Name == pack.Name {
+			gr.packs[i] = pack
+			return nil
+		}
+	}
+	gr.packs = append(gr.packs, pack)
+	return nil
+}
+
+// Delete removes a pack by name, reporting whether it was loaded
+func (gr *GuardrailRules) Delete(name string) bool {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	for i, pack := range gr.packs {
+		if pack.Name == name {
+			gr.packs = append(gr.packs[:i], gr.packs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Packs returns the loaded packs in load order
+func (gr *GuardrailRules) Packs() []model.GuardrailRulePack {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	packs := make([]model.GuardrailRulePack, 0, len(gr.packs))
+	for _, pack := range gr.packs {
+		packs = append(packs, *pack)
+	}
+	return packs
+}
+
+// Evaluate runs every rule that applies to the request against its metrics. Rules
+// whose metric is missing and whose if_missing is skip are left out.
+func (gr *GuardrailRules) Evaluate(task, tenantID, accountType string, metrics map[string]interface{}) []model.GuardrailRuleResult {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	var results []model.GuardrailRuleResult
+	for _, pack := range gr.packs {
+		for _, rule := range pack.Rules {
+			if !ruleApplies(rule.AppliesTo, task, tenantID, accountType) {
+				continue
+			}
+
+			result := model.GuardrailRuleResult{
+				Pack:      pack.Name,
+				Rule:      rule.Name,
+				Metric:    rule.Metric,
+				Operator:  rule.Operator,
+				Threshold: rule.Threshold,
+			}
+
+			value, ok := metrics[rule.Metric]
+			if !ok || value == nil {
+				if rule.IfMissing == "skip" {
+					continue
+				}
+				result.Passed = rule.IfMissing != "fail"
+			} else {
+				result.Value = value
+				result.Passed = compareMetric(normalizeThreshold(value), rule.Operator, rule.Threshold)
+			}
+
+			if !result.Passed {
+				result.Message = rule.Message
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// compareMetric reports whether "value operator threshold" holds. Values of the
+// wrong type for the operator fail the rule.
+func compareMetric(value interface{}, operator string, threshold interface{}) bool {
+	switch operator {
+	case "in", "not_in":
+		list, _ := threshold.([]interface{})
+		found := false
+		for _, item := range list {
+			if fmt.Sprint(item) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "in")
+	case "eq":
+		return fmt.Sprint(value) == fmt.Sprint(threshold)
+	case "neq":
+		return fmt.Sprint(value) != fmt.Sprint(threshold)
+	}
+
+	v, ok := value.(float64)
+	t, tok := threshold.(float64)
+	if !ok || !tok {
+		return false
+	}
+	switch operator {
+	case "lt":
+		return v < t
+	case "lte":
+		return v <= t
+	case "gt":
+		return v > t
+	case "gte":
+		return v >= t
+	}
+	return false
+}
+
+// validateRulePack checks that a pack is named and each rule is complete, with a
+// threshold of the type its operator needs
+func validateRulePack(pack *model.GuardrailRulePack) error {
+	if pack == nil || pack.Name == "" {
+		return fmt.Errorf("rule pack needs a name")
+	}
+	if len(pack.Rules) == 0 {
+		return fmt.Errorf("rule pack %s has no rules", pack.Name)
+	}
+
+	seen := make(map[string]bool)
+	for i, rule := range pack.Rules {
+		where := fmt.Sprintf("rule pack %s, rule %d", pack.Name, i)
+		switch {
+		case rule.Name == "":
+			return fmt.Errorf("%s: name is required", where)
+		case seen[rule.Name]:
+			return fmt.Errorf("%s: duplicate rule name %s", where, rule.Name)
+		case rule.Metric == "":
+			return fmt.Errorf("%s (%s): metric is required", where, rule.Name)
+		case !guardrailOperators[rule.Operator]:
+			return fmt.Errorf("%s (%s): unknown operator %q", where, rule.Name, rule.Operator)
+		case rule.Threshold == nil:
+			return fmt.Errorf("%s (%s): threshold is required", where, rule.Name)
+		case rule.Message == "":
+			return fmt.Errorf("%s (%s): message is required", where, rule.Name)
+		case rule.IfMissing != "" && rule.IfMissing != "pass" && rule.IfMissing != "fail" && rule.IfMissing != "skip":
+			return fmt.Errorf("%s (%s): if_missing must be pass, fail or skip", where, rule.Name)
+		}
+		seen[rule.Name] = true
+
+		switch rule.Operator {
+		case "lt", "lte", "gt", "gte":
+			if _, ok := rule.Threshold.(float64); !ok {
+				return fmt.Errorf("%s (%s): %s needs a numeric threshold", where, rule.Name, rule.Operator)
+			}
+		case "in", "not_in":
+			if _, ok := rule.Threshold.([]interface{}); !ok {
+				return fmt.Errorf("%s (%s): %s needs a list threshold", where, rule.Name, rule.Operator)
+			}
+		}
+	}
+	return nil
+}
+
+// ruleApplies reports whether a rule covers a request
+func ruleApplies(a model.GuardrailApplicability, task, tenantID, accountType string) bool {
+	return matchesAny(a.Tasks, task) && matchesAny(a.Tenants, tenantID) && matchesAny(a.AccountTypes, accountType)
+}
+
+// matchesAny reports whether value is in list, or list is empty
+func matchesAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRule(pack *model.GuardrailRulePack, name string) bool {
+	for _, rule := range pack.Rules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeThreshold turns whole numbers into float64, as JSON decodes them
+func normalizeThreshold(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case []interface{}:
+		for i := range n {
+			n[i] = normalizeThreshold(n[i])
+		}
+	}
+	return v
+}
+
+// defaultGuardrailPack is the RBI and bank policy pack used when no packs are configured
+func defaultGuardrailPack() *model.GuardrailRulePack {
+	return &model.GuardrailRulePack{
+		Name:        "rbi-savings",
+		Version:     "v1",
+		Regulation:  "RBI",
+		Description: "RBI limits and bank policy for savings account transfers",
+		Rules: []model.GuardrailRule{
+			{
+				Name:      "daily_limit",
+				Metric:    "daily_total",
+				Operator:  "lte",
+				Threshold: 200000.0,
+				Message:   "The daily transfer limit of Rs 2,00,000 for savings accounts would be exceeded",
+			},
+			{
+				Name:      "single_transaction_limit",
+				Metric:    "amount",
+				Operator:  "lte",
+				Threshold: 100000.0,
+				Message:   "The amount is above the single transaction limit of Rs 1,00,000",
+			},
+			{
+				Name:      "velocity_limit",
+				Metric:    "transaction_count_24h",
+				Operator:  "lt",
+				Threshold: 10.0,
+				Message:   "Too many transactions in the last 24 hours",
+			},
+			{
+				Name:      "beneficiary_age",
+				Metric:    "beneficiary_age_days",
+				Operator:  "gte",
+				Threshold: 1.0,
+				IfMissing: "fail",
+				Message:   "The beneficiary was added less than 24 hours ago",
+			},
+			{
+				Name:      "untrusted_device_limit",
+				Metric:    "untrusted_device_amount",
+				Operator:  "lte",
+				Threshold: 50000.0,
+				Message:   "A device without a trusted history can transfer at most Rs 50,000 at a time",
+			},
+			{
+				Name:      "kyc_verified",
+				Metric:    "kyc_status",
+				Operator:  "eq",
+				Threshold: "VERIFIED",
+				Message:   "KYC is not verified",
+			},
+			{
+				Name:      "account_active",
+				Metric:    "account_status",
+				Operator:  "eq",
+				Threshold: "ACTIVE",
+				Message:   "The account is not active",
+			},
+			{
+				Name:      "rbi_blacklist",
+				Metric:    "blacklisted",
+				Operator:  "eq",
+				Threshold: false,
+				Message:   "The user or account is on the RBI blacklist",
+			},
+		},
+	}
+}
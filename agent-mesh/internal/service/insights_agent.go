@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Spending pattern thresholds
+const (
+	spendingUpRatio   = 1.3  // This month's spending against the usual month that is worth pointing out
+	minSpendingUp     = 5000 // Smallest rise in spending worth pointing out
+	minRDInstalment   = 1000 // Smallest recurring deposit instalment worth suggesting
+	topSpendingShare  = 0.5  // Share of spending on one rail worth pointing out
+	sweepRoundingUnit = 10000
+)
+
+// InsightsAgent gives proactive advice from a user's DWH profile and history: idle
+// balance that would earn more in a fixed deposit, a monthly surplus for a recurring
+// deposit and changes in spending
+type InsightsAgent struct {
+	*AgentBase
+	cfg *config.InsightsConfig
+	dwh *DWHClient
+}
+
+// NewInsightsAgent creates a new insights agent
+func NewInsightsAgent(base *AgentBase, cfg *config.InsightsConfig, dwh *DWHClient) *InsightsAgent {
+	return &InsightsAgent{
+		AgentBase: base,
+		cfg:       cfg,
+		dwh:       dwh,
+	}
+}
+
+// Process processes a GET_INSIGHTS request
+func (ia *InsightsAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
+	log.Info().
+		Str("task", req.Task).
+		Str("request_id", req.RequestID).
+		Msg("Insights agent processing request")
+
+	userID, _ := req.InputContext["user_id"].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	profile, err := ia.dwh.Profile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	days := ia.cfg.HistoryDays
+	if days <= 0 {
+		days = 90
+	}
+	history, err := ia.dwh.History(ctx, userID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := numberField(profile, "total_balance")
+	income := numberField(profile, "monthly_income")
+	spending := summarizeSpending(history, days, time.Now())
+	insights := ia.insights(balance, income, spending)
+
+	explanation := "No suggestions right now; your money is working well"
+	if len(insights) > 0 {
+		explanation = insights[0].Message
+	}
+
+	log.Info().
+		Str("user_id", userID).
+		Int("insights", len(insights)).
+		Msg("Insights computed")
+
+	return &model.AgentResponse{
+		AgentID:   ia.agentType,
+		AgentType: "INSIGHTS",
+		Status:    "APPROVED",
+		Result: map[string]interface{}{
+			"user_id":  userID,
+			"insights": insights,
+			"summary": map[string]interface{}{
+				"balance":               balance,
+				"monthly_income":        income,
+				"monthly_spending":      math.Round(spending.monthly),
+				"this_month_spending":   math.Round(spending.lastMonth),
+				"history_days":          days,
+				"transactions_analyzed": spending.count,
+			},
+		},
+		Explanation: explanation,
+		Confidence:  0.8,
+		Timestamp:   time.Now(),
+		RequestID:   req.RequestID,
+	}, nil
+}
+
+// spendingSummary is a user's spending drawn from their history
+type spendingSummary struct {
+	count     int
+	monthly   float64            // Average a month over the whole history
+	lastMonth float64            // Over the last 30 days
+	byRail    map[string]float64 // Total by transaction type
+	total     float64
+}
+
+// summarizeSpending adds up the money that left the user's accounts. Credits and
+// transactions that did not go through are left out.
+func summarizeSpending(history []model.DWHTransaction, days int, now time.Time) spendingSummary {
+	s := spendingSummary{byRail: make(map[string]float64)}
+	monthAgo := now.AddDate(0, 0, -30)
+	for _, txn := range history {
+		status := strings.ToUpper(txn.Status)
+		if status == "FAILED" || status == "REJECTED" {
+			continue
+		}
+		if txn.Type == "CREDIT" || (txn.FromAccount == "" && txn.ToAccount != "") {
+			continue
+		}
+		s.count++
+		s.total += txn.Amount
+		s.byRail[txn.Type] += txn.Amount
+		if txn.CreatedAt.After(monthAgo) {
+			s.lastMonth += txn.Amount
+		}
+	}
+	s.monthly = s.total / (float64(days) / 30)
+	return s
+}
+
+// insights turns the numbers into advice, most valuable first
+func (ia *InsightsAgent) insights(balance, income float64, spending spendingSummary) []model.Insight {
+	insights := []model.Insight{}
+
+	// Keep a few months of spending at hand and suggest sweeping the rest into an FD
+	buffer := spending.monthly * ia.cfg.BufferMonths
+	sweep := math.Floor((balance-buffer)/sweepRoundingUnit) * sweepRoundingUnit
+	if sweep >= ia.cfg.MinSweep {
+		gain := math.Round(sweep * (ia.cfg.FDRate - ia.cfg.SavingsRate) / 100)
+		insights = append(insights, model.Insight{
+			Type:  model.InsightSweepToFD,
+			Title: "Put idle savings to work",
+			Message: fmt.Sprintf("You could sweep %s to a fixed deposit and still keep %s, about %.0f months of spending, in savings. At %.1f%% that earns about %s more a year.",
+				rupees(sweep), rupees(balance-sweep), ia.cfg.BufferMonths, ia.cfg.FDRate, rupees(gain)),
+			Amount:        sweep,
+			EstimatedGain: gain,
+		})
+	}
+
+	// Part of a regular monthly surplus could go into a recurring deposit
+	if income > 0 {
+		instalment := math.Floor((income-spending.monthly)/2/minRDInstalment) * minRDInstalment
+		if instalment >= minRDInstalment {
+			// A year of instalments is in the deposit for half a year on average
+			gain := math.Round(instalment * 12 * (ia.cfg.FDRate - ia.cfg.SavingsRate) / 100 / 2)
+			insights = append(insights, model.Insight{
+				Type:  model.InsightStartRD,
+				Title: "Save a part of each month",
+				Message: fmt.Sprintf("You spend about %s of your %s monthly income. A recurring deposit of %s a month would build savings without touching your buffer.",
+					rupees(spending.monthly), rupees(income), rupees(instalment)),
+				Amount:        instalment,
+				EstimatedGain: gain,
+			})
+		}
+	}
+
+	// Spending this month well above the usual month
+	if spending.monthly > 0 && spending.lastMonth > spending.monthly*spendingUpRatio && spending.lastMonth-spending.monthly >= minSpendingUp {
+		insights = append(insights, model.Insight{
+			Type:  model.InsightSpendingUp,
+			Title: "Spending is up this month",
+			Message: fmt.Sprintf("You have spent %s in the last 30 days, %.0f%% more than your usual %s a month.",
+				rupees(spending.lastMonth), (spending.lastMonth/spending.monthly-1)*100, rupees(spending.monthly)),
+			Amount: math.Round(spending.lastMonth - spending.monthly),
+		})
+	}
+
+	// The rail most of the money goes through
+	if rail, amount := topRail(spending.byRail); rail != "" && spending.total > 0 && amount/spending.total >= topSpendingShare {
+		insights = append(insights, model.Insight{
+			Type:    model.InsightTopSpending,
+			Title:   "Where your money goes",
+			Message: fmt.Sprintf("%.0f%% of your spending (%s) went by %s.", amount/spending.total*100, rupees(amount), rail),
+			Amount:  amount,
+		})
+	}
+
+	sort.SliceStable(insights, func(a, b int) bool { return insights[a].EstimatedGain > insights[b].EstimatedGain })
+	for i := range insights {
+		insights[i].Priority = i + 1
+	}
+	return insights
+}
+
+// topRail returns the transaction type with the most spending
+func topRail(byRail map[string]float64) (string, float64) {
+	rails := make([]string, 0, len(byRail))
+	for rail := range byRail {
+		rails = append(rails, rail)
+	}
+	sort.Strings(rails)
+
+	top, topAmount := "", 0.0
+	for _, rail := range rails {
+		if byRail[rail] > topAmount {
+			top, topAmount = rail, byRail[rail]
+		}
+	}
+	return top, topAmount
+}
+
+// numberField reads a numeric field of a JSON object
+func numberField(fields map[string]interface{}, key string) float64 {
+	v, _ := fields[key].(float64)
+	return v
+}
+
+// rupees formats an amount with Indian digit grouping, e.g. ₹2,00,000
+func rupees(amount float64) string {
+	digits := fmt.Sprintf("%.0f", math.Abs(amount))
+	if len(digits) > 3 {
+		head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		digits = strings.Join(append([]string{head}, groups...), ",") + "," + tail
+	}
+	if amount < 0 {
+		return "-₹" + digits
+	}
+	return "₹" + digits
+}
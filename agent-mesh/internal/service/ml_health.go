@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// mlCall is one call to the ML service in the health window
+type mlCall struct {
+	latency time.Duration
+	failed  bool
+}
+
+// MLHealthTracker decides whether the ML service is called. It scores the last calls
+// on their error rate and latency; once either crosses its threshold the agent fails
+// over to rules for the cool-down, so a degraded service does not have requests
+// flip between ML and rules one by one. After the cool-down one call at a time probes
+// the service, and it takes several good probes in a row to go back to ML.
+type MLHealthTracker struct {
+	cfg *config.MLConfig
+
+	mu         sync.Mutex
+	mode       string
+	since      time.Time
+	calls      []mlCall // Ring of the last HealthWindow calls
+	next       int
+	probing    bool // A probe call is in flight
+	probesOK   int  // Good probes in a row
+	failovers  int64
+	lastReason string
+}
+
+// NewMLHealthTracker creates a tracker that starts out calling the ML service
+func NewMLHealthTracker(cfg *config.MLConfig) *MLHealthTracker {
+	return &MLHealthTracker{cfg: cfg, mode: model.MLModeML, since: time.Now()}
+}
+
+// Allow reports whether a call may go to the ML service, and whether it is a probe
+// whose outcome decides recovery. A failed-over tracker whose cool-down is over
+// starts probing.
+func (mh *MLHealthTracker) Allow() (allowed, probe bool) {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+
+	if mh.mode == model.MLModeFailover && !time.Now().Before(mh.probeAt()) {
+		mh.setMode(model.MLModeProbing, "cool-down over")
+	}
+	switch mh.mode {
+	case model.MLModeML:
+		return true, false
+	case model.MLModeProbing:
+		if mh.probing {
+			return false, false
+		}
+		mh.probing = true
+		return true, true
+	}
+	return false, false
+}
+
+// Record notes the outcome of a call Allow let through
+func (mh *MLHealthTracker) Record(latency time.Duration, err error, probe bool) {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+
+	slow := latency >= time.Duration(mh.cfg.MaxLatencyMs)*time.Millisecond
+	if probe {
+		mh.probing = false
+		if mh.mode != model.MLModeProbing {
+			return
+		}
+		switch {
+		case err != nil:
+			mh.failover(fmt.Sprintf("probe failed: %v", err))
+		case slow:
+			mh.failover(fmt.Sprintf("probe took %dms", latency.Milliseconds()))
+		default:
+			mh.probesOK++
+			if mh.probesOK >= mh.cfg.RecoveryProbes {
+				mh.calls, mh.next = nil, 0
+				mh.setMode(model.MLModeML, fmt.Sprintf("%d probes succeeded", mh.probesOK))
+			}
+		}
+		return
+	}
+	if mh.mode != model.MLModeML {
+		return
+	}
+
+	call := mlCall{latency: latency, failed: err != nil}
+	if len(mh.calls) < mh.cfg.HealthWindow {
+		mh.calls = append(mh.calls, call)
+	} else {
+		mh.calls[mh.next] = call
+		mh.next = (mh.next + 1) % len(mh.calls)
+	}
+	if len(mh.calls) < mh.cfg.HealthMinCalls {
+		return
+	}
+
+	errorRate, avgLatency := mh.window()
+	switch {
+	case errorRate >= mh.cfg.MaxErrorRate:
+		mh.failover(fmt.Sprintf("error rate %.2f over the last %d calls", errorRate, len(mh.calls)))
+	case avgLatency >= float64(mh.cfg.MaxLatencyMs):
+		mh.failover(fmt.Sprintf("average latency %.0fms over the last %d calls", avgLatency, len(mh.calls)))
+	}
+}
+
+// Health returns the current mode and score
+func (mh *MLHealthTracker) Health() *model.MLHealth {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+
+	errorRate, avgLatency := mh.window()
+	health := &model.MLHealth{
+		Mode:         mh.mode,
+		Score:        mh.score(errorRate, avgLatency),
+		ErrorRate:    errorRate,
+		AvgLatencyMs: avgLatency,
+		Calls:        len(mh.calls),
+		Since:        mh.since,
+		Failovers:    mh.failovers,
+		LastReason:   mh.lastReason,
+	}
+	if mh.mode == model.MLModeFailover {
+		probeAt := mh.probeAt()
+		health.ProbeAt = &probeAt
+	}
+	return health
+}
+
+// window returns the error rate and average latency in milliseconds of the window
+func (mh *MLHealthTracker) window() (float64, float64) {
+	if len(mh.calls) == 0 {
+		return 0, 0
+	}
+	var failed int
+	var total time.Duration
+	for _, call := range mh.calls {
+		if call.failed {
+			failed++
+		}
+		total += call.latency
+	}
+	n := float64(len(mh.calls))
+	return float64(failed) / n, float64(total.Microseconds()) / 1000 / n
+}
+
+// score is the mean of the error and latency scores, each 1 with no errors or no
+// latency and 0 at its threshold. A failed-over tracker scores 0.
+func (mh *MLHealthTracker) score(errorRate, avgLatency float64) float64 {
+	if mh.mode == model.MLModeFailover {
+		return 0
+	}
+	errorScore := clamp01(1 - errorRate/mh.cfg.MaxErrorRate)
+	latencyScore := clamp01(1 - avgLatency/float64(mh.cfg.MaxLatencyMs))
+	return (errorScore + latencyScore) / 2
+}
+
+// failover stops calling the ML service for the cool-down
+func (mh *MLHealthTracker) failover(reason string) {
+	mh.failovers++
+	mh.lastReason = reason
+	mh.probesOK = 0
+	mh.setMode(model.MLModeFailover, reason)
+}
+
+// probeAt is when a failed-over tracker starts probing
+func (mh *MLHealthTracker) probeAt() time.Time {
+	return mh.since.Add(time.Duration(mh.cfg.CooldownSeconds) * time.Second)
+}
+
+func (mh *MLHealthTracker) setMode(mode, reason string) {
+	event := log.Info()
+	if mode == model.MLModeFailover {
+		event = log.Warn()
+	}
+	event.Str("from", mh.mode).Str("to", mode).Str("reason", reason).Msg("ML service mode changed")
+	mh.mode = mode
+	mh.since = time.Now()
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
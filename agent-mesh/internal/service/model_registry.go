@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+)
+
+// ModelRegistry resolves which version of an ML model a request uses
+type ModelRegistry struct {
+	config *model.ModelRegistryConfig
+	specs  map[string]*model.ModelSpec // Keyed by name@version
+}
+
+// NewModelRegistry creates a registry from a config, checking that every version it
+// routes to is defined
+func NewModelRegistry(cfg *model.ModelRegistryConfig) (*ModelRegistry, error) {
+	mr := &ModelRegistry{
+		config: cfg,
+		specs:  make(map[string]*model.ModelSpec),
+	}
+
+	for i := range cfg.Models {
+		spec := &cfg.Models[i]
+		if spec.Name == "" || spec.Version == "" || spec.Endpoint == "" {
+			return nil, fmt.Errorf("model %d needs a name, version and endpoint", i)
+		}
+		mr.specs[modelKey(spec.Name, spec.Version)] = spec
+	}
+
+	for name, version := range cfg.Defaults {
+		if !mr.has(name, version) {
+			return nil, fmt.Errorf("default %s@%s is not a registered model", name, version)
+		}
+	}
+	for tenant, versions := range cfg.Tenants {
+		for name, version := range versions {
+			if !mr.has(name, version) {
+				return nil, fmt.Errorf("tenant %s uses %s@%s, which is not a registered model", tenant, name, version)
+			}
+		}
+	}
+	for _, exp := range cfg.Experiments {
+		if !mr.has(exp.Model, exp.Version) {
+			return nil, fmt.Errorf("experiment %s uses %s@%s, which is not a registered model", exp.Name, exp.Model, exp.Version)
+		}
+		if exp.Percent < 0 || exp.Percent > 100 {
+			return nil, fmt.Errorf("experiment %s percent must be between 0 and 100", exp.Name)
+		}
+	}
+
+	return mr, nil
+}
+
+// LoadModelRegistry reads a registry config file, or returns the default registry of
+// the Layer 4 v1 models when path is empty
+func LoadModelRegistry(path string) (*ModelRegistry, error) {
+	if path == "" {
+		return NewModelRegistry(defaultModelRegistry())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model registry: %w", err)
+	}
+
+	var cfg model.ModelRegistryConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid model registry: %w", err)
+	}
+	return NewModelRegistry(&cfg)
+}
+
+// Select returns the version of a model to use for a user of a tenant, and the name of
+// the experiment that chose it, if any. It returns nil when the model has no version.
+func (mr *ModelRegistry) Select(name, tenantID, userID string) (*model.ModelSpec, string) {
+	for _, exp := range mr.config.Experiments {
+		if exp.Model != name || !experimentCovers(exp, tenantID) {
+			continue
+		}
+		if userID != "" && userBucket(exp.Name, userID) < exp.Percent {
+			return mr.specs[modelKey(name, exp.Version)], exp.Name
+		}
+	}
+
+	if version, ok := mr.config.Tenants[tenantID][name]; ok {
+		return mr.specs[modelKey(name, version)], ""
+	}
+	if version, ok := mr.config.Defaults[name]; ok {
+		return mr.specs[modelKey(name, version)], ""
+	}
+	return nil, ""
+}
+
+// Models returns every registered model version
+func (mr *ModelRegistry) Models() []model.ModelSpec {
+	return mr.config.Models
+}
+
+func (mr *ModelRegistry) has(name, version string) bool {
+	_, ok := mr.specs[modelKey(name, version)]
+	return ok
+}
+
+func modelKey(name, version string) string { return name + "@" + version }
+
+// experimentCovers reports whether an experiment runs for the tenant
+func experimentCovers(exp model.ModelExperiment, tenantID string) bool {
+	if len(exp.Tenants) == 0 {
+		return true
+	}
+	for _, t := range exp.Tenants {
+		if t == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// userBucket places a user in 0-99 for an experiment. The same user always lands in the
+// same bucket, and buckets are independent between experiments.
+func userBucket(experiment, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(experiment + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// defaultModelRegistry routes every request to the v1 models of the Layer 4 ML service
+func defaultModelRegistry() *model.ModelRegistryConfig {
+	return &model.ModelRegistryConfig{
+		Models: []model.ModelSpec{
+			{
+				Name:     "credit",
+				Version:  "v1",
+				Endpoint: "/api/v1/scoring/credit",
+				Features: []model.FeatureSpec{
+					{Name: "account_age_days", Required: true},
+					{Name: "monthly_income", Source: "income"},
+					{Name: "total_balance", Source: "balance"},
+					{Name: "delinquency_count"},
+					{Name: "loan_history_count"},
+				},
+			},
+			{
+				Name:     "fraud",
+				Version:  "v1",
+				Endpoint: "/api/v1/fraud/predict",
+				Features: []model.FeatureSpec{
+					{Name: "amount", Required: true},
+					{Name: "hour"},
+					{Name: "transaction_count_24h"},
+					{Name: "beneficiary_age_days"},
+					{Name: "device_risk"},
+					{Name: "location_risk"},
+				},
+			},
+			{
+				Name:     "risk",
+				Version:  "v1",
+				Endpoint: "/api/v1/scoring/risk",
+				Features: []model.FeatureSpec{
+					{Name: "account_age_days", Required: true},
+					{Name: "monthly_income", Source: "income"},
+					{Name: "total_balance", Source: "balance"},
+					{Name: "amount"},
+					{Name: "transaction_count_24h"},
+					{Name: "beneficiary_age_days"},
+					{Name: "device_risk"},
+					{Name: "location_risk"},
+				},
+			},
+		},
+		Defaults: map[string]string{"credit": "v1", "fraud": "v1", "risk": "v1"},
+	}
+}
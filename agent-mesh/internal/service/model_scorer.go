@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// ModelScorer calls the model version the registry selects on the Layer 4 ML service.
+// Whenever it cannot (a required feature is missing, the ML service is not configured
+// or does not answer) it returns no result and the agent scores with its rules; the
+// returned ModelVersion says which happened. While the ML service is unhealthy no
+// calls are made at all until it has recovered.
+type ModelScorer struct {
+	registry   *ModelRegistry
+	baseURL    string
+	httpClient *http.Client
+	health     *MLHealthTracker // Nil when ML calls are disabled
+}
+
+// NewModelScorer creates a new model scorer. An empty ML base URL disables ML calls.
+func NewModelScorer(registry *ModelRegistry, cfg *config.MLConfig) *ModelScorer {
+	ms := &ModelScorer{
+		registry:   registry,
+		baseURL:    cfg.BaseURL,
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+	if cfg.BaseURL != "" {
+		ms.health = NewMLHealthTracker(cfg)
+	}
+	return ms
+}
+
+// Warmup opens a connection to the ML service ahead of traffic
+func (ms *ModelScorer) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, ms.httpClient, ms.baseURL)
+}
+
+// Predict scores inputs with the selected version of the named model. The tenant and
+// user are read from inputCtx. A nil result means the caller should use its rules.
+func (ms *ModelScorer) Predict(ctx context.Context, name string, inputCtx, inputs map[string]interface{}) (map[string]interface{}, *model.ModelVersion) {
+	tenantID, _ := inputCtx["tenant_id"].(string)
+	userID, _ := inputCtx["user_id"].(string)
+
+	spec, experiment := ms.registry.Select(name, tenantID, userID)
+	if spec == nil {
+		return nil, &model.ModelVersion{Name: name, Version: "rules", Source: "rules", Fallback: "no_model"}
+	}
+
+	version := &model.ModelVersion{
+		Name:       spec.Name,
+		Version:    spec.Version,
+		Experiment: experiment,
+		Source:     "rules",
+	}
+
+	// The response says which mode the ML service is in once this call has counted
+	if ms.health != nil {
+		defer func() { recordMLHealth(ctx, ms.health.Health()) }()
+	}
+
+	features, missing := buildFeatures(spec, inputs)
+	switch {
+	case len(missing) > 0:
+		version.Fallback = "missing_features"
+		version.MissingFeatures = missing
+	case ms.baseURL == "":
+		version.Fallback = "ml_disabled"
+	case !mlAllowed(inputCtx):
+		version.Fallback = "ml_not_allowed"
+	default:
+		allowed, probe := ms.health.Allow()
+		if !allowed {
+			version.Fallback = "ml_failover"
+			break
+		}
+		start := time.Now()
+		result, err := ms.call(ctx, spec, features)
+		ms.health.Record(time.Since(start), err, probe)
+		recordCall(ctx, "ml:"+spec.Name, start, err)
+		if err == nil {
+			version.Source = "ml"
+			ms.logVersion(version)
+			return result, version
+		}
+		log.Warn().Err(err).Str("model", spec.Name).Str("version", spec.Version).Msg("ML model call failed, scoring with rules")
+		version.Fallback = "ml_unavailable"
+	}
+
+	ms.logVersion(version)
+	return nil, version
+}
+
+// mlAllowed reports whether the task may be scored with ML models. The AI Skin sets
+// allow_ml to false in the task context when the request's budget rules them out.
+func mlAllowed(inputCtx map[string]interface{}) bool {
+	taskContext, _ := inputCtx["context"].(map[string]interface{})
+	allowed, ok := taskContext["allow_ml"].(bool)
+	return !ok || allowed
+}
+
+// buildFeatures collects a model's features from inputs and lists the required ones
+// that are absent or not numeric. Optional features that are absent are left out so
+// the model applies its own defaults.
+func buildFeatures(spec *model.ModelSpec, inputs map[string]interface{}) (map[string]interface{}, []string) {
+	features := make(map[string]interface{}, len(spec.Features))
+	var missing []string
+	for _, f := range spec.Features {
+		source := f.Source
+		if source == "" {
+			source = f.Name
+		}
+
+		if v, ok := inputs[source].(float64); ok {
+			features[f.Name] = v
+		} else if f.Required {
+			missing = append(missing, f.Name)
+		}
+	}
+	return features, missing
+}
+
+// call posts features to a model endpoint and returns its result
+func (ms *ModelScorer) call(ctx context.Context, spec *model.ModelSpec, features map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(features)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal features: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ms.baseURL+spec.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := ms.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ML service unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ML service returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var out struct {
+		Success bool                   `json:"success"`
+		Result  map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode ML response: %w", err)
+	}
+	if !out.Success || out.Result == nil {
+		return nil, fmt.Errorf("ML service returned no result")
+	}
+	return out.Result, nil
+}
+
+func (ms *ModelScorer) logVersion(v *model.ModelVersion) {
+	log.Info().
+		Str("model", v.Name).
+		Str("version", v.Version).
+		Str("experiment", v.Experiment).
+		Str("source", v.Source).
+		Str("fallback", v.Fallback).
+		Strs("missing_features", v.MissingFeatures).
+		Msg("Model selected")
+}
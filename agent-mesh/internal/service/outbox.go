@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrOutboxEntryNotFound is returned for an idempotency key the outbox has no entry for
+	ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+	// ErrOutboxEntryHeld is returned for a transfer held for manual review
+	ErrOutboxEntryHeld = errors.New("transfer is held for manual review")
+	// ErrOutboxEntrySettled is returned when abandoning an entry that is not PENDING
+	ErrOutboxEntrySettled = errors.New("outbox entry is already settled")
+)
+
+// Outbox sends the Banking Agent's transfers to Banking Integrations so none is lost
+// to a crash. Each transfer is written to OUTBOX_DIR as PENDING before it is sent and
+// marked CONFIRMED, or FAILED when refused, once Banking Integrations answers. An agent
+// that dies between the two leaves the entry PENDING; the recovery sweep, run at start
+// and every OUTBOX_SWEEP_INTERVAL, looks such entries up by their idempotency key and
+// confirms those that went through. One Banking Integrations does not know is held for
+// manual review rather than abandoned, since its transfer ledger does not outlive a
+// restart or TRANSFER_IDEMPOTENCY_HOURS: a held transfer is not sent again until an
+// operator, having checked the DWH, abandons it. Nothing is sent again by the sweep:
+// a task retried with the same request ID sends its transfer with the same key, which
+// Banking Integrations answers from the first.
+type Outbox struct {
+	cfg       *config.OutboxConfig
+	transfers *TransferClient
+
+	mu        sync.Mutex
+	entries   map[string]*model.OutboxEntry // Keyed by idempotency key
+	inFlight  map[string]bool               // Keys being sent by this process, left alone by the sweep
+	lastSweep *model.OutboxSweep
+	sweepMu   sync.Mutex // One sweep at a time
+}
+
+// NewOutbox creates the outbox and loads the entries kept in OUTBOX_DIR
+func NewOutbox(cfg *config.OutboxConfig, transfers *TransferClient) (*Outbox, error) {
+	o := &Outbox{
+		cfg:       cfg,
+		transfers: transfers,
+		entries:   make(map[string]*model.OutboxEntry),
+		inFlight:  make(map[string]bool),
+	}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Transfer records a transfer and sends it. A transfer already confirmed with the
+// same key is answered from the outbox, and one already refused gets its refusal
+// again. When the answer is lost the entry stays PENDING for the sweep and the error
+// is returned.
+func (o *Outbox) Transfer(ctx context.Context, requestID string, req *model.TransferRequest) (*model.TransferResponse, error) {
+	o.mu.Lock()
+	if entry, ok := o.entries[req.IdempotencyKey]; ok {
+		switch entry.Status {
+		case model.OutboxConfirmed:
+			resp := *entry.Result
+			resp.Replayed = true
+			o.mu.Unlock()
+			log.Info().Str("idempotency_key", req.IdempotencyKey).Msg("Transfer already confirmed, answered from the outbox")
+			return &resp, nil
+		case model.OutboxFailed:
+			reason := entry.Error
+			o.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrTransferRefused, reason)
+		case model.OutboxPending:
+			if entry.Held {
+				o.mu.Unlock()
+				return nil, fmt.Errorf("%w: %s", ErrOutboxEntryHeld, req.IdempotencyKey)
+			}
+		}
+	}
+	if o.inFlight[req.IdempotencyKey] {
+		o.mu.Unlock()
+		return nil, fmt.Errorf("transfer %s is already being sent", req.IdempotencyKey)
+	}
+
+	now := time.Now()
+	entry := &model.OutboxEntry{
+		IdempotencyKey: req.IdempotencyKey,
+		RequestID:      requestID,
+		Transfer:       *req,
+		Status:         model.OutboxPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if previous, ok := o.entries[req.IdempotencyKey]; ok {
+		entry.CreatedAt = previous.CreatedAt
+	}
+	// Nothing is sent unless it is recorded first
+	if err := o.persist(entry); err != nil {
+		o.mu.Unlock()
+		return nil, fmt.Errorf("failed to record transfer in the outbox: %w", err)
+	}
+	o.entries[req.IdempotencyKey] = entry
+	o.inFlight[req.IdempotencyKey] = true
+	o.mu.Unlock()
+
+	resp, err := o.transfers.Transfer(ctx, req)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.inFlight, req.IdempotencyKey)
+	switch {
+	case err == nil:
+		o.settle(entry, model.OutboxConfirmed, resp, "")
+	case errors.Is(err, ErrTransferRefused):
+		o.settle(entry, model.OutboxFailed, nil, strings.TrimPrefix(err.Error(), ErrTransferRefused.Error()+": "))
+	default:
+		entry.Error = err.Error()
+		entry.UpdatedAt = time.Now()
+		o.persistOrWarn(entry)
+		log.Warn().Err(err).Str("idempotency_key", req.IdempotencyKey).Msg("Transfer outcome unknown, left for the recovery sweep")
+	}
+	return resp, err
+}
+
+// Get returns a copy of an entry
+func (o *Outbox) Get(key string) (*model.OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[key]
+	if !ok {
+		return nil, ErrOutboxEntryNotFound
+	}
+	return copyOutboxEntry(entry), nil
+}
+
+// List returns the entries with a status, or all of them, newest first
+func (o *Outbox) List(status model.OutboxStatus, limit int) []*model.OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := make([]*model.OutboxEntry, 0, len(o.entries))
+	for _, entry := range o.entries {
+		if status == "" || entry.Status == status {
+			entries = append(entries, copyOutboxEntry(entry))
+		}
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].CreatedAt.After(entries[b].CreatedAt) })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// LastSweep returns the outcome of the latest recovery sweep, nil before the first
+func (o *Outbox) LastSweep() *model.OutboxSweep {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.lastSweep
+}
+
+// Run sweeps at once and then every OUTBOX_SWEEP_INTERVAL until ctx is done
+func (o *Outbox) Run(ctx context.Context) {
+	o.Sweep(ctx, false)
+
+	ticker := time.NewTicker(time.Duration(o.cfg.SweepInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.Sweep(ctx, false)
+			o.purge()
+		}
+	}
+}
+
+// Sweep reconciles PENDING entries left unconfirmed for OUTBOX_RECONCILE_AFTER, or
+// all of them with all set, against Banking Integrations by idempotency key. Entries
+// being sent by this process are left alone.
+func (o *Outbox) Sweep(ctx context.Context, all bool) *model.OutboxSweep {
+	o.sweepMu.Lock()
+	defer o.sweepMu.Unlock()
+
+	cutoff := time.Now().Add(-time.Duration(o.cfg.ReconcileAfter) * time.Second)
+	o.mu.Lock()
+	var due []*model.OutboxEntry
+	for key, entry := range o.entries {
+		if entry.Status == model.OutboxPending && !o.inFlight[key] && (all || entry.UpdatedAt.Before(cutoff)) {
+			due = append(due, entry)
+		}
+	}
+	o.mu.Unlock()
+
+	sweep := &model.OutboxSweep{Checked: len(due)}
+	for _, entry := range due {
+		key := entry.IdempotencyKey
+		resp, err := o.transfers.Lookup(ctx, key)
+
+		o.mu.Lock()
+		if o.inFlight[key] || o.entries[key] != entry || entry.Status != model.OutboxPending {
+			// Sent again or settled while it was being looked up
+			o.mu.Unlock()
+			continue
+		}
+		entry.Reconciles++
+		switch {
+		case err == nil:
+			entry.RecoveredBy = "sweep"
+			o.settle(entry, model.OutboxConfirmed, resp, "")
+			sweep.Confirmed++
+			log.Warn().
+				Str("idempotency_key", key).
+				Str("request_id", entry.RequestID).
+				Str("transaction_id", resp.TransactionID).
+				Msg("Unconfirmed transfer found in Banking Integrations, confirmed by the recovery sweep")
+		case errors.Is(err, ErrTransferUnknown):
+			// Banking Integrations forgets keys on a restart, so a transfer it made may
+			// read as unknown; only an operator checking the DWH can tell
+			if !entry.Held {
+				log.Warn().
+					Str("idempotency_key", key).
+					Str("request_id", entry.RequestID).
+					Msg("Unconfirmed transfer unknown to Banking Integrations, held for manual review")
+			}
+			entry.Held = true
+			entry.Error = "unknown to Banking Integrations; check the DWH before abandoning it"
+			entry.UpdatedAt = time.Now()
+			o.persistOrWarn(entry)
+			sweep.Held++
+		default:
+			entry.Error = err.Error()
+			entry.UpdatedAt = time.Now()
+			o.persistOrWarn(entry)
+			sweep.Pending++
+			log.Warn().Err(err).Str("idempotency_key", key).Msg("Failed to reconcile transfer, will try again")
+		}
+		o.mu.Unlock()
+	}
+
+	sweep.SweptAt = time.Now()
+	o.mu.Lock()
+	o.lastSweep = sweep
+	o.mu.Unlock()
+	if sweep.Checked > 0 {
+		log.Info().
+			Int("checked", sweep.Checked).
+			Int("confirmed", sweep.Confirmed).
+			Int("held", sweep.Held).
+			Int("pending", sweep.Pending).
+			Msg("Outbox recovery sweep finished")
+	}
+	return sweep
+}
+
+// Abandon settles a PENDING entry whose transfer an operator found never went
+// through, so a retry of its task may send it again
+func (o *Outbox) Abandon(key, reason string) (*model.OutboxEntry, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("a reason is required to abandon a transfer")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[key]
+	if !ok {
+		return nil, ErrOutboxEntryNotFound
+	}
+	if entry.Status != model.OutboxPending {
+		return nil, fmt.Errorf("%w: %s is %s", ErrOutboxEntrySettled, key, entry.Status)
+	}
+	if o.inFlight[key] {
+		return nil, fmt.Errorf("transfer %s is being sent", key)
+	}
+	entry.RecoveredBy = "operator"
+	entry.Held = false
+	o.settle(entry, model.OutboxAbandoned, nil, reason)
+	log.Warn().Str("idempotency_key", key).Str("request_id", entry.RequestID).
+		Str("reason", reason).Msg("Unconfirmed transfer abandoned by an operator")
+	return copyOutboxEntry(entry), nil
+}
+
+// settle records how an entry ended. Callers hold o.mu.
+func (o *Outbox) settle(entry *model.OutboxEntry, status model.OutboxStatus, resp *model.TransferResponse, reason string) {
+	now := time.Now()
+	entry.Status = status
+	entry.Result = resp
+	entry.Error = reason
+	entry.UpdatedAt = now
+	entry.SettledAt = &now
+	o.persistOrWarn(entry)
+}
+
+// purge removes entries settled more than OUTBOX_RETENTION_HOURS ago
+func (o *Outbox) purge() {
+	cutoff := time.Now().Add(-time.Duration(o.cfg.RetentionHours) * time.Hour)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for key, entry := range o.entries {
+		if entry.SettledAt == nil || entry.SettledAt.After(cutoff) {
+			continue
+		}
+		delete(o.entries, key)
+		if err := os.Remove(o.path(key)); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Str("idempotency_key", key).Msg("Failed to remove outbox entry file")
+		}
+	}
+}
+
+// load reads the entries kept in OUTBOX_DIR
+func (o *Outbox) load() error {
+	if err := os.MkdirAll(o.cfg.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(o.cfg.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read outbox entry: %w", err)
+		}
+		var entry model.OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.IdempotencyKey == "" {
+			log.Warn().Err(err).Str("file", file).Msg("Skipping unreadable outbox entry")
+			continue
+		}
+		if entry.Status == model.OutboxPending {
+			pending++
+		}
+		o.entries[entry.IdempotencyKey] = &entry
+	}
+	if pending > 0 {
+		log.Warn().Int("pending", pending).Str("dir", o.cfg.Dir).Msg("Outbox has unconfirmed transfers from before the restart")
+	}
+	return nil
+}
+
+// persist writes an entry to OUTBOX_DIR, replacing the file whole
+func (o *Outbox) persist(entry *model.OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp := o.path(entry.IdempotencyKey) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.path(entry.IdempotencyKey))
+}
+
+func (o *Outbox) persistOrWarn(entry *model.OutboxEntry) {
+	if err := o.persist(entry); err != nil {
+		log.Warn().Err(err).Str("idempotency_key", entry.IdempotencyKey).Msg("Failed to persist outbox entry")
+	}
+}
+
+// path names an entry's file by a hash of its key, which may hold any characters
+func (o *Outbox) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(o.cfg.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func copyOutboxEntry(entry *model.OutboxEntry) *model.OutboxEntry {
+	c := *entry
+	if entry.Result != nil {
+		result := *entry.Result
+		c.Result = &result
+	}
+	return &c
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// PaymentRequestClient raises UPI payment requests with Banking Integrations (Layer 5)
+type PaymentRequestClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewPaymentRequestClient creates a new payment request client
+func NewPaymentRequestClient(cfg *config.BankingIntegrationsConfig) *PaymentRequestClient {
+	return &PaymentRequestClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (pc *PaymentRequestClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, pc.httpClient, pc.baseURL)
+}
+
+// Create raises a payment request and returns it with its deep link and QR payload
+func (pc *PaymentRequestClient) Create(ctx context.Context, req *model.PaymentRequestCreate) (pr *model.PaymentRequest, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:payment_requests", start, err) }(time.Now())
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/payment-requests", pc.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", pc.apiKey.Get())
+
+	resp, err := pc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	pr = &model.PaymentRequest{}
+	if err := json.Unmarshal(body, pr); err != nil {
+		return nil, fmt.Errorf("failed to parse payment request: %w", err)
+	}
+	return pr, nil
+}
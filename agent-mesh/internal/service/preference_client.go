@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// PreferenceClient reads user preferences from Banking Integrations (Layer 5)
+type PreferenceClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewPreferenceClient creates a new preference client
+func NewPreferenceClient(cfg *config.BankingIntegrationsConfig) *PreferenceClient {
+	return &PreferenceClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (pc *PreferenceClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, pc.httpClient, pc.baseURL)
+}
+
+// Get returns a user's preferences, or nil when the user has none
+func (pc *PreferenceClient) Get(ctx context.Context, userID string) (prefs *model.UserPreferences, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:preferences", start, err) }(time.Now())
+
+	endpoint := fmt.Sprintf("%s/api/v1/preferences/%s", pc.baseURL, url.PathEscape(userID))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-API-Key", pc.apiKey.Get())
+
+	resp, err := pc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	prefs = &model.UserPreferences{}
+	if err := json.Unmarshal(body, prefs); err != nil {
+		return nil, fmt.Errorf("failed to parse preferences: %w", err)
+	}
+	return prefs, nil
+}
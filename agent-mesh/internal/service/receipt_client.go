@@ -0,0 +1,86 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// ErrNoReceipt is returned for a transfer that cannot be found or did not go through
+var ErrNoReceipt = errors.New("no receipt to share")
+
+// ReceiptClient creates shareable receipt links with Banking Integrations (Layer 5)
+type ReceiptClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewReceiptClient creates a new receipt client
+func NewReceiptClient(cfg *config.BankingIntegrationsConfig) *ReceiptClient {
+	return &ReceiptClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (rc *ReceiptClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, rc.httpClient, rc.baseURL)
+}
+
+// CreateLink returns a link to a transfer's receipt. A transfer without a receipt
+// is an ErrNoReceipt, wrapped with Banking Integrations' reason.
+func (rc *ReceiptClient) CreateLink(ctx context.Context, req *model.ReceiptLinkRequest) (link *model.ReceiptLink, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:receipts", start, err) }(time.Now())
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/receipts/links", rc.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", rc.apiKey.Get())
+
+	resp, err := rc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receipt link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict {
+		var apiErr struct {
+			Details string `json:"details"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return nil, fmt.Errorf("%w: %s", ErrNoReceipt, apiErr.Details)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	link = &model.ReceiptLink{}
+	if err := json.Unmarshal(body, link); err != nil {
+		return nil, fmt.Errorf("failed to parse receipt link: %w", err)
+	}
+	return link, nil
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// Replay modes
+const (
+	ReplayOff    = "off"
+	ReplayRecord = "record"
+	ReplayReplay = "replay"
+)
+
+// ReplayTransport records the responses of a downstream service to fixture files, or
+// answers from those files without calling the service, so agents can be tested
+// against Banking Integrations or the ML service deterministically and offline.
+//
+// A request is matched on its method, path, query and JSON body (with keys sorted
+// and the configured fields left out); the host is not part of the match, so fixtures
+// recorded against one environment replay against any other. Recording the same
+// request again overwrites its fixture. Request headers, including API keys, are
+// never written.
+type ReplayTransport struct {
+	mode         string
+	dir          string
+	ignoreFields map[string]bool
+	next         http.RoundTripper
+	mu           sync.Mutex
+}
+
+// replayFixture is one recorded request and response
+type replayFixture struct {
+	Request    replayRequest  `json:"request"`
+	Response   replayResponse `json:"response"`
+	RecordedAt time.Time      `json:"recorded_at"`
+}
+
+type replayRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type replayResponse struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"` // JSON responses as-is
+	Text        string          `json:"text,omitempty"` // Anything else
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// NewReplayTransport wraps next (http.DefaultTransport when nil) for the configured
+// mode. It returns next unchanged when record/replay is off.
+func NewReplayTransport(cfg *config.ReplayConfig, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	mode := strings.ToLower(cfg.Mode)
+	if mode == "" || mode == ReplayOff {
+		return next
+	}
+	if mode != ReplayRecord && mode != ReplayReplay {
+		log.Warn().Str("mode", cfg.Mode).Msg("Unknown replay mode, calling services directly")
+		return next
+	}
+
+	ignore := make(map[string]bool, len(cfg.IgnoreFields))
+	for _, field := range cfg.IgnoreFields {
+		ignore[field] = true
+	}
+	log.Info().Str("mode", mode).Str("dir", cfg.Dir).Msg("Downstream record/replay enabled")
+	return &ReplayTransport{
+		mode:         mode,
+		dir:          cfg.Dir,
+		ignoreFields: ignore,
+		next:         next,
+	}
+}
+
+// newDownstreamClient creates the HTTP client for a downstream service
+func newDownstreamClient(timeoutSeconds int, replay *config.ReplayConfig) *http.Client {
+	return &http.Client{
+		Timeout:   time.Duration(timeoutSeconds) * time.Second,
+		Transport: NewReplayTransport(replay, nil),
+	}
+}
+
+// RoundTrip records or replays one request
+func (rt *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	matchBody := rt.canonicalBody(body)
+	path := rt.fixturePath(req, matchBody)
+
+	if rt.mode == ReplayReplay {
+		return rt.replay(req, path)
+	}
+	return rt.record(req, path, matchBody)
+}
+
+// replay answers from the fixture, failing the request like an unreachable service
+// when there is none
+func (rt *ReplayTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no recorded response for %s %s (%s)", req.Method, req.URL.Path, filepath.Base(path))
+	}
+
+	var fixture replayFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid replay fixture %s: %w", path, err)
+	}
+
+	body := []byte(fixture.Response.Text)
+	if len(fixture.Response.Body) > 0 {
+		body = fixture.Response.Body
+	}
+	header := make(http.Header)
+	if fixture.Response.ContentType != "" {
+		header.Set("Content-Type", fixture.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// record calls the service and writes its response to the fixture. A failed write is
+// logged and does not fail the request.
+func (rt *ReplayTransport) record(req *http.Request, path string, matchBody []byte) (*http.Response, error) {
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	fixture := replayFixture{
+		Request: replayRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+		},
+		Response: replayResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+		RecordedAt: time.Now().UTC(),
+	}
+	if json.Valid(matchBody) {
+		fixture.Request.Body = matchBody
+	}
+	if json.Valid(respBody) {
+		fixture.Response.Body = respBody
+	} else {
+		fixture.Response.Text = string(respBody)
+	}
+
+	if err := rt.write(path, &fixture); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to record downstream response")
+	}
+	return resp, nil
+}
+
+func (rt *ReplayTransport) write(path string, fixture *replayFixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// canonicalBody re-encodes a JSON body with sorted keys and without the ignored
+// fields, so equal requests match however they were encoded
+func (rt *ReplayTransport) canonicalBody(body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	canonical, err := json.Marshal(rt.dropIgnored(value))
+	if err != nil {
+		return body
+	}
+	return canonical
+}
+
+func (rt *ReplayTransport) dropIgnored(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if rt.ignoreFields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = rt.dropIgnored(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rt.dropIgnored(item)
+		}
+	}
+	return value
+}
+
+// fixturePath names a fixture after the request, e.g. GET_api_v1_preferences_u1_3f2a9c01.json
+func (rt *ReplayTransport) fixturePath(req *http.Request, matchBody []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery + "\n"))
+	hash.Write(matchBody)
+	sum := hex.EncodeToString(hash.Sum(nil))[:8]
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Path, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return filepath.Join(rt.dir, fmt.Sprintf("%s_%s_%s.json", req.Method, name, sum))
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+)
+
+// resultSchema is the schema of an intent's result and the agent type that produces it
+type resultSchema struct {
+	name      string
+	agentType string
+	decode    func() model.ResultSchema
+}
+
+// resultSchemas are keyed by intent; transfers are keyed TRANSFER
+var resultSchemas = map[string]resultSchema{
+	"CHECK_BALANCE": {model.ResultSchemaBalance, "BANKING", func() model.ResultSchema { return &model.BalanceResult{} }},
+	"TRANSFER":      {model.ResultSchemaTransfer, "BANKING", func() model.ResultSchema { return &model.TransferResult{} }},
+	"GET_STATEMENT": {model.ResultSchemaStatement, "BANKING", func() model.ResultSchema { return &model.StatementResult{} }},
+	"CREDIT_SCORE":  {model.ResultSchemaScore, "SCORING", func() model.ResultSchema { return &model.ScoreResult{} }},
+}
+
+// ConformResult checks a response to a task against the intent's schema and names the
+// schema in the response. A response from any other agent type, or a rejection, has
+// no schema and is left as is.
+func ConformResult(task string, resp *model.AgentResponse) error {
+	key := task
+	if strings.HasPrefix(task, "TRANSFER_") {
+		key = "TRANSFER"
+	}
+	schema, ok := resultSchemas[key]
+	if !ok || schema.agentType != resp.AgentType || resp.Status == "REJECTED" {
+		return nil
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return fmt.Errorf("%s result is not JSON: %w", resp.AgentType, err)
+	}
+	typed := schema.decode()
+	if err := json.Unmarshal(data, typed); err != nil {
+		return fmt.Errorf("%s result does not match %s: %w", resp.AgentType, schema.name, err)
+	}
+	if err := typed.Validate(); err != nil {
+		return fmt.Errorf("%s result does not match %s: %w", resp.AgentType, schema.name, err)
+	}
+	resp.ResultSchema = schema.name
+	return nil
+}
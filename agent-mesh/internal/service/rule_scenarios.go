@@ -0,0 +1,325 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Agents whose rules scenarios can check
+const (
+	scenarioAgentGuardrail = "GUARDRAIL"
+	scenarioAgentFraud     = "FRAUD"
+)
+
+// defaultScenarioTask is the task a scenario is run as when it names none
+const defaultScenarioTask = "TRANSFER_NEFT"
+
+// LoadRuleScenarios reads scenario suite files (JSON, or YAML by .yaml/.yml
+// extension). A directory is read for its suite files, in name order.
+func LoadRuleScenarios(paths []string) ([]*model.RuleScenarioSuite, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule scenarios: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule scenarios: %w", err)
+		}
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".json", ".yaml", ".yml":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	suites := make([]*model.RuleScenarioSuite, 0, len(files))
+	names := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule scenarios: %w", err)
+		}
+		format := "json"
+		if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+		suite, err := ParseRuleScenarioSuite(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if other, ok := names[suite.Name]; ok {
+			return nil, fmt.Errorf("%s: suite %s is already defined in %s", file, suite.Name, other)
+		}
+		names[suite.Name] = file
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+// ParseRuleScenarioSuite decodes a scenario suite from JSON or YAML and validates it
+func ParseRuleScenarioSuite(data []byte, format string) (*model.RuleScenarioSuite, error) {
+	var suite model.RuleScenarioSuite
+	var err error
+	if format == "yaml" {
+		err = yaml.Unmarshal(data, &suite)
+	} else {
+		err = json.Unmarshal(data, &suite)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule scenarios: %w", err)
+	}
+
+	// YAML decodes whole numbers as int; metrics are compared as float64
+	for i := range suite.Scenarios {
+		for _, values := range []map[string]interface{}{suite.Scenarios[i].Context, suite.Scenarios[i].Data} {
+			for k, v := range values {
+				values[k] = normalizeThreshold(v)
+			}
+		}
+	}
+
+	if err := validateScenarioSuite(&suite); err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+// validateScenarioSuite checks that a suite is named, is for an agent with rules, and
+// that each scenario is named and expects something its agent decides
+func validateScenarioSuite(suite *model.RuleScenarioSuite) error {
+	if suite.Name == "" {
+		return fmt.Errorf("scenario suite needs a name")
+	}
+	suite.Agent = strings.ToUpper(strings.TrimSpace(suite.Agent))
+	if suite.Agent != scenarioAgentGuardrail && suite.Agent != scenarioAgentFraud {
+		return fmt.Errorf("scenario suite %s: agent must be GUARDRAIL or FRAUD", suite.Name)
+	}
+	if len(suite.Scenarios) == 0 {
+		return fmt.Errorf("scenario suite %s has no scenarios", suite.Name)
+	}
+
+	seen := make(map[string]bool)
+	for i, scenario := range suite.Scenarios {
+		where := fmt.Sprintf("scenario suite %s, scenario %d", suite.Name, i)
+		expect := scenario.Expect
+		switch {
+		case scenario.Name == "":
+			return fmt.Errorf("%s: name is required", where)
+		case seen[scenario.Name]:
+			return fmt.Errorf("%s: duplicate scenario name %s", where, scenario.Name)
+		}
+		seen[scenario.Name] = true
+		where = fmt.Sprintf("%s (%s)", where, scenario.Name)
+
+		guardrailChecks := len(expect.Fails) + len(expect.Passes)
+		fraudChecks := len(expect.Flags) + len(expect.NotFlags)
+		if expect.MinScore != nil || expect.MaxScore != nil || expect.RiskLevel != "" {
+			fraudChecks++
+		}
+		switch {
+		case expect.Status == "" && guardrailChecks == 0 && fraudChecks == 0:
+			return fmt.Errorf("%s: expect is empty", where)
+		case suite.Agent == scenarioAgentGuardrail && fraudChecks > 0:
+			return fmt.Errorf("%s: scores, risk levels and flags are for fraud suites", where)
+		case suite.Agent == scenarioAgentFraud && guardrailChecks > 0:
+			return fmt.Errorf("%s: fails and passes are for guardrail suites", where)
+		case expect.MinScore != nil && expect.MaxScore != nil && *expect.MinScore > *expect.MaxScore:
+			return fmt.Errorf("%s: min_score is above max_score", where)
+		}
+
+		switch expect.Status {
+		case "", "APPROVED", "REJECTED":
+		case "PENDING":
+			if suite.Agent == scenarioAgentGuardrail {
+				return fmt.Errorf("%s: guardrail checks are APPROVED or REJECTED", where)
+			}
+		default:
+			return fmt.Errorf("%s: unknown status %q", where, expect.Status)
+		}
+		switch expect.RiskLevel {
+		case "", "LOW", "MEDIUM", "HIGH":
+		default:
+			return fmt.Errorf("%s: risk_level must be LOW, MEDIUM or HIGH", where)
+		}
+	}
+	return nil
+}
+
+// RuleScenarioRunner runs scenario suites against the rules the agents decide by: the
+// guardrail rule packs, and the rules the Fraud Agent scores by when its ML model
+// cannot be used. Nothing is called, so a run gives the same outcome wherever it is
+// made. Values other services supply, the guardrail's blacklisted and account_status,
+// are taken from the scenario's context; blacklisted is false when it is not given.
+type RuleScenarioRunner struct {
+	guardrail *GuardrailRules // Nil when guardrail suites cannot be run
+}
+
+// NewRuleScenarioRunner creates a runner checking guardrail suites against rules
+func NewRuleScenarioRunner(guardrail *GuardrailRules) *RuleScenarioRunner {
+	return &RuleScenarioRunner{
+		guardrail: guardrail,
+	}
+}
+
+// Run runs every scenario of the suites, in order
+func (rr *RuleScenarioRunner) Run(suites []*model.RuleScenarioSuite) *model.RuleScenarioReport {
+	report := &model.RuleScenarioReport{Results: []model.RuleScenarioResult{}}
+	for _, suite := range suites {
+		for _, scenario := range suite.Scenarios {
+			var result model.RuleScenarioResult
+			if suite.Agent == scenarioAgentFraud {
+				result = runFraudScenario(&scenario)
+			} else {
+				result = rr.runGuardrailScenario(&scenario)
+			}
+			result.Suite = suite.Name
+			result.Scenario = scenario.Name
+			result.Passed = len(result.Mismatches) == 0
+
+			report.Scenarios++
+			if result.Passed {
+				report.Passed++
+			} else {
+				report.Failed++
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	return report
+}
+
+// runGuardrailScenario evaluates the rule packs for a scenario as the Guardrail Agent
+// would for the request
+func (rr *RuleScenarioRunner) runGuardrailScenario(scenario *model.RuleScenario) model.RuleScenarioResult {
+	if rr.guardrail == nil {
+		return model.RuleScenarioResult{Mismatches: []string{"no guardrail rules are loaded here"}}
+	}
+
+	task := scenario.Task
+	if task == "" {
+		task = defaultScenarioTask
+	}
+	amount, _ := scenario.Data["amount"].(float64)
+	tenantID, _ := scenario.Context["tenant_id"].(string)
+	accountType, _ := scenario.Context["account_type"].(string)
+
+	metrics := derivedMetrics(amount, scenario.Context, scenario.Data)
+	if _, ok := metrics["blacklisted"]; !ok {
+		metrics["blacklisted"] = false
+	}
+
+	outcomes := make(map[string]bool)
+	result := model.RuleScenarioResult{Status: "APPROVED"}
+	for _, r := range rr.guardrail.Evaluate(task, tenantID, accountType, metrics) {
+		outcomes[r.Rule] = r.Passed
+		if !r.Passed {
+			result.FailedRules = append(result.FailedRules, r.Rule)
+			result.Status = "REJECTED"
+		}
+	}
+
+	expect := scenario.Expect
+	result.Mismatches = statusMismatches(result.Status, expect.Status)
+	for _, rule := range expect.Fails {
+		passed, applied := outcomes[rule]
+		switch {
+		case !applied:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("rule %s did not apply, expected it to fail", rule))
+		case passed:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("rule %s passed, expected it to fail", rule))
+		}
+	}
+	for _, rule := range expect.Passes {
+		passed, applied := outcomes[rule]
+		switch {
+		case !applied:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("rule %s did not apply, expected it to pass", rule))
+		case !passed:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("rule %s failed, expected it to pass", rule))
+		}
+	}
+	return result
+}
+
+// runFraudScenario scores a scenario with the fraud rules as the Fraud Agent would
+// for the request. A graph_risk in the context is scored as the transfer graph's.
+func runFraudScenario(scenario *model.RuleScenario) model.RuleScenarioResult {
+	amount, _ := scenario.Data["amount"].(float64)
+	inputs := make(map[string]interface{}, len(scenario.Context)+1)
+	for k, v := range scenario.Context {
+		inputs[k] = v
+	}
+	if _, ok := scenario.Data["amount"].(float64); ok {
+		inputs["amount"] = amount
+	}
+
+	score := ruleFraudScore(amount, inputs)
+	status, _ := fraudStatus(score)
+	result := model.RuleScenarioResult{
+		Status:    status,
+		Score:     &score,
+		RiskLevel: fraudRiskLevel(score),
+		Flags:     fraudFlags(amount, scenario.Context),
+	}
+
+	expect := scenario.Expect
+	result.Mismatches = statusMismatches(result.Status, expect.Status)
+	if expect.MinScore != nil && score < *expect.MinScore {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("score %.2f is below min_score %.2f", score, *expect.MinScore))
+	}
+	if expect.MaxScore != nil && score > *expect.MaxScore {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("score %.2f is above max_score %.2f", score, *expect.MaxScore))
+	}
+	if expect.RiskLevel != "" && result.RiskLevel != expect.RiskLevel {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("risk level is %s, expected %s", result.RiskLevel, expect.RiskLevel))
+	}
+	raised := make(map[string]bool, len(result.Flags))
+	for _, flag := range result.Flags {
+		raised[flag] = true
+	}
+	for _, flag := range expect.Flags {
+		if !raised[flag] {
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("flag %s was not raised", flag))
+		}
+	}
+	for _, flag := range expect.NotFlags {
+		if raised[flag] {
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("flag %s was raised, expected it not to be", flag))
+		}
+	}
+	return result
+}
+
+// statusMismatches compares a scenario's status with the one expected, if any
+func statusMismatches(status, expected string) []string {
+	if expected == "" || status == expected {
+		return nil
+	}
+	return []string{fmt.Sprintf("status is %s, expected %s", status, expected)}
+}
+
+// ScenarioSuitesFor returns the suites for one agent type, in load order
+func ScenarioSuitesFor(suites []*model.RuleScenarioSuite, agentType string) []*model.RuleScenarioSuite {
+	matching := []*model.RuleScenarioSuite{}
+	for _, suite := range suites {
+		if suite.Agent == agentType {
+			matching = append(matching, suite)
+		}
+	}
+	return matching
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aibanking/agent-mesh/internal/model"
@@ -12,17 +13,22 @@ import (
 // ScoringAgent handles credit scoring, fraud scoring, and risk assessment
 type ScoringAgent struct {
 	*AgentBase
+	scorer *ModelScorer
 }
 
 // NewScoringAgent creates a new scoring agent
-func NewScoringAgent(base *AgentBase) *ScoringAgent {
+func NewScoringAgent(base *AgentBase, scorer *ModelScorer) *ScoringAgent {
 	return &ScoringAgent{
 		AgentBase: base,
+		scorer:    scorer,
 	}
 }
 
 // Process processes a scoring request
-func (sa *ScoringAgent) Process(ctx context.Context, req *model.AgentRequest) (*model.AgentResponse, error) {
+func (sa *ScoringAgent) Process(ctx context.Context, req *model.AgentRequest) (resp *model.AgentResponse, err error) {
+	ctx, diagnostics := startDiagnostics(ctx)
+	defer func() { diagnostics.attach(resp) }()
+
 	log.Info().
 		Str("task", req.Task).
 		Str("request_id", req.RequestID).
@@ -38,20 +44,58 @@ func (sa *ScoringAgent) Process(ctx context.Context, req *model.AgentRequest) (*
 	var riskScore float64
 	var explanation string
 
+	// The registry's model is tried first; the rules below score when it cannot be used
+	var modelName string
 	switch scoreType {
 	case "CREDIT":
-		result, riskScore, explanation = sa.calculateCreditScore(ctx, inputCtx)
+		modelName = "credit"
 	case "FRAUD":
-		result, riskScore, explanation = sa.calculateFraudScore(ctx, inputCtx)
+		modelName = "fraud"
 	case "RISK":
-		result, riskScore, explanation = sa.calculateRiskScore(ctx, inputCtx)
+		modelName = "risk"
 	default:
 		return nil, fmt.Errorf("unsupported score type: %s", scoreType)
 	}
+	mlResult, version := sa.scorer.Predict(ctx, modelName, inputCtx, inputCtx)
+
+	scored := false
+	if mlResult != nil {
+		result, riskScore, explanation, scored = sa.fromModel(scoreType, mlResult)
+		if !scored {
+			version.Source = "rules"
+			version.Fallback = "invalid_ml_result"
+		}
+	}
+	if !scored {
+		switch scoreType {
+		case "CREDIT":
+			result, riskScore, explanation = sa.calculateCreditScore(ctx, inputCtx)
+		case "FRAUD":
+			result, riskScore, explanation = sa.calculateFraudScore(ctx, inputCtx)
+		case "RISK":
+			result, riskScore, explanation = sa.calculateRiskScore(ctx, inputCtx)
+		}
+	}
+
+	// Tell the user how to improve a credit score, not just what it is
+	if scoreType == "CREDIT" {
+		if tips := sa.addCreditTips(result, inputCtx); len(tips) > 0 {
+			explanation += fmt.Sprintf("; %d %s to improve it", len(tips), plural(float64(len(tips)), "way", "ways"))
+		}
+	}
+
+	// Scores from rules because the request lacked what the model needs are less certain
+	confidence := 0.9
+	if len(version.MissingFeatures) > 0 {
+		confidence = 0.6
+		explanation += fmt.Sprintf(" (scored by rules; missing %s)", strings.Join(version.MissingFeatures, ", "))
+	}
 
 	log.Info().
 		Str("score_type", scoreType).
 		Float64("risk_score", riskScore).
+		Str("model_version", version.Version).
+		Str("model_source", version.Source).
 		Msg("Scoring completed")
 
 	return &model.AgentResponse{
@@ -61,65 +105,72 @@ func (sa *ScoringAgent) Process(ctx context.Context, req *model.AgentRequest) (*
 		Result:      result,
 		RiskScore:   riskScore,
 		Explanation: explanation,
-		Confidence:  0.9,
+		Confidence:  confidence,
 		Timestamp:   time.Now(),
 		RequestID:   req.RequestID,
+		Model:       version,
 	}, nil
 }
 
-// calculateCreditScore calculates credit score
-func (sa *ScoringAgent) calculateCreditScore(ctx context.Context, context map[string]interface{}) (map[string]interface{}, float64, string) {
-	// Extract user profile data
-	accountAge, _ := context["account_age_days"].(float64)
-	income, _ := context["income"].(float64)
-	delinquency, _ := context["delinquency_count"].(float64)
-	loanHistory, _ := context["loan_history_count"].(float64)
-	balance, _ := context["balance"].(float64)
-
-	// Base score
-	score := 600.0
-
-	// Account age factor (max +50 points)
-	if accountAge > 365 {
-		score += 50
-	} else if accountAge > 180 {
-		score += 30
-	} else if accountAge > 90 {
-		score += 15
-	}
-
-	// Income factor (max +100 points)
-	if income > 100000 {
-		score += 100
-	} else if income > 50000 {
-		score += 60
-	} else if income > 25000 {
-		score += 30
-	}
-
-	// Delinquency penalty (max -100 points)
-	score -= delinquency * 20
-	if score < 300 {
-		score = 300
-	}
-
-	// Loan history bonus (max +50 points)
-	if loanHistory > 0 {
-		score += 30
+// fromModel turns an ML service result into the agent's result, risk score and
+// explanation. It returns false when the result lacks the score.
+func (sa *ScoringAgent) fromModel(scoreType string, ml map[string]interface{}) (map[string]interface{}, float64, string, bool) {
+	switch scoreType {
+	case "CREDIT":
+		score, ok := ml["credit_score"].(float64)
+		if !ok {
+			return nil, 0, "", false
+		}
+		ml["credit_score"] = int(score)
+		if _, ok := ml["score_range"]; !ok {
+			ml["score_range"] = sa.getScoreRange(score)
+		}
+		if _, ok := ml["risk_category"]; !ok {
+			ml["risk_category"] = sa.getRiskCategory(1.0 - (score / 850.0))
+		}
+		return ml, 1.0 - (score / 850.0), fmt.Sprintf("Credit score calculated: %d (%s)", int(score), ml["score_range"]), true
+	case "FRAUD":
+		score, ok := ml["fraud_score"].(float64)
+		if !ok {
+			return nil, 0, "", false
+		}
+		result := map[string]interface{}{
+			"fraud_score":    score,
+			"risk_level":     sa.getRiskLevel(score),
+			"recommendation": sa.getFraudRecommendation(score),
+		}
+		return result, score, fmt.Sprintf("Fraud risk score: %.2f (%s)", score, sa.getRiskLevel(score)), true
+	case "RISK":
+		overallRisk, ok := ml["overall_risk_score"].(float64)
+		if !ok {
+			return nil, 0, "", false
+		}
+		result := map[string]interface{}{
+			"overall_risk":  overallRisk,
+			"risk_category": sa.getRiskCategory(overallRisk),
+			"components":    ml["components"],
+		}
+		return result, overallRisk, fmt.Sprintf("Overall risk score: %.2f (%s)", overallRisk, sa.getRiskCategory(overallRisk)), true
 	}
+	return nil, 0, "", false
+}
 
-	// Balance factor (max +50 points)
-	if balance > 100000 {
-		score += 50
-	} else if balance > 50000 {
-		score += 30
-	} else if balance > 10000 {
-		score += 15
+// calculateCreditScore calculates credit score
+func (sa *ScoringAgent) calculateCreditScore(ctx context.Context, context map[string]interface{}) (map[string]interface{}, float64, string) {
+	factors := creditFactors(context)
+	breakdown := make(map[string]interface{}, len(factors))
+
+	score := creditBaseScore
+	for _, f := range factors {
+		score += f.Points
+		// Penalties stop at the floor; the bonuses after them still count
+		if f.Points < 0 && score < creditMinScore {
+			score = creditMinScore
+		}
+		breakdown[f.Factor] = f.Value
 	}
-
-	// Cap at 850
-	if score > 850 {
-		score = 850
+	if score > creditMaxScore {
+		score = creditMaxScore
 	}
 
 	// Convert to risk score (inverse: higher credit score = lower risk)
@@ -127,15 +178,9 @@ func (sa *ScoringAgent) calculateCreditScore(ctx context.Context, context map[st
 
 	result := map[string]interface{}{
 		"credit_score":  int(score),
-		"score_range":  sa.getScoreRange(score),
+		"score_range":   sa.getScoreRange(score),
 		"risk_category": sa.getRiskCategory(riskScore),
-		"factors": map[string]interface{}{
-			"account_age":    accountAge,
-			"income":         income,
-			"delinquency":    delinquency,
-			"loan_history":   loanHistory,
-			"balance":        balance,
-		},
+		"factors":       breakdown,
 	}
 
 	explanation := fmt.Sprintf("Credit score calculated: %d (%s)", int(score), sa.getScoreRange(score))
@@ -143,6 +188,21 @@ func (sa *ScoringAgent) calculateCreditScore(ctx context.Context, context map[st
 	return result, riskScore, explanation
 }
 
+// addCreditTips adds the points each factor contributed and the actions that would
+// raise the score, whether the model or the rules scored it. Factors the ML
+// service returned are left as they are.
+func (sa *ScoringAgent) addCreditTips(result map[string]interface{}, context map[string]interface{}) []model.CreditTip {
+	score, ok := result["credit_score"].(int)
+	if !ok {
+		return nil
+	}
+	factors := creditFactors(context)
+	tips := creditTips(factors, float64(score))
+	result["factor_breakdown"] = factors
+	result["tips"] = tips
+	return tips
+}
+
 // calculateFraudScore calculates fraud risk score
 func (sa *ScoringAgent) calculateFraudScore(ctx context.Context, context map[string]interface{}) (map[string]interface{}, float64, string) {
 	amount, _ := context["amount"].(float64)
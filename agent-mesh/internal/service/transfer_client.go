@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+var (
+	// ErrTransferRefused is returned for a transfer Banking Integrations refused before
+	// moving any money, such as one from a frozen account, wrapped with its reason
+	ErrTransferRefused = errors.New("transfer refused")
+	// ErrTransferUnknown is returned by a lookup when Banking Integrations remembers no
+	// transfer with the key, which it may have forgotten on a restart
+	ErrTransferUnknown = errors.New("no transfer with this idempotency key")
+)
+
+// TransferClient sends transfers to Banking Integrations (Layer 5) and looks them up
+// by idempotency key
+type TransferClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewTransferClient creates a new transfer client
+func NewTransferClient(cfg *config.BankingIntegrationsConfig) *TransferClient {
+	return &TransferClient{
+		baseURL:    cfg.BaseURL,
+		apiKey:     config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: newDownstreamClient(cfg.Timeout, &cfg.Replay),
+	}
+}
+
+// Warmup opens a connection to Banking Integrations ahead of traffic
+func (tc *TransferClient) Warmup(ctx context.Context) error {
+	return pingHealth(ctx, tc.httpClient, tc.baseURL)
+}
+
+// Transfer sends a transfer. A 4xx answer, other than a repeat of a transfer still in
+// progress, is an ErrTransferRefused; any other error leaves it unknown whether the
+// money moved.
+func (tc *TransferClient) Transfer(ctx context.Context, req *model.TransferRequest) (result *model.TransferResponse, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:transfer", start, err) }(time.Now())
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/transfer", tc.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Idempotency-Key", req.IdempotencyKey)
+
+	body, code, err := tc.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	if code >= 400 && code < 500 && code != http.StatusConflict && code != http.StatusTooManyRequests {
+		var apiErr struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		json.Unmarshal(body, &apiErr)
+		reason := apiErr.Details
+		if reason == "" {
+			reason = apiErr.Error
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTransferRefused, reason)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	result = &model.TransferResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer: %w", err)
+	}
+	return result, nil
+}
+
+// Lookup returns the response of the transfer made with an idempotency key, or
+// ErrTransferUnknown when Banking Integrations does not remember one
+func (tc *TransferClient) Lookup(ctx context.Context, key string) (result *model.TransferResponse, err error) {
+	defer func(start time.Time) { recordCall(ctx, "banking:transfer_lookup", start, err) }(time.Now())
+
+	endpoint := fmt.Sprintf("%s/api/v1/transfers/idempotency/%s", tc.baseURL, url.PathEscape(key))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, code, err := tc.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up transfer: %w", err)
+	}
+	if code == http.StatusNotFound {
+		return nil, ErrTransferUnknown
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	result = &model.TransferResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer: %w", err)
+	}
+	return result, nil
+}
+
+func (tc *TransferClient) do(httpReq *http.Request) ([]byte, int, error) {
+	httpReq.Header.Set("X-API-Key", tc.apiKey.Get())
+	resp, err := tc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
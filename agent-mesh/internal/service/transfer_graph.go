@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/config"
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// TransferGraph tracks who pays whom: sender→beneficiary edges read from each
+// sender's DWH history when the sender is first checked, and refreshed after
+// GraphRefreshMinutes, plus the transfers the Fraud Agent lets through. From it come
+// mule signals per-user counters miss: a beneficiary many senders pay (fan-in), a
+// sender paying many beneficiaries (fan-out), and a new beneficiary several senders
+// start paying at once. Edges are held in memory and cover GraphWindowDays.
+type TransferGraph struct {
+	cfg    *config.FraudConfig
+	dwh    *DWHClient
+	out    map[string]map[string]*graphEdge // Sender → beneficiary
+	in     map[string]map[string]*graphEdge // Beneficiary → sender
+	seen   map[string]time.Time             // DWH transactions already in the graph
+	loaded map[string]time.Time             // When each sender's edges were last read from the DWH
+	mu     sync.Mutex
+}
+
+type graphEdge struct {
+	transfers int
+	amount    float64
+	firstAt   time.Time
+	lastAt    time.Time
+}
+
+// NewTransferGraph creates an empty transfer graph fed from the DWH
+func NewTransferGraph(cfg *config.FraudConfig, dwh *DWHClient) *TransferGraph {
+	return &TransferGraph{
+		cfg:    cfg,
+		dwh:    dwh,
+		out:    make(map[string]map[string]*graphEdge),
+		in:     make(map[string]map[string]*graphEdge),
+		seen:   make(map[string]time.Time),
+		loaded: make(map[string]time.Time),
+	}
+}
+
+// Signals scores a transfer from sender to beneficiary against the graph, counting
+// the transfer itself
+func (tg *TransferGraph) Signals(ctx context.Context, sender, beneficiary string) *model.GraphSignals {
+	tg.load(ctx, sender)
+
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.prune()
+
+	now := time.Now()
+	fanOut := len(tg.out[sender])
+	if _, ok := tg.out[sender][beneficiary]; !ok {
+		fanOut++
+	}
+	fanIn, clusterSenders, firstAt := tg.beneficiary(beneficiary, now)
+	if _, ok := tg.in[beneficiary][sender]; !ok {
+		fanIn++
+		clusterSenders++
+		if firstAt.IsZero() {
+			firstAt = now
+		}
+	}
+
+	signals := &model.GraphSignals{
+		FanIn:          fanIn,
+		FanOut:         fanOut,
+		ClusterSenders: clusterSenders,
+		NewCluster:     tg.newCluster(firstAt, clusterSenders, now),
+	}
+	signals.GraphRisk = tg.risk(fanIn, fanOut, signals.NewCluster)
+	signals.Flags = tg.flags(fanIn, fanOut, signals.NewCluster)
+	return signals
+}
+
+// Record adds a transfer the Fraud Agent let through, ahead of it reaching the DWH
+func (tg *TransferGraph) Record(sender, beneficiary string, amount float64, at time.Time) {
+	if sender == "" || beneficiary == "" {
+		return
+	}
+	tg.mu.Lock()
+	tg.add(sender, beneficiary, amount, at)
+	tg.mu.Unlock()
+}
+
+// UserGraph returns a user's outgoing edges, busiest beneficiary first, with who
+// else pays each beneficiary
+func (tg *TransferGraph) UserGraph(ctx context.Context, userID string) *model.UserTransferGraph {
+	tg.load(ctx, userID)
+
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.prune()
+
+	now := time.Now()
+	graph := &model.UserTransferGraph{
+		UserID:     userID,
+		WindowDays: tg.cfg.GraphWindowDays,
+		FanOut:     len(tg.out[userID]),
+		Edges:      []model.TransferEdge{},
+	}
+	if loadedAt, ok := tg.loaded[userID]; ok {
+		graph.LoadedAt = &loadedAt
+	}
+
+	maxFanIn := 0
+	cluster := false
+	for beneficiary, e := range tg.out[userID] {
+		fanIn, clusterSenders, firstAt := tg.beneficiary(beneficiary, now)
+		edge := model.TransferEdge{
+			Beneficiary:    beneficiary,
+			Transfers:      e.transfers,
+			Amount:         e.amount,
+			FirstAt:        e.firstAt,
+			LastAt:         e.lastAt,
+			FanIn:          fanIn,
+			ClusterSenders: clusterSenders,
+			NewCluster:     tg.newCluster(firstAt, clusterSenders, now),
+		}
+		for sender := range tg.in[beneficiary] {
+			if sender != userID {
+				edge.OtherSenders = append(edge.OtherSenders, sender)
+			}
+		}
+		sort.Strings(edge.OtherSenders)
+		graph.Edges = append(graph.Edges, edge)
+
+		if fanIn > maxFanIn {
+			maxFanIn = fanIn
+		}
+		cluster = cluster || edge.NewCluster
+	}
+	sort.Slice(graph.Edges, func(a, b int) bool {
+		if graph.Edges[a].FanIn != graph.Edges[b].FanIn {
+			return graph.Edges[a].FanIn > graph.Edges[b].FanIn
+		}
+		return graph.Edges[a].Beneficiary < graph.Edges[b].Beneficiary
+	})
+	graph.Flags = tg.flags(maxFanIn, graph.FanOut, cluster)
+	return graph
+}
+
+// load reads a sender's outgoing transfers from the DWH unless they were read within
+// the refresh interval. When the DWH cannot be reached the graph goes on with what
+// it has.
+func (tg *TransferGraph) load(ctx context.Context, sender string) {
+	tg.mu.Lock()
+	loadedAt, ok := tg.loaded[sender]
+	tg.mu.Unlock()
+	if ok && time.Since(loadedAt) < time.Duration(tg.cfg.GraphRefreshMinutes)*time.Minute {
+		return
+	}
+
+	history, err := tg.dwh.History(ctx, sender, tg.cfg.GraphWindowDays)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", sender).Msg("Failed to read transfers for the transfer graph")
+		return
+	}
+
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	for _, txn := range history {
+		if txn.ToAccount == "" || strings.EqualFold(txn.Type, "CREDIT") || !transferSucceeded(txn.Status) {
+			continue
+		}
+		if _, ok := tg.seen[txn.TransactionID]; ok {
+			continue
+		}
+		tg.seen[txn.TransactionID] = txn.CreatedAt
+		tg.add(sender, txn.ToAccount, txn.Amount, txn.CreatedAt)
+	}
+	tg.loaded[sender] = time.Now()
+}
+
+// add counts a transfer on its edge. Callers hold tg.mu.
+func (tg *TransferGraph) add(sender, beneficiary string, amount float64, at time.Time) {
+	e, ok := tg.out[sender][beneficiary]
+	if !ok {
+		e = &graphEdge{firstAt: at, lastAt: at}
+		if tg.out[sender] == nil {
+			tg.out[sender] = make(map[string]*graphEdge)
+		}
+		if tg.in[beneficiary] == nil {
+			tg.in[beneficiary] = make(map[string]*graphEdge)
+		}
+		tg.out[sender][beneficiary] = e
+		tg.in[beneficiary][sender] = e
+	}
+	e.transfers++
+	e.amount += amount
+	if at.Before(e.firstAt) {
+		e.firstAt = at
+	}
+	if at.After(e.lastAt) {
+		e.lastAt = at
+	}
+}
+
+// prune drops edges last used before the window. Callers hold tg.mu.
+func (tg *TransferGraph) prune() {
+	cutoff := time.Now().AddDate(0, 0, -tg.cfg.GraphWindowDays)
+	for sender, edges := range tg.out {
+		for beneficiary, e := range edges {
+			if e.lastAt.Before(cutoff) {
+				delete(edges, beneficiary)
+				delete(tg.in[beneficiary], sender)
+				if len(tg.in[beneficiary]) == 0 {
+					delete(tg.in, beneficiary)
+				}
+			}
+		}
+		if len(edges) == 0 {
+			delete(tg.out, sender)
+		}
+	}
+	for id, at := range tg.seen {
+		if at.Before(cutoff) {
+			delete(tg.seen, id)
+		}
+	}
+}
+
+// beneficiary returns how many distinct senders pay a beneficiary, how many of them
+// started within the cluster window, and when it was first paid. Callers hold tg.mu.
+func (tg *TransferGraph) beneficiary(beneficiary string, now time.Time) (fanIn, clusterSenders int, firstAt time.Time) {
+	since := now.Add(-time.Duration(tg.cfg.GraphClusterHours) * time.Hour)
+	for _, e := range tg.in[beneficiary] {
+		fanIn++
+		if !e.firstAt.Before(since) {
+			clusterSenders++
+		}
+		if firstAt.IsZero() || e.firstAt.Before(firstAt) {
+			firstAt = e.firstAt
+		}
+	}
+	return fanIn, clusterSenders, firstAt
+}
+
+// newCluster reports whether a beneficiary first paid within the cluster window
+// already has enough senders to look like a mule account being spun up
+func (tg *TransferGraph) newCluster(firstAt time.Time, clusterSenders int, now time.Time) bool {
+	isNew := !firstAt.Before(now.Add(-time.Duration(tg.cfg.GraphClusterHours) * time.Hour))
+	return isNew && clusterSenders >= tg.cfg.GraphClusterSenders
+}
+
+// risk weighs fan-in most, as mule accounts collect from many victims, then a new
+// cluster, then fan-out
+func (tg *TransferGraph) risk(fanIn, fanOut int, newCluster bool) float64 {
+	risk := 0.45*ratio(fanIn-1, tg.cfg.GraphFanIn-1) + 0.25*ratio(fanOut-1, tg.cfg.GraphFanOut-1)
+	if newCluster {
+		risk += 0.3
+	}
+	return math.Round(math.Min(risk, 1)*1000) / 1000
+}
+
+func (tg *TransferGraph) flags(fanIn, fanOut int, newCluster bool) []string {
+	var flags []string
+	if fanIn >= tg.cfg.GraphFanIn {
+		flags = append(flags, model.FlagMuleFanIn)
+	}
+	if fanOut >= tg.cfg.GraphFanOut {
+		flags = append(flags, model.FlagHighFanOut)
+	}
+	if newCluster {
+		flags = append(flags, model.FlagNewBeneficiaryCluster)
+	}
+	return flags
+}
+
+// ratio returns n over limit, capped at 1
+func ratio(n, limit int) float64 {
+	if n <= 0 || limit <= 0 {
+		return 0
+	}
+	return math.Min(float64(n)/float64(limit), 1)
+}
+
+// transferSucceeded reports whether a DWH transaction status means money moved
+func transferSucceeded(status string) bool {
+	switch strings.ToUpper(status) {
+	case "FAILED", "REJECTED", "REVERSED":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aibanking/agent-mesh/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Warmable is a downstream client that can open its connections ahead of traffic
+type Warmable interface {
+	Warmup(ctx context.Context) error
+}
+
+type warmupTarget struct {
+	name   string
+	client Warmable
+}
+
+// Warmer gets an agent ready for traffic the MCP Server expects, by pinging the
+// downstream services the agent calls so their connections are pooled and any cold
+// start happens before the first real request rather than during it
+type Warmer struct {
+	agentType string
+	cooldown  time.Duration
+	targets   []warmupTarget
+
+	mu   sync.Mutex
+	last *model.WarmupResponse
+}
+
+// NewWarmer creates a new warmer
+func NewWarmer(agentType string, cooldownSeconds int) *Warmer {
+	cooldown := time.Duration(cooldownSeconds) * time.Second
+	if cooldown < 0 {
+		cooldown = 0
+	}
+	return &Warmer{
+		agentType: agentType,
+		cooldown:  cooldown,
+	}
+}
+
+// Add registers a downstream client to warm; nil clients are skipped
+func (w *Warmer) Add(name string, client Warmable) {
+	if client == nil {
+		return
+	}
+	w.targets = append(w.targets, warmupTarget{name: name, client: client})
+}
+
+// Warm pings every target concurrently. Within the cooldown the last result is
+// returned instead, unless force is set.
+func (w *Warmer) Warm(ctx context.Context, force bool) *model.WarmupResponse {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !force && w.last != nil && time.Since(w.last.WarmedAt) < w.cooldown {
+		cached := *w.last
+		cached.Cached = true
+		return &cached
+	}
+
+	start := time.Now()
+	results := make([]model.WarmupTarget, len(w.targets))
+	var wg sync.WaitGroup
+	for i, target := range w.targets {
+		wg.Add(1)
+		go func(i int, target warmupTarget) {
+			defer wg.Done()
+			callStart := time.Now()
+			err := target.client.Warmup(ctx)
+			results[i] = model.WarmupTarget{
+				Name:      target.name,
+				OK:        err == nil,
+				LatencyMs: float64(time.Since(callStart).Microseconds()) / 1000,
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	w.last = &model.WarmupResponse{
+		AgentType:  w.agentType,
+		WarmedAt:   start,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		Targets:    results,
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.OK {
+			failed++
+		}
+	}
+	log.Info().
+		Str("agent_type", w.agentType).
+		Int("targets", len(results)).
+		Int("failed", failed).
+		Float64("duration_ms", w.last.DurationMs).
+		Msg("Agent warmed up")
+
+	copied := *w.last
+	return &copied
+}
+
+// pingHealth calls a service's /health endpoint and drains the body so the
+// connection goes back to the client's pool
+func pingHealth(ctx context.Context, client *http.Client, baseURL string) error {
+	if baseURL == "" {
+		return fmt.Errorf("no base URL configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned %d", resp.StatusCode)
+	}
+	return nil
+}
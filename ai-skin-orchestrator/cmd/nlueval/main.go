@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	engine := flag.String("engine", service.NLUEngineRules, "Engine to evaluate: rules or llm")
+	datasetPath := flag.String("dataset", "", "Labeled dataset file (defaults to the embedded dataset)")
+	check := flag.Bool("check", false, "Fail if the dataset's thresholds for the engine are not met")
+	asJSON := flag.Bool("json", false, "Print the full report as JSON")
+	flag.Parse()
+
+	// Keep service logging out of the report
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	dataset, err := service.LoadNLUDataset(*datasetPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var llmService *service.LLMService
+	if *engine == service.NLUEngineLLM {
+		if llmService, err = newLLMService(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	evaluator := service.NewNLUEvaluator(service.NewIntentParser(llmService, llmService != nil), llmService, dataset)
+	report, err := evaluator.Run(context.Background(), *engine)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "evaluation failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		printReport(report)
+	}
+
+	if *check && !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// newLLMService builds the LLM service from the same environment as the server
+func newLLMService() (*service.LLMService, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if !cfg.LLM.Enabled {
+		return nil, fmt.Errorf("the llm engine needs LLM_ENABLED=true")
+	}
+
+	promptService, err := service.NewPromptService(&cfg.Prompts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+	ollamaService := service.NewOllamaService(&cfg.Ollama)
+	promptGuard := service.NewPromptGuard()
+	llmService := service.NewLLMService(&cfg.LLM, ollamaService, promptService, promptGuard, service.NewLLMQuota(&config.QuotaConfig{}))
+
+	// Parse with the few-shot examples the server would show the LLM
+	var embedder service.Embedder = service.NewHashEmbedder(cfg.RAG.HashDimensions)
+	if cfg.RAG.EmbeddingProvider == service.ProviderOllama {
+		embedder = service.NewOllamaEmbedder(ollamaService, cfg.RAG.EmbeddingModel)
+	}
+	fewShot, err := service.NewFewShotLibrary(&cfg.FewShot, embedder, promptGuard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load few-shot examples: %w", err)
+	}
+	llmService.SetFewShot(fewShot)
+	return llmService, nil
+}
+
+// printReport prints scores, per-intent accuracy and failures as text
+func printReport(r *model.NLUReport) {
+	fmt.Printf("dataset %s v%d, engine %s, %d cases, %dms\n", r.Dataset, r.DatasetVersion, r.Engine, r.Cases, r.DurationMs)
+	fmt.Printf("intent accuracy %.3f  entity precision %.3f  recall %.3f  F1 %.3f\n\n", r.IntentAccuracy, r.EntityPrecision, r.EntityRecall, r.EntityF1)
+
+	intents := make([]string, 0, len(r.PerIntent))
+	for intent := range r.PerIntent {
+		intents = append(intents, string(intent))
+	}
+	sort.Strings(intents)
+	for _, intent := range intents {
+		score := r.PerIntent[model.IntentType(intent)]
+		fmt.Printf("%-20s %3d/%-3d %.3f\n", intent, score.Correct, score.Cases, score.Accuracy)
+	}
+
+	if len(r.Failures) > 0 {
+		fmt.Println("\nfailures:")
+	}
+	for _, f := range r.Failures {
+		detail := fmt.Sprintf("expected %s, got %s", f.ExpectedIntent, f.ActualIntent)
+		if f.Error != "" {
+			detail = "error: " + f.Error
+		}
+		if len(f.Missing) > 0 {
+			detail += "; missing " + strings.Join(f.Missing, ",")
+		}
+		if len(f.Unexpected) > 0 {
+			detail += "; unexpected " + strings.Join(f.Unexpected, ",")
+		}
+		fmt.Printf("  %-28s %q: %s\n", f.ID, f.Text, detail)
+	}
+
+	verdict := "PASS"
+	if !r.Passed {
+		verdict = "FAIL: " + strings.Join(r.Violations, "; ")
+	}
+	fmt.Printf("\n%s\n", verdict)
+}
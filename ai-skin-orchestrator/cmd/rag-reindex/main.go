@@ -0,0 +1,147 @@
+// Command rag-reindex migrates the RAG documents of a running ai-skin-orchestrator to
+// another embedding model via the admin re-index API. It starts a job, or resumes a
+// paused one, and by default follows it until it completes or pauses, printing
+// progress as it goes.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8081", "AI Skin Orchestrator base URL")
+	apiKey := flag.String("api-key", "rag-reindex-cli", "API key sent in the X-API-Key header")
+	provider := flag.String("provider", "ollama", "Embedding provider to migrate to: hash or ollama")
+	embeddingModel := flag.String("model", "", "Ollama embedding model (default: the server's RAG_EMBEDDING_MODEL)")
+	dimensions := flag.Int("dimensions", 0, "Vector size for the hash provider (default: the server's RAG_HASH_DIMENSIONS)")
+	batchSize := flag.Int("batch-size", 0, "Documents per embedding call (default: the server's RAG_REINDEX_BATCH_SIZE)")
+	resume := flag.Bool("resume", false, "Resume the paused job instead of starting one")
+	status := flag.Bool("status", false, "Print the current job and exit")
+	wait := flag.Bool("wait", true, "Follow the job until it completes or pauses")
+	timeout := flag.Duration("timeout", 2*time.Hour, "How long to follow the job")
+	flag.Parse()
+
+	c := &client{http: &http.Client{Timeout: 10 * time.Second}, baseURL: *baseURL, apiKey: *apiKey}
+	var err error
+	switch {
+	case *status:
+		var job model.ReindexJob
+		if err = c.call(http.MethodGet, "/api/v1/admin/rag/reindex", nil, &job); err == nil {
+			err = printJSON(job)
+		}
+	default:
+		err = run(c, &model.ReindexRequest{
+			Provider:   *provider,
+			Model:      *embeddingModel,
+			Dimensions: *dimensions,
+			BatchSize:  *batchSize,
+		}, *resume, *wait, *timeout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rag-reindex:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *client, req *model.ReindexRequest, resume, wait bool, timeout time.Duration) error {
+	var job model.ReindexJob
+	if resume {
+		if err := c.call(http.MethodPost, "/api/v1/admin/rag/reindex/resume", nil, &job); err != nil {
+			return err
+		}
+	} else if err := c.call(http.MethodPost, "/api/v1/admin/rag/reindex", req, &job); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s -> %s, %d documents\n", job.JobID, job.FromModel, job.ToModel, job.Total)
+	if !wait {
+		return printJSON(job)
+	}
+
+	deadline := time.Now().Add(timeout)
+	lastDone := -1
+	for {
+		if err := c.call(http.MethodGet, "/api/v1/admin/rag/reindex", nil, &job); err != nil {
+			return err
+		}
+		if job.Done != lastDone {
+			fmt.Fprintf(os.Stderr, "%s: %d/%d documents in %d batches\n", job.Status, job.Done, job.Total, job.Batches)
+			lastDone = job.Done
+		}
+		switch job.Status {
+		case model.ReindexCompleted:
+			return printJSON(job)
+		case model.ReindexPaused:
+			if err := printJSON(job); err != nil {
+				return err
+			}
+			return fmt.Errorf("job %s paused: %s; continue with -resume", job.JobID, job.Error)
+		case model.ReindexCancelled:
+			if err := printJSON(job); err != nil {
+				return err
+			}
+			return fmt.Errorf("job %s was cancelled", job.JobID)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("job %s still running after %s (%d/%d documents); it carries on in the service", job.JobID, timeout, job.Done, job.Total)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+type client struct {
+	http    *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func (c *client) call(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
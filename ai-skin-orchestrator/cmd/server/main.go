@@ -15,10 +15,17 @@ import (
 	"github.com/aibanking/ai-skin-orchestrator/internal/router"
 	"github.com/aibanking/ai-skin-orchestrator/internal/service"
 	"github.com/aibanking/ai-skin-orchestrator/internal/utils"
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/tz"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	// Store and send every time in UTC, whatever time zone the host runs in
+	tz.UseUTC()
+
 	// Initialize configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -27,10 +34,51 @@ func main() {
 
 	utils.InitLogger(cfg.Logging.Level, cfg.Logging.Format)
 
-	log.Info().Msg("Starting AI Skin Orchestrator (Layer 2)")
+	// Fail fast on settings that are missing or unsafe for the environment
+	problems := cfg.Validate()
+	for _, p := range problems {
+		log.Warn().Str("setting", p.Key).Str("severity", p.Severity).Msg(p.Message)
+	}
+	if profile.HasErrors(problems) {
+		log.Fatal().Str("environment", cfg.Environment).Msg("Invalid configuration, run cmd/config-lint for details")
+	}
+
+	log.Info().Str("environment", cfg.Environment).Msg("Starting AI Skin Orchestrator (Layer 2)")
 
 	// Initialize services
-	llmService := service.NewLLMService(&cfg.LLM)
+	promptService, err := service.NewPromptService(&cfg.Prompts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load prompt templates")
+	}
+	promptGuard := service.NewPromptGuard()
+	ollamaService := service.NewOllamaService(&cfg.Ollama)
+	llmQuota := service.NewLLMQuota(&cfg.Quota)
+	llmService := service.NewLLMService(&cfg.LLM, ollamaService, promptService, promptGuard, llmQuota)
+
+	// Verify the local models are present before taking traffic
+	if cfg.LLM.Enabled && cfg.LLM.Provider == service.ProviderOllama {
+		checkCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Ollama.PullTimeout)*time.Second)
+		err := ollamaService.EnsureModels(checkCtx, llmService.Models(), cfg.Ollama.PullOnStart)
+		cancel()
+		if err != nil && cfg.Ollama.StartupCheck {
+			log.Fatal().Err(err).Msg("Ollama models not ready")
+		} else if err != nil {
+			log.Warn().Err(err).Msg("Ollama models not ready, LLM calls will fail until they are pulled")
+		} else {
+			log.Info().Strs("models", llmService.Models()).Msg("Ollama models ready")
+		}
+	}
+	var embedder service.Embedder = service.NewHashEmbedder(cfg.RAG.HashDimensions)
+	if cfg.RAG.EmbeddingProvider == service.ProviderOllama {
+		embedder = service.NewOllamaEmbedder(ollamaService, cfg.RAG.EmbeddingModel)
+	}
+	ragService := service.NewRAGService(&cfg.RAG, embedder)
+	fewShot, err := service.NewFewShotLibrary(&cfg.FewShot, embedder, promptGuard)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load few-shot examples")
+	}
+	llmService.SetFewShot(fewShot)
+
 	historyService := service.NewHistoryService()
 	behaviorAnalyzer := service.NewBehaviorAnalyzer()
 	riskCalculator := service.NewRiskCalculator()
@@ -38,23 +86,107 @@ func main() {
 	intentParser := service.NewIntentParser(llmService, cfg.LLM.Enabled)
 	contextEnricher := service.NewContextEnricher(historyService, behaviorAnalyzer, riskCalculator)
 	mcpClient := service.NewMCPClient(&cfg.MCPServer)
-	responseMerger := service.NewResponseMerger()
+	intentParser.SetCustomIntents(service.NewCustomIntents(&cfg.MCPServer, mcpClient))
+	responseMerger, err := service.NewResponseMerger(&cfg.Merger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load merger precedences")
+	}
+	responseGuard := service.NewResponseGuard()
+	preferenceClient := service.NewPreferenceClient(&cfg.Banking)
+	calendarClient := service.NewCalendarClient(&cfg.Banking)
+	payeeClient := service.NewPayeeClient(&cfg.Banking)
+	decisionStore := service.NewDecisionStore()
+	contextResolver := service.NewContextResolver(decisionStore)
+	intentFlags := service.NewIntentFlags(&cfg.MCPServer, mcpClient)
+	capabilityService := service.NewCapabilityService(&cfg.MCPServer, mcpClient, llmService, intentFlags)
+	analyticsService := service.NewAnalyticsService(&cfg.Analytics)
+	responsePipeline, err := service.NewResponsePipeline(&cfg.Response)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load response transformers")
+	}
+	handoffRouter, err := service.NewHandoffRouter(&cfg.Handoff)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load handoff screens")
+	}
+
+	// In demo mode a trigger phrase plays its scenario's scripted intent and outcomes
+	var scenarios *demo.Library
+	if cfg.Demo.Enabled {
+		scenarios, err = demo.Load(cfg.Demo.ScenariosDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load demo scenarios")
+		}
+		log.Warn().Int("scenarios", len(scenarios.List())).Msg("Demo mode is on; trigger phrases play scripted scenarios")
+	}
 
 	orchestrator := service.NewOrchestrator(
 		intentParser,
 		contextEnricher,
 		mcpClient,
 		responseMerger,
+		responseGuard,
+		ragService,
+		preferenceClient,
+		decisionStore,
+		contextResolver,
+		calendarClient,
+		payeeClient,
+		capabilityService,
+		responsePipeline,
+		analyticsService,
+		handoffRouter,
+		service.NewScamDetector(&cfg.Scam),
+		service.NewSuggestionGenerator(&cfg.Response, decisionStore, capabilityService),
+		service.NewErrorHumanizer(&cfg.Errors, llmService, promptService, promptGuard),
+		intentFlags,
+		service.NewStatementSummarizer(&cfg.Summary, &cfg.Response, llmService, promptService, promptGuard),
+		scenarios,
 	)
 
+	memoryService := service.NewMemoryService(&cfg.Memory, llmService, promptService, promptGuard)
+	bankingTools := service.NewBankingTools(mcpClient, promptGuard)
+	nluDataset, err := service.LoadNLUDataset(cfg.NLUEval.Dataset)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load NLU evaluation dataset")
+	}
+	nluEvaluator := service.NewNLUEvaluator(intentParser, llmService, nluDataset)
+	retentionService := service.NewRetentionService(&cfg.Retention, ragService, memoryService, analyticsService)
+	knowledgeService := service.NewKnowledgeService(&cfg.Knowledge, ragService, llmService, promptService, promptGuard)
+	llmJobs, err := service.NewLLMJobQueue(&cfg.LLMJobs, llmService, promptService, promptGuard, llmQuota)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to start LLM job queue")
+	}
+	chatService := service.NewChatService(llmService, promptService, promptGuard, responseGuard, bankingTools, ragService, memoryService, intentParser, analyticsService, cfg.LLM.MaxToolIterations)
+
 	// Initialize controllers
-	orchestratorController := controller.NewOrchestratorController(orchestrator)
+	cancellationTracker := service.NewCancellationTracker()
+	sessionBinder := service.NewSessionBinder(mcpClient)
+	orchestratorController := controller.NewOrchestratorController(orchestrator, chatService, llmService, llmQuota, cancellationTracker, sessionBinder, service.NewBudgetPolicy(&cfg.Budget))
+	promptController := controller.NewPromptController(promptService)
+	llmController := controller.NewLLMController(llmService, ollamaService)
+	ragController := controller.NewRAGController(ragService, service.NewRAGReindexer(&cfg.RAG, ragService, ollamaService))
+	knowledgeController := controller.NewKnowledgeController(knowledgeService, cfg.Security.APIKeyHeader)
+	memoryController := controller.NewMemoryController(memoryService)
+	nluController := controller.NewNLUController(nluEvaluator, fewShot)
+	retentionController := controller.NewRetentionController(retentionService, cfg.Retention.Enabled)
+	userDataController := controller.NewUserDataController(ragService, memoryService, decisionStore, retentionService, analyticsService)
+	capabilityController := controller.NewCapabilityController(capabilityService)
+	analyticsController := controller.NewAnalyticsController(analyticsService)
+	transcriptService := service.NewTranscriptService(&cfg.Transcript, analyticsService, sessionBinder, mcpClient)
+	transcriptController := controller.NewTranscriptController(transcriptService, cfg.Security.APIKeyHeader)
+	llmJobController := controller.NewLLMJobController(llmJobs)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter()
+	recovery := middleware.NewRecovery(cfg.Recovery)
+	auditLogger, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatal().Err(err).Str("sink", cfg.Audit.Sink).Msg("Failed to open access log")
+	}
+	accessLog := middleware.NewAccessLog(auditLogger, cfg.Security.APIKeyHeader)
 
 	// Initialize router
-	appRouter := router.NewRouter(orchestratorController, rateLimiter)
+	appRouter := router.NewRouter(orchestratorController, promptController, llmController, ragController, knowledgeController, memoryController, nluController, retentionController, userDataController, capabilityController, analyticsController, transcriptController, llmJobController, rateLimiter, recovery, accessLog, middleware.NewAdminAuth(&cfg.RBAC))
 	r := appRouter.SetupRoutes()
 
 	// Create HTTP server
@@ -79,6 +211,17 @@ func main() {
 		}
 	}()
 
+	retentionCtx, stopRetention := context.WithCancel(context.Background())
+	defer stopRetention()
+	if cfg.Retention.Enabled {
+		go retentionService.Run(retentionCtx)
+	}
+
+	// Re-read the secrets provider for rotated secrets
+	secretsCtx, stopSecrets := context.WithCancel(context.Background())
+	defer stopSecrets()
+	go config.Profile().WatchSecrets(secretsCtx, cfg.Secrets.RefreshInterval)
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -94,6 +237,17 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// After the server, so the last requests it finished are recorded
+	if err := auditLogger.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close access log")
+	}
+
+	// Let in-flight embeddings finish
+	ragService.Stop(shutdownCtx)
+
+	// Jobs still running are queued again on the next start when LLM_JOBS_DIR is set
+	llmJobs.Stop(shutdownCtx)
+
 	log.Info().Msg("AI Skin Orchestrator exited")
 }
 
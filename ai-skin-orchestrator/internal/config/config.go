@@ -1,21 +1,54 @@
 package config
 
 import (
-	"log"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/recovery"
+	"github.com/aibanking/shared/secrets"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the AI Skin Orchestrator
+
 type Config struct {
+	Environment string // dev, staging or prod; see Validate
 	Server      ServerConfig
 	MCPServer   MCPServerConfig
+	Banking     BankingIntegrationsConfig
 	LLM         LLMConfig
+	Ollama      OllamaConfig
+	RAG         RAGConfig
+	Knowledge   KnowledgeConfig
+	Memory      MemoryConfig
+	Retention   RetentionConfig
+	Quota       QuotaConfig
+	LLMJobs     LLMJobsConfig
+	Prompts     PromptConfig
+	NLUEval     NLUEvalConfig
+	FewShot     FewShotConfig
+	Budget      BudgetConfig
 	Context     ContextConfig
+	Response    ResponseConfig
+	Handoff     HandoffConfig
+	Merger      MergerConfig
+	Scam        ScamConfig
+	Errors      ErrorsConfig
+	Summary     SummaryConfig
+	Analytics   AnalyticsConfig
+	Transcript  TranscriptConfig
 	Logging     LoggingConfig
+	Recovery    recovery.Config
 	Security    SecurityConfig
+	RBAC        RBACConfig
+	Secrets     secrets.Config
+	Audit       audit.Config
+	Demo        demo.Config
 }
 
 // ServerConfig holds server-related configuration
@@ -32,17 +65,148 @@ type MCPServerConfig struct {
 	BaseURL string
 	APIKey  string
 	Timeout int
+
+	// Task result polling
+	PollInitialDelay int // Milliseconds before the first poll; doubles up to PollMaxDelay
+	PollMaxDelay     int // Milliseconds
+	ReadDeadline     int // Seconds to wait for balance, statement and beneficiary lookups
+	TransferDeadline int // Seconds to wait for transfers
+	DefaultDeadline  int // Seconds to wait for any other intent
+
+	CoalesceReads bool // Share one task among identical read-only requests in flight
+
+	CapabilitiesCacheTTL int // Seconds the agent registry is cached for /capabilities
+	IntentsCacheTTL      int // Seconds tenants' custom intents are cached for the parser
+	FlagsCacheTTL        int // Seconds intent flags are cached before requests are checked against them
+}
+
+// BankingIntegrationsConfig holds Banking Integrations (Layer 5) connection configuration
+type BankingIntegrationsConfig struct {
+	BaseURL string
+	APIKey  string
+	Timeout int
 }
 
 // LLMConfig holds LLM service configuration
 type LLMConfig struct {
-	Provider    string // "openai", "anthropic", "local"
+	Provider    string // "openai", "ollama", "local" (OpenAI-compatible at BaseURL)
 	APIKey      string
 	Model       string
 	BaseURL     string // For local/self-hosted models
 	Temperature float64
 	MaxTokens   int
 	Enabled     bool
+	MaxToolIterations int // Rounds of tool calls allowed per chat request
+
+	// Per-purpose defaults and override limits
+	IntentModel       string
+	IntentTemperature float64
+	ChatModel         string
+	ChatTemperature   float64
+	AllowedModels     []string // Models callers may select per request or session
+	MaxTokensLimit    int      // Upper bound for max_tokens overrides
+}
+
+// OllamaConfig holds configuration for a local Ollama host
+type OllamaConfig struct {
+	BaseURL      string
+	Timeout      int    // Seconds per chat or status request
+	PullTimeout  int    // Seconds allowed for a model pull
+	KeepAlive    string // How long models stay loaded after a request, e.g. "10m"
+	PullOnStart  bool   // Pull missing models during startup
+	StartupCheck bool   // Fail startup when configured models are unavailable
+	StreamRetries int   // Attempts to resume a stream that drops mid-response
+}
+
+// RAGConfig holds retrieval and embedding pipeline configuration
+type RAGConfig struct {
+	EmbeddingProvider string // "hash" (local, default) or "ollama"
+	EmbeddingModel    string // Ollama embedding model, e.g. nomic-embed-text
+	HashDimensions    int
+	Workers           int // Concurrent embedding workers
+	QueueSize         int // Documents that may wait for embedding before new ones are dropped
+	MaxAttempts       int
+	EmbedTimeout      int // Seconds per embedding call
+	ReindexBatchSize  int // Documents embedded per call when re-indexing to another model
+}
+
+// KnowledgeConfig holds configuration for policy questions answered from the
+// knowledge collection
+type KnowledgeConfig struct {
+	TopK              int     // Documents retrieved per question
+	MinScore          float64 // Documents less similar than this are not used
+	Synthesize        bool    // Have the LLM write the answer by default
+	CacheTTL          int     // Seconds an answer is reused for the same question
+	CacheSize         int     // Answers kept; the oldest go first
+	RequestsPerMinute int     // Questions per API key per minute, 0 for no limit
+}
+
+// MemoryConfig holds long-term user memory configuration
+type MemoryConfig struct {
+	MaxFacts       int // Facts kept per user; the oldest are dropped first
+	ExtractTimeout int // Seconds allowed for one background extraction
+}
+
+// RetentionConfig holds how many days each class of user data is kept (0 keeps it
+// forever) and how often expired data is purged
+type RetentionConfig struct {
+	Enabled           bool // Purge on a schedule; manual purges work regardless
+	IntervalMinutes   int
+	ConversationsDays int
+	TransactionsDays  int
+}
+
+// QuotaConfig holds per-user LLM quota configuration. A zero limit disables it.
+type QuotaConfig struct {
+	RequestsPerMinute int // LLM-backed requests per user per minute
+	TokensPerDay      int // LLM tokens per user per UTC day
+}
+
+// LLMJobsConfig holds configuration for long-running LLM generations run in the
+// background, apart from interactive chat
+type LLMJobsConfig struct {
+	Workers          int    // Jobs generated at once
+	QueueSize        int    // Jobs that may wait; further submissions are refused
+	PerUser          int    // Unfinished jobs a user may have, 0 for no limit
+	Timeout          int    // Seconds one job may run
+	MaxDocuments     int    // Documents per job
+	MaxDocumentChars int    // Characters kept of each document
+	Dir              string // Optional directory jobs are kept in across restarts
+	RetentionHours   int    // Finished jobs are removed after this
+	CallbackSecret   string // Signs completion callbacks when set
+	CallbackAttempts int
+	CallbackTimeout  int  // Seconds per callback attempt
+	CallbackAllowHTTP bool // Allow plain http callback URLs, for local development
+}
+
+// PromptConfig holds prompt template configuration
+type PromptConfig struct {
+	Dir        string // Optional directory of <name>.v<N>.tmpl overrides
+	Persona    string
+	TenantName string
+}
+
+// NLUEvalConfig holds intent parser evaluation configuration
+type NLUEvalConfig struct {
+	Dataset string // Optional labeled dataset file; the embedded dataset is used otherwise
+}
+
+// FewShotConfig holds the few-shot examples added to the intent parsing prompt
+type FewShotConfig struct {
+	Enabled       bool
+	ExamplesFile  string  // Optional JSON file of examples; the embedded examples are used otherwise
+	MaxExamples   int     // Examples added to one prompt, most similar first
+	MinSimilarity float64 // Below this an example is not close enough to the input to help
+}
+
+// BudgetConfig holds how a request's latency budget is kept to. A stage is skipped,
+// and the rule-based path used instead, when less than its minimum is left.
+type BudgetConfig struct {
+	ChannelLatencyMs map[string]int // Budget of every request on a channel, e.g. IVR=2000; a request may ask for less
+	LLMMinMs         int            // Time left below which the intent is parsed by rules
+	RAGMinMs         int            // Time left below which no few-shot examples are retrieved
+	MLMinMs          int            // Time left below which agents score with rules
+	ReserveMs        int            // Time kept back from an LLM call for the agents
 }
 
 // ContextConfig holds context enrichment configuration
@@ -53,6 +217,72 @@ type ContextConfig struct {
 	CacheTTL           int
 }
 
+// ResponseConfig holds response post-processing configuration
+type ResponseConfig struct {
+	Transformers     string // Default pipeline: transformer names, comma-separated, in the order they run
+	TransformersFile string // Optional JSON file of pipelines per intent and channel
+	Currency         string // Currency of amounts that do not name one
+	Timezone         string // Dates are shown in this time zone
+	DateFormat       string // Go time layout dates are shown in
+	MaxSuggestions   int    // Quick replies offered with a response; 0 offers none
+}
+
+// HandoffConfig holds which intents are handed to a native app screen instead of
+// being executed
+type HandoffConfig struct {
+	ScreensFile string // Optional JSON file of screens per intent, tenant and channel
+}
+
+// MergerConfig holds which agent types decide the merged status of each intent
+type MergerConfig struct {
+	PrecedenceFile string // Optional JSON file of authoritative and advisory agent types per intent
+}
+
+// ScamConfig holds detection of social-engineering scams in what users type: signals
+// such as urgency or remote-access apps raise a transfer's risk and add a warning to
+// its confirmation
+type ScamConfig struct {
+	Enabled       bool
+	WindowMinutes int     // How long a session's signals count towards its transfers
+	LargeAmount   float64 // Transfers from this amount count as large
+	WarnScore     float64 // Scam score from which the confirmation carries a warning
+	HighScore     float64 // Scam score from which the transfer is high risk and held for step-up
+}
+
+// ErrorsConfig holds how failures are explained to users: a friendly message per
+// error code, with the technical detail kept for logs and the diagnostics block
+type ErrorsConfig struct {
+	LLMPolish       bool // Have the LLM reword the message for the request; the template is used when it fails
+	PolishTimeoutMs int  // How long rewording may take before the template is used
+	ExposeDetails   bool // Put the raw error in the diagnostics block; for development only
+}
+
+// SummaryConfig holds how statement summaries are narrated. The figures are always
+// computed by the orchestrator; the LLM only words them.
+type SummaryConfig struct {
+	LLMNarrative bool // Have the LLM write the narrative; the template is used when it fails
+	TimeoutMs    int  // How long the narrative may take before the template is used
+	TopPayees    int  // Payees listed in the aggregates, biggest spend first
+}
+
+// AnalyticsConfig holds conversation analytics configuration. Events are purged with
+// conversations, under RETENTION_CONVERSATIONS_DAYS.
+type AnalyticsConfig struct {
+	MaxEvents int // Conversation events kept; the oldest are dropped first
+}
+
+// TranscriptConfig holds the API keys allowed to read session transcripts, by role
+type TranscriptConfig struct {
+	SupportKeys      []string // Read transcripts with account numbers, phones and emails masked
+	InvestigatorKeys []string // Read transcripts unmasked
+}
+
+// RBACConfig grants roles to API keys. Endpoints that need a role are refused to
+// every key when no key has been granted it.
+type RBACConfig struct {
+	Admins map[string]string // API key -> operator ID
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level  string
@@ -70,9 +300,11 @@ var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables and .env file
 func LoadConfig() (*Config, error) {
-	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	// Load .env.<APP_ENV> and .env if they exist
+	settings = profile.New()
+	environment := settings.LoadEnvironment(godotenv.Read, godotenv.Load)
+	if err := settings.LoadSecrets(); err != nil {
+		return nil, err
 	}
 
 	viper.SetDefault("SERVER_PORT", "8081")
@@ -80,24 +312,130 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("MCP_SERVER_URL", "http://localhost:8080")
 	viper.SetDefault("MCP_SERVER_API_KEY", "test-api-key")
 	viper.SetDefault("MCP_SERVER_TIMEOUT", "30")
+	viper.SetDefault("MCP_POLL_INITIAL_DELAY_MS", "200")
+	viper.SetDefault("MCP_POLL_MAX_DELAY_MS", "2000")
+	viper.SetDefault("MCP_DEADLINE_READ", "10")
+	viper.SetDefault("MCP_DEADLINE_TRANSFER", "30")
+	viper.SetDefault("MCP_DEADLINE_DEFAULT", "20")
+	viper.SetDefault("BANKING_INTEGRATIONS_URL", "http://localhost:7000")
+	viper.SetDefault("BANKING_INTEGRATIONS_API_KEY", "test-api-key")
 	viper.SetDefault("LLM_PROVIDER", "openai")
 	viper.SetDefault("LLM_MODEL", "gpt-3.5-turbo")
 	viper.SetDefault("LLM_TEMPERATURE", "0.7")
 	viper.SetDefault("LLM_MAX_TOKENS", "1000")
 	viper.SetDefault("LLM_ENABLED", "true")
+	viper.SetDefault("LLM_MAX_TOOL_ITERATIONS", "5")
+	viper.SetDefault("LLM_INTENT_TEMPERATURE", "0")
+	viper.SetDefault("LLM_ALLOWED_MODELS", "")
+	viper.SetDefault("LLM_MAX_TOKENS_LIMIT", "4000")
+	viper.SetDefault("OLLAMA_BASE_URL", "http://localhost:11434")
+	viper.SetDefault("OLLAMA_TIMEOUT", "120")
+	viper.SetDefault("OLLAMA_PULL_TIMEOUT", "1800")
+	viper.SetDefault("OLLAMA_KEEP_ALIVE", "")
+	viper.SetDefault("OLLAMA_PULL_ON_START", "false")
+	viper.SetDefault("OLLAMA_STARTUP_CHECK", "false")
+	viper.SetDefault("OLLAMA_STREAM_RETRIES", "2")
+	viper.SetDefault("RAG_EMBEDDING_PROVIDER", "hash")
+	viper.SetDefault("RAG_EMBEDDING_MODEL", "nomic-embed-text")
+	viper.SetDefault("RAG_HASH_DIMENSIONS", "512")
+	viper.SetDefault("RAG_EMBED_WORKERS", "4")
+	viper.SetDefault("RAG_EMBED_QUEUE_SIZE", "1000")
+	viper.SetDefault("RAG_EMBED_MAX_ATTEMPTS", "3")
+	viper.SetDefault("RAG_EMBED_TIMEOUT", "30")
+	viper.SetDefault("RAG_REINDEX_BATCH_SIZE", "32")
+	viper.SetDefault("KNOWLEDGE_TOP_K", "3")
+	viper.SetDefault("KNOWLEDGE_MIN_SCORE", "0.3")
+	viper.SetDefault("KNOWLEDGE_SYNTHESIZE", "true")
+	viper.SetDefault("KNOWLEDGE_CACHE_TTL", "300")
+	viper.SetDefault("KNOWLEDGE_CACHE_SIZE", "500")
+	viper.SetDefault("KNOWLEDGE_RATE_LIMIT_PER_MINUTE", "60")
+	viper.SetDefault("MEMORY_MAX_FACTS", "50")
+	viper.SetDefault("MEMORY_EXTRACT_TIMEOUT", "60")
+	viper.SetDefault("RETENTION_ENABLED", "true")
+	viper.SetDefault("RETENTION_PURGE_INTERVAL_MINUTES", "60")
+	viper.SetDefault("RETENTION_CONVERSATIONS_DAYS", "90")
+	viper.SetDefault("RETENTION_TRANSACTIONS_DAYS", "365")
+	viper.SetDefault("LLM_QUOTA_REQUESTS_PER_MINUTE", "20")
+	viper.SetDefault("LLM_QUOTA_TOKENS_PER_DAY", "200000")
+	viper.SetDefault("LLM_JOBS_WORKERS", "2")
+	viper.SetDefault("LLM_JOBS_QUEUE_SIZE", "100")
+	viper.SetDefault("LLM_JOBS_PER_USER", "5")
+	viper.SetDefault("LLM_JOBS_TIMEOUT", "600")
+	viper.SetDefault("LLM_JOBS_MAX_DOCUMENTS", "10")
+	viper.SetDefault("LLM_JOBS_MAX_DOCUMENT_CHARS", "50000")
+	viper.SetDefault("LLM_JOBS_DIR", "")
+	viper.SetDefault("LLM_JOBS_RETENTION_HOURS", "72")
+	viper.SetDefault("LLM_JOBS_CALLBACK_SECRET", "")
+	viper.SetDefault("LLM_JOBS_CALLBACK_ATTEMPTS", "5")
+	viper.SetDefault("LLM_JOBS_CALLBACK_TIMEOUT", "10")
+	viper.SetDefault("LLM_JOBS_CALLBACK_ALLOW_HTTP", "false")
+	viper.SetDefault("PROMPT_DIR", "")
+	viper.SetDefault("PROMPT_PERSONA", "Aria")
+	viper.SetDefault("PROMPT_TENANT_NAME", "AI Banking")
+	viper.SetDefault("NLU_EVAL_DATASET", "")
+	viper.SetDefault("FEWSHOT_ENABLED", "true")
+	viper.SetDefault("FEWSHOT_EXAMPLES_FILE", "")
+	viper.SetDefault("FEWSHOT_MAX_EXAMPLES", "3")
+	viper.SetDefault("FEWSHOT_MIN_SIMILARITY", "0.3")
+	viper.SetDefault("BUDGET_CHANNEL_MAX_LATENCY_MS", defaultChannelLatency)
+	viper.SetDefault("BUDGET_LLM_MIN_MS", "3000")
+	viper.SetDefault("BUDGET_RAG_MIN_MS", "4000")
+	viper.SetDefault("BUDGET_ML_MIN_MS", "1000")
+	viper.SetDefault("BUDGET_RESERVE_MS", "1000")
 	viper.SetDefault("CONTEXT_HISTORY_DAYS", "90")
 	viper.SetDefault("CONTEXT_ENABLE_BEHAVIOR", "true")
 	viper.SetDefault("CONTEXT_ENABLE_RISK", "true")
 	viper.SetDefault("CONTEXT_CACHE_TTL", "300")
+	viper.SetDefault("RESPONSE_TRANSFORMERS", "mask_accounts,format_currency,localize_dates")
+	viper.SetDefault("RESPONSE_TRANSFORMERS_FILE", "")
+	viper.SetDefault("RESPONSE_CURRENCY", "INR")
+	viper.SetDefault("RESPONSE_TIMEZONE", "Asia/Kolkata")
+	viper.SetDefault("RESPONSE_DATE_FORMAT", "02 Jan 2006, 03:04 PM")
+	viper.SetDefault("RESPONSE_MAX_SUGGESTIONS", "4")
+	viper.SetDefault("HANDOFF_SCREENS_FILE", "")
+	viper.SetDefault("MERGER_PRECEDENCE_FILE", "")
+	viper.SetDefault("SCAM_DETECTION_ENABLED", "true")
+	viper.SetDefault("SCAM_SIGNAL_WINDOW_MINUTES", "30")
+	viper.SetDefault("SCAM_LARGE_AMOUNT", "50000")
+	viper.SetDefault("SCAM_WARN_SCORE", "0.4")
+	viper.SetDefault("SCAM_HIGH_SCORE", "0.7")
+	viper.SetDefault("ANALYTICS_MAX_EVENTS", "100000")
+	viper.SetDefault("TRANSCRIPT_SUPPORT_API_KEYS", "")
+	viper.SetDefault("TRANSCRIPT_INVESTIGATOR_API_KEYS", "")
 	viper.SetDefault("LOGGING_LEVEL", "info")
 	viper.SetDefault("LOGGING_FORMAT", "json")
+	viper.SetDefault("ERROR_LLM_POLISH", "false")
+	viper.SetDefault("ERROR_POLISH_TIMEOUT_MS", "1500")
+	viper.SetDefault("ERROR_EXPOSE_DETAILS", "false")
+	viper.SetDefault("SUMMARY_LLM_NARRATIVE", "true")
+	viper.SetDefault("SUMMARY_TIMEOUT_MS", "3000")
+	viper.SetDefault("SUMMARY_TOP_PAYEES", "3")
+	viper.SetDefault("RECOVERY_EXPOSE_DETAILS", "false")
+	viper.SetDefault("RECOVERY_STACK_LOG_INTERVAL", "300")
+	viper.SetDefault("RECOVERY_KEEP_FINGERPRINTS", "100")
 	viper.SetDefault("SECURITY_API_KEY_HEADER", "X-API-Key")
 	viper.SetDefault("SECURITY_RATE_LIMIT_RPS", "100")
+	viper.SetDefault("SECRETS_PROVIDER", "env")
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "300")
+	viper.SetDefault("AUDIT_SINK", "file")
+	viper.SetDefault("AUDIT_DIR", "audit")
+	viper.SetDefault("AUDIT_RETENTION_DAYS", "365")
+	viper.SetDefault("AUDIT_REDIS_ADDR", "localhost:6379")
+	viper.SetDefault("DEMO_MODE", "false")
+	viper.SetDefault("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios")
 
 	// Bind environment variables
 	viper.AutomaticEnv()
 
+	llmModel := getEnv("LLM_MODEL", "gpt-3.5-turbo")
+	llmTemperature := getEnvFloat("LLM_TEMPERATURE", 0.7)
+
 	AppConfig = &Config{
+		Environment: environment,
+		Secrets: secrets.Config{
+			Provider:        settings.SecretsProvider(),
+			RefreshInterval: getEnvInt("SECRETS_REFRESH_INTERVAL", 300),
+		},
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "8081"),
 			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
@@ -109,15 +447,115 @@ func LoadConfig() (*Config, error) {
 			BaseURL: getEnv("MCP_SERVER_URL", "http://localhost:8080"),
 			APIKey:  getEnv("MCP_SERVER_API_KEY", "test-api-key"),
 			Timeout: 30,
+
+			PollInitialDelay: getEnvInt("MCP_POLL_INITIAL_DELAY_MS", 200),
+			PollMaxDelay:     getEnvInt("MCP_POLL_MAX_DELAY_MS", 2000),
+			ReadDeadline:     getEnvInt("MCP_DEADLINE_READ", 10),
+			TransferDeadline: getEnvInt("MCP_DEADLINE_TRANSFER", 30),
+			DefaultDeadline:  getEnvInt("MCP_DEADLINE_DEFAULT", 20),
+
+			CoalesceReads: getEnv("MCP_COALESCE_READS", "true") == "true",
+
+			CapabilitiesCacheTTL: getEnvInt("CAPABILITIES_CACHE_TTL", 15),
+			IntentsCacheTTL:      getEnvInt("CUSTOM_INTENTS_CACHE_TTL", 30),
+			FlagsCacheTTL:        getEnvInt("INTENT_FLAGS_CACHE_TTL", 10),
+		},
+		Banking: BankingIntegrationsConfig{
+			BaseURL: getEnv("BANKING_INTEGRATIONS_URL", "http://localhost:7000"),
+			APIKey:  getEnv("BANKING_INTEGRATIONS_API_KEY", "test-api-key"),
+			Timeout: 5,
 		},
 		LLM: LLMConfig{
 			Provider:    getEnv("LLM_PROVIDER", "openai"),
 			APIKey:      getEnv("LLM_API_KEY", ""),
-			Model:       getEnv("LLM_MODEL", "gpt-3.5-turbo"),
+			Model:       llmModel,
 			BaseURL:     getEnv("LLM_BASE_URL", ""),
-			Temperature: 0.7,
-			MaxTokens:   1000,
+			Temperature: llmTemperature,
+			MaxTokens:   getEnvInt("LLM_MAX_TOKENS", 1000),
 			Enabled:     getEnv("LLM_ENABLED", "true") == "true",
+			MaxToolIterations: getEnvInt("LLM_MAX_TOOL_ITERATIONS", 5),
+			IntentModel:       getEnv("LLM_INTENT_MODEL", llmModel),
+			IntentTemperature: getEnvFloat("LLM_INTENT_TEMPERATURE", 0),
+			ChatModel:         getEnv("LLM_CHAT_MODEL", llmModel),
+			ChatTemperature:   getEnvFloat("LLM_CHAT_TEMPERATURE", llmTemperature),
+			AllowedModels:     getEnvList("LLM_ALLOWED_MODELS"),
+			MaxTokensLimit:    getEnvInt("LLM_MAX_TOKENS_LIMIT", 4000),
+		},
+		Ollama: OllamaConfig{
+			BaseURL:      getEnv("OLLAMA_BASE_URL", "http://localhost:11434"),
+			Timeout:      getEnvInt("OLLAMA_TIMEOUT", 120),
+			PullTimeout:  getEnvInt("OLLAMA_PULL_TIMEOUT", 1800),
+			KeepAlive:    getEnv("OLLAMA_KEEP_ALIVE", ""),
+			PullOnStart:  getEnv("OLLAMA_PULL_ON_START", "false") == "true",
+			StartupCheck: getEnv("OLLAMA_STARTUP_CHECK", "false") == "true",
+			StreamRetries: getEnvInt("OLLAMA_STREAM_RETRIES", 2),
+		},
+		RAG: RAGConfig{
+			EmbeddingProvider: getEnv("RAG_EMBEDDING_PROVIDER", "hash"),
+			EmbeddingModel:    getEnv("RAG_EMBEDDING_MODEL", "nomic-embed-text"),
+			HashDimensions:    getEnvInt("RAG_HASH_DIMENSIONS", 512),
+			Workers:           getEnvInt("RAG_EMBED_WORKERS", 4),
+			QueueSize:         getEnvInt("RAG_EMBED_QUEUE_SIZE", 1000),
+			MaxAttempts:       getEnvInt("RAG_EMBED_MAX_ATTEMPTS", 3),
+			EmbedTimeout:      getEnvInt("RAG_EMBED_TIMEOUT", 30),
+			ReindexBatchSize:  getEnvInt("RAG_REINDEX_BATCH_SIZE", 32),
+		},
+		Knowledge: KnowledgeConfig{
+			TopK:              getEnvInt("KNOWLEDGE_TOP_K", 3),
+			MinScore:          getEnvFloat("KNOWLEDGE_MIN_SCORE", 0.3),
+			Synthesize:        getEnv("KNOWLEDGE_SYNTHESIZE", "true") == "true",
+			CacheTTL:          getEnvInt("KNOWLEDGE_CACHE_TTL", 300),
+			CacheSize:         getEnvInt("KNOWLEDGE_CACHE_SIZE", 500),
+			RequestsPerMinute: getEnvInt("KNOWLEDGE_RATE_LIMIT_PER_MINUTE", 60),
+		},
+		Memory: MemoryConfig{
+			MaxFacts:       getEnvInt("MEMORY_MAX_FACTS", 50),
+			ExtractTimeout: getEnvInt("MEMORY_EXTRACT_TIMEOUT", 60),
+		},
+		Retention: RetentionConfig{
+			Enabled:           getEnv("RETENTION_ENABLED", "true") == "true",
+			IntervalMinutes:   getEnvInt("RETENTION_PURGE_INTERVAL_MINUTES", 60),
+			ConversationsDays: getEnvInt("RETENTION_CONVERSATIONS_DAYS", 90),
+			TransactionsDays:  getEnvInt("RETENTION_TRANSACTIONS_DAYS", 365),
+		},
+		Quota: QuotaConfig{
+			RequestsPerMinute: getEnvInt("LLM_QUOTA_REQUESTS_PER_MINUTE", 20),
+			TokensPerDay:      getEnvInt("LLM_QUOTA_TOKENS_PER_DAY", 200000),
+		},
+		LLMJobs: LLMJobsConfig{
+			Workers:           getEnvInt("LLM_JOBS_WORKERS", 2),
+			QueueSize:         getEnvInt("LLM_JOBS_QUEUE_SIZE", 100),
+			PerUser:           getEnvInt("LLM_JOBS_PER_USER", 5),
+			Timeout:           getEnvInt("LLM_JOBS_TIMEOUT", 600),
+			MaxDocuments:      getEnvInt("LLM_JOBS_MAX_DOCUMENTS", 10),
+			MaxDocumentChars:  getEnvInt("LLM_JOBS_MAX_DOCUMENT_CHARS", 50000),
+			Dir:               getEnv("LLM_JOBS_DIR", ""),
+			RetentionHours:    getEnvInt("LLM_JOBS_RETENTION_HOURS", 72),
+			CallbackSecret:    getEnv("LLM_JOBS_CALLBACK_SECRET", ""),
+			CallbackAttempts:  getEnvInt("LLM_JOBS_CALLBACK_ATTEMPTS", 5),
+			CallbackTimeout:   getEnvInt("LLM_JOBS_CALLBACK_TIMEOUT", 10),
+			CallbackAllowHTTP: getEnv("LLM_JOBS_CALLBACK_ALLOW_HTTP", "false") == "true",
+		},
+		Prompts: PromptConfig{
+			Dir:        getEnv("PROMPT_DIR", ""),
+			Persona:    getEnv("PROMPT_PERSONA", "Aria"),
+			TenantName: getEnv("PROMPT_TENANT_NAME", "AI Banking"),
+		},
+		NLUEval: NLUEvalConfig{
+			Dataset: getEnv("NLU_EVAL_DATASET", ""),
+		},
+		FewShot: FewShotConfig{
+			Enabled:       getEnv("FEWSHOT_ENABLED", "true") == "true",
+			ExamplesFile:  getEnv("FEWSHOT_EXAMPLES_FILE", ""),
+			MaxExamples:   getEnvInt("FEWSHOT_MAX_EXAMPLES", 3),
+			MinSimilarity: getEnvFloat("FEWSHOT_MIN_SIMILARITY", 0.3),
+		},
+		Budget: BudgetConfig{
+			ChannelLatencyMs: getEnvMs("BUDGET_CHANNEL_MAX_LATENCY_MS", defaultChannelLatency),
+			LLMMinMs:         getEnvInt("BUDGET_LLM_MIN_MS", 3000),
+			RAGMinMs:         getEnvInt("BUDGET_RAG_MIN_MS", 4000),
+			MLMinMs:          getEnvInt("BUDGET_ML_MIN_MS", 1000),
+			ReserveMs:        getEnvInt("BUDGET_RESERVE_MS", 1000),
 		},
 		Context: ContextConfig{
 			HistoryLookbackDays:    90,
@@ -125,24 +563,121 @@ func LoadConfig() (*Config, error) {
 			EnableRiskScoring:      true,
 			CacheTTL:             300,
 		},
+		Response: ResponseConfig{
+			Transformers:     getEnv("RESPONSE_TRANSFORMERS", "mask_accounts,format_currency,localize_dates"),
+			TransformersFile: getEnv("RESPONSE_TRANSFORMERS_FILE", ""),
+			Currency:         getEnv("RESPONSE_CURRENCY", "INR"),
+			Timezone:         getEnv("RESPONSE_TIMEZONE", "Asia/Kolkata"),
+			DateFormat:       getEnv("RESPONSE_DATE_FORMAT", "02 Jan 2006, 03:04 PM"),
+			MaxSuggestions:   getEnvInt("RESPONSE_MAX_SUGGESTIONS", 4),
+		},
+		Handoff: HandoffConfig{
+			ScreensFile: getEnv("HANDOFF_SCREENS_FILE", ""),
+		},
+		Merger: MergerConfig{
+			PrecedenceFile: getEnv("MERGER_PRECEDENCE_FILE", ""),
+		},
+		Scam: ScamConfig{
+			Enabled:       getEnv("SCAM_DETECTION_ENABLED", "true") == "true",
+			WindowMinutes: getEnvInt("SCAM_SIGNAL_WINDOW_MINUTES", 30),
+			LargeAmount:   getEnvFloat("SCAM_LARGE_AMOUNT", 50000),
+			WarnScore:     getEnvFloat("SCAM_WARN_SCORE", 0.4),
+			HighScore:     getEnvFloat("SCAM_HIGH_SCORE", 0.7),
+		},
+		Errors: ErrorsConfig{
+			LLMPolish:       getEnv("ERROR_LLM_POLISH", "false") == "true",
+			PolishTimeoutMs: getEnvInt("ERROR_POLISH_TIMEOUT_MS", 1500),
+			ExposeDetails:   getEnv("ERROR_EXPOSE_DETAILS", "false") == "true",
+		},
+		Summary: SummaryConfig{
+			LLMNarrative: getEnv("SUMMARY_LLM_NARRATIVE", "true") == "true",
+			TimeoutMs:    getEnvInt("SUMMARY_TIMEOUT_MS", 3000),
+			TopPayees:    getEnvInt("SUMMARY_TOP_PAYEES", 3),
+		},
+		Analytics: AnalyticsConfig{
+			MaxEvents: getEnvInt("ANALYTICS_MAX_EVENTS", 100000),
+		},
+		Transcript: TranscriptConfig{
+			SupportKeys:      getEnvList("TRANSCRIPT_SUPPORT_API_KEYS"),
+			InvestigatorKeys: getEnvList("TRANSCRIPT_INVESTIGATOR_API_KEYS"),
+		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOGGING_LEVEL", "info"),
 			Format: getEnv("LOGGING_FORMAT", "json"),
 		},
+		Recovery: recovery.Config{
+			ExposeDetails:    getEnv("RECOVERY_EXPOSE_DETAILS", "false") == "true",
+			StackLogInterval: getEnvInt("RECOVERY_STACK_LOG_INTERVAL", 300),
+			KeepFingerprints: getEnvInt("RECOVERY_KEEP_FINGERPRINTS", 100),
+		},
 		Security: SecurityConfig{
 			APIKeyHeader: getEnv("SECURITY_API_KEY_HEADER", "X-API-Key"),
 			JWTSecret:    getEnv("SECURITY_JWT_SECRET", "your-secret-key"),
 			RateLimitRPS: 100,
 		},
+		RBAC: RBACConfig{
+			Admins: settings.GetGrants("RBAC_ADMIN_OPERATORS"),
+		},
+		Audit: audit.Config{
+			Service:       "ai-skin-orchestrator",
+			Sink:          strings.ToLower(getEnv("AUDIT_SINK", audit.SinkFile)),
+			Dir:           getEnv("AUDIT_DIR", "audit"),
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			RedisAddr:     getEnv("AUDIT_REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("AUDIT_REDIS_PASSWORD", ""),
+			RedisStream:   getEnv("AUDIT_REDIS_STREAM", "audit:access:ai-skin-orchestrator"),
+			SyslogNetwork: getEnv("AUDIT_SYSLOG_NETWORK", ""),
+			SyslogAddr:    getEnv("AUDIT_SYSLOG_ADDR", ""),
+			SigningKey:    getEnv("AUDIT_SIGNING_KEY", ""),
+		},
+		Demo: demo.Config{
+			Enabled:      getEnv("DEMO_MODE", "false") == "true",
+			ScenariosDir: getEnv("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios"),
+		},
 	}
 
 	return AppConfig, nil
 }
 
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	return settings.Get(key, defaultValue)
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	return settings.GetInt(key, defaultValue)
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	return settings.GetFloat(key, defaultValue)
+}
+
+// getEnvList reads a comma-separated list, dropping empty entries
+func getEnvList(key string) []string {
+	settings.Record(key, os.Getenv(key), "", os.Getenv(key) != "", false)
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
 	}
-	return defaultValue
+	return values
 }
 
+// defaultChannelLatency holds phone banking to answers in under two seconds
+const defaultChannelLatency = "IVR=2000"
+
+// getEnvMs reads "NAME=ms,NAME=ms", upper-casing names and skipping malformed entries
+func getEnvMs(key, defaultValue string) map[string]int {
+	value := getEnv(key, defaultValue)
+	values := make(map[string]int)
+	for _, item := range strings.Split(value, ",") {
+		name, ms, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.Atoi(strings.TrimSpace(ms)); err == nil && parsed > 0 {
+			values[strings.ToUpper(strings.TrimSpace(name))] = parsed
+		}
+	}
+	return values
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aibanking/shared/channel"
+	"github.com/aibanking/shared/profile"
+)
+
+// settings holds every value LoadConfig read, by key
+var settings = profile.New()
+
+// Profile returns what LoadConfig read: every setting and where it came from, for
+// config-lint, and the secrets provider, whose rotations the service's clients follow
+func Profile() *profile.Profile {
+	return settings
+}
+
+// Validate checks the configuration against its environment. Errors should stop the
+// service from starting; warnings are logged.
+func (c *Config) Validate() []profile.Problem {
+	v := profile.NewValidator(settings, c.Environment)
+	v.Required("MCP_SERVER_URL", "MCP_SERVER_API_KEY", "BANKING_INTEGRATIONS_URL", "BANKING_INTEGRATIONS_API_KEY")
+	v.URLs("MCP_SERVER_URL", "BANKING_INTEGRATIONS_URL", "LLM_BASE_URL")
+	v.Secrets("MCP_SERVER_API_KEY", "BANKING_INTEGRATIONS_API_KEY")
+
+	usesOllama := c.RAG.EmbeddingProvider == "ollama"
+	if c.LLM.Enabled {
+		if c.LLM.Provider == "ollama" {
+			usesOllama = true
+		} else if c.LLM.APIKey == "" && v.Strict() {
+			v.Add("LLM_API_KEY", v.Severity(profile.SeverityWarning, profile.SeverityError), fmt.Sprintf("not set; the %s LLM is disabled and intents fall back to rules", c.LLM.Provider))
+		}
+	}
+	if usesOllama {
+		v.Required("OLLAMA_BASE_URL")
+		v.URLs("OLLAMA_BASE_URL")
+	}
+	if c.RAG.ReindexBatchSize < 1 {
+		v.Add("RAG_REINDEX_BATCH_SIZE", profile.SeverityError, "must be at least 1")
+	}
+	if _, err := time.LoadLocation(c.Response.Timezone); err != nil {
+		v.Add("RESPONSE_TIMEZONE", profile.SeverityError, fmt.Sprintf("unknown time zone %q", c.Response.Timezone))
+	}
+	if c.Response.MaxSuggestions < 0 || c.Response.MaxSuggestions > 4 {
+		v.Add("RESPONSE_MAX_SUGGESTIONS", profile.SeverityError, "must be between 0 and 4")
+	}
+	if c.LLMJobs.Workers < 1 {
+		v.Add("LLM_JOBS_WORKERS", profile.SeverityError, "must be at least 1")
+	}
+	if c.LLMJobs.CallbackAllowHTTP && v.Strict() {
+		v.Add("LLM_JOBS_CALLBACK_ALLOW_HTTP", v.Severity(profile.SeverityWarning, profile.SeverityError), "job callbacks may be sent over plain http")
+	}
+	if c.LLMJobs.CallbackSecret == "" && v.Strict() {
+		v.Add("LLM_JOBS_CALLBACK_SECRET", profile.SeverityWarning, "not set; job callbacks are not signed")
+	}
+	if c.Scam.Enabled {
+		if c.Scam.WindowMinutes < 1 {
+			v.Add("SCAM_SIGNAL_WINDOW_MINUTES", profile.SeverityError, "must be at least 1")
+		}
+		if c.Scam.WarnScore <= 0 || c.Scam.WarnScore > c.Scam.HighScore {
+			v.Add("SCAM_WARN_SCORE", profile.SeverityError, "must be above 0 and no more than SCAM_HIGH_SCORE")
+		}
+		if c.Scam.HighScore > 1 {
+			v.Add("SCAM_HIGH_SCORE", profile.SeverityError, "must be no more than 1")
+		}
+	} else if v.Strict() {
+		v.Add("SCAM_DETECTION_ENABLED", profile.SeverityWarning, "is off; transfers are not checked for signs of a scam")
+	}
+	if c.Errors.LLMPolish && c.Errors.PolishTimeoutMs < 1 {
+		v.Add("ERROR_POLISH_TIMEOUT_MS", profile.SeverityError, "must be at least 1")
+	}
+	if c.Summary.LLMNarrative && c.Summary.TimeoutMs < 1 {
+		v.Add("SUMMARY_TIMEOUT_MS", profile.SeverityError, "must be at least 1")
+	}
+	if c.Summary.TopPayees < 1 {
+		v.Add("SUMMARY_TOP_PAYEES", profile.SeverityError, "must be at least 1")
+	}
+	if c.Errors.ExposeDetails && v.Strict() {
+		v.Add("ERROR_EXPOSE_DETAILS", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; raw errors, which may name internal hosts and fields, are sent to callers")
+	}
+	if c.Recovery.ExposeDetails && v.Strict() {
+		v.Add("RECOVERY_EXPOSE_DETAILS", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; panic messages, which may hold customer data, are sent to callers")
+	}
+	if c.Recovery.KeepFingerprints < 1 {
+		v.Add("RECOVERY_KEEP_FINGERPRINTS", profile.SeverityError, "must be at least 1")
+	}
+	if c.FewShot.Enabled && c.FewShot.MaxExamples < 1 {
+		v.Add("FEWSHOT_MAX_EXAMPLES", profile.SeverityError, "must be at least 1")
+	}
+	if c.FewShot.MinSimilarity < 0 || c.FewShot.MinSimilarity > 1 {
+		v.Add("FEWSHOT_MIN_SIMILARITY", profile.SeverityError, "must be between 0 and 1")
+	}
+	for name := range c.Budget.ChannelLatencyMs {
+		if !channel.Channel(name).Valid() {
+			v.Add("BUDGET_CHANNEL_MAX_LATENCY_MS", profile.SeverityError, fmt.Sprintf("names %s, which is not a channel; want one of %s", name, strings.Join(channel.Allowed(), ", ")))
+		}
+	}
+	if c.Budget.LLMMinMs < 0 || c.Budget.RAGMinMs < 0 || c.Budget.MLMinMs < 0 {
+		v.Add("BUDGET_LLM_MIN_MS", profile.SeverityError, "BUDGET_LLM_MIN_MS, BUDGET_RAG_MIN_MS and BUDGET_ML_MIN_MS must not be negative")
+	}
+	if c.Budget.ReserveMs < 0 || (c.Budget.LLMMinMs > 0 && c.Budget.ReserveMs >= c.Budget.LLMMinMs) {
+		v.Add("BUDGET_RESERVE_MS", profile.SeverityError, "must be at least 0 and less than BUDGET_LLM_MIN_MS, or an LLM call the budget allows gets no time")
+	}
+	for _, key := range c.Transcript.SupportKeys {
+		for _, investigator := range c.Transcript.InvestigatorKeys {
+			if key == investigator {
+				v.Add("TRANSCRIPT_SUPPORT_API_KEYS", profile.SeverityError, "lists a key that is also in TRANSCRIPT_INVESTIGATOR_API_KEYS; give each key one role")
+			}
+		}
+	}
+	v.Placeholders("SECURITY_JWT_SECRET")
+	v.AuditSink(c.Audit)
+	v.DemoMode(c.Demo)
+	v.SecretSources(c.Secrets)
+	return v.Problems()
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// defaultAnalyticsDays is the range a report covers when no from date is given
+const defaultAnalyticsDays = 30
+
+// AnalyticsController handles conversation analytics
+type AnalyticsController struct {
+	analytics *service.AnalyticsService
+}
+
+// NewAnalyticsController creates a new analytics controller
+func NewAnalyticsController(analytics *service.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{analytics: analytics}
+}
+
+// GetConversations handles GET /admin/analytics/conversations?from=2026-10-01&to=2026-10-15&tenant_id=&user_id=&group_by=tenant.
+// Dates are RFC 3339 timestamps or whole days; to is inclusive of its day.
+func (ac *AnalyticsController) GetConversations(w http.ResponseWriter, r *http.Request) {
+	query, err := analyticsQuery(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid analytics query", err)
+		return
+	}
+	query.UserID = r.URL.Query().Get("user_id")
+	query.TenantID = r.URL.Query().Get("tenant_id")
+	query.GroupBy = r.URL.Query().Get("group_by")
+
+	ac.respond(w, query)
+}
+
+// GetUserConversations handles GET /admin/analytics/users/{userID}?from=&to=
+func (ac *AnalyticsController) GetUserConversations(w http.ResponseWriter, r *http.Request) {
+	query, err := analyticsQuery(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid analytics query", err)
+		return
+	}
+	query.UserID = mux.Vars(r)["userID"]
+
+	ac.respond(w, query)
+}
+
+func (ac *AnalyticsController) respond(w http.ResponseWriter, query model.AnalyticsQuery) {
+	report, err := ac.analytics.Report(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid analytics query", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// analyticsQuery reads the date range of an analytics request, the last 30 days
+// by default
+func analyticsQuery(r *http.Request) (model.AnalyticsQuery, error) {
+	q := model.AnalyticsQuery{To: time.Now()}
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		to, err := parseAnalyticsDate(raw, true)
+		if err != nil {
+			return q, fmt.Errorf("to: %w", err)
+		}
+		q.To = to
+	}
+
+	q.From = q.To.AddDate(0, 0, -defaultAnalyticsDays)
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		from, err := parseAnalyticsDate(raw, false)
+		if err != nil {
+			return q, fmt.Errorf("from: %w", err)
+		}
+		q.From = from
+	}
+
+	if !q.From.Before(q.To) {
+		return q, fmt.Errorf("from must be before to")
+	}
+	return q, nil
+}
+
+// parseAnalyticsDate parses an RFC 3339 timestamp or a day. A day used as the end of
+// a range ends at the following midnight, so the day itself is included.
+func parseAnalyticsDate(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD) or RFC 3339 timestamp", raw)
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
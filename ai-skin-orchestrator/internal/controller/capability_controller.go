@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+)
+
+// CapabilityController reports what the platform can do right now
+type CapabilityController struct {
+	capabilities *service.CapabilityService
+}
+
+// NewCapabilityController creates a new capability controller
+func NewCapabilityController(capabilities *service.CapabilityService) *CapabilityController {
+	return &CapabilityController{
+		capabilities: capabilities,
+	}
+}
+
+// GetCapabilities handles GET /capabilities
+func (cc *CapabilityController) GetCapabilities(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, cc.capabilities.GetCapabilities(r.Context()))
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+)
+
+// maxKnowledgeQuestion caps the length of a policy question
+const maxKnowledgeQuestion = 1000
+
+// KnowledgeController handles policy and FAQ questions from other bank systems
+type KnowledgeController struct {
+	knowledge    *service.KnowledgeService
+	apiKeyHeader string // Callers are rate-limited by the API key they present
+}
+
+// NewKnowledgeController creates a new knowledge controller
+func NewKnowledgeController(knowledge *service.KnowledgeService, apiKeyHeader string) *KnowledgeController {
+	return &KnowledgeController{
+		knowledge:    knowledge,
+		apiKeyHeader: apiKeyHeader,
+	}
+}
+
+// Query handles POST /knowledge/query
+func (kc *KnowledgeController) Query(w http.ResponseWriter, r *http.Request) {
+	if retryAt, ok := kc.knowledge.Allow(r.Header.Get(kc.apiKeyHeader)); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(retryAt).Seconds())+1))
+		respondWithError(w, http.StatusTooManyRequests, "Too many knowledge queries, try again later", nil)
+		return
+	}
+
+	var req model.KnowledgeQuery
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		respondWithError(w, http.StatusBadRequest, "question is required", nil)
+		return
+	}
+	if len(req.Question) > maxKnowledgeQuestion {
+		respondWithError(w, http.StatusBadRequest, "question must be at most 1000 characters", nil)
+		return
+	}
+	if req.TopK < 0 || req.TopK > 10 {
+		respondWithError(w, http.StatusBadRequest, "top_k must be between 1 and 10", nil)
+		return
+	}
+
+	answer, err := kc.knowledge.Query(r.Context(), &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Knowledge query failed", err)
+		return
+	}
+
+	if answer.Cached {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+	respondWithJSON(w, http.StatusOK, answer)
+}
+
+// AddDocument handles POST /admin/knowledge/documents
+func (kc *KnowledgeController) AddDocument(w http.ResponseWriter, r *http.Request) {
+	var req model.KnowledgeDocument
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Content) == "" {
+		respondWithError(w, http.StatusBadRequest, "title and content are required", nil)
+		return
+	}
+
+	doc, err := kc.knowledge.AddDocument(&req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to store document", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, doc)
+}
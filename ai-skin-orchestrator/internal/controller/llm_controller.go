@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// LLMController handles LLM settings requests
+type LLMController struct {
+	llmService *service.LLMService
+	ollama     *service.OllamaService
+}
+
+// NewLLMController creates a new LLM controller
+func NewLLMController(llmService *service.LLMService, ollama *service.OllamaService) *LLMController {
+	return &LLMController{
+		llmService: llmService,
+		ollama:     ollama,
+	}
+}
+
+// GetOptions handles GET /llm/options
+func (lc *LLMController) GetOptions(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, lc.llmService.Options())
+}
+
+// GetCallStats handles GET /admin/llm/errors. window is a duration such as 5m and
+// defaults to the 15 minutes kept.
+func (lc *LLMController) GetCallStats(w http.ResponseWriter, r *http.Request) {
+	var window time.Duration
+	if v := r.URL.Query().Get("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid window", err)
+			return
+		}
+		window = parsed
+	}
+
+	respondWithJSON(w, http.StatusOK, lc.llmService.CallStats(window))
+}
+
+// GetSessionOverrides handles GET /session/{sessionID}/llm
+func (lc *LLMController) GetSessionOverrides(w http.ResponseWriter, r *http.Request) {
+	stored, ok := lc.llmService.GetSessionOverrides(mux.Vars(r)["sessionID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "No LLM overrides set for session", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stored)
+}
+
+// SetSessionOverrides handles PUT /session/{sessionID}/llm
+func (lc *LLMController) SetSessionOverrides(w http.ResponseWriter, r *http.Request) {
+	var overrides model.LLMOverrides
+	if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if overrides.IsEmpty() {
+		respondWithError(w, http.StatusBadRequest, "At least one of model, temperature or max_tokens is required", nil)
+		return
+	}
+
+	stored, err := lc.llmService.SetSessionOverrides(mux.Vars(r)["sessionID"], overrides)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid LLM overrides", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stored)
+}
+
+// ClearSessionOverrides handles DELETE /session/{sessionID}/llm
+func (lc *LLMController) ClearSessionOverrides(w http.ResponseWriter, r *http.Request) {
+	if !lc.llmService.ClearSessionOverrides(mux.Vars(r)["sessionID"]) {
+		respondWithError(w, http.StatusNotFound, "No LLM overrides set for session", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Session LLM overrides cleared",
+	})
+}
+
+// ListModels handles GET /admin/llm/models
+func (lc *LLMController) ListModels(w http.ResponseWriter, r *http.Request) {
+	models, err := lc.ollama.ListModels(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Failed to list Ollama models", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"models": models,
+		"count":  len(models),
+	})
+}
+
+// PullModel handles POST /admin/llm/models/pull
+func (lc *LLMController) PullModel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model == "" {
+		respondWithError(w, http.StatusBadRequest, "Model name is required", err)
+		return
+	}
+
+	if err := lc.ollama.PullModel(r.Context(), req.Model); err != nil {
+		respondWithError(w, http.StatusBadGateway, "Failed to pull model", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Model pulled",
+		"model":   req.Model,
+	})
+}
+
+// GetModelStatus handles GET /admin/llm/models/{name}/status
+func (lc *LLMController) GetModelStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := lc.ollama.ModelStatus(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Failed to get model status", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// GetReadiness handles GET /admin/llm/status, reporting the models the service is configured to use
+func (lc *LLMController) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]*model.OllamaModelStatus, 0)
+	ready := true
+	for _, name := range lc.llmService.Models() {
+		status, err := lc.ollama.ModelStatus(r.Context(), name)
+		if err != nil {
+			respondWithError(w, http.StatusBadGateway, "Failed to reach Ollama", err)
+			return
+		}
+		ready = ready && status.Available
+		statuses = append(statuses, status)
+	}
+
+	code := http.StatusOK
+	if !ready {
+		code = http.StatusServiceUnavailable
+	}
+
+	respondWithJSON(w, code, map[string]interface{}{
+		"ready":  ready,
+		"models": statuses,
+	})
+}
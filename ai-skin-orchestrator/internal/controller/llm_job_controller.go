@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// LLMJobController handles background LLM jobs
+type LLMJobController struct {
+	jobs *service.LLMJobQueue
+}
+
+// NewLLMJobController creates a new LLM job controller
+func NewLLMJobController(jobs *service.LLMJobQueue) *LLMJobController {
+	return &LLMJobController{
+		jobs: jobs,
+	}
+}
+
+// SubmitJob handles POST /llm/jobs. The job is queued and returned at once; poll it or
+// name a callback_url.
+func (jc *LLMJobController) SubmitJob(w http.ResponseWriter, r *http.Request) {
+	var req model.LLMJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	job, err := jc.jobs.Submit(&req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLLMJob), errors.Is(err, service.ErrInvalidLLMOverrides):
+			respondWithError(w, http.StatusBadRequest, "Invalid LLM job", err)
+		case errors.Is(err, service.ErrLLMJobUserLimit), errors.Is(err, service.ErrLLMJobQuota):
+			respondWithError(w, http.StatusTooManyRequests, "LLM job not accepted", err)
+		case errors.Is(err, service.ErrLLMJobQueueFull), errors.Is(err, service.ErrLLMDisabled):
+			w.Header().Set("Retry-After", "60")
+			respondWithError(w, http.StatusServiceUnavailable, "LLM job not accepted", err)
+		default:
+			respondWithError(w, http.StatusInternalServerError, "LLM job not accepted", err)
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/llm/jobs/"+job.JobID)
+	respondWithJSON(w, http.StatusAccepted, job)
+}
+
+// GetJob handles GET /llm/jobs/{jobID}
+func (jc *LLMJobController) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := jc.jobs.Get(mux.Vars(r)["jobID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "LLM job not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// ListJobs handles GET /llm/jobs?user_id=&status=&limit=50, newest first
+func (jc *LLMJobController) ListJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 50
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a positive integer", err)
+			return
+		}
+		limit = parsed
+	}
+
+	jobs := jc.jobs.List(query.Get("user_id"), strings.ToUpper(query.Get("status")), limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// CancelJob handles DELETE /llm/jobs/{jobID}
+func (jc *LLMJobController) CancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := jc.jobs.Cancel(mux.Vars(r)["jobID"])
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLLMJobNotFound):
+			respondWithError(w, http.StatusNotFound, "LLM job not found", err)
+		case errors.Is(err, service.ErrLLMJobFinished):
+			respondWithError(w, http.StatusConflict, "LLM job not cancelled", err)
+		default:
+			respondWithError(w, http.StatusInternalServerError, "LLM job not cancelled", err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// GetStats handles GET /admin/llm/jobs
+func (jc *LLMJobController) GetStats(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, jc.jobs.Stats())
+}
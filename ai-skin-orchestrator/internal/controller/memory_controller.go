@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// MemoryController handles long-term memory requests
+type MemoryController struct {
+	memoryService *service.MemoryService
+}
+
+// NewMemoryController creates a new memory controller
+func NewMemoryController(memoryService *service.MemoryService) *MemoryController {
+	return &MemoryController{
+		memoryService: memoryService,
+	}
+}
+
+// ListMemory handles GET /users/{userID}/memory
+func (mc *MemoryController) ListMemory(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	facts := mc.memoryService.List(userID)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": mc.memoryService.Settings(userID).Enabled,
+		"facts":   facts,
+		"count":   len(facts),
+	})
+}
+
+// DeleteMemory handles DELETE /users/{userID}/memory/{memoryID}
+func (mc *MemoryController) DeleteMemory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !mc.memoryService.Delete(vars["userID"], vars["memoryID"]) {
+		respondWithError(w, http.StatusNotFound, "Memory not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Memory deleted",
+	})
+}
+
+// ClearMemory handles DELETE /users/{userID}/memory
+func (mc *MemoryController) ClearMemory(w http.ResponseWriter, r *http.Request) {
+	removed := mc.memoryService.Clear(mux.Vars(r)["userID"])
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Memory cleared",
+		"removed": removed,
+	})
+}
+
+// GetSettings handles GET /users/{userID}/memory/settings
+func (mc *MemoryController) GetSettings(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, mc.memoryService.Settings(mux.Vars(r)["userID"]))
+}
+
+// UpdateSettings handles PUT /users/{userID}/memory/settings
+func (mc *MemoryController) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if req.Enabled == nil {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, mc.memoryService.SetEnabled(mux.Vars(r)["userID"], *req.Enabled))
+}
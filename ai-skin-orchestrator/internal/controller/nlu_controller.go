@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// NLUController serves intent parser evaluation reports and curates the few-shot
+// examples the LLM parser is shown
+type NLUController struct {
+	evaluator *service.NLUEvaluator
+	fewShot   *service.FewShotLibrary
+}
+
+// NewNLUController creates a new NLU controller
+func NewNLUController(evaluator *service.NLUEvaluator, fewShot *service.FewShotLibrary) *NLUController {
+	return &NLUController{
+		evaluator: evaluator,
+		fewShot:   fewShot,
+	}
+}
+
+// Evaluate handles GET /admin/nlu/eval?engine=rules|llm
+func (nc *NLUController) Evaluate(w http.ResponseWriter, r *http.Request) {
+	engine := r.URL.Query().Get("engine")
+	if engine == "" {
+		engine = service.NLUEngineRules
+	}
+	if engine != service.NLUEngineRules && engine != service.NLUEngineLLM {
+		respondWithError(w, http.StatusBadRequest, "engine must be rules or llm", nil)
+		return
+	}
+
+	report, err := nc.evaluator.Run(r.Context(), engine)
+	if errors.Is(err, service.ErrLLMDisabled) {
+		respondWithError(w, http.StatusServiceUnavailable, "LLM evaluation requires the LLM to be enabled", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Evaluation failed", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// ListExamples handles GET /admin/nlu/examples?intent=TRANSFER_NEFT
+func (nc *NLUController) ListExamples(w http.ResponseWriter, r *http.Request) {
+	intent := model.IntentType(strings.ToUpper(r.URL.Query().Get("intent")))
+	examples := nc.fewShot.List(intent)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"examples": examples,
+		"count":    len(examples),
+	})
+}
+
+// GetExample handles GET /admin/nlu/examples/{exampleID}
+func (nc *NLUController) GetExample(w http.ResponseWriter, r *http.Request) {
+	example, err := nc.fewShot.Get(mux.Vars(r)["exampleID"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Example not found", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, example)
+}
+
+// AddExample handles POST /admin/nlu/examples
+func (nc *NLUController) AddExample(w http.ResponseWriter, r *http.Request) {
+	var req model.FewShotExampleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	example, err := nc.fewShot.Add(&req)
+	if respondIfExampleRefused(w, err) {
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, example)
+}
+
+// UpdateExample handles PUT /admin/nlu/examples/{exampleID}
+func (nc *NLUController) UpdateExample(w http.ResponseWriter, r *http.Request) {
+	var req model.FewShotExampleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	example, err := nc.fewShot.Update(mux.Vars(r)["exampleID"], &req)
+	if respondIfExampleRefused(w, err) {
+		return
+	}
+	respondWithJSON(w, http.StatusOK, example)
+}
+
+// DeleteExample handles DELETE /admin/nlu/examples/{exampleID}
+func (nc *NLUController) DeleteExample(w http.ResponseWriter, r *http.Request) {
+	if err := nc.fewShot.Delete(mux.Vars(r)["exampleID"]); err != nil {
+		respondWithError(w, http.StatusNotFound, "Example not found", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Example deleted",
+	})
+}
+
+// SelectExamples handles POST /admin/nlu/examples/select, showing which examples an
+// input would be parsed with and how similar they are
+func (nc *NLUController) SelectExamples(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Input string `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Input) == "" {
+		respondWithError(w, http.StatusBadRequest, "input is required", err)
+		return
+	}
+
+	matches := nc.fewShot.Preview(r.Context(), req.Input)
+	if matches == nil {
+		matches = []model.FewShotMatch{}
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"input":    req.Input,
+		"examples": matches,
+	})
+}
+
+// respondIfExampleRefused answers an add or update the library refused and reports
+// whether it did
+func respondIfExampleRefused(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrFewShotNotFound):
+		respondWithError(w, http.StatusNotFound, "Example not found", err)
+	case errors.Is(err, service.ErrFewShotDuplicate):
+		respondWithError(w, http.StatusConflict, "Example already exists", err)
+	case errors.Is(err, service.ErrInvalidFewShot):
+		respondWithError(w, http.StatusBadRequest, "Invalid example", err)
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Failed to save example", err)
+	}
+	return true
+}
@@ -2,22 +2,40 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/aibanking/ai-skin-orchestrator/internal/model"
 	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/aibanking/shared/channel"
+	"github.com/aibanking/shared/tz"
+	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 )
 
 // OrchestratorController handles orchestration requests
 type OrchestratorController struct {
-	orchestrator *service.Orchestrator
+	orchestrator  *service.Orchestrator
+	chatService   *service.ChatService
+	llmService    *service.LLMService
+	quota         *service.LLMQuota
+	cancellations *service.CancellationTracker
+	sessions      *service.SessionBinder
+	budgets       *service.BudgetPolicy
 }
 
 // NewOrchestratorController creates a new orchestrator controller
-func NewOrchestratorController(orchestrator *service.Orchestrator) *OrchestratorController {
+func NewOrchestratorController(orchestrator *service.Orchestrator, chatService *service.ChatService, llmService *service.LLMService, quota *service.LLMQuota, cancellations *service.CancellationTracker, sessions *service.SessionBinder, budgets *service.BudgetPolicy) *OrchestratorController {
 	return &OrchestratorController{
-		orchestrator: orchestrator,
+		orchestrator:  orchestrator,
+		chatService:   chatService,
+		llmService:    llmService,
+		quota:         quota,
+		cancellations: cancellations,
+		sessions:      sessions,
+		budgets:       budgets,
 	}
 }
 
@@ -34,22 +52,424 @@ func (oc *OrchestratorController) ProcessRequest(w http.ResponseWriter, r *http.
 		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
+	if !normalizeUserLocale(w, req.Context) {
+		return
+	}
+
+	// The channel's budget, tightened by the request's own, holds from here on
+	ctx, err := oc.budgets.Begin(r.Context(), req.Channel, req.Budget)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid budget", err)
+		return
+	}
+	r = r.WithContext(ctx)
 
 	// Set default input type if not provided
 	if req.InputType == "" {
 		req.InputType = "natural_language"
 	}
 
+	binding, ok := oc.bindSession(w, r, req.UserID, req.Channel, req.SessionID, req.Context)
+	if !ok {
+		return
+	}
+	req.SessionID = binding.SessionID
+	req.Context = service.ApplySessionContext(req.Context, binding)
+
+	// Layer request LLM overrides over the session's
+	overrides, err := oc.llmService.ResolveOverrides(req.SessionID, req.LLM)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid LLM overrides", err)
+		return
+	}
+	req.LLM = overrides
+
+	// Structured input never reaches the LLM, so it does not count against the quota;
+	// nor does a request whose budget does not allow the LLM
+	if req.InputType != "structured" && oc.llmService.Enabled() && service.BudgetAllowsLLM(r.Context()) {
+		_, allowed := oc.checkQuota(w, req.UserID)
+		req.RulesOnly = !allowed
+	}
+
 	// Process request
 	response, err := oc.orchestrator.ProcessRequest(r.Context(), &req)
+	if oc.abandoned("process", model.CancelStageParse, err) {
+		return
+	}
+	if respondIfBusy(w, err) {
+		return
+	}
+	if err != nil {
+		oc.respondWithExplanation(w, r, &req, err)
+		return
+	}
+
+	response.SessionID = req.SessionID
+	response.Budget = service.BudgetReport(r.Context())
+	oc.sessions.Record(r.Context(), binding, response.Status)
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// VerifyAuth handles POST /auth/challenges/{challengeID}/{method}, answering the
+// step-up challenge a transfer came back with. The method is otp, biometric or token.
+func (oc *OrchestratorController) VerifyAuth(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	switch vars["method"] {
+	case "otp", "biometric", "token":
+	default:
+		respondWithError(w, http.StatusBadRequest, "method must be otp, biometric or token", nil)
+		return
+	}
+
+	var answer map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&answer); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	response, err := oc.orchestrator.VerifyAuth(r.Context(), vars["challengeID"], vars["method"], answer)
+	if oc.abandoned("verify_auth", model.CancelStageSubmit, err) {
+		return
+	}
+	if respondIfBusy(w, err) {
+		return
+	}
+	var refused *service.AuthVerifyError
+	if errors.As(err, &refused) {
+		respondWithJSON(w, refused.StatusCode, refused.Body)
+		return
+	}
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to process request", err)
+		oc.respondWithExplanation(w, r, nil, err)
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, response)
 }
 
+// ConfirmSplit handles POST /splits/{splitID}/confirm, accepting the split a transfer
+// over its rail's limits came back with
+func (oc *OrchestratorController) ConfirmSplit(w http.ResponseWriter, r *http.Request) {
+	response, err := oc.orchestrator.ConfirmSplit(r.Context(), mux.Vars(r)["splitID"])
+	oc.respondSplit(w, r, "confirm_split", response, err)
+}
+
+// DeclineSplit handles POST /splits/{splitID}/decline
+func (oc *OrchestratorController) DeclineSplit(w http.ResponseWriter, r *http.Request) {
+	response, err := oc.orchestrator.DeclineSplit(r.Context(), mux.Vars(r)["splitID"])
+	oc.respondSplit(w, r, "decline_split", response, err)
+}
+
+func (oc *OrchestratorController) respondSplit(w http.ResponseWriter, r *http.Request, operation string, response *model.MergedResponse, err error) {
+	if oc.abandoned(operation, model.CancelStageSubmit, err) {
+		return
+	}
+	if respondIfBusy(w, err) {
+		return
+	}
+	var refused *service.SplitError
+	if errors.As(err, &refused) {
+		respondWithJSON(w, refused.StatusCode, refused.Body)
+		return
+	}
+	if err != nil {
+		oc.respondWithExplanation(w, r, nil, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// Chat handles POST /chat
+func (oc *OrchestratorController) Chat(w http.ResponseWriter, r *http.Request) {
+	req, binding, ok := oc.decodeChatRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var response *model.ChatResponse
+	var err error
+	if quota, allowed := oc.checkQuota(w, req.UserID); allowed {
+		response, err = oc.chatService.Chat(r.Context(), req)
+	} else {
+		response, err = oc.chatService.Degraded(r.Context(), req, quota)
+	}
+	if oc.abandoned("chat", model.CancelStageLLM, err) {
+		return
+	}
+	if respondIfBusy(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrLLMDisabled) {
+		respondWithError(w, http.StatusServiceUnavailable, "Chat requires the LLM to be enabled", err)
+		return
+	}
+	if err != nil {
+		oc.respondWithExplanation(w, r, chatUserRequest(req), err)
+		return
+	}
+
+	response.SessionID = req.SessionID
+	oc.sessions.Record(r.Context(), binding, "COMPLETED")
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// ChatStream handles POST /chat/stream, sending the answer as server-sent events:
+// "delta" events carry text, then a "done" event carries the full chat response
+// (with partial set if the answer was cut off) or an "error" event
+func (oc *OrchestratorController) ChatStream(w http.ResponseWriter, r *http.Request) {
+	req, binding, ok := oc.decodeChatRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if !oc.llmService.Enabled() {
+		respondWithError(w, http.StatusServiceUnavailable, "Chat requires the LLM to be enabled", service.ErrLLMDisabled)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming not supported", nil)
+		return
+	}
+
+	quota, allowed := oc.checkQuota(w, req.UserID)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	var response *model.ChatResponse
+	var err error
+	if allowed {
+		response, err = oc.chatService.ChatStream(r.Context(), req, func(delta string) {
+			writeEvent(w, "delta", map[string]string{"text": delta})
+			flusher.Flush()
+		})
+	} else {
+		// Without the LLM the answer is ready at once and is sent as a single delta
+		response, err = oc.chatService.Degraded(r.Context(), req, quota)
+		if err == nil {
+			writeEvent(w, "delta", map[string]string{"text": response.Answer})
+		}
+	}
+	if oc.abandoned("chat_stream", model.CancelStageLLM, err) {
+		return
+	}
+	if err != nil {
+		explanation, diagnostics := oc.orchestrator.ExplainError(r.Context(), chatUserRequest(req), err)
+		message := explanation.Text()
+		var busy *service.MCPBusyError
+		if errors.As(err, &busy) {
+			message = busy.UserMessage()
+		}
+		writeEvent(w, "error", map[string]interface{}{
+			"error":             message,
+			"error_explanation": explanation,
+			"diagnostics":       diagnostics,
+		})
+	} else {
+		response.SessionID = req.SessionID
+		oc.sessions.Record(r.Context(), binding, "COMPLETED")
+		writeEvent(w, "done", response)
+	}
+	flusher.Flush()
+}
+
+// abandoned counts a request whose client went away before it was answered. There is
+// nobody left to write a response to, so the handler just returns.
+func (oc *OrchestratorController) abandoned(endpoint, stage string, err error) bool {
+	if !oc.cancellations.Record(endpoint, stage, err) {
+		return false
+	}
+	log.Info().Err(err).Str("endpoint", endpoint).Msg("Client abandoned request")
+	return true
+}
+
+// GetCancellations handles GET /admin/requests/cancelled, counting the requests clients
+// abandoned by endpoint and stage, and what became of their MCP tasks
+func (oc *OrchestratorController) GetCancellations(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, oc.cancellations.Stats())
+}
+
+// GetCoalescing handles GET /admin/requests/coalesced, counting the read tasks submitted
+// and the identical requests that shared one in flight
+func (oc *OrchestratorController) GetCoalescing(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, oc.orchestrator.CoalescingStats())
+}
+
+// decodeChatRequest reads and validates a chat request and binds its session, writing
+// the error response on failure
+func (oc *OrchestratorController) decodeChatRequest(w http.ResponseWriter, r *http.Request) (*model.ChatRequest, *model.SessionBinding, bool) {
+	var req model.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return nil, nil, false
+	}
+
+	if req.UserID == "" || req.Channel == "" || req.Message == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
+		return nil, nil, false
+	}
+	if !normalizeChannel(w, &req.Channel) {
+		return nil, nil, false
+	}
+	userLocale := map[string]interface{}{}
+	if req.Timezone != "" {
+		userLocale["timezone"] = req.Timezone
+	}
+	if req.Locale != "" {
+		userLocale["locale"] = req.Locale
+	}
+	if !normalizeUserLocale(w, userLocale) {
+		return nil, nil, false
+	}
+
+	binding, ok := oc.bindSession(w, r, req.UserID, req.Channel, req.SessionID, userLocale)
+	if !ok {
+		return nil, nil, false
+	}
+	req.SessionID = binding.SessionID
+	userLocale = service.ApplySessionContext(userLocale, binding)
+	req.Timezone, _ = userLocale["timezone"].(string)
+	req.Locale, _ = userLocale["locale"].(string)
+
+	overrides, err := oc.llmService.ResolveOverrides(req.SessionID, req.LLM)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid LLM overrides", err)
+		return nil, nil, false
+	}
+	req.LLM = overrides
+
+	return &req, binding, true
+}
+
+// bindSession binds the request's session shared with the MCP server, answering 409
+// for a session of another user and 400 for an ID the MCP server does not accept
+func (oc *OrchestratorController) bindSession(w http.ResponseWriter, r *http.Request, userID, channel, sessionID string, reqContext map[string]interface{}) (*model.SessionBinding, bool) {
+	binding, err := oc.sessions.Bind(r.Context(), userID, channel, sessionID, reqContext)
+	if errors.Is(err, service.ErrSessionConflict) {
+		respondWithError(w, http.StatusConflict, "Session belongs to another user", err)
+		return nil, false
+	}
+	if errors.Is(err, service.ErrInvalidSessionID) {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID", err)
+		return nil, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to bind session", err)
+		return nil, false
+	}
+	return binding, true
+}
+
+// GetSession handles GET /session/{sessionID}, the conversation as the AI Skin and the
+// MCP server share it
+func (oc *OrchestratorController) GetSession(w http.ResponseWriter, r *http.Request) {
+	binding, err := oc.sessions.Get(r.Context(), mux.Vars(r)["sessionID"])
+	if errors.Is(err, service.ErrSessionNotFound) {
+		respondWithError(w, http.StatusNotFound, "Session not found", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Failed to read session", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, binding)
+}
+
+// checkQuota counts an LLM-backed request against the user's quota and reports the
+// usage in X-LLM-Quota-* headers. Limits that are not configured are left out.
+func (oc *OrchestratorController) checkQuota(w http.ResponseWriter, userID string) (model.QuotaStatus, bool) {
+	status, allowed := oc.quota.Allow(userID)
+
+	h := w.Header()
+	if status.RequestsLimit > 0 {
+		h.Set("X-LLM-Quota-Requests-Limit", strconv.Itoa(status.RequestsLimit))
+		h.Set("X-LLM-Quota-Requests-Remaining", strconv.Itoa(status.RequestsRemaining))
+		h.Set("X-LLM-Quota-Requests-Reset", strconv.FormatInt(status.RequestsReset.Unix(), 10))
+	}
+	if status.TokensLimit > 0 {
+		h.Set("X-LLM-Quota-Tokens-Limit", strconv.Itoa(status.TokensLimit))
+		h.Set("X-LLM-Quota-Tokens-Remaining", strconv.Itoa(status.TokensRemaining))
+		h.Set("X-LLM-Quota-Tokens-Reset", strconv.FormatInt(status.TokensReset.Unix(), 10))
+	}
+	if !allowed {
+		h.Set("X-LLM-Quota-Exceeded", status.Exceeded)
+	}
+	return status, allowed
+}
+
+// respondIfBusy answers 503 with Retry-After and a message the customer can act on
+// when the MCP server refused the work because it is saturated
+func respondIfBusy(w http.ResponseWriter, err error) bool {
+	var busy *service.MCPBusyError
+	if !errors.As(err, &busy) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(busy.RetryAfter.Seconds())))
+	respondWithError(w, http.StatusServiceUnavailable, busy.UserMessage(), err)
+	return true
+}
+
+// respondWithExplanation answers a failed request with what the user is told about err
+// and the diagnostics block; the raw error is only logged. req may be nil.
+func (oc *OrchestratorController) respondWithExplanation(w http.ResponseWriter, r *http.Request, req *model.UserRequest, err error) {
+	explanation, diagnostics := oc.orchestrator.ExplainError(r.Context(), req, err)
+	code := explanationStatus(explanation.Code)
+	respondWithJSON(w, code, map[string]interface{}{
+		"error":             explanation.Text(),
+		"code":              code,
+		"error_explanation": explanation,
+		"diagnostics":       diagnostics,
+	})
+}
+
+// explanationStatus is the HTTP status of a request that failed with an error code
+func explanationStatus(errorCode string) int {
+	switch errorCode {
+	case model.ErrorCodeTimeout:
+		return http.StatusGatewayTimeout
+	case model.ErrorCodeUnavailable, model.ErrorCodeAgentUnavailable, model.ErrorCodeInvalidResult:
+		return http.StatusBadGateway
+	case model.ErrorCodeBusy:
+		return http.StatusServiceUnavailable
+	case model.ErrorCodeInvalidRequest:
+		return http.StatusBadRequest
+	case model.ErrorCodeDuplicate:
+		return http.StatusConflict
+	case model.ErrorCodeFeatureDisabled:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// chatUserRequest is a chat request as the user request its failures are explained for
+func chatUserRequest(req *model.ChatRequest) *model.UserRequest {
+	return &model.UserRequest{
+		UserID:    req.UserID,
+		Channel:   req.Channel,
+		Input:     req.Message,
+		SessionID: req.SessionID,
+	}
+}
+
+// writeEvent writes one server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("event", event).Msg("Failed to marshal event")
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
 // HealthCheck handles GET /health
 func (oc *OrchestratorController) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -57,6 +477,46 @@ func (oc *OrchestratorController) HealthCheck(w http.ResponseWriter, r *http.Req
 	w.Write([]byte(`{"status":"healthy","service":"ai-skin-orchestrator"}`))
 }
 
+// normalizeChannel replaces a request's channel with its canonical spelling, answering
+// 400 with the allowed channels when it is not one of them
+func normalizeChannel(w http.ResponseWriter, raw *string) bool {
+	c, err := channel.Parse(*raw)
+	if err != nil {
+		log.Warn().Err(err).Msg("Request with invalid channel")
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid channel",
+			"code":    http.StatusBadRequest,
+			"details": err.Error(),
+			"allowed": channel.Allowed(),
+		})
+		return false
+	}
+	*raw = string(c)
+	return true
+}
+
+// normalizeUserLocale checks the user's time zone and locale in a request context,
+// answering 400 for one that is not recognised, and puts the locale in canonical form
+func normalizeUserLocale(w http.ResponseWriter, reqContext map[string]interface{}) bool {
+	if raw, ok := reqContext["timezone"]; ok {
+		name, _ := raw.(string)
+		if _, err := tz.Load(name); err != nil {
+			respondWithError(w, http.StatusBadRequest, "timezone must be an IANA time zone such as Asia/Kolkata", err)
+			return false
+		}
+	}
+	if raw, ok := reqContext["locale"]; ok {
+		s, _ := raw.(string)
+		locale, err := tz.ParseLocale(s)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "locale must be a language and region such as en-IN", err)
+			return false
+		}
+		reqContext["locale"] = string(locale)
+	}
+	return true
+}
+
 // respondWithJSON sends a JSON response
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
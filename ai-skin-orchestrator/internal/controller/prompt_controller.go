@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PromptController handles prompt template admin requests
+type PromptController struct {
+	prompts *service.PromptService
+}
+
+// NewPromptController creates a new prompt controller
+func NewPromptController(prompts *service.PromptService) *PromptController {
+	return &PromptController{
+		prompts: prompts,
+	}
+}
+
+// ListPrompts handles GET /admin/prompts
+func (pc *PromptController) ListPrompts(w http.ResponseWriter, r *http.Request) {
+	prompts := pc.prompts.List()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"prompts": prompts,
+		"count":   len(prompts),
+	})
+}
+
+// GetPrompt handles GET /admin/prompts/{name}?version=N
+func (pc *PromptController) GetPrompt(w http.ResponseWriter, r *http.Request) {
+	version, err := versionParam(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid version", err)
+		return
+	}
+
+	prompt, err := pc.prompts.Get(mux.Vars(r)["name"], version)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Prompt not found", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prompt)
+}
+
+// AddVersion handles POST /admin/prompts/{name}/versions
+func (pc *PromptController) AddVersion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Body == "" {
+		respondWithError(w, http.StatusBadRequest, "Template body is required", err)
+		return
+	}
+
+	prompt, err := pc.prompts.AddVersion(mux.Vars(r)["name"], req.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid prompt template", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, prompt)
+}
+
+// PreviewPrompt handles POST /admin/prompts/{name}/preview
+func (pc *PromptController) PreviewPrompt(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Version int              `json:"version"`
+		Vars    model.PromptVars `json:"vars"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	rendered, err := pc.prompts.RenderVersion(mux.Vars(r)["name"], req.Version, req.Vars)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to render prompt", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rendered)
+}
+
+// ActivatePrompt handles POST /admin/prompts/{name}/activate
+func (pc *PromptController) ActivatePrompt(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Version <= 0 {
+		respondWithError(w, http.StatusBadRequest, "A positive version is required", err)
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := pc.prompts.Activate(name, req.Version); err != nil {
+		respondWithError(w, http.StatusNotFound, "Failed to activate prompt", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":        "Prompt version activated",
+		"name":           name,
+		"active_version": req.Version,
+	})
+}
+
+// versionParam reads the optional ?version= query parameter
+func versionParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("version")
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// RAGController handles retrieval requests
+type RAGController struct {
+	ragService *service.RAGService
+	reindexer  *service.RAGReindexer
+}
+
+// NewRAGController creates a new RAG controller
+func NewRAGController(ragService *service.RAGService, reindexer *service.RAGReindexer) *RAGController {
+	return &RAGController{
+		ragService: ragService,
+		reindexer:  reindexer,
+	}
+}
+
+// Search handles POST /rag/search
+func (rc *RAGController) Search(w http.ResponseWriter, r *http.Request) {
+	var req model.SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if req.Query == "" || (req.UserID == "" && req.Collection != model.CollectionKnowledge) {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
+		return
+	}
+
+	results, err := rc.ragService.Search(r.Context(), &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Search failed", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	})
+}
+
+// GetDocument handles GET /rag/documents/{documentID}
+func (rc *RAGController) GetDocument(w http.ResponseWriter, r *http.Request) {
+	doc, ok := rc.ragService.GetDocument(mux.Vars(r)["documentID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Document not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, doc)
+}
+
+// GetStats handles GET /admin/rag/stats
+func (rc *RAGController) GetStats(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, rc.ragService.Stats())
+}
+
+// StartReindex handles POST /admin/rag/reindex
+func (rc *RAGController) StartReindex(w http.ResponseWriter, r *http.Request) {
+	var req model.ReindexRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	job, err := rc.reindexer.Start(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrReindexActive) {
+			respondWithError(w, http.StatusConflict, "A re-index is already under way", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Invalid re-index request", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, job)
+}
+
+// GetReindex handles GET /admin/rag/reindex
+func (rc *RAGController) GetReindex(w http.ResponseWriter, r *http.Request) {
+	job, err := rc.reindexer.Job()
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No re-index job", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// PauseReindex handles POST /admin/rag/reindex/pause
+func (rc *RAGController) PauseReindex(w http.ResponseWriter, r *http.Request) {
+	rc.respondWithReindex(w, rc.reindexer.Pause)
+}
+
+// ResumeReindex handles POST /admin/rag/reindex/resume
+func (rc *RAGController) ResumeReindex(w http.ResponseWriter, r *http.Request) {
+	rc.respondWithReindex(w, rc.reindexer.Resume)
+}
+
+// CancelReindex handles DELETE /admin/rag/reindex
+func (rc *RAGController) CancelReindex(w http.ResponseWriter, r *http.Request) {
+	rc.respondWithReindex(w, rc.reindexer.Cancel)
+}
+
+// respondWithReindex applies a change to the re-index job and answers with the job
+func (rc *RAGController) respondWithReindex(w http.ResponseWriter, change func() (*model.ReindexJob, error)) {
+	job, err := change()
+	if err != nil {
+		if errors.Is(err, service.ErrReindexNotFound) {
+			respondWithError(w, http.StatusNotFound, "No re-index job", err)
+			return
+		}
+		respondWithError(w, http.StatusConflict, "Re-index job cannot be changed", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// RetentionController handles retention policies, purges and legal holds
+type RetentionController struct {
+	retentionService *service.RetentionService
+	enabled          bool
+}
+
+// NewRetentionController creates a new retention controller
+func NewRetentionController(retentionService *service.RetentionService, enabled bool) *RetentionController {
+	return &RetentionController{
+		retentionService: retentionService,
+		enabled:          enabled,
+	}
+}
+
+// GetPolicies handles GET /admin/retention/policies
+func (rc *RetentionController) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"scheduled": rc.enabled,
+		"policies":  rc.retentionService.Policies(),
+	})
+}
+
+// Purge handles POST /admin/retention/purge. With dry_run=true it reports what would
+// be purged without removing anything.
+func (rc *RetentionController) Purge(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	respondWithJSON(w, http.StatusOK, rc.retentionService.Purge(service.PurgeManual, dryRun))
+}
+
+// GetReports handles GET /admin/retention/reports
+func (rc *RetentionController) GetReports(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+
+	reports := rc.retentionService.Reports(limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// GetHolds handles GET /admin/retention/holds
+func (rc *RetentionController) GetHolds(w http.ResponseWriter, r *http.Request) {
+	holds := rc.retentionService.Holds()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"holds": holds,
+		"count": len(holds),
+	})
+}
+
+// PlaceHold handles PUT /admin/retention/holds/{userID}
+func (rc *RetentionController) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var req model.LegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	hold, err := rc.retentionService.PlaceHold(mux.Vars(r)["userID"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid legal hold", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, hold)
+}
+
+// ReleaseHold handles DELETE /admin/retention/holds/{userID}
+func (rc *RetentionController) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	if !rc.retentionService.ReleaseHold(userID) {
+		respondWithError(w, http.StatusNotFound, "Legal hold not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Legal hold released",
+		"user_id": userID,
+	})
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// TranscriptController serves session transcripts to support staff
+type TranscriptController struct {
+	transcripts *service.TranscriptService
+	keyHeader   string // Header carrying the caller's API key, whose role decides the masking
+}
+
+// NewTranscriptController creates a new transcript controller
+func NewTranscriptController(transcripts *service.TranscriptService, keyHeader string) *TranscriptController {
+	return &TranscriptController{transcripts: transcripts, keyHeader: keyHeader}
+}
+
+// GetTranscript handles GET /sessions/{sessionID}/transcript. Support keys get PII
+// masked, investigator keys see it unmasked; other keys are refused.
+func (tc *TranscriptController) GetTranscript(w http.ResponseWriter, r *http.Request) {
+	role := tc.transcripts.Role(r.Header.Get(tc.keyHeader))
+	if role == "" {
+		respondWithError(w, http.StatusForbidden, "Transcripts need a support or investigator API key", nil)
+		return
+	}
+
+	transcript, err := tc.transcripts.Transcript(r.Context(), mux.Vars(r)["sessionID"], role)
+	if errors.Is(err, service.ErrTranscriptNotFound) || errors.Is(err, service.ErrInvalidSessionID) {
+		respondWithError(w, http.StatusNotFound, "Session not found", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Failed to read session", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, transcript)
+}
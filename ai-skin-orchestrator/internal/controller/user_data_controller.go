@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/ai-skin-orchestrator/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// UserDataController exports and erases everything the AI Skin holds about a user,
+// for data-subject requests
+type UserDataController struct {
+	ragService       *service.RAGService
+	memoryService    *service.MemoryService
+	decisionStore    *service.DecisionStore
+	retentionService *service.RetentionService
+	analytics        *service.AnalyticsService
+}
+
+// NewUserDataController creates a new user data controller
+func NewUserDataController(ragService *service.RAGService, memoryService *service.MemoryService, decisionStore *service.DecisionStore, retentionService *service.RetentionService, analytics *service.AnalyticsService) *UserDataController {
+	return &UserDataController{
+		ragService:       ragService,
+		memoryService:    memoryService,
+		decisionStore:    decisionStore,
+		retentionService: retentionService,
+		analytics:        analytics,
+	}
+}
+
+// ExportUserData handles GET /admin/users/{userID}/data
+func (uc *UserDataController) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	export := &model.UserDataExport{
+		UserID:         userID,
+		ExportedAt:     time.Now(),
+		Documents:      uc.ragService.UserDocuments(userID),
+		MemoryFacts:    uc.memoryService.List(userID),
+		MemorySettings: uc.memoryService.Settings(userID),
+		Decisions:      uc.decisionStore.UserDecisions(userID),
+		Events:         uc.analytics.UserEvents(userID),
+	}
+	respondWithJSON(w, http.StatusOK, export)
+}
+
+// EraseUserData handles DELETE /admin/users/{userID}/data. Data under a legal hold
+// is not erased.
+func (uc *UserDataController) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	if uc.retentionService.IsHeld(userID) {
+		respondWithError(w, http.StatusConflict, "User data is under a legal hold", fmt.Errorf("legal hold on %s", userID))
+		return
+	}
+
+	erasure := &model.UserDataErasure{
+		UserID:      userID,
+		ErasedAt:    time.Now(),
+		Documents:   uc.ragService.DeleteUser(userID),
+		MemoryFacts: uc.memoryService.Forget(userID),
+		Decisions:   uc.decisionStore.ForgetUser(userID),
+		Events:      uc.analytics.ForgetUser(userID),
+	}
+	log.Info().
+		Str("user_id", userID).
+		Int("documents", erasure.Documents).
+		Int("memory_facts", erasure.MemoryFacts).
+		Int("decisions", erasure.Decisions).
+		Int("conversation_events", erasure.Events).
+		Msg("User data erased")
+
+	respondWithJSON(w, http.StatusOK, erasure)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aibanking/shared/audit"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// AccessLog records every request but health checks in the audit log
+type AccessLog = audit.AccessLog
+
+// NewAccessLog creates the access-logging middleware, naming each request's route by
+// its mux template
+func NewAccessLog(logger *audit.Logger, apiKeyHeader string) *AccessLog {
+	return audit.NewAccessLog(logger, apiKeyHeader, accessRoute, logAccessError)
+}
+
+// accessRoute returns the template of the route a request matched, e.g.
+// /api/v1/tasks/{taskID}
+func accessRoute(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		template, _ := route.GetPathTemplate()
+		return template
+	}
+	return ""
+}
+
+// logAccessError logs an access record that could not be written
+func logAccessError(rec *audit.Record, err error) {
+	log.Error().Err(err).Str("path", rec.Path).Msg("Failed to write access record")
+}
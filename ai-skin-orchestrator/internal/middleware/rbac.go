@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/shared/rbac"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminAuth admits only API keys granted the admin role and identifies the operator
+// behind each request
+type AdminAuth = rbac.Role
+
+// NewAdminAuth creates the admin role check from RBAC configuration
+func NewAdminAuth(cfg *config.RBACConfig) *AdminAuth {
+	if len(cfg.Admins) == 0 {
+		log.Warn().Msg("No API key has the admin role; admin endpoints are disabled")
+	}
+	return rbac.New("admin", "operator", config.AppConfig.Security.APIKeyHeader, cfg.Admins, func(r *http.Request) {
+		log.Warn().Str("path", r.URL.Path).Msg("Admin request refused: API key lacks the admin role")
+	})
+}
+
+// OperatorFromContext returns the admin operator who made a request
+func OperatorFromContext(ctx context.Context) string {
+	return rbac.HolderFromContext(ctx)
+}
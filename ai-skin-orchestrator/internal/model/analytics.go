@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+// Conversation event kinds
+const (
+	EventIntent = "intent" // One request of a /process message
+	EventChat   = "chat"   // A /chat message, answered conversationally
+)
+
+// Intent parsers, as recorded in Intent.Metadata["parsed_by"]
+const (
+	ParsedByLLM        = "llm"
+	ParsedByRules      = "rules"
+	ParsedByStructured = "structured"
+	ParsedByCustom     = "custom" // A tenant's custom intent matched where the parser found none
+)
+
+// ConversationEvent is one request a user made of the AI Skin, kept for analytics
+type ConversationEvent struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	TenantID  string     `json:"tenant_id,omitempty"`
+	SessionID string     `json:"session_id,omitempty"`
+	Channel   string     `json:"channel"`
+	Kind      string     `json:"kind"`                // intent or chat
+	Intent    IntentType `json:"intent,omitempty"`    // As parsed, before any retry was resolved
+	ParsedBy  string     `json:"parsed_by,omitempty"` // llm, rules, structured or custom
+	Status    string     `json:"status"`              // Decision status, SKIPPED or FAILED
+	Fallback  bool       `json:"fallback,omitempty"`  // No banking intent was found, so the user only got conversational help
+
+	// Set when the request was held for the user to confirm with a step-up challenge
+	ChallengeID        string     `json:"challenge_id,omitempty"`
+	ChallengeExpiresAt *time.Time `json:"challenge_expires_at,omitempty"`
+	ConfirmedAt        *time.Time `json:"confirmed_at,omitempty"`
+
+	// What was said and done, for the session transcript
+	Input      string                 `json:"input,omitempty"` // The user's text; a step's own segment for a message holding several requests
+	Confidence float64                `json:"confidence,omitempty"`
+	Entities   map[string]interface{} `json:"entities,omitempty"`
+	Outcomes   []AgentOutcome         `json:"outcomes,omitempty"` // Agent responses or tool calls, in order
+	Reply      string                 `json:"reply,omitempty"`    // Explanation or chat answer the user was given
+
+	At time.Time `json:"at"`
+}
+
+// AgentOutcome is what an agent or tool answered for one request
+type AgentOutcome struct {
+	TaskID      string    `json:"task_id,omitempty"`
+	AgentType   string    `json:"agent_type"` // Tool name for a chat tool call
+	Status      string    `json:"status"`
+	RiskScore   float64   `json:"risk_score,omitempty"`
+	Explanation string    `json:"explanation,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// AnalyticsQuery selects the events a report covers. From is inclusive, To exclusive.
+type AnalyticsQuery struct {
+	From     time.Time
+	To       time.Time
+	UserID   string
+	TenantID string
+	GroupBy  string // user, tenant or channel; empty for totals only
+}
+
+// ConversationStats aggregates conversation events
+type ConversationStats struct {
+	Requests      int                `json:"requests"` // Intents and chat messages
+	Intents       map[IntentType]int `json:"intents"`
+	ChatMessages  int                `json:"chat_messages"`
+	Failed        int                `json:"failed"`    // Requests that errored before an outcome
+	Cancelled     int                `json:"cancelled"` // Requests their clients abandoned
+	Fallbacks     int                `json:"fallbacks"`
+	FallbackRate  float64            `json:"fallback_rate"` // Share of intents that found no banking intent
+	Parsing       ParseShare         `json:"parsing"`
+	Confirmations ConfirmationStats  `json:"confirmations"`
+}
+
+// ParseShare counts intents by the parser that understood them. Structured input is
+// not natural language and is left out of the shares.
+type ParseShare struct {
+	LLM        int     `json:"llm"`
+	Rules      int     `json:"rules"`
+	Structured int     `json:"structured"`
+	LLMShare   float64 `json:"llm_share"`
+	RuleShare  float64 `json:"rule_share"`
+}
+
+// ConfirmationStats counts step-up challenges by how they ended. A challenge not
+// answered before it expired was abandoned; one that may still be answered is open.
+type ConfirmationStats struct {
+	Requested       int     `json:"requested"`
+	Confirmed       int     `json:"confirmed"`
+	Abandoned       int     `json:"abandoned"`
+	Open            int     `json:"open"`
+	AbandonmentRate float64 `json:"abandonment_rate"` // Abandoned of those that ended
+}
+
+// ConversationGroup is the stats of one user, tenant or channel
+type ConversationGroup struct {
+	Key   string            `json:"key"`
+	Users int               `json:"users"`
+	Stats ConversationStats `json:"stats"`
+}
+
+// ConversationAnalytics is a conversation analytics report over a date range
+type ConversationAnalytics struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	UserID   string              `json:"user_id,omitempty"`
+	TenantID string              `json:"tenant_id,omitempty"`
+	GroupBy  string              `json:"group_by,omitempty"`
+	Users    int                 `json:"users"`
+	Totals   ConversationStats   `json:"totals"`
+	Groups   []ConversationGroup `json:"groups,omitempty"` // Busiest first
+}
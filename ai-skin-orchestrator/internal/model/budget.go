@@ -0,0 +1,36 @@
+package model
+
+// Pipeline stages a request budget can skip or cut short
+const (
+	BudgetStageIntentLLM          = "intent_llm"          // Intent parsed by rules instead of the LLM
+	BudgetStageFewShot            = "few_shot"            // No examples retrieved for the intent prompt
+	BudgetStageStatementNarrative = "statement_narrative" // Statement summary narrated from its template
+	BudgetStageErrorPolish        = "error_polish"        // Error explanation left as its template
+	BudgetStageMLScoring          = "ml_scoring"          // Agents scored with their rules instead of ML models
+	BudgetStageAgentWait          = "agent_wait"          // Answered PENDING before the task finished
+)
+
+// Why a stage was degraded
+const (
+	BudgetReasonNotAllowed = "not_allowed" // The budget does not allow the LLM or ML models
+	BudgetReasonLatency    = "latency"     // Too little of the budget was left
+)
+
+// RequestBudget bounds what a request may spend. A channel can set a budget for all
+// its requests; a request may tighten it, never loosen it.
+type RequestBudget struct {
+	MaxLatencyMs int   `json:"max_latency_ms,omitempty"` // Time to answer in; 0 for no limit
+	AllowLLM     *bool `json:"allow_llm,omitempty"`      // Defaults to true
+	AllowML      *bool `json:"allow_ml,omitempty"`       // Defaults to true
+}
+
+// BudgetReport is the budget a request ran under and what was degraded to keep to
+// it, returned with the response
+type BudgetReport struct {
+	MaxLatencyMs int      `json:"max_latency_ms,omitempty"`
+	AllowLLM     bool     `json:"allow_llm"`
+	AllowML      bool     `json:"allow_ml"`
+	ElapsedMs    int64    `json:"elapsed_ms"`
+	Exceeded     bool     `json:"exceeded,omitempty"`     // Answered after MaxLatencyMs
+	Degradations []string `json:"degradations,omitempty"` // "stage:reason", in the order applied
+}
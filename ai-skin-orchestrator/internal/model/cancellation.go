@@ -0,0 +1,27 @@
+package model
+
+// Stages a request can be abandoned in
+const (
+	CancelStageParse  = "parse"  // Intent parsing, usually an LLM call
+	CancelStageEnrich = "enrich" // Context enrichment
+	CancelStageSubmit = "submit" // Submitting the task to the MCP server
+	CancelStageWait   = "wait"   // Polling the MCP server for the task's result
+	CancelStageLLM    = "llm"    // Chat completions and tool calls
+)
+
+// CancellationStats counts requests whose clients went away before they were answered
+type CancellationStats struct {
+	Total      int64                    `json:"total"`
+	ByEndpoint map[string]int64         `json:"by_endpoint"`
+	ByStage    map[string]int64         `json:"by_stage"`
+	MCPTasks   MCPTaskCancellationStats `json:"mcp_tasks"`
+}
+
+// MCPTaskCancellationStats counts the MCP tasks of abandoned requests. A task the MCP
+// server refuses to cancel, such as a transfer an agent is already executing, runs to
+// completion.
+type MCPTaskCancellationStats struct {
+	Cancelled int64 `json:"cancelled"`
+	Refused   int64 `json:"refused"`
+	Failed    int64 `json:"failed"`
+}
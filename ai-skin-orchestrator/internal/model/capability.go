@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Capability availability, derived from the health of the agents an intent needs
+const (
+	CapabilityAvailable   = "AVAILABLE"
+	CapabilityDegraded    = "DEGRADED"    // Runs, but a check agent is missing or unhealthy
+	CapabilityUnavailable = "UNAVAILABLE" // The executing agent is missing or unhealthy
+	CapabilityUnknown     = "UNKNOWN"     // The agent registry could not be reached
+)
+
+// Capability is one intent the platform can execute and whether it can right now
+type Capability struct {
+	Intent      string   `json:"intent"`
+	Feature     string   `json:"feature"` // Client feature toggle the intent belongs to
+	Description string   `json:"description"`
+	Example     string   `json:"example"`
+	Status      string   `json:"status"`
+	AgentType   string   `json:"agent_type,omitempty"` // Empty when the AI Skin handles the intent itself
+	Checks      []string `json:"checks,omitempty"`     // Agent types that vet the intent before it runs
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// CapabilityAgent is an agent from the MCP registry as the capabilities report shows it
+type CapabilityAgent struct {
+	AgentID      string    `json:"agent_id"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	Capabilities []string  `json:"capabilities"`
+	LastHealthAt time.Time `json:"last_health_at"`
+}
+
+// Capabilities is the response of GET /capabilities
+type Capabilities struct {
+	Capabilities []Capability      `json:"capabilities"`
+	Agents       []CapabilityAgent `json:"agents"`
+	Features     map[string]bool   `json:"features"` // Feature toggle -> at least one of its intents is usable
+	Source       string            `json:"source"`   // mcp, cache (registry unreachable, last known) or static
+	Stale        bool              `json:"stale,omitempty"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+}
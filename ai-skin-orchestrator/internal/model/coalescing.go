@@ -0,0 +1,8 @@
+package model
+
+// CoalescingStats counts identical read-only requests answered by one MCP task
+type CoalescingStats struct {
+	Enabled  bool  `json:"enabled"`
+	Executed int64 `json:"executed"` // Read tasks submitted to the MCP server
+	Shared   int64 `json:"shared"`   // Requests that joined a read already in flight
+}
@@ -0,0 +1,20 @@
+package model
+
+// CustomIntent is a tenant's own intent as registered with the MCP server. The parser
+// recognises it from its patterns when no built-in intent matches.
+type CustomIntent struct {
+	Intent      string               `json:"intent"`
+	TenantID    string               `json:"tenant_id,omitempty"` // Empty for every tenant
+	Description string               `json:"description"`
+	Example     string               `json:"example,omitempty"`
+	Patterns    []string             `json:"patterns"`
+	Entities    []CustomIntentEntity `json:"entities,omitempty"`
+	Capability  string               `json:"capability"`
+}
+
+// CustomIntentEntity is a value extracted from input matching a custom intent
+type CustomIntentEntity struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"` // Its first group, or the whole match, is the value
+	Required bool   `json:"required,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Decision is the outcome of a user's last request, kept on the session so a later
+// "why?" can be answered without re-running the pipeline
+type Decision struct {
+	UserID       string                 `json:"user_id"`
+	SessionID    string                 `json:"session_id,omitempty"`
+	Intent       IntentType             `json:"intent"`
+	Status       string                 `json:"status"`
+	Amount       float64                `json:"amount,omitempty"`
+	Entities     map[string]interface{} `json:"entities,omitempty"`               // Slots of the request, reused when it is retried
+	MethodSource string                 `json:"transfer_method_source,omitempty"` // Set when the rail came from preferences
+	RiskScore    float64                `json:"risk_score"`
+	Explanation  string                 `json:"explanation,omitempty"`
+	Reasons      []DecisionReason       `json:"reasons,omitempty"`
+	Simulated    bool                   `json:"simulated,omitempty"`
+	DecidedAt    time.Time              `json:"decided_at"`
+}
+
+// DecisionReason is one structured reason behind a rejection or hold
+type DecisionReason struct {
+	Code      string   `json:"code"`   // Failed check or flag, e.g. daily_limit, fraud_score
+	Source    string   `json:"source"` // Agent type that raised it
+	Detail    string   `json:"detail,omitempty"`
+	Limit     *float64 `json:"limit,omitempty"`
+	Used      *float64 `json:"used,omitempty"`
+	Requested *float64 `json:"requested,omitempty"`
+	Score     *float64 `json:"score,omitempty"`
+}
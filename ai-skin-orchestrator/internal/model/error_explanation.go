@@ -0,0 +1,52 @@
+package model
+
+// Error codes of failures explained to users
+const (
+	ErrorCodeUnavailable       = "SERVICE_UNAVAILABLE"  // The MCP server or a backend could not be reached
+	ErrorCodeBusy              = "SERVICE_BUSY"         // The MCP server refused the task under load
+	ErrorCodeTimeout           = "TIMEOUT"              // A call took too long
+	ErrorCodeAgentUnavailable  = "AGENT_UNAVAILABLE"    // No agent could take the task
+	ErrorCodeInvalidResult     = "INVALID_AGENT_RESULT" // An agent answered with a result missing what it needs
+	ErrorCodeDuplicate         = "DUPLICATE_SUBMISSION" // A money-moving request was refused as a replay
+	ErrorCodeInterrupted       = "INTERRUPTED"          // The server stopped while a transfer was being carried out
+	ErrorCodeInsufficientFunds = "INSUFFICIENT_FUNDS"
+	ErrorCodeLimitExceeded     = "LIMIT_EXCEEDED"
+	ErrorCodeInvalidRequest    = "INVALID_REQUEST"  // Something in the request was refused as invalid
+	ErrorCodeFeatureDisabled   = "FEATURE_DISABLED" // The intent is switched off by an intent flag
+	ErrorCodeInternal          = "INTERNAL_ERROR"
+)
+
+// ErrorExplanation is what the user is told about a failure: what happened and what
+// they can do, never the error itself
+type ErrorExplanation struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Action    string `json:"action,omitempty"` // What the user can do about it
+	Retryable bool   `json:"retryable"`
+	Reference string `json:"reference"`          // Quoted to support to find the failure in the logs
+	Polished  bool   `json:"polished,omitempty"` // Message reworded by the LLM
+}
+
+// Text is the explanation as one sentence or two, for a response's explanation
+func (e *ErrorExplanation) Text() string {
+	if e.Action == "" {
+		return e.Message
+	}
+	return e.Message + " " + e.Action
+}
+
+// ErrorDiagnostics is the technical side of a failure, for support and developers
+type ErrorDiagnostics struct {
+	Code      string `json:"code"`
+	Reference string `json:"reference"`
+	Source    string `json:"source"` // Where the error came from: mcp_server, task or ai_skin
+	TaskID    string `json:"task_id,omitempty"`
+	Detail    string `json:"detail,omitempty"` // The raw error, only with ERROR_EXPOSE_DETAILS
+}
+
+// Sources of errors
+const (
+	ErrorSourceMCP  = "mcp_server" // A call to the MCP server failed
+	ErrorSourceTask = "task"       // The task failed on the MCP server
+	ErrorSourceSkin = "ai_skin"
+)
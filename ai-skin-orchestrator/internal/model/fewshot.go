@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Few-shot example sources
+const (
+	FewShotSourceEmbedded = "embedded"
+	FewShotSourceFile     = "file"
+	FewShotSourceAPI      = "api"
+)
+
+// FewShotExample is an utterance with the JSON the intent parser should answer it with,
+// shown to the LLM when a user's input is similar
+type FewShotExample struct {
+	ID        string                 `json:"id"`
+	Utterance string                 `json:"utterance"`
+	Expected  map[string]interface{} `json:"expected"` // intent, confidence and entities, as the intent_extraction prompt asks
+	Intent    IntentType             `json:"intent"`   // Expected["intent"]
+	Enabled   bool                   `json:"enabled"`
+	Note      string                 `json:"note,omitempty"` // Why it was added, e.g. the phrasing it fixes
+	Source    string                 `json:"source"`
+	AddedBy   string                 `json:"added_by,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+
+	// Hit metrics, since the service started
+	Hits      int64      `json:"hits"`    // Prompts the example was added to
+	Matches   int64      `json:"matches"` // Hits where the LLM answered the example's intent
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+}
+
+// FewShotExampleRequest adds an example, or changes one. On a change, fields left out
+// keep their value.
+type FewShotExampleRequest struct {
+	Utterance string                 `json:"utterance"`
+	Expected  map[string]interface{} `json:"expected"`
+	Enabled   *bool                  `json:"enabled,omitempty"` // Defaults to true for a new example
+	Note      string                 `json:"note,omitempty"`
+	AddedBy   string                 `json:"added_by,omitempty"`
+}
+
+// FewShotMatch is an example chosen for an input, with how similar it is
+type FewShotMatch struct {
+	Example    FewShotExample `json:"example"`
+	Similarity float64        `json:"similarity"`
+}
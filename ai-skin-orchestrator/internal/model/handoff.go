@@ -0,0 +1,16 @@
+package model
+
+// StatusHandoff is the status of a response that hands the user to an app screen
+// instead of executing their request
+const StatusHandoff = "HANDOFF"
+
+// Handoff sends the user to a native app screen, e.g. the full loan application,
+// with what the conversation already told us filled in
+type Handoff struct {
+	Intent   string                 `json:"intent"`
+	ScreenID string                 `json:"screen_id,omitempty"`
+	DeepLink string                 `json:"deep_link,omitempty"` // Prefilled values are added as query parameters
+	Title    string                 `json:"title,omitempty"`
+	Prefill  map[string]interface{} `json:"prefill"`           // Screen field → value
+	Missing  []string               `json:"missing,omitempty"` // Screen fields the conversation did not fill
+}
@@ -6,16 +6,27 @@ import "time"
 type IntentType string
 
 const (
-	IntentTransferNEFT   IntentType = "TRANSFER_NEFT"
-	IntentTransferRTGS   IntentType = "TRANSFER_RTGS"
-	IntentTransferIMPS   IntentType = "TRANSFER_IMPS"
-	IntentTransferUPI    IntentType = "TRANSFER_UPI"
-	IntentCheckBalance   IntentType = "CHECK_BALANCE"
-	IntentGetStatement   IntentType = "GET_STATEMENT"
-	IntentAddBeneficiary IntentType = "ADD_BENEFICIARY"
-	IntentApplyLoan      IntentType = "APPLY_LOAN"
-	IntentCreditScore    IntentType = "CREDIT_SCORE"
-	IntentUnknown        IntentType = "UNKNOWN"
+	IntentTransferNEFT      IntentType = "TRANSFER_NEFT"
+	IntentTransferRTGS      IntentType = "TRANSFER_RTGS"
+	IntentTransferIMPS      IntentType = "TRANSFER_IMPS"
+	IntentTransferUPI       IntentType = "TRANSFER_UPI"
+	IntentCheckBalance      IntentType = "CHECK_BALANCE"
+	IntentGetStatement      IntentType = "GET_STATEMENT"
+	IntentSummarizeStatement IntentType = "SUMMARIZE_STATEMENT" // Totals, top payees and categories of a statement, narrated
+	IntentAddBeneficiary    IntentType = "ADD_BENEFICIARY"
+	IntentListBeneficiaries IntentType = "LIST_BENEFICIARIES"
+	IntentRequestMoney      IntentType = "REQUEST_MONEY" // Asks someone to pay the user over UPI
+	IntentShareReceipt      IntentType = "SHARE_RECEIPT" // A public link to a transfer's receipt
+	IntentExportTransactions IntentType = "EXPORT_TRANSACTIONS" // A download link to transactions as OFX, QIF or CSV
+	IntentSetBudget         IntentType = "SET_BUDGET"    // A monthly spending limit the user is alerted about
+	IntentBudgetStatus      IntentType = "BUDGET_STATUS" // How much of each budget is spent this month
+	IntentApplyLoan         IntentType = "APPLY_LOAN"
+	IntentCreditScore       IntentType = "CREDIT_SCORE"
+	IntentGetInsights       IntentType = "GET_INSIGHTS" // Savings and spending suggestions
+	IntentSetPreference     IntentType = "SET_PREFERENCE" // Handled by the orchestrator, not an agent
+	IntentWhyRejected       IntentType = "WHY_REJECTED"   // Answered from the session's last decision
+	IntentRetryLast         IntentType = "RETRY_LAST"     // Re-submits the session's last action with changed slots
+	IntentUnknown           IntentType = "UNKNOWN"
 )
 
 // Intent represents a parsed user intent
@@ -25,6 +36,7 @@ type Intent struct {
 	Entities    map[string]interface{}  `json:"entities"`    // Extracted entities (amount, account, etc.)
 	OriginalText string                 `json:"original_text,omitempty"`
 	Metadata    map[string]interface{}  `json:"metadata,omitempty"`
+	Inferred    []string                `json:"inferred,omitempty"` // Filled in from the session, to confirm with the user
 }
 
 // UserRequest represents the incoming user request
@@ -36,6 +48,10 @@ type UserRequest struct {
 	StructuredData map[string]interface{} `json:"structured_data,omitempty"`
 	Context     map[string]interface{} `json:"context,omitempty"`
 	SessionID   string                 `json:"session_id,omitempty"`
+	Sandbox     bool                   `json:"sandbox,omitempty"` // Simulate execution without side effects
+	LLM         *LLMOverrides          `json:"llm,omitempty"`     // Per-request model/temperature overrides
+	Budget      *RequestBudget         `json:"budget,omitempty"`  // Latency, LLM and ML limits, e.g. for IVR
+	RulesOnly   bool                   `json:"-"`                 // Set when the user's LLM quota is exhausted
 }
 
 // OrchestrationRequest represents a request to the orchestrator
@@ -52,23 +68,36 @@ type OrchestrationRequest struct {
 type AgentResponse struct {
 	AgentID     string                 `json:"agent_id"`
 	AgentType   string                 `json:"agent_type"`
+	TaskID      string                 `json:"task_id,omitempty"` // MCP task that produced the response
 	Status      string                 `json:"status"` // APPROVED, REJECTED, PENDING
 	Result      map[string]interface{} `json:"result"`
 	RiskScore   float64                `json:"risk_score"`
 	Explanation string                 `json:"explanation"`
 	Confidence  float64                `json:"confidence"`
 	Timestamp   time.Time              `json:"timestamp"`
+	Error       string                 `json:"-"` // Raw error of a failed task; logged, never shown
 }
 
 // MergedResponse represents the final merged response from multiple agents
 type MergedResponse struct {
-	Status      string                 `json:"status"` // APPROVED, REJECTED, PENDING, CONFLICT
+	Status      string                 `json:"status"` // APPROVED, REJECTED, PENDING, CONFLICT, HANDOFF
 	FinalResult map[string]interface{} `json:"final_result"`
 	RiskScore   float64                `json:"risk_score"`
 	Explanation string                 `json:"explanation"`
 	AgentResponses []AgentResponse     `json:"agent_responses"`
 	Conflicts   []Conflict             `json:"conflicts,omitempty"`
 	ResolvedBy  string                 `json:"resolved_by,omitempty"` // Which agent/rule resolved conflicts
+	Advisory    []string               `json:"advisory,omitempty"`    // Agents whose status did not count, e.g. scoring on a balance inquiry
+	Simulated   bool                   `json:"simulated,omitempty"`   // True when produced in sandbox mode
+	Guardrails  []string               `json:"guardrails,omitempty"`  // Output guardrails that modified this response
+	Degraded    bool                   `json:"degraded,omitempty"`    // Intent parsed by rules because the LLM quota was exhausted
+	Transformers []string              `json:"transformers,omitempty"` // Response transformers applied to FinalResult, in order
+	SessionID    string                `json:"session_id,omitempty"`   // Conversation shared with the MCP server; send it with the next request
+	Handoff      *Handoff              `json:"handoff,omitempty"`      // Screen to open instead, when Status is HANDOFF
+	Suggestions  []QuickReply          `json:"suggestions,omitempty"`  // Quick replies to offer after this response
+	Error        *ErrorExplanation     `json:"error,omitempty"`        // What went wrong, when Status is FAILED
+	Diagnostics  *ErrorDiagnostics     `json:"diagnostics,omitempty"`  // The technical side of Error
+	Budget       *BudgetReport         `json:"budget,omitempty"`       // The request's budget and what was degraded to keep to it
 }
 
 // Conflict represents a conflict between agent responses
@@ -121,6 +150,7 @@ type RiskIndicators struct {
 	AmountRisk     float64  `json:"amount_risk"`
 	DeviceRisk     float64  `json:"device_risk,omitempty"`
 	LocationRisk   float64  `json:"location_risk,omitempty"`
+	ScamRisk       float64  `json:"scam_risk,omitempty"` // Signs in the conversation that the user is being scammed
 }
 
 // BehaviorPattern represents user behavior patterns
@@ -132,3 +162,38 @@ type BehaviorPattern struct {
 	AnomalyDetected   bool      `json:"anomaly_detected"`
 }
 
+
+// ChatRequest is a conversational request answered by the LLM with tool calls
+type ChatRequest struct {
+	UserID    string `json:"user_id"`
+	Channel   string `json:"channel"`
+	Message   string `json:"message"`
+	SessionID string        `json:"session_id,omitempty"`
+	Sandbox   bool          `json:"sandbox,omitempty"`
+	LLM       *LLMOverrides `json:"llm,omitempty"`      // Per-request model/temperature overrides
+	Timezone  string        `json:"timezone,omitempty"` // IANA name; kept on the session
+	Locale    string        `json:"locale,omitempty"`   // e.g. en-IN; kept on the session
+}
+
+// ToolInvocation records one tool call made while answering a chat request
+type ToolInvocation struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+	TaskID    string                 `json:"task_id,omitempty"` // MCP task the call ran as
+	Status    string                 `json:"status"` // Agent status, or ERROR
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// ChatResponse is the final answer to a chat request
+type ChatResponse struct {
+	Answer     string           `json:"answer"`
+	ToolCalls  []ToolInvocation `json:"tool_calls,omitempty"`
+	Iterations int              `json:"iterations"`
+	Model      string           `json:"model,omitempty"` // Model that produced the answer
+	Partial    bool             `json:"partial,omitempty"` // Answer was cut off and could not be recovered
+	Simulated  bool             `json:"simulated,omitempty"`
+	Guardrails []string         `json:"guardrails,omitempty"`
+	Degraded   bool             `json:"degraded,omitempty"` // Answered without the LLM because the quota was exhausted
+	SessionID  string           `json:"session_id,omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Intent flag modes, as set on the MCP server
+const (
+	IntentFlagEnabled  = "enabled"
+	IntentFlagDisabled = "disabled"
+	IntentFlagReadOnly = "read_only" // Only runs without side effects: sandbox requests, or an intent that only reads
+)
+
+// IntentFlag switches an intent off, or back on, for every tenant and channel or for
+// one tenant, one channel or both, as set on the MCP server
+type IntentFlag struct {
+	Intent   string `json:"intent"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Mode     string `json:"mode"`
+	Message  string `json:"message,omitempty"` // Told to users while the intent is off
+}
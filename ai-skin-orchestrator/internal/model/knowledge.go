@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// KnowledgeQuery is a policy or FAQ question answered from the knowledge collection
+type KnowledgeQuery struct {
+	Question   string `json:"question"`
+	TopK       int    `json:"top_k,omitempty"`
+	Synthesize *bool  `json:"synthesize,omitempty"` // Have the LLM write the answer; the server default when unset
+	UserID     string `json:"user_id,omitempty"`    // Customer asking, if any; charged for LLM tokens
+}
+
+// KnowledgeSource is a document an answer was drawn from
+type KnowledgeSource struct {
+	DocumentID string  `json:"document_id"`
+	Title      string  `json:"title,omitempty"`
+	Score      float64 `json:"score"`
+	Excerpt    string  `json:"excerpt"`
+}
+
+// KnowledgeAnswer answers a knowledge query. Without synthesis the answer is the
+// best-matching document; with no matching document it is empty.
+type KnowledgeAnswer struct {
+	Question    string            `json:"question"`
+	Answer      string            `json:"answer"`
+	Synthesized bool              `json:"synthesized"`
+	Sources     []KnowledgeSource `json:"sources"`
+	Cached      bool              `json:"cached"`
+	AnsweredAt  time.Time         `json:"answered_at"`
+}
+
+// KnowledgeDocument is a policy or FAQ document added to the knowledge collection
+type KnowledgeDocument struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Source  string `json:"source,omitempty"` // Where the document came from, e.g. a policy URL
+}
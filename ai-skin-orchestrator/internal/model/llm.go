@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// LLMOverrides are caller-supplied LLM settings for a request or session.
+// Empty fields fall back to the service defaults.
+type LLMOverrides struct {
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"` // Pointer so 0 can be requested explicitly
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+}
+
+// IsEmpty reports whether no override is set
+func (o *LLMOverrides) IsEmpty() bool {
+	return o == nil || (o.Model == "" && o.Temperature == nil && o.MaxTokens == 0)
+}
+
+// LLMSettings are the resolved settings used for one LLM call
+type LLMSettings struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens"`
+}
+
+// SessionLLMOverrides are the overrides stored for a session
+type SessionLLMOverrides struct {
+	SessionID string       `json:"session_id"`
+	Overrides LLMOverrides `json:"overrides"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// LLMOptions describes what callers may override
+type LLMOptions struct {
+	AllowedModels  []string               `json:"allowed_models"`
+	MinTemperature float64                `json:"min_temperature"`
+	MaxTemperature float64                `json:"max_temperature"`
+	MaxTokensLimit int                    `json:"max_tokens_limit"`
+	Defaults       map[string]LLMSettings `json:"defaults"` // Keyed by purpose (intent, chat)
+}
+
+// LLMCallStats counts recent LLM calls and how many failed
+type LLMCallStats struct {
+	WindowSeconds int     `json:"window_seconds"`
+	Calls         int     `json:"calls"`
+	Errors        int     `json:"errors"`
+	ErrorRate     float64 `json:"error_rate"`
+}
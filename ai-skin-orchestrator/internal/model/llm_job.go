@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// LLM job statuses
+const (
+	LLMJobQueued    = "QUEUED"
+	LLMJobRunning   = "RUNNING"
+	LLMJobSucceeded = "SUCCEEDED"
+	LLMJobFailed    = "FAILED"
+	LLMJobCancelled = "CANCELLED"
+)
+
+// Job callback statuses
+const (
+	CallbackPending   = "PENDING"
+	CallbackDelivered = "DELIVERED"
+	CallbackFailed    = "FAILED" // Out of attempts
+)
+
+// LLMJobRequest asks for a generation too long to wait for over HTTP, such as a
+// statement summary or an analysis across several policy documents
+type LLMJobRequest struct {
+	UserID      string           `json:"user_id"`
+	Instruction string           `json:"instruction"` // What to do with the documents
+	Documents   []LLMJobDocument `json:"documents,omitempty"`
+	LLM         *LLMOverrides    `json:"llm,omitempty"`
+	CallbackURL string           `json:"callback_url,omitempty"` // Posted the job once it finishes
+}
+
+// LLMJobDocument is text a job works on, e.g. a statement or a policy
+type LLMJobDocument struct {
+	Source  string `json:"source"` // Name shown to the model, e.g. "statement_2024_05"
+	Content string `json:"content"`
+}
+
+// LLMJob is a generation run in the background
+type LLMJob struct {
+	JobID       string           `json:"job_id"`
+	UserID      string           `json:"user_id"`
+	Status      string           `json:"status"`
+	Instruction string           `json:"instruction"`
+	Documents   []LLMJobDocument `json:"documents,omitempty"`
+	LLM         *LLMOverrides    `json:"llm,omitempty"`
+	Model       string           `json:"model,omitempty"` // Model the job ran on
+	Result      string           `json:"result,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	Flagged     []string         `json:"flagged,omitempty"` // Prompt injection patterns removed from the input
+	Attempts    int              `json:"attempts"`          // Runs started, counting those a restart interrupted
+	CreatedAt   time.Time        `json:"created_at"`
+	StartedAt   *time.Time       `json:"started_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	Callback    *JobCallback     `json:"callback,omitempty"`
+}
+
+// Finished reports whether the job will not run again
+func (j *LLMJob) Finished() bool {
+	return j.Status == LLMJobSucceeded || j.Status == LLMJobFailed || j.Status == LLMJobCancelled
+}
+
+// JobCallback is where a finished job is posted and how that went
+type JobCallback struct {
+	URL         string     `json:"url"`
+	Status      string     `json:"status,omitempty"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// LLMJobStats is how busy the background LLM workers are
+type LLMJobStats struct {
+	Workers   int            `json:"workers"`
+	QueueSize int            `json:"queue_size"`
+	Queued    int            `json:"queued"`
+	Running   int            `json:"running"`
+	ByStatus  map[string]int `json:"by_status"`
+	Persisted bool           `json:"persisted"` // Jobs are kept in LLM_JOBS_DIR across restarts
+}
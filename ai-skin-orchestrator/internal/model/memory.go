@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Memory categories a fact can be filed under
+const (
+	MemoryPayee      = "payee"    // Preferred or frequent payees
+	MemorySchedule   = "schedule" // Recurring dates such as salary day or rent
+	MemoryAccount    = "account"  // Preferred accounts and products
+	MemoryStyle      = "style"    // How the user likes to be answered
+	MemoryPreference = "preference"
+)
+
+// MemoryFact is a durable fact about a user distilled from past conversations
+type MemoryFact struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Category  string    `json:"category"`
+	Content   string    `json:"content"`
+	SessionID string    `json:"session_id,omitempty"` // Conversation the fact was learned from
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemorySettings records whether a user has opted in to long-term memory
+type MemorySettings struct {
+	UserID    string    `json:"user_id"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,61 @@
+package model
+
+// NLUDataset is a labeled set of utterances used to score the intent parser
+type NLUDataset struct {
+	Name       string                  `json:"name"`
+	Version    int                     `json:"version"`
+	Thresholds map[string]NLUThreshold `json:"thresholds"` // Minimum scores per engine (rules, llm)
+	Cases      []NLUCase               `json:"cases"`
+}
+
+// NLUThreshold is the minimum score an engine must reach. Zero values are not checked.
+type NLUThreshold struct {
+	IntentAccuracy float64 `json:"intent_accuracy"`
+	EntityF1       float64 `json:"entity_f1"`
+}
+
+// NLUCase is one labeled utterance. Only the labeled entities are expected; any other
+// entity the parser returns counts against precision.
+type NLUCase struct {
+	ID       string                 `json:"id"`
+	Text     string                 `json:"text"`
+	Intent   IntentType             `json:"intent"`
+	Entities map[string]interface{} `json:"entities,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+}
+
+// NLUReport is the outcome of scoring one engine against a dataset
+type NLUReport struct {
+	Dataset         string                         `json:"dataset"`
+	DatasetVersion  int                            `json:"dataset_version"`
+	Engine          string                         `json:"engine"`
+	Cases           int                            `json:"cases"`
+	IntentAccuracy  float64                        `json:"intent_accuracy"`
+	EntityPrecision float64                        `json:"entity_precision"`
+	EntityRecall    float64                        `json:"entity_recall"`
+	EntityF1        float64                        `json:"entity_f1"`
+	PerIntent       map[IntentType]*NLUIntentScore `json:"per_intent"`
+	Failures        []NLUFailure                   `json:"failures,omitempty"`
+	Threshold       *NLUThreshold                  `json:"threshold,omitempty"`
+	Passed          bool                           `json:"passed"`
+	Violations      []string                       `json:"violations,omitempty"` // Thresholds that were not met
+	DurationMs      int64                          `json:"duration_ms"`
+}
+
+// NLUIntentScore is the intent accuracy for the cases labeled with one intent
+type NLUIntentScore struct {
+	Cases    int     `json:"cases"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// NLUFailure is a case the parser got at least partly wrong
+type NLUFailure struct {
+	ID             string     `json:"id"`
+	Text           string     `json:"text"`
+	ExpectedIntent IntentType `json:"expected_intent"`
+	ActualIntent   IntentType `json:"actual_intent,omitempty"`
+	Missing        []string   `json:"missing_entities,omitempty"`    // Expected but absent or different
+	Unexpected     []string   `json:"unexpected_entities,omitempty"` // Returned but not expected, or different
+	Error          string     `json:"error,omitempty"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// OllamaMessage is one message in an Ollama /api/chat conversation
+type OllamaMessage struct {
+	Role      string           `json:"role"` // system, user, assistant, tool
+	Content   string           `json:"content"`
+	ToolCalls []OllamaToolCall `json:"tool_calls,omitempty"`
+}
+
+// OllamaToolCall is a tool call requested by an Ollama model
+type OllamaToolCall struct {
+	Function OllamaFunctionCall `json:"function"`
+}
+
+// OllamaFunctionCall names the function and its decoded arguments
+type OllamaFunctionCall struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// OllamaChatResponse is a non-streaming /api/chat response
+type OllamaChatResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Message         OllamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason,omitempty"`
+	TotalDuration   int64         `json:"total_duration,omitempty"` // Nanoseconds
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+	Error           string        `json:"error,omitempty"` // Set on a failed stream chunk
+
+	// Set by the client when a stream had to be resumed
+	Resumes int  `json:"resumes,omitempty"`
+	Partial bool `json:"partial,omitempty"` // Stream dropped and could not be recovered
+}
+
+// OllamaModel is a model available on the Ollama host
+type OllamaModel struct {
+	Name       string             `json:"name"`
+	Digest     string             `json:"digest"`
+	Size       int64              `json:"size"`
+	ModifiedAt time.Time          `json:"modified_at"`
+	Details    OllamaModelDetails `json:"details"`
+}
+
+// OllamaModelDetails describes a model's format and size
+type OllamaModelDetails struct {
+	Family            string `json:"family,omitempty"`
+	ParameterSize     string `json:"parameter_size,omitempty"`
+	QuantizationLevel string `json:"quantization_level,omitempty"`
+}
+
+// OllamaRunningModel is a model currently loaded into memory
+type OllamaRunningModel struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	SizeVRAM  int64     `json:"size_vram"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OllamaModelStatus reports whether a model is downloaded and loaded
+type OllamaModelStatus struct {
+	Name      string     `json:"name"`
+	Available bool       `json:"available"` // Pulled to the host
+	Loaded    bool       `json:"loaded"`    // Resident in memory
+	SizeVRAM  int64      `json:"size_vram,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,44 @@
+package model
+
+// PayeeMatch is a biller or merchant the Banking Integrations payee directory found
+// for a name the user mentioned
+type PayeeMatch struct {
+	PayeeID       string     `json:"payee_id"`
+	Name          string     `json:"name"`
+	Category      string     `json:"category"` // BILLER or MERCHANT
+	Subcategory   string     `json:"subcategory,omitempty"`
+	Verified      bool       `json:"verified"`
+	AccountNumber string     `json:"account_number,omitempty"`
+	IFSC          string     `json:"ifsc,omitempty"`
+	VPA           string     `json:"vpa,omitempty"`
+	Logo          *PayeeLogo `json:"logo,omitempty"`
+	Score         float64    `json:"score"`
+	MatchedOn     string     `json:"matched_on"`
+}
+
+// PayeeLogo is the badge a client draws for a directory payee
+type PayeeLogo struct {
+	URL      string `json:"url,omitempty"`
+	Initials string `json:"initials"`
+	Color    string `json:"color,omitempty"`
+}
+
+// PayeeVerificationRequest asks whether a transfer's payee is who it claims to be
+type PayeeVerificationRequest struct {
+	ToAccount string `json:"to_account,omitempty"`
+	IFSC      string `json:"ifsc,omitempty"`
+	VPA       string `json:"vpa,omitempty"`
+	PayeeName string `json:"payee_name,omitempty"`
+}
+
+// PayeeVerification is the verification badge shown with a transfer confirmation:
+// VERIFIED, UNVERIFIED, NAME_MISMATCH or NOT_LISTED
+type PayeeVerification struct {
+	Status   string     `json:"status"`
+	Verified bool       `json:"verified"`
+	PayeeID  string     `json:"payee_id,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Category string     `json:"category,omitempty"`
+	Logo     *PayeeLogo `json:"logo,omitempty"`
+	Message  string     `json:"message"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// TransferRule picks a transfer method for amounts up to MaxAmount (0 means any amount)
+type TransferRule struct {
+	Method    string  `json:"method"`
+	MaxAmount float64 `json:"max_amount,omitempty"`
+}
+
+// UserPreferences are a user's banking defaults as stored by Banking Integrations
+type UserPreferences struct {
+	UserID                  string         `json:"user_id"`
+	DefaultAccount          string         `json:"default_account,omitempty"`
+	PreferredTransferMethod string         `json:"preferred_transfer_method,omitempty"`
+	NotificationChannel     string         `json:"notification_channel,omitempty"`
+	TransferRules           []TransferRule `json:"transfer_rules,omitempty"` // Smallest MaxAmount first
+}
+
+// PreferenceUpdate changes the preferences that are set and leaves the others as they are
+type PreferenceUpdate struct {
+	DefaultAccount          string         `json:"default_account,omitempty"`
+	PreferredTransferMethod string         `json:"preferred_transfer_method,omitempty"`
+	NotificationChannel     string         `json:"notification_channel,omitempty"`
+	TransferRules           []TransferRule `json:"transfer_rules,omitempty"`
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *PreferenceUpdate) IsEmpty() bool {
+	return u.DefaultAccount == "" && u.PreferredTransferMethod == "" && u.NotificationChannel == "" && len(u.TransferRules) == 0
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// PromptTemplate is one version of a named prompt template
+type PromptTemplate struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Body      string    `json:"body"`
+	Source    string    `json:"source"` // "embedded", "file" or "api"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PromptInfo summarises the versions available for a prompt
+type PromptInfo struct {
+	Name          string `json:"name"`
+	ActiveVersion int    `json:"active_version"`
+	Versions      []int  `json:"versions"`
+}
+
+// PromptVars are the variables available to every prompt template
+type PromptVars struct {
+	Persona      string                 `json:"persona"`
+	TenantName   string                 `json:"tenant_name"`
+	Capabilities []string               `json:"capabilities"`
+	UserInput    string                 `json:"user_input,omitempty"`
+	Examples     []PromptExample        `json:"examples,omitempty"` // Few-shot examples chosen for the input
+	Extra        map[string]interface{} `json:"extra,omitempty"`
+}
+
+// PromptExample is a few-shot example as templates render it
+type PromptExample struct {
+	Input  string `json:"input"`
+	Output string `json:"output"` // The JSON to answer Input with
+}
+
+// RenderedPrompt is a rendered template together with the version used
+type RenderedPrompt struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
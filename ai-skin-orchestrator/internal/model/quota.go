@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Quota limits a user can run into
+const (
+	QuotaRequestsPerMinute = "requests_per_minute"
+	QuotaTokensPerDay      = "tokens_per_day"
+)
+
+// QuotaStatus is a user's LLM quota usage. Limits of zero are not enforced.
+type QuotaStatus struct {
+	UserID            string    `json:"user_id"`
+	RequestsLimit     int       `json:"requests_limit"`
+	RequestsRemaining int       `json:"requests_remaining"`
+	RequestsReset     time.Time `json:"requests_reset"`
+	TokensLimit       int       `json:"tokens_limit"`
+	TokensUsed        int       `json:"tokens_used"`
+	TokensRemaining   int       `json:"tokens_remaining"`
+	TokensReset       time.Time `json:"tokens_reset"`
+	Exceeded          string    `json:"exceeded,omitempty"` // Limit that blocked the request, if any
+}
+
+// RetryAt returns when the exceeded limit resets
+func (s QuotaStatus) RetryAt() time.Time {
+	if s.Exceeded == QuotaTokensPerDay {
+		return s.TokensReset
+	}
+	return s.RequestsReset
+}
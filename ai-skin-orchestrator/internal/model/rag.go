@@ -0,0 +1,106 @@
+package model
+
+import "time"
+
+// RAG document collections
+const (
+	CollectionConversation = "conversation"
+	CollectionTransaction  = "transaction"
+	CollectionKnowledge    = "knowledge" // Shared policy/FAQ documents, not tied to a user
+)
+
+// EmbeddingStatus tracks a document through the embedding pipeline
+type EmbeddingStatus string
+
+const (
+	EmbeddingPending  EmbeddingStatus = "PENDING"  // Queued, not yet searchable
+	EmbeddingEmbedded EmbeddingStatus = "EMBEDDED" // Searchable
+	EmbeddingFailed   EmbeddingStatus = "FAILED"
+)
+
+// Document is a piece of text stored for retrieval
+type Document struct {
+	ID             string                 `json:"id"`
+	UserID         string                 `json:"user_id,omitempty"`
+	Collection     string                 `json:"collection"`
+	Content        string                 `json:"content"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Embedding      []float32              `json:"-"`
+	EmbeddingModel string                 `json:"embedding_model,omitempty"`
+	Status         EmbeddingStatus        `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	Error          string                 `json:"error,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	EmbeddedAt     *time.Time             `json:"embedded_at,omitempty"`
+
+	// Set while a re-index migrates the store to another model
+	NextEmbedding      []float32 `json:"-"`
+	NextEmbeddingModel string    `json:"next_embedding_model,omitempty"`
+}
+
+// SearchRequest is a similarity search over stored documents
+type SearchRequest struct {
+	UserID     string `json:"user_id"`
+	Collection string `json:"collection"`
+	Query      string `json:"query"`
+	TopK       int    `json:"top_k,omitempty"`
+}
+
+// SearchResult is a document and its similarity to the query
+type SearchResult struct {
+	Document *Document `json:"document"`
+	Score    float64   `json:"score"`
+}
+
+// EmbeddingStats reports the state of the embedding pipeline
+type EmbeddingStats struct {
+	Model          string  `json:"model"`
+	NextModel      string  `json:"next_model,omitempty"` // Model a re-index is migrating to
+	Workers        int     `json:"workers"`
+	QueueDepth     int     `json:"queue_depth"` // Documents waiting for a worker
+	QueueCapacity  int     `json:"queue_capacity"`
+	Pending        int     `json:"pending"` // Queued or being embedded
+	Embedded       int64   `json:"embedded_total"`
+	Failed         int64   `json:"failed_total"`
+	Retried        int64   `json:"retried_total"`
+	Dropped        int64   `json:"dropped_total"` // Rejected because the queue was full
+	Documents      int     `json:"documents"`
+	AvgLatencyMs   float64 `json:"avg_embed_latency_ms"`
+	OldestPendingS float64 `json:"oldest_pending_seconds"` // Age of the oldest unembedded document
+}
+
+// ReindexStatus tracks a re-index job
+type ReindexStatus string
+
+const (
+	ReindexRunning   ReindexStatus = "RUNNING"
+	ReindexPaused    ReindexStatus = "PAUSED" // By an operator or after a batch kept failing; resumable
+	ReindexCompleted ReindexStatus = "COMPLETED"
+	ReindexCancelled ReindexStatus = "CANCELLED"
+)
+
+// ReindexRequest starts re-embedding every stored document with another model
+type ReindexRequest struct {
+	Provider   string `json:"provider"`             // hash or ollama
+	Model      string `json:"model,omitempty"`      // Ollama model; RAG_EMBEDDING_MODEL when empty
+	Dimensions int    `json:"dimensions,omitempty"` // Hash vector size; RAG_HASH_DIMENSIONS when empty
+	BatchSize  int    `json:"batch_size,omitempty"` // RAG_REINDEX_BATCH_SIZE when empty
+}
+
+// ReindexJob migrates the document store from one embedding model to another. Search
+// keeps working throughout; the new model takes over once every document has a
+// vector from it.
+type ReindexJob struct {
+	JobID      string        `json:"job_id"`
+	FromModel  string        `json:"from_model"`
+	ToModel    string        `json:"to_model"`
+	Status     ReindexStatus `json:"status"`
+	BatchSize  int           `json:"batch_size"`
+	Total      int           `json:"total"` // Grows as documents are stored during the job
+	Done       int           `json:"done"`
+	Batches    int           `json:"batches"`
+	Error      string        `json:"error,omitempty"` // Why the job paused
+	StartedAt  time.Time     `json:"started_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Data classes the AI Skin keeps under a retention policy
+const (
+	RetentionConversations = "conversations" // Conversation documents, and the memory facts and analytics events from them
+	RetentionTransactions  = "transactions"  // Transaction documents
+)
+
+// RetentionPolicy is how long one class of data is kept. Days of 0 keeps it forever.
+type RetentionPolicy struct {
+	Class       string `json:"class"`
+	Days        int    `json:"days"`
+	Description string `json:"description"`
+}
+
+// LegalHold exempts a user's data from purging until it is released
+type LegalHold struct {
+	UserID   string    `json:"user_id"`
+	Reason   string    `json:"reason"`
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// LegalHoldRequest places a legal hold
+type LegalHoldRequest struct {
+	Reason   string `json:"reason"`
+	PlacedBy string `json:"placed_by"`
+}
+
+// PurgeReport records one purge run: what each class lost and what was kept back
+// because of a legal hold
+type PurgeReport struct {
+	ID         string        `json:"id"`
+	DryRun     bool          `json:"dry_run"`
+	Trigger    string        `json:"trigger"` // "schedule" or "manual"
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Classes    []PurgedClass `json:"classes"`
+}
+
+// PurgedClass is the outcome of a purge for one data class
+type PurgedClass struct {
+	Class   string     `json:"class"`
+	Cutoff  *time.Time `json:"cutoff,omitempty"` // Data older than this was purged
+	Purged  int        `json:"purged"`
+	Held    int        `json:"held"`              // Past the cutoff but under a legal hold
+	IDs     []string   `json:"ids,omitempty"`     // What was purged, up to a limit
+	Skipped bool       `json:"skipped,omitempty"` // The class is kept forever
+}
@@ -0,0 +1,30 @@
+package model
+
+// Scam signal codes
+const (
+	ScamUrgency        = "URGENCY"          // "urgent", "right now", "within 10 minutes"
+	ScamCoaching       = "COACHING"         // Someone is telling the user what to say or to keep quiet
+	ScamRemoteAccess   = "REMOTE_ACCESS"    // AnyDesk, TeamViewer, screen sharing
+	ScamAuthority      = "AUTHORITY"        // Police, RBI, customs, "digital arrest", KYC expiring
+	ScamOTPSharing     = "OTP_SHARING"      // Asked to pass on an OTP, PIN or CVV
+	ScamPrize          = "PRIZE"            // Lottery wins and fees to release a prize or refund
+	ScamFirstTimePayee = "FIRST_TIME_PAYEE" // A payee the user has not paid before
+	ScamLargeAmount    = "LARGE_AMOUNT"     // From SCAM_LARGE_AMOUNT, or far above the user's usual
+	ScamCombination    = "COMBINATION"      // A large first payment to a new payee while being coached
+)
+
+// ScamSignal is one sign that a user is being talked into a payment
+type ScamSignal struct {
+	Code     string  `json:"code"`
+	Weight   float64 `json:"weight"`
+	Evidence string  `json:"evidence,omitempty"` // The words that raised it
+}
+
+// ScamAssessment is how likely a transfer or new payee is to be part of a
+// social-engineering scam, judged on what was said in the session and the payment
+type ScamAssessment struct {
+	Score   float64      `json:"score"`
+	Level   string       `json:"level"` // LOW, MEDIUM, HIGH
+	Signals []ScamSignal `json:"signals"`
+	Warning string       `json:"warning,omitempty"` // Added to the confirmation from MEDIUM
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// SessionBinding is a conversation as the AI Skin and the MCP server share it: one
+// session ID, the context both sides have contributed and the tasks run in it
+type SessionBinding struct {
+	SessionID   string                 `json:"session_id"`
+	UserID      string                 `json:"user_id"`
+	Channel     string                 `json:"channel"`
+	Context     map[string]interface{} `json:"context"`
+	TaskHistory []string               `json:"task_history"`
+	ExpiresAt   time.Time              `json:"expires_at"`
+	SyncedAt    time.Time              `json:"synced_at,omitempty"` // Zero while the MCP server could not be reached
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Narrative sources of a statement summary
+const (
+	NarratedByLLM      = "llm"      // Written by the LLM and checked against the aggregates
+	NarratedByTemplate = "template" // Built from the aggregates without the LLM
+)
+
+// StatementPeriod is the stretch of time a statement summary covers
+type StatementPeriod struct {
+	Name string    `json:"name"` // LAST_MONTH, THIS_MONTH, LAST_WEEK, THIS_WEEK or LAST_<N>_DAYS
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"` // Exclusive
+}
+
+// StatementSummary is what a statement adds up to over a period, with a short
+// narrative of it for the user
+type StatementSummary struct {
+	Period     StatementPeriod     `json:"period"`
+	AccountID  string              `json:"account_id,omitempty"`
+	Narrative  string              `json:"narrative"`
+	NarratedBy string              `json:"narrated_by"` // NarratedByLLM or NarratedByTemplate
+	Aggregates StatementAggregates `json:"aggregates"`
+}
+
+// StatementAggregates are the figures a statement summary's narrative is grounded in
+type StatementAggregates struct {
+	Transactions int             `json:"transactions"`
+	Debits       int             `json:"debits"`
+	Credits      int             `json:"credits"`
+	TotalSpent   float64         `json:"total_spent"`
+	TotalIn      float64         `json:"total_in"`
+	Net          float64         `json:"net"` // Money in less money spent
+	TopPayees    []PayeeSpend    `json:"top_payees"`
+	Categories   []CategorySpend `json:"categories"` // Biggest spend first
+}
+
+// PayeeSpend is how much went to one payee in the period
+type PayeeSpend struct {
+	Payee  string  `json:"payee"`
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+}
+
+// CategorySpend is how much went on one spending category in the period
+type CategorySpend struct {
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+	Count    int     `json:"count"`
+	Share    float64 `json:"share"` // Percent of the period's spending
+}
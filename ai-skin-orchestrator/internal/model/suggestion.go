@@ -0,0 +1,10 @@
+package model
+
+// QuickReply is a suggestion chip a chat UI shows after a response. Tapping it sends
+// Message as the user's next input.
+type QuickReply struct {
+	Label      string     `json:"label"`
+	Message    string     `json:"message"`
+	Intent     IntentType `json:"intent"`                // What Message is parsed as
+	NeedsInput bool       `json:"needs_input,omitempty"` // Message is a start for the user to finish, not to be sent as is
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Transcript roles, granted per API key
+const (
+	TranscriptRoleSupport      = "support"      // Account numbers, phones, emails and UPI IDs are masked
+	TranscriptRoleInvestigator = "investigator" // Sees the conversation as it was recorded
+)
+
+// Transcript entry kinds
+const (
+	TranscriptMessage = "message" // What the user said
+	TranscriptIntent  = "intent"  // What the parser understood
+	TranscriptTask    = "task"    // A task submitted to the MCP server
+	TranscriptOutcome = "outcome" // What an agent or tool answered
+	TranscriptStatus  = "status"  // How the request ended
+	TranscriptReply   = "reply"   // What the user was told
+)
+
+// Transcript is a session's conversation and the decisions made in it, oldest first
+type Transcript struct {
+	SessionID   string            `json:"session_id"`
+	UserID      string            `json:"user_id"`
+	Channel     string            `json:"channel,omitempty"`
+	Role        string            `json:"role"`
+	Masked      bool              `json:"masked"`
+	Turns       int               `json:"turns"` // Requests recorded, one per intent of a /process message or per /chat message
+	Entries     []TranscriptEntry `json:"entries"`
+	Warnings    []string          `json:"warnings,omitempty"` // Parts that could not be read, such as current task statuses
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// TranscriptEntry is one line of a transcript. Turn is the request it belongs to,
+// from 1; tasks the AI Skin has no record of have none.
+type TranscriptEntry struct {
+	At   time.Time `json:"at"`
+	Kind string    `json:"kind"`
+	Turn int       `json:"turn,omitempty"`
+
+	Text string `json:"text,omitempty"` // Message or reply
+
+	// Intent entries
+	Intent     IntentType             `json:"intent,omitempty"`
+	Confidence float64                `json:"confidence,omitempty"`
+	ParsedBy   string                 `json:"parsed_by,omitempty"`
+	Entities   map[string]interface{} `json:"entities,omitempty"`
+
+	// Task and outcome entries
+	TaskID        string  `json:"task_id,omitempty"`
+	AgentType     string  `json:"agent_type,omitempty"`
+	RiskScore     float64 `json:"risk_score,omitempty"`
+	Explanation   string  `json:"explanation,omitempty"`
+	CurrentStatus string  `json:"current_status,omitempty"` // The task's status now, when it had not finished when the user was answered
+
+	// Outcome and status entries
+	Status      string     `json:"status,omitempty"`
+	Fallback    bool       `json:"fallback,omitempty"`
+	ChallengeID string     `json:"challenge_id,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UserDataExport is everything the AI Skin holds about one user, for a data-subject
+// access request
+type UserDataExport struct {
+	UserID         string              `json:"user_id"`
+	ExportedAt     time.Time           `json:"exported_at"`
+	Documents      []Document          `json:"documents"` // Conversations and transactions, without embeddings
+	MemoryFacts    []MemoryFact        `json:"memory_facts"`
+	MemorySettings MemorySettings      `json:"memory_settings"`
+	Decisions      []Decision          `json:"decisions"`
+	Events         []ConversationEvent `json:"conversation_events"`
+}
+
+// UserDataErasure counts what was erased for one user
+type UserDataErasure struct {
+	UserID      string    `json:"user_id"`
+	ErasedAt    time.Time `json:"erased_at"`
+	Documents   int       `json:"documents"`
+	MemoryFacts int       `json:"memory_facts"`
+	Decisions   int       `json:"decisions"`
+	Events      int       `json:"conversation_events"`
+}
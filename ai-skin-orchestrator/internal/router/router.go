@@ -9,17 +9,62 @@ import (
 // Router sets up all routes
 type Router struct {
 	orchestratorController *controller.OrchestratorController
+	promptController       *controller.PromptController
+	llmController          *controller.LLMController
+	ragController          *controller.RAGController
+	knowledgeController    *controller.KnowledgeController
+	memoryController       *controller.MemoryController
+	nluController          *controller.NLUController
+	retentionController    *controller.RetentionController
+	userDataController     *controller.UserDataController
+	capabilityController   *controller.CapabilityController
+	analyticsController    *controller.AnalyticsController
+	transcriptController   *controller.TranscriptController
+	llmJobController       *controller.LLMJobController
 	rateLimiter            *middleware.RateLimiter
+	recovery               *middleware.Recovery
+	accessLog              *middleware.AccessLog
+	adminAuth              *middleware.AdminAuth
 }
 
 // NewRouter creates a new router instance
 func NewRouter(
 	orchestratorController *controller.OrchestratorController,
+	promptController *controller.PromptController,
+	llmController *controller.LLMController,
+	ragController *controller.RAGController,
+	knowledgeController *controller.KnowledgeController,
+	memoryController *controller.MemoryController,
+	nluController *controller.NLUController,
+	retentionController *controller.RetentionController,
+	userDataController *controller.UserDataController,
+	capabilityController *controller.CapabilityController,
+	analyticsController *controller.AnalyticsController,
+	transcriptController *controller.TranscriptController,
+	llmJobController *controller.LLMJobController,
 	rateLimiter *middleware.RateLimiter,
+	recovery *middleware.Recovery,
+	accessLog *middleware.AccessLog,
+	adminAuth *middleware.AdminAuth,
 ) *Router {
 	return &Router{
 		orchestratorController: orchestratorController,
+		promptController:       promptController,
+		llmController:          llmController,
+		ragController:          ragController,
+		knowledgeController:    knowledgeController,
+		memoryController:       memoryController,
+		nluController:          nluController,
+		retentionController:    retentionController,
+		userDataController:     userDataController,
+		capabilityController:   capabilityController,
+		analyticsController:    analyticsController,
+		transcriptController:   transcriptController,
+		llmJobController:       llmJobController,
 		rateLimiter:            rateLimiter,
+		recovery:               recovery,
+		accessLog:              accessLog,
+		adminAuth:              adminAuth,
 	}
 }
 
@@ -33,12 +78,111 @@ func (r *Router) SetupRoutes() *mux.Router {
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/process", r.orchestratorController.ProcessRequest).Methods("POST")
+	api.HandleFunc("/auth/challenges/{challengeID}/{method}", r.orchestratorController.VerifyAuth).Methods("POST")
+	api.HandleFunc("/splits/{splitID}/confirm", r.orchestratorController.ConfirmSplit).Methods("POST")
+	api.HandleFunc("/splits/{splitID}/decline", r.orchestratorController.DeclineSplit).Methods("POST")
+	api.HandleFunc("/chat", r.orchestratorController.Chat).Methods("POST")
+	api.HandleFunc("/chat/stream", r.orchestratorController.ChatStream).Methods("POST")
+	api.HandleFunc("/admin/requests/cancelled", r.orchestratorController.GetCancellations).Methods("GET")
+	api.HandleFunc("/admin/requests/coalesced", r.orchestratorController.GetCoalescing).Methods("GET")
+	api.HandleFunc("/capabilities", r.capabilityController.GetCapabilities).Methods("GET")
 
-	// Apply middleware (CORS first)
+	// LLM settings routes
+	api.HandleFunc("/llm/options", r.llmController.GetOptions).Methods("GET")
+	api.HandleFunc("/session/{sessionID}", r.orchestratorController.GetSession).Methods("GET")
+	api.HandleFunc("/session/{sessionID}/llm", r.llmController.GetSessionOverrides).Methods("GET")
+	api.HandleFunc("/session/{sessionID}/llm", r.llmController.SetSessionOverrides).Methods("PUT")
+	api.HandleFunc("/session/{sessionID}/llm", r.llmController.ClearSessionOverrides).Methods("DELETE")
+
+	// Session transcripts for support staff
+	api.HandleFunc("/sessions/{sessionID}/transcript", r.transcriptController.GetTranscript).Methods("GET")
+
+	// Ollama model management routes
+	api.HandleFunc("/admin/llm/status", r.llmController.GetReadiness).Methods("GET")
+	api.HandleFunc("/admin/llm/errors", r.llmController.GetCallStats).Methods("GET")
+	api.HandleFunc("/admin/llm/models", r.llmController.ListModels).Methods("GET")
+	api.HandleFunc("/admin/llm/models/pull", r.llmController.PullModel).Methods("POST")
+	api.HandleFunc("/admin/llm/models/{name}/status", r.llmController.GetModelStatus).Methods("GET")
+
+	// Background LLM job routes
+	api.HandleFunc("/llm/jobs", r.llmJobController.SubmitJob).Methods("POST")
+	api.HandleFunc("/llm/jobs", r.llmJobController.ListJobs).Methods("GET")
+	api.HandleFunc("/llm/jobs/{jobID}", r.llmJobController.GetJob).Methods("GET")
+	api.HandleFunc("/llm/jobs/{jobID}", r.llmJobController.CancelJob).Methods("DELETE")
+	api.HandleFunc("/admin/llm/jobs", r.llmJobController.GetStats).Methods("GET")
+
+	// Retrieval routes
+	api.HandleFunc("/rag/search", r.ragController.Search).Methods("POST")
+	api.HandleFunc("/rag/documents/{documentID}", r.ragController.GetDocument).Methods("GET")
+	api.HandleFunc("/admin/rag/stats", r.ragController.GetStats).Methods("GET")
+	api.HandleFunc("/admin/rag/reindex", r.ragController.StartReindex).Methods("POST")
+	api.HandleFunc("/admin/rag/reindex", r.ragController.GetReindex).Methods("GET")
+	api.HandleFunc("/admin/rag/reindex", r.ragController.CancelReindex).Methods("DELETE")
+	api.HandleFunc("/admin/rag/reindex/pause", r.ragController.PauseReindex).Methods("POST")
+	api.HandleFunc("/admin/rag/reindex/resume", r.ragController.ResumeReindex).Methods("POST")
+
+	// Policy Q&A routes
+	api.HandleFunc("/knowledge/query", r.knowledgeController.Query).Methods("POST")
+	api.HandleFunc("/admin/knowledge/documents", r.knowledgeController.AddDocument).Methods("POST")
+
+	// Long-term memory routes
+	api.HandleFunc("/users/{userID}/memory", r.memoryController.ListMemory).Methods("GET")
+	api.HandleFunc("/users/{userID}/memory", r.memoryController.ClearMemory).Methods("DELETE")
+	api.HandleFunc("/users/{userID}/memory/settings", r.memoryController.GetSettings).Methods("GET")
+	api.HandleFunc("/users/{userID}/memory/settings", r.memoryController.UpdateSettings).Methods("PUT")
+	api.HandleFunc("/users/{userID}/memory/{memoryID}", r.memoryController.DeleteMemory).Methods("DELETE")
+
+	// Prompt template admin routes
+	api.HandleFunc("/admin/prompts", r.promptController.ListPrompts).Methods("GET")
+	api.HandleFunc("/admin/prompts/{name}", r.promptController.GetPrompt).Methods("GET")
+	api.HandleFunc("/admin/prompts/{name}/preview", r.promptController.PreviewPrompt).Methods("POST")
+
+	// Intent parser evaluation and few-shot examples
+	api.HandleFunc("/admin/nlu/eval", r.nluController.Evaluate).Methods("GET")
+	api.HandleFunc("/admin/nlu/examples", r.nluController.ListExamples).Methods("GET")
+	api.HandleFunc("/admin/nlu/examples", r.nluController.AddExample).Methods("POST")
+	api.HandleFunc("/admin/nlu/examples/select", r.nluController.SelectExamples).Methods("POST")
+	api.HandleFunc("/admin/nlu/examples/{exampleID}", r.nluController.GetExample).Methods("GET")
+	api.HandleFunc("/admin/nlu/examples/{exampleID}", r.nluController.UpdateExample).Methods("PUT")
+	api.HandleFunc("/admin/nlu/examples/{exampleID}", r.nluController.DeleteExample).Methods("DELETE")
+
+	// Data retention admin routes
+	api.HandleFunc("/admin/retention/policies", r.retentionController.GetPolicies).Methods("GET")
+	api.HandleFunc("/admin/retention/reports", r.retentionController.GetReports).Methods("GET")
+	api.HandleFunc("/admin/retention/holds", r.retentionController.GetHolds).Methods("GET")
+	api.HandleFunc("/admin/retention/holds/{userID}", r.retentionController.PlaceHold).Methods("PUT")
+
+	// Data-subject request routes
+	api.HandleFunc("/admin/users/{userID}/data", r.userDataController.ExportUserData).Methods("GET")
+
+	// Conversation analytics routes
+	api.HandleFunc("/admin/analytics/conversations", r.analyticsController.GetConversations).Methods("GET")
+	api.HandleFunc("/admin/analytics/users/{userID}", r.analyticsController.GetUserConversations).Methods("GET")
+
+	// Panics recovered, per route and by fingerprint
+	api.HandleFunc("/admin/panics", r.recovery.Stats).Methods("GET")
+
+	// Admin-only routes (admin role required)
+	adminOnly := api.PathPrefix("/admin").Subrouter()
+	adminOnly.Use(r.adminAuth.Middleware)
+	adminOnly.HandleFunc("/prompts/{name}/versions", r.promptController.AddVersion).Methods("POST")
+	adminOnly.HandleFunc("/prompts/{name}/activate", r.promptController.ActivatePrompt).Methods("POST")
+	adminOnly.HandleFunc("/retention/purge", r.retentionController.Purge).Methods("POST")
+	adminOnly.HandleFunc("/retention/holds/{userID}", r.retentionController.ReleaseHold).Methods("DELETE")
+	adminOnly.HandleFunc("/users/{userID}/data", r.userDataController.EraseUserData).Methods("DELETE")
+
+	// Apply middleware. Access records come first, so requests refused by any later
+	// middleware are recorded too.
+	router.Use(r.accessLog.Middleware)
+	router.NotFoundHandler = r.accessLog.NotFound()
+	router.MethodNotAllowedHandler = r.accessLog.MethodNotAllowed()
 	router.Use(middleware.CORSMiddleware)
 	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.CompressionMiddleware)
 	router.Use(middleware.AuthMiddleware)
 	router.Use(r.rateLimiter.RateLimitMiddleware)
+	// Last, so a panicking handler is answered before any middleware finishes its response
+	router.Use(r.recovery.Middleware)
 
 	return router
 }
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+)
+
+// confirmationWindow is how long a step-up challenge that does not say when it
+// expires is counted as open
+const confirmationWindow = 5 * time.Minute
+
+// Analytics groupings
+const (
+	GroupByUser    = "user"
+	GroupByTenant  = "tenant"
+	GroupByChannel = "channel"
+)
+
+// AnalyticsService records what users ask of the AI Skin and how each request ended,
+// and aggregates it per user and tenant: the intents asked for, how often no banking
+// intent was found, how many confirmations were abandoned and how much parsing the
+// LLM did rather than the rules
+type AnalyticsService struct {
+	events     []*model.ConversationEvent          // Oldest first
+	challenges map[string]*model.ConversationEvent // Keyed by challenge ID
+	tenants    map[string]string                   // Last tenant each user was seen with
+	maxEvents  int
+	mu         sync.RWMutex
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(cfg *config.AnalyticsConfig) *AnalyticsService {
+	maxEvents := cfg.MaxEvents
+	if maxEvents <= 0 {
+		maxEvents = 100000
+	}
+	return &AnalyticsService{
+		challenges: make(map[string]*model.ConversationEvent),
+		tenants:    make(map[string]string),
+		maxEvents:  maxEvents,
+	}
+}
+
+// RecordIntent records one intent of a /process message. Status is the decision
+// status of its response, or SKIPPED or FAILED when there is none.
+func (as *AnalyticsService) RecordIntent(req *model.UserRequest, intent *model.Intent, resp *model.MergedResponse, status string) {
+	event := &model.ConversationEvent{
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+		Channel:   req.Channel,
+		Kind:      model.EventIntent,
+		Intent:    intent.Type,
+		Status:    status,
+		Fallback:  intent.Type == model.IntentUnknown,
+		// A step of a message holding several requests is recorded with its own segment
+		Input:      intent.OriginalText,
+		Confidence: intent.Confidence,
+		Entities:   intent.Entities,
+		At:         time.Now(),
+	}
+	if event.Input == "" {
+		event.Input = req.Input
+	}
+	event.ParsedBy, _ = intent.Metadata["parsed_by"].(string)
+	if tenantID, ok := req.Context["tenant_id"].(string); ok {
+		event.TenantID = tenantID
+	}
+	if resp != nil {
+		event.ChallengeID, event.ChallengeExpiresAt = authChallenge(resp.AgentResponses)
+		event.Reply = resp.Explanation
+		for _, agent := range resp.AgentResponses {
+			event.Outcomes = append(event.Outcomes, model.AgentOutcome{
+				TaskID:      agent.TaskID,
+				AgentType:   agent.AgentType,
+				Status:      agent.Status,
+				RiskScore:   agent.RiskScore,
+				Explanation: agent.Explanation,
+				At:          agent.Timestamp,
+			})
+		}
+	}
+	as.add(event)
+}
+
+// RecordChat records a /chat message; resp and err are what answering it returned
+func (as *AnalyticsService) RecordChat(req *model.ChatRequest, resp *model.ChatResponse, err error) {
+	status := "COMPLETED"
+	if err != nil {
+		status = failureStatus(err)
+	}
+	event := &model.ConversationEvent{
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+		Channel:   req.Channel,
+		Kind:      model.EventChat,
+		Status:    status,
+		Input:     req.Message,
+		At:        time.Now(),
+	}
+	if resp != nil {
+		event.Reply = resp.Answer
+		for _, call := range resp.ToolCalls {
+			event.Outcomes = append(event.Outcomes, model.AgentOutcome{
+				TaskID:    call.TaskID,
+				AgentType: call.Name,
+				Status:    call.Status,
+				At:        event.At,
+			})
+		}
+	}
+	as.add(event)
+}
+
+// RecordConfirmation marks the request held on a step-up challenge as confirmed
+func (as *AnalyticsService) RecordConfirmation(challengeID string) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if event, ok := as.challenges[challengeID]; ok && event.ConfirmedAt == nil {
+		now := time.Now()
+		event.ConfirmedAt = &now
+	}
+}
+
+// add stores an event. Chat requests carry no context, so they take the tenant the
+// user was last seen with.
+func (as *AnalyticsService) add(event *model.ConversationEvent) {
+	event.ID = ids.New(ids.Event)
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if event.TenantID != "" {
+		as.tenants[event.UserID] = event.TenantID
+	} else {
+		event.TenantID = as.tenants[event.UserID]
+	}
+	if event.ChallengeID != "" {
+		as.challenges[event.ChallengeID] = event
+	}
+
+	as.events = append(as.events, event)
+	if len(as.events) > as.maxEvents {
+		as.drop(as.events[:len(as.events)-as.maxEvents])
+		as.events = as.events[len(as.events)-as.maxEvents:]
+	}
+}
+
+// drop forgets the challenges of events that are removed. Callers hold the lock.
+func (as *AnalyticsService) drop(events []*model.ConversationEvent) {
+	for _, event := range events {
+		if event.ChallengeID != "" {
+			delete(as.challenges, event.ChallengeID)
+		}
+	}
+}
+
+// authChallenge returns the step-up challenge an agent response is held on, if any
+func authChallenge(responses []model.AgentResponse) (string, *time.Time) {
+	for _, resp := range responses {
+		challenge, ok := resp.Result["auth_challenge"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := challenge["challenge_id"].(string)
+		if id == "" {
+			continue
+		}
+		if raw, ok := challenge["expires_at"].(string); ok {
+			if expiresAt, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+				return id, &expiresAt
+			}
+		}
+		return id, nil
+	}
+	return "", nil
+}
+
+// Report aggregates the events a query selects
+func (as *AnalyticsService) Report(q model.AnalyticsQuery) (*model.ConversationAnalytics, error) {
+	var keyOf func(*model.ConversationEvent) string
+	switch q.GroupBy {
+	case "":
+	case GroupByUser:
+		keyOf = func(e *model.ConversationEvent) string { return e.UserID }
+	case GroupByTenant:
+		keyOf = func(e *model.ConversationEvent) string { return e.TenantID }
+	case GroupByChannel:
+		keyOf = func(e *model.ConversationEvent) string { return e.Channel }
+	default:
+		return nil, fmt.Errorf("group_by must be %s, %s or %s", GroupByUser, GroupByTenant, GroupByChannel)
+	}
+
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	now := time.Now()
+	totals := newStatsBuilder()
+	groups := make(map[string]*statsBuilder)
+	for _, e := range as.events {
+		if e.At.Before(q.From) || !e.At.Before(q.To) {
+			continue
+		}
+		if (q.UserID != "" && e.UserID != q.UserID) || (q.TenantID != "" && e.TenantID != q.TenantID) {
+			continue
+		}
+		totals.add(e, now)
+		if keyOf == nil {
+			continue
+		}
+		key := keyOf(e)
+		if groups[key] == nil {
+			groups[key] = newStatsBuilder()
+		}
+		groups[key].add(e, now)
+	}
+
+	report := &model.ConversationAnalytics{
+		From:     q.From,
+		To:       q.To,
+		UserID:   q.UserID,
+		TenantID: q.TenantID,
+		GroupBy:  q.GroupBy,
+		Users:    len(totals.users),
+		Totals:   totals.stats(),
+	}
+	for key, b := range groups {
+		report.Groups = append(report.Groups, model.ConversationGroup{Key: key, Users: len(b.users), Stats: b.stats()})
+	}
+	sort.Slice(report.Groups, func(a, b int) bool {
+		ga, gb := report.Groups[a], report.Groups[b]
+		if ga.Stats.Requests != gb.Stats.Requests {
+			return ga.Stats.Requests > gb.Stats.Requests
+		}
+		return ga.Key < gb.Key
+	})
+	return report, nil
+}
+
+// statsBuilder accumulates the stats of a set of events
+type statsBuilder struct {
+	s     model.ConversationStats
+	users map[string]bool
+}
+
+func newStatsBuilder() *statsBuilder {
+	return &statsBuilder{
+		s:     model.ConversationStats{Intents: make(map[model.IntentType]int)},
+		users: make(map[string]bool),
+	}
+}
+
+func (b *statsBuilder) add(e *model.ConversationEvent, now time.Time) {
+	b.users[e.UserID] = true
+	b.s.Requests++
+	switch e.Status {
+	case "FAILED":
+		b.s.Failed++
+	case "CANCELLED":
+		b.s.Cancelled++
+	}
+	if e.Kind == model.EventChat {
+		b.s.ChatMessages++
+		return
+	}
+
+	b.s.Intents[e.Intent]++
+	if e.Fallback {
+		b.s.Fallbacks++
+	}
+	switch e.ParsedBy {
+	case model.ParsedByLLM:
+		b.s.Parsing.LLM++
+	case model.ParsedByRules:
+		b.s.Parsing.Rules++
+	case model.ParsedByStructured:
+		b.s.Parsing.Structured++
+	}
+
+	if e.ChallengeID == "" {
+		return
+	}
+	c := &b.s.Confirmations
+	c.Requested++
+	expiresAt := e.At.Add(confirmationWindow)
+	if e.ChallengeExpiresAt != nil {
+		expiresAt = *e.ChallengeExpiresAt
+	}
+	switch {
+	case e.ConfirmedAt != nil:
+		c.Confirmed++
+	case now.Before(expiresAt):
+		c.Open++
+	default:
+		c.Abandoned++
+	}
+}
+
+func (b *statsBuilder) stats() model.ConversationStats {
+	s := b.s
+	intents := s.Requests - s.ChatMessages
+	s.FallbackRate = ratio(s.Fallbacks, intents)
+	parsed := s.Parsing.LLM + s.Parsing.Rules
+	s.Parsing.LLMShare = ratio(s.Parsing.LLM, parsed)
+	s.Parsing.RuleShare = ratio(s.Parsing.Rules, parsed)
+	s.Confirmations.AbandonmentRate = ratio(s.Confirmations.Abandoned, s.Confirmations.Confirmed+s.Confirmations.Abandoned)
+	return s
+}
+
+// UserEvents returns the user's conversation events, oldest first
+func (as *AnalyticsService) UserEvents(userID string) []model.ConversationEvent {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	events := []model.ConversationEvent{}
+	for _, e := range as.events {
+		if e.UserID == userID {
+			events = append(events, *e)
+		}
+	}
+	return events
+}
+
+// SessionEvents returns the conversation events of a session, oldest first
+func (as *AnalyticsService) SessionEvents(sessionID string) []model.ConversationEvent {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	events := []model.ConversationEvent{}
+	for _, e := range as.events {
+		if e.SessionID == sessionID {
+			events = append(events, *e)
+		}
+	}
+	return events
+}
+
+// ForgetUser deletes the user's conversation events and returns how many there were
+func (as *AnalyticsService) ForgetUser(userID string) int {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	delete(as.tenants, userID)
+	return as.remove(func(e *model.ConversationEvent) bool { return e.UserID == userID })
+}
+
+// Purge deletes events recorded before cutoff and returns their IDs, with the number
+// kept back because their user is held
+func (as *AnalyticsService) Purge(cutoff time.Time, held func(userID string) bool, dryRun bool) ([]string, int) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	var purged []string
+	kept := 0
+	expired := func(e *model.ConversationEvent) bool {
+		if !e.At.Before(cutoff) {
+			return false
+		}
+		if held(e.UserID) {
+			kept++
+			return false
+		}
+		purged = append(purged, e.ID)
+		return true
+	}
+	if dryRun {
+		for _, e := range as.events {
+			expired(e)
+		}
+		return purged, kept
+	}
+	as.remove(expired)
+	return purged, kept
+}
+
+// remove deletes the events match picks and returns how many. Callers hold the lock.
+func (as *AnalyticsService) remove(match func(*model.ConversationEvent) bool) int {
+	remaining := as.events[:0]
+	var removed []*model.ConversationEvent
+	for _, e := range as.events {
+		if match(e) {
+			removed = append(removed, e)
+		} else {
+			remaining = append(remaining, e)
+		}
+	}
+	as.drop(removed)
+	// Clear the tail so removed events can be collected
+	for i := len(remaining); i < len(as.events); i++ {
+		as.events[i] = nil
+	}
+	as.events = remaining
+	return len(removed)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+// bankingTool is a read-only banking operation the LLM may call
+type bankingTool struct {
+	name        string
+	description string
+	parameters  map[string]interface{}
+	intent      model.IntentType
+}
+
+// BankingTools exposes banking operations to the LLM and executes its calls through MCP
+type BankingTools struct {
+	mcpClient *MCPClient
+	guard     *PromptGuard
+	tools     map[string]bankingTool
+	order     []string
+}
+
+// NewBankingTools creates the banking tool set
+func NewBankingTools(mcpClient *MCPClient, guard *PromptGuard) *BankingTools {
+	accountParam := map[string]interface{}{
+		"type":        "string",
+		"description": "Account ID; omit for the primary account",
+	}
+
+	tools := []bankingTool{
+		{
+			name:        "get_balance",
+			description: "Get the current and available balance of one of the customer's accounts.",
+			parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"account_id": accountParam},
+			},
+			intent: model.IntentCheckBalance,
+		},
+		{
+			name:        "list_beneficiaries",
+			description: "List the beneficiaries (payees) the customer has registered.",
+			parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+			intent: model.IntentListBeneficiaries,
+		},
+		{
+			name:        "get_statement",
+			description: "Get recent transactions on one of the customer's accounts.",
+			parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"account_id": accountParam,
+					"days": map[string]interface{}{
+						"type":        "integer",
+						"description": "How many days of history to return (1-90)",
+						"minimum":     1,
+						"maximum":     90,
+					},
+				},
+			},
+			intent: model.IntentGetStatement,
+		},
+		{
+			name:        "get_credit_score",
+			description: "Get the customer's credit score with the factors behind it and tips, biggest lift first, on how to improve it.",
+			parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+			intent: model.IntentCreditScore,
+		},
+	}
+
+	bt := &BankingTools{
+		mcpClient: mcpClient,
+		guard:     guard,
+		tools:     make(map[string]bankingTool),
+	}
+	for _, t := range tools {
+		bt.tools[t.name] = t
+		bt.order = append(bt.order, t.name)
+	}
+	return bt
+}
+
+// Definitions returns the tool schemas sent to the LLM
+func (bt *BankingTools) Definitions() []openai.Tool {
+	defs := make([]openai.Tool, 0, len(bt.order))
+	for _, name := range bt.order {
+		t := bt.tools[name]
+		defs = append(defs, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.name,
+				Description: t.description,
+				Parameters:  t.parameters,
+			},
+		})
+	}
+	return defs
+}
+
+// Execute runs one tool call for the user and returns the invocation record and
+// the content to feed back to the LLM. Tool failures are reported to the LLM, not returned.
+func (bt *BankingTools) Execute(ctx context.Context, req *model.UserRequest, call openai.ToolCall) (model.ToolInvocation, string) {
+	inv := model.ToolInvocation{Name: call.Function.Name, Arguments: map[string]interface{}{}}
+
+	tool, ok := bt.tools[call.Function.Name]
+	if !ok {
+		inv.Status = "ERROR"
+		inv.Error = fmt.Sprintf("unknown tool %q", call.Function.Name)
+		return inv, toolError(inv.Error)
+	}
+
+	if call.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &inv.Arguments); err != nil {
+			inv.Status = "ERROR"
+			inv.Error = "arguments are not a JSON object"
+			return inv, toolError(inv.Error)
+		}
+	}
+
+	return bt.run(ctx, req, tool, inv)
+}
+
+// ExecuteIntent runs the read-only tool behind an intent without the LLM, passing the
+// entities the tool declares. It reports false when no tool serves the intent.
+func (bt *BankingTools) ExecuteIntent(ctx context.Context, req *model.UserRequest, intent *model.Intent) (model.ToolInvocation, bool) {
+	for _, name := range bt.order {
+		tool := bt.tools[name]
+		if tool.intent != intent.Type {
+			continue
+		}
+
+		inv := model.ToolInvocation{Name: tool.name, Arguments: map[string]interface{}{}}
+		props, _ := tool.parameters["properties"].(map[string]interface{})
+		for key, value := range intent.Entities {
+			if _, ok := props[key]; ok {
+				inv.Arguments[key] = value
+			}
+		}
+
+		inv, _ = bt.run(ctx, req, tool, inv)
+		return inv, true
+	}
+	return model.ToolInvocation{}, false
+}
+
+// run validates a tool's arguments and executes it through MCP
+func (bt *BankingTools) run(ctx context.Context, req *model.UserRequest, tool bankingTool, inv model.ToolInvocation) (model.ToolInvocation, string) {
+	if err := validateToolArgs(tool, inv.Arguments); err != nil {
+		inv.Status = "ERROR"
+		inv.Error = err.Error()
+		return inv, toolError(inv.Error)
+	}
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("tool", tool.name).
+		Msg("Executing LLM tool call")
+
+	resp, err := bt.mcpClient.ExecuteIntent(ctx, req, tool.intent, inv.Arguments)
+	var busy *MCPBusyError
+	if errors.As(err, &busy) {
+		inv.Status = "BUSY"
+		inv.Error = err.Error()
+		return inv, toolError("the banking service is busy; tell the customer to try again in a minute")
+	}
+	var refusal *IntentDisabledError
+	if errors.As(err, &refusal) {
+		inv.Status = "DISABLED"
+		inv.Error = err.Error()
+		return inv, toolError("this service is switched off; tell the customer: " + switchedOffMessage(refusal))
+	}
+	if err != nil {
+		inv.Status = "ERROR"
+		inv.Error = err.Error()
+		return inv, toolError("the banking service is unavailable")
+	}
+
+	inv.TaskID = resp.TaskID
+	inv.Status = resp.Status
+	inv.Result = resp.Result
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"status": resp.Status,
+		"result": resp.Result,
+	})
+
+	// Results can echo user-controlled text (names, remarks), so treat them as untrusted
+	return inv, bt.guard.SanitizeRetrieved("tool:"+tool.name, string(payload)).Text
+}
+
+// validateToolArgs rejects arguments the tool schema does not declare or that have the wrong type
+func validateToolArgs(tool bankingTool, args map[string]interface{}) error {
+	props, _ := tool.parameters["properties"].(map[string]interface{})
+	for key, value := range args {
+		spec, ok := props[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected argument %q for %s", key, tool.name)
+		}
+
+		switch spec["type"] {
+		case "string":
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("argument %q must be a string", key)
+			}
+		case "integer":
+			n, ok := value.(float64)
+			if !ok || n != float64(int(n)) {
+				return fmt.Errorf("argument %q must be an integer", key)
+			}
+			if min, ok := spec["minimum"].(int); ok && n < float64(min) {
+				return fmt.Errorf("argument %q must be at least %d", key, min)
+			}
+			if max, ok := spec["maximum"].(int); ok && n > float64(max) {
+				return fmt.Errorf("argument %q must be at most %d", key, max)
+			}
+		}
+	}
+	return nil
+}
+
+// toolError formats an error message returned to the LLM as the tool result
+func toolError(msg string) string {
+	payload, _ := json.Marshal(map[string]string{"error": msg})
+	return string(payload)
+}
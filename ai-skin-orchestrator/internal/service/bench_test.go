@@ -0,0 +1,59 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog"
+)
+
+// budgetEnv turns the latency budgets of the hot-path benchmarks into failures, as
+// loadtest/smoke-perf.sh does
+const budgetEnv = "BENCH_BUDGETS"
+
+// budgetMinElapsed is how long a benchmark run must take for its time per operation to
+// be held to a budget; the short warm-up runs go test makes first are not
+const budgetMinElapsed = 10 * time.Millisecond
+
+func TestMain(m *testing.M) {
+	// Keep service logging out of the measurements
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+// withinBudget fails a benchmark whose time per operation exceeds its budget, when
+// BENCH_BUDGETS is set
+func withinBudget(b *testing.B, budget time.Duration) {
+	b.Helper()
+	if os.Getenv(budgetEnv) == "" || b.Elapsed() < budgetMinElapsed {
+		return
+	}
+	if perOp := b.Elapsed() / time.Duration(b.N); perOp > budget {
+		b.Fatalf("%s/op is over the %s budget", perOp, budget)
+	}
+}
+
+// syntheticResponse builds an agent response used as merge input
+func syntheticResponse(agentType, status string, riskScore float64) model.AgentResponse {
+	return model.AgentResponse{
+		AgentID:     agentType + "-bench",
+		AgentType:   agentType,
+		Status:      status,
+		Result:      map[string]interface{}{"status": status, "agent": agentType},
+		RiskScore:   riskScore,
+		Explanation: "synthetic response",
+		Confidence:  0.9,
+		Timestamp:   time.Now(),
+	}
+}
+
+// benchIntent is the transfer the enrichment, merge and guard benchmarks work on
+var benchIntent = model.Intent{
+	Type:       model.IntentTransferNEFT,
+	Confidence: 0.9,
+	Entities:   map[string]interface{}{"amount": "50000", "to_account": "XXXX4321"},
+}
+
+const benchTransferInput = "Transfer 50000 rupees to account number XXXX4321 using NEFT"
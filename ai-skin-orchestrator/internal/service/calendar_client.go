@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// CalendarClient asks Banking Integrations (Layer 5) when a transfer will settle
+type CalendarClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewCalendarClient creates a new calendar client
+func NewCalendarClient(cfg *config.BankingIntegrationsConfig) *CalendarClient {
+	return &CalendarClient{
+		baseURL: cfg.BaseURL,
+		apiKey:  config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// EstimateSettlement returns when a transfer on rail made at the given time will be credited
+func (cc *CalendarClient) EstimateSettlement(ctx context.Context, rail string, at time.Time) (*model.SettlementEstimate, error) {
+	query := url.Values{"rail": {rail}, "at": {at.Format(time.RFC3339)}}
+	endpoint := fmt.Sprintf("%s/api/v1/calendar/settlement?%s", cc.baseURL, query.Encode())
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-API-Key", cc.apiKey.Get())
+
+	resp, err := cc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement estimate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("banking integrations error: %s", string(body))
+	}
+
+	var estimate model.SettlementEstimate
+	if err := json.Unmarshal(body, &estimate); err != nil {
+		return nil, fmt.Errorf("failed to parse settlement estimate: %w", err)
+	}
+	return &estimate, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// Outcomes of asking the MCP server to cancel an abandoned request's task
+const (
+	taskCancelOK      = "cancelled"
+	taskCancelRefused = "refused"
+	taskCancelFailed  = "failed"
+)
+
+// CancelledError is a request abandoned by its client. It records the stage the
+// request was in and, once a task was submitted, what became of the task.
+type CancelledError struct {
+	Stage      string
+	TaskID     string
+	TaskCancel string // taskCancelOK, taskCancelRefused or taskCancelFailed; empty without a task
+	Err        error
+}
+
+func (e *CancelledError) Error() string {
+	if e.TaskID != "" {
+		return fmt.Sprintf("request cancelled during %s (task %s %s): %v", e.Stage, e.TaskID, e.TaskCancel, e.Err)
+	}
+	return fmt.Sprintf("request cancelled during %s: %v", e.Stage, e.Err)
+}
+
+func (e *CancelledError) Unwrap() error {
+	return e.Err
+}
+
+// cancelledAt wraps err as a cancellation in stage when the caller gave up, and returns
+// it unchanged otherwise, including when it already names its stage
+func cancelledAt(ctx context.Context, stage string, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+	var cancelled *CancelledError
+	if errors.As(err, &cancelled) {
+		return err
+	}
+	return &CancelledError{Stage: stage, Err: err}
+}
+
+// CancellationTracker counts requests abandoned by their clients, by endpoint and by
+// the stage they were in
+type CancellationTracker struct {
+	mu    sync.Mutex
+	stats model.CancellationStats
+}
+
+// NewCancellationTracker creates an empty cancellation tracker
+func NewCancellationTracker() *CancellationTracker {
+	return &CancellationTracker{
+		stats: model.CancellationStats{
+			ByEndpoint: map[string]int64{},
+			ByStage:    map[string]int64{},
+		},
+	}
+}
+
+// Record counts err against endpoint if it is a client cancellation and reports whether
+// it was. Cancellations that do not name their stage are counted under stage.
+func (ct *CancellationTracker) Record(endpoint, stage string, err error) bool {
+	if !errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var cancelled *CancelledError
+	taskCancel := ""
+	if errors.As(err, &cancelled) {
+		stage = cancelled.Stage
+		taskCancel = cancelled.TaskCancel
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	ct.stats.Total++
+	ct.stats.ByEndpoint[endpoint]++
+	ct.stats.ByStage[stage]++
+	switch taskCancel {
+	case taskCancelOK:
+		ct.stats.MCPTasks.Cancelled++
+	case taskCancelRefused:
+		ct.stats.MCPTasks.Refused++
+	case taskCancelFailed:
+		ct.stats.MCPTasks.Failed++
+	}
+	return true
+}
+
+// Stats returns a copy of the counts
+func (ct *CancellationTracker) Stats() model.CancellationStats {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	stats := ct.stats
+	stats.ByEndpoint = make(map[string]int64, len(ct.stats.ByEndpoint))
+	for endpoint, n := range ct.stats.ByEndpoint {
+		stats.ByEndpoint[endpoint] = n
+	}
+	stats.ByStage = make(map[string]int64, len(ct.stats.ByStage))
+	for stage, n := range ct.stats.ByStage {
+		stats.ByStage[stage] = n
+	}
+	return stats
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// capabilityEntry describes an intent and the agents it needs. The agent types mirror
+// the MCP server's intent routing: agentType executes the intent and checks may vet it
+// first. An empty agentType means the AI Skin handles the intent itself.
+type capabilityEntry struct {
+	intent      model.IntentType
+	feature     string
+	description string
+	example     string
+	agentType   string
+	checks      []string
+}
+
+// capabilityCatalog is in SupportedCapabilities order
+var capabilityCatalog = []capabilityEntry{
+	{model.IntentTransferNEFT, "transfers", "Transfer money by NEFT", "Send 5000 to Ravi by NEFT", "BANKING", []string{"GUARDRAIL", "FRAUD"}},
+	{model.IntentTransferRTGS, "transfers", "Transfer money by RTGS", "Send 3 lakh to account 123456789012 by RTGS", "BANKING", []string{"GUARDRAIL", "FRAUD"}},
+	{model.IntentTransferIMPS, "transfers", "Transfer money instantly by IMPS", "Send 2000 to Priya by IMPS", "BANKING", []string{"GUARDRAIL", "FRAUD"}},
+	{model.IntentTransferUPI, "transfers", "Pay a UPI ID", "Pay 500 to ravi@upi", "BANKING", []string{"GUARDRAIL", "FRAUD"}},
+	{model.IntentCheckBalance, "balance", "Check your account balance", "Check my balance", "BANKING", nil},
+	{model.IntentGetStatement, "statements", "View your account statement", "Show my statement for last month", "BANKING", nil},
+	{model.IntentSummarizeStatement, "statements", "Summarize your spending", "Summarize my spending last month", "BANKING", nil},
+	{model.IntentAddBeneficiary, "beneficiaries", "Add a beneficiary", "Add Ravi as a beneficiary", "GUARDRAIL", nil},
+	{model.IntentListBeneficiaries, "beneficiaries", "List your beneficiaries", "Show my beneficiaries", "BANKING", nil},
+	{model.IntentRequestMoney, "payment_requests", "Request money over UPI", "Ask Ravi for 500", "BANKING", nil},
+	{model.IntentShareReceipt, "receipts", "Share a receipt for a transfer", "Share the receipt for my last transfer", "BANKING", nil},
+	{model.IntentExportTransactions, "exports", "Export your transactions to a finance or accounting tool", "Export last month's transactions as OFX", "BANKING", nil},
+	{model.IntentSetBudget, "budgets", "Get alerts when your spending nears a monthly limit", "Alert me when I spend over 50,000 a month", "BANKING", nil},
+	{model.IntentBudgetStatus, "budgets", "See how much of your budget is left", "How am I doing on my budget?", "BANKING", nil},
+	{model.IntentApplyLoan, "loans", "Apply for a loan", "I want a personal loan of 2 lakh", "CLEARANCE", nil},
+	{model.IntentCreditScore, "credit_score", "Check your credit score", "What is my credit score?", "SCORING", nil},
+	{model.IntentGetInsights, "insights", "Get suggestions to grow your savings", "How can I save more?", "INSIGHTS", nil},
+	{model.IntentSetPreference, "preferences", "Save your payment preferences", "Always use IMPS for transfers", "", nil},
+	{model.IntentWhyRejected, "explanations", "Explain the last decision", "Why was it rejected?", "", nil},
+	{model.IntentRetryLast, "retry", "Retry the last action with changes", "Send 50,000 instead", "", nil},
+}
+
+// registryTimeout bounds a single agent registry lookup
+const registryTimeout = 3 * time.Second
+
+// CapabilityService reports what the platform can do right now, from the MCP agent
+// registry. The registry is cached for a short TTL; while the MCP server cannot be
+// reached the last known report is served marked stale.
+type CapabilityService struct {
+	mcpClient *MCPClient
+	llm       *LLMService
+	flags     *IntentFlags
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	agents    []model.CapabilityAgent
+	fetched   bool
+	fetchedAt time.Time
+	lastErr   error
+}
+
+// NewCapabilityService creates a new capability service
+func NewCapabilityService(cfg *config.MCPServerConfig, mcpClient *MCPClient, llm *LLMService, flags *IntentFlags) *CapabilityService {
+	ttl := time.Duration(cfg.CapabilitiesCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	return &CapabilityService{
+		mcpClient: mcpClient,
+		llm:       llm,
+		flags:     flags,
+		ttl:       ttl,
+	}
+}
+
+// GetCapabilities composes the capabilities report from the agent registry
+func (cs *CapabilityService) GetCapabilities(ctx context.Context) *model.Capabilities {
+	agents, source := cs.registry(ctx)
+
+	report := &model.Capabilities{
+		Capabilities: make([]model.Capability, 0, len(capabilityCatalog)),
+		Agents:       agents,
+		Features:     make(map[string]bool),
+		Source:       source,
+		Stale:        source == "cache",
+		GeneratedAt:  time.Now(),
+	}
+	if report.Agents == nil {
+		report.Agents = []model.CapabilityAgent{}
+	}
+
+	health := agentHealth(agents)
+	switchedOff := cs.flags.Disabled(ctx)
+	for _, entry := range capabilityCatalog {
+		c := model.Capability{
+			Intent:      string(entry.intent),
+			Feature:     entry.feature,
+			Description: entry.description,
+			Example:     entry.example,
+			AgentType:   entry.agentType,
+			Checks:      entry.checks,
+		}
+		switch {
+		case entry.agentType == "":
+			c.Status = model.CapabilityAvailable
+		case source == "static":
+			c.Status = model.CapabilityUnknown
+			c.Reason = "Agent registry is unreachable"
+		default:
+			c.Status, c.Reason = capabilityStatus(entry, health)
+		}
+		// An intent switched off for everyone is unavailable whatever its agents' health;
+		// a read-only one only if it changes something
+		if flag, ok := switchedOff[entry.intent]; ok && (flag.Mode == model.IntentFlagDisabled || !readIntents[entry.intent]) {
+			c.Status = model.CapabilityUnavailable
+			c.Reason = "Switched off by an intent flag"
+		}
+		report.Capabilities = append(report.Capabilities, c)
+
+		// A feature stays on unless every one of its intents is known to be unavailable
+		if c.Status != model.CapabilityUnavailable {
+			report.Features[entry.feature] = true
+		} else if _, ok := report.Features[entry.feature]; !ok {
+			report.Features[entry.feature] = false
+		}
+	}
+	report.Features["chat"] = cs.llm != nil && cs.llm.Enabled()
+
+	return report
+}
+
+// Available returns the capabilities that can be used right now, or whose state is unknown
+func (cs *CapabilityService) Available(ctx context.Context) []model.Capability {
+	var available []model.Capability
+	for _, c := range cs.GetCapabilities(ctx).Capabilities {
+		if c.Status != model.CapabilityUnavailable {
+			available = append(available, c)
+		}
+	}
+	return available
+}
+
+// HelpText suggests what the user can ask for: a short list for the error and example
+// phrases for the explanation, one per agent-backed feature that is available
+func (cs *CapabilityService) HelpText(ctx context.Context) (string, string) {
+	var labels, examples []string
+	seen := make(map[string]bool)
+	for _, c := range cs.Available(ctx) {
+		if c.AgentType == "" || seen[c.Feature] {
+			continue
+		}
+		seen[c.Feature] = true
+		labels = append(labels, strings.ToLower(c.Description[:1])+c.Description[1:])
+		examples = append(examples, fmt.Sprintf("'%s'", c.Example))
+	}
+
+	if len(labels) == 0 {
+		return "Could not understand your request, and no banking services are available right now. Please try again later.",
+			"I couldn't determine what you're asking for, and our banking services are unavailable at the moment."
+	}
+	return "Could not understand your request. Please try rephrasing or use one of these: " + strings.Join(labels, ", ") + ".",
+		"I couldn't determine what you're asking for. Please try phrases like " + joinOr(examples) + "."
+}
+
+// registry returns the registered agents and where they came from: mcp, cache when the
+// registry failed and an earlier answer is served, or static when there is none
+func (cs *CapabilityService) registry(ctx context.Context) ([]model.CapabilityAgent, string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if !cs.fetchedAt.IsZero() && time.Since(cs.fetchedAt) < cs.ttl {
+		return cs.agents, cs.cachedSource()
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	agents, err := cs.mcpClient.ListAgents(lookupCtx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// The caller went away; that says nothing about the registry
+			return cs.agents, cs.cachedSource()
+		}
+		log.Warn().Err(err).Msg("Failed to read the agent registry, serving last known capabilities")
+		// Hold on to the failure for a TTL so a down MCP server is not asked on every request
+		cs.lastErr = err
+		cs.fetchedAt = time.Now()
+		return cs.agents, cs.cachedSource()
+	}
+
+	cs.agents = agents
+	cs.fetched = true
+	cs.fetchedAt = time.Now()
+	cs.lastErr = nil
+	return cs.agents, "mcp"
+}
+
+// cachedSource names the source of the cached registry; callers hold cs.mu
+func (cs *CapabilityService) cachedSource() string {
+	switch {
+	case cs.lastErr == nil:
+		return "mcp"
+	case cs.fetched:
+		return "cache"
+	}
+	return "static"
+}
+
+// agentHealth maps each agent type to its best agent status
+func agentHealth(agents []model.CapabilityAgent) map[string]string {
+	rank := map[string]int{"HEALTHY": 2, "DEGRADED": 1}
+	health := make(map[string]string)
+	for _, a := range agents {
+		if current, ok := health[a.Type]; !ok || rank[a.Status] > rank[current] {
+			health[a.Type] = a.Status
+		}
+	}
+	return health
+}
+
+// capabilityStatus rates an intent by the agents it needs
+func capabilityStatus(entry capabilityEntry, health map[string]string) (string, string) {
+	switch health[entry.agentType] {
+	case "HEALTHY":
+	case "DEGRADED":
+		return model.CapabilityDegraded, fmt.Sprintf("%s agents are degraded", entry.agentType)
+	case "":
+		return model.CapabilityUnavailable, fmt.Sprintf("No %s agent is registered", entry.agentType)
+	default:
+		return model.CapabilityUnavailable, fmt.Sprintf("No healthy %s agent is registered", entry.agentType)
+	}
+
+	var missing []string
+	for _, check := range entry.checks {
+		if health[check] != "HEALTHY" {
+			missing = append(missing, check)
+		}
+	}
+	if len(missing) > 0 {
+		return model.CapabilityDegraded, fmt.Sprintf("No healthy %s agent for pre-checks", strings.Join(missing, " or "))
+	}
+	return model.CapabilityAvailable, ""
+}
+
+// joinOr joins phrases as "a, b, or c"
+func joinOr(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + ", or " + items[len(items)-1]
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/tz"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+// PromptChatAssistant is the system prompt for tool-calling chat
+const PromptChatAssistant = "chat_assistant"
+
+// ErrLLMDisabled is returned when a feature needs the LLM and it is not configured
+var ErrLLMDisabled = errors.New("LLM service is disabled")
+
+// ChatService answers conversational questions with an LLM that can call banking tools
+type ChatService struct {
+	llmService    *LLMService
+	prompts       *PromptService
+	promptGuard   *PromptGuard
+	responseGuard *ResponseGuard
+	tools         *BankingTools
+	ragService    *RAGService
+	memory        *MemoryService
+	intentParser  *IntentParser
+	analytics     *AnalyticsService
+	maxIterations int
+}
+
+// NewChatService creates a new chat service
+func NewChatService(llmService *LLMService, prompts *PromptService, promptGuard *PromptGuard, responseGuard *ResponseGuard, tools *BankingTools, ragService *RAGService, memory *MemoryService, intentParser *IntentParser, analytics *AnalyticsService, maxIterations int) *ChatService {
+	if maxIterations <= 0 {
+		maxIterations = 5
+	}
+	return &ChatService{
+		llmService:    llmService,
+		prompts:       prompts,
+		promptGuard:   promptGuard,
+		responseGuard: responseGuard,
+		tools:         tools,
+		ragService:    ragService,
+		memory:        memory,
+		intentParser:  intentParser,
+		analytics:     analytics,
+		maxIterations: maxIterations,
+	}
+}
+
+// Chat runs the tool-calling loop for one customer message
+func (cs *ChatService) Chat(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error) {
+	resp, err := cs.run(ctx, req, nil)
+	cs.analytics.RecordChat(req, resp, err)
+	return resp, err
+}
+
+// ChatStream is Chat with the answer passed to onDelta as it is generated. Text is
+// released a sentence at a time once it has passed the output guardrails.
+func (cs *ChatService) ChatStream(ctx context.Context, req *model.ChatRequest, onDelta func(string)) (*model.ChatResponse, error) {
+	resp, err := cs.run(ctx, req, onDelta)
+	cs.analytics.RecordChat(req, resp, err)
+	return resp, err
+}
+
+// run answers a chat request, streaming when onDelta is set
+func (cs *ChatService) run(ctx context.Context, req *model.ChatRequest, onDelta func(string)) (*model.ChatResponse, error) {
+	if !cs.llmService.Enabled() {
+		return nil, ErrLLMDisabled
+	}
+
+	system, err := cs.prompts.Render(PromptChatAssistant, model.PromptVars{})
+	if err != nil {
+		return nil, err
+	}
+
+	sanitized := cs.promptGuard.SanitizeUserInput(req.Message)
+	userMessage := fmt.Sprintf("<user_input>\n%s\n</user_input>", sanitized.Text)
+	if remembered := cs.memory.PromptContext(req.UserID); remembered != "" {
+		userMessage = remembered + "\n\n" + userMessage
+	}
+
+	// Tool calls run as the requesting user through the normal MCP pipeline
+	userReq := &model.UserRequest{
+		UserID:    req.UserID,
+		Channel:   req.Channel,
+		Input:     req.Message,
+		SessionID: req.SessionID,
+		Sandbox:   req.Sandbox,
+		Context:   chatContext(req),
+	}
+
+	var invocations []model.ToolInvocation
+	execute := func(ctx context.Context, call openai.ToolCall) string {
+		inv, content := cs.tools.Execute(ctx, userReq, call)
+		invocations = append(invocations, inv)
+		return content
+	}
+
+	var stream *GuardedStream
+	var handler DeltaHandler
+	if onDelta != nil {
+		stream = cs.responseGuard.NewStream(userReq, nil)
+		handler = func(delta string) {
+			if text := stream.Write(delta); text != "" {
+				onDelta(text)
+			}
+		}
+	}
+
+	settings := cs.llmService.Settings(LLMPurposeChat, req.LLM)
+	result, err := cs.llmService.RunToolLoop(WithQuotaUser(ctx, req.UserID), settings, system.Text, userMessage, cs.tools.Definitions(), execute, cs.maxIterations, handler)
+	if err != nil {
+		return nil, fmt.Errorf("chat failed: %w", err)
+	}
+
+	var answer string
+	var guardrails []string
+	if stream != nil {
+		if tail := stream.Close("APPROVED", nil); tail != "" {
+			onDelta(tail)
+		}
+		answer, guardrails = strings.TrimSpace(stream.Text()), stream.Flags(req.UserID)
+	} else {
+		answer, guardrails = cs.responseGuard.CheckText(result.Answer, "APPROVED", userReq, nil)
+	}
+
+	// Keep the exchange for retrieval; embedding happens in the background
+	if !req.Sandbox {
+		if _, err := cs.ragService.StoreConversation(req.UserID, req.SessionID, req.Message, answer); err != nil {
+			log.Warn().Err(err).Msg("Failed to store conversation for retrieval")
+		}
+		cs.memory.Remember(req.UserID, req.SessionID, req.Message, answer)
+	}
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("model", settings.Model).
+		Int("tool_calls", len(invocations)).
+		Int("iterations", result.Iterations).
+		Bool("partial", result.Partial).
+		Msg("Chat request answered")
+
+	return &model.ChatResponse{
+		Answer:     answer,
+		ToolCalls:  invocations,
+		Iterations: result.Iterations,
+		Model:      settings.Model,
+		Partial:    result.Partial,
+		Simulated:  req.Sandbox,
+		Guardrails: guardrails,
+	}, nil
+}
+
+// degradedSubjects describes what a read-only intent looked up, for degraded answers
+var degradedSubjects = map[model.IntentType]string{
+	model.IntentCheckBalance:      "your balance",
+	model.IntentListBeneficiaries: "your beneficiaries",
+	model.IntentGetStatement:      "your recent transactions",
+	model.IntentCreditScore:       "your credit score",
+}
+
+// Degraded answers a chat request without the LLM once the user's quota is exhausted.
+// Read-only requests the rule-based parser recognises are still run directly; anything
+// else gets an explanation of when the assistant is available again.
+func (cs *ChatService) Degraded(ctx context.Context, req *model.ChatRequest, quota model.QuotaStatus) (*model.ChatResponse, error) {
+	resp, err := cs.degraded(ctx, req, quota)
+	cs.analytics.RecordChat(req, resp, err)
+	return resp, err
+}
+
+// chatContext carries the user's time zone and locale to the tasks a chat runs
+func chatContext(req *model.ChatRequest) map[string]interface{} {
+	if req.Timezone == "" && req.Locale == "" {
+		return nil
+	}
+	reqContext := make(map[string]interface{})
+	if req.Timezone != "" {
+		reqContext["timezone"] = req.Timezone
+	}
+	if req.Locale != "" {
+		reqContext["locale"] = req.Locale
+	}
+	return reqContext
+}
+
+func (cs *ChatService) degraded(ctx context.Context, req *model.ChatRequest, quota model.QuotaStatus) (*model.ChatResponse, error) {
+	userReq := &model.UserRequest{
+		UserID:    req.UserID,
+		Channel:   req.Channel,
+		Input:     req.Message,
+		SessionID: req.SessionID,
+		Sandbox:   req.Sandbox,
+		Context:   chatContext(req),
+	}
+
+	intent, err := cs.intentParser.ParseIntentWithoutLLM(req.Message, "natural_language")
+	if err != nil {
+		return nil, fmt.Errorf("chat failed: %w", err)
+	}
+
+	var invocations []model.ToolInvocation
+	answer := fmt.Sprintf("You've reached your assistant limit, so I can't give a full answer until %s.",
+		quota.RetryAt().In(tz.LoadOr(req.Timezone, time.UTC)).Format("15:04 MST"))
+
+	if inv, ok := cs.tools.ExecuteIntent(ctx, userReq, intent); ok {
+		invocations = append(invocations, inv)
+		if inv.Status == "BUSY" {
+			answer += " Our banking service is also busy, so please try again in a minute."
+		} else if inv.Status == "ERROR" {
+			answer += fmt.Sprintf(" I tried to look up %s directly, but the banking service is unavailable.", degradedSubjects[intent.Type])
+		} else {
+			answer += fmt.Sprintf(" I looked up %s directly; the details are attached.", degradedSubjects[intent.Type])
+		}
+	} else {
+		answer += " Simple requests such as \"check my balance\" or \"show my statement\" still work in the meantime."
+	}
+
+	answer, guardrails := cs.responseGuard.CheckText(answer, "APPROVED", userReq, nil)
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("intent", string(intent.Type)).
+		Str("quota", quota.Exceeded).
+		Msg("Chat request answered without the LLM")
+
+	return &model.ChatResponse{
+		Answer:     answer,
+		ToolCalls:  invocations,
+		Simulated:  req.Sandbox,
+		Guardrails: guardrails,
+		Degraded:   true,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// payeeEntities are the entities that identify who receives a transfer
+var payeeEntities = []string{"to_account", "ifsc", "name", "upi_id"}
+
+var (
+	// payeeReferenceRegex matches words that point back at an earlier payee
+	payeeReferenceRegex = regexp.MustCompile(`\b(him|her|them|same (?:person|payee|beneficiary|account))\b`)
+	// amountReferenceRegex matches words that point back at an earlier amount
+	amountReferenceRegex = regexp.MustCompile(`\b(same amount|that amount|that much)\b`)
+	// repeatRegex matches words that repeat the last action as a whole
+	repeatRegex = regexp.MustCompile(`\b(again|once more|one more time)\b`)
+)
+
+// ContextResolver fills in what a follow-up leaves out, such as the payee in "send him
+// 2000 again", from the user's last action in the session. Entities it fills in are
+// listed on the intent so they can be confirmed with the user.
+type ContextResolver struct {
+	decisions *DecisionStore
+}
+
+// NewContextResolver creates a new context resolver
+func NewContextResolver(decisions *DecisionStore) *ContextResolver {
+	return &ContextResolver{
+		decisions: decisions,
+	}
+}
+
+// Resolve fills missing entities of a natural language intent from the last action
+// when the input refers back to it, and records them in intent.Inferred
+func (cr *ContextResolver) Resolve(req *model.UserRequest, intent *model.Intent) {
+	if req.InputType == "structured" || (intent.Type != model.IntentUnknown && !isActionIntent(intent.Type)) {
+		return
+	}
+
+	input := strings.ToLower(req.Input)
+	refersToPayee := payeeReferenceRegex.MatchString(input)
+	refersToAmount := amountReferenceRegex.MatchString(input)
+	repeats := repeatRegex.MatchString(input)
+	if !refersToPayee && !refersToAmount && !repeats {
+		return
+	}
+
+	last := cr.decisions.LastAction(req.UserID, req.SessionID)
+	if last == nil {
+		return
+	}
+
+	if intent.Entities == nil {
+		intent.Entities = make(map[string]interface{})
+	}
+
+	var inferred []string
+	if intent.Type == model.IntentUnknown {
+		intent.Type = last.Intent
+		inferred = append(inferred, "intent")
+	}
+
+	// Payees only carry over between transfers
+	if (refersToPayee || repeats) && isTransferIntent(intent.Type) && isTransferIntent(last.Intent) && !hasAnyEntity(intent.Entities, payeeEntities) {
+		for _, key := range payeeEntities {
+			if v, ok := last.Entities[key]; ok {
+				intent.Entities[key] = v
+				inferred = append(inferred, key)
+			}
+		}
+	}
+
+	if (refersToAmount || repeats) && intent.Type == last.Intent && entityAmount(intent.Entities) == 0 {
+		if v, ok := last.Entities["amount"]; ok {
+			intent.Entities["amount"] = v
+			inferred = append(inferred, "amount")
+		}
+	}
+
+	if len(inferred) == 0 {
+		return
+	}
+	intent.Inferred = inferred
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("intent", string(intent.Type)).
+		Strs("inferred", inferred).
+		Msg("Entities inferred from session context")
+}
+
+// hasAnyEntity reports whether any of keys is set in entities
+func hasAnyEntity(entities map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if v, ok := entities[key]; ok && v != nil && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// describeInferred tells the user which details were taken from their last action
+func describeInferred(inferred []string) string {
+	names := map[string]string{
+		"intent":     "the kind of request",
+		"to_account": "the payee account",
+		"ifsc":       "the IFSC",
+		"name":       "the payee name",
+		"upi_id":     "the UPI ID",
+		"amount":     "the amount",
+	}
+
+	parts := make([]string, 0, len(inferred))
+	for _, key := range inferred {
+		if name, ok := names[key]; ok {
+			parts = append(parts, name)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if len(parts) > 1 {
+		parts = append(parts[:len(parts)-2], parts[len(parts)-2]+" and "+parts[len(parts)-1])
+	}
+	return "I took " + strings.Join(parts, ", ") + " from your last request; please check they are right."
+}
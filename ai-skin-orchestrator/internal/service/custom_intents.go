@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// customIntentConfidence is the confidence of an intent matched by a tenant's pattern
+const customIntentConfidence = 0.8
+
+// compiledIntent is a custom intent with its patterns compiled
+type compiledIntent struct {
+	def      model.CustomIntent
+	patterns []*regexp.Regexp
+	entities []compiledEntity
+}
+
+type compiledEntity struct {
+	name     string
+	pattern  *regexp.Regexp
+	required bool
+}
+
+// CustomIntents recognises the intents tenants registered with the MCP server, so a
+// bank can add "open RD" or "update nominee" without changing the parser. The
+// definitions are cached for a TTL; while the MCP server cannot be reached the last
+// known ones are used.
+type CustomIntents struct {
+	mcpClient *MCPClient
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	intents   []compiledIntent
+	fetchedAt time.Time
+}
+
+// NewCustomIntents creates a custom intent matcher
+func NewCustomIntents(cfg *config.MCPServerConfig, mcpClient *MCPClient) *CustomIntents {
+	ttl := time.Duration(cfg.IntentsCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &CustomIntents{
+		mcpClient: mcpClient,
+		ttl:       ttl,
+	}
+}
+
+// Match returns the custom intent the input asks for, trying the tenant's own intents
+// before those shared by every tenant, or nil when none matches. Required entities
+// the input lacks are listed in the intent's "missing_entities" metadata.
+func (ci *CustomIntents) Match(ctx context.Context, tenantID, input string) *model.Intent {
+	intents := ci.current(ctx)
+
+	for _, shared := range []bool{false, true} {
+		if !shared && tenantID == "" {
+			continue
+		}
+		for _, intent := range intents {
+			if shared != (intent.def.TenantID == "") || (!shared && intent.def.TenantID != tenantID) {
+				continue
+			}
+			if matched := intent.match(input); matched != nil {
+				matched.Metadata["tenant_id"] = tenantID
+				return matched
+			}
+		}
+	}
+	return nil
+}
+
+// match parses input as this intent if one of its patterns matches
+func (ci *compiledIntent) match(input string) *model.Intent {
+	found := false
+	for _, pattern := range ci.patterns {
+		if pattern.MatchString(input) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	entities := make(map[string]interface{})
+	var missing []string
+	for _, entity := range ci.entities {
+		match := entity.pattern.FindStringSubmatch(input)
+		value := ""
+		switch {
+		case len(match) > 1:
+			value = match[1]
+		case len(match) == 1:
+			value = match[0]
+		}
+		value = strings.TrimSpace(value)
+		if value != "" {
+			entities[entity.name] = value
+		} else if entity.required {
+			missing = append(missing, entity.name)
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"parsed_by":  model.ParsedByCustom,
+		"capability": ci.def.Capability,
+	}
+	if len(missing) > 0 {
+		metadata["missing_entities"] = missing
+	}
+	return &model.Intent{
+		Type:         model.IntentType(ci.def.Intent),
+		Confidence:   customIntentConfidence,
+		Entities:     entities,
+		OriginalText: input,
+		Metadata:     metadata,
+	}
+}
+
+// current returns the cached custom intents, fetching them again once the TTL is up
+func (ci *CustomIntents) current(ctx context.Context) []compiledIntent {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	if !ci.fetchedAt.IsZero() && time.Since(ci.fetchedAt) < ci.ttl {
+		return ci.intents
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	defs, err := ci.mcpClient.ListCustomIntents(lookupCtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn().Err(err).Msg("Failed to read custom intents, using the last known")
+			// Wait a TTL before asking a down MCP server again
+			ci.fetchedAt = time.Now()
+		}
+		return ci.intents
+	}
+
+	ci.intents = compileIntents(defs)
+	ci.fetchedAt = time.Now()
+	return ci.intents
+}
+
+// compileIntents compiles the patterns of each definition case-insensitively. A
+// definition with a pattern that does not compile is skipped; the MCP server checks
+// them on registration, so this only happens across regexp versions.
+func compileIntents(defs []model.CustomIntent) []compiledIntent {
+	compiled := make([]compiledIntent, 0, len(defs))
+	for _, def := range defs {
+		intent := compiledIntent{def: def}
+		ok := true
+		for _, pattern := range def.Patterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				ok = false
+				break
+			}
+			intent.patterns = append(intent.patterns, re)
+		}
+		for _, entity := range def.Entities {
+			re, err := regexp.Compile("(?i)" + entity.Pattern)
+			if err != nil {
+				ok = false
+				break
+			}
+			intent.entities = append(intent.entities, compiledEntity{name: entity.Name, pattern: re, required: entity.Required})
+		}
+		if !ok {
+			log.Warn().Str("intent", def.Intent).Str("tenant_id", def.TenantID).Msg("Skipping custom intent with a pattern that does not compile")
+			continue
+		}
+		compiled = append(compiled, intent)
+	}
+	return compiled
+}
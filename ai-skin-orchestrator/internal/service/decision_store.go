@@ -0,0 +1,366 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// decisionTTL is how long a decision can still be asked about
+const decisionTTL = 24 * time.Hour
+
+// DecisionStore keeps the last decision per session, and per user for requests made
+// without a session, so follow-up questions can be answered from it. The last action
+// (a transfer, loan application or new payee) is kept separately so it can be retried
+// after a balance check or a "why?" in between.
+type DecisionStore struct {
+	decisions map[string]*model.Decision
+	mu        sync.RWMutex
+}
+
+// NewDecisionStore creates a new decision store
+func NewDecisionStore() *DecisionStore {
+	ds := &DecisionStore{
+		decisions: make(map[string]*model.Decision),
+	}
+
+	go ds.cleanup()
+
+	return ds
+}
+
+// Record stores the outcome of a request along with the structured reasons the agents gave
+func (ds *DecisionStore) Record(req *model.UserRequest, intent *model.Intent, merged *model.MergedResponse) *model.Decision {
+	decision := &model.Decision{
+		UserID:      req.UserID,
+		SessionID:   req.SessionID,
+		Intent:      intent.Type,
+		Status:      decisionStatus(merged),
+		Amount:      entityAmount(intent.Entities),
+		Entities:    copyEntities(intent.Entities),
+		RiskScore:   merged.RiskScore,
+		Explanation: merged.Explanation,
+		Reasons:     decisionReasons(merged.AgentResponses),
+		Simulated:   merged.Simulated,
+		DecidedAt:   time.Now(),
+	}
+	if source, ok := intent.Metadata["transfer_method_source"].(string); ok {
+		decision.MethodSource = source
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	keys := []string{userDecisionKey(req.UserID)}
+	if req.SessionID != "" {
+		keys = append(keys, sessionDecisionKey(req.SessionID))
+	}
+	for _, key := range keys {
+		ds.decisions[key] = decision
+		if isActionIntent(intent.Type) {
+			ds.decisions[actionKey(key)] = decision
+		}
+	}
+	return decision
+}
+
+// Last returns the user's last decision in the session, or their last decision overall
+// when the session has none
+func (ds *DecisionStore) Last(userID, sessionID string) *model.Decision {
+	return ds.lookup(userID, sessionID, func(key string) string { return key })
+}
+
+// LastAction returns the user's last transfer, loan application or new payee in the
+// session, or overall when the session has none
+func (ds *DecisionStore) LastAction(userID, sessionID string) *model.Decision {
+	return ds.lookup(userID, sessionID, actionKey)
+}
+
+// lookup returns the first live decision of the user under the session key, then the
+// user key, each passed through keyFn
+func (ds *DecisionStore) lookup(userID, sessionID string, keyFn func(string) string) *model.Decision {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	keys := []string{userDecisionKey(userID)}
+	if sessionID != "" {
+		keys = append([]string{sessionDecisionKey(sessionID)}, keys...)
+	}
+
+	for _, key := range keys {
+		d, ok := ds.decisions[keyFn(key)]
+		if ok && d.UserID == userID && time.Since(d.DecidedAt) < decisionTTL {
+			return d
+		}
+	}
+	return nil
+}
+
+// UserDecisions returns the user's decisions still held, oldest first
+func (ds *DecisionStore) UserDecisions(userID string) []model.Decision {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	// One decision is stored under several keys
+	seen := make(map[*model.Decision]bool)
+	decisions := []model.Decision{}
+	for _, d := range ds.decisions {
+		if d.UserID == userID && !seen[d] {
+			seen[d] = true
+			decisions = append(decisions, *d)
+		}
+	}
+	sort.Slice(decisions, func(a, b int) bool { return decisions[a].DecidedAt.Before(decisions[b].DecidedAt) })
+	return decisions
+}
+
+// ForgetUser deletes the user's decisions and returns how many there were
+func (ds *DecisionStore) ForgetUser(userID string) int {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	seen := make(map[*model.Decision]bool)
+	for key, d := range ds.decisions {
+		if d.UserID == userID {
+			seen[d] = true
+			delete(ds.decisions, key)
+		}
+	}
+	return len(seen)
+}
+
+func sessionDecisionKey(sessionID string) string { return "session:" + sessionID }
+func userDecisionKey(userID string) string       { return "user:" + userID }
+func actionKey(key string) string                { return "action:" + key }
+
+// isActionIntent reports whether an intent changes something and can be retried
+func isActionIntent(t model.IntentType) bool {
+	return isTransferIntent(t) || t == model.IntentApplyLoan || t == model.IntentAddBeneficiary
+}
+
+// copyEntities copies intent entities so later changes to the intent do not reach
+// the stored decision
+func copyEntities(entities map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(entities))
+	for k, v := range entities {
+		out[k] = v
+	}
+	return out
+}
+
+// cleanup drops decisions too old to be asked about
+func (ds *DecisionStore) cleanup() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ds.mu.Lock()
+		for key, d := range ds.decisions {
+			if time.Since(d.DecidedAt) >= decisionTTL {
+				delete(ds.decisions, key)
+			}
+		}
+		ds.mu.Unlock()
+	}
+}
+
+// agentStatus is the verdict of an agent. Through MCP the response status is the task
+// status (COMPLETED) and the agent's own verdict is in the result.
+func agentStatus(resp model.AgentResponse) string {
+	if status, ok := resp.Result["status"].(string); ok && status != "" {
+		return status
+	}
+	return resp.Status
+}
+
+// decisionStatus is REJECTED if any agent rejected, PENDING if any held the request
+// or it waits for the user to authenticate, and otherwise the merged status
+func decisionStatus(merged *model.MergedResponse) string {
+	status := merged.Status
+	for _, resp := range merged.AgentResponses {
+		switch agentStatus(resp) {
+		case "REJECTED":
+			return "REJECTED"
+		case "PENDING", taskStatusAwaitingAuth:
+			status = "PENDING"
+		}
+	}
+	return status
+}
+
+// decisionReasons collects the failed checks, fraud flags and limit figures from the
+// agents that did not approve
+func decisionReasons(responses []model.AgentResponse) []model.DecisionReason {
+	var reasons []model.DecisionReason
+	for _, resp := range responses {
+		status := agentStatus(resp)
+		if status == "APPROVED" || resp.Result == nil {
+			continue
+		}
+		source := resp.AgentType
+		limits, _ := resp.Result["limits"].(map[string]interface{})
+
+		for _, check := range stringList(resp.Result["failed_checks"]) {
+			reason := model.DecisionReason{Code: check, Source: source}
+			if usage, ok := limits[check].(map[string]interface{}); ok {
+				reason.Limit = floatField(usage, "limit")
+				reason.Used = floatField(usage, "used")
+				reason.Requested = floatField(usage, "requested")
+			}
+			reasons = append(reasons, reason)
+		}
+
+		if score := floatField(resp.Result, "fraud_score"); score != nil {
+			reasons = append(reasons, model.DecisionReason{
+				Code:   "fraud_score",
+				Source: source,
+				Detail: strings.Join(stringList(resp.Result["flags"]), ", "),
+				Score:  score,
+			})
+		}
+
+		if text, ok := resp.Result["reason"].(string); ok && text != "" {
+			reasons = append(reasons, model.DecisionReason{Code: "agent_reason", Source: source, Detail: text})
+		}
+	}
+	return reasons
+}
+
+// stringList reads a JSON array of strings, skipping anything else
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// floatField returns a numeric field of a JSON object, or nil
+func floatField(m map[string]interface{}, key string) *float64 {
+	if v, ok := m[key].(float64); ok {
+		return &v
+	}
+	return nil
+}
+
+// ExplainDecision turns a decision into a customer-facing explanation
+func ExplainDecision(d *model.Decision) string {
+	what := decisionSubject(d)
+
+	switch d.Status {
+	case "APPROVED", "COMPLETED":
+		return fmt.Sprintf("Nothing was rejected: %s went through.", what)
+	case "PENDING":
+		if len(d.Reasons) == 0 {
+			return fmt.Sprintf("%s is on hold and still being processed.", capitalize(what))
+		}
+	}
+
+	if len(d.Reasons) == 0 {
+		if d.Explanation != "" {
+			return fmt.Sprintf("%s was not completed: %s", capitalize(what), d.Explanation)
+		}
+		return fmt.Sprintf("%s was not completed, but no specific reason was recorded.", capitalize(what))
+	}
+
+	verb := "was declined"
+	switch d.Status {
+	case "PENDING":
+		verb = "is on hold"
+	case "FAILED":
+		verb = "could not be completed"
+	}
+
+	lines := make([]string, 0, len(d.Reasons))
+	for _, r := range d.Reasons {
+		lines = append(lines, "- "+explainReason(r))
+	}
+	return fmt.Sprintf("%s %s because:\n%s", capitalize(what), verb, strings.Join(lines, "\n"))
+}
+
+// decisionSubject names what a decision was about, e.g. "your transfer of ₹50,000"
+func decisionSubject(d *model.Decision) string {
+	if !isTransferIntent(d.Intent) {
+		return "your last request"
+	}
+	if d.Amount > 0 {
+		return fmt.Sprintf("your transfer of %s", formatRupees(d.Amount))
+	}
+	return "your last transfer"
+}
+
+// explainReason describes one reason in plain language, with the limit math when known
+func explainReason(r model.DecisionReason) string {
+	switch r.Code {
+	case "daily_limit":
+		if r.Limit != nil && r.Requested != nil {
+			used := 0.0
+			if r.Used != nil {
+				used = *r.Used
+			}
+			return fmt.Sprintf("it would take you over your daily limit of %s: you had already sent %s today, and %s more would make %s",
+				formatRupees(*r.Limit), formatRupees(used), formatRupees(*r.Requested), formatRupees(used+*r.Requested))
+		}
+		return "it would take you over your daily transfer limit"
+	case "single_transaction_limit":
+		if r.Limit != nil && r.Requested != nil {
+			return fmt.Sprintf("%s is more than the %s allowed in a single transfer", formatRupees(*r.Requested), formatRupees(*r.Limit))
+		}
+		return "it is more than the amount allowed in a single transfer"
+	case "velocity_limit":
+		if r.Limit != nil {
+			return fmt.Sprintf("you have reached the maximum of %.0f transfers in 24 hours", *r.Limit)
+		}
+		return "you have made too many transfers in the last 24 hours"
+	case "beneficiary_age":
+		return "the payee was added recently; new payees can receive transfers after 24 hours"
+	case "kyc_verified":
+		return "your KYC verification is not complete"
+	case "account_active":
+		return "your account is not active"
+	case "rbi_blacklist":
+		return "it was stopped by a regulatory check"
+	case "fraud_score":
+		text := "our fraud checks flagged it as unusual"
+		if r.Detail != "" {
+			text += fmt.Sprintf(" (%s)", strings.ReplaceAll(r.Detail, "_", " "))
+		}
+		return text
+	case "agent_reason":
+		return r.Detail
+	}
+	return fmt.Sprintf("the %s check did not pass", strings.ReplaceAll(r.Code, "_", " "))
+}
+
+// formatRupees formats an amount with Indian digit grouping, e.g. ₹2,00,000
+func formatRupees(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	if len(digits) <= 3 {
+		return "₹" + digits
+	}
+
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+	return "₹" + strings.Join(groups, ",") + "," + tail
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Embedder turns text into vectors for similarity search
+type Embedder interface {
+	// Model identifies the embedding space; vectors from different models are not comparable
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// HashEmbedder is a local term-frequency embedder using feature hashing. It needs no
+// model server and is the default when no embedding model is configured.
+type HashEmbedder struct {
+	dimensions int
+	tokenRe    *regexp.Regexp
+}
+
+// NewHashEmbedder creates a hashing embedder with the given vector size
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = 512
+	}
+	return &HashEmbedder{
+		dimensions: dimensions,
+		tokenRe:    regexp.MustCompile(`[a-z0-9]+`),
+	}
+}
+
+// Model returns the embedder's model name
+func (he *HashEmbedder) Model() string {
+	return fmt.Sprintf("tfidf-hash-%d", he.dimensions)
+}
+
+// Embed hashes each token into a bucket and L2-normalizes the counts
+func (he *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, he.dimensions)
+		for _, token := range he.tokenRe.FindAllString(strings.ToLower(text), -1) {
+			h := fnv.New32a()
+			h.Write([]byte(token))
+			// Sublinear weighting keeps repeated words from dominating
+			vec[h.Sum32()%uint32(he.dimensions)] += 1
+		}
+		for j, v := range vec {
+			if v > 0 {
+				vec[j] = float32(1 + math.Log(float64(v)))
+			}
+		}
+		vectors[i] = normalize(vec)
+	}
+	return vectors, nil
+}
+
+// OllamaEmbedder embeds text with an Ollama embedding model such as nomic-embed-text
+type OllamaEmbedder struct {
+	ollama *OllamaService
+	model  string
+}
+
+// NewOllamaEmbedder creates an embedder backed by Ollama
+func NewOllamaEmbedder(ollama *OllamaService, model string) *OllamaEmbedder {
+	return &OllamaEmbedder{
+		ollama: ollama,
+		model:  model,
+	}
+}
+
+// Model returns the Ollama model name
+func (oe *OllamaEmbedder) Model() string {
+	return oe.model
+}
+
+// Embed calls Ollama and normalizes the vectors
+func (oe *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := oe.ollama.Embed(ctx, oe.model, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(vectors))
+	}
+	for i := range vectors {
+		vectors[i] = normalize(vectors[i])
+	}
+	return vectors, nil
+}
+
+// normalize scales a vector to unit length so cosine similarity is a dot product
+func normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vec
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vec {
+		vec[i] /= norm
+	}
+	return vec
+}
+
+// cosine returns the similarity of two unit vectors
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
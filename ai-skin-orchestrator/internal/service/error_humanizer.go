@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// PromptErrorExplanation rewords an error explanation for the request that failed
+const PromptErrorExplanation = "error_explanation"
+
+// errorTemplate is what users are told for an error code. %s is the subject, such as
+// "your transfer".
+type errorTemplate struct {
+	message        string
+	action         string
+	transferAction string // Instead of action for transfers, which may have gone through
+	retryable      bool
+	support        bool // The user may need support, so the action quotes the reference
+}
+
+var errorTemplates = map[string]errorTemplate{
+	model.ErrorCodeUnavailable: {
+		message:   "We couldn't reach our banking systems to complete %s.",
+		action:    "Please try again in a few minutes.",
+		retryable: true,
+	},
+	model.ErrorCodeBusy: {
+		message:   "We're busy right now and couldn't take %s.",
+		action:    "Please try again in a minute.",
+		retryable: true,
+	},
+	model.ErrorCodeTimeout: {
+		message:        "%s took longer than expected to complete.",
+		action:         "Please try again shortly.",
+		transferAction: "Check your recent transactions before trying again, so the money isn't sent twice.",
+		retryable:      true,
+	},
+	model.ErrorCodeAgentUnavailable: {
+		message:   "The service that handles %s isn't available right now.",
+		action:    "Please try again later.",
+		retryable: true,
+	},
+	model.ErrorCodeInvalidResult: {
+		message:        "We couldn't confirm the outcome of %s.",
+		action:         "Please try again.",
+		transferAction: "Check your recent transactions before trying again: if the money left your account, it will show there.",
+		retryable:      true,
+		support:        true,
+	},
+	model.ErrorCodeDuplicate: {
+		message: "This looks like a repeat of a request you already made, so we didn't send it again.",
+		action:  "Check your recent transactions; if you do mean to make it again, please start a new request.",
+	},
+	model.ErrorCodeInterrupted: {
+		message: "Our systems restarted while %s was being carried out, so we can't tell yet whether it went through.",
+		action:  "Check your recent transactions before trying again.",
+		support: true,
+	},
+	model.ErrorCodeInsufficientFunds: {
+		message: "There isn't enough balance in your account for %s.",
+		action:  "Check your balance, or try a smaller amount.",
+	},
+	model.ErrorCodeLimitExceeded: {
+		message: "%s is over a limit on your account.",
+		action:  "Try a smaller amount or another way to pay.",
+	},
+	model.ErrorCodeInvalidRequest: {
+		message:   "We couldn't process %s as it was given.",
+		action:    "Please check the details and try again.",
+		retryable: true,
+	},
+	model.ErrorCodeFeatureDisabled: {
+		message: "We've paused this service for now.",
+		action:  "Please try again later.",
+	},
+	model.ErrorCodeInternal: {
+		message:   "Something went wrong on our side with %s.",
+		action:    "Please try again.",
+		retryable: true,
+		support:   true,
+	},
+}
+
+// errorRule maps raw errors matching a pattern to an error code
+type errorRule struct {
+	code string
+	re   *regexp.Regexp
+}
+
+// errorRules are matched in order against lower-cased raw errors; the first match
+// wins and anything unmatched is an internal error
+var errorRules = []errorRule{
+	{model.ErrorCodeInterrupted, regexp.MustCompile(`server (stopped|restarted)`)},
+	{model.ErrorCodeBusy, regexp.MustCompile(`\b(busy|queue is full|shutting down)\b`)},
+	{model.ErrorCodeTimeout, regexp.MustCompile(`timeout|timed out|deadline exceeded|did not complete before`)},
+	{model.ErrorCodeDuplicate, regexp.MustCompile(`nonce|submission refused|replay`)},
+	{model.ErrorCodeInsufficientFunds, regexp.MustCompile(`insufficient (funds|balance)|not enough balance`)},
+	{model.ErrorCodeLimitExceeded, regexp.MustCompile(`limit (exceeded|reached)|(over|exceeds?) (the |its |your )?(daily |per-transfer )?limit`)},
+	{model.ErrorCodeAgentUnavailable, regexp.MustCompile(`no agent available|agent not found|no healthy|hold queue|plan \S+ not found`)},
+	{model.ErrorCodeUnavailable, regexp.MustCompile(`connection refused|no such host|failed to (submit|reach|get result|verify challenge|\w+ split)|eof|llm service is disabled|unavailable`)},
+	{model.ErrorCodeInvalidResult, regexp.MustCompile(`does not match|not found in response|result is not json|missing in (the )?result|failed to parse response|invalid response`)},
+	{model.ErrorCodeInvalidRequest, regexp.MustCompile(`invalid|required|must be`)},
+}
+
+// ErrorHumanizer turns errors into what users are told: a friendly message and what to
+// do for each error code, with a reference they can quote to support. Raw errors, such
+// as "transaction_id not found in response", go to the logs under the reference and,
+// with ERROR_EXPOSE_DETAILS, to the diagnostics block; never into the message. With
+// ERROR_LLM_POLISH the LLM rewords the message for the request from its template,
+// without seeing the raw error.
+type ErrorHumanizer struct {
+	cfg        *config.ErrorsConfig
+	llmService *LLMService
+	prompts    *PromptService
+	guard      *PromptGuard
+}
+
+// NewErrorHumanizer creates a new error humanizer
+func NewErrorHumanizer(cfg *config.ErrorsConfig, llmService *LLMService, prompts *PromptService, guard *PromptGuard) *ErrorHumanizer {
+	return &ErrorHumanizer{
+		cfg:        cfg,
+		llmService: llmService,
+		prompts:    prompts,
+		guard:      guard,
+	}
+}
+
+// Explain classifies err, logs it under a new reference and returns what to tell the
+// user and the diagnostics block. taskID is set for a task that failed on the MCP
+// server; req may be nil when there is no request to reword the message for.
+func (eh *ErrorHumanizer) Explain(ctx context.Context, req *model.UserRequest, intent model.IntentType, taskID string, err error) (*model.ErrorExplanation, *model.ErrorDiagnostics) {
+	code := classifyError(err)
+	template := errorTemplates[code]
+	subject := errorSubject(intent)
+
+	explanation := &model.ErrorExplanation{
+		Code:      code,
+		Message:   capitalize(strings.Replace(template.message, "%s", subject, 1)),
+		Action:    template.action,
+		Retryable: template.retryable,
+		Reference: ids.Ref(ids.ErrorRef),
+	}
+	if template.transferAction != "" && isTransferIntent(intent) {
+		explanation.Action = template.transferAction
+	}
+
+	diagnostics := &model.ErrorDiagnostics{
+		Code:      code,
+		Reference: explanation.Reference,
+		Source:    errorSource(taskID, err),
+		TaskID:    taskID,
+	}
+	if eh.cfg.ExposeDetails {
+		diagnostics.Detail = err.Error()
+	}
+
+	event := log.Warn().
+		Err(err).
+		Str("reference", explanation.Reference).
+		Str("error_code", code).
+		Str("source", diagnostics.Source).
+		Str("intent", string(intent)).
+		Str("task_id", taskID)
+	if req != nil {
+		event = event.Str("user_id", req.UserID).Str("session_id", req.SessionID)
+	}
+	event.Msg("Request failed")
+
+	if eh.cfg.LLMPolish && req != nil && req.Input != "" && eh.llmService.Enabled() && budgetFrom(ctx).useLLM(model.BudgetStageErrorPolish) {
+		if polished, err := eh.polish(ctx, req, explanation.Message); err != nil {
+			log.Debug().Err(err).Str("reference", explanation.Reference).Msg("Error explanation not reworded, using its template")
+		} else {
+			explanation.Message, explanation.Polished = polished, true
+		}
+	}
+	if template.support {
+		explanation.Action = strings.TrimSpace(explanation.Action + " If you contact us, please quote reference " + explanation.Reference + ".")
+	}
+	return explanation, diagnostics
+}
+
+// polish has the LLM reword a message for the request. The result is refused when it
+// is empty, runs long or brings in numbers the message and request do not have.
+func (eh *ErrorHumanizer) polish(ctx context.Context, req *model.UserRequest, message string) (string, error) {
+	input := eh.guard.SanitizeUserInput(req.Input).Text
+	prompt, err := eh.prompts.Render(PromptErrorExplanation, model.PromptVars{
+		UserInput: input,
+		Extra:     map[string]interface{}{"message": message},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The request's budget bounds the call when it is tighter than the timeout
+	ctx, cancelBudget := budgetFrom(ctx).llmContext(ctx)
+	defer cancelBudget()
+	ctx, cancel := context.WithTimeout(WithQuotaUser(ctx, req.UserID), time.Duration(eh.cfg.PolishTimeoutMs)*time.Millisecond)
+	defer cancel()
+	text, err := eh.llmService.CallLLMWithSystem(ctx, eh.llmService.Settings(LLMPurposeChat, nil), "", prompt.Text)
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.Trim(strings.TrimSpace(text), `"`)
+	switch {
+	case text == "":
+		return "", fmt.Errorf("empty rewording")
+	case len(text) > 2*len(message)+80:
+		return "", fmt.Errorf("rewording is %d characters, too long for %d", len(text), len(message))
+	}
+	known := message + " " + input
+	for _, number := range errorNumberRegex.FindAllString(text, -1) {
+		if !strings.Contains(known, number) {
+			return "", fmt.Errorf("rewording adds the number %s", number)
+		}
+	}
+	return text, nil
+}
+
+// errorNumberRegex finds numbers, which a rewording must not make up
+var errorNumberRegex = regexp.MustCompile(`\d+`)
+
+// classifyError returns the error code of err
+func classifyError(err error) string {
+	var busy *MCPBusyError
+	var disabled *IntentDisabledError
+	switch {
+	case errors.As(err, &busy):
+		return model.ErrorCodeBusy
+	case errors.As(err, &disabled):
+		return model.ErrorCodeFeatureDisabled
+	case errors.Is(err, context.DeadlineExceeded):
+		return model.ErrorCodeTimeout
+	}
+
+	raw := strings.ToLower(err.Error())
+	for _, rule := range errorRules {
+		if rule.re.MatchString(raw) {
+			return rule.code
+		}
+	}
+	return model.ErrorCodeInternal
+}
+
+// errorSource says where err came from
+func errorSource(taskID string, err error) string {
+	raw := strings.ToLower(err.Error())
+	switch {
+	case taskID != "":
+		return model.ErrorSourceTask
+	case strings.Contains(raw, "mcp server") || strings.Contains(raw, "agent response") || errors.As(err, new(*MCPBusyError)):
+		return model.ErrorSourceMCP
+	}
+	return model.ErrorSourceSkin
+}
+
+// errorSubject names what failed, for the middle of a sentence
+func errorSubject(intent model.IntentType) string {
+	switch {
+	case isTransferIntent(intent):
+		return "your transfer"
+	case intent == model.IntentApplyLoan:
+		return "your loan application"
+	case intent == model.IntentAddBeneficiary:
+		return "adding your payee"
+	case intent == model.IntentCheckBalance:
+		return "your balance check"
+	case intent == model.IntentGetStatement:
+		return "your statement"
+	case intent == model.IntentSummarizeStatement:
+		return "your spending summary"
+	}
+	return "your request"
+}
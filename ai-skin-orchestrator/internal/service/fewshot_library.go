@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed fewshot/examples.json
+var embeddedFewShotExamples []byte
+
+const (
+	// maxFewShotExamples bounds the library; every example is scored for every parse
+	maxFewShotExamples = 1000
+
+	// maxFewShotUtterance bounds an example's utterance, in bytes
+	maxFewShotUtterance = 500
+)
+
+// ErrFewShotNotFound is returned for an example ID the library does not hold
+var ErrFewShotNotFound = errors.New("few-shot example not found")
+
+// ErrFewShotDuplicate is returned for an utterance the library already holds
+var ErrFewShotDuplicate = errors.New("few-shot example with this utterance already exists")
+
+// ErrInvalidFewShot is returned for an example the intent parser could not learn from
+var ErrInvalidFewShot = errors.New("invalid few-shot example")
+
+// fewShotFile is the layout of the examples file
+type fewShotFile struct {
+	Examples []struct {
+		ID        string                 `json:"id"`
+		Utterance string                 `json:"utterance"`
+		Expected  map[string]interface{} `json:"expected"`
+		Enabled   *bool                  `json:"enabled"`
+		Note      string                 `json:"note"`
+	} `json:"examples"`
+}
+
+// fewShotEntry is an example with its embedding, once it has one
+type fewShotEntry struct {
+	example model.FewShotExample
+	vector  []float32
+}
+
+// FewShotLibrary keeps the examples shown to the LLM when it parses an intent. For each
+// input the most similar examples, by the RAG embedder, are added to the
+// intent_extraction prompt, so phrasings the LLM gets wrong can be fixed by adding an
+// example rather than a new prompt version. Examples added through the API are kept in
+// memory only.
+type FewShotLibrary struct {
+	cfg      *config.FewShotConfig
+	embedder Embedder
+	guard    *PromptGuard
+
+	mu      sync.RWMutex
+	entries map[string]*fewShotEntry
+}
+
+// NewFewShotLibrary loads the examples from cfg.ExamplesFile, or the embedded ones
+// when it is not set
+func NewFewShotLibrary(cfg *config.FewShotConfig, embedder Embedder, guard *PromptGuard) (*FewShotLibrary, error) {
+	fl := &FewShotLibrary{
+		cfg:      cfg,
+		embedder: embedder,
+		guard:    guard,
+		entries:  make(map[string]*fewShotEntry),
+	}
+
+	data, source := embeddedFewShotExamples, model.FewShotSourceEmbedded
+	if cfg.ExamplesFile != "" {
+		var err error
+		if data, err = os.ReadFile(cfg.ExamplesFile); err != nil {
+			return nil, fmt.Errorf("failed to read few-shot examples file: %w", err)
+		}
+		source = model.FewShotSourceFile
+	}
+	var file fewShotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid few-shot examples file: %w", err)
+	}
+	for i, e := range file.Examples {
+		req := &model.FewShotExampleRequest{Utterance: e.Utterance, Expected: e.Expected, Enabled: e.Enabled, Note: e.Note}
+		if _, err := fl.add(e.ID, req, source); err != nil {
+			return nil, fmt.Errorf("few-shot example %d (%s): %w", i+1, e.ID, err)
+		}
+	}
+
+	log.Info().Int("examples", len(fl.entries)).Str("source", source).Bool("enabled", cfg.Enabled).Msg("Few-shot examples loaded")
+	return fl, nil
+}
+
+// List returns the examples, oldest first, optionally only those of one intent
+func (fl *FewShotLibrary) List(intent model.IntentType) []model.FewShotExample {
+	fl.mu.RLock()
+	defer fl.mu.RUnlock()
+
+	examples := []model.FewShotExample{}
+	for _, entry := range fl.entries {
+		if intent == "" || entry.example.Intent == intent {
+			examples = append(examples, entry.example)
+		}
+	}
+	sort.Slice(examples, func(a, b int) bool {
+		if !examples[a].CreatedAt.Equal(examples[b].CreatedAt) {
+			return examples[a].CreatedAt.Before(examples[b].CreatedAt)
+		}
+		return examples[a].ID < examples[b].ID
+	})
+	return examples
+}
+
+// Get returns one example
+func (fl *FewShotLibrary) Get(id string) (*model.FewShotExample, error) {
+	fl.mu.RLock()
+	defer fl.mu.RUnlock()
+
+	entry, ok := fl.entries[id]
+	if !ok {
+		return nil, ErrFewShotNotFound
+	}
+	example := entry.example
+	return &example, nil
+}
+
+// Add adds an example
+func (fl *FewShotLibrary) Add(req *model.FewShotExampleRequest) (*model.FewShotExample, error) {
+	example, err := fl.add("", req, model.FewShotSourceAPI)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("example_id", example.ID).Str("intent", string(example.Intent)).Str("added_by", example.AddedBy).Msg("Few-shot example added")
+	return example, nil
+}
+
+// add validates and stores an example, with a new ID when id is empty
+func (fl *FewShotLibrary) add(id string, req *model.FewShotExampleRequest, source string) (*model.FewShotExample, error) {
+	utterance, expected, intent, err := fl.validate(req.Utterance, req.Expected)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		id = ids.New(ids.FewShot)
+	}
+	now := time.Now()
+	example := model.FewShotExample{
+		ID:        id,
+		Utterance: utterance,
+		Expected:  expected,
+		Intent:    intent,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		Note:      req.Note,
+		Source:    source,
+		AddedBy:   req.AddedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if _, ok := fl.entries[id]; ok {
+		return nil, fmt.Errorf("%w: ID %s is used twice", ErrInvalidFewShot, id)
+	}
+	if fl.holds(utterance, "") {
+		return nil, ErrFewShotDuplicate
+	}
+	if len(fl.entries) >= maxFewShotExamples {
+		return nil, fmt.Errorf("%w: the library is full at %d examples", ErrInvalidFewShot, maxFewShotExamples)
+	}
+	fl.entries[id] = &fewShotEntry{example: example}
+	return &example, nil
+}
+
+// Update changes an example. A new utterance is embedded again on its next use; its
+// hit metrics are kept.
+func (fl *FewShotLibrary) Update(id string, req *model.FewShotExampleRequest) (*model.FewShotExample, error) {
+	fl.mu.RLock()
+	entry, ok := fl.entries[id]
+	var current model.FewShotExample
+	if ok {
+		current = entry.example
+	}
+	fl.mu.RUnlock()
+	if !ok {
+		return nil, ErrFewShotNotFound
+	}
+
+	if req.Utterance == "" {
+		req.Utterance = current.Utterance
+	}
+	if req.Expected == nil {
+		req.Expected = current.Expected
+	}
+	utterance, expected, intent, err := fl.validate(req.Utterance, req.Expected)
+	if err != nil {
+		return nil, err
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	entry, ok = fl.entries[id]
+	if !ok {
+		return nil, ErrFewShotNotFound
+	}
+	if fl.holds(utterance, id) {
+		return nil, ErrFewShotDuplicate
+	}
+	if utterance != entry.example.Utterance {
+		entry.vector = nil
+	}
+	entry.example.Utterance = utterance
+	entry.example.Expected = expected
+	entry.example.Intent = intent
+	if req.Enabled != nil {
+		entry.example.Enabled = *req.Enabled
+	}
+	if req.Note != "" {
+		entry.example.Note = req.Note
+	}
+	entry.example.UpdatedAt = time.Now()
+
+	log.Info().Str("example_id", id).Str("intent", string(intent)).Bool("enabled", entry.example.Enabled).Msg("Few-shot example updated")
+	example := entry.example
+	return &example, nil
+}
+
+// Delete removes an example
+func (fl *FewShotLibrary) Delete(id string) error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if _, ok := fl.entries[id]; !ok {
+		return ErrFewShotNotFound
+	}
+	delete(fl.entries, id)
+	log.Info().Str("example_id", id).Msg("Few-shot example deleted")
+	return nil
+}
+
+// holds reports whether an example other than except has the utterance. Callers hold
+// the lock.
+func (fl *FewShotLibrary) holds(utterance, except string) bool {
+	for id, entry := range fl.entries {
+		if id != except && strings.EqualFold(entry.example.Utterance, utterance) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that an example is one the LLM could be shown: an utterance the
+// prompt guard leaves alone and an answer the intent schema accepts as it is. It
+// returns the trimmed utterance, a copy of the answer and the expected intent.
+func (fl *FewShotLibrary) validate(utterance string, expected map[string]interface{}) (string, map[string]interface{}, model.IntentType, error) {
+	utterance = strings.TrimSpace(utterance)
+	if utterance == "" {
+		return "", nil, "", fmt.Errorf("%w: utterance is required", ErrInvalidFewShot)
+	}
+	if len(utterance) > maxFewShotUtterance {
+		return "", nil, "", fmt.Errorf("%w: utterance is longer than %d bytes", ErrInvalidFewShot, maxFewShotUtterance)
+	}
+	if flagged := fl.guard.sanitize(utterance).Flagged; len(flagged) > 0 {
+		return "", nil, "", fmt.Errorf("%w: utterance matches the prompt injection patterns %s", ErrInvalidFewShot, strings.Join(flagged, ", "))
+	}
+	if expected == nil {
+		return "", nil, "", fmt.Errorf("%w: expected is required", ErrInvalidFewShot)
+	}
+
+	// The guard drops entities it does not know, which an example must not rely on
+	checked := make(map[string]interface{}, len(expected))
+	for k, v := range expected {
+		checked[k] = v
+	}
+	entities, _ := expected["entities"].(map[string]interface{})
+	if entities != nil {
+		copied := make(map[string]interface{}, len(entities))
+		for k, v := range entities {
+			copied[k] = v
+		}
+		checked["entities"] = copied
+	}
+	if err := fl.guard.ValidateIntentOutput(checked); err != nil {
+		return "", nil, "", fmt.Errorf("%w: %v", ErrInvalidFewShot, err)
+	}
+	kept, _ := checked["entities"].(map[string]interface{})
+	for k := range entities {
+		if _, ok := kept[k]; !ok {
+			return "", nil, "", fmt.Errorf("%w: entity %s is not one the intent parser returns", ErrInvalidFewShot, k)
+		}
+	}
+	intent, _ := checked["intent"].(string)
+	return utterance, checked, model.IntentType(intent), nil
+}
+
+// Select returns up to FEWSHOT_MAX_EXAMPLES enabled examples at least
+// FEWSHOT_MIN_SIMILARITY similar to the input, most similar first. Nothing is selected
+// while the library is switched off or the input cannot be embedded.
+func (fl *FewShotLibrary) Select(ctx context.Context, input string) []model.FewShotMatch {
+	if !fl.cfg.Enabled {
+		return nil
+	}
+	return fl.nearest(ctx, input)
+}
+
+// nearest scores the enabled examples against the input. Examples not yet embedded
+// are embedded first.
+func (fl *FewShotLibrary) nearest(ctx context.Context, input string) []model.FewShotMatch {
+	vectors, err := fl.embedder.Embed(ctx, []string{input})
+	if err != nil || len(vectors) != 1 {
+		log.Warn().Err(err).Msg("Input could not be embedded; no few-shot examples selected")
+		return nil
+	}
+	if err := fl.embedPending(ctx); err != nil {
+		log.Warn().Err(err).Msg("Few-shot examples could not be embedded; only embedded ones are selected")
+	}
+
+	fl.mu.RLock()
+	var matches []model.FewShotMatch
+	for _, entry := range fl.entries {
+		if !entry.example.Enabled || entry.vector == nil {
+			continue
+		}
+		if similarity := cosine(vectors[0], entry.vector); similarity >= fl.cfg.MinSimilarity {
+			matches = append(matches, model.FewShotMatch{Example: entry.example, Similarity: similarity})
+		}
+	}
+	fl.mu.RUnlock()
+
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Similarity != matches[b].Similarity {
+			return matches[a].Similarity > matches[b].Similarity
+		}
+		return matches[a].Example.ID < matches[b].Example.ID
+	})
+	if len(matches) > fl.cfg.MaxExamples {
+		matches = matches[:fl.cfg.MaxExamples]
+	}
+	return matches
+}
+
+// embedPending embeds the examples that have no vector yet, such as new ones or those
+// whose utterance changed
+func (fl *FewShotLibrary) embedPending(ctx context.Context) error {
+	fl.mu.RLock()
+	var pending []string
+	var utterances []string
+	for id, entry := range fl.entries {
+		if entry.vector == nil {
+			pending = append(pending, id)
+			utterances = append(utterances, entry.example.Utterance)
+		}
+	}
+	fl.mu.RUnlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	vectors, err := fl.embedder.Embed(ctx, utterances)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(pending) {
+		return fmt.Errorf("embedder returned %d vectors for %d examples", len(vectors), len(pending))
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	for i, id := range pending {
+		// Skip examples deleted or changed while they were being embedded
+		if entry, ok := fl.entries[id]; ok && entry.vector == nil && entry.example.Utterance == utterances[i] {
+			entry.vector = vectors[i]
+		}
+	}
+	return nil
+}
+
+// Preview returns the examples Select would choose for an input, whether or not the
+// library is switched on, without counting hits
+func (fl *FewShotLibrary) Preview(ctx context.Context, input string) []model.FewShotMatch {
+	return fl.nearest(ctx, input)
+}
+
+// RecordHits counts the examples added to a prompt, and as matches those whose intent
+// the LLM answered with. intent is empty when the LLM gave no usable answer.
+func (fl *FewShotLibrary) RecordHits(matches []model.FewShotMatch, intent string) {
+	if len(matches) == 0 {
+		return
+	}
+	now := time.Now()
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	for _, match := range matches {
+		entry, ok := fl.entries[match.Example.ID]
+		if !ok {
+			continue
+		}
+		entry.example.Hits++
+		if intent != "" && string(entry.example.Intent) == intent {
+			entry.example.Matches++
+		}
+		entry.example.LastHitAt = &now
+	}
+}
+
+// promptExamples renders matches for the intent_extraction template
+func promptExamples(matches []model.FewShotMatch) []model.PromptExample {
+	examples := make([]model.PromptExample, 0, len(matches))
+	for _, match := range matches {
+		output, err := json.Marshal(match.Example.Expected)
+		if err != nil {
+			continue
+		}
+		examples = append(examples, model.PromptExample{Input: match.Example.Utterance, Output: string(output)})
+	}
+	return examples
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/channel"
+	"github.com/rs/zerolog/log"
+)
+
+// handoffRule hands an intent to an app screen for a tenant, a channel, both or
+// everyone. A rule with neither a screen nor a deep link executes the intent as usual,
+// so a tenant or channel can opt out of a wider rule.
+type handoffRule struct {
+	Intent   string            `json:"intent"`
+	TenantID string            `json:"tenant_id,omitempty"`
+	Channel  string            `json:"channel,omitempty"`
+	ScreenID string            `json:"screen_id,omitempty"`
+	DeepLink string            `json:"deep_link,omitempty"`
+	Title    string            `json:"title,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"` // Entity → screen field; every entity under its own name when empty
+}
+
+// HandoffRouter decides which intents are handed to a native screen rather than
+// executed, e.g. a loan application that needs more than a conversation can collect.
+// A rule for the tenant and channel wins over one for the tenant, which wins over one
+// for the channel, which wins over one for the intent alone.
+type HandoffRouter struct {
+	rules []handoffRule
+}
+
+// NewHandoffRouter loads the intent to screen rules; without a file no intent is
+// handed off
+func NewHandoffRouter(cfg *config.HandoffConfig) (*HandoffRouter, error) {
+	hr := &HandoffRouter{}
+	if cfg.ScreensFile == "" {
+		return hr, nil
+	}
+
+	data, err := os.ReadFile(cfg.ScreensFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handoff screens file: %w", err)
+	}
+	if err := json.Unmarshal(data, &hr.rules); err != nil {
+		return nil, fmt.Errorf("invalid handoff screens file: %w", err)
+	}
+
+	for i := range hr.rules {
+		rule := &hr.rules[i]
+		rule.Intent = strings.ToUpper(strings.TrimSpace(rule.Intent))
+		if rule.Intent == "" {
+			return nil, fmt.Errorf("handoff rule %d names no intent", i+1)
+		}
+		if rule.Channel != "" {
+			c, err := channel.Parse(rule.Channel)
+			if err != nil {
+				return nil, fmt.Errorf("handoff rule %d: %w", i+1, err)
+			}
+			rule.Channel = string(c)
+		}
+		if rule.DeepLink != "" {
+			if _, err := url.Parse(rule.DeepLink); err != nil {
+				return nil, fmt.Errorf("handoff rule %d: invalid deep link: %w", i+1, err)
+			}
+		}
+	}
+
+	log.Info().Int("rules", len(hr.rules)).Msg("Handoff screens loaded")
+	return hr, nil
+}
+
+// Handoff returns the screen an intent is handed to for the tenant and channel, with
+// the entities the conversation gave so far, or nil when the intent is executed
+func (hr *HandoffRouter) Handoff(intent *model.Intent, tenantID, channel string) *model.Handoff {
+	rule := hr.match(string(intent.Type), tenantID, strings.ToUpper(channel))
+	if rule == nil || (rule.ScreenID == "" && rule.DeepLink == "") {
+		return nil
+	}
+
+	handoff := &model.Handoff{
+		Intent:   string(intent.Type),
+		ScreenID: rule.ScreenID,
+		Title:    rule.Title,
+		Prefill:  make(map[string]interface{}),
+	}
+	if len(rule.Fields) == 0 {
+		for name, value := range intent.Entities {
+			if isPrefillValue(value) {
+				handoff.Prefill[name] = value
+			}
+		}
+	} else {
+		for entity, field := range rule.Fields {
+			if value, ok := intent.Entities[entity]; ok && isPrefillValue(value) {
+				handoff.Prefill[field] = value
+			} else {
+				handoff.Missing = append(handoff.Missing, field)
+			}
+		}
+		sort.Strings(handoff.Missing)
+	}
+	handoff.DeepLink = deepLink(rule.DeepLink, handoff.Prefill)
+	return handoff
+}
+
+// match returns the most specific rule for an intent, tenant and channel
+func (hr *HandoffRouter) match(intent, tenantID, channel string) *handoffRule {
+	var best *handoffRule
+	bestScore := 0
+	for i := range hr.rules {
+		rule := &hr.rules[i]
+		if rule.Intent != intent || (rule.TenantID != "" && rule.TenantID != tenantID) || (rule.Channel != "" && rule.Channel != channel) {
+			continue
+		}
+		score := 1
+		if rule.Channel != "" {
+			score++
+		}
+		if rule.TenantID != "" {
+			score += 2
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// deepLink adds prefilled scalar values to a deep link as query parameters, keeping
+// any the link already has
+func deepLink(link string, prefill map[string]interface{}) string {
+	if link == "" || len(prefill) == 0 {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	query := u.Query()
+	for field, value := range prefill {
+		if query.Get(field) == "" {
+			query.Set(field, fmt.Sprint(value))
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// isPrefillValue reports whether an entity is a value a screen field can take
+func isPrefillValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v) != ""
+	case float64, float32, int, int64, bool:
+		return true
+	}
+	return false
+}
+
+// handoffExplanation tells the user where they are being taken
+func handoffExplanation(handoff *model.Handoff) string {
+	screen := handoff.Title
+	if screen == "" {
+		screen = strings.ToLower(strings.ReplaceAll(handoff.Intent, "_", " "))
+	}
+	if len(handoff.Prefill) == 0 {
+		return fmt.Sprintf("Please continue with %s in the app.", screen)
+	}
+	return fmt.Sprintf("Please continue with %s in the app; we've filled in what you told us.", screen)
+}
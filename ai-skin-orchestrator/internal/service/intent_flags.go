@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// readIntents only read, so they still run while their flag is read-only. The MCP
+// server keeps the same list.
+var readIntents = map[model.IntentType]bool{
+	model.IntentCheckBalance: true, model.IntentGetStatement: true, model.IntentListBeneficiaries: true,
+	model.IntentCreditScore: true, model.IntentGetInsights: true, model.IntentBudgetStatus: true,
+	model.IntentWhyRejected: true, model.IntentSummarizeStatement: true,
+}
+
+// featureNames name a capability feature in the middle of a sentence
+var featureNames = map[string]string{
+	"transfers":        "transfers",
+	"balance":          "balance checks",
+	"statements":       "statements",
+	"beneficiaries":    "payees",
+	"payment_requests": "payment requests",
+	"receipts":         "receipts",
+	"exports":          "transaction exports",
+	"budgets":          "budgets",
+	"loans":            "loan applications",
+	"credit_score":     "credit score checks",
+	"insights":         "savings insights",
+	"preferences":      "saving preferences",
+	"explanations":     "decision explanations",
+	"retry":            "retries",
+}
+
+// IntentFlags answers requests for intents an operator switched off on the MCP server
+// before anything is submitted, with the flag's message. Flags are cached for a TTL;
+// while the MCP server cannot be reached the last known ones are used, and the MCP
+// server checks every task again.
+type IntentFlags struct {
+	mcpClient *MCPClient
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	flags     map[string]model.IntentFlag // Keyed by intent, tenant and channel
+	fetchedAt time.Time
+}
+
+// NewIntentFlags creates the intent flag cache
+func NewIntentFlags(cfg *config.MCPServerConfig, mcpClient *MCPClient) *IntentFlags {
+	ttl := time.Duration(cfg.FlagsCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = 10 * time.Second
+	}
+	return &IntentFlags{
+		mcpClient: mcpClient,
+		ttl:       ttl,
+	}
+}
+
+// Lookup returns the flag that applies to an intent for a tenant and channel, with the
+// MCP server's precedence: one naming both, then the tenant, then the channel, then
+// everyone. Nil when the intent has no flag.
+func (f *IntentFlags) Lookup(ctx context.Context, intent model.IntentType, tenantID, channel string) *model.IntentFlag {
+	flags := f.current(ctx)
+
+	candidates := []string{flagKey(intent, "", "")}
+	if channel != "" {
+		candidates = append(candidates, flagKey(intent, "", channel))
+	}
+	if tenantID != "" {
+		candidates = append(candidates, flagKey(intent, tenantID, ""))
+		if channel != "" {
+			candidates = append(candidates, flagKey(intent, tenantID, channel))
+		}
+	}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if flag, ok := flags[candidates[i]]; ok {
+			return &flag
+		}
+	}
+	return nil
+}
+
+// Check returns the refusal of a request for intent while its flag switches it off for
+// the request's tenant and channel, or nil when it may run. A read-only intent still
+// runs sandbox requests, and every request when it only reads.
+func (f *IntentFlags) Check(ctx context.Context, req *model.UserRequest, intent model.IntentType) *IntentDisabledError {
+	tenantID, _ := req.Context["tenant_id"].(string)
+	flag := f.Lookup(ctx, intent, tenantID, req.Channel)
+	if flag == nil {
+		return nil
+	}
+	if flag.Mode == model.IntentFlagEnabled || (flag.Mode == model.IntentFlagReadOnly && (req.Sandbox || readIntents[intent])) {
+		return nil
+	}
+	return &IntentDisabledError{Intent: string(intent), Mode: flag.Mode, Message: flag.Message}
+}
+
+// Disabled returns the intents switched off for every tenant and channel, for the
+// capabilities report
+func (f *IntentFlags) Disabled(ctx context.Context) map[model.IntentType]model.IntentFlag {
+	disabled := make(map[model.IntentType]model.IntentFlag)
+	for _, flag := range f.current(ctx) {
+		if flag.TenantID == "" && flag.Channel == "" && flag.Mode != model.IntentFlagEnabled {
+			disabled[model.IntentType(flag.Intent)] = flag
+		}
+	}
+	return disabled
+}
+
+// current returns the cached flags, fetching them again once the TTL is up
+func (f *IntentFlags) current(ctx context.Context) map[string]model.IntentFlag {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.fetchedAt.IsZero() && time.Since(f.fetchedAt) < f.ttl {
+		return f.flags
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	flags, err := f.mcpClient.ListIntentFlags(lookupCtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn().Err(err).Msg("Failed to read intent flags, using the last known")
+			// Wait a TTL before asking a down MCP server again
+			f.fetchedAt = time.Now()
+		}
+		return f.flags
+	}
+
+	f.flags = make(map[string]model.IntentFlag, len(flags))
+	for _, flag := range flags {
+		f.flags[flagKey(model.IntentType(flag.Intent), flag.TenantID, flag.Channel)] = flag
+	}
+	f.fetchedAt = time.Now()
+	return f.flags
+}
+
+// flagKey keys a flag by intent, tenant and channel
+func flagKey(intent model.IntentType, tenantID, channel string) string {
+	return string(intent) + "|" + tenantID + "|" + channel
+}
+
+// switchedOffMessage is what the user is told about an intent that is switched off:
+// the flag's message, or one naming the intent's feature
+func switchedOffMessage(refusal *IntentDisabledError) string {
+	if refusal.Message != "" {
+		return refusal.Message
+	}
+	name := "this service"
+	for _, entry := range capabilityCatalog {
+		if string(entry.intent) == refusal.Intent {
+			name = featureNames[entry.feature]
+			break
+		}
+	}
+	if refusal.Mode == model.IntentFlagReadOnly {
+		return fmt.Sprintf("We've paused %s for now, though you can still check your balance and statements. Please try again later.", name)
+	}
+	return fmt.Sprintf("We've paused %s for now. Please try again later.", name)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/aibanking/ai-skin-orchestrator/internal/model"
@@ -15,6 +16,7 @@ import (
 type IntentParser struct {
 	llmService *LLMService
 	useLLM    bool
+	custom     *CustomIntents // Tenants' own intents; none when nil
 }
 
 // NewIntentParser creates a new intent parser
@@ -25,8 +27,32 @@ func NewIntentParser(llmService *LLMService, useLLM bool) *IntentParser {
 	}
 }
 
-// ParseIntent parses user input to extract intent and entities
-func (ip *IntentParser) ParseIntent(ctx context.Context, userInput string, inputType string) (*model.Intent, error) {
+// SetCustomIntents has the parser recognise tenants' custom intents
+func (ip *IntentParser) SetCustomIntents(custom *CustomIntents) {
+	ip.custom = custom
+}
+
+// MatchCustom replaces each intent the parser could not place with the custom intent
+// of the tenant its text matches, if any. Built-in intents always win, so a tenant's
+// pattern cannot take over a transfer or a balance check.
+func (ip *IntentParser) MatchCustom(ctx context.Context, tenantID string, intents []*model.Intent) {
+	if ip.custom == nil {
+		return
+	}
+	for i, intent := range intents {
+		if intent.Type != model.IntentUnknown {
+			continue
+		}
+		if matched := ip.custom.Match(ctx, tenantID, intent.OriginalText); matched != nil {
+			log.Info().Str("intent", string(matched.Type)).Str("tenant_id", tenantID).Msg("Custom intent matched")
+			intents[i] = matched
+		}
+	}
+}
+
+// ParseIntent parses user input to extract intent and entities. Overrides, if any,
+// apply to the LLM call and must already be validated.
+func (ip *IntentParser) ParseIntent(ctx context.Context, userInput string, inputType string, overrides *model.LLMOverrides) (*model.Intent, error) {
 	if inputType == "structured" {
 		// If structured, extract directly
 		return ip.parseStructuredInput(userInput)
@@ -34,12 +60,136 @@ func (ip *IntentParser) ParseIntent(ctx context.Context, userInput string, input
 
 	// For natural language, use LLM if available, otherwise use rule-based
 	if ip.useLLM && ip.llmService != nil {
-		return ip.parseWithLLM(ctx, userInput)
+		return ip.parseWithLLM(ctx, userInput, overrides)
 	}
 
 	return ip.parseWithRules(userInput)
 }
 
+// UsesLLM reports whether input of a type is parsed with the LLM
+func (ip *IntentParser) UsesLLM(inputType string) bool {
+	return inputType != "structured" && ip.useLLM && ip.llmService != nil
+}
+
+// ParseIntentWithoutLLM parses user input with the rule-based parser only, for
+// users whose LLM quota is exhausted
+func (ip *IntentParser) ParseIntentWithoutLLM(userInput string, inputType string) (*model.Intent, error) {
+	if inputType == "structured" {
+		return ip.parseStructuredInput(userInput)
+	}
+	return ip.parseWithRules(userInput)
+}
+
+// ParseIntents splits input holding several requests, such as "check my balance and
+// then send 5000 to Ravi", and parses each segment in order. Structured input is
+// always a single intent.
+func (ip *IntentParser) ParseIntents(ctx context.Context, userInput string, inputType string, overrides *model.LLMOverrides) ([]*model.Intent, error) {
+	if inputType == "structured" {
+		return ip.parseSegments([]string{userInput}, inputType, func(s string) (*model.Intent, error) {
+			return ip.parseStructuredInput(s)
+		})
+	}
+
+	segments := []string{userInput}
+	if mayHoldSeveralRequests(userInput) {
+		segments = ip.splitUtterance(ctx, userInput, overrides)
+	}
+	return ip.parseSegments(segments, inputType, func(s string) (*model.Intent, error) {
+		return ip.ParseIntent(ctx, s, inputType, overrides)
+	})
+}
+
+// ParseIntentsWithoutLLM splits and parses input with the rule-based parser only
+func (ip *IntentParser) ParseIntentsWithoutLLM(userInput string, inputType string) ([]*model.Intent, error) {
+	segments := []string{userInput}
+	if inputType != "structured" && mayHoldSeveralRequests(userInput) {
+		segments = ip.splitWithRules(userInput)
+	}
+	return ip.parseSegments(segments, inputType, func(s string) (*model.Intent, error) {
+		return ip.ParseIntentWithoutLLM(s, inputType)
+	})
+}
+
+// parseSegments parses each segment in order
+func (ip *IntentParser) parseSegments(segments []string, inputType string, parse func(string) (*model.Intent, error)) ([]*model.Intent, error) {
+	intents := make([]*model.Intent, 0, len(segments))
+	for _, segment := range segments {
+		intent, err := parse(segment)
+		if err != nil {
+			return nil, err
+		}
+		intents = append(intents, intent)
+	}
+
+	if len(intents) > 1 {
+		log.Info().Int("intents", len(intents)).Str("input_type", inputType).Msg("Input split into several intents")
+	}
+	return intents, nil
+}
+
+// splitUtterance splits input with the LLM when it is available, and with rules otherwise
+// or when the LLM's split cannot be used
+func (ip *IntentParser) splitUtterance(ctx context.Context, userInput string, overrides *model.LLMOverrides) []string {
+	if ip.useLLM && ip.llmService != nil {
+		segments, err := ip.llmService.SplitUtteranceWithLLM(ctx, userInput, overrides)
+		if err == nil {
+			return segments
+		}
+		log.Warn().Err(err).Msg("LLM utterance split failed, falling back to rules")
+	}
+	return ip.splitWithRules(userInput)
+}
+
+var (
+	// severalRequestsRegex matches words that can join two requests
+	severalRequestsRegex = regexp.MustCompile(`(?i)\b(and|then|also)\b|;`)
+	// segmentSeparatorRegex matches separators that always start a new request
+	segmentSeparatorRegex = regexp.MustCompile(`(?i)\s*(?:,?\s*\band then\b|,?\s*\bafter that\b|,?\s*\band also\b|,?\s*\bthen\b|;)\s*`)
+	// conjunctionRegex matches a plain "and", which may join two requests or two details
+	conjunctionRegex = regexp.MustCompile(`(?i)\s+and\s+`)
+)
+
+// mayHoldSeveralRequests reports whether input has a word that could join two requests,
+// so single requests skip splitting
+func mayHoldSeveralRequests(input string) bool {
+	return severalRequestsRegex.MatchString(input)
+}
+
+// splitWithRules splits input on "then", "after that" and ";", and on "and" only when
+// every side is a request of its own, so "send 5000 to Ravi and Sita" stays whole
+func (ip *IntentParser) splitWithRules(userInput string) []string {
+	var segments []string
+	for _, part := range segmentSeparatorRegex.Split(userInput, -1) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pieces := conjunctionRegex.Split(part, -1)
+		if len(pieces) > 1 && ip.allRequests(pieces) {
+			segments = append(segments, pieces...)
+		} else {
+			segments = append(segments, part)
+		}
+	}
+
+	if len(segments) == 0 {
+		return []string{userInput}
+	}
+	return segments
+}
+
+// allRequests reports whether the rule-based parser finds an intent in every piece
+func (ip *IntentParser) allRequests(pieces []string) bool {
+	for _, piece := range pieces {
+		intent, err := ip.parseWithRules(piece)
+		if err != nil || intent.Type == model.IntentUnknown {
+			return false
+		}
+	}
+	return true
+}
+
 // parseStructuredInput parses structured JSON input
 func (ip *IntentParser) parseStructuredInput(input string) (*model.Intent, error) {
 	var data map[string]interface{}
@@ -62,49 +212,31 @@ func (ip *IntentParser) parseStructuredInput(input string) (*model.Intent, error
 		Confidence:  1.0,
 		Entities:    entities,
 		OriginalText: input,
+		Metadata:    map[string]interface{}{"parsed_by": model.ParsedByStructured},
 	}, nil
 }
 
 // parseWithLLM uses LLM to parse natural language intent
-func (ip *IntentParser) parseWithLLM(ctx context.Context, userInput string) (*model.Intent, error) {
-	prompt := fmt.Sprintf(`Analyze the following banking request and extract:
-1. Intent type (one of: TRANSFER_NEFT, TRANSFER_RTGS, TRANSFER_IMPS, TRANSFER_UPI, CHECK_BALANCE, GET_STATEMENT, ADD_BENEFICIARY, APPLY_LOAN, CREDIT_SCORE)
-2. Entities (amount, account number, beneficiary, etc.)
-3. Confidence score (0.0 to 1.0)
-
-User request: "%s"
-
-Respond in JSON format:
-{
-  "intent": "INTENT_TYPE",
-  "confidence": 0.95,
-  "entities": {
-    "amount": 50000,
-    "to_account": "XXXX4321"
-  }
-}`, userInput)
-
-	response, err := ip.llmService.CallLLM(ctx, prompt)
+func (ip *IntentParser) parseWithLLM(ctx context.Context, userInput string, overrides *model.LLMOverrides) (*model.Intent, error) {
+	result, err := ip.llmService.ParseIntentWithLLM(ctx, userInput, overrides)
 	if err != nil {
 		log.Warn().Err(err).Msg("LLM parsing failed, falling back to rules")
 		return ip.parseWithRules(userInput)
 	}
 
-	var result struct {
-		Intent     string                 `json:"intent"`
-		Confidence float64                `json:"confidence"`
-		Entities   map[string]interface{} `json:"entities"`
-	}
-
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
+	intentType, _ := result["intent"].(string)
+	confidence, _ := result["confidence"].(float64)
+	entities, _ := result["entities"].(map[string]interface{})
+	if intentType == "" {
 		return ip.parseWithRules(userInput)
 	}
 
 	return &model.Intent{
-		Type:        model.IntentType(result.Intent),
-		Confidence:  result.Confidence,
-		Entities:   result.Entities,
+		Type:        model.IntentType(intentType),
+		Confidence:  confidence,
+		Entities:   entities,
 		OriginalText: userInput,
+		Metadata:    map[string]interface{}{"parsed_by": model.ParsedByLLM},
 	}, nil
 }
 
@@ -137,7 +269,71 @@ func (ip *IntentParser) parseWithRules(userInput string) (*model.Intent, error)
 	var confidence float64 = 0.7
 
 	switch {
-	case containsAny(input, []string{"neft", "transfer neft", "send via neft", "transfer", "send money", "pay"}):
+	// Checked first: "notify me when I spend over 50,000" sets a budget, not a channel
+	case isBudgetRequest(input):
+		delete(entities, "amount")
+		for k, v := range extractBudgetEntities(input) {
+			entities[k] = v
+		}
+		intentType = model.IntentBudgetStatus
+		if _, ok := entities["amount"]; ok {
+			intentType = model.IntentSetBudget
+		}
+		confidence = 0.85
+	// Then: "always use IMPS for transfers" also mentions a transfer
+	case isPreferenceStatement(input):
+		intentType = model.IntentSetPreference
+		confidence = 0.85
+		delete(entities, "amount")
+		for k, v := range extractPreferenceEntities(input) {
+			entities[k] = v
+		}
+	// Also before transfers: "why was my transfer rejected" names a transfer
+	case isWhyRejected(input):
+		intentType = model.IntentWhyRejected
+		confidence = 0.85
+		delete(entities, "amount")
+	// Before transfers and retries: "resend the receipt for my last transfer" shares it
+	case isReceiptRequest(input):
+		intentType = model.IntentShareReceipt
+		confidence = 0.85
+		delete(entities, "amount")
+		for k, v := range extractReceiptEntities(input) {
+			entities[k] = v
+		}
+	// And "send 50,000 instead" changes the last transfer rather than starting a new one
+	case isRetryRequest(input):
+		intentType = model.IntentRetryLast
+		confidence = 0.85
+		delete(entities, "amount")
+		for k, v := range extractRetryEntities(input) {
+			entities[k] = v
+		}
+	// Before transfers too: "ask Ravi to pay me 500" asks for money rather than paying it
+	case isMoneyRequest(input):
+		intentType = model.IntentRequestMoney
+		confidence = 0.85
+		for k, v := range extractMoneyRequestEntities(userInput) {
+			entities[k] = v
+		}
+	// Before summaries and statements: "download my statement as CSV" wants a file
+	case isExportRequest(input):
+		intentType = model.IntentExportTransactions
+		confidence = 0.85
+		delete(entities, "amount")
+		for k, v := range extractExportEntities(input) {
+			entities[k] = v
+		}
+	// And before transfers and balance: "summarize my transfers" and "how much did I
+	// spend last month" ask about the statement
+	case isStatementSummary(input):
+		intentType = model.IntentSummarizeStatement
+		confidence = 0.85
+		delete(entities, "amount")
+		if period := statementPeriodName(input); period != "" {
+			entities["period"] = period
+		}
+	case containsAny(input, []string{"neft", "transfer neft", "send via neft", "transfer", "send money"}) || transferVerbRegex.MatchString(input):
 		intentType = model.IntentTransferNEFT
 		confidence = 0.9
 	case containsAny(input, []string{"rtgs", "transfer rtgs"}):
@@ -149,12 +345,19 @@ func (ip *IntentParser) parseWithRules(userInput string) (*model.Intent, error)
 	case containsAny(input, []string{"upi", "pay via upi", "scan qr"}):
 		intentType = model.IntentTransferUPI
 		confidence = 0.9
+	// Before balance: "what should I do with my idle balance" asks for advice
+	case containsAny(input, []string{"insights", "how can i save", "savings suggestions", "save more", "idle balance", "grow my savings", "fixed deposit", "recurring deposit"}):
+		intentType = model.IntentGetInsights
+		confidence = 0.85
 	case containsAny(input, []string{"balance", "check balance", "account balance", "how much", "what is my balance"}):
 		intentType = model.IntentCheckBalance
 		confidence = 0.95
 	case containsAny(input, []string{"statement", "mini statement", "transaction history", "transactions", "history"}):
 		intentType = model.IntentGetStatement
 		confidence = 0.9
+	case containsAny(input, []string{"list beneficiaries", "show beneficiaries", "my beneficiaries", "my payees", "list payees"}):
+		intentType = model.IntentListBeneficiaries
+		confidence = 0.9
 	case containsAny(input, []string{"add beneficiary", "add payee", "save beneficiary", "beneficiary"}):
 		intentType = model.IntentAddBeneficiary
 		confidence = 0.9
@@ -169,14 +372,258 @@ func (ip *IntentParser) parseWithRules(userInput string) (*model.Intent, error)
 		confidence = 0.3
 	}
 
+	// "Pay Airtel 599" and "send 5000 to Ravi" name the payee, with or without an account
+	if isTransferIntent(intentType) {
+		if name := extractPayeeName(userInput); name != "" {
+			entities["name"] = name
+		}
+	}
+
 	return &model.Intent{
 		Type:        intentType,
 		Confidence:  confidence,
 		Entities:    entities,
 		OriginalText: userInput,
+		Metadata:    map[string]interface{}{"parsed_by": model.ParsedByRules},
 	}, nil
 }
 
+// preferenceLimitRegex matches limits such as "under 1 lakh" or "up to 50,000"
+var preferenceLimitRegex = regexp.MustCompile(`(?:under|below|up\s*to|upto|less\s+than|within)\s*(?:rs\.?|₹|inr)?\s*(\d+(?:,\d+)*(?:\.\d+)?)\s*(lakhs?|lacs?|crores?|k|thousand)?`)
+
+// transferVerbRegex matches "pay" as a word, so "payees" is not a transfer, and "send"
+// followed by an amount, as in "send 5000 to Ravi" or "send him 2000"
+var transferVerbRegex = regexp.MustCompile(`\bpay\b|\bsend\s+(?:(?:him|her|them)\s+)?(?:rs\.?|₹|inr)?\s*\d`)
+
+// transferMethodRegex matches a named payment rail
+var transferMethodRegex = regexp.MustCompile(`(?i)\b(neft|rtgs|imps|upi)\b`)
+
+var (
+	// payeeNameRegex matches up to three words after "pay" or "to", as in "pay Tata
+	// Power 1500" or "send 5000 to Ravi"
+	payeeNameRegex = regexp.MustCompile(`(?i)\b(?:pay|to)\s+([a-z][a-z.&'-]*(?:\s+[a-z][a-z.&'-]*){0,2})`)
+	// payeeNameStopWords end a payee name; a name starting with one is no name at all
+	payeeNameStopWords = map[string]bool{
+		"to": true, "account": true, "acc": true, "ac": true, "a/c": true, "via": true, "using": true, "by": true,
+		"through": true, "for": true, "from": true, "rs": true, "rs.": true, "inr": true, "rupees": true, "my": true,
+		"the": true, "me": true, "him": true, "her": true, "them": true, "upi": true, "neft": true, "rtgs": true,
+		"imps": true, "bill": true, "bills": true, "on": true, "now": true, "today": true, "with": true, "and": true,
+		"then": true, "please": true, "money": true, "same": true, "back": true,
+	}
+)
+
+// extractPayeeName pulls the payee a transfer names, or "" when it names none
+func extractPayeeName(input string) string {
+	for _, matches := range payeeNameRegex.FindAllStringSubmatch(input, -1) {
+		var words []string
+		for _, word := range strings.Fields(matches[1]) {
+			if payeeNameStopWords[strings.ToLower(word)] {
+				break
+			}
+			words = append(words, word)
+		}
+		if len(words) > 0 {
+			return strings.TrimRight(strings.Join(words, " "), ".")
+		}
+	}
+	return ""
+}
+
+// isPreferenceStatement reports whether lowercased input sets a standing preference
+func isPreferenceStatement(input string) bool {
+	return containsAny(input, []string{"always use", "always send", "always pay", "i prefer", "by default", "default account", "set my default", "notify me", "send my alerts", "alert me by", "alert me on"})
+}
+
+var (
+	// moneyRequestRegex matches asking for money: "request 500 from Priya", "collect
+	// 200 from Ravi", "ask Rahul to pay me 1200", "ask Priya for 300"
+	moneyRequestRegex = regexp.MustCompile(`\b(?:request|collect)\s+(?:rs\.?|₹|inr)?\s*\d|\bask\s+\w+\s+(?:to\s+(?:pay|send)\s+me\b|for\s+(?:rs\.?|₹|inr)?\s*\d)|\b(?:request|get)\s+money\s+from\b`)
+	// payerRegex matches who is asked to pay, as in "from Priya" or "ask Rahul"
+	payerRegex = regexp.MustCompile(`(?i)\b(?:from|ask)\s+([A-Za-z][A-Za-z.]*)`)
+	// upiIDRegex matches a UPI ID such as priya.s@okbank
+	upiIDRegex = regexp.MustCompile(`\b([a-zA-Z0-9._-]{2,}@[a-zA-Z]{2,})\b`)
+)
+
+// isMoneyRequest reports whether lowercased input asks someone to pay the user
+func isMoneyRequest(input string) bool {
+	return moneyRequestRegex.MatchString(input)
+}
+
+// extractMoneyRequestEntities pulls who is asked to pay: their UPI ID when given,
+// else their name
+func extractMoneyRequestEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	if matches := upiIDRegex.FindStringSubmatch(input); len(matches) > 1 {
+		entities["upi_id"] = strings.ToLower(matches[1])
+		return entities
+	}
+	if matches := payerRegex.FindStringSubmatch(input); len(matches) > 1 {
+		name := strings.TrimRight(matches[1], ".")
+		switch strings.ToLower(name) {
+		case "me", "my", "him", "her", "them", "someone", "account", "acc":
+		default:
+			entities["name"] = name
+		}
+	}
+	return entities
+}
+
+// transactionIDRegex matches a gateway transaction ID, e.g. mb_01m53rfj27ecesbv
+var transactionIDRegex = regexp.MustCompile(`\b((?:mb|nb|sbx|seed)_[a-z0-9_]+)\b`)
+
+// isReceiptRequest reports whether lowercased input asks for a transfer's receipt
+func isReceiptRequest(input string) bool {
+	return containsAny(input, []string{"receipt", "proof of payment", "payment proof", "proof of transfer", "transfer proof"})
+}
+
+// extractReceiptEntities pulls the transfer a receipt is for, when named; otherwise
+// the receipt is for the user's most recent transfer
+func extractReceiptEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	if matches := transactionIDRegex.FindStringSubmatch(input); len(matches) > 1 {
+		entities["transaction_id"] = strings.ToUpper(matches[1])
+	}
+	return entities
+}
+
+var (
+	// spendAlertRegex matches asking to be told about spending, as in "alert me when I
+	// spend over 50,000" or "warn me if my spending crosses 1 lakh"
+	spendAlertRegex = regexp.MustCompile(`\b(?:alert|notify|warn|tell)\s+me\s+(?:when|if|once)\s+(?:i|my)\s+(?:spend|spending)`)
+	// budgetCategoryWords name the categories a budget can be for
+	budgetCategoryWords = []struct {
+		category string
+		words    []string
+	}{
+		{"GROCERIES", []string{"grocery", "groceries"}},
+		{"FOOD", []string{"food", "eating out", "dining", "restaurant"}},
+		{"SHOPPING", []string{"shopping"}},
+		{"TRAVEL", []string{"travel", "fuel", "petrol", "cab"}},
+		{"BILLS", []string{"bills", "bill", "utilities"}},
+		{"RENT", []string{"rent"}},
+		{"ENTERTAINMENT", []string{"entertainment", "movies", "subscriptions"}},
+	}
+)
+
+// isBudgetRequest reports whether lowercased input sets or asks about a spending budget
+func isBudgetRequest(input string) bool {
+	return strings.Contains(input, "budget") || spendAlertRegex.MatchString(input)
+}
+
+// extractBudgetEntities pulls a budget's monthly limit, with lakh and thousand units,
+// and its category; without a category the budget is for all spending
+func extractBudgetEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	for _, matches := range retryAmountRegex.FindAllStringSubmatch(input, -1) {
+		if amount := scaleAmount(matches[1], matches[2]); amount > 0 {
+			entities["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+			break
+		}
+	}
+	for _, group := range budgetCategoryWords {
+		if containsAny(input, group.words) {
+			entities["category"] = group.category
+			break
+		}
+	}
+	return entities
+}
+
+// isWhyRejected reports whether lowercased input asks why the last request failed
+func isWhyRejected(input string) bool {
+	trimmed := strings.Trim(strings.TrimSpace(input), "?!. ")
+	if trimmed == "why" || trimmed == "why not" {
+		return true
+	}
+	return containsAny(input, []string{"why", "reason", "what happened"}) &&
+		containsAny(input, []string{"reject", "declin", "fail", "block", "denied", "not go through", "didn't go through", "didnt go through", "on hold", "stopped"})
+}
+
+// retryAmountRegex matches an amount with an optional unit, e.g. "50,000" or "1.5 lakh"
+var retryAmountRegex = regexp.MustCompile(`(?:rs\.?|₹|inr)?\s*(\d+(?:,\d+)*(?:\.\d+)?)\s*(lakhs?|lacs?|crores?|k|thousand)?\b`)
+
+// isRetryRequest reports whether lowercased input asks to re-submit the last action,
+// possibly with a changed amount, payee or rail
+func isRetryRequest(input string) bool {
+	return containsAny(input, []string{"instead", "try again", "retry", "resend", "send it again", "do it again", "same again"})
+}
+
+// extractRetryEntities pulls the slots a retry changes: the amount, with lakh and
+// thousand units, and the payment rail
+func extractRetryEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+
+	// Nine or more digits is an account number, not an amount
+	for _, matches := range retryAmountRegex.FindAllStringSubmatch(input, -1) {
+		if len(strings.ReplaceAll(matches[1], ",", "")) >= 9 {
+			continue
+		}
+		if amount := scaleAmount(matches[1], matches[2]); amount > 0 {
+			entities["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+			break
+		}
+	}
+
+	if method := namedTransferMethod(input); method != "" {
+		entities["transfer_method"] = method
+	}
+
+	return entities
+}
+
+// scaleAmount parses an amount such as "1,50,000" and applies a unit such as "lakh" or "k"
+func scaleAmount(value, unit string) float64 {
+	amount := parseAmount(strings.ReplaceAll(value, ",", ""))
+	switch {
+	case strings.HasPrefix(unit, "lakh"), strings.HasPrefix(unit, "lac"):
+		amount *= 100000
+	case strings.HasPrefix(unit, "crore"):
+		amount *= 10000000
+	case unit == "k", unit == "thousand":
+		amount *= 1000
+	}
+	return amount
+}
+
+// extractPreferenceEntities pulls transfer method, amount limit, default account and
+// notification channel out of a lowercased preference statement
+func extractPreferenceEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+
+	if method := namedTransferMethod(input); method != "" {
+		entities["transfer_method"] = method
+	}
+
+	if matches := preferenceLimitRegex.FindStringSubmatch(input); len(matches) > 1 {
+		if limit := scaleAmount(matches[1], matches[2]); limit > 0 {
+			entities["max_amount"] = limit
+		}
+	}
+
+	accountRegex := regexp.MustCompile(`default\s+account\s*(?:to|is|as)?\s*(?:account\s*)?(?:no\.?|number)?\s*([\dx]{4,})`)
+	if matches := accountRegex.FindStringSubmatch(input); len(matches) > 1 {
+		entities["default_account"] = strings.ToUpper(matches[1])
+	}
+
+	channelRegex := regexp.MustCompile(`\b(sms|text|e-?mail|push|whatsapp)\b`)
+	if containsAny(input, []string{"notify", "alert"}) {
+		if matches := channelRegex.FindStringSubmatch(input); len(matches) > 1 {
+			channels := map[string]string{"sms": "SMS", "text": "SMS", "email": "EMAIL", "e-mail": "EMAIL", "push": "PUSH", "whatsapp": "WHATSAPP"}
+			entities["notification_channel"] = channels[matches[1]]
+		}
+	}
+
+	return entities
+}
+
+// namedTransferMethod returns the payment rail named in text (NEFT, RTGS, IMPS, UPI), or ""
+func namedTransferMethod(text string) string {
+	if matches := transferMethodRegex.FindStringSubmatch(text); len(matches) > 1 {
+		return strings.ToUpper(matches[1])
+	}
+	return ""
+}
+
 func containsAny(s string, keywords []string) bool {
 	for _, keyword := range keywords {
 		if strings.Contains(s, keyword) {
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func BenchmarkParseIntent(b *testing.B) {
+	ctx := context.Background()
+	intentParser := NewIntentParser(nil, false)
+
+	cases := []struct {
+		name      string
+		input     string
+		inputType string
+		budget    time.Duration
+	}{
+		{"rules-transfer", benchTransferInput, "natural_language", 200 * time.Microsecond},
+		{"rules-balance", "What is my balance?", "natural_language", 200 * time.Microsecond},
+		{"structured", `{"intent": "TRANSFER_NEFT", "entities": {"amount": 50000, "to_account": "XXXX4321"}}`, "structured", 100 * time.Microsecond},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := intentParser.ParseIntent(ctx, tc.input, tc.inputType, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+			withinBudget(b, tc.budget)
+		})
+	}
+}
+
+func BenchmarkEnrichContext(b *testing.B) {
+	ctx := context.Background()
+	contextEnricher := NewContextEnricher(NewHistoryService(), NewBehaviorAnalyzer(), NewRiskCalculator())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := contextEnricher.EnrichContext(ctx, "U10001", "sess_bench", "MB", benchIntent); err != nil {
+			b.Fatal(err)
+		}
+	}
+	withinBudget(b, 100*time.Microsecond)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// PromptKnowledgeAnswer answers a policy question from retrieved documents
+const PromptKnowledgeAnswer = "knowledge_answer"
+
+// maxKnowledgeExcerpt caps the document text returned with each source
+const maxKnowledgeExcerpt = 300
+
+// KnowledgeService answers policy and FAQ questions from the knowledge collection for
+// other bank systems, without the chat pipeline. Answers are cached per question and
+// callers are limited per minute.
+type KnowledgeService struct {
+	ragService *RAGService
+	llmService *LLMService
+	prompts    *PromptService
+	guard      *PromptGuard
+	cfg        config.KnowledgeConfig
+
+	mu      sync.Mutex
+	cache   map[string]*knowledgeCacheEntry
+	order   []string // Cache keys, oldest first
+	callers map[string]*callerWindow
+}
+
+type knowledgeCacheEntry struct {
+	answer  *model.KnowledgeAnswer
+	expires time.Time
+}
+
+// callerWindow counts one caller's questions in the current minute
+type callerWindow struct {
+	minute time.Time
+	count  int
+}
+
+// NewKnowledgeService creates a new knowledge service
+func NewKnowledgeService(cfg *config.KnowledgeConfig, ragService *RAGService, llmService *LLMService, prompts *PromptService, guard *PromptGuard) *KnowledgeService {
+	ks := &KnowledgeService{
+		ragService: ragService,
+		llmService: llmService,
+		prompts:    prompts,
+		guard:      guard,
+		cfg:        *cfg,
+		cache:      make(map[string]*knowledgeCacheEntry),
+		callers:    make(map[string]*callerWindow),
+	}
+	if ks.cfg.TopK <= 0 {
+		ks.cfg.TopK = 3
+	}
+	return ks
+}
+
+// Allow counts one question for the caller. When the caller is over the limit it
+// returns false and when the current minute ends.
+func (ks *KnowledgeService) Allow(caller string) (time.Time, bool) {
+	now := time.Now()
+	minute := now.Truncate(time.Minute)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	w, ok := ks.callers[caller]
+	if !ok || w.minute.Before(minute) {
+		w = &callerWindow{minute: minute}
+		ks.callers[caller] = w
+	}
+	if ks.cfg.RequestsPerMinute > 0 && w.count >= ks.cfg.RequestsPerMinute {
+		return minute.Add(time.Minute), false
+	}
+	w.count++
+	return time.Time{}, true
+}
+
+// Query answers a question from the best-matching knowledge documents. Synthesis
+// falls back to the best document when the LLM is disabled or fails.
+func (ks *KnowledgeService) Query(ctx context.Context, req *model.KnowledgeQuery) (*model.KnowledgeAnswer, error) {
+	question := strings.Join(strings.Fields(req.Question), " ")
+	topK := req.TopK
+	if topK <= 0 {
+		topK = ks.cfg.TopK
+	}
+	synthesize := ks.cfg.Synthesize
+	if req.Synthesize != nil {
+		synthesize = *req.Synthesize
+	}
+
+	key := fmt.Sprintf("%s|%d|%t", strings.ToLower(question), topK, synthesize)
+	if cached, ok := ks.cached(key); ok {
+		cached.Question = question
+		return cached, nil
+	}
+
+	results, err := ks.ragService.Search(ctx, &model.SearchRequest{
+		Collection: model.CollectionKnowledge,
+		Query:      question,
+		TopK:       topK,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &model.KnowledgeAnswer{
+		Question:   question,
+		Sources:    make([]model.KnowledgeSource, 0, len(results)),
+		AnsweredAt: time.Now(),
+	}
+	var documents []model.SearchResult
+	for _, result := range results {
+		if result.Score < ks.cfg.MinScore {
+			continue
+		}
+		documents = append(documents, result)
+		title, _ := result.Document.Metadata["title"].(string)
+		answer.Sources = append(answer.Sources, model.KnowledgeSource{
+			DocumentID: result.Document.ID,
+			Title:      title,
+			Score:      result.Score,
+			Excerpt:    excerpt(result.Document.Content, maxKnowledgeExcerpt),
+		})
+	}
+	if len(documents) == 0 {
+		ks.store(key, answer)
+		return answer, nil
+	}
+
+	complete := true
+	if synthesize && ks.llmService.Enabled() {
+		text, err := ks.synthesize(ctx, req.UserID, question, documents)
+		if err == nil {
+			answer.Answer, answer.Synthesized = text, true
+		} else {
+			complete = false
+			log.Warn().Err(err).Msg("Knowledge answer synthesis failed, returning the best document")
+		}
+	}
+	if !answer.Synthesized {
+		answer.Answer = documents[0].Document.Content
+	}
+
+	// An answer that fell back because the LLM failed is not kept, so the next
+	// caller gets another try
+	if complete {
+		ks.store(key, answer)
+	}
+	return answer, nil
+}
+
+// synthesize has the LLM answer the question from the retrieved documents
+func (ks *KnowledgeService) synthesize(ctx context.Context, userID, question string, documents []model.SearchResult) (string, error) {
+	docs := make([]map[string]string, 0, len(documents))
+	for _, result := range documents {
+		docs = append(docs, map[string]string{
+			"id":      result.Document.ID,
+			"content": ks.guard.SanitizeRetrieved("knowledge:"+result.Document.ID, result.Document.Content).Text,
+		})
+	}
+
+	prompt, err := ks.prompts.Render(PromptKnowledgeAnswer, model.PromptVars{
+		UserInput: ks.guard.SanitizeUserInput(question).Text,
+		Extra:     map[string]interface{}{"documents": docs},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if userID != "" {
+		ctx = WithQuotaUser(ctx, userID)
+	}
+	text, err := ks.llmService.CallLLM(ctx, ks.llmService.Settings(LLMPurposeChat, nil), prompt.Text)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("empty answer")
+	}
+	return text, nil
+}
+
+// AddDocument adds a policy or FAQ document to the knowledge collection. Cached
+// answers are dropped, so questions are answered afresh once it has been embedded.
+func (ks *KnowledgeService) AddDocument(doc *model.KnowledgeDocument) (*model.Document, error) {
+	metadata := map[string]interface{}{"title": doc.Title}
+	if doc.Source != "" {
+		metadata["source"] = doc.Source
+	}
+	stored, err := ks.ragService.Store(model.CollectionKnowledge, "", doc.Content, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.mu.Lock()
+	ks.cache = make(map[string]*knowledgeCacheEntry)
+	ks.order = nil
+	ks.mu.Unlock()
+	return stored, nil
+}
+
+// cached returns a copy of a live cached answer
+func (ks *KnowledgeService) cached(key string) (*model.KnowledgeAnswer, bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	entry, ok := ks.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	answer := *entry.answer
+	answer.Sources = append([]model.KnowledgeSource(nil), entry.answer.Sources...)
+	answer.Cached = true
+	return &answer, true
+}
+
+// store caches an answer, dropping expired answers and then the oldest when full
+func (ks *KnowledgeService) store(key string, answer *model.KnowledgeAnswer) {
+	if ks.cfg.CacheTTL <= 0 || ks.cfg.CacheSize <= 0 {
+		return
+	}
+	now := time.Now()
+	copied := *answer
+	copied.Sources = append([]model.KnowledgeSource(nil), answer.Sources...)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, ok := ks.cache[key]; !ok {
+		ks.order = append(ks.order, key)
+	}
+	ks.cache[key] = &knowledgeCacheEntry{answer: &copied, expires: now.Add(time.Duration(ks.cfg.CacheTTL) * time.Second)}
+
+	for len(ks.order) > 0 {
+		oldest := ks.order[0]
+		entry, ok := ks.cache[oldest]
+		if ok && len(ks.order) <= ks.cfg.CacheSize && now.Before(entry.expires) {
+			break
+		}
+		delete(ks.cache, oldest)
+		ks.order = ks.order[1:]
+	}
+}
+
+// excerpt shortens text to at most n bytes, cutting at a word boundary
+func excerpt(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	cut := strings.LastIndex(text[:n], " ")
+	if cut <= 0 {
+		cut = n
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimSpace(text[:cut]) + "…"
+}
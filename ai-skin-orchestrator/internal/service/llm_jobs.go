@@ -0,0 +1,651 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// PromptLLMJob carries out a background job's instruction over its documents
+const PromptLLMJob = "llm_job"
+
+// LLM job errors
+var (
+	ErrInvalidLLMJob   = errors.New("invalid LLM job")
+	ErrLLMJobNotFound  = errors.New("LLM job not found")
+	ErrLLMJobFinished  = errors.New("LLM job has already finished")
+	ErrLLMJobQueueFull = errors.New("LLM job queue is full")
+	ErrLLMJobUserLimit = errors.New("too many unfinished LLM jobs for this user")
+	ErrLLMJobQuota     = errors.New("LLM quota exceeded")
+)
+
+// Job callback request headers
+const (
+	HeaderJobID        = "X-Job-ID"
+	HeaderJobTimestamp = "X-Job-Timestamp"
+	HeaderJobSignature = "X-Job-Signature" // Hex HMAC-SHA256 of "<timestamp>.<body>"
+)
+
+const (
+	llmJobPurgeInterval    = 10 * time.Minute
+	llmJobCallbackMaxDelay = time.Minute
+)
+
+// LLMJobQueue runs generations too long for an HTTP request, such as statement
+// summaries and multi-document policy analyses. Jobs wait in their own queue for
+// their own workers, so they never hold up interactive chat, and count against the
+// user's LLM quota like any other call. A caller polls the job or names a callback
+// URL that is posted the job once it finishes. With LLM_JOBS_DIR set every job is
+// written there and jobs that were queued or running when the service stopped are
+// run again when it starts.
+type LLMJobQueue struct {
+	cfg        *config.LLMJobsConfig
+	llm        *LLMService
+	prompts    *PromptService
+	guard      *PromptGuard
+	quota      *LLMQuota
+	httpClient *http.Client
+	secret     *secrets.Value // Signs completion callbacks when set
+	jobs       map[string]*model.LLMJob
+	running    map[string]context.CancelFunc
+	queue      chan string // Job IDs waiting for a worker
+	ctx        context.Context
+	stop       context.CancelFunc
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+}
+
+// NewLLMJobQueue creates the job queue, loads any jobs kept in LLM_JOBS_DIR and
+// starts the workers
+func NewLLMJobQueue(cfg *config.LLMJobsConfig, llm *LLMService, prompts *PromptService, guard *PromptGuard, quota *LLMQuota) (*LLMJobQueue, error) {
+	ctx, stop := context.WithCancel(context.Background())
+	jq := &LLMJobQueue{
+		cfg:        cfg,
+		llm:        llm,
+		prompts:    prompts,
+		guard:      guard,
+		quota:      quota,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.CallbackTimeout) * time.Second},
+		secret:     config.Profile().RotatingSecret("LLM_JOBS_CALLBACK_SECRET", cfg.CallbackSecret),
+		jobs:       make(map[string]*model.LLMJob),
+		running:    make(map[string]context.CancelFunc),
+		ctx:        ctx,
+		stop:       stop,
+	}
+
+	pending, callbacks, err := jq.load()
+	if err != nil {
+		stop()
+		return nil, err
+	}
+
+	// Restored jobs may outnumber the queue size; they are all queued regardless
+	jq.queue = make(chan string, cfg.QueueSize+len(pending))
+	for _, id := range pending {
+		jq.queue <- id
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		jq.wg.Add(1)
+		go jq.worker()
+	}
+	for _, id := range callbacks {
+		jq.wg.Add(1)
+		go jq.deliver(id)
+	}
+	go jq.purgeLoop()
+
+	log.Info().
+		Int("workers", workers).
+		Int("queue_size", cfg.QueueSize).
+		Int("restored", len(jq.jobs)).
+		Int("requeued", len(pending)).
+		Bool("persisted", cfg.Dir != "").
+		Msg("LLM job queue started")
+	return jq, nil
+}
+
+// Submit validates a job and queues it
+func (jq *LLMJobQueue) Submit(req *model.LLMJobRequest) (*model.LLMJob, error) {
+	job, err := jq.newJob(req)
+	if err != nil {
+		return nil, err
+	}
+	if !jq.llm.Enabled() {
+		return nil, ErrLLMDisabled
+	}
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	queued, unfinished := 0, 0
+	for _, j := range jq.jobs {
+		if j.Status == model.LLMJobQueued {
+			queued++
+		}
+		if j.UserID == job.UserID && !j.Finished() {
+			unfinished++
+		}
+	}
+	if queued >= jq.cfg.QueueSize {
+		return nil, ErrLLMJobQueueFull
+	}
+	if jq.cfg.PerUser > 0 && unfinished >= jq.cfg.PerUser {
+		return nil, fmt.Errorf("%w (%d)", ErrLLMJobUserLimit, jq.cfg.PerUser)
+	}
+	if status, ok := jq.quota.Allow(job.UserID); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLLMJobQuota, status.Exceeded)
+	}
+
+	jq.jobs[job.JobID] = job
+	jq.persist(job)
+	select {
+	case jq.queue <- job.JobID:
+	default:
+		// Only when restored jobs filled the queue's spare room
+		go func() { jq.queue <- job.JobID }()
+	}
+
+	log.Info().
+		Str("job_id", job.JobID).
+		Str("user_id", job.UserID).
+		Int("documents", len(job.Documents)).
+		Msg("LLM job queued")
+	return jq.copyOf(job), nil
+}
+
+// newJob checks a request and builds the job it asks for
+func (jq *LLMJobQueue) newJob(req *model.LLMJobRequest) (*model.LLMJob, error) {
+	if strings.TrimSpace(req.UserID) == "" {
+		return nil, fmt.Errorf("%w: user_id is required", ErrInvalidLLMJob)
+	}
+	if strings.TrimSpace(req.Instruction) == "" {
+		return nil, fmt.Errorf("%w: instruction is required", ErrInvalidLLMJob)
+	}
+	if len(req.Documents) > jq.cfg.MaxDocuments {
+		return nil, fmt.Errorf("%w: at most %d documents are allowed", ErrInvalidLLMJob, jq.cfg.MaxDocuments)
+	}
+	if err := jq.llm.ValidateOverrides(req.LLM); err != nil {
+		return nil, err
+	}
+
+	job := &model.LLMJob{
+		JobID:       ids.New(ids.LLMJob),
+		UserID:      req.UserID,
+		Status:      model.LLMJobQueued,
+		Instruction: req.Instruction,
+		LLM:         req.LLM,
+		CreatedAt:   time.Now(),
+	}
+	for i, doc := range req.Documents {
+		if strings.TrimSpace(doc.Content) == "" {
+			return nil, fmt.Errorf("%w: document %d has no content", ErrInvalidLLMJob, i+1)
+		}
+		if strings.TrimSpace(doc.Source) == "" {
+			doc.Source = fmt.Sprintf("document_%d", i+1)
+		}
+		job.Documents = append(job.Documents, doc)
+	}
+
+	if req.CallbackURL != "" {
+		endpoint, err := url.Parse(req.CallbackURL)
+		if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+			return nil, fmt.Errorf("%w: callback_url must be an absolute http or https URL", ErrInvalidLLMJob)
+		}
+		if endpoint.Scheme == "http" && !jq.cfg.CallbackAllowHTTP {
+			return nil, fmt.Errorf("%w: callback_url must use https", ErrInvalidLLMJob)
+		}
+		job.Callback = &model.JobCallback{URL: req.CallbackURL}
+	}
+	return job, nil
+}
+
+// Get returns a job
+func (jq *LLMJobQueue) Get(jobID string) (*model.LLMJob, bool) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	job, ok := jq.jobs[jobID]
+	if !ok {
+		return nil, false
+	}
+	return jq.copyOf(job), true
+}
+
+// List returns a user's jobs, or everyone's, newest first, optionally of one status.
+// Documents are left out.
+func (jq *LLMJobQueue) List(userID, status string, limit int) []*model.LLMJob {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	jobs := make([]*model.LLMJob, 0)
+	for _, job := range jq.jobs {
+		if (userID != "" && job.UserID != userID) || (status != "" && job.Status != status) {
+			continue
+		}
+		summary := jq.copyOf(job)
+		summary.Documents = nil
+		jobs = append(jobs, summary)
+	}
+	// Job IDs sort in the order they were created
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].JobID > jobs[b].JobID })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs
+}
+
+// Cancel stops a job that has not finished. A running job's LLM call is abandoned.
+func (jq *LLMJobQueue) Cancel(jobID string) (*model.LLMJob, error) {
+	jq.mu.Lock()
+	job, ok := jq.jobs[jobID]
+	if !ok {
+		jq.mu.Unlock()
+		return nil, ErrLLMJobNotFound
+	}
+	if job.Finished() {
+		jq.mu.Unlock()
+		return nil, ErrLLMJobFinished
+	}
+
+	if cancel, running := jq.running[jobID]; running {
+		cancel()
+	}
+	jq.finish(job, model.LLMJobCancelled, "", "")
+	cancelled := jq.copyOf(job)
+	jq.mu.Unlock()
+
+	log.Info().Str("job_id", jobID).Msg("LLM job cancelled")
+	return cancelled, nil
+}
+
+// Stats reports how busy the workers are and how many jobs are in each status
+func (jq *LLMJobQueue) Stats() *model.LLMJobStats {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	stats := &model.LLMJobStats{
+		Workers:   jq.cfg.Workers,
+		QueueSize: jq.cfg.QueueSize,
+		ByStatus:  make(map[string]int),
+		Persisted: jq.cfg.Dir != "",
+	}
+	for _, job := range jq.jobs {
+		stats.ByStatus[job.Status]++
+	}
+	stats.Queued = stats.ByStatus[model.LLMJobQueued]
+	stats.Running = stats.ByStatus[model.LLMJobRunning]
+	return stats
+}
+
+// Stop stops the workers and waits for them. Jobs that were running are left queued
+// so they run again after a restart, when jobs are persisted.
+func (jq *LLMJobQueue) Stop(ctx context.Context) {
+	jq.stop()
+
+	done := make(chan struct{})
+	go func() {
+		jq.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("LLM job workers stopped")
+	case <-ctx.Done():
+		log.Warn().Msg("LLM job workers did not stop in time")
+	}
+}
+
+// worker runs queued jobs until the queue stops
+func (jq *LLMJobQueue) worker() {
+	defer jq.wg.Done()
+
+	for {
+		select {
+		case <-jq.ctx.Done():
+			return
+		case id := <-jq.queue:
+			jq.run(id)
+		}
+	}
+}
+
+// run generates one job's result
+func (jq *LLMJobQueue) run(jobID string) {
+	jq.mu.Lock()
+	job, ok := jq.jobs[jobID]
+	if !ok || job.Status != model.LLMJobQueued {
+		// Cancelled or purged while it waited
+		jq.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	job.Status = model.LLMJobRunning
+	job.StartedAt = &now
+	job.Attempts++
+	ctx, cancel := context.WithTimeout(WithQuotaUser(jq.ctx, job.UserID), time.Duration(jq.cfg.Timeout)*time.Second)
+	jq.running[jobID] = cancel
+	jq.persist(job)
+	instruction, documents, overrides := job.Instruction, job.Documents, job.LLM
+	jq.mu.Unlock()
+	defer cancel()
+
+	settings := jq.llm.Settings(LLMPurposeChat, overrides)
+	result, flagged, err := jq.generate(ctx, instruction, documents, settings)
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+	delete(jq.running, jobID)
+	if job.Status == model.LLMJobCancelled {
+		return
+	}
+	job.Model = settings.Model
+	job.Flagged = flagged
+
+	switch {
+	case err != nil && jq.ctx.Err() != nil:
+		// Shutting down: run it again after the restart
+		job.Status = model.LLMJobQueued
+		job.StartedAt = nil
+		jq.persist(job)
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		jq.finish(job, model.LLMJobFailed, "", fmt.Sprintf("timed out after %ds", jq.cfg.Timeout))
+	case err != nil:
+		jq.finish(job, model.LLMJobFailed, "", err.Error())
+	default:
+		jq.finish(job, model.LLMJobSucceeded, result, "")
+	}
+
+	log.Info().
+		Str("job_id", jobID).
+		Str("status", job.Status).
+		Dur("duration", time.Since(now)).
+		Msg("LLM job finished")
+}
+
+// generate renders the job prompt with its documents and calls the LLM
+func (jq *LLMJobQueue) generate(ctx context.Context, instruction string, documents []model.LLMJobDocument, settings model.LLMSettings) (string, []string, error) {
+	var flagged []string
+	docs := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		guarded := jq.guard.SanitizeDocument(doc.Source, doc.Content, jq.cfg.MaxDocumentChars)
+		flagged = append(flagged, guarded.Flagged...)
+		docs = append(docs, guarded.Text)
+	}
+	input := jq.guard.SanitizeUserInput(instruction)
+	flagged = append(flagged, input.Flagged...)
+
+	prompt, err := jq.prompts.Render(PromptLLMJob, model.PromptVars{
+		UserInput: input.Text,
+		Extra:     map[string]interface{}{"documents": docs},
+	})
+	if err != nil {
+		return "", flagged, err
+	}
+
+	text, err := jq.llm.CallLLM(ctx, settings, prompt.Text)
+	if err != nil {
+		return "", flagged, err
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return "", flagged, fmt.Errorf("empty result")
+	}
+	return text, flagged, nil
+}
+
+// finish records a job's outcome and starts its callback. Callers hold jq.mu.
+func (jq *LLMJobQueue) finish(job *model.LLMJob, status, result, errText string) {
+	now := time.Now()
+	job.Status = status
+	job.Result = result
+	job.Error = errText
+	job.CompletedAt = &now
+	if job.Callback != nil {
+		job.Callback.Status = model.CallbackPending
+	}
+	jq.persist(job)
+
+	if job.Callback != nil {
+		jq.wg.Add(1)
+		go jq.deliver(job.JobID)
+	}
+}
+
+// deliver posts a finished job to its callback URL, retrying with doubling backoff
+// until it is accepted or its attempts run out
+func (jq *LLMJobQueue) deliver(jobID string) {
+	defer jq.wg.Done()
+
+	for {
+		jq.mu.Lock()
+		job, ok := jq.jobs[jobID]
+		if !ok || job.Callback == nil || job.Callback.Status != model.CallbackPending {
+			jq.mu.Unlock()
+			return
+		}
+		payload := jq.copyOf(job)
+		payload.Documents = nil
+		jq.mu.Unlock()
+
+		err := jq.post(payload)
+
+		jq.mu.Lock()
+		callback := job.Callback
+		callback.Attempts++
+		if err == nil {
+			now := time.Now()
+			callback.Status = model.CallbackDelivered
+			callback.LastError = ""
+			callback.DeliveredAt = &now
+		} else {
+			callback.LastError = err.Error()
+			if callback.Attempts >= jq.cfg.CallbackAttempts {
+				callback.Status = model.CallbackFailed
+			}
+		}
+		jq.persist(job)
+		attempts, status := callback.Attempts, callback.Status
+		jq.mu.Unlock()
+
+		if status != model.CallbackPending {
+			if status == model.CallbackFailed {
+				log.Warn().Err(err).Str("job_id", jobID).Int("attempts", attempts).Msg("LLM job callback failed")
+			}
+			return
+		}
+
+		select {
+		case <-jq.ctx.Done():
+			return
+		case <-time.After(callbackBackoff(attempts)):
+		}
+	}
+}
+
+// post sends one callback, signed when a secret is configured
+func (jq *LLMJobQueue) post(job *model.LLMJob) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(jq.ctx, http.MethodPost, job.Callback.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderJobID, job.JobID)
+	req.Header.Set(HeaderJobTimestamp, timestamp)
+	if secret := jq.secret.Get(); secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		req.Header.Set(HeaderJobSignature, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := jq.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// callbackBackoff waits a second after the first failed attempt, doubling up to a minute
+func callbackBackoff(attempts int) time.Duration {
+	wait := time.Second
+	for i := 1; i < attempts && wait < llmJobCallbackMaxDelay; i++ {
+		wait *= 2
+	}
+	if wait > llmJobCallbackMaxDelay {
+		wait = llmJobCallbackMaxDelay
+	}
+	return wait
+}
+
+// purgeLoop removes finished jobs past LLM_JOBS_RETENTION_HOURS until the queue stops
+func (jq *LLMJobQueue) purgeLoop() {
+	if jq.cfg.RetentionHours <= 0 {
+		return
+	}
+	ticker := time.NewTicker(llmJobPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jq.ctx.Done():
+			return
+		case <-ticker.C:
+			jq.purge()
+		}
+	}
+}
+
+func (jq *LLMJobQueue) purge() {
+	cutoff := time.Now().Add(-time.Duration(jq.cfg.RetentionHours) * time.Hour)
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+	purged := 0
+	for id, job := range jq.jobs {
+		if !job.Finished() || job.CompletedAt == nil || job.CompletedAt.After(cutoff) {
+			continue
+		}
+		if job.Callback != nil && job.Callback.Status == model.CallbackPending {
+			continue
+		}
+		delete(jq.jobs, id)
+		if jq.cfg.Dir != "" {
+			if err := os.Remove(jq.path(id)); err != nil && !os.IsNotExist(err) {
+				log.Warn().Err(err).Str("job_id", id).Msg("Failed to remove LLM job file")
+			}
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Info().Int("jobs", purged).Msg("Purged finished LLM jobs")
+	}
+}
+
+// load reads the jobs kept in LLM_JOBS_DIR. Jobs that were queued or running are
+// returned to be queued again, oldest first, and those with a callback still to
+// deliver to have it resent.
+func (jq *LLMJobQueue) load() (pending, callbacks []string, err error) {
+	if jq.cfg.Dir == "" {
+		return nil, nil, nil
+	}
+	if err := os.MkdirAll(jq.cfg.Dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create LLM jobs directory: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(jq.cfg.Dir, ids.LLMJob+"_*.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read LLM job: %w", err)
+		}
+		var job model.LLMJob
+		if err := json.Unmarshal(data, &job); err != nil || job.JobID == "" {
+			log.Warn().Err(err).Str("file", file).Msg("Skipping unreadable LLM job")
+			continue
+		}
+		if job.Status == model.LLMJobRunning {
+			job.Status = model.LLMJobQueued
+			job.StartedAt = nil
+		}
+		if job.Status == model.LLMJobQueued {
+			pending = append(pending, job.JobID)
+		}
+		if job.Callback != nil && job.Callback.Status == model.CallbackPending {
+			callbacks = append(callbacks, job.JobID)
+		}
+		jq.jobs[job.JobID] = &job
+	}
+	sort.Strings(pending)
+	return pending, callbacks, nil
+}
+
+// persist writes a job to LLM_JOBS_DIR, replacing the file whole. Callers hold jq.mu.
+func (jq *LLMJobQueue) persist(job *model.LLMJob) {
+	if jq.cfg.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(job)
+	if err == nil {
+		tmp := jq.path(job.JobID) + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o600); err == nil {
+			err = os.Rename(tmp, jq.path(job.JobID))
+		}
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("job_id", job.JobID).Msg("Failed to persist LLM job")
+	}
+}
+
+func (jq *LLMJobQueue) path(jobID string) string {
+	return filepath.Join(jq.cfg.Dir, jobID+".json")
+}
+
+// copyOf returns a copy of a job callers may read without jq.mu. Callers hold jq.mu.
+func (jq *LLMJobQueue) copyOf(job *model.LLMJob) *model.LLMJob {
+	c := *job
+	if job.Callback != nil {
+		callback := *job.Callback
+		c.Callback = &callback
+	}
+	return &c
+}
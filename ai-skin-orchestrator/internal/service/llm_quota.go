@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// quotaUserKey carries the user LLM tokens are charged to
+type quotaUserKey struct{}
+
+// WithQuotaUser returns a context whose LLM calls are charged to userID
+func WithQuotaUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, quotaUserKey{}, userID)
+}
+
+// quotaUser returns the user LLM calls on ctx are charged to, or ""
+func quotaUser(ctx context.Context) string {
+	userID, _ := ctx.Value(quotaUserKey{}).(string)
+	return userID
+}
+
+// userQuota is one user's usage in the current windows
+type userQuota struct {
+	minute   time.Time // Start of the request window
+	requests int
+	day      time.Time // Start of the UTC day tokens are counted for
+	tokens   int
+}
+
+// LLMQuota limits LLM-backed requests per user per minute and LLM tokens per user
+// per UTC day. Requests over quota are not rejected; callers fall back to rule-based
+// handling instead.
+type LLMQuota struct {
+	requestsPerMinute int
+	tokensPerDay      int
+	users             map[string]*userQuota
+	mu                sync.Mutex
+}
+
+// NewLLMQuota creates a new LLM quota tracker
+func NewLLMQuota(cfg *config.QuotaConfig) *LLMQuota {
+	q := &LLMQuota{
+		requestsPerMinute: cfg.RequestsPerMinute,
+		tokensPerDay:      cfg.TokensPerDay,
+		users:             make(map[string]*userQuota),
+	}
+
+	go q.cleanup()
+
+	return q
+}
+
+// Allow counts one LLM-backed request for the user if both limits have room. When
+// it returns false the status names the exceeded limit and nothing is counted.
+func (q *LLMQuota) Allow(userID string) (model.QuotaStatus, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.current(userID, time.Now())
+	status := q.status(userID, u)
+
+	switch {
+	case q.requestsPerMinute > 0 && u.requests >= q.requestsPerMinute:
+		status.Exceeded = model.QuotaRequestsPerMinute
+	case q.tokensPerDay > 0 && u.tokens >= q.tokensPerDay:
+		status.Exceeded = model.QuotaTokensPerDay
+	default:
+		u.requests++
+		return q.status(userID, u), true
+	}
+
+	log.Warn().
+		Str("user_id", userID).
+		Str("limit", status.Exceeded).
+		Msg("LLM quota exceeded, using rule-based fallback")
+	return status, false
+}
+
+// AddTokens charges tokens used by an LLM call to the user
+func (q *LLMQuota) AddTokens(userID string, tokens int) {
+	if userID == "" || tokens <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.current(userID, time.Now()).tokens += tokens
+}
+
+// Status returns the user's usage without counting a request
+func (q *LLMQuota) Status(userID string) model.QuotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.status(userID, q.current(userID, time.Now()))
+}
+
+// current returns the user's usage, starting new windows once the old ones have passed
+func (q *LLMQuota) current(userID string, now time.Time) *userQuota {
+	minute := now.Truncate(time.Minute)
+	day := now.UTC().Truncate(24 * time.Hour)
+
+	u, ok := q.users[userID]
+	if !ok {
+		u = &userQuota{minute: minute, day: day}
+		q.users[userID] = u
+	}
+	if u.minute.Before(minute) {
+		u.minute, u.requests = minute, 0
+	}
+	if u.day.Before(day) {
+		u.day, u.tokens = day, 0
+	}
+	return u
+}
+
+// status reports usage against the configured limits
+func (q *LLMQuota) status(userID string, u *userQuota) model.QuotaStatus {
+	return model.QuotaStatus{
+		UserID:            userID,
+		RequestsLimit:     q.requestsPerMinute,
+		RequestsRemaining: remaining(q.requestsPerMinute, u.requests),
+		RequestsReset:     u.minute.Add(time.Minute),
+		TokensLimit:       q.tokensPerDay,
+		TokensUsed:        u.tokens,
+		TokensRemaining:   remaining(q.tokensPerDay, u.tokens),
+		TokensReset:       u.day.Add(24 * time.Hour),
+	}
+}
+
+// remaining is what is left of a limit, or zero when the limit is not enforced
+func remaining(limit, used int) int {
+	if limit <= 0 || used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// cleanup drops users whose windows have all expired
+func (q *LLMQuota) cleanup() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		q.mu.Lock()
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		for userID, u := range q.users {
+			if u.day.Before(today) {
+				delete(q.users, userID)
+			}
+		}
+		q.mu.Unlock()
+	}
+}
@@ -3,31 +3,98 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
 	"github.com/rs/zerolog/log"
 	"github.com/sashabaranov/go-openai"
 )
 
+// LLMPurpose selects the default settings profile for an LLM call
+type LLMPurpose string
+
+const (
+	LLMPurposeIntent LLMPurpose = "intent" // Structured intent extraction
+	LLMPurposeChat   LLMPurpose = "chat"   // Conversational answers
+)
+
+const (
+	ProviderOllama = "ollama"
+
+	minLLMTemperature  = 0.0
+	maxLLMTemperature  = 2.0
+	sessionOverrideTTL = 24 * time.Hour // Matches the MCP session lifetime
+)
+
+// ErrInvalidLLMOverrides is returned when requested LLM settings are not allowed
+var ErrInvalidLLMOverrides = errors.New("invalid LLM overrides")
+
 // LLMService handles LLM interactions for intent parsing and natural language understanding
 type LLMService struct {
 	client    *openai.Client
+	ollama    *OllamaService // Set when the provider is Ollama
 	enabled   bool
-	model     string
-	temperature float64
-	maxTokens int
+	profiles  map[LLMPurpose]model.LLMSettings
+	allowedModels  map[string]bool
+	maxTokensLimit int
+	sessionOverrides map[string]*model.SessionLLMOverrides
+	mu        sync.RWMutex
+	prompts   *PromptService
+	guard     *PromptGuard
+	quota     *LLMQuota
+	calls     *llmCallLog
+	fewShot   *FewShotLibrary // Examples for the intent prompt; none when nil
 }
 
 // NewLLMService creates a new LLM service
-func NewLLMService(cfg *config.LLMConfig) *LLMService {
-	if !cfg.Enabled || cfg.APIKey == "" {
-		log.Info().Msg("LLM service disabled or API key not provided")
-		return &LLMService{
-			enabled: false,
+func NewLLMService(cfg *config.LLMConfig, ollama *OllamaService, prompts *PromptService, guard *PromptGuard, quota *LLMQuota) *LLMService {
+	ls := &LLMService{
+		profiles: map[LLMPurpose]model.LLMSettings{
+			LLMPurposeIntent: {Model: cfg.IntentModel, Temperature: cfg.IntentTemperature, MaxTokens: cfg.MaxTokens},
+			LLMPurposeChat:   {Model: cfg.ChatModel, Temperature: cfg.ChatTemperature, MaxTokens: cfg.MaxTokens},
+		},
+		allowedModels:    make(map[string]bool),
+		maxTokensLimit:   cfg.MaxTokensLimit,
+		sessionOverrides: make(map[string]*model.SessionLLMOverrides),
+		prompts:          prompts,
+		guard:            guard,
+		quota:            quota,
+		calls:            &llmCallLog{},
+	}
+
+	// The configured defaults are always selectable
+	for _, name := range append([]string{cfg.Model, cfg.IntentModel, cfg.ChatModel}, cfg.AllowedModels...) {
+		if name != "" {
+			ls.allowedModels[name] = true
 		}
 	}
+	if ls.maxTokensLimit < cfg.MaxTokens {
+		ls.maxTokensLimit = cfg.MaxTokens
+	}
+
+	if !cfg.Enabled {
+		log.Info().Msg("LLM service disabled")
+		return ls
+	}
+
+	// Ollama needs no API key and uses its native chat API
+	if cfg.Provider == ProviderOllama {
+		ls.ollama = ollama
+		ls.enabled = true
+		return ls
+	}
+
+	if cfg.APIKey == "" {
+		log.Info().Msg("LLM service disabled or API key not provided")
+		return ls
+	}
 
 	var client *openai.Client
 	if cfg.BaseURL != "" {
@@ -40,45 +107,49 @@ func NewLLMService(cfg *config.LLMConfig) *LLMService {
 		client = openai.NewClient(cfg.APIKey)
 	}
 
-	return &LLMService{
-		client:      client,
-		enabled:     true,
-		model:       cfg.Model,
-		temperature: cfg.Temperature,
-		maxTokens:   cfg.MaxTokens,
+	ls.client = client
+	ls.enabled = true
+	return ls
+}
+
+// SetFewShot has intent parsing show the LLM the examples most similar to the input
+func (ls *LLMService) SetFewShot(fewShot *FewShotLibrary) {
+	ls.fewShot = fewShot
+}
+
+// CallLLM calls the LLM with the active banking system prompt and returns the response
+func (ls *LLMService) CallLLM(ctx context.Context, settings model.LLMSettings, prompt string) (string, error) {
+	system, err := ls.prompts.Render(PromptBankingSystem, model.PromptVars{})
+	if err != nil {
+		return "", err
 	}
+	return ls.CallLLMWithSystem(ctx, settings, system.Text, prompt)
 }
 
-// CallLLM calls the LLM with a prompt and returns the response
-func (ls *LLMService) CallLLM(ctx context.Context, prompt string) (string, error) {
+// CallLLMWithSystem calls the LLM with an explicit system prompt
+func (ls *LLMService) CallLLMWithSystem(ctx context.Context, settings model.LLMSettings, systemPrompt, prompt string) (string, error) {
 	if !ls.enabled {
 		return "", fmt.Errorf("LLM service is disabled")
 	}
 
-	resp, err := ls.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: ls.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			Temperature: float32(ls.temperature),
-			MaxTokens:   ls.maxTokens,
-		},
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("LLM API error: %w", err)
+	messages := []openai.ChatCompletionMessage{}
+	if systemPrompt != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: systemPrompt,
+		})
 	}
+	messages = append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: prompt,
+	})
 
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from LLM")
+	msg, _, err := ls.chat(ctx, settings, messages, nil, nil, nil)
+	if err != nil {
+		return "", err
 	}
 
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content := strings.TrimSpace(msg.Content)
 	
 	// Try to extract JSON if response is wrapped
 	if strings.HasPrefix(content, "```json") {
@@ -95,26 +166,38 @@ func (ls *LLMService) CallLLM(ctx context.Context, prompt string) (string, error
 }
 
 // ParseIntentWithLLM uses LLM to parse natural language intent
-func (ls *LLMService) ParseIntentWithLLM(ctx context.Context, userInput string) (map[string]interface{}, error) {
-	prompt := fmt.Sprintf(`You are a banking assistant. Analyze the following user request and extract:
-1. Intent type (one of: TRANSFER_NEFT, TRANSFER_RTGS, TRANSFER_IMPS, TRANSFER_UPI, CHECK_BALANCE, GET_STATEMENT, ADD_BENEFICIARY, APPLY_LOAN, CREDIT_SCORE)
-2. Entities (amount, account number, beneficiary name, IFSC code, etc.)
-3. Confidence score (0.0 to 1.0)
-
-User request: "%s"
-
-Respond ONLY with valid JSON in this format:
-{
-  "intent": "INTENT_TYPE",
-  "confidence": 0.95,
-  "entities": {
-    "amount": 50000,
-    "to_account": "XXXX4321",
-    "ifsc": "BANK0001234"
-  }
-}`, userInput)
-
-	response, err := ls.CallLLM(ctx, prompt)
+func (ls *LLMService) ParseIntentWithLLM(ctx context.Context, userInput string, overrides *model.LLMOverrides) (map[string]interface{}, error) {
+	sanitized := ls.guard.SanitizeUserInput(userInput)
+
+	// Retrieval is skipped when the request's budget is nearly spent
+	var examples []model.FewShotMatch
+	if ls.fewShot != nil && budgetFrom(ctx).useRAG(model.BudgetStageFewShot) {
+		examples = ls.fewShot.Select(ctx, sanitized.Text)
+	}
+	prompt, err := ls.prompts.Render(PromptIntentExtraction, model.PromptVars{UserInput: sanitized.Text, Examples: promptExamples(examples)})
+	if err != nil {
+		return nil, err
+	}
+
+	settings := ls.Settings(LLMPurposeIntent, overrides)
+	log.Debug().
+		Str("prompt", prompt.Name).
+		Int("version", prompt.Version).
+		Str("model", settings.Model).
+		Int("examples", len(examples)).
+		Msg("Rendered intent prompt")
+
+	result, err := ls.intentResult(ctx, settings, prompt.Text)
+	if ls.fewShot != nil {
+		intent, _ := result["intent"].(string)
+		ls.fewShot.RecordHits(examples, intent)
+	}
+	return result, err
+}
+
+// intentResult runs an intent prompt and returns the intent the LLM answered with
+func (ls *LLMService) intentResult(ctx context.Context, settings model.LLMSettings, prompt string) (map[string]interface{}, error) {
+	response, err := ls.CallLLM(ctx, settings, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +207,327 @@ Respond ONLY with valid JSON in this format:
 		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
 
+	// Never act on output that does not fit the intent schema
+	if err := ls.guard.ValidateIntentOutput(result); err != nil {
+		log.Warn().Err(err).Msg("LLM output rejected by schema validation")
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// SplitUtteranceWithLLM asks the LLM to split input holding several requests into
+// segments, in order. Segments that are not verbatim parts of the input are rejected.
+func (ls *LLMService) SplitUtteranceWithLLM(ctx context.Context, userInput string, overrides *model.LLMOverrides) ([]string, error) {
+	sanitized := ls.guard.SanitizeUserInput(userInput)
+
+	prompt, err := ls.prompts.Render(PromptUtteranceSplit, model.PromptVars{UserInput: sanitized.Text})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := ls.CallLLM(ctx, ls.Settings(LLMPurposeIntent, overrides), prompt.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Segments []string `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse utterance split response: %w", err)
+	}
+
+	// Rewritten segments could carry text the user never wrote
+	lower := strings.ToLower(sanitized.Text)
+	segments := make([]string, 0, len(result.Segments))
+	for _, segment := range result.Segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		if !strings.Contains(lower, strings.ToLower(segment)) {
+			return nil, fmt.Errorf("LLM returned a segment that is not part of the input")
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("LLM returned no segments")
+	}
+	return segments, nil
+}
+
+// ToolExecutor runs one tool call requested by the model and returns the content fed back to it
+type ToolExecutor func(ctx context.Context, call openai.ToolCall) string
+
+// DeltaHandler receives answer text as it is generated
+type DeltaHandler func(delta string)
+
+// ToolLoopResult is the outcome of a tool-calling conversation
+type ToolLoopResult struct {
+	Answer     string
+	Iterations int
+	Partial    bool // The answer stream dropped and could not be recovered
+}
+
+// RunToolLoop lets the model call tools until it produces a final answer. After
+// maxIterations rounds of tool calls the model is asked to answer without tools.
+// With onDelta set the answer is streamed where the provider supports it.
+func (ls *LLMService) RunToolLoop(ctx context.Context, settings model.LLMSettings, systemPrompt, userMessage string, tools []openai.Tool, execute ToolExecutor, maxIterations int, onDelta DeltaHandler) (*ToolLoopResult, error) {
+	if !ls.enabled {
+		return nil, fmt.Errorf("LLM service is disabled")
+	}
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: userMessage},
+	}
+
+	for iteration := 1; iteration <= maxIterations; iteration++ {
+		msg, partial, err := ls.chat(ctx, settings, messages, tools, nil, onDelta)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(msg.ToolCalls) == 0 {
+			return &ToolLoopResult{Answer: strings.TrimSpace(msg.Content), Iterations: iteration, Partial: partial}, nil
+		}
+
+		messages = append(messages, msg)
+		for _, call := range msg.ToolCalls {
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    execute(ctx, call),
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	log.Warn().Int("max_iterations", maxIterations).Msg("Tool loop limit reached, forcing final answer")
+
+	msg, partial, err := ls.chat(ctx, settings, messages, tools, "none", onDelta)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolLoopResult{Answer: strings.TrimSpace(msg.Content), Iterations: maxIterations + 1, Partial: partial}, nil
+}
+
+// chat sends one chat completion request with tools and returns the model's message and
+// whether it is an unrecovered partial answer
+func (ls *LLMService) chat(ctx context.Context, settings model.LLMSettings, messages []openai.ChatCompletionMessage, tools []openai.Tool, toolChoice any, onDelta DeltaHandler) (_ openai.ChatCompletionMessage, _ bool, err error) {
+	defer func() { ls.calls.record(err) }()
+
+	if ls.ollama != nil {
+		// Ollama has no tool_choice, so "none" is expressed by offering no tools
+		if toolChoice == "none" {
+			tools = nil
+		}
+		msg, partial, tokens, err := ls.ollama.ChatCompletion(ctx, settings, messages, tools, onDelta)
+		if err != nil {
+			return openai.ChatCompletionMessage{}, false, fmt.Errorf("LLM API error: %w", err)
+		}
+		ls.quota.AddTokens(quotaUser(ctx), tokens)
+		return msg, partial, nil
+	}
+
+	resp, err := ls.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       settings.Model,
+		Messages:    messages,
+		Tools:       tools,
+		ToolChoice:  toolChoice,
+		Temperature: requestTemperature(settings.Temperature),
+		MaxTokens:   settings.MaxTokens,
+	})
+	if err != nil {
+		return openai.ChatCompletionMessage{}, false, fmt.Errorf("LLM API error: %w", err)
+	}
+
+	ls.quota.AddTokens(quotaUser(ctx), resp.Usage.TotalTokens)
+
+	if len(resp.Choices) == 0 {
+		return openai.ChatCompletionMessage{}, false, fmt.Errorf("no response from LLM")
+	}
+
+	// Streaming is Ollama-only; other providers deliver the final answer in one piece
+	msg := resp.Choices[0].Message
+	if onDelta != nil && len(msg.ToolCalls) == 0 && msg.Content != "" {
+		onDelta(msg.Content)
+	}
+	return msg, false, nil
+}
+
+// requestTemperature converts a temperature for the API request. The client drops a
+// zero temperature as unset, which providers treat as their default (usually 1.0).
+func requestTemperature(t float64) float32 {
+	if t == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+	return float32(t)
+}
+
+// CallStats returns how many LLM calls were made in the last window and how many failed
+func (ls *LLMService) CallStats(window time.Duration) *model.LLMCallStats {
+	return ls.calls.stats(window)
+}
+
+// Models returns the distinct models used by the default profiles
+func (ls *LLMService) Models() []string {
+	seen := make(map[string]bool)
+	var models []string
+	for _, purpose := range []LLMPurpose{LLMPurposeIntent, LLMPurposeChat} {
+		if name := ls.profiles[purpose].Model; name != "" && !seen[name] {
+			seen[name] = true
+			models = append(models, name)
+		}
+	}
+	return models
+}
+
+// Enabled reports whether the LLM is configured
+func (ls *LLMService) Enabled() bool {
+	return ls.enabled
+}
+
+// Settings returns the settings for a purpose with already validated overrides applied
+func (ls *LLMService) Settings(purpose LLMPurpose, overrides *model.LLMOverrides) model.LLMSettings {
+	settings, ok := ls.profiles[purpose]
+	if !ok {
+		settings = ls.profiles[LLMPurposeChat]
+	}
+	if overrides.IsEmpty() {
+		return settings
+	}
+
+	if overrides.Model != "" {
+		settings.Model = overrides.Model
+	}
+	if overrides.Temperature != nil {
+		settings.Temperature = *overrides.Temperature
+	}
+	if overrides.MaxTokens > 0 {
+		settings.MaxTokens = overrides.MaxTokens
+	}
+	return settings
+}
+
+// ValidateOverrides checks overrides against the model allow-list and limits
+func (ls *LLMService) ValidateOverrides(overrides *model.LLMOverrides) error {
+	if overrides.IsEmpty() {
+		return nil
+	}
+
+	if overrides.Model != "" && !ls.allowedModels[overrides.Model] {
+		return fmt.Errorf("%w: model %q is not allowed", ErrInvalidLLMOverrides, overrides.Model)
+	}
+	if t := overrides.Temperature; t != nil && (*t < minLLMTemperature || *t > maxLLMTemperature) {
+		return fmt.Errorf("%w: temperature must be between %.1f and %.1f", ErrInvalidLLMOverrides, minLLMTemperature, maxLLMTemperature)
+	}
+	if overrides.MaxTokens < 0 || overrides.MaxTokens > ls.maxTokensLimit {
+		return fmt.Errorf("%w: max_tokens must be between 1 and %d", ErrInvalidLLMOverrides, ls.maxTokensLimit)
+	}
+	return nil
+}
+
+// ResolveOverrides validates request overrides and layers them over any stored for the session
+func (ls *LLMService) ResolveOverrides(sessionID string, requested *model.LLMOverrides) (*model.LLMOverrides, error) {
+	if err := ls.ValidateOverrides(requested); err != nil {
+		return nil, err
+	}
+
+	var resolved model.LLMOverrides
+	if stored, ok := ls.GetSessionOverrides(sessionID); ok {
+		resolved = stored.Overrides
+	}
+	if !requested.IsEmpty() {
+		if requested.Model != "" {
+			resolved.Model = requested.Model
+		}
+		if requested.Temperature != nil {
+			resolved.Temperature = requested.Temperature
+		}
+		if requested.MaxTokens > 0 {
+			resolved.MaxTokens = requested.MaxTokens
+		}
+	}
+
+	if resolved.IsEmpty() {
+		return nil, nil
+	}
+	return &resolved, nil
+}
+
+// SetSessionOverrides stores overrides applied to every LLM call in a session
+func (ls *LLMService) SetSessionOverrides(sessionID string, overrides model.LLMOverrides) (*model.SessionLLMOverrides, error) {
+	if err := ls.ValidateOverrides(&overrides); err != nil {
+		return nil, err
+	}
+
+	stored := &model.SessionLLMOverrides{
+		SessionID: sessionID,
+		Overrides: overrides,
+		ExpiresAt: time.Now().Add(sessionOverrideTTL),
+	}
+
+	ls.mu.Lock()
+	ls.sessionOverrides[sessionID] = stored
+	ls.mu.Unlock()
+
+	log.Info().
+		Str("session_id", sessionID).
+		Str("model", overrides.Model).
+		Msg("Session LLM overrides updated")
+
+	return stored, nil
+}
+
+// GetSessionOverrides returns the overrides stored for a session
+func (ls *LLMService) GetSessionOverrides(sessionID string) (*model.SessionLLMOverrides, bool) {
+	if sessionID == "" {
+		return nil, false
+	}
+
+	ls.mu.RLock()
+	stored, ok := ls.sessionOverrides[sessionID]
+	ls.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		ls.ClearSessionOverrides(sessionID)
+		return nil, false
+	}
+	return stored, true
+}
+
+// ClearSessionOverrides removes the overrides stored for a session
+func (ls *LLMService) ClearSessionOverrides(sessionID string) bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	_, ok := ls.sessionOverrides[sessionID]
+	delete(ls.sessionOverrides, sessionID)
+	return ok
+}
+
+// Options describes the overrides callers may use
+func (ls *LLMService) Options() model.LLMOptions {
+	models := make([]string, 0, len(ls.allowedModels))
+	for name := range ls.allowedModels {
+		models = append(models, name)
+	}
+	sort.Strings(models)
+
+	defaults := make(map[string]model.LLMSettings, len(ls.profiles))
+	for purpose, settings := range ls.profiles {
+		defaults[string(purpose)] = settings
+	}
+
+	return model.LLMOptions{
+		AllowedModels:  models,
+		MinTemperature: minLLMTemperature,
+		MaxTemperature: maxLLMTemperature,
+		MaxTokensLimit: ls.maxTokensLimit,
+		Defaults:       defaults,
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// llmStatsRetention is how far back LLM call outcomes are kept
+const llmStatsRetention = 15 * time.Minute
+
+// llmCallLog keeps the outcome of recent LLM calls so error spikes can be alerted on
+type llmCallLog struct {
+	mu    sync.Mutex
+	calls []llmCall // Oldest first
+}
+
+type llmCall struct {
+	at     time.Time
+	failed bool
+}
+
+// record adds one call. Calls cancelled by the client are not counted.
+func (cl *llmCallLog) record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	now := time.Now()
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.calls = append(cl.calls, llmCall{at: now, failed: err != nil})
+	cutoff := now.Add(-llmStatsRetention)
+	drop := 0
+	for drop < len(cl.calls) && cl.calls[drop].at.Before(cutoff) {
+		drop++
+	}
+	cl.calls = cl.calls[drop:]
+}
+
+// stats counts the calls and errors of the last window, up to llmStatsRetention
+func (cl *llmCallLog) stats(window time.Duration) *model.LLMCallStats {
+	if window <= 0 || window > llmStatsRetention {
+		window = llmStatsRetention
+	}
+	cutoff := time.Now().Add(-window)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	stats := &model.LLMCallStats{WindowSeconds: int(window.Seconds())}
+	for _, call := range cl.calls {
+		if call.at.Before(cutoff) {
+			continue
+		}
+		stats.Calls++
+		if call.failed {
+			stats.Errors++
+		}
+	}
+	if stats.Calls > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(stats.Calls)
+	}
+	return stats
+}
@@ -4,31 +4,119 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/aibanking/ai-skin-orchestrator/internal/config"
 	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/secrets"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 // MCPClient handles communication with the MCP Server (Layer 1)
 type MCPClient struct {
 	baseURL    string
-	apiKey     string
+	apiKey     *secrets.Value
 	httpClient *http.Client
+
+	pollInitial time.Duration
+	pollMax     time.Duration
+	deadlines   map[intentClass]time.Duration
+
+	reads *readCoalescer // Nil when identical reads are not coalesced
+}
+
+// intentClass groups intents that share a result deadline
+type intentClass string
+
+const (
+	intentClassRead     intentClass = "read"
+	intentClassTransfer intentClass = "transfer"
+	intentClassDefault  intentClass = "default"
+)
+
+// classifyIntent returns the deadline class of an intent
+func classifyIntent(intent string) intentClass {
+	switch model.IntentType(intent) {
+	case model.IntentCheckBalance, model.IntentGetStatement, model.IntentListBeneficiaries:
+		return intentClassRead
+	case model.IntentTransferNEFT, model.IntentTransferRTGS, model.IntentTransferIMPS, model.IntentTransferUPI:
+		return intentClassTransfer
+	}
+	return intentClassDefault
+}
+
+// MCPBusyError is returned when the MCP server refuses a task because its execution
+// pipeline is saturated
+type MCPBusyError struct {
+	RetryAfter time.Duration
+}
+
+func (e *MCPBusyError) Error() string {
+	return fmt.Sprintf("MCP server is busy, retry after %s", e.RetryAfter)
+}
+
+// UserMessage tells the customer when to try again
+func (e *MCPBusyError) UserMessage() string {
+	if e.RetryAfter > time.Minute {
+		return "We're very busy right now. Please try again in a few minutes."
+	}
+	return "We're busy right now. Please try again in a minute."
+}
+
+// IntentDisabledError is returned when the MCP server refuses a task because its
+// intent is switched off for the tenant and channel
+type IntentDisabledError struct {
+	Intent  string
+	Mode    string
+	Message string // For the user
 }
 
+func (e *IntentDisabledError) Error() string {
+	return fmt.Sprintf("intent %s is switched off (%s)", e.Intent, e.Mode)
+}
+
+// defaultBusyRetryAfter is assumed when the MCP server sends no usable Retry-After
+const defaultBusyRetryAfter = 30 * time.Second
+
 // NewMCPClient creates a new MCP client
 func NewMCPClient(cfg *config.MCPServerConfig) *MCPClient {
-	return &MCPClient{
+	mc := &MCPClient{
 		baseURL: cfg.BaseURL,
-		apiKey:  cfg.APIKey,
+		apiKey:  config.Profile().RotatingSecret("MCP_SERVER_API_KEY", cfg.APIKey),
 		httpClient: &http.Client{
 			Timeout: time.Duration(cfg.Timeout) * time.Second,
 		},
+		pollInitial: time.Duration(cfg.PollInitialDelay) * time.Millisecond,
+		pollMax:     time.Duration(cfg.PollMaxDelay) * time.Millisecond,
+		deadlines: map[intentClass]time.Duration{
+			intentClassRead:     time.Duration(cfg.ReadDeadline) * time.Second,
+			intentClassTransfer: time.Duration(cfg.TransferDeadline) * time.Second,
+			intentClassDefault:  time.Duration(cfg.DefaultDeadline) * time.Second,
+		},
+	}
+	if mc.pollInitial <= 0 {
+		mc.pollInitial = 200 * time.Millisecond
 	}
+	if mc.pollMax < mc.pollInitial {
+		mc.pollMax = mc.pollInitial
+	}
+	for class, d := range mc.deadlines {
+		if d <= 0 {
+			mc.deadlines[class] = 20 * time.Second
+		}
+	}
+	if cfg.CoalesceReads {
+		mc.reads = newReadCoalescer()
+	}
+	return mc
 }
 
 // SubmitTask submits a task to the MCP server
@@ -39,13 +127,135 @@ func (mc *MCPClient) SubmitTask(ctx context.Context, req *model.UserRequest, int
 		"channel":  req.Channel,
 		"intent":   string(intent.Type),
 		"data":     intent.Entities,
-		"context":  enrichedContext.Metadata,
+		"context":  stepUpContext(req, enrichedContext),
 	}
 
 	if req.SessionID != "" {
 		taskReq["session_id"] = req.SessionID
 	}
 
+	if req.Sandbox {
+		taskReq["sandbox"] = true
+	}
+
+	return mc.submit(ctx, req, intent, taskReq)
+}
+
+// submit posts a task and waits for its result. An identical read-only request of the
+// same user already in flight shares its task instead.
+func (mc *MCPClient) submit(ctx context.Context, req *model.UserRequest, intent model.Intent, taskReq map[string]interface{}) (*model.AgentResponse, error) {
+	// The MCP server applies the tenant's intent flags
+	if tenantID, _ := req.Context["tenant_id"].(string); tenantID != "" {
+		if taskContext, ok := taskReq["context"].(map[string]interface{}); ok {
+			taskContext["tenant_id"] = tenantID
+		}
+	}
+
+	// The agents play the demo scenario's forced outcomes
+	scenarioID, _ := req.Context[demo.ContextKey].(string)
+	if scenarioID != "" {
+		if taskContext, ok := taskReq["context"].(map[string]interface{}); ok {
+			taskContext[demo.ContextKey] = scenarioID
+		}
+	}
+
+	// Agents score with their rules when the request's budget rules ML models out
+	if !budgetFrom(ctx).useML() {
+		if taskContext, ok := taskReq["context"].(map[string]interface{}); ok {
+			taskContext["allow_ml"] = false
+		}
+	}
+
+	// A demo task must not share the task of a request playing no scenario, or the reverse
+	if mc.reads != nil && scenarioID == "" && classifyIntent(string(intent.Type)) == intentClassRead {
+		if key, ok := coalesceKey(req, intent); ok {
+			return mc.reads.do(ctx, key, func(ctx context.Context) (*model.AgentResponse, error) {
+				return mc.submitAndWait(ctx, taskReq)
+			})
+		}
+	}
+	return mc.submitAndWait(ctx, taskReq)
+}
+
+// CoalescingStats counts the read tasks submitted and the requests that shared one
+func (mc *MCPClient) CoalescingStats() model.CoalescingStats {
+	if mc.reads == nil {
+		return model.CoalescingStats{}
+	}
+	stats := mc.reads.stats()
+	stats.Enabled = true
+	return stats
+}
+
+// stepUpFields are the client context fields the MCP server's step-up policy and
+// device profiles read
+var stepUpFields = []string{"device_id", "device_trust", "location"}
+
+// stepUpContext is the task context: the enrichment metadata, the overall risk score
+// the MCP server weighs when deciding whether the user must authenticate again, and
+// the device and location the client reported
+func stepUpContext(req *model.UserRequest, enrichedContext *model.EnrichedContext) map[string]interface{} {
+	taskContext := make(map[string]interface{}, len(enrichedContext.Metadata)+len(stepUpFields)+1)
+	for k, v := range enrichedContext.Metadata {
+		taskContext[k] = v
+	}
+
+	risk := enrichedContext.RiskIndicators
+	score := risk.FraudRisk
+	for _, r := range []float64{risk.VelocityRisk, risk.AmountRisk, risk.DeviceRisk, risk.LocationRisk, risk.ScamRisk} {
+		if r > score {
+			score = r
+		}
+	}
+	taskContext["risk_score"] = score
+
+	for _, field := range stepUpFields {
+		if v, ok := req.Context[field].(string); ok && v != "" {
+			taskContext[field] = v
+		}
+	}
+	return taskContext
+}
+
+// ExecuteIntent submits a single operation on behalf of the user, e.g. a tool call made by the LLM
+func (mc *MCPClient) ExecuteIntent(ctx context.Context, req *model.UserRequest, intentType model.IntentType, data map[string]interface{}) (*model.AgentResponse, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	taskReq := map[string]interface{}{
+		"user_id": req.UserID,
+		"channel": req.Channel,
+		"intent":  string(intentType),
+		"data":    data,
+		"context": map[string]interface{}{"source": "tool_call"},
+	}
+
+	if req.SessionID != "" {
+		taskReq["session_id"] = req.SessionID
+	}
+
+	if req.Sandbox {
+		taskReq["sandbox"] = true
+	}
+
+	return mc.submit(ctx, req, model.Intent{Type: intentType, Entities: data}, taskReq)
+}
+
+// submitAndWait posts a task to the MCP server and polls for its result until the
+// deadline of the task's intent class
+func (mc *MCPClient) submitAndWait(ctx context.Context, taskReq map[string]interface{}) (*model.AgentResponse, error) {
+	intent, _ := taskReq["intent"].(string)
+	// A request with little budget left is answered PENDING sooner
+	deadline, capped := budgetFrom(ctx).capWait(mc.deadlines[classifyIntent(intent)])
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	// Each submission carries a fresh nonce and the time it was made, so the MCP server
+	// can refuse replays of money-moving tasks
+	taskReq["nonce"] = uuid.New().String()
+	taskReq["timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+
 	url := fmt.Sprintf("%s/api/v1/submit-task", mc.baseURL)
 	
 	body, err := json.Marshal(taskReq)
@@ -59,11 +269,11 @@ func (mc *MCPClient) SubmitTask(ctx context.Context, req *model.UserRequest, int
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-API-Key", mc.apiKey)
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
 
 	resp, err := mc.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to submit task: %w", err)
+		return nil, cancelledAt(ctx, model.CancelStageSubmit, fmt.Errorf("failed to submit task: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -72,27 +282,441 @@ func (mc *MCPClient) SubmitTask(ctx context.Context, req *model.UserRequest, int
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	// 503 means the agents a task needs are down and no more tasks can wait for them
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return nil, &MCPBusyError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		var refusal struct {
+			Intent  string `json:"intent"`
+			Mode    string `json:"mode"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &refusal) == nil && refusal.Mode != "" {
+			return nil, &IntentDisabledError{Intent: refusal.Intent, Mode: refusal.Mode, Message: refusal.Message}
+		}
+	}
 	if resp.StatusCode != http.StatusAccepted {
 		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
 	}
 
 	var taskResp struct {
-		TaskID    string `json:"task_id"`
-		SessionID string `json:"session_id"`
-		Status    string `json:"status"`
+		TaskID        string                 `json:"task_id"`
+		SessionID     string                 `json:"session_id"`
+		Status        string                 `json:"status"`
+		Message       string                 `json:"message"`
+		AuthChallenge map[string]interface{} `json:"auth_challenge,omitempty"`
+		Split         map[string]interface{} `json:"split,omitempty"`
+	}
+
+	if err := json.Unmarshal(respBody, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	// A task held for step-up authentication or a split goes nowhere until the user answers
+	if taskResp.Status == taskStatusAwaitingAuth || taskResp.Status == taskStatusAwaitingConfirmation {
+		held := &taskResult{TaskID: taskResp.TaskID, Status: taskResp.Status, Explanation: taskResp.Message, AuthChallenge: taskResp.AuthChallenge, Split: taskResp.Split}
+		return held.agentResponse(), nil
+	}
+
+	agentResp, err := mc.waitForResult(ctx, taskResp.TaskID, deadline)
+	if capped && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		budgetFrom(ctx).degrade(model.BudgetStageAgentWait, model.BudgetReasonLatency)
+	}
+	return agentResp, err
+}
+
+// SplitError is the MCP server refusing to confirm or decline a split: an unknown
+// split or one no longer waiting for the user
+type SplitError struct {
+	StatusCode int
+	Body       map[string]interface{}
+}
+
+func (e *SplitError) Error() string {
+	return fmt.Sprintf("split refused (%d): %v", e.StatusCode, e.Body["details"])
+}
+
+// ConfirmSplit accepts the split a transfer was offered as and waits for the transfer
+// like any other. A transfer that needs step-up authentication comes back held on
+// its challenge.
+func (mc *MCPClient) ConfirmSplit(ctx context.Context, splitID string) (*model.AgentResponse, error) {
+	deadline := mc.deadlines[intentClassTransfer]
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	respBody, err := mc.postSplit(ctx, splitID, "confirm", http.StatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskResp struct {
+		TaskID        string                 `json:"task_id"`
+		Status        string                 `json:"status"`
+		Message       string                 `json:"message"`
+		AuthChallenge map[string]interface{} `json:"auth_challenge,omitempty"`
+		Split         map[string]interface{} `json:"split,omitempty"`
+	}
 	if err := json.Unmarshal(respBody, &taskResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	if taskResp.Status == taskStatusAwaitingAuth {
+		held := &taskResult{TaskID: taskResp.TaskID, Status: taskResp.Status, Explanation: taskResp.Message, AuthChallenge: taskResp.AuthChallenge}
+		return held.agentResponse(), nil
+	}
+
+	return mc.waitForResult(ctx, taskResp.TaskID, deadline)
+}
+
+// DeclineSplit turns down the split a transfer was offered as, which rejects it
+func (mc *MCPClient) DeclineSplit(ctx context.Context, splitID string) (*model.AgentResponse, error) {
+	respBody, err := mc.postSplit(ctx, splitID, "decline", http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var split map[string]interface{}
+	if err := json.Unmarshal(respBody, &split); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	taskID, _ := split["task_id"].(string)
+	message, _ := split["message"].(string)
+	return &model.AgentResponse{
+		AgentID:     "mcp-agent",
+		AgentType:   "ORCHESTRATED",
+		TaskID:      taskID,
+		Status:      "REJECTED",
+		Result:      map[string]interface{}{"task_id": taskID, "split": split},
+		Explanation: message,
+		Confidence:  0.9,
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// postSplit answers a split on the MCP server and returns the response body
+func (mc *MCPClient) postSplit(ctx context.Context, splitID, action string, want int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/v1/splits/%s/%s", mc.baseURL, splitID, action)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, cancelledAt(ctx, model.CancelStageSubmit, fmt.Errorf("failed to %s split: %w", action, err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &MCPBusyError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode != want {
+		refusal := &SplitError{StatusCode: resp.StatusCode, Body: map[string]interface{}{}}
+		if err := json.Unmarshal(respBody, &refusal.Body); err != nil {
+			return nil, fmt.Errorf("MCP server error: %s", string(respBody))
+		}
+		return nil, refusal
+	}
+	return respBody, nil
+}
+
+// AuthVerifyError is the MCP server refusing the answer to a step-up challenge: a
+// wrong code or signature, a closed challenge or the wrong method
+type AuthVerifyError struct {
+	StatusCode int
+	Body       map[string]interface{} // The MCP server's error, with the challenge's new state
+}
+
+func (e *AuthVerifyError) Error() string {
+	return fmt.Sprintf("step-up verification refused (%d): %v", e.StatusCode, e.Body["details"])
+}
+
+// VerifyAuth answers a step-up challenge on the MCP server and, once it is verified,
+// waits for the released transfer like any other
+func (mc *MCPClient) VerifyAuth(ctx context.Context, challengeID, method string, answer map[string]interface{}) (*model.AgentResponse, error) {
+	deadline := mc.deadlines[intentClassTransfer]
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	body, err := json.Marshal(answer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/auth/challenges/%s/%s", mc.baseURL, challengeID, method)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, cancelledAt(ctx, model.CancelStageSubmit, fmt.Errorf("failed to verify challenge: %w", err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &MCPBusyError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		refusal := &AuthVerifyError{StatusCode: resp.StatusCode, Body: map[string]interface{}{}}
+		if err := json.Unmarshal(respBody, &refusal.Body); err != nil {
+			return nil, fmt.Errorf("MCP server error: %s", string(respBody))
+		}
+		return nil, refusal
+	}
+
+	var challenge struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.Unmarshal(respBody, &challenge); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return mc.waitForResult(ctx, challenge.TaskID, deadline)
+}
+
+// waitForResult polls a task with exponential backoff and jitter. A completion estimate
+// from the server replaces the backoff delay when it is later. If the deadline passes
+// first, the task is reported as PENDING; it keeps running on the MCP server.
+func (mc *MCPClient) waitForResult(ctx context.Context, taskID string, deadline time.Duration) (*model.AgentResponse, error) {
+	delay := mc.pollInitial
+	polls := 0
+	var eta *time.Time
+	var last *taskResult
+
+	for {
+		wait := jitter(delay)
+		if eta != nil {
+			if untilETA := time.Until(*eta); untilETA > wait {
+				wait = untilETA
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return mc.pendingResult(ctx, taskID, polls, deadline, last)
+		case <-time.After(wait):
+		}
+
+		result, err := mc.fetchResult(ctx, taskID)
+		polls++
+		if err != nil {
+			if ctx.Err() != nil {
+				return mc.pendingResult(ctx, taskID, polls, deadline, last)
+			}
+			return nil, err
+		}
+
+		if result.done() {
+			return result.agentResponse(), nil
+		}
+		eta = result.EstimatedCompletion
+		last = result
+
+		if delay *= 2; delay > mc.pollMax {
+			delay = mc.pollMax
+		}
+	}
+}
+
+// pendingResult reports a task that did not finish in time. When the caller gave up
+// instead, the task is cancelled on the MCP server and a CancelledError returned. A task
+// the MCP server holds until an agent is back says so.
+func (mc *MCPClient) pendingResult(ctx context.Context, taskID string, polls int, deadline time.Duration, last *taskResult) (*model.AgentResponse, error) {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, &CancelledError{
+			Stage:      model.CancelStageWait,
+			TaskID:     taskID,
+			TaskCancel: mc.cancelTask(ctx, taskID),
+			Err:        ctx.Err(),
+		}
+	}
+
+	log.Warn().
+		Str("task_id", taskID).
+		Int("polls", polls).
+		Dur("deadline", deadline).
+		Msg("Task did not complete before the deadline")
+
+	result := map[string]interface{}{"task_id": taskID}
+	explanation := "Your request is still being processed. Check back shortly for the result."
+	if last != nil && last.Status == taskStatusHeld && last.Hold != nil {
+		result["hold"] = last.Hold
+		if message, ok := last.Hold["message"].(string); ok && message != "" {
+			explanation = message
+		}
+	}
+
+	return &model.AgentResponse{
+		AgentID:     "mcp-agent",
+		AgentType:   "ORCHESTRATED",
+		TaskID:      taskID,
+		Status:      "PENDING",
+		Result:      result,
+		Explanation: explanation,
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// taskCancelTimeout bounds the request cancelling an abandoned task, which outlives
+// the caller's context
+const taskCancelTimeout = 5 * time.Second
+
+// cancelTask asks the MCP server to stop a task nobody is waiting for any more and
+// reports the outcome. The MCP server refuses with 409 once the task has finished or
+// an agent is already moving money.
+func (mc *MCPClient) cancelTask(ctx context.Context, taskID string) string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), taskCancelTimeout)
+	defer cancel()
+
+	body, _ := json.Marshal(map[string]string{"reason": "Client disconnected"})
+	url := fmt.Sprintf("%s/api/v1/task/%s/cancel", mc.baseURL, taskID)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return taskCancelFailed
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", taskID).Msg("Failed to cancel abandoned task")
+		return taskCancelFailed
+	}
+	defer resp.Body.Close()
 
-	// Wait a bit and get the result
-	time.Sleep(2 * time.Second)
-	return mc.GetTaskResult(ctx, taskResp.TaskID)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		log.Info().Str("task_id", taskID).Msg("Cancelled abandoned task")
+		return taskCancelOK
+	case http.StatusConflict:
+		log.Info().Str("task_id", taskID).Msg("MCP server kept abandoned task running")
+		return taskCancelRefused
+	}
+	log.Warn().Int("status", resp.StatusCode).Str("task_id", taskID).Msg("Failed to cancel abandoned task")
+	return taskCancelFailed
+}
+
+// jitter returns a delay between half and all of d, so clients polling in step spread out
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return defaultBusyRetryAfter
 }
 
 // GetTaskResult retrieves task result from MCP server
 func (mc *MCPClient) GetTaskResult(ctx context.Context, taskID string) (*model.AgentResponse, error) {
+	result, err := mc.fetchResult(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return result.agentResponse(), nil
+}
+
+// taskResult is the MCP server's view of a task
+type taskResult struct {
+	TaskID              string                 `json:"task_id"`
+	Status              string                 `json:"status"`
+	Result              map[string]interface{} `json:"result"`
+	RiskScore           float64                `json:"risk_score"`
+	Explanation         string                 `json:"explanation"`
+	Error               string                 `json:"error,omitempty"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"`
+	AuthChallenge       map[string]interface{} `json:"auth_challenge,omitempty"`
+	Hold                map[string]interface{} `json:"hold,omitempty"`
+	Split               map[string]interface{} `json:"split,omitempty"`
+}
+
+// taskStatusAwaitingAuth is a task the MCP server holds until the user passes a
+// step-up challenge
+const taskStatusAwaitingAuth = "AWAITING_AUTH"
+
+// taskStatusHeld is a task the MCP server holds until an agent of the type it needs
+// registers or recovers; it runs by itself once one does
+const taskStatusHeld = "HELD"
+
+// taskStatusAwaitingConfirmation is a transfer too large for its rail that the MCP
+// server holds until the user accepts or declines sending it as a split
+const taskStatusAwaitingConfirmation = "AWAITING_CONFIRMATION"
+
+// taskStatusScheduled is a split transfer with parts sent and the rest waiting for a
+// later day; it finishes by itself
+const taskStatusScheduled = "SCHEDULED"
+
+// done reports whether the task has finished, successfully or not, is held for the
+// user to authenticate or accept a split, or has sent what it can today. Running tasks
+// pass through PENDING, PROCESSING, HELD, WAITING and EXECUTING.
+func (tr *taskResult) done() bool {
+	switch tr.Status {
+	case "COMPLETED", "FAILED", "REJECTED", taskStatusAwaitingAuth, taskStatusAwaitingConfirmation, taskStatusScheduled:
+		return true
+	}
+	return false
+}
+
+// agentResponse converts the task result for the response merger. A held task carries
+// its challenge or split proposal for the client to answer, and a scheduled split what
+// has been sent and what is still to go. A failed task carries its raw error for the
+// orchestrator to explain.
+func (tr *taskResult) agentResponse() *model.AgentResponse {
+	result := tr.Result
+	switch tr.Status {
+	case taskStatusAwaitingAuth:
+		result = map[string]interface{}{"task_id": tr.TaskID, "auth_challenge": tr.AuthChallenge}
+	case taskStatusAwaitingConfirmation, taskStatusScheduled:
+		result = map[string]interface{}{"task_id": tr.TaskID, "split": tr.Split}
+		if message, _ := tr.Split["message"].(string); message != "" && tr.Explanation == "" {
+			tr.Explanation = message
+		}
+	case "FAILED":
+		if result == nil {
+			result = map[string]interface{}{"task_id": tr.TaskID}
+		}
+	}
+	return &model.AgentResponse{
+		AgentID:     "mcp-agent",
+		AgentType:   "ORCHESTRATED",
+		TaskID:      tr.TaskID,
+		Status:      tr.Status,
+		Result:      result,
+		RiskScore:   tr.RiskScore,
+		Explanation: tr.Explanation,
+		Confidence:  0.9,
+		Timestamp:   time.Now(),
+		Error:       tr.Error,
+	}
+}
+
+// fetchResult retrieves a task from the MCP server
+func (mc *MCPClient) fetchResult(ctx context.Context, taskID string) (*taskResult, error) {
 	url := fmt.Sprintf("%s/api/v1/get-result/%s", mc.baseURL, taskID)
 
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -100,7 +724,7 @@ func (mc *MCPClient) GetTaskResult(ctx context.Context, taskID string) (*model.A
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("X-API-Key", mc.apiKey)
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
 
 	resp, err := mc.httpClient.Do(httpReq)
 	if err != nil {
@@ -117,28 +741,186 @@ func (mc *MCPClient) GetTaskResult(ctx context.Context, taskID string) (*model.A
 		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
 	}
 
+	var result taskResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ListAgents returns the agents registered with the MCP server
+func (mc *MCPClient) ListAgents(ctx context.Context) ([]model.CapabilityAgent, error) {
+	url := fmt.Sprintf("%s/api/v1/agents", mc.baseURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
+	}
+
+	var result struct {
+		Agents []model.CapabilityAgent `json:"agents"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return result.Agents, nil
+}
+
+// ListCustomIntents returns the custom intents registered with the MCP server
+func (mc *MCPClient) ListCustomIntents(ctx context.Context) ([]model.CustomIntent, error) {
+	url := fmt.Sprintf("%s/api/v1/intents", mc.baseURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom intents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
+	}
+
 	var result struct {
-		TaskID      string                 `json:"task_id"`
-		Status      string                 `json:"status"`
-		Result      map[string]interface{} `json:"result"`
-		RiskScore   float64                `json:"risk_score"`
-		Explanation string                 `json:"explanation"`
-		Error       string                 `json:"error,omitempty"`
+		Intents []model.CustomIntent `json:"intents"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return result.Intents, nil
+}
+
+// ListIntentFlags returns the intent flags set on the MCP server
+func (mc *MCPClient) ListIntentFlags(ctx context.Context) ([]model.IntentFlag, error) {
+	url := fmt.Sprintf("%s/api/v1/intents/flags", mc.baseURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intent flags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
 	}
 
+	var result struct {
+		Flags []model.IntentFlag `json:"flags"`
+	}
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return &model.AgentResponse{
-		AgentID:     "mcp-agent",
-		AgentType:   "ORCHESTRATED",
-		Status:      result.Status,
-		Result:      result.Result,
-		RiskScore:   result.RiskScore,
-		Explanation: result.Explanation,
-		Confidence:  0.9,
-		Timestamp:   time.Now(),
-	}, nil
+	return result.Flags, nil
+}
+
+// BindSession upserts the session on the MCP server under the skin's session ID,
+// merging context into it, and returns the session as the MCP server now has it
+func (mc *MCPClient) BindSession(ctx context.Context, sessionID, userID, channel string, sessionContext map[string]interface{}) (*model.SessionBinding, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"user_id": userID,
+		"channel": channel,
+		"context": sessionContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/session/%s", mc.baseURL, sessionID)
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	return mc.doSession(httpReq)
+}
+
+// GetSession reads a session from the MCP server
+func (mc *MCPClient) GetSession(ctx context.Context, sessionID string) (*model.SessionBinding, error) {
+	url := fmt.Sprintf("%s/api/v1/get-session/%s", mc.baseURL, sessionID)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-API-Key", mc.apiKey.Get())
+
+	return mc.doSession(httpReq)
 }
 
+// doSession sends a session request and decodes the session it answers with
+func (mc *MCPClient) doSession(httpReq *http.Request) (*model.SessionBinding, error) {
+	resp, err := mc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach MCP server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusBadRequest:
+		return nil, ErrInvalidSessionID
+	case http.StatusConflict:
+		return nil, ErrSessionConflict
+	case http.StatusNotFound:
+		return nil, ErrSessionNotFound
+	default:
+		return nil, fmt.Errorf("MCP server error: %s", string(respBody))
+	}
+
+	var binding model.SessionBinding
+	if err := json.Unmarshal(respBody, &binding); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	binding.SyncedAt = time.Now()
+	return &binding, nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// PromptMemoryExtraction distills durable facts from a conversation
+const PromptMemoryExtraction = "memory_extraction"
+
+// maxMemoryFactLength caps a single stored fact
+const maxMemoryFactLength = 200
+
+// MemoryService keeps curated long-term facts per user for users who opted in.
+// Facts are extracted by the LLM after each chat exchange, can be listed and deleted
+// by the user, and are injected into later chat prompts as untrusted documents.
+type MemoryService struct {
+	llmService     *LLMService
+	prompts        *PromptService
+	guard          *PromptGuard
+	facts          map[string][]*model.MemoryFact // Keyed by user ID, oldest first
+	settings       map[string]*model.MemorySettings
+	mu             sync.RWMutex
+	maxFacts       int
+	extractTimeout time.Duration
+	categories     map[string]bool
+}
+
+// NewMemoryService creates a new memory service
+func NewMemoryService(cfg *config.MemoryConfig, llmService *LLMService, prompts *PromptService, guard *PromptGuard) *MemoryService {
+	maxFacts := cfg.MaxFacts
+	if maxFacts <= 0 {
+		maxFacts = 50
+	}
+	return &MemoryService{
+		llmService:     llmService,
+		prompts:        prompts,
+		guard:          guard,
+		facts:          make(map[string][]*model.MemoryFact),
+		settings:       make(map[string]*model.MemorySettings),
+		maxFacts:       maxFacts,
+		extractTimeout: time.Duration(cfg.ExtractTimeout) * time.Second,
+		categories: map[string]bool{
+			model.MemoryPayee:      true,
+			model.MemorySchedule:   true,
+			model.MemoryAccount:    true,
+			model.MemoryStyle:      true,
+			model.MemoryPreference: true,
+		},
+	}
+}
+
+// Settings returns a user's memory settings; users are opted out until they opt in
+func (ms *MemoryService) Settings(userID string) model.MemorySettings {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if s, ok := ms.settings[userID]; ok {
+		return *s
+	}
+	return model.MemorySettings{UserID: userID}
+}
+
+// SetEnabled opts a user in or out. Opting out deletes everything remembered.
+func (ms *MemoryService) SetEnabled(userID string, enabled bool) model.MemorySettings {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	s := &model.MemorySettings{UserID: userID, Enabled: enabled, UpdatedAt: time.Now()}
+	ms.settings[userID] = s
+	if !enabled {
+		delete(ms.facts, userID)
+	}
+
+	log.Info().Str("user_id", userID).Bool("enabled", enabled).Msg("Long-term memory setting updated")
+	return *s
+}
+
+// List returns a copy of the facts remembered for a user
+func (ms *MemoryService) List(userID string) []model.MemoryFact {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	facts := make([]model.MemoryFact, 0, len(ms.facts[userID]))
+	for _, f := range ms.facts[userID] {
+		facts = append(facts, *f)
+	}
+	return facts
+}
+
+// Delete removes one fact and reports whether it existed
+func (ms *MemoryService) Delete(userID, id string) bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	facts := ms.facts[userID]
+	for i, f := range facts {
+		if f.ID == id {
+			ms.facts[userID] = append(facts[:i], facts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes every fact for a user and returns how many were removed
+func (ms *MemoryService) Clear(userID string) int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	n := len(ms.facts[userID])
+	delete(ms.facts, userID)
+	return n
+}
+
+// Purge deletes facts last confirmed before cutoff and returns their IDs, with the
+// number kept because their user is on legal hold. With dryRun nothing is deleted.
+func (ms *MemoryService) Purge(cutoff time.Time, held func(userID string) bool, dryRun bool) ([]string, int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var purged []string
+	kept := 0
+	for userID, facts := range ms.facts {
+		remaining := facts[:0:0]
+		for _, f := range facts {
+			if !f.UpdatedAt.Before(cutoff) {
+				remaining = append(remaining, f)
+				continue
+			}
+			if held(userID) {
+				kept++
+				remaining = append(remaining, f)
+				continue
+			}
+			purged = append(purged, f.ID)
+		}
+		if dryRun {
+			continue
+		}
+		if len(remaining) == 0 {
+			delete(ms.facts, userID)
+		} else {
+			ms.facts[userID] = remaining
+		}
+	}
+	return purged, kept
+}
+
+// Forget deletes a user's facts and memory settings, as if they had never opted in,
+// and returns how many facts were deleted
+func (ms *MemoryService) Forget(userID string) int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	n := len(ms.facts[userID])
+	delete(ms.facts, userID)
+	delete(ms.settings, userID)
+	return n
+}
+
+// PromptContext returns the user's facts as a sanitized document for a prompt, or ""
+// when there is nothing to inject
+func (ms *MemoryService) PromptContext(userID string) string {
+	if !ms.Settings(userID).Enabled {
+		return ""
+	}
+
+	facts := ms.List(userID)
+	if len(facts) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(facts))
+	for _, f := range facts {
+		lines = append(lines, fmt.Sprintf("- (%s) %s", f.Category, f.Content))
+	}
+	return ms.guard.SanitizeRetrieved("long_term_memory", strings.Join(lines, "\n")).Text
+}
+
+// Remember extracts durable facts from one exchange in the background. It does nothing
+// for users who have not opted in or when the LLM is disabled.
+func (ms *MemoryService) Remember(userID, sessionID, userMessage, answer string) {
+	if !ms.llmService.Enabled() || !ms.Settings(userID).Enabled {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(WithQuotaUser(context.Background(), userID), ms.extractTimeout)
+		defer cancel()
+
+		added, err := ms.extract(ctx, userID, sessionID, userMessage, answer)
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Memory extraction failed")
+			return
+		}
+		if added > 0 {
+			log.Info().Str("user_id", userID).Int("facts_added", added).Msg("Long-term memory updated")
+		}
+	}()
+}
+
+// extract asks the LLM for new facts and stores the ones that pass validation
+func (ms *MemoryService) extract(ctx context.Context, userID, sessionID, userMessage, answer string) (int, error) {
+	known := make([]string, 0)
+	for _, f := range ms.List(userID) {
+		known = append(known, f.Content)
+	}
+
+	conversation := ms.guard.SanitizeUserInput(fmt.Sprintf("Customer: %s\nReply: %s", userMessage, answer))
+	prompt, err := ms.prompts.Render(PromptMemoryExtraction, model.PromptVars{
+		UserInput: conversation.Text,
+		Extra:     map[string]interface{}{"known": known},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Structured extraction shares the deterministic intent profile
+	response, err := ms.llmService.CallLLM(ctx, ms.llmService.Settings(LLMPurposeIntent, nil), prompt.Text)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Facts []struct {
+			Category string `json:"category"`
+			Content  string `json:"content"`
+		} `json:"facts"`
+	}
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		return 0, fmt.Errorf("failed to parse memory extraction response: %w", err)
+	}
+
+	added := 0
+	for _, f := range result.Facts {
+		content := strings.TrimSpace(f.Content)
+		if !ms.categories[f.Category] || content == "" || len(content) > maxMemoryFactLength {
+			continue
+		}
+		// Never keep account or card numbers in long-term memory
+		if hasDigitRun(content, 9) {
+			log.Warn().Str("user_id", userID).Msg("Dropping memory fact containing an account number")
+			continue
+		}
+		if ms.add(userID, sessionID, f.Category, content) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// add stores a fact unless it is already known, evicting the oldest beyond the cap
+func (ms *MemoryService) add(userID, sessionID, category, content string) bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// The user may have opted out while extraction was running
+	if s, ok := ms.settings[userID]; !ok || !s.Enabled {
+		return false
+	}
+
+	now := time.Now()
+	for _, f := range ms.facts[userID] {
+		if strings.EqualFold(f.Content, content) {
+			f.UpdatedAt = now
+			return false
+		}
+	}
+
+	facts := append(ms.facts[userID], &model.MemoryFact{
+		ID:        ids.New(ids.Memory),
+		UserID:    userID,
+		Category:  category,
+		Content:   content,
+		SessionID: sessionID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if len(facts) > ms.maxFacts {
+		facts = facts[len(facts)-ms.maxFacts:]
+	}
+	ms.facts[userID] = facts
+	return true
+}
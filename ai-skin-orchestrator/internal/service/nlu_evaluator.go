@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// NLU engines that can be evaluated
+const (
+	NLUEngineRules = "rules" // Rule-based parser
+	NLUEngineLLM   = "llm"   // LLM parser, without the fallback to rules
+)
+
+//go:embed evaldata/nlu_dataset.json
+var embeddedNLUDataset []byte
+
+// LoadNLUDataset reads a labeled dataset from path, or the embedded default dataset
+// when path is empty
+func LoadNLUDataset(path string) (*model.NLUDataset, error) {
+	data := embeddedNLUDataset
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read NLU dataset: %w", err)
+		}
+	}
+
+	var dataset model.NLUDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("invalid NLU dataset: %w", err)
+	}
+	if len(dataset.Cases) == 0 {
+		return nil, fmt.Errorf("NLU dataset %q has no cases", dataset.Name)
+	}
+	for i, c := range dataset.Cases {
+		if c.Text == "" || c.Intent == "" {
+			return nil, fmt.Errorf("NLU dataset case %d (%s) needs text and intent", i, c.ID)
+		}
+	}
+	return &dataset, nil
+}
+
+// NLUEvaluator scores the intent parser against a labeled dataset
+type NLUEvaluator struct {
+	intentParser *IntentParser
+	llmService   *LLMService
+	dataset      *model.NLUDataset
+}
+
+// NewNLUEvaluator creates a new NLU evaluator
+func NewNLUEvaluator(intentParser *IntentParser, llmService *LLMService, dataset *model.NLUDataset) *NLUEvaluator {
+	return &NLUEvaluator{
+		intentParser: intentParser,
+		llmService:   llmService,
+		dataset:      dataset,
+	}
+}
+
+// Run scores one engine on every case and checks the dataset's thresholds for it
+func (ne *NLUEvaluator) Run(ctx context.Context, engine string) (*model.NLUReport, error) {
+	var parse func(text string) (*model.Intent, error)
+	switch engine {
+	case NLUEngineRules:
+		parse = ne.intentParser.parseWithRules
+	case NLUEngineLLM:
+		if ne.llmService == nil || !ne.llmService.Enabled() {
+			return nil, ErrLLMDisabled
+		}
+		parse = func(text string) (*model.Intent, error) { return ne.parseWithLLM(ctx, text) }
+	default:
+		return nil, fmt.Errorf("unknown NLU engine %q", engine)
+	}
+
+	start := time.Now()
+	report := &model.NLUReport{
+		Dataset:        ne.dataset.Name,
+		DatasetVersion: ne.dataset.Version,
+		Engine:         engine,
+		Cases:          len(ne.dataset.Cases),
+		PerIntent:      make(map[model.IntentType]*model.NLUIntentScore),
+	}
+
+	correct, truePos, falsePos, falseNeg := 0, 0, 0, 0
+	for _, c := range ne.dataset.Cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		score, ok := report.PerIntent[c.Intent]
+		if !ok {
+			score = &model.NLUIntentScore{}
+			report.PerIntent[c.Intent] = score
+		}
+		score.Cases++
+
+		failure := model.NLUFailure{ID: c.ID, Text: c.Text, ExpectedIntent: c.Intent}
+		intent, err := parse(c.Text)
+		if err != nil {
+			failure.Error = err.Error()
+			failure.Missing = sortedKeys(c.Entities)
+			falseNeg += len(c.Entities)
+			report.Failures = append(report.Failures, failure)
+			continue
+		}
+
+		failure.ActualIntent = intent.Type
+		if intent.Type == c.Intent {
+			correct++
+			score.Correct++
+		}
+
+		matched, missing, unexpected := compareEntities(c.Entities, intent.Entities)
+		truePos += matched
+		falseNeg += len(missing)
+		falsePos += len(unexpected)
+
+		if intent.Type != c.Intent || len(missing) > 0 || len(unexpected) > 0 {
+			failure.Missing = missing
+			failure.Unexpected = unexpected
+			report.Failures = append(report.Failures, failure)
+		}
+	}
+
+	for _, score := range report.PerIntent {
+		score.Accuracy = ratio(score.Correct, score.Cases)
+	}
+	report.IntentAccuracy = ratio(correct, report.Cases)
+	report.EntityPrecision = ratio(truePos, truePos+falsePos)
+	report.EntityRecall = ratio(truePos, truePos+falseNeg)
+	if report.EntityPrecision+report.EntityRecall > 0 {
+		report.EntityF1 = 2 * report.EntityPrecision * report.EntityRecall / (report.EntityPrecision + report.EntityRecall)
+	}
+
+	report.Passed = true
+	if threshold, ok := ne.dataset.Thresholds[engine]; ok {
+		report.Threshold = &threshold
+		if threshold.IntentAccuracy > 0 && report.IntentAccuracy < threshold.IntentAccuracy {
+			report.Violations = append(report.Violations, fmt.Sprintf("intent accuracy %.3f is below %.3f", report.IntentAccuracy, threshold.IntentAccuracy))
+		}
+		if threshold.EntityF1 > 0 && report.EntityF1 < threshold.EntityF1 {
+			report.Violations = append(report.Violations, fmt.Sprintf("entity F1 %.3f is below %.3f", report.EntityF1, threshold.EntityF1))
+		}
+		report.Passed = len(report.Violations) == 0
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// parseWithLLM parses with the LLM alone, so LLM failures count against it instead of
+// being hidden by the fallback to rules
+func (ne *NLUEvaluator) parseWithLLM(ctx context.Context, text string) (*model.Intent, error) {
+	result, err := ne.llmService.ParseIntentWithLLM(ctx, text, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	intentType, _ := result["intent"].(string)
+	entities, _ := result["entities"].(map[string]interface{})
+	return &model.Intent{
+		Type:         model.IntentType(intentType),
+		Entities:     entities,
+		OriginalText: text,
+	}, nil
+}
+
+// compareEntities counts expected entities the parser returned with the same value, and
+// lists the expected ones it missed or got wrong and the ones it returned unasked or wrong
+func compareEntities(expected, actual map[string]interface{}) (matched int, missing, unexpected []string) {
+	for key, want := range expected {
+		got, ok := actual[key]
+		switch {
+		case !ok:
+			missing = append(missing, key)
+		case normalizeEntity(got) != normalizeEntity(want):
+			missing = append(missing, key)
+			unexpected = append(unexpected, key)
+		default:
+			matched++
+		}
+	}
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			unexpected = append(unexpected, key)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return matched, missing, unexpected
+}
+
+// normalizeEntity puts an entity value in a comparable form: numbers, including
+// numeric strings such as "50,000", as plain decimals and text lowercased
+func normalizeEntity(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case string:
+		s := strings.TrimSpace(n)
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(fmt.Sprint(v))
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ratio is n/d, or 0 when d is 0
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
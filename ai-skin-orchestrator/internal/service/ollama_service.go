@@ -0,0 +1,486 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+// OllamaService talks to a local Ollama host for chat and model management
+type OllamaService struct {
+	baseURL       string
+	keepAlive     string
+	streamRetries int
+	httpClient    *http.Client
+	pullClient    *http.Client // Pulls can take minutes
+}
+
+// NewOllamaService creates a new Ollama service
+func NewOllamaService(cfg *config.OllamaConfig) *OllamaService {
+	return &OllamaService{
+		baseURL:       strings.TrimSuffix(cfg.BaseURL, "/"),
+		keepAlive:     cfg.KeepAlive,
+		streamRetries: cfg.StreamRetries,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+		pullClient: &http.Client{
+			Timeout: time.Duration(cfg.PullTimeout) * time.Second,
+		},
+	}
+}
+
+// Chat sends a role-based conversation to /api/chat
+func (ol *OllamaService) Chat(ctx context.Context, settings model.LLMSettings, messages []model.OllamaMessage, tools []openai.Tool) (*model.OllamaChatResponse, error) {
+	var resp model.OllamaChatResponse
+	if err := ol.do(ctx, ol.httpClient, "POST", "/api/chat", ol.chatBody(settings, messages, tools, false), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ChatStream streams a conversation from /api/chat, passing content to onDelta as it
+// arrives. If the stream drops before the final chunk, the request is retried with the
+// text received so far as a trailing assistant message so the model continues it, and
+// any text the model repeats is skipped. When retries run out the text received so far
+// is returned with Partial set.
+func (ol *OllamaService) ChatStream(ctx context.Context, settings model.LLMSettings, messages []model.OllamaMessage, tools []openai.Tool, onDelta func(string)) (*model.OllamaChatResponse, error) {
+	var content strings.Builder
+	var toolCalls []model.OllamaToolCall
+	var lastErr error
+
+	for attempt := 0; attempt <= ol.streamRetries; attempt++ {
+		request := messages
+		var dedupe *resumeDeduper
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ol.partialResponse(settings, content.String(), toolCalls, attempt-1, ctx.Err())
+			case <-time.After(time.Duration(attempt) * streamRetryBackoff):
+			}
+
+			if content.Len() > 0 {
+				request = append(append([]model.OllamaMessage{}, messages...), model.OllamaMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: content.String(),
+				})
+			}
+			dedupe = &resumeDeduper{prior: content.String()}
+
+			log.Warn().
+				Err(lastErr).
+				Int("attempt", attempt).
+				Int("received_chars", content.Len()).
+				Msg("Ollama stream dropped, resuming")
+		}
+
+		emit := func(delta string) {
+			if dedupe != nil {
+				delta = dedupe.next(delta)
+			}
+			if delta == "" {
+				return
+			}
+			content.WriteString(delta)
+			if onDelta != nil {
+				onDelta(delta)
+			}
+		}
+
+		final, calls, retryable, err := ol.streamOnce(ctx, settings, request, tools, emit)
+		toolCalls = append(toolCalls, calls...)
+		if err == nil {
+			final.Message.Role = openai.ChatMessageRoleAssistant
+			final.Message.Content = content.String()
+			final.Message.ToolCalls = toolCalls
+			final.Resumes = attempt
+			return final, nil
+		}
+
+		lastErr = err
+		// Tool calls arrive whole in a single chunk, so a stream that delivered them is usable
+		if !retryable || len(toolCalls) > 0 {
+			break
+		}
+	}
+
+	return ol.partialResponse(settings, content.String(), toolCalls, ol.streamRetries, lastErr)
+}
+
+// streamRetryBackoff is the wait before the first resume; later attempts wait longer
+const streamRetryBackoff = 500 * time.Millisecond
+
+// minResumeOverlap is the shortest repeated text treated as overlap when resuming
+const minResumeOverlap = 4
+
+// streamOnce reads one streaming response. It returns the final chunk, or an error
+// that says whether the stream is worth retrying.
+func (ol *OllamaService) streamOnce(ctx context.Context, settings model.LLMSettings, messages []model.OllamaMessage, tools []openai.Tool, emit func(string)) (*model.OllamaChatResponse, []model.OllamaToolCall, bool, error) {
+	data, err := json.Marshal(ol.chatBody(settings, messages, tools, true))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ol.baseURL+"/api/chat", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := ol.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, ctx.Err() == nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		// Client errors (unknown model, bad request) will not succeed on retry
+		return nil, nil, resp.StatusCode >= 500, fmt.Errorf("ollama error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var toolCalls []model.OllamaToolCall
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk model.OllamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, toolCalls, true, fmt.Errorf("malformed stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, toolCalls, true, fmt.Errorf("ollama stream error: %s", chunk.Error)
+		}
+
+		emit(chunk.Message.Content)
+		toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+
+		if chunk.Done {
+			return &chunk, toolCalls, false, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, toolCalls, ctx.Err() == nil, fmt.Errorf("stream interrupted: %w", err)
+	}
+	return nil, toolCalls, ctx.Err() == nil, fmt.Errorf("stream ended before completion")
+}
+
+// partialResponse returns what was received before recovery failed
+func (ol *OllamaService) partialResponse(settings model.LLMSettings, content string, toolCalls []model.OllamaToolCall, resumes int, cause error) (*model.OllamaChatResponse, error) {
+	if content == "" && len(toolCalls) == 0 {
+		return nil, fmt.Errorf("ollama stream failed: %w", cause)
+	}
+
+	log.Warn().
+		Err(cause).
+		Int("resumes", resumes).
+		Int("received_chars", len(content)).
+		Msg("Ollama stream could not be recovered, returning partial response")
+
+	return &model.OllamaChatResponse{
+		Model: settings.Model,
+		Message: model.OllamaMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   content,
+			ToolCalls: toolCalls,
+		},
+		Resumes: resumes,
+		Partial: true,
+	}, nil
+}
+
+// chatBody builds an /api/chat request
+func (ol *OllamaService) chatBody(settings model.LLMSettings, messages []model.OllamaMessage, tools []openai.Tool, stream bool) map[string]interface{} {
+	options := map[string]interface{}{
+		"temperature": settings.Temperature,
+	}
+	if settings.MaxTokens > 0 {
+		options["num_predict"] = settings.MaxTokens
+	}
+
+	body := map[string]interface{}{
+		"model":    settings.Model,
+		"messages": messages,
+		"stream":   stream,
+		"options":  options,
+	}
+	if len(tools) > 0 {
+		body["tools"] = tools
+	}
+	if ol.keepAlive != "" {
+		body["keep_alive"] = ol.keepAlive
+	}
+	return body
+}
+
+// resumeDeduper drops text a resumed stream repeats. A model asked to continue may
+// pick up where it stopped, restart from the beginning, or repeat the last few words.
+type resumeDeduper struct {
+	prior    string
+	pending  string
+	resolved bool
+}
+
+// next returns the part of delta that is new
+func (d *resumeDeduper) next(delta string) string {
+	if d.resolved {
+		return delta
+	}
+
+	d.pending += delta
+	// Still consistent with replaying text already sent, from the start or mid-way
+	if strings.Contains(d.prior, d.pending) {
+		return ""
+	}
+
+	d.resolved = true
+	if strings.HasPrefix(d.pending, d.prior) {
+		return d.pending[len(d.prior):]
+	}
+	return d.pending[suffixPrefixOverlap(d.prior, d.pending):]
+}
+
+// suffixPrefixOverlap returns the length of the longest suffix of a that is a prefix
+// of b, ignoring overlaps shorter than minResumeOverlap
+func suffixPrefixOverlap(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	for n := max; n >= minResumeOverlap; n-- {
+		if strings.HasSuffix(a, b[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// ChatCompletion runs one chat round using OpenAI message types, so the LLM
+// service can switch providers without changing its conversation handling. With
+// onDelta set the round is streamed; the bool reports an unrecovered partial answer
+// and the int the tokens the round used.
+func (ol *OllamaService) ChatCompletion(ctx context.Context, settings model.LLMSettings, messages []openai.ChatCompletionMessage, tools []openai.Tool, onDelta func(string)) (openai.ChatCompletionMessage, bool, int, error) {
+	converted := make([]model.OllamaMessage, 0, len(messages))
+	for _, msg := range messages {
+		om := model.OllamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, call := range msg.ToolCalls {
+			args := map[string]interface{}{}
+			if call.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+					return openai.ChatCompletionMessage{}, false, 0, fmt.Errorf("invalid tool arguments for %s: %w", call.Function.Name, err)
+				}
+			}
+			om.ToolCalls = append(om.ToolCalls, model.OllamaToolCall{
+				Function: model.OllamaFunctionCall{Name: call.Function.Name, Arguments: args},
+			})
+		}
+		converted = append(converted, om)
+	}
+
+	var resp *model.OllamaChatResponse
+	var err error
+	if onDelta != nil {
+		resp, err = ol.ChatStream(ctx, settings, converted, tools, onDelta)
+	} else {
+		resp, err = ol.Chat(ctx, settings, converted, tools)
+	}
+	if err != nil {
+		return openai.ChatCompletionMessage{}, false, 0, err
+	}
+
+	result := openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleAssistant,
+		Content: resp.Message.Content,
+	}
+	// Ollama does not issue call IDs, so number them for the tool replies
+	for i, call := range resp.Message.ToolCalls {
+		args, err := json.Marshal(call.Function.Arguments)
+		if err != nil {
+			return openai.ChatCompletionMessage{}, false, 0, fmt.Errorf("failed to encode tool arguments: %w", err)
+		}
+		result.ToolCalls = append(result.ToolCalls, openai.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i+1),
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      call.Function.Name,
+				Arguments: string(args),
+			},
+		})
+	}
+
+	return result, resp.Partial, resp.PromptEvalCount + resp.EvalCount, nil
+}
+
+// Embed returns one embedding per input from /api/embed
+func (ol *OllamaService) Embed(ctx context.Context, embeddingModel string, inputs []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	body := map[string]interface{}{
+		"model": embeddingModel,
+		"input": inputs,
+	}
+	if err := ol.do(ctx, ol.httpClient, "POST", "/api/embed", body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// ListModels returns the models pulled to the Ollama host
+func (ol *OllamaService) ListModels(ctx context.Context) ([]model.OllamaModel, error) {
+	var resp struct {
+		Models []model.OllamaModel `json:"models"`
+	}
+	if err := ol.do(ctx, ol.httpClient, "GET", "/api/tags", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Models, nil
+}
+
+// RunningModels returns the models currently loaded into memory
+func (ol *OllamaService) RunningModels(ctx context.Context) ([]model.OllamaRunningModel, error) {
+	var resp struct {
+		Models []model.OllamaRunningModel `json:"models"`
+	}
+	if err := ol.do(ctx, ol.httpClient, "GET", "/api/ps", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Models, nil
+}
+
+// PullModel downloads a model and blocks until the pull completes
+func (ol *OllamaService) PullModel(ctx context.Context, name string) error {
+	log.Info().Str("model", name).Msg("Pulling Ollama model")
+
+	var resp struct {
+		Status string `json:"status"`
+	}
+	if err := ol.do(ctx, ol.pullClient, "POST", "/api/pull", map[string]interface{}{"model": name, "stream": false}, &resp); err != nil {
+		return err
+	}
+	if resp.Status != "success" {
+		return fmt.Errorf("pull of %s did not complete: %s", name, resp.Status)
+	}
+
+	log.Info().Str("model", name).Msg("Ollama model pulled")
+	return nil
+}
+
+// ModelStatus reports whether a model is available on the host and loaded into memory
+func (ol *OllamaService) ModelStatus(ctx context.Context, name string) (*model.OllamaModelStatus, error) {
+	status := &model.OllamaModelStatus{Name: name}
+
+	models, err := ol.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range models {
+		if sameModel(m.Name, name) {
+			status.Available = true
+			break
+		}
+	}
+
+	running, err := ol.RunningModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range running {
+		if sameModel(m.Name, name) {
+			expiresAt := m.ExpiresAt
+			status.Loaded = true
+			status.SizeVRAM = m.SizeVRAM
+			status.ExpiresAt = &expiresAt
+			break
+		}
+	}
+
+	return status, nil
+}
+
+// EnsureModels checks that each model is available, pulling missing ones when pull is set
+func (ol *OllamaService) EnsureModels(ctx context.Context, names []string, pull bool) error {
+	for _, name := range names {
+		status, err := ol.ModelStatus(ctx, name)
+		if err != nil {
+			return fmt.Errorf("ollama unreachable at %s: %w", ol.baseURL, err)
+		}
+		if status.Available {
+			continue
+		}
+		if !pull {
+			return fmt.Errorf("model %s is not available on the Ollama host", name)
+		}
+		if err := ol.PullModel(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// do sends a JSON request to the Ollama API and decodes the response into out
+func (ol *OllamaService) do(ctx context.Context, client *http.Client, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, ol.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// sameModel compares model names, treating a missing tag as ":latest"
+func sameModel(a, b string) bool {
+	if !strings.Contains(a, ":") {
+		a += ":latest"
+	}
+	if !strings.Contains(b, ":") {
+		b += ":latest"
+	}
+	return a == b
+}
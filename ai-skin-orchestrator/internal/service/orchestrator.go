@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/demo"
 	"github.com/rs/zerolog/log"
 )
 
@@ -15,6 +20,23 @@ type Orchestrator struct {
 	contextEnricher  *ContextEnricher
 	mcpClient        *MCPClient
 	responseMerger   *ResponseMerger
+	responseGuard    *ResponseGuard
+	ragService       *RAGService
+	preferences      *PreferenceClient
+	decisions        *DecisionStore
+	contextResolver  *ContextResolver
+	calendar         *CalendarClient
+	payees           *PayeeClient
+	capabilities     *CapabilityService
+	pipeline         *ResponsePipeline
+	analytics        *AnalyticsService
+	handoffs         *HandoffRouter
+	scam             *ScamDetector
+	suggestions      *SuggestionGenerator
+	humanizer        *ErrorHumanizer
+	flags            *IntentFlags
+	summarizer       *StatementSummarizer
+	demo             *demo.Library // Nil unless DEMO_MODE is on
 }
 
 // NewOrchestrator creates a new orchestrator instance
@@ -23,12 +45,46 @@ func NewOrchestrator(
 	contextEnricher *ContextEnricher,
 	mcpClient *MCPClient,
 	responseMerger *ResponseMerger,
+	responseGuard *ResponseGuard,
+	ragService *RAGService,
+	preferences *PreferenceClient,
+	decisions *DecisionStore,
+	contextResolver *ContextResolver,
+	calendar *CalendarClient,
+	payees *PayeeClient,
+	capabilities *CapabilityService,
+	pipeline *ResponsePipeline,
+	analytics *AnalyticsService,
+	handoffs *HandoffRouter,
+	scam *ScamDetector,
+	suggestions *SuggestionGenerator,
+	humanizer *ErrorHumanizer,
+	flags *IntentFlags,
+	summarizer *StatementSummarizer,
+	scenarios *demo.Library,
 ) *Orchestrator {
 	return &Orchestrator{
 		intentParser:    intentParser,
 		contextEnricher: contextEnricher,
 		mcpClient:       mcpClient,
 		responseMerger:  responseMerger,
+		responseGuard:   responseGuard,
+		ragService:      ragService,
+		preferences:     preferences,
+		decisions:       decisions,
+		contextResolver: contextResolver,
+		calendar:        calendar,
+		payees:          payees,
+		capabilities:    capabilities,
+		pipeline:        pipeline,
+		analytics:       analytics,
+		handoffs:        handoffs,
+		scam:            scam,
+		suggestions:     suggestions,
+		humanizer:       humanizer,
+		flags:           flags,
+		summarizer:      summarizer,
+		demo:            scenarios,
 	}
 }
 
@@ -42,22 +98,115 @@ func (o *Orchestrator) ProcessRequest(ctx context.Context, req *model.UserReques
 		Str("input_type", req.InputType).
 		Msg("Processing user request")
 
-	// Step 1: Parse intent from user input
-	intent, err := o.intentParser.ParseIntent(ctx, req.Input, req.InputType)
+	// Keep the scam signals in what the user says for the session's transfers
+	o.scam.Observe(req.UserID, req.SessionID, req.Input)
+
+	// Step 1: Parse intents from user input, by rules alone once the LLM quota is used up
+	// or when the request's budget rules the LLM out. "Check my balance and then send
+	// 5000 to Ravi" holds two. A demo scenario's trigger phrase gives its scripted intent
+	// instead.
+	intents := o.playDemo(req)
+	budget := budgetFrom(ctx)
+	var err error
+	switch {
+	case intents != nil:
+		// Scripted by the demo scenario
+	case req.RulesOnly:
+		intents, err = o.intentParser.ParseIntentsWithoutLLM(req.Input, req.InputType)
+	case o.intentParser.UsesLLM(req.InputType) && !budget.useLLM(model.BudgetStageIntentLLM):
+		intents, err = o.intentParser.ParseIntentsWithoutLLM(req.Input, req.InputType)
+	default:
+		parseCtx, cancel := budget.llmContext(WithQuotaUser(ctx, req.UserID))
+		intents, err = o.intentParser.ParseIntents(parseCtx, req.Input, req.InputType, req.LLM)
+		if errors.Is(parseCtx.Err(), context.DeadlineExceeded) {
+			// The LLM ran out of budget and the parser fell back to rules
+			budget.degrade(model.BudgetStageIntentLLM, model.BudgetReasonLatency)
+		}
+		cancel()
+	}
+	if err != nil {
+		return nil, cancelledAt(ctx, model.CancelStageParse, fmt.Errorf("failed to parse intent: %w", err))
+	}
+	tenantID, _ := req.Context["tenant_id"].(string)
+	o.intentParser.MatchCustom(ctx, tenantID, intents)
+
+	var mergedResponse *model.MergedResponse
+	if len(intents) == 1 {
+		mergedResponse, err = o.processIntent(ctx, req, intents[0])
+		if err == nil {
+			o.pipeline.Apply(mergedResponse, req, intents[0])
+			mergedResponse.Suggestions = o.suggestions.Suggest(ctx, req, intents[0], mergedResponse)
+			o.analytics.RecordIntent(req, intents[0], mergedResponse, decisionStatus(mergedResponse))
+		} else {
+			o.analytics.RecordIntent(req, intents[0], nil, failureStatus(err))
+		}
+	} else {
+		mergedResponse, err = o.processSequence(ctx, req, intents)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse intent: %w", err)
+		return nil, err
 	}
 
+	duration := time.Since(startTime)
+	log.Info().
+		Str("final_status", mergedResponse.Status).
+		Int("intents", len(intents)).
+		Dur("duration", duration).
+		Msg("Request processed successfully")
+
+	return mergedResponse, nil
+}
+
+// playDemo tags a request whose input holds a demo scenario's trigger phrase with the
+// scenario, so the agents answer with its forced outcomes, and returns its scripted
+// intent if it has one. A tag sent by the client is always dropped: only a trigger
+// phrase plays a scenario.
+func (o *Orchestrator) playDemo(req *model.UserRequest) []*model.Intent {
+	delete(req.Context, demo.ContextKey)
+	scenario := o.demo.Match(req.Input)
+	if scenario == nil {
+		return nil
+	}
+	if req.Context == nil {
+		req.Context = make(map[string]interface{})
+	}
+	req.Context[demo.ContextKey] = scenario.ID
+	log.Info().Str("user_id", req.UserID).Str("scenario", scenario.ID).Msg("Playing demo scenario")
+
+	if scenario.Intent == "" {
+		return nil
+	}
+	entities := make(map[string]interface{}, len(scenario.Entities))
+	for k, v := range scenario.Entities {
+		entities[k] = v
+	}
+	return []*model.Intent{{
+		Type:         model.IntentType(scenario.Intent),
+		Confidence:   1,
+		Entities:     entities,
+		OriginalText: req.Input,
+		Metadata:     map[string]interface{}{demo.ContextKey: scenario.ID},
+	}}
+}
+
+// processIntent runs one intent through the pipeline: context resolution, enrichment,
+// agent execution, merging and output guardrails
+func (o *Orchestrator) processIntent(ctx context.Context, req *model.UserRequest, intent *model.Intent) (*model.MergedResponse, error) {
+	// Resolve "him", "same amount" and "again" against the last action in the session
+	o.contextResolver.Resolve(req, intent)
+
 	if intent.Type == model.IntentUnknown {
-		// Return a helpful error response instead of failing
+		// Return a helpful error response instead of failing, suggesting only what is available
+		errorText, explanation := o.capabilities.HelpText(ctx)
 		return &model.MergedResponse{
 			Status: "REJECTED",
 			FinalResult: map[string]interface{}{
-				"error": "Could not understand your request. Please try rephrasing or use one of these: check balance, transfer money, view statement, add beneficiary.",
+				"error": errorText,
 			},
 			RiskScore:   0.5,
-			Explanation: "I couldn't determine what you're asking for. Please try phrases like 'Check my balance', 'Transfer money', 'Show statement', or 'Add beneficiary'.",
+			Explanation: explanation,
 			AgentResponses: []model.AgentResponse{},
+			Degraded:       req.RulesOnly,
 		}, nil
 	}
 
@@ -66,10 +215,104 @@ func (o *Orchestrator) ProcessRequest(ctx context.Context, req *model.UserReques
 		Float64("confidence", intent.Confidence).
 		Msg("Intent parsed")
 
+	// An intent an operator switched off is answered without submitting anything
+	if refusal := o.flags.Check(ctx, req, intent.Type); refusal != nil {
+		return switchedOff(req, refusal), nil
+	}
+
+	// Some intents are better finished on a native screen, which collects what the
+	// conversation did not; nothing is executed
+	tenantID, _ := req.Context["tenant_id"].(string)
+	if handoff := o.handoffs.Handoff(intent, tenantID, req.Channel); handoff != nil {
+		log.Info().
+			Str("intent", string(intent.Type)).
+			Str("screen_id", handoff.ScreenID).
+			Int("prefilled", len(handoff.Prefill)).
+			Msg("Intent handed off to app screen")
+		result := map[string]interface{}{"screen_id": handoff.ScreenID}
+		if handoff.DeepLink != "" {
+			result["deep_link"] = handoff.DeepLink
+		}
+		return &model.MergedResponse{
+			Status:         model.StatusHandoff,
+			FinalResult:    result,
+			Explanation:    handoffExplanation(handoff),
+			AgentResponses: []model.AgentResponse{},
+			Simulated:      req.Sandbox,
+			Degraded:       req.RulesOnly,
+			Handoff:        handoff,
+		}, nil
+	}
+
+	// A custom intent runs only once the user has given every value it requires
+	if missing, ok := intent.Metadata["missing_entities"].([]string); ok && len(missing) > 0 {
+		return &model.MergedResponse{
+			Status: "REJECTED",
+			FinalResult: map[string]interface{}{
+				"error":            fmt.Sprintf("Please include the %s.", strings.Join(humanEntityNames(missing), " and ")),
+				"missing_entities": missing,
+			},
+			Explanation:    "Some details this request needs are missing.",
+			AgentResponses: []model.AgentResponse{},
+		}, nil
+	}
+
+	// Preference statements are stored directly, no agent is involved
+	if intent.Type == model.IntentSetPreference {
+		return o.savePreferences(ctx, req, intent), nil
+	}
+
+	// "Why?" is answered from the recorded decision rather than by re-running the pipeline
+	if intent.Type == model.IntentWhyRejected {
+		return o.explainLastDecision(req), nil
+	}
+
+	// A summary fetches the statement and computes its figures here; agents only see
+	// the statement request
+	if intent.Type == model.IntentSummarizeStatement {
+		return o.summarizeStatement(ctx, req, intent)
+	}
+
+	// "Send 50,000 instead" re-submits the last action with the slots the user changed
+	var retried []string
+	if intent.Type == model.IntentRetryLast {
+		var refusal *model.MergedResponse
+		intent, retried, refusal = o.resolveRetry(req, intent)
+		if refusal != nil {
+			return refusal, nil
+		}
+		if refusal := o.flags.Check(ctx, req, intent.Type); refusal != nil {
+			return switchedOff(req, refusal), nil
+		}
+	}
+
+	// An export's period becomes dates here, in the user's time zone
+	if intent.Type == model.IntentExportTransactions {
+		o.summarizer.ExportRange(req, intent, time.Now())
+	}
+
+	// Fill what the user left out from their saved preferences
+	applied := o.applyPreferences(ctx, req, intent)
+
+	// Find a named biller or merchant in the payee directory and badge the payee
+	verification, fromDirectory := o.resolvePayee(ctx, intent)
+
 	// Step 2: Enrich context with user history and behavior
 	enrichedContext, err := o.contextEnricher.EnrichContext(ctx, req.UserID, req.SessionID, req.Channel, *intent)
 	if err != nil {
-		return nil, fmt.Errorf("failed to enrich context: %w", err)
+		return nil, cancelledAt(ctx, model.CancelStageEnrich, fmt.Errorf("failed to enrich context: %w", err))
+	}
+
+	// A payment the user appears to be coached into is riskier than its numbers say
+	scamAssessment := o.scam.Assess(req, intent, enrichedContext, verification)
+	if scamAssessment != nil {
+		o.scam.Raise(enrichedContext, scamAssessment)
+		log.Warn().
+			Str("user_id", req.UserID).
+			Str("intent", string(intent.Type)).
+			Float64("scam_score", scamAssessment.Score).
+			Str("scam_level", scamAssessment.Level).
+			Msg("Scam signals around payment")
 	}
 
 	log.Info().
@@ -81,6 +324,11 @@ func (o *Orchestrator) ProcessRequest(ctx context.Context, req *model.UserReques
 
 	// Step 4: Submit task to MCP server and get response
 	agentResponse, err := o.mcpClient.SubmitTask(ctx, req, *intent, enrichedContext)
+	// The MCP server may know of a flag before the cached ones do
+	var refusal *IntentDisabledError
+	if errors.As(err, &refusal) {
+		return switchedOff(req, refusal), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agent response: %w", err)
 	}
@@ -95,20 +343,733 @@ func (o *Orchestrator) ProcessRequest(ctx context.Context, req *model.UserReques
 	responses := []model.AgentResponse{*agentResponse}
 
 	// Step 6: Merge responses (even if single, for consistency)
-	mergedResponse, err := o.responseMerger.MergeResponses(responses)
+	mergedResponse, err := o.responseMerger.MergeResponses(intent.Type, responses)
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge responses: %w", err)
 	}
+	mergedResponse.Simulated = req.Sandbox
+	mergedResponse.Degraded = req.RulesOnly
+	o.explainFailure(ctx, req, intent.Type, mergedResponse)
+	if len(applied) > 0 && mergedResponse.FinalResult != nil {
+		mergedResponse.FinalResult["preferences_applied"] = applied
+	}
+	if retried != nil && mergedResponse.FinalResult != nil {
+		mergedResponse.FinalResult["retried_with"] = retried
+	}
+	if len(intent.Inferred) > 0 {
+		if mergedResponse.FinalResult != nil {
+			mergedResponse.FinalResult["inferred_from_context"] = intent.Inferred
+		}
+		if note := describeInferred(intent.Inferred); note != "" {
+			mergedResponse.Explanation = strings.TrimSpace(mergedResponse.Explanation + " " + note)
+		}
+	}
+
+	if verification != nil && mergedResponse.FinalResult != nil {
+		mergedResponse.FinalResult["payee_verification"] = verification
+		if len(fromDirectory) > 0 {
+			mergedResponse.FinalResult["payee_from_directory"] = fromDirectory
+		}
+		// Every transfer to a person is NOT_LISTED; only directory payees are worth a note
+		if verification.Status != "NOT_LISTED" {
+			mergedResponse.Explanation = strings.TrimSpace(mergedResponse.Explanation + " " + verification.Message)
+		}
+	}
+
+	// Tell the user when an accepted transfer will actually be credited
+	o.addSettlement(ctx, intent, mergedResponse)
+
+	// Say how to improve a credit score, not just what it is
+	narrateCreditTips(intent, mergedResponse)
+
+	// Warn before the user confirms a payment that looks like a scam
+	if scamAssessment != nil && mergedResponse.FinalResult != nil {
+		mergedResponse.FinalResult["scam_assessment"] = scamAssessment
+		if scamAssessment.Warning != "" && mergedResponse.Status != "REJECTED" {
+			mergedResponse.Explanation = strings.TrimSpace(scamAssessment.Warning + " " + mergedResponse.Explanation)
+		}
+	}
+
+	// Step 7: Validate user-facing text before it is returned
+	o.responseGuard.Check(mergedResponse, req, intent)
+
+	// Keep the outcome on the session so the user can ask why it was rejected
+	o.decisions.Record(req, intent, mergedResponse)
+
+	// Step 8: Record the operation for retrieval; embedding happens in the background
+	if !req.Sandbox {
+		if _, err := o.ragService.StoreTransaction(req.UserID, *intent, mergedResponse.Status, mergedResponse.FinalResult); err != nil {
+			log.Warn().Err(err).Msg("Failed to store transaction for retrieval")
+		}
+	}
+
+	return mergedResponse, nil
+}
+
+// resolvePayee looks a transfer's payee up in the Banking Integrations payee directory.
+// A payee named without an account, as in "pay Airtel 599", is filled in from its
+// verified listing; payees known only by VPA are paid over UPI. Whatever the payee,
+// it gets the verification badge shown with the confirmation. Returns the badge and
+// the entities filled in from the directory.
+func (o *Orchestrator) resolvePayee(ctx context.Context, intent *model.Intent) (*model.PayeeVerification, []string) {
+	if !isTransferIntent(intent.Type) || intent.Entities == nil {
+		return nil, nil
+	}
+	name, _ := intent.Entities["name"].(string)
+	account, _ := intent.Entities["to_account"].(string)
+	upiID, _ := intent.Entities["upi_id"].(string)
+	ifsc, _ := intent.Entities["ifsc"].(string)
+
+	var filled []string
+	if account == "" && upiID == "" && name != "" {
+		matches, err := o.payees.Search(ctx, name)
+		if err != nil {
+			log.Warn().Err(err).Str("name", name).Msg("Failed to look up payee, continuing without it")
+			return nil, nil
+		}
+
+		// Only a verified listing the name fully matches decides where money goes
+		if len(matches) > 0 && matches[0].Verified && matches[0].Score >= 0.9 {
+			payee := matches[0]
+			switch {
+			case payee.VPA != "" && (intent.Type == model.IntentTransferUPI || payee.AccountNumber == ""):
+				account = payee.VPA
+				if intent.Type != model.IntentTransferUPI {
+					intent.Type = model.IntentTransferUPI
+					filled = append(filled, "transfer_method")
+				}
+			case payee.AccountNumber != "":
+				account, ifsc = payee.AccountNumber, payee.IFSC
+				if ifsc != "" {
+					intent.Entities["ifsc"] = ifsc
+					filled = append(filled, "ifsc")
+				}
+			}
+			intent.Entities["to_account"] = account
+			intent.Entities["name"] = payee.Name
+			filled = append(filled, "to_account")
+
+			log.Info().
+				Str("payee_id", payee.PayeeID).
+				Str("name", payee.Name).
+				Str("intent", string(intent.Type)).
+				Msg("Payee filled in from the payee directory")
+		}
+	}
+	if account == "" && upiID == "" {
+		return nil, filled
+	}
+
+	verification, err := o.payees.Verify(ctx, &model.PayeeVerificationRequest{
+		ToAccount: account,
+		IFSC:      ifsc,
+		VPA:       upiID,
+		PayeeName: name,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to verify payee, continuing without it")
+		return nil, filled
+	}
+	return verification, filled
+}
+
+// VerifyAuth answers the step-up challenge a transfer is held on and returns the
+// transfer's outcome once the MCP server releases it
+func (o *Orchestrator) VerifyAuth(ctx context.Context, challengeID, method string, answer map[string]interface{}) (*model.MergedResponse, error) {
+	agentResponse, err := o.mcpClient.VerifyAuth(ctx, challengeID, method, answer)
+	if err != nil {
+		return nil, err
+	}
+	o.analytics.RecordConfirmation(challengeID)
+
+	return o.mergeHeldTransfer(ctx, agentResponse)
+}
+
+// ConfirmSplit accepts the split a transfer over its rail's limits was offered as and
+// returns the transfer's outcome, or the step-up challenge it is then held on
+func (o *Orchestrator) ConfirmSplit(ctx context.Context, splitID string) (*model.MergedResponse, error) {
+	agentResponse, err := o.mcpClient.ConfirmSplit(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	return o.mergeHeldTransfer(ctx, agentResponse)
+}
+
+// DeclineSplit turns down the split a transfer was offered as; nothing is sent
+func (o *Orchestrator) DeclineSplit(ctx context.Context, splitID string) (*model.MergedResponse, error) {
+	agentResponse, err := o.mcpClient.DeclineSplit(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	return o.responseMerger.MergeResponses("", []model.AgentResponse{*agentResponse})
+}
+
+// switchedOff answers a request for an intent an operator switched off
+func switchedOff(req *model.UserRequest, refusal *IntentDisabledError) *model.MergedResponse {
+	log.Warn().
+		Str("user_id", req.UserID).
+		Str("intent", refusal.Intent).
+		Str("mode", refusal.Mode).
+		Msg("Request refused by intent flag")
+	message := switchedOffMessage(refusal)
+	return &model.MergedResponse{
+		Status: "REJECTED",
+		FinalResult: map[string]interface{}{
+			"error":       message,
+			"error_code":  model.ErrorCodeFeatureDisabled,
+			"intent_flag": refusal.Mode,
+		},
+		Explanation:    message,
+		AgentResponses: []model.AgentResponse{},
+		Simulated:      req.Sandbox,
+		Degraded:       req.RulesOnly,
+	}
+}
+
+// mergeHeldTransfer merges the outcome of a transfer released by the user, after
+// step-up authentication or accepting a split
+func (o *Orchestrator) mergeHeldTransfer(ctx context.Context, agentResponse *model.AgentResponse) (*model.MergedResponse, error) {
+	resp, err := o.responseMerger.MergeResponses("", []model.AgentResponse{*agentResponse})
+	if err != nil {
+		return nil, err
+	}
+	// The rail is not known here; any one words the explanation as a transfer's
+	o.explainFailure(ctx, nil, model.IntentTransferNEFT, resp)
+	return resp, nil
+}
+
+// explainFailure replaces what a failed task says with an explanation for the user.
+// The task's raw error goes to the logs and the diagnostics block only.
+func (o *Orchestrator) explainFailure(ctx context.Context, req *model.UserRequest, intent model.IntentType, resp *model.MergedResponse) {
+	if resp.Status != "FAILED" {
+		return
+	}
+
+	raw, taskID := "", ""
+	for _, ar := range resp.AgentResponses {
+		if ar.Error != "" {
+			raw = ar.Error
+		}
+		if id, _ := ar.Result["task_id"].(string); id != "" {
+			taskID = id
+		}
+	}
+	if raw == "" {
+		raw = "task failed without an error"
+	}
+
+	explanation, diagnostics := o.humanizer.Explain(ctx, req, intent, taskID, errors.New(raw))
+	resp.Error = explanation
+	resp.Diagnostics = diagnostics
+	resp.Explanation = explanation.Text()
+	resp.FinalResult = map[string]interface{}{
+		"error":      explanation.Message,
+		"error_code": explanation.Code,
+	}
+	if taskID != "" {
+		resp.FinalResult["task_id"] = taskID
+	}
+}
+
+// ExplainError returns what to tell the user about a request that failed with err,
+// and the diagnostics block
+func (o *Orchestrator) ExplainError(ctx context.Context, req *model.UserRequest, err error) (*model.ErrorExplanation, *model.ErrorDiagnostics) {
+	return o.humanizer.Explain(ctx, req, model.IntentUnknown, "", err)
+}
+
+// addSettlement adds the expected settlement time of a transfer that was not
+// rejected, and says so in the explanation when the transfer will be credited late
+func (o *Orchestrator) addSettlement(ctx context.Context, intent *model.Intent, resp *model.MergedResponse) {
+	if !strings.HasPrefix(string(intent.Type), "TRANSFER_") || resp.Status == "REJECTED" || resp.FinalResult == nil {
+		return
+	}
+
+	rail := strings.TrimPrefix(string(intent.Type), "TRANSFER_")
+	estimate, err := o.calendar.EstimateSettlement(ctx, rail, time.Now())
+	if err != nil {
+		log.Warn().Err(err).Str("rail", rail).Msg("Failed to estimate settlement, continuing without it")
+		return
+	}
+
+	resp.FinalResult["expected_settlement"] = estimate
+	if estimate.Delayed {
+		resp.Explanation = strings.TrimSpace(resp.Explanation + " " + estimate.Message)
+	}
+}
+
+// maxNarratedTips caps the credit-score tips read out in the explanation; the rest
+// stay in final_result.tips
+const maxNarratedTips = 3
+
+// narrateCreditTips turns the improvement tips the scoring agent returned with a
+// credit score into a few conversational sentences, the biggest lift first
+func narrateCreditTips(intent *model.Intent, resp *model.MergedResponse) {
+	if intent.Type != model.IntentCreditScore || resp.FinalResult == nil {
+		return
+	}
+	tips, _ := resp.FinalResult["tips"].([]interface{})
+	var titles []string
+	var first string
+	for _, t := range tips {
+		tip, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		title, _ := tip["title"].(string)
+		if title == "" {
+			continue
+		}
+		if first == "" {
+			detail, _ := tip["detail"].(string)
+			first = strings.TrimSpace(fmt.Sprintf("The biggest lift: %s. %s", strings.ToLower(title[:1])+title[1:], detail))
+			if points, ok := tip["potential_points"].(float64); ok && points > 0 {
+				first += fmt.Sprintf(" That could add about %.0f points.", points)
+			}
+			continue
+		}
+		titles = append(titles, strings.ToLower(title[:1])+title[1:])
+		if len(titles) == maxNarratedTips-1 {
+			break
+		}
+	}
+	if first == "" {
+		return
+	}
+
+	narration := first
+	if len(titles) > 0 {
+		also := titles[len(titles)-1]
+		if len(titles) > 1 {
+			also = strings.Join(titles[:len(titles)-1], ", ") + " and " + also
+		}
+		narration += " You could also " + also + "."
+	}
+	if explanation := strings.TrimRight(resp.Explanation, ". "); explanation != "" {
+		narration = explanation + ". " + narration
+	}
+	resp.Explanation = narration
+}
+
+// maxIntentsPerRequest caps how many requests one message may hold
+const maxIntentsPerRequest = 5
+
+// processSequence runs the intents of a message holding several requests in order and
+// combines their results under final_result.steps. A step that does not go through
+// stops the steps after it, which are reported as SKIPPED, so a transfer never runs
+// after the balance check before it failed.
+func (o *Orchestrator) processSequence(ctx context.Context, req *model.UserRequest, intents []*model.Intent) (*model.MergedResponse, error) {
+	if len(intents) > maxIntentsPerRequest {
+		return &model.MergedResponse{
+			Status:         "REJECTED",
+			FinalResult:    map[string]interface{}{"error": fmt.Sprintf("Too many requests in one message (%d)", len(intents))},
+			Explanation:    fmt.Sprintf("Please send at most %d requests in one message.", maxIntentsPerRequest),
+			AgentResponses: []model.AgentResponse{},
+			Degraded:       req.RulesOnly,
+		}, nil
+	}
+
+	combined := &model.MergedResponse{
+		Status:         "APPROVED",
+		AgentResponses: []model.AgentResponse{},
+		Simulated:      req.Sandbox,
+		Degraded:       req.RulesOnly,
+	}
+	steps := make([]map[string]interface{}, 0, len(intents))
+	explanations := make([]string, 0, len(intents))
+	stopped := false
+	skipped := 0
+	// The last step that ran picks the quick replies
+	var lastReq *model.UserRequest
+	var lastIntent *model.Intent
+	var lastResp *model.MergedResponse
+
+	for i, intent := range intents {
+		step := map[string]interface{}{
+			"step":   i + 1,
+			"intent": intent.Type,
+			"input":  intent.OriginalText,
+		}
+		if stopped {
+			step["status"] = "SKIPPED"
+			steps = append(steps, step)
+			skipped++
+			o.analytics.RecordIntent(req, intent, nil, "SKIPPED")
+			continue
+		}
+
+		// Each step sees only its own segment, so rails and pronouns resolve per request
+		stepReq := *req
+		stepReq.Input = intent.OriginalText
+
+		resp, err := o.processIntent(ctx, &stepReq, intent)
+		if err != nil {
+			o.analytics.RecordIntent(&stepReq, intent, nil, failureStatus(err))
+			// Nothing has run yet, so the whole request can fail as a single one would.
+			// Nobody is left to read the steps of an abandoned request.
+			if i == 0 || errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			log.Warn().Int("step", i+1).Str("intent", string(intent.Type)).Msg("Step of multi-intent request failed")
+			explanation, _ := o.humanizer.Explain(ctx, &stepReq, intent.Type, "", err)
+			step["status"] = "FAILED"
+			step["error"] = explanation.Message
+			step["error_code"] = explanation.Code
+			step["error_reference"] = explanation.Reference
+			combined.Status = "FAILED"
+			explanations = append(explanations, fmt.Sprintf("%d. This request could not be completed.", i+1))
+			steps = append(steps, step)
+			stopped = true
+			continue
+		}
+
+		// Each step is formatted for its own intent
+		o.pipeline.Apply(resp, &stepReq, intent)
+		lastReq, lastIntent, lastResp = &stepReq, intent, resp
+
+		status := decisionStatus(resp)
+		o.analytics.RecordIntent(&stepReq, intent, resp, status)
+		step["status"] = status
+		step["final_result"] = resp.FinalResult
+		step["explanation"] = resp.Explanation
+		steps = append(steps, step)
+
+		explanations = append(explanations, fmt.Sprintf("%d. %s", i+1, resp.Explanation))
+		combined.AgentResponses = append(combined.AgentResponses, resp.AgentResponses...)
+		combined.Conflicts = append(combined.Conflicts, resp.Conflicts...)
+		combined.Guardrails = append(combined.Guardrails, resp.Guardrails...)
+		if resp.Handoff != nil {
+			combined.Handoff = resp.Handoff
+		}
+		if resp.RiskScore > combined.RiskScore {
+			combined.RiskScore = resp.RiskScore
+		}
+
+		if status != "APPROVED" && status != "COMPLETED" {
+			combined.Status = status
+			stopped = true
+		}
+	}
+
+	if skipped > 0 {
+		explanations = append(explanations, fmt.Sprintf("The %d request(s) after it were not run.", skipped))
+	}
+	combined.FinalResult = map[string]interface{}{"steps": steps}
+	combined.Explanation = strings.Join(explanations, "\n")
+	if lastResp != nil {
+		combined.Suggestions = o.suggestions.Suggest(ctx, lastReq, lastIntent, lastResp)
+	}
+	return combined, nil
+}
+
+// CoalescingStats counts the read-only requests that shared an MCP task
+func (o *Orchestrator) CoalescingStats() model.CoalescingStats {
+	return o.mcpClient.CoalescingStats()
+}
+
+// failureStatus is the analytics status of an intent that returned err
+func failureStatus(err error) string {
+	if errors.Is(err, context.Canceled) {
+		return "CANCELLED"
+	}
+	return "FAILED"
+}
+
+// explainLastDecision explains the user's last decision in this session from its
+// recorded reasons
+func (o *Orchestrator) explainLastDecision(req *model.UserRequest) *model.MergedResponse {
+	decision := o.decisions.Last(req.UserID, req.SessionID)
+	if decision == nil {
+		return &model.MergedResponse{
+			Status:         "APPROVED",
+			FinalResult:    map[string]interface{}{"last_decision": nil},
+			Explanation:    "I don't have a recent request of yours to explain. If a transfer was declined, try it again and ask me right after.",
+			AgentResponses: []model.AgentResponse{},
+		}
+	}
 
-	duration := time.Since(startTime)
 	log.Info().
-		Str("final_status", mergedResponse.Status).
-		Dur("duration", duration).
-		Msg("Request processed successfully")
+		Str("user_id", req.UserID).
+		Str("intent", string(decision.Intent)).
+		Str("status", decision.Status).
+		Int("reasons", len(decision.Reasons)).
+		Msg("Explaining last decision")
+
+	return &model.MergedResponse{
+		Status:         "APPROVED",
+		FinalResult:    map[string]interface{}{"last_decision": decision},
+		RiskScore:      decision.RiskScore,
+		Explanation:    ExplainDecision(decision),
+		AgentResponses: []model.AgentResponse{},
+		Simulated:      decision.Simulated,
+	}
+}
+
+// summarizeStatement answers a SUMMARIZE_STATEMENT intent: the statement for the
+// period is fetched like GET_STATEMENT, and a successful one is replaced by its
+// narrative and aggregates
+func (o *Orchestrator) summarizeStatement(ctx context.Context, req *model.UserRequest, intent *model.Intent) (*model.MergedResponse, error) {
+	period := o.summarizer.Period(req, intent, time.Now())
+	statement := o.summarizer.StatementIntent(intent, period)
+
+	enrichedContext, err := o.contextEnricher.EnrichContext(ctx, req.UserID, req.SessionID, req.Channel, *statement)
+	if err != nil {
+		return nil, cancelledAt(ctx, model.CancelStageEnrich, fmt.Errorf("failed to enrich context: %w", err))
+	}
+
+	agentResponse, err := o.mcpClient.SubmitTask(ctx, req, *statement, enrichedContext)
+	var refusal *IntentDisabledError
+	if errors.As(err, &refusal) {
+		return switchedOff(req, refusal), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent response: %w", err)
+	}
+
+	mergedResponse, err := o.responseMerger.MergeResponses(statement.Type, []model.AgentResponse{*agentResponse})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge responses: %w", err)
+	}
+	mergedResponse.Simulated = req.Sandbox
+	mergedResponse.Degraded = req.RulesOnly
+	o.explainFailure(ctx, req, intent.Type, mergedResponse)
+
+	if (mergedResponse.Status == "APPROVED" || mergedResponse.Status == "COMPLETED") && mergedResponse.FinalResult != nil {
+		summary := o.summarizer.Summarize(ctx, req, period, mergedResponse.FinalResult)
+		mergedResponse.FinalResult = map[string]interface{}{
+			"narrative":   summary.Narrative,
+			"narrated_by": summary.NarratedBy,
+			"period":      summary.Period,
+			"aggregates":  summary.Aggregates,
+		}
+		if summary.AccountID != "" {
+			mergedResponse.FinalResult["account_id"] = summary.AccountID
+		}
+		mergedResponse.Explanation = summary.Narrative
+	}
 
+	o.responseGuard.Check(mergedResponse, req, intent)
+	o.decisions.Record(req, intent, mergedResponse)
 	return mergedResponse, nil
 }
 
+// resolveRetry builds the intent for a retry from the user's last action, replacing
+// only the slots the follow-up names, and returns the slots it changed. Actions that
+// went through or are still pending are not retried; the returned response says why.
+func (o *Orchestrator) resolveRetry(req *model.UserRequest, changes *model.Intent) (*model.Intent, []string, *model.MergedResponse) {
+	last := o.decisions.LastAction(req.UserID, req.SessionID)
+	if last == nil {
+		return nil, nil, &model.MergedResponse{
+			Status:         "REJECTED",
+			FinalResult:    map[string]interface{}{"error": "No recent action to retry"},
+			Explanation:    "I don't have a recent transfer or request of yours to retry. Please give me the full instruction, for example 'Transfer 50,000 to account 1234567890 via IMPS'.",
+			AgentResponses: []model.AgentResponse{},
+		}
+	}
+
+	switch last.Status {
+	case "APPROVED", "COMPLETED", "PENDING":
+		state := "went through"
+		if last.Status == "PENDING" {
+			state = "is still being processed"
+		}
+		return nil, nil, &model.MergedResponse{
+			Status:         "REJECTED",
+			FinalResult:    map[string]interface{}{"error": "Last action was not rejected", "last_decision": last},
+			Explanation:    fmt.Sprintf("%s %s, so there is nothing to retry. To make another one, please give me the full instruction.", capitalize(decisionSubject(last)), state),
+			AgentResponses: []model.AgentResponse{},
+		}
+	}
+
+	intent := &model.Intent{
+		Type:         last.Intent,
+		Confidence:   changes.Confidence,
+		Entities:     copyEntities(last.Entities),
+		OriginalText: changes.OriginalText,
+		Metadata:     map[string]interface{}{"retry_of": last.DecidedAt},
+	}
+
+	changed := []string{}
+	for k, v := range changes.Entities {
+		if k == "transfer_method" {
+			continue
+		}
+		if !reflect.DeepEqual(intent.Entities[k], v) {
+			changed = append(changed, k)
+		}
+		intent.Entities[k] = v
+	}
+
+	// A rail the user names now, or named originally, is kept; one that came from
+	// preferences is chosen again for the new amount
+	method, _ := changes.Entities["transfer_method"].(string)
+	if method != "" && isTransferIntent(intent.Type) {
+		if preferred := model.IntentType("TRANSFER_" + method); preferred != intent.Type {
+			intent.Type = preferred
+			changed = append(changed, "transfer_method")
+		}
+		intent.Metadata["transfer_method_fixed"] = true
+	} else if last.MethodSource == "" {
+		intent.Metadata["transfer_method_fixed"] = true
+	}
+	sort.Strings(changed)
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("intent", string(intent.Type)).
+		Strs("changed", changed).
+		Msg("Retrying last action")
+
+	return intent, changed, nil
+}
+
+// applyPreferences picks the user's preferred transfer method when a transfer request
+// did not name one, and returns the entities that came from preferences. Default
+// accounts are applied by the Banking Agent.
+func (o *Orchestrator) applyPreferences(ctx context.Context, req *model.UserRequest, intent *model.Intent) []string {
+	if !isTransferIntent(intent.Type) || req.InputType == "structured" || namedTransferMethod(req.Input) != "" || intent.Metadata["transfer_method_fixed"] == true {
+		return nil
+	}
+
+	prefs, err := o.preferences.Get(ctx, req.UserID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", req.UserID).Msg("Failed to load user preferences, continuing without them")
+		return nil
+	}
+
+	method, source := TransferMethodFor(prefs, entityAmount(intent.Entities))
+	if method == "" {
+		return nil
+	}
+
+	preferred := model.IntentType("TRANSFER_" + method)
+	if preferred != intent.Type {
+		log.Info().
+			Str("user_id", req.UserID).
+			Str("from", string(intent.Type)).
+			Str("to", string(preferred)).
+			Str("source", source).
+			Msg("Transfer method chosen from user preferences")
+		intent.Type = preferred
+	}
+
+	if intent.Metadata == nil {
+		intent.Metadata = make(map[string]interface{})
+	}
+	intent.Metadata["transfer_method_source"] = source
+	return []string{"transfer_method"}
+}
+
+// savePreferences stores a preference statement such as "always use IMPS for transfers
+// under 1 lakh". In sandbox mode nothing is stored.
+func (o *Orchestrator) savePreferences(ctx context.Context, req *model.UserRequest, intent *model.Intent) *model.MergedResponse {
+	update := preferenceUpdateFromEntities(intent.Entities)
+	if update.IsEmpty() {
+		return &model.MergedResponse{
+			Status:         "REJECTED",
+			FinalResult:    map[string]interface{}{"error": "No preference found in the request"},
+			Explanation:    "I couldn't tell which preference to save. Try 'Always use IMPS for transfers under 1 lakh' or 'Notify me by SMS'.",
+			AgentResponses: []model.AgentResponse{},
+		}
+	}
+
+	explanation := "Your preferences have been saved: " + describePreferenceUpdate(update)
+	if req.Sandbox {
+		return &model.MergedResponse{
+			Status:         "APPROVED",
+			FinalResult:    map[string]interface{}{"update": update},
+			Explanation:    "Preferences simulated in sandbox mode; nothing was saved: " + describePreferenceUpdate(update),
+			AgentResponses: []model.AgentResponse{},
+			Simulated:      true,
+		}
+	}
+
+	prefs, err := o.preferences.Update(ctx, req.UserID, update)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", req.UserID).Msg("Failed to save user preferences")
+		return &model.MergedResponse{
+			Status:         "REJECTED",
+			FinalResult:    map[string]interface{}{"error": "Failed to save preferences"},
+			Explanation:    "I couldn't save your preference right now. Please try again later.",
+			AgentResponses: []model.AgentResponse{},
+		}
+	}
+
+	return &model.MergedResponse{
+		Status:         "APPROVED",
+		FinalResult:    map[string]interface{}{"preferences": prefs},
+		Explanation:    explanation,
+		AgentResponses: []model.AgentResponse{},
+	}
+}
+
+// preferenceUpdateFromEntities turns SET_PREFERENCE entities into an update. A method
+// with an amount limit becomes a transfer rule; without one it is the preferred method.
+func preferenceUpdateFromEntities(entities map[string]interface{}) *model.PreferenceUpdate {
+	update := &model.PreferenceUpdate{}
+
+	method, _ := entities["transfer_method"].(string)
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method != "" {
+		if limit := entityFloat(entities["max_amount"]); limit > 0 {
+			update.TransferRules = []model.TransferRule{{Method: method, MaxAmount: limit}}
+		} else {
+			update.PreferredTransferMethod = method
+		}
+	}
+	if account, ok := entities["default_account"].(string); ok {
+		update.DefaultAccount = strings.TrimSpace(account)
+	}
+	if channel, ok := entities["notification_channel"].(string); ok {
+		update.NotificationChannel = strings.ToUpper(strings.TrimSpace(channel))
+	}
+	return update
+}
+
+// describePreferenceUpdate summarises an update for the user
+func describePreferenceUpdate(update *model.PreferenceUpdate) string {
+	var parts []string
+	for _, rule := range update.TransferRules {
+		parts = append(parts, fmt.Sprintf("%s for transfers up to INR %.0f", rule.Method, rule.MaxAmount))
+	}
+	if update.PreferredTransferMethod != "" {
+		parts = append(parts, fmt.Sprintf("%s for transfers", update.PreferredTransferMethod))
+	}
+	if update.DefaultAccount != "" {
+		parts = append(parts, fmt.Sprintf("default account %s", update.DefaultAccount))
+	}
+	if update.NotificationChannel != "" {
+		parts = append(parts, fmt.Sprintf("notifications by %s", update.NotificationChannel))
+	}
+	return strings.Join(parts, ", ") + "."
+}
+
+// isTransferIntent reports whether an intent moves money between accounts
+func isTransferIntent(t model.IntentType) bool {
+	switch t {
+	case model.IntentTransferNEFT, model.IntentTransferRTGS, model.IntentTransferIMPS, model.IntentTransferUPI:
+		return true
+	}
+	return false
+}
+
+// entityAmount returns the amount entity as a number, 0 when absent
+func entityAmount(entities map[string]interface{}) float64 {
+	return entityFloat(entities["amount"])
+}
+
+// entityFloat reads a numeric entity that may arrive as a number or a string
+func entityFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case string:
+		return parseAmount(strings.ReplaceAll(n, ",", ""))
+	}
+	return 0
+}
+
 // shouldUseMultiAgent determines if multiple agents should be involved
 func (o *Orchestrator) shouldUseMultiAgent(intent *model.Intent, context *model.EnrichedContext) bool {
 	// Use multi-agent for high-risk transactions
@@ -164,3 +1125,11 @@ func (o *Orchestrator) ProcessMultiAgentRequest(ctx context.Context, req *model.
 	}, nil
 }
 
+// humanEntityNames turns entity names such as nominee_name into "nominee name"
+func humanEntityNames(names []string) []string {
+	human := make([]string, len(names))
+	for i, name := range names {
+		human[i] = strings.ReplaceAll(name, "_", " ")
+	}
+	return human
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// PayeeClient looks payees up in the Banking Integrations (Layer 5) directory of
+// billers and merchants
+type PayeeClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewPayeeClient creates a new payee client
+func NewPayeeClient(cfg *config.BankingIntegrationsConfig) *PayeeClient {
+	return &PayeeClient{
+		baseURL: cfg.BaseURL,
+		apiKey:  config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// Search returns the directory payees matching a name, best match first
+func (pc *PayeeClient) Search(ctx context.Context, name string) ([]model.PayeeMatch, error) {
+	query := url.Values{"q": {name}, "limit": {"3"}}
+	var result struct {
+		Payees []model.PayeeMatch `json:"payees"`
+	}
+	if err := pc.do(ctx, "GET", "/api/v1/payees?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Payees, nil
+}
+
+// Verify returns the verification badge for a transfer's payee
+func (pc *PayeeClient) Verify(ctx context.Context, req *model.PayeeVerificationRequest) (*model.PayeeVerification, error) {
+	var verification model.PayeeVerification
+	if err := pc.do(ctx, "POST", "/api/v1/payees/verify", req, &verification); err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// do sends one directory request and decodes its response
+func (pc *PayeeClient) do(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, pc.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", pc.apiKey.Get())
+
+	resp, err := pc.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to reach banking integrations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("banking integrations error: %s", string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse payee response: %w", err)
+	}
+	return nil
+}
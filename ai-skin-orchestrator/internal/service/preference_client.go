@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// PreferenceClient reads and updates user preferences in Banking Integrations (Layer 5)
+type PreferenceClient struct {
+	baseURL    string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewPreferenceClient creates a new preference client
+func NewPreferenceClient(cfg *config.BankingIntegrationsConfig) *PreferenceClient {
+	return &PreferenceClient{
+		baseURL: cfg.BaseURL,
+		apiKey:  config.Profile().RotatingSecret("BANKING_INTEGRATIONS_API_KEY", cfg.APIKey),
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// Get returns a user's preferences, or nil when the user has none
+func (pc *PreferenceClient) Get(ctx context.Context, userID string) (*model.UserPreferences, error) {
+	var prefs model.UserPreferences
+	found, err := pc.do(ctx, "GET", userID, nil, &prefs)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Update merges an update into a user's preferences and returns the result
+func (pc *PreferenceClient) Update(ctx context.Context, userID string, update *model.PreferenceUpdate) (*model.UserPreferences, error) {
+	var prefs model.UserPreferences
+	if _, err := pc.do(ctx, "PUT", userID, update, &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// do sends one preference request; found is false on 404
+func (pc *PreferenceClient) do(ctx context.Context, method, userID string, payload interface{}, out interface{}) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/preferences/%s", pc.baseURL, url.PathEscape(userID))
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", pc.apiKey.Get())
+
+	resp, err := pc.httpClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to reach banking integrations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("banking integrations error: %s", string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return true, nil
+}
+
+// TransferMethodFor returns the method preferred for an amount (0 when unknown) and
+// whether it came from an amount rule or the general preference
+func TransferMethodFor(prefs *model.UserPreferences, amount float64) (string, string) {
+	if prefs == nil {
+		return "", ""
+	}
+	if amount > 0 {
+		for _, rule := range prefs.TransferRules {
+			if rule.MaxAmount == 0 || amount <= rule.MaxAmount {
+				return rule.Method, "rule"
+			}
+		}
+	}
+	if prefs.PreferredTransferMethod != "" {
+		return prefs.PreferredTransferMethod, "preferred"
+	}
+	return "", ""
+}
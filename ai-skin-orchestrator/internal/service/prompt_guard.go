@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxUntrustedLength caps how much untrusted text is placed in a single prompt
+const maxUntrustedLength = 4000
+
+// injectionPattern is an instruction-like phrase that untrusted text must not carry into a prompt
+type injectionPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// GuardResult is sanitized text plus the names of the patterns that were removed
+type GuardResult struct {
+	Text    string
+	Flagged []string
+}
+
+// PromptGuard mitigates prompt injection from user messages and retrieved content,
+// and validates LLM output before the platform acts on it
+type PromptGuard struct {
+	patterns      []injectionPattern
+	allowedIntent map[string]bool
+	allowedEntity map[string]bool
+}
+
+// NewPromptGuard creates a new prompt guard
+func NewPromptGuard() *PromptGuard {
+	allowedIntent := map[string]bool{"UNKNOWN": true}
+	for _, c := range SupportedCapabilities() {
+		allowedIntent[c] = true
+	}
+
+	allowedEntity := make(map[string]bool)
+	for _, k := range []string{
+		"amount", "to_account", "from_account", "account", "ifsc", "name",
+		"beneficiary", "beneficiary_name", "upi_id", "remarks", "transfer_type",
+		"start_date", "end_date", "period", "loan_amount", "loan_type", "tenure_months",
+		"transfer_method", "max_amount", "default_account", "notification_channel",
+	} {
+		allowedEntity[k] = true
+	}
+
+	return &PromptGuard{
+		patterns: []injectionPattern{
+			{"override_instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b[^.\n]{0,40}\b(previous|prior|above|earlier|all|any|system)\b[^.\n]{0,20}\b(instructions?|prompts?|rules?|messages?)`)},
+			{"new_instructions", regexp.MustCompile(`(?i)\b(new|updated|real)\s+(instructions?|system\s+prompt)\s*:`)},
+			{"role_reassignment", regexp.MustCompile(`(?i)\byou\s+are\s+now\b|\bact\s+as\s+(an?\s+)?(admin|administrator|developer|system|root)\b|\bpretend\s+(to\s+be|you\s+are)\b`)},
+			{"prompt_exfiltration", regexp.MustCompile(`(?i)\b(reveal|print|show|repeat|output)\b[^.\n]{0,30}\b(system\s+prompt|your\s+(instructions|prompt|rules))`)},
+			{"role_marker", regexp.MustCompile(`(?im)^\s*(system|assistant|developer)\s*:`)},
+			{"delimiter_spoofing", regexp.MustCompile(`(?i)</?\s*(system|user_input|document|assistant|instructions?)\s*[^>]*>`)},
+			{"jailbreak", regexp.MustCompile(`(?i)\b(jailbreak|dan\s+mode|developer\s+mode)\b`)},
+			{"code_fence", regexp.MustCompile("```")},
+		},
+		allowedIntent: allowedIntent,
+		allowedEntity: allowedEntity,
+	}
+}
+
+// SanitizeUserInput strips instruction-like patterns from a user message
+func (pg *PromptGuard) SanitizeUserInput(text string) GuardResult {
+	result := pg.sanitize(text)
+	if len(result.Flagged) > 0 {
+		log.Warn().
+			Str("source", "user_input").
+			Strs("patterns", result.Flagged).
+			Msg("Prompt injection attempt flagged")
+	}
+	return result
+}
+
+// SanitizeRetrieved strips instruction-like patterns from retrieved content (documents,
+// stored messages) and brackets it so the model treats it as data, not instructions
+func (pg *PromptGuard) SanitizeRetrieved(source, content string) GuardResult {
+	result := pg.sanitize(content)
+	if len(result.Flagged) > 0 {
+		log.Warn().
+			Str("source", source).
+			Strs("patterns", result.Flagged).
+			Msg("Prompt injection attempt flagged in retrieved content")
+	}
+
+	result.Text = fmt.Sprintf("<document source=%q>\n%s\n</document>", sanitizeAttr(source), result.Text)
+	return result
+}
+
+// SanitizeDocument is SanitizeRetrieved for documents a caller hands over whole, such
+// as a statement to summarize, keeping up to limit bytes rather than the usual cap
+func (pg *PromptGuard) SanitizeDocument(source, content string, limit int) GuardResult {
+	result := pg.sanitizeUpTo(content, limit)
+	if len(result.Flagged) > 0 {
+		log.Warn().
+			Str("source", source).
+			Strs("patterns", result.Flagged).
+			Msg("Prompt injection attempt flagged in document")
+	}
+
+	result.Text = fmt.Sprintf("<document source=%q>\n%s\n</document>", sanitizeAttr(source), result.Text)
+	return result
+}
+
+// sanitize removes every matching pattern and truncates oversized input
+func (pg *PromptGuard) sanitize(text string) GuardResult {
+	return pg.sanitizeUpTo(text, maxUntrustedLength)
+}
+
+func (pg *PromptGuard) sanitizeUpTo(text string, limit int) GuardResult {
+	if len(text) > limit {
+		text = strings.ToValidUTF8(text[:limit], "")
+	}
+
+	var flagged []string
+	for _, p := range pg.patterns {
+		if p.re.MatchString(text) {
+			flagged = append(flagged, p.name)
+			text = p.re.ReplaceAllString(text, "[removed]")
+		}
+	}
+
+	// Keep the text on one quoted line inside the prompt
+	text = strings.ReplaceAll(text, `"`, `'`)
+
+	return GuardResult{Text: strings.TrimSpace(text), Flagged: flagged}
+}
+
+// ValidateIntentOutput enforces the intent extraction schema on an LLM result. Unknown
+// entity keys are dropped; anything else that does not fit the schema is an error.
+func (pg *PromptGuard) ValidateIntentOutput(result map[string]interface{}) error {
+	intent, ok := result["intent"].(string)
+	if !ok || !pg.allowedIntent[intent] {
+		return fmt.Errorf("LLM returned unsupported intent: %v", result["intent"])
+	}
+
+	confidence, ok := result["confidence"].(float64)
+	if !ok || confidence < 0 || confidence > 1 {
+		return fmt.Errorf("LLM returned invalid confidence: %v", result["confidence"])
+	}
+
+	raw, present := result["entities"]
+	if !present || raw == nil {
+		result["entities"] = map[string]interface{}{}
+		return nil
+	}
+	entities, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("LLM returned non-object entities")
+	}
+
+	for key, value := range entities {
+		if !pg.allowedEntity[key] {
+			log.Warn().Str("entity", key).Msg("Dropping unexpected entity from LLM output")
+			delete(entities, key)
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if len(v) > 256 {
+				return fmt.Errorf("LLM returned oversized entity %s", key)
+			}
+		case float64:
+			if v < 0 {
+				return fmt.Errorf("LLM returned negative entity %s", key)
+			}
+		case bool, nil:
+		default:
+			return fmt.Errorf("LLM returned non-scalar entity %s", key)
+		}
+	}
+
+	return nil
+}
+
+// sanitizeAttr keeps a source label safe to embed in a delimiter attribute
+func sanitizeAttr(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '<' || r == '>' || r == '"' || r == '\n' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Prompt names used by the LLM call paths
+const (
+	PromptBankingSystem    = "banking_system"
+	PromptIntentExtraction = "intent_extraction"
+	PromptUtteranceSplit   = "utterance_split"
+)
+
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+// promptFileRegex matches template files named <name>.v<version>.tmpl
+var promptFileRegex = regexp.MustCompile(`^([a-z0-9_]+)\.v(\d+)\.tmpl$`)
+
+// promptNameRegex restricts prompt names to the same charset as file names
+var promptNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// promptFuncs are the helper functions available inside templates
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// promptEntry holds every version of one prompt and which is active
+type promptEntry struct {
+	versions map[int]*model.PromptTemplate
+	parsed   map[int]*template.Template
+	active   int
+}
+
+// PromptService stores versioned prompt templates and renders them for every LLM call path
+type PromptService struct {
+	mu       sync.RWMutex
+	prompts  map[string]*promptEntry
+	defaults model.PromptVars
+}
+
+// NewPromptService loads the embedded templates plus any found in cfg.Dir.
+// The highest version of each prompt is active until another is activated.
+func NewPromptService(cfg *config.PromptConfig) (*PromptService, error) {
+	ps := &PromptService{
+		prompts: make(map[string]*promptEntry),
+		defaults: model.PromptVars{
+			Persona:      cfg.Persona,
+			TenantName:   cfg.TenantName,
+			Capabilities: SupportedCapabilities(),
+		},
+	}
+
+	if err := ps.loadFS(embeddedPrompts, "prompts", "embedded"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Dir != "" {
+		if _, err := os.Stat(cfg.Dir); err == nil {
+			if err := ps.loadFS(os.DirFS(cfg.Dir), ".", "file"); err != nil {
+				return nil, err
+			}
+		} else {
+			log.Warn().Str("dir", cfg.Dir).Msg("Prompt directory not found, using embedded prompts only")
+		}
+	}
+
+	for name, entry := range ps.prompts {
+		entry.active = latestVersion(entry)
+		log.Info().Str("prompt", name).Int("active_version", entry.active).Msg("Prompt template loaded")
+	}
+
+	return ps, nil
+}
+
+// SupportedCapabilities lists the intents the platform can execute
+func SupportedCapabilities() []string {
+	return []string{
+		string(model.IntentTransferNEFT),
+		string(model.IntentTransferRTGS),
+		string(model.IntentTransferIMPS),
+		string(model.IntentTransferUPI),
+		string(model.IntentCheckBalance),
+		string(model.IntentGetStatement),
+		string(model.IntentSummarizeStatement),
+		string(model.IntentAddBeneficiary),
+		string(model.IntentListBeneficiaries),
+		string(model.IntentRequestMoney),
+		string(model.IntentShareReceipt),
+		string(model.IntentExportTransactions),
+		string(model.IntentSetBudget),
+		string(model.IntentBudgetStatus),
+		string(model.IntentApplyLoan),
+		string(model.IntentCreditScore),
+		string(model.IntentGetInsights),
+		string(model.IntentSetPreference),
+		string(model.IntentWhyRejected),
+		string(model.IntentRetryLast),
+	}
+}
+
+// loadFS loads all <name>.v<version>.tmpl files from a directory of fsys
+func (ps *PromptService) loadFS(fsys fs.FS, dir, source string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read prompt directory: %w", err)
+	}
+
+	for _, e := range entries {
+		matches := promptFileRegex.FindStringSubmatch(e.Name())
+		if e.IsDir() || matches == nil {
+			continue
+		}
+
+		body, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, e.Name())))
+		if err != nil {
+			return fmt.Errorf("failed to read prompt %s: %w", e.Name(), err)
+		}
+
+		version, _ := strconv.Atoi(matches[2])
+		if _, err := ps.addVersion(matches[1], version, string(body), source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addVersion parses and stores a template version; callers must not hold the lock
+func (ps *PromptService) addVersion(name string, version int, body, source string) (*model.PromptTemplate, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	entry, ok := ps.prompts[name]
+	if !ok {
+		entry = &promptEntry{
+			versions: make(map[int]*model.PromptTemplate),
+			parsed:   make(map[int]*template.Template),
+		}
+		ps.prompts[name] = entry
+	}
+
+	if version == 0 {
+		version = latestVersion(entry) + 1
+	}
+
+	pt := &model.PromptTemplate{
+		Name:      name,
+		Version:   version,
+		Body:      body,
+		Source:    source,
+		CreatedAt: time.Now(),
+	}
+	entry.versions[version] = pt
+	entry.parsed[version] = tmpl
+	return pt, nil
+}
+
+// AddVersion stores a new version of a prompt without activating it
+func (ps *PromptService) AddVersion(name, body string) (*model.PromptTemplate, error) {
+	if !promptNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid prompt name: %s", name)
+	}
+
+	pt, err := ps.addVersion(name, 0, body, "api")
+	if err != nil {
+		return nil, err
+	}
+
+	// A brand-new prompt has nothing else to fall back to
+	ps.mu.Lock()
+	if entry := ps.prompts[name]; entry.active == 0 {
+		entry.active = pt.Version
+	}
+	ps.mu.Unlock()
+
+	log.Info().Str("prompt", name).Int("version", pt.Version).Msg("Prompt version added")
+	return pt, nil
+}
+
+// Activate makes a version the one used by Render
+func (ps *PromptService) Activate(name string, version int) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	entry, ok := ps.prompts[name]
+	if !ok {
+		return fmt.Errorf("prompt not found: %s", name)
+	}
+	if _, ok := entry.versions[version]; !ok {
+		return fmt.Errorf("prompt %s has no version %d", name, version)
+	}
+
+	previous := entry.active
+	entry.active = version
+
+	log.Info().Str("prompt", name).Int("from_version", previous).Int("to_version", version).Msg("Prompt version activated")
+	return nil
+}
+
+// Render renders the active version of a prompt
+func (ps *PromptService) Render(name string, vars model.PromptVars) (*model.RenderedPrompt, error) {
+	return ps.RenderVersion(name, 0, vars)
+}
+
+// RenderVersion renders a specific version of a prompt (0 means the active version).
+// Unset variables fall back to the configured defaults.
+func (ps *PromptService) RenderVersion(name string, version int, vars model.PromptVars) (*model.RenderedPrompt, error) {
+	ps.mu.RLock()
+	entry, ok := ps.prompts[name]
+	if !ok {
+		ps.mu.RUnlock()
+		return nil, fmt.Errorf("prompt not found: %s", name)
+	}
+	if version == 0 {
+		version = entry.active
+	}
+	tmpl, ok := entry.parsed[version]
+	ps.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("prompt %s has no version %d", name, version)
+	}
+
+	if vars.Persona == "" {
+		vars.Persona = ps.defaults.Persona
+	}
+	if vars.TenantName == "" {
+		vars.TenantName = ps.defaults.TenantName
+	}
+	if len(vars.Capabilities) == 0 {
+		vars.Capabilities = ps.defaults.Capabilities
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s v%d: %w", name, version, err)
+	}
+
+	return &model.RenderedPrompt{
+		Name:    name,
+		Version: version,
+		Text:    strings.TrimSpace(buf.String()),
+	}, nil
+}
+
+// List returns the versions and active version of every prompt
+func (ps *PromptService) List() []model.PromptInfo {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	infos := make([]model.PromptInfo, 0, len(ps.prompts))
+	for name, entry := range ps.prompts {
+		versions := make([]int, 0, len(entry.versions))
+		for v := range entry.versions {
+			versions = append(versions, v)
+		}
+		sort.Ints(versions)
+		infos = append(infos, model.PromptInfo{Name: name, ActiveVersion: entry.active, Versions: versions})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Get returns one version of a prompt (0 means the active version)
+func (ps *PromptService) Get(name string, version int) (*model.PromptTemplate, error) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	entry, ok := ps.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("prompt not found: %s", name)
+	}
+	if version == 0 {
+		version = entry.active
+	}
+	pt, ok := entry.versions[version]
+	if !ok {
+		return nil, fmt.Errorf("prompt %s has no version %d", name, version)
+	}
+	return pt, nil
+}
+
+// latestVersion returns the highest version number of an entry
+func latestVersion(entry *promptEntry) int {
+	latest := 0
+	for v := range entry.versions {
+		if v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
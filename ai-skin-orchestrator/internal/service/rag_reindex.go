@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// ProviderHash is the local feature-hashing embedder
+const ProviderHash = "hash"
+
+// Re-index errors
+var (
+	ErrReindexActive    = errors.New("a re-index is already under way")
+	ErrReindexNotFound  = errors.New("no re-index job")
+	ErrReindexSameModel = errors.New("documents are already embedded with this model")
+	ErrReindexState     = errors.New("re-index job is not in a state that allows this")
+)
+
+// NewEmbedder returns the embedder for a provider: the local hashing embedder with the
+// given dimensions, or an Ollama embedding model
+func NewEmbedder(provider, embeddingModel string, dimensions int, ollama *OllamaService) (Embedder, error) {
+	switch provider {
+	case ProviderHash:
+		return NewHashEmbedder(dimensions), nil
+	case ProviderOllama:
+		if embeddingModel == "" {
+			return nil, fmt.Errorf("ollama embeddings need a model")
+		}
+		return NewOllamaEmbedder(ollama, embeddingModel), nil
+	}
+	return nil, fmt.Errorf("unknown embedding provider %q, want %s or %s", provider, ProviderHash, ProviderOllama)
+}
+
+// RAGReindexer migrates the stored documents to another embedding model, e.g. from the
+// hashing embedder to nomic-embed-text. Documents are re-embedded in batches alongside
+// their current vectors, so search keeps working on both models while the job runs;
+// once every document has a vector from the new model it replaces the old one.
+//
+// One job runs at a time. It pauses when a batch still fails after
+// RAG_EMBED_MAX_ATTEMPTS and can be resumed where it stopped, since only documents
+// without a vector from the new model are picked up.
+type RAGReindexer struct {
+	cfg    *config.RAGConfig
+	rag    *RAGService
+	ollama *OllamaService
+
+	mu   sync.Mutex
+	job  *model.ReindexJob
+	next Embedder
+	run  int // Incremented on every start, pause and resume; a batch loop stops when it changes
+}
+
+// NewRAGReindexer creates the re-indexer for a RAG service
+func NewRAGReindexer(cfg *config.RAGConfig, rag *RAGService, ollama *OllamaService) *RAGReindexer {
+	return &RAGReindexer{
+		cfg:    cfg,
+		rag:    rag,
+		ollama: ollama,
+	}
+}
+
+// Start begins re-embedding every document with the requested model
+func (rr *RAGReindexer) Start(req *model.ReindexRequest) (*model.ReindexJob, error) {
+	embeddingModel := req.Model
+	if embeddingModel == "" {
+		embeddingModel = rr.cfg.EmbeddingModel
+	}
+	dimensions := req.Dimensions
+	if dimensions <= 0 {
+		dimensions = rr.cfg.HashDimensions
+	}
+	next, err := NewEmbedder(req.Provider, embeddingModel, dimensions, rr.ollama)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = rr.cfg.ReindexBatchSize
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.job != nil && (rr.job.Status == model.ReindexRunning || rr.job.Status == model.ReindexPaused) {
+		return nil, ErrReindexActive
+	}
+	current, err := rr.rag.beginReindex(next)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rr.next = next
+	rr.job = &model.ReindexJob{
+		JobID:     ids.Ref("REIDX_"),
+		FromModel: current,
+		ToModel:   next.Model(),
+		Status:    model.ReindexRunning,
+		BatchSize: batchSize,
+		Total:     rr.rag.reindexRemaining(next.Model()),
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	rr.run++
+	go rr.loop(rr.run, next, batchSize)
+
+	log.Info().Str("job_id", rr.job.JobID).Str("from", current).Str("to", next.Model()).Int("documents", rr.job.Total).Msg("RAG re-index started")
+	return rr.snapshot(), nil
+}
+
+// Pause stops the job after the batch in flight
+func (rr *RAGReindexer) Pause() (*model.ReindexJob, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.job == nil {
+		return nil, ErrReindexNotFound
+	}
+	if rr.job.Status != model.ReindexRunning {
+		return nil, fmt.Errorf("%w: job is %s", ErrReindexState, rr.job.Status)
+	}
+	rr.run++
+	rr.job.Status = model.ReindexPaused
+	rr.job.UpdatedAt = time.Now()
+	return rr.snapshot(), nil
+}
+
+// Resume continues a paused job with the documents not yet re-embedded
+func (rr *RAGReindexer) Resume() (*model.ReindexJob, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.job == nil {
+		return nil, ErrReindexNotFound
+	}
+	if rr.job.Status != model.ReindexPaused {
+		return nil, fmt.Errorf("%w: job is %s", ErrReindexState, rr.job.Status)
+	}
+	rr.run++
+	rr.job.Status = model.ReindexRunning
+	rr.job.Error = ""
+	rr.job.UpdatedAt = time.Now()
+	go rr.loop(rr.run, rr.next, rr.job.BatchSize)
+
+	log.Info().Str("job_id", rr.job.JobID).Int("done", rr.job.Done).Int("total", rr.job.Total).Msg("RAG re-index resumed")
+	return rr.snapshot(), nil
+}
+
+// Cancel stops the job and drops the vectors it made; search stays on the current model
+func (rr *RAGReindexer) Cancel() (*model.ReindexJob, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.job == nil {
+		return nil, ErrReindexNotFound
+	}
+	if rr.job.Status != model.ReindexRunning && rr.job.Status != model.ReindexPaused {
+		return nil, fmt.Errorf("%w: job is %s", ErrReindexState, rr.job.Status)
+	}
+	rr.run++
+	rr.rag.abandonReindex()
+	rr.finish(model.ReindexCancelled)
+	log.Info().Str("job_id", rr.job.JobID).Msg("RAG re-index cancelled")
+	return rr.snapshot(), nil
+}
+
+// Job returns the current or last re-index job
+func (rr *RAGReindexer) Job() (*model.ReindexJob, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.job == nil {
+		return nil, ErrReindexNotFound
+	}
+	return rr.snapshot(), nil
+}
+
+// loop re-embeds batches until no document is left, then switches search over to the
+// new model
+func (rr *RAGReindexer) loop(run int, next Embedder, batchSize int) {
+	for {
+		select {
+		case <-rr.rag.stop:
+			rr.pause(run, "shutdown before the re-index completed")
+			return
+		default:
+		}
+
+		batch := rr.rag.reindexBatch(next.Model(), batchSize)
+		if len(batch) == 0 {
+			rr.mu.Lock()
+			if rr.run == run && rr.rag.completeReindex(next) {
+				rr.finish(model.ReindexCompleted)
+				log.Info().Str("job_id", rr.job.JobID).Str("embedding_model", next.Model()).Int("documents", rr.job.Done).Msg("RAG re-index completed")
+				rr.mu.Unlock()
+				return
+			}
+			stopped := rr.run != run
+			rr.mu.Unlock()
+			if stopped {
+				return
+			}
+			continue // Documents stored since the last batch
+		}
+
+		vectors, err := rr.embedBatch(next, batch)
+		if err != nil {
+			rr.pause(run, err.Error())
+			return
+		}
+
+		rr.mu.Lock()
+		if rr.run != run {
+			rr.mu.Unlock()
+			return
+		}
+		embedded := rr.rag.setNextEmbeddings(batch, vectors, next.Model())
+		rr.job.Done += embedded
+		rr.job.Batches++
+		rr.job.Total = rr.job.Done + rr.rag.reindexRemaining(next.Model())
+		rr.job.UpdatedAt = time.Now()
+		rr.mu.Unlock()
+	}
+}
+
+// embedBatch embeds a batch with the new model, retrying with backoff
+func (rr *RAGReindexer) embedBatch(next Embedder, batch []reindexDocument) ([][]float32, error) {
+	texts := make([]string, len(batch))
+	for i, doc := range batch {
+		texts[i] = doc.content
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= rr.rag.maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-rr.rag.stop:
+				return nil, fmt.Errorf("shutdown before the re-index completed")
+			case <-time.After(time.Duration(attempt-1) * time.Second):
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), rr.rag.embedTimeout)
+		vectors, err := next.Embed(ctx, texts)
+		cancel()
+		if err == nil {
+			return vectors, nil
+		}
+		lastErr = err
+	}
+	log.Warn().Err(lastErr).Str("embedding_model", next.Model()).Int("documents", len(batch)).Msg("RAG re-index batch failed, pausing")
+	return nil, fmt.Errorf("batch of %d documents failed after %d attempts: %w", len(batch), rr.rag.maxAttempts, lastErr)
+}
+
+// pause stops a run that could not go on, unless an operator already stopped it
+func (rr *RAGReindexer) pause(run int, reason string) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.run != run {
+		return
+	}
+	rr.run++
+	rr.job.Status = model.ReindexPaused
+	rr.job.Error = reason
+	rr.job.UpdatedAt = time.Now()
+}
+
+// finish closes the job; callers hold rr.mu
+func (rr *RAGReindexer) finish(status model.ReindexStatus) {
+	now := time.Now()
+	rr.job.Status = status
+	rr.job.UpdatedAt = now
+	rr.job.FinishedAt = &now
+	rr.next = nil
+}
+
+// snapshot copies the job; callers hold rr.mu
+func (rr *RAGReindexer) snapshot() *model.ReindexJob {
+	job := *rr.job
+	return &job
+}
+
+// reindexDocument is a document waiting for a vector from the new model
+type reindexDocument struct {
+	id      string
+	content string
+}
+
+// beginReindex starts scoring searches with the new model too and returns the current
+// model
+func (rs *RAGService) beginReindex(next Embedder) (string, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if next.Model() == rs.embedder.Model() {
+		return "", fmt.Errorf("%w: %s", ErrReindexSameModel, next.Model())
+	}
+	rs.next = next
+	for _, doc := range rs.documents {
+		if doc.NextEmbeddingModel != next.Model() {
+			doc.NextEmbedding, doc.NextEmbeddingModel = nil, ""
+		}
+	}
+	return rs.embedder.Model(), nil
+}
+
+// reindexBatch returns up to n documents without a vector from the model, oldest first
+func (rs *RAGService) reindexBatch(embeddingModel string, n int) []reindexDocument {
+	rs.mu.RLock()
+	var waiting []*model.Document
+	for _, doc := range rs.documents {
+		if doc.NextEmbeddingModel != embeddingModel {
+			waiting = append(waiting, doc)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		if !waiting[i].CreatedAt.Equal(waiting[j].CreatedAt) {
+			return waiting[i].CreatedAt.Before(waiting[j].CreatedAt)
+		}
+		return waiting[i].ID < waiting[j].ID
+	})
+	if len(waiting) > n {
+		waiting = waiting[:n]
+	}
+	batch := make([]reindexDocument, len(waiting))
+	for i, doc := range waiting {
+		batch[i] = reindexDocument{id: doc.ID, content: doc.Content}
+	}
+	rs.mu.RUnlock()
+	return batch
+}
+
+// reindexRemaining counts the documents without a vector from the model
+func (rs *RAGService) reindexRemaining(embeddingModel string) int {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	remaining := 0
+	for _, doc := range rs.documents {
+		if doc.NextEmbeddingModel != embeddingModel {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// setNextEmbeddings keeps the new model's vectors beside the current ones and returns
+// how many documents got one; documents deleted in the meantime are skipped
+func (rs *RAGService) setNextEmbeddings(batch []reindexDocument, vectors [][]float32, embeddingModel string) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	embedded := 0
+	for i, item := range batch {
+		if doc, ok := rs.documents[item.id]; ok && i < len(vectors) {
+			doc.NextEmbedding = vectors[i]
+			doc.NextEmbeddingModel = embeddingModel
+			embedded++
+		}
+	}
+	return embedded
+}
+
+// completeReindex makes the new model current once every document has a vector from
+// it, and reports whether it did
+func (rs *RAGService) completeReindex(next Embedder) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for _, doc := range rs.documents {
+		if doc.NextEmbeddingModel != next.Model() {
+			return false
+		}
+	}
+
+	now := time.Now()
+	for _, doc := range rs.documents {
+		doc.Embedding, doc.EmbeddingModel = doc.NextEmbedding, doc.NextEmbeddingModel
+		doc.NextEmbedding, doc.NextEmbeddingModel = nil, ""
+		if doc.Status != model.EmbeddingEmbedded {
+			doc.Status = model.EmbeddingEmbedded
+			doc.Error = ""
+			doc.EmbeddedAt = &now
+		}
+	}
+	rs.embedder = next
+	rs.next = nil
+	return true
+}
+
+// abandonReindex drops the new model's vectors
+func (rs *RAGService) abandonReindex() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for _, doc := range rs.documents {
+		doc.NextEmbedding, doc.NextEmbeddingModel = nil, ""
+	}
+	rs.next = nil
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// RAGService stores conversations, transactions and knowledge documents for retrieval.
+// Documents are embedded by a pool of background workers so storing never waits on
+// the embedding model; a document becomes searchable once it has been embedded.
+type RAGService struct {
+	embedder     Embedder // Guarded by mu: a re-index swaps it when it completes
+	next         Embedder // The model a re-index is migrating to, if one is under way
+	documents    map[string]*model.Document
+	mu           sync.RWMutex
+	queue        chan string // Document IDs waiting to be embedded
+	workers      int
+	maxAttempts  int
+	embedTimeout time.Duration
+	wg           sync.WaitGroup
+	stop         chan struct{}
+	stopOnce     sync.Once
+
+	// Pipeline metrics
+	embedded     atomic.Int64
+	failed       atomic.Int64
+	retried      atomic.Int64
+	dropped      atomic.Int64
+	latencyNanos atomic.Int64 // Sum over embedded documents
+}
+
+// NewRAGService creates a RAG service and starts its embedding workers
+func NewRAGService(cfg *config.RAGConfig, embedder Embedder) *RAGService {
+	rs := &RAGService{
+		embedder:     embedder,
+		documents:    make(map[string]*model.Document),
+		queue:        make(chan string, cfg.QueueSize),
+		workers:      cfg.Workers,
+		maxAttempts:  cfg.MaxAttempts,
+		embedTimeout: time.Duration(cfg.EmbedTimeout) * time.Second,
+		stop:         make(chan struct{}),
+	}
+	if rs.workers <= 0 {
+		rs.workers = 1
+	}
+	if rs.maxAttempts <= 0 {
+		rs.maxAttempts = 1
+	}
+
+	for i := 0; i < rs.workers; i++ {
+		rs.wg.Add(1)
+		go rs.worker()
+	}
+
+	log.Info().
+		Str("embedding_model", embedder.Model()).
+		Int("workers", rs.workers).
+		Int("queue_size", cfg.QueueSize).
+		Msg("RAG embedding pipeline started")
+
+	return rs
+}
+
+// StoreConversation stores one exchange of a conversation
+func (rs *RAGService) StoreConversation(userID, sessionID, userMessage, answer string) (*model.Document, error) {
+	content := fmt.Sprintf("User: %s\nAssistant: %s", userMessage, answer)
+	return rs.Store(model.CollectionConversation, userID, content, map[string]interface{}{
+		"session_id": sessionID,
+	})
+}
+
+// StoreTransaction stores a completed banking operation
+func (rs *RAGService) StoreTransaction(userID string, intent model.Intent, status string, result map[string]interface{}) (*model.Document, error) {
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%s %s", intent.Type, status))
+
+	keys := make([]string, 0, len(intent.Entities))
+	for k := range intent.Entities {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", k, intent.Entities[k]))
+	}
+
+	metadata := map[string]interface{}{
+		"intent": string(intent.Type),
+		"status": status,
+	}
+	if txnID, ok := result["transaction_id"]; ok {
+		metadata["transaction_id"] = txnID
+	}
+
+	return rs.Store(model.CollectionTransaction, userID, strings.Join(parts, ", "), metadata)
+}
+
+// Store adds a document and queues it for embedding. It never blocks: when the queue
+// is full the document is kept but marked FAILED and counted as dropped.
+func (rs *RAGService) Store(collection, userID, content string, metadata map[string]interface{}) (*model.Document, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("document content is empty")
+	}
+	if collection != model.CollectionKnowledge && userID == "" {
+		return nil, fmt.Errorf("user_id is required for %s documents", collection)
+	}
+
+	doc := &model.Document{
+		ID:         ids.New(ids.Document),
+		UserID:     userID,
+		Collection: collection,
+		Content:    content,
+		Metadata:   metadata,
+		Status:     model.EmbeddingPending,
+		CreatedAt:  time.Now(),
+	}
+
+	rs.mu.Lock()
+	rs.documents[doc.ID] = doc
+	rs.mu.Unlock()
+
+	select {
+	case rs.queue <- doc.ID:
+	default:
+		rs.dropped.Add(1)
+		rs.markFailed(doc.ID, "embedding queue full")
+		log.Warn().
+			Str("document_id", doc.ID).
+			Str("collection", collection).
+			Msg("Embedding queue full, document stored without embedding")
+	}
+
+	return doc, nil
+}
+
+// Purge deletes a collection's documents stored before cutoff and returns their IDs,
+// with the number kept because their user is on legal hold. With dryRun nothing is
+// deleted. A purged document still waiting to be embedded is skipped by the workers.
+func (rs *RAGService) Purge(collection string, cutoff time.Time, held func(userID string) bool, dryRun bool) ([]string, int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var purged []string
+	kept := 0
+	for id, doc := range rs.documents {
+		if doc.Collection != collection || !doc.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if held(doc.UserID) {
+			kept++
+			continue
+		}
+		purged = append(purged, id)
+		if !dryRun {
+			delete(rs.documents, id)
+		}
+	}
+	return purged, kept
+}
+
+// UserDocuments returns copies of a user's documents, oldest first, without their
+// embeddings
+func (rs *RAGService) UserDocuments(userID string) []model.Document {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	docs := []model.Document{}
+	for _, doc := range rs.documents {
+		if doc.UserID != userID {
+			continue
+		}
+		copied := *doc
+		copied.Embedding = nil
+		copied.NextEmbedding = nil
+		docs = append(docs, copied)
+	}
+	sort.Slice(docs, func(a, b int) bool { return docs[a].CreatedAt.Before(docs[b].CreatedAt) })
+	return docs
+}
+
+// DeleteUser deletes every document of a user and returns how many were deleted
+func (rs *RAGService) DeleteUser(userID string) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	deleted := 0
+	for id, doc := range rs.documents {
+		if doc.UserID == userID {
+			delete(rs.documents, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// GetDocument returns a copy of a stored document
+func (rs *RAGService) GetDocument(id string) (*model.Document, bool) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	doc, ok := rs.documents[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *doc
+	return &copied, true
+}
+
+// Search returns the embedded documents most similar to the query. User collections
+// only search that user's documents; the knowledge collection is shared.
+//
+// While a re-index is under way the query is embedded with both models. A document
+// already re-embedded is scored by both and the scores averaged; the rest are scored
+// by the current model alone, so search keeps working throughout the migration.
+func (rs *RAGService) Search(ctx context.Context, req *model.SearchRequest) ([]model.SearchResult, error) {
+	topK := req.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+
+	embedder, next := rs.embedders()
+	vectors, err := embedder.Embed(ctx, []string{req.Query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	query := vectors[0]
+	embeddingModel := embedder.Model()
+
+	var nextQuery []float32
+	nextModel := ""
+	if next != nil {
+		if vectors, err := next.Embed(ctx, []string{req.Query}); err == nil {
+			nextQuery, nextModel = vectors[0], next.Model()
+		} else {
+			log.Warn().Err(err).Str("embedding_model", next.Model()).Msg("Failed to embed query with the re-index model, scoring with the current model only")
+		}
+	}
+
+	rs.mu.RLock()
+	var results []model.SearchResult
+	for _, doc := range rs.documents {
+		if req.Collection != "" && doc.Collection != req.Collection {
+			continue
+		}
+		if doc.Collection != model.CollectionKnowledge && doc.UserID != req.UserID {
+			continue
+		}
+		var score float64
+		scores := 0
+		if doc.Status == model.EmbeddingEmbedded && doc.EmbeddingModel == embeddingModel {
+			score += cosine(query, doc.Embedding)
+			scores++
+		}
+		if nextModel != "" && doc.NextEmbeddingModel == nextModel {
+			score += cosine(nextQuery, doc.NextEmbedding)
+			scores++
+		}
+		if scores == 0 {
+			continue
+		}
+		copied := *doc
+		results = append(results, model.SearchResult{Document: &copied, Score: score / float64(scores)})
+	}
+	rs.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// Stats reports the embedding backlog and failure counters
+func (rs *RAGService) Stats() model.EmbeddingStats {
+	embedder, next := rs.embedders()
+	stats := model.EmbeddingStats{
+		Model:         embedder.Model(),
+		Workers:       rs.workers,
+		QueueDepth:    len(rs.queue),
+		QueueCapacity: cap(rs.queue),
+		Embedded:      rs.embedded.Load(),
+		Failed:        rs.failed.Load(),
+		Retried:       rs.retried.Load(),
+		Dropped:       rs.dropped.Load(),
+	}
+	if stats.Embedded > 0 {
+		stats.AvgLatencyMs = float64(rs.latencyNanos.Load()) / float64(stats.Embedded) / float64(time.Millisecond)
+	}
+	if next != nil {
+		stats.NextModel = next.Model()
+	}
+
+	rs.mu.RLock()
+	stats.Documents = len(rs.documents)
+	var oldest time.Time
+	for _, doc := range rs.documents {
+		if doc.Status != model.EmbeddingPending {
+			continue
+		}
+		stats.Pending++
+		if oldest.IsZero() || doc.CreatedAt.Before(oldest) {
+			oldest = doc.CreatedAt
+		}
+	}
+	rs.mu.RUnlock()
+
+	if !oldest.IsZero() {
+		stats.OldestPendingS = time.Since(oldest).Seconds()
+	}
+	return stats
+}
+
+// embedders returns the current embedding model and the one a re-index is migrating
+// to, if any
+func (rs *RAGService) embedders() (Embedder, Embedder) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.embedder, rs.next
+}
+
+// Stop stops accepting work and waits for in-flight embeddings to finish
+func (rs *RAGService) Stop(ctx context.Context) {
+	rs.stopOnce.Do(func() { close(rs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		rs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("RAG embedding workers stopped")
+	case <-ctx.Done():
+		log.Warn().Int("queue_depth", len(rs.queue)).Msg("RAG embedding workers did not stop in time")
+	}
+}
+
+// worker embeds queued documents until the service stops
+func (rs *RAGService) worker() {
+	defer rs.wg.Done()
+
+	for {
+		select {
+		case <-rs.stop:
+			return
+		case id := <-rs.queue:
+			rs.embedDocument(id)
+		}
+	}
+}
+
+// embedDocument embeds one document, retrying with backoff on failure
+func (rs *RAGService) embedDocument(id string) {
+	rs.mu.RLock()
+	doc, ok := rs.documents[id]
+	var content string
+	if ok {
+		content = doc.Content
+		// A re-index that completed while the document waited has embedded it already
+		ok = doc.Status != model.EmbeddingEmbedded || doc.EmbeddingModel != rs.embedder.Model()
+	}
+	embedder := rs.embedder
+	rs.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= rs.maxAttempts; attempt++ {
+		if attempt > 1 {
+			rs.retried.Add(1)
+			select {
+			case <-rs.stop:
+				rs.markFailed(id, "shutdown before embedding completed")
+				return
+			case <-time.After(time.Duration(attempt-1) * time.Second):
+			}
+		}
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), rs.embedTimeout)
+		vectors, err := embedder.Embed(ctx, []string{content})
+		cancel()
+		if err == nil {
+			rs.latencyNanos.Add(int64(time.Since(start)))
+			rs.markEmbedded(id, vectors[0], embedder.Model(), attempt)
+			return
+		}
+
+		lastErr = err
+		rs.mu.Lock()
+		doc.Attempts = attempt
+		rs.mu.Unlock()
+	}
+
+	rs.failed.Add(1)
+	rs.markFailed(id, lastErr.Error())
+	log.Warn().Err(lastErr).Str("document_id", id).Int("attempts", rs.maxAttempts).Msg("Failed to embed document")
+}
+
+// markEmbedded makes a document searchable. A vector from a model that a re-index
+// has since replaced is not kept; the document is queued again for the new model.
+func (rs *RAGService) markEmbedded(id string, vector []float32, embeddingModel string, attempts int) {
+	now := time.Now()
+
+	rs.mu.Lock()
+	if embeddingModel != rs.embedder.Model() {
+		rs.mu.Unlock()
+		select {
+		case rs.queue <- id:
+		default:
+			rs.dropped.Add(1)
+			rs.markFailed(id, "embedding queue full")
+		}
+		return
+	}
+	if doc, ok := rs.documents[id]; ok {
+		doc.Embedding = vector
+		doc.EmbeddingModel = embeddingModel
+		doc.Status = model.EmbeddingEmbedded
+		doc.Attempts = attempts
+		doc.Error = ""
+		doc.EmbeddedAt = &now
+	}
+	rs.mu.Unlock()
+
+	rs.embedded.Add(1)
+}
+
+// markFailed records why a document could not be embedded
+func (rs *RAGService) markFailed(id, reason string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if doc, ok := rs.documents[id]; ok {
+		doc.Status = model.EmbeddingFailed
+		doc.Error = reason
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// RAG corpus the retrieval benchmarks search: a knowledge base shared by every user and
+// the conversation history of a few hundred users
+const (
+	ragKnowledgeDocs  = 500
+	ragUsers          = 200
+	ragDocsPerUser    = 20
+	ragSearchBudget   = 2 * time.Millisecond // Search scans every stored vector
+	ragEmbedTimeLimit = time.Minute
+)
+
+// seededRAG returns a RAG service on the local hashing embedder, holding the benchmark
+// corpus with every document embedded
+func seededRAG(b *testing.B) *RAGService {
+	cfg := &config.RAGConfig{HashDimensions: 512, Workers: 4, QueueSize: ragKnowledgeDocs + ragUsers*ragDocsPerUser, MaxAttempts: 1, EmbedTimeout: 5}
+	rag := NewRAGService(cfg, NewHashEmbedder(cfg.HashDimensions))
+
+	topics := []string{"IMPS daily limit", "NEFT settlement hours", "RTGS minimum amount", "UPI PIN reset", "card blocking", "cheque book request", "fixed deposit premature withdrawal", "home loan prepayment"}
+	for i := 0; i < ragKnowledgeDocs; i++ {
+		topic := topics[i%len(topics)]
+		content := fmt.Sprintf("Policy %d on %s: customers may ask about %s at any branch or in the app; revision %d applies.", i, topic, topic, i/len(topics))
+		if _, err := rag.Store(model.CollectionKnowledge, "", content, nil); err != nil {
+			b.Fatalf("failed to seed RAG: %v", err)
+		}
+	}
+	for u := 0; u < ragUsers; u++ {
+		userID := fmt.Sprintf("U%d", 10001+u)
+		for i := 0; i < ragDocsPerUser; i++ {
+			question := fmt.Sprintf("Send %d rupees by NEFT to payee %d", 1000*(i+1), i%5)
+			answer := fmt.Sprintf("Transfer of %d rupees to payee %d submitted, reference REF%d%d", 1000*(i+1), i%5, u, i)
+			if _, err := rag.StoreConversation(userID, fmt.Sprintf("sess_%d", i/4), question, answer); err != nil {
+				b.Fatalf("failed to seed RAG: %v", err)
+			}
+		}
+	}
+
+	deadline := time.Now().Add(ragEmbedTimeLimit)
+	for rag.Stats().Pending > 0 {
+		if time.Now().After(deadline) {
+			b.Fatalf("RAG corpus not embedded after %s", ragEmbedTimeLimit)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return rag
+}
+
+func BenchmarkRAGSearch(b *testing.B) {
+	ctx := context.Background()
+	rag := seededRAG(b)
+
+	cases := []struct {
+		name  string
+		query *model.SearchRequest
+	}{
+		{"knowledge", &model.SearchRequest{Collection: model.CollectionKnowledge, Query: "What is the daily limit for IMPS transfers?", TopK: 3}},
+		{"user-history", &model.SearchRequest{UserID: "U10001", Collection: model.CollectionConversation, Query: "Did my NEFT to the landlord go through?", TopK: 5}},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := rag.Search(ctx, tc.query); err != nil {
+					b.Fatal(err)
+				}
+			}
+			withinBudget(b, ragSearchBudget)
+		})
+	}
+}
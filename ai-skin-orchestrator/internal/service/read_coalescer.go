@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// readCoalescer runs identical read-only tasks that are in flight at the same time
+// once and shares the result, so ten quick "check balance" taps make one MCP task.
+// The shared task runs until its last caller has gone.
+type readCoalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+
+	executed atomic.Int64
+	shared   atomic.Int64
+}
+
+// coalescedCall is one task and the callers waiting for it
+type coalescedCall struct {
+	done    chan struct{}
+	resp    *model.AgentResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newReadCoalescer() *readCoalescer {
+	return &readCoalescer{calls: make(map[string]*coalescedCall)}
+}
+
+// coalesceKey identifies a read by its user, channel, intent and parameters. Entities
+// are compared as JSON, which orders map keys.
+func coalesceKey(req *model.UserRequest, intent model.Intent) (string, bool) {
+	entities, err := json.Marshal(intent.Entities)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s|%s|%s|%t|%s", req.UserID, req.Channel, intent.Type, req.Sandbox, entities), true
+}
+
+// do returns the result of fn for key, joining a call already in flight for the same
+// key. A caller that gives up leaves the call to the others; the last one to leave
+// cancels it and waits for it to stop.
+func (rc *readCoalescer) do(ctx context.Context, key string, fn func(ctx context.Context) (*model.AgentResponse, error)) (*model.AgentResponse, error) {
+	rc.mu.Lock()
+	call, ok := rc.calls[key]
+	if ok {
+		call.waiters++
+		rc.shared.Add(1)
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		rc.calls[key] = call
+		rc.executed.Add(1)
+		go rc.run(callCtx, key, call, fn)
+	}
+	rc.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result()
+	case <-ctx.Done():
+	}
+
+	rc.mu.Lock()
+	call.waiters--
+	last := call.waiters == 0
+	if last && rc.calls[key] == call {
+		// Callers arriving from now on start afresh rather than join a cancelled call
+		delete(rc.calls, key)
+	}
+	rc.mu.Unlock()
+	if !last {
+		return nil, cancelledAt(ctx, model.CancelStageWait, ctx.Err())
+	}
+
+	call.cancel()
+	<-call.done
+	return call.result()
+}
+
+func (rc *readCoalescer) run(ctx context.Context, key string, call *coalescedCall, fn func(ctx context.Context) (*model.AgentResponse, error)) {
+	defer call.cancel()
+	call.resp, call.err = fn(ctx)
+
+	rc.mu.Lock()
+	if rc.calls[key] == call {
+		delete(rc.calls, key)
+	}
+	rc.mu.Unlock()
+	close(call.done)
+}
+
+// result gives each caller its own copy, since responses are decorated per request
+func (call *coalescedCall) result() (*model.AgentResponse, error) {
+	if call.err != nil {
+		return nil, call.err
+	}
+	resp := *call.resp
+	resp.Result = copyResultMap(call.resp.Result)
+	return &resp, nil
+}
+
+// stats counts the reads executed and the callers that shared one in flight
+func (rc *readCoalescer) stats() model.CoalescingStats {
+	return model.CoalescingStats{
+		Executed: rc.executed.Load(),
+		Shared:   rc.shared.Load(),
+	}
+}
+
+// copyResultMap copies a decoded JSON object with its nested objects and arrays
+func copyResultMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = copyResultValue(v)
+	}
+	return out
+}
+
+func copyResultValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyResultMap(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = copyResultValue(e)
+		}
+		return out
+	}
+	return v
+}
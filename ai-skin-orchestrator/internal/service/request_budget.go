@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidBudget is returned for a request budget that cannot be kept to
+var ErrInvalidBudget = errors.New("invalid request budget")
+
+// budgetMinWait is the least time a task is waited for once the budget is spent, so
+// the user still gets its task ID to check back with
+const budgetMinWait = 250 * time.Millisecond
+
+// budgetKey carries the budget of the request a context belongs to
+type budgetKey struct{}
+
+// BudgetPolicy applies request budgets: the channel's, tightened by the request's own.
+// Stages the budget rules out are skipped and the rule-based path taken instead, so an
+// IVR caller is never kept waiting on a slow LLM.
+type BudgetPolicy struct {
+	cfg *config.BudgetConfig
+}
+
+// NewBudgetPolicy creates a budget policy
+func NewBudgetPolicy(cfg *config.BudgetConfig) *BudgetPolicy {
+	return &BudgetPolicy{cfg: cfg}
+}
+
+// Begin starts the clock on a request's budget and returns a context carrying it. A
+// request that asks for no budget on a channel that has none runs unbounded, with ctx
+// returned as it is.
+func (bp *BudgetPolicy) Begin(ctx context.Context, channel string, requested *model.RequestBudget) (context.Context, error) {
+	if requested != nil && requested.MaxLatencyMs < 0 {
+		return ctx, fmt.Errorf("%w: max_latency_ms must not be negative", ErrInvalidBudget)
+	}
+	maxLatencyMs := bp.cfg.ChannelLatencyMs[channel]
+	if requested == nil && maxLatencyMs == 0 {
+		return ctx, nil
+	}
+
+	budget := &requestBudget{cfg: bp.cfg, allowLLM: true, allowML: true, start: time.Now()}
+	if requested != nil {
+		if requested.MaxLatencyMs > 0 && (maxLatencyMs == 0 || requested.MaxLatencyMs < maxLatencyMs) {
+			maxLatencyMs = requested.MaxLatencyMs
+		}
+		if requested.AllowLLM != nil {
+			budget.allowLLM = *requested.AllowLLM
+		}
+		if requested.AllowML != nil {
+			budget.allowML = *requested.AllowML
+		}
+	}
+	budget.maxLatency = time.Duration(maxLatencyMs) * time.Millisecond
+	return context.WithValue(ctx, budgetKey{}, budget), nil
+}
+
+// BudgetReport returns the budget the request on ctx ran under and what was degraded
+// to keep to it, or nil when it ran without one
+func BudgetReport(ctx context.Context) *model.BudgetReport {
+	budget := budgetFrom(ctx)
+	if budget == nil {
+		return nil
+	}
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	elapsed := time.Since(budget.start)
+	return &model.BudgetReport{
+		MaxLatencyMs: int(budget.maxLatency / time.Millisecond),
+		AllowLLM:     budget.allowLLM,
+		AllowML:      budget.allowML,
+		ElapsedMs:    elapsed.Milliseconds(),
+		Exceeded:     budget.maxLatency > 0 && elapsed > budget.maxLatency,
+		Degradations: append([]string(nil), budget.degradations...),
+	}
+}
+
+// BudgetAllowsLLM reports whether the budget of the request on ctx allows the LLM at
+// all, whatever time is left
+func BudgetAllowsLLM(ctx context.Context) bool {
+	budget := budgetFrom(ctx)
+	return budget == nil || budget.allowLLM
+}
+
+// requestBudget is one request's budget as it moves through the pipeline. Its methods
+// may be called on nil, for a request without a budget, and then allow everything.
+type requestBudget struct {
+	cfg        *config.BudgetConfig
+	maxLatency time.Duration // 0 for no limit
+	allowLLM   bool
+	allowML    bool
+	start      time.Time
+
+	mu           sync.Mutex
+	degradations []string
+}
+
+// budgetFrom returns the budget of the request ctx belongs to, or nil
+func budgetFrom(ctx context.Context) *requestBudget {
+	budget, _ := ctx.Value(budgetKey{}).(*requestBudget)
+	return budget
+}
+
+// remaining returns the time left, and false when the budget has no latency limit
+func (b *requestBudget) remaining() (time.Duration, bool) {
+	if b == nil || b.maxLatency == 0 {
+		return 0, false
+	}
+	return b.maxLatency - time.Since(b.start), true
+}
+
+// below reports whether less than minMs of the budget is left
+func (b *requestBudget) below(minMs int) bool {
+	left, limited := b.remaining()
+	return limited && left < time.Duration(minMs)*time.Millisecond
+}
+
+// useLLM reports whether a stage may call the LLM, recording the degradation when not.
+// Enough must be left for the call and the agents after it.
+func (b *requestBudget) useLLM(stage string) bool {
+	if b == nil {
+		return true
+	}
+	if !b.allowLLM {
+		b.degrade(stage, model.BudgetReasonNotAllowed)
+		return false
+	}
+	if b.below(b.cfg.LLMMinMs) || b.below(b.cfg.ReserveMs+1) {
+		b.degrade(stage, model.BudgetReasonLatency)
+		return false
+	}
+	return true
+}
+
+// useRAG reports whether a stage may retrieve by embedding, recording the degradation
+// when not
+func (b *requestBudget) useRAG(stage string) bool {
+	if b == nil {
+		return true
+	}
+	if b.below(b.cfg.RAGMinMs) {
+		b.degrade(stage, model.BudgetReasonLatency)
+		return false
+	}
+	return true
+}
+
+// useML reports whether agents may score with ML models, recording the degradation
+// when not
+func (b *requestBudget) useML() bool {
+	if b == nil {
+		return true
+	}
+	if !b.allowML {
+		b.degrade(model.BudgetStageMLScoring, model.BudgetReasonNotAllowed)
+		return false
+	}
+	if b.below(b.cfg.MLMinMs) {
+		b.degrade(model.BudgetStageMLScoring, model.BudgetReasonLatency)
+		return false
+	}
+	return true
+}
+
+// llmContext bounds an LLM call the budget allowed, keeping the reserve back for the
+// agents. A call that runs out falls back to rules like any failed call.
+func (b *requestBudget) llmContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	left, limited := b.remaining()
+	if !limited {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, left-time.Duration(b.cfg.ReserveMs)*time.Millisecond)
+}
+
+// capWait shortens the wait for a task to what is left of the budget, and reports
+// whether it did
+func (b *requestBudget) capWait(wait time.Duration) (time.Duration, bool) {
+	left, limited := b.remaining()
+	if !limited || left >= wait {
+		return wait, false
+	}
+	if left < budgetMinWait {
+		left = budgetMinWait
+	}
+	return left, true
+}
+
+// degrade records that a stage was skipped or cut short, once per stage and reason
+func (b *requestBudget) degrade(stage, reason string) {
+	if b == nil {
+		return
+	}
+	degradation := stage + ":" + reason
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range b.degradations {
+		if d == degradation {
+			return
+		}
+	}
+	b.degradations = append(b.degradations, degradation)
+
+	event := log.Info().Str("stage", stage).Str("reason", reason)
+	if left, limited := b.remaining(); limited {
+		event = event.Int64("remaining_ms", left.Milliseconds())
+	}
+	event.Msg("Request budget degraded a pipeline stage")
+}
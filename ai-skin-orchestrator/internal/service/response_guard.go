@@ -0,0 +1,304 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Guardrail flags recorded on a MergedResponse
+const (
+	GuardrailAccountRedacted  = "ACCOUNT_NUMBER_REDACTED"
+	GuardrailUnsupportedClaim = "UNSUPPORTED_CLAIM_REMOVED"
+	GuardrailToxicContent     = "TOXIC_CONTENT_REMOVED"
+	GuardrailRegenerated      = "EXPLANATION_REGENERATED"
+)
+
+// redactedAccount replaces account numbers that do not belong to the requesting user
+const redactedAccount = "[account redacted]"
+
+// ResponseGuard validates user-facing text before it leaves the orchestrator
+type ResponseGuard struct {
+	accountRe         *regexp.Regexp
+	unsupportedClaims *regexp.Regexp
+	toxicRe           *regexp.Regexp
+	sentenceRe        *regexp.Regexp
+}
+
+// NewResponseGuard creates a new response guard
+func NewResponseGuard() *ResponseGuard {
+	return &ResponseGuard{
+		// Unmasked account/card numbers: 9 to 18 consecutive digits
+		accountRe: regexp.MustCompile(`\b\d{9,18}\b`),
+		unsupportedClaims: regexp.MustCompile(`(?i)` + strings.Join([]string{
+			`\b(open|close)(ed|d)?\s+(a\s+|an\s+|your\s+)?(new\s+)?(account|fixed\s+deposit|fd|demat)`,
+			`\b(invest(ed|ment)?|mutual\s+funds?|stocks?|crypto(currency)?|bitcoin)\b`,
+			`\bguaranteed\s+(returns?|approval|profit)`,
+			`\bcredit\s+card\s+(has\s+been\s+|is\s+)?(approved|issued|sanctioned)\b`,
+			`\b(insurance|policy)\s+(purchased|renewed|activated)\b`,
+		}, "|")),
+		toxicRe:    regexp.MustCompile(`(?i)\b(idiot|stupid|moron|dumb|shut\s+up|hate\s+you)\b`),
+		sentenceRe: regexp.MustCompile(`[^.!?\n]+[.!?]?`),
+	}
+}
+
+// Check redacts or regenerates unsafe text in a merged response in place and records
+// what it changed. Account numbers the user supplied in this request are allowed.
+func (rg *ResponseGuard) Check(resp *model.MergedResponse, req *model.UserRequest, intent *model.Intent) {
+	allowed := rg.allowedAccounts(req, intent)
+	flags := make(map[string]bool)
+
+	explanation := rg.cleanText(resp.Explanation, allowed, flags)
+	if strings.TrimSpace(explanation) == "" && resp.Explanation != "" {
+		explanation = regenerateExplanation(resp.Status, intent)
+		flags[GuardrailRegenerated] = true
+	}
+	resp.Explanation = explanation
+
+	rg.redactValues(resp.FinalResult, allowed, flags)
+
+	// Agent explanations are exposed to the client as well
+	for i := range resp.AgentResponses {
+		resp.AgentResponses[i].Explanation = rg.cleanText(resp.AgentResponses[i].Explanation, allowed, flags)
+		rg.redactValues(resp.AgentResponses[i].Result, allowed, flags)
+	}
+
+	resp.Guardrails = append(resp.Guardrails, rg.report(req.UserID, flags)...)
+}
+
+// CheckText applies the same checks to a free-text answer, such as a chat reply
+func (rg *ResponseGuard) CheckText(text, status string, req *model.UserRequest, intent *model.Intent) (string, []string) {
+	flags := make(map[string]bool)
+	cleaned := rg.cleanText(text, rg.allowedAccounts(req, intent), flags)
+	if strings.TrimSpace(cleaned) == "" && text != "" {
+		cleaned = regenerateExplanation(status, intent)
+		flags[GuardrailRegenerated] = true
+	}
+	return cleaned, rg.report(req.UserID, flags)
+}
+
+// cleanText redacts account numbers and drops unsafe sentences
+func (rg *ResponseGuard) cleanText(text string, allowed map[string]bool, flags map[string]bool) string {
+	return rg.dropSentences(rg.redactAccounts(text, allowed, flags), flags)
+}
+
+// report orders the raised flags and logs them
+func (rg *ResponseGuard) report(userID string, flags map[string]bool) []string {
+	var raised []string
+	for _, flag := range []string{GuardrailAccountRedacted, GuardrailUnsupportedClaim, GuardrailToxicContent, GuardrailRegenerated} {
+		if flags[flag] {
+			raised = append(raised, flag)
+		}
+	}
+
+	if len(raised) > 0 {
+		log.Warn().
+			Str("user_id", userID).
+			Strs("guardrails", raised).
+			Msg("Response modified by output guardrails")
+	}
+	return raised
+}
+
+// allowedAccounts collects account numbers the user provided themselves
+func (rg *ResponseGuard) allowedAccounts(req *model.UserRequest, intent *model.Intent) map[string]bool {
+	allowed := make(map[string]bool)
+	sources := []string{req.Input}
+	if intent != nil {
+		for _, v := range intent.Entities {
+			if s, ok := v.(string); ok {
+				sources = append(sources, s)
+			}
+		}
+	}
+
+	for _, s := range sources {
+		if !hasDigitRun(s, 9) {
+			continue
+		}
+		for _, m := range rg.accountRe.FindAllString(s, -1) {
+			allowed[m] = true
+		}
+	}
+	return allowed
+}
+
+// redactAccounts replaces account numbers that are not in allowed
+func (rg *ResponseGuard) redactAccounts(text string, allowed map[string]bool, flags map[string]bool) string {
+	if !hasDigitRun(text, 9) {
+		return text
+	}
+	return rg.accountRe.ReplaceAllStringFunc(text, func(m string) string {
+		if allowed[m] {
+			return m
+		}
+		flags[GuardrailAccountRedacted] = true
+		return redactedAccount
+	})
+}
+
+// dropSentences removes sentences with unsupported claims or toxic content
+func (rg *ResponseGuard) dropSentences(text string, flags map[string]bool) string {
+	// Most responses are clean; only split into sentences when something matches
+	if text == "" || (!rg.toxicRe.MatchString(text) && !rg.unsupportedClaims.MatchString(text)) {
+		return text
+	}
+
+	var kept []string
+	for _, sentence := range rg.sentenceRe.FindAllString(text, -1) {
+		if rg.toxicRe.MatchString(sentence) {
+			flags[GuardrailToxicContent] = true
+			continue
+		}
+		if rg.unsupportedClaims.MatchString(sentence) {
+			flags[GuardrailUnsupportedClaim] = true
+			continue
+		}
+		kept = append(kept, strings.TrimSpace(sentence))
+	}
+	return strings.Join(kept, " ")
+}
+
+// redactValues applies account redaction to string values in a result map
+func (rg *ResponseGuard) redactValues(values map[string]interface{}, allowed map[string]bool, flags map[string]bool) {
+	for k, v := range values {
+		switch val := v.(type) {
+		case string:
+			values[k] = rg.redactAccounts(val, allowed, flags)
+		case map[string]interface{}:
+			rg.redactValues(val, allowed, flags)
+		}
+	}
+}
+
+// hasDigitRun reports whether text contains at least n consecutive digits
+func hasDigitRun(text string, n int) bool {
+	run := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] >= '0' && text[i] <= '9' {
+			run++
+			if run >= n {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+	return false
+}
+
+// regenerateExplanation builds a safe explanation when nothing of the original survives
+func regenerateExplanation(status string, intent *model.Intent) string {
+	action := "your request"
+	if intent != nil && intent.Type != "" && intent.Type != model.IntentUnknown {
+		action = fmt.Sprintf("your %s request", strings.ToLower(strings.ReplaceAll(string(intent.Type), "_", " ")))
+	}
+
+	switch status {
+	case "APPROVED":
+		return fmt.Sprintf("We have processed %s.", action)
+	case "REJECTED":
+		return fmt.Sprintf("We could not complete %s.", action)
+	default:
+		return fmt.Sprintf("We have received %s and it is being reviewed.", action)
+	}
+}
+
+// GuardedStream applies the output guardrails to streamed text. Text is held back
+// until a sentence is complete so each sentence is checked before it is sent.
+type GuardedStream struct {
+	guard   *ResponseGuard
+	allowed map[string]bool
+	flags   map[string]bool
+	pending strings.Builder
+	sent    strings.Builder
+}
+
+// NewStream starts a guarded stream for a request
+func (rg *ResponseGuard) NewStream(req *model.UserRequest, intent *model.Intent) *GuardedStream {
+	return &GuardedStream{
+		guard:   rg,
+		allowed: rg.allowedAccounts(req, intent),
+		flags:   make(map[string]bool),
+	}
+}
+
+// Write buffers a delta and returns the guarded text of any sentences it completed
+func (gs *GuardedStream) Write(delta string) string {
+	gs.pending.WriteString(delta)
+
+	text := gs.pending.String()
+	cut := sentenceBoundary(text)
+	if cut == 0 {
+		return ""
+	}
+
+	gs.pending.Reset()
+	gs.pending.WriteString(text[cut:])
+	return gs.release(text[:cut])
+}
+
+// Close releases the remaining text. If the guard removed everything, a neutral
+// answer for the status is returned instead.
+func (gs *GuardedStream) Close(status string, intent *model.Intent) string {
+	tail := gs.release(gs.pending.String())
+	gs.pending.Reset()
+
+	if strings.TrimSpace(gs.sent.String()) == "" && len(gs.flags) > 0 {
+		gs.flags[GuardrailRegenerated] = true
+		tail = regenerateExplanation(status, intent)
+		gs.sent.WriteString(tail)
+	}
+	return tail
+}
+
+// Text returns everything sent so far
+func (gs *GuardedStream) Text() string {
+	return gs.sent.String()
+}
+
+// Flags reports and logs the guardrails that fired on the stream
+func (gs *GuardedStream) Flags(userID string) []string {
+	return gs.guard.report(userID, gs.flags)
+}
+
+// release checks a run of complete sentences and records what is sent
+func (gs *GuardedStream) release(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	cleaned := gs.guard.cleanText(text, gs.allowed, gs.flags)
+	if cleaned != text {
+		// Dropping sentences loses the spacing around them; restore the segment's own
+		if trimmed := strings.TrimSpace(cleaned); trimmed != "" {
+			lead := text[:len(text)-len(strings.TrimLeft(text, " \t\n"))]
+			trail := text[len(strings.TrimRight(text, " \t\n")):]
+			cleaned = lead + trimmed + trail
+		} else {
+			cleaned = ""
+		}
+	}
+
+	gs.sent.WriteString(cleaned)
+	return cleaned
+}
+
+// sentenceBoundary returns the end of the last complete sentence in text, or 0. A
+// sentence ends at a newline or at . ! ? followed by whitespace, so decimals are not split.
+func sentenceBoundary(text string) int {
+	for i := len(text) - 1; i >= 0; i-- {
+		switch text[i] {
+		case '\n':
+			return i + 1
+		case ' ', '\t':
+			if i > 0 && strings.ContainsRune(".!?", rune(text[i-1])) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
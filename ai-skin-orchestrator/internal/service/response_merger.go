@@ -1,22 +1,121 @@
 package service
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
 	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
 )
 
-// ResponseMerger merges responses from multiple agents
+// AgentRole is how an agent type's response counts towards the merged status
+type AgentRole string
+
+const (
+	RoleAuthoritative AgentRole = "authoritative" // Decides the status; its rejection vetoes
+	RoleAdvisory      AgentRole = "advisory"      // Adds its explanation and risk, never decides the status
+)
+
+// IntentPrecedence names the authoritative and advisory agent types of an intent.
+// Agent types it does not name are authoritative.
+type IntentPrecedence struct {
+	Intent        string   `json:"intent"`
+	Authoritative []string `json:"authoritative,omitempty"`
+	Advisory      []string `json:"advisory,omitempty"`
+}
+
+// mergerAgentTypes are the agent types a precedence may name
+var mergerAgentTypes = map[string]bool{
+	"BANKING": true, "FRAUD": true, "GUARDRAIL": true, "CLEARANCE": true,
+	"SCORING": true, "INSIGHTS": true, "PAYMENT": true,
+}
+
+// builtinPrecedence keeps enrichment agents from vetoing reads: the banking agent's
+// answer is the answer, and scoring or insights only add to it
+var builtinPrecedence = []IntentPrecedence{
+	{Intent: string(model.IntentCheckBalance), Authoritative: []string{"BANKING"}, Advisory: []string{"SCORING", "INSIGHTS"}},
+	{Intent: string(model.IntentGetStatement), Authoritative: []string{"BANKING"}, Advisory: []string{"SCORING", "INSIGHTS"}},
+	{Intent: string(model.IntentSummarizeStatement), Authoritative: []string{"BANKING"}, Advisory: []string{"SCORING", "INSIGHTS"}},
+	{Intent: string(model.IntentListBeneficiaries), Authoritative: []string{"BANKING"}, Advisory: []string{"SCORING", "INSIGHTS"}},
+	{Intent: string(model.IntentBudgetStatus), Authoritative: []string{"BANKING"}, Advisory: []string{"SCORING", "INSIGHTS"}},
+}
+
+// ResponseMerger merges responses from multiple agents. Which agents decide the
+// status is set per intent; for intents without a precedence every agent does, and
+// any rejection rejects.
 type ResponseMerger struct {
+	roles map[string]map[string]AgentRole // Intent → agent type → role
+}
+
+// NewResponseMerger creates a new response merger with the built-in precedences, and
+// those of the precedence file in place of them for the intents it names
+func NewResponseMerger(cfg *config.MergerConfig) (*ResponseMerger, error) {
+	precedences := builtinPrecedence
+	if cfg.PrecedenceFile != "" {
+		data, err := os.ReadFile(cfg.PrecedenceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read merger precedence file: %w", err)
+		}
+		var loaded []IntentPrecedence
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return nil, fmt.Errorf("invalid merger precedence file: %w", err)
+		}
+		precedences = append(append([]IntentPrecedence{}, builtinPrecedence...), loaded...)
+		log.Info().Int("precedences", len(loaded)).Msg("Merger precedences loaded")
+	}
+
+	rm := &ResponseMerger{roles: make(map[string]map[string]AgentRole)}
+	fromFile := make(map[string]bool)
+	for i, p := range precedences {
+		intent := strings.ToUpper(strings.TrimSpace(p.Intent))
+		if intent == "" {
+			return nil, fmt.Errorf("merger precedence %d of the file names no intent", i+1-len(builtinPrecedence))
+		}
+		// A file entry replaces the built-in one; two file entries for an intent are a mistake
+		if i >= len(builtinPrecedence) {
+			if fromFile[intent] {
+				return nil, fmt.Errorf("merger precedence for %s is given twice", intent)
+			}
+			fromFile[intent] = true
+		}
+
+		roles := make(map[string]AgentRole)
+		for _, list := range []struct {
+			types []string
+			role  AgentRole
+		}{{p.Authoritative, RoleAuthoritative}, {p.Advisory, RoleAdvisory}} {
+			for _, agentType := range list.types {
+				agentType = strings.ToUpper(strings.TrimSpace(agentType))
+				if !mergerAgentTypes[agentType] {
+					return nil, fmt.Errorf("merger precedence for %s: unknown agent type %q", intent, agentType)
+				}
+				if _, ok := roles[agentType]; ok {
+					return nil, fmt.Errorf("merger precedence for %s names %s twice", intent, agentType)
+				}
+				roles[agentType] = list.role
+			}
+		}
+		rm.roles[intent] = roles
+	}
+	return rm, nil
 }
 
-// NewResponseMerger creates a new response merger
-func NewResponseMerger() *ResponseMerger {
-	return &ResponseMerger{}
+// Role returns how an agent type's response counts for an intent
+func (rm *ResponseMerger) Role(intent model.IntentType, agentType string) AgentRole {
+	if role, ok := rm.roles[string(intent)][strings.ToUpper(agentType)]; ok {
+		return role
+	}
+	return RoleAuthoritative
 }
 
-// MergeResponses merges multiple agent responses into a single response
-func (rm *ResponseMerger) MergeResponses(responses []model.AgentResponse) (*model.MergedResponse, error) {
+// MergeResponses merges multiple agent responses to an intent into a single response.
+// Advisory agents add their explanations, results and risk but do not count towards
+// the status, unless no authoritative agent answered. An empty intent, as for a held
+// transfer released by the user, merges with no precedence.
+func (rm *ResponseMerger) MergeResponses(intent model.IntentType, responses []model.AgentResponse) (*model.MergedResponse, error) {
 	if len(responses) == 0 {
 		return nil, fmt.Errorf("no responses to merge")
 	}
@@ -25,25 +124,39 @@ func (rm *ResponseMerger) MergeResponses(responses []model.AgentResponse) (*mode
 		return rm.singleResponseToMerged(responses[0]), nil
 	}
 
+	deciding, advisory := rm.splitByRole(intent, responses)
+
 	// Check for conflicts
-	conflicts := rm.detectConflicts(responses)
+	conflicts := rm.detectConflicts(deciding)
 
 	// Determine final status
-	finalStatus := rm.determineFinalStatus(responses, conflicts)
+	finalStatus := rm.determineFinalStatus(deciding, conflicts)
 
-	// Merge results
-	finalResult := rm.mergeResults(responses)
+	// Merge results, an advisory agent's only where the deciding agents left a gap
+	finalResult := rm.mergeResults(deciding)
+	for k, v := range rm.mergeResults(advisory) {
+		if _, exists := finalResult[k]; !exists {
+			finalResult[k] = v
+		}
+	}
 
-	// Calculate average risk score
+	// Calculate average risk score, advisory agents included
 	avgRiskScore := rm.calculateAverageRiskScore(responses)
 
 	// Generate explanation
-	explanation := rm.generateExplanation(responses, conflicts)
+	explanation := rm.generateExplanation(deciding, conflicts)
+	var advisoryAgents []string
+	for _, resp := range advisory {
+		advisoryAgents = append(advisoryAgents, resp.AgentID)
+		if resp.Explanation != "" {
+			explanation += " " + resp.Explanation
+		}
+	}
 
 	// Determine who resolved conflicts
 	resolvedBy := ""
 	if len(conflicts) > 0 {
-		resolvedBy = rm.resolveConflicts(responses, conflicts)
+		resolvedBy = rm.resolveConflicts(deciding, conflicts)
 	}
 
 	return &model.MergedResponse{
@@ -54,9 +167,26 @@ func (rm *ResponseMerger) MergeResponses(responses []model.AgentResponse) (*mode
 		AgentResponses: responses,
 		Conflicts:      conflicts,
 		ResolvedBy:     resolvedBy,
+		Advisory:       advisoryAgents,
 	}, nil
 }
 
+// splitByRole separates the responses that decide the status from the advisory ones.
+// When only advisory agents answered, they decide.
+func (rm *ResponseMerger) splitByRole(intent model.IntentType, responses []model.AgentResponse) (deciding, advisory []model.AgentResponse) {
+	for _, resp := range responses {
+		if rm.Role(intent, resp.AgentType) == RoleAdvisory {
+			advisory = append(advisory, resp)
+		} else {
+			deciding = append(deciding, resp)
+		}
+	}
+	if len(deciding) == 0 {
+		return responses, nil
+	}
+	return deciding, advisory
+}
+
 // singleResponseToMerged converts a single response to merged format
 func (rm *ResponseMerger) singleResponseToMerged(resp model.AgentResponse) *model.MergedResponse {
 	return &model.MergedResponse{
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// benchMerger returns a merger with the built-in precedences only, which always load
+func benchMerger(b *testing.B) *ResponseMerger {
+	responseMerger, err := NewResponseMerger(&config.MergerConfig{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return responseMerger
+}
+
+// benchMultiResponses are three agents' answers to one transfer, the fraud check
+// rejecting it
+func benchMultiResponses() []model.AgentResponse {
+	return []model.AgentResponse{
+		syntheticResponse("GUARDRAIL", "APPROVED", 0.15),
+		syntheticResponse("FRAUD", "REJECTED", 0.75),
+		syntheticResponse("BANKING", "APPROVED", 0.1),
+	}
+}
+
+func BenchmarkMergeResponses(b *testing.B) {
+	responseMerger := benchMerger(b)
+
+	cases := []struct {
+		name      string
+		responses []model.AgentResponse
+		budget    time.Duration
+	}{
+		{"single", []model.AgentResponse{syntheticResponse("BANKING", "APPROVED", 0.1)}, 20 * time.Microsecond},
+		{"multi-conflict", benchMultiResponses(), 50 * time.Microsecond},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := responseMerger.MergeResponses(benchIntent.Type, tc.responses); err != nil {
+					b.Fatal(err)
+				}
+			}
+			withinBudget(b, tc.budget)
+		})
+	}
+}
+
+// BenchmarkResponseGuard includes the multi-agent merge it guards
+func BenchmarkResponseGuard(b *testing.B) {
+	responseMerger := benchMerger(b)
+	responseGuard := NewResponseGuard()
+	multi := benchMultiResponses()
+	req := &model.UserRequest{UserID: "U10001", Input: benchTransferInput}
+	intent := benchIntent
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		merged, err := responseMerger.MergeResponses(intent.Type, multi)
+		if err != nil {
+			b.Fatal(err)
+		}
+		responseGuard.Check(merged, req, &intent)
+	}
+	withinBudget(b, 100*time.Microsecond)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/channel"
+	"github.com/aibanking/shared/tz"
+	"github.com/rs/zerolog/log"
+)
+
+// ResponseTransformer rewrites the final result of a response for display, e.g. masking
+// account numbers. Transformers change result values in place; nested maps and lists
+// are visited by the transformer itself.
+type ResponseTransformer interface {
+	Name() string
+	Transform(result map[string]interface{}, tc *TransformContext)
+}
+
+// TransformContext is what a transformer knows about the response it formats
+type TransformContext struct {
+	Intent     string
+	Channel    string
+	Currency   string         // For amounts whose result does not name a currency
+	Location   *time.Location // Dates are shown in this time zone, the user's when known
+	DateFormat string
+	Locale     tz.Locale // The user's locale, when known; amounts and dates follow its region
+}
+
+// pipelineRule picks the transformers for responses to an intent, a channel or both
+type pipelineRule struct {
+	Intent       string   `json:"intent,omitempty"`
+	Channel      string   `json:"channel,omitempty"`
+	Transformers []string `json:"transformers"`
+}
+
+// ResponsePipeline formats results consistently whichever agent produced them. Each
+// response runs through an ordered list of transformers chosen by its intent and
+// channel: a rule for both wins over one for the intent, which wins over one for the
+// channel, which wins over the default pipeline.
+type ResponsePipeline struct {
+	transformers map[string]ResponseTransformer
+	defaults     []string
+	rules        []pipelineRule
+	currency     string
+	location     *time.Location
+	dateFormat   string
+}
+
+// NewResponsePipeline creates the response pipeline with the built-in transformers and
+// any plugins, and loads the per-intent and per-channel pipelines
+func NewResponsePipeline(cfg *config.ResponseConfig, plugins ...ResponseTransformer) (*ResponsePipeline, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown response time zone %q: %w", cfg.Timezone, err)
+	}
+
+	rp := &ResponsePipeline{
+		transformers: make(map[string]ResponseTransformer),
+		defaults:     splitNames(cfg.Transformers),
+		currency:     strings.ToUpper(cfg.Currency),
+		location:     location,
+		dateFormat:   cfg.DateFormat,
+	}
+	for _, t := range append([]ResponseTransformer{maskAccounts{}, formatCurrency{}, localizeDates{}}, plugins...) {
+		rp.transformers[t.Name()] = t
+	}
+
+	if cfg.TransformersFile != "" {
+		data, err := os.ReadFile(cfg.TransformersFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response transformers file: %w", err)
+		}
+		if err := json.Unmarshal(data, &rp.rules); err != nil {
+			return nil, fmt.Errorf("invalid response transformers file: %w", err)
+		}
+	}
+
+	if err := rp.check(rp.defaults); err != nil {
+		return nil, fmt.Errorf("RESPONSE_TRANSFORMERS: %w", err)
+	}
+	for i := range rp.rules {
+		rule := &rp.rules[i]
+		rule.Intent = strings.ToUpper(strings.TrimSpace(rule.Intent))
+		if rule.Intent == "" && strings.TrimSpace(rule.Channel) == "" {
+			return nil, fmt.Errorf("response transformers rule %d names neither an intent nor a channel", i+1)
+		}
+		if rule.Channel != "" {
+			c, err := channel.Parse(rule.Channel)
+			if err != nil {
+				return nil, fmt.Errorf("response transformers rule %d: %w", i+1, err)
+			}
+			rule.Channel = string(c)
+		}
+		if err := rp.check(rule.Transformers); err != nil {
+			return nil, fmt.Errorf("response transformers rule %d: %w", i+1, err)
+		}
+	}
+
+	log.Info().Strs("default", rp.defaults).Int("rules", len(rp.rules)).Msg("Response pipeline loaded")
+	return rp, nil
+}
+
+// Apply runs a response's final result through the pipeline for its intent and channel
+// and records which transformers ran
+func (rp *ResponsePipeline) Apply(resp *model.MergedResponse, req *model.UserRequest, intent *model.Intent) {
+	if resp == nil || resp.FinalResult == nil {
+		return
+	}
+	intentType := ""
+	if intent != nil {
+		intentType = string(intent.Type)
+	}
+	names := rp.Pipeline(intentType, req.Channel)
+	if len(names) == 0 {
+		return
+	}
+
+	// Agents' results may hold structs; as JSON they are maps transformers can visit
+	result, err := normalizeResult(resp.FinalResult)
+	if err != nil {
+		log.Warn().Err(err).Str("intent", intentType).Msg("Final result is not JSON, skipping response transformers")
+		return
+	}
+
+	tc := &TransformContext{
+		Intent:     intentType,
+		Channel:    strings.ToUpper(req.Channel),
+		Currency:   rp.currency,
+		Location:   rp.location,
+		DateFormat: rp.dateFormat,
+	}
+	// Times are stored in UTC and shown in the user's time zone and locale, from the
+	// request or its session
+	if name, ok := req.Context["timezone"].(string); ok {
+		tc.Location = tz.LoadOr(name, rp.location)
+	}
+	if raw, ok := req.Context["locale"].(string); ok {
+		if locale, err := tz.ParseLocale(raw); err == nil {
+			tc.Locale = locale
+		}
+	}
+	for _, name := range names {
+		rp.transformers[name].Transform(result, tc)
+	}
+	resp.FinalResult = result
+	resp.Transformers = names
+}
+
+// Pipeline returns the transformers that run for an intent and channel, in order
+func (rp *ResponsePipeline) Pipeline(intent, channel string) []string {
+	intent = strings.ToUpper(intent)
+	channel = strings.ToUpper(channel)
+
+	best, bestScore := rp.defaults, 0
+	for _, rule := range rp.rules {
+		if (rule.Intent != "" && rule.Intent != intent) || (rule.Channel != "" && rule.Channel != channel) {
+			continue
+		}
+		score := 1
+		if rule.Intent != "" {
+			score++
+		}
+		if rule.Intent != "" && rule.Channel != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule.Transformers, score
+		}
+	}
+	return best
+}
+
+// Transformers returns the names of the registered transformers
+func (rp *ResponsePipeline) Transformers() []string {
+	names := make([]string, 0, len(rp.transformers))
+	for name := range rp.transformers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check reports the first transformer name that is not registered
+func (rp *ResponsePipeline) check(names []string) error {
+	for _, name := range names {
+		if _, ok := rp.transformers[name]; !ok {
+			return fmt.Errorf("unknown transformer %q", name)
+		}
+	}
+	return nil
+}
+
+// splitNames splits a comma-separated list, dropping blanks
+func splitNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeResult turns a result into plain JSON values
+func normalizeResult(result map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// walkResult calls fn for every key of a result and of the maps nested in it, lists
+// included
+func walkResult(value interface{}, fn func(values map[string]interface{}, key string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// Transformers may add keys; visit only the ones already there
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			fn(v, key)
+			walkResult(v[key], fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkResult(item, fn)
+		}
+	}
+}
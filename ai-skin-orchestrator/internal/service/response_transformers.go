@@ -0,0 +1,202 @@
+package service
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/shared/tz"
+)
+
+// displaySuffix names the formatted copy a transformer adds beside a value, so clients
+// that read the raw value keep working
+const displaySuffix = "_display"
+
+// maskAccounts masks account and card numbers to their last four digits, as XXXX1234
+type maskAccounts struct{}
+
+func (maskAccounts) Name() string { return "mask_accounts" }
+
+func (maskAccounts) Transform(result map[string]interface{}, tc *TransformContext) {
+	walkResult(result, func(values map[string]interface{}, key string) {
+		lower := strings.ToLower(key)
+		if !strings.Contains(lower, "account") && !strings.Contains(lower, "card") {
+			return
+		}
+		if s, ok := values[key].(string); ok {
+			values[key] = maskAccountNumber(s)
+		}
+	})
+}
+
+// maskAccountNumber masks a value with at least six digits; shorter ones, such as an
+// already masked number or an account type, are left alone
+func maskAccountNumber(value string) string {
+	var digits []byte
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			digits = append(digits, value[i])
+		}
+	}
+	if len(digits) < 6 {
+		return value
+	}
+	return "XXXX" + string(digits[len(digits)-4:])
+}
+
+// formatCurrency adds a formatted copy of every amount, e.g. balance_display: "₹50,000.00".
+// Amounts are grouped in lakhs and crores for a user in India, or when the user's
+// locale is not known, for rupee amounts.
+type formatCurrency struct{}
+
+func (formatCurrency) Name() string { return "format_currency" }
+
+func (formatCurrency) Transform(result map[string]interface{}, tc *TransformContext) {
+	walkResult(result, func(values map[string]interface{}, key string) {
+		amount, ok := values[key].(float64)
+		if !ok || !isAmountKey(key) {
+			return
+		}
+		currency := tc.Currency
+		if named, ok := values["currency"].(string); ok && named != "" {
+			currency = strings.ToUpper(named)
+		}
+		values[key+displaySuffix] = formatAmount(amount, currency, indianGrouping(currency, tc.Locale))
+	})
+}
+
+// amountKeys are result keys that hold money
+var amountKeys = map[string]bool{
+	"amount": true, "balance": true, "fee": true, "fees": true, "charges": true,
+	"emi": true, "principal": true, "limit": true, "total": true,
+}
+
+func isAmountKey(key string) bool {
+	key = strings.ToLower(key)
+	if amountKeys[key] {
+		return true
+	}
+	for _, suffix := range []string{"_amount", "_balance", "_fee", "_charges", "_limit", "_total", "_emi"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// currencySymbols are shown in place of the currency code
+var currencySymbols = map[string]string{"INR": "₹", "USD": "$", "EUR": "€", "GBP": "£"}
+
+// indianGrouping reports whether an amount is grouped in lakhs and crores
+func indianGrouping(currency string, locale tz.Locale) bool {
+	if locale.Region() != "" {
+		return locale.Region() == "IN"
+	}
+	return currency == "INR"
+}
+
+// formatAmount renders an amount with two decimals, grouped in lakhs and crores or in
+// thousands
+func formatAmount(amount float64, currency string, indian bool) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+	fraction := cents % 100
+
+	var grouped string
+	if indian {
+		grouped = groupIndian(whole)
+	} else {
+		grouped = groupThousands(whole)
+	}
+
+	number := grouped + "." + strconv.FormatInt(100+fraction, 10)[1:]
+	if symbol, ok := currencySymbols[currency]; ok {
+		return sign + symbol + number
+	}
+	return sign + currency + " " + number
+}
+
+// groupThousands groups digits in threes: 1,234,567
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head := len(digits) % 3
+	var b strings.Builder
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// groupIndian groups the last three digits, then pairs: 12,34,567
+func groupIndian(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	rest, last := digits[:len(digits)-3], digits[len(digits)-3:]
+	head := len(rest) % 2
+	var b strings.Builder
+	if head > 0 {
+		b.WriteString(rest[:head])
+	}
+	for i := head; i < len(rest); i += 2 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(rest[i : i+2])
+	}
+	return b.String() + "," + last
+}
+
+// regionDateFormats are the layouts of timestamps and plain dates for the locale
+// regions that do not put the day first
+var regionDateFormats = map[string][2]string{
+	"US": {"Jan 2, 2006, 03:04 PM", "Jan 2, 2006"},
+	"CA": {"Jan 2, 2006, 03:04 PM", "Jan 2, 2006"},
+	"PH": {"Jan 2, 2006, 03:04 PM", "Jan 2, 2006"},
+}
+
+// localizeDates adds a copy of every timestamp in the user's time zone, or the
+// configured one, and layout, e.g. created_at_display: "16 Oct 2026, 05:15 PM". Users
+// whose locale puts the month first see "Oct 16, 2026, 05:15 PM".
+type localizeDates struct{}
+
+func (localizeDates) Name() string { return "localize_dates" }
+
+func (localizeDates) Transform(result map[string]interface{}, tc *TransformContext) {
+	timeFormat, dateFormat := tc.DateFormat, "02 Jan 2006"
+	if formats, ok := regionDateFormats[tc.Locale.Region()]; ok {
+		timeFormat, dateFormat = formats[0], formats[1]
+	}
+	walkResult(result, func(values map[string]interface{}, key string) {
+		s, ok := values[key].(string)
+		if !ok || !isDateKey(key) {
+			return
+		}
+		// A plain date is already a calendar day, so it is not moved between zones
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			values[key+displaySuffix] = t.In(tc.Location).Format(timeFormat)
+		} else if d, err := time.Parse("2006-01-02", s); err == nil {
+			values[key+displaySuffix] = d.Format(dateFormat)
+		}
+	})
+}
+
+func isDateKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasSuffix(key, "_at") || strings.HasSuffix(key, "date") ||
+		strings.HasSuffix(key, "_time") || strings.HasSuffix(key, "timestamp")
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Purge report limits
+const (
+	purgeReportLimit = 50   // Reports kept, oldest dropped first
+	purgedIDLimit    = 1000 // IDs listed per class in a report
+)
+
+// Purge triggers
+const (
+	PurgeScheduled = "schedule"
+	PurgeManual    = "manual"
+)
+
+// RetentionService purges conversations, transactions, and the memory facts and
+// analytics events that come from conversations once they are older than their class's retention policy, except
+// the data of users under a legal hold, and keeps a report of every purge
+type RetentionService struct {
+	cfg           *config.RetentionConfig
+	ragService    *RAGService
+	memoryService *MemoryService
+	analytics     *AnalyticsService
+	holds         map[string]*model.LegalHold // Keyed by user ID
+	reports       []model.PurgeReport         // Oldest first
+	mu            sync.Mutex
+	purgeMu       sync.Mutex // One purge at a time
+}
+
+// NewRetentionService creates a retention service. Purges only run on a schedule
+// once Run is started.
+func NewRetentionService(cfg *config.RetentionConfig, ragService *RAGService, memoryService *MemoryService, analytics *AnalyticsService) *RetentionService {
+	return &RetentionService{
+		cfg:           cfg,
+		ragService:    ragService,
+		memoryService: memoryService,
+		analytics:     analytics,
+		holds:         make(map[string]*model.LegalHold),
+	}
+}
+
+// Policies returns the retention policy of each data class
+func (rs *RetentionService) Policies() []model.RetentionPolicy {
+	return []model.RetentionPolicy{
+		{Class: model.RetentionConversations, Days: rs.cfg.ConversationsDays, Description: "Conversation documents, memory facts not confirmed since, and analytics events"},
+		{Class: model.RetentionTransactions, Days: rs.cfg.TransactionsDays, Description: "Transaction documents"},
+	}
+}
+
+// Run purges every RETENTION_PURGE_INTERVAL_MINUTES until ctx is done
+func (rs *RetentionService) Run(ctx context.Context) {
+	interval := time.Duration(rs.cfg.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log.Info().Dur("interval", interval).Msg("Retention purging started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.Purge(PurgeScheduled, false)
+		}
+	}
+}
+
+// Purge removes everything past its retention and records a report. With dryRun it
+// only reports what would be removed.
+func (rs *RetentionService) Purge(trigger string, dryRun bool) *model.PurgeReport {
+	rs.purgeMu.Lock()
+	defer rs.purgeMu.Unlock()
+
+	report := model.PurgeReport{
+		ID:        ids.New(ids.Purge),
+		DryRun:    dryRun,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	for _, policy := range rs.Policies() {
+		class := model.PurgedClass{Class: policy.Class}
+		if policy.Days <= 0 {
+			class.Skipped = true
+			report.Classes = append(report.Classes, class)
+			continue
+		}
+
+		cutoff := report.StartedAt.AddDate(0, 0, -policy.Days)
+		class.Cutoff = &cutoff
+
+		var ids []string
+		switch policy.Class {
+		case model.RetentionConversations:
+			ids, class.Held = rs.ragService.Purge(model.CollectionConversation, cutoff, rs.IsHeld, dryRun)
+			facts, held := rs.memoryService.Purge(cutoff, rs.IsHeld, dryRun)
+			ids = append(ids, facts...)
+			class.Held += held
+			events, held := rs.analytics.Purge(cutoff, rs.IsHeld, dryRun)
+			ids = append(ids, events...)
+			class.Held += held
+		case model.RetentionTransactions:
+			ids, class.Held = rs.ragService.Purge(model.CollectionTransaction, cutoff, rs.IsHeld, dryRun)
+		}
+
+		class.Purged = len(ids)
+		sort.Strings(ids)
+		if len(ids) > purgedIDLimit {
+			ids = ids[:purgedIDLimit]
+		}
+		class.IDs = ids
+		report.Classes = append(report.Classes, class)
+	}
+	report.FinishedAt = time.Now()
+
+	event := log.Info().Str("purge_id", report.ID).Str("trigger", trigger).Bool("dry_run", dryRun)
+	for _, class := range report.Classes {
+		event = event.Int(class.Class+"_purged", class.Purged).Int(class.Class+"_held", class.Held)
+	}
+	event.Msg("Retention purge finished")
+
+	rs.mu.Lock()
+	rs.reports = append(rs.reports, report)
+	if len(rs.reports) > purgeReportLimit {
+		rs.reports = rs.reports[len(rs.reports)-purgeReportLimit:]
+	}
+	rs.mu.Unlock()
+
+	return &report
+}
+
+// Reports returns up to limit purge reports, most recent first
+func (rs *RetentionService) Reports(limit int) []model.PurgeReport {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	reports := make([]model.PurgeReport, 0, len(rs.reports))
+	for i := len(rs.reports) - 1; i >= 0; i-- {
+		reports = append(reports, rs.reports[i])
+		if limit > 0 && len(reports) >= limit {
+			break
+		}
+	}
+	return reports
+}
+
+// PlaceHold exempts a user's data from purging, replacing any hold already placed
+func (rs *RetentionService) PlaceHold(userID string, req *model.LegalHoldRequest) (*model.LegalHold, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	if req.Reason == "" || req.PlacedBy == "" {
+		return nil, fmt.Errorf("reason and placed_by are required")
+	}
+
+	hold := &model.LegalHold{
+		UserID:   userID,
+		Reason:   req.Reason,
+		PlacedBy: req.PlacedBy,
+		PlacedAt: time.Now(),
+	}
+
+	rs.mu.Lock()
+	rs.holds[userID] = hold
+	rs.mu.Unlock()
+
+	log.Info().Str("user_id", userID).Str("placed_by", req.PlacedBy).Msg("Legal hold placed")
+	holdCopy := *hold
+	return &holdCopy, nil
+}
+
+// ReleaseHold lifts a user's legal hold, reporting whether there was one. Their data
+// is purged by the next run once it is past retention.
+func (rs *RetentionService) ReleaseHold(userID string) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if _, ok := rs.holds[userID]; !ok {
+		return false
+	}
+	delete(rs.holds, userID)
+	log.Info().Str("user_id", userID).Msg("Legal hold released")
+	return true
+}
+
+// Holds returns the legal holds in place, most recent first
+func (rs *RetentionService) Holds() []model.LegalHold {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	holds := make([]model.LegalHold, 0, len(rs.holds))
+	for _, hold := range rs.holds {
+		holds = append(holds, *hold)
+	}
+	sort.Slice(holds, func(a, b int) bool { return holds[a].PlacedAt.After(holds[b].PlacedAt) })
+	return holds
+}
+
+// IsHeld reports whether a user is under a legal hold
+func (rs *RetentionService) IsHeld(userID string) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	_, ok := rs.holds[userID]
+	return ok
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// scamPattern is a family of phrases scammers have victims repeat or that describe
+// what the scammer is doing
+type scamPattern struct {
+	code   string
+	weight float64
+	re     *regexp.Regexp
+}
+
+// scamPatterns are matched against lower-cased messages. Coaching and remote access
+// weigh most: a genuine payment rarely comes with either.
+var scamPatterns = []scamPattern{
+	{model.ScamUrgency, 0.2, regexp.MustCompile(`\b(urgent(ly)?|immediately|right now|right away|asap|as soon as possible|within \d+ ?(minutes?|mins?|hours?|hrs?)|emergency|jaldi|turant)\b`)},
+	{model.ScamCoaching, 0.4, regexp.MustCompile(`\b(tell (the|this|you|your) (bot|bank|app|assistant)|(say|saying) (it'?s|it is|that it'?s|that it is) for|(don'?t|do not|never) tell (anyone|anybody|the bank|my \w+)|(he|she|they|caller|agent|officer|the man|the lady) (told|asked) me to (say|send|pay|transfer|install|move)|keep (it|this) (a )?secret|what (do|should) i (say|tell) (you|the bank))\b`)},
+	{model.ScamRemoteAccess, 0.4, regexp.MustCompile(`\b(any ?desk|team ?viewer|quick ?support|rust ?desk|airdroid|screen ?shar(e|ing)|share (my|the) screen|remote (access|support|control|app))\b`)},
+	{model.ScamAuthority, 0.3, regexp.MustCompile(`\b(police|cbi|rbi (officer|official)|customs|income tax (officer|department)|cyber ?(cell|crime) (officer|department)|digital arrest|arrest warrant|narcotics|(parcel|courier) (seized|held|with drugs)|kyc (is )?(expir\w*|pending|suspended)|account (will be |is |has been )?(blocked|suspended|frozen))\b`)},
+	{model.ScamOTPSharing, 0.3, regexp.MustCompile(`\b((share|give|read|send|tell)( \w+){0,3} (the )?(otp|pin|cvv|password)|(otp|pin|cvv) (to|with) (him|her|them|the caller|the agent|the officer))\b`)},
+	{model.ScamPrize, 0.2, regexp.MustCompile(`\b(lottery|jackpot|you (have )?won|i (have )?won|prize money|(processing|release|clearance|refund) fee|kbc)\b`)},
+}
+
+// Weights of the signals that come from the payment rather than the conversation
+const (
+	scamFirstTimePayeeWeight = 0.15
+	scamLargeAmountWeight    = 0.2
+	scamCombinationWeight    = 0.2
+)
+
+// scamUnusualMultiple is how many times the user's average a transfer must be to count
+// as large below SCAM_LARGE_AMOUNT
+const scamUnusualMultiple = 5
+
+// scamWarnings say what to do about the strongest signal, most telling first
+var scamWarnings = []struct {
+	code    string
+	warning string
+}{
+	{model.ScamRemoteAccess, "If anyone has asked you to install AnyDesk, TeamViewer or another screen-sharing app, stop and uninstall it: whoever can see your screen can see your codes and move your money. We never ask you to share your screen."},
+	{model.ScamCoaching, "If someone is telling you what to say to us, or to keep this payment from the bank or your family, it is almost certainly a scam. Nobody genuine needs you to give us a reason that isn't true."},
+	{model.ScamOTPSharing, "Never share an OTP, PIN or CVV with anyone, including callers who say they are from the bank."},
+	{model.ScamAuthority, "Police, RBI, customs and tax officials never ask for money over a call or chat, and there is no such thing as a \"digital arrest\"."},
+	{model.ScamPrize, "Genuine prizes and refunds never ask you to pay a fee first."},
+	{model.ScamUrgency, "Scammers make things urgent so you don't stop to check. Take a moment, and if in doubt call the person back on a number you already have."},
+}
+
+const scamWarningSuffix = "If you think you are being scammed, don't confirm and call 1930, the National Cyber Crime Helpline."
+
+// seenScamSignal is a signal raised by a message in a session
+type seenScamSignal struct {
+	signal model.ScamSignal
+	at     time.Time
+}
+
+// ScamDetector looks for signs that a user is being talked into a payment: scammers
+// coach victims through the chat ("tell the bot it's for a family emergency"), keep
+// them on a screen-sharing app or pose as officials. Every message in a session is
+// scanned; when the session then makes a transfer or adds a payee, the signals seen
+// in the last SCAM_SIGNAL_WINDOW_MINUTES are weighed with the payment itself, a large
+// first payment to a new payee counting most.
+type ScamDetector struct {
+	cfg *config.ScamConfig
+
+	mu       sync.Mutex
+	sessions map[string]map[string]seenScamSignal // Session key -> code -> latest sighting
+}
+
+// NewScamDetector creates a new scam detector
+func NewScamDetector(cfg *config.ScamConfig) *ScamDetector {
+	sd := &ScamDetector{
+		cfg:      cfg,
+		sessions: make(map[string]map[string]seenScamSignal),
+	}
+
+	if cfg.Enabled {
+		go sd.cleanup()
+	}
+
+	return sd
+}
+
+// Observe scans a message for scam signals and keeps them for the session
+func (sd *ScamDetector) Observe(userID, sessionID, text string) []model.ScamSignal {
+	if !sd.cfg.Enabled {
+		return nil
+	}
+	signals := scanScamSignals(text)
+	if len(signals) == 0 {
+		return nil
+	}
+
+	key := scamSessionKey(userID, sessionID)
+	now := time.Now()
+	sd.mu.Lock()
+	seen := sd.sessions[key]
+	if seen == nil {
+		seen = make(map[string]seenScamSignal)
+		sd.sessions[key] = seen
+	}
+	for _, signal := range signals {
+		seen[signal.Code] = seenScamSignal{signal: signal, at: now}
+	}
+	sd.mu.Unlock()
+
+	codes := make([]string, len(signals))
+	for i, signal := range signals {
+		codes[i] = signal.Code
+	}
+	log.Info().
+		Str("user_id", userID).
+		Str("session_id", sessionID).
+		Strs("signals", codes).
+		Msg("Scam signals in message")
+	return signals
+}
+
+// Assess weighs a transfer or new payee against the scam signals its session raised.
+// Returns nil when detection is off, the intent moves no money to a new party or the
+// conversation raised no signal: the payment alone is for the risk agents to judge.
+func (sd *ScamDetector) Assess(req *model.UserRequest, intent *model.Intent, enriched *model.EnrichedContext, verification *model.PayeeVerification) *model.ScamAssessment {
+	if !sd.cfg.Enabled || (!isTransferIntent(intent.Type) && intent.Type != model.IntentAddBeneficiary) {
+		return nil
+	}
+
+	window := time.Duration(sd.cfg.WindowMinutes) * time.Minute
+	var signals []model.ScamSignal
+	sd.mu.Lock()
+	for _, seen := range sd.sessions[scamSessionKey(req.UserID, req.SessionID)] {
+		if time.Since(seen.at) <= window {
+			signals = append(signals, seen.signal)
+		}
+	}
+	sd.mu.Unlock()
+	if len(signals) == 0 {
+		return nil
+	}
+
+	coached := false
+	for _, signal := range signals {
+		switch signal.Code {
+		case model.ScamCoaching, model.ScamRemoteAccess, model.ScamAuthority, model.ScamOTPSharing:
+			coached = true
+		}
+	}
+
+	account, _ := intent.Entities["to_account"].(string)
+	if account == "" {
+		account, _ = intent.Entities["upi_id"].(string)
+	}
+	firstTime := intent.Type == model.IntentAddBeneficiary ||
+		(account != "" && (verification == nil || !verification.Verified) && !knownBeneficiary(account, enriched.BehaviorPattern.FrequentBeneficiaries))
+	if firstTime {
+		signals = append(signals, model.ScamSignal{Code: model.ScamFirstTimePayee, Weight: scamFirstTimePayeeWeight})
+	}
+
+	amount := entityAmount(intent.Entities)
+	average := enriched.BehaviorPattern.AverageAmount
+	large := amount > 0 && (amount >= sd.cfg.LargeAmount || (average > 0 && amount > average*scamUnusualMultiple))
+	if large {
+		signals = append(signals, model.ScamSignal{Code: model.ScamLargeAmount, Weight: scamLargeAmountWeight})
+	}
+	if firstTime && large && coached {
+		signals = append(signals, model.ScamSignal{Code: model.ScamCombination, Weight: scamCombinationWeight})
+	}
+
+	sort.Slice(signals, func(i, j int) bool {
+		if signals[i].Weight != signals[j].Weight {
+			return signals[i].Weight > signals[j].Weight
+		}
+		return signals[i].Code < signals[j].Code
+	})
+	assessment := &model.ScamAssessment{Level: "LOW", Signals: signals}
+	for _, signal := range signals {
+		assessment.Score += signal.Weight
+	}
+	assessment.Score = math.Min(math.Round(assessment.Score*100)/100, 1)
+	switch {
+	case assessment.Score >= sd.cfg.HighScore:
+		assessment.Level = "HIGH"
+	case assessment.Score >= sd.cfg.WarnScore:
+		assessment.Level = "MEDIUM"
+	}
+	if assessment.Level != "LOW" {
+		assessment.Warning = scamWarning(signals)
+	}
+	return assessment
+}
+
+// Raise adds a scam assessment to a transfer's risk indicators and to the task context
+// the agents and the MCP server's step-up policy see. A HIGH assessment makes the
+// transfer high risk.
+func (sd *ScamDetector) Raise(enriched *model.EnrichedContext, assessment *model.ScamAssessment) {
+	risk := &enriched.RiskIndicators
+	risk.ScamRisk = assessment.Score
+	switch {
+	case assessment.Level == "HIGH":
+		risk.OverallRisk = "HIGH"
+	case assessment.Level == "MEDIUM" && risk.OverallRisk == "LOW":
+		risk.OverallRisk = "MEDIUM"
+	}
+
+	codes := make([]string, len(assessment.Signals))
+	for i, signal := range assessment.Signals {
+		codes[i] = signal.Code
+	}
+	enriched.Metadata["scam_score"] = assessment.Score
+	enriched.Metadata["scam_signals"] = codes
+}
+
+// scanScamSignals returns the signals a message raises, one per code
+func scanScamSignals(text string) []model.ScamSignal {
+	lower := strings.ToLower(text)
+	var signals []model.ScamSignal
+	for _, p := range scamPatterns {
+		if match := p.re.FindString(lower); match != "" {
+			signals = append(signals, model.ScamSignal{Code: p.code, Weight: p.weight, Evidence: match})
+		}
+	}
+	return signals
+}
+
+// scamWarning picks the warning for the most telling signal; a risky payment with
+// only urgency behind it gets the general one
+func scamWarning(signals []model.ScamSignal) string {
+	for _, w := range scamWarnings {
+		for _, signal := range signals {
+			if signal.Code == w.code {
+				return w.warning + " " + scamWarningSuffix
+			}
+		}
+	}
+	return "You haven't paid this account before. Check the details with the person directly before you confirm. " + scamWarningSuffix
+}
+
+// knownBeneficiary reports whether an account is one the user pays often. Frequent
+// beneficiaries are masked to their last four characters.
+func knownBeneficiary(account string, frequent []string) bool {
+	if len(account) < 4 {
+		return false
+	}
+	suffix := account[len(account)-4:]
+	for _, beneficiary := range frequent {
+		if beneficiary == account || strings.HasSuffix(beneficiary, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scamSessionKey keys signals by session, and by user for requests made without one
+func scamSessionKey(userID, sessionID string) string {
+	if sessionID != "" {
+		return "session:" + sessionID
+	}
+	return "user:" + userID
+}
+
+// cleanup drops signals older than the window
+func (sd *ScamDetector) cleanup() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	window := time.Duration(sd.cfg.WindowMinutes) * time.Minute
+	for range ticker.C {
+		sd.mu.Lock()
+		for key, seen := range sd.sessions {
+			for code, s := range seen {
+				if time.Since(s.at) > window {
+					delete(seen, code)
+				}
+			}
+			if len(seen) == 0 {
+				delete(sd.sessions, key)
+			}
+		}
+		sd.mu.Unlock()
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrSessionConflict is returned for a session ID that is bound to another user
+var ErrSessionConflict = errors.New("session belongs to another user")
+
+// ErrSessionNotFound is returned for a session neither side knows
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrInvalidSessionID is returned for a session ID the MCP server does not accept
+var ErrInvalidSessionID = errors.New("invalid session ID")
+
+// sessionContextFields are the request context fields kept on the shared session, so a
+// tenant, device or the user's time zone and locale given once applies to the rest of
+// the conversation on both sides
+var sessionContextFields = []string{"tenant_id", "device_id", "device_trust", "location", "phone", "timezone", "locale"}
+
+const (
+	// sessionResyncInterval is how long a binding is trusted before it is bound again
+	sessionResyncInterval = 5 * time.Minute
+
+	// sessionSyncTimeout bounds pushing the outcome of a request to the MCP server
+	sessionSyncTimeout = 5 * time.Second
+
+	// maxBoundSessions is when expired bindings are swept
+	maxBoundSessions = 10000
+)
+
+// SessionBinder keeps the AI Skin and the MCP server on one conversation identity. The
+// skin picks the session ID, binds it on the MCP server before a request runs and
+// pushes the outcome afterwards; context either side holds flows to the other.
+type SessionBinder struct {
+	mcpClient *MCPClient
+
+	mu       sync.Mutex
+	sessions map[string]*model.SessionBinding
+}
+
+// NewSessionBinder creates a session binder
+func NewSessionBinder(mcpClient *MCPClient) *SessionBinder {
+	return &SessionBinder{
+		mcpClient: mcpClient,
+		sessions:  make(map[string]*model.SessionBinding),
+	}
+}
+
+// Bind returns the shared session for a request, creating a session ID when the
+// client sent none. The request's session context is pushed to the MCP server and the
+// session's context comes back. While the MCP server cannot be reached the request
+// proceeds on the skin's own binding; submitting its task binds the ID there too.
+func (sb *SessionBinder) Bind(ctx context.Context, userID, channel, sessionID string, reqContext map[string]interface{}) (*model.SessionBinding, error) {
+	if sessionID == "" {
+		sessionID = ids.New(ids.Session)
+	}
+	pushed := sessionContext(reqContext)
+
+	sb.mu.Lock()
+	cached, ok := sb.sessions[sessionID]
+	if ok && time.Now().After(cached.ExpiresAt) {
+		delete(sb.sessions, sessionID)
+		ok = false
+	}
+	if ok && cached.UserID != userID {
+		sb.mu.Unlock()
+		return nil, ErrSessionConflict
+	}
+	if ok && !cached.SyncedAt.IsZero() && time.Since(cached.SyncedAt) < sessionResyncInterval && cached.Channel == channel && !addsContext(cached.Context, pushed) {
+		binding := copyBinding(cached)
+		sb.mu.Unlock()
+		return binding, nil
+	}
+	sb.mu.Unlock()
+
+	binding, err := sb.mcpClient.BindSession(ctx, sessionID, userID, channel, pushed)
+	if errors.Is(err, ErrSessionConflict) || errors.Is(err, ErrInvalidSessionID) {
+		return nil, err
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("session_id", sessionID).Msg("Failed to bind session on MCP server")
+		binding = &model.SessionBinding{
+			SessionID:   sessionID,
+			UserID:      userID,
+			Channel:     channel,
+			Context:     pushed,
+			TaskHistory: []string{},
+			ExpiresAt:   time.Now().Add(sessionResyncInterval),
+		}
+		if ok {
+			binding.Context = mergeContext(cached.Context, pushed)
+			binding.TaskHistory = cached.TaskHistory
+		}
+	}
+
+	sb.store(binding)
+	return copyBinding(binding), nil
+}
+
+// Record pushes the outcome of a request to the MCP server in the background, so the
+// session there knows what the user last asked and the skin learns the tasks it ran
+func (sb *SessionBinder) Record(ctx context.Context, binding *model.SessionBinding, status string) {
+	state := map[string]interface{}{
+		"last_status":    status,
+		"last_active_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sessionSyncTimeout)
+	go func() {
+		defer cancel()
+		synced, err := sb.mcpClient.BindSession(ctx, binding.SessionID, binding.UserID, binding.Channel, state)
+		if err != nil {
+			log.Warn().Err(err).Str("session_id", binding.SessionID).Msg("Failed to sync session to MCP server")
+			return
+		}
+		sb.store(synced)
+	}()
+}
+
+// Get returns a session as the MCP server has it, or the skin's own binding while the
+// MCP server cannot be reached
+func (sb *SessionBinder) Get(ctx context.Context, sessionID string) (*model.SessionBinding, error) {
+	binding, err := sb.mcpClient.GetSession(ctx, sessionID)
+	if err == nil {
+		sb.store(binding)
+		return copyBinding(binding), nil
+	}
+
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if errors.Is(err, ErrSessionNotFound) {
+		delete(sb.sessions, sessionID)
+		return nil, err
+	}
+	if cached, ok := sb.sessions[sessionID]; ok {
+		return copyBinding(cached), nil
+	}
+	return nil, err
+}
+
+// ApplySessionContext fills the session context fields a request left out from its
+// session
+func ApplySessionContext(reqContext map[string]interface{}, binding *model.SessionBinding) map[string]interface{} {
+	for _, field := range sessionContextFields {
+		v, ok := binding.Context[field]
+		if !ok {
+			continue
+		}
+		if _, set := reqContext[field]; set {
+			continue
+		}
+		if reqContext == nil {
+			reqContext = make(map[string]interface{})
+		}
+		reqContext[field] = v
+	}
+	return reqContext
+}
+
+func (sb *SessionBinder) store(binding *model.SessionBinding) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if len(sb.sessions) >= maxBoundSessions {
+		now := time.Now()
+		for id, b := range sb.sessions {
+			if now.After(b.ExpiresAt) {
+				delete(sb.sessions, id)
+			}
+		}
+	}
+	sb.sessions[binding.SessionID] = binding
+}
+
+// sessionContext picks the fields of a request's context that belong to the session
+func sessionContext(reqContext map[string]interface{}) map[string]interface{} {
+	pushed := make(map[string]interface{})
+	for _, field := range sessionContextFields {
+		if v, ok := reqContext[field]; ok && v != nil && v != "" {
+			pushed[field] = v
+		}
+	}
+	return pushed
+}
+
+// addsContext reports whether pushed holds a field the session does not have yet, or
+// has with another value
+func addsContext(current, pushed map[string]interface{}) bool {
+	for k, v := range pushed {
+		if cur, ok := current[k]; !ok || !reflect.DeepEqual(cur, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeContext(current, pushed map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(current)+len(pushed))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range pushed {
+		merged[k] = v
+	}
+	return merged
+}
+
+func copyBinding(binding *model.SessionBinding) *model.SessionBinding {
+	c := *binding
+	c.Context = mergeContext(binding.Context, nil)
+	c.TaskHistory = append([]string(nil), binding.TaskHistory...)
+	return &c
+}
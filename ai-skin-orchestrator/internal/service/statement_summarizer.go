@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/tz"
+	"github.com/rs/zerolog/log"
+)
+
+// PromptStatementSummary narrates the aggregates of a statement summary
+const PromptStatementSummary = "statement_summary"
+
+// Statement periods a summary can cover; "last 10 days" is LAST_10_DAYS
+const (
+	PeriodLastMonth = "LAST_MONTH"
+	PeriodThisMonth = "THIS_MONTH"
+	PeriodLastWeek  = "LAST_WEEK"
+	PeriodThisWeek  = "THIS_WEEK"
+)
+
+const (
+	// defaultSummaryDays is the period of a summary that names none
+	defaultSummaryDays = 30
+	// maxStatementDays is the longest history a statement is asked for
+	maxStatementDays = 90
+)
+
+var (
+	// periodDaysRegex matches a period in days, as in "last 10 days" or "past 2 weeks"
+	periodDaysRegex = regexp.MustCompile(`\b(?:last|past|previous)\s+(\d{1,3})\s+(days?|weeks?)\b`)
+	// spendingQuestionRegex matches asking where money went, as in "how much did I
+	// spend last month" or "where did my money go"
+	spendingQuestionRegex = regexp.MustCompile(`\bhow\s+much\s+(?:did|have|do)\s+i\s+(?:spend|spent)\b|\bwhere\s+(?:did|has|does)\s+(?:all\s+)?my\s+money\s+(?:go|gone)\b|\b(?:breakdown|break\s+down)\s+(?:of\s+)?my\s+(?:spending|expenses)\b|\bmy\s+(?:spending|expenses)\s+(?:last|this|in|over|for)\b`)
+	// summaryNumberRegex finds numbers, which a narrative must take from the aggregates
+	summaryNumberRegex = regexp.MustCompile(`\d+`)
+)
+
+// summaryCategoryWords place a payment in a spending category by words in its
+// description, as Banking Integrations does for budgets. Rail transfers come last so a
+// "UPI payment to Swiggy" is food.
+var summaryCategoryWords = []struct {
+	category string
+	words    []string
+}{
+	{"GROCERIES", []string{"grocery", "groceries", "supermarket", "vegetables", "kirana"}},
+	{"RENT", []string{"rent", "landlord", "lease"}},
+	{"BILLS", []string{"bill", "electricity", "water", "gas", "broadband", "mobile", "recharge", "dth", "insurance", "emi"}},
+	{"FOOD", []string{"food", "restaurant", "dinner", "lunch", "breakfast", "cafe", "swiggy", "zomato"}},
+	{"TRAVEL", []string{"travel", "flight", "train", "cab", "uber", "ola", "fuel", "petrol", "hotel", "trip"}},
+	{"SHOPPING", []string{"shopping", "amazon", "flipkart", "myntra", "clothes", "electronics"}},
+	{"ENTERTAINMENT", []string{"movie", "netflix", "spotify", "concert", "games", "subscription"}},
+	{"TRANSFERS", []string{"neft", "rtgs", "imps", "upi", "transfer"}},
+}
+
+// isStatementSummary reports whether lowercased input asks for a summary of spending
+// rather than the transactions themselves
+func isStatementSummary(input string) bool {
+	if spendingQuestionRegex.MatchString(input) {
+		return true
+	}
+	return strings.Contains(input, "summar") &&
+		containsAny(input, []string{"spend", "spent", "statement", "transaction", "expense", "account", "money", "month", "week", "days"})
+}
+
+// statementPeriodName returns the period lowercased text names, or "" when it names none
+func statementPeriodName(text string) string {
+	if matches := periodDaysRegex.FindStringSubmatch(text); len(matches) > 2 {
+		n, _ := strconv.Atoi(matches[1])
+		if strings.HasPrefix(matches[2], "week") {
+			n *= 7
+		}
+		if n >= 1 {
+			return fmt.Sprintf("LAST_%d_DAYS", min(n, maxStatementDays))
+		}
+	}
+	switch {
+	case containsAny(text, []string{"last month", "previous month", "past month"}):
+		return PeriodLastMonth
+	case containsAny(text, []string{"this month", "month so far", "month to date"}):
+		return PeriodThisMonth
+	case containsAny(text, []string{"last week", "previous week", "past week"}):
+		return PeriodLastWeek
+	case containsAny(text, []string{"this week", "week so far"}):
+		return PeriodThisWeek
+	}
+	return ""
+}
+
+// StatementSummarizer answers SUMMARIZE_STATEMENT: it works out the period asked
+// about, computes the totals, top payees and category split of the statement's
+// transactions in it, and narrates them. With SUMMARY_LLM_NARRATIVE the LLM writes the
+// narrative from the computed figures only; one that is empty, runs long or brings in
+// numbers the figures do not have is replaced by the template.
+type StatementSummarizer struct {
+	cfg        *config.SummaryConfig
+	location   *time.Location
+	llmService *LLMService
+	prompts    *PromptService
+	guard      *PromptGuard
+}
+
+// NewStatementSummarizer creates a new statement summarizer. Periods are in the
+// response time zone unless the request names the user's.
+func NewStatementSummarizer(cfg *config.SummaryConfig, response *config.ResponseConfig, llmService *LLMService, prompts *PromptService, guard *PromptGuard) *StatementSummarizer {
+	return &StatementSummarizer{
+		cfg:        cfg,
+		location:   tz.LoadOr(response.Timezone, time.UTC),
+		llmService: llmService,
+		prompts:    prompts,
+		guard:      guard,
+	}
+}
+
+// Period resolves the period a summary intent asks about as of now. The period entity
+// may be a name such as LAST_MONTH or, from the LLM, words such as "last month";
+// without one the request's text is read, and the last 30 days are the default.
+func (ss *StatementSummarizer) Period(req *model.UserRequest, intent *model.Intent, now time.Time) model.StatementPeriod {
+	now = ss.userTime(req, now)
+	raw, _ := intent.Entities["period"].(string)
+	name := strings.ToUpper(strings.TrimSpace(raw))
+	if _, ok := resolvePeriod(name, now); !ok {
+		name = statementPeriodName(strings.ToLower(raw))
+		if name == "" {
+			name = statementPeriodName(strings.ToLower(intent.OriginalText))
+		}
+	}
+	if _, ok := resolvePeriod(name, now); !ok {
+		name = fmt.Sprintf("LAST_%d_DAYS", defaultSummaryDays)
+	}
+	period, _ := resolvePeriod(name, now)
+	return period
+}
+
+// userTime is now in the user's time zone, when the request names it
+func (ss *StatementSummarizer) userTime(req *model.UserRequest, now time.Time) time.Time {
+	location := ss.location
+	if name, ok := req.Context["timezone"].(string); ok {
+		location = tz.LoadOr(name, location)
+	}
+	return now.In(location)
+}
+
+// resolvePeriod returns the bounds of a named period as of now
+func resolvePeriod(name string, now time.Time) (model.StatementPeriod, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	period := model.StatementPeriod{Name: name, To: now}
+	switch name {
+	case PeriodLastMonth:
+		period.From, period.To = monthStart.AddDate(0, -1, 0), monthStart
+	case PeriodThisMonth:
+		period.From = monthStart
+	case PeriodLastWeek:
+		period.From, period.To = monday.AddDate(0, 0, -7), monday
+	case PeriodThisWeek:
+		period.From = monday
+	default:
+		var days int
+		if _, err := fmt.Sscanf(name, "LAST_%d_DAYS", &days); err != nil || days < 1 || days > maxStatementDays {
+			return model.StatementPeriod{}, false
+		}
+		period.From = today.AddDate(0, 0, -(days - 1))
+	}
+	return period, true
+}
+
+// StatementIntent returns the GET_STATEMENT intent that fetches a summary's
+// transactions, asking for enough days of history to cover the period
+func (ss *StatementSummarizer) StatementIntent(intent *model.Intent, period model.StatementPeriod) *model.Intent {
+	entities := copyEntities(intent.Entities)
+	delete(entities, "period")
+	entities["days"] = min(int(math.Ceil(time.Since(period.From).Hours()/24)), maxStatementDays)
+
+	return &model.Intent{
+		Type:         model.IntentGetStatement,
+		Confidence:   intent.Confidence,
+		Entities:     entities,
+		OriginalText: intent.OriginalText,
+		Metadata:     intent.Metadata,
+	}
+}
+
+// Summarize computes the aggregates of a statement result's transactions in the
+// period and narrates them
+func (ss *StatementSummarizer) Summarize(ctx context.Context, req *model.UserRequest, period model.StatementPeriod, result map[string]interface{}) *model.StatementSummary {
+	summary := &model.StatementSummary{
+		Period:     period,
+		Aggregates: ss.aggregate(result["transactions"], period),
+		NarratedBy: model.NarratedByTemplate,
+	}
+	summary.AccountID, _ = result["account_id"].(string)
+
+	figures := summaryFigures(period, &summary.Aggregates)
+	summary.Narrative = templateNarrative(period, &summary.Aggregates)
+	if ss.cfg.LLMNarrative && req.Input != "" && !req.RulesOnly && summary.Aggregates.Transactions > 0 && ss.llmService.Enabled() && budgetFrom(ctx).useLLM(model.BudgetStageStatementNarrative) {
+		if narrative, err := ss.narrate(ctx, req, figures); err != nil {
+			log.Debug().Err(err).Str("user_id", req.UserID).Msg("Statement summary not narrated by the LLM, using its template")
+		} else {
+			summary.Narrative, summary.NarratedBy = narrative, model.NarratedByLLM
+		}
+	}
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("period", period.Name).
+		Int("transactions", summary.Aggregates.Transactions).
+		Str("narrated_by", summary.NarratedBy).
+		Msg("Statement summarized")
+	return summary
+}
+
+// aggregate adds up the transactions dated in the period. Transactions without a date
+// are counted, as the statement was asked for the period.
+func (ss *StatementSummarizer) aggregate(raw interface{}, period model.StatementPeriod) model.StatementAggregates {
+	agg := model.StatementAggregates{TopPayees: []model.PayeeSpend{}, Categories: []model.CategorySpend{}}
+	payees := make(map[string]*model.PayeeSpend)
+	categories := make(map[string]*model.CategorySpend)
+
+	transactions, _ := raw.([]interface{})
+	for _, t := range transactions {
+		txn, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		amount, ok := txn["amount"].(float64)
+		if !ok || amount <= 0 {
+			continue
+		}
+		if date, ok := txn["date"].(string); ok {
+			if at, err := time.Parse(time.RFC3339Nano, date); err == nil && (at.Before(period.From) || !at.Before(period.To)) {
+				continue
+			}
+		}
+
+		agg.Transactions++
+		kind, _ := txn["type"].(string)
+		if strings.ToUpper(kind) == "CREDIT" {
+			agg.Credits++
+			agg.TotalIn += amount
+			continue
+		}
+		agg.Debits++
+		agg.TotalSpent += amount
+
+		description, _ := txn["description"].(string)
+		description = strings.TrimSpace(description)
+		if description == "" {
+			description = "Unlabelled payments"
+		}
+		payee := payees[strings.ToLower(description)]
+		if payee == nil {
+			payee = &model.PayeeSpend{Payee: description}
+			payees[strings.ToLower(description)] = payee
+		}
+		payee.Amount += amount
+		payee.Count++
+
+		name := summaryCategory(description)
+		category := categories[name]
+		if category == nil {
+			category = &model.CategorySpend{Category: name}
+			categories[name] = category
+		}
+		category.Amount += amount
+		category.Count++
+	}
+
+	for _, payee := range payees {
+		payee.Amount = roundMoney(payee.Amount)
+		agg.TopPayees = append(agg.TopPayees, *payee)
+	}
+	sort.Slice(agg.TopPayees, func(i, j int) bool {
+		a, b := agg.TopPayees[i], agg.TopPayees[j]
+		return a.Amount > b.Amount || (a.Amount == b.Amount && a.Payee < b.Payee)
+	})
+	if len(agg.TopPayees) > ss.cfg.TopPayees {
+		agg.TopPayees = agg.TopPayees[:ss.cfg.TopPayees]
+	}
+
+	for _, category := range categories {
+		category.Amount = roundMoney(category.Amount)
+		category.Share = math.Round(category.Amount/agg.TotalSpent*1000) / 10
+		agg.Categories = append(agg.Categories, *category)
+	}
+	sort.Slice(agg.Categories, func(i, j int) bool {
+		a, b := agg.Categories[i], agg.Categories[j]
+		return a.Amount > b.Amount || (a.Amount == b.Amount && a.Category < b.Category)
+	})
+
+	agg.TotalSpent = roundMoney(agg.TotalSpent)
+	agg.TotalIn = roundMoney(agg.TotalIn)
+	agg.Net = roundMoney(agg.TotalIn - agg.TotalSpent)
+	return agg
+}
+
+// summaryCategory places a payment in a spending category by its description
+func summaryCategory(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !('a' <= r && r <= 'z')
+	})
+	for _, group := range summaryCategoryWords {
+		for _, word := range words {
+			for _, keyword := range group.words {
+				if word == keyword {
+					return group.category
+				}
+			}
+		}
+	}
+	return "OTHER"
+}
+
+// narrate has the LLM word the figures for the request. The narrative is refused when
+// it is empty, runs long, quotes no figure or has numbers the figures do not.
+func (ss *StatementSummarizer) narrate(ctx context.Context, req *model.UserRequest, figures string) (string, error) {
+	prompt, err := ss.prompts.Render(PromptStatementSummary, model.PromptVars{
+		UserInput: ss.guard.SanitizeUserInput(req.Input).Text,
+		Extra:     map[string]interface{}{"figures": figures},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The request's budget bounds the call when it is tighter than the timeout
+	ctx, cancelBudget := budgetFrom(ctx).llmContext(ctx)
+	defer cancelBudget()
+	ctx, cancel := context.WithTimeout(WithQuotaUser(ctx, req.UserID), time.Duration(ss.cfg.TimeoutMs)*time.Millisecond)
+	defer cancel()
+	text, err := ss.llmService.CallLLMWithSystem(ctx, ss.llmService.Settings(LLMPurposeChat, req.LLM), "", prompt.Text)
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.Trim(strings.TrimSpace(text), `"`)
+	switch {
+	case text == "":
+		return "", fmt.Errorf("empty narrative")
+	case len(text) > len(figures)+200:
+		return "", fmt.Errorf("narrative is %d characters, too long for its figures", len(text))
+	}
+	numbers := summaryNumberRegex.FindAllString(text, -1)
+	if len(numbers) == 0 {
+		return "", fmt.Errorf("narrative quotes none of the figures")
+	}
+	for _, number := range numbers {
+		if !strings.Contains(figures, number) {
+			return "", fmt.Errorf("narrative has the number %s, which is not in the figures", number)
+		}
+	}
+	return text, nil
+}
+
+// summaryFigures lists the aggregates as the narrative may quote them
+func summaryFigures(period model.StatementPeriod, agg *model.StatementAggregates) string {
+	lines := []string{
+		"Period: " + describePeriod(period),
+		fmt.Sprintf("Spent: %s in %s", formatRupees(agg.TotalSpent), plural(agg.Debits, "payment")),
+		fmt.Sprintf("Received: %s in %s", formatRupees(agg.TotalIn), plural(agg.Credits, "credit")),
+	}
+	switch {
+	case agg.Net > 0:
+		lines = append(lines, fmt.Sprintf("Net: %s more came in than went out", formatRupees(agg.Net)))
+	case agg.Net < 0:
+		lines = append(lines, fmt.Sprintf("Net: %s more went out than came in", formatRupees(-agg.Net)))
+	}
+	if len(agg.TopPayees) > 0 {
+		payees := make([]string, 0, len(agg.TopPayees))
+		for _, p := range agg.TopPayees {
+			payees = append(payees, fmt.Sprintf("%s %s (%s)", p.Payee, formatRupees(p.Amount), plural(p.Count, "payment")))
+		}
+		lines = append(lines, "Top payees: "+strings.Join(payees, "; "))
+	}
+	if len(agg.Categories) > 0 {
+		categories := make([]string, 0, len(agg.Categories))
+		for _, c := range agg.Categories {
+			categories = append(categories, fmt.Sprintf("%s %s (%s)", categoryLabel(c.Category), formatRupees(c.Amount), formatShare(c.Share)))
+		}
+		lines = append(lines, "Categories: "+strings.Join(categories, "; "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// templateNarrative words the aggregates without the LLM
+func templateNarrative(period model.StatementPeriod, agg *model.StatementAggregates) string {
+	when := describePeriod(period)
+	switch {
+	case agg.Transactions == 0:
+		return fmt.Sprintf("You had no transactions from %s.", when)
+	case agg.Debits == 0:
+		return fmt.Sprintf("From %s you received %s and spent nothing.", when, formatRupees(agg.TotalIn))
+	}
+
+	text := fmt.Sprintf("From %s you spent %s in %s", when, formatRupees(agg.TotalSpent), plural(agg.Debits, "payment"))
+	switch {
+	case agg.Net > 0:
+		text += fmt.Sprintf(" and received %s, so %s more came in than went out.", formatRupees(agg.TotalIn), formatRupees(agg.Net))
+	case agg.Net < 0 && agg.Credits > 0:
+		text += fmt.Sprintf(" and received %s, so %s more went out than came in.", formatRupees(agg.TotalIn), formatRupees(-agg.Net))
+	default:
+		text += "."
+	}
+
+	top := agg.Categories[0]
+	text += fmt.Sprintf(" Most of it went on %s (%s of your spending)", strings.ToLower(categoryLabel(top.Category)), formatShare(top.Share))
+	if len(agg.TopPayees) > 0 {
+		text += fmt.Sprintf(", and your biggest payee was %s at %s", agg.TopPayees[0].Payee, formatRupees(agg.TopPayees[0].Amount))
+	}
+	return text + "."
+}
+
+// describePeriod names a period's first and last days, e.g. "1 Sep to 30 Sep 2026"
+func describePeriod(period model.StatementPeriod) string {
+	last := period.To.Add(-time.Nanosecond)
+	if last.Year() == period.From.Year() && last.YearDay() == period.From.YearDay() {
+		return last.Format("2 Jan 2006")
+	}
+	return period.From.Format("2 Jan") + " to " + last.Format("2 Jan 2006")
+}
+
+// categoryLabel names a spending category, e.g. "Groceries"
+func categoryLabel(category string) string {
+	return capitalize(strings.ToLower(category))
+}
+
+// formatShare formats a percentage without trailing zeros, e.g. "42.5%"
+func formatShare(share float64) string {
+	return strconv.FormatFloat(share, 'f', -1, 64) + "%"
+}
+
+// plural counts things, e.g. "1 payment" or "3 payments"
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// roundMoney rounds an amount to paise
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+	"github.com/aibanking/shared/channel"
+)
+
+// minSuggestions is how many quick replies a response gets at least, topped up with
+// the general ones
+const minSuggestions = 2
+
+// Quick replies offered after responses. The rule-based parser understands their
+// messages, so chips work when the LLM is unavailable; "Send again" is resolved
+// against the session's last transfer.
+var (
+	replyCheckBalance = model.QuickReply{Label: "Check balance", Message: "Check my balance", Intent: model.IntentCheckBalance}
+	replyStatement    = model.QuickReply{Label: "View statement", Message: "Show my statement", Intent: model.IntentGetStatement}
+	replySummary      = model.QuickReply{Label: "Spending summary", Message: "Summarize my spending this month", Intent: model.IntentSummarizeStatement}
+	replySendMoney    = model.QuickReply{Label: "Send money", Message: "Send money to ", Intent: model.IntentTransferNEFT, NeedsInput: true}
+	replyShareReceipt = model.QuickReply{Label: "Share receipt", Message: "Share the receipt for my last transfer", Intent: model.IntentShareReceipt}
+	replyWhy          = model.QuickReply{Label: "Why?", Message: "Why was it rejected?", Intent: model.IntentWhyRejected}
+	replyRetry        = model.QuickReply{Label: "Try again", Message: "Retry", Intent: model.IntentRetryLast}
+	replyInsights     = model.QuickReply{Label: "Spending insights", Message: "How can I save more?", Intent: model.IntentGetInsights}
+	replyBudget       = model.QuickReply{Label: "Budget status", Message: "How am I doing on my budget?", Intent: model.IntentBudgetStatus}
+	replySetBudget    = model.QuickReply{Label: "Set a budget", Message: "Alert me when I spend over ", Intent: model.IntentSetBudget, NeedsInput: true}
+	replyPayees       = model.QuickReply{Label: "My payees", Message: "Show my beneficiaries", Intent: model.IntentListBeneficiaries}
+	replyAddPayee     = model.QuickReply{Label: "Add a payee", Message: "Add a beneficiary ", Intent: model.IntentAddBeneficiary, NeedsInput: true}
+	replyCreditScore  = model.QuickReply{Label: "Credit score", Message: "What is my credit score?", Intent: model.IntentCreditScore}
+	replyLoan         = model.QuickReply{Label: "Apply for a loan", Message: "I want a personal loan of ", Intent: model.IntentApplyLoan, NeedsInput: true}
+)
+
+// generalReplies top up the suggestions of any response
+var generalReplies = []model.QuickReply{replyCheckBalance, replyStatement, replySendMoney}
+
+// intentReplies are the quick replies after an intent went through, most useful first
+var intentReplies = map[model.IntentType][]model.QuickReply{
+	model.IntentCheckBalance:       {replyStatement, replySendMoney, replyInsights},
+	model.IntentGetStatement:       {replySummary, replyCheckBalance, replyInsights},
+	model.IntentSummarizeStatement: {replyStatement, replySetBudget, replyInsights},
+	model.IntentListBeneficiaries:  {replySendMoney, replyAddPayee},
+	model.IntentRequestMoney:       {replyCheckBalance, replyStatement},
+	model.IntentShareReceipt:       {replyCheckBalance, replyStatement},
+	model.IntentExportTransactions: {replySummary, replyCheckBalance},
+	model.IntentSetBudget:          {replyBudget, replyStatement},
+	model.IntentBudgetStatus:       {replyInsights, replyStatement, replySetBudget},
+	model.IntentApplyLoan:          {replyCreditScore, replyCheckBalance},
+	model.IntentCreditScore:        {replyLoan, replyInsights},
+	model.IntentGetInsights:        {replySetBudget, replyBudget, replyStatement},
+	model.IntentSetPreference:      {replySendMoney, replyCheckBalance},
+	model.IntentWhyRejected:        {replyCheckBalance, replyStatement},
+}
+
+// SuggestionGenerator picks the quick replies a chat UI shows after a response: what
+// usually follows the intent, given how it turned out and what the session did last.
+// Replies for intents that are unavailable right now are left out.
+type SuggestionGenerator struct {
+	cfg          *config.ResponseConfig
+	decisions    *DecisionStore
+	capabilities *CapabilityService
+}
+
+// NewSuggestionGenerator creates a new suggestion generator
+func NewSuggestionGenerator(cfg *config.ResponseConfig, decisions *DecisionStore, capabilities *CapabilityService) *SuggestionGenerator {
+	return &SuggestionGenerator{
+		cfg:          cfg,
+		decisions:    decisions,
+		capabilities: capabilities,
+	}
+}
+
+// Suggest returns between two and RESPONSE_MAX_SUGGESTIONS quick replies for a
+// response to intent. Responses that wait for the user to authenticate, confirm or
+// finish on an app screen get none, nor does phone banking, which has no chips.
+func (sg *SuggestionGenerator) Suggest(ctx context.Context, req *model.UserRequest, intent *model.Intent, resp *model.MergedResponse) []model.QuickReply {
+	if sg.cfg.MaxSuggestions <= 0 || channel.Channel(req.Channel) == channel.IVR {
+		return nil
+	}
+
+	var candidates []model.QuickReply
+	switch status := decisionStatus(resp); status {
+	case "PENDING", taskStatusAwaitingAuth, taskStatusAwaitingConfirmation, model.StatusHandoff:
+		return nil
+	case "REJECTED", "FAILED", "CONFLICT":
+		candidates = sg.afterRejection(intent)
+	default:
+		candidates = sg.afterSuccess(intent)
+	}
+
+	// A rejected transfer, loan or payee can be retried after a look at the balance
+	if !isActionIntent(intent.Type) && intent.Type != model.IntentUnknown {
+		if last := sg.decisions.LastAction(req.UserID, req.SessionID); last != nil && last.Status == "REJECTED" {
+			retry := replyRetry
+			retry.Label = "Retry " + actionNoun(last.Intent)
+			candidates = append([]model.QuickReply{retry}, candidates...)
+		}
+	}
+
+	unavailable := sg.unavailable(ctx)
+	suggestions := make([]model.QuickReply, 0, sg.cfg.MaxSuggestions)
+	seen := make(map[string]bool)
+	add := func(replies []model.QuickReply, limit int) {
+		for _, reply := range replies {
+			if len(suggestions) >= limit {
+				return
+			}
+			// Offering what the user just did is no help, unless it moves money again
+			if (reply.Intent == intent.Type && !isTransferIntent(intent.Type)) || seen[reply.Label] || unavailable[reply.Intent] {
+				continue
+			}
+			seen[reply.Label] = true
+			suggestions = append(suggestions, reply)
+		}
+	}
+	add(candidates, sg.cfg.MaxSuggestions)
+	// The general replies top up a response with few of its own
+	add(generalReplies, min(minSuggestions, sg.cfg.MaxSuggestions))
+	return suggestions
+}
+
+// afterSuccess returns the replies for an intent that went through
+func (sg *SuggestionGenerator) afterSuccess(intent *model.Intent) []model.QuickReply {
+	switch {
+	case isTransferIntent(intent.Type):
+		again := model.QuickReply{Label: "Send again", Message: "Send again", Intent: intent.Type}
+		return []model.QuickReply{again, replyShareReceipt, replyCheckBalance, replyStatement}
+	case intent.Type == model.IntentAddBeneficiary:
+		send := replySendMoney
+		if name, _ := intent.Entities["name"].(string); name != "" {
+			send.Label = "Send money to " + name
+			send.Message = "Send money to " + name + " "
+		}
+		return []model.QuickReply{send, replyPayees}
+	}
+	return intentReplies[intent.Type]
+}
+
+// afterRejection returns the replies for an intent that did not go through: why it
+// failed, a retry for an action, and what else might help
+func (sg *SuggestionGenerator) afterRejection(intent *model.Intent) []model.QuickReply {
+	switch {
+	case intent.Type == model.IntentUnknown:
+		return []model.QuickReply{replyCheckBalance, replyStatement, replySendMoney, replyPayees}
+	case isActionIntent(intent.Type):
+		replies := []model.QuickReply{replyWhy, replyRetry}
+		if intent.Type == model.IntentApplyLoan {
+			return append(replies, replyCreditScore)
+		}
+		return append(replies, replyCheckBalance)
+	}
+	return []model.QuickReply{replyWhy}
+}
+
+// unavailable returns the intents whose agents are known to be down
+func (sg *SuggestionGenerator) unavailable(ctx context.Context) map[model.IntentType]bool {
+	unavailable := make(map[model.IntentType]bool)
+	for _, c := range sg.capabilities.GetCapabilities(ctx).Capabilities {
+		if c.Status == model.CapabilityUnavailable {
+			unavailable[model.IntentType(c.Intent)] = true
+		}
+	}
+	return unavailable
+}
+
+// actionNoun names a retryable action for a chip label
+func actionNoun(t model.IntentType) string {
+	switch {
+	case isTransferIntent(t):
+		return "transfer"
+	case t == model.IntentApplyLoan:
+		return "loan application"
+	case t == model.IntentAddBeneficiary:
+		return "adding payee"
+	}
+	return strings.ToLower(string(t))
+}
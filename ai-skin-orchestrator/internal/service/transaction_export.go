@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// Periods only an export can cover, on top of the statement periods; statements look
+// back at most 90 days. Financial years run April to March.
+const (
+	PeriodThisYear          = "THIS_YEAR"
+	PeriodLastYear          = "LAST_YEAR"
+	PeriodThisFinancialYear = "THIS_FINANCIAL_YEAR"
+	PeriodLastFinancialYear = "LAST_FINANCIAL_YEAR"
+)
+
+// exportDateLayout is how an export's from and to dates are sent to the agents
+const exportDateLayout = "2006-01-02"
+
+var (
+	// exportFormatRegex matches a named export format
+	exportFormatRegex = regexp.MustCompile(`\b(ofx|qif|csv)\b`)
+	// exportMonthsRegex matches a period in months, as in "last 6 months"
+	exportMonthsRegex = regexp.MustCompile(`\b(?:last|past|previous)\s+(\d{1,2})\s+months?\b`)
+	// exportToolFormats pick the format a named tool imports best
+	exportToolFormats = []struct {
+		format string
+		words  []string
+	}{
+		{"OFX", []string{"quicken", "quickbooks", "gnucash", "moneydance", "tally"}},
+		{"CSV", []string{"excel", "spreadsheet", "google sheets"}},
+	}
+)
+
+// isExportRequest reports whether lowercased input asks for transactions as a file
+// to import elsewhere, rather than to see them
+func isExportRequest(input string) bool {
+	if exportFormatRegex.MatchString(input) {
+		return true
+	}
+	for _, tool := range exportToolFormats {
+		if containsAny(input, tool.words) {
+			return true
+		}
+	}
+	return containsAny(input, []string{"export", "download"}) &&
+		containsAny(input, []string{"transaction", "statement", "history", "passbook", "spending"})
+}
+
+// extractExportEntities pulls the format an export is asked for, by name or by the
+// tool it is for, and its period; the agent defaults the format to CSV
+func extractExportEntities(input string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	if matches := exportFormatRegex.FindStringSubmatch(input); len(matches) > 1 {
+		entities["format"] = strings.ToUpper(matches[1])
+	} else {
+		for _, tool := range exportToolFormats {
+			if containsAny(input, tool.words) {
+				entities["format"] = tool.format
+				break
+			}
+		}
+	}
+	if period := exportPeriodName(input); period != "" {
+		entities["period"] = period
+	}
+	return entities
+}
+
+// exportPeriodName returns the period lowercased text names, or "" when it names none.
+// Unlike a statement's, an export's period in days is not capped; Banking
+// Integrations refuses one that is too long.
+func exportPeriodName(text string) string {
+	financialYear := containsAny(text, []string{"financial year", "fiscal year", "tax year", " fy"})
+	switch {
+	case financialYear && containsAny(text, []string{"last", "previous", "past"}):
+		return PeriodLastFinancialYear
+	case financialYear:
+		return PeriodThisFinancialYear
+	case containsAny(text, []string{"last year", "previous year", "past year"}):
+		return PeriodLastYear
+	case containsAny(text, []string{"this year", "year so far", "year to date"}):
+		return PeriodThisYear
+	}
+	if matches := exportMonthsRegex.FindStringSubmatch(text); len(matches) > 1 {
+		if n, _ := strconv.Atoi(matches[1]); n >= 1 {
+			return fmt.Sprintf("LAST_%d_MONTHS", n)
+		}
+	}
+	if matches := periodDaysRegex.FindStringSubmatch(text); len(matches) > 2 {
+		n, _ := strconv.Atoi(matches[1])
+		if strings.HasPrefix(matches[2], "week") {
+			n *= 7
+		}
+		if n >= 1 {
+			return fmt.Sprintf("LAST_%d_DAYS", n)
+		}
+	}
+	return statementPeriodName(text)
+}
+
+// resolveExportPeriod returns the bounds of a named export period as of now
+func resolveExportPeriod(name string, now time.Time) (model.StatementPeriod, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	financialYearStart := time.Date(now.Year(), time.April, 1, 0, 0, 0, 0, now.Location())
+	if now.Before(financialYearStart) {
+		financialYearStart = financialYearStart.AddDate(-1, 0, 0)
+	}
+
+	period := model.StatementPeriod{Name: name, To: now}
+	var n int
+	switch {
+	case name == PeriodThisYear:
+		period.From = yearStart
+	case name == PeriodLastYear:
+		period.From, period.To = yearStart.AddDate(-1, 0, 0), yearStart
+	case name == PeriodThisFinancialYear:
+		period.From = financialYearStart
+	case name == PeriodLastFinancialYear:
+		period.From, period.To = financialYearStart.AddDate(-1, 0, 0), financialYearStart
+	case scanPeriod(name, "LAST_%d_MONTHS", &n):
+		period.From = today.AddDate(0, -n, 1)
+	case scanPeriod(name, "LAST_%d_DAYS", &n):
+		period.From = today.AddDate(0, 0, -(n - 1))
+	default:
+		return resolvePeriod(name, now)
+	}
+	return period, true
+}
+
+// scanPeriod reports whether name is the format's period, with a count of at least 1
+func scanPeriod(name, format string, n *int) bool {
+	_, err := fmt.Sscanf(name, format, n)
+	return err == nil && *n >= 1
+}
+
+// ExportRange sets the from and to dates of an export intent, inclusive and in the
+// user's time zone, from the period it names; without one the last 30 days are
+// exported. Dates the intent already has, say from the LLM, are kept.
+func (ss *StatementSummarizer) ExportRange(req *model.UserRequest, intent *model.Intent, now time.Time) {
+	from, _ := intent.Entities["from"].(string)
+	to, _ := intent.Entities["to"].(string)
+	if isExportDate(from) && isExportDate(to) {
+		return
+	}
+
+	now = ss.userTime(req, now)
+	raw, _ := intent.Entities["period"].(string)
+	name := strings.ToUpper(strings.TrimSpace(raw))
+	if _, ok := resolveExportPeriod(name, now); !ok {
+		name = exportPeriodName(strings.ToLower(raw))
+		if name == "" {
+			name = exportPeriodName(strings.ToLower(intent.OriginalText))
+		}
+	}
+	period, ok := resolveExportPeriod(name, now)
+	if !ok {
+		period, _ = resolvePeriod(fmt.Sprintf("LAST_%d_DAYS", defaultSummaryDays), now)
+	}
+
+	if intent.Entities == nil {
+		intent.Entities = make(map[string]interface{})
+	}
+	intent.Entities["period"] = period.Name
+	intent.Entities["from"] = period.From.Format(exportDateLayout)
+	intent.Entities["to"] = period.To.Add(-time.Nanosecond).Format(exportDateLayout)
+}
+
+// isExportDate reports whether an entity is a date as exports take them
+func isExportDate(value string) bool {
+	_, err := time.Parse(exportDateLayout, value)
+	return err == nil
+}
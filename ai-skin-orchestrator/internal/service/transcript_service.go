@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aibanking/ai-skin-orchestrator/internal/config"
+	"github.com/aibanking/ai-skin-orchestrator/internal/model"
+)
+
+// ErrTranscriptNotFound is returned for a session with no recorded conversation that
+// the MCP server does not know either
+var ErrTranscriptNotFound = errors.New("no conversation recorded for session")
+
+var (
+	// transcriptAccountRegex finds account and phone numbers in free text
+	transcriptAccountRegex = regexp.MustCompile(`\b\d{9,18}\b`)
+
+	// transcriptHandleRegex finds email addresses and UPI IDs in free text
+	transcriptHandleRegex = regexp.MustCompile(`\b[a-zA-Z0-9._%+-]{2,}@([a-zA-Z0-9.-]{2,})\b`)
+)
+
+// transcriptMaskedFields are entity fields that are masked whatever they hold
+var transcriptMaskedFields = []string{"account", "phone", "mobile", "email", "upi"}
+
+// TranscriptService renders a session for support staff investigating a complaint:
+// what the user said, what was understood, the tasks submitted and what the agents
+// answered, in the order it happened. It is built from the conversation events the
+// AnalyticsService keeps, so it covers what retention has not purged.
+type TranscriptService struct {
+	cfg       *config.TranscriptConfig
+	analytics *AnalyticsService
+	sessions  *SessionBinder
+	mcpClient *MCPClient
+}
+
+// NewTranscriptService creates a transcript service
+func NewTranscriptService(cfg *config.TranscriptConfig, analytics *AnalyticsService, sessions *SessionBinder, mcpClient *MCPClient) *TranscriptService {
+	return &TranscriptService{
+		cfg:       cfg,
+		analytics: analytics,
+		sessions:  sessions,
+		mcpClient: mcpClient,
+	}
+}
+
+// Role returns the transcript role of an API key, or "" when it may not read transcripts
+func (ts *TranscriptService) Role(apiKey string) string {
+	if apiKey == "" {
+		return ""
+	}
+	for _, key := range ts.cfg.InvestigatorKeys {
+		if key == apiKey {
+			return model.TranscriptRoleInvestigator
+		}
+	}
+	for _, key := range ts.cfg.SupportKeys {
+		if key == apiKey {
+			return model.TranscriptRoleSupport
+		}
+	}
+	return ""
+}
+
+// Transcript renders a session for a role. Tasks that had not finished when the user
+// was answered are looked up on the MCP server for their current status, as are tasks
+// of the session the AI Skin has no record of.
+func (ts *TranscriptService) Transcript(ctx context.Context, sessionID, role string) (*model.Transcript, error) {
+	events := ts.analytics.SessionEvents(sessionID)
+	binding, err := ts.sessions.Get(ctx, sessionID)
+	var warnings []string
+	switch {
+	case errors.Is(err, ErrSessionNotFound):
+		// Expired; the recorded events are all there is
+	case err != nil && len(events) == 0:
+		return nil, err
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("session could not be read from the MCP server: %v", err))
+	}
+	if len(events) == 0 && binding == nil {
+		return nil, ErrTranscriptNotFound
+	}
+
+	transcript := &model.Transcript{
+		SessionID:   sessionID,
+		Role:        role,
+		Masked:      role != model.TranscriptRoleInvestigator,
+		Turns:       len(events),
+		Entries:     []model.TranscriptEntry{},
+		GeneratedAt: time.Now(),
+	}
+	if binding != nil {
+		transcript.UserID, transcript.Channel = binding.UserID, binding.Channel
+	} else {
+		transcript.UserID, transcript.Channel = events[0].UserID, events[0].Channel
+	}
+
+	seen := make(map[string]bool)
+	for i, event := range events {
+		entries, unread := ts.turn(ctx, i+1, event)
+		transcript.Entries = append(transcript.Entries, entries...)
+		warnings = append(warnings, unread...)
+		for _, outcome := range event.Outcomes {
+			seen[outcome.TaskID] = true
+		}
+	}
+	if binding != nil {
+		for _, taskID := range binding.TaskHistory {
+			if seen[taskID] {
+				continue
+			}
+			entries, err := ts.unrecordedTask(ctx, taskID, transcript.GeneratedAt)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("task %s could not be read: %v", taskID, err))
+				continue
+			}
+			transcript.Entries = append(transcript.Entries, entries...)
+		}
+	}
+
+	// Stable, so the entries of a request that share a time keep their order
+	sort.SliceStable(transcript.Entries, func(a, b int) bool {
+		return transcript.Entries[a].At.Before(transcript.Entries[b].At)
+	})
+	if transcript.Masked {
+		for i := range transcript.Entries {
+			maskTranscriptEntry(&transcript.Entries[i])
+		}
+	}
+	transcript.Warnings = warnings
+	return transcript, nil
+}
+
+// turn renders one conversation event. Events are recorded once the user has been
+// answered, so the message and intent take the time of the first agent outcome when
+// there is one. It also returns the tasks whose current status could not be read.
+func (ts *TranscriptService) turn(ctx context.Context, turn int, event model.ConversationEvent) ([]model.TranscriptEntry, []string) {
+	started := event.At
+	for _, outcome := range event.Outcomes {
+		if !outcome.At.IsZero() && outcome.At.Before(started) {
+			started = outcome.At
+		}
+	}
+
+	var entries []model.TranscriptEntry
+	var warnings []string
+	if event.Input != "" {
+		entries = append(entries, model.TranscriptEntry{At: started, Kind: model.TranscriptMessage, Turn: turn, Text: event.Input})
+	}
+	if event.Kind == model.EventIntent {
+		entries = append(entries, model.TranscriptEntry{
+			At:         started,
+			Kind:       model.TranscriptIntent,
+			Turn:       turn,
+			Intent:     event.Intent,
+			Confidence: event.Confidence,
+			ParsedBy:   event.ParsedBy,
+			Entities:   event.Entities,
+		})
+	}
+	for _, outcome := range event.Outcomes {
+		at := outcome.At
+		if at.IsZero() {
+			at = event.At
+		}
+		if outcome.TaskID != "" {
+			entries = append(entries, model.TranscriptEntry{At: at, Kind: model.TranscriptTask, Turn: turn, TaskID: outcome.TaskID, Intent: event.Intent, AgentType: outcome.AgentType})
+		}
+		entry := model.TranscriptEntry{
+			At:          at,
+			Kind:        model.TranscriptOutcome,
+			Turn:        turn,
+			TaskID:      outcome.TaskID,
+			AgentType:   outcome.AgentType,
+			Status:      outcome.Status,
+			RiskScore:   outcome.RiskScore,
+			Explanation: outcome.Explanation,
+		}
+		if outcome.TaskID != "" && !taskFinished(outcome.Status) {
+			if result, err := ts.mcpClient.fetchResult(ctx, outcome.TaskID); err != nil {
+				warnings = append(warnings, fmt.Sprintf("current status of task %s could not be read: %v", outcome.TaskID, err))
+			} else if result.Status != outcome.Status {
+				entry.CurrentStatus = result.Status
+			}
+		}
+		entries = append(entries, entry)
+	}
+	entries = append(entries, model.TranscriptEntry{
+		At:          event.At,
+		Kind:        model.TranscriptStatus,
+		Turn:        turn,
+		Status:      event.Status,
+		Fallback:    event.Fallback,
+		ChallengeID: event.ChallengeID,
+		ConfirmedAt: event.ConfirmedAt,
+	})
+	if event.Reply != "" {
+		entries = append(entries, model.TranscriptEntry{At: event.At, Kind: model.TranscriptReply, Turn: turn, Text: event.Reply})
+	}
+	return entries, warnings
+}
+
+// unrecordedTask renders a task of the session the AI Skin has no event for, as after
+// a restart, at the time it completed, or at now while it is still running
+func (ts *TranscriptService) unrecordedTask(ctx context.Context, taskID string, now time.Time) ([]model.TranscriptEntry, error) {
+	result, err := ts.mcpClient.fetchResult(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	at := now
+	if result.CompletedAt != nil {
+		at = *result.CompletedAt
+	}
+	return []model.TranscriptEntry{
+		{At: at, Kind: model.TranscriptTask, TaskID: taskID},
+		{At: at, Kind: model.TranscriptOutcome, TaskID: taskID, Status: result.Status, RiskScore: result.RiskScore, Explanation: result.Explanation},
+	}, nil
+}
+
+// taskFinished reports whether a task status is final. Outcomes of tasks that were
+// still running, held or scheduled when the user was answered are not.
+func taskFinished(status string) bool {
+	switch status {
+	case "COMPLETED", "FAILED", "REJECTED", "CANCELLED":
+		return true
+	}
+	return false
+}
+
+// maskTranscriptEntry masks the account and phone numbers, emails and UPI IDs in an
+// entry's text and entities
+func maskTranscriptEntry(entry *model.TranscriptEntry) {
+	entry.Text = maskTranscriptText(entry.Text)
+	entry.Explanation = maskTranscriptText(entry.Explanation)
+	if entry.Entities != nil {
+		entry.Entities = maskTranscriptValue("", entry.Entities).(map[string]interface{})
+	}
+}
+
+// maskTranscriptValue masks a value held under key. Fields named for accounts,
+// phones, emails or UPI IDs are masked whole; other text is searched for them.
+// Maps are copied, so recorded events are left as they are.
+func maskTranscriptValue(key string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(value))
+		for k, field := range value {
+			masked[k] = maskTranscriptValue(k, field)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(value))
+		for i, item := range value {
+			masked[i] = maskTranscriptValue(key, item)
+		}
+		return masked
+	case string:
+		lower := strings.ToLower(key)
+		for _, field := range transcriptMaskedFields {
+			if strings.Contains(lower, field) {
+				return maskTranscriptField(value)
+			}
+		}
+		return maskTranscriptText(value)
+	}
+	return v
+}
+
+// maskTranscriptField masks a value that is PII whole: numbers keep their last four
+// digits and handles their domain
+func maskTranscriptField(value string) string {
+	if at := strings.LastIndex(value, "@"); at > 0 {
+		return "****" + value[at:]
+	}
+	if masked := maskAccountNumber(value); masked != value {
+		return masked
+	}
+	return "****"
+}
+
+// maskTranscriptText masks the account and phone numbers, emails and UPI IDs in text
+func maskTranscriptText(text string) string {
+	if text == "" {
+		return text
+	}
+	text = transcriptHandleRegex.ReplaceAllString(text, "****@$1")
+	return transcriptAccountRegex.ReplaceAllStringFunc(text, maskAccountNumber)
+}
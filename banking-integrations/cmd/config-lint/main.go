@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/shared/profile"
+)
+
+// Prints the effective configuration Banking Integrations would start with, where each
+// value came from, and the settings that are missing or unsafe for the environment.
+// Exits 1 when the service would refuse to start.
+func main() {
+	profile.Lint("banking-integrations", func() (string, []profile.Setting, []profile.Problem, error) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return cfg.Environment, config.Profile().Settings(), cfg.Validate(), nil
+	})
+}
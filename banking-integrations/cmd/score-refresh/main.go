@@ -0,0 +1,95 @@
+// Command score-refresh starts a credit-score refresh across the customer base on a
+// running banking-integrations service via the admin scoring API, and by default
+// waits for it to finish and prints the run with its drift report.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:7000", "Banking integrations base URL")
+	apiKey := flag.String("api-key", os.Getenv("SCORE_REFRESH_API_KEY"), "API key with the back-office role, sent in the X-API-Key header (default $SCORE_REFRESH_API_KEY)")
+	wait := flag.Bool("wait", true, "Wait for the run to finish and print its result")
+	timeout := flag.Duration("timeout", 30*time.Minute, "How long to wait for the run")
+	flag.Parse()
+
+	if err := run(*baseURL, *apiKey, *wait, *timeout); err != nil {
+		fmt.Fprintln(os.Stderr, "score-refresh:", err)
+		os.Exit(1)
+	}
+}
+
+func run(baseURL, apiKey string, wait bool, timeout time.Duration) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var started model.ScoringRun
+	if err := call(client, http.MethodPost, baseURL+"/api/v1/admin/scoring/runs?trigger=cli", apiKey, &started); err != nil {
+		return err
+	}
+	if !wait {
+		return printJSON(started)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var current model.ScoringRun
+		if err := call(client, http.MethodGet, baseURL+"/api/v1/admin/scoring/runs/"+started.RunID, apiKey, &current); err != nil {
+			return err
+		}
+		if current.Status != model.ScoringRunRunning {
+			if err := printJSON(current); err != nil {
+				return err
+			}
+			if current.Status == model.ScoringRunFailed {
+				return fmt.Errorf("run %s failed", current.RunID)
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("run %s still running after %s (%d/%d scored)", current.RunID, timeout, current.Scored, current.Users)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func call(client *http.Client, method, url, apiKey string, out interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,76 @@
+// Command seed loads a JSON test data fixture into a running
+// banking-integrations service via the admin seed API.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+func main() {
+	file := flag.String("file", "fixtures/demo.json", "Path to the JSON fixture")
+	baseURL := flag.String("url", "http://localhost:7000", "Banking integrations base URL")
+	apiKey := flag.String("api-key", os.Getenv("SEED_API_KEY"), "API key with the back-office role, sent in the X-API-Key header (default $SEED_API_KEY)")
+	replace := flag.Bool("replace", false, "Drop previously seeded data before loading")
+	clear := flag.Bool("clear", false, "Clear all seeded data instead of loading")
+	flag.Parse()
+
+	if err := run(*file, *baseURL, *apiKey, *replace, *clear); err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		os.Exit(1)
+	}
+}
+
+func run(file, baseURL, apiKey string, replace, clear bool) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	url := baseURL + "/api/v1/admin/seed"
+
+	var req *http.Request
+	var err error
+	if clear {
+		req, err = http.NewRequest(http.MethodDelete, url, nil)
+	} else {
+		raw, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return fmt.Errorf("failed to read fixture: %w", readErr)
+		}
+
+		// Validate locally so typos are reported before anything is sent
+		var fixture model.SeedFixture
+		if err := json.Unmarshal(raw, &fixture); err != nil {
+			return fmt.Errorf("invalid fixture %s: %w", file, err)
+		}
+
+		if replace {
+			url += "?replace=true"
+		}
+		req, err = http.NewRequest(http.MethodPost, url, bytes.NewReader(raw))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	fmt.Println(string(bytes.TrimSpace(body)))
+	return nil
+}
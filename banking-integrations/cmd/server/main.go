@@ -15,10 +15,17 @@ import (
 	"github.com/aibanking/banking-integrations/internal/router"
 	"github.com/aibanking/banking-integrations/internal/service"
 	"github.com/aibanking/banking-integrations/internal/utils"
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/tz"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	// Store and send every time in UTC, whatever time zone the host runs in
+	tz.UseUTC()
+
 	// Initialize configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -27,24 +34,115 @@ func main() {
 
 	utils.InitLogger(cfg.Logging.Level, cfg.Logging.Format)
 
-	log.Info().Msg("Starting Banking Integrations Service (Layer 5)")
+	// Fail fast on settings that are missing or unsafe for the environment
+	problems := cfg.Validate()
+	for _, p := range problems {
+		log.Warn().Str("setting", p.Key).Str("severity", p.Severity).Msg(p.Message)
+	}
+	if profile.HasErrors(problems) {
+		log.Fatal().Str("environment", cfg.Environment).Msg("Invalid configuration, run cmd/config-lint for details")
+	}
+
+	log.Info().Str("environment", cfg.Environment).Msg("Starting Banking Integrations Service (Layer 5)")
 
 	// Initialize services
-	mbService := service.NewMBService()
-	nbService := service.NewNBService()
-	dwhService := service.NewDWHService(&cfg.DWH)
-	bankingGateway := service.NewBankingGateway(mbService, nbService, dwhService)
+	connectors, err := service.NewChannelConnectors(&cfg.Connectors)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure channel connectors")
+	}
+	seedStore := service.NewSeedStore()
+	dwhService := service.NewDWHService(&cfg.DWH, seedStore)
+	sandboxService := service.NewSandboxService(cfg.Sandbox.OpeningBalance)
+	preferenceStore := service.NewPreferenceStore()
+	bankingCalendar, err := service.NewBankingCalendar(&cfg.Calendar)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load banking calendar")
+	}
+	paymentMessages := service.NewPaymentMessageService(&cfg.ISO20022, seedStore, bankingCalendar)
+	paymentRequests := service.NewPaymentRequestService(&cfg.PaymentRequests, seedStore)
+	payeeDirectory, err := service.NewPayeeDirectory(&cfg.PayeeDirectory)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load payee directory")
+	}
+	// In demo mode every scenario's users are seeded before the first request
+	var demoController *controller.DemoController
+	if cfg.Demo.Enabled {
+		scenarios, err := demo.Load(cfg.Demo.ScenariosDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load demo scenarios")
+		}
+		seeder, err := service.NewDemoSeeder(scenarios, seedStore)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load demo scenarios")
+		}
+		if _, err := seeder.Seed(context.Background()); err != nil {
+			log.Fatal().Err(err).Msg("Failed to seed demo scenarios")
+		}
+		demoController = controller.NewDemoController(seeder)
+		log.Warn().Int("scenarios", len(scenarios.List())).Msg("Demo mode is on; demo scenario users are seeded")
+	}
+	fieldMasker, err := service.NewFieldMasker(&cfg.Masking, &cfg.RBAC, cfg.Security.APIKeyHeader)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load masking rules")
+	}
+	bankingGateway := service.NewBankingGateway(connectors, dwhService, sandboxService, seedStore, preferenceStore, bankingCalendar, paymentMessages, paymentRequests, payeeDirectory)
+	scoreStore := service.NewScoreStore(cfg.Scoring.KeepRuns)
+	scoreJob := service.NewScoreJob(dwhService, service.NewCreditScorer(&cfg.Scoring), scoreStore, cfg.Scoring.Concurrency)
+	notifications := service.NewNotificationService(preferenceStore)
+	insightsDigest := service.NewInsightsDigest(dwhService, service.NewInsightsClient(&cfg.Insights), notifications, cfg.Insights.KeepRuns)
+	accountBook := service.NewAccountBook(connectors, seedStore, dwhService)
+	adjustmentService := service.NewAdjustmentService(&cfg.Adjustments, accountBook)
+	accountStatus := service.NewAccountStatusService(&cfg.AccountStatus, accountBook, adjustmentService)
+	bankingGateway.SetAccountStatus(accountStatus)
+	webhooks := service.NewWebhookService(&cfg.Webhooks)
+	bankingGateway.SetWebhooks(webhooks)
+	accountStatus.SetWebhooks(webhooks)
+	receipts := service.NewReceiptService(&cfg.Receipts, dwhService)
+	exports := service.NewExportService(&cfg.Exports, cfg.ISO20022.BankIFSC, dwhService, seedStore, bankingCalendar)
+	budgets := service.NewBudgetService(&cfg.Budgets, dwhService, notifications, bankingCalendar)
+	bankingGateway.SetBudgets(budgets)
+	bankingGateway.SetLedger(service.NewTransferLedger(&cfg.Transfers))
 
 	// Initialize controller
-	bankingController := controller.NewBankingController(bankingGateway)
+	bankingController := controller.NewBankingController(bankingGateway, fieldMasker)
+	scoringController := controller.NewScoringController(scoreJob, scoreStore)
+	adjustmentController := controller.NewAdjustmentController(adjustmentService)
+	paymentRequestController := controller.NewPaymentRequestController(paymentRequests)
+	payeeController := controller.NewPayeeController(payeeDirectory)
+	accountStatusController := controller.NewAccountStatusController(accountStatus)
+	notificationController := controller.NewNotificationController(notifications, insightsDigest)
+	webhookController := controller.NewWebhookController(webhooks)
+	receiptController := controller.NewReceiptController(receipts)
+	exportController := controller.NewExportController(exports)
+	budgetController := controller.NewBudgetController(budgets)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter()
+	recovery := middleware.NewRecovery(cfg.Recovery)
+	auditLogger, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatal().Err(err).Str("sink", cfg.Audit.Sink).Msg("Failed to open access log")
+	}
+	accessLog := middleware.NewAccessLog(auditLogger, cfg.Security.APIKeyHeader)
 
 	// Initialize router
-	appRouter := router.NewRouter(bankingController, rateLimiter)
+	appRouter := router.NewRouter(bankingController, scoringController, adjustmentController, paymentRequestController, payeeController, accountStatusController, notificationController, webhookController, receiptController, exportController, budgetController, rateLimiter, recovery, middleware.NewBackOfficeAuth(&cfg.RBAC), middleware.NewAgentAuth(&cfg.RBAC), accessLog, demoController)
 	r := appRouter.SetupRoutes()
 
+	// Schedule the credit-score refresh, if configured
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.Scoring.IntervalHours > 0 {
+		scoreJob.Schedule(jobCtx, time.Duration(cfg.Scoring.IntervalHours)*time.Hour)
+		log.Info().Int("interval_hours", cfg.Scoring.IntervalHours).Msg("Credit-score refresh scheduled")
+	}
+	if cfg.Insights.DigestIntervalHours > 0 {
+		insightsDigest.Schedule(jobCtx, time.Duration(cfg.Insights.DigestIntervalHours)*time.Hour)
+		log.Info().Int("interval_hours", cfg.Insights.DigestIntervalHours).Msg("Insights digest scheduled")
+	}
+	go config.Profile().WatchSecrets(jobCtx, cfg.Secrets.RefreshInterval)
+	go dwhService.WatchReplicas(jobCtx)
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
@@ -82,6 +180,11 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// After the server, so the last requests it finished are recorded
+	if err := auditLogger.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close access log")
+	}
+
 	log.Info().Msg("Banking Integrations Service exited")
 }
 
@@ -3,7 +3,7 @@ module github.com/aibanking/banking-integrations
 go 1.21
 
 require (
-	github.com/google/uuid v1.5.0
+	github.com/aibanking/shared v0.0.0
 	github.com/gorilla/mux v1.8.1
 	github.com/joho/godotenv v1.5.1
 	github.com/rs/zerolog v1.31.0
@@ -33,3 +33,5 @@ require (
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/aibanking/shared => ../shared
@@ -1,20 +1,49 @@
 package config
 
 import (
-	"log"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/recovery"
+	"github.com/aibanking/shared/secrets"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	DWH      DWHConfig
-	Logging  LoggingConfig
-	Security SecurityConfig
+	Environment     string // dev, staging or prod; see Validate
+	Server          ServerConfig
+	Database        DatabaseConfig
+	DWH             DWHConfig
+	Sandbox         SandboxConfig
+	Connectors      ConnectorsConfig
+	Logging         LoggingConfig
+	Recovery        recovery.Config
+	Security        SecurityConfig
+	RBAC            RBACConfig
+	Masking         MaskingConfig
+	Adjustments     AdjustmentsConfig
+	AccountStatus   AccountStatusConfig
+	Scoring         ScoringConfig
+	Insights        InsightsConfig
+	Calendar        CalendarConfig
+	ISO20022        ISO20022Config
+	PaymentRequests PaymentRequestsConfig
+	PayeeDirectory  PayeeDirectoryConfig
+	Webhooks        WebhooksConfig
+	Receipts        ReceiptsConfig
+	Exports         ExportsConfig
+	Budgets         BudgetsConfig
+	Transfers       TransfersConfig
+	Secrets         secrets.Config
+	Audit           audit.Config
+	Demo            demo.Config
 }
 
 // ServerConfig holds server configuration
@@ -46,6 +75,34 @@ type DWHConfig struct {
 	DBName   string
 	SSLMode  string
 	Enabled  bool
+
+	// Read replicas of the primary at Host. Writes always go to the primary; reads are
+	// spread over the replicas, falling back to the primary when no replica is within
+	// MaxReplicaLag or has replayed the latest write to the data being read.
+	ReplicaHosts         []string
+	MaxReplicaLag        int // Seconds
+	ReplicaCheckInterval int // Seconds between replica lag checks
+}
+
+// SandboxConfig holds configuration for simulated (demo) operations
+type SandboxConfig struct {
+	OpeningBalance float64
+}
+
+// ConnectorsConfig selects the connector behind each banking channel
+type ConnectorsConfig struct {
+	MB  ConnectorConfig
+	NB  ConnectorConfig
+	API ConnectorConfig
+}
+
+// ConnectorConfig configures one channel's connector
+type ConnectorConfig struct {
+	Type        string // mock, rest or iso8583; empty leaves the channel unsupported
+	URL         string // REST base URL, or host:port of the ISO 8583 switch
+	APIKey      string
+	Timeout     int    // Seconds
+	MappingFile string // REST field mapping; the built-in mapping when empty
 }
 
 // LoggingConfig holds logging configuration
@@ -61,12 +118,134 @@ type SecurityConfig struct {
 	RateLimitRPS int
 }
 
+// RBACConfig grants roles to API keys. Endpoints that need a role are refused to
+// every key when no key has been granted it.
+type RBACConfig struct {
+	BackOffice map[string]string // API key -> operator ID
+	Agents     map[string]string // API key -> agent ID; only agents report fraud signals
+	Partners   map[string]string // API key -> partner ID; always served the API channel's masking
+}
+
+// MaskingConfig holds the masking of customer data in statement, balance and
+// transaction-history responses, per channel
+type MaskingConfig struct {
+	Enabled   bool
+	RulesFile string // Optional JSON of channel -> field -> action, replacing the built-in rules of each channel it names
+}
+
+// AdjustmentsConfig holds limits for back-office balance adjustments
+type AdjustmentsConfig struct {
+	MaxAmount    float64 // Largest adjustment, either way
+	PendingHours int     // Unapproved adjustments expire after this long
+}
+
+// AccountStatusConfig holds the fraud outcomes that freeze or lock accounts
+type AccountStatusConfig struct {
+	AutoFreeze  bool    // Restrict accounts on high-severity fraud signals
+	FreezeScore float64 // Fraud score at or above which the account is frozen
+	LockScore   float64 // Fraud score at or above which the account is locked
+}
+
+// ScoringConfig holds configuration for the batch credit-score refresh job
+type ScoringConfig struct {
+	AgentURL      string // Scoring Agent (Layer 3) base URL
+	APIKey        string
+	Timeout       int // Seconds per user
+	Concurrency   int // Users scored at once
+	IntervalHours int // Scheduled refresh interval; 0 disables the schedule
+	KeepRuns      int // Completed runs kept for drift comparison and lookups
+}
+
+// InsightsConfig holds configuration for the insights digest sent to every user
+type InsightsConfig struct {
+	AgentURL            string // Insights Agent (Layer 3) base URL
+	APIKey              string
+	Timeout             int // Seconds per user
+	DigestIntervalHours int // Hours between scheduled digests; 0 disables the schedule
+	KeepRuns            int // Digest runs kept for lookups
+}
+
+// CalendarConfig holds the banking calendar: bank time, holidays and rail windows
+type CalendarConfig struct {
+	Timezone     string
+	HolidaysFile string // Optional JSON list of {"date", "name"} on top of the national holidays
+	RTGSWindow   string // HH:MM-HH:MM on working days
+	NEFTWindow   string // HH:MM-HH:MM on working days; the close is the cutoff
+}
+
+// ISO20022Config holds settings for the ISO 20022 messages NEFT and RTGS transfers go out as
+type ISO20022Config struct {
+	Enabled  bool
+	BankIFSC string // IFSC of this bank, the debtor agent of every outgoing transfer
+}
+
+// PaymentRequestsConfig holds settings for UPI payment requests
+type PaymentRequestsConfig struct {
+	VPAHandle   string  // PSP handle of the VPAs given to users without one, e.g. user@aibank
+	ExpiryHours int     // Unpaid requests expire after this long
+	MaxAmount   float64 // Largest amount that can be requested
+}
+
+// PayeeDirectoryConfig holds the directory of billers and merchants
+type PayeeDirectoryConfig struct {
+	File string // Optional JSON list of payees on top of the built-in ones
+}
+
+// WebhooksConfig holds delivery settings for webhook subscriptions
+type WebhooksConfig struct {
+	Timeout        int  // Seconds per delivery attempt
+	MaxAttempts    int  // Attempts before a delivery is dead-lettered
+	RetryBackoff   int  // Seconds before the first retry; doubles on each one
+	MaxBackoff     int  // Longest wait between retries, in seconds
+	KeepDeliveries int  // Deliveries kept for lookups; dead letters are kept until retried
+	AllowHTTP      bool // Accept plain-HTTP endpoints, not just HTTPS
+}
+
+// ReceiptsConfig holds the shareable receipt links of transfers. A link carries the
+// transaction it is for and when it expires, sealed with the signing key, so anyone
+// holding it can see the redacted receipt until then without logging in.
+type ReceiptsConfig struct {
+	Enabled       bool
+	SigningKey    string // Seals links; changing it invalidates every link given out
+	BaseURL       string // Public address of this service that links point at
+	TTLMinutes    int    // How long a link works unless the request asks for less
+	MaxTTLMinutes int    // Longest a request may ask for
+	LookbackDays  int    // How far back "my last transfer" and receipts of past transfers look
+}
+
+// ExportsConfig holds transaction exports for personal finance tools. A download link
+// carries the user, date range and format sealed with the signing key, like a receipt
+// link, so it downloads without logging in until it expires.
+type ExportsConfig struct {
+	Enabled      bool
+	SigningKey   string // Seals download links; changing it invalidates every link given out
+	BaseURL      string // Public address of this service that links point at
+	TTLMinutes   int    // How long a download link works unless the request asks for less
+	MaxRangeDays int    // Longest date range one export may cover
+	WindowDays   int    // Days of history read at a time while an export streams
+}
+
+// BudgetsConfig holds users' monthly spending budgets. Budgets are soft: payments
+// are never refused, users are notified as spending crosses each threshold.
+type BudgetsConfig struct {
+	Enabled    bool
+	Thresholds []int // Percentages of a budget that send an alert unless the user picks their own
+}
+
+// TransfersConfig holds how repeated transfers are recognised
+type TransfersConfig struct {
+	IdempotencyHours int // Hours a transfer's idempotency key answers repeats and lookups
+}
+
 var AppConfig *Config
 
 // LoadConfig loads configuration from environment
 func LoadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	// Load .env.<APP_ENV> and .env if they exist
+	settings = profile.New()
+	environment := settings.LoadEnvironment(godotenv.Read, godotenv.Load)
+	if err := settings.LoadSecrets(); err != nil {
+		return nil, err
 	}
 
 	viper.SetDefault("SERVER_PORT", "7000")
@@ -85,14 +264,78 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("DWH_NAME", "dwh")
 	viper.SetDefault("DWH_SSLMODE", "disable")
 	viper.SetDefault("DWH_ENABLED", "false")
+	viper.SetDefault("DWH_REPLICA_HOSTS", "")
+	viper.SetDefault("DWH_MAX_REPLICA_LAG", "5")
+	viper.SetDefault("DWH_REPLICA_CHECK_INTERVAL", "1")
+	viper.SetDefault("SANDBOX_OPENING_BALANCE", "150000")
+	viper.SetDefault("CONNECTOR_MB_TYPE", "mock")
+	viper.SetDefault("CONNECTOR_NB_TYPE", "mock")
 	viper.SetDefault("LOGGING_LEVEL", "info")
 	viper.SetDefault("LOGGING_FORMAT", "json")
+	viper.SetDefault("RECOVERY_EXPOSE_DETAILS", "false")
+	viper.SetDefault("RECOVERY_STACK_LOG_INTERVAL", "300")
+	viper.SetDefault("RECOVERY_KEEP_FINGERPRINTS", "100")
 	viper.SetDefault("SECURITY_API_KEY_HEADER", "X-API-Key")
 	viper.SetDefault("SECURITY_RATE_LIMIT_RPS", "100")
+	viper.SetDefault("CALENDAR_TIMEZONE", "Asia/Kolkata")
+	viper.SetDefault("CALENDAR_RTGS_WINDOW", "07:00-18:00")
+	viper.SetDefault("CALENDAR_NEFT_WINDOW", "08:00-18:00")
+	viper.SetDefault("ISO20022_ENABLED", "true")
+	viper.SetDefault("ISO20022_BANK_IFSC", "AIBK0000001")
+	viper.SetDefault("PAYMENT_REQUEST_VPA_HANDLE", "aibank")
+	viper.SetDefault("PAYMENT_REQUEST_EXPIRY_HOURS", "24")
+	viper.SetDefault("PAYMENT_REQUEST_MAX_AMOUNT", "100000")
+	viper.SetDefault("ADJUSTMENT_MAX_AMOUNT", "1000000")
+	viper.SetDefault("ADJUSTMENT_PENDING_HOURS", "24")
+	viper.SetDefault("ACCOUNT_AUTO_FREEZE", "true")
+	viper.SetDefault("ACCOUNT_FREEZE_FRAUD_SCORE", "0.85")
+	viper.SetDefault("ACCOUNT_LOCK_FRAUD_SCORE", "0.95")
+	viper.SetDefault("SCORING_AGENT_URL", "http://localhost:8005")
+	viper.SetDefault("SCORING_AGENT_API_KEY", "test-api-key")
+	viper.SetDefault("SCORING_JOB_CONCURRENCY", "8")
+	viper.SetDefault("SCORING_JOB_INTERVAL_HOURS", "0")
+	viper.SetDefault("SCORING_JOB_KEEP_RUNS", "10")
+	viper.SetDefault("INSIGHTS_AGENT_URL", "http://localhost:8006")
+	viper.SetDefault("INSIGHTS_AGENT_API_KEY", "test-api-key")
+	viper.SetDefault("INSIGHTS_DIGEST_INTERVAL_HOURS", "0")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", "6")
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF_SECONDS", "10")
+	viper.SetDefault("WEBHOOK_MAX_BACKOFF_SECONDS", "3600")
+	viper.SetDefault("WEBHOOK_KEEP_DELIVERIES", "1000")
+	viper.SetDefault("WEBHOOK_ALLOW_HTTP", "true")
+	viper.SetDefault("RECEIPTS_ENABLED", "true")
+	viper.SetDefault("RECEIPT_SIGNING_KEY", "change-me-receipt-signing-key")
+	viper.SetDefault("RECEIPT_BASE_URL", "http://localhost:7000")
+	viper.SetDefault("RECEIPT_LINK_TTL_MINUTES", "60")
+	viper.SetDefault("RECEIPT_LINK_MAX_TTL_MINUTES", "1440")
+	viper.SetDefault("RECEIPT_LOOKBACK_DAYS", "90")
+	viper.SetDefault("EXPORTS_ENABLED", "true")
+	viper.SetDefault("EXPORT_SIGNING_KEY", "change-me-export-signing-key")
+	viper.SetDefault("EXPORT_BASE_URL", "http://localhost:7000")
+	viper.SetDefault("EXPORT_LINK_TTL_MINUTES", "15")
+	viper.SetDefault("EXPORT_MAX_RANGE_DAYS", "366")
+	viper.SetDefault("EXPORT_WINDOW_DAYS", "31")
+	viper.SetDefault("BUDGETS_ENABLED", "true")
+	viper.SetDefault("BUDGET_ALERT_THRESHOLDS", "80,100")
+	viper.SetDefault("SECRETS_PROVIDER", "env")
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "300")
+	viper.SetDefault("AUDIT_SINK", "file")
+	viper.SetDefault("AUDIT_DIR", "audit")
+	viper.SetDefault("AUDIT_RETENTION_DAYS", "365")
+	viper.SetDefault("AUDIT_REDIS_ADDR", "localhost:6379")
+	viper.SetDefault("MASKING_ENABLED", "true")
+	viper.SetDefault("DEMO_MODE", "false")
+	viper.SetDefault("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios")
 
 	viper.AutomaticEnv()
 
 	AppConfig = &Config{
+		Environment: environment,
+		Secrets: secrets.Config{
+			Provider:        settings.SecretsProvider(),
+			RefreshInterval: getEnvInt("SECRETS_REFRESH_INTERVAL", 300),
+		},
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "7000"),
 			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
@@ -117,25 +360,194 @@ func LoadConfig() (*Config, error) {
 			DBName:   getEnv("DWH_NAME", "dwh"),
 			SSLMode:  getEnv("DWH_SSLMODE", "disable"),
 			Enabled:  getEnv("DWH_ENABLED", "false") == "true",
+
+			ReplicaHosts:         getEnvList("DWH_REPLICA_HOSTS"),
+			MaxReplicaLag:        getEnvInt("DWH_MAX_REPLICA_LAG", 5),
+			ReplicaCheckInterval: getEnvInt("DWH_REPLICA_CHECK_INTERVAL", 1),
+		},
+		Sandbox: SandboxConfig{
+			OpeningBalance: getEnvFloat("SANDBOX_OPENING_BALANCE", 150000),
+		},
+		Connectors: ConnectorsConfig{
+			MB:  getEnvConnector("MB", "mock"),
+			NB:  getEnvConnector("NB", "mock"),
+			API: getEnvConnector("API", ""),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOGGING_LEVEL", "info"),
 			Format: getEnv("LOGGING_FORMAT", "json"),
 		},
+		Recovery: recovery.Config{
+			ExposeDetails:    getEnv("RECOVERY_EXPOSE_DETAILS", "false") == "true",
+			StackLogInterval: getEnvInt("RECOVERY_STACK_LOG_INTERVAL", 300),
+			KeepFingerprints: getEnvInt("RECOVERY_KEEP_FINGERPRINTS", 100),
+		},
 		Security: SecurityConfig{
 			APIKeyHeader: getEnv("SECURITY_API_KEY_HEADER", "X-API-Key"),
 			JWTSecret:    getEnv("SECURITY_JWT_SECRET", "your-secret-key"),
 			RateLimitRPS: 100,
 		},
+		RBAC: RBACConfig{
+			BackOffice: getEnvGrants("RBAC_BACKOFFICE_OPERATORS"),
+			Agents:     getEnvGrants("RBAC_AGENTS"),
+			Partners:   getEnvGrants("RBAC_PARTNERS"),
+		},
+		Masking: MaskingConfig{
+			Enabled:   getEnv("MASKING_ENABLED", "true") == "true",
+			RulesFile: getEnv("MASKING_RULES_FILE", ""),
+		},
+		Adjustments: AdjustmentsConfig{
+			MaxAmount:    getEnvFloat("ADJUSTMENT_MAX_AMOUNT", 1000000),
+			PendingHours: getEnvInt("ADJUSTMENT_PENDING_HOURS", 24),
+		},
+		AccountStatus: AccountStatusConfig{
+			AutoFreeze:  getEnv("ACCOUNT_AUTO_FREEZE", "true") == "true",
+			FreezeScore: getEnvFloat("ACCOUNT_FREEZE_FRAUD_SCORE", 0.85),
+			LockScore:   getEnvFloat("ACCOUNT_LOCK_FRAUD_SCORE", 0.95),
+		},
+		Calendar: CalendarConfig{
+			Timezone:     getEnv("CALENDAR_TIMEZONE", "Asia/Kolkata"),
+			HolidaysFile: getEnv("CALENDAR_HOLIDAYS_FILE", ""),
+			RTGSWindow:   getEnv("CALENDAR_RTGS_WINDOW", "07:00-18:00"),
+			NEFTWindow:   getEnv("CALENDAR_NEFT_WINDOW", "08:00-18:00"),
+		},
+		ISO20022: ISO20022Config{
+			Enabled:  getEnv("ISO20022_ENABLED", "true") == "true",
+			BankIFSC: strings.ToUpper(getEnv("ISO20022_BANK_IFSC", "AIBK0000001")),
+		},
+		PaymentRequests: PaymentRequestsConfig{
+			VPAHandle:   getEnv("PAYMENT_REQUEST_VPA_HANDLE", "aibank"),
+			ExpiryHours: getEnvInt("PAYMENT_REQUEST_EXPIRY_HOURS", 24),
+			MaxAmount:   getEnvFloat("PAYMENT_REQUEST_MAX_AMOUNT", 100000),
+		},
+		PayeeDirectory: PayeeDirectoryConfig{
+			File: getEnv("PAYEE_DIRECTORY_FILE", ""),
+		},
+		Scoring: ScoringConfig{
+			AgentURL:      getEnv("SCORING_AGENT_URL", "http://localhost:8005"),
+			APIKey:        getEnv("SCORING_AGENT_API_KEY", "test-api-key"),
+			Timeout:       getEnvInt("SCORING_AGENT_TIMEOUT", 10),
+			Concurrency:   getEnvInt("SCORING_JOB_CONCURRENCY", 8),
+			IntervalHours: getEnvInt("SCORING_JOB_INTERVAL_HOURS", 0),
+			KeepRuns:      getEnvInt("SCORING_JOB_KEEP_RUNS", 10),
+		},
+		Insights: InsightsConfig{
+			AgentURL:            getEnv("INSIGHTS_AGENT_URL", "http://localhost:8006"),
+			APIKey:              getEnv("INSIGHTS_AGENT_API_KEY", "test-api-key"),
+			Timeout:             getEnvInt("INSIGHTS_AGENT_TIMEOUT", 10),
+			DigestIntervalHours: getEnvInt("INSIGHTS_DIGEST_INTERVAL_HOURS", 0),
+			KeepRuns:            getEnvInt("INSIGHTS_DIGEST_KEEP_RUNS", 10),
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:        getEnvInt("WEBHOOK_TIMEOUT", 10),
+			MaxAttempts:    getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			RetryBackoff:   getEnvInt("WEBHOOK_RETRY_BACKOFF_SECONDS", 10),
+			MaxBackoff:     getEnvInt("WEBHOOK_MAX_BACKOFF_SECONDS", 3600),
+			KeepDeliveries: getEnvInt("WEBHOOK_KEEP_DELIVERIES", 1000),
+			AllowHTTP:      getEnv("WEBHOOK_ALLOW_HTTP", "true") == "true",
+		},
+		Receipts: ReceiptsConfig{
+			Enabled:       getEnv("RECEIPTS_ENABLED", "true") == "true",
+			SigningKey:    getEnv("RECEIPT_SIGNING_KEY", "change-me-receipt-signing-key"),
+			BaseURL:       strings.TrimRight(getEnv("RECEIPT_BASE_URL", "http://localhost:7000"), "/"),
+			TTLMinutes:    getEnvInt("RECEIPT_LINK_TTL_MINUTES", 60),
+			MaxTTLMinutes: getEnvInt("RECEIPT_LINK_MAX_TTL_MINUTES", 1440),
+			LookbackDays:  getEnvInt("RECEIPT_LOOKBACK_DAYS", 90),
+		},
+		Exports: ExportsConfig{
+			Enabled:      getEnv("EXPORTS_ENABLED", "true") == "true",
+			SigningKey:   getEnv("EXPORT_SIGNING_KEY", "change-me-export-signing-key"),
+			BaseURL:      strings.TrimRight(getEnv("EXPORT_BASE_URL", "http://localhost:7000"), "/"),
+			TTLMinutes:   getEnvInt("EXPORT_LINK_TTL_MINUTES", 15),
+			MaxRangeDays: getEnvInt("EXPORT_MAX_RANGE_DAYS", 366),
+			WindowDays:   getEnvInt("EXPORT_WINDOW_DAYS", 31),
+		},
+		Budgets: BudgetsConfig{
+			Enabled:    getEnv("BUDGETS_ENABLED", "true") == "true",
+			Thresholds: getEnvInts("BUDGET_ALERT_THRESHOLDS", "80,100"),
+		},
+		Transfers: TransfersConfig{
+			IdempotencyHours: getEnvInt("TRANSFER_IDEMPOTENCY_HOURS", 72),
+		},
+		Audit: audit.Config{
+			Service:       "banking-integrations",
+			Sink:          strings.ToLower(getEnv("AUDIT_SINK", audit.SinkFile)),
+			Dir:           getEnv("AUDIT_DIR", "audit"),
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			RedisAddr:     getEnv("AUDIT_REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("AUDIT_REDIS_PASSWORD", ""),
+			RedisStream:   getEnv("AUDIT_REDIS_STREAM", "audit:access:banking-integrations"),
+			SyslogNetwork: getEnv("AUDIT_SYSLOG_NETWORK", ""),
+			SyslogAddr:    getEnv("AUDIT_SYSLOG_ADDR", ""),
+			SigningKey:    getEnv("AUDIT_SIGNING_KEY", ""),
+		},
+		Demo: demo.Config{
+			Enabled:      getEnv("DEMO_MODE", "false") == "true",
+			ScenariosDir: getEnv("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios"),
+		},
 	}
 
 	return AppConfig, nil
 }
 
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	return settings.Get(key, defaultValue)
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	return settings.GetInt(key, defaultValue)
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	return settings.GetFloat(key, defaultValue)
+}
+
+// getEnvInts parses a comma-separated list of integers such as "80,100"
+func getEnvInts(key, defaultValue string) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	var values []int
+	invalid := false
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil {
+			invalid = true
+			continue
+		}
+		values = append(values, n)
+	}
+	settings.Record(key, value, defaultValue, os.Getenv(key) != "", invalid)
+	return values
+}
+
+// getEnvList parses a comma-separated list such as "dwh-replica-1,dwh-replica-2",
+// dropping empty entries
+func getEnvList(key string) []string {
+	value := getEnv(key, "")
+	var values []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
 	}
-	return defaultValue
+	return values
 }
 
+// getEnvGrants parses "operator:apikey,operator:apikey" into an API key -> operator map
+func getEnvGrants(key string) map[string]string {
+	return settings.GetGrants(key)
+}
+
+// getEnvConnector reads CONNECTOR_<CHANNEL>_* settings
+func getEnvConnector(channel, defaultType string) ConnectorConfig {
+	prefix := "CONNECTOR_" + channel + "_"
+	return ConnectorConfig{
+		Type:        strings.ToLower(getEnv(prefix+"TYPE", defaultType)),
+		URL:         getEnv(prefix+"URL", ""),
+		APIKey:      getEnv(prefix+"API_KEY", ""),
+		Timeout:     getEnvInt(prefix+"TIMEOUT", 10),
+		MappingFile: getEnv(prefix+"MAPPING_FILE", ""),
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aibanking/shared/profile"
+)
+
+// settings holds every value LoadConfig read, by key
+var settings = profile.New()
+
+// Profile returns what LoadConfig read: every setting and where it came from, for
+// config-lint, and the secrets provider, whose rotations the service's clients follow
+func Profile() *profile.Profile {
+	return settings
+}
+
+// Validate checks the configuration against its environment. Errors should stop the
+// service from starting; warnings are logged.
+func (c *Config) Validate() []profile.Problem {
+	v := profile.NewValidator(settings, c.Environment)
+	if c.Database.Enabled {
+		v.Required("DB_HOST", "DB_PASSWORD")
+		v.Hosts("DB_HOST")
+		v.Secrets("DB_PASSWORD")
+	}
+	if c.DWH.Enabled {
+		v.Required("DWH_HOST", "DWH_PASSWORD")
+		v.Hosts("DWH_HOST")
+		v.Secrets("DWH_PASSWORD")
+	}
+	if len(c.DWH.ReplicaHosts) > 0 {
+		for _, host := range c.DWH.ReplicaHosts {
+			if v.Strict() && profile.IsLocalHost(host) {
+				v.Add("DWH_REPLICA_HOSTS", v.Severity(profile.SeverityWarning, profile.SeverityError), fmt.Sprintf("lists %s", host))
+			}
+		}
+		if c.DWH.MaxReplicaLag < 1 {
+			v.Add("DWH_MAX_REPLICA_LAG", profile.SeverityError, "must be at least 1")
+		}
+		if c.DWH.ReplicaCheckInterval < 1 {
+			v.Add("DWH_REPLICA_CHECK_INTERVAL", profile.SeverityError, "must be at least 1")
+		}
+	}
+	if c.Scoring.IntervalHours > 0 {
+		v.Required("SCORING_AGENT_URL", "SCORING_AGENT_API_KEY")
+		v.Secrets("SCORING_AGENT_API_KEY")
+	}
+	v.URLs("SCORING_AGENT_URL")
+	if c.Insights.DigestIntervalHours > 0 {
+		v.Required("INSIGHTS_AGENT_URL", "INSIGHTS_AGENT_API_KEY")
+		v.Secrets("INSIGHTS_AGENT_API_KEY")
+	}
+	v.URLs("INSIGHTS_AGENT_URL")
+
+	for channel, connector := range map[string]ConnectorConfig{"MB": c.Connectors.MB, "NB": c.Connectors.NB, "API": c.Connectors.API} {
+		key := "CONNECTOR_" + channel + "_"
+		switch connector.Type {
+		case "":
+		case "mock":
+			if c.Environment == profile.EnvProduction {
+				v.Add(key+"TYPE", profile.SeverityWarning, "is mock; the channel serves simulated accounts")
+			}
+		default:
+			if connector.URL == "" {
+				v.Add(key+"URL", profile.SeverityError, fmt.Sprintf("not set; the %s connector needs a core-banking URL", connector.Type))
+			}
+			v.URLs(key + "URL")
+		}
+	}
+
+	if c.AccountStatus.AutoFreeze {
+		if c.AccountStatus.FreezeScore <= 0 || c.AccountStatus.FreezeScore > 1 {
+			v.Add("ACCOUNT_FREEZE_FRAUD_SCORE", profile.SeverityError, "must be above 0 and at most 1")
+		}
+		if c.AccountStatus.LockScore < c.AccountStatus.FreezeScore {
+			v.Add("ACCOUNT_LOCK_FRAUD_SCORE", profile.SeverityError, "is below ACCOUNT_FREEZE_FRAUD_SCORE")
+		}
+	}
+
+	if c.Webhooks.MaxAttempts < 1 {
+		v.Add("WEBHOOK_MAX_ATTEMPTS", profile.SeverityError, "must be at least 1")
+	}
+	if c.Webhooks.RetryBackoff < 1 || c.Webhooks.MaxBackoff < c.Webhooks.RetryBackoff {
+		v.Add("WEBHOOK_RETRY_BACKOFF_SECONDS", profile.SeverityError, "must be at least 1 and no more than WEBHOOK_MAX_BACKOFF_SECONDS")
+	}
+	if c.Webhooks.AllowHTTP && v.Strict() {
+		v.Add("WEBHOOK_ALLOW_HTTP", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; signed event payloads may be sent unencrypted")
+	}
+
+	if c.Receipts.Enabled {
+		v.Required("RECEIPT_SIGNING_KEY", "RECEIPT_BASE_URL")
+		v.Secrets("RECEIPT_SIGNING_KEY")
+		v.URLs("RECEIPT_BASE_URL")
+		if strings.HasPrefix(c.Receipts.BaseURL, "http://") && v.Strict() && !v.Flagged("RECEIPT_BASE_URL") {
+			v.Add("RECEIPT_BASE_URL", v.Severity(profile.SeverityWarning, profile.SeverityError), "is plain HTTP; receipt links would be sent unencrypted")
+		}
+		if c.Receipts.TTLMinutes < 1 || c.Receipts.MaxTTLMinutes < c.Receipts.TTLMinutes {
+			v.Add("RECEIPT_LINK_TTL_MINUTES", profile.SeverityError, "must be at least 1 and no more than RECEIPT_LINK_MAX_TTL_MINUTES")
+		}
+	}
+
+	if c.Exports.Enabled {
+		v.Required("EXPORT_SIGNING_KEY", "EXPORT_BASE_URL")
+		v.Secrets("EXPORT_SIGNING_KEY")
+		v.URLs("EXPORT_BASE_URL")
+		if strings.HasPrefix(c.Exports.BaseURL, "http://") && v.Strict() && !v.Flagged("EXPORT_BASE_URL") {
+			v.Add("EXPORT_BASE_URL", v.Severity(profile.SeverityWarning, profile.SeverityError), "is plain HTTP; exported transactions would be sent unencrypted")
+		}
+		if c.Exports.TTLMinutes < 1 {
+			v.Add("EXPORT_LINK_TTL_MINUTES", profile.SeverityError, "must be at least 1")
+		}
+		if c.Exports.MaxRangeDays < 1 {
+			v.Add("EXPORT_MAX_RANGE_DAYS", profile.SeverityError, "must be at least 1")
+		}
+		if c.Exports.WindowDays < 1 {
+			v.Add("EXPORT_WINDOW_DAYS", profile.SeverityError, "must be at least 1")
+		}
+	}
+
+	if c.Budgets.Enabled {
+		if len(c.Budgets.Thresholds) == 0 {
+			v.Add("BUDGET_ALERT_THRESHOLDS", profile.SeverityError, "must list at least one percentage")
+		}
+		for _, t := range c.Budgets.Thresholds {
+			if t < 1 || t > 200 {
+				v.Add("BUDGET_ALERT_THRESHOLDS", profile.SeverityError, "percentages must be between 1 and 200")
+				break
+			}
+		}
+	}
+	if c.Transfers.IdempotencyHours < 1 {
+		v.Add("TRANSFER_IDEMPOTENCY_HOURS", profile.SeverityError, "must be at least 1; agents reconcile interrupted transfers by their key")
+	}
+
+	if len(c.RBAC.BackOffice) == 0 && c.Environment == profile.EnvProduction {
+		v.Add("RBAC_BACKOFFICE_OPERATORS", profile.SeverityWarning, "no back-office operators; ledger adjustments and unfreezes cannot be made")
+	}
+	if len(c.RBAC.Agents) == 0 && c.Environment == profile.EnvProduction {
+		v.Add("RBAC_AGENTS", profile.SeverityWarning, "no agent credentials; fraud signals cannot freeze accounts")
+	}
+	if !c.Masking.Enabled && v.Strict() {
+		v.Add("MASKING_ENABLED", profile.SeverityWarning, "is off; API-channel partners see full account numbers and remarks")
+	}
+	if c.Recovery.ExposeDetails && v.Strict() {
+		v.Add("RECOVERY_EXPOSE_DETAILS", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; panic messages, which may hold customer data, are sent to callers")
+	}
+	if c.Recovery.KeepFingerprints < 1 {
+		v.Add("RECOVERY_KEEP_FINGERPRINTS", profile.SeverityError, "must be at least 1")
+	}
+	v.Placeholders("SECURITY_JWT_SECRET")
+	v.AuditSink(c.Audit)
+	v.DemoMode(c.Demo)
+	v.SecretSources(c.Secrets)
+	return v.Problems()
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/middleware"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AccountStatusController handles account freezes and locks: the status agents
+// check before a debit, fraud signals that restrict accounts, and the back-office
+// routes that place and lift restrictions
+type AccountStatusController struct {
+	accountStatus *service.AccountStatusService
+}
+
+// NewAccountStatusController creates a new account status controller
+func NewAccountStatusController(accountStatus *service.AccountStatusService) *AccountStatusController {
+	return &AccountStatusController{accountStatus: accountStatus}
+}
+
+// GetStatus handles GET /accounts/status?user_id=&account_id=
+func (sc *AccountStatusController) GetStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	userID, accountID := query.Get("user_id"), query.Get("account_id")
+	if userID == "" && accountID == "" {
+		respondWithError(w, http.StatusBadRequest, "user_id or account_id is required", nil)
+		return
+	}
+
+	status, err := sc.accountStatus.Status(r.Context(), userID, accountID)
+	if err != nil {
+		respondWithError(w, http.StatusForbidden, "Account status refused", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// ReportFraudSignal handles POST /accounts/fraud-signals
+func (sc *AccountStatusController) ReportFraudSignal(w http.ResponseWriter, r *http.Request) {
+	var sig model.FraudSignal
+	if err := json.NewDecoder(r.Body).Decode(&sig); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	resp, err := sc.accountStatus.HandleFraudSignal(r.Context(), &sig)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fraud signal", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListRestrictions handles GET /admin/accounts/restrictions?active=true&user_id=&account_id=
+func (sc *AccountStatusController) ListRestrictions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	restrictions := sc.accountStatus.List(query.Get("active") == "true", query.Get("user_id"), query.Get("account_id"))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"restrictions": restrictions,
+		"count":        len(restrictions),
+	})
+}
+
+// PlaceRestriction handles POST /admin/accounts/restrictions. The caller is recorded
+// as the operator who froze or locked the account.
+func (sc *AccountStatusController) PlaceRestriction(w http.ResponseWriter, r *http.Request) {
+	var req model.RestrictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	restriction, err := sc.accountStatus.Restrict(r.Context(), middleware.OperatorFromContext(r.Context()), &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid restriction", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, restriction)
+}
+
+// LiftRestriction handles POST /admin/accounts/restrictions/{id}/lift, unfreezing
+// the account
+func (sc *AccountStatusController) LiftRestriction(w http.ResponseWriter, r *http.Request) {
+	var req model.LiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	restriction, err := sc.accountStatus.Lift(mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()), req.Reason)
+	switch {
+	case errors.Is(err, service.ErrRestrictionNotFound):
+		respondWithError(w, http.StatusNotFound, "Restriction not found", nil)
+		return
+	case errors.Is(err, service.ErrRestrictionLifted):
+		respondWithError(w, http.StatusConflict, "Restriction not lifted", err)
+		return
+	case err != nil:
+		respondWithError(w, http.StatusBadRequest, "Restriction not lifted", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, restriction)
+}
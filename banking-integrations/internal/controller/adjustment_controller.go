@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/middleware"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AdjustmentController handles the back-office balance adjustment API. Every route
+// sits behind the back-office role check, which identifies the operator.
+type AdjustmentController struct {
+	adjustments *service.AdjustmentService
+}
+
+// NewAdjustmentController creates a new adjustment controller
+func NewAdjustmentController(adjustments *service.AdjustmentService) *AdjustmentController {
+	return &AdjustmentController{
+		adjustments: adjustments,
+	}
+}
+
+// CreateAdjustment handles POST /admin/adjustments. The caller is the maker.
+func (ac *AdjustmentController) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	var req model.AdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	adj, err := ac.adjustments.Create(r.Context(), middleware.OperatorFromContext(r.Context()), &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrConnectorUnavailable) || errors.Is(err, service.ErrOperationUnsupported) {
+			status = gatewayErrorStatus(err)
+		}
+		respondWithError(w, status, "Invalid adjustment", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, adj)
+}
+
+// ListAdjustments handles GET /admin/adjustments?status=PENDING
+func (ac *AdjustmentController) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	status := model.AdjustmentStatus(strings.ToUpper(r.URL.Query().Get("status")))
+	adjustments := ac.adjustments.List(status)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"adjustments": adjustments,
+		"count":       len(adjustments),
+	})
+}
+
+// GetAdjustment handles GET /admin/adjustments/{id}
+func (ac *AdjustmentController) GetAdjustment(w http.ResponseWriter, r *http.Request) {
+	adj, ok := ac.adjustments.Get(mux.Vars(r)["id"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Adjustment not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adj)
+}
+
+// ApproveAdjustment handles POST /admin/adjustments/{id}/approve. The caller is the
+// checker and must not be the maker.
+func (ac *AdjustmentController) ApproveAdjustment(w http.ResponseWriter, r *http.Request) {
+	var decision model.AdjustmentDecision
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	adj, err := ac.adjustments.Approve(r.Context(), mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()), decision.Note)
+	if err != nil {
+		respondWithAdjustmentError(w, "Adjustment not approved", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adj)
+}
+
+// RejectAdjustment handles POST /admin/adjustments/{id}/reject
+func (ac *AdjustmentController) RejectAdjustment(w http.ResponseWriter, r *http.Request) {
+	var decision model.AdjustmentDecision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	adj, err := ac.adjustments.Reject(mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()), decision.Note)
+	if err != nil {
+		respondWithAdjustmentError(w, "Adjustment not rejected", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adj)
+}
+
+// GetAudit handles GET /admin/audit?adjustment_id=&account_id=&user_id=
+func (ac *AdjustmentController) GetAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	records := ac.adjustments.Audit(query.Get("adjustment_id"), query.Get("account_id"), query.Get("user_id"))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"records": records,
+		"count":   len(records),
+	})
+}
+
+// respondWithAdjustmentError maps adjustment errors to status codes
+func respondWithAdjustmentError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrAdjustmentNotFound):
+		respondWithError(w, http.StatusNotFound, "Adjustment not found", nil)
+	case errors.Is(err, service.ErrSelfApproval):
+		respondWithError(w, http.StatusForbidden, message, err)
+	case errors.Is(err, service.ErrAdjustmentNotPending):
+		respondWithError(w, http.StatusConflict, message, err)
+	case errors.Is(err, service.ErrConnectorUnavailable), errors.Is(err, service.ErrOperationUnsupported):
+		respondWithError(w, gatewayErrorStatus(err), message, err)
+	default:
+		respondWithError(w, http.StatusUnprocessableEntity, message, err)
+	}
+}
@@ -1,24 +1,44 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aibanking/banking-integrations/internal/iso20022"
 	"github.com/aibanking/banking-integrations/internal/model"
 	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/aibanking/shared/channel"
+	"github.com/aibanking/shared/tz"
+	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 )
 
+// maxLookupTransactions caps the IDs looked up in one DWH request
+const maxLookupTransactions = 1000
+
+// maxIdempotencyKeyLength bounds the idempotency key of a transfer
+const maxIdempotencyKeyLength = 128
+
 // BankingController handles banking API requests
 type BankingController struct {
 	gateway *service.BankingGateway
+	masker  *service.FieldMasker
 }
 
-// NewBankingController creates a new banking controller
-func NewBankingController(gateway *service.BankingGateway) *BankingController {
+// NewBankingController creates a new banking controller. Statement, balance and
+// transaction-history responses are masked for the channel they are served to.
+func NewBankingController(gateway *service.BankingGateway, masker *service.FieldMasker) *BankingController {
 	return &BankingController{
 		gateway: gateway,
+		masker:  masker,
 	}
 }
 
@@ -34,14 +54,22 @@ func (bc *BankingController) GetBalance(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
 
 	response, err := bc.gateway.GetBalance(r.Context(), &req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to get balance", err)
+		respondWithError(w, gatewayErrorStatus(err), "Failed to get balance", err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, response)
+	body, err := bc.masker.Mask(bc.masker.Audience(r, req.Channel), response)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get balance", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, body)
 }
 
 // TransferFunds handles POST /transfer
@@ -56,10 +84,32 @@ func (bc *BankingController) TransferFunds(w http.ResponseWriter, r *http.Reques
 		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+	}
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("idempotency_key must be at most %d characters", maxIdempotencyKeyLength), nil)
+		return
+	}
 
 	response, err := bc.gateway.TransferFunds(r.Context(), &req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to transfer funds", err)
+		respondWithError(w, gatewayErrorStatus(err), "Failed to transfer funds", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// GetTransferByIdempotencyKey handles GET /transfers/idempotency/{key}: the response
+// of the transfer made with the key, 404 when none was, 409 while it is in progress
+func (bc *BankingController) GetTransferByIdempotencyKey(w http.ResponseWriter, r *http.Request) {
+	response, err := bc.gateway.LookupTransfer(mux.Vars(r)["key"])
+	if err != nil {
+		respondWithError(w, gatewayErrorStatus(err), "Transfer not available", err)
 		return
 	}
 
@@ -74,10 +124,71 @@ func (bc *BankingController) GetStatement(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	bc.serveStatement(w, r, &req)
+}
+
+// GetStatementByQuery handles GET /statement?user_id=&account_id=&channel=&start_date=&end_date=&limit=,
+// the cacheable form of POST /statement. Dates are RFC 3339 times or YYYY-MM-DD.
+func (bc *BankingController) GetStatementByQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := model.StatementRequest{
+		AccountID: q.Get("account_id"),
+		UserID:    q.Get("user_id"),
+		Channel:   model.Channel(q.Get("channel")),
+		Sandbox:   q.Get("sandbox") == "true",
+	}
+
+	// Plain dates are days in the user's time zone, the bank's when not given
+	location := bc.gateway.BankLocation()
+	if name := q.Get("timezone"); name != "" {
+		loc, err := tz.Load(name)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "timezone must be an IANA time zone such as Asia/Kolkata", err)
+			return
+		}
+		location = loc
+	}
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{{"start_date", &req.StartDate}, {"end_date", &req.EndDate}} {
+		v := q.Get(param.name)
+		if v == "" {
+			continue
+		}
+		parsed, wholeDay, err := tz.ParseDate(v, location)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, param.name+" must be an RFC 3339 time or YYYY-MM-DD", err)
+			return
+		}
+		// An end date includes the whole of that day
+		if wholeDay && param.name == "end_date" {
+			parsed = tz.EndOfDay(parsed)
+		}
+		*param.dst = parsed
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a non-negative number", err)
+			return
+		}
+		req.Limit = limit
+	}
+
+	bc.serveStatement(w, r, &req)
+}
+
+// serveStatement validates a statement request, fills in the default dates and
+// responds with the statement
+func (bc *BankingController) serveStatement(w http.ResponseWriter, r *http.Request, req *model.StatementRequest) {
 	if req.AccountID == "" || req.UserID == "" {
 		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
 
 	// Set default dates if not provided
 	if req.StartDate.IsZero() {
@@ -87,13 +198,44 @@ func (bc *BankingController) GetStatement(w http.ResponseWriter, r *http.Request
 		req.EndDate = time.Now()
 	}
 
-	response, err := bc.gateway.GetStatement(r.Context(), &req)
+	response, err := bc.gateway.GetStatement(r.Context(), req)
+	if err != nil {
+		respondWithError(w, gatewayErrorStatus(err), "Failed to get statement", err)
+		return
+	}
+
+	// Tagged after masking, so each channel's view has its own tag
+	body, err := bc.masker.Mask(bc.masker.Audience(r, req.Channel), response)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to get statement", err)
 		return
 	}
+	if etag, ok := statementETag(body); ok {
+		w.Header().Set("ETag", etag)
+	}
+	respondWithJSON(w, http.StatusOK, body)
+}
 
-	respondWithJSON(w, http.StatusOK, response)
+// statementETag tags a statement by its account and entries, leaving out the
+// generation time and default date range, which change on every request
+func statementETag(body interface{}) (string, bool) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", false
+	}
+	var tagged struct {
+		AccountID    string          `json:"account_id"`
+		Transactions json.RawMessage `json:"transactions"`
+		Simulated    bool            `json:"simulated"`
+	}
+	if err := json.Unmarshal(data, &tagged); err != nil {
+		return "", false
+	}
+	if data, err = json.Marshal(tagged); err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, true
 }
 
 // AddBeneficiary handles POST /beneficiary
@@ -104,6 +246,7 @@ func (bc *BankingController) AddBeneficiary(w http.ResponseWriter, r *http.Reque
 		IFSC         string          `json:"ifsc"`
 		Name         string          `json:"name"`
 		Channel      model.Channel   `json:"channel"`
+		Sandbox      bool            `json:"sandbox,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -115,16 +258,78 @@ func (bc *BankingController) AddBeneficiary(w http.ResponseWriter, r *http.Reque
 		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
 
-	response, err := bc.gateway.AddBeneficiary(r.Context(), req.Channel, req.UserID, req.AccountNumber, req.IFSC, req.Name)
+	response, err := bc.gateway.AddBeneficiary(r.Context(), req.Channel, req.UserID, req.AccountNumber, req.IFSC, req.Name, req.Sandbox)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to add beneficiary", err)
+		respondWithError(w, gatewayErrorStatus(err), "Failed to add beneficiary", err)
 		return
 	}
 
 	respondWithJSON(w, http.StatusCreated, response)
 }
 
+// GetBeneficiary handles GET /beneficiaries?user_id=&account_number=
+func (bc *BankingController) GetBeneficiary(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID, accountNumber := q.Get("user_id"), q.Get("account_number")
+	channel := model.Channel(q.Get("channel"))
+	if userID == "" || accountNumber == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
+		return
+	}
+	if !normalizeChannel(w, &channel) {
+		return
+	}
+
+	beneficiary, err := bc.gateway.GetBeneficiary(r.Context(), channel, userID, accountNumber, q.Get("sandbox") == "true")
+	if err != nil {
+		respondWithError(w, gatewayErrorStatus(err), "Failed to look up beneficiary", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, beneficiary)
+}
+
+// GetPaymentMessage handles GET /payments/{transactionID}/message. With
+// ?format=xml the pacs.008 message is returned as sent.
+func (bc *BankingController) GetPaymentMessage(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["transactionID"]
+	msg, ok := bc.gateway.GetPaymentMessage(r.Context(), transactionID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "No payment message for this transaction", nil)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "xml" {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(msg.XML))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, msg)
+}
+
+// AcknowledgePayment handles POST /payments/acknowledgements, whose body is a
+// pacs.002 status report
+func (bc *BankingController) AcknowledgePayment(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil || len(body) == 0 {
+		respondWithError(w, http.StatusBadRequest, "A pacs.002 status report is required", err)
+		return
+	}
+
+	msg, err := bc.gateway.AcknowledgePayment(r.Context(), body)
+	if err != nil {
+		respondWithError(w, gatewayErrorStatus(err), "Failed to record acknowledgment", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, msg)
+}
+
 // QueryDWH handles POST /dwh/query
 func (bc *BankingController) QueryDWH(w http.ResponseWriter, r *http.Request) {
 	var req model.DWHQueryRequest
@@ -147,18 +352,51 @@ func (bc *BankingController) QueryDWH(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, response)
 }
 
-// GetTransactionHistory handles GET /dwh/history/{userID}
+// LookupTransactions handles POST /dwh/transactions/lookup
+func (bc *BankingController) LookupTransactions(w http.ResponseWriter, r *http.Request) {
+	var req model.DWHLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if len(req.TransactionIDs) == 0 || len(req.TransactionIDs) > maxLookupTransactions {
+		respondWithError(w, http.StatusBadRequest, "transaction_ids must list between 1 and 1000 IDs", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, bc.gateway.LookupTransactions(r.Context(), req.TransactionIDs))
+}
+
+// GetDWHReplication handles GET /admin/dwh/replication
+func (bc *BankingController) GetDWHReplication(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, bc.gateway.DWHReplication())
+}
+
+// GetTransactionHistory handles GET /dwh/history/{userID}?days=&channel=. History is
+// masked for the channel when one is given.
 func (bc *BankingController) GetTransactionHistory(w http.ResponseWriter, r *http.Request) {
-	userID := r.PathValue("userID")
+	userID := mux.Vars(r)["userID"]
 	if userID == "" {
 		respondWithError(w, http.StatusBadRequest, "User ID is required", nil)
 		return
 	}
+	var ch model.Channel
+	if named := r.URL.Query().Get("channel"); named != "" {
+		ch = model.Channel(named)
+		if !normalizeChannel(w, &ch) {
+			return
+		}
+	}
 
 	days := 90 // Default 90 days
 	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
-		// Parse days parameter if provided
-		// For simplicity, using default
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 || parsed > 3650 {
+			respondWithError(w, http.StatusBadRequest, "days must be between 1 and 3650", err)
+			return
+		}
+		days = parsed
 	}
 
 	transactions, err := bc.gateway.GetTransactionHistory(r.Context(), userID, days)
@@ -166,14 +404,161 @@ func (bc *BankingController) GetTransactionHistory(w http.ResponseWriter, r *htt
 		respondWithError(w, http.StatusInternalServerError, "Failed to get transaction history", err)
 		return
 	}
+	masked, err := bc.masker.Mask(bc.masker.Audience(r, ch), transactions)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get transaction history", err)
+		return
+	}
 
 	respondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"user_id":     userID,
-		"transactions": transactions,
+		"transactions": masked,
 		"count":       len(transactions),
 	})
 }
 
+// GetPreferences handles GET /preferences/{userID}
+func (bc *BankingController) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, ok := bc.gateway.GetPreferences(r.Context(), mux.Vars(r)["userID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "No preferences set for user", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PUT /preferences/{userID}
+func (bc *BankingController) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var update model.PreferenceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	prefs, err := bc.gateway.UpdatePreferences(r.Context(), mux.Vars(r)["userID"], &update)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid preferences", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// DeletePreferences handles DELETE /preferences/{userID}
+func (bc *BankingController) DeletePreferences(w http.ResponseWriter, r *http.Request) {
+	if !bc.gateway.DeletePreferences(r.Context(), mux.Vars(r)["userID"]) {
+		respondWithError(w, http.StatusNotFound, "No preferences set for user", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Preferences deleted",
+	})
+}
+
+// ResolveTransferMethod handles GET /preferences/{userID}/transfer-method?amount=N
+func (bc *BankingController) ResolveTransferMethod(w http.ResponseWriter, r *http.Request) {
+	amount, err := strconv.ParseFloat(r.URL.Query().Get("amount"), 64)
+	if err != nil || amount <= 0 {
+		respondWithError(w, http.StatusBadRequest, "A positive amount is required", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, bc.gateway.ResolveTransferMethod(r.Context(), mux.Vars(r)["userID"], amount))
+}
+
+// EstimateSettlement handles GET /calendar/settlement?rail=NEFT&at=RFC3339
+func (bc *BankingController) EstimateSettlement(w http.ResponseWriter, r *http.Request) {
+	rail := model.TransactionType(strings.ToUpper(r.URL.Query().Get("rail")))
+	switch rail {
+	case model.TransactionTypeNEFT, model.TransactionTypeRTGS, model.TransactionTypeIMPS, model.TransactionTypeUPI:
+	default:
+		respondWithError(w, http.StatusBadRequest, "rail must be NEFT, RTGS, IMPS or UPI", nil)
+		return
+	}
+
+	at := time.Now()
+	if v := r.URL.Query().Get("at"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "at must be an RFC 3339 time", err)
+			return
+		}
+		at = parsed
+	}
+
+	respondWithJSON(w, http.StatusOK, bc.gateway.EstimateSettlement(r.Context(), rail, at))
+}
+
+// GetBankingCalendar handles GET /calendar?year=YYYY
+func (bc *BankingController) GetBankingCalendar(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid year", err)
+			return
+		}
+		year = parsed
+	}
+
+	respondWithJSON(w, http.StatusOK, bc.gateway.GetBankingCalendar(r.Context(), year))
+}
+
+// ResetSandbox handles POST /sandbox/reset
+func (bc *BankingController) ResetSandbox(w http.ResponseWriter, r *http.Request) {
+	cleared := bc.gateway.ResetSandbox(r.Context())
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Sandbox state reset",
+		"cleared": cleared,
+	})
+}
+
+// SeedData handles POST /admin/seed
+func (bc *BankingController) SeedData(w http.ResponseWriter, r *http.Request) {
+	var fixture model.SeedFixture
+	if err := json.NewDecoder(r.Body).Decode(&fixture); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fixture payload", err)
+		return
+	}
+
+	replace := r.URL.Query().Get("replace") == "true"
+	result, err := bc.gateway.SeedData(r.Context(), &fixture, replace)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fixture", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Seed data loaded",
+		"loaded":  result,
+	})
+}
+
+// ClearSeedData handles DELETE /admin/seed
+func (bc *BankingController) ClearSeedData(w http.ResponseWriter, r *http.Request) {
+	cleared := bc.gateway.ClearSeedData(r.Context())
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Seed data cleared",
+		"cleared": cleared,
+	})
+}
+
+// GetSeededUser handles GET /admin/seed/{userID}
+func (bc *BankingController) GetSeededUser(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	user, ok := bc.gateway.GetSeededUser(r.Context(), userID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "User has not been seeded", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
 // HealthCheck handles GET /health
 func (bc *BankingController) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -212,3 +597,48 @@ func respondWithError(w http.ResponseWriter, code int, message string, err error
 	respondWithJSON(w, code, response)
 }
 
+// normalizeChannel replaces a request's channel with its canonical spelling, answering
+// 400 with the allowed channels when it is not one of them
+func normalizeChannel(w http.ResponseWriter, c *model.Channel) bool {
+	parsed, err := channel.Parse(string(*c))
+	if err != nil {
+		log.Warn().Err(err).Msg("Request with invalid channel")
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid channel",
+			"code":    http.StatusBadRequest,
+			"details": err.Error(),
+			"allowed": channel.Allowed(),
+		})
+		return false
+	}
+	*c = parsed
+	return true
+}
+
+// gatewayErrorStatus maps a channel error to a status code: a channel nothing is
+// configured for is the caller's mistake, a connector that cannot serve the request
+// is not
+func gatewayErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedChannel):
+		return http.StatusBadRequest
+	case errors.Is(err, iso20022.ErrInvalidMessage):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrPaymentMessageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAcknowledgementMismatch):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrOperationUnsupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, service.ErrConnectorUnavailable):
+		return http.StatusBadGateway
+	case errors.Is(err, service.ErrAccountRestricted), errors.Is(err, service.ErrAccountNotOwned):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTransferNotFound), errors.Is(err, service.ErrBeneficiaryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTransferInProgress), errors.Is(err, service.ErrIdempotencyConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
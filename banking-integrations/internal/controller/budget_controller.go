@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// BudgetController handles users' monthly spending budgets
+type BudgetController struct {
+	budgets *service.BudgetService
+}
+
+// NewBudgetController creates a new budget controller
+func NewBudgetController(budgets *service.BudgetService) *BudgetController {
+	return &BudgetController{
+		budgets: budgets,
+	}
+}
+
+// SetBudget handles PUT /budgets
+func (bc *BudgetController) SetBudget(w http.ResponseWriter, r *http.Request) {
+	var req model.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	status, err := bc.budgets.Set(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrBudgetsDisabled) {
+			respondWithError(w, http.StatusServiceUnavailable, "Budgets are disabled", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Invalid budget", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// ListBudgets handles GET /budgets?user_id=, each budget with how it stands this month
+func (bc *BudgetController) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, http.StatusBadRequest, "user_id is required", nil)
+		return
+	}
+
+	budgets, err := bc.budgets.Status(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Budgets are disabled", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id": userID,
+		"budgets": budgets,
+		"count":   len(budgets),
+	})
+}
+
+// DeleteBudget handles DELETE /budgets/{userID}/{category}
+func (bc *BudgetController) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := bc.budgets.Delete(r.Context(), vars["userID"], vars["category"]); err != nil {
+		respondWithError(w, http.StatusNotFound, "Budget not found", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Budget deleted",
+	})
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/service"
+)
+
+// DemoController handles demo mode's scenario seed data
+type DemoController struct {
+	seeder *service.DemoSeeder
+}
+
+// NewDemoController creates a new demo controller
+func NewDemoController(seeder *service.DemoSeeder) *DemoController {
+	return &DemoController{
+		seeder: seeder,
+	}
+}
+
+// ListScenarios handles GET /demo/scenarios
+func (dc *DemoController) ListScenarios(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"scenarios": dc.seeder.Scenarios(),
+	})
+}
+
+// Reset handles POST /demo/reset: every scenario's users are seeded again, undoing
+// what earlier runs of the demo did to their accounts
+func (dc *DemoController) Reset(w http.ResponseWriter, r *http.Request) {
+	loaded, err := dc.seeder.Seed(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to seed demo scenarios", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Demo scenarios seeded",
+		"loaded":  loaded,
+	})
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// exportWriteTimeout is how long an export may take to send each window of history.
+// The deadline is pushed back after every window, so a long export is not cut off by
+// the server's write timeout while it is still making progress.
+const exportWriteTimeout = 30 * time.Second
+
+// ExportController handles transaction exports: downloading them directly, and
+// download links that need no login
+type ExportController struct {
+	exports *service.ExportService
+}
+
+// NewExportController creates a new export controller
+func NewExportController(exports *service.ExportService) *ExportController {
+	return &ExportController{
+		exports: exports,
+	}
+}
+
+// CreateLink handles POST /exports/links
+func (ec *ExportController) CreateLink(w http.ResponseWriter, r *http.Request) {
+	var req model.ExportLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	link, err := ec.exports.CreateLink(r.Context(), &req)
+	if err != nil {
+		ec.respondWithExportError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, link)
+}
+
+// Download handles GET /exports?user_id=U10001&format=OFX&from=2026-01-01&to=2026-03-31,
+// with an optional account_id
+func (ec *ExportController) Download(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	export, err := ec.exports.Prepare(query.Get("user_id"), query.Get("account_id"), query.Get("format"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		ec.respondWithExportError(w, err)
+		return
+	}
+	ec.stream(w, r, export)
+}
+
+// DownloadLink handles GET /exports/{token}, the public download an export link
+// opens. No API key is needed; the token is the authorization.
+func (ec *ExportController) DownloadLink(w http.ResponseWriter, r *http.Request) {
+	export, err := ec.exports.Open(mux.Vars(r)["token"])
+	if err != nil {
+		w.Header().Set("Cache-Control", "no-store")
+		switch {
+		case errors.Is(err, service.ErrExportLinkExpired):
+			respondWithError(w, http.StatusGone, "Export link expired", nil)
+		default:
+			respondWithError(w, http.StatusNotFound, "Export not found", nil)
+		}
+		return
+	}
+	ec.stream(w, r, export)
+}
+
+// stream sends an export as a file download, a window of history at a time
+func (ec *ExportController) stream(w http.ResponseWriter, r *http.Request, export *model.Export) {
+	w.Header().Set("Content-Type", service.ExportContentType(export.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", service.ExportFileName(export)))
+	// Keep the history out of caches, search engines and the referrers of any link
+	// followed from it
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	flush := func() error {
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		return nil
+	}
+
+	// The status is sent; an export that fails now can only be cut short
+	if _, err := ec.exports.Stream(r.Context(), w, export, flush); err != nil {
+		log.Warn().Err(err).Str("user_id", export.UserID).Str("format", export.Format).Msg("Export cut short")
+	}
+}
+
+// respondWithExportError answers a request for an export that cannot be made
+func (ec *ExportController) respondWithExportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrExportsDisabled):
+		respondWithError(w, http.StatusServiceUnavailable, "Transaction exports are disabled", err)
+	case errors.Is(err, service.ErrExportAccountNotFound):
+		respondWithError(w, http.StatusNotFound, "Account not found", err)
+	default:
+		respondWithError(w, http.StatusBadRequest, "Invalid export request", err)
+	}
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+)
+
+// NotificationController handles notifications to users and the insights digest
+// sent through them
+type NotificationController struct {
+	notifications *service.NotificationService
+	digest        *service.InsightsDigest
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notifications *service.NotificationService, digest *service.InsightsDigest) *NotificationController {
+	return &NotificationController{
+		notifications: notifications,
+		digest:        digest,
+	}
+}
+
+// SendNotification handles POST /notifications
+func (nc *NotificationController) SendNotification(w http.ResponseWriter, r *http.Request) {
+	var req model.NotificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	n, err := nc.notifications.Send(r.Context(), &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid notification", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, n)
+}
+
+// ListNotifications handles GET /notifications?user_id=&category=
+func (nc *NotificationController) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("user_id") == "" {
+		respondWithError(w, http.StatusBadRequest, "user_id is required", nil)
+		return
+	}
+
+	notifications := nc.notifications.List(query.Get("user_id"), query.Get("category"))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// StartDigest handles POST /admin/insights/digests
+func (nc *NotificationController) StartDigest(w http.ResponseWriter, r *http.Request) {
+	run, err := nc.digest.Start("api")
+	if errors.Is(err, service.ErrDigestRunInProgress) {
+		respondWithError(w, http.StatusConflict, "An insights digest is already running", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to start insights digest", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, run)
+}
+
+// ListDigests handles GET /admin/insights/digests
+func (nc *NotificationController) ListDigests(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"runs": nc.digest.Runs(),
+	})
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PayeeController handles the directory of billers and merchants: finding a payee
+// by name and checking whether a transfer's payee is verified
+type PayeeController struct {
+	payees *service.PayeeDirectory
+}
+
+// NewPayeeController creates a new payee controller
+func NewPayeeController(payees *service.PayeeDirectory) *PayeeController {
+	return &PayeeController{
+		payees: payees,
+	}
+}
+
+// SearchPayees handles GET /payees?q=airtel&category=BILLER&limit=5
+func (pc *PayeeController) SearchPayees(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		respondWithError(w, http.StatusBadRequest, "q is required", nil)
+		return
+	}
+
+	category := model.PayeeCategory(strings.ToUpper(r.URL.Query().Get("category")))
+	if category != "" && category != model.PayeeCategoryBiller && category != model.PayeeCategoryMerchant {
+		respondWithError(w, http.StatusBadRequest, "category must be BILLER or MERCHANT", nil)
+		return
+	}
+
+	limit := 10
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 || parsed > 50 {
+			respondWithError(w, http.StatusBadRequest, "limit must be between 1 and 50", err)
+			return
+		}
+		limit = parsed
+	}
+
+	matches := pc.payees.Search(query, category, limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"query":  query,
+		"payees": matches,
+		"count":  len(matches),
+	})
+}
+
+// GetPayee handles GET /payees/{payeeID}
+func (pc *PayeeController) GetPayee(w http.ResponseWriter, r *http.Request) {
+	payee, ok := pc.payees.Get(mux.Vars(r)["payeeID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Payee not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payee)
+}
+
+// VerifyPayee handles POST /payees/verify, the badge a client shows before the
+// customer confirms a transfer
+func (pc *PayeeController) VerifyPayee(w http.ResponseWriter, r *http.Request) {
+	var req model.PayeeVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	if req.ToAccount == "" && req.VPA == "" {
+		respondWithError(w, http.StatusBadRequest, "to_account or vpa is required", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pc.payees.Verify(&req))
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PaymentRequestController handles UPI payment requests: asking someone for money
+// and recording the credit that pays it
+type PaymentRequestController struct {
+	requests *service.PaymentRequestService
+}
+
+// NewPaymentRequestController creates a new payment request controller
+func NewPaymentRequestController(requests *service.PaymentRequestService) *PaymentRequestController {
+	return &PaymentRequestController{
+		requests: requests,
+	}
+}
+
+// CreatePaymentRequest handles POST /payment-requests
+func (pc *PaymentRequestController) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	var req model.PaymentRequestCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	pr, err := pc.requests.Create(&req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid payment request", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, pr)
+}
+
+// ListPaymentRequests handles GET /payment-requests?user_id=U10001&status=PENDING
+func (pc *PaymentRequestController) ListPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, http.StatusBadRequest, "user_id is required", nil)
+		return
+	}
+
+	status := model.PaymentRequestStatus(strings.ToUpper(r.URL.Query().Get("status")))
+	requests := pc.requests.List(userID, status)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"payment_requests": requests,
+		"count":            len(requests),
+	})
+}
+
+// GetPaymentRequest handles GET /payment-requests/{id}
+func (pc *PaymentRequestController) GetPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	pr, ok := pc.requests.Get(mux.Vars(r)["id"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Payment request not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pr)
+}
+
+// CancelPaymentRequest handles POST /payment-requests/{id}/cancel
+func (pc *PaymentRequestController) CancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	pr, err := pc.requests.Cancel(mux.Vars(r)["id"])
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentRequestNotFound):
+			respondWithError(w, http.StatusNotFound, "Payment request not found", err)
+		case errors.Is(err, service.ErrPaymentRequestNotPending):
+			respondWithError(w, http.StatusConflict, "Payment request not cancelled", err)
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Payment request not cancelled", err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pr)
+}
+
+// RecordCredit handles POST /payment-requests/credits, the notification of a credit
+// that arrived from outside the gateway, such as an inbound UPI payment. It reports
+// which request, if any, the credit paid.
+func (pc *PaymentRequestController) RecordCredit(w http.ResponseWriter, r *http.Request) {
+	var credit model.IncomingCredit
+	if err := json.NewDecoder(r.Body).Decode(&credit); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	if credit.TransactionID == "" || credit.Amount <= 0 || (credit.AccountID == "" && credit.VPA == "" && credit.Reference == "") {
+		respondWithError(w, http.StatusBadRequest, "transaction_id, amount and one of account_id, vpa or reference are required", nil)
+		return
+	}
+
+	pr := pc.requests.Fulfil(&credit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"fulfilled":       pr != nil,
+		"payment_request": pr,
+	})
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// receiptPage renders a receipt for whoever opens its link. It loads nothing from
+// elsewhere, so the page's CSP can forbid everything but its inline style.
+var receiptPage = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Payment receipt</title>
+<style>
+body { font-family: sans-serif; max-width: 28rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { font-size: 1.25rem; }
+.amount { font-size: 2rem; margin: 0.5rem 0; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 0.4rem 0; border-bottom: 1px solid #eee; }
+td:first-child { color: #666; }
+.note { color: #666; font-size: 0.85rem; margin-top: 1.5rem; }
+</style>
+</head>
+<body>
+{{- with .Receipt}}
+<h1>Payment receipt</h1>
+<div class="amount">{{.Currency}} {{printf "%.2f" .Amount}}</div>
+<table>
+<tr><td>Status</td><td>{{.Status}}</td></tr>
+<tr><td>Paid via</td><td>{{.Rail}}</td></tr>
+{{- if .Reference}}
+<tr><td>Reference</td><td>{{.Reference}}</td></tr>
+{{- end}}
+<tr><td>Transaction</td><td>{{.TransactionID}}</td></tr>
+{{- if .FromAccount}}
+<tr><td>From account</td><td>{{.FromAccount}}</td></tr>
+{{- end}}
+{{- if .ToAccount}}
+<tr><td>To account</td><td>{{.ToAccount}}</td></tr>
+{{- end}}
+{{- if .PayeeBank}}
+<tr><td>Payee bank</td><td>{{.PayeeBank}}</td></tr>
+{{- end}}
+<tr><td>Paid at</td><td>{{.PaidAt.Format "02 Jan 2006 15:04 MST"}}</td></tr>
+{{- if .CompletedAt}}
+<tr><td>Completed at</td><td>{{.CompletedAt.Format "02 Jan 2006 15:04 MST"}}</td></tr>
+{{- end}}
+</table>
+<p class="note">This link stops working at {{.LinkExpiresAt.Format "02 Jan 2006 15:04 MST"}}.</p>
+{{- else}}
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// ReceiptController handles shareable receipt links: creating them for a user's
+// transfer, and showing the receipt to whoever opens one
+type ReceiptController struct {
+	receipts *service.ReceiptService
+}
+
+// NewReceiptController creates a new receipt controller
+func NewReceiptController(receipts *service.ReceiptService) *ReceiptController {
+	return &ReceiptController{
+		receipts: receipts,
+	}
+}
+
+// CreateLink handles POST /receipts/links. Without a transaction_id the link is for
+// the user's most recent transfer.
+func (rc *ReceiptController) CreateLink(w http.ResponseWriter, r *http.Request) {
+	var req model.ReceiptLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	link, err := rc.receipts.CreateLink(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReceiptsDisabled):
+			respondWithError(w, http.StatusServiceUnavailable, "Receipt links are disabled", err)
+		case errors.Is(err, service.ErrReceiptNotFound):
+			respondWithError(w, http.StatusNotFound, "No transfer to share a receipt for", err)
+		case errors.Is(err, service.ErrReceiptUnavailable):
+			respondWithError(w, http.StatusConflict, "Failed to create receipt link", err)
+		default:
+			respondWithError(w, http.StatusBadRequest, "Failed to create receipt link", err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, link)
+}
+
+// ViewReceipt handles GET /receipts/{token}, the public page a receipt link opens.
+// No API key is needed; the token is the authorization. Clients asking for JSON get
+// the receipt as JSON.
+func (rc *ReceiptController) ViewReceipt(w http.ResponseWriter, r *http.Request) {
+	// Keep the receipt out of caches, search engines and the referrers of any link
+	// followed from it
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+
+	receipt, err := rc.receipts.Open(r.Context(), mux.Vars(r)["token"])
+	wantsJSON := strings.Contains(r.Header.Get("Accept"), "application/json")
+	if err != nil {
+		code, title, message := http.StatusNotFound, "Receipt not found", "This receipt link is not valid."
+		switch {
+		case errors.Is(err, service.ErrReceiptLinkExpired):
+			code, title, message = http.StatusGone, "Receipt link expired", "This receipt link has expired. Ask the sender for a new one."
+		case errors.Is(err, service.ErrReceiptNotFound):
+			message = "The transfer on this receipt can no longer be found."
+		}
+		if wantsJSON {
+			respondWithError(w, code, title, nil)
+			return
+		}
+		renderReceipt(w, code, map[string]interface{}{"Title": title, "Message": message})
+		return
+	}
+
+	if wantsJSON {
+		respondWithJSON(w, http.StatusOK, receipt)
+		return
+	}
+	renderReceipt(w, http.StatusOK, map[string]interface{}{"Receipt": receipt})
+}
+
+// renderReceipt writes the receipt page
+func renderReceipt(w http.ResponseWriter, code int, data map[string]interface{}) {
+	var page strings.Builder
+	if err := receiptPage.Execute(&page, data); err != nil {
+		log.Error().Err(err).Msg("Failed to render receipt")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write([]byte(page.String()))
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// ScoringController handles the credit-score refresh admin API
+type ScoringController struct {
+	job   *service.ScoreJob
+	store *service.ScoreStore
+}
+
+// NewScoringController creates a new scoring controller
+func NewScoringController(job *service.ScoreJob, store *service.ScoreStore) *ScoringController {
+	return &ScoringController{
+		job:   job,
+		store: store,
+	}
+}
+
+// StartRun handles POST /admin/scoring/runs
+func (sc *ScoringController) StartRun(w http.ResponseWriter, r *http.Request) {
+	trigger := r.URL.Query().Get("trigger")
+	if trigger == "" {
+		trigger = "api"
+	}
+
+	run, err := sc.job.Start(trigger)
+	if errors.Is(err, service.ErrScoringRunInProgress) {
+		respondWithError(w, http.StatusConflict, "A credit-score refresh is already running", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to start credit-score refresh", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, run)
+}
+
+// ListRuns handles GET /admin/scoring/runs
+func (sc *ScoringController) ListRuns(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"runs": sc.store.Runs(),
+	})
+}
+
+// GetRun handles GET /admin/scoring/runs/{runID}
+func (sc *ScoringController) GetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := sc.store.Run(mux.Vars(r)["runID"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Scoring run not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// GetUserScores handles GET /admin/scoring/users/{userID}
+func (sc *ScoringController) GetUserScores(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	history := sc.store.History(userID)
+	if len(history) == 0 {
+		respondWithError(w, http.StatusNotFound, "No refreshed scores for user", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id": userID,
+		"latest":  history[0],
+		"history": history,
+	})
+}
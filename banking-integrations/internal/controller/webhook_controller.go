@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/banking-integrations/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// WebhookController handles webhook subscriptions for external systems and the
+// deliveries made to them
+type WebhookController struct {
+	webhooks *service.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(webhooks *service.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhooks: webhooks,
+	}
+}
+
+// CreateWebhook handles POST /webhooks. The secret payloads are signed with is only
+// returned here.
+func (wc *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req model.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	sub, err := wc.webhooks.Subscribe(&req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook subscription", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, sub)
+}
+
+// ListWebhooks handles GET /webhooks?event=transaction.created
+func (wc *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs := wc.webhooks.List(r.URL.Query().Get("event"))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+// GetWebhook handles GET /webhooks/{id}
+func (wc *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := wc.webhooks.Get(mux.Vars(r)["id"])
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Webhook subscription not found", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sub)
+}
+
+// DeleteWebhook handles DELETE /webhooks/{id}
+func (wc *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := wc.webhooks.Unsubscribe(id); err != nil {
+		respondWithError(w, http.StatusNotFound, "Webhook subscription not found", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      id,
+		"deleted": true,
+	})
+}
+
+// ListDeliveries handles GET /webhooks/deliveries?subscription_id=&status=&limit=50,
+// newest first
+func (wc *WebhookController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	wc.respondDeliveries(w, r, strings.ToUpper(r.URL.Query().Get("status")))
+}
+
+// ListDeadLetters handles GET /webhooks/dead-letters?subscription_id=&limit=50, the
+// deliveries that ran out of attempts
+func (wc *WebhookController) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	wc.respondDeliveries(w, r, model.DeliveryDead)
+}
+
+func (wc *WebhookController) respondDeliveries(w http.ResponseWriter, r *http.Request, status string) {
+	query := r.URL.Query()
+	limit := 50
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a positive integer", err)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries := wc.webhooks.Deliveries(query.Get("subscription_id"), status, limit)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// RetryDelivery handles POST /webhooks/deliveries/{deliveryID}/retry, sending a
+// dead-lettered delivery again
+func (wc *WebhookController) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := wc.webhooks.Retry(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDeliveryNotFound):
+			respondWithError(w, http.StatusNotFound, "Webhook delivery not found", err)
+		case errors.Is(err, service.ErrDeliveryNotDead), errors.Is(err, service.ErrWebhookNotFound):
+			respondWithError(w, http.StatusConflict, "Webhook delivery not retried", err)
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Webhook delivery not retried", err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,177 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Status report definition acknowledgments are read as
+const (
+	Pacs002Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.002.001.10"
+	Pacs002Name      = "pacs.002.001.10"
+)
+
+// Transaction statuses (ExternalPaymentTransactionStatus1Code) a status report can carry
+var transactionStatuses = map[string]bool{
+	"ACCP": true, // Accepted customer profile
+	"ACSC": true, // Accepted, settlement completed
+	"ACSP": true, // Accepted, settlement in process
+	"ACTC": true, // Accepted technical validation
+	"ACWC": true, // Accepted with change
+	"PDNG": true, // Pending
+	"RCVD": true, // Received
+	"RJCT": true, // Rejected
+}
+
+// Pacs002Document is a pacs.002 FI-to-FI payment status report
+type Pacs002Document struct {
+	XMLName xml.Name        `xml:"Document"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Report  FIToFIPmtStsRpt `xml:"FIToFIPmtStsRpt"`
+}
+
+// FIToFIPmtStsRpt holds the report header and the statuses it reports
+type FIToFIPmtStsRpt struct {
+	GrpHdr            StatusGroupHeader     `xml:"GrpHdr"`
+	OrgnlGrpInfAndSts []OriginalGroupStatus `xml:"OrgnlGrpInfAndSts"`
+	TxInfAndSts       []TransactionStatus   `xml:"TxInfAndSts"`
+}
+
+// StatusGroupHeader identifies the report
+type StatusGroupHeader struct {
+	MsgId   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+// OriginalGroupStatus is the status of a whole original message
+type OriginalGroupStatus struct {
+	OrgnlMsgId   string         `xml:"OrgnlMsgId"`
+	OrgnlMsgNmId string         `xml:"OrgnlMsgNmId"`
+	GrpSts       string         `xml:"GrpSts,omitempty"`
+	StsRsnInf    []StatusReason `xml:"StsRsnInf"`
+}
+
+// TransactionStatus is the status of one original transaction
+type TransactionStatus struct {
+	StsId           string         `xml:"StsId,omitempty"`
+	OrgnlInstrId    string         `xml:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId string         `xml:"OrgnlEndToEndId,omitempty"`
+	OrgnlTxId       string         `xml:"OrgnlTxId,omitempty"`
+	TxSts           string         `xml:"TxSts,omitempty"`
+	StsRsnInf       []StatusReason `xml:"StsRsnInf"`
+}
+
+// StatusReason explains a status, e.g. why a transfer was rejected
+type StatusReason struct {
+	Rsn      *StatusReasonCode `xml:"Rsn,omitempty"`
+	AddtlInf []string          `xml:"AddtlInf"`
+}
+
+// StatusReasonCode is an ISO reason code (e.g. AC01) or a proprietary one
+type StatusReasonCode struct {
+	Cd    string `xml:"Cd,omitempty"`
+	Prtry string `xml:"Prtry,omitempty"`
+}
+
+// StatusReport is what an acknowledgment says about one original message
+type StatusReport struct {
+	MessageID         string // The report's own ID
+	CreatedAt         string
+	OriginalMessageID string
+	EndToEndID        string
+	TransactionID     string
+	Status            string // Transaction status, or the group status when no transaction is reported
+	ReasonCode        string
+	AdditionalInfo    string
+}
+
+// ParsePacs002 reads and validates a pacs.002 status report about a pacs.008
+// message. Only the first original group and transaction are read, since NEFT
+// and RTGS messages carry one transfer each.
+func ParsePacs002(data []byte) (*StatusReport, error) {
+	var doc Pacs002Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %s is not well-formed: %v", ErrInvalidMessage, Pacs002Name, err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	rpt := doc.Report
+	grp := rpt.OrgnlGrpInfAndSts[0]
+	report := &StatusReport{
+		MessageID:         rpt.GrpHdr.MsgId,
+		CreatedAt:         rpt.GrpHdr.CreDtTm,
+		OriginalMessageID: grp.OrgnlMsgId,
+		Status:            grp.GrpSts,
+	}
+	reasons := grp.StsRsnInf
+	if len(rpt.TxInfAndSts) > 0 {
+		tx := rpt.TxInfAndSts[0]
+		report.EndToEndID = tx.OrgnlEndToEndId
+		report.TransactionID = tx.OrgnlTxId
+		if tx.TxSts != "" {
+			report.Status = tx.TxSts
+		}
+		if len(tx.StsRsnInf) > 0 {
+			reasons = tx.StsRsnInf
+		}
+	}
+	if len(reasons) > 0 {
+		if reasons[0].Rsn != nil {
+			report.ReasonCode = reasons[0].Rsn.Cd
+			if report.ReasonCode == "" {
+				report.ReasonCode = reasons[0].Rsn.Prtry
+			}
+		}
+		report.AdditionalInfo = strings.Join(reasons[0].AddtlInf, " ")
+	}
+	return report, nil
+}
+
+// Validate checks the report against the pacs.002.001.10 schema rules, and that it
+// reports on a pacs.008 message with a status
+func (d *Pacs002Document) Validate() error {
+	v := &validator{}
+	if d.Xmlns != Pacs002Namespace {
+		v.fail("Document/@xmlns", "%q is not %s", d.Xmlns, Pacs002Namespace)
+	}
+
+	rpt := d.Report
+	v.text("GrpHdr/MsgId", rpt.GrpHdr.MsgId, 35, true)
+	v.dateTime("GrpHdr/CreDtTm", rpt.GrpHdr.CreDtTm)
+
+	if len(rpt.OrgnlGrpInfAndSts) == 0 {
+		v.fail("OrgnlGrpInfAndSts", "is required")
+		return v.err(Pacs002Name)
+	}
+	grp := rpt.OrgnlGrpInfAndSts[0]
+	v.text("OrgnlGrpInfAndSts/OrgnlMsgId", grp.OrgnlMsgId, 35, true)
+	v.text("OrgnlGrpInfAndSts/OrgnlMsgNmId", grp.OrgnlMsgNmId, 35, true)
+	if grp.OrgnlMsgNmId != "" && !strings.HasPrefix(grp.OrgnlMsgNmId, "pacs.008") {
+		v.fail("OrgnlGrpInfAndSts/OrgnlMsgNmId", "%q does not report on a pacs.008 message", grp.OrgnlMsgNmId)
+	}
+	if grp.GrpSts != "" && !transactionStatuses[grp.GrpSts] {
+		v.fail("OrgnlGrpInfAndSts/GrpSts", "%q is not an allowed code", grp.GrpSts)
+	}
+
+	status := grp.GrpSts
+	for i, tx := range rpt.TxInfAndSts {
+		path := fmt.Sprintf("TxInfAndSts[%d]", i+1)
+		v.text(path+"/OrgnlEndToEndId", tx.OrgnlEndToEndId, 35, false)
+		v.text(path+"/OrgnlTxId", tx.OrgnlTxId, 35, false)
+		if tx.TxSts != "" {
+			if !transactionStatuses[tx.TxSts] {
+				v.fail(path+"/TxSts", "%q is not an allowed code", tx.TxSts)
+			}
+			if i == 0 {
+				status = tx.TxSts
+			}
+		}
+	}
+	if status == "" {
+		v.fail("OrgnlGrpInfAndSts/GrpSts", "a group or transaction status is required")
+	}
+	return v.err(Pacs002Name)
+}
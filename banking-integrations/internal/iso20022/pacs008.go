@@ -0,0 +1,232 @@
+// Package iso20022 renders outgoing NEFT and RTGS transfers as ISO 20022
+// pacs.008 credit transfers, validates them against the schema's rules and reads
+// the pacs.002 status reports that acknowledge them.
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Message definitions this package speaks
+const (
+	Pacs008Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.008.001.08"
+	Pacs008Name      = "pacs.008.001.08"
+)
+
+// ClearingSystemIFSC is the ISO external clearing system code for India's IFSC
+const ClearingSystemIFSC = "INFSC"
+
+// Transfer is an outgoing transfer to render as a pacs.008 message
+type Transfer struct {
+	MessageID       string
+	EndToEndID      string
+	TransactionID   string
+	Rail            string // NEFT or RTGS
+	Amount          float64
+	Currency        string
+	CreatedAt       time.Time
+	SettlementDate  time.Time
+	DebtorName      string
+	DebtorAccount   string
+	DebtorIFSC      string // The sending bank's IFSC
+	CreditorName    string
+	CreditorAccount string
+	CreditorIFSC    string
+	Remittance      string
+}
+
+// Pacs008Document is a pacs.008 FI-to-FI customer credit transfer
+type Pacs008Document struct {
+	XMLName  xml.Name          `xml:"Document"`
+	Xmlns    string            `xml:"xmlns,attr"`
+	Transfer FIToFICstmrCdtTrf `xml:"FIToFICstmrCdtTrf"`
+}
+
+// FIToFICstmrCdtTrf holds the group header and the credit transfers
+type FIToFICstmrCdtTrf struct {
+	GrpHdr      GroupHeader            `xml:"GrpHdr"`
+	CdtTrfTxInf []CreditTransferTxInfo `xml:"CdtTrfTxInf"`
+}
+
+// GroupHeader identifies the message and how it settles
+type GroupHeader struct {
+	MsgId    string                `xml:"MsgId"`
+	CreDtTm  string                `xml:"CreDtTm"`
+	NbOfTxs  string                `xml:"NbOfTxs"`
+	SttlmInf SettlementInstruction `xml:"SttlmInf"`
+}
+
+// SettlementInstruction names the clearing system the transfer settles through
+type SettlementInstruction struct {
+	SttlmMtd string           `xml:"SttlmMtd"`
+	ClrSys   *ProprietaryCode `xml:"ClrSys,omitempty"`
+}
+
+// ProprietaryCode is a proprietary identification such as a clearing system name
+type ProprietaryCode struct {
+	Prtry string `xml:"Prtry"`
+}
+
+// CreditTransferTxInfo is one credit transfer
+type CreditTransferTxInfo struct {
+	PmtId          PaymentID        `xml:"PmtId"`
+	PmtTpInf       *PaymentTypeInfo `xml:"PmtTpInf,omitempty"`
+	IntrBkSttlmAmt Amount           `xml:"IntrBkSttlmAmt"`
+	IntrBkSttlmDt  string           `xml:"IntrBkSttlmDt,omitempty"`
+	ChrgBr         string           `xml:"ChrgBr"`
+	InstgAgt       *Agent           `xml:"InstgAgt,omitempty"`
+	InstdAgt       *Agent           `xml:"InstdAgt,omitempty"`
+	Dbtr           Party            `xml:"Dbtr"`
+	DbtrAcct       *CashAccount     `xml:"DbtrAcct,omitempty"`
+	DbtrAgt        Agent            `xml:"DbtrAgt"`
+	CdtrAgt        Agent            `xml:"CdtrAgt"`
+	Cdtr           Party            `xml:"Cdtr"`
+	CdtrAcct       *CashAccount     `xml:"CdtrAcct,omitempty"`
+	RmtInf         *RemittanceInfo  `xml:"RmtInf,omitempty"`
+}
+
+// PaymentID carries the references that follow the payment end to end
+type PaymentID struct {
+	InstrId    string `xml:"InstrId,omitempty"`
+	EndToEndId string `xml:"EndToEndId"`
+	TxId       string `xml:"TxId,omitempty"`
+}
+
+// PaymentTypeInfo names the local instrument (the rail)
+type PaymentTypeInfo struct {
+	LclInstrm *ProprietaryCode `xml:"LclInstrm,omitempty"`
+}
+
+// Amount is an amount with its currency
+type Amount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Agent is a bank identified by its clearing system member ID
+type Agent struct {
+	FinInstnId FinancialInstitutionID `xml:"FinInstnId"`
+}
+
+// FinancialInstitutionID identifies a bank
+type FinancialInstitutionID struct {
+	ClrSysMmbId *ClearingSystemMember `xml:"ClrSysMmbId,omitempty"`
+	Nm          string                `xml:"Nm,omitempty"`
+}
+
+// ClearingSystemMember is a bank's ID in a clearing system, here its IFSC
+type ClearingSystemMember struct {
+	ClrSysId ClearingSystemID `xml:"ClrSysId"`
+	MmbId    string           `xml:"MmbId"`
+}
+
+// ClearingSystemID names the clearing system by code
+type ClearingSystemID struct {
+	Cd string `xml:"Cd"`
+}
+
+// Party is the debtor or creditor
+type Party struct {
+	Nm string `xml:"Nm,omitempty"`
+}
+
+// CashAccount is an account identified by a local account number
+type CashAccount struct {
+	Id AccountID `xml:"Id"`
+}
+
+// AccountID holds a non-IBAN account number
+type AccountID struct {
+	Othr GenericAccountID `xml:"Othr"`
+}
+
+// GenericAccountID is an account number
+type GenericAccountID struct {
+	Id string `xml:"Id"`
+}
+
+// RemittanceInfo carries the payer's remarks
+type RemittanceInfo struct {
+	Ustrd string `xml:"Ustrd,omitempty"`
+}
+
+// NewPacs008 builds the pacs.008 document for a transfer
+func NewPacs008(t *Transfer) *Pacs008Document {
+	created := t.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	settlement := t.SettlementDate
+	if settlement.IsZero() {
+		settlement = created
+	}
+
+	tx := CreditTransferTxInfo{
+		PmtId: PaymentID{
+			InstrId:    t.MessageID,
+			EndToEndId: t.EndToEndID,
+			TxId:       t.TransactionID,
+		},
+		PmtTpInf:       &PaymentTypeInfo{LclInstrm: &ProprietaryCode{Prtry: t.Rail}},
+		IntrBkSttlmAmt: Amount{Ccy: t.Currency, Value: formatAmount(t.Amount)},
+		IntrBkSttlmDt:  settlement.Format("2006-01-02"),
+		ChrgBr:         "SLEV", // Charges follow the clearing system's service level
+		InstgAgt:       ifscAgent(t.DebtorIFSC),
+		InstdAgt:       ifscAgent(t.CreditorIFSC),
+		Dbtr:           Party{Nm: t.DebtorName},
+		DbtrAcct:       &CashAccount{Id: AccountID{Othr: GenericAccountID{Id: t.DebtorAccount}}},
+		DbtrAgt:        *ifscAgent(t.DebtorIFSC),
+		CdtrAgt:        *ifscAgent(t.CreditorIFSC),
+		Cdtr:           Party{Nm: t.CreditorName},
+		CdtrAcct:       &CashAccount{Id: AccountID{Othr: GenericAccountID{Id: t.CreditorAccount}}},
+	}
+	if t.Remittance != "" {
+		tx.RmtInf = &RemittanceInfo{Ustrd: t.Remittance}
+	}
+
+	return &Pacs008Document{
+		Xmlns: Pacs008Namespace,
+		Transfer: FIToFICstmrCdtTrf{
+			GrpHdr: GroupHeader{
+				MsgId:   t.MessageID,
+				CreDtTm: created.Format("2006-01-02T15:04:05.000-07:00"),
+				NbOfTxs: "1",
+				SttlmInf: SettlementInstruction{
+					SttlmMtd: "CLRG",
+					ClrSys:   &ProprietaryCode{Prtry: t.Rail},
+				},
+			},
+			CdtTrfTxInf: []CreditTransferTxInfo{tx},
+		},
+	}
+}
+
+// RenderPacs008 builds, validates and serializes the pacs.008 message for a transfer
+func RenderPacs008(t *Transfer) ([]byte, error) {
+	doc := NewPacs008(t)
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pacs.008: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// ifscAgent identifies a bank by its IFSC
+func ifscAgent(ifsc string) *Agent {
+	return &Agent{FinInstnId: FinancialInstitutionID{
+		ClrSysMmbId: &ClearingSystemMember{ClrSysId: ClearingSystemID{Cd: ClearingSystemIFSC}, MmbId: ifsc},
+	}}
+}
+
+// formatAmount renders an amount in paise precision, as INR has two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount*100)/100, 'f', 2, 64)
+}
@@ -0,0 +1,204 @@
+package iso20022
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidMessage is wrapped by every validation failure
+var ErrInvalidMessage = errors.New("message does not conform to the ISO 20022 schema")
+
+// ValidationError lists every rule a message breaks, each as "path: problem"
+type ValidationError struct {
+	Message    string // Message definition, e.g. pacs.008.001.08
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidMessage, e.Message, strings.Join(e.Violations, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidMessage
+}
+
+// Simple type patterns from the pacs.008.001.08 and pacs.002.001.10 schemas, plus
+// the RBI format of an IFSC: four letters, a zero and six letters or digits
+var (
+	numericTextPattern = regexp.MustCompile(`^[0-9]{1,15}$`)
+	currencyPattern    = regexp.MustCompile(`^[A-Z]{3}$`)
+	amountPattern      = regexp.MustCompile(`^[0-9]{1,13}(\.[0-9]{1,5})?$`) // totalDigits 18, fractionDigits 5
+	ifscPattern        = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+)
+
+// Code lists the schema restricts elements to
+var (
+	settlementMethods = map[string]bool{"INDA": true, "INGA": true, "COVE": true, "CLRG": true}
+	chargeBearers     = map[string]bool{"DEBT": true, "CRED": true, "SHAR": true, "SLEV": true}
+	rails             = map[string]bool{"NEFT": true, "RTGS": true}
+)
+
+// validator collects violations as a document is walked
+type validator struct {
+	violations []string
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+// text checks a MaxNText element: required ones must have at least one character
+func (v *validator) text(path, value string, max int, required bool) {
+	if value == "" {
+		if required {
+			v.fail(path, "is required")
+		}
+		return
+	}
+	if n := utf8.RuneCountInString(value); n > max {
+		v.fail(path, "is %d characters, the limit is %d", n, max)
+	}
+}
+
+// pattern checks a required element against a pattern
+func (v *validator) pattern(path, value string, re *regexp.Regexp, what string) {
+	if value == "" {
+		v.fail(path, "is required")
+	} else if !re.MatchString(value) {
+		v.fail(path, "%q is not %s", value, what)
+	}
+}
+
+// code checks a required element against a code list
+func (v *validator) code(path, value string, codes map[string]bool) {
+	if value == "" {
+		v.fail(path, "is required")
+	} else if !codes[value] {
+		v.fail(path, "%q is not an allowed code", value)
+	}
+}
+
+// dateTime checks an ISODateTime element
+func (v *validator) dateTime(path, value string) {
+	if value == "" {
+		v.fail(path, "is required")
+		return
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return
+		}
+	}
+	v.fail(path, "%q is not an ISO date and time", value)
+}
+
+// date checks an optional ISODate element
+func (v *validator) date(path, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		v.fail(path, "%q is not an ISO date", value)
+	}
+}
+
+// amount checks an ActiveCurrencyAndAmount, which a transfer needs to be positive
+func (v *validator) amount(path string, amt Amount) {
+	v.pattern(path+"/@Ccy", amt.Ccy, currencyPattern, "an ISO 4217 currency code")
+	if !amountPattern.MatchString(amt.Value) {
+		v.fail(path, "%q is not a decimal amount of at most 18 digits and 5 decimals", amt.Value)
+		return
+	}
+	if value, _ := strconv.ParseFloat(amt.Value, 64); value <= 0 {
+		v.fail(path, "must be greater than zero")
+	}
+}
+
+// ifscAgent checks a bank identified by its IFSC
+func (v *validator) ifscAgent(path string, agent *Agent) {
+	if agent == nil || agent.FinInstnId.ClrSysMmbId == nil {
+		v.fail(path+"/FinInstnId/ClrSysMmbId", "is required")
+		return
+	}
+	member := agent.FinInstnId.ClrSysMmbId
+	if member.ClrSysId.Cd != ClearingSystemIFSC {
+		v.fail(path+"/FinInstnId/ClrSysMmbId/ClrSysId/Cd", "%q is not %s", member.ClrSysId.Cd, ClearingSystemIFSC)
+	}
+	v.pattern(path+"/FinInstnId/ClrSysMmbId/MmbId", member.MmbId, ifscPattern, "an IFSC")
+}
+
+// account checks a debtor or creditor account
+func (v *validator) account(path string, acct *CashAccount) {
+	if acct == nil {
+		v.fail(path, "is required")
+		return
+	}
+	v.text(path+"/Id/Othr/Id", acct.Id.Othr.Id, 34, true)
+}
+
+// err returns the collected violations, if any
+func (v *validator) err(message string) error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Message: message, Violations: v.violations}
+}
+
+// Validate checks the document against the pacs.008.001.08 schema rules this
+// package renders, and the NEFT/RTGS market practice: one transfer per message,
+// settled through the rail's clearing system between banks identified by IFSC.
+func (d *Pacs008Document) Validate() error {
+	v := &validator{}
+	if d.Xmlns != Pacs008Namespace {
+		v.fail("Document/@xmlns", "%q is not %s", d.Xmlns, Pacs008Namespace)
+	}
+
+	hdr := d.Transfer.GrpHdr
+	v.text("GrpHdr/MsgId", hdr.MsgId, 35, true)
+	v.dateTime("GrpHdr/CreDtTm", hdr.CreDtTm)
+	v.pattern("GrpHdr/NbOfTxs", hdr.NbOfTxs, numericTextPattern, "a number")
+	if hdr.NbOfTxs != strconv.Itoa(len(d.Transfer.CdtTrfTxInf)) {
+		v.fail("GrpHdr/NbOfTxs", "is %s but the message has %d transactions", hdr.NbOfTxs, len(d.Transfer.CdtTrfTxInf))
+	}
+	v.code("GrpHdr/SttlmInf/SttlmMtd", hdr.SttlmInf.SttlmMtd, settlementMethods)
+	if hdr.SttlmInf.ClrSys != nil {
+		v.code("GrpHdr/SttlmInf/ClrSys/Prtry", hdr.SttlmInf.ClrSys.Prtry, rails)
+	}
+
+	if len(d.Transfer.CdtTrfTxInf) != 1 {
+		v.fail("CdtTrfTxInf", "NEFT and RTGS messages carry exactly one transaction")
+	}
+	for i, tx := range d.Transfer.CdtTrfTxInf {
+		path := fmt.Sprintf("CdtTrfTxInf[%d]", i+1)
+		v.text(path+"/PmtId/InstrId", tx.PmtId.InstrId, 35, false)
+		v.text(path+"/PmtId/EndToEndId", tx.PmtId.EndToEndId, 35, true)
+		v.text(path+"/PmtId/TxId", tx.PmtId.TxId, 35, false)
+		if tx.PmtTpInf != nil && tx.PmtTpInf.LclInstrm != nil {
+			v.code(path+"/PmtTpInf/LclInstrm/Prtry", tx.PmtTpInf.LclInstrm.Prtry, rails)
+		}
+		v.amount(path+"/IntrBkSttlmAmt", tx.IntrBkSttlmAmt)
+		v.date(path+"/IntrBkSttlmDt", tx.IntrBkSttlmDt)
+		v.code(path+"/ChrgBr", tx.ChrgBr, chargeBearers)
+		if tx.InstgAgt != nil {
+			v.ifscAgent(path+"/InstgAgt", tx.InstgAgt)
+		}
+		if tx.InstdAgt != nil {
+			v.ifscAgent(path+"/InstdAgt", tx.InstdAgt)
+		}
+		v.text(path+"/Dbtr/Nm", tx.Dbtr.Nm, 140, true)
+		v.account(path+"/DbtrAcct", tx.DbtrAcct)
+		v.ifscAgent(path+"/DbtrAgt", &tx.DbtrAgt)
+		v.ifscAgent(path+"/CdtrAgt", &tx.CdtrAgt)
+		v.text(path+"/Cdtr/Nm", tx.Cdtr.Nm, 140, true)
+		v.account(path+"/CdtrAcct", tx.CdtrAcct)
+		if tx.RmtInf != nil {
+			v.text(path+"/RmtInf/Ustrd", tx.RmtInf.Ustrd, 140, false)
+		}
+	}
+	return v.err(Pacs008Name)
+}
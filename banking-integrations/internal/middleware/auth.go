@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/aibanking/banking-integrations/internal/config"
 )
@@ -9,7 +10,8 @@ import (
 // AuthMiddleware validates API key from header
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/health" {
+		// Receipt and export links are sealed, so opening one needs no API key
+		if r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/receipts/") || strings.HasPrefix(r.URL.Path, "/exports/") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -0,0 +1,7 @@
+package middleware
+
+import "github.com/aibanking/shared/response"
+
+// CompressionMiddleware compresses responses with gzip or deflate when the client
+// accepts it
+var CompressionMiddleware = response.Compress
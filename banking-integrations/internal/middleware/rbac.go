@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/shared/audit"
+	"github.com/rs/zerolog/log"
+)
+
+type operatorKey struct{}
+
+// BackOfficeAuth admits only API keys granted the back-office role and identifies
+// the operator behind each request
+type BackOfficeAuth struct {
+	operators map[string]string // API key -> operator ID
+}
+
+// NewBackOfficeAuth creates the back-office role check from RBAC configuration
+func NewBackOfficeAuth(cfg *config.RBACConfig) *BackOfficeAuth {
+	if len(cfg.BackOffice) == 0 {
+		log.Warn().Msg("No API key has the back-office role; back-office endpoints are disabled")
+	}
+	return &BackOfficeAuth{operators: cfg.BackOffice}
+}
+
+// Middleware refuses requests whose API key does not have the back-office role
+func (b *BackOfficeAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := r.Header.Get(config.AppConfig.Security.APIKeyHeader)
+		operator, ok := b.operators[apiKey]
+		if !ok {
+			log.Warn().Str("path", r.URL.Path).Msg("Back-office request refused: API key lacks the back-office role")
+			http.Error(w, "Forbidden: back-office role required", http.StatusForbidden)
+			return
+		}
+
+		// The access record names the operator rather than the key
+		audit.SetActor(r.Context(), "operator:"+operator)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), operatorKey{}, operator)))
+	})
+}
+
+// AgentAuth admits only API keys granted the agent role, for the routes through
+// which agents act on accounts without an operator, such as fraud signals
+type AgentAuth struct {
+	agents map[string]string // API key -> agent ID
+}
+
+// NewAgentAuth creates the agent role check from RBAC configuration
+func NewAgentAuth(cfg *config.RBACConfig) *AgentAuth {
+	if len(cfg.Agents) == 0 {
+		log.Warn().Msg("No API key has the agent role; fraud signals are refused")
+	}
+	return &AgentAuth{agents: cfg.Agents}
+}
+
+// Middleware refuses requests whose API key does not have the agent role
+func (a *AgentAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := r.Header.Get(config.AppConfig.Security.APIKeyHeader)
+		agent, ok := a.agents[apiKey]
+		if !ok {
+			log.Warn().Str("path", r.URL.Path).Msg("Agent request refused: API key lacks the agent role")
+			http.Error(w, "Forbidden: agent role required", http.StatusForbidden)
+			return
+		}
+
+		audit.SetActor(r.Context(), "agent:"+agent)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// OperatorFromContext returns the back-office operator who made a request
+func OperatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
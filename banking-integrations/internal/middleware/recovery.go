@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aibanking/shared/recovery"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// CorrelationHeader carries the ID that ties a failed response to its log entry
+const CorrelationHeader = recovery.CorrelationHeader
+
+// Recovery turns a panic in a handler into a 500 with a correlation ID and counts
+// panics per route for GET /admin/panics
+type Recovery = recovery.Recovery
+
+// NewRecovery creates the panic-recovery middleware, counting panics by mux route
+// template and logging each one
+func NewRecovery(cfg recovery.Config) *Recovery {
+	return recovery.New(cfg, routeTemplate, logPanic)
+}
+
+// routeTemplate names the route a request matched, e.g. GET /api/v1/tasks/{id}
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+	return r.Method + " " + r.URL.Path
+}
+
+// logPanic logs a recovered panic, with its stack when one is handed over
+func logPanic(e recovery.Event) {
+	event := log.Error().
+		Str("correlation_id", e.CorrelationID).
+		Str("fingerprint", e.Fingerprint).
+		Str("method", e.Method).
+		Str("route", e.Route).
+		Str("panic", e.Panic).
+		Str("location", e.Location).
+		Int64("occurrences", e.Occurrences)
+	if e.Stack != "" {
+		event = event.Str("stack", e.Stack)
+	}
+	event.Msg("Recovered from panic")
+}
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// AccountStatus is whether money may move on an account
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "ACTIVE"
+	AccountFrozen AccountStatus = "FROZEN" // Debits refused; credits still accepted
+	AccountLocked AccountStatus = "LOCKED" // Suspicious activity: debits and credits refused
+)
+
+// Sources of an account restriction
+const (
+	RestrictionSourceFraud    = "fraud"    // Placed automatically on a high-severity fraud outcome
+	RestrictionSourceOperator = "operator" // Placed by back-office staff
+)
+
+// AccountRestriction freezes or locks an account, or every account of a user when
+// AccountID is empty. It stays in force until an operator lifts it.
+type AccountRestriction struct {
+	ID         string        `json:"id"`
+	UserID     string        `json:"user_id,omitempty"`
+	AccountID  string        `json:"account_id,omitempty"` // Empty for every account of the user
+	Status     AccountStatus `json:"status"`               // FROZEN or LOCKED
+	Reason     string        `json:"reason"`
+	Source     string        `json:"source"`
+	PlacedBy   string        `json:"placed_by"`
+	PlacedAt   time.Time     `json:"placed_at"`
+	FraudScore float64       `json:"fraud_score,omitempty"`
+	RequestID  string        `json:"request_id,omitempty"` // Fraud check that triggered it
+	Active     bool          `json:"active"`
+	LiftedBy   string        `json:"lifted_by,omitempty"`
+	LiftedAt   *time.Time    `json:"lifted_at,omitempty"`
+	LiftReason string        `json:"lift_reason,omitempty"`
+}
+
+// AccountStatusResponse is the effective status of an account: the most severe of
+// the restrictions in force on it
+type AccountStatusResponse struct {
+	UserID       string               `json:"user_id,omitempty"`
+	AccountID    string               `json:"account_id,omitempty"`
+	Status       AccountStatus        `json:"status"`
+	Restrictions []AccountRestriction `json:"restrictions"`
+}
+
+// FraudSignal reports a fraud outcome; a severe enough one restricts the account
+type FraudSignal struct {
+	UserID     string   `json:"user_id"`
+	AccountID  string   `json:"account_id,omitempty"` // Account debited; empty restricts every account of the user
+	FraudScore float64  `json:"fraud_score"`
+	Decision   string   `json:"decision,omitempty"` // Fraud agent's decision, e.g. REJECTED
+	RequestID  string   `json:"request_id,omitempty"`
+	Flags      []string `json:"flags,omitempty"`
+}
+
+// FraudSignalResponse says whether a fraud signal restricted an account
+type FraudSignalResponse struct {
+	Action      string              `json:"action"` // none, frozen, locked or already_restricted
+	Restriction *AccountRestriction `json:"restriction,omitempty"`
+}
+
+// RestrictionRequest is an operator freezing or locking an account
+type RestrictionRequest struct {
+	UserID    string        `json:"user_id,omitempty"`
+	AccountID string        `json:"account_id,omitempty"`
+	Status    AccountStatus `json:"status"` // FROZEN (default) or LOCKED
+	Reason    string        `json:"reason"`
+}
+
+// LiftRequest is an operator unfreezing an account
+type LiftRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Audit actions recorded for account restrictions
+const (
+	AuditAccountFrozen   = "ACCOUNT_FROZEN"
+	AuditAccountLocked   = "ACCOUNT_LOCKED"
+	AuditAccountUnfrozen = "ACCOUNT_UNFROZEN"
+)
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// AdjustmentStatus is where a balance adjustment is in its maker-checker review
+type AdjustmentStatus string
+
+const (
+	AdjustmentPending  AdjustmentStatus = "PENDING"
+	AdjustmentApplying AdjustmentStatus = "APPLYING" // Approved, being posted to the account
+	AdjustmentApproved AdjustmentStatus = "APPROVED"
+	AdjustmentRejected AdjustmentStatus = "REJECTED"
+	AdjustmentExpired  AdjustmentStatus = "EXPIRED"
+)
+
+// TransactionTypeAdjustment marks ledger entries posted by a balance adjustment
+const TransactionTypeAdjustment TransactionType = "ADJUSTMENT"
+
+// ChannelBackOffice is the channel of ledger entries posted by back-office staff
+const ChannelBackOffice Channel = "BACKOFFICE"
+
+// BalanceAdjustment is a back-office correction to an account balance. One
+// operator (the maker) raises it and a different one (the checker) approves it
+// before it is posted.
+type BalanceAdjustment struct {
+	ID            string           `json:"id"`
+	AccountID     string           `json:"account_id"`
+	UserID        string           `json:"user_id"`
+	Amount        float64          `json:"amount"` // Positive credits, negative debits
+	Currency      string           `json:"currency"`
+	Reason        string           `json:"reason"`
+	Reference     string           `json:"reference,omitempty"` // e.g. a support ticket
+	Status        AdjustmentStatus `json:"status"`
+	Maker         string           `json:"maker"`
+	CreatedAt     time.Time        `json:"created_at"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+	Checker       string           `json:"checker,omitempty"`
+	DecidedAt     *time.Time       `json:"decided_at,omitempty"`
+	DecisionNote  string           `json:"decision_note,omitempty"`
+	BalanceBefore *float64         `json:"balance_before,omitempty"`
+	BalanceAfter  *float64         `json:"balance_after,omitempty"`
+	LedgerEntryID string           `json:"ledger_entry_id,omitempty"`
+}
+
+// AdjustmentRequest raises a balance adjustment
+type AdjustmentRequest struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+	Reference string  `json:"reference,omitempty"`
+}
+
+// AdjustmentDecision approves or rejects a balance adjustment
+type AdjustmentDecision struct {
+	Note string `json:"note,omitempty"`
+}
+
+// Audit actions recorded for balance adjustments
+const (
+	AuditAdjustmentCreated  = "ADJUSTMENT_CREATED"
+	AuditAdjustmentApproved = "ADJUSTMENT_APPROVED"
+	AuditAdjustmentRejected = "ADJUSTMENT_REJECTED"
+	AuditAdjustmentExpired  = "ADJUSTMENT_EXPIRED"
+	AuditAdjustmentRefused  = "ADJUSTMENT_REFUSED" // An approval that was not allowed
+)
+
+// AuditRecord is one entry in the back-office audit trail
+type AuditRecord struct {
+	ID            string    `json:"id"`
+	At            time.Time `json:"at"`
+	Operator      string    `json:"operator"`
+	Action        string    `json:"action"`
+	AdjustmentID  string    `json:"adjustment_id,omitempty"`
+	RestrictionID string    `json:"restriction_id,omitempty"`
+	AccountID     string    `json:"account_id,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	Detail        string    `json:"detail,omitempty"`
+}
@@ -1,14 +1,18 @@
 package model
 
-import "time"
+import (
+	"time"
 
-// Channel represents the banking channel
-type Channel string
+	"github.com/aibanking/shared/channel"
+)
+
+// Channel represents the banking channel, one of the channels shared by every service
+type Channel = channel.Channel
 
 const (
-	ChannelMB Channel = "MB" // Mobile Banking
-	ChannelNB Channel = "NB" // Net Banking
-	ChannelAPI Channel = "API" // API Banking
+	ChannelMB  = channel.MB  // Mobile Banking
+	ChannelNB  = channel.NB  // Net Banking
+	ChannelAPI = channel.API // API Banking
 )
 
 // TransactionType represents transaction type
@@ -78,6 +82,7 @@ type Beneficiary struct {
 	Status          string    `json:"status"` // ACTIVE, INACTIVE
 	AddedAt         time.Time `json:"added_at"`
 	LastUsed        *time.Time `json:"last_used,omitempty"`
+	Simulated       bool       `json:"simulated,omitempty"`
 }
 
 // StatementRequest represents a statement request
@@ -88,6 +93,7 @@ type StatementRequest struct {
 	EndDate   time.Time `json:"end_date"`
 	Channel   Channel   `json:"channel"`
 	Limit     int       `json:"limit,omitempty"`
+	Sandbox   bool      `json:"sandbox,omitempty"`
 }
 
 // StatementResponse represents statement response
@@ -98,6 +104,7 @@ type StatementResponse struct {
 	Transactions []Transaction `json:"transactions"`
 	Count        int           `json:"count"`
 	GeneratedAt  time.Time     `json:"generated_at"`
+	Simulated    bool          `json:"simulated,omitempty"`
 }
 
 // TransferRequest represents a fund transfer request
@@ -106,22 +113,33 @@ type TransferRequest struct {
 	FromAccount string          `json:"from_account"`
 	ToAccount   string          `json:"to_account"`
 	IFSC        string          `json:"ifsc,omitempty"`
+	PayeeName   string          `json:"payee_name,omitempty"` // Named in NEFT/RTGS payment messages
 	Amount      float64         `json:"amount"`
 	Type        TransactionType `json:"type"`
 	Remarks     string          `json:"remarks,omitempty"`
 	Channel     Channel         `json:"channel"`
+	Sandbox     bool            `json:"sandbox,omitempty"`
+
+	// IdempotencyKey makes the transfer safe to send again: a repeat with the same key
+	// gets the first transfer's response instead of moving money twice
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // TransferResponse represents transfer response
 type TransferResponse struct {
-	TransactionID   string    `json:"transaction_id"`
-	Status          string    `json:"status"`
-	Amount          float64   `json:"amount"`
-	FromAccount     string    `json:"from_account"`
-	ToAccount       string    `json:"to_account"`
-	ReferenceNumber string    `json:"reference_number"`
-	ProcessedAt     time.Time `json:"processed_at"`
-	Message         string    `json:"message"`
+	TransactionID     string              `json:"transaction_id"`
+	Status            string              `json:"status"`
+	Amount            float64             `json:"amount"`
+	FromAccount       string              `json:"from_account"`
+	ToAccount         string              `json:"to_account"`
+	ReferenceNumber   string              `json:"reference_number"`
+	ProcessedAt       time.Time           `json:"processed_at"`
+	Message           string              `json:"message"`
+	Simulated         bool                `json:"simulated,omitempty"`
+	Settlement        *SettlementEstimate `json:"settlement,omitempty"`         // When the rail will credit the payee
+	PaymentMessageID  string              `json:"payment_message_id,omitempty"` // ISO 20022 message of a NEFT/RTGS transfer
+	PayeeVerification *PayeeVerification  `json:"payee_verification,omitempty"` // Whether the payee is a verified biller or merchant
+	Replayed          bool                `json:"replayed,omitempty"`           // Answered from an earlier transfer with the same idempotency key
 }
 
 // BalanceRequest represents balance inquiry request
@@ -129,6 +147,7 @@ type BalanceRequest struct {
 	UserID    string `json:"user_id"`
 	AccountID string `json:"account_id"`
 	Channel   Channel `json:"channel"`
+	Sandbox   bool    `json:"sandbox,omitempty"`
 }
 
 // BalanceResponse represents balance response
@@ -139,6 +158,24 @@ type BalanceResponse struct {
 	Currency    string    `json:"currency"`
 	AvailableBalance float64 `json:"available_balance"`
 	LastUpdated time.Time `json:"last_updated"`
+	Simulated   bool      `json:"simulated,omitempty"`
+}
+
+// BalanceUpdateRequest posts a ledger entry to an account held in core banking,
+// such as an approved back-office adjustment
+type BalanceUpdateRequest struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"` // Positive credits, negative debits
+	EntryID   string  `json:"entry_id"`
+	Reference string  `json:"reference,omitempty"`
+	Remarks   string  `json:"remarks,omitempty"`
+}
+
+// BalanceUpdateResponse is an account's balance either side of a posted entry
+type BalanceUpdateResponse struct {
+	AccountID     string  `json:"account_id"`
+	BalanceBefore float64 `json:"balance_before"`
+	BalanceAfter  float64 `json:"balance_after"`
 }
 
 // DWHQueryRequest represents DWH query request
@@ -152,11 +189,49 @@ type DWHQueryRequest struct {
 	Limit     int                    `json:"limit,omitempty"`
 }
 
+// DWHLookupRequest looks up transactions by ID, e.g. to reconcile task outcomes
+type DWHLookupRequest struct {
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
+// DWHLookupResponse holds the transactions found, keyed by ID, and the IDs the DWH
+// has no record of
+type DWHLookupResponse struct {
+	Transactions map[string]Transaction `json:"transactions"`
+	Missing      []string               `json:"missing"`
+	ExecutedAt   time.Time              `json:"executed_at"`
+	ServedBy     string                 `json:"served_by"` // DWH host that answered
+}
+
 // DWHQueryResponse represents DWH query response
 type DWHQueryResponse struct {
 	QueryType  string                   `json:"query_type"`
 	Data       []map[string]interface{} `json:"data"`
 	Count      int                      `json:"count"`
 	ExecutedAt time.Time                `json:"executed_at"`
+	ServedBy   string                   `json:"served_by"` // DWH host that answered
+}
+
+// DWH node roles
+const (
+	DWHRolePrimary = "PRIMARY"
+	DWHRoleReplica = "REPLICA"
+)
+
+// DWHNodeStatus is how one DWH database stands
+type DWHNodeStatus struct {
+	Host      string     `json:"host"`
+	Role      string     `json:"role"`
+	Position  uint64     `json:"position"`    // Writes applied
+	Lag       float64    `json:"lag_seconds"` // How far it trailed the primary at the last check
+	Available bool       `json:"available"`   // Serving reads; a replica stops beyond DWH_MAX_REPLICA_LAG
+	Reads     int64      `json:"reads"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// DWHReplicationStatus is how DWH reads are being routed
+type DWHReplicationStatus struct {
+	Nodes     []DWHNodeStatus  `json:"nodes"`
+	Fallbacks map[string]int64 `json:"fallbacks"` // Reads sent to the primary despite replicas, by reason
 }
 
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Budget categories. A budget for ALL counts every payment out; the others count
+// payments whose remarks place them in that category.
+const (
+	BudgetCategoryAll           = "ALL"
+	BudgetCategoryFood          = "FOOD"
+	BudgetCategoryGroceries     = "GROCERIES"
+	BudgetCategoryShopping      = "SHOPPING"
+	BudgetCategoryTravel        = "TRAVEL"
+	BudgetCategoryBills         = "BILLS"
+	BudgetCategoryRent          = "RENT"
+	BudgetCategoryEntertainment = "ENTERTAINMENT"
+	BudgetCategoryOther         = "OTHER"
+)
+
+// Budget is a soft monthly spending limit. Nothing is blocked when it is exceeded;
+// the user is notified as spending crosses each of its thresholds.
+type Budget struct {
+	BudgetID   string    `json:"budget_id"`
+	UserID     string    `json:"user_id"`
+	Category   string    `json:"category"`
+	Limit      float64   `json:"limit"`
+	Currency   string    `json:"currency"`
+	Thresholds []int     `json:"thresholds"` // Percentages of the limit that send an alert, ascending
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BudgetRequest sets a user's budget for a category, replacing any they had
+type BudgetRequest struct {
+	UserID     string  `json:"user_id"`
+	Category   string  `json:"category,omitempty"` // ALL when empty
+	Limit      float64 `json:"limit"`
+	Thresholds []int   `json:"thresholds,omitempty"` // Defaults to BUDGET_ALERT_THRESHOLDS
+}
+
+// BudgetStatus is how a budget stands in the current month
+type BudgetStatus struct {
+	Budget
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	Spent        float64   `json:"spent"`
+	Remaining    float64   `json:"remaining"` // Negative once the limit is exceeded
+	PercentUsed  float64   `json:"percent_used"`
+	Transactions int       `json:"transactions"`      // Payments counted this month
+	Alerted      []int     `json:"alerted,omitempty"` // Thresholds already notified this month
+}
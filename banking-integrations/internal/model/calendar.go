@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Holiday is a bank holiday on which the clearing rails do not settle
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// RailWindow is when a payment rail settles transfers. Rails without a window
+// settle around the clock.
+type RailWindow struct {
+	Rail            TransactionType `json:"rail"`
+	Open            string          `json:"open"`  // HH:MM, bank time
+	Close           string          `json:"close"` // HH:MM; transfers after this cutoff wait for the next window
+	WorkingDaysOnly bool            `json:"working_days_only"`
+	SettlementMins  int             `json:"settlement_minutes"` // Typical time to credit once accepted
+}
+
+// SettlementEstimate tells when a transfer on a rail will actually be credited
+type SettlementEstimate struct {
+	Rail               TransactionType `json:"rail"`
+	RequestedAt        time.Time       `json:"requested_at"`
+	WithinWindow       bool            `json:"within_window"`
+	ExpectedSettlement time.Time       `json:"expected_settlement"`
+	Delayed            bool            `json:"delayed"` // Settles in a later window than requested
+	Reason             string          `json:"reason,omitempty"`
+	Holiday            string          `json:"holiday,omitempty"` // Name of the holiday that delays it, if any
+	Message            string          `json:"message"`           // Customer-facing summary
+}
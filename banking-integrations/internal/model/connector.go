@@ -0,0 +1,36 @@
+package model
+
+// Connector types a channel can be configured with
+const (
+	ConnectorMock    = "mock"
+	ConnectorREST    = "rest"
+	ConnectorISO8583 = "iso8583"
+)
+
+// RESTMapping describes how the REST connector talks to a core-banking API: the
+// endpoint of each operation and how fields are renamed on the way in and out
+type RESTMapping struct {
+	Balance     RESTOperation `json:"balance"`
+	Transfer    RESTOperation `json:"transfer"`
+	Statement   RESTOperation `json:"statement"`
+	Beneficiary RESTOperation `json:"beneficiary"`
+
+	// BeneficiaryLookup reads back a beneficiary a user registered, with when it was added
+	BeneficiaryLookup RESTOperation `json:"beneficiary_lookup"`
+
+	// Back-office operations: an account's record, and posting to its balance
+	Account       RESTOperation `json:"account"`
+	BalanceUpdate RESTOperation `json:"balance_update"`
+}
+
+// RESTOperation maps one gateway operation onto a core-banking endpoint. Gateway
+// fields are the JSON names of the gateway's own requests and responses, e.g.
+// account_id or available_balance.
+type RESTOperation struct {
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`               // {field} placeholders are filled from the request
+	Request  map[string]string `json:"request,omitempty"`  // Remote field -> gateway field; the request is sent as is when empty
+	Response map[string]string `json:"response,omitempty"` // Gateway field -> dotted path in the response; read as is when empty
+	Items    string            `json:"items,omitempty"`    // Statement only: dotted path to the transaction list
+	Item     map[string]string `json:"item,omitempty"`     // Statement only: gateway field -> dotted path in each transaction
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Scoring run statuses
+const (
+	ScoringRunRunning   = "RUNNING"
+	ScoringRunCompleted = "COMPLETED"
+	ScoringRunFailed    = "FAILED"
+)
+
+// ScoringRun is one batch refresh of credit scores across the customer base
+type ScoringRun struct {
+	RunID      string      `json:"run_id"`
+	Trigger    string      `json:"trigger"` // api, cli, schedule
+	Status     string      `json:"status"`
+	Users      int         `json:"users"`
+	Scored     int         `json:"scored"`
+	Failed     int         `json:"failed"`
+	Errors     []string    `json:"errors,omitempty"` // First few failures
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Drift      *ScoreDrift `json:"drift,omitempty"` // Against the previous completed run
+}
+
+// ScoreSnapshot is a user's credit score as of one run
+type ScoreSnapshot struct {
+	RunID        string    `json:"run_id"`
+	UserID       string    `json:"user_id"`
+	CreditScore  int       `json:"credit_score"`
+	ScoreRange   string    `json:"score_range,omitempty"`
+	RiskScore    float64   `json:"risk_score"`
+	ModelVersion string    `json:"model_version,omitempty"`
+	ScoredAt     time.Time `json:"scored_at"`
+}
+
+// ScoreDrift compares a run's score distribution with the previous run's
+type ScoreDrift struct {
+	PreviousRunID  string        `json:"previous_run_id"`
+	Mean           float64       `json:"mean"`
+	PreviousMean   float64       `json:"previous_mean"`
+	MeanShift      float64       `json:"mean_shift"`
+	StdDev         float64       `json:"std_dev"`
+	PreviousStdDev float64       `json:"previous_std_dev"`
+	PSI            float64       `json:"psi"`      // Population stability index over the score buckets
+	Severity       string        `json:"severity"` // STABLE, MODERATE, SIGNIFICANT
+	Buckets        []ScoreBucket `json:"buckets"`
+	ComparedUsers  int           `json:"compared_users"`  // Users scored in both runs
+	MeanAbsChange  float64       `json:"mean_abs_change"` // Average per-user change, in points
+	RangeChanged   int           `json:"range_changed"`   // Users whose score range changed
+}
+
+// ScoreBucket is the share of users in a score band for two runs
+type ScoreBucket struct {
+	From          int     `json:"from"`
+	To            int     `json:"to"`
+	Share         float64 `json:"share"`
+	PreviousShare float64 `json:"previous_share"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Transaction export formats, for importing into personal finance and accounting tools
+const (
+	ExportFormatOFX = "OFX" // Open Financial Exchange 1.0.2, as Quicken, GnuCash and most tools import
+	ExportFormatQIF = "QIF" // Quicken Interchange Format, for older tools
+	ExportFormatCSV = "CSV"
+)
+
+// ExportLinkRequest asks for a download link to a user's transactions over a date range
+type ExportLinkRequest struct {
+	UserID     string `json:"user_id"`
+	AccountID  string `json:"account_id,omitempty"`  // Every account of the user's when empty
+	Format     string `json:"format"`                // OFX, QIF or CSV
+	From       string `json:"from"`                  // YYYY-MM-DD, in the bank's time zone
+	To         string `json:"to"`                    // YYYY-MM-DD, inclusive
+	TTLMinutes int    `json:"ttl_minutes,omitempty"` // Defaults to EXPORT_LINK_TTL_MINUTES
+}
+
+// ExportLink is a signed URL that downloads the export until it expires, without
+// logging in
+type ExportLink struct {
+	URL       string    `json:"url"`
+	Format    string    `json:"format"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	FileName  string    `json:"file_name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Export is a checked export request, ready to stream
+type Export struct {
+	UserID    string
+	AccountID string
+	Format    string
+	From      time.Time // Start of the first day, in the bank's time zone
+	To        time.Time // Start of the day after the last one
+}
@@ -0,0 +1,13 @@
+package model
+
+// MaskAction is what masking does to a response field
+type MaskAction string
+
+const (
+	MaskTail   MaskAction = "mask"   // Keep the last four characters, e.g. XXXX6789
+	MaskRemove MaskAction = "remove" // Leave the field out
+)
+
+// MaskingRules maps a channel to the fields masked in its responses, by JSON name.
+// A field is masked wherever it appears, e.g. in every transaction of a statement.
+type MaskingRules map[Channel]map[string]MaskAction
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Notification categories
+const (
+	NotificationInsights = "INSIGHTS" // Savings and spending digest
+	NotificationBudget   = "BUDGET"   // Spending crossed a budget threshold
+	NotificationSecurity = "SECURITY" // One-time codes and sign-in alerts
+)
+
+// Notification is a message sent to a user on their preferred channel
+type Notification struct {
+	ID       string    `json:"id"`
+	UserID   string    `json:"user_id"`
+	Channel  string    `json:"channel"` // SMS, EMAIL, PUSH or WHATSAPP
+	Category string    `json:"category,omitempty"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// NotificationRequest sends a notification to a user
+type NotificationRequest struct {
+	UserID   string `json:"user_id"`
+	Category string `json:"category,omitempty"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+}
+
+// Insights digest run statuses
+const (
+	DigestRunRunning   = "RUNNING"
+	DigestRunCompleted = "COMPLETED"
+	DigestRunFailed    = "FAILED"
+)
+
+// DigestRun is one pass of the insights digest over the customer base
+type DigestRun struct {
+	RunID      string     `json:"run_id"`
+	Trigger    string     `json:"trigger"` // api or schedule
+	Status     string     `json:"status"`
+	Users      int        `json:"users"`
+	Sent       int        `json:"sent"`
+	NoInsights int        `json:"no_insights"` // Users with nothing worth sending
+	Failed     int        `json:"failed"`
+	Errors     []string   `json:"errors,omitempty"` // First few failures
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Insight is one suggestion from the Insights Agent (Layer 3)
+type Insight struct {
+	Type          string  `json:"type"`
+	Title         string  `json:"title"`
+	Message       string  `json:"message"`
+	Amount        float64 `json:"amount,omitempty"`
+	EstimatedGain float64 `json:"estimated_gain,omitempty"`
+	Priority      int     `json:"priority"`
+}
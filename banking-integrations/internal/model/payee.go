@@ -0,0 +1,68 @@
+package model
+
+// PayeeCategory is the kind of business a directory payee is
+type PayeeCategory string
+
+const (
+	PayeeCategoryBiller   PayeeCategory = "BILLER"   // Utilities, telecom, insurance: paid against a bill
+	PayeeCategoryMerchant PayeeCategory = "MERCHANT" // Shops and services paid at checkout
+)
+
+// PayeeVerificationStatus is what the directory says about a transfer's payee
+type PayeeVerificationStatus string
+
+const (
+	PayeeVerified       PayeeVerificationStatus = "VERIFIED"      // The account belongs to a verified directory payee
+	PayeeUnverified     PayeeVerificationStatus = "UNVERIFIED"    // The account is listed, but its owner is not verified
+	PayeeNameMismatch   PayeeVerificationStatus = "NAME_MISMATCH" // Named as a directory payee, but the account is not theirs
+	PayeeNotInDirectory PayeeVerificationStatus = "NOT_LISTED"    // An ordinary account, such as a person's
+)
+
+// PayeeLogo is how a client draws a payee's badge. Clients without the image fall
+// back to the initials on the brand colour.
+type PayeeLogo struct {
+	URL      string `json:"url,omitempty"`
+	Initials string `json:"initials"`
+	Color    string `json:"color,omitempty"` // #RRGGBB
+}
+
+// DirectoryPayee is a biller or merchant in the bank's payee directory. Verified
+// payees have had their collection accounts confirmed by the bank.
+type DirectoryPayee struct {
+	PayeeID       string        `json:"payee_id"`
+	Name          string        `json:"name"`
+	Aliases       []string      `json:"aliases,omitempty"` // Other names customers use, e.g. "BSNL" for Bharat Sanchar Nigam
+	Category      PayeeCategory `json:"category"`
+	Subcategory   string        `json:"subcategory,omitempty"` // e.g. Mobile, Electricity
+	Verified      bool          `json:"verified"`
+	AccountNumber string        `json:"account_number,omitempty"`
+	IFSC          string        `json:"ifsc,omitempty"`
+	VPA           string        `json:"vpa,omitempty"`
+	Logo          PayeeLogo     `json:"logo"`
+}
+
+// PayeeMatch is a directory payee found by a lookup, with how well it matched
+type PayeeMatch struct {
+	DirectoryPayee
+	Score     float64 `json:"score"`      // 1 for an exact name, less for partial matches
+	MatchedOn string  `json:"matched_on"` // The name or alias that matched
+}
+
+// PayeeVerificationRequest asks whether a transfer's payee is who it claims to be
+type PayeeVerificationRequest struct {
+	ToAccount string `json:"to_account,omitempty"`
+	IFSC      string `json:"ifsc,omitempty"`
+	VPA       string `json:"vpa,omitempty"`
+	PayeeName string `json:"payee_name,omitempty"`
+}
+
+// PayeeVerification is the badge shown with a transfer confirmation
+type PayeeVerification struct {
+	Status   PayeeVerificationStatus `json:"status"`
+	Verified bool                    `json:"verified"`
+	PayeeID  string                  `json:"payee_id,omitempty"`
+	Name     string                  `json:"name,omitempty"`
+	Category PayeeCategory           `json:"category,omitempty"`
+	Logo     *PayeeLogo              `json:"logo,omitempty"`
+	Message  string                  `json:"message"` // Customer-facing summary
+}
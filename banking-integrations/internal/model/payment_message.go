@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// PaymentMessageStatus is what the clearing system last said about a payment message
+type PaymentMessageStatus string
+
+const (
+	PaymentMessageSent     PaymentMessageStatus = "SENT"     // No acknowledgment yet
+	PaymentMessageAccepted PaymentMessageStatus = "ACCEPTED" // Accepted, not yet settled
+	PaymentMessageSettled  PaymentMessageStatus = "SETTLED"
+	PaymentMessageRejected PaymentMessageStatus = "REJECTED"
+)
+
+// PaymentMessage is the ISO 20022 message a NEFT or RTGS transfer went out as,
+// kept with the transaction for audit along with every acknowledgment received
+type PaymentMessage struct {
+	TransactionID    string                   `json:"transaction_id"`
+	MessageID        string                   `json:"message_id"`
+	EndToEndID       string                   `json:"end_to_end_id"`
+	Definition       string                   `json:"definition"` // e.g. pacs.008.001.08
+	Rail             TransactionType          `json:"rail"`
+	Amount           float64                  `json:"amount"`
+	Status           PaymentMessageStatus     `json:"status"`
+	XML              string                   `json:"xml"`
+	CreatedAt        time.Time                `json:"created_at"`
+	Acknowledgements []PaymentAcknowledgement `json:"acknowledgements"` // Oldest first
+}
+
+// PaymentAcknowledgement is a pacs.002 status report received for a payment message
+type PaymentAcknowledgement struct {
+	MessageID      string    `json:"message_id"`
+	ReceivedAt     time.Time `json:"received_at"`
+	Status         string    `json:"status"` // ISO transaction status code, e.g. ACSC or RJCT
+	ReasonCode     string    `json:"reason_code,omitempty"`
+	AdditionalInfo string    `json:"additional_info,omitempty"`
+	XML            string    `json:"xml"`
+}
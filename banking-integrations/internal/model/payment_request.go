@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// PaymentRequestStatus is where a request for money stands
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending   PaymentRequestStatus = "PENDING"
+	PaymentRequestFulfilled PaymentRequestStatus = "FULFILLED"
+	PaymentRequestExpired   PaymentRequestStatus = "EXPIRED"
+	PaymentRequestCancelled PaymentRequestStatus = "CANCELLED"
+)
+
+// PaymentRequest asks someone to pay the user over UPI. The deep link opens the
+// payer's UPI app with the payment filled in; the QR payload is the same link for
+// rendering as a QR code.
+type PaymentRequest struct {
+	ID            string               `json:"id"` // Also the UPI transaction reference (tr)
+	UserID        string               `json:"user_id"`
+	AccountID     string               `json:"account_id,omitempty"` // Account the money is credited to
+	PayeeVPA      string               `json:"payee_vpa"`
+	PayeeName     string               `json:"payee_name"`
+	PayerName     string               `json:"payer_name,omitempty"` // Who is asked to pay
+	PayerVPA      string               `json:"payer_vpa,omitempty"`
+	Amount        float64              `json:"amount"`
+	Currency      string               `json:"currency"`
+	Note          string               `json:"note,omitempty"`
+	Status        PaymentRequestStatus `json:"status"`
+	DeepLink      string               `json:"deep_link"`
+	QRPayload     string               `json:"qr_payload"`
+	CreatedAt     time.Time            `json:"created_at"`
+	ExpiresAt     time.Time            `json:"expires_at"`
+	FulfilledAt   *time.Time           `json:"fulfilled_at,omitempty"`
+	TransactionID string               `json:"transaction_id,omitempty"` // The credit that paid it
+	Simulated     bool                 `json:"simulated,omitempty"`
+}
+
+// PaymentRequestCreate requests money from someone
+type PaymentRequestCreate struct {
+	UserID      string  `json:"user_id"`
+	AccountID   string  `json:"account_id,omitempty"`
+	PayeeVPA    string  `json:"payee_vpa,omitempty"` // Defaults to the user's VPA with this bank
+	PayeeName   string  `json:"payee_name,omitempty"`
+	PayerName   string  `json:"payer_name,omitempty"`
+	PayerVPA    string  `json:"payer_vpa,omitempty"`
+	Amount      float64 `json:"amount"`
+	Note        string  `json:"note,omitempty"`
+	ExpiryHours int     `json:"expiry_hours,omitempty"`
+	Sandbox     bool    `json:"sandbox,omitempty"`
+}
+
+// IncomingCredit is money arriving for a user, which may pay one of their requests
+type IncomingCredit struct {
+	AccountID     string  `json:"account_id,omitempty"`
+	VPA           string  `json:"vpa,omitempty"`
+	Amount        float64 `json:"amount"`
+	TransactionID string  `json:"transaction_id"`
+	Reference     string  `json:"reference,omitempty"` // The UPI transaction reference the payer's app echoed
+	Sandbox       bool    `json:"sandbox,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Notification channels a user can choose
+const (
+	NotifySMS      = "SMS"
+	NotifyEmail    = "EMAIL"
+	NotifyPush     = "PUSH"
+	NotifyWhatsApp = "WHATSAPP"
+)
+
+// TransferRule picks a transfer method for amounts up to MaxAmount (0 means any amount)
+type TransferRule struct {
+	Method    TransactionType `json:"method"`
+	MaxAmount float64         `json:"max_amount,omitempty"`
+}
+
+// UserPreferences are the defaults used when a request leaves something unspecified
+type UserPreferences struct {
+	UserID                  string          `json:"user_id"`
+	DefaultAccount          string          `json:"default_account,omitempty"`
+	PreferredTransferMethod TransactionType `json:"preferred_transfer_method,omitempty"`
+	NotificationChannel     string          `json:"notification_channel,omitempty"`
+	TransferRules           []TransferRule  `json:"transfer_rules,omitempty"` // Checked from the smallest MaxAmount up
+	UpdatedAt               time.Time       `json:"updated_at"`
+}
+
+// PreferenceUpdate changes the fields that are set and leaves the others as they are.
+// A transfer rule replaces any existing rule with the same MaxAmount.
+type PreferenceUpdate struct {
+	DefaultAccount          string          `json:"default_account,omitempty"`
+	PreferredTransferMethod TransactionType `json:"preferred_transfer_method,omitempty"`
+	NotificationChannel     string          `json:"notification_channel,omitempty"`
+	TransferRules           []TransferRule  `json:"transfer_rules,omitempty"`
+}
+
+// TransferMethodResolution is the method chosen for an amount and why
+type TransferMethodResolution struct {
+	UserID string          `json:"user_id"`
+	Amount float64         `json:"amount"`
+	Method TransactionType `json:"method,omitempty"` // Empty when no preference applies
+	Source string          `json:"source"`           // "rule", "preferred" or "none"
+	Rule   *TransferRule   `json:"rule,omitempty"`
+}
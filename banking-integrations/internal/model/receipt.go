@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// ReceiptLinkRequest asks for a shareable link to the receipt of one of a user's
+// transfers
+type ReceiptLinkRequest struct {
+	UserID        string `json:"user_id"`
+	TransactionID string `json:"transaction_id,omitempty"` // The user's most recent transfer when empty
+	TTLMinutes    int    `json:"ttl_minutes,omitempty"`    // Defaults to RECEIPT_LINK_TTL_MINUTES
+}
+
+// ReceiptLink is a signed URL anyone can open, without logging in, to see a
+// transfer's redacted receipt until it expires
+type ReceiptLink struct {
+	TransactionID string    `json:"transaction_id"`
+	URL           string    `json:"url"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Receipt       *Receipt  `json:"receipt"` // What the link shows
+}
+
+// Receipt is the proof of a transfer shown to whoever holds its link. It is redacted
+// to what a payee needs to trace the payment: accounts show only their last four
+// digits, and the user, their remarks and the payee's IFSC branch are left out.
+type Receipt struct {
+	TransactionID string            `json:"transaction_id"`      // Masked like the accounts
+	Reference     string            `json:"reference,omitempty"` // Rail reference, e.g. the UTR the payee's bank can trace
+	Rail          TransactionType   `json:"rail"`
+	Amount        float64           `json:"amount"`
+	Currency      string            `json:"currency"`
+	Status        TransactionStatus `json:"status"`
+	FromAccount   string            `json:"from_account,omitempty"`
+	ToAccount     string            `json:"to_account,omitempty"`
+	PayeeBank     string            `json:"payee_bank,omitempty"` // Bank code from the payee's IFSC
+	PaidAt        time.Time         `json:"paid_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+	LinkExpiresAt time.Time         `json:"link_expires_at"`
+}
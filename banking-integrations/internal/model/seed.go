@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// SeedFixture describes test data to load into the integration layer
+type SeedFixture struct {
+	Users []SeedUser `json:"users"`
+}
+
+// SeedUser is a user with the accounts, beneficiaries and history to seed
+type SeedUser struct {
+	UserID        string            `json:"user_id"`
+	Name          string            `json:"name,omitempty"`
+	Phone         string            `json:"phone,omitempty"` // Registered mobile number step-up OTPs go to
+	KYCStatus     string            `json:"kyc_status,omitempty"`
+	CreditScore   int               `json:"credit_score,omitempty"`
+	MonthlyIncome float64           `json:"monthly_income,omitempty"`
+	Accounts      []Account         `json:"accounts"`
+	Beneficiaries []Beneficiary     `json:"beneficiaries,omitempty"`
+	Transactions  []SeedTransaction `json:"transactions,omitempty"`
+	SeededAt      time.Time         `json:"seeded_at"`
+}
+
+// SeedTransaction is a historical transaction. DaysAgo, when set, places the
+// transaction relative to load time so fixtures do not go stale.
+type SeedTransaction struct {
+	Transaction
+	DaysAgo int `json:"days_ago,omitempty"`
+}
+
+// SeedResult summarises what a seed operation loaded or cleared
+type SeedResult struct {
+	Users         int `json:"users"`
+	Accounts      int `json:"accounts"`
+	Beneficiaries int `json:"beneficiaries"`
+	Transactions  int `json:"transactions"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types
+const (
+	EventTransactionCreated = "transaction.created" // A transfer went out
+	EventTransferRejected   = "transfer.rejected"   // A transfer was refused, or rejected as fraud
+	EventBeneficiaryAdded   = "beneficiary.added"
+)
+
+// WebhookEventTypes are the events a subscription may ask for
+var WebhookEventTypes = []string{EventTransactionCreated, EventTransferRejected, EventBeneficiaryAdded}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "PENDING"   // Not attempted yet
+	DeliveryRetrying  = "RETRYING"  // Failed, another attempt is scheduled
+	DeliveryDelivered = "DELIVERED" // The endpoint answered 2xx
+	DeliveryDead      = "DEAD"      // Every attempt failed; kept in the dead-letter list
+)
+
+// WebhookSubscription sends the events of the types it lists to an external system.
+// The secret signs every payload; it is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Secret      string    `json:"secret,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookSubscriptionRequest creates a subscription. A secret is generated when
+// none is given.
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// WebhookEvent is the payload posted to subscribers
+type WebhookEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookDelivery is one event on its way to one subscription
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	URL            string          `json:"url"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// TransferRejection is the data of a transfer.rejected event
+type TransferRejection struct {
+	UserID      string   `json:"user_id,omitempty"`
+	FromAccount string   `json:"from_account,omitempty"`
+	ToAccount   string   `json:"to_account,omitempty"`
+	Amount      float64  `json:"amount,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Source      string   `json:"source"` // gateway, connector or fraud
+	Reason      string   `json:"reason"`
+	FraudScore  float64  `json:"fraud_score,omitempty"`
+	RequestID   string   `json:"request_id,omitempty"` // Fraud check that rejected it
+	Flags       []string `json:"flags,omitempty"`
+}
+
+// Sources of a transfer rejection
+const (
+	RejectionSourceGateway   = "gateway"   // Refused before it reached a connector
+	RejectionSourceConnector = "connector" // Refused by the core-banking system
+	RejectionSourceFraud     = "fraud"     // Rejected by the fraud check
+)
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/aibanking/banking-integrations/internal/controller"
 	"github.com/aibanking/banking-integrations/internal/middleware"
 	"github.com/gorilla/mux"
@@ -8,18 +10,63 @@ import (
 
 // Router sets up all routes
 type Router struct {
-	bankingController *controller.BankingController
-	rateLimiter       *middleware.RateLimiter
+	bankingController    *controller.BankingController
+	scoringController    *controller.ScoringController
+	adjustmentController *controller.AdjustmentController
+	paymentRequests      *controller.PaymentRequestController
+	payees               *controller.PayeeController
+	accountStatus        *controller.AccountStatusController
+	notifications        *controller.NotificationController
+	webhooks             *controller.WebhookController
+	receipts             *controller.ReceiptController
+	exports              *controller.ExportController
+	budgets              *controller.BudgetController
+	rateLimiter          *middleware.RateLimiter
+	recovery             *middleware.Recovery
+	backOfficeAuth       *middleware.BackOfficeAuth
+	agentAuth            *middleware.AgentAuth
+	accessLog            *middleware.AccessLog
+	demoController       *controller.DemoController // Only set in demo mode
 }
 
 // NewRouter creates a new router instance
 func NewRouter(
 	bankingController *controller.BankingController,
+	scoringController *controller.ScoringController,
+	adjustmentController *controller.AdjustmentController,
+	paymentRequests *controller.PaymentRequestController,
+	payees *controller.PayeeController,
+	accountStatus *controller.AccountStatusController,
+	notifications *controller.NotificationController,
+	webhooks *controller.WebhookController,
+	receipts *controller.ReceiptController,
+	exports *controller.ExportController,
+	budgets *controller.BudgetController,
 	rateLimiter *middleware.RateLimiter,
+	recovery *middleware.Recovery,
+	backOfficeAuth *middleware.BackOfficeAuth,
+	agentAuth *middleware.AgentAuth,
+	accessLog *middleware.AccessLog,
+	demoController *controller.DemoController,
 ) *Router {
 	return &Router{
-		bankingController: bankingController,
-		rateLimiter:       rateLimiter,
+		bankingController:    bankingController,
+		scoringController:    scoringController,
+		adjustmentController: adjustmentController,
+		paymentRequests:      paymentRequests,
+		payees:               payees,
+		accountStatus:        accountStatus,
+		notifications:        notifications,
+		webhooks:             webhooks,
+		receipts:             receipts,
+		exports:              exports,
+		budgets:              budgets,
+		rateLimiter:          rateLimiter,
+		recovery:             recovery,
+		backOfficeAuth:       backOfficeAuth,
+		agentAuth:            agentAuth,
+		accessLog:            accessLog,
+		demoController:       demoController,
 	}
 }
 
@@ -30,23 +77,137 @@ func (r *Router) SetupRoutes() *mux.Router {
 	// Health check (no auth required)
 	router.HandleFunc("/health", r.bankingController.HealthCheck).Methods("GET")
 
+	// Shared receipt pages (no auth required; the signed link is the authorization)
+	router.HandleFunc("/receipts/{token}", r.receipts.ViewReceipt).Methods("GET")
+
+	// Export downloads (no auth required; the sealed link is the authorization)
+	router.HandleFunc("/exports/{token}", r.exports.DownloadLink).Methods("GET")
+
 	// Banking API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/balance", r.bankingController.GetBalance).Methods("POST")
 	api.HandleFunc("/transfer", r.bankingController.TransferFunds).Methods("POST")
+	api.HandleFunc("/transfers/idempotency/{key}", r.bankingController.GetTransferByIdempotencyKey).Methods("GET")
 	api.HandleFunc("/statement", r.bankingController.GetStatement).Methods("POST")
+	api.Handle("/statement", middleware.ETagMiddleware(http.HandlerFunc(r.bankingController.GetStatementByQuery))).Methods("GET")
 	api.HandleFunc("/beneficiary", r.bankingController.AddBeneficiary).Methods("POST")
+	api.HandleFunc("/beneficiaries", r.bankingController.GetBeneficiary).Methods("GET")
+
+	// ISO 20022 payment message routes
+	api.HandleFunc("/payments/acknowledgements", r.bankingController.AcknowledgePayment).Methods("POST")
+	api.HandleFunc("/payments/{transactionID}/message", r.bankingController.GetPaymentMessage).Methods("GET")
+
+	// UPI payment request routes
+	api.HandleFunc("/payment-requests", r.paymentRequests.CreatePaymentRequest).Methods("POST")
+	api.HandleFunc("/payment-requests", r.paymentRequests.ListPaymentRequests).Methods("GET")
+	api.HandleFunc("/payment-requests/credits", r.paymentRequests.RecordCredit).Methods("POST")
+	api.HandleFunc("/payment-requests/{id}", r.paymentRequests.GetPaymentRequest).Methods("GET")
+	api.HandleFunc("/payment-requests/{id}/cancel", r.paymentRequests.CancelPaymentRequest).Methods("POST")
+
+	// Payee directory routes
+	api.HandleFunc("/payees", r.payees.SearchPayees).Methods("GET")
+	api.HandleFunc("/payees/verify", r.payees.VerifyPayee).Methods("POST")
+	api.HandleFunc("/payees/{payeeID}", r.payees.GetPayee).Methods("GET")
+
+	// Account status routes
+	api.HandleFunc("/accounts/status", r.accountStatus.GetStatus).Methods("GET")
+	api.Handle("/accounts/fraud-signals", r.agentAuth.Middleware(http.HandlerFunc(r.accountStatus.ReportFraudSignal))).Methods("POST") // Agent role required
+
+	// Notification routes
+	api.HandleFunc("/notifications", r.notifications.SendNotification).Methods("POST")
+	api.HandleFunc("/notifications", r.notifications.ListNotifications).Methods("GET")
+
+	// Receipt link routes
+	api.HandleFunc("/receipts/links", r.receipts.CreateLink).Methods("POST")
+
+	// Transaction export routes
+	api.HandleFunc("/exports", r.exports.Download).Methods("GET")
+	api.HandleFunc("/exports/links", r.exports.CreateLink).Methods("POST")
+
+	// Budget routes
+	api.HandleFunc("/budgets", r.budgets.SetBudget).Methods("PUT")
+	api.HandleFunc("/budgets", r.budgets.ListBudgets).Methods("GET")
+	api.HandleFunc("/budgets/{userID}/{category}", r.budgets.DeleteBudget).Methods("DELETE")
 
 	// DWH routes
 	api.HandleFunc("/dwh/query", r.bankingController.QueryDWH).Methods("POST")
-	api.HandleFunc("/dwh/history/{userID}", r.bankingController.GetTransactionHistory).Methods("GET")
+	api.HandleFunc("/dwh/transactions/lookup", r.bankingController.LookupTransactions).Methods("POST")
+	api.Handle("/dwh/history/{userID}", middleware.ETagMiddleware(http.HandlerFunc(r.bankingController.GetTransactionHistory))).Methods("GET")
+
+	// Preference routes
+	api.HandleFunc("/preferences/{userID}", r.bankingController.GetPreferences).Methods("GET")
+	api.HandleFunc("/preferences/{userID}", r.bankingController.UpdatePreferences).Methods("PUT")
+	api.HandleFunc("/preferences/{userID}", r.bankingController.DeletePreferences).Methods("DELETE")
+	api.HandleFunc("/preferences/{userID}/transfer-method", r.bankingController.ResolveTransferMethod).Methods("GET")
 
-	// Apply middleware (CORS first)
+	// Banking calendar routes
+	api.HandleFunc("/calendar", r.bankingController.GetBankingCalendar).Methods("GET")
+	api.HandleFunc("/calendar/settlement", r.bankingController.EstimateSettlement).Methods("GET")
+
+	// Sandbox routes
+	api.HandleFunc("/sandbox/reset", r.bankingController.ResetSandbox).Methods("POST")
+
+	// Demo scenario routes
+	if r.demoController != nil {
+		api.HandleFunc("/demo/scenarios", r.demoController.ListScenarios).Methods("GET")
+		api.HandleFunc("/demo/reset", r.demoController.Reset).Methods("POST")
+	}
+
+	// Back-office routes (back-office role required)
+	backOffice := api.PathPrefix("/admin").Subrouter()
+	backOffice.Use(r.backOfficeAuth.Middleware)
+	backOffice.HandleFunc("/adjustments", r.adjustmentController.CreateAdjustment).Methods("POST")
+	backOffice.HandleFunc("/adjustments", r.adjustmentController.ListAdjustments).Methods("GET")
+	backOffice.HandleFunc("/adjustments/{id}", r.adjustmentController.GetAdjustment).Methods("GET")
+	backOffice.HandleFunc("/adjustments/{id}/approve", r.adjustmentController.ApproveAdjustment).Methods("POST")
+	backOffice.HandleFunc("/adjustments/{id}/reject", r.adjustmentController.RejectAdjustment).Methods("POST")
+	backOffice.HandleFunc("/accounts/restrictions", r.accountStatus.PlaceRestriction).Methods("POST")
+	backOffice.HandleFunc("/accounts/restrictions", r.accountStatus.ListRestrictions).Methods("GET")
+	backOffice.HandleFunc("/accounts/restrictions/{id}/lift", r.accountStatus.LiftRestriction).Methods("POST")
+	backOffice.HandleFunc("/audit", r.adjustmentController.GetAudit).Methods("GET")
+
+	// Credit-score refresh
+	backOffice.HandleFunc("/scoring/runs", r.scoringController.StartRun).Methods("POST")
+	backOffice.HandleFunc("/scoring/runs", r.scoringController.ListRuns).Methods("GET")
+	backOffice.HandleFunc("/scoring/runs/{runID}", r.scoringController.GetRun).Methods("GET")
+	backOffice.HandleFunc("/scoring/users/{userID}", r.scoringController.GetUserScores).Methods("GET")
+
+	// Insights digests
+	backOffice.HandleFunc("/insights/digests", r.notifications.StartDigest).Methods("POST")
+	backOffice.HandleFunc("/insights/digests", r.notifications.ListDigests).Methods("GET")
+
+	// DWH read routing over the primary and replicas
+	backOffice.HandleFunc("/dwh/replication", r.bankingController.GetDWHReplication).Methods("GET")
+
+	// Webhook subscriptions and deliveries
+	backOffice.HandleFunc("/webhooks", r.webhooks.CreateWebhook).Methods("POST")
+	backOffice.HandleFunc("/webhooks", r.webhooks.ListWebhooks).Methods("GET")
+	backOffice.HandleFunc("/webhooks/deliveries", r.webhooks.ListDeliveries).Methods("GET")
+	backOffice.HandleFunc("/webhooks/dead-letters", r.webhooks.ListDeadLetters).Methods("GET")
+	backOffice.HandleFunc("/webhooks/deliveries/{deliveryID}/retry", r.webhooks.RetryDelivery).Methods("POST")
+	backOffice.HandleFunc("/webhooks/{id}", r.webhooks.GetWebhook).Methods("GET")
+	backOffice.HandleFunc("/webhooks/{id}", r.webhooks.DeleteWebhook).Methods("DELETE")
+
+	// Panics recovered, per route and by fingerprint
+	backOffice.HandleFunc("/panics", r.recovery.Stats).Methods("GET")
+
+	// Test data seeding
+	backOffice.HandleFunc("/seed", r.bankingController.SeedData).Methods("POST")
+	backOffice.HandleFunc("/seed", r.bankingController.ClearSeedData).Methods("DELETE")
+	backOffice.HandleFunc("/seed/{userID}", r.bankingController.GetSeededUser).Methods("GET")
+
+	// Apply middleware. Access records come first, so requests refused by any later
+	// middleware are recorded too.
+	router.Use(r.accessLog.Middleware)
+	router.NotFoundHandler = r.accessLog.NotFound()
+	router.MethodNotAllowedHandler = r.accessLog.MethodNotAllowed()
 	router.Use(middleware.CORSMiddleware)
 	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.CompressionMiddleware)
 	router.Use(middleware.AuthMiddleware)
 	router.Use(r.rateLimiter.RateLimitMiddleware)
+	// Last, so a panicking handler is answered before any middleware finishes its response
+	router.Use(r.recovery.Middleware)
 
 	return router
 }
-
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// backOfficeChannels are the channels whose connector the back office reaches core
+// banking through, in order of preference
+var backOfficeChannels = []model.Channel{model.ChannelAPI, model.ChannelMB, model.ChannelNB}
+
+// AccountBook reads and posts to accounts wherever they are held: seeded accounts
+// in the seed store, every other account in core banking through the back-office
+// connector. Entries posted to core banking are recorded in the DWH.
+type AccountBook struct {
+	seedStore *SeedStore
+	connector ChannelConnector // Nil when no channel has a connector
+	dwh       *DWHService
+}
+
+// NewAccountBook creates an account book reaching core banking through the first
+// channel of API, MB and NB that has a connector
+func NewAccountBook(connectors map[model.Channel]ChannelConnector, seedStore *SeedStore, dwh *DWHService) *AccountBook {
+	book := &AccountBook{
+		seedStore: seedStore,
+		dwh:       dwh,
+	}
+	for _, channel := range backOfficeChannels {
+		if connector, ok := connectors[channel]; ok {
+			book.connector = connector
+			break
+		}
+	}
+	return book
+}
+
+// Account returns an account's record, including its owner
+func (ab *AccountBook) Account(ctx context.Context, accountID string) (*model.Account, error) {
+	if acct, ok := ab.seedStore.Account(accountID); ok {
+		return acct, nil
+	}
+	if ab.connector == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+	}
+	acct, err := ab.connector.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+	}
+	return acct, nil
+}
+
+// UpdateBalance applies a signed amount to an account's balance and records the
+// ledger entry in its history, returning the balances before and after. The balance
+// may not go negative.
+func (ab *AccountBook) UpdateBalance(ctx context.Context, accountID string, amount float64, entry model.Transaction) (float64, float64, error) {
+	if _, ok := ab.seedStore.Account(accountID); ok {
+		return ab.seedStore.PostEntry(accountID, amount, entry)
+	}
+
+	acct, err := ab.Account(ctx, accountID)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp, err := ab.connector.UpdateBalance(ctx, &model.BalanceUpdateRequest{
+		AccountID: accountID,
+		Amount:    amount,
+		EntryID:   entry.TransactionID,
+		Reference: entry.ReferenceNumber,
+		Remarks:   entry.Remarks,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	entry.AccountID = accountID
+	entry.UserID = acct.UserID
+	ab.dwh.RecordEntry(entry)
+	return resp.BalanceBefore, resp.BalanceAfter, nil
+}
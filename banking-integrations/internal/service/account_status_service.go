@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Account restriction errors
+var (
+	ErrAccountRestricted   = errors.New("account is not active")
+	ErrAccountNotOwned     = errors.New("account does not belong to the user")
+	ErrRestrictionNotFound = errors.New("account restriction not found")
+	ErrRestrictionLifted   = errors.New("account restriction has already been lifted")
+)
+
+// Fraud signal actions
+const (
+	FraudActionNone       = "none"
+	FraudActionFrozen     = "frozen"
+	FraudActionLocked     = "locked"
+	FraudActionRestricted = "already_restricted"
+)
+
+// AccountStatusService keeps the freezes and locks on accounts. A high-severity
+// fraud signal restricts the account automatically; only a back-office operator
+// lifts a restriction, and every change is written to the back-office audit trail.
+type AccountStatusService struct {
+	cfg          *config.AccountStatusConfig
+	accounts     *AccountBook
+	audit        *AdjustmentService
+	restrictions map[string]*model.AccountRestriction
+	webhooks     *WebhookService
+	mu           sync.RWMutex
+}
+
+// NewAccountStatusService creates an account status service
+func NewAccountStatusService(cfg *config.AccountStatusConfig, accounts *AccountBook, audit *AdjustmentService) *AccountStatusService {
+	return &AccountStatusService{
+		cfg:          cfg,
+		accounts:     accounts,
+		audit:        audit,
+		restrictions: make(map[string]*model.AccountRestriction),
+	}
+}
+
+// SetWebhooks publishes fraud rejections to webhook subscribers
+func (ss *AccountStatusService) SetWebhooks(webhooks *WebhookService) {
+	ss.webhooks = webhooks
+}
+
+// Status returns the effective status of an account, taking in restrictions on
+// every account of its user. An account that belongs to someone other than the
+// user named is refused.
+func (ss *AccountStatusService) Status(ctx context.Context, userID, accountID string) (*model.AccountStatusResponse, error) {
+	userID, err := ss.owner(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	resp := &model.AccountStatusResponse{
+		UserID:       userID,
+		AccountID:    accountID,
+		Status:       model.AccountActive,
+		Restrictions: []model.AccountRestriction{},
+	}
+	for _, r := range ss.restrictions {
+		if !r.Active || !covers(r, userID, accountID) {
+			continue
+		}
+		resp.Restrictions = append(resp.Restrictions, *r)
+		if severity(r.Status) > severity(resp.Status) {
+			resp.Status = r.Status
+		}
+	}
+	sort.Slice(resp.Restrictions, func(a, b int) bool {
+		return resp.Restrictions[a].PlacedAt.Before(resp.Restrictions[b].PlacedAt)
+	})
+	return resp, nil
+}
+
+// CheckDebit refuses a debit from a frozen or locked account, or from an account
+// of someone other than the user debiting it
+func (ss *AccountStatusService) CheckDebit(ctx context.Context, userID, accountID string) error {
+	status, err := ss.Status(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if status.Status != model.AccountActive {
+		return fmt.Errorf("%w: account %s is %s", ErrAccountRestricted, accountID, status.Status)
+	}
+	return nil
+}
+
+// CheckCredit refuses a credit to a locked account, or to any account of a user
+// who is locked; a frozen account still receives money
+func (ss *AccountStatusService) CheckCredit(ctx context.Context, accountID string) error {
+	status, err := ss.Status(ctx, "", accountID)
+	if err != nil {
+		return err
+	}
+	if status.Status == model.AccountLocked {
+		return fmt.Errorf("%w: account %s is %s", ErrAccountRestricted, accountID, status.Status)
+	}
+	return nil
+}
+
+// HandleFraudSignal freezes or locks the account of a fraud outcome whose score
+// reaches the configured thresholds. An existing fraud restriction is escalated
+// from a freeze to a lock rather than duplicated.
+func (ss *AccountStatusService) HandleFraudSignal(ctx context.Context, sig *model.FraudSignal) (*model.FraudSignalResponse, error) {
+	if sig.UserID == "" && sig.AccountID == "" {
+		return nil, fmt.Errorf("user_id or account_id is required")
+	}
+	if ss.webhooks != nil && strings.EqualFold(sig.Decision, "REJECTED") {
+		ss.webhooks.Publish(model.EventTransferRejected, &model.TransferRejection{
+			UserID:      sig.UserID,
+			FromAccount: sig.AccountID,
+			Source:      model.RejectionSourceFraud,
+			Reason:      fmt.Sprintf("rejected by the fraud check at score %.2f", sig.FraudScore),
+			FraudScore:  sig.FraudScore,
+			RequestID:   sig.RequestID,
+			Flags:       sig.Flags,
+		})
+	}
+	if !ss.cfg.AutoFreeze || sig.FraudScore < ss.cfg.FreezeScore {
+		return &model.FraudSignalResponse{Action: FraudActionNone}, nil
+	}
+
+	status := model.AccountFrozen
+	if sig.FraudScore >= ss.cfg.LockScore {
+		status = model.AccountLocked
+	}
+	userID, err := ss.owner(ctx, sig.UserID, sig.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	reason := fmt.Sprintf("fraud score %.2f", sig.FraudScore)
+	if len(sig.Flags) > 0 {
+		reason += ": " + strings.Join(sig.Flags, ", ")
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, r := range ss.restrictions {
+		if !r.Active || r.Source != model.RestrictionSourceFraud || r.UserID != userID || r.AccountID != sig.AccountID {
+			continue
+		}
+		if severity(r.Status) >= severity(status) {
+			return &model.FraudSignalResponse{Action: FraudActionRestricted, Restriction: copyRestriction(r)}, nil
+		}
+		r.Status = status
+		r.Reason = reason
+		r.FraudScore = sig.FraudScore
+		r.RequestID = sig.RequestID
+		ss.record("system", model.AuditAccountLocked, r, "escalated from a freeze: "+reason)
+		log.Warn().Str("restriction_id", r.ID).Str("user_id", r.UserID).Str("account_id", r.AccountID).
+			Float64("fraud_score", sig.FraudScore).Msg("Account freeze escalated to a lock")
+		return &model.FraudSignalResponse{Action: FraudActionLocked, Restriction: copyRestriction(r)}, nil
+	}
+
+	r := &model.AccountRestriction{
+		ID:         ids.Ref("RST_"),
+		UserID:     userID,
+		AccountID:  sig.AccountID,
+		Status:     status,
+		Reason:     reason,
+		Source:     model.RestrictionSourceFraud,
+		PlacedBy:   "system",
+		PlacedAt:   time.Now(),
+		FraudScore: sig.FraudScore,
+		RequestID:  sig.RequestID,
+		Active:     true,
+	}
+	ss.restrictions[r.ID] = r
+	ss.record("system", auditAction(status), r, reason)
+	log.Warn().Str("restriction_id", r.ID).Str("user_id", r.UserID).Str("account_id", r.AccountID).
+		Str("status", string(status)).Float64("fraud_score", sig.FraudScore).Msg("Account restricted on a fraud signal")
+
+	action := FraudActionFrozen
+	if status == model.AccountLocked {
+		action = FraudActionLocked
+	}
+	return &model.FraudSignalResponse{Action: action, Restriction: copyRestriction(r)}, nil
+}
+
+// Restrict freezes or locks an account, or every account of a user, for an operator
+func (ss *AccountStatusService) Restrict(ctx context.Context, operator string, req *model.RestrictionRequest) (*model.AccountRestriction, error) {
+	if req.UserID == "" && req.AccountID == "" {
+		return nil, fmt.Errorf("user_id or account_id is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	status := model.AccountStatus(strings.ToUpper(string(req.Status)))
+	if status == "" {
+		status = model.AccountFrozen
+	}
+	if status != model.AccountFrozen && status != model.AccountLocked {
+		return nil, fmt.Errorf("status must be FROZEN or LOCKED")
+	}
+
+	userID, err := ss.owner(ctx, req.UserID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &model.AccountRestriction{
+		ID:        ids.Ref("RST_"),
+		UserID:    userID,
+		AccountID: req.AccountID,
+		Status:    status,
+		Reason:    req.Reason,
+		Source:    model.RestrictionSourceOperator,
+		PlacedBy:  operator,
+		PlacedAt:  time.Now(),
+		Active:    true,
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.restrictions[r.ID] = r
+	ss.record(operator, auditAction(status), r, req.Reason)
+	log.Info().Str("restriction_id", r.ID).Str("user_id", r.UserID).Str("account_id", r.AccountID).
+		Str("status", string(status)).Str("operator", operator).Msg("Account restricted by operator")
+	return copyRestriction(r), nil
+}
+
+// Lift unfreezes an account by lifting one of its restrictions
+func (ss *AccountStatusService) Lift(id, operator, reason string) (*model.AccountRestriction, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("a reason is required to lift a restriction")
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	r, ok := ss.restrictions[id]
+	if !ok {
+		return nil, ErrRestrictionNotFound
+	}
+	if !r.Active {
+		return nil, ErrRestrictionLifted
+	}
+
+	now := time.Now()
+	r.Active = false
+	r.LiftedBy = operator
+	r.LiftedAt = &now
+	r.LiftReason = reason
+	ss.record(operator, model.AuditAccountUnfrozen, r, fmt.Sprintf("lifted %s: %s", r.Status, reason))
+	log.Info().Str("restriction_id", r.ID).Str("user_id", r.UserID).Str("account_id", r.AccountID).
+		Str("operator", operator).Msg("Account restriction lifted")
+	return copyRestriction(r), nil
+}
+
+// List returns restrictions, newest first, optionally only those in force or for
+// one user or account
+func (ss *AccountStatusService) List(activeOnly bool, userID, accountID string) []model.AccountRestriction {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	restrictions := make([]model.AccountRestriction, 0, len(ss.restrictions))
+	for _, r := range ss.restrictions {
+		if activeOnly && !r.Active {
+			continue
+		}
+		if userID != "" && r.UserID != userID {
+			continue
+		}
+		if accountID != "" && r.AccountID != accountID {
+			continue
+		}
+		restrictions = append(restrictions, *r)
+	}
+	sort.Slice(restrictions, func(a, b int) bool { return restrictions[a].PlacedAt.After(restrictions[b].PlacedAt) })
+	return restrictions
+}
+
+// owner returns the user of an account as its record names them, whoever the caller
+// said it belongs to, and refuses an account the record gives to another user. An
+// account whose record cannot be read is taken to be the named user's, so a user-wide
+// restriction on them still applies.
+func (ss *AccountStatusService) owner(ctx context.Context, userID, accountID string) (string, error) {
+	if accountID == "" {
+		return userID, nil
+	}
+	acct, err := ss.accounts.Account(ctx, accountID)
+	if err != nil || acct.UserID == "" {
+		log.Debug().Err(err).Str("account_id", accountID).Msg("Account owner unknown")
+		return userID, nil
+	}
+	if userID != "" && userID != acct.UserID {
+		log.Warn().Str("user_id", userID).Str("account_id", accountID).Msg("Account named for a user who does not own it")
+		return "", fmt.Errorf("%w: account %s is not %s's", ErrAccountNotOwned, accountID, userID)
+	}
+	return acct.UserID, nil
+}
+
+// record writes a restriction change to the audit trail. Callers hold ss.mu.
+func (ss *AccountStatusService) record(operator, action string, r *model.AccountRestriction, detail string) {
+	ss.audit.RecordAudit(model.AuditRecord{
+		Operator:      operator,
+		Action:        action,
+		RestrictionID: r.ID,
+		AccountID:     r.AccountID,
+		UserID:        r.UserID,
+		Detail:        detail,
+	})
+}
+
+// covers reports whether a restriction applies to an account: it names the account,
+// or is on every account of the account's user
+func covers(r *model.AccountRestriction, userID, accountID string) bool {
+	if r.AccountID != "" {
+		return r.AccountID == accountID
+	}
+	return userID != "" && r.UserID == userID
+}
+
+func severity(status model.AccountStatus) int {
+	switch status {
+	case model.AccountLocked:
+		return 2
+	case model.AccountFrozen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func auditAction(status model.AccountStatus) string {
+	if status == model.AccountLocked {
+		return model.AuditAccountLocked
+	}
+	return model.AuditAccountFrozen
+}
+
+func copyRestriction(r *model.AccountRestriction) *model.AccountRestriction {
+	rCopy := *r
+	return &rCopy
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// A lock on every account of a user refuses credits to an account that was never
+// seeded, its owner being read from core banking
+func TestUserLockBlocksCreditToCoreBankingAccount(t *testing.T) {
+	ctx := context.Background()
+	core := NewMBService()
+	connectors := map[model.Channel]ChannelConnector{model.ChannelMB: core}
+	seedStore := NewSeedStore()
+	dwh := NewDWHService(&config.DWHConfig{}, seedStore)
+	book := NewAccountBook(connectors, seedStore, dwh)
+	accountStatus := NewAccountStatusService(&config.AccountStatusConfig{}, book,
+		NewAdjustmentService(&config.AdjustmentsConfig{}, book))
+
+	gateway := NewBankingGateway(connectors, dwh, nil, seedStore, nil, nil, nil, nil, nil)
+	gateway.SetAccountStatus(accountStatus)
+
+	// The customer's balance inquiry is how the mock learns who owns the account
+	if _, err := core.GetBalance(ctx, &model.BalanceRequest{UserID: "U20001", AccountID: "ACC_777", Channel: model.ChannelMB}); err != nil {
+		t.Fatalf("balance inquiry failed: %v", err)
+	}
+	if err := accountStatus.CheckCredit(ctx, "ACC_777"); err != nil {
+		t.Fatalf("credit refused before any restriction: %v", err)
+	}
+
+	if _, err := accountStatus.Restrict(ctx, "operator", &model.RestrictionRequest{
+		UserID: "U20001",
+		Status: model.AccountLocked,
+		Reason: "Suspected account takeover",
+	}); err != nil {
+		t.Fatalf("Restrict failed: %v", err)
+	}
+
+	status, err := accountStatus.Status(ctx, "", "ACC_777")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.UserID != "U20001" || status.Status != model.AccountLocked {
+		t.Errorf("ACC_777 is %s for user %q, want LOCKED for U20001", status.Status, status.UserID)
+	}
+	_, err = gateway.TransferFunds(ctx, &model.TransferRequest{
+		UserID:      "U30001",
+		FromAccount: "ACC_888",
+		ToAccount:   "ACC_777",
+		Amount:      1000,
+		Type:        model.TransactionTypeIMPS,
+		Channel:     model.ChannelMB,
+	})
+	if !errors.Is(err, ErrAccountRestricted) {
+		t.Errorf("transfer to ACC_777 returned %v, want %v", err, ErrAccountRestricted)
+	}
+}
+
+// A debit naming another user as the owner of a locked user's account is refused,
+// rather than checked against the other user's restrictions
+func TestDebitNamingAnotherUserIsRefused(t *testing.T) {
+	ctx := context.Background()
+	core := NewMBService()
+	connectors := map[model.Channel]ChannelConnector{model.ChannelMB: core}
+	seedStore := NewSeedStore()
+	book := NewAccountBook(connectors, seedStore, NewDWHService(&config.DWHConfig{}, seedStore))
+	accountStatus := NewAccountStatusService(&config.AccountStatusConfig{}, book,
+		NewAdjustmentService(&config.AdjustmentsConfig{}, book))
+
+	if _, err := core.GetBalance(ctx, &model.BalanceRequest{UserID: "U20001", AccountID: "ACC_777", Channel: model.ChannelMB}); err != nil {
+		t.Fatalf("balance inquiry failed: %v", err)
+	}
+	if _, err := accountStatus.Restrict(ctx, "operator", &model.RestrictionRequest{
+		UserID: "U20001",
+		Status: model.AccountLocked,
+		Reason: "Suspected account takeover",
+	}); err != nil {
+		t.Fatalf("Restrict failed: %v", err)
+	}
+
+	if err := accountStatus.CheckDebit(ctx, "U20001", "ACC_777"); !errors.Is(err, ErrAccountRestricted) {
+		t.Errorf("debit by the owner returned %v, want %v", err, ErrAccountRestricted)
+	}
+	if err := accountStatus.CheckDebit(ctx, "U30001", "ACC_777"); !errors.Is(err, ErrAccountNotOwned) {
+		t.Errorf("debit naming another user returned %v, want %v", err, ErrAccountNotOwned)
+	}
+}
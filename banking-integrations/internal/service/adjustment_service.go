@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Balance adjustment errors
+var (
+	ErrAdjustmentNotFound   = errors.New("adjustment not found")
+	ErrAdjustmentNotPending = errors.New("adjustment is no longer pending")
+	ErrSelfApproval         = errors.New("the maker of an adjustment cannot approve or reject it")
+)
+
+// AdjustmentService runs back-office balance adjustments through maker-checker
+// review. An approved adjustment posts a ledger entry to the account, seeded or held
+// in core banking, and every step, including refused approvals, is written to the
+// audit trail.
+type AdjustmentService struct {
+	cfg         *config.AdjustmentsConfig
+	accounts    *AccountBook
+	adjustments map[string]*model.BalanceAdjustment
+	audit       []model.AuditRecord // Oldest first, append-only
+	mu          sync.Mutex
+}
+
+// NewAdjustmentService creates an adjustment service
+func NewAdjustmentService(cfg *config.AdjustmentsConfig, accounts *AccountBook) *AdjustmentService {
+	return &AdjustmentService{
+		cfg:         cfg,
+		accounts:    accounts,
+		adjustments: make(map[string]*model.BalanceAdjustment),
+	}
+}
+
+// Create raises an adjustment for approval by another operator
+func (as *AdjustmentService) Create(ctx context.Context, maker string, req *model.AdjustmentRequest) (*model.BalanceAdjustment, error) {
+	if req.AccountID == "" || req.Reason == "" {
+		return nil, fmt.Errorf("account_id and reason are required")
+	}
+	if req.Amount == 0 || math.IsNaN(req.Amount) || math.IsInf(req.Amount, 0) {
+		return nil, fmt.Errorf("amount must be a non-zero number")
+	}
+	if as.cfg.MaxAmount > 0 && math.Abs(req.Amount) > as.cfg.MaxAmount {
+		return nil, fmt.Errorf("amount exceeds the adjustment limit of %.2f", as.cfg.MaxAmount)
+	}
+
+	acct, err := as.accounts.Account(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := time.Duration(as.cfg.PendingHours) * time.Hour
+	if pending <= 0 {
+		pending = 24 * time.Hour
+	}
+
+	now := time.Now()
+	adj := &model.BalanceAdjustment{
+		ID:        ids.Ref("ADJ_"),
+		AccountID: acct.AccountID,
+		UserID:    acct.UserID,
+		Amount:    math.Round(req.Amount*100) / 100,
+		Currency:  acct.Currency,
+		Reason:    req.Reason,
+		Reference: req.Reference,
+		Status:    model.AdjustmentPending,
+		Maker:     maker,
+		CreatedAt: now,
+		ExpiresAt: now.Add(pending),
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.adjustments[adj.ID] = adj
+	as.record(maker, model.AuditAdjustmentCreated, adj, fmt.Sprintf("%+.2f %s: %s", adj.Amount, adj.Currency, adj.Reason))
+	log.Info().Str("adjustment_id", adj.ID).Str("account_id", adj.AccountID).Float64("amount", adj.Amount).
+		Str("maker", maker).Msg("Balance adjustment raised")
+
+	adjCopy := *adj
+	return &adjCopy, nil
+}
+
+// Approve posts a pending adjustment. The checker must not be its maker. The
+// adjustment is APPLYING while it is posted, which for a core banking account is a
+// connector call made without holding the lock; one the account refuses is PENDING
+// again.
+func (as *AdjustmentService) Approve(ctx context.Context, id, checker, note string) (*model.BalanceAdjustment, error) {
+	as.mu.Lock()
+	adj, err := as.decidable(id, checker)
+	if err != nil {
+		as.mu.Unlock()
+		return nil, err
+	}
+	adj.Status = model.AdjustmentApplying
+	adj.Checker = checker
+
+	now := time.Now()
+	entry := model.Transaction{
+		TransactionID:   ids.Ref("LDG_"),
+		Type:            model.TransactionTypeAdjustment,
+		Amount:          math.Abs(adj.Amount),
+		Currency:        adj.Currency,
+		Status:          model.TransactionStatusCompleted,
+		Remarks:         fmt.Sprintf("Balance adjustment %s: %s", adj.ID, adj.Reason),
+		Channel:         model.ChannelBackOffice,
+		ReferenceNumber: adj.ID,
+		CreatedAt:       now,
+		CompletedAt:     &now,
+	}
+	if adj.Amount < 0 {
+		entry.FromAccount = adj.AccountID
+	} else {
+		entry.ToAccount = adj.AccountID
+	}
+
+	as.mu.Unlock()
+
+	before, after, err := as.accounts.UpdateBalance(ctx, adj.AccountID, adj.Amount, entry)
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if err != nil {
+		adj.Status = model.AdjustmentPending
+		adj.Checker = ""
+		as.record(checker, model.AuditAdjustmentRefused, adj, err.Error())
+		return nil, err
+	}
+
+	adj.Status = model.AdjustmentApproved
+	adj.DecidedAt = &now
+	adj.DecisionNote = note
+	adj.BalanceBefore = &before
+	adj.BalanceAfter = &after
+	adj.LedgerEntryID = entry.TransactionID
+	as.record(checker, model.AuditAdjustmentApproved, adj, fmt.Sprintf("balance %.2f -> %.2f, ledger entry %s", before, after, entry.TransactionID))
+	log.Info().Str("adjustment_id", adj.ID).Str("account_id", adj.AccountID).Float64("balance_before", before).
+		Float64("balance_after", after).Str("maker", adj.Maker).Str("checker", checker).Msg("Balance adjustment approved and posted")
+
+	adjCopy := *adj
+	return &adjCopy, nil
+}
+
+// Reject closes a pending adjustment without posting it. The checker must not be
+// its maker; a maker withdraws their own adjustment by letting it expire.
+func (as *AdjustmentService) Reject(id, checker, note string) (*model.BalanceAdjustment, error) {
+	if note == "" {
+		return nil, fmt.Errorf("a note is required to reject an adjustment")
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	adj, err := as.decidable(id, checker)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	adj.Status = model.AdjustmentRejected
+	adj.Checker = checker
+	adj.DecidedAt = &now
+	adj.DecisionNote = note
+	as.record(checker, model.AuditAdjustmentRejected, adj, note)
+	log.Info().Str("adjustment_id", adj.ID).Str("checker", checker).Msg("Balance adjustment rejected")
+
+	adjCopy := *adj
+	return &adjCopy, nil
+}
+
+// Get returns an adjustment
+func (as *AdjustmentService) Get(id string) (*model.BalanceAdjustment, bool) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	adj, ok := as.adjustments[id]
+	if !ok {
+		return nil, false
+	}
+	as.expire(adj)
+	adjCopy := *adj
+	return &adjCopy, true
+}
+
+// List returns adjustments with the given status, or all of them, newest first
+func (as *AdjustmentService) List(status model.AdjustmentStatus) []model.BalanceAdjustment {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	adjustments := make([]model.BalanceAdjustment, 0, len(as.adjustments))
+	for _, adj := range as.adjustments {
+		as.expire(adj)
+		if status == "" || adj.Status == status {
+			adjustments = append(adjustments, *adj)
+		}
+	}
+	sort.Slice(adjustments, func(a, b int) bool { return adjustments[a].CreatedAt.After(adjustments[b].CreatedAt) })
+	return adjustments
+}
+
+// Audit returns the audit trail, optionally for one adjustment, account or user, oldest first
+func (as *AdjustmentService) Audit(adjustmentID, accountID, userID string) []model.AuditRecord {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	records := make([]model.AuditRecord, 0, len(as.audit))
+	for _, rec := range as.audit {
+		if adjustmentID != "" && rec.AdjustmentID != adjustmentID {
+			continue
+		}
+		if accountID != "" && rec.AccountID != accountID {
+			continue
+		}
+		if userID != "" && rec.UserID != userID {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// RecordAudit appends a record from another back-office workflow, such as account
+// freezes, to the audit trail
+func (as *AdjustmentService) RecordAudit(rec model.AuditRecord) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if rec.ID == "" {
+		rec.ID = ids.Ref("AUD_")
+	}
+	if rec.At.IsZero() {
+		rec.At = time.Now()
+	}
+	as.audit = append(as.audit, rec)
+}
+
+// decidable returns a pending adjustment a checker may decide, auditing attempts
+// by its maker. Callers hold as.mu.
+func (as *AdjustmentService) decidable(id, checker string) (*model.BalanceAdjustment, error) {
+	adj, ok := as.adjustments[id]
+	if !ok {
+		return nil, ErrAdjustmentNotFound
+	}
+	as.expire(adj)
+	if adj.Status != model.AdjustmentPending {
+		return nil, ErrAdjustmentNotPending
+	}
+	if checker == adj.Maker {
+		as.record(checker, model.AuditAdjustmentRefused, adj, "maker attempted to decide their own adjustment")
+		log.Warn().Str("adjustment_id", adj.ID).Str("operator", checker).Msg("Self-approval of balance adjustment refused")
+		return nil, ErrSelfApproval
+	}
+	return adj, nil
+}
+
+// expire marks a pending adjustment expired once its approval window has passed.
+// Callers hold as.mu.
+func (as *AdjustmentService) expire(adj *model.BalanceAdjustment) {
+	if adj.Status != model.AdjustmentPending || time.Now().Before(adj.ExpiresAt) {
+		return
+	}
+	adj.Status = model.AdjustmentExpired
+	as.record("system", model.AuditAdjustmentExpired, adj, "not approved in time")
+}
+
+// record appends to the audit trail. Callers hold as.mu.
+func (as *AdjustmentService) record(operator, action string, adj *model.BalanceAdjustment, detail string) {
+	as.audit = append(as.audit, model.AuditRecord{
+		ID:           ids.Ref("AUD_"),
+		At:           time.Now(),
+		Operator:     operator,
+		Action:       action,
+		AdjustmentID: adj.ID,
+		AccountID:    adj.AccountID,
+		UserID:       adj.UserID,
+		Detail:       detail,
+	})
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// An adjustment to an account that was never seeded is read from and posted to
+// core banking, with its ledger entry in the DWH and its steps in the audit trail
+func TestAdjustmentOfCoreBankingAccount(t *testing.T) {
+	ctx := context.Background()
+	core := NewMBService()
+	connectors := map[model.Channel]ChannelConnector{model.ChannelMB: core}
+	seedStore := NewSeedStore()
+	dwh := NewDWHService(&config.DWHConfig{}, seedStore)
+	adjustments := NewAdjustmentService(&config.AdjustmentsConfig{MaxAmount: 100000, PendingHours: 24},
+		NewAccountBook(connectors, seedStore, dwh))
+
+	// The customer's balance inquiry is how the mock learns who owns the account
+	if _, err := core.GetBalance(ctx, &model.BalanceRequest{UserID: "U10001", AccountID: "ACC_001", Channel: model.ChannelMB}); err != nil {
+		t.Fatalf("balance inquiry failed: %v", err)
+	}
+
+	adj, err := adjustments.Create(ctx, "maker", &model.AdjustmentRequest{AccountID: "ACC_001", Amount: 250.5, Reason: "Fee reversal"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if adj.UserID != "U10001" || adj.Currency != "INR" {
+		t.Fatalf("adjustment is for user %q in %q, want U10001 in INR", adj.UserID, adj.Currency)
+	}
+
+	approved, err := adjustments.Approve(ctx, adj.ID, "checker", "")
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if *approved.BalanceBefore != mockOpeningBalance || *approved.BalanceAfter != mockOpeningBalance+250.5 {
+		t.Errorf("balance went %.2f -> %.2f, want %.2f -> %.2f",
+			*approved.BalanceBefore, *approved.BalanceAfter, mockOpeningBalance, mockOpeningBalance+250.5)
+	}
+
+	balance, err := core.GetBalance(ctx, &model.BalanceRequest{UserID: "U10001", AccountID: "ACC_001", Channel: model.ChannelMB})
+	if err != nil {
+		t.Fatalf("balance inquiry failed: %v", err)
+	}
+	if balance.Balance != *approved.BalanceAfter {
+		t.Errorf("core banking balance is %.2f, want %.2f", balance.Balance, *approved.BalanceAfter)
+	}
+
+	found := dwh.LookupTransactions(ctx, []string{approved.LedgerEntryID})
+	entry, ok := found.Transactions[approved.LedgerEntryID]
+	if !ok {
+		t.Fatalf("ledger entry %s is not in the DWH", approved.LedgerEntryID)
+	}
+	if entry.AccountID != "ACC_001" || entry.UserID != "U10001" || entry.Type != model.TransactionTypeAdjustment {
+		t.Errorf("ledger entry is %s on %s for %s, want ADJUSTMENT on ACC_001 for U10001", entry.Type, entry.AccountID, entry.UserID)
+	}
+
+	var actions []string
+	for _, rec := range adjustments.Audit(adj.ID, "", "U10001") {
+		actions = append(actions, rec.Action)
+	}
+	if len(actions) != 2 || actions[0] != model.AuditAdjustmentCreated || actions[1] != model.AuditAdjustmentApproved {
+		t.Errorf("audit trail is %v, want [%s %s]", actions, model.AuditAdjustmentCreated, model.AuditAdjustmentApproved)
+	}
+}
+
+// A core banking account is not taken below zero
+func TestAdjustmentCannotOverdrawCoreBankingAccount(t *testing.T) {
+	ctx := context.Background()
+	core := NewMBService()
+	seedStore := NewSeedStore()
+	adjustments := NewAdjustmentService(&config.AdjustmentsConfig{PendingHours: 24},
+		NewAccountBook(map[model.Channel]ChannelConnector{model.ChannelMB: core}, seedStore, NewDWHService(&config.DWHConfig{}, seedStore)))
+
+	adj, err := adjustments.Create(ctx, "maker", &model.AdjustmentRequest{AccountID: "ACC_002", Amount: -(mockOpeningBalance + 1), Reason: "Chargeback"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := adjustments.Approve(ctx, adj.ID, "checker", ""); err == nil {
+		t.Fatal("Approve overdrew the account")
+	}
+	if got, _ := adjustments.Get(adj.ID); got.Status != model.AdjustmentPending {
+		t.Errorf("adjustment is %s after a refused approval, want PENDING", got.Status)
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// nationalHolidays are bank holidays on the same date every year, as MM-DD
+var nationalHolidays = map[string]string{
+	"01-26": "Republic Day",
+	"08-15": "Independence Day",
+	"10-02": "Gandhi Jayanti",
+	"12-25": "Christmas",
+}
+
+// BankingCalendar knows the bank's working days, its holidays and when each payment
+// rail settles, so the expected credit time of a transfer can be told up front
+type BankingCalendar struct {
+	location *time.Location
+	holidays map[string]string // YYYY-MM-DD -> name, in addition to nationalHolidays
+	windows  map[model.TransactionType]model.RailWindow
+}
+
+// NewBankingCalendar creates a calendar from configuration. RTGS and NEFT settle in
+// their configured windows on working days; IMPS and UPI settle around the clock.
+func NewBankingCalendar(cfg *config.CalendarConfig) (*BankingCalendar, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		// Containers without tzdata still get bank time for India
+		log.Warn().Err(err).Str("timezone", cfg.Timezone).Msg("Timezone not found, using IST")
+		location = time.FixedZone("IST", 5*60*60+30*60)
+	}
+
+	bc := &BankingCalendar{
+		location: location,
+		holidays: make(map[string]string),
+		windows:  make(map[model.TransactionType]model.RailWindow),
+	}
+
+	for rail, spec := range map[model.TransactionType]string{
+		model.TransactionTypeRTGS: cfg.RTGSWindow,
+		model.TransactionTypeNEFT: cfg.NEFTWindow,
+	} {
+		open, close, err := parseWindow(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s window %q: %w", rail, spec, err)
+		}
+		settlement := 30
+		if rail == model.TransactionTypeNEFT {
+			settlement = 120 // Half-hourly batches
+		}
+		bc.windows[rail] = model.RailWindow{Rail: rail, Open: open, Close: close, WorkingDaysOnly: true, SettlementMins: settlement}
+	}
+
+	if cfg.HolidaysFile != "" {
+		data, err := os.ReadFile(cfg.HolidaysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read holidays: %w", err)
+		}
+		var holidays []model.Holiday
+		if err := json.Unmarshal(data, &holidays); err != nil {
+			return nil, fmt.Errorf("invalid holidays file: %w", err)
+		}
+		for i, h := range holidays {
+			if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+				return nil, fmt.Errorf("holidays[%d]: date must be YYYY-MM-DD", i)
+			}
+			bc.holidays[h.Date] = h.Name
+		}
+	}
+
+	return bc, nil
+}
+
+// Location is the bank's time zone
+func (bc *BankingCalendar) Location() *time.Location {
+	return bc.location
+}
+
+// IsWorkingDay reports whether banks settle on the day of t, and if not, why. Sundays,
+// the second and fourth Saturdays and holidays are not working days.
+func (bc *BankingCalendar) IsWorkingDay(t time.Time) (bool, string) {
+	t = t.In(bc.location)
+	if name := bc.holidayName(t); name != "" {
+		return false, name
+	}
+	switch t.Weekday() {
+	case time.Sunday:
+		return false, "Sunday"
+	case time.Saturday:
+		if week := (t.Day()-1)/7 + 1; week == 2 || week == 4 {
+			return false, fmt.Sprintf("the %s Saturday of the month", ordinal(week))
+		}
+	}
+	return true, ""
+}
+
+// Holidays returns a year's holidays in date order
+func (bc *BankingCalendar) Holidays(year int) []model.Holiday {
+	var holidays []model.Holiday
+	for monthDay, name := range nationalHolidays {
+		holidays = append(holidays, model.Holiday{Date: fmt.Sprintf("%d-%s", year, monthDay), Name: name})
+	}
+	prefix := fmt.Sprintf("%d-", year)
+	for date, name := range bc.holidays {
+		if strings.HasPrefix(date, prefix) && nationalHolidays[date[5:]] == "" {
+			holidays = append(holidays, model.Holiday{Date: date, Name: name})
+		}
+	}
+	sort.Slice(holidays, func(a, b int) bool { return holidays[a].Date < holidays[b].Date })
+	return holidays
+}
+
+// Windows returns the settlement window of each rail that has one
+func (bc *BankingCalendar) Windows() []model.RailWindow {
+	windows := make([]model.RailWindow, 0, len(bc.windows))
+	for _, w := range bc.windows {
+		windows = append(windows, w)
+	}
+	sort.Slice(windows, func(a, b int) bool { return windows[a].Rail < windows[b].Rail })
+	return windows
+}
+
+// Estimate tells when a transfer on a rail made at a given time will be credited
+func (bc *BankingCalendar) Estimate(rail model.TransactionType, at time.Time) *model.SettlementEstimate {
+	at = at.In(bc.location)
+	estimate := &model.SettlementEstimate{Rail: rail, RequestedAt: at}
+
+	window, ok := bc.windows[rail]
+	if !ok {
+		estimate.WithinWindow = true
+		estimate.ExpectedSettlement = at
+		estimate.Message = fmt.Sprintf("%s transfers are credited immediately, at any time of day.", rail)
+		return estimate
+	}
+
+	settle := time.Duration(window.SettlementMins) * time.Minute
+	open := atClock(at, window.Open)
+	close := atClock(at, window.Close)
+	working, whyNot := bc.IsWorkingDay(at)
+
+	switch {
+	case working && !at.Before(open) && at.Before(close):
+		estimate.WithinWindow = true
+		estimate.ExpectedSettlement = at.Add(settle)
+		estimate.Message = fmt.Sprintf("Your %s transfer should be credited by %s today.", rail, estimate.ExpectedSettlement.Format("3:04 PM"))
+		return estimate
+	case working && at.Before(open):
+		estimate.Reason = fmt.Sprintf("%s opens at %s", rail, window.Open)
+		estimate.ExpectedSettlement = open.Add(settle)
+	default:
+		closed := []string{}
+		if working {
+			estimate.Reason = fmt.Sprintf("it was made after the %s cutoff of %s", rail, window.Close)
+		} else {
+			closed = append(closed, whyNot)
+			if name := bc.holidayName(at); name != "" {
+				estimate.Holiday = name
+			}
+		}
+
+		next, skipped := bc.nextWorkingDay(at)
+		for _, day := range skipped {
+			_, why := bc.IsWorkingDay(day)
+			closed = append(closed, why)
+			if name := bc.holidayName(day); name != "" && estimate.Holiday == "" {
+				estimate.Holiday = name
+			}
+		}
+		if len(closed) > 0 {
+			if estimate.Reason != "" {
+				estimate.Reason += " and "
+			}
+			estimate.Reason += "banks are closed on " + joinWithAnd(closed)
+		}
+		estimate.ExpectedSettlement = atClock(next, window.Open).Add(settle)
+	}
+
+	estimate.Delayed = true
+	estimate.Message = fmt.Sprintf("Your %s transfer will be credited by %s, because %s.",
+		rail, estimate.ExpectedSettlement.Format("Monday 2 Jan, 3:04 PM"), estimate.Reason)
+	return estimate
+}
+
+// nextWorkingDay returns the first working day after t and the closed days skipped
+// to reach it
+func (bc *BankingCalendar) nextWorkingDay(t time.Time) (time.Time, []time.Time) {
+	var skipped []time.Time
+	day := t
+	for i := 0; i < 31; i++ {
+		day = day.AddDate(0, 0, 1)
+		if working, _ := bc.IsWorkingDay(day); working {
+			return day, skipped
+		}
+		skipped = append(skipped, day)
+	}
+	return day, skipped
+}
+
+// joinWithAnd joins items as "a, b and c"
+func joinWithAnd(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// holidayName returns the name of the holiday on t's date, or ""
+func (bc *BankingCalendar) holidayName(t time.Time) string {
+	if name, ok := bc.holidays[t.Format("2006-01-02")]; ok {
+		return name
+	}
+	return nationalHolidays[t.Format("01-02")]
+}
+
+// atClock returns t's date at an HH:MM time
+func atClock(t time.Time, clock string) time.Time {
+	c, _ := time.Parse("15:04", clock)
+	return time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), 0, 0, t.Location())
+}
+
+// parseWindow parses an "HH:MM-HH:MM" window
+func parseWindow(spec string) (string, string, error) {
+	parts := strings.Split(spec, "-")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected HH:MM-HH:MM")
+	}
+	open, close := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	o, err := time.Parse("15:04", open)
+	if err != nil {
+		return "", "", fmt.Errorf("bad opening time")
+	}
+	c, err := time.Parse("15:04", close)
+	if err != nil {
+		return "", "", fmt.Errorf("bad cutoff time")
+	}
+	if !o.Before(c) {
+		return "", "", fmt.Errorf("opening time must be before the cutoff")
+	}
+	return open, close, nil
+}
+
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "first"
+	case 2:
+		return "second"
+	case 3:
+		return "third"
+	case 4:
+		return "fourth"
+	}
+	return "fifth"
+}
@@ -3,72 +3,303 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
 )
 
 // BankingGateway provides unified interface for all banking channels
 type BankingGateway struct {
-	mbService  *MBService
-	nbService  *NBService
-	dwhService *DWHService
+	connectors     map[model.Channel]ChannelConnector
+	dwhService     *DWHService
+	sandboxService *SandboxService
+	seedStore      *SeedStore
+	preferences    *PreferenceStore
+	calendar       *BankingCalendar
+	payments       *PaymentMessageService
+	requests       *PaymentRequestService
+	payees         *PayeeDirectory
+	accountStatus  *AccountStatusService
+	webhooks       *WebhookService
+	budgets        *BudgetService
+	ledger         *TransferLedger
 }
 
 // NewBankingGateway creates a new banking gateway
-func NewBankingGateway(mbService *MBService, nbService *NBService, dwhService *DWHService) *BankingGateway {
+func NewBankingGateway(connectors map[model.Channel]ChannelConnector, dwhService *DWHService, sandboxService *SandboxService, seedStore *SeedStore, preferences *PreferenceStore, calendar *BankingCalendar, payments *PaymentMessageService, requests *PaymentRequestService, payees *PayeeDirectory) *BankingGateway {
 	return &BankingGateway{
-		mbService:  mbService,
-		nbService:  nbService,
-		dwhService: dwhService,
+		connectors:     connectors,
+		dwhService:     dwhService,
+		sandboxService: sandboxService,
+		seedStore:      seedStore,
+		preferences:    preferences,
+		calendar:       calendar,
+		payments:       payments,
+		requests:       requests,
+		payees:         payees,
 	}
 }
 
+// SetAccountStatus makes transfers honour account freezes and locks
+func (bg *BankingGateway) SetAccountStatus(accountStatus *AccountStatusService) {
+	bg.accountStatus = accountStatus
+}
+
+// SetWebhooks publishes transfers, rejections and new beneficiaries to webhook
+// subscribers. Sandbox operations are never published.
+func (bg *BankingGateway) SetWebhooks(webhooks *WebhookService) {
+	bg.webhooks = webhooks
+}
+
+// SetBudgets checks users' budgets after each transfer they make
+func (bg *BankingGateway) SetBudgets(budgets *BudgetService) {
+	bg.budgets = budgets
+}
+
+// SetLedger answers transfers repeated with an idempotency key from the first one
+func (bg *BankingGateway) SetLedger(ledger *TransferLedger) {
+	bg.ledger = ledger
+}
+
 // GetBalance retrieves balance based on channel
 func (bg *BankingGateway) GetBalance(ctx context.Context, req *model.BalanceRequest) (*model.BalanceResponse, error) {
-	switch req.Channel {
-	case model.ChannelMB:
-		return bg.mbService.GetBalance(ctx, req)
-	case model.ChannelNB:
-		return bg.nbService.GetBalance(ctx, req)
-	default:
-		return nil, fmt.Errorf("unsupported channel: %s", req.Channel)
+	if req.Sandbox {
+		return bg.sandboxService.GetBalance(ctx, req)
 	}
+
+	// Seeded accounts take precedence over the channel mocks
+	if acct, ok := bg.seedStore.Account(req.AccountID); ok {
+		return &model.BalanceResponse{
+			AccountID:        acct.AccountID,
+			AccountNumber:    acct.AccountNumber,
+			Balance:          acct.Balance,
+			Currency:         acct.Currency,
+			AvailableBalance: acct.Balance,
+			LastUpdated:      acct.LastUpdated,
+		}, nil
+	}
+
+	connector, err := bg.connector(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return connector.GetBalance(ctx, req)
 }
 
-// TransferFunds processes transfer based on channel
+// TransferFunds processes transfer based on channel. A transfer repeated with its
+// idempotency key gets the first one's response and moves no money.
 func (bg *BankingGateway) TransferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error) {
-	switch req.Channel {
-	case model.ChannelMB:
-		return bg.mbService.TransferFunds(ctx, req)
-	case model.ChannelNB:
-		return bg.nbService.TransferFunds(ctx, req)
-	default:
-		return nil, fmt.Errorf("unsupported channel: %s", req.Channel)
+	if bg.ledger == nil || req.IdempotencyKey == "" {
+		return bg.transferFunds(ctx, req)
+	}
+	if previous, err := bg.ledger.Begin(req); previous != nil || err != nil {
+		return previous, err
+	}
+	resp, err := bg.transferFunds(ctx, req)
+	if err != nil {
+		bg.ledger.Release(req.IdempotencyKey)
+		return nil, err
+	}
+	bg.ledger.Complete(req.IdempotencyKey, resp)
+	return resp, nil
+}
+
+// LookupTransfer returns the response of the transfer made with an idempotency key
+func (bg *BankingGateway) LookupTransfer(key string) (*model.TransferResponse, error) {
+	if bg.ledger == nil {
+		return nil, ErrTransferNotFound
+	}
+	return bg.ledger.Lookup(key)
+}
+
+func (bg *BankingGateway) transferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error) {
+	// No money leaves a frozen or locked account, and none reaches a locked one
+	if bg.accountStatus != nil {
+		if err := bg.accountStatus.CheckDebit(ctx, req.UserID, req.FromAccount); err != nil {
+			log.Warn().Str("user_id", req.UserID).Str("account_id", req.FromAccount).Msg("Debit from restricted account refused")
+			bg.publishRejection(req, model.RejectionSourceGateway, err.Error())
+			return nil, err
+		}
+		if err := bg.accountStatus.CheckCredit(ctx, req.ToAccount); err != nil {
+			log.Warn().Str("account_id", req.ToAccount).Msg("Credit to locked account refused")
+			bg.publishRejection(req, model.RejectionSourceGateway, err.Error())
+			return nil, err
+		}
+	}
+
+	// Check the payee against the directory of billers and merchants; a verified
+	// payee names a transfer that did not name its payee
+	verification := bg.payees.Verify(&model.PayeeVerificationRequest{
+		ToAccount: req.ToAccount,
+		IFSC:      req.IFSC,
+		PayeeName: req.PayeeName,
+	})
+	if req.PayeeName == "" && verification.Status == model.PayeeVerified {
+		req.PayeeName = verification.Name
+	}
+
+	var resp *model.TransferResponse
+	var msg *model.PaymentMessage
+	var err error
+	if req.Sandbox {
+		resp, err = bg.sandboxService.TransferFunds(ctx, req)
+	} else {
+		var connector ChannelConnector
+		if connector, err = bg.connector(req.Channel); err != nil {
+			return nil, err
+		}
+		// NEFT and RTGS transfers go out as ISO 20022 messages; one that would not
+		// validate is refused before any money moves
+		if msg, err = bg.payments.Render(req); err != nil {
+			bg.publishRejection(req, model.RejectionSourceGateway, err.Error())
+			return nil, err
+		}
+		resp, err = connector.TransferFunds(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if msg != nil {
+		bg.payments.Record(msg, resp.TransactionID)
+		resp.PaymentMessageID = msg.MessageID
+	}
+	if !req.Sandbox {
+		bg.dwhService.RecordTransfer(req, resp)
+	}
+	resp.PayeeVerification = verification
+
+	if !req.Sandbox {
+		if resp.Status == string(model.TransactionStatusFailed) || resp.Status == string(model.TransactionStatusRejected) {
+			bg.publishRejection(req, model.RejectionSourceConnector, resp.Message)
+		} else {
+			if bg.webhooks != nil {
+				bg.webhooks.Publish(model.EventTransactionCreated, resp)
+			}
+			if bg.budgets != nil {
+				bg.budgets.Evaluate(ctx, req.UserID)
+			}
+		}
+	}
+
+	// A transfer into a user's account may pay one of their payment requests
+	if resp.Status != string(model.TransactionStatusFailed) && resp.Status != string(model.TransactionStatusRejected) {
+		bg.requests.Fulfil(&model.IncomingCredit{
+			AccountID:     req.ToAccount,
+			Amount:        req.Amount,
+			TransactionID: resp.TransactionID,
+			Reference:     req.Remarks,
+			Sandbox:       req.Sandbox,
+		})
 	}
+
+	// Tell the caller when the money will actually arrive on this rail
+	if req.Type != "" {
+		resp.Settlement = bg.calendar.Estimate(req.Type, resp.ProcessedAt)
+	}
+	return resp, nil
 }
 
 // GetStatement retrieves statement based on channel
 func (bg *BankingGateway) GetStatement(ctx context.Context, req *model.StatementRequest) (*model.StatementResponse, error) {
-	switch req.Channel {
-	case model.ChannelMB:
-		return bg.mbService.GetStatement(ctx, req)
-	case model.ChannelNB:
-		return bg.nbService.GetStatement(ctx, req)
-	default:
-		return nil, fmt.Errorf("unsupported channel: %s", req.Channel)
+	if req.Sandbox {
+		return bg.sandboxService.GetStatement(ctx, req)
+	}
+
+	if _, ok := bg.seedStore.Account(req.AccountID); ok {
+		transactions, _ := bg.seedStore.Transactions(req.UserID, req.AccountID, req.StartDate, req.EndDate)
+		if req.Limit > 0 && len(transactions) > req.Limit {
+			transactions = transactions[:req.Limit]
+		}
+		return &model.StatementResponse{
+			AccountID:    req.AccountID,
+			StartDate:    req.StartDate,
+			EndDate:      req.EndDate,
+			Transactions: transactions,
+			Count:        len(transactions),
+			GeneratedAt:  time.Now(),
+		}, nil
+	}
+
+	connector, err := bg.connector(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return connector.GetStatement(ctx, req)
+}
+
+// AddBeneficiary adds beneficiary based on channel (or to the sandbox store when sandbox is set)
+func (bg *BankingGateway) AddBeneficiary(ctx context.Context, channel model.Channel, userID, accountNumber, ifsc, name string, sandbox bool) (*model.Beneficiary, error) {
+	if sandbox {
+		return bg.sandboxService.AddBeneficiary(ctx, userID, accountNumber, ifsc, name)
+	}
+
+	connector, err := bg.connector(channel)
+	if err != nil {
+		return nil, err
+	}
+	beneficiary, err := connector.AddBeneficiary(ctx, userID, accountNumber, ifsc, name)
+	if err == nil && bg.webhooks != nil {
+		bg.webhooks.Publish(model.EventBeneficiaryAdded, beneficiary)
+	}
+	return beneficiary, err
+}
+
+// GetBeneficiary returns the beneficiary a user registered for an account number,
+// from the sandbox store when sandbox is set and otherwise from the seeded users and
+// then the channel
+func (bg *BankingGateway) GetBeneficiary(ctx context.Context, channel model.Channel, userID, accountNumber string, sandbox bool) (*model.Beneficiary, error) {
+	if sandbox {
+		if ben, ok := bg.sandboxService.Beneficiary(userID, accountNumber); ok {
+			return ben, nil
+		}
+		return nil, fmt.Errorf("%w: %s for user %s", ErrBeneficiaryNotFound, accountNumber, userID)
+	}
+	if ben, ok := bg.seedStore.Beneficiary(userID, accountNumber); ok {
+		return ben, nil
+	}
+
+	connector, err := bg.connector(channel)
+	if err != nil {
+		return nil, err
+	}
+	return connector.GetBeneficiary(ctx, userID, accountNumber)
+}
+
+// publishRejection tells webhook subscribers a transfer was refused
+func (bg *BankingGateway) publishRejection(req *model.TransferRequest, source, reason string) {
+	if bg.webhooks == nil || req.Sandbox {
+		return
 	}
+	bg.webhooks.Publish(model.EventTransferRejected, &model.TransferRejection{
+		UserID:      req.UserID,
+		FromAccount: req.FromAccount,
+		ToAccount:   req.ToAccount,
+		Amount:      req.Amount,
+		Type:        string(req.Type),
+		Source:      source,
+		Reason:      reason,
+	})
 }
 
-// AddBeneficiary adds beneficiary based on channel
-func (bg *BankingGateway) AddBeneficiary(ctx context.Context, channel model.Channel, userID, accountNumber, ifsc, name string) (*model.Beneficiary, error) {
-	switch channel {
-	case model.ChannelMB:
-		return bg.mbService.AddBeneficiary(ctx, userID, accountNumber, ifsc, name)
-	case model.ChannelNB:
-		return bg.nbService.AddBeneficiary(ctx, userID, accountNumber, ifsc, name)
-	default:
-		return nil, fmt.Errorf("unsupported channel: %s", channel)
+// connector returns the connector configured for a channel
+func (bg *BankingGateway) connector(channel model.Channel) (ChannelConnector, error) {
+	connector, ok := bg.connectors[channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedChannel, channel)
 	}
+	return connector, nil
+}
+
+// GetPaymentMessage returns the ISO 20022 message a transfer went out as
+func (bg *BankingGateway) GetPaymentMessage(ctx context.Context, transactionID string) (*model.PaymentMessage, bool) {
+	return bg.payments.Get(transactionID)
+}
+
+// AcknowledgePayment records a pacs.002 acknowledgment of a payment message
+func (bg *BankingGateway) AcknowledgePayment(ctx context.Context, data []byte) (*model.PaymentMessage, error) {
+	return bg.payments.Acknowledge(data)
 }
 
 // QueryDWH queries data warehouse
@@ -76,8 +307,78 @@ func (bg *BankingGateway) QueryDWH(ctx context.Context, req *model.DWHQueryReque
 	return bg.dwhService.Query(ctx, req)
 }
 
+// LookupTransactions looks transactions up in the DWH by ID
+func (bg *BankingGateway) LookupTransactions(ctx context.Context, transactionIDs []string) *model.DWHLookupResponse {
+	return bg.dwhService.LookupTransactions(ctx, transactionIDs)
+}
+
+// DWHReplication reports how DWH reads are being routed
+func (bg *BankingGateway) DWHReplication() *model.DWHReplicationStatus {
+	return bg.dwhService.Replication()
+}
+
+// ResetSandbox clears all simulated sandbox state
+func (bg *BankingGateway) ResetSandbox(ctx context.Context) map[string]int {
+	return bg.sandboxService.Reset(ctx)
+}
+
+// SeedData loads a test data fixture
+func (bg *BankingGateway) SeedData(ctx context.Context, fixture *model.SeedFixture, replace bool) (*model.SeedResult, error) {
+	return bg.seedStore.Load(ctx, fixture, replace)
+}
+
+// ClearSeedData drops all seeded test data
+func (bg *BankingGateway) ClearSeedData(ctx context.Context) *model.SeedResult {
+	return bg.seedStore.Clear(ctx)
+}
+
+// GetSeededUser returns the seeded state for a user
+func (bg *BankingGateway) GetSeededUser(ctx context.Context, userID string) (*model.SeedUser, bool) {
+	return bg.seedStore.User(userID)
+}
+
 // GetTransactionHistory retrieves transaction history from DWH
 func (bg *BankingGateway) GetTransactionHistory(ctx context.Context, userID string, days int) ([]model.Transaction, error) {
 	return bg.dwhService.GetTransactionHistory(ctx, userID, days)
 }
 
+
+// GetPreferences returns a user's preferences
+func (bg *BankingGateway) GetPreferences(ctx context.Context, userID string) (*model.UserPreferences, bool) {
+	return bg.preferences.Get(ctx, userID)
+}
+
+// UpdatePreferences merges an update into a user's preferences
+func (bg *BankingGateway) UpdatePreferences(ctx context.Context, userID string, update *model.PreferenceUpdate) (*model.UserPreferences, error) {
+	return bg.preferences.Update(ctx, userID, update)
+}
+
+// DeletePreferences removes a user's preferences
+func (bg *BankingGateway) DeletePreferences(ctx context.Context, userID string) bool {
+	return bg.preferences.Delete(ctx, userID)
+}
+
+// ResolveTransferMethod picks the transfer method a user prefers for an amount
+func (bg *BankingGateway) ResolveTransferMethod(ctx context.Context, userID string, amount float64) *model.TransferMethodResolution {
+	return bg.preferences.ResolveTransferMethod(ctx, userID, amount)
+}
+
+// EstimateSettlement tells when a transfer on a rail made at a given time will be credited
+func (bg *BankingGateway) EstimateSettlement(ctx context.Context, rail model.TransactionType, at time.Time) *model.SettlementEstimate {
+	return bg.calendar.Estimate(rail, at)
+}
+
+// BankLocation is the bank's time zone, in which dates are read when a request does
+// not name the user's
+func (bg *BankingGateway) BankLocation() *time.Location {
+	return bg.calendar.Location()
+}
+
+// GetBankingCalendar returns a year's holidays and the rails' settlement windows
+func (bg *BankingGateway) GetBankingCalendar(ctx context.Context, year int) map[string]interface{} {
+	return map[string]interface{}{
+		"year":     year,
+		"holidays": bg.calendar.Holidays(year),
+		"windows":  bg.calendar.Windows(),
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Budget errors
+var (
+	ErrBudgetsDisabled = errors.New("budgets are disabled")
+	ErrBudgetNotFound  = errors.New("budget not found")
+)
+
+// budgetKeywords place a payment in a category by words in its remarks. Groceries
+// are checked before food so "grocery" is not read as a meal.
+var budgetKeywords = []struct {
+	category string
+	words    []string
+}{
+	{model.BudgetCategoryGroceries, []string{"grocery", "groceries", "supermarket", "vegetables", "kirana"}},
+	{model.BudgetCategoryRent, []string{"rent", "landlord", "lease"}},
+	{model.BudgetCategoryBills, []string{"bill", "electricity", "water", "gas", "broadband", "mobile", "recharge", "dth", "insurance", "emi"}},
+	{model.BudgetCategoryFood, []string{"food", "restaurant", "dinner", "lunch", "breakfast", "cafe", "swiggy", "zomato"}},
+	{model.BudgetCategoryTravel, []string{"travel", "flight", "train", "cab", "uber", "ola", "fuel", "petrol", "hotel", "trip"}},
+	{model.BudgetCategoryShopping, []string{"shopping", "amazon", "flipkart", "myntra", "clothes", "electronics"}},
+	{model.BudgetCategoryEntertainment, []string{"movie", "netflix", "spotify", "concert", "games", "subscription"}},
+}
+
+// budgetCategories are the categories a budget can be set for
+var budgetCategories = map[string]bool{
+	model.BudgetCategoryAll:           true,
+	model.BudgetCategoryFood:          true,
+	model.BudgetCategoryGroceries:     true,
+	model.BudgetCategoryShopping:      true,
+	model.BudgetCategoryTravel:        true,
+	model.BudgetCategoryBills:         true,
+	model.BudgetCategoryRent:          true,
+	model.BudgetCategoryEntertainment: true,
+	model.BudgetCategoryOther:         true,
+}
+
+// budgetEntry is a budget and the thresholds already notified in its current month
+type budgetEntry struct {
+	budget  model.Budget
+	period  time.Time // Start of the month alerted holds thresholds for
+	alerted []int
+}
+
+// BudgetService keeps users' monthly spending budgets and notifies them as their
+// spending crosses each threshold. Months run in the bank's time zone. Budgets are
+// soft: no payment is ever refused for exceeding one.
+type BudgetService struct {
+	cfg           *config.BudgetsConfig
+	dwh           *DWHService
+	notifications *NotificationService
+	location      *time.Location
+
+	mu      sync.Mutex
+	budgets map[string]map[string]*budgetEntry // User ID -> category -> budget
+}
+
+// NewBudgetService creates a budget service
+func NewBudgetService(cfg *config.BudgetsConfig, dwh *DWHService, notifications *NotificationService, calendar *BankingCalendar) *BudgetService {
+	return &BudgetService{
+		cfg:           cfg,
+		dwh:           dwh,
+		notifications: notifications,
+		location:      calendar.Location(),
+		budgets:       make(map[string]map[string]*budgetEntry),
+	}
+}
+
+// Set creates or replaces a user's budget for a category. Thresholds the month's
+// spending has already passed are not notified; the returned status shows them.
+func (bs *BudgetService) Set(ctx context.Context, req *model.BudgetRequest) (*model.BudgetStatus, error) {
+	if !bs.cfg.Enabled {
+		return nil, ErrBudgetsDisabled
+	}
+	if req.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	category := strings.ToUpper(strings.TrimSpace(req.Category))
+	if category == "" {
+		category = model.BudgetCategoryAll
+	}
+	if !budgetCategories[category] {
+		return nil, fmt.Errorf("unknown category %q", req.Category)
+	}
+	if req.Limit <= 0 || math.IsNaN(req.Limit) || math.IsInf(req.Limit, 0) {
+		return nil, fmt.Errorf("limit must be a positive amount")
+	}
+	thresholds, err := normalizeThresholds(req.Thresholds, bs.cfg.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := bs.period(time.Now())
+	spent, count := bs.spent(ctx, req.UserID, category, start, end)
+
+	now := time.Now()
+	bs.mu.Lock()
+	userBudgets, ok := bs.budgets[req.UserID]
+	if !ok {
+		userBudgets = make(map[string]*budgetEntry)
+		bs.budgets[req.UserID] = userBudgets
+	}
+	entry := &budgetEntry{
+		budget: model.Budget{
+			BudgetID:   ids.Ref("BGT_"),
+			UserID:     req.UserID,
+			Category:   category,
+			Limit:      math.Round(req.Limit*100) / 100,
+			Currency:   "INR",
+			Thresholds: thresholds,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+		period: start,
+	}
+	if previous, ok := userBudgets[category]; ok {
+		entry.budget.BudgetID = previous.budget.BudgetID
+		entry.budget.CreatedAt = previous.budget.CreatedAt
+	}
+	entry.alerted = crossed(thresholds, percentUsed(spent, entry.budget.Limit))
+	userBudgets[category] = entry
+	status := budgetStatus(entry, start, end, spent, count)
+	bs.mu.Unlock()
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("category", category).
+		Float64("limit", entry.budget.Limit).
+		Ints("thresholds", thresholds).
+		Msg("Budget set")
+	return status, nil
+}
+
+// Status returns how each of a user's budgets stands this month
+func (bs *BudgetService) Status(ctx context.Context, userID string) ([]model.BudgetStatus, error) {
+	if !bs.cfg.Enabled {
+		return nil, ErrBudgetsDisabled
+	}
+	start, end := bs.period(time.Now())
+	entries := bs.entries(userID)
+
+	statuses := make([]model.BudgetStatus, 0, len(entries))
+	for _, entry := range entries {
+		spent, count := bs.spent(ctx, userID, entry.budget.Category, start, end)
+		bs.mu.Lock()
+		statuses = append(statuses, *budgetStatus(entry, start, end, spent, count))
+		bs.mu.Unlock()
+	}
+	return statuses, nil
+}
+
+// Delete removes a user's budget for a category
+func (bs *BudgetService) Delete(ctx context.Context, userID, category string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	category = strings.ToUpper(category)
+	if _, ok := bs.budgets[userID][category]; !ok {
+		return ErrBudgetNotFound
+	}
+	delete(bs.budgets[userID], category)
+	if len(bs.budgets[userID]) == 0 {
+		delete(bs.budgets, userID)
+	}
+	log.Info().Str("user_id", userID).Str("category", category).Msg("Budget deleted")
+	return nil
+}
+
+// Evaluate checks a user's budgets after a payment is stored and notifies them of
+// each threshold newly crossed this month. Each threshold is notified once a month.
+func (bs *BudgetService) Evaluate(ctx context.Context, userID string) {
+	if !bs.cfg.Enabled {
+		return
+	}
+	start, end := bs.period(time.Now())
+	for _, entry := range bs.entries(userID) {
+		spent, _ := bs.spent(ctx, userID, entry.budget.Category, start, end)
+		used := percentUsed(spent, entry.budget.Limit)
+
+		bs.mu.Lock()
+		if !entry.period.Equal(start) {
+			entry.period, entry.alerted = start, nil
+		}
+		var due []int
+		for _, threshold := range crossed(entry.budget.Thresholds, used) {
+			if !containsInt(entry.alerted, threshold) {
+				due = append(due, threshold)
+				entry.alerted = append(entry.alerted, threshold)
+			}
+		}
+		budget := entry.budget
+		bs.mu.Unlock()
+
+		// One notification for the highest threshold crossed; a payment that jumps
+		// past several should not send a message for each
+		if len(due) == 0 {
+			continue
+		}
+		threshold := due[len(due)-1]
+		if _, err := bs.notifications.Send(ctx, budgetAlert(&budget, threshold, spent)); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Str("category", budget.Category).Msg("Budget alert not sent")
+			continue
+		}
+		log.Info().
+			Str("user_id", userID).
+			Str("category", budget.Category).
+			Int("threshold", threshold).
+			Float64("spent", spent).
+			Msg("Budget threshold crossed")
+	}
+}
+
+// entries returns a user's budgets, ordered by category
+func (bs *BudgetService) entries(userID string) []*budgetEntry {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	entries := make([]*budgetEntry, 0, len(bs.budgets[userID]))
+	for _, entry := range bs.budgets[userID] {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].budget.Category < entries[j].budget.Category })
+	return entries
+}
+
+// period returns the first and last instants of the bank's calendar month holding t
+func (bs *BudgetService) period(t time.Time) (time.Time, time.Time) {
+	local := t.In(bs.location)
+	start := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, bs.location)
+	return start.UTC(), start.AddDate(0, 1, 0).Add(-time.Nanosecond).UTC()
+}
+
+// spent totals a user's payments out in a category between start and end, from
+// their history and the transfers made through the gateway
+func (bs *BudgetService) spent(ctx context.Context, userID, category string, start, end time.Time) (float64, int) {
+	candidates := bs.dwh.UserTransfers(userID)
+	days := int(time.Since(start).Hours()/24) + 1
+	if history, err := bs.dwh.GetTransactionHistory(ctx, userID, days); err == nil {
+		candidates = append(candidates, history...)
+	} else {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Transaction history unavailable for budgets")
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	total, count := 0.0, 0
+	for _, txn := range candidates {
+		if seen[txn.TransactionID] {
+			continue
+		}
+		seen[txn.TransactionID] = true
+		if !isSpending(&txn) || txn.CreatedAt.Before(start) || txn.CreatedAt.After(end) {
+			continue
+		}
+		if category != model.BudgetCategoryAll && spendingCategory(txn.Remarks) != category {
+			continue
+		}
+		total += math.Abs(txn.Amount)
+		count++
+	}
+	return math.Round(total*100) / 100, count
+}
+
+// isSpending reports whether a transaction is money the user paid out that has not
+// failed
+func isSpending(txn *model.Transaction) bool {
+	if txn.Status == model.TransactionStatusFailed || txn.Status == model.TransactionStatusRejected {
+		return false
+	}
+	return isRailTransfer(txn.Type) || txn.Type == model.TransactionTypeDEBIT
+}
+
+// spendingCategory places a payment in a category by its remarks
+func spendingCategory(remarks string) string {
+	words := strings.FieldsFunc(strings.ToLower(remarks), func(r rune) bool {
+		return !('a' <= r && r <= 'z')
+	})
+	for _, group := range budgetKeywords {
+		for _, word := range words {
+			if containsString(group.words, word) {
+				return group.category
+			}
+		}
+	}
+	return model.BudgetCategoryOther
+}
+
+// normalizeThresholds checks requested thresholds, or takes the defaults, and
+// returns them ascending without repeats
+func normalizeThresholds(requested, defaults []int) ([]int, error) {
+	source := requested
+	if len(source) == 0 {
+		source = defaults
+	}
+	thresholds := make([]int, 0, len(source))
+	for _, t := range source {
+		if t < 1 || t > 200 {
+			return nil, fmt.Errorf("thresholds must be percentages between 1 and 200")
+		}
+		if !containsInt(thresholds, t) {
+			thresholds = append(thresholds, t)
+		}
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+// crossed returns the thresholds a percentage used has reached
+func crossed(thresholds []int, used float64) []int {
+	var reached []int
+	for _, t := range thresholds {
+		if used >= float64(t) {
+			reached = append(reached, t)
+		}
+	}
+	return reached
+}
+
+func percentUsed(spent, limit float64) float64 {
+	return math.Round(spent/limit*1000) / 10
+}
+
+// budgetStatus builds a budget's status; callers hold the service's lock
+func budgetStatus(entry *budgetEntry, start, end time.Time, spent float64, count int) *model.BudgetStatus {
+	status := &model.BudgetStatus{
+		Budget:       entry.budget,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		Spent:        spent,
+		Remaining:    math.Round((entry.budget.Limit-spent)*100) / 100,
+		PercentUsed:  percentUsed(spent, entry.budget.Limit),
+		Transactions: count,
+	}
+	status.Thresholds = append([]int(nil), entry.budget.Thresholds...)
+	if entry.period.Equal(start) {
+		status.Alerted = append([]int(nil), entry.alerted...)
+	}
+	return status
+}
+
+// budgetAlert is the notification for a budget crossing a threshold
+func budgetAlert(budget *model.Budget, threshold int, spent float64) *model.NotificationRequest {
+	scope := "your monthly budget"
+	if budget.Category != model.BudgetCategoryAll {
+		scope = "your monthly " + strings.ToLower(budget.Category) + " budget"
+	}
+	subject := fmt.Sprintf("You have used %d%% of %s", threshold, scope)
+	if spent >= budget.Limit {
+		subject = "You have gone over " + scope
+	}
+	body := fmt.Sprintf("You have spent %s %.2f of %s of %s %.2f this month.", budget.Currency, spent, scope, budget.Currency, budget.Limit)
+	if remaining := budget.Limit - spent; remaining > 0 {
+		body += fmt.Sprintf(" %s %.2f is left.", budget.Currency, remaining)
+	} else {
+		body += fmt.Sprintf(" That is %s %.2f over.", budget.Currency, -remaining)
+	}
+	return &model.NotificationRequest{
+		UserID:   budget.UserID,
+		Category: model.NotificationBudget,
+		Subject:  subject,
+		Body:     body,
+	}
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
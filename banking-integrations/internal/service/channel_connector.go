@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Connector errors
+var (
+	ErrUnsupportedChannel   = errors.New("unsupported channel")
+	ErrConnectorUnavailable = errors.New("connector cannot reach its backend")
+	ErrOperationUnsupported = errors.New("operation not supported by this connector")
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrBeneficiaryNotFound  = errors.New("beneficiary not found")
+)
+
+// ChannelConnector is how the gateway reaches the system behind a banking channel.
+// MBService and NBService are the mock implementations.
+type ChannelConnector interface {
+	GetBalance(ctx context.Context, req *model.BalanceRequest) (*model.BalanceResponse, error)
+	TransferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error)
+	GetStatement(ctx context.Context, req *model.StatementRequest) (*model.StatementResponse, error)
+	AddBeneficiary(ctx context.Context, userID, accountNumber, ifsc, name string) (*model.Beneficiary, error)
+	// GetBeneficiary returns the beneficiary a user registered for an account number,
+	// including when it was added
+	GetBeneficiary(ctx context.Context, userID, accountNumber string) (*model.Beneficiary, error)
+
+	// GetAccount and UpdateBalance serve the back office: the record of an account,
+	// including its owner, and posting a ledger entry to its balance
+	GetAccount(ctx context.Context, accountID string) (*model.Account, error)
+	UpdateBalance(ctx context.Context, req *model.BalanceUpdateRequest) (*model.BalanceUpdateResponse, error)
+}
+
+// NewChannelConnectors builds the connector configured for each channel. Channels
+// without a connector type are left out and the gateway refuses them.
+func NewChannelConnectors(cfg *config.ConnectorsConfig) (map[model.Channel]ChannelConnector, error) {
+	channels := map[model.Channel]config.ConnectorConfig{
+		model.ChannelMB:  cfg.MB,
+		model.ChannelNB:  cfg.NB,
+		model.ChannelAPI: cfg.API,
+	}
+
+	connectors := make(map[model.Channel]ChannelConnector)
+	for channel, connCfg := range channels {
+		if connCfg.Type == "" {
+			continue
+		}
+		connector, err := newChannelConnector(channel, connCfg)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channel, err)
+		}
+		connectors[channel] = connector
+		log.Info().Str("channel", string(channel)).Str("connector", connCfg.Type).Str("url", connCfg.URL).Msg("Channel connector configured")
+	}
+	return connectors, nil
+}
+
+// newChannelConnector builds one channel's connector
+func newChannelConnector(channel model.Channel, cfg config.ConnectorConfig) (ChannelConnector, error) {
+	switch cfg.Type {
+	case model.ConnectorMock:
+		if channel == model.ChannelNB {
+			return NewNBService(), nil
+		}
+		return NewMBService(), nil
+	case model.ConnectorREST:
+		return NewRESTConnector(channel, cfg)
+	case model.ConnectorISO8583:
+		return NewISO8583Connector(channel, cfg)
+	default:
+		return nil, fmt.Errorf("unknown connector type %q (want mock, rest or iso8583)", cfg.Type)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// CreditScorer scores a user's DWH profile with the Scoring Agent (Layer 3), so batch
+// scores come from the same pipeline, and model versions, as scores given in a chat
+type CreditScorer struct {
+	agentURL   string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewCreditScorer creates a new credit scorer
+func NewCreditScorer(cfg *config.ScoringConfig) *CreditScorer {
+	return &CreditScorer{
+		agentURL: cfg.AgentURL,
+		apiKey:   config.Profile().RotatingSecret("SCORING_AGENT_API_KEY", cfg.APIKey),
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// Score returns the credit score snapshot for a profile
+func (cs *CreditScorer) Score(ctx context.Context, runID string, profile map[string]interface{}) (*model.ScoreSnapshot, error) {
+	userID, _ := profile["user_id"].(string)
+	reqBody := map[string]interface{}{
+		"task":       "CREDIT_SCORE",
+		"request_id": fmt.Sprintf("%s_%s", runID, userID),
+		"session_id": runID,
+		"timestamp":  time.Now(),
+		"input_context": map[string]interface{}{
+			"score_type":       "CREDIT",
+			"user_id":          userID,
+			"account_age_days": profileNumber(profile, "account_age_days"),
+			"income":           profileNumber(profile, "monthly_income"),
+			"balance":          profileNumber(profile, "total_balance"),
+		},
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", cs.agentURL+"/api/v1/process", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", cs.apiKey.Get())
+
+	resp, err := cs.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("scoring agent unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("scoring agent returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var out struct {
+		Result    map[string]interface{} `json:"result"`
+		RiskScore float64                `json:"risk_score"`
+		Model     *struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Source  string `json:"source"`
+		} `json:"model"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode scoring response: %w", err)
+	}
+
+	score, ok := out.Result["credit_score"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("scoring agent returned no credit score")
+	}
+
+	snapshot := &model.ScoreSnapshot{
+		RunID:       runID,
+		UserID:      userID,
+		CreditScore: int(score),
+		RiskScore:   out.RiskScore,
+		ScoredAt:    time.Now(),
+	}
+	snapshot.ScoreRange, _ = out.Result["score_range"].(string)
+	if out.Model != nil {
+		snapshot.ModelVersion = fmt.Sprintf("%s@%s (%s)", out.Model.Name, out.Model.Version, out.Model.Source)
+	}
+	return snapshot, nil
+}
+
+// profileNumber reads a numeric profile field as a float, whatever its Go type
+func profileNumber(profile map[string]interface{}, key string) float64 {
+	switch v := profile[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/demo"
+)
+
+// DemoSeeder loads the seed data of every demo scenario, at startup and again on
+// reset, so each storyline starts from the same accounts, balances and history
+type DemoSeeder struct {
+	scenarios *demo.Library
+	seeds     *SeedStore
+}
+
+// NewDemoSeeder checks every scenario's seed data parses as a fixture
+func NewDemoSeeder(scenarios *demo.Library, seeds *SeedStore) (*DemoSeeder, error) {
+	for _, scenario := range scenarios.List() {
+		if _, err := seedFixture(scenario); err != nil {
+			return nil, err
+		}
+	}
+	return &DemoSeeder{scenarios: scenarios, seeds: seeds}, nil
+}
+
+// Seed loads every scenario's fixture, replacing the seeded state of its users. Users
+// seeded by anything else are kept.
+func (ds *DemoSeeder) Seed(ctx context.Context) (map[string]*model.SeedResult, error) {
+	results := make(map[string]*model.SeedResult)
+	for _, scenario := range ds.scenarios.List() {
+		// Parsed afresh each time: the store keeps the fixture's accounts and changes them
+		fixture, err := seedFixture(scenario)
+		if err != nil || fixture == nil {
+			continue
+		}
+		result, err := ds.seeds.Load(ctx, fixture, false)
+		if err != nil {
+			return results, fmt.Errorf("demo scenario %s: %w", scenario.ID, err)
+		}
+		results[scenario.ID] = result
+	}
+	return results, nil
+}
+
+// Scenarios returns the loaded scenarios
+func (ds *DemoSeeder) Scenarios() []*demo.Scenario {
+	return ds.scenarios.List()
+}
+
+// seedFixture parses a scenario's seed data, or returns nil when it has none
+func seedFixture(scenario *demo.Scenario) (*model.SeedFixture, error) {
+	if len(scenario.Seed) == 0 {
+		return nil, nil
+	}
+	var fixture model.SeedFixture
+	if err := json.Unmarshal(scenario.Seed, &fixture); err != nil {
+		return nil, fmt.Errorf("demo scenario %s: invalid seed: %w", scenario.ID, err)
+	}
+	return &fixture, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/aibanking/banking-integrations/internal/config"
@@ -12,22 +13,101 @@ import (
 
 // DWHService handles Data Warehouse operations
 type DWHService struct {
-	config *config.DWHConfig
-	// In production, this would have database connection
+	config    *config.DWHConfig
+	seedStore *SeedStore
+
+	// Transfers made through the gateway, written to the primary and read from the
+	// replicas; in production the DWH is fed from the core banking system
+	store *DWHStore
 }
 
 // NewDWHService creates a new DWH service
-func NewDWHService(cfg *config.DWHConfig) *DWHService {
+func NewDWHService(cfg *config.DWHConfig, seedStore *SeedStore) *DWHService {
 	return &DWHService{
-		config: cfg,
+		config:    cfg,
+		seedStore: seedStore,
+		store:     NewDWHStore(cfg),
+	}
+}
+
+// RecordTransfer records a transfer the gateway made
+func (dwh *DWHService) RecordTransfer(req *model.TransferRequest, resp *model.TransferResponse) {
+	txn := model.Transaction{
+		TransactionID:   resp.TransactionID,
+		AccountID:       req.FromAccount,
+		UserID:          req.UserID,
+		Type:            req.Type,
+		Amount:          resp.Amount,
+		Currency:        "INR",
+		FromAccount:     req.FromAccount,
+		ToAccount:       req.ToAccount,
+		IFSC:            req.IFSC,
+		Status:          model.TransactionStatus(resp.Status),
+		Remarks:         req.Remarks,
+		Channel:         req.Channel,
+		ReferenceNumber: resp.ReferenceNumber,
+		CreatedAt:       resp.ProcessedAt,
+	}
+	if txn.Status == model.TransactionStatusCompleted {
+		completed := resp.ProcessedAt
+		txn.CompletedAt = &completed
+	}
+
+	dwh.store.Write(txn)
+}
+
+// RecordEntry records a ledger entry posted to an account in core banking, such as
+// a back-office adjustment
+func (dwh *DWHService) RecordEntry(entry model.Transaction) {
+	dwh.store.Write(entry)
+}
+
+// UserTransfers returns the transfers a user made through the gateway
+func (dwh *DWHService) UserTransfers(userID string) []model.Transaction {
+	transfers, _ := dwh.store.UserTransfers(userID)
+	return transfers
+}
+
+// WatchReplicas keeps the DWH replicas' lag up to date until ctx is done
+func (dwh *DWHService) WatchReplicas(ctx context.Context) {
+	dwh.store.Watch(ctx)
+}
+
+// Replication reports how DWH reads are being routed over the primary and replicas
+func (dwh *DWHService) Replication() *model.DWHReplicationStatus {
+	return dwh.store.Status()
+}
+
+// LookupTransactions returns the transactions with the given IDs that the DWH has a
+// record of, and the IDs it has none for
+func (dwh *DWHService) LookupTransactions(ctx context.Context, transactionIDs []string) *model.DWHLookupResponse {
+	resp := &model.DWHLookupResponse{
+		Transactions: make(map[string]model.Transaction),
+		Missing:      []string{},
+		ExecutedAt:   time.Now(),
+	}
+
+	transfers, servedBy := dwh.store.Lookup(transactionIDs)
+	resp.ServedBy = servedBy
+	for _, id := range transactionIDs {
+		if txn, ok := transfers[id]; ok {
+			resp.Transactions[id] = txn
+		} else if txn, ok := dwh.seedStore.Transaction(id); ok {
+			resp.Transactions[id] = *txn
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
 	}
+	return resp
 }
 
 // Query executes a query against the data warehouse
 func (dwh *DWHService) Query(ctx context.Context, req *model.DWHQueryRequest) (*model.DWHQueryResponse, error) {
+	servedBy := dwh.store.Route(req.UserID)
 	log.Info().
 		Str("query_type", req.QueryType).
 		Str("user_id", req.UserID).
+		Str("node", servedBy).
 		Msg("DWH: Executing query")
 
 	var data []map[string]interface{}
@@ -48,11 +128,39 @@ func (dwh *DWHService) Query(ctx context.Context, req *model.DWHQueryRequest) (*
 		Data:       data,
 		Count:      len(data),
 		ExecutedAt: time.Now(),
+		ServedBy:   servedBy,
 	}, nil
 }
 
 // getTransactionHistory retrieves transaction history from DWH
 func (dwh *DWHService) getTransactionHistory(ctx context.Context, req *model.DWHQueryRequest) []map[string]interface{} {
+	since := time.Time{}
+	if req.StartDate != nil {
+		since = *req.StartDate
+	}
+	until := time.Time{}
+	if req.EndDate != nil {
+		until = *req.EndDate
+	}
+	if seeded, ok := dwh.seedStore.Transactions(req.UserID, req.AccountID, since, until); ok {
+		history := make([]map[string]interface{}, 0, len(seeded))
+		for _, txn := range seeded {
+			if req.Limit > 0 && len(history) >= req.Limit {
+				break
+			}
+			history = append(history, map[string]interface{}{
+				"transaction_id": txn.TransactionID,
+				"user_id":        txn.UserID,
+				"account_id":     txn.AccountID,
+				"amount":         txn.Amount,
+				"type":           string(txn.Type),
+				"status":         string(txn.Status),
+				"created_at":     txn.CreatedAt,
+			})
+		}
+		return history
+	}
+
 	// Mock data - in production would query DWH database
 	history := []map[string]interface{}{
 		{
@@ -86,10 +194,15 @@ func (dwh *DWHService) getTransactionHistory(ctx context.Context, req *model.DWH
 
 // getUserProfile retrieves user profile from DWH
 func (dwh *DWHService) getUserProfile(ctx context.Context, req *model.DWHQueryRequest) []map[string]interface{} {
+	if user, ok := dwh.seedStore.User(req.UserID); ok {
+		return []map[string]interface{}{seededProfile(user)}
+	}
+
 	// Mock data - in production would query DWH database
 	profile := []map[string]interface{}{
 		{
 			"user_id":          req.UserID,
+			"phone":            "+919800000000",
 			"account_age_days": 365,
 			"total_balance":    150000.0,
 			"monthly_income":   50000.0,
@@ -137,16 +250,65 @@ func (dwh *DWHService) getAnalytics(ctx context.Context, req *model.DWHQueryRequ
 	return analytics
 }
 
+// ListUsers returns the IDs of the users in the customer base
+func (dwh *DWHService) ListUsers(ctx context.Context) []string {
+	// Only seeded users are known until the DWH database is wired in
+	return dwh.seedStore.UserIDs()
+}
+
+// GetUserProfile returns a user's profile row
+func (dwh *DWHService) GetUserProfile(ctx context.Context, userID string) map[string]interface{} {
+	return dwh.getUserProfile(ctx, &model.DWHQueryRequest{UserID: userID})[0]
+}
+
 // GetTransactionHistory retrieves transaction history for a user
 func (dwh *DWHService) GetTransactionHistory(ctx context.Context, userID string, days int) ([]model.Transaction, error) {
 	log.Info().
 		Str("user_id", userID).
 		Int("days", days).
+		Str("node", dwh.store.Route(userID)).
 		Msg("DWH: Getting transaction history")
 
-	// Mock transactions - in production would query DWH
-	now := time.Now()
-	transactions := []model.Transaction{
+	if seeded, ok := dwh.seedStore.Transactions(userID, "", time.Now().AddDate(0, 0, -days), time.Time{}); ok {
+		return seeded, nil
+	}
+
+	return mockHistory(userID), nil
+}
+
+// TransactionsBetween returns a user's transactions made in [from, to), oldest first,
+// for one account or all of them. Transfers made through the gateway are included.
+func (dwh *DWHService) TransactionsBetween(ctx context.Context, userID, accountID string, from, to time.Time) []model.Transaction {
+	history, ok := dwh.seedStore.Transactions(userID, accountID, from, to.Add(-time.Nanosecond))
+	if !ok {
+		history = mockHistory(userID)
+	}
+	transfers, _ := dwh.store.UserTransfers(userID)
+
+	seen := make(map[string]bool, len(history))
+	transactions := make([]model.Transaction, 0, len(history))
+	for _, txn := range append(history, transfers...) {
+		if seen[txn.TransactionID] || txn.CreatedAt.Before(from) || !txn.CreatedAt.Before(to) {
+			continue
+		}
+		if accountID != "" && txn.AccountID != accountID {
+			continue
+		}
+		seen[txn.TransactionID] = true
+		transactions = append(transactions, txn)
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+	})
+	return transactions
+}
+
+// mockHistory is the history of a user who was not seeded - in production this would
+// query the DWH. Dated from midnight so repeated reads on one day return the same
+// history.
+func mockHistory(userID string) []model.Transaction {
+	now := time.Now().Truncate(24 * time.Hour)
+	return []model.Transaction{
 		{
 			TransactionID: "TXN_001",
 			UserID:        userID,
@@ -172,7 +334,50 @@ func (dwh *DWHService) GetTransactionHistory(ctx context.Context, userID string,
 			CreatedAt:     now.AddDate(0, 0, -1),
 		},
 	}
-
-	return transactions, nil
 }
 
+
+// seededProfile builds a USER_PROFILE row from seeded data
+func seededProfile(user *model.SeedUser) map[string]interface{} {
+	totalBalance := 0.0
+	accountType := ""
+	accountAgeDays := 0
+	for _, acct := range user.Accounts {
+		totalBalance += acct.Balance
+		if accountType == "" {
+			accountType = acct.AccountType
+		}
+		if age := int(time.Since(acct.CreatedAt).Hours() / 24); age > accountAgeDays {
+			accountAgeDays = age
+		}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -30)
+	count30d := 0
+	total := 0.0
+	for _, txn := range user.Transactions {
+		total += txn.Amount
+		if txn.CreatedAt.After(cutoff) {
+			count30d++
+		}
+	}
+	avgAmount := 0.0
+	if len(user.Transactions) > 0 {
+		avgAmount = total / float64(len(user.Transactions))
+	}
+
+	return map[string]interface{}{
+		"user_id":                user.UserID,
+		"name":                   user.Name,
+		"phone":                  user.Phone,
+		"account_age_days":       accountAgeDays,
+		"total_balance":          totalBalance,
+		"monthly_income":         user.MonthlyIncome,
+		"transaction_count_30d":  count30d,
+		"avg_transaction_amount": avgAmount,
+		"credit_score":           user.CreditScore,
+		"kyc_status":             user.KYCStatus,
+		"account_type":           accountType,
+		"seeded":                 true,
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Reasons a read went to the primary although replicas are configured
+const (
+	dwhFallbackLag            = "REPLICA_LAG"      // No replica is within DWH_MAX_REPLICA_LAG
+	dwhFallbackReadAfterWrite = "READ_AFTER_WRITE" // No replica has replayed the latest write to the data
+)
+
+// dwhNode is one DWH database: the primary or a read replica
+type dwhNode struct {
+	host      string
+	role      string
+	transfers map[string]model.Transaction
+	position  uint64        // Writes applied
+	lag       time.Duration // How far it trailed the primary at the last check
+	checkedAt time.Time
+	lagging   bool
+	reads     int64
+}
+
+func newDWHNode(host, role string) *dwhNode {
+	return &dwhNode{
+		host:      host,
+		role:      role,
+		transfers: make(map[string]model.Transaction),
+	}
+}
+
+// dwhWrite is a write to the primary that a replica has yet to replay
+type dwhWrite struct {
+	position uint64
+	txn      model.Transaction
+	at       time.Time
+}
+
+// DWHStore is where the DWH keeps its data. Writes go to the primary and reads are
+// spread over the read replicas, so heavy statement and analytics reads do not contend
+// with transfers being recorded.
+//
+// Writes are numbered. A read of a user or transaction goes to a replica only once the
+// replica has replayed the latest write to it, so a transfer reads back as soon as it
+// is made; until then, or when every replica trails the primary by more than
+// DWH_MAX_REPLICA_LAG, reads go to the primary.
+//
+// The nodes are kept in memory until the DWH database is wired in. The replicas replay
+// the primary's writes at each lag check, so reads are routed as they will be against
+// real replicas, where the database replicates and the check reads each replica's
+// replay position and lag.
+type DWHStore struct {
+	cfg *config.DWHConfig
+
+	mu        sync.Mutex
+	primary   *dwhNode
+	replicas  []*dwhNode
+	pending   []dwhWrite        // Writes not yet replayed on every replica, oldest first
+	written   map[string]uint64 // User or transaction key -> position of its latest write
+	next      int               // The replica to try first, round-robin
+	fallbacks map[string]int64
+}
+
+// NewDWHStore creates the DWH store with the primary and replicas in cfg
+func NewDWHStore(cfg *config.DWHConfig) *DWHStore {
+	store := &DWHStore{
+		cfg:       cfg,
+		primary:   newDWHNode(cfg.Host, model.DWHRolePrimary),
+		written:   make(map[string]uint64),
+		fallbacks: make(map[string]int64),
+	}
+	for _, host := range cfg.ReplicaHosts {
+		store.replicas = append(store.replicas, newDWHNode(host, model.DWHRoleReplica))
+	}
+	return store
+}
+
+// Write records a transaction on the primary
+func (s *DWHStore) Write(txn model.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.primary.position++
+	s.primary.transfers[txn.TransactionID] = txn
+	if len(s.replicas) == 0 {
+		return
+	}
+	s.written[dwhUserKey(txn.UserID)] = s.primary.position
+	s.written[dwhTransactionKey(txn.TransactionID)] = s.primary.position
+	s.pending = append(s.pending, dwhWrite{position: s.primary.position, txn: txn, at: time.Now()})
+}
+
+// UserTransfers returns the transfers a user made through the gateway, and the host
+// that answered
+func (s *DWHStore) UserTransfers(userID string) ([]model.Transaction, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := s.reader(dwhUserKey(userID))
+	var transfers []model.Transaction
+	for _, txn := range node.transfers {
+		if txn.UserID == userID {
+			transfers = append(transfers, txn)
+		}
+	}
+	return transfers, node.host
+}
+
+// Lookup returns the transactions the store has with the given IDs, and the host that
+// answered
+func (s *DWHStore) Lookup(transactionIDs []string) (map[string]model.Transaction, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, len(transactionIDs))
+	for i, id := range transactionIDs {
+		keys[i] = dwhTransactionKey(id)
+	}
+	node := s.reader(keys...)
+	found := make(map[string]model.Transaction)
+	for _, id := range transactionIDs {
+		if txn, ok := node.transfers[id]; ok {
+			found[id] = txn
+		}
+	}
+	return found, node.host
+}
+
+// Route picks the host for a read of a user's data that the store does not answer
+// itself yet, such as a DWH query
+func (s *DWHStore) Route(userID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if userID == "" {
+		return s.reader().host
+	}
+	return s.reader(dwhUserKey(userID)).host
+}
+
+// reader picks the node for a read of keys and counts the read; callers hold s.mu
+func (s *DWHStore) reader(keys ...string) *dwhNode {
+	if len(s.replicas) > 0 {
+		var needed uint64
+		for _, key := range keys {
+			if position := s.written[key]; position > needed {
+				needed = position
+			}
+		}
+		reason := dwhFallbackLag
+		for i := range s.replicas {
+			replica := s.replicas[(s.next+i)%len(s.replicas)]
+			if !s.available(replica) {
+				continue
+			}
+			if replica.position < needed {
+				reason = dwhFallbackReadAfterWrite
+				continue
+			}
+			s.next = (s.next + i + 1) % len(s.replicas)
+			replica.reads++
+			return replica
+		}
+		s.fallbacks[reason]++
+	}
+	s.primary.reads++
+	return s.primary
+}
+
+// available reports whether a replica may serve reads: it was checked recently and
+// trailed the primary by no more than DWH_MAX_REPLICA_LAG. A replica whose checks stop
+// succeeding drops out once its last check is that old.
+func (s *DWHStore) available(replica *dwhNode) bool {
+	maxLag := time.Duration(s.cfg.MaxReplicaLag) * time.Second
+	return !replica.checkedAt.IsZero() && time.Since(replica.checkedAt) <= maxLag && replica.lag <= maxLag
+}
+
+// Watch checks the replicas every DWH_REPLICA_CHECK_INTERVAL until ctx is done
+func (s *DWHStore) Watch(ctx context.Context) {
+	if len(s.replicas) == 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(s.cfg.ReplicaCheckInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		s.check()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check measures how far each replica trails the primary and has it replay the
+// primary's writes
+func (s *DWHStore) check() {
+	maxLag := time.Duration(s.cfg.MaxReplicaLag) * time.Second
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayed := s.primary.position
+	for _, replica := range s.replicas {
+		replica.lag = 0
+		for _, w := range s.pending {
+			if w.position <= replica.position {
+				continue
+			}
+			if replica.lag == 0 {
+				replica.lag = now.Sub(w.at)
+			}
+			replica.transfers[w.txn.TransactionID] = w.txn
+			replica.position = w.position
+		}
+		replica.checkedAt = now
+
+		if lagging := replica.lag > maxLag; lagging != replica.lagging {
+			replica.lagging = lagging
+			if lagging {
+				log.Warn().Str("replica", replica.host).Dur("lag", replica.lag).Msg("DWH replica is lagging; its reads go to the primary")
+			} else {
+				log.Info().Str("replica", replica.host).Dur("lag", replica.lag).Msg("DWH replica caught up")
+			}
+		}
+		if replica.position < replayed {
+			replayed = replica.position
+		}
+	}
+
+	// Writes every replica has replayed need no more tracking
+	kept := s.pending[:0]
+	for _, w := range s.pending {
+		if w.position > replayed {
+			kept = append(kept, w)
+		}
+	}
+	s.pending = kept
+	for key, position := range s.written {
+		if position <= replayed {
+			delete(s.written, key)
+		}
+	}
+}
+
+// Status reports each node's position, lag and reads, and why reads fell back to the
+// primary
+func (s *DWHStore) Status() *model.DWHReplicationStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &model.DWHReplicationStatus{
+		Nodes: []model.DWHNodeStatus{{
+			Host:      s.primary.host,
+			Role:      s.primary.role,
+			Position:  s.primary.position,
+			Available: true,
+			Reads:     s.primary.reads,
+		}},
+		Fallbacks: make(map[string]int64, len(s.fallbacks)),
+	}
+	for _, replica := range s.replicas {
+		node := model.DWHNodeStatus{
+			Host:      replica.host,
+			Role:      replica.role,
+			Position:  replica.position,
+			Lag:       replica.lag.Seconds(),
+			Available: s.available(replica),
+			Reads:     replica.reads,
+		}
+		if !replica.checkedAt.IsZero() {
+			checkedAt := replica.checkedAt
+			node.CheckedAt = &checkedAt
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+	for reason, count := range s.fallbacks {
+		status.Fallbacks[reason] = count
+	}
+	return status
+}
+
+func dwhUserKey(userID string) string {
+	return "user:" + userID
+}
+
+func dwhTransactionKey(transactionID string) string {
+	return "txn:" + transactionID
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// exportWriter writes an export in one format, a transaction at a time as they are
+// read. Nothing it writes may depend on transactions not yet seen.
+type exportWriter interface {
+	begin() error
+	write(txn *model.Transaction) error
+	end() error
+}
+
+// csvWriter writes a header row and a row per transaction
+type csvWriter struct {
+	out      *csv.Writer
+	location *time.Location
+}
+
+func newCSVWriter(out *bufio.Writer, location *time.Location) *csvWriter {
+	return &csvWriter{out: csv.NewWriter(out), location: location}
+}
+
+func (cw *csvWriter) begin() error {
+	return cw.row("Date", "Description", "Amount", "Currency", "Type", "Status", "Reference", "Transaction ID", "Account")
+}
+
+func (cw *csvWriter) write(txn *model.Transaction) error {
+	return cw.row(
+		txn.CreatedAt.In(cw.location).Format("2006-01-02"),
+		csvText(exportDescription(txn)),
+		strconv.FormatFloat(signedAmount(txn), 'f', 2, 64),
+		exportCurrency(txn),
+		string(txn.Type),
+		string(txn.Status),
+		csvText(txn.ReferenceNumber),
+		txn.TransactionID,
+		txn.AccountID,
+	)
+}
+
+func (cw *csvWriter) end() error {
+	return nil
+}
+
+// row writes a row through to the buffered output, so it is flushed with the window
+func (cw *csvWriter) row(fields ...string) error {
+	if err := cw.out.Write(fields); err != nil {
+		return err
+	}
+	cw.out.Flush()
+	return cw.out.Error()
+}
+
+// csvText keeps a spreadsheet from running text as a formula: text starting with one
+// of = + - @ is prefixed with a quote
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// qifWriter writes a Quicken Interchange Format bank account list
+type qifWriter struct {
+	out      *bufio.Writer
+	location *time.Location
+}
+
+func (qw *qifWriter) begin() error {
+	_, err := qw.out.WriteString("!Type:Bank\n")
+	return err
+}
+
+func (qw *qifWriter) write(txn *model.Transaction) error {
+	var entry strings.Builder
+	entry.WriteString("D" + txn.CreatedAt.In(qw.location).Format("01/02/2006") + "\n")
+	entry.WriteString("T" + strconv.FormatFloat(signedAmount(txn), 'f', 2, 64) + "\n")
+	if txn.ReferenceNumber != "" {
+		entry.WriteString("N" + qifText(txn.ReferenceNumber) + "\n")
+	}
+	entry.WriteString("P" + qifText(exportDescription(txn)) + "\n")
+	entry.WriteString("M" + qifText(fmt.Sprintf("%s %s", txn.Type, txn.TransactionID)) + "\n")
+	if txn.Status == model.TransactionStatusCompleted {
+		entry.WriteString("CX\n")
+	}
+	entry.WriteString("^\n")
+	_, err := qw.out.WriteString(entry.String())
+	return err
+}
+
+func (qw *qifWriter) end() error {
+	return nil
+}
+
+// qifText keeps a field on its line; QIF fields end at the newline
+func qifText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// ofxWriter writes an OFX 1.0.2 bank statement. The transaction list's dates are the
+// export's range, known up front; the ledger balance, which comes after the list, is
+// the account's current balance when a single known account is exported, and is left
+// out otherwise.
+type ofxWriter struct {
+	out      *bufio.Writer
+	export   *model.Export
+	location *time.Location
+	bankID   string
+	account  *model.Account // Nil unless one known account is exported
+}
+
+func (ow *ofxWriter) begin() error {
+	accountID := ow.export.AccountID
+	if accountID == "" {
+		accountID = ow.export.UserID
+	}
+	currency := "INR"
+	accountType := "SAVINGS"
+	if ow.account != nil {
+		if ow.account.Currency != "" {
+			currency = ow.account.Currency
+		}
+		if ow.account.AccountType == "CURRENT" {
+			accountType = "CHECKING"
+		}
+	}
+
+	_, err := fmt.Fprintf(ow.out, "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\nSECURITY:NONE\nENCODING:USASCII\nCHARSET:1252\nCOMPRESSION:NONE\nOLDFILEUID:NONE\nNEWFILEUID:NONE\n\n"+
+		"<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n<DTSERVER>%s\n<LANGUAGE>ENG\n</SONRS>\n</SIGNONMSGSRSV1>\n"+
+		"<BANKMSGSRSV1>\n<STMTTRNRS>\n<TRNUID>0\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n<STMTRS>\n<CURDEF>%s\n"+
+		"<BANKACCTFROM>\n<BANKID>%s\n<ACCTID>%s\n<ACCTTYPE>%s\n</BANKACCTFROM>\n"+
+		"<BANKTRANLIST>\n<DTSTART>%s\n<DTEND>%s\n",
+		ow.date(time.Now()), currency, ofxText(ow.bankID, 9), ofxText(accountID, 22), accountType,
+		ow.date(ow.export.From), ow.date(ow.export.To))
+	return err
+}
+
+func (ow *ofxWriter) write(txn *model.Transaction) error {
+	amount := signedAmount(txn)
+	trnType := "DEBIT"
+	if amount > 0 {
+		trnType = "CREDIT"
+	}
+	var entry strings.Builder
+	fmt.Fprintf(&entry, "<STMTTRN>\n<TRNTYPE>%s\n<DTPOSTED>%s\n<TRNAMT>%s\n<FITID>%s\n",
+		trnType, ow.date(txn.CreatedAt), strconv.FormatFloat(amount, 'f', 2, 64), ofxText(txn.TransactionID, 255))
+	if txn.ReferenceNumber != "" {
+		fmt.Fprintf(&entry, "<REFNUM>%s\n", ofxText(txn.ReferenceNumber, 32))
+	}
+	fmt.Fprintf(&entry, "<NAME>%s\n<MEMO>%s\n</STMTTRN>\n",
+		ofxText(exportDescription(txn), 32), ofxText(fmt.Sprintf("%s %s", txn.Type, txn.Status), 255))
+	_, err := ow.out.WriteString(entry.String())
+	return err
+}
+
+func (ow *ofxWriter) end() error {
+	if _, err := ow.out.WriteString("</BANKTRANLIST>\n"); err != nil {
+		return err
+	}
+	if ow.account != nil {
+		if _, err := fmt.Fprintf(ow.out, "<LEDGERBAL>\n<BALAMT>%s\n<DTASOF>%s\n</LEDGERBAL>\n",
+			strconv.FormatFloat(ow.account.Balance, 'f', 2, 64), ow.date(time.Now())); err != nil {
+			return err
+		}
+	}
+	_, err := ow.out.WriteString("</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	return err
+}
+
+// date formats a time as OFX does, with its offset from UTC, e.g.
+// 20260315093000[+5.5:IST]
+func (ow *ofxWriter) date(t time.Time) string {
+	t = t.In(ow.location)
+	name, offset := t.Zone()
+	return fmt.Sprintf("%s[%s:%s]", t.Format("20060102150405"), strconv.FormatFloat(float64(offset)/3600, 'f', -1, 64), name)
+}
+
+// ofxText escapes a value for OFX's SGML and cuts it to the field's length. OFX 1.0.2
+// is US-ASCII, so other characters are dropped.
+func ofxText(text string, max int) string {
+	var b strings.Builder
+	for _, r := range strings.Join(strings.Fields(text), " ") {
+		if r > 126 {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	text = b.String()
+	if len(text) > max {
+		text = text[:max]
+	}
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
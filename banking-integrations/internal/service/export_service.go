@@ -0,0 +1,310 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// Export errors
+var (
+	ErrExportsDisabled       = errors.New("transaction exports are disabled")
+	ErrExportAccountNotFound = errors.New("account not found")
+	ErrExportLinkInvalid     = errors.New("export link is not valid")
+	ErrExportLinkExpired     = errors.New("export link has expired")
+)
+
+// exportLinkPurpose keys export links apart from other sealed links
+const exportLinkPurpose = "export-link"
+
+// exportDateLayout is how export requests and links give their dates
+const exportDateLayout = "2006-01-02"
+
+// exportClaims is what an export download link carries, sealed so the link neither
+// reveals the user nor can be altered to download another user's or range's history
+type exportClaims struct {
+	UserID    string `json:"u"`
+	AccountID string `json:"a,omitempty"`
+	Format    string `json:"f"`
+	From      string `json:"s"`
+	To        string `json:"t"`
+	ExpiresAt int64  `json:"e"` // Unix seconds
+}
+
+// ExportService exports users' transactions as OFX, QIF or CSV for personal finance
+// and accounting tools. An export is streamed: the history is read a window of days at
+// a time and each window written out before the next is read, so a long history is
+// never held in memory. Download links work like receipt links: nothing is stored,
+// the export is sealed into the link with EXPORT_SIGNING_KEY.
+type ExportService struct {
+	cfg        *config.ExportsConfig
+	bankIFSC   string
+	dwh        *DWHService
+	seedStore  *SeedStore
+	location   *time.Location
+	signingKey *secrets.Value
+}
+
+// NewExportService creates an export service. Dates are read in the calendar's time
+// zone.
+func NewExportService(cfg *config.ExportsConfig, bankIFSC string, dwh *DWHService, seedStore *SeedStore, calendar *BankingCalendar) *ExportService {
+	return &ExportService{
+		cfg:        cfg,
+		bankIFSC:   bankIFSC,
+		dwh:        dwh,
+		seedStore:  seedStore,
+		location:   calendar.Location(),
+		signingKey: config.Profile().RotatingSecret("EXPORT_SIGNING_KEY", cfg.SigningKey),
+	}
+}
+
+// Prepare checks an export of a user's transactions between two dates, inclusive
+func (es *ExportService) Prepare(userID, accountID, format, from, to string) (*model.Export, error) {
+	if !es.cfg.Enabled {
+		return nil, ErrExportsDisabled
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	format = strings.ToUpper(strings.TrimSpace(format))
+	switch format {
+	case model.ExportFormatOFX, model.ExportFormatQIF, model.ExportFormatCSV:
+	default:
+		return nil, fmt.Errorf("format must be OFX, QIF or CSV")
+	}
+
+	start, err := time.ParseInLocation(exportDateLayout, from, es.location)
+	if err != nil {
+		return nil, fmt.Errorf("from must be a date as YYYY-MM-DD")
+	}
+	last, err := time.ParseInLocation(exportDateLayout, to, es.location)
+	if err != nil {
+		return nil, fmt.Errorf("to must be a date as YYYY-MM-DD")
+	}
+	if last.Before(start) {
+		return nil, fmt.Errorf("to must not be before from")
+	}
+	end := last.AddDate(0, 0, 1)
+	if days := int(end.Sub(start).Hours()/24 + 0.5); days > es.cfg.MaxRangeDays {
+		return nil, fmt.Errorf("an export may cover at most %d days", es.cfg.MaxRangeDays)
+	}
+
+	if accountID != "" {
+		if acct, ok := es.seedStore.Account(accountID); ok && acct.UserID != userID {
+			return nil, ErrExportAccountNotFound
+		}
+	}
+
+	return &model.Export{UserID: userID, AccountID: accountID, Format: format, From: start, To: end}, nil
+}
+
+// CreateLink returns a link that downloads an export until it expires
+func (es *ExportService) CreateLink(ctx context.Context, req *model.ExportLinkRequest) (*model.ExportLink, error) {
+	export, err := es.Prepare(req.UserID, req.AccountID, req.Format, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	ttl := es.cfg.TTLMinutes
+	if req.TTLMinutes < 0 || req.TTLMinutes > es.cfg.TTLMinutes {
+		return nil, fmt.Errorf("ttl_minutes must be between 1 and %d", es.cfg.TTLMinutes)
+	}
+	if req.TTLMinutes > 0 {
+		ttl = req.TTLMinutes
+	}
+
+	expiresAt := time.Now().Add(time.Duration(ttl) * time.Minute).Truncate(time.Second)
+	claims := &exportClaims{
+		UserID:    export.UserID,
+		AccountID: export.AccountID,
+		Format:    export.Format,
+		From:      export.From.Format(exportDateLayout),
+		To:        export.To.AddDate(0, 0, -1).Format(exportDateLayout),
+		ExpiresAt: expiresAt.Unix(),
+	}
+	token, err := sealLink(exportLinkPurpose, es.signingKey.Get(), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("user_id", export.UserID).
+		Str("format", export.Format).
+		Str("from", claims.From).
+		Str("to", claims.To).
+		Time("expires_at", expiresAt).
+		Msg("Export link created")
+
+	return &model.ExportLink{
+		URL:       es.cfg.BaseURL + "/exports/" + token,
+		Format:    export.Format,
+		From:      claims.From,
+		To:        claims.To,
+		FileName:  ExportFileName(export),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Open returns the export a link downloads, if the link is genuine and unexpired
+func (es *ExportService) Open(token string) (*model.Export, error) {
+	if !es.cfg.Enabled {
+		return nil, ErrExportLinkInvalid
+	}
+	var claims exportClaims
+	if err := openLink(exportLinkPurpose, es.signingKey.Get(), token, &claims); err != nil {
+		return nil, ErrExportLinkInvalid
+	}
+	if time.Now().After(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrExportLinkExpired
+	}
+	return es.Prepare(claims.UserID, claims.AccountID, claims.Format, claims.From, claims.To)
+}
+
+// Stream writes an export to w, oldest transaction first, reading the history a
+// window of EXPORT_WINDOW_DAYS at a time. After each window flush is called, if set,
+// to send what has been written on its way. Returns how many transactions were
+// written; on an error the export written so far is incomplete.
+func (es *ExportService) Stream(ctx context.Context, w io.Writer, export *model.Export, flush func() error) (int, error) {
+	out := bufio.NewWriter(w)
+	writer := es.newWriter(out, export)
+	if err := writer.begin(); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for start := export.From; start.Before(export.To); {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		end := start.AddDate(0, 0, es.cfg.WindowDays)
+		if end.After(export.To) {
+			end = export.To
+		}
+		for _, txn := range es.dwh.TransactionsBetween(ctx, export.UserID, export.AccountID, start, end) {
+			if !exported(txn.Status) {
+				continue
+			}
+			if err := writer.write(&txn); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if err := out.Flush(); err != nil {
+			return written, err
+		}
+		if flush != nil {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+		start = end
+	}
+
+	if err := writer.end(); err != nil {
+		return written, err
+	}
+	if err := out.Flush(); err != nil {
+		return written, err
+	}
+
+	log.Info().
+		Str("user_id", export.UserID).
+		Str("format", export.Format).
+		Int("transactions", written).
+		Msg("Transactions exported")
+	return written, nil
+}
+
+// ExportFileName is the name an export downloads as, e.g.
+// transactions_2026-01-01_2026-03-31.ofx
+func ExportFileName(export *model.Export) string {
+	name := "transactions"
+	if export.AccountID != "" {
+		name += "_" + export.AccountID
+	}
+	return fmt.Sprintf("%s_%s_%s.%s", name, export.From.Format(exportDateLayout),
+		export.To.AddDate(0, 0, -1).Format(exportDateLayout), strings.ToLower(export.Format))
+}
+
+// ExportContentType is the media type of an export format
+func ExportContentType(format string) string {
+	switch format {
+	case model.ExportFormatOFX:
+		return "application/x-ofx"
+	case model.ExportFormatQIF:
+		return "application/qif"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// newWriter returns the writer of an export's format
+func (es *ExportService) newWriter(out *bufio.Writer, export *model.Export) exportWriter {
+	switch export.Format {
+	case model.ExportFormatOFX:
+		// BANKID takes nine characters; the IFSC's first four are the bank's code
+		bankID := es.bankIFSC
+		if len(bankID) > 4 {
+			bankID = bankID[:4]
+		}
+		ofx := &ofxWriter{out: out, export: export, location: es.location, bankID: bankID}
+		if export.AccountID != "" {
+			ofx.account, _ = es.seedStore.Account(export.AccountID)
+		}
+		return ofx
+	case model.ExportFormatQIF:
+		return &qifWriter{out: out, location: es.location}
+	}
+	return newCSVWriter(out, es.location)
+}
+
+// exported reports whether a transaction in this status moved money, and so belongs
+// in an export; failed and rejected transfers never touched the balance
+func exported(status model.TransactionStatus) bool {
+	return status != model.TransactionStatusFailed && status != model.TransactionStatusRejected
+}
+
+// signedAmount is a transaction's amount as it moved the user's balance: negative for
+// money out. Amounts are recorded unsigned, so the direction comes from the type, or
+// for an adjustment from the side of it the account is on.
+func signedAmount(txn *model.Transaction) float64 {
+	switch {
+	case txn.Type == model.TransactionTypeCREDIT:
+		return txn.Amount
+	case txn.Type == model.TransactionTypeAdjustment && txn.FromAccount == "":
+		return txn.Amount
+	}
+	return -txn.Amount
+}
+
+// exportDescription describes a transaction for the payee or description column of
+// an export: its remarks, or its type and the other party's masked account
+func exportDescription(txn *model.Transaction) string {
+	description := strings.Join(strings.Fields(txn.Remarks), " ")
+	if description != "" {
+		return description
+	}
+	if signedAmount(txn) > 0 {
+		if txn.FromAccount != "" {
+			return fmt.Sprintf("%s from %s", txn.Type, maskTail(txn.FromAccount))
+		}
+	} else if txn.ToAccount != "" {
+		return fmt.Sprintf("%s to %s", txn.Type, maskTail(txn.ToAccount))
+	}
+	return string(txn.Type)
+}
+
+// exportCurrency is a transaction's currency, INR when it has none recorded
+func exportCurrency(txn *model.Transaction) string {
+	if txn.Currency == "" {
+		return "INR"
+	}
+	return txn.Currency
+}
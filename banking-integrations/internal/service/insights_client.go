@@ -0,0 +1,78 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/secrets"
+)
+
+// InsightsClient asks the Insights Agent (Layer 3) for a user's savings and spending
+// suggestions, the same ones a user gets by asking in a chat
+type InsightsClient struct {
+	agentURL   string
+	apiKey     *secrets.Value
+	httpClient *http.Client
+}
+
+// NewInsightsClient creates a new insights client
+func NewInsightsClient(cfg *config.InsightsConfig) *InsightsClient {
+	return &InsightsClient{
+		agentURL: cfg.AgentURL,
+		apiKey:   config.Profile().RotatingSecret("INSIGHTS_AGENT_API_KEY", cfg.APIKey),
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Timeout) * time.Second,
+		},
+	}
+}
+
+// Insights returns a user's suggestions, most valuable first
+func (ic *InsightsClient) Insights(ctx context.Context, runID, userID string) ([]model.Insight, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"task":       "GET_INSIGHTS",
+		"request_id": fmt.Sprintf("%s_%s", runID, userID),
+		"session_id": runID,
+		"timestamp":  time.Now(),
+		"input_context": map[string]interface{}{
+			"user_id": userID,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ic.agentURL+"/api/v1/process", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", ic.apiKey.Get())
+
+	resp, err := ic.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("insights agent unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("insights agent returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var out struct {
+		Result struct {
+			Insights []model.Insight `json:"insights"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode insights response: %w", err)
+	}
+	return out.Result.Insights, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrDigestRunInProgress is returned when a digest is started while one is running
+var ErrDigestRunInProgress = errors.New("an insights digest is already running")
+
+// digestInsights is how many suggestions a digest carries
+const digestInsights = 3
+
+// InsightsDigest sends every user in the DWH a digest of their top savings and
+// spending suggestions through the notification service. Users with nothing worth
+// suggesting are not notified.
+type InsightsDigest struct {
+	dwhService    *DWHService
+	insights      *InsightsClient
+	notifications *NotificationService
+	keepRuns      int
+
+	mu         sync.Mutex
+	runs       []*model.DigestRun // Oldest first
+	runningRun string
+}
+
+// NewInsightsDigest creates a new insights digest job
+func NewInsightsDigest(dwhService *DWHService, insights *InsightsClient, notifications *NotificationService, keepRuns int) *InsightsDigest {
+	if keepRuns < 1 {
+		keepRuns = 10
+	}
+	return &InsightsDigest{
+		dwhService:    dwhService,
+		insights:      insights,
+		notifications: notifications,
+		keepRuns:      keepRuns,
+	}
+}
+
+// Start begins a digest in the background and returns the new run
+func (dg *InsightsDigest) Start(trigger string) (*model.DigestRun, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	if dg.runningRun != "" {
+		return nil, ErrDigestRunInProgress
+	}
+
+	run := &model.DigestRun{
+		RunID:     ids.Ref("DIG_"),
+		Trigger:   trigger,
+		Status:    model.DigestRunRunning,
+		StartedAt: time.Now(),
+	}
+	dg.runs = append(dg.runs, run)
+	if len(dg.runs) > dg.keepRuns {
+		dg.runs = dg.runs[len(dg.runs)-dg.keepRuns:]
+	}
+	dg.runningRun = run.RunID
+
+	go dg.execute(context.Background(), run)
+
+	runCopy := *run
+	return &runCopy, nil
+}
+
+// Schedule starts a digest every interval until ctx is done. A digest still running
+// when the next one is due is left to finish and that tick is skipped.
+func (dg *InsightsDigest) Schedule(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := dg.Start("schedule"); err != nil {
+					log.Warn().Err(err).Msg("Scheduled insights digest skipped")
+				}
+			}
+		}
+	}()
+}
+
+// Runs returns the kept runs, newest first
+func (dg *InsightsDigest) Runs() []model.DigestRun {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	runs := make([]model.DigestRun, 0, len(dg.runs))
+	for i := len(dg.runs) - 1; i >= 0; i-- {
+		runs = append(runs, *dg.runs[i])
+	}
+	return runs
+}
+
+// execute sends each user their digest, one user at a time, and completes the run
+func (dg *InsightsDigest) execute(ctx context.Context, run *model.DigestRun) {
+	userIDs := dg.dwhService.ListUsers(ctx)
+	dg.update(func() { run.Users = len(userIDs) })
+	log.Info().Str("run_id", run.RunID).Int("users", len(userIDs)).Msg("Insights digest started")
+
+	for _, userID := range userIDs {
+		err := dg.sendDigest(ctx, run.RunID, userID)
+		dg.update(func() {
+			switch {
+			case errors.Is(err, errNoInsights):
+				run.NoInsights++
+			case err != nil:
+				run.Failed++
+				if len(run.Errors) < maxRunErrors {
+					run.Errors = append(run.Errors, fmt.Sprintf("%s: %v", userID, err))
+				}
+			default:
+				run.Sent++
+			}
+		})
+		if err != nil && !errors.Is(err, errNoInsights) {
+			log.Warn().Err(err).Str("run_id", run.RunID).Str("user_id", userID).Msg("Insights digest failed for user")
+		}
+	}
+
+	var final model.DigestRun
+	dg.update(func() {
+		now := time.Now()
+		run.FinishedAt = &now
+		run.Status = model.DigestRunCompleted
+		if run.Users > 0 && run.Sent+run.NoInsights == 0 {
+			run.Status = model.DigestRunFailed
+		}
+		dg.runningRun = ""
+		final = *run
+	})
+	log.Info().Str("run_id", final.RunID).Str("status", final.Status).Int("sent", final.Sent).
+		Int("no_insights", final.NoInsights).Int("failed", final.Failed).Msg("Insights digest finished")
+}
+
+// errNoInsights marks a user the digest had nothing to send
+var errNoInsights = errors.New("no insights")
+
+// sendDigest notifies one user of their top suggestions
+func (dg *InsightsDigest) sendDigest(ctx context.Context, runID, userID string) error {
+	insights, err := dg.insights.Insights(ctx, runID, userID)
+	if err != nil {
+		return err
+	}
+	if len(insights) == 0 {
+		return errNoInsights
+	}
+	if len(insights) > digestInsights {
+		insights = insights[:digestInsights]
+	}
+
+	lines := make([]string, 0, len(insights))
+	for _, insight := range insights {
+		lines = append(lines, fmt.Sprintf("- %s: %s", insight.Title, insight.Message))
+	}
+	_, err = dg.notifications.Send(ctx, &model.NotificationRequest{
+		UserID:   userID,
+		Category: model.NotificationInsights,
+		Subject:  "Ways to make your money work harder: " + insights[0].Title,
+		Body:     strings.Join(lines, "\n"),
+	})
+	return err
+}
+
+func (dg *InsightsDigest) update(fn func()) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	fn()
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// ISO 8583 processing codes (data element 3)
+const (
+	iso8583BalanceInquiry = "310000"
+	iso8583FundsTransfer  = "400000"
+	iso8583DebitAdjust    = "020000"
+	iso8583CreditAdjust   = "220000"
+)
+
+// iso8583CurrencyINR is the ISO 4217 numeric code sent in data element 49
+const iso8583CurrencyINR = "356"
+
+// ISO8583Connector is a stub for switches that speak ISO 8583. It builds the 0200
+// financial request for balance inquiries and transfers and logs its data elements,
+// but has no transport yet, so every call fails with ErrConnectorUnavailable.
+// Account records are read with a balance inquiry and back-office adjustments sent
+// as debit or credit adjustments. Statements and beneficiaries have no ISO 8583
+// message and are unsupported.
+type ISO8583Connector struct {
+	channel    model.Channel
+	address    string // host:port of the switch
+	terminalID string
+	stan       atomic.Uint32 // System trace audit number, data element 11
+}
+
+// iso8583Message is an ISO 8583 message: its type indicator and data elements
+type iso8583Message struct {
+	MTI    string
+	Fields map[int]string
+}
+
+// NewISO8583Connector creates the ISO 8583 stub
+func NewISO8583Connector(channel model.Channel, cfg config.ConnectorConfig) (*ISO8583Connector, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("iso8583 connector needs CONNECTOR_%s_URL (host:port of the switch)", channel)
+	}
+	log.Warn().Str("channel", string(channel)).Msg("ISO 8583 connector is a stub: requests are built but not sent")
+	return &ISO8583Connector{
+		channel:    channel,
+		address:    cfg.URL,
+		terminalID: fmt.Sprintf("AIBNK%s", strings.ToUpper(string(channel))),
+	}, nil
+}
+
+// GetBalance builds a balance inquiry
+func (ic *ISO8583Connector) GetBalance(ctx context.Context, req *model.BalanceRequest) (*model.BalanceResponse, error) {
+	msg := ic.request(iso8583BalanceInquiry, 0)
+	msg.Fields[102] = req.AccountID
+	return nil, ic.send(msg)
+}
+
+// TransferFunds builds a funds transfer
+func (ic *ISO8583Connector) TransferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error) {
+	msg := ic.request(iso8583FundsTransfer, req.Amount)
+	msg.Fields[102] = req.FromAccount
+	msg.Fields[103] = req.ToAccount
+	return nil, ic.send(msg)
+}
+
+// GetStatement is not an ISO 8583 operation
+func (ic *ISO8583Connector) GetStatement(ctx context.Context, req *model.StatementRequest) (*model.StatementResponse, error) {
+	return nil, fmt.Errorf("%w: iso8583 has no statement message", ErrOperationUnsupported)
+}
+
+// AddBeneficiary is not an ISO 8583 operation
+func (ic *ISO8583Connector) AddBeneficiary(ctx context.Context, userID, accountNumber, ifsc, name string) (*model.Beneficiary, error) {
+	return nil, fmt.Errorf("%w: iso8583 has no beneficiary message", ErrOperationUnsupported)
+}
+
+// GetBeneficiary is not an ISO 8583 operation
+func (ic *ISO8583Connector) GetBeneficiary(ctx context.Context, userID, accountNumber string) (*model.Beneficiary, error) {
+	return nil, fmt.Errorf("%w: iso8583 has no beneficiary message", ErrOperationUnsupported)
+}
+
+// GetAccount builds a balance inquiry for the account's record
+func (ic *ISO8583Connector) GetAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	msg := ic.request(iso8583BalanceInquiry, 0)
+	msg.Fields[102] = accountID
+	return nil, ic.send(msg)
+}
+
+// UpdateBalance builds a debit or credit adjustment
+func (ic *ISO8583Connector) UpdateBalance(ctx context.Context, req *model.BalanceUpdateRequest) (*model.BalanceUpdateResponse, error) {
+	code := iso8583CreditAdjust
+	if req.Amount < 0 {
+		code = iso8583DebitAdjust
+	}
+	msg := ic.request(code, math.Abs(req.Amount))
+	msg.Fields[102] = req.AccountID
+	return nil, ic.send(msg)
+}
+
+// request builds a 0200 financial request with the common data elements
+func (ic *ISO8583Connector) request(processingCode string, amount float64) *iso8583Message {
+	now := time.Now().UTC()
+	stan := ic.stan.Add(1) % 1000000
+	return &iso8583Message{
+		MTI: "0200",
+		Fields: map[int]string{
+			3:  processingCode,
+			4:  fmt.Sprintf("%012d", int64(math.Round(amount*100))), // Paise
+			7:  now.Format("0102150405"),                            // MMDDhhmmss
+			11: fmt.Sprintf("%06d", stan),
+			37: fmt.Sprintf("%s%06d", now.Format("060102"), stan), // Retrieval reference number
+			41: ic.terminalID,
+			49: iso8583CurrencyINR,
+		},
+	}
+}
+
+// send would write the message to the switch; the stub only logs it
+func (ic *ISO8583Connector) send(msg *iso8583Message) error {
+	log.Info().Str("channel", string(ic.channel)).Str("switch", ic.address).Str("mti", msg.MTI).
+		Str("fields", msg.String()).Msg("ISO 8583: request built (stub, not sent)")
+	return fmt.Errorf("%w: iso8583 transport to %s is not implemented", ErrConnectorUnavailable, ic.address)
+}
+
+// String renders the data elements in order, e.g. "3=310000 4=000000000000"
+func (m *iso8583Message) String() string {
+	numbers := make([]int, 0, len(m.Fields))
+	for n := range m.Fields {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	parts := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		parts = append(parts, fmt.Sprintf("%d=%s", n, m.Fields[n]))
+	}
+	return strings.Join(parts, " ")
+}
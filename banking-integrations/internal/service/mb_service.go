@@ -6,18 +6,20 @@ import (
 	"time"
 
 	"github.com/aibanking/banking-integrations/internal/model"
-	"github.com/google/uuid"
+	"github.com/aibanking/shared/ids"
 	"github.com/rs/zerolog/log"
 )
 
 // MBService handles Mobile Banking operations
 type MBService struct {
-	// In production, this would have database connection
+	accounts *mockAccounts // In production, this would be a database connection
 }
 
 // NewMBService creates a new MB service
 func NewMBService() *MBService {
-	return &MBService{}
+	return &MBService{
+		accounts: newMockAccounts(),
+	}
 }
 
 // GetBalance retrieves account balance for mobile banking
@@ -29,12 +31,13 @@ func (mb *MBService) GetBalance(ctx context.Context, req *model.BalanceRequest)
 		Msg("MB: Getting balance")
 
 	// Mock implementation - in production would query database
-	balance := 150000.0
+	acct := mb.accounts.get(req.AccountID, req.UserID)
+	balance := acct.Balance
 	availableBalance := balance - 5000.0 // Reserve for pending transactions
 
 	return &model.BalanceResponse{
 		AccountID:        req.AccountID,
-		AccountNumber:    "XXXX1234",
+		AccountNumber:    acct.AccountNumber,
 		Balance:          balance,
 		Currency:         "INR",
 		AvailableBalance: availableBalance,
@@ -52,9 +55,11 @@ func (mb *MBService) TransferFunds(ctx context.Context, req *model.TransferReque
 		Str("type", string(req.Type)).
 		Msg("MB: Processing fund transfer")
 
+	mb.accounts.get(req.FromAccount, req.UserID)
+
 	// Generate transaction ID
-	txnID := fmt.Sprintf("MB_%s", uuid.New().String()[:8])
-	refNumber := fmt.Sprintf("REF%s", uuid.New().String()[:12])
+	txnID := ids.Ref("MB_")
+	refNumber := ids.Ref("REF")
 
 	// Mock processing - in production would call core banking system
 	status := "COMPLETED"
@@ -81,7 +86,9 @@ func (mb *MBService) GetStatement(ctx context.Context, req *model.StatementReque
 		Time("end_date", req.EndDate).
 		Msg("MB: Getting statement")
 
-	// Mock transactions - in production would query database
+	// Mock transactions - in production would query database. Dated from midnight
+	// so repeated reads on one day return the same statement.
+	today := time.Now().Truncate(24 * time.Hour)
 	transactions := []model.Transaction{
 		{
 			TransactionID: "TXN_001",
@@ -92,7 +99,7 @@ func (mb *MBService) GetStatement(ctx context.Context, req *model.StatementReque
 			Currency:      "INR",
 			Status:        model.TransactionStatusCompleted,
 			Channel:       model.ChannelMB,
-			CreatedAt:     time.Now().AddDate(0, 0, -5),
+			CreatedAt:     today.AddDate(0, 0, -5),
 		},
 		{
 			TransactionID: "TXN_002",
@@ -103,7 +110,7 @@ func (mb *MBService) GetStatement(ctx context.Context, req *model.StatementReque
 			Currency:      "INR",
 			Status:        model.TransactionStatusCompleted,
 			Channel:       model.ChannelMB,
-			CreatedAt:     time.Now().AddDate(0, 0, -10),
+			CreatedAt:     today.AddDate(0, 0, -10),
 		},
 	}
 
@@ -125,10 +132,8 @@ func (mb *MBService) AddBeneficiary(ctx context.Context, userID, accountNumber,
 		Str("ifsc", ifsc).
 		Msg("MB: Adding beneficiary")
 
-	beneficiaryID := fmt.Sprintf("BEN_%s", uuid.New().String()[:8])
-
-	return &model.Beneficiary{
-		BeneficiaryID: beneficiaryID,
+	beneficiary := model.Beneficiary{
+		BeneficiaryID: ids.Ref(ids.Beneficiary),
 		UserID:        userID,
 		AccountNumber: accountNumber,
 		IFSC:          ifsc,
@@ -136,6 +141,41 @@ func (mb *MBService) AddBeneficiary(ctx context.Context, userID, accountNumber,
 		AccountType:   "SAVINGS",
 		Status:        "ACTIVE",
 		AddedAt:       time.Now(),
-	}, nil
+	}
+	mb.accounts.addBeneficiary(beneficiary)
+	return &beneficiary, nil
 }
 
+// GetBeneficiary returns a beneficiary added through mobile banking
+func (mb *MBService) GetBeneficiary(ctx context.Context, userID, accountNumber string) (*model.Beneficiary, error) {
+	ben, ok := mb.accounts.beneficiary(userID, accountNumber)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s for user %s", ErrBeneficiaryNotFound, accountNumber, userID)
+	}
+	return &ben, nil
+}
+
+// GetAccount returns a mock account's record
+func (mb *MBService) GetAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	acct := mb.accounts.get(accountID, "")
+	return &acct, nil
+}
+
+// UpdateBalance posts a ledger entry to a mock account
+func (mb *MBService) UpdateBalance(ctx context.Context, req *model.BalanceUpdateRequest) (*model.BalanceUpdateResponse, error) {
+	log.Info().
+		Str("account_id", req.AccountID).
+		Float64("amount", req.Amount).
+		Str("entry_id", req.EntryID).
+		Msg("MB: Posting balance update")
+
+	before, after, err := mb.accounts.post(req.AccountID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return &model.BalanceUpdateResponse{
+		AccountID:     req.AccountID,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// mockOpeningBalance is the balance a mock account is opened with
+const mockOpeningBalance = 150000.0
+
+// mockAccounts is the account book behind the MB/NB mocks - in production this is
+// the core banking system. An account is opened the first time it is seen, and is
+// owned by the user who first used it. Beneficiaries are kept as they are added.
+type mockAccounts struct {
+	mu            sync.Mutex
+	accounts      map[string]*model.Account      // Keyed by account ID
+	beneficiaries map[string][]model.Beneficiary // Keyed by user ID
+}
+
+func newMockAccounts() *mockAccounts {
+	return &mockAccounts{
+		accounts:      make(map[string]*model.Account),
+		beneficiaries: make(map[string][]model.Beneficiary),
+	}
+}
+
+// addBeneficiary registers a beneficiary for its user
+func (m *mockAccounts) addBeneficiary(ben model.Beneficiary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.beneficiaries[ben.UserID] = append(m.beneficiaries[ben.UserID], ben)
+}
+
+// beneficiary returns the beneficiary a user registered for an account number, the
+// earliest if it was added more than once
+func (m *mockAccounts) beneficiary(userID, accountNumber string) (model.Beneficiary, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ben := range m.beneficiaries[userID] {
+		if ben.AccountNumber == accountNumber {
+			return ben, true
+		}
+	}
+	return model.Beneficiary{}, false
+}
+
+// get returns an account, opening it if it is new and recording userID as its
+// owner if it has none yet
+func (m *mockAccounts) get(accountID, userID string) model.Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.open(accountID, userID)
+}
+
+// post applies a signed amount to an account's balance, returning the balances
+// before and after. The balance may not go negative.
+func (m *mockAccounts) post(accountID string, amount float64) (float64, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acct := m.open(accountID, "")
+	before := acct.Balance
+	after := before + amount
+	if after < 0 {
+		return before, before, fmt.Errorf("balance of %s would go negative: %.2f %+.2f", accountID, before, amount)
+	}
+	acct.Balance = after
+	acct.LastUpdated = time.Now()
+	return before, after, nil
+}
+
+// open returns an account, opening it if it is new. Callers hold m.mu.
+func (m *mockAccounts) open(accountID, userID string) *model.Account {
+	acct, ok := m.accounts[accountID]
+	if !ok {
+		now := time.Now()
+		acct = &model.Account{
+			AccountID:     accountID,
+			AccountNumber: "XXXX1234",
+			AccountType:   "SAVINGS",
+			Balance:       mockOpeningBalance,
+			Currency:      "INR",
+			Status:        "ACTIVE",
+			KYCStatus:     "VERIFIED",
+			CreatedAt:     now,
+			LastUpdated:   now,
+		}
+		m.accounts[accountID] = acct
+	}
+	if acct.UserID == "" {
+		acct.UserID = userID
+	}
+	return acct
+}
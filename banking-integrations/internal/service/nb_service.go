@@ -6,18 +6,20 @@ import (
 	"time"
 
 	"github.com/aibanking/banking-integrations/internal/model"
-	"github.com/google/uuid"
+	"github.com/aibanking/shared/ids"
 	"github.com/rs/zerolog/log"
 )
 
 // NBService handles Net Banking operations
 type NBService struct {
-	// In production, this would have database connection
+	accounts *mockAccounts // In production, this would be a database connection
 }
 
 // NewNBService creates a new NB service
 func NewNBService() *NBService {
-	return &NBService{}
+	return &NBService{
+		accounts: newMockAccounts(),
+	}
 }
 
 // GetBalance retrieves account balance for net banking
@@ -29,12 +31,13 @@ func (nb *NBService) GetBalance(ctx context.Context, req *model.BalanceRequest)
 		Msg("NB: Getting balance")
 
 	// Mock implementation - in production would query database
-	balance := 150000.0
+	acct := nb.accounts.get(req.AccountID, req.UserID)
+	balance := acct.Balance
 	availableBalance := balance - 5000.0
 
 	return &model.BalanceResponse{
 		AccountID:        req.AccountID,
-		AccountNumber:    "XXXX1234",
+		AccountNumber:    acct.AccountNumber,
 		Balance:          balance,
 		Currency:         "INR",
 		AvailableBalance: availableBalance,
@@ -52,9 +55,11 @@ func (nb *NBService) TransferFunds(ctx context.Context, req *model.TransferReque
 		Str("type", string(req.Type)).
 		Msg("NB: Processing fund transfer")
 
+	nb.accounts.get(req.FromAccount, req.UserID)
+
 	// Generate transaction ID
-	txnID := fmt.Sprintf("NB_%s", uuid.New().String()[:8])
-	refNumber := fmt.Sprintf("REF%s", uuid.New().String()[:12])
+	txnID := ids.Ref("NB_")
+	refNumber := ids.Ref("REF")
 
 	// Mock processing
 	status := "COMPLETED"
@@ -81,7 +86,9 @@ func (nb *NBService) GetStatement(ctx context.Context, req *model.StatementReque
 		Time("end_date", req.EndDate).
 		Msg("NB: Getting statement")
 
-	// Mock transactions
+	// Mock transactions, dated from midnight so repeated reads on one day return the
+	// same statement
+	today := time.Now().Truncate(24 * time.Hour)
 	transactions := []model.Transaction{
 		{
 			TransactionID: "TXN_003",
@@ -93,7 +100,7 @@ func (nb *NBService) GetStatement(ctx context.Context, req *model.StatementReque
 			ToAccount:     "YYYY5678",
 			Status:        model.TransactionStatusCompleted,
 			Channel:       model.ChannelNB,
-			CreatedAt:     time.Now().AddDate(0, 0, -3),
+			CreatedAt:     today.AddDate(0, 0, -3),
 		},
 		{
 			TransactionID: "TXN_004",
@@ -105,7 +112,7 @@ func (nb *NBService) GetStatement(ctx context.Context, req *model.StatementReque
 			ToAccount:     "ZZZZ9012",
 			Status:        model.TransactionStatusCompleted,
 			Channel:       model.ChannelNB,
-			CreatedAt:     time.Now().AddDate(0, 0, -7),
+			CreatedAt:     today.AddDate(0, 0, -7),
 		},
 	}
 
@@ -127,10 +134,8 @@ func (nb *NBService) AddBeneficiary(ctx context.Context, userID, accountNumber,
 		Str("ifsc", ifsc).
 		Msg("NB: Adding beneficiary")
 
-	beneficiaryID := fmt.Sprintf("BEN_%s", uuid.New().String()[:8])
-
-	return &model.Beneficiary{
-		BeneficiaryID: beneficiaryID,
+	beneficiary := model.Beneficiary{
+		BeneficiaryID: ids.Ref(ids.Beneficiary),
 		UserID:        userID,
 		AccountNumber: accountNumber,
 		IFSC:          ifsc,
@@ -138,6 +143,41 @@ func (nb *NBService) AddBeneficiary(ctx context.Context, userID, accountNumber,
 		AccountType:   "SAVINGS",
 		Status:        "ACTIVE",
 		AddedAt:       time.Now(),
-	}, nil
+	}
+	nb.accounts.addBeneficiary(beneficiary)
+	return &beneficiary, nil
 }
 
+// GetBeneficiary returns a beneficiary added through net banking
+func (nb *NBService) GetBeneficiary(ctx context.Context, userID, accountNumber string) (*model.Beneficiary, error) {
+	ben, ok := nb.accounts.beneficiary(userID, accountNumber)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s for user %s", ErrBeneficiaryNotFound, accountNumber, userID)
+	}
+	return &ben, nil
+}
+
+// GetAccount returns a mock account's record
+func (nb *NBService) GetAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	acct := nb.accounts.get(accountID, "")
+	return &acct, nil
+}
+
+// UpdateBalance posts a ledger entry to a mock account
+func (nb *NBService) UpdateBalance(ctx context.Context, req *model.BalanceUpdateRequest) (*model.BalanceUpdateResponse, error) {
+	log.Info().
+		Str("account_id", req.AccountID).
+		Float64("amount", req.Amount).
+		Str("entry_id", req.EntryID).
+		Msg("NB: Posting balance update")
+
+	before, after, err := nb.accounts.post(req.AccountID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return &model.BalanceUpdateResponse{
+		AccountID:     req.AccountID,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// maxNotifications caps the notifications kept for lookups; the oldest are dropped first
+const maxNotifications = 10000
+
+// NotificationService sends messages to users on the channel they chose in their
+// preferences, push when they chose none. Delivery is simulated: each notification is
+// logged and kept for lookups until an SMS, email and push gateway is wired in.
+type NotificationService struct {
+	preferences   *PreferenceStore
+	notifications []model.Notification // Oldest first
+	mu            sync.Mutex
+}
+
+// NewNotificationService creates a notification service
+func NewNotificationService(preferences *PreferenceStore) *NotificationService {
+	return &NotificationService{preferences: preferences}
+}
+
+// Send delivers a notification to a user
+func (ns *NotificationService) Send(ctx context.Context, req *model.NotificationRequest) (*model.Notification, error) {
+	if req.UserID == "" || strings.TrimSpace(req.Subject) == "" || strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("user_id, subject and body are required")
+	}
+
+	channel := model.NotifyPush
+	if prefs, ok := ns.preferences.Get(ctx, req.UserID); ok && prefs.NotificationChannel != "" {
+		channel = prefs.NotificationChannel
+	}
+
+	n := model.Notification{
+		ID:       ids.Ref("NTF_"),
+		UserID:   req.UserID,
+		Channel:  channel,
+		Category: req.Category,
+		Subject:  req.Subject,
+		Body:     req.Body,
+		SentAt:   time.Now(),
+	}
+
+	ns.mu.Lock()
+	ns.notifications = append(ns.notifications, n)
+	if len(ns.notifications) > maxNotifications {
+		ns.notifications = ns.notifications[len(ns.notifications)-maxNotifications:]
+	}
+	ns.mu.Unlock()
+
+	log.Info().Str("notification_id", n.ID).Str("user_id", n.UserID).Str("channel", channel).
+		Str("category", n.Category).Msg("Notification sent")
+	return &n, nil
+}
+
+// List returns a user's notifications, optionally of one category, newest first
+func (ns *NotificationService) List(userID, category string) []model.Notification {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	notifications := []model.Notification{}
+	for i := len(ns.notifications) - 1; i >= 0; i-- {
+		n := ns.notifications[i]
+		if (userID == "" || n.UserID == userID) && (category == "" || n.Category == category) {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications
+}
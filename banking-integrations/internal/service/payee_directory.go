@@ -0,0 +1,248 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ifscPattern matches an IFSC: four letters for the bank, a zero, six for the branch
+	ifscPattern = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	// payeeNameUnsafe matches what a name lookup ignores
+	payeeNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// builtinPayees are the billers and merchants listed without a directory file
+var builtinPayees = []model.DirectoryPayee{
+	{PayeeID: "PAY_AIRTEL", Name: "Airtel", Aliases: []string{"Bharti Airtel", "Airtel Postpaid", "Airtel Prepaid"}, Category: model.PayeeCategoryBiller, Subcategory: "Mobile", Verified: true,
+		AccountNumber: "50200011112222", IFSC: "HDFC0000240", VPA: "airtel.bills@hdfcbank", Logo: model.PayeeLogo{Initials: "A", Color: "#E40000"}},
+	{PayeeID: "PAY_JIO", Name: "Jio", Aliases: []string{"Reliance Jio", "Jio Fiber"}, Category: model.PayeeCategoryBiller, Subcategory: "Mobile", Verified: true,
+		AccountNumber: "00601400021", IFSC: "ICIC0000006", VPA: "jio.bills@icici", Logo: model.PayeeLogo{Initials: "J", Color: "#0A2885"}},
+	{PayeeID: "PAY_VI", Name: "Vi", Aliases: []string{"Vodafone Idea", "Vodafone", "Idea"}, Category: model.PayeeCategoryBiller, Subcategory: "Mobile", Verified: true,
+		AccountNumber: "918020034455667", IFSC: "UTIB0000004", VPA: "vi.bills@axisbank", Logo: model.PayeeLogo{Initials: "Vi", Color: "#ED1C24"}},
+	{PayeeID: "PAY_BSNL", Name: "BSNL", Aliases: []string{"Bharat Sanchar Nigam"}, Category: model.PayeeCategoryBiller, Subcategory: "Landline and Broadband", Verified: true,
+		AccountNumber: "10084465321", IFSC: "SBIN0000691", Logo: model.PayeeLogo{Initials: "B", Color: "#1C75BC"}},
+	{PayeeID: "PAY_TATA_POWER", Name: "Tata Power", Aliases: []string{"Tata Power DDL", "Tata Power Mumbai"}, Category: model.PayeeCategoryBiller, Subcategory: "Electricity", Verified: true,
+		AccountNumber: "50200055667788", IFSC: "HDFC0000060", VPA: "tatapower@hdfcbank", Logo: model.PayeeLogo{Initials: "TP", Color: "#1B4F9C"}},
+	{PayeeID: "PAY_BESCOM", Name: "BESCOM", Aliases: []string{"Bangalore Electricity"}, Category: model.PayeeCategoryBiller, Subcategory: "Electricity", Verified: true,
+		AccountNumber: "64052011223", IFSC: "SBIN0040520", Logo: model.PayeeLogo{Initials: "B", Color: "#F7A600"}},
+	{PayeeID: "PAY_MGL", Name: "Mahanagar Gas", Aliases: []string{"MGL"}, Category: model.PayeeCategoryBiller, Subcategory: "Gas", Verified: true,
+		AccountNumber: "00600350044411", IFSC: "HDFC0000060", Logo: model.PayeeLogo{Initials: "MG", Color: "#0B7A3E"}},
+	{PayeeID: "PAY_LIC", Name: "LIC", Aliases: []string{"Life Insurance Corporation"}, Category: model.PayeeCategoryBiller, Subcategory: "Insurance", Verified: true,
+		AccountNumber: "30007722119", IFSC: "SBIN0000300", VPA: "licpremium@sbi", Logo: model.PayeeLogo{Initials: "LIC", Color: "#FFCC00"}},
+	{PayeeID: "PAY_SWIGGY", Name: "Swiggy", Category: model.PayeeCategoryMerchant, Subcategory: "Food Delivery", Verified: true,
+		VPA: "swiggy@icici", Logo: model.PayeeLogo{Initials: "S", Color: "#FC8019"}},
+	{PayeeID: "PAY_QUICKMART", Name: "QuickMart", Aliases: []string{"Quick Mart Stores"}, Category: model.PayeeCategoryMerchant, Subcategory: "Groceries",
+		AccountNumber: "7788990011", IFSC: "KKBK0000811", VPA: "quickmart@kotak", Logo: model.PayeeLogo{Initials: "QM", Color: "#6A1B9A"}},
+}
+
+// PayeeDirectory lists common billers and merchants with their collection accounts
+// and whether the bank has verified them. Customers find payees by name, and every
+// transfer is checked against it so a payment to an account that merely claims to
+// be a known biller is flagged before the customer confirms.
+type PayeeDirectory struct {
+	payees map[string]*model.DirectoryPayee // Keyed by payee ID
+}
+
+// NewPayeeDirectory creates the directory from the built-in payees and, when
+// configured, a JSON file of payees that add to them or replace them by ID
+func NewPayeeDirectory(cfg *config.PayeeDirectoryConfig) (*PayeeDirectory, error) {
+	pd := &PayeeDirectory{payees: make(map[string]*model.DirectoryPayee)}
+	for i := range builtinPayees {
+		payee := builtinPayees[i]
+		pd.payees[payee.PayeeID] = &payee
+	}
+
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payee directory: %w", err)
+		}
+		var payees []model.DirectoryPayee
+		if err := json.Unmarshal(data, &payees); err != nil {
+			return nil, fmt.Errorf("invalid payee directory file: %w", err)
+		}
+		for i := range payees {
+			payee := payees[i]
+			if err := normalizePayee(&payee); err != nil {
+				return nil, fmt.Errorf("payees[%d]: %w", i, err)
+			}
+			pd.payees[payee.PayeeID] = &payee
+		}
+		log.Info().Str("file", cfg.File).Int("payees", len(payees)).Msg("Payee directory loaded")
+	}
+
+	return pd, nil
+}
+
+// normalizePayee validates a payee from the directory file and fills in its initials
+func normalizePayee(payee *model.DirectoryPayee) error {
+	if payee.PayeeID == "" || payee.Name == "" {
+		return fmt.Errorf("payee_id and name are required")
+	}
+	if payee.Category != model.PayeeCategoryBiller && payee.Category != model.PayeeCategoryMerchant {
+		return fmt.Errorf("category must be BILLER or MERCHANT")
+	}
+	if payee.AccountNumber == "" && payee.VPA == "" {
+		return fmt.Errorf("account_number or vpa is required")
+	}
+	payee.IFSC = strings.ToUpper(payee.IFSC)
+	if payee.IFSC != "" && !ifscPattern.MatchString(payee.IFSC) {
+		return fmt.Errorf("ifsc %q is not an IFSC", payee.IFSC)
+	}
+	payee.VPA = strings.ToLower(payee.VPA)
+	if payee.VPA != "" && !vpaPattern.MatchString(payee.VPA) {
+		return fmt.Errorf("vpa %q is not a UPI ID", payee.VPA)
+	}
+	if payee.Logo.Initials == "" {
+		for _, word := range strings.Fields(payee.Name) {
+			if len(payee.Logo.Initials) < 2 {
+				payee.Logo.Initials += strings.ToUpper(string([]rune(word)[0]))
+			}
+		}
+	}
+	return nil
+}
+
+// Get returns a directory payee
+func (pd *PayeeDirectory) Get(payeeID string) (*model.DirectoryPayee, bool) {
+	payee, ok := pd.payees[payeeID]
+	if !ok {
+		return nil, false
+	}
+	payeeCopy := *payee
+	return &payeeCopy, true
+}
+
+// Search finds payees by name or alias, best match first and verified payees ahead
+// of unverified ones that match as well. category limits the search when set.
+func (pd *PayeeDirectory) Search(query string, category model.PayeeCategory, limit int) []model.PayeeMatch {
+	q := normalizePayeeName(query)
+	matches := make([]model.PayeeMatch, 0)
+	if q == "" {
+		return matches
+	}
+
+	for _, payee := range pd.payees {
+		if category != "" && payee.Category != category {
+			continue
+		}
+		best := model.PayeeMatch{DirectoryPayee: *payee}
+		for _, name := range append([]string{payee.Name}, payee.Aliases...) {
+			if score := nameScore(q, normalizePayeeName(name)); score > best.Score {
+				best.Score = score
+				best.MatchedOn = name
+			}
+		}
+		if best.Score > 0 {
+			matches = append(matches, best)
+		}
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		if matches[a].Verified != matches[b].Verified {
+			return matches[a].Verified
+		}
+		return matches[a].Name < matches[b].Name
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Verify checks a transfer's payee against the directory. An account or VPA that
+// belongs to a directory payee is verified or not as that payee is; an account that
+// does not, sent to a name the directory knows, is a mismatch the customer should
+// check before paying.
+func (pd *PayeeDirectory) Verify(req *model.PayeeVerificationRequest) *model.PayeeVerification {
+	account := strings.TrimSpace(req.ToAccount)
+	vpa := strings.ToLower(strings.TrimSpace(req.VPA))
+	if strings.Contains(account, "@") {
+		vpa, account = strings.ToLower(account), ""
+	}
+	ifsc := strings.ToUpper(strings.TrimSpace(req.IFSC))
+
+	for _, payee := range pd.payees {
+		sameAccount := account != "" && payee.AccountNumber == account && (ifsc == "" || payee.IFSC == "" || payee.IFSC == ifsc)
+		sameVPA := vpa != "" && payee.VPA == vpa
+		if !sameAccount && !sameVPA {
+			continue
+		}
+
+		logo := payee.Logo
+		verification := &model.PayeeVerification{
+			Status:   model.PayeeUnverified,
+			Verified: payee.Verified,
+			PayeeID:  payee.PayeeID,
+			Name:     payee.Name,
+			Category: payee.Category,
+			Logo:     &logo,
+			Message:  fmt.Sprintf("This account is listed for %s, which the bank has not verified.", payee.Name),
+		}
+		if payee.Verified {
+			verification.Status = model.PayeeVerified
+			verification.Message = fmt.Sprintf("%s is a verified %s.", payee.Name, strings.ToLower(string(payee.Category)))
+		}
+		return verification
+	}
+
+	if req.PayeeName != "" {
+		if matches := pd.Search(req.PayeeName, "", 1); len(matches) > 0 && matches[0].Score >= 0.9 {
+			named := matches[0]
+			return &model.PayeeVerification{
+				Status:   model.PayeeNameMismatch,
+				PayeeID:  named.PayeeID,
+				Name:     named.Name,
+				Category: named.Category,
+				Message:  fmt.Sprintf("This account does not belong to %s. Check the payee details before you pay.", named.Name),
+			}
+		}
+	}
+
+	return &model.PayeeVerification{
+		Status:  model.PayeeNotInDirectory,
+		Message: "The payee is not in the bank's directory of billers and merchants.",
+	}
+}
+
+// nameScore rates how well a normalized query matches a normalized payee name:
+// 1 for the same name, 0.9 when the query starts the name ("tata" for Tata Power),
+// 0.8 when the name is a word of the query ("airtel postpaid bill"), 0.6 when the
+// query is a word of the name, and 0 otherwise
+func nameScore(query, name string) float64 {
+	switch {
+	case name == "":
+		return 0
+	case query == name:
+		return 1
+	case len(query) >= 3 && strings.HasPrefix(name, query):
+		return 0.9
+	case containsWords(query, name):
+		return 0.8
+	case len(query) >= 3 && containsWords(name, query):
+		return 0.6
+	}
+	return 0
+}
+
+// containsWords reports whether the words of part appear together in text
+func containsWords(text, part string) bool {
+	return strings.Contains(" "+text+" ", " "+part+" ")
+}
+
+// normalizePayeeName lowercases a name and reduces it to words of letters and digits
+func normalizePayeeName(name string) string {
+	return strings.TrimSpace(payeeNameUnsafe.ReplaceAllString(strings.ToLower(name), " "))
+}
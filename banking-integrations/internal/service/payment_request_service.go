@@ -0,0 +1,257 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Payment request errors
+var (
+	ErrPaymentRequestNotFound   = errors.New("payment request not found")
+	ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
+)
+
+var (
+	// vpaPattern matches a UPI virtual payment address such as ravi.k@okbank
+	vpaPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{2,256}@[a-zA-Z]{2,64}$`)
+	// vpaUnsafe matches characters a user ID cannot bring into a VPA
+	vpaUnsafe = regexp.MustCompile(`[^a-z0-9._-]`)
+)
+
+// PaymentRequestService lets users request money over UPI. Each request carries a
+// UPI intent deep link and QR payload, and is fulfilled when a credit of its
+// amount arrives for the user, matched by its reference or else by account.
+type PaymentRequestService struct {
+	cfg       *config.PaymentRequestsConfig
+	seedStore *SeedStore
+	requests  map[string]*model.PaymentRequest
+	mu        sync.Mutex
+}
+
+// NewPaymentRequestService creates a payment request service
+func NewPaymentRequestService(cfg *config.PaymentRequestsConfig, seedStore *SeedStore) *PaymentRequestService {
+	return &PaymentRequestService{
+		cfg:       cfg,
+		seedStore: seedStore,
+		requests:  make(map[string]*model.PaymentRequest),
+	}
+}
+
+// Create raises a request for money and builds its UPI deep link
+func (ps *PaymentRequestService) Create(req *model.PaymentRequestCreate) (*model.PaymentRequest, error) {
+	if req.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	if req.Amount <= 0 || math.IsNaN(req.Amount) || math.IsInf(req.Amount, 0) {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	if ps.cfg.MaxAmount > 0 && req.Amount > ps.cfg.MaxAmount {
+		return nil, fmt.Errorf("amount exceeds the UPI request limit of %.2f", ps.cfg.MaxAmount)
+	}
+	if len(req.Note) > 50 {
+		return nil, fmt.Errorf("note must be at most 50 characters")
+	}
+	if req.PayerVPA != "" && !vpaPattern.MatchString(req.PayerVPA) {
+		return nil, fmt.Errorf("payer_vpa %q is not a UPI ID", req.PayerVPA)
+	}
+
+	payee := &model.PaymentRequest{
+		UserID:    req.UserID,
+		AccountID: req.AccountID,
+		PayeeVPA:  req.PayeeVPA,
+		PayeeName: req.PayeeName,
+	}
+	if user, ok := ps.seedStore.User(req.UserID); ok {
+		if payee.PayeeName == "" {
+			payee.PayeeName = user.Name
+		}
+		if payee.AccountID == "" && len(user.Accounts) > 0 {
+			payee.AccountID = user.Accounts[0].AccountID
+		}
+	}
+	if payee.PayeeVPA == "" {
+		payee.PayeeVPA = fmt.Sprintf("%s@%s", vpaUnsafe.ReplaceAllString(strings.ToLower(req.UserID), ""), ps.cfg.VPAHandle)
+	}
+	if !vpaPattern.MatchString(payee.PayeeVPA) {
+		return nil, fmt.Errorf("payee_vpa %q is not a UPI ID", payee.PayeeVPA)
+	}
+	if payee.PayeeName == "" {
+		payee.PayeeName = req.UserID
+	}
+
+	expiry := time.Duration(req.ExpiryHours) * time.Hour
+	if expiry <= 0 {
+		expiry = time.Duration(ps.cfg.ExpiryHours) * time.Hour
+	}
+	if expiry <= 0 {
+		expiry = 24 * time.Hour
+	}
+
+	now := time.Now()
+	pr := &model.PaymentRequest{
+		ID:        ids.Ref("PRQ_"),
+		UserID:    req.UserID,
+		AccountID: payee.AccountID,
+		PayeeVPA:  payee.PayeeVPA,
+		PayeeName: payee.PayeeName,
+		PayerName: req.PayerName,
+		PayerVPA:  req.PayerVPA,
+		Amount:    math.Round(req.Amount*100) / 100,
+		Currency:  "INR",
+		Note:      req.Note,
+		Status:    model.PaymentRequestPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(expiry),
+		Simulated: req.Sandbox,
+	}
+	pr.DeepLink = upiDeepLink(pr)
+	pr.QRPayload = pr.DeepLink
+
+	ps.mu.Lock()
+	ps.requests[pr.ID] = pr
+	ps.mu.Unlock()
+
+	log.Info().Str("request_id", pr.ID).Str("user_id", pr.UserID).Float64("amount", pr.Amount).
+		Str("payer", pr.PayerName).Bool("sandbox", pr.Simulated).Msg("Payment request created")
+
+	prCopy := *pr
+	return &prCopy, nil
+}
+
+// Get returns a payment request
+func (ps *PaymentRequestService) Get(id string) (*model.PaymentRequest, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	pr, ok := ps.requests[id]
+	if !ok {
+		return nil, false
+	}
+	ps.expire(pr)
+	prCopy := *pr
+	return &prCopy, true
+}
+
+// List returns a user's payment requests with the given status, or all of them, newest first
+func (ps *PaymentRequestService) List(userID string, status model.PaymentRequestStatus) []model.PaymentRequest {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	requests := make([]model.PaymentRequest, 0)
+	for _, pr := range ps.requests {
+		ps.expire(pr)
+		if pr.UserID == userID && (status == "" || pr.Status == status) {
+			requests = append(requests, *pr)
+		}
+	}
+	sort.Slice(requests, func(a, b int) bool { return requests[a].CreatedAt.After(requests[b].CreatedAt) })
+	return requests
+}
+
+// Cancel withdraws a pending payment request
+func (ps *PaymentRequestService) Cancel(id string) (*model.PaymentRequest, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	pr, ok := ps.requests[id]
+	if !ok {
+		return nil, ErrPaymentRequestNotFound
+	}
+	ps.expire(pr)
+	if pr.Status != model.PaymentRequestPending {
+		return nil, ErrPaymentRequestNotPending
+	}
+
+	pr.Status = model.PaymentRequestCancelled
+	log.Info().Str("request_id", pr.ID).Msg("Payment request cancelled")
+
+	prCopy := *pr
+	return &prCopy, nil
+}
+
+// Fulfil records a credit against the pending request it pays, if any. A credit
+// quoting a request's reference pays that request; any other credit pays the
+// oldest pending request for the same account or VPA. Either way the amount
+// must match the request's. Returns nil when the credit pays no request.
+func (ps *PaymentRequestService) Fulfil(credit *model.IncomingCredit) *model.PaymentRequest {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	amount := math.Round(credit.Amount*100) / 100
+	matches := func(pr *model.PaymentRequest) bool {
+		ps.expire(pr)
+		return pr.Status == model.PaymentRequestPending && pr.Simulated == credit.Sandbox && pr.Amount == amount
+	}
+
+	var paid *model.PaymentRequest
+	if pr, ok := ps.requests[strings.TrimSpace(credit.Reference)]; ok && matches(pr) {
+		paid = pr
+	} else {
+		for _, pr := range ps.requests {
+			if !matches(pr) {
+				continue
+			}
+			sameAccount := credit.AccountID != "" && pr.AccountID == credit.AccountID
+			sameVPA := credit.VPA != "" && strings.EqualFold(pr.PayeeVPA, credit.VPA)
+			if (sameAccount || sameVPA) && (paid == nil || pr.CreatedAt.Before(paid.CreatedAt)) {
+				paid = pr
+			}
+		}
+	}
+	if paid == nil {
+		return nil
+	}
+
+	now := time.Now()
+	paid.Status = model.PaymentRequestFulfilled
+	paid.FulfilledAt = &now
+	paid.TransactionID = credit.TransactionID
+	log.Info().Str("request_id", paid.ID).Str("transaction_id", credit.TransactionID).
+		Float64("amount", amount).Msg("Payment request fulfilled")
+
+	prCopy := *paid
+	return &prCopy
+}
+
+// expire marks a pending request expired once its time is up. Callers hold ps.mu.
+func (ps *PaymentRequestService) expire(pr *model.PaymentRequest) {
+	if pr.Status == model.PaymentRequestPending && !time.Now().Before(pr.ExpiresAt) {
+		pr.Status = model.PaymentRequestExpired
+	}
+}
+
+// upiDeepLink builds the UPI intent link (upi://pay) for a request: payee address
+// and name, the fixed amount, the request ID as transaction reference, and the note
+func upiDeepLink(pr *model.PaymentRequest) string {
+	params := []struct{ key, value string }{
+		{"pa", pr.PayeeVPA},
+		{"pn", pr.PayeeName},
+		{"am", fmt.Sprintf("%.2f", pr.Amount)},
+		{"cu", pr.Currency},
+		{"tr", pr.ID},
+		{"tn", pr.Note},
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.value == "" {
+			continue
+		}
+		// UPI apps read %20, not +, as a space, and expect the @ of a VPA as is
+		value := strings.NewReplacer("+", "%20", "%40", "@").Replace(url.QueryEscape(p.value))
+		parts = append(parts, p.key+"="+value)
+	}
+	return "upi://pay?" + strings.Join(parts, "&")
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// PreferenceStore holds per-user banking preferences such as the default account,
+// preferred transfer method and notification channel
+type PreferenceStore struct {
+	mu          sync.RWMutex
+	preferences map[string]*model.UserPreferences // Keyed by user ID
+}
+
+// NewPreferenceStore creates an empty preference store
+func NewPreferenceStore() *PreferenceStore {
+	return &PreferenceStore{
+		preferences: make(map[string]*model.UserPreferences),
+	}
+}
+
+// Get returns a copy of a user's preferences
+func (ps *PreferenceStore) Get(ctx context.Context, userID string) (*model.UserPreferences, bool) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	prefs, ok := ps.preferences[userID]
+	if !ok {
+		return nil, false
+	}
+	return copyPreferences(prefs), true
+}
+
+// Update validates and merges an update into a user's preferences
+func (ps *PreferenceStore) Update(ctx context.Context, userID string, update *model.PreferenceUpdate) (*model.UserPreferences, error) {
+	if err := validatePreferenceUpdate(update); err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	prefs, ok := ps.preferences[userID]
+	if !ok {
+		prefs = &model.UserPreferences{UserID: userID}
+		ps.preferences[userID] = prefs
+	}
+
+	if update.DefaultAccount != "" {
+		prefs.DefaultAccount = update.DefaultAccount
+	}
+	if update.PreferredTransferMethod != "" {
+		prefs.PreferredTransferMethod = update.PreferredTransferMethod
+	}
+	if update.NotificationChannel != "" {
+		prefs.NotificationChannel = update.NotificationChannel
+	}
+	for _, rule := range update.TransferRules {
+		prefs.TransferRules = upsertRule(prefs.TransferRules, rule)
+	}
+	prefs.UpdatedAt = time.Now()
+
+	log.Info().
+		Str("user_id", userID).
+		Int("transfer_rules", len(prefs.TransferRules)).
+		Msg("User preferences updated")
+
+	return copyPreferences(prefs), nil
+}
+
+// Delete removes all of a user's preferences and reports whether any existed
+func (ps *PreferenceStore) Delete(ctx context.Context, userID string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	_, ok := ps.preferences[userID]
+	delete(ps.preferences, userID)
+	return ok
+}
+
+// ResolveTransferMethod picks the transfer method for an amount: the tightest rule
+// that covers it, else the preferred method, else none
+func (ps *PreferenceStore) ResolveTransferMethod(ctx context.Context, userID string, amount float64) *model.TransferMethodResolution {
+	resolution := &model.TransferMethodResolution{UserID: userID, Amount: amount, Source: "none"}
+
+	prefs, ok := ps.Get(ctx, userID)
+	if !ok {
+		return resolution
+	}
+
+	for _, rule := range prefs.TransferRules {
+		if rule.MaxAmount == 0 || amount <= rule.MaxAmount {
+			r := rule
+			resolution.Method = rule.Method
+			resolution.Source = "rule"
+			resolution.Rule = &r
+			return resolution
+		}
+	}
+
+	if prefs.PreferredTransferMethod != "" {
+		resolution.Method = prefs.PreferredTransferMethod
+		resolution.Source = "preferred"
+	}
+	return resolution
+}
+
+// upsertRule replaces the rule with the same MaxAmount or adds it, keeping rules
+// ordered from the smallest limit up with "any amount" last
+func upsertRule(rules []model.TransferRule, rule model.TransferRule) []model.TransferRule {
+	replaced := false
+	for i := range rules {
+		if rules[i].MaxAmount == rule.MaxAmount {
+			rules[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		rules = append(rules, rule)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i].MaxAmount, rules[j].MaxAmount
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return rules
+}
+
+// copyPreferences returns a copy that callers may modify
+func copyPreferences(prefs *model.UserPreferences) *model.UserPreferences {
+	copied := *prefs
+	copied.TransferRules = append([]model.TransferRule(nil), prefs.TransferRules...)
+	return &copied
+}
+
+// validatePreferenceUpdate rejects unknown methods and channels
+func validatePreferenceUpdate(update *model.PreferenceUpdate) error {
+	if update.PreferredTransferMethod != "" && !isTransferMethod(update.PreferredTransferMethod) {
+		return fmt.Errorf("unsupported transfer method: %s", update.PreferredTransferMethod)
+	}
+
+	switch update.NotificationChannel {
+	case "", model.NotifySMS, model.NotifyEmail, model.NotifyPush, model.NotifyWhatsApp:
+	default:
+		return fmt.Errorf("unsupported notification channel: %s", update.NotificationChannel)
+	}
+
+	for _, rule := range update.TransferRules {
+		if !isTransferMethod(rule.Method) {
+			return fmt.Errorf("unsupported transfer method in rule: %s", rule.Method)
+		}
+		if rule.MaxAmount < 0 {
+			return fmt.Errorf("transfer rule max_amount must not be negative")
+		}
+	}
+	return nil
+}
+
+// isTransferMethod reports whether t is a rail a customer can transfer on
+func isTransferMethod(t model.TransactionType) bool {
+	switch t {
+	case model.TransactionTypeNEFT, model.TransactionTypeRTGS, model.TransactionTypeIMPS, model.TransactionTypeUPI:
+		return true
+	}
+	return false
+}
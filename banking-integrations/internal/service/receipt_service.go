@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// Receipt errors
+var (
+	ErrReceiptsDisabled   = errors.New("receipt links are disabled")
+	ErrReceiptNotFound    = errors.New("transfer not found")
+	ErrReceiptUnavailable = errors.New("the transfer did not go through, so it has no receipt")
+	ErrReceiptLinkInvalid = errors.New("receipt link is not valid")
+	ErrReceiptLinkExpired = errors.New("receipt link has expired")
+)
+
+// receiptLinkPurpose keys receipt links apart from other sealed links
+const receiptLinkPurpose = "receipt-link"
+
+// receiptClaims is what a receipt link carries. It is sealed, not just signed, so the
+// link neither reveals the user nor can be altered to show another transfer.
+type receiptClaims struct {
+	TransactionID string `json:"t"`
+	UserID        string `json:"u"`
+	ExpiresAt     int64  `json:"e"` // Unix seconds
+}
+
+// ReceiptService gives out short-lived links to the receipts of transfers, for users
+// to share as proof of payment. Links need no login and nothing is stored for them:
+// the transfer and expiry are sealed into the link with RECEIPT_SIGNING_KEY.
+type ReceiptService struct {
+	cfg        *config.ReceiptsConfig
+	dwh        *DWHService
+	signingKey *secrets.Value
+}
+
+// NewReceiptService creates a receipt service
+func NewReceiptService(cfg *config.ReceiptsConfig, dwh *DWHService) *ReceiptService {
+	return &ReceiptService{
+		cfg:        cfg,
+		dwh:        dwh,
+		signingKey: config.Profile().RotatingSecret("RECEIPT_SIGNING_KEY", cfg.SigningKey),
+	}
+}
+
+// CreateLink returns a link to the receipt of one of the user's transfers, their most
+// recent one when the request names none
+func (rs *ReceiptService) CreateLink(ctx context.Context, req *model.ReceiptLinkRequest) (*model.ReceiptLink, error) {
+	if !rs.cfg.Enabled {
+		return nil, ErrReceiptsDisabled
+	}
+	if req.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	ttl := rs.cfg.TTLMinutes
+	if req.TTLMinutes < 0 || req.TTLMinutes > rs.cfg.MaxTTLMinutes {
+		return nil, fmt.Errorf("ttl_minutes must be between 1 and %d", rs.cfg.MaxTTLMinutes)
+	}
+	if req.TTLMinutes > 0 {
+		ttl = req.TTLMinutes
+	}
+
+	txn, err := rs.find(ctx, req.UserID, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasReceipt(txn.Status) {
+		return nil, ErrReceiptUnavailable
+	}
+
+	expiresAt := time.Now().Add(time.Duration(ttl) * time.Minute).Truncate(time.Second)
+	token, err := rs.seal(&receiptClaims{TransactionID: txn.TransactionID, UserID: req.UserID, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("transaction_id", txn.TransactionID).
+		Time("expires_at", expiresAt).
+		Msg("Receipt link created")
+
+	return &model.ReceiptLink{
+		TransactionID: txn.TransactionID,
+		URL:           rs.cfg.BaseURL + "/receipts/" + token,
+		ExpiresAt:     expiresAt,
+		Receipt:       redactReceipt(txn, expiresAt),
+	}, nil
+}
+
+// Open returns the receipt a link points at, if the link is genuine and unexpired
+func (rs *ReceiptService) Open(ctx context.Context, token string) (*model.Receipt, error) {
+	if !rs.cfg.Enabled {
+		return nil, ErrReceiptLinkInvalid
+	}
+	claims, err := rs.open(token)
+	if err != nil {
+		return nil, ErrReceiptLinkInvalid
+	}
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if time.Now().After(expiresAt) {
+		return nil, ErrReceiptLinkExpired
+	}
+
+	txn, err := rs.find(ctx, claims.UserID, claims.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	return redactReceipt(txn, expiresAt), nil
+}
+
+// find returns one of the user's transfers by ID, or their most recent one. Transfers
+// made through the gateway are looked at along with the user's history.
+func (rs *ReceiptService) find(ctx context.Context, userID, transactionID string) (*model.Transaction, error) {
+	candidates := rs.dwh.UserTransfers(userID)
+	if history, err := rs.dwh.GetTransactionHistory(ctx, userID, rs.cfg.LookbackDays); err == nil {
+		candidates = append(candidates, history...)
+	} else {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Transaction history unavailable for receipts")
+	}
+
+	var found *model.Transaction
+	for i := range candidates {
+		txn := &candidates[i]
+		if transactionID != "" {
+			if txn.TransactionID == transactionID {
+				return txn, nil
+			}
+			continue
+		}
+		if isRailTransfer(txn.Type) && (found == nil || txn.CreatedAt.After(found.CreatedAt)) {
+			found = txn
+		}
+	}
+	if found == nil {
+		return nil, ErrReceiptNotFound
+	}
+	return found, nil
+}
+
+// isRailTransfer reports whether a transaction is money the user sent over a rail,
+// as opposed to a card debit or an incoming credit
+func isRailTransfer(t model.TransactionType) bool {
+	switch t {
+	case model.TransactionTypeNEFT, model.TransactionTypeRTGS, model.TransactionTypeIMPS, model.TransactionTypeUPI:
+		return true
+	}
+	return false
+}
+
+// hasReceipt reports whether a transfer in this status is proof of anything
+func hasReceipt(status model.TransactionStatus) bool {
+	return status == model.TransactionStatusCompleted || status == model.TransactionStatusPending
+}
+
+// redactReceipt keeps what proves the payment and masks or drops the rest
+func redactReceipt(txn *model.Transaction, linkExpiresAt time.Time) *model.Receipt {
+	currency := txn.Currency
+	if currency == "" {
+		currency = "INR"
+	}
+	from := txn.FromAccount
+	if from == "" {
+		from = txn.AccountID
+	}
+	receipt := &model.Receipt{
+		TransactionID: maskTail(txn.TransactionID),
+		Reference:     txn.ReferenceNumber,
+		Rail:          txn.Type,
+		Amount:        txn.Amount,
+		Currency:      currency,
+		Status:        txn.Status,
+		FromAccount:   maskTail(from),
+		ToAccount:     maskTail(txn.ToAccount),
+		PaidAt:        txn.CreatedAt,
+		CompletedAt:   txn.CompletedAt,
+		LinkExpiresAt: linkExpiresAt,
+	}
+	if len(txn.IFSC) >= 4 {
+		receipt.PayeeBank = strings.ToUpper(txn.IFSC[:4])
+	}
+	return receipt
+}
+
+// maskTail keeps the last four characters, e.g. XXXX6789
+func maskTail(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 4 {
+		return "XXXX"
+	}
+	return "XXXX" + value[len(value)-4:]
+}
+
+// seal encrypts claims into a receipt link's token
+func (rs *ReceiptService) seal(claims *receiptClaims) (string, error) {
+	return sealLink(receiptLinkPurpose, rs.signingKey.Get(), claims)
+}
+
+// open reverses seal
+func (rs *ReceiptService) open(token string) (*receiptClaims, error) {
+	var claims receiptClaims
+	if err := openLink(receiptLinkPurpose, rs.signingKey.Get(), token, &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultRESTMapping is used when no mapping file is configured: a core-banking API
+// that speaks the gateway's own field names
+var defaultRESTMapping = model.RESTMapping{
+	Balance:     model.RESTOperation{Method: "GET", Path: "/accounts/{account_id}/balance"},
+	Transfer:    model.RESTOperation{Method: "POST", Path: "/transfers"},
+	Statement:   model.RESTOperation{Method: "GET", Path: "/accounts/{account_id}/statement?from={start_date}&to={end_date}&limit={limit}"},
+	Beneficiary: model.RESTOperation{Method: "POST", Path: "/users/{user_id}/beneficiaries"},
+
+	BeneficiaryLookup: model.RESTOperation{Method: "GET", Path: "/users/{user_id}/beneficiaries/{account_number}"},
+
+	Account:       model.RESTOperation{Method: "GET", Path: "/accounts/{account_id}"},
+	BalanceUpdate: model.RESTOperation{Method: "POST", Path: "/accounts/{account_id}/balance-updates"},
+}
+
+// unfilledPlaceholder matches path placeholders left after filling in the request
+var unfilledPlaceholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// numericFields are gateway fields core-banking APIs often send as strings
+var numericFields = map[string]bool{"balance": true, "available_balance": true, "amount": true, "balance_before": true, "balance_after": true}
+
+// RESTConnector talks to a core-banking REST API, renaming fields as its mapping says
+type RESTConnector struct {
+	channel    model.Channel
+	baseURL    string
+	apiKey     *secrets.Value
+	mapping    model.RESTMapping
+	httpClient *http.Client
+}
+
+// NewRESTConnector creates a REST connector, loading its mapping file if one is set
+func NewRESTConnector(channel model.Channel, cfg config.ConnectorConfig) (*RESTConnector, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("rest connector needs CONNECTOR_%s_URL", channel)
+	}
+
+	mapping := defaultRESTMapping
+	if cfg.MappingFile != "" {
+		data, err := os.ReadFile(cfg.MappingFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mapping file: %w", err)
+		}
+		if err := json.Unmarshal(data, &mapping); err != nil {
+			return nil, fmt.Errorf("failed to parse mapping file %s: %w", cfg.MappingFile, err)
+		}
+	}
+	for name, op := range map[string]model.RESTOperation{
+		"balance": mapping.Balance, "transfer": mapping.Transfer, "statement": mapping.Statement, "beneficiary": mapping.Beneficiary,
+		"beneficiary_lookup": mapping.BeneficiaryLookup, "account": mapping.Account, "balance_update": mapping.BalanceUpdate,
+	} {
+		if op.Method == "" || op.Path == "" {
+			return nil, fmt.Errorf("mapping for %s needs a method and a path", name)
+		}
+	}
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &RESTConnector{
+		channel:    channel,
+		baseURL:    strings.TrimRight(cfg.URL, "/"),
+		apiKey:     config.Profile().RotatingSecret(fmt.Sprintf("CONNECTOR_%s_API_KEY", channel), cfg.APIKey),
+		mapping:    mapping,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// GetBalance reads an account balance from core banking
+func (rc *RESTConnector) GetBalance(ctx context.Context, req *model.BalanceRequest) (*model.BalanceResponse, error) {
+	raw, err := rc.call(ctx, rc.mapping.Balance, toFields(req))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.BalanceResponse
+	if err := mapFields(raw, rc.mapping.Balance.Response, &resp); err != nil {
+		return nil, err
+	}
+	if resp.AccountID == "" {
+		resp.AccountID = req.AccountID
+	}
+	if resp.Currency == "" {
+		resp.Currency = "INR"
+	}
+	if resp.LastUpdated.IsZero() {
+		resp.LastUpdated = time.Now()
+	}
+	return &resp, nil
+}
+
+// TransferFunds submits a transfer to core banking. A transfer whose status core
+// banking does not report is returned as PENDING.
+func (rc *RESTConnector) TransferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error) {
+	raw, err := rc.call(ctx, rc.mapping.Transfer, toFields(req))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.TransferResponse
+	if err := mapFields(raw, rc.mapping.Transfer.Response, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == "" {
+		resp.Status = string(model.TransactionStatusPending)
+	}
+	if resp.Amount == 0 {
+		resp.Amount = req.Amount
+	}
+	if resp.FromAccount == "" {
+		resp.FromAccount = req.FromAccount
+	}
+	if resp.ToAccount == "" {
+		resp.ToAccount = req.ToAccount
+	}
+	if resp.ProcessedAt.IsZero() {
+		resp.ProcessedAt = time.Now()
+	}
+	return &resp, nil
+}
+
+// GetStatement reads an account's transactions from core banking
+func (rc *RESTConnector) GetStatement(ctx context.Context, req *model.StatementRequest) (*model.StatementResponse, error) {
+	op := rc.mapping.Statement
+	raw, err := rc.call(ctx, op, toFields(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := model.StatementResponse{}
+	if op.Items == "" {
+		if err := mapFields(raw, op.Response, &resp); err != nil {
+			return nil, err
+		}
+	} else {
+		items, _ := lookupPath(raw, op.Items).([]interface{})
+		for _, item := range items {
+			var txn model.Transaction
+			if err := mapFields(item, op.Item, &txn); err != nil {
+				return nil, err
+			}
+			if txn.AccountID == "" {
+				txn.AccountID = req.AccountID
+			}
+			if txn.UserID == "" {
+				txn.UserID = req.UserID
+			}
+			if txn.Channel == "" {
+				txn.Channel = rc.channel
+			}
+			resp.Transactions = append(resp.Transactions, txn)
+		}
+	}
+
+	if resp.Transactions == nil {
+		resp.Transactions = []model.Transaction{}
+	}
+	resp.AccountID = req.AccountID
+	resp.StartDate = req.StartDate
+	resp.EndDate = req.EndDate
+	resp.Count = len(resp.Transactions)
+	resp.GeneratedAt = time.Now()
+	return &resp, nil
+}
+
+// AddBeneficiary registers a beneficiary with core banking
+func (rc *RESTConnector) AddBeneficiary(ctx context.Context, userID, accountNumber, ifsc, name string) (*model.Beneficiary, error) {
+	fields := map[string]interface{}{
+		"user_id":        userID,
+		"account_number": accountNumber,
+		"ifsc":           ifsc,
+		"name":           name,
+	}
+	raw, err := rc.call(ctx, rc.mapping.Beneficiary, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.Beneficiary
+	if err := mapFields(raw, rc.mapping.Beneficiary.Response, &resp); err != nil {
+		return nil, err
+	}
+	if resp.UserID == "" {
+		resp.UserID = userID
+	}
+	if resp.AccountNumber == "" {
+		resp.AccountNumber = accountNumber
+	}
+	if resp.IFSC == "" {
+		resp.IFSC = ifsc
+	}
+	if resp.Name == "" {
+		resp.Name = name
+	}
+	if resp.Status == "" {
+		resp.Status = "ACTIVE"
+	}
+	if resp.AddedAt.IsZero() {
+		resp.AddedAt = time.Now()
+	}
+	return &resp, nil
+}
+
+// GetBeneficiary reads a registered beneficiary back from core banking. A
+// beneficiary whose registration date core banking does not send is refused rather
+// than dated now, since callers decide on its age.
+func (rc *RESTConnector) GetBeneficiary(ctx context.Context, userID, accountNumber string) (*model.Beneficiary, error) {
+	fields := map[string]interface{}{"user_id": userID, "account_number": accountNumber}
+	raw, err := rc.call(ctx, rc.mapping.BeneficiaryLookup, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.Beneficiary
+	if err := mapFields(raw, rc.mapping.BeneficiaryLookup.Response, &resp); err != nil {
+		return nil, err
+	}
+	if resp.AddedAt.IsZero() {
+		return nil, fmt.Errorf("core banking sent no registration date for beneficiary %s", accountNumber)
+	}
+	if resp.UserID == "" {
+		resp.UserID = userID
+	}
+	if resp.AccountNumber == "" {
+		resp.AccountNumber = accountNumber
+	}
+	return &resp, nil
+}
+
+// GetAccount reads an account's record, including its owner, from core banking
+func (rc *RESTConnector) GetAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	raw, err := rc.call(ctx, rc.mapping.Account, map[string]interface{}{"account_id": accountID})
+	if err != nil {
+		return nil, err
+	}
+
+	var acct model.Account
+	if err := mapFields(raw, rc.mapping.Account.Response, &acct); err != nil {
+		return nil, err
+	}
+	if acct.AccountID == "" {
+		acct.AccountID = accountID
+	}
+	if acct.Currency == "" {
+		acct.Currency = "INR"
+	}
+	return &acct, nil
+}
+
+// UpdateBalance posts a ledger entry to an account in core banking. Core banking
+// refuses an entry that would overdraw the account.
+func (rc *RESTConnector) UpdateBalance(ctx context.Context, req *model.BalanceUpdateRequest) (*model.BalanceUpdateResponse, error) {
+	raw, err := rc.call(ctx, rc.mapping.BalanceUpdate, toFields(req))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.BalanceUpdateResponse
+	if err := mapFields(raw, rc.mapping.BalanceUpdate.Response, &resp); err != nil {
+		return nil, err
+	}
+	if resp.AccountID == "" {
+		resp.AccountID = req.AccountID
+	}
+	return &resp, nil
+}
+
+// call sends one mapped operation and returns the decoded response body
+func (rc *RESTConnector) call(ctx context.Context, op model.RESTOperation, fields map[string]interface{}) (interface{}, error) {
+	path := op.Path
+	for field, value := range fields {
+		path = strings.ReplaceAll(path, "{"+field+"}", url.QueryEscape(fieldString(value)))
+	}
+	path = unfilledPlaceholder.ReplaceAllString(path, "") // Fields the request left out
+
+	var body io.Reader
+	if op.Method != http.MethodGet && op.Method != http.MethodDelete {
+		payload := fields
+		if len(op.Request) > 0 {
+			payload = make(map[string]interface{}, len(op.Request))
+			for remote, field := range op.Request {
+				if value, ok := fields[field]; ok {
+					payload[remote] = value
+				}
+			}
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, op.Method, rc.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if rc.apiKey.Get() != "" {
+		httpReq.Header.Set("X-API-Key", rc.apiKey.Get())
+	}
+
+	start := time.Now()
+	resp, err := rc.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %v", ErrConnectorUnavailable, op.Method, path, err)
+	}
+	defer resp.Body.Close()
+
+	log.Debug().Str("channel", string(rc.channel)).Str("method", op.Method).Str("path", path).
+		Int("status", resp.StatusCode).Dur("took", time.Since(start)).Msg("Core banking call")
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read core banking response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := string(respBody)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return nil, fmt.Errorf("core banking returned %d for %s %s: %s", resp.StatusCode, op.Method, path, strings.TrimSpace(snippet))
+	}
+
+	var raw interface{}
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, &raw); err != nil {
+			return nil, fmt.Errorf("core banking response is not JSON: %w", err)
+		}
+	}
+	return raw, nil
+}
+
+// toFields flattens a gateway request into its JSON fields
+func toFields(v interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if data, err := json.Marshal(v); err == nil {
+		json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// mapFields fills out from raw, reading each gateway field from its mapped path.
+// With no mapping raw is read as is.
+func mapFields(raw interface{}, mapping map[string]string, out interface{}) error {
+	source := raw
+	if len(mapping) > 0 {
+		mapped := make(map[string]interface{}, len(mapping))
+		for field, path := range mapping {
+			value := lookupPath(raw, path)
+			if value == nil {
+				continue
+			}
+			if s, ok := value.(string); ok && numericFields[field] {
+				if f, err := strconv.ParseFloat(s, 64); err == nil {
+					value = f
+				}
+			}
+			mapped[field] = value
+		}
+		source = mapped
+	}
+
+	data, err := json.Marshal(source)
+	if err != nil {
+		return fmt.Errorf("failed to map core banking response: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to map core banking response: %w", err)
+	}
+	return nil
+}
+
+// lookupPath reads a dotted path such as data.balance.available from decoded JSON
+func lookupPath(raw interface{}, path string) interface{} {
+	current := raw
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+	return current
+}
+
+// fieldString renders a request field for a path placeholder
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// SandboxService handles simulated banking operations in an isolated store.
+// Nothing written here is visible to the MB/NB/DWH services.
+type SandboxService struct {
+	mu             sync.RWMutex
+	openingBalance float64
+	balances       map[string]float64             // Keyed by account ID
+	transactions   map[string][]model.Transaction // Keyed by account ID
+	beneficiaries  map[string][]model.Beneficiary // Keyed by user ID
+}
+
+// NewSandboxService creates a new sandbox service
+func NewSandboxService(openingBalance float64) *SandboxService {
+	return &SandboxService{
+		openingBalance: openingBalance,
+		balances:       make(map[string]float64),
+		transactions:   make(map[string][]model.Transaction),
+		beneficiaries:  make(map[string][]model.Beneficiary),
+	}
+}
+
+// GetBalance retrieves the sandbox balance for an account
+func (s *SandboxService) GetBalance(ctx context.Context, req *model.BalanceRequest) (*model.BalanceResponse, error) {
+	s.mu.Lock()
+	balance := s.balanceLocked(req.AccountID)
+	s.mu.Unlock()
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("account_id", req.AccountID).
+		Msg("Sandbox: Getting balance")
+
+	return &model.BalanceResponse{
+		AccountID:        req.AccountID,
+		AccountNumber:    "XXXX1234",
+		Balance:          balance,
+		Currency:         "INR",
+		AvailableBalance: balance,
+		LastUpdated:      time.Now(),
+		Simulated:        true,
+	}, nil
+}
+
+// TransferFunds debits the sandbox balance and records a simulated transaction
+func (s *SandboxService) TransferFunds(ctx context.Context, req *model.TransferRequest) (*model.TransferResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	balance := s.balanceLocked(req.FromAccount)
+	if req.Amount > balance {
+		return nil, fmt.Errorf("insufficient sandbox balance: available %.2f, requested %.2f", balance, req.Amount)
+	}
+
+	now := time.Now()
+	txnID := ids.Ref(ids.Sandbox)
+	refNumber := ids.Ref("SIMREF")
+
+	s.balances[req.FromAccount] = balance - req.Amount
+	s.transactions[req.FromAccount] = append(s.transactions[req.FromAccount], model.Transaction{
+		TransactionID:   txnID,
+		AccountID:       req.FromAccount,
+		UserID:          req.UserID,
+		Type:            req.Type,
+		Amount:          req.Amount,
+		Currency:        "INR",
+		FromAccount:     req.FromAccount,
+		ToAccount:       req.ToAccount,
+		IFSC:            req.IFSC,
+		Status:          model.TransactionStatusCompleted,
+		Remarks:         req.Remarks,
+		Channel:         req.Channel,
+		ReferenceNumber: refNumber,
+		CreatedAt:       now,
+		CompletedAt:     &now,
+	})
+
+	log.Info().
+		Str("user_id", req.UserID).
+		Str("from_account", req.FromAccount).
+		Str("to_account", req.ToAccount).
+		Float64("amount", req.Amount).
+		Str("txn_id", txnID).
+		Msg("Sandbox: Processing simulated transfer")
+
+	return &model.TransferResponse{
+		TransactionID:   txnID,
+		Status:          "COMPLETED",
+		Amount:          req.Amount,
+		FromAccount:     req.FromAccount,
+		ToAccount:       req.ToAccount,
+		ReferenceNumber: refNumber,
+		ProcessedAt:     now,
+		Message:         "Simulated transfer processed in sandbox (no real funds moved)",
+		Simulated:       true,
+	}, nil
+}
+
+// GetStatement returns the simulated transactions recorded for an account
+func (s *SandboxService) GetStatement(ctx context.Context, req *model.StatementRequest) (*model.StatementResponse, error) {
+	s.mu.RLock()
+	transactions := []model.Transaction{}
+	for _, txn := range s.transactions[req.AccountID] {
+		if txn.CreatedAt.Before(req.StartDate) || txn.CreatedAt.After(req.EndDate) {
+			continue
+		}
+		transactions = append(transactions, txn)
+	}
+	s.mu.RUnlock()
+
+	// Newest first, matching the channel services
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+	})
+
+	if req.Limit > 0 && len(transactions) > req.Limit {
+		transactions = transactions[:req.Limit]
+	}
+
+	return &model.StatementResponse{
+		AccountID:    req.AccountID,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		Transactions: transactions,
+		Count:        len(transactions),
+		GeneratedAt:  time.Now(),
+		Simulated:    true,
+	}, nil
+}
+
+// AddBeneficiary records a simulated beneficiary
+func (s *SandboxService) AddBeneficiary(ctx context.Context, userID, accountNumber, ifsc, name string) (*model.Beneficiary, error) {
+	beneficiary := model.Beneficiary{
+		BeneficiaryID: ids.Ref("SBXBEN_"),
+		UserID:        userID,
+		AccountNumber: accountNumber,
+		IFSC:          ifsc,
+		Name:          name,
+		AccountType:   "SAVINGS",
+		Status:        "ACTIVE",
+		AddedAt:       time.Now(),
+		Simulated:     true,
+	}
+
+	s.mu.Lock()
+	s.beneficiaries[userID] = append(s.beneficiaries[userID], beneficiary)
+	s.mu.Unlock()
+
+	log.Info().
+		Str("user_id", userID).
+		Str("account_number", accountNumber).
+		Msg("Sandbox: Adding simulated beneficiary")
+
+	return &beneficiary, nil
+}
+
+// Beneficiary returns a simulated beneficiary a user added for an account number
+func (s *SandboxService) Beneficiary(userID, accountNumber string) (*model.Beneficiary, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ben := range s.beneficiaries[userID] {
+		if ben.AccountNumber == accountNumber {
+			return &ben, true
+		}
+	}
+	return nil, false
+}
+
+// Reset discards all sandbox balances, transactions and beneficiaries
+func (s *SandboxService) Reset(ctx context.Context) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txnCount := 0
+	for _, txns := range s.transactions {
+		txnCount += len(txns)
+	}
+	beneficiaryCount := 0
+	for _, bens := range s.beneficiaries {
+		beneficiaryCount += len(bens)
+	}
+
+	cleared := map[string]int{
+		"accounts":      len(s.balances),
+		"transactions":  txnCount,
+		"beneficiaries": beneficiaryCount,
+	}
+
+	s.balances = make(map[string]float64)
+	s.transactions = make(map[string][]model.Transaction)
+	s.beneficiaries = make(map[string][]model.Beneficiary)
+
+	log.Info().
+		Int("accounts", cleared["accounts"]).
+		Int("transactions", cleared["transactions"]).
+		Msg("Sandbox: State reset")
+
+	return cleared
+}
+
+// balanceLocked returns the balance for an account, seeding it on first use.
+// Caller must hold the write lock.
+func (s *SandboxService) balanceLocked(accountID string) float64 {
+	balance, ok := s.balances[accountID]
+	if !ok {
+		balance = s.openingBalance
+		s.balances[accountID] = balance
+	}
+	return balance
+}
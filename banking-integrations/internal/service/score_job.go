@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrScoringRunInProgress is returned when a refresh is started while one is running
+var ErrScoringRunInProgress = errors.New("a credit-score refresh is already running")
+
+// maxRunErrors caps how many per-user failures a run records
+const maxRunErrors = 10
+
+// scoreBands are the credit score buckets drift is measured over
+var scoreBands = [][2]int{{300, 579}, {580, 669}, {670, 739}, {740, 799}, {800, 850}}
+
+// ScoreJob refreshes the credit score of every user in the DWH, a few users at a
+// time, and compares each run's score distribution with the previous run's
+type ScoreJob struct {
+	dwhService  *DWHService
+	scorer      *CreditScorer
+	store       *ScoreStore
+	concurrency int
+
+	mu         sync.Mutex
+	runningRun string // ID of the run in progress, if any
+}
+
+// NewScoreJob creates a new score refresh job
+func NewScoreJob(dwhService *DWHService, scorer *CreditScorer, store *ScoreStore, concurrency int) *ScoreJob {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &ScoreJob{
+		dwhService:  dwhService,
+		scorer:      scorer,
+		store:       store,
+		concurrency: concurrency,
+	}
+}
+
+// Start begins a refresh in the background and returns the new run
+func (sj *ScoreJob) Start(trigger string) (*model.ScoringRun, error) {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	if sj.runningRun != "" {
+		return nil, ErrScoringRunInProgress
+	}
+
+	run := &model.ScoringRun{
+		RunID:     ids.Ref("SCR_"),
+		Trigger:   trigger,
+		Status:    model.ScoringRunRunning,
+		StartedAt: time.Now(),
+	}
+	sj.store.AddRun(run)
+	sj.runningRun = run.RunID
+
+	go sj.execute(context.Background(), run.RunID)
+
+	runCopy := *run
+	return &runCopy, nil
+}
+
+// Schedule starts a refresh every interval until ctx is done. A refresh still
+// running when the next one is due is left to finish and that tick is skipped.
+func (sj *ScoreJob) Schedule(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := sj.Start("schedule"); err != nil {
+					log.Warn().Err(err).Msg("Scheduled credit-score refresh skipped")
+				}
+			}
+		}
+	}()
+}
+
+// execute scores every user and completes the run
+func (sj *ScoreJob) execute(ctx context.Context, runID string) {
+	defer func() {
+		sj.mu.Lock()
+		sj.runningRun = ""
+		sj.mu.Unlock()
+	}()
+
+	userIDs := sj.dwhService.ListUsers(ctx)
+	sj.store.UpdateRun(runID, func(run *model.ScoringRun) { run.Users = len(userIDs) })
+
+	log.Info().Str("run_id", runID).Int("users", len(userIDs)).Int("concurrency", sj.concurrency).Msg("Credit-score refresh started")
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < sj.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for userID := range jobs {
+				sj.scoreUser(ctx, runID, userID)
+			}
+		}()
+	}
+	for _, userID := range userIDs {
+		jobs <- userID
+	}
+	close(jobs)
+	wg.Wait()
+
+	var drift *model.ScoreDrift
+	if previous, ok := sj.store.PreviousCompleted(runID); ok {
+		drift = computeDrift(previous.RunID, sj.store.Snapshots(previous.RunID), sj.store.Snapshots(runID))
+	}
+
+	var final model.ScoringRun
+	sj.store.UpdateRun(runID, func(run *model.ScoringRun) {
+		now := time.Now()
+		run.FinishedAt = &now
+		run.Drift = drift
+		run.Status = model.ScoringRunCompleted
+		if run.Users > 0 && run.Scored == 0 {
+			run.Status = model.ScoringRunFailed
+		}
+		final = *run
+	})
+
+	event := log.Info()
+	if drift != nil && drift.Severity == "SIGNIFICANT" {
+		event = log.Warn()
+	}
+	event = event.
+		Str("run_id", runID).
+		Str("status", final.Status).
+		Int("scored", final.Scored).
+		Int("failed", final.Failed).
+		Dur("duration", final.FinishedAt.Sub(final.StartedAt))
+	if drift != nil {
+		event = event.
+			Str("previous_run_id", drift.PreviousRunID).
+			Float64("mean_shift", drift.MeanShift).
+			Float64("psi", drift.PSI).
+			Str("drift", drift.Severity)
+	}
+	event.Msg("Credit-score refresh finished")
+}
+
+// scoreUser scores one user and records the result on the run
+func (sj *ScoreJob) scoreUser(ctx context.Context, runID, userID string) {
+	profile := sj.dwhService.GetUserProfile(ctx, userID)
+	snapshot, err := sj.scorer.Score(ctx, runID, profile)
+	if err != nil {
+		log.Warn().Err(err).Str("run_id", runID).Str("user_id", userID).Msg("Credit-score refresh failed for user")
+		sj.store.UpdateRun(runID, func(run *model.ScoringRun) {
+			run.Failed++
+			if len(run.Errors) < maxRunErrors {
+				run.Errors = append(run.Errors, fmt.Sprintf("%s: %v", userID, err))
+			}
+		})
+		return
+	}
+
+	sj.store.AddSnapshot(snapshot)
+	sj.store.UpdateRun(runID, func(run *model.ScoringRun) { run.Scored++ })
+}
+
+// computeDrift compares two runs' score distributions. PSI below 0.1 is read as
+// stable, below 0.25 as a moderate shift and above that as significant.
+func computeDrift(previousRunID string, previous, current map[string]model.ScoreSnapshot) *model.ScoreDrift {
+	drift := &model.ScoreDrift{PreviousRunID: previousRunID}
+	drift.Mean, drift.StdDev = scoreStats(current)
+	drift.PreviousMean, drift.PreviousStdDev = scoreStats(previous)
+	drift.MeanShift = drift.Mean - drift.PreviousMean
+
+	currentShares := bandShares(current)
+	previousShares := bandShares(previous)
+	for i, band := range scoreBands {
+		drift.Buckets = append(drift.Buckets, model.ScoreBucket{
+			From:          band[0],
+			To:            band[1],
+			Share:         currentShares[i],
+			PreviousShare: previousShares[i],
+		})
+
+		// Empty bands are floored so the index stays finite
+		actual := math.Max(currentShares[i], 0.0001)
+		expected := math.Max(previousShares[i], 0.0001)
+		drift.PSI += (actual - expected) * math.Log(actual/expected)
+	}
+
+	totalChange := 0.0
+	for userID, snapshot := range current {
+		before, ok := previous[userID]
+		if !ok {
+			continue
+		}
+		drift.ComparedUsers++
+		totalChange += math.Abs(float64(snapshot.CreditScore - before.CreditScore))
+		if snapshot.ScoreRange != before.ScoreRange {
+			drift.RangeChanged++
+		}
+	}
+	if drift.ComparedUsers > 0 {
+		drift.MeanAbsChange = totalChange / float64(drift.ComparedUsers)
+	}
+
+	switch {
+	case drift.PSI < 0.1:
+		drift.Severity = "STABLE"
+	case drift.PSI < 0.25:
+		drift.Severity = "MODERATE"
+	default:
+		drift.Severity = "SIGNIFICANT"
+	}
+	return drift
+}
+
+// scoreStats returns the mean and standard deviation of a run's credit scores
+func scoreStats(scores map[string]model.ScoreSnapshot) (float64, float64) {
+	if len(scores) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, s := range scores {
+		sum += float64(s.CreditScore)
+	}
+	mean := sum / float64(len(scores))
+
+	variance := 0.0
+	for _, s := range scores {
+		d := float64(s.CreditScore) - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / float64(len(scores)))
+}
+
+// bandShares returns the share of a run's users in each score band
+func bandShares(scores map[string]model.ScoreSnapshot) []float64 {
+	shares := make([]float64, len(scoreBands))
+	if len(scores) == 0 {
+		return shares
+	}
+
+	for _, s := range scores {
+		for i, band := range scoreBands {
+			if s.CreditScore <= band[1] || i == len(scoreBands)-1 {
+				shares[i]++
+				break
+			}
+		}
+	}
+	for i := range shares {
+		shares[i] /= float64(len(scores))
+	}
+	return shares
+}
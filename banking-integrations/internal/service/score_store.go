@@ -0,0 +1,136 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+)
+
+// ScoreStore holds credit-score refresh runs and the snapshots each produced. Only
+// the most recent runs are kept; older ones are dropped as new runs are added.
+type ScoreStore struct {
+	mu        sync.RWMutex
+	keepRuns  int
+	runs      []*model.ScoringRun                        // Oldest first
+	snapshots map[string]map[string]*model.ScoreSnapshot // Run ID -> user ID -> snapshot
+}
+
+// NewScoreStore creates an empty score store keeping up to keepRuns runs
+func NewScoreStore(keepRuns int) *ScoreStore {
+	if keepRuns < 2 {
+		keepRuns = 2 // Drift needs the previous run
+	}
+	return &ScoreStore{
+		keepRuns:  keepRuns,
+		snapshots: make(map[string]map[string]*model.ScoreSnapshot),
+	}
+}
+
+// AddRun registers a new run
+func (ss *ScoreStore) AddRun(run *model.ScoringRun) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.runs = append(ss.runs, run)
+	ss.snapshots[run.RunID] = make(map[string]*model.ScoreSnapshot)
+
+	for len(ss.runs) > ss.keepRuns {
+		delete(ss.snapshots, ss.runs[0].RunID)
+		ss.runs = ss.runs[1:]
+	}
+}
+
+// UpdateRun applies fn to a run under the store's lock
+func (ss *ScoreStore) UpdateRun(runID string, fn func(run *model.ScoringRun)) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, run := range ss.runs {
+		if run.RunID == runID {
+			fn(run)
+			return
+		}
+	}
+}
+
+// AddSnapshot stores a user's score for a run
+func (ss *ScoreStore) AddSnapshot(snapshot *model.ScoreSnapshot) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if scores, ok := ss.snapshots[snapshot.RunID]; ok {
+		scores[snapshot.UserID] = snapshot
+	}
+}
+
+// Run returns a copy of a run
+func (ss *ScoreStore) Run(runID string) (*model.ScoringRun, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	for _, run := range ss.runs {
+		if run.RunID == runID {
+			runCopy := *run
+			return &runCopy, true
+		}
+	}
+	return nil, false
+}
+
+// Runs returns copies of all kept runs, newest first
+func (ss *ScoreStore) Runs() []model.ScoringRun {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	runs := make([]model.ScoringRun, 0, len(ss.runs))
+	for i := len(ss.runs) - 1; i >= 0; i-- {
+		runs = append(runs, *ss.runs[i])
+	}
+	return runs
+}
+
+// Snapshots returns the scores of a run keyed by user ID
+func (ss *ScoreStore) Snapshots(runID string) map[string]model.ScoreSnapshot {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	scores := make(map[string]model.ScoreSnapshot, len(ss.snapshots[runID]))
+	for userID, snapshot := range ss.snapshots[runID] {
+		scores[userID] = *snapshot
+	}
+	return scores
+}
+
+// PreviousCompleted returns the newest completed run before runID
+func (ss *ScoreStore) PreviousCompleted(runID string) (*model.ScoringRun, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	before := false
+	for i := len(ss.runs) - 1; i >= 0; i-- {
+		run := ss.runs[i]
+		if run.RunID == runID {
+			before = true
+			continue
+		}
+		if before && run.Status == model.ScoringRunCompleted {
+			runCopy := *run
+			return &runCopy, true
+		}
+	}
+	return nil, false
+}
+
+// History returns a user's scores across the kept runs, newest first
+func (ss *ScoreStore) History(userID string) []model.ScoreSnapshot {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var history []model.ScoreSnapshot
+	for i := len(ss.runs) - 1; i >= 0; i-- {
+		if snapshot, ok := ss.snapshots[ss.runs[i].RunID][userID]; ok {
+			history = append(history, *snapshot)
+		}
+	}
+	return history
+}
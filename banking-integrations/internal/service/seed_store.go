@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// SeedStore holds test data loaded from fixtures. Seeded users and accounts
+// take precedence over the built-in mock data in the MB/NB/DWH services.
+type SeedStore struct {
+	mu       sync.RWMutex
+	users    map[string]*model.SeedUser // Keyed by user ID
+	accounts map[string]*model.Account  // Keyed by account ID
+}
+
+// NewSeedStore creates an empty seed store
+func NewSeedStore() *SeedStore {
+	return &SeedStore{
+		users:    make(map[string]*model.SeedUser),
+		accounts: make(map[string]*model.Account),
+	}
+}
+
+// Load validates and loads a fixture. Existing users in the fixture are
+// overwritten; when replace is set all previously seeded data is dropped first.
+func (s *SeedStore) Load(ctx context.Context, fixture *model.SeedFixture, replace bool) (*model.SeedResult, error) {
+	if err := validateFixture(fixture); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if replace {
+		s.users = make(map[string]*model.SeedUser)
+		s.accounts = make(map[string]*model.Account)
+	} else if err := s.checkAccountOwners(fixture); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &model.SeedResult{}
+	for i := range fixture.Users {
+		user := fixture.Users[i]
+		user.SeededAt = now
+
+		// Drop accounts from a previous load of the same user
+		if previous, exists := s.users[user.UserID]; exists {
+			for _, acct := range previous.Accounts {
+				delete(s.accounts, acct.AccountID)
+			}
+		}
+
+		for j := range user.Accounts {
+			acct := &user.Accounts[j]
+			acct.UserID = user.UserID
+			if acct.Currency == "" {
+				acct.Currency = "INR"
+			}
+			if acct.Status == "" {
+				acct.Status = "ACTIVE"
+			}
+			if acct.AccountType == "" {
+				acct.AccountType = "SAVINGS"
+			}
+			if acct.KYCStatus == "" {
+				acct.KYCStatus = user.KYCStatus
+			}
+			if acct.CreatedAt.IsZero() {
+				acct.CreatedAt = now
+			}
+			acct.LastUpdated = now
+			s.accounts[acct.AccountID] = acct
+		}
+
+		for j := range user.Beneficiaries {
+			ben := &user.Beneficiaries[j]
+			ben.UserID = user.UserID
+			if ben.BeneficiaryID == "" {
+				ben.BeneficiaryID = fmt.Sprintf("BEN_%s_%d", user.UserID, j+1)
+			}
+			if ben.Status == "" {
+				ben.Status = "ACTIVE"
+			}
+			if ben.AddedAt.IsZero() {
+				ben.AddedAt = now
+			}
+		}
+
+		for j := range user.Transactions {
+			txn := &user.Transactions[j]
+			txn.UserID = user.UserID
+			if txn.TransactionID == "" {
+				txn.TransactionID = fmt.Sprintf("SEED_%s_%d", user.UserID, j+1)
+			}
+			if txn.AccountID == "" && len(user.Accounts) > 0 {
+				txn.AccountID = user.Accounts[0].AccountID
+			}
+			if txn.Currency == "" {
+				txn.Currency = "INR"
+			}
+			if txn.Status == "" {
+				txn.Status = model.TransactionStatusCompleted
+			}
+			if txn.DaysAgo > 0 || txn.CreatedAt.IsZero() {
+				txn.CreatedAt = now.AddDate(0, 0, -txn.DaysAgo)
+			}
+		}
+
+		// Newest first, matching the DWH history ordering
+		sort.Slice(user.Transactions, func(a, b int) bool {
+			return user.Transactions[a].CreatedAt.After(user.Transactions[b].CreatedAt)
+		})
+
+		s.users[user.UserID] = &user
+		result.Users++
+		result.Accounts += len(user.Accounts)
+		result.Beneficiaries += len(user.Beneficiaries)
+		result.Transactions += len(user.Transactions)
+	}
+
+	log.Info().
+		Int("users", result.Users).
+		Int("accounts", result.Accounts).
+		Int("transactions", result.Transactions).
+		Bool("replace", replace).
+		Msg("Seed: Fixture loaded")
+
+	return result, nil
+}
+
+// Clear drops all seeded data
+func (s *SeedStore) Clear(ctx context.Context) *model.SeedResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &model.SeedResult{Users: len(s.users), Accounts: len(s.accounts)}
+	for _, user := range s.users {
+		result.Beneficiaries += len(user.Beneficiaries)
+		result.Transactions += len(user.Transactions)
+	}
+
+	s.users = make(map[string]*model.SeedUser)
+	s.accounts = make(map[string]*model.Account)
+
+	log.Info().Int("users", result.Users).Msg("Seed: Cleared seeded data")
+	return result
+}
+
+// User returns a copy of a seeded user
+func (s *SeedStore) User(userID string) (*model.SeedUser, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, false
+	}
+	userCopy := *user
+	return &userCopy, true
+}
+
+// UserIDs returns the IDs of all seeded users in order
+func (s *SeedStore) UserIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Account returns a copy of a seeded account
+func (s *SeedStore) Account(accountID string) (*model.Account, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	acct, ok := s.accounts[accountID]
+	if !ok {
+		return nil, false
+	}
+	acctCopy := *acct
+	return &acctCopy, true
+}
+
+// Beneficiary returns a seeded user's beneficiary for an account number
+func (s *SeedStore) Beneficiary(userID, accountNumber string) (*model.Beneficiary, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, false
+	}
+	for _, ben := range user.Beneficiaries {
+		if ben.AccountNumber == accountNumber {
+			return &ben, true
+		}
+	}
+	return nil, false
+}
+
+// Transactions returns seeded transactions for a user created after since.
+// The boolean is false when the user has not been seeded.
+func (s *SeedStore) Transactions(userID, accountID string, since, until time.Time) ([]model.Transaction, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, false
+	}
+
+	transactions := make([]model.Transaction, 0, len(user.Transactions))
+	for _, txn := range user.Transactions {
+		if accountID != "" && txn.AccountID != accountID {
+			continue
+		}
+		if txn.CreatedAt.Before(since) || (!until.IsZero() && txn.CreatedAt.After(until)) {
+			continue
+		}
+		transactions = append(transactions, txn.Transaction)
+	}
+	return transactions, true
+}
+
+// Transaction returns a seeded transaction by ID
+func (s *SeedStore) Transaction(transactionID string) (*model.Transaction, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		for _, txn := range user.Transactions {
+			if txn.TransactionID == transactionID {
+				found := txn.Transaction
+				return &found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// PostEntry applies a signed amount to a seeded account's balance and records the
+// ledger entry in the owner's history, returning the balances before and after. The
+// balance may not go negative.
+func (s *SeedStore) PostEntry(accountID string, amount float64, entry model.Transaction) (float64, float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acct, ok := s.accounts[accountID]
+	if !ok {
+		return 0, 0, fmt.Errorf("account %s not found", accountID)
+	}
+	user, ok := s.users[acct.UserID]
+	if !ok {
+		return 0, 0, fmt.Errorf("owner of account %s not found", accountID)
+	}
+
+	before := acct.Balance
+	after := before + amount
+	if after < 0 {
+		return before, before, fmt.Errorf("balance of %s would go negative: %.2f %+.2f", accountID, before, amount)
+	}
+
+	acct.Balance = after
+	acct.LastUpdated = entry.CreatedAt
+	entry.AccountID = accountID
+	entry.UserID = acct.UserID
+	user.Transactions = append(user.Transactions, model.SeedTransaction{Transaction: entry})
+	return before, after, nil
+}
+
+// checkAccountOwners refuses a fixture that gives a user an account already seeded
+// for another user, unless that user is reloaded by the same fixture. Caller holds
+// the lock.
+func (s *SeedStore) checkAccountOwners(fixture *model.SeedFixture) error {
+	reloaded := make(map[string]bool, len(fixture.Users))
+	for _, user := range fixture.Users {
+		reloaded[user.UserID] = true
+	}
+	for i, user := range fixture.Users {
+		for j, acct := range user.Accounts {
+			existing, ok := s.accounts[acct.AccountID]
+			if ok && existing.UserID != user.UserID && !reloaded[existing.UserID] {
+				return fmt.Errorf("users[%d].accounts[%d]: account_id %s is already seeded for user %s", i, j, acct.AccountID, existing.UserID)
+			}
+		}
+	}
+	return nil
+}
+
+// validateFixture checks that IDs are present and accounts are unique
+func validateFixture(fixture *model.SeedFixture) error {
+	if fixture == nil || len(fixture.Users) == 0 {
+		return fmt.Errorf("fixture contains no users")
+	}
+
+	seenUsers := make(map[string]bool)
+	seenAccounts := make(map[string]bool)
+	for i, user := range fixture.Users {
+		if user.UserID == "" {
+			return fmt.Errorf("users[%d]: user_id is required", i)
+		}
+		if seenUsers[user.UserID] {
+			return fmt.Errorf("users[%d]: duplicate user_id %s", i, user.UserID)
+		}
+		seenUsers[user.UserID] = true
+
+		for j, acct := range user.Accounts {
+			if acct.AccountID == "" {
+				return fmt.Errorf("users[%d].accounts[%d]: account_id is required", i, j)
+			}
+			if seenAccounts[acct.AccountID] {
+				return fmt.Errorf("users[%d].accounts[%d]: duplicate account_id %s", i, j, acct.AccountID)
+			}
+			if acct.Balance < 0 {
+				return fmt.Errorf("users[%d].accounts[%d]: balance cannot be negative", i, j)
+			}
+			seenAccounts[acct.AccountID] = true
+		}
+
+		for j, txn := range user.Transactions {
+			if txn.Amount <= 0 {
+				return fmt.Errorf("users[%d].transactions[%d]: amount must be positive", i, j)
+			}
+			if txn.DaysAgo < 0 {
+				return fmt.Errorf("users[%d].transactions[%d]: days_ago cannot be negative", i, j)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrTransferNotFound is returned for an idempotency key no transfer was made with
+	ErrTransferNotFound = errors.New("no transfer with this idempotency key")
+	// ErrTransferInProgress is returned while the transfer with a key is being made
+	ErrTransferInProgress = errors.New("the transfer with this idempotency key is still in progress")
+	// ErrIdempotencyConflict is returned for a key already used for another transfer
+	ErrIdempotencyConflict = errors.New("idempotency key was used for a different transfer")
+)
+
+// ledgerEntry is a transfer made with an idempotency key. The response is nil while
+// the transfer is in progress.
+type ledgerEntry struct {
+	fingerprint string
+	response    *model.TransferResponse
+	startedAt   time.Time
+}
+
+// TransferLedger remembers transfers by their idempotency key for
+// TRANSFER_IDEMPOTENCY_HOURS, so a caller that lost a transfer's response, such as an
+// agent that crashed mid-call, can send it again or look it up without moving money
+// twice. A transfer that fails with an error is forgotten, as no money moved; one the
+// connector rejected is kept with its response.
+//
+// The ledger is kept in memory until the banking database is wired in.
+type TransferLedger struct {
+	retention time.Duration
+	mu        sync.Mutex
+	entries   map[string]*ledgerEntry // Keyed by idempotency key
+}
+
+// NewTransferLedger creates an empty transfer ledger
+func NewTransferLedger(cfg *config.TransfersConfig) *TransferLedger {
+	return &TransferLedger{
+		retention: time.Duration(cfg.IdempotencyHours) * time.Hour,
+		entries:   make(map[string]*ledgerEntry),
+	}
+}
+
+// Begin claims the request's idempotency key. It returns the response of the transfer
+// already made with the key, ErrTransferInProgress while that transfer is being made
+// and ErrIdempotencyConflict when the key was used for another transfer; otherwise
+// nil, and the caller makes the transfer and calls Complete or Release.
+func (tl *TransferLedger) Begin(req *model.TransferRequest) (*model.TransferResponse, error) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.purge()
+
+	fingerprint := transferFingerprint(req)
+	if entry, ok := tl.entries[req.IdempotencyKey]; ok {
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, ErrIdempotencyConflict
+		case entry.response == nil:
+			return nil, ErrTransferInProgress
+		}
+		replay := *entry.response
+		replay.Replayed = true
+		log.Info().
+			Str("idempotency_key", req.IdempotencyKey).
+			Str("transaction_id", replay.TransactionID).
+			Msg("Repeated transfer answered from the ledger")
+		return &replay, nil
+	}
+
+	tl.entries[req.IdempotencyKey] = &ledgerEntry{fingerprint: fingerprint, startedAt: time.Now()}
+	return nil, nil
+}
+
+// Complete records the response of the transfer made with a key
+func (tl *TransferLedger) Complete(key string, resp *model.TransferResponse) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if entry, ok := tl.entries[key]; ok {
+		stored := *resp
+		entry.response = &stored
+	}
+}
+
+// Release forgets a key whose transfer failed before any money moved, so it can be
+// sent again
+func (tl *TransferLedger) Release(key string) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if entry, ok := tl.entries[key]; ok && entry.response == nil {
+		delete(tl.entries, key)
+	}
+}
+
+// Lookup returns the response of the transfer made with a key. ErrTransferNotFound
+// only means the ledger does not remember one: it is lost on a restart.
+func (tl *TransferLedger) Lookup(key string) (*model.TransferResponse, error) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.purge()
+
+	entry, ok := tl.entries[key]
+	if !ok {
+		return nil, ErrTransferNotFound
+	}
+	if entry.response == nil {
+		return nil, ErrTransferInProgress
+	}
+	resp := *entry.response
+	return &resp, nil
+}
+
+// purge forgets keys older than the retention. Callers hold tl.mu.
+func (tl *TransferLedger) purge() {
+	cutoff := time.Now().Add(-tl.retention)
+	for key, entry := range tl.entries {
+		if entry.startedAt.Before(cutoff) {
+			delete(tl.entries, key)
+		}
+	}
+}
+
+// transferFingerprint identifies what a transfer moves, so a key sent again with a
+// different transfer is refused
+func transferFingerprint(req *model.TransferRequest) string {
+	return fmt.Sprintf("%s|%s|%s|%.2f|%s|%t", req.UserID, req.FromAccount, req.ToAccount, req.Amount, req.Type, req.Sandbox)
+}
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aibanking/banking-integrations/internal/config"
+	"github.com/aibanking/banking-integrations/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// Webhook errors
+var (
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrDeliveryNotDead  = errors.New("only dead-lettered deliveries can be retried")
+)
+
+// Webhook request headers
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookID        = "X-Webhook-ID" // Event ID, the same on every retry
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// WebhookService lets external systems such as CRMs and fraud SIEMs subscribe to
+// banking events. Each event is posted to every subscription that lists its type,
+// signed with the subscription's secret. A failed delivery is retried with doubling
+// backoff and, once its attempts run out, kept in a dead-letter list until an
+// operator retries it. Subscriptions and deliveries are held in memory.
+type WebhookService struct {
+	cfg           *config.WebhooksConfig
+	httpClient    *http.Client
+	subscriptions map[string]*model.WebhookSubscription
+	deliveries    []*model.WebhookDelivery // Oldest first
+	mu            sync.Mutex
+}
+
+// NewWebhookService creates a webhook service
+func NewWebhookService(cfg *config.WebhooksConfig) *WebhookService {
+	return &WebhookService{
+		cfg:           cfg,
+		httpClient:    &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+		subscriptions: make(map[string]*model.WebhookSubscription),
+	}
+}
+
+// Subscribe creates a subscription and returns it with its secret
+func (ws *WebhookService) Subscribe(req *model.WebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+		return nil, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if endpoint.Scheme == "http" && !ws.cfg.AllowHTTP {
+		return nil, fmt.Errorf("url must use https")
+	}
+	if len(req.Events) == 0 {
+		return nil, fmt.Errorf("events is required, any of %v", model.WebhookEventTypes)
+	}
+	events := make([]string, 0, len(req.Events))
+	seen := make(map[string]bool)
+	for _, event := range req.Events {
+		if !knownEvent(event) {
+			return nil, fmt.Errorf("unknown event %q, expected any of %v", event, model.WebhookEventTypes)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < 16 {
+		return nil, fmt.Errorf("secret must be at least 16 characters")
+	}
+
+	sub := &model.WebhookSubscription{
+		ID:          ids.Ref("WHK_"),
+		URL:         req.URL,
+		Events:      events,
+		Secret:      secret,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+
+	ws.mu.Lock()
+	ws.subscriptions[sub.ID] = sub
+	ws.mu.Unlock()
+
+	log.Info().Str("subscription_id", sub.ID).Str("url", sub.URL).Strs("events", sub.Events).Msg("Webhook subscription created")
+	created := *sub
+	return &created, nil
+}
+
+// List returns the subscriptions, oldest first, optionally only those for one event
+// type. Secrets are left out.
+func (ws *WebhookService) List(event string) []model.WebhookSubscription {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	subs := []model.WebhookSubscription{}
+	for _, sub := range ws.subscriptions {
+		if event == "" || subscribes(sub, event) {
+			listed := *sub
+			listed.Secret = ""
+			subs = append(subs, listed)
+		}
+	}
+	sort.Slice(subs, func(a, b int) bool { return subs[a].ID < subs[b].ID })
+	return subs
+}
+
+// Get returns a subscription without its secret
+func (ws *WebhookService) Get(id string) (*model.WebhookSubscription, bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	sub, ok := ws.subscriptions[id]
+	if !ok {
+		return nil, false
+	}
+	found := *sub
+	found.Secret = ""
+	return &found, true
+}
+
+// Unsubscribe deletes a subscription. Deliveries already scheduled for it are dropped.
+func (ws *WebhookService) Unsubscribe(id string) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if _, ok := ws.subscriptions[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	delete(ws.subscriptions, id)
+	log.Info().Str("subscription_id", id).Msg("Webhook subscription deleted")
+	return nil
+}
+
+// Publish sends an event to every subscription for its type. Delivery happens in the
+// background; Publish never blocks the operation that raised the event.
+func (ws *WebhookService) Publish(eventType string, data interface{}) {
+	event := model.WebhookEvent{
+		ID:         ids.Ref("EVT_"),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("Failed to marshal webhook event")
+		return
+	}
+
+	ws.mu.Lock()
+	var queued []*model.WebhookDelivery
+	for _, sub := range ws.subscriptions {
+		if !subscribes(sub, eventType) {
+			continue
+		}
+		delivery := &model.WebhookDelivery{
+			ID:             ids.Ref("WHD_"),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			URL:            sub.URL,
+			Status:         model.DeliveryPending,
+			CreatedAt:      event.OccurredAt,
+			Payload:        payload,
+		}
+		ws.deliveries = append(ws.deliveries, delivery)
+		queued = append(queued, delivery)
+	}
+	ws.trim()
+	ws.mu.Unlock()
+
+	for _, delivery := range queued {
+		go ws.attempt(delivery.ID)
+	}
+}
+
+// Deliveries returns deliveries, newest first, optionally only for one subscription
+// or in one status
+func (ws *WebhookService) Deliveries(subscriptionID, status string, limit int) []model.WebhookDelivery {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	deliveries := []model.WebhookDelivery{}
+	for i := len(ws.deliveries) - 1; i >= 0 && (limit <= 0 || len(deliveries) < limit); i-- {
+		d := ws.deliveries[i]
+		if (subscriptionID == "" || d.SubscriptionID == subscriptionID) && (status == "" || d.Status == status) {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	return deliveries
+}
+
+// Retry sends a dead-lettered delivery again, with a fresh set of attempts
+func (ws *WebhookService) Retry(deliveryID string) (*model.WebhookDelivery, error) {
+	ws.mu.Lock()
+	delivery := ws.delivery(deliveryID)
+	if delivery == nil {
+		ws.mu.Unlock()
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status != model.DeliveryDead {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("%w: delivery %s is %s", ErrDeliveryNotDead, deliveryID, delivery.Status)
+	}
+	if _, ok := ws.subscriptions[delivery.SubscriptionID]; !ok {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrWebhookNotFound, delivery.SubscriptionID)
+	}
+	delivery.Status = model.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = nil
+	retried := *delivery
+	ws.mu.Unlock()
+
+	go ws.attempt(deliveryID)
+	return &retried, nil
+}
+
+// attempt posts a delivery once and schedules the next attempt, or dead-letters it,
+// when the endpoint does not answer 2xx
+func (ws *WebhookService) attempt(deliveryID string) {
+	ws.mu.Lock()
+	delivery := ws.delivery(deliveryID)
+	if delivery == nil {
+		ws.mu.Unlock()
+		return
+	}
+	sub, ok := ws.subscriptions[delivery.SubscriptionID]
+	if !ok {
+		ws.mu.Unlock()
+		return
+	}
+	secret, payload := sub.Secret, delivery.Payload
+	delivery.Attempts++
+	ws.mu.Unlock()
+
+	statusCode, err := ws.post(delivery, secret, payload)
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delivery.LastStatusCode = statusCode
+	delivery.NextAttemptAt = nil
+	if err == nil {
+		now := time.Now()
+		delivery.Status = model.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts >= ws.cfg.MaxAttempts {
+		delivery.Status = model.DeliveryDead
+		log.Warn().Err(err).Str("delivery_id", delivery.ID).Str("subscription_id", delivery.SubscriptionID).
+			Str("event", delivery.EventType).Int("attempts", delivery.Attempts).Msg("Webhook delivery dead-lettered")
+		return
+	}
+
+	backoff := ws.backoff(delivery.Attempts)
+	next := time.Now().Add(backoff)
+	delivery.Status = model.DeliveryRetrying
+	delivery.NextAttemptAt = &next
+	log.Debug().Err(err).Str("delivery_id", delivery.ID).Int("attempt", delivery.Attempts).
+		Dur("retry_in", backoff).Msg("Webhook delivery failed, retrying")
+	time.AfterFunc(backoff, func() { ws.attempt(deliveryID) })
+}
+
+// post sends the payload signed as HMAC-SHA256 of "<timestamp>.<payload>" with the
+// subscription's secret
+func (ws *WebhookService) post(delivery *model.WebhookDelivery, secret string, payload []byte) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ws.httpClient.Timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", delivery.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	httpReq.Header.Set(HeaderWebhookID, delivery.EventID)
+	httpReq.Header.Set(HeaderWebhookDelivery, delivery.ID)
+	httpReq.Header.Set(HeaderWebhookTimestamp, timestamp)
+	httpReq.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhook(secret, timestamp, payload))
+
+	resp, err := ws.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 a subscriber checks a payload against
+func SignWebhook(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the wait after a failed attempt: the configured backoff, doubled
+// for each attempt after the first, up to the maximum
+func (ws *WebhookService) backoff(attempts int) time.Duration {
+	wait := time.Duration(ws.cfg.RetryBackoff) * time.Second
+	max := time.Duration(ws.cfg.MaxBackoff) * time.Second
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// delivery finds a delivery by ID; the caller holds the lock
+func (ws *WebhookService) delivery(id string) *model.WebhookDelivery {
+	for i := len(ws.deliveries) - 1; i >= 0; i-- {
+		if ws.deliveries[i].ID == id {
+			return ws.deliveries[i]
+		}
+	}
+	return nil
+}
+
+// trim drops the oldest finished deliveries past the configured number; pending,
+// retrying and dead-lettered ones are kept. The caller holds the lock.
+func (ws *WebhookService) trim() {
+	excess := len(ws.deliveries) - ws.cfg.KeepDeliveries
+	if ws.cfg.KeepDeliveries <= 0 || excess <= 0 {
+		return
+	}
+	kept := ws.deliveries[:0]
+	for _, d := range ws.deliveries {
+		if excess > 0 && d.Status == model.DeliveryDelivered {
+			excess--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	ws.deliveries = kept
+}
+
+func subscribes(sub *model.WebhookSubscription, event string) bool {
+	for _, e := range sub.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func knownEvent(event string) bool {
+	for _, e := range model.WebhookEventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// Mock Layer 4 ML service used by the end-to-end scenarios. It serves the fraud and
+// scoring endpoints of the model registry with fixed, low-risk results so agent
+// decisions are deterministic, and counts calls on /__calls so scenarios can assert
+// that the agents really reached it.
+func main() {
+	addr := flag.String("addr", ":9000", "Address to listen on")
+	fraudScore := flag.Float64("fraud-score", 0.05, "Fraud score returned by /api/v1/fraud/predict")
+	creditScore := flag.Float64("credit-score", 742, "Credit score returned by /api/v1/scoring/credit")
+	flag.Parse()
+
+	m := &mockML{calls: make(map[string]int)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
+	})
+	mux.HandleFunc("/api/v1/fraud/predict", m.predict("fraud", map[string]interface{}{
+		"fraud_score":   *fraudScore,
+		"is_fraud":      false,
+		"model_version": "mock",
+	}))
+	mux.HandleFunc("/api/v1/scoring/credit", m.predict("credit", map[string]interface{}{
+		"credit_score":  *creditScore,
+		"model_version": "mock",
+	}))
+	mux.HandleFunc("/api/v1/scoring/risk", m.predict("risk", map[string]interface{}{
+		"overall_risk_score": 0.1,
+		"components":         map[string]float64{"fraud": *fraudScore, "credit": 0.1},
+		"model_version":      "mock",
+	}))
+	mux.HandleFunc("/__calls", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"calls": m.calls})
+	})
+
+	log.Printf("mock ML service listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+type mockML struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// predict answers a model endpoint with a fixed result in the ML service's envelope
+func (m *mockML) predict(name string, result map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var features map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&features); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": false, "error": err.Error()})
+			return
+		}
+
+		m.mu.Lock()
+		m.calls[name]++
+		m.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true, "result": result})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
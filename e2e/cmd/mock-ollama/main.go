@@ -0,0 +1,440 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mock Ollama host used by the end-to-end scenarios. It speaks the subset of the
+// Ollama API the AI Skin uses (/api/chat, /api/embed, /api/tags, /api/ps, /api/pull)
+// and answers deterministically: intent prompts get JSON classified by keywords,
+// chat requests with tools call get_statement or get_balance once and then answer
+// from the tool result, and embeddings are hashed from the text.
+func main() {
+	addr := flag.String("addr", ":11434", "Address to listen on")
+	models := flag.String("models", "llama3.1,nomic-embed-text", "Comma-separated models reported as pulled")
+	flag.Parse()
+
+	m := &mockOllama{models: splitList(*models)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "Ollama is running"})
+	})
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"version": "0.0.0-mock"})
+	})
+	mux.HandleFunc("/api/tags", m.handleTags)
+	mux.HandleFunc("/api/ps", m.handlePS)
+	mux.HandleFunc("/api/pull", m.handlePull)
+	mux.HandleFunc("/api/chat", m.handleChat)
+	mux.HandleFunc("/api/embed", m.handleEmbed)
+	mux.HandleFunc("/__calls", m.handleCalls)
+
+	log.Printf("mock Ollama listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+type mockOllama struct {
+	models []string
+
+	mu    sync.Mutex
+	calls map[string]int // Requests served per kind, reported on /__calls
+}
+
+type chatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+}
+
+type toolCall struct {
+	Function struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	} `json:"function"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Tools    []struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	} `json:"tools"`
+}
+
+func (m *mockOllama) count(kind string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[kind]++
+}
+
+func (m *mockOllama) handleCalls(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"calls": m.calls})
+}
+
+func (m *mockOllama) handleTags(w http.ResponseWriter, r *http.Request) {
+	models := make([]map[string]interface{}, 0, len(m.models))
+	for _, name := range m.models {
+		models = append(models, map[string]interface{}{
+			"name":        name,
+			"digest":      fmt.Sprintf("%x", fnvHash(name)),
+			"size":        1 << 30,
+			"modified_at": time.Now().UTC(),
+			"details":     map[string]string{"family": "mock"},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"models": models})
+}
+
+func (m *mockOllama) handlePS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"models": []interface{}{}})
+}
+
+func (m *mockOllama) handlePull(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func (m *mockOllama) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Input interface{} `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	m.count("embed")
+
+	var inputs []string
+	switch v := req.Input.(type) {
+	case string:
+		inputs = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			s, _ := item.(string)
+			inputs = append(inputs, s)
+		}
+	}
+
+	embeddings := make([][]float32, 0, len(inputs))
+	for _, text := range inputs {
+		embeddings = append(embeddings, embed(text, 64))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"embeddings": embeddings})
+}
+
+func (m *mockOllama) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var reply chatMessage
+	if len(req.Tools) > 0 {
+		m.count("chat_tools")
+		reply = answerWithTools(req)
+	} else {
+		m.count("chat")
+		reply = chatMessage{Role: "assistant", Content: answerPrompt(req.Messages)}
+	}
+	reply.Role = "assistant"
+
+	final := map[string]interface{}{
+		"model":             req.Model,
+		"created_at":        time.Now().UTC(),
+		"message":           reply,
+		"done":              true,
+		"done_reason":       "stop",
+		"prompt_eval_count": 20,
+		"eval_count":        len(strings.Fields(reply.Content)) + 1,
+	}
+	if !req.Stream {
+		writeJSON(w, http.StatusOK, final)
+		return
+	}
+
+	// Stream the content word by word, then the final chunk with the tool calls
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for i, word := range strings.SplitAfter(reply.Content, " ") {
+		if word == "" {
+			continue
+		}
+		enc.Encode(map[string]interface{}{
+			"model":   req.Model,
+			"message": chatMessage{Role: "assistant", Content: word},
+			"done":    false,
+		})
+		if flusher != nil && i%8 == 0 {
+			flusher.Flush()
+		}
+	}
+	final["message"] = chatMessage{Role: "assistant", ToolCalls: reply.ToolCalls}
+	enc.Encode(final)
+}
+
+// answerWithTools calls a tool for the question on the first round and answers from
+// the tool result once it is in the conversation
+func answerWithTools(req chatRequest) chatMessage {
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role == "tool" {
+		return chatMessage{Content: summarizeToolResult(last.Content)}
+	}
+
+	question := strings.ToLower(userInput(req.Messages))
+	offered := map[string]bool{}
+	for _, t := range req.Tools {
+		offered[t.Function.Name] = true
+	}
+
+	name := ""
+	switch {
+	case strings.Contains(question, "transaction") || strings.Contains(question, "statement") || strings.Contains(question, "spent"):
+		name = "get_statement"
+	case strings.Contains(question, "balance"):
+		name = "get_balance"
+	case strings.Contains(question, "beneficiar") || strings.Contains(question, "payee"):
+		name = "list_beneficiaries"
+	}
+	if name == "" || !offered[name] {
+		return chatMessage{Content: "I can help with balances, statements and beneficiaries."}
+	}
+
+	var call toolCall
+	call.Function.Name = name
+	call.Function.Arguments = map[string]interface{}{}
+	return chatMessage{ToolCalls: []toolCall{call}}
+}
+
+// summarizeToolResult phrases a tool result the way a model would, keeping the
+// figures the scenarios assert on
+func summarizeToolResult(content string) string {
+	var payload struct {
+		Status string                 `json:"status"`
+		Result map[string]interface{} `json:"result"`
+		Error  string                 `json:"error"`
+	}
+	// The AI Skin's prompt guard turns double quotes in tool results into single ones
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(strings.ReplaceAll(content[start:end+1], "'", `"`)), &payload) != nil {
+		return "I couldn't read the result from the bank."
+	}
+	if payload.Error != "" {
+		return "Sorry, " + payload.Error + "."
+	}
+
+	if txns, ok := payload.Result["transactions"].([]interface{}); ok {
+		if len(txns) == 0 {
+			return "You have no recent transactions."
+		}
+		txn, _ := txns[0].(map[string]interface{})
+		amount, _ := txn["amount"].(float64)
+		kind, _ := txn["type"].(string)
+		label, _ := txn["transaction_id"].(string)
+		if label == "" {
+			label, _ = txn["description"].(string)
+		}
+		return fmt.Sprintf("Your last transaction was a %s of INR %s (%s).", strings.ToLower(kind), formatAmount(amount), label)
+	}
+	if balance, ok := payload.Result["available_balance"].(float64); ok {
+		return fmt.Sprintf("Your available balance is INR %s.", formatAmount(balance))
+	}
+	if list, ok := payload.Result["beneficiaries"].([]interface{}); ok {
+		return fmt.Sprintf("You have %d beneficiaries registered.", len(list))
+	}
+	return "Done."
+}
+
+// narrateFigures words a statement summary's figures, quoting them as given
+func narrateFigures(prompt string) string {
+	var spent, received string
+	for _, line := range strings.Split(prompt, "\n") {
+		if value, ok := strings.CutPrefix(line, "Spent: "); ok {
+			spent = value
+		}
+		if value, ok := strings.CutPrefix(line, "Received: "); ok {
+			received = value
+		}
+	}
+	if spent == "" {
+		return "OK."
+	}
+	return fmt.Sprintf("You spent %s and received %s.", spent, received)
+}
+
+var (
+	accountPattern = regexp.MustCompile(`\b\d{9,18}\b`)
+	ifscPattern    = regexp.MustCompile(`(?i)\b[A-Z]{4}0[A-Z0-9]{6}\b`)
+	amountPattern  = regexp.MustCompile(`(?i)(?:rs\.?|inr|₹)?\s*(\d[\d,]*(?:\.\d+)?)`)
+	namePattern    = regexp.MustCompile(`(?i)(?:beneficiary|payee|to)\s+([A-Z][a-z]+(?:\s+[A-Z][a-z]+)?)`)
+)
+
+// answerPrompt answers the single-shot prompts: utterance splitting, intent
+// extraction and statement summaries. Anything else gets a short canned reply.
+func answerPrompt(messages []chatMessage) string {
+	prompt := ""
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			prompt = msg.Content
+		}
+	}
+	input := userInput(messages)
+
+	if strings.Contains(prompt, `"segments"`) {
+		out, _ := json.Marshal(map[string][]string{"segments": {input}})
+		return string(out)
+	}
+	if strings.Contains(prompt, "<figures>") {
+		return narrateFigures(prompt)
+	}
+	if !strings.Contains(prompt, `"intent"`) {
+		return "OK."
+	}
+
+	lower := strings.ToLower(input)
+	entities := map[string]interface{}{}
+	intent := "UNKNOWN"
+	confidence := 0.3
+	switch {
+	case strings.Contains(lower, "beneficiary") || strings.Contains(lower, "payee"):
+		intent, confidence = "ADD_BENEFICIARY", 0.95
+		if strings.Contains(lower, "list") || strings.Contains(lower, "show") {
+			intent = "LIST_BENEFICIARIES"
+		}
+	case strings.Contains(lower, "transfer") || strings.Contains(lower, "send") || strings.Contains(lower, "pay "):
+		intent, confidence = "TRANSFER_IMPS", 0.95
+		for _, rail := range []string{"NEFT", "RTGS", "UPI"} {
+			if strings.Contains(strings.ToUpper(input), rail) {
+				intent = "TRANSFER_" + rail
+			}
+		}
+	case strings.Contains(lower, "summar") || strings.Contains(lower, "did i spend"):
+		intent, confidence = "SUMMARIZE_STATEMENT", 0.95
+		for _, period := range []string{"last month", "this month", "last week", "this week"} {
+			if strings.Contains(lower, period) {
+				entities["period"] = period
+			}
+		}
+	case strings.Contains(lower, "statement") || strings.Contains(lower, "transactions"):
+		intent, confidence = "GET_STATEMENT", 0.95
+	case strings.Contains(lower, "balance"):
+		intent, confidence = "CHECK_BALANCE", 0.95
+	}
+
+	account := accountPattern.FindString(input)
+	if account != "" {
+		if intent == "ADD_BENEFICIARY" {
+			entities["account"] = account
+		} else {
+			entities["to_account"] = account
+		}
+	}
+	if ifsc := ifscPattern.FindString(input); ifsc != "" {
+		entities["ifsc"] = strings.ToUpper(ifsc)
+	}
+	if match := namePattern.FindStringSubmatch(input); match != nil && !strings.EqualFold(match[1], "account") {
+		entities["name"] = match[1]
+	}
+	if strings.HasPrefix(intent, "TRANSFER_") {
+		for _, match := range amountPattern.FindAllStringSubmatch(input, -1) {
+			digits := strings.ReplaceAll(match[1], ",", "")
+			if digits == account {
+				continue
+			}
+			if amount, err := strconv.ParseFloat(digits, 64); err == nil {
+				entities["amount"] = amount
+				break
+			}
+		}
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"intent":     intent,
+		"confidence": confidence,
+		"entities":   entities,
+	})
+	return string(out)
+}
+
+// userInput returns the text between the <user_input> tags of the last user message
+func userInput(messages []chatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		content := messages[i].Content
+		if messages[i].Role != "user" {
+			continue
+		}
+		// Prompts mention the tags in their instructions, so take the last pair
+		end := strings.LastIndex(content, "</user_input>")
+		start := strings.LastIndex(content[:max(end, 0)], "<user_input>")
+		if start >= 0 && end > start {
+			return strings.TrimSpace(content[start+len("<user_input>") : end])
+		}
+		return strings.TrimSpace(content)
+	}
+	return ""
+}
+
+// embed hashes words into a normalized vector so similar texts score as similar
+func embed(text string, dims int) []float32 {
+	vec := make([]float32, dims)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		vec[fnvHash(word)%uint32(dims)]++
+	}
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v * v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vec {
+			vec[i] *= scale
+		}
+	}
+	return vec
+}
+
+func fnvHash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aibanking/e2e/internal/scenario"
+)
+
+// Runs the end-to-end scenarios against a running stack (see e2e/docker-compose.yml)
+// and exits non-zero when any of them fails. go test -tags e2e runs the same scenarios
+// as TestScenarios.
+func main() {
+	stack := scenario.StackFromEnv()
+	dir := flag.String("dir", "scenarios", "Directory of scenario files")
+	run := flag.String("run", "", "Only run scenarios whose name matches this regexp")
+	skinURL := flag.String("skin", stack.Services["skin"], "AI Skin Orchestrator base URL")
+	mcpURL := flag.String("mcp", stack.Services["mcp"], "MCP Server base URL")
+	bankingURL := flag.String("banking", stack.Services["banking"], "Banking Integrations base URL")
+	ollamaURL := flag.String("ollama", stack.Services["ollama"], "Mock Ollama base URL")
+	mlURL := flag.String("ml", stack.Services["ml"], "Mock ML service base URL")
+	fraudURL := flag.String("fraud", stack.Services["fraud"], "Fraud Agent base URL")
+	flag.StringVar(&stack.APIKey, "api-key", stack.APIKey, "API key sent to every service")
+	flag.DurationVar(&stack.Wait, "wait", stack.Wait, "How long to wait for the services to become healthy")
+	flag.DurationVar(&stack.Timeout, "timeout", stack.Timeout, "Timeout per HTTP call")
+	asJSON := flag.Bool("json", false, "Print results as JSON")
+	flag.Parse()
+
+	scenarios, err := scenario.Load(*dir)
+	if err == nil {
+		scenarios, err = scenario.Filter(scenarios, *run)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	stack.Services = map[string]string{
+		"skin":    *skinURL,
+		"mcp":     *mcpURL,
+		"banking": *bankingURL,
+		"ollama":  *ollamaURL,
+		"ml":      *mlURL,
+		"fraud":   *fraudURL,
+	}
+	runner, err := stack.Connect(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	results := make([]*scenario.Result, 0, len(scenarios))
+	failed := 0
+	for _, sc := range scenarios {
+		result := runner.Run(context.Background(), sc)
+		results = append(results, result)
+		if !result.Passed {
+			failed++
+		}
+		if !*asJSON {
+			printResult(result)
+		}
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("\n%d scenarios, %d failed (run %s)\n", len(results), failed, runner.RunID())
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printResult prints a scenario's steps and the failures of the step that failed
+func printResult(r *scenario.Result) {
+	verdict := "PASS"
+	if !r.Passed {
+		verdict = "FAIL"
+	}
+	fmt.Printf("%s %s (%dms)\n", verdict, r.Scenario, r.DurationMs)
+	for _, step := range r.Steps {
+		switch {
+		case step.Skipped:
+			fmt.Printf("  - %-40s skipped\n", step.Name)
+		case step.Passed:
+			fmt.Printf("  ✓ %-40s %dms, %d attempt(s)\n", step.Name, step.DurationMs, step.Attempts)
+		default:
+			fmt.Printf("  ✗ %-40s %dms, %d attempt(s)\n", step.Name, step.DurationMs, step.Attempts)
+			for _, f := range step.Failures {
+				fmt.Printf("      %s\n", f)
+			}
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/aibanking/e2e
+
+go 1.21
@@ -0,0 +1,116 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxBodyInFailure caps how much of a response body a failure message quotes
+const maxBodyInFailure = 400
+
+// check returns every expectation the response does not meet
+func check(expect Expect, status int, body interface{}, raw []byte) []string {
+	var failures []string
+
+	want := expect.Status
+	if want == 0 {
+		want = http.StatusOK
+	}
+	if status != want {
+		failures = append(failures, fmt.Sprintf("status %d, want %d: %s", status, want, truncate(string(raw))))
+		return failures
+	}
+
+	for _, path := range sortedKeys(expect.Equals) {
+		got, ok := lookup(body, path)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s missing, want %v", path, expect.Equals[path]))
+		} else if !equalJSON(got, expect.Equals[path]) {
+			failures = append(failures, fmt.Sprintf("%s = %v, want %v", path, got, expect.Equals[path]))
+		}
+	}
+
+	for _, path := range sortedKeys(expect.Contains) {
+		got, ok := lookup(body, path)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s missing, want it to contain %q", path, expect.Contains[path]))
+			continue
+		}
+		text := fmt.Sprint(got)
+		if _, isString := got.(string); !isString {
+			data, _ := json.Marshal(got)
+			text = string(data)
+		}
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(expect.Contains[path])) {
+			failures = append(failures, fmt.Sprintf("%s = %s, want it to contain %q", path, truncate(text), expect.Contains[path]))
+		}
+	}
+
+	for _, path := range expect.Exists {
+		if got, ok := lookup(body, path); !ok || got == nil {
+			failures = append(failures, fmt.Sprintf("%s missing", path))
+		}
+	}
+
+	for _, path := range sortedKeys(expect.MinLength) {
+		got, _ := lookup(body, path)
+		list, ok := got.([]interface{})
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s is not a list", path))
+		} else if len(list) < expect.MinLength[path] {
+			failures = append(failures, fmt.Sprintf("%s has %d items, want at least %d", path, len(list), expect.MinLength[path]))
+		}
+	}
+
+	return failures
+}
+
+// lookup follows a dot-separated path through decoded JSON; numeric segments index lists
+func lookup(body interface{}, path string) (interface{}, bool) {
+	current := body
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// equalJSON compares values as JSON, so 500 and 500.0 are equal
+func equalJSON(a, b interface{}) bool {
+	return reflect.DeepEqual(toInterface(a), toInterface(b))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func truncate(s string) string {
+	if len(s) > maxBodyInFailure {
+		return s[:maxBodyInFailure] + "..."
+	}
+	return s
+}
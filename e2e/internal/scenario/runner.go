@@ -0,0 +1,232 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Runner runs scenarios against running services
+type Runner struct {
+	services   map[string]string // Service name -> base URL
+	apiKey     string
+	httpClient *http.Client
+	runID      string
+}
+
+// Result is the outcome of one scenario
+type Result struct {
+	Scenario   string       `json:"scenario"`
+	Passed     bool         `json:"passed"`
+	Steps      []StepResult `json:"steps"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+// StepResult is the outcome of one step
+type StepResult struct {
+	Name       string   `json:"name"`
+	Passed     bool     `json:"passed"`
+	Skipped    bool     `json:"skipped,omitempty"` // An earlier step failed
+	Attempts   int      `json:"attempts"`
+	DurationMs int64    `json:"duration_ms"`
+	Failures   []string `json:"failures,omitempty"`
+}
+
+// NewRunner creates a runner for the given service base URLs. The run ID keeps the
+// users and sessions of one run apart from earlier runs against the same services.
+func NewRunner(services map[string]string, apiKey string, timeout time.Duration, runID string) *Runner {
+	return &Runner{
+		services:   services,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+		runID:      runID,
+	}
+}
+
+// RunID returns the ID that keeps this run's users and sessions apart
+func (r *Runner) RunID() string {
+	return r.runID
+}
+
+// WaitHealthy polls GET /health on every configured service until all answer 200
+func (r *Runner) WaitHealthy(ctx context.Context) error {
+	for name, base := range r.services {
+		for {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"/health", nil)
+			resp, err := r.httpClient.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					break
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%s at %s is not healthy: %w", name, base, ctx.Err())
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	return nil
+}
+
+// Run runs a scenario's steps in order. A failed step skips the steps after it,
+// since they depend on what it should have done.
+func (r *Runner) Run(ctx context.Context, sc *Scenario) *Result {
+	start := time.Now()
+	slug := strings.Trim(nonWord.ReplaceAllString(strings.ToLower(sc.Name), "_"), "_")
+	vars := map[string]interface{}{
+		"run_id":     r.runID,
+		"user_id":    fmt.Sprintf("e2e_%s_%s", slug, r.runID),
+		"session_id": fmt.Sprintf("e2e_sess_%s_%s", slug, r.runID),
+	}
+
+	result := &Result{Scenario: sc.Name, Passed: true}
+	for _, step := range sc.Steps {
+		if !result.Passed {
+			result.Steps = append(result.Steps, StepResult{Name: step.Name, Skipped: true})
+			continue
+		}
+		sr := r.runStep(ctx, step, vars)
+		result.Steps = append(result.Steps, sr)
+		if !sr.Passed {
+			result.Passed = false
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// runStep calls a step, retrying while it is allowed to, and captures its variables
+func (r *Runner) runStep(ctx context.Context, step Step, vars map[string]interface{}) StepResult {
+	start := time.Now()
+	sr := StepResult{Name: step.Name}
+	window, _ := step.eventuallyFor()
+	deadline := start.Add(window)
+
+	for {
+		sr.Attempts++
+		body, failures := r.attempt(ctx, step, vars)
+		if len(failures) == 0 {
+			for name, path := range step.Capture {
+				value, ok := lookup(body, path)
+				if !ok {
+					failures = append(failures, fmt.Sprintf("capture %s: %s not in response", name, path))
+					continue
+				}
+				vars[name] = value
+			}
+		}
+		sr.Failures = failures
+
+		if len(failures) == 0 || time.Now().After(deadline) || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	sr.Passed = len(sr.Failures) == 0
+	sr.DurationMs = time.Since(start).Milliseconds()
+	return sr
+}
+
+// attempt makes one call and checks the response, returning the decoded body and
+// every expectation that did not hold
+func (r *Runner) attempt(ctx context.Context, step Step, vars map[string]interface{}) (interface{}, []string) {
+	base, ok := r.services[step.Service]
+	if !ok {
+		return nil, []string{fmt.Sprintf("unknown service %q", step.Service)}
+	}
+
+	var payload io.Reader
+	if step.Body != nil {
+		data, err := json.Marshal(substitute(step.Body, vars))
+		if err != nil {
+			return nil, []string{fmt.Sprintf("failed to encode body: %v", err)}
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	path, _ := substitute(step.Path, vars).(string)
+	req, err := http.NewRequestWithContext(ctx, step.Method, base+path, payload)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("failed to create request: %v", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", r.apiKey)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("%s %s failed: %v", step.Method, path, err)}
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+
+	var body interface{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			body = string(raw)
+		}
+	}
+
+	var expect Expect
+	if data, err := json.Marshal(substitute(toInterface(step.Expect), vars)); err == nil {
+		json.Unmarshal(data, &expect)
+	}
+	return body, check(expect, resp.StatusCode, body, raw)
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+// substitute replaces {{name}} references in strings nested anywhere in v. A string
+// that is exactly one reference takes the variable's value with its JSON type.
+func substitute(v interface{}, vars map[string]interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		if m := placeholder.FindStringSubmatch(t); m != nil && m[0] == t {
+			if value, ok := vars[m[1]]; ok {
+				return value
+			}
+			return t
+		}
+		return placeholder.ReplaceAllStringFunc(t, func(ref string) string {
+			name := placeholder.FindStringSubmatch(ref)[1]
+			if value, ok := vars[name]; ok {
+				return fmt.Sprint(value)
+			}
+			return ref
+		})
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			out[k] = substitute(item, vars)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = substitute(item, vars)
+		}
+		return out
+	}
+	return v
+}
+
+// toInterface converts a struct to its generic JSON form
+func toInterface(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
@@ -0,0 +1,128 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Scenario is a chained flow across the services: each step is an HTTP call whose
+// response is asserted on and may capture values for the steps after it
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Steps       []Step `json:"steps"`
+
+	File string `json:"-"`
+}
+
+// Step is one HTTP call. Path, body and expected values may reference variables as
+// {{name}}: user_id, session_id and run_id are set per run, anything else must have
+// been captured by an earlier step.
+type Step struct {
+	Name    string            `json:"name"`
+	Service string            `json:"service"` // skin, mcp, banking, fraud, ollama or ml
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Body    interface{}       `json:"body,omitempty"`
+	Expect  Expect            `json:"expect"`
+	Capture map[string]string `json:"capture,omitempty"` // Variable -> response path
+
+	// Eventually retries the step until its expectations hold or this long has
+	// passed, for effects that land asynchronously such as RAG embedding
+	Eventually string `json:"eventually,omitempty"`
+}
+
+// Expect lists what a step's response must satisfy. Paths are dot-separated and
+// index arrays by number, e.g. "tool_calls.0.name".
+type Expect struct {
+	Status    int                    `json:"status,omitempty"`     // HTTP status, 200 when unset
+	Equals    map[string]interface{} `json:"equals,omitempty"`     // Path -> JSON value
+	Contains  map[string]string      `json:"contains,omitempty"`   // Path -> substring, case-insensitive
+	Exists    []string               `json:"exists,omitempty"`     // Paths that must be present and not null
+	MinLength map[string]int         `json:"min_length,omitempty"` // Path -> minimum array length
+}
+
+// eventuallyFor returns how long a step may be retried, zero when it runs once
+func (s *Step) eventuallyFor() (time.Duration, error) {
+	if s.Eventually == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s.Eventually)
+}
+
+// Load reads every *.json scenario in dir, sorted by file name
+func Load(dir string) ([]*Scenario, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	scenarios := make([]*Scenario, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		var sc Scenario
+		if err := json.Unmarshal(data, &sc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		sc.File = file
+		if err := sc.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		scenarios = append(scenarios, &sc)
+	}
+	if len(scenarios) == 0 {
+		return nil, fmt.Errorf("no scenarios found in %s", dir)
+	}
+	return scenarios, nil
+}
+
+// Filter keeps the scenarios whose name matches a regexp; an empty pattern keeps all
+func Filter(scenarios []*Scenario, pattern string) ([]*Scenario, error) {
+	if pattern == "" {
+		return scenarios, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario pattern: %w", err)
+	}
+	var filtered []*Scenario
+	for _, sc := range scenarios {
+		if re.MatchString(sc.Name) {
+			filtered = append(filtered, sc)
+		}
+	}
+	return filtered, nil
+}
+
+// validate rejects scenarios that could never run
+func (sc *Scenario) validate() error {
+	if sc.Name == "" {
+		return fmt.Errorf("scenario needs a name")
+	}
+	if len(sc.Steps) == 0 {
+		return fmt.Errorf("scenario %s has no steps", sc.Name)
+	}
+	for i, step := range sc.Steps {
+		if step.Service == "" || step.Path == "" {
+			return fmt.Errorf("step %d of %s needs a service and path", i+1, sc.Name)
+		}
+		if _, err := step.eventuallyFor(); err != nil {
+			return fmt.Errorf("step %d of %s: invalid eventually: %w", i+1, sc.Name, err)
+		}
+		if step.Method == "" {
+			sc.Steps[i].Method = "GET"
+		}
+		sc.Steps[i].Method = strings.ToUpper(sc.Steps[i].Method)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package scenario
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Stack is the running services the scenarios are played against
+type Stack struct {
+	Services map[string]string // Service name -> base URL
+	APIKey   string            // Sent to every service
+	Wait     time.Duration     // How long to wait for the services to become healthy
+	Timeout  time.Duration     // Timeout per HTTP call
+}
+
+// StackFromEnv reads the service URLs and API key from the E2E_* variables, falling
+// back to the default local ports
+func StackFromEnv() Stack {
+	return Stack{
+		Services: map[string]string{
+			"skin":    env("E2E_SKIN_URL", "http://localhost:8081"),
+			"mcp":     env("E2E_MCP_URL", "http://localhost:8080"),
+			"banking": env("E2E_BANKING_URL", "http://localhost:7000"),
+			"ollama":  env("E2E_OLLAMA_URL", "http://localhost:11434"),
+			"ml":      env("E2E_ML_URL", "http://localhost:9000"),
+			"fraud":   env("E2E_FRAUD_AGENT_URL", "http://localhost:8002"),
+		},
+		APIKey:  env("E2E_API_KEY", "test-api-key"),
+		Wait:    2 * time.Minute,
+		Timeout: 60 * time.Second,
+	}
+}
+
+// Connect waits for every service to be healthy and returns a runner for a new run
+func (s Stack) Connect(ctx context.Context) (*Runner, error) {
+	runID := strconv.FormatInt(time.Now().Unix(), 36)
+	runner := NewRunner(s.Services, s.APIKey, s.Timeout, runID)
+
+	waitCtx, cancel := context.WithTimeout(ctx, s.Wait)
+	defer cancel()
+	if err := runner.WaitHealthy(waitCtx); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+func env(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,57 @@
+#!/bin/bash
+
+# Runs the end-to-end scenarios without Docker: builds every service and the mocks,
+# starts them on their default ports, runs the scenarios and stops everything again.
+# Redis is optional; the MCP Server falls back to memory without it.
+
+set -u
+cd "$(dirname "$0")"
+E2E_DIR=$(pwd)
+ROOT=$(cd .. && pwd)
+BIN="$E2E_DIR/bin"
+LOGS="$E2E_DIR/logs"
+mkdir -p "$BIN" "$LOGS"
+
+echo "Building services..."
+for svc in mcp-server ai-skin-orchestrator banking-integrations agent-mesh; do
+    (cd "$ROOT/$svc" && go build -o "$BIN/$svc" ./cmd/server) || exit 2
+done
+for cmd in mock-ollama mock-ml scenarios; do
+    go build -o "$BIN/$cmd" "./cmd/$cmd" || exit 2
+done
+
+PIDS=()
+cleanup() {
+    kill "${PIDS[@]}" 2>/dev/null
+    wait 2>/dev/null
+}
+trap cleanup EXIT
+
+# start <name> <dir> <env...> runs a binary from its module directory
+start() {
+    local name=$1 dir=$2 binary=$3
+    shift 3
+    (cd "$dir" && exec env LOGGING_LEVEL=warn "$@" "$BIN/$binary" > "$LOGS/$name.log" 2>&1) &
+    PIDS+=($!)
+}
+
+start mock-ollama "$E2E_DIR" mock-ollama
+start mock-ml "$E2E_DIR" mock-ml
+start banking-integrations "$ROOT/banking-integrations" banking-integrations SERVER_PORT=7000
+start mcp-server "$ROOT/mcp-server" mcp-server SERVER_PORT=8080
+for agent in BANKING:8001 FRAUD:8002 GUARDRAIL:8003; do
+    type=${agent%%:*}
+    port=${agent##*:}
+    start "agent-$type" "$ROOT/agent-mesh" agent-mesh AGENT_TYPE="$type" SERVER_PORT="$port" \
+        AGENT_ENDPOINT="http://localhost:$port" ML_SERVICE_URL=http://localhost:9000
+done
+start ai-skin-orchestrator "$ROOT/ai-skin-orchestrator" ai-skin-orchestrator SERVER_PORT=8081 \
+    LLM_ENABLED=true LLM_PROVIDER=ollama LLM_MODEL=llama3.1 LLM_ALLOWED_MODELS=llama3.1 \
+    OLLAMA_BASE_URL=http://localhost:11434 RAG_EMBEDDING_PROVIDER=ollama
+
+"$BIN/scenarios" -dir scenarios -wait 1m "$@"
+status=$?
+if [ $status -ne 0 ]; then
+    echo "Service logs are in $LOGS"
+fi
+exit $status
@@ -0,0 +1,40 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aibanking/e2e/internal/scenario"
+)
+
+// TestScenarios plays every scenario against a running stack, each as a subtest, so
+// go test -tags e2e -run 'TestScenarios/step_up' picks scenarios by name. The stack
+// is found through the E2E_* variables; run-local.sh and docker compose start one.
+func TestScenarios(t *testing.T) {
+	scenarios, err := scenario.Load("scenarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := scenario.StackFromEnv().Connect(context.Background())
+	if err != nil {
+		t.Fatalf("stack is not up: %v", err)
+	}
+	t.Logf("run %s", runner.RunID())
+
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.Name, func(t *testing.T) {
+			result := runner.Run(context.Background(), sc)
+			for _, step := range result.Steps {
+				if step.Passed || step.Skipped {
+					continue
+				}
+				t.Errorf("step %q failed after %d attempt(s):\n\t%s", step.Name, step.Attempts, strings.Join(step.Failures, "\n\t"))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// k6 load test for the AI Skin orchestration path (parse -> route -> agent -> merge)
+//
+// Usage:
+//   k6 run loadtest/k6/process.js
+//   k6 run -e SMOKE=1 loadtest/k6/process.js          # CI smoke-perf mode
+//   k6 run -e BASE_URL=http://skin:8081 loadtest/k6/process.js
+
+import http from 'k6/http';
+import { check } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8081';
+const API_KEY = __ENV.API_KEY || 'test-api-key';
+const SMOKE = __ENV.SMOKE === '1';
+
+export const options = SMOKE
+  ? {
+      vus: 2,
+      duration: '20s',
+      thresholds: {
+        http_req_failed: ['rate<0.01'],
+        'http_req_duration{intent:balance}': ['p(95)<3000'],
+        'http_req_duration{intent:transfer}': ['p(95)<3500'],
+      },
+    }
+  : {
+      scenarios: {
+        steady: {
+          executor: 'constant-arrival-rate',
+          rate: 20,
+          timeUnit: '1s',
+          duration: '2m',
+          preAllocatedVUs: 50,
+          maxVUs: 200,
+        },
+      },
+      thresholds: {
+        http_req_failed: ['rate<0.01'],
+        'http_req_duration{intent:balance}': ['p(95)<2500', 'p(99)<4000'],
+        'http_req_duration{intent:transfer}': ['p(95)<3000', 'p(99)<5000'],
+      },
+    };
+
+const requests = [
+  { tag: 'balance', input: 'What is my balance?' },
+  { tag: 'transfer', input: 'Transfer 5000 rupees to account XXXX4321 via NEFT' },
+  { tag: 'statement', input: 'Show my mini statement' },
+];
+
+export default function () {
+  const req = requests[Math.floor(Math.random() * requests.length)];
+  const payload = JSON.stringify({
+    user_id: `U${10000 + (__VU % 50)}`,
+    channel: 'MB',
+    input: req.input,
+    input_type: 'natural_language',
+  });
+
+  const res = http.post(`${BASE_URL}/api/v1/process`, payload, {
+    headers: { 'Content-Type': 'application/json', 'X-API-Key': API_KEY },
+    tags: { intent: req.tag },
+  });
+
+  check(res, {
+    'status is 200': (r) => r.status === 200,
+    'has status field': (r) => r.json('status') !== undefined,
+  });
+}
@@ -0,0 +1,27 @@
+#!/bin/bash
+
+# CI-runnable smoke-perf check.
+# 1. Runs the in-process hot-path benchmarks with per-operation budgets.
+# 2. If k6 is installed and the AI Skin is reachable, runs a short k6 pass
+#    with SLO thresholds.
+
+set -e
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+BASE_URL="${BASE_URL:-http://localhost:8081}"
+
+echo "==> Hot-path benchmarks (smoke)"
+(cd "$ROOT/ai-skin-orchestrator" && BENCH_BUDGETS=1 go test -run '^$' -bench . -benchtime 100ms -benchmem ./internal/service/)
+
+if ! command -v k6 >/dev/null 2>&1; then
+    echo "==> k6 not installed, skipping HTTP smoke pass"
+    exit 0
+fi
+
+if ! curl -sf "$BASE_URL/health" >/dev/null; then
+    echo "==> AI Skin not reachable at $BASE_URL, skipping HTTP smoke pass"
+    exit 0
+fi
+
+echo "==> k6 smoke pass against $BASE_URL"
+k6 run -e SMOKE=1 -e BASE_URL="$BASE_URL" "$ROOT/loadtest/k6/process.js"
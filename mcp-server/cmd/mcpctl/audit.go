@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aibanking/shared/audit"
+	"github.com/spf13/cobra"
+)
+
+// newAuditCmd works with the access logs written by AUDIT_SINK=file. These commands
+// run locally and never call a service.
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check access log files",
+	}
+
+	var verify struct {
+		key string
+	}
+	verifyCmd := &cobra.Command{
+		Use:   "verify [file...]",
+		Short: "Check the hash chain of access log files, read in order as one run, or stdin",
+		Long: "Check that no access record was removed, reordered or edited: every hash matches its\n" +
+			"record, sequence numbers follow on and each record names the hash of the one before.\n" +
+			"Give a service's daily files oldest first, e.g. audit/mcp-server-access-*.jsonl.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readers := []io.Reader{cmd.InOrStdin()}
+			if len(args) > 0 {
+				readers = readers[:0]
+				for _, path := range args {
+					f, err := os.Open(path)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					readers = append(readers, f)
+				}
+			}
+			count, err := audit.Verify(io.MultiReader(readers...), verify.key)
+			if err != nil {
+				return fmt.Errorf("chain broken after %d records: %w", count, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d records verified\n", count)
+			return nil
+		},
+	}
+	verifyCmd.Flags().StringVar(&verify.key, "key", os.Getenv("AUDIT_SIGNING_KEY"), "Signing key of the chain (defaults to $AUDIT_SIGNING_KEY)")
+	cmd.AddCommand(verifyCmd)
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiClient calls one of the platform's JSON admin APIs
+type apiClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// newAPIClient creates a client for the given base URL
+func newAPIClient(baseURL, apiKey string) *apiClient {
+	return &apiClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// mcpClient returns a client for the MCP server of the resolved profile
+func mcpClient(opts *globalOptions) (*apiClient, error) {
+	p, err := resolveProfile(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newAPIClient(p.MCPURL, p.APIKey), nil
+}
+
+// skinClient returns a client for the AI Skin Orchestrator of the resolved profile
+func skinClient(opts *globalOptions) (*apiClient, error) {
+	p, err := resolveProfile(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newAPIClient(p.SkinURL, p.APIKey), nil
+}
+
+// do sends a request and decodes the JSON response into out (if non-nil)
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			if apiErr.Details != "" {
+				return fmt.Errorf("%s %s: %d %s: %s", method, path, resp.StatusCode, apiErr.Error, apiErr.Details)
+			}
+			return fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, apiErr.Error)
+		}
+		return fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,913 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// newAgentsCmd builds the agent registry commands
+func newAgentsCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agents",
+		Aliases: []string{"agent"},
+		Short:   "Inspect registered agents",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List registered agents",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/agents", nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["agents"]),
+				[]string{"agent_id", "name", "type", "status", "endpoint"})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <agent-id>",
+		Short: "Show a registered agent",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/agent/"+url.PathEscape(args[0]), nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "diagnostics",
+		Short: "Show latency, fallback and downstream figures per agent type",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/agents/diagnostics", nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["agents"]),
+				[]string{"agent_type", "tasks", "p50_ms", "p95_ms", "p99_ms", "fallback_rate", "downstream_success_rate"})
+		},
+	})
+
+	return cmd
+}
+
+// newTaskCmd builds the task commands
+func newTaskCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "task",
+		Aliases: []string{"tasks"},
+		Short:   "Submit, inspect, cancel and requeue tasks",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <task-id>",
+		Short: "Show the status and result of a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/get-result/"+url.PathEscape(args[0]), nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "requeue <task-id>",
+		Short: "Re-route and re-execute a failed or rejected task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/task/"+url.PathEscape(args[0])+"/requeue", nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	var cancelReason string
+	cancelCmd := &cobra.Command{
+		Use:   "cancel <task-id>",
+		Short: "Cancel a task that has not finished",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			body := map[string]interface{}{"reason": cancelReason}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/task/"+url.PathEscape(args[0])+"/cancel", body, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	}
+	cancelCmd.Flags().StringVar(&cancelReason, "reason", "", "Why the task is cancelled, recorded as its error")
+	cmd.AddCommand(cancelCmd)
+
+	var submit struct {
+		userID    string
+		channel   string
+		intent    string
+		sessionID string
+		data      string
+		sandbox   bool
+	}
+	submitCmd := &cobra.Command{
+		Use:   "submit",
+		Short: "Submit a structured task to the MCP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data := map[string]interface{}{}
+			if submit.data != "" {
+				if err := json.Unmarshal([]byte(submit.data), &data); err != nil {
+					return fmt.Errorf("--data must be a JSON object: %w", err)
+				}
+			}
+
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"user_id":    submit.userID,
+				"channel":    submit.channel,
+				"intent":     submit.intent,
+				"session_id": submit.sessionID,
+				"data":       data,
+				"sandbox":    submit.sandbox,
+				"nonce":      uuid.New().String(),
+				"timestamp":  time.Now().UTC().Format(time.RFC3339Nano),
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/submit-task", body, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	}
+	submitCmd.Flags().StringVar(&submit.userID, "user", "", "User ID (required)")
+	submitCmd.Flags().StringVar(&submit.channel, "channel", "MB", "Channel")
+	submitCmd.Flags().StringVar(&submit.intent, "intent", "", "Intent, e.g. CHECK_BALANCE (required)")
+	submitCmd.Flags().StringVar(&submit.sessionID, "session", "", "Existing session ID")
+	submitCmd.Flags().StringVar(&submit.data, "data", "", "Task data as a JSON object")
+	submitCmd.Flags().BoolVar(&submit.sandbox, "sandbox", false, "Run the task in sandbox mode")
+	submitCmd.MarkFlagRequired("user")
+	submitCmd.MarkFlagRequired("intent")
+	cmd.AddCommand(submitCmd)
+
+	return cmd
+}
+
+// newSessionCmd builds the session commands
+func newSessionCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "session",
+		Aliases: []string{"sessions"},
+		Short:   "Inspect and clear sessions",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <session-id>",
+		Short: "Show a session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/get-session/"+url.PathEscape(args[0]), nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "clear <session-id>",
+		Aliases: []string{"delete"},
+		Short:   "Delete a session and its context",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodDelete, "/api/v1/session/"+url.PathEscape(args[0]), nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	return cmd
+}
+
+// newRulesCmd builds the routing rule commands
+func newRulesCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rules",
+		Aliases: []string{"rule"},
+		Short:   "List and upload routing rules",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List routing rules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/rules", nil, &resp); err != nil {
+				return err
+			}
+			rules, _ := resp["rules"].(map[string]interface{})
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), rules)
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, rules)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "upload <file.json>",
+		Short: "Upload routing rules from a JSON file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			raw, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read rules file: %w", err)
+			}
+
+			var rules map[string]interface{}
+			if err := json.Unmarshal(raw, &rules); err != nil {
+				return fmt.Errorf("rules file must be a JSON object: %w", err)
+			}
+
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/rules/upload", rules, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	return cmd
+}
+
+// newFlagsCmd builds the intent flag commands, the kill switches of intents
+func newFlagsCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "flags",
+		Aliases: []string{"flag"},
+		Short:   "Switch intents off, read-only or back on",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List intent flags",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/intents/flags", nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["flags"]), []string{"intent", "tenant_id", "channel", "mode", "reason", "updated_by", "updated_at"})
+		},
+	})
+
+	var set struct {
+		tenantID  string
+		channel   string
+		message   string
+		reason    string
+	}
+	setCmd := &cobra.Command{
+		Use:   "set <intent> <enabled|disabled|read_only>",
+		Short: "Set an intent's flag for everyone, a tenant, a channel or both",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"tenant_id": set.tenantID,
+				"channel":   set.channel,
+				"mode":      args[1],
+				"message":   set.message,
+				"reason":    set.reason,
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPut, "/api/v1/intents/"+url.PathEscape(strings.ToUpper(args[0]))+"/flag", body, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	}
+	setCmd.Flags().StringVar(&set.tenantID, "tenant", "", "Only for this tenant")
+	setCmd.Flags().StringVar(&set.channel, "channel", "", "Only for this channel")
+	setCmd.Flags().StringVar(&set.message, "message", "", "What users are told while the intent is off")
+	setCmd.Flags().StringVar(&set.reason, "reason", "", "Why, for operators (required)")
+	setCmd.MarkFlagRequired("reason")
+	cmd.AddCommand(setCmd)
+
+	var clear struct {
+		tenantID string
+		channel  string
+	}
+	clearCmd := &cobra.Command{
+		Use:   "clear <intent>",
+		Short: "Remove an intent's flag, leaving the intent to any wider flag",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if clear.tenantID != "" {
+				query.Set("tenant_id", clear.tenantID)
+			}
+			if clear.channel != "" {
+				query.Set("channel", clear.channel)
+			}
+			path := "/api/v1/intents/" + url.PathEscape(strings.ToUpper(args[0])) + "/flag"
+			if len(query) > 0 {
+				path += "?" + query.Encode()
+			}
+			if err := client.do(cmd.Context(), http.MethodDelete, path, nil, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Flag of %s removed\n", strings.ToUpper(args[0]))
+			return nil
+		},
+	}
+	clearCmd.Flags().StringVar(&clear.tenantID, "tenant", "", "The flag's tenant")
+	clearCmd.Flags().StringVar(&clear.channel, "channel", "", "The flag's channel")
+	cmd.AddCommand(clearCmd)
+
+	return cmd
+}
+
+// newSLACmd builds the latency SLA commands
+func newSLACmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sla",
+		Short: "Inspect end-to-end latency and SLA breaches per intent",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "Show latency percentiles and breaches per intent",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/sla/stats", nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["intents"]),
+				[]string{"intent", "tasks", "p50_ms", "p95_ms", "p99_ms", "threshold_ms", "breaches", "breach_reasons"})
+		},
+	})
+
+	var intent string
+	var limit int
+	breachesCmd := &cobra.Command{
+		Use:   "breaches",
+		Short: "List recent tasks that exceeded their SLA",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{"limit": {fmt.Sprint(limit)}}
+			if intent != "" {
+				query.Set("intent", intent)
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/sla/breaches?"+query.Encode(), nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["breaches"]),
+				[]string{"task_id", "intent", "total_ms", "threshold_ms", "breach_reason", "queue_ms", "agent_ms", "downstream_ms"})
+		},
+	}
+	breachesCmd.Flags().StringVar(&intent, "intent", "", "Only breaches of this intent")
+	breachesCmd.Flags().IntVar(&limit, "limit", 50, "Maximum breaches to list")
+	cmd.AddCommand(breachesCmd)
+
+	return cmd
+}
+
+// retentionTarget is a service holding data under retention, and where its retention
+// API is
+type retentionTarget struct {
+	name   string
+	prefix string
+	client *apiClient
+}
+
+// retentionTargets returns the MCP server and the AI Skin, which purge and hold their
+// own data
+func retentionTargets(opts *globalOptions) ([]retentionTarget, error) {
+	mcp, err := mcpClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	skin, err := skinClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return []retentionTarget{
+		{name: "mcp", prefix: "/api/v1/retention", client: mcp},
+		{name: "skin", prefix: "/api/v1/admin/retention", client: skin},
+	}, nil
+}
+
+// newRetentionCmd builds the data retention commands. Each applies to both the MCP
+// server and the AI Skin.
+func newRetentionCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Inspect retention policies, purge expired data and manage legal holds",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "policies",
+		Short: "Show how long each class of data is kept",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := retentionTargets(opts)
+			if err != nil {
+				return err
+			}
+
+			var rows []map[string]interface{}
+			for _, target := range targets {
+				var resp map[string]interface{}
+				if err := target.client.do(cmd.Context(), http.MethodGet, target.prefix+"/policies", nil, &resp); err != nil {
+					return fmt.Errorf("%s: %w", target.name, err)
+				}
+				for _, row := range toRows(resp["policies"]) {
+					row["service"] = target.name
+					rows = append(rows, row)
+				}
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, rows, []string{"service", "class", "days", "description"})
+		},
+	})
+
+	var dryRun bool
+	purgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Purge data past its retention now",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := retentionTargets(opts)
+			if err != nil {
+				return err
+			}
+
+			path := "/purge"
+			if dryRun {
+				path += "?dry_run=true"
+			}
+			var rows []map[string]interface{}
+			for _, target := range targets {
+				var resp map[string]interface{}
+				if err := target.client.do(cmd.Context(), http.MethodPost, target.prefix+path, nil, &resp); err != nil {
+					return fmt.Errorf("%s: %w", target.name, err)
+				}
+				for _, row := range toRows(resp["classes"]) {
+					row["service"] = target.name
+					rows = append(rows, row)
+				}
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, rows, []string{"service", "class", "cutoff", "purged", "held", "skipped"})
+		},
+	}
+	purgeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only report what would be purged")
+	cmd.AddCommand(purgeCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "holds",
+		Short: "List legal holds",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := retentionTargets(opts)
+			if err != nil {
+				return err
+			}
+
+			var rows []map[string]interface{}
+			for _, target := range targets {
+				var resp map[string]interface{}
+				if err := target.client.do(cmd.Context(), http.MethodGet, target.prefix+"/holds", nil, &resp); err != nil {
+					return fmt.Errorf("%s: %w", target.name, err)
+				}
+				for _, row := range toRows(resp["holds"]) {
+					row["service"] = target.name
+					rows = append(rows, row)
+				}
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, rows, []string{"service", "user_id", "reason", "placed_by", "placed_at"})
+		},
+	})
+
+	var hold struct {
+		reason   string
+		placedBy string
+	}
+	holdCmd := &cobra.Command{
+		Use:   "hold <user-id>",
+		Short: "Place a legal hold on a user's data",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := retentionTargets(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"reason":    hold.reason,
+				"placed_by": hold.placedBy,
+			}
+			for _, target := range targets {
+				if err := target.client.do(cmd.Context(), http.MethodPut, target.prefix+"/holds/"+url.PathEscape(args[0]), body, nil); err != nil {
+					return fmt.Errorf("%s: %w", target.name, err)
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Legal hold placed on %s\n", args[0])
+			return nil
+		},
+	}
+	holdCmd.Flags().StringVar(&hold.reason, "reason", "", "Why the data must be kept, e.g. a case reference (required)")
+	holdCmd.Flags().StringVar(&hold.placedBy, "by", "", "Who is placing the hold (required)")
+	holdCmd.MarkFlagRequired("reason")
+	holdCmd.MarkFlagRequired("by")
+	cmd.AddCommand(holdCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "release <user-id>",
+		Short: "Release a user's legal hold",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := retentionTargets(opts)
+			if err != nil {
+				return err
+			}
+
+			released := 0
+			for _, target := range targets {
+				err := target.client.do(cmd.Context(), http.MethodDelete, target.prefix+"/holds/"+url.PathEscape(args[0]), nil, nil)
+				if err == nil {
+					released++
+				} else if !strings.Contains(err.Error(), ": 404 ") {
+					return fmt.Errorf("%s: %w", target.name, err)
+				}
+			}
+			if released == 0 {
+				return fmt.Errorf("no legal hold on %s", args[0])
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Legal hold on %s released\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+// newDSARCmd builds the data-subject request commands
+func newDSARCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dsar",
+		Short: "Open, verify and process data-subject access and erasure requests",
+	}
+
+	var status string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List data-subject requests, oldest due first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			path := "/api/v1/dsar"
+			if status != "" {
+				path += "?status=" + url.QueryEscape(status)
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, path, nil, &resp); err != nil {
+				return err
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["requests"]),
+				[]string{"id", "user_id", "type", "status", "received_at", "due_at", "overdue"})
+		},
+	}
+	listCmd.Flags().StringVar(&status, "status", "", "Only requests with this status, e.g. VERIFIED")
+	cmd.AddCommand(listCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <request-id>",
+		Short: "Show a data-subject request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/dsar/"+url.PathEscape(args[0]), nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	var create struct {
+		userID     string
+		kind       string
+		regulation string
+		by         string
+		channel    string
+	}
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Open a data-subject request",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"user_id":      create.userID,
+				"type":         create.kind,
+				"regulation":   create.regulation,
+				"requested_by": create.by,
+				"channel":      create.channel,
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/dsar", body, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	}
+	createCmd.Flags().StringVar(&create.userID, "user", "", "The data subject's user ID (required)")
+	createCmd.Flags().StringVar(&create.kind, "type", "ACCESS", "ACCESS or ERASURE")
+	createCmd.Flags().StringVar(&create.regulation, "regulation", "", "The regulation invoked, e.g. DPDP or GDPR")
+	createCmd.Flags().StringVar(&create.by, "by", "", "Who made the request (required)")
+	createCmd.Flags().StringVar(&create.channel, "channel", "", "Where the request was received, e.g. branch or email")
+	createCmd.MarkFlagRequired("user")
+	createCmd.MarkFlagRequired("by")
+	cmd.AddCommand(createCmd)
+
+	var verify struct {
+		method    string
+		reference string
+	}
+	verifyCmd := &cobra.Command{
+		Use:   "verify <request-id>",
+		Short: "Record that the requester is the data subject",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"method":    verify.method,
+				"reference": verify.reference,
+			}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/dsar/"+url.PathEscape(args[0])+"/verify", body, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Request %s verified\n", args[0])
+			return nil
+		},
+	}
+	verifyCmd.Flags().StringVar(&verify.method, "method", "", "How identity was checked, e.g. OTP or branch_kyc (required)")
+	verifyCmd.Flags().StringVar(&verify.reference, "reference", "", "Reference of the check, e.g. an OTP transaction ID")
+	verifyCmd.MarkFlagRequired("method")
+	cmd.AddCommand(verifyCmd)
+
+	var reason string
+	rejectCmd := &cobra.Command{
+		Use:   "reject <request-id>",
+		Short: "Close a data-subject request without acting on it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{"reason": reason}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/dsar/"+url.PathEscape(args[0])+"/reject", body, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Request %s rejected\n", args[0])
+			return nil
+		},
+	}
+	rejectCmd.Flags().StringVar(&reason, "reason", "", "Why the request is rejected (required)")
+	rejectCmd.MarkFlagRequired("reason")
+	cmd.AddCommand(rejectCmd)
+
+	processCmd := &cobra.Command{
+		Use:   "process <request-id>",
+		Short: "Carry out a verified request and show what each store held",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/dsar/"+url.PathEscape(args[0])+"/process", nil, &resp); err != nil {
+				return err
+			}
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), resp)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Request %s is %v\n", args[0], resp["status"])
+			if msg, ok := resp["error"].(string); ok && msg != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Error: %s\n", msg)
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, toRows(resp["stores"]), []string{"store", "items", "outcome", "remaining", "note"})
+		},
+	}
+	cmd.AddCommand(processCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "export <request-id>",
+		Short: "Print the compiled export of a completed access request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := mcpClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var export json.RawMessage
+			if err := client.do(cmd.Context(), http.MethodGet, "/api/v1/dsar/"+url.PathEscape(args[0])+"/export", nil, &export); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(export))
+			return err
+		},
+	})
+
+	return cmd
+}
+
+// newSkinCmd builds the AI Skin Orchestrator commands
+func newSkinCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "skin",
+		Short: "Call the AI Skin Orchestrator",
+	}
+
+	var process struct {
+		userID    string
+		channel   string
+		sessionID string
+		sandbox   bool
+	}
+	processCmd := &cobra.Command{
+		Use:   "process <input>",
+		Short: "Send a natural language request through the full pipeline",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := skinClient(opts)
+			if err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{
+				"user_id":    process.userID,
+				"channel":    process.channel,
+				"input":      args[0],
+				"input_type": "natural_language",
+				"session_id": process.sessionID,
+				"sandbox":    process.sandbox,
+			}
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodPost, "/api/v1/process", body, &resp); err != nil {
+				return err
+			}
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), resp)
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, map[string]interface{}{
+				"status":      resp["status"],
+				"risk_score":  resp["risk_score"],
+				"explanation": resp["explanation"],
+				"simulated":   resp["simulated"],
+			})
+		},
+	}
+	processCmd.Flags().StringVar(&process.userID, "user", "U10001", "User ID")
+	processCmd.Flags().StringVar(&process.channel, "channel", "MB", "Channel")
+	processCmd.Flags().StringVar(&process.sessionID, "session", "", "Existing session ID")
+	processCmd.Flags().BoolVar(&process.sandbox, "sandbox", false, "Run the request in sandbox mode")
+	cmd.AddCommand(processCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "health",
+		Short: "Check AI Skin Orchestrator health",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := skinClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var resp map[string]interface{}
+			if err := client.do(cmd.Context(), http.MethodGet, "/health", nil, &resp); err != nil {
+				return err
+			}
+			return printObject(cmd.OutOrStdout(), opts.output, resp)
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+// Command mcpctl is an operator CLI for the MCP server and AI Skin
+// Orchestrator admin APIs.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// globalOptions holds flags shared by every command
+type globalOptions struct {
+	configPath string
+	profile    string
+	mcpURL     string
+	skinURL    string
+	apiKey     string
+	output     string
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCmd builds the command tree
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+
+	root := &cobra.Command{
+		Use:           "mcpctl",
+		Short:         "Admin CLI for the MCP server and AI Skin Orchestrator",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output != "json" && opts.output != "table" {
+				return fmt.Errorf("unsupported output format %q (use json or table)", opts.output)
+			}
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", defaultConfigPath(), "Path to the mcpctl config file")
+	flags.StringVarP(&opts.profile, "profile", "p", os.Getenv("MCPCTL_PROFILE"), "Environment profile to use (defaults to the current profile)")
+	flags.StringVar(&opts.mcpURL, "mcp-url", "", "Override the MCP server URL")
+	flags.StringVar(&opts.skinURL, "skin-url", "", "Override the AI Skin Orchestrator URL")
+	flags.StringVar(&opts.apiKey, "api-key", "", "Override the API key")
+	flags.StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+
+	root.AddCommand(
+		newProfileCmd(opts),
+		newAgentsCmd(opts),
+		newTaskCmd(opts),
+		newSessionCmd(opts),
+		newRulesCmd(opts),
+		newFlagsCmd(opts),
+		newSLACmd(opts),
+		newRetentionCmd(opts),
+		newDSARCmd(opts),
+		newSkinCmd(opts),
+		newSecretsCmd(),
+		newAuditCmd(),
+	)
+
+	return root
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// printRows renders a list either as indented JSON or as a table with the given columns
+func printRows(w io.Writer, format string, rows []map[string]interface{}, columns []string) error {
+	if format == "json" {
+		return printJSON(w, rows)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = formatValue(row[col])
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printObject renders a single object either as JSON or as a two-column key/value table
+func printObject(w io.Writer, format string, obj map[string]interface{}) error {
+	if format == "json" {
+		return printJSON(w, obj)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", k, formatValue(obj[k]))
+	}
+	return tw.Flush()
+}
+
+// printJSON writes v as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatValue renders a table cell; nested values are shown as compact JSON
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if val == "" {
+			return "-"
+		}
+		return val
+	case float64:
+		return fmt.Sprintf("%g", val)
+	case bool:
+		return fmt.Sprintf("%t", val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	}
+}
+
+// toRows converts a decoded JSON array into rows
+func toRows(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if row, ok := item.(map[string]interface{}); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds connection settings for one environment
+type Profile struct {
+	MCPURL  string `yaml:"mcp_url" json:"mcp_url"`
+	SkinURL string `yaml:"skin_url" json:"skin_url"`
+	APIKey  string `yaml:"api_key" json:"api_key,omitempty"`
+}
+
+// cliConfig is the on-disk mcpctl configuration
+type cliConfig struct {
+	Current  string              `yaml:"current"`
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// localProfile matches the default ports used by docker-compose and `make run`
+var localProfile = Profile{
+	MCPURL:  "http://localhost:8080",
+	SkinURL: "http://localhost:8081",
+	APIKey:  "dev-api-key",
+}
+
+// defaultConfigPath returns $MCPCTL_CONFIG or ~/.mcpctl.yaml
+func defaultConfigPath() string {
+	if path := os.Getenv("MCPCTL_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".mcpctl.yaml"
+	}
+	return filepath.Join(home, ".mcpctl.yaml")
+}
+
+// loadConfig reads the config file, returning a config with a "local" profile if it does not exist
+func loadConfig(path string) (*cliConfig, error) {
+	cfg := &cliConfig{Profiles: make(map[string]*Profile)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		local := localProfile
+		cfg.Current = "local"
+		cfg.Profiles["local"] = &local
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file with owner-only permissions (it holds API keys)
+func saveConfig(path string, cfg *cliConfig) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// resolveProfile applies the selected profile and any flag overrides
+func resolveProfile(opts *globalOptions) (*Profile, error) {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	name := opts.profile
+	if name == "" {
+		name = cfg.Current
+	}
+
+	resolved := localProfile
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in %s", name, opts.configPath)
+		}
+		resolved = *p
+	}
+
+	if opts.mcpURL != "" {
+		resolved.MCPURL = opts.mcpURL
+	}
+	if opts.skinURL != "" {
+		resolved.SkinURL = opts.skinURL
+	}
+	if opts.apiKey != "" {
+		resolved.APIKey = opts.apiKey
+	}
+	return &resolved, nil
+}
+
+// newProfileCmd builds the profile management commands
+func newProfileCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage environment profiles",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List configured profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(cfg.Profiles))
+			for name := range cfg.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			rows := make([]map[string]interface{}, 0, len(names))
+			for _, name := range names {
+				p := cfg.Profiles[name]
+				rows = append(rows, map[string]interface{}{
+					"name":     name,
+					"current":  name == cfg.Current,
+					"mcp_url":  p.MCPURL,
+					"skin_url": p.SkinURL,
+				})
+			}
+			return printRows(cmd.OutOrStdout(), opts.output, rows, []string{"name", "current", "mcp_url", "skin_url"})
+		},
+	})
+
+	var setProfile Profile
+	setCmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create or update a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+
+			p, ok := cfg.Profiles[args[0]]
+			if !ok {
+				local := localProfile
+				p = &local
+				cfg.Profiles[args[0]] = p
+			}
+			if cmd.Flags().Changed("mcp") {
+				p.MCPURL = setProfile.MCPURL
+			}
+			if cmd.Flags().Changed("skin") {
+				p.SkinURL = setProfile.SkinURL
+			}
+			if cmd.Flags().Changed("key") {
+				p.APIKey = setProfile.APIKey
+			}
+			if cfg.Current == "" {
+				cfg.Current = args[0]
+			}
+
+			if err := saveConfig(opts.configPath, cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Profile %q saved to %s\n", args[0], opts.configPath)
+			return nil
+		},
+	}
+	setCmd.Flags().StringVar(&setProfile.MCPURL, "mcp", "", "MCP server URL")
+	setCmd.Flags().StringVar(&setProfile.SkinURL, "skin", "", "AI Skin Orchestrator URL")
+	setCmd.Flags().StringVar(&setProfile.APIKey, "key", "", "API key")
+	cmd.AddCommand(setCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the current profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found", args[0])
+			}
+			cfg.Current = args[0]
+
+			if err := saveConfig(opts.configPath, cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Switched to profile %q\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aibanking/shared/secrets"
+	"github.com/spf13/cobra"
+)
+
+// newSecretsCmd works with the sealed secrets files read by SECRETS_PROVIDER=file.
+// These commands run locally and never call a service.
+func newSecretsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Create seal keys and sealed secrets files for SECRETS_PROVIDER=file",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "keygen",
+		Short: "Print a new seal key for SECRETS_SEAL_KEY",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := secrets.GenerateKey()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), key)
+			return nil
+		},
+	})
+
+	var seal struct {
+		file string
+		key  string
+	}
+	sealCmd := &cobra.Command{
+		Use:   "seal <setting>",
+		Short: "Seal a secret read from stdin, adding it to a secrets file or printing it",
+		Long: "Seal a secret read from stdin, so it never appears in shell history. With --file the\n" +
+			"sealed value is added to the file, replacing any value the setting had; replacing the\n" +
+			"file rotates the secret on each service's next refresh.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := secrets.ParseKey(seal.key)
+			if err != nil {
+				return fmt.Errorf("seal key: %w", err)
+			}
+			value, err := readSecret(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			sealed, err := secrets.Seal(key, value)
+			if err != nil {
+				return err
+			}
+			if seal.file == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), sealed)
+				return nil
+			}
+			if err := addSealed(seal.file, args[0], sealed); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Sealed %s into %s\n", args[0], seal.file)
+			return nil
+		},
+	}
+	sealCmd.Flags().StringVar(&seal.file, "file", "", "Secrets file to add the sealed value to; printed when empty")
+	sealCmd.Flags().StringVar(&seal.key, "key", os.Getenv("SECRETS_SEAL_KEY"), "Seal key (defaults to $SECRETS_SEAL_KEY)")
+	cmd.AddCommand(sealCmd)
+
+	var list struct {
+		file string
+		key  string
+	}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the settings in a secrets file, checking each opens with the seal key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := secrets.ParseKey(list.key)
+			if err != nil {
+				return fmt.Errorf("seal key: %w", err)
+			}
+			values, err := readSealed(list.file)
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				status := "ok"
+				if _, err := secrets.Open(key, values[name]); err != nil {
+					status = err.Error()
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-32s %s\n", name, status)
+			}
+			return nil
+		},
+	}
+	listCmd.Flags().StringVar(&list.file, "file", "", "Secrets file (required)")
+	listCmd.Flags().StringVar(&list.key, "key", os.Getenv("SECRETS_SEAL_KEY"), "Seal key (defaults to $SECRETS_SEAL_KEY)")
+	listCmd.MarkFlagRequired("file")
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// readSecret reads the first line of r, without its line ending
+func readSecret(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("no secret on stdin")
+	}
+	return value, nil
+}
+
+// readSealed reads a secrets file; a missing file is empty
+func readSealed(path string) (map[string]string, error) {
+	values := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// addSealed sets one setting in a secrets file, replacing the file in one step so a
+// service refreshing meanwhile reads either version whole
+func addSealed(path, setting, sealed string) error {
+	values, err := readSealed(path)
+	if err != nil {
+		return err
+	}
+	values[setting] = sealed
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -16,11 +16,18 @@ import (
 	"github.com/aibanking/mcp-server/internal/router"
 	"github.com/aibanking/mcp-server/internal/service"
 	"github.com/aibanking/mcp-server/internal/utils"
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/tz"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	// Store and send every time in UTC, whatever time zone the host runs in
+	tz.UseUTC()
+
 	// Initialize logger
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -29,7 +36,16 @@ func main() {
 
 	utils.InitLogger(cfg.Logging.Level, cfg.Logging.Format)
 
-	log.Info().Msg("Starting MCP Server for AI Banking Platform")
+	// Fail fast on settings that are missing or unsafe for the environment
+	problems := cfg.Validate()
+	for _, p := range problems {
+		log.Warn().Str("setting", p.Key).Str("severity", p.Severity).Msg(p.Message)
+	}
+	if profile.HasErrors(problems) {
+		log.Fatal().Str("environment", cfg.Environment).Msg("Invalid configuration, run cmd/config-lint for details")
+	}
+
+	log.Info().Str("environment", cfg.Environment).Msg("Starting MCP Server for AI Banking Platform")
 
 	// Initialize Redis client
 	redisClient := redis.NewClient(&redis.Options{
@@ -50,18 +66,79 @@ func main() {
 	sessionManager := service.NewSessionManager(redisClient)
 	taskManager := service.NewTaskManager(redisClient)
 	agentRegistry := service.NewAgentRegistry(redisClient)
+	agentRegistry.SetTenantPools(service.NewTenantPools(&cfg.TenantPools))
 	ruleEngine := service.NewRuleEngine()
-	contextRouter := service.NewContextRouter(agentRegistry, ruleEngine)
-	orchestrator := service.NewOrchestrator(sessionManager, taskManager, agentRegistry, contextRouter)
+	intentRegistry := service.NewIntentRegistry(redisClient)
+	intentFlags := service.NewIntentFlags(redisClient)
+	contextRouter := service.NewContextRouter(agentRegistry, ruleEngine, intentRegistry)
+	contextRouter.SetFastPath(service.NewFastPath(&cfg.FastPath))
+	executionQueue := service.NewExecutionQueue(&cfg.Queue)
+	slaTracker := service.NewSLATracker(&cfg.SLA)
+	nonceStore := service.NewNonceStore(&cfg.Replay, redisClient)
+	stepUpAuth, err := service.NewStepUpAuth(&cfg.StepUp)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load step-up auth policy")
+	}
+	deviceProfiles := service.NewDeviceProfiles(&cfg.Devices, redisClient)
+	holdQueue := service.NewAgentHoldQueue(&cfg.Hold, agentRegistry)
+	agentWarmer := service.NewAgentWarmer(&cfg.Warmup, service.NewIntentHistories(&cfg.Warmup, redisClient), agentRegistry)
+	planStore := service.NewPlanStore(redisClient)
+	// In demo mode tasks playing a scenario get the agent outcomes it forces
+	var scenarios *demo.Library
+	if cfg.Demo.Enabled {
+		scenarios, err = demo.Load(cfg.Demo.ScenariosDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load demo scenarios")
+		}
+		for _, scenario := range scenarios.List() {
+			if scenario.Plan != "" && !planStore.Exists(scenario.Plan) {
+				log.Fatal().Str("scenario", scenario.ID).Str("plan_id", scenario.Plan).Msg("Demo scenario names an unknown orchestration plan")
+			}
+		}
+		contextRouter.SetDemo(scenarios)
+		log.Warn().Int("scenarios", len(scenarios.List())).Msg("Demo mode is on; tasks playing a demo scenario get scripted outcomes")
+	}
+	orchestrator := service.NewOrchestrator(sessionManager, taskManager, agentRegistry, contextRouter, executionQueue, slaTracker, nonceStore, stepUpAuth, deviceProfiles, holdQueue, agentWarmer, planStore, service.NewTransferSplitter(&cfg.Split), intentFlags, scenarios)
+
+	// Initialize draining and handover of tasks across deploys
+	drainer := service.NewDrainer(&cfg.Drain, orchestrator, taskManager, redisClient)
 
 	// Initialize controllers
-	taskController := controller.NewTaskController(orchestrator, taskManager)
+	taskController := controller.NewTaskController(orchestrator, taskManager, cfg.Stateless.Enabled, time.Duration(cfg.Stateless.WaitSeconds)*time.Second)
 	agentController := controller.NewAgentController(agentRegistry)
 	sessionController := controller.NewSessionController(sessionManager)
-	ruleController := controller.NewRuleController(ruleEngine)
+	ruleController := controller.NewRuleController(ruleEngine, planStore)
+	authController := controller.NewAuthController(orchestrator, stepUpAuth)
+	deviceController := controller.NewDeviceController(deviceProfiles)
+	warmupController := controller.NewWarmupController(agentWarmer)
+	planController := controller.NewPlanController(planStore, ruleEngine)
+	intentController := controller.NewIntentController(intentRegistry, intentFlags)
+
+	// Initialize alerting
+	alertManager := service.NewAlertManager(&cfg.Alerts, service.NewAlertSinks(&cfg.Alerts), orchestrator, agentRegistry, redisClient)
+	alertController := controller.NewAlertController(alertManager, cfg.Alerts.Enabled)
+
+	// Initialize retention
+	retentionManager := service.NewRetentionManager(&cfg.Retention, taskManager, alertManager, redisClient)
+	retentionController := controller.NewRetentionController(retentionManager, cfg.Retention.Enabled)
+	taskCompactor := service.NewTaskCompactor(&cfg.Compaction, taskManager, retentionManager.IsHeld)
+
+	// Initialize data-subject requests
+	dsarService := service.NewDSARService(&cfg.DSAR, sessionManager, taskManager, retentionManager, redisClient)
+	dsarController := controller.NewDSARController(dsarService)
+
+	// Initialize reconciliation against the DWH
+	reconciler := service.NewReconciler(&cfg.Reconcile, taskManager)
+	reconcileController := controller.NewReconciliationController(reconciler, cfg.Reconcile.AutoCorrect)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter()
+	recovery := middleware.NewRecovery(cfg.Recovery)
+	auditLogger, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatal().Err(err).Str("sink", cfg.Audit.Sink).Msg("Failed to open access log")
+	}
+	accessLog := middleware.NewAccessLog(auditLogger, cfg.Security.APIKeyHeader)
 
 	// Initialize router
 	appRouter := router.NewRouter(
@@ -69,7 +146,19 @@ func main() {
 		agentController,
 		sessionController,
 		ruleController,
+		alertController,
+		retentionController,
+		dsarController,
+		authController,
+		deviceController,
+		warmupController,
+		planController,
+		reconcileController,
+		intentController,
 		rateLimiter,
+		recovery,
+		accessLog,
+		middleware.NewAdminAuth(&cfg.RBAC),
 	)
 
 	// Setup routes
@@ -98,12 +187,40 @@ func main() {
 	// Register default agents (for testing/demo)
 	registerDefaultAgents(ctx, agentRegistry)
 
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go drainer.Heartbeat(backgroundCtx)
+
+	// Pick up the tasks a stopped instance left unfinished, now agents are registered
+	drainer.Recover(ctx)
+
+	if cfg.Alerts.Enabled {
+		go alertManager.Run(backgroundCtx)
+	}
+	if cfg.Retention.Enabled {
+		go retentionManager.Run(backgroundCtx)
+	}
+	if cfg.Compaction.Enabled {
+		go taskCompactor.Run(backgroundCtx)
+	}
+	if cfg.Warmup.Enabled {
+		go agentWarmer.Run(backgroundCtx)
+	}
+	if cfg.Reconcile.Enabled {
+		go reconciler.Run(backgroundCtx)
+	}
+	go config.Profile().WatchSecrets(backgroundCtx, cfg.Secrets.RefreshInterval)
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Info().Msg("Shutting down server...")
+	log.Info().Str("instance", drainer.Instance()).Msg("Shutting down server...")
+
+	// Refuse new tasks and wait for running ones while clients can still poll them
+	stopBackground()
+	drainer.Drain(context.Background())
 
 	// Graceful shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -113,6 +230,11 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// After the server, so the last requests it finished are recorded
+	if err := auditLogger.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close access log")
+	}
+
 	log.Info().Msg("Server exited")
 }
 
@@ -154,6 +276,12 @@ func registerDefaultAgents(ctx context.Context, registry *service.AgentRegistry)
 			endpoint:     "http://localhost:8005",
 			capabilities: []string{"CREDIT_SCORE", "RISK_SCORE"},
 		},
+		{
+			name:         "Insights Agent",
+			agentType:    "INSIGHTS",
+			endpoint:     "http://localhost:8006",
+			capabilities: []string{"GET_INSIGHTS"},
+		},
 	}
 
 	for _, agentDef := range defaultAgents {
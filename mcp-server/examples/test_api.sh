@@ -32,6 +32,9 @@ echo ""
 
 # 3. Submit Task - NEFT Transfer
 echo "3. Submitting NEFT Transfer Task"
+# Transfers carry a unique nonce and the time they were made, see REPLAY_PROTECTION_ENABLED
+NONCE="test-api-$(date +%s)-$RANDOM$RANDOM"
+TIMESTAMP=$(date -u +%Y-%m-%dT%H:%M:%SZ)
 TASK_RESPONSE=$(curl -s -X POST "$BASE_URL/submit-task" \
   -H "Content-Type: application/json" \
   -H "X-API-Key: $API_KEY" \
@@ -44,7 +47,9 @@ TASK_RESPONSE=$(curl -s -X POST "$BASE_URL/submit-task" \
       \"amount\": 50000,
       \"to_account\": \"XXXX4321\",
       \"ifsc\": \"BANK0001234\"
-    }
+    },
+    \"nonce\": \"$NONCE\",
+    \"timestamp\": \"$TIMESTAMP\"
   }")
 echo "$TASK_RESPONSE" | jq .
 TASK_ID=$(echo "$TASK_RESPONSE" | jq -r '.task_id')
@@ -1,21 +1,117 @@
 package config
 
 import (
-	"log"
-	"os"
+	"strconv"
+	"strings"
 
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/profile"
+	"github.com/aibanking/shared/recovery"
+	"github.com/aibanking/shared/secrets"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Security SecurityConfig
-	Logging  LoggingConfig
-	Agents   AgentsConfig
+	Environment string // dev, staging or prod; see Validate
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Security    SecurityConfig
+	RBAC        RBACConfig
+	Logging     LoggingConfig
+	Recovery    recovery.Config
+	Agents      AgentsConfig
+	Queue       QueueConfig
+	Drain       DrainConfig
+	Hold        HoldConfig
+	Alerts      AlertsConfig
+	SLA         SLAConfig
+	Replay      ReplayConfig
+	Retention   RetentionConfig
+	Compaction  CompactionConfig
+	DSAR        DSARConfig
+	StepUp      StepUpConfig
+	Devices     DeviceTrustConfig
+	Warmup      WarmupConfig
+	Reconcile   ReconcileConfig
+	FastPath    FastPathConfig
+	Split       SplitConfig
+	Stateless   StatelessConfig
+	TenantPools TenantPoolConfig
+	Secrets     secrets.Config
+	Audit       audit.Config
+	Demo        demo.Config
+}
+
+// Tenant pool policies: when a tenant's tasks may use the shared pool of agents that
+// are dedicated to no tenant
+const (
+	TenantPoolOverflow  = "overflow"  // When none of the tenant's own agents is healthy, or all are busy
+	TenantPoolFallback  = "fallback"  // Only when none of the tenant's own agents is healthy
+	TenantPoolDedicated = "dedicated" // Never; with no agent of its own the task waits or fails
+)
+
+// TenantPoolConfig holds how agents dedicated to a tenant are shared. A dedicated
+// agent only ever serves its tenant; a tenant's tasks go to its own agents first and
+// to the shared pool as its policy allows. Tasks of tenants without agents of their
+// own use the shared pool.
+type TenantPoolConfig struct {
+	Policy        string            // Policy of tenants without their own
+	Policies      map[string]string // Policy by tenant
+	AgentCapacity int               // Tasks an agent works on at once before it counts as busy; 0 never does
+}
+
+// StatelessConfig holds the sessionless mode for server-to-server callers: no session
+// is created, the task is kept in memory only, and the submission waits for the result
+type StatelessConfig struct {
+	Enabled     bool
+	WaitSeconds int // How long a stateless submission waits for its result before returning the task to poll
+}
+
+// SplitConfig holds the limits of each payment rail and the splitting of transfers
+// above them: a transfer larger than its rail allows at once is offered to the user as
+// legs across rails and days, and runs as a saga once the user confirms
+type SplitConfig struct {
+	Enabled            bool
+	PerTransfer        map[string]float64 // Largest single transfer per rail, e.g. IMPS=200000
+	Daily              map[string]float64 // Most a user may send per rail per day
+	Rails              []string           // Rails legs may use besides the one asked for, in order of preference
+	MaxLegs            int
+	MaxDays            int    // Days legs may be spread over, today included
+	ProposalTTLSeconds int    // A split not confirmed within this is withdrawn
+	LaterDayHour       int    // Hour of day legs on later days run
+	Timezone           string // Time zone of days and LaterDayHour; UTC when empty
+}
+
+// FastPathConfig holds the fast path that sends small transfers to known
+// beneficiaries from low-risk devices straight to the banking agent, skipping the
+// guardrail and fraud checks
+type FastPathConfig struct {
+	Enabled            bool
+	MaxAmount          float64  // Largest transfer that may take the fast path
+	MaxRiskScore       float64  // Highest profiled device risk that may take the fast path
+	MinBeneficiaryDays int      // Days a beneficiary must have been registered to count as known
+	Intents            []string // Transfer intents the fast path applies to
+	KeepRecords        int      // Fast-path decisions kept for the audit trail
+	BankingURL         string   // Banking integration beneficiaries are looked up in
+	BankingAPIKey      string
+}
+
+// ReconcileConfig holds the nightly reconciliation of transfer tasks against DWH
+// transactions. Tasks finished in the LookbackHours before the run, less the last
+// GraceMinutes the DWH may not have caught up with yet, are checked.
+type ReconcileConfig struct {
+	Enabled       bool
+	Hour          int    // Hour of day the run starts
+	Timezone      string // Time zone of Hour; UTC when empty
+	LookbackHours int
+	GraceMinutes  int
+	AutoCorrect   bool // Correct the status of tasks the DWH contradicts
+	BankingURL    string
+	BankingAPIKey string
 }
 
 // ServerConfig holds server-related configuration
@@ -53,6 +149,12 @@ type SecurityConfig struct {
 	RateLimitRPS int
 }
 
+// RBACConfig grants roles to API keys. Endpoints that need a role are refused to
+// every key when no key has been granted it.
+type RBACConfig struct {
+	Admins map[string]string // API key -> operator ID
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level  string
@@ -61,17 +163,153 @@ type LoggingConfig struct {
 
 // AgentsConfig holds agent-related configuration
 type AgentsConfig struct {
-	DefaultTimeout int
+	DefaultTimeout      int
 	HealthCheckInterval int
 }
 
+// QueueConfig holds back-pressure thresholds for task execution. Once HighWatermark
+// tasks are in flight new submissions are refused until the depth falls to LowWatermark.
+type QueueConfig struct {
+	HighWatermark int
+	LowWatermark  int
+	RetryAfter    int // Seconds clients are told to wait when refused
+}
+
+// DrainConfig holds zero-downtime deploys: on shutdown new tasks are refused and
+// running ones are waited for; those still unfinished are handed over, and the next
+// instance resumes or fails them over when it starts
+type DrainConfig struct {
+	TimeoutSeconds   int    // How long shutdown waits for running tasks
+	HandoverFile     string // Unfinished tasks are written here when tasks are not in Redis
+	HeartbeatSeconds int    // How often the instance renews its liveness in Redis
+	RecoverOnStart   bool   // Resume or fail over tasks a stopped instance left unfinished
+}
+
+// HoldConfig holds the hold queue for tasks whose agent type has no healthy agent.
+// Such tasks wait up to MaxWaitSeconds for one to register or recover instead of
+// failing at once.
+type HoldConfig struct {
+	Enabled        bool
+	MaxWaitSeconds int
+	MaxTasks       int // Tasks held at once; further tasks fail as before
+	PollIntervalMs int // How often the registry is checked for a returning agent
+}
+
+// AlertsConfig holds the alert conditions and where alerts are sent. A sink is used
+// when its URL or address is set.
+type AlertsConfig struct {
+	Enabled          bool
+	CheckInterval    int // Seconds between checks
+	RepeatMinutes    int // A still-firing alert is sent again after this long; 0 sends it once
+	FallbackRate     float64
+	FallbackMinTasks int
+	RedisDownMinutes int
+	AgentHealth      bool // Probe each registered agent's /health
+	SkinURL          string
+	SkinAPIKey       string
+	LLMErrorRate     float64
+	LLMMinCalls      int
+	WebhookURL       string
+	SlackWebhookURL  string
+	EmailSMTPAddr    string // host:port
+	EmailUsername    string
+	EmailPassword    string
+	EmailFrom        string
+	EmailTo          []string
+}
+
+// SLAConfig holds the end-to-end latency each intent should complete within
+type SLAConfig struct {
+	DefaultMs  int            // For intents without their own threshold
+	Thresholds map[string]int // Milliseconds per intent
+}
+
+// ReplayConfig holds replay protection for money-moving submissions, which must carry
+// a nonce and a timestamp within MaxSkewSeconds of the server clock
+type ReplayConfig struct {
+	Enabled        bool
+	MaxSkewSeconds int
+}
+
+// RetentionConfig holds how many days each class of data is kept (0 keeps it forever)
+// and how often expired data is purged
+type RetentionConfig struct {
+	Enabled         bool // Purge on a schedule; manual purges work regardless
+	IntervalMinutes int
+	TasksDays       int
+	AuditDays       int
+}
+
+// CompactionConfig holds the eviction of finished tasks from memory. Evicted tasks
+// are still read from Redis, where they stay until their TTL.
+type CompactionConfig struct {
+	Enabled         bool
+	IntervalSeconds int // How often memory is compacted
+	AgeMinutes      int // Finished tasks are evicted this long after they finished
+}
+
+// DSARConfig holds the data-subject request workflow: the response deadline, the key
+// completion records are signed with, and the services a user's data is gathered from
+type DSARConfig struct {
+	DeadlineDays  int
+	SigningKey    string
+	SkinURL       string
+	SkinAPIKey    string
+	BankingURL    string
+	BankingAPIKey string
+	HistoryDays   int // How far back DWH transactions are exported
+}
+
+// StepUpConfig holds risk-based step-up authentication for money-moving tasks: the
+// policy that picks a method, how long a challenge stays open and how many answers
+// it takes
+type StepUpConfig struct {
+	Enabled             bool
+	PolicyFile          string // JSON rules replacing the built-in policy
+	ChallengeTTLSeconds int
+	MaxAttempts         int
+	DevEchoOTP          bool   // Return OTPs in challenges outside sandbox too, for development
+	BankingURL          string // Banking integration holding the phones OTPs go to
+	BankingAPIKey       string
+}
+
+// DeviceTrustConfig holds how device history becomes trust: how fast the evidence
+// fades and the scores at which a device counts as trusted or known
+type DeviceTrustConfig struct {
+	HalfLifeDays float64 // Days for usage and step-up evidence to lose half its weight
+	TrustedScore float64
+	KnownScore   float64
+}
+
+// WarmupConfig holds agent pre-warming: intent history per user and hour of day
+// decides which agents are likely to be needed soon, and those are asked to warm up
+type WarmupConfig struct {
+	Enabled         bool
+	IntervalSeconds int     // Seconds between scheduled warmups
+	LeadMinutes     int     // Scheduled warmups look this far ahead for the coming hour
+	MinShare        float64 // Share of an hour's intents an intent needs before its agents are warmed
+	CooldownSeconds int     // An agent is not asked again within this time
+	HalfLifeDays    float64 // Days for intent history to lose half its weight
+	Timezone        string  // Time zone of the hour of day; UTC when empty
+	AgentAPIKey     string  // Sent to agents as X-API-Key
+}
+
 var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables and .env file
 func LoadConfig() (*Config, error) {
-	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	// Load .env.<APP_ENV> and .env if they exist
+	settings = profile.New()
+	environment := settings.LoadEnvironment(godotenv.Read, godotenv.Load)
+	if err := settings.LoadSecrets(); err != nil {
+		return nil, err
+	}
+
+	// Replay protection is on by default outside dev, where the example scripts and
+	// hand-written curl requests submit transfers without a nonce
+	replayDefault := "true"
+	if environment == profile.EnvDevelopment {
+		replayDefault = "false"
 	}
 
 	viper.SetDefault("SERVER_PORT", "8080")
@@ -92,13 +330,109 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("SECURITY_RATE_LIMIT_RPS", "100")
 	viper.SetDefault("LOGGING_LEVEL", "info")
 	viper.SetDefault("LOGGING_FORMAT", "json")
+	viper.SetDefault("RECOVERY_EXPOSE_DETAILS", "false")
+	viper.SetDefault("RECOVERY_STACK_LOG_INTERVAL", "300")
+	viper.SetDefault("RECOVERY_KEEP_FINGERPRINTS", "100")
 	viper.SetDefault("AGENTS_DEFAULT_TIMEOUT", "30")
 	viper.SetDefault("AGENTS_HEALTH_CHECK_INTERVAL", "60")
+	viper.SetDefault("QUEUE_HIGH_WATERMARK", "500")
+	viper.SetDefault("DRAIN_TIMEOUT_SECONDS", "25")
+	viper.SetDefault("DRAIN_HANDOVER_FILE", "task-handover.json")
+	viper.SetDefault("DRAIN_HEARTBEAT_SECONDS", "5")
+	viper.SetDefault("DRAIN_RECOVER_ON_START", "true")
+	viper.SetDefault("QUEUE_LOW_WATERMARK", "400")
+	viper.SetDefault("QUEUE_RETRY_AFTER", "30")
+	viper.SetDefault("AGENT_HOLD_ENABLED", "true")
+	viper.SetDefault("AGENT_HOLD_MAX_WAIT_SECONDS", "120")
+	viper.SetDefault("AGENT_HOLD_MAX_TASKS", "200")
+	viper.SetDefault("AGENT_HOLD_POLL_MS", "500")
+	viper.SetDefault("SLA_DEFAULT_MS", "5000")
+	viper.SetDefault("SLA_THRESHOLDS", defaultSLAThresholds)
+	viper.SetDefault("REPLAY_PROTECTION_ENABLED", replayDefault)
+	viper.SetDefault("REPLAY_MAX_SKEW_SECONDS", "300")
+	viper.SetDefault("RETENTION_ENABLED", "true")
+	viper.SetDefault("RETENTION_PURGE_INTERVAL_MINUTES", "60")
+	viper.SetDefault("RETENTION_TASKS_DAYS", "7")
+	viper.SetDefault("RETENTION_AUDIT_DAYS", "365")
+	viper.SetDefault("TASK_COMPACTION_ENABLED", "true")
+	viper.SetDefault("TASK_COMPACTION_INTERVAL_SECONDS", "60")
+	viper.SetDefault("TASK_COMPACTION_AGE_MINUTES", "15")
+	viper.SetDefault("DSAR_DEADLINE_DAYS", "30")
+	viper.SetDefault("DSAR_SIGNING_KEY", "change-me-dsar-signing-key")
+	viper.SetDefault("DSAR_SKIN_URL", "http://localhost:8081")
+	viper.SetDefault("DSAR_BANKING_URL", "http://localhost:7000")
+	viper.SetDefault("DSAR_HISTORY_DAYS", "3650")
+	viper.SetDefault("STEPUP_ENABLED", "true")
+	viper.SetDefault("STEPUP_CHALLENGE_TTL_SECONDS", "300")
+	viper.SetDefault("STEPUP_MAX_ATTEMPTS", "3")
+	viper.SetDefault("STEPUP_OTP_DEV_ECHO", "false")
+	viper.SetDefault("STEPUP_BANKING_URL", "http://localhost:7000")
+	viper.SetDefault("DEVICE_TRUST_HALF_LIFE_DAYS", "30")
+	viper.SetDefault("DEVICE_TRUST_TRUSTED_SCORE", "0.7")
+	viper.SetDefault("DEVICE_TRUST_KNOWN_SCORE", "0.3")
+	viper.SetDefault("WARMUP_ENABLED", "true")
+	viper.SetDefault("WARMUP_INTERVAL_SECONDS", "300")
+	viper.SetDefault("WARMUP_LEAD_MINUTES", "10")
+	viper.SetDefault("WARMUP_MIN_SHARE", "0.2")
+	viper.SetDefault("WARMUP_COOLDOWN_SECONDS", "120")
+	viper.SetDefault("WARMUP_HISTORY_HALF_LIFE_DAYS", "14")
+	viper.SetDefault("WARMUP_TIMEZONE", "")
+	viper.SetDefault("WARMUP_AGENT_API_KEY", "test-api-key")
+	viper.SetDefault("RECONCILE_ENABLED", "true")
+	viper.SetDefault("RECONCILE_HOUR", "2")
+	viper.SetDefault("RECONCILE_TIMEZONE", "")
+	viper.SetDefault("RECONCILE_LOOKBACK_HOURS", "24")
+	viper.SetDefault("RECONCILE_GRACE_MINUTES", "30")
+	viper.SetDefault("RECONCILE_AUTO_CORRECT", "false")
+	viper.SetDefault("RECONCILE_BANKING_URL", "http://localhost:7000")
+	viper.SetDefault("STATELESS_ENABLED", "true")
+	viper.SetDefault("STATELESS_WAIT_SECONDS", "30")
+	viper.SetDefault("TENANT_POOL_POLICY", "overflow")
+	viper.SetDefault("TENANT_POOL_POLICIES", "")
+	viper.SetDefault("TENANT_POOL_AGENT_CAPACITY", "10")
+	viper.SetDefault("FAST_PATH_ENABLED", "false")
+	viper.SetDefault("FAST_PATH_MAX_AMOUNT", "500")
+	viper.SetDefault("FAST_PATH_MAX_RISK_SCORE", "0.3")
+	viper.SetDefault("FAST_PATH_MIN_BENEFICIARY_DAYS", "30")
+	viper.SetDefault("FAST_PATH_INTENTS", "TRANSFER_UPI,TRANSFER_IMPS")
+	viper.SetDefault("FAST_PATH_KEEP_RECORDS", "10000")
+	viper.SetDefault("FAST_PATH_BANKING_URL", "http://localhost:7000")
+	viper.SetDefault("SPLIT_ENABLED", "true")
+	viper.SetDefault("RAIL_LIMITS", defaultRailLimits)
+	viper.SetDefault("RAIL_DAILY_LIMITS", defaultRailDailyLimits)
+	viper.SetDefault("SPLIT_RAILS", "IMPS,UPI")
+	viper.SetDefault("SPLIT_MAX_LEGS", "6")
+	viper.SetDefault("SPLIT_MAX_DAYS", "3")
+	viper.SetDefault("SPLIT_PROPOSAL_TTL_SECONDS", "300")
+	viper.SetDefault("SPLIT_LATER_DAY_HOUR", "9")
+	viper.SetDefault("SPLIT_TIMEZONE", "")
+	viper.SetDefault("ALERTS_ENABLED", "false")
+	viper.SetDefault("ALERT_CHECK_INTERVAL", "30")
+	viper.SetDefault("ALERT_REPEAT_MINUTES", "60")
+	viper.SetDefault("ALERT_FALLBACK_RATE", "0.5")
+	viper.SetDefault("ALERT_FALLBACK_MIN_TASKS", "20")
+	viper.SetDefault("ALERT_REDIS_DOWN_MINUTES", "5")
+	viper.SetDefault("ALERT_AGENT_HEALTH", "true")
+	viper.SetDefault("ALERT_LLM_ERROR_RATE", "0.3")
+	viper.SetDefault("ALERT_LLM_MIN_CALLS", "10")
+	viper.SetDefault("SECRETS_PROVIDER", "env")
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "300")
+	viper.SetDefault("AUDIT_SINK", "file")
+	viper.SetDefault("AUDIT_DIR", "audit")
+	viper.SetDefault("AUDIT_RETENTION_DAYS", "365")
+	viper.SetDefault("AUDIT_REDIS_ADDR", "localhost:6379")
+	viper.SetDefault("DEMO_MODE", "false")
+	viper.SetDefault("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios")
 
 	// Bind environment variables
 	viper.AutomaticEnv()
 
 	AppConfig = &Config{
+		Environment: environment,
+		Secrets: secrets.Config{
+			Provider:        settings.SecretsProvider(),
+			RefreshInterval: getEnvInt("SECRETS_REFRESH_INTERVAL", 300),
+		},
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "8080"),
 			GRPCPort:     getEnv("SERVER_GRPC_PORT", "9090"),
@@ -126,23 +460,244 @@ func LoadConfig() (*Config, error) {
 			JWTSecret:    getEnv("SECURITY_JWT_SECRET", "your-secret-key-change-in-production"),
 			RateLimitRPS: 100,
 		},
+		RBAC: RBACConfig{
+			Admins: settings.GetGrants("RBAC_ADMIN_OPERATORS"),
+		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOGGING_LEVEL", "info"),
 			Format: getEnv("LOGGING_FORMAT", "json"),
 		},
+		Recovery: recovery.Config{
+			ExposeDetails:    getEnv("RECOVERY_EXPOSE_DETAILS", "false") == "true",
+			StackLogInterval: getEnvInt("RECOVERY_STACK_LOG_INTERVAL", 300),
+			KeepFingerprints: getEnvInt("RECOVERY_KEEP_FINGERPRINTS", 100),
+		},
 		Agents: AgentsConfig{
-			DefaultTimeout:       30,
+			DefaultTimeout:      30,
 			HealthCheckInterval: 60,
 		},
+		Drain: DrainConfig{
+			TimeoutSeconds:   getEnvInt("DRAIN_TIMEOUT_SECONDS", 25),
+			HandoverFile:     getEnv("DRAIN_HANDOVER_FILE", "task-handover.json"),
+			HeartbeatSeconds: getEnvInt("DRAIN_HEARTBEAT_SECONDS", 5),
+			RecoverOnStart:   getEnv("DRAIN_RECOVER_ON_START", "true") == "true",
+		},
+		Queue: QueueConfig{
+			HighWatermark: getEnvInt("QUEUE_HIGH_WATERMARK", 500),
+			LowWatermark:  getEnvInt("QUEUE_LOW_WATERMARK", 400),
+			RetryAfter:    getEnvInt("QUEUE_RETRY_AFTER", 30),
+		},
+		Hold: HoldConfig{
+			Enabled:        getEnv("AGENT_HOLD_ENABLED", "true") == "true",
+			MaxWaitSeconds: getEnvInt("AGENT_HOLD_MAX_WAIT_SECONDS", 120),
+			MaxTasks:       getEnvInt("AGENT_HOLD_MAX_TASKS", 200),
+			PollIntervalMs: getEnvInt("AGENT_HOLD_POLL_MS", 500),
+		},
+		SLA: SLAConfig{
+			DefaultMs:  getEnvInt("SLA_DEFAULT_MS", 5000),
+			Thresholds: parseThresholds(getEnv("SLA_THRESHOLDS", defaultSLAThresholds)),
+		},
+		Replay: ReplayConfig{
+			Enabled:        getEnv("REPLAY_PROTECTION_ENABLED", replayDefault) == "true",
+			MaxSkewSeconds: getEnvInt("REPLAY_MAX_SKEW_SECONDS", 300),
+		},
+		Retention: RetentionConfig{
+			Enabled:         getEnv("RETENTION_ENABLED", "true") == "true",
+			IntervalMinutes: getEnvInt("RETENTION_PURGE_INTERVAL_MINUTES", 60),
+			TasksDays:       getEnvInt("RETENTION_TASKS_DAYS", 7),
+			AuditDays:       getEnvInt("RETENTION_AUDIT_DAYS", 365),
+		},
+		Compaction: CompactionConfig{
+			Enabled:         getEnv("TASK_COMPACTION_ENABLED", "true") == "true",
+			IntervalSeconds: getEnvInt("TASK_COMPACTION_INTERVAL_SECONDS", 60),
+			AgeMinutes:      getEnvInt("TASK_COMPACTION_AGE_MINUTES", 15),
+		},
+		DSAR: DSARConfig{
+			DeadlineDays:  getEnvInt("DSAR_DEADLINE_DAYS", 30),
+			SigningKey:    getEnv("DSAR_SIGNING_KEY", "change-me-dsar-signing-key"),
+			SkinURL:       getEnv("DSAR_SKIN_URL", "http://localhost:8081"),
+			SkinAPIKey:    getEnv("DSAR_SKIN_API_KEY", "test-api-key"),
+			BankingURL:    getEnv("DSAR_BANKING_URL", "http://localhost:7000"),
+			BankingAPIKey: getEnv("DSAR_BANKING_API_KEY", "test-api-key"),
+			HistoryDays:   getEnvInt("DSAR_HISTORY_DAYS", 3650),
+		},
+		StepUp: StepUpConfig{
+			Enabled:             getEnv("STEPUP_ENABLED", "true") == "true",
+			PolicyFile:          getEnv("STEPUP_POLICY_FILE", ""),
+			ChallengeTTLSeconds: getEnvInt("STEPUP_CHALLENGE_TTL_SECONDS", 300),
+			MaxAttempts:         getEnvInt("STEPUP_MAX_ATTEMPTS", 3),
+			DevEchoOTP:          getEnv("STEPUP_OTP_DEV_ECHO", "false") == "true",
+			BankingURL:          getEnv("STEPUP_BANKING_URL", "http://localhost:7000"),
+			BankingAPIKey:       getEnv("STEPUP_BANKING_API_KEY", "test-api-key"),
+		},
+		Devices: DeviceTrustConfig{
+			HalfLifeDays: getEnvFloat("DEVICE_TRUST_HALF_LIFE_DAYS", 30),
+			TrustedScore: getEnvFloat("DEVICE_TRUST_TRUSTED_SCORE", 0.7),
+			KnownScore:   getEnvFloat("DEVICE_TRUST_KNOWN_SCORE", 0.3),
+		},
+		Warmup: WarmupConfig{
+			Enabled:         getEnv("WARMUP_ENABLED", "true") == "true",
+			IntervalSeconds: getEnvInt("WARMUP_INTERVAL_SECONDS", 300),
+			LeadMinutes:     getEnvInt("WARMUP_LEAD_MINUTES", 10),
+			MinShare:        getEnvFloat("WARMUP_MIN_SHARE", 0.2),
+			CooldownSeconds: getEnvInt("WARMUP_COOLDOWN_SECONDS", 120),
+			HalfLifeDays:    getEnvFloat("WARMUP_HISTORY_HALF_LIFE_DAYS", 14),
+			Timezone:        getEnv("WARMUP_TIMEZONE", ""),
+			AgentAPIKey:     getEnv("WARMUP_AGENT_API_KEY", "test-api-key"),
+		},
+		Alerts: AlertsConfig{
+			Enabled:          getEnv("ALERTS_ENABLED", "false") == "true",
+			CheckInterval:    getEnvInt("ALERT_CHECK_INTERVAL", 30),
+			RepeatMinutes:    getEnvInt("ALERT_REPEAT_MINUTES", 60),
+			FallbackRate:     getEnvFloat("ALERT_FALLBACK_RATE", 0.5),
+			FallbackMinTasks: getEnvInt("ALERT_FALLBACK_MIN_TASKS", 20),
+			RedisDownMinutes: getEnvInt("ALERT_REDIS_DOWN_MINUTES", 5),
+			AgentHealth:      getEnv("ALERT_AGENT_HEALTH", "true") == "true",
+			SkinURL:          getEnv("ALERT_SKIN_URL", ""),
+			SkinAPIKey:       getEnv("ALERT_SKIN_API_KEY", "test-api-key"),
+			LLMErrorRate:     getEnvFloat("ALERT_LLM_ERROR_RATE", 0.3),
+			LLMMinCalls:      getEnvInt("ALERT_LLM_MIN_CALLS", 10),
+			WebhookURL:       getEnv("ALERT_WEBHOOK_URL", ""),
+			SlackWebhookURL:  getEnv("ALERT_SLACK_WEBHOOK_URL", ""),
+			EmailSMTPAddr:    getEnv("ALERT_EMAIL_SMTP_ADDR", ""),
+			EmailUsername:    getEnv("ALERT_EMAIL_USERNAME", ""),
+			EmailPassword:    getEnv("ALERT_EMAIL_PASSWORD", ""),
+			EmailFrom:        getEnv("ALERT_EMAIL_FROM", "alerts@aibanking.local"),
+			EmailTo:          splitList(getEnv("ALERT_EMAIL_TO", "")),
+		},
+		Reconcile: ReconcileConfig{
+			Enabled:       getEnv("RECONCILE_ENABLED", "true") == "true",
+			Hour:          getEnvInt("RECONCILE_HOUR", 2),
+			Timezone:      getEnv("RECONCILE_TIMEZONE", ""),
+			LookbackHours: getEnvInt("RECONCILE_LOOKBACK_HOURS", 24),
+			GraceMinutes:  getEnvInt("RECONCILE_GRACE_MINUTES", 30),
+			AutoCorrect:   getEnv("RECONCILE_AUTO_CORRECT", "false") == "true",
+			BankingURL:    getEnv("RECONCILE_BANKING_URL", "http://localhost:7000"),
+			BankingAPIKey: getEnv("RECONCILE_BANKING_API_KEY", "test-api-key"),
+		},
+		FastPath: FastPathConfig{
+			Enabled:            getEnv("FAST_PATH_ENABLED", "false") == "true",
+			MaxAmount:          getEnvFloat("FAST_PATH_MAX_AMOUNT", 500),
+			MaxRiskScore:       getEnvFloat("FAST_PATH_MAX_RISK_SCORE", 0.3),
+			MinBeneficiaryDays: getEnvInt("FAST_PATH_MIN_BENEFICIARY_DAYS", 30),
+			Intents:            splitList(getEnv("FAST_PATH_INTENTS", "TRANSFER_UPI,TRANSFER_IMPS")),
+			KeepRecords:        getEnvInt("FAST_PATH_KEEP_RECORDS", 10000),
+			BankingURL:         getEnv("FAST_PATH_BANKING_URL", "http://localhost:7000"),
+			BankingAPIKey:      getEnv("FAST_PATH_BANKING_API_KEY", "test-api-key"),
+		},
+		Split: SplitConfig{
+			Enabled:            getEnv("SPLIT_ENABLED", "true") == "true",
+			PerTransfer:        parseAmounts(getEnv("RAIL_LIMITS", defaultRailLimits)),
+			Daily:              parseAmounts(getEnv("RAIL_DAILY_LIMITS", defaultRailDailyLimits)),
+			Rails:              splitList(getEnv("SPLIT_RAILS", "IMPS,UPI")),
+			MaxLegs:            getEnvInt("SPLIT_MAX_LEGS", 6),
+			MaxDays:            getEnvInt("SPLIT_MAX_DAYS", 3),
+			ProposalTTLSeconds: getEnvInt("SPLIT_PROPOSAL_TTL_SECONDS", 300),
+			LaterDayHour:       getEnvInt("SPLIT_LATER_DAY_HOUR", 9),
+			Timezone:           getEnv("SPLIT_TIMEZONE", ""),
+		},
+		Stateless: StatelessConfig{
+			Enabled:     getEnv("STATELESS_ENABLED", "true") == "true",
+			WaitSeconds: getEnvInt("STATELESS_WAIT_SECONDS", 30),
+		},
+		TenantPools: TenantPoolConfig{
+			Policy:        strings.ToLower(getEnv("TENANT_POOL_POLICY", TenantPoolOverflow)),
+			Policies:      parsePolicies(getEnv("TENANT_POOL_POLICIES", "")),
+			AgentCapacity: getEnvInt("TENANT_POOL_AGENT_CAPACITY", 10),
+		},
+		Audit: audit.Config{
+			Service:       "mcp-server",
+			Sink:          strings.ToLower(getEnv("AUDIT_SINK", audit.SinkFile)),
+			Dir:           getEnv("AUDIT_DIR", "audit"),
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			RedisAddr:     getEnv("AUDIT_REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("AUDIT_REDIS_PASSWORD", ""),
+			RedisStream:   getEnv("AUDIT_REDIS_STREAM", "audit:access:mcp-server"),
+			SyslogNetwork: getEnv("AUDIT_SYSLOG_NETWORK", ""),
+			SyslogAddr:    getEnv("AUDIT_SYSLOG_ADDR", ""),
+			SigningKey:    getEnv("AUDIT_SIGNING_KEY", ""),
+		},
+		Demo: demo.Config{
+			Enabled:      getEnv("DEMO_MODE", "false") == "true",
+			ScenariosDir: getEnv("DEMO_SCENARIOS_DIR", "../shared/demo/scenarios"),
+		},
 	}
 
 	return AppConfig, nil
 }
 
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	return settings.Get(key, defaultValue)
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	return settings.GetFloat(key, defaultValue)
+}
+
+// defaultSLAThresholds holds reads to a tighter SLA than money movement and loans
+const defaultSLAThresholds = "CHECK_BALANCE=2000,GET_STATEMENT=3000,LIST_BENEFICIARIES=2000"
+
+// parseThresholds parses "INTENT=ms,INTENT=ms", skipping malformed entries
+func parseThresholds(value string) map[string]int {
+	thresholds := make(map[string]int)
+	for _, item := range splitList(value) {
+		intent, ms, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.Atoi(strings.TrimSpace(ms)); err == nil && parsed > 0 {
+			thresholds[strings.ToUpper(strings.TrimSpace(intent))] = parsed
+		}
 	}
-	return defaultValue
+	return thresholds
 }
 
+// Rail limits: NEFT and RTGS take any amount
+const (
+	defaultRailLimits      = "UPI=100000,IMPS=200000"
+	defaultRailDailyLimits = "UPI=100000,IMPS=500000"
+)
+
+// parseAmounts parses "RAIL=amount,RAIL=amount", skipping malformed entries
+func parseAmounts(value string) map[string]float64 {
+	amounts := make(map[string]float64)
+	for _, item := range splitList(value) {
+		rail, amount, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(amount), 64); err == nil && parsed > 0 {
+			amounts[strings.ToUpper(strings.TrimSpace(rail))] = parsed
+		}
+	}
+	return amounts
+}
+
+// parsePolicies parses "tenant=policy,tenant=policy", skipping malformed entries.
+// Tenant IDs keep their case.
+func parsePolicies(value string) map[string]string {
+	policies := make(map[string]string)
+	for _, item := range splitList(value) {
+		tenantID, policy, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(tenantID) == "" {
+			continue
+		}
+		policies[strings.TrimSpace(tenantID)] = strings.ToLower(strings.TrimSpace(policy))
+	}
+	return policies
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	return settings.GetInt(key, defaultValue)
+}
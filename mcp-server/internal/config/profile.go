@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aibanking/shared/profile"
+)
+
+// settings holds every value LoadConfig read, by key
+var settings = profile.New()
+
+// Profile returns what LoadConfig read: every setting and where it came from, for
+// config-lint, and the secrets provider, whose rotations the service's clients follow
+func Profile() *profile.Profile {
+	return settings
+}
+
+// Validate checks the configuration against its environment. Errors should stop the
+// service from starting; warnings are logged.
+func (c *Config) Validate() []profile.Problem {
+	v := profile.NewValidator(settings, c.Environment)
+	v.Required("REDIS_HOST",
+		"DSAR_SIGNING_KEY", "DSAR_SKIN_URL", "DSAR_SKIN_API_KEY", "DSAR_BANKING_URL", "DSAR_BANKING_API_KEY")
+	v.URLs("DSAR_SKIN_URL", "DSAR_BANKING_URL", "ALERT_SKIN_URL", "ALERT_WEBHOOK_URL", "ALERT_SLACK_WEBHOOK_URL")
+	v.Hosts("REDIS_HOST")
+	v.Secrets("DSAR_SIGNING_KEY", "DSAR_SKIN_API_KEY", "DSAR_BANKING_API_KEY")
+	if c.Alerts.SkinURL != "" {
+		v.Secrets("ALERT_SKIN_API_KEY")
+	}
+
+	v.URLs("STEPUP_BANKING_URL")
+	v.Secrets("STEPUP_BANKING_API_KEY")
+	if c.StepUp.DevEchoOTP && v.Strict() {
+		v.Add("STEPUP_OTP_DEV_ECHO", v.Severity(profile.SeverityWarning, profile.SeverityError), "returns OTPs in API responses")
+	}
+	if !c.StepUp.Enabled && v.Strict() {
+		v.Add("STEPUP_ENABLED", profile.SeverityWarning, "high-value transfers go through without step-up authentication")
+	}
+	if !c.Replay.Enabled && v.Strict() {
+		v.Add("REPLAY_PROTECTION_ENABLED", profile.SeverityWarning, "money-moving submissions can be replayed")
+	}
+	if c.Redis.Password == "" && c.Environment == profile.EnvProduction {
+		v.Add("REDIS_PASSWORD", profile.SeverityWarning, "Redis has no password")
+	}
+	if c.Reconcile.Enabled {
+		v.URLs("RECONCILE_BANKING_URL")
+		v.Secrets("RECONCILE_BANKING_API_KEY")
+		if c.Reconcile.Hour < 0 || c.Reconcile.Hour > 23 {
+			v.Add("RECONCILE_HOUR", profile.SeverityError, fmt.Sprintf("must be an hour of day from 0 to 23, got %d", c.Reconcile.Hour))
+		}
+		if _, err := time.LoadLocation(c.Reconcile.Timezone); err != nil {
+			v.Add("RECONCILE_TIMEZONE", profile.SeverityError, fmt.Sprintf("unknown time zone %q", c.Reconcile.Timezone))
+		}
+	}
+	if c.Warmup.Enabled {
+		v.Secrets("WARMUP_AGENT_API_KEY")
+		if _, err := time.LoadLocation(c.Warmup.Timezone); err != nil {
+			v.Add("WARMUP_TIMEZONE", profile.SeverityError, fmt.Sprintf("unknown time zone %q", c.Warmup.Timezone))
+		}
+	}
+	if c.FastPath.Enabled {
+		v.URLs("FAST_PATH_BANKING_URL")
+		v.Secrets("FAST_PATH_BANKING_API_KEY")
+		if c.FastPath.MaxAmount <= 0 {
+			v.Add("FAST_PATH_MAX_AMOUNT", profile.SeverityError, fmt.Sprintf("must be positive, got %g", c.FastPath.MaxAmount))
+		} else if c.FastPath.MaxAmount > 10000 {
+			v.Add("FAST_PATH_MAX_AMOUNT", v.Severity(profile.SeverityWarning, profile.SeverityError), fmt.Sprintf("transfers up to %g skip the guardrail and fraud checks", c.FastPath.MaxAmount))
+		}
+		if c.FastPath.MaxRiskScore < 0 || c.FastPath.MaxRiskScore > 1 {
+			v.Add("FAST_PATH_MAX_RISK_SCORE", profile.SeverityError, fmt.Sprintf("must be between 0 and 1, got %g", c.FastPath.MaxRiskScore))
+		}
+		for _, intent := range c.FastPath.Intents {
+			if !strings.HasPrefix(intent, "TRANSFER_") {
+				v.Add("FAST_PATH_INTENTS", profile.SeverityError, fmt.Sprintf("%s is not a transfer intent", intent))
+			}
+		}
+	}
+	if c.Stateless.Enabled && c.Stateless.WaitSeconds < 1 {
+		v.Add("STATELESS_WAIT_SECONDS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Stateless.WaitSeconds))
+	}
+	if c.Compaction.Enabled {
+		if c.Compaction.IntervalSeconds < 1 {
+			v.Add("TASK_COMPACTION_INTERVAL_SECONDS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Compaction.IntervalSeconds))
+		}
+		if c.Compaction.AgeMinutes < 1 {
+			v.Add("TASK_COMPACTION_AGE_MINUTES", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Compaction.AgeMinutes))
+		}
+	}
+
+	if !validTenantPolicy(c.TenantPools.Policy) {
+		v.Add("TENANT_POOL_POLICY", profile.SeverityError, fmt.Sprintf("must be overflow, fallback or dedicated, got %q", c.TenantPools.Policy))
+	}
+	for tenantID, policy := range c.TenantPools.Policies {
+		if !validTenantPolicy(policy) {
+			v.Add("TENANT_POOL_POLICIES", profile.SeverityError, fmt.Sprintf("%s: must be overflow, fallback or dedicated, got %q", tenantID, policy))
+		}
+	}
+	if c.TenantPools.AgentCapacity < 0 {
+		v.Add("TENANT_POOL_AGENT_CAPACITY", profile.SeverityError, fmt.Sprintf("must not be negative, got %d", c.TenantPools.AgentCapacity))
+	}
+
+	if c.Split.Enabled {
+		if c.Split.MaxLegs < 2 {
+			v.Add("SPLIT_MAX_LEGS", profile.SeverityError, fmt.Sprintf("a split needs at least 2 legs, got %d", c.Split.MaxLegs))
+		}
+		if c.Split.MaxDays < 1 {
+			v.Add("SPLIT_MAX_DAYS", profile.SeverityError, fmt.Sprintf("must be at least 1, got %d", c.Split.MaxDays))
+		}
+		if c.Split.ProposalTTLSeconds <= 0 {
+			v.Add("SPLIT_PROPOSAL_TTL_SECONDS", profile.SeverityError, fmt.Sprintf("must be positive, got %d", c.Split.ProposalTTLSeconds))
+		}
+		if c.Split.LaterDayHour < 0 || c.Split.LaterDayHour > 23 {
+			v.Add("SPLIT_LATER_DAY_HOUR", profile.SeverityError, fmt.Sprintf("must be an hour of day from 0 to 23, got %d", c.Split.LaterDayHour))
+		}
+		if _, err := time.LoadLocation(c.Split.Timezone); err != nil {
+			v.Add("SPLIT_TIMEZONE", profile.SeverityError, fmt.Sprintf("unknown time zone %q", c.Split.Timezone))
+		}
+		for _, rail := range c.Split.Rails {
+			if _, capped := c.Split.PerTransfer[strings.ToUpper(rail)]; !capped {
+				v.Add("SPLIT_RAILS", profile.SeverityWarning, fmt.Sprintf("%s has no limit in RAIL_LIMITS, so one leg on it takes whatever is left", rail))
+			}
+		}
+	}
+	if c.Drain.TimeoutSeconds < 1 {
+		v.Add("DRAIN_TIMEOUT_SECONDS", profile.SeverityError, "must be at least 1")
+	}
+	if c.Drain.HeartbeatSeconds < 1 {
+		v.Add("DRAIN_HEARTBEAT_SECONDS", profile.SeverityError, "must be at least 1")
+	}
+	if !c.Drain.RecoverOnStart && v.Strict() {
+		v.Add("DRAIN_RECOVER_ON_START", profile.SeverityWarning, "is off; tasks a stopped instance left unfinished stay unfinished")
+	}
+	if c.Recovery.ExposeDetails && v.Strict() {
+		v.Add("RECOVERY_EXPOSE_DETAILS", v.Severity(profile.SeverityWarning, profile.SeverityError), "is on; panic messages, which may hold customer data, are sent to callers")
+	}
+	if c.Recovery.KeepFingerprints < 1 {
+		v.Add("RECOVERY_KEEP_FINGERPRINTS", profile.SeverityError, "must be at least 1")
+	}
+	v.Placeholders("SECURITY_JWT_SECRET")
+	v.AuditSink(c.Audit)
+	v.DemoMode(c.Demo)
+	v.SecretSources(c.Secrets)
+	return v.Problems()
+}
+
+func validTenantPolicy(policy string) bool {
+	switch policy {
+	case TenantPoolOverflow, TenantPoolFallback, TenantPoolDedicated:
+		return true
+	}
+	return false
+}
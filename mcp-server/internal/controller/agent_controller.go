@@ -6,6 +6,7 @@ import (
 
 	"github.com/aibanking/mcp-server/internal/model"
 	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
 )
 
 // AgentController handles agent-related HTTP requests
@@ -46,6 +47,7 @@ func (ac *AgentController) RegisterAgent(w http.ResponseWriter, r *http.Request)
 		Name:         agent.Name,
 		Type:         string(agent.Type),
 		Status:       string(agent.Status),
+		TenantID:     agent.TenantID,
 		RegisteredAt: agent.RegisteredAt,
 		Message:      "Agent registered successfully",
 	}
@@ -55,7 +57,7 @@ func (ac *AgentController) RegisterAgent(w http.ResponseWriter, r *http.Request)
 
 // GetAgent handles GET /agent/{agentID}
 func (ac *AgentController) GetAgent(w http.ResponseWriter, r *http.Request) {
-	agentID := r.PathValue("agentID")
+	agentID := mux.Vars(r)["agentID"]
 	if agentID == "" {
 		RespondWithError(w, http.StatusBadRequest, "Agent ID is required", nil)
 		return
@@ -83,3 +85,9 @@ func (ac *AgentController) GetAllAgents(w http.ResponseWriter, r *http.Request)
 		"count":  len(agents),
 	})
 }
+
+// GetTenantUtilization handles GET /agents/tenants, how busy each tenant keeps its own
+// agents and the shared pool
+func (ac *AgentController) GetTenantUtilization(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, ac.agentRegistry.TenantUtilization(r.Context()))
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/aibanking/mcp-server/internal/service"
+)
+
+// AlertController handles alert-related HTTP requests
+type AlertController struct {
+	alertManager *service.AlertManager
+	enabled      bool
+}
+
+// NewAlertController creates a new alert controller
+func NewAlertController(alertManager *service.AlertManager, enabled bool) *AlertController {
+	return &AlertController{
+		alertManager: alertManager,
+		enabled:      enabled,
+	}
+}
+
+// GetAlerts handles GET /alerts
+func (ac *AlertController) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled":  ac.enabled,
+		"active":   ac.alertManager.Active(),
+		"resolved": ac.alertManager.History(),
+	})
+}
+
+// CheckNow handles POST /alerts/check. It runs every check immediately, even when
+// scheduled alerting is disabled, and notifies the sinks of any change.
+func (ac *AlertController) CheckNow(w http.ResponseWriter, r *http.Request) {
+	ac.alertManager.Check(r.Context())
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": ac.enabled,
+		"active":  ac.alertManager.Active(),
+	})
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AuthController handles risk-based step-up authentication: the policy, the
+// challenges tasks wait on and the factors users register to answer them
+type AuthController struct {
+	orchestrator *service.Orchestrator
+	auth         *service.StepUpAuth
+}
+
+// NewAuthController creates a new step-up authentication controller
+func NewAuthController(orchestrator *service.Orchestrator, auth *service.StepUpAuth) *AuthController {
+	return &AuthController{
+		orchestrator: orchestrator,
+		auth:         auth,
+	}
+}
+
+// GetPolicy handles GET /auth/policy
+func (ac *AuthController) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": ac.auth.Enabled(),
+		"rules":   ac.auth.Policy(),
+	})
+}
+
+// GetChallenge handles GET /auth/challenges/{id}
+func (ac *AuthController) GetChallenge(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := ac.orchestrator.AuthChallenge(mux.Vars(r)["id"])
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Auth challenge not found", nil)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, challenge)
+}
+
+// VerifyOTP handles POST /auth/challenges/{id}/otp
+func (ac *AuthController) VerifyOTP(w http.ResponseWriter, r *http.Request) {
+	ac.verify(w, r, model.AuthOTP)
+}
+
+// VerifyBiometric handles POST /auth/challenges/{id}/biometric
+func (ac *AuthController) VerifyBiometric(w http.ResponseWriter, r *http.Request) {
+	ac.verify(w, r, model.AuthBiometric)
+}
+
+// VerifyToken handles POST /auth/challenges/{id}/token
+func (ac *AuthController) VerifyToken(w http.ResponseWriter, r *http.Request) {
+	ac.verify(w, r, model.AuthHardwareToken)
+}
+
+// verify answers a challenge with the given method. A verified challenge releases
+// its task, which the client then polls as usual.
+func (ac *AuthController) verify(w http.ResponseWriter, r *http.Request, method model.AuthMethod) {
+	var req model.AuthVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	challenge, err := ac.orchestrator.VerifyAuth(r.Context(), mux.Vars(r)["id"], method, &req)
+	if respondIfQueueFull(w, err) {
+		return
+	}
+	switch {
+	case err == nil:
+		RespondWithJSON(w, http.StatusOK, challenge)
+	case errors.Is(err, service.ErrChallengeNotFound):
+		RespondWithError(w, http.StatusNotFound, "Auth challenge not found", nil)
+	case errors.Is(err, service.ErrChallengeMethod):
+		RespondWithError(w, http.StatusBadRequest, "Challenge must be answered with another method", err)
+	case errors.Is(err, service.ErrChallengeClosed):
+		RespondWithError(w, http.StatusConflict, "Auth challenge is no longer pending", err)
+	case errors.Is(err, service.ErrAuthFailed):
+		RespondWithJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"error":     "Authentication failed",
+			"code":      http.StatusUnauthorized,
+			"details":   err.Error(),
+			"challenge": challenge,
+		})
+	default:
+		RespondWithError(w, http.StatusInternalServerError, "Failed to verify challenge", err)
+	}
+}
+
+// Enroll handles POST /auth/enrollments: the challenge a user answers before
+// registering a factor
+func (ac *AuthController) Enroll(w http.ResponseWriter, r *http.Request) {
+	var req model.AuthEnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	challenge, err := ac.auth.Enroll(r.Context(), &req)
+	switch {
+	case err == nil:
+		RespondWithJSON(w, http.StatusCreated, challenge)
+	case errors.Is(err, service.ErrNoRegisteredPhone):
+		RespondWithError(w, http.StatusUnprocessableEntity, "User has no registered phone to send an OTP to", err)
+	case errors.Is(err, service.ErrPhoneLookup):
+		RespondWithError(w, http.StatusBadGateway, "Failed to look up the user's registered phone", err)
+	case errors.Is(err, service.ErrOTPDelivery):
+		RespondWithError(w, http.StatusBadGateway, "Failed to send the OTP", err)
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Invalid enrollment request", err)
+	}
+}
+
+// RegisterDevice handles POST /auth/devices
+func (ac *AuthController) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	var req model.AuthDeviceRegistration
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if err := ac.auth.RegisterDevice(&req); err != nil {
+		respondRegistrationError(w, "Invalid device registration", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, ac.auth.Factors(req.UserID))
+}
+
+// RegisterToken handles POST /auth/tokens
+func (ac *AuthController) RegisterToken(w http.ResponseWriter, r *http.Request) {
+	var req model.AuthTokenRegistration
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	if err := ac.auth.RegisterToken(&req); err != nil {
+		respondRegistrationError(w, "Invalid token registration", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, ac.auth.Factors(req.UserID))
+}
+
+// respondRegistrationError refuses a registration without proof with 403
+func respondRegistrationError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, service.ErrEnrollmentProof) {
+		RespondWithError(w, http.StatusForbidden, "Factor registration needs a verified enrollment challenge", err)
+		return
+	}
+	RespondWithError(w, http.StatusBadRequest, message, err)
+}
+
+// GetFactors handles GET /auth/factors/{userID}
+func (ac *AuthController) GetFactors(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, ac.auth.Factors(mux.Vars(r)["userID"]))
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DeviceController exposes the trust profiles built up for users' devices
+type DeviceController struct {
+	devices *service.DeviceProfiles
+}
+
+// NewDeviceController creates a new device profile controller
+func NewDeviceController(devices *service.DeviceProfiles) *DeviceController {
+	return &DeviceController{
+		devices: devices,
+	}
+}
+
+// ListDevices handles GET /devices/{userID}
+func (dc *DeviceController) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	profiles := dc.devices.Profiles(r.Context(), userID)
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id": userID,
+		"devices": profiles,
+		"count":   len(profiles),
+	})
+}
+
+// ForgetDevice handles DELETE /devices/{userID}/{deviceID}
+func (dc *DeviceController) ForgetDevice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !dc.devices.Forget(r.Context(), vars["userID"], vars["deviceID"]) {
+		RespondWithError(w, http.StatusNotFound, "Device not found", nil)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message":   "Device history forgotten",
+		"user_id":   vars["userID"],
+		"device_id": vars["deviceID"],
+	})
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aibanking/mcp-server/internal/middleware"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DSARController handles data-subject access and erasure requests
+type DSARController struct {
+	dsarService *service.DSARService
+}
+
+// NewDSARController creates a new data-subject request controller
+func NewDSARController(dsarService *service.DSARService) *DSARController {
+	return &DSARController{
+		dsarService: dsarService,
+	}
+}
+
+// CreateRequest handles POST /dsar
+func (dc *DSARController) CreateRequest(w http.ResponseWriter, r *http.Request) {
+	var req model.DSARCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	dsar, err := dc.dsarService.Create(&req)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid data-subject request", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, dsar)
+}
+
+// ListRequests handles GET /dsar?status=VERIFIED
+func (dc *DSARController) ListRequests(w http.ResponseWriter, r *http.Request) {
+	requests := dc.dsarService.List(r.URL.Query().Get("status"))
+
+	overdue := 0
+	for _, dsar := range requests {
+		if dsar.Overdue {
+			overdue++
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+		"overdue":  overdue,
+	})
+}
+
+// GetRequest handles GET /dsar/{id}
+func (dc *DSARController) GetRequest(w http.ResponseWriter, r *http.Request) {
+	dsar, err := dc.dsarService.Get(mux.Vars(r)["id"])
+	if respondIfDSARError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dsar)
+}
+
+// VerifyRequest handles POST /dsar/{id}/verify
+func (dc *DSARController) VerifyRequest(w http.ResponseWriter, r *http.Request) {
+	var verification model.DSARVerification
+	if err := json.NewDecoder(r.Body).Decode(&verification); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	dsar, err := dc.dsarService.Verify(mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()), &verification)
+	if respondIfDSARError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dsar)
+}
+
+// RejectRequest handles POST /dsar/{id}/reject
+func (dc *DSARController) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	var req model.DSARRejectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	dsar, err := dc.dsarService.Reject(mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()), &req)
+	if respondIfDSARError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dsar)
+}
+
+// ProcessRequest handles POST /dsar/{id}/process. It runs synchronously and returns
+// the request as COMPLETED or FAILED.
+func (dc *DSARController) ProcessRequest(w http.ResponseWriter, r *http.Request) {
+	dsar, err := dc.dsarService.Process(r.Context(), mux.Vars(r)["id"], middleware.OperatorFromContext(r.Context()))
+	if respondIfDSARError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dsar)
+}
+
+// GetExport handles GET /dsar/{id}/export
+func (dc *DSARController) GetExport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	export, err := dc.dsarService.Export(r.Context(), id)
+	if respondIfDSARError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+id+".json\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(export)
+}
+
+// VerifyCompletion handles GET /dsar/{id}/completion. It returns the completion record
+// and whether its signature is still valid.
+func (dc *DSARController) VerifyCompletion(w http.ResponseWriter, r *http.Request) {
+	dsar, err := dc.dsarService.Get(mux.Vars(r)["id"])
+	if respondIfDSARError(w, err) {
+		return
+	}
+	if dsar.Completion == nil {
+		RespondWithError(w, http.StatusNotFound, "Request has not been completed", nil)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"completion":      dsar.Completion,
+		"signature_valid": dc.dsarService.VerifySignature(dsar.Completion),
+	})
+}
+
+// respondIfDSARError writes the response for a data-subject request error, reporting
+// whether there was one
+func respondIfDSARError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var stateErr *service.DSARStateError
+	switch {
+	case errors.Is(err, service.ErrDSARNotFound):
+		RespondWithError(w, http.StatusNotFound, "Data-subject request not found", nil)
+	case errors.Is(err, service.ErrDSARSelfVerification):
+		RespondWithError(w, http.StatusForbidden, "The requester cannot verify their own request", err)
+	case errors.As(err, &stateErr):
+		RespondWithError(w, http.StatusConflict, "Data-subject request is not in a state that allows this", err)
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Invalid data-subject request", err)
+	}
+	return true
+}
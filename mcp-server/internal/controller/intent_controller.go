@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aibanking/mcp-server/internal/middleware"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// IntentController handles the registration of tenants' custom intents and the flags
+// switching intents off
+type IntentController struct {
+	intents *service.IntentRegistry
+	flags   *service.IntentFlags
+}
+
+// NewIntentController creates a new intent controller
+func NewIntentController(intents *service.IntentRegistry, flags *service.IntentFlags) *IntentController {
+	return &IntentController{intents: intents, flags: flags}
+}
+
+// ListIntents handles GET /intents. With tenant_id only the intents that apply to
+// that tenant are listed: its own and those shared by every tenant.
+func (ic *IntentController) ListIntents(w http.ResponseWriter, r *http.Request) {
+	intents := ic.intents.List(r.URL.Query().Get("tenant_id"))
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"intents": intents,
+		"count":   len(intents),
+	})
+}
+
+// GetIntent handles GET /intents/{intent}?tenant_id=
+func (ic *IntentController) GetIntent(w http.ResponseWriter, r *http.Request) {
+	def, ok := ic.intents.Get(r.URL.Query().Get("tenant_id"), mux.Vars(r)["intent"])
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Custom intent not found", nil)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, def)
+}
+
+// RegisterIntent handles PUT /intents/{intent}
+func (ic *IntentController) RegisterIntent(w http.ResponseWriter, r *http.Request) {
+	var req model.IntentDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	def, err := ic.intents.Register(mux.Vars(r)["intent"], &req)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid intent definition", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, def)
+}
+
+// DeleteIntent handles DELETE /intents/{intent}?tenant_id=
+func (ic *IntentController) DeleteIntent(w http.ResponseWriter, r *http.Request) {
+	intent := mux.Vars(r)["intent"]
+	err := ic.intents.Delete(r.URL.Query().Get("tenant_id"), intent)
+	if errors.Is(err, service.ErrIntentNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Custom intent not found", nil)
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to remove custom intent", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Custom intent removed",
+		"intent":  intent,
+	})
+}
+
+// ListFlags handles GET /intents/flags. With intent, tenant_id and channel it also
+// answers with the flag that applies to them.
+func (ic *IntentController) ListFlags(w http.ResponseWriter, r *http.Request) {
+	flags := ic.flags.List()
+	response := map[string]interface{}{
+		"flags": flags,
+		"count": len(flags),
+	}
+	query := r.URL.Query()
+	if intent := strings.ToUpper(query.Get("intent")); intent != "" {
+		response["applies"] = ic.flags.Lookup(intent, query.Get("tenant_id"), query.Get("channel"))
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// SetFlag handles PUT /intents/{intent}/flag, switching an intent off, read-only or
+// back on at runtime
+func (ic *IntentController) SetFlag(w http.ResponseWriter, r *http.Request) {
+	var req model.IntentFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	flag, err := ic.flags.Set(mux.Vars(r)["intent"], middleware.OperatorFromContext(r.Context()), &req)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid intent flag", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, flag)
+}
+
+// DeleteFlag handles DELETE /intents/{intent}/flag?tenant_id=&channel=
+func (ic *IntentController) DeleteFlag(w http.ResponseWriter, r *http.Request) {
+	intent := mux.Vars(r)["intent"]
+	query := r.URL.Query()
+	err := ic.flags.Delete(intent, query.Get("tenant_id"), query.Get("channel"), middleware.OperatorFromContext(r.Context()))
+	if errors.Is(err, service.ErrIntentFlagNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Intent flag not found", nil)
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to remove intent flag", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Intent flag removed",
+		"intent":  strings.ToUpper(intent),
+	})
+}
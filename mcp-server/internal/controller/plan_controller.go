@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PlanController handles orchestration plan templates and their executed instances
+type PlanController struct {
+	plans      *service.PlanStore
+	ruleEngine *service.RuleEngine
+}
+
+// NewPlanController creates a new plan controller
+func NewPlanController(plans *service.PlanStore, ruleEngine *service.RuleEngine) *PlanController {
+	return &PlanController{
+		plans:      plans,
+		ruleEngine: ruleEngine,
+	}
+}
+
+// ListPlans handles GET /plans
+func (pc *PlanController) ListPlans(w http.ResponseWriter, r *http.Request) {
+	plans := pc.plans.List()
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"plans": plans,
+		"count": len(plans),
+	})
+}
+
+// CreatePlan handles POST /plans
+func (pc *PlanController) CreatePlan(w http.ResponseWriter, r *http.Request) {
+	var plan model.OrchestrationPlan
+	if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	created, err := pc.plans.Create(r.Context(), &plan)
+	if respondIfPlanError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, created)
+}
+
+// GetPlan handles GET /plans/{planID}
+func (pc *PlanController) GetPlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := pc.plans.Get(mux.Vars(r)["planID"])
+	if respondIfPlanError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"plan":  plan,
+		"rules": pc.ruleEngine.RulesReferencingPlan(plan.PlanID),
+	})
+}
+
+// UpdatePlan handles PUT /plans/{planID}
+func (pc *PlanController) UpdatePlan(w http.ResponseWriter, r *http.Request) {
+	var plan model.OrchestrationPlan
+	if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	updated, err := pc.plans.Update(r.Context(), mux.Vars(r)["planID"], &plan)
+	if respondIfPlanError(w, err) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, updated)
+}
+
+// DeletePlan handles DELETE /plans/{planID}; plans that routing rules reference are kept
+func (pc *PlanController) DeletePlan(w http.ResponseWriter, r *http.Request) {
+	planID := mux.Vars(r)["planID"]
+	if rules := pc.ruleEngine.RulesReferencingPlan(planID); len(rules) > 0 {
+		respondIfPlanError(w, &service.PlanInUseError{PlanID: planID, Rules: rules})
+		return
+	}
+
+	if respondIfPlanError(w, pc.plans.Delete(r.Context(), planID)) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Plan deleted successfully",
+		"plan_id": planID,
+	})
+}
+
+// GetPlanRuns handles GET /plans/{planID}/runs?limit=20
+func (pc *PlanController) GetPlanRuns(w http.ResponseWriter, r *http.Request) {
+	planID := mux.Vars(r)["planID"]
+	if !pc.plans.Exists(planID) {
+		respondIfPlanError(w, service.ErrPlanNotFound)
+		return
+	}
+
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			RespondWithError(w, http.StatusBadRequest, "limit must be a positive integer", err)
+			return
+		}
+		limit = parsed
+	}
+
+	runs := pc.plans.Runs(planID, limit)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"plan_id": planID,
+		"runs":    runs,
+		"count":   len(runs),
+	})
+}
+
+// respondIfPlanError writes the response for a plan store error and reports whether
+// there was one
+func respondIfPlanError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var inUse *service.PlanInUseError
+	switch {
+	case errors.Is(err, service.ErrPlanNotFound):
+		RespondWithError(w, http.StatusNotFound, "Orchestration plan not found", nil)
+	case errors.Is(err, service.ErrPlanExists):
+		RespondWithError(w, http.StatusConflict, "Orchestration plan already exists", err)
+	case errors.Is(err, service.ErrPlanBuiltin):
+		RespondWithError(w, http.StatusConflict, "Built-in orchestration plans cannot be deleted", err)
+	case errors.As(err, &inUse):
+		RespondWithError(w, http.StatusConflict, "Orchestration plan is referenced by routing rules", err)
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Invalid orchestration plan", err)
+	}
+	return true
+}
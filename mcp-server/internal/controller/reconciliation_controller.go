@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// ReconciliationController handles reconciliation of transfer tasks against the DWH
+type ReconciliationController struct {
+	reconciler  *service.Reconciler
+	autoCorrect bool // Default for manual runs
+}
+
+// NewReconciliationController creates a new reconciliation controller
+func NewReconciliationController(reconciler *service.Reconciler, autoCorrect bool) *ReconciliationController {
+	return &ReconciliationController{
+		reconciler:  reconciler,
+		autoCorrect: autoCorrect,
+	}
+}
+
+// Run handles POST /reconciliation/run. auto_correct=true or false overrides
+// RECONCILE_AUTO_CORRECT for this run.
+func (rc *ReconciliationController) Run(w http.ResponseWriter, r *http.Request) {
+	autoCorrect := rc.autoCorrect
+	if value := r.URL.Query().Get("auto_correct"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "auto_correct must be true or false", err)
+			return
+		}
+		autoCorrect = parsed
+	}
+
+	report := rc.reconciler.Reconcile(r.Context(), service.ReconcileManual, autoCorrect)
+	if report.Error != "" {
+		RespondWithJSON(w, http.StatusBadGateway, report)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
+
+// GetReports handles GET /reconciliation/reports
+func (rc *ReconciliationController) GetReports(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+
+	reports := rc.reconciler.Reports(limit)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// GetExceptions handles GET /reconciliation/exceptions, optionally filtered by status
+func (rc *ReconciliationController) GetExceptions(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != model.ExceptionOpen && status != model.ExceptionResolved {
+		RespondWithError(w, http.StatusBadRequest, "status must be OPEN or RESOLVED", nil)
+		return
+	}
+
+	exceptions := rc.reconciler.Exceptions(status)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"exceptions": exceptions,
+		"count":      len(exceptions),
+	})
+}
+
+// ResolveException handles POST /reconciliation/exceptions/{exceptionID}/resolve
+func (rc *ReconciliationController) ResolveException(w http.ResponseWriter, r *http.Request) {
+	var req model.ResolveExceptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	exception, err := rc.reconciler.Resolve(mux.Vars(r)["exceptionID"], &req)
+	if errors.Is(err, service.ErrExceptionNotFound) {
+		RespondWithError(w, http.StatusNotFound, "Exception not found", nil)
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot resolve exception", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, exception)
+}
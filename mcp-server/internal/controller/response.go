@@ -2,8 +2,10 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/aibanking/shared/channel"
 	"github.com/rs/zerolog/log"
 )
 
@@ -38,3 +40,30 @@ func RespondWithError(w http.ResponseWriter, code int, message string, err error
 	RespondWithJSON(w, code, response)
 }
 
+// normalizeChannel replaces a request's channel with its canonical spelling, answering
+// 400 with the allowed channels when it is not one of them
+func normalizeChannel(w http.ResponseWriter, raw *string) bool {
+	c, err := channel.Parse(*raw)
+	if err != nil {
+		log.Warn().Err(err).Msg("Request with invalid channel")
+		RespondWithJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid channel",
+			"code":    http.StatusBadRequest,
+			"details": err.Error(),
+			"allowed": channel.Allowed(),
+		})
+		return false
+	}
+	*raw = string(c)
+	return true
+}
+
+// writeEvent writes one server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("event", event).Msg("Failed to marshal event")
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// RetentionController handles retention policies, purges and legal holds
+type RetentionController struct {
+	retentionManager *service.RetentionManager
+	enabled          bool
+}
+
+// NewRetentionController creates a new retention controller
+func NewRetentionController(retentionManager *service.RetentionManager, enabled bool) *RetentionController {
+	return &RetentionController{
+		retentionManager: retentionManager,
+		enabled:          enabled,
+	}
+}
+
+// GetPolicies handles GET /retention/policies
+func (rc *RetentionController) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"scheduled": rc.enabled,
+		"policies":  rc.retentionManager.Policies(),
+	})
+}
+
+// Purge handles POST /retention/purge. With dry_run=true it reports what would be
+// purged without removing anything.
+func (rc *RetentionController) Purge(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	RespondWithJSON(w, http.StatusOK, rc.retentionManager.Purge(r.Context(), service.PurgeManual, dryRun))
+}
+
+// GetReports handles GET /retention/reports
+func (rc *RetentionController) GetReports(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+
+	reports := rc.retentionManager.Reports(limit)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// GetHolds handles GET /retention/holds
+func (rc *RetentionController) GetHolds(w http.ResponseWriter, r *http.Request) {
+	holds := rc.retentionManager.Holds()
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"holds": holds,
+		"count": len(holds),
+	})
+}
+
+// PlaceHold handles PUT /retention/holds/{userID}
+func (rc *RetentionController) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var req model.LegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	hold, err := rc.retentionManager.PlaceHold(mux.Vars(r)["userID"], &req)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid legal hold", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, hold)
+}
+
+// ReleaseHold handles DELETE /retention/holds/{userID}
+func (rc *RetentionController) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	if !rc.retentionManager.ReleaseHold(userID) {
+		RespondWithError(w, http.StatusNotFound, "Legal hold not found", nil)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Legal hold released",
+		"user_id": userID,
+	})
+}
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/aibanking/mcp-server/internal/service"
 )
@@ -10,12 +12,14 @@ import (
 // RuleController handles rule-related HTTP requests
 type RuleController struct {
 	ruleEngine *service.RuleEngine
+	plans      *service.PlanStore
 }
 
 // NewRuleController creates a new rule controller
-func NewRuleController(ruleEngine *service.RuleEngine) *RuleController {
+func NewRuleController(ruleEngine *service.RuleEngine, plans *service.PlanStore) *RuleController {
 	return &RuleController{
 		ruleEngine: ruleEngine,
+		plans:      plans,
 	}
 }
 
@@ -27,6 +31,29 @@ func (rc *RuleController) UploadRules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Channel rules are keyed by the channel's canonical spelling, so they match the
+	// normalised channel of tasks
+	normalized := make(map[string]interface{}, len(rules))
+	for key, rule := range rules {
+		if raw, ok := strings.CutPrefix(key, "channel:"); ok {
+			if !normalizeChannel(w, &raw) {
+				return
+			}
+			key = "channel:" + raw
+		}
+		normalized[key] = rule
+	}
+	rules = normalized
+
+	// A rule may only reference a plan that exists
+	for key, rule := range rules {
+		ruleMap, _ := rule.(map[string]interface{})
+		if planID, ok := ruleMap["plan_id"].(string); ok && planID != "" && !rc.plans.Exists(planID) {
+			RespondWithError(w, http.StatusBadRequest, "Rule references an unknown orchestration plan", fmt.Errorf("%s: plan %s not found", key, planID))
+			return
+		}
+	}
+
 	if err := rc.ruleEngine.UploadRules(rules); err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Failed to upload rules", err)
 		return
@@ -2,10 +2,12 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/aibanking/mcp-server/internal/model"
 	"github.com/aibanking/mcp-server/internal/service"
+	"github.com/gorilla/mux"
 )
 
 // SessionController handles session-related HTTP requests
@@ -22,7 +24,7 @@ func NewSessionController(sessionManager *service.SessionManager) *SessionContro
 
 // GetSession handles GET /get-session/{sessionID}
 func (sc *SessionController) GetSession(w http.ResponseWriter, r *http.Request) {
-	sessionID := r.PathValue("sessionID")
+	sessionID := mux.Vars(r)["sessionID"]
 	if sessionID == "" {
 		RespondWithError(w, http.StatusBadRequest, "Session ID is required", nil)
 		return
@@ -34,17 +36,7 @@ func (sc *SessionController) GetSession(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := &model.SessionResponse{
-		SessionID:   session.SessionID,
-		UserID:      session.UserID,
-		Channel:     session.Channel,
-		Context:     session.Context,
-		TaskHistory: session.TaskHistory,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
-	}
-
-	RespondWithJSON(w, http.StatusOK, response)
+	RespondWithJSON(w, http.StatusOK, sessionResponse(session))
 }
 
 // CreateSession handles POST /create-session
@@ -60,6 +52,9 @@ func (sc *SessionController) CreateSession(w http.ResponseWriter, r *http.Reques
 		RespondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
 
 	session, err := sc.sessionManager.CreateSession(r.Context(), &req)
 	if err != nil {
@@ -67,16 +62,91 @@ func (sc *SessionController) CreateSession(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	response := &model.SessionResponse{
+	RespondWithJSON(w, http.StatusCreated, sessionResponse(session))
+}
+
+// BindSession handles PUT /session/{sessionID}, binding a session ID the caller chose.
+// The session is created under that ID if it is unknown (201), or has the request's
+// context merged into its own (200); the response carries the session's context and
+// task history either way, so both sides agree on the conversation.
+func (sc *SessionController) BindSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionID"]
+
+	var req model.SessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	if req.UserID == "" || req.Channel == "" {
+		RespondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
+		return
+	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
+
+	session, created, err := sc.sessionManager.BindSession(r.Context(), sessionID, &req)
+	if respondIfSessionRefused(w, err) {
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to bind session", err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	RespondWithJSON(w, status, sessionResponse(session))
+}
+
+// DeleteSession handles DELETE /session/{sessionID}
+func (sc *SessionController) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionID"]
+	if sessionID == "" {
+		RespondWithError(w, http.StatusBadRequest, "Session ID is required", nil)
+		return
+	}
+
+	if err := sc.sessionManager.DeleteSession(r.Context(), sessionID); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Session not found", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Session deleted",
+		"session_id": sessionID,
+	})
+}
+
+// sessionResponse is the API view of a session
+func sessionResponse(session *model.Session) *model.SessionResponse {
+	return &model.SessionResponse{
 		SessionID:   session.SessionID,
 		UserID:      session.UserID,
 		Channel:     session.Channel,
 		Context:     session.Context,
 		TaskHistory: session.TaskHistory,
+		Sandbox:     session.Sandbox,
 		CreatedAt:   session.CreatedAt,
 		UpdatedAt:   session.UpdatedAt,
+		ExpiresAt:   session.ExpiresAt,
 	}
-
-	RespondWithJSON(w, http.StatusCreated, response)
 }
 
+// respondIfSessionRefused answers a session ID the caller may not use: 400 for a
+// malformed one, 409 for one bound to another user
+func respondIfSessionRefused(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidSessionID):
+		RespondWithError(w, http.StatusBadRequest, "Invalid session ID", err)
+	case errors.Is(err, service.ErrSessionOwner):
+		RespondWithError(w, http.StatusConflict, "Session belongs to another user", err)
+	case errors.Is(err, service.ErrStatelessSession):
+		RespondWithError(w, http.StatusBadRequest, "Stateless tasks take no session_id", err)
+	default:
+		return false
+	}
+	return true
+}
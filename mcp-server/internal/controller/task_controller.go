@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aibanking/mcp-server/internal/model"
 	"github.com/aibanking/mcp-server/internal/service"
@@ -14,16 +18,23 @@ import (
 type TaskController struct {
 	orchestrator *service.Orchestrator
 	taskManager  *service.TaskManager
+	stateless     bool          // Stateless submissions accepted
+	statelessWait time.Duration // How long a stateless submission waits for its result
 }
 
 // NewTaskController creates a new task controller
-func NewTaskController(orchestrator *service.Orchestrator, taskManager *service.TaskManager) *TaskController {
+func NewTaskController(orchestrator *service.Orchestrator, taskManager *service.TaskManager, stateless bool, statelessWait time.Duration) *TaskController {
 	return &TaskController{
-		orchestrator: orchestrator,
-		taskManager:  taskManager,
+		orchestrator:  orchestrator,
+		taskManager:   taskManager,
+		stateless:     stateless,
+		statelessWait: statelessWait,
 	}
 }
 
+// StatelessHeader asks for a stateless task, the same as "stateless": true in the body
+const StatelessHeader = "X-Stateless"
+
 // SubmitTask handles POST /submit-task
 func (tc *TaskController) SubmitTask(w http.ResponseWriter, r *http.Request) {
 	var req model.TaskRequest
@@ -37,17 +48,55 @@ func (tc *TaskController) SubmitTask(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Missing required fields", nil)
 		return
 	}
+	if !normalizeChannel(w, &req.Channel) {
+		return
+	}
+	if r.Header.Get(StatelessHeader) == "true" {
+		req.Stateless = true
+	}
+	if req.Stateless && !tc.stateless {
+		RespondWithError(w, http.StatusBadRequest, "Stateless mode is disabled", nil)
+		return
+	}
 
 	// Process task
 	response, err := tc.orchestrator.ProcessTask(r.Context(), &req)
+	if respondIfQueueFull(w, err) || respondIfHoldFull(w, err) || respondIfReplayed(w, err) {
+		return
+	}
+	if respondIfSessionRefused(w, err) || respondIfIntentDisabled(w, err) {
+		return
+	}
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Failed to process task", err)
 		return
 	}
 
+	if req.Stateless {
+		tc.respondStateless(w, r, response)
+		return
+	}
 	RespondWithJSON(w, http.StatusAccepted, response)
 }
 
+// respondStateless waits for a stateless task and answers with its result, as
+// GET /get-result would, so the caller never has to poll. A task that is held, or
+// still running when the wait is up, is answered 202 with its submission response.
+func (tc *TaskController) respondStateless(w http.ResponseWriter, r *http.Request, response *model.TaskResponse) {
+	ctx, cancel := context.WithTimeout(r.Context(), tc.statelessWait)
+	defer cancel()
+
+	task, err := tc.taskManager.Await(ctx, response.TaskID)
+	if err != nil || !task.Status.IsTerminal() {
+		if task != nil {
+			response.Status = string(task.Status)
+		}
+		RespondWithJSON(w, http.StatusAccepted, response)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, tc.resultResponse(task))
+}
+
 // GetTaskResult handles GET /get-result/{taskID}
 func (tc *TaskController) GetTaskResult(w http.ResponseWriter, r *http.Request) {
 	// Get taskID from URL path using gorilla/mux
@@ -83,16 +132,313 @@ func (tc *TaskController) GetTaskResult(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := &model.TaskResultResponse{
-		TaskID:      task.TaskID,
-		Status:      string(task.Status),
-		Result:      task.Result,
-		RiskScore:   task.RiskScore,
-		Explanation: task.Explanation,
-		Error:       task.Error,
-		CompletedAt: task.CompletedAt,
+	RespondWithJSON(w, http.StatusOK, tc.resultResponse(task))
+}
+
+// StreamTaskEvents handles GET /task/{taskID}/events, sending the task as server-sent
+// events: a "progress" event whenever it changes while running, then a "done" event
+// once it has finished
+func (tc *TaskController) StreamTaskEvents(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+
+	if _, err := tc.taskManager.GetTask(r.Context(), taskID); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Task not found", err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		RespondWithError(w, http.StatusInternalServerError, "Streaming not supported", nil)
+		return
 	}
 
-	RespondWithJSON(w, http.StatusOK, response)
+	updates, unsubscribe := tc.taskManager.Subscribe(taskID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		task, err := tc.taskManager.GetTask(r.Context(), taskID)
+		if err != nil {
+			writeEvent(w, "error", map[string]string{"error": err.Error()})
+			flusher.Flush()
+			return
+		}
+
+		if task.Status.IsTerminal() {
+			writeEvent(w, "done", tc.resultResponse(task))
+			flusher.Flush()
+			return
+		}
+		writeEvent(w, "progress", tc.resultResponse(task))
+		flusher.Flush()
+
+		// Resend periodically so idle connections are kept open
+		select {
+		case <-r.Context().Done():
+			return
+		case <-updates:
+		case <-time.After(taskEventKeepAlive):
+		}
+	}
+}
+
+// taskEventKeepAlive is how often an unchanged task is resent on an event stream
+const taskEventKeepAlive = 15 * time.Second
+
+// resultResponse builds the client view of a task
+func (tc *TaskController) resultResponse(task *model.Task) *model.TaskResultResponse {
+	return &model.TaskResultResponse{
+		TaskID:              task.TaskID,
+		Status:              string(task.Status),
+		Result:              task.Result,
+		ResultSchema:        task.ResultSchema,
+		RiskScore:           task.RiskScore,
+		Explanation:         task.Explanation,
+		Error:               task.Error,
+		Simulated:           task.Sandbox,
+		CompletedAt:         task.CompletedAt,
+		EstimatedCompletion: tc.taskManager.EstimateCompletion(task),
+		Progress:            tc.taskManager.Progress(task),
+		Diagnostics:         task.Diagnostics,
+		SLA:                 task.SLA,
+		AuthChallenge:       task.AuthChallenge,
+		Hold:                task.Hold,
+		PlanRun:             task.PlanRun,
+		Split:               task.Split,
+		Handover:            task.Handover,
+	}
+}
+
+// RequeueTask handles POST /task/{taskID}/requeue
+func (tc *TaskController) RequeueTask(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if taskID == "" {
+		RespondWithError(w, http.StatusBadRequest, "Task ID is required", nil)
+		return
+	}
+
+	if _, err := tc.taskManager.GetTask(r.Context(), taskID); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Task not found", err)
+		return
+	}
+
+	response, err := tc.orchestrator.RequeueTask(r.Context(), taskID)
+	if respondIfQueueFull(w, err) || respondIfHoldFull(w, err) {
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusConflict, "Failed to requeue task", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, response)
 }
 
+// CancelTask handles POST /task/{taskID}/cancel. The body, with a reason, is optional.
+func (tc *TaskController) CancelTask(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+
+	var req model.TaskCancelRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
+			return
+		}
+	}
+
+	if _, err := tc.taskManager.GetTask(r.Context(), taskID); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Task not found", err)
+		return
+	}
+
+	task, err := tc.orchestrator.CancelTask(r.Context(), taskID, req.Reason)
+	if errors.Is(err, service.ErrTaskNotCancellable) || errors.Is(err, service.ErrTaskCancelled) {
+		RespondWithError(w, http.StatusConflict, "Task cannot be cancelled", err)
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to cancel task", err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, tc.resultResponse(task))
+}
+
+// GetQueueStats handles GET /queue/stats
+func (tc *TaskController) GetQueueStats(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, tc.orchestrator.QueueStats())
+}
+
+// Draining reports whether the instance is shutting down, so it is no longer ready
+// for traffic
+func (tc *TaskController) Draining() bool {
+	return tc.orchestrator.Draining()
+}
+
+// GetHeldTasks handles GET /queue/held
+func (tc *TaskController) GetHeldTasks(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, tc.orchestrator.HoldStats())
+}
+
+// GetSLAStats handles GET /sla/stats
+func (tc *TaskController) GetSLAStats(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"intents": tc.orchestrator.SLAStats(),
+	})
+}
+
+// GetSLABreaches handles GET /sla/breaches
+func (tc *TaskController) GetSLABreaches(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	breaches := tc.orchestrator.SLABreaches(r.URL.Query().Get("intent"), limit)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"breaches": breaches,
+		"count":    len(breaches),
+	})
+}
+
+// GetSplit handles GET /splits/{splitID}
+func (tc *TaskController) GetSplit(w http.ResponseWriter, r *http.Request) {
+	proposal, ok := tc.orchestrator.Split(mux.Vars(r)["splitID"])
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Split not found", nil)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, proposal)
+}
+
+// ConfirmSplit handles POST /splits/{splitID}/confirm. The transfer then runs, or
+// waits for step-up authentication, and the client polls it as usual.
+func (tc *TaskController) ConfirmSplit(w http.ResponseWriter, r *http.Request) {
+	response, err := tc.orchestrator.ConfirmSplit(r.Context(), mux.Vars(r)["splitID"])
+	if respondIfQueueFull(w, err) || respondIfSplitError(w, err) {
+		return
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to confirm split", err)
+		return
+	}
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// DeclineSplit handles POST /splits/{splitID}/decline
+func (tc *TaskController) DeclineSplit(w http.ResponseWriter, r *http.Request) {
+	proposal, err := tc.orchestrator.DeclineSplit(r.Context(), mux.Vars(r)["splitID"])
+	if respondIfSplitError(w, err) {
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, proposal)
+}
+
+// respondIfSplitError answers 404 for an unknown split and 409 for one that no longer
+// waits for confirmation
+func respondIfSplitError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrSplitNotFound):
+		RespondWithError(w, http.StatusNotFound, "Split not found", nil)
+	case errors.Is(err, service.ErrSplitClosed):
+		RespondWithError(w, http.StatusConflict, "Split is no longer waiting for confirmation", err)
+	default:
+		return false
+	}
+	return true
+}
+
+// GetFastPathAudit handles GET /routing/fast-path
+func (tc *TaskController) GetFastPathAudit(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	records := tc.orchestrator.FastPathAudit(r.URL.Query().Get("user_id"), limit)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"records": records,
+		"count":   len(records),
+	})
+}
+
+// GetAgentDiagnostics handles GET /agents/diagnostics
+func (tc *TaskController) GetAgentDiagnostics(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"agents": tc.orchestrator.AgentDiagnostics(),
+	})
+}
+
+// respondIfQueueFull answers 429 with Retry-After when err is a full execution queue,
+// and 503 when the instance is draining: a retry reaches another instance at once
+func respondIfQueueFull(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, service.ErrDraining) {
+		w.Header().Set("Retry-After", "1")
+		RespondWithError(w, http.StatusServiceUnavailable, "Server is shutting down, retry", err)
+		return true
+	}
+
+	var full *service.QueueFullError
+	if !errors.As(err, &full) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(full.RetryAfter.Seconds())))
+	RespondWithError(w, http.StatusTooManyRequests, "Server is busy, retry later", err)
+	return true
+}
+
+// respondIfHoldFull answers 503 with Retry-After when a task's agent type is down and
+// the hold queue has no room for it
+func respondIfHoldFull(w http.ResponseWriter, err error) bool {
+	var full *service.HoldQueueFullError
+	if !errors.As(err, &full) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(full.RetryAfter.Seconds())))
+	RespondWithError(w, http.StatusServiceUnavailable, "No agent available, retry later", err)
+	return true
+}
+
+// respondIfIntentDisabled answers 403 when the task's intent is switched off, with the
+// flag's mode and its message for the user, if it has one
+func respondIfIntentDisabled(w http.ResponseWriter, err error) bool {
+	var disabled *service.IntentDisabledError
+	if !errors.As(err, &disabled) {
+		return false
+	}
+
+	response := map[string]interface{}{
+		"error":   "Intent is switched off",
+		"code":    http.StatusForbidden,
+		"details": err.Error(),
+		"intent":  disabled.Flag.Intent,
+		"mode":    disabled.Flag.Mode,
+	}
+	if disabled.Flag.Message != "" {
+		response["message"] = disabled.Flag.Message
+	}
+	RespondWithJSON(w, http.StatusForbidden, response)
+	return true
+}
+
+// respondIfReplayed answers 409 when err refuses a reused nonce and 400 when the nonce
+// or timestamp is missing or stale
+func respondIfReplayed(w http.ResponseWriter, err error) bool {
+	var replay *service.ReplayError
+	if !errors.As(err, &replay) {
+		return false
+	}
+
+	status := http.StatusBadRequest
+	if replay.Reason == service.ReplayDuplicate {
+		status = http.StatusConflict
+	}
+	RespondWithError(w, status, "Submission refused as a possible replay", err)
+	return true
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aibanking/mcp-server/internal/service"
+)
+
+// WarmupController exposes agent pre-warming and the intent history behind it
+type WarmupController struct {
+	warmer *service.AgentWarmer
+}
+
+// NewWarmupController creates a new warmup controller
+func NewWarmupController(warmer *service.AgentWarmer) *WarmupController {
+	return &WarmupController{
+		warmer: warmer,
+	}
+}
+
+// GetStats handles GET /warmup/stats
+func (wc *WarmupController) GetStats(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, wc.warmer.Stats())
+}
+
+// GetPredictions handles GET /warmup/predictions?user_id=&hour=. Without a user the
+// predictions are for all users; without an hour they are for the current one.
+func (wc *WarmupController) GetPredictions(w http.ResponseWriter, r *http.Request) {
+	hour := -1
+	if value := r.URL.Query().Get("hour"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > 23 {
+			RespondWithError(w, http.StatusBadRequest, "hour must be between 0 and 23", err)
+			return
+		}
+		hour = parsed
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	hour, predictions := wc.warmer.Predictions(r.Context(), userID, hour)
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":     userID,
+		"hour":        hour,
+		"predictions": predictions,
+	})
+}
+
+// WarmNow handles POST /warmup/run, warming agents for the coming hour right away
+func (wc *WarmupController) WarmNow(w http.ResponseWriter, r *http.Request) {
+	records := wc.warmer.WarmScheduled(r.Context())
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"warmed": records,
+		"count":  len(records),
+	})
+}
@@ -0,0 +1,7 @@
+package middleware
+
+import "github.com/aibanking/shared/response"
+
+// ETagMiddleware tags successful GET responses with an ETag and answers a matching
+// If-None-Match with 304 Not Modified
+var ETagMiddleware = response.ETag
@@ -13,6 +13,7 @@ const (
 	AgentTypeGuardrail  AgentType = "GUARDRAIL"
 	AgentTypeClearance  AgentType = "CLEARANCE"
 	AgentTypeScoring    AgentType = "SCORING"
+	AgentTypeInsights   AgentType = "INSIGHTS"
 	AgentTypePayment    AgentType = "PAYMENT"
 	AgentTypeTrade      AgentType = "TRADE"
 	AgentTypeAuth       AgentType = "AUTH"
@@ -39,6 +40,7 @@ type Agent struct {
 	Rules        map[string]interface{} `json:"rules" db:"rules"`               // Routing rules
 	Metadata     map[string]interface{} `json:"metadata" db:"metadata"`
 	HealthCheck  string                 `json:"health_check,omitempty" db:"health_check"`
+	TenantID     string                 `json:"tenant_id,omitempty" db:"tenant_id"` // Dedicated to this tenant; empty for the shared pool
 	LastHealthAt time.Time              `json:"last_health_at" db:"last_health_at"`
 	RegisteredAt time.Time              `json:"registered_at" db:"registered_at"`
 	UpdatedAt    time.Time              `json:"updated_at" db:"updated_at"`
@@ -54,6 +56,7 @@ type AgentRegistrationRequest struct {
 	Rules        map[string]interface{} `json:"rules,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 	HealthCheck  string                 `json:"health_check,omitempty"`
+	TenantID     string                 `json:"tenant_id,omitempty"` // Serve only this tenant's tasks
 }
 
 // AgentResponse represents the agent registration response
@@ -62,6 +65,7 @@ type AgentResponse struct {
 	Name         string    `json:"name"`
 	Type         string    `json:"type"`
 	Status       string    `json:"status"`
+	TenantID     string    `json:"tenant_id,omitempty"`
 	RegisteredAt time.Time `json:"registered_at"`
 	Message      string    `json:"message"`
 }
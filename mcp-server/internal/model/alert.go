@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Alert statuses
+const (
+	AlertFiring   = "FIRING"
+	AlertResolved = "RESOLVED"
+)
+
+// Alert severities
+const (
+	AlertSeverityWarning  = "WARNING"
+	AlertSeverityCritical = "CRITICAL"
+)
+
+// Alert conditions
+const (
+	AlertConditionFallbackRate     = "fallback_rate"
+	AlertConditionRedisDown        = "redis_down"
+	AlertConditionAgentUnreachable = "agent_unreachable"
+	AlertConditionLLMErrors        = "llm_error_spike"
+)
+
+// Alert is a degradation that was detected, and later resolved. An alert is keyed by
+// its condition and subject, so one condition can fire for several agents at once.
+type Alert struct {
+	Key            string                 `json:"key"` // condition:subject
+	Condition      string                 `json:"condition"`
+	Subject        string                 `json:"subject"` // e.g. the agent type or ID
+	Severity       string                 `json:"severity"`
+	Status         string                 `json:"status"`
+	Summary        string                 `json:"summary"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	FiredAt        time.Time              `json:"fired_at"`
+	ResolvedAt     *time.Time             `json:"resolved_at,omitempty"`
+	LastNotifiedAt time.Time              `json:"last_notified_at"`
+	Notifications  int                    `json:"notifications"`
+}
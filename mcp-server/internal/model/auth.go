@@ -0,0 +1,139 @@
+package model
+
+import "time"
+
+// AuthMethod is the authentication a task needs before it runs, weakest first
+type AuthMethod string
+
+const (
+	AuthNone          AuthMethod = "NONE"
+	AuthOTP           AuthMethod = "OTP"            // One-time code sent to the registered phone
+	AuthBiometric     AuthMethod = "BIOMETRIC"      // Assertion signed by a registered device after a biometric check
+	AuthHardwareToken AuthMethod = "HARDWARE_TOKEN" // Time-based code from the user's hardware token
+)
+
+// authStrength orders the methods from weakest to strongest
+var authStrength = map[AuthMethod]int{AuthNone: 0, AuthOTP: 1, AuthBiometric: 2, AuthHardwareToken: 3}
+
+// Stronger reports whether m is a stronger requirement than other
+func (m AuthMethod) Stronger(other AuthMethod) bool {
+	return authStrength[m] > authStrength[other]
+}
+
+// Valid reports whether m is a known method
+func (m AuthMethod) Valid() bool {
+	_, ok := authStrength[m]
+	return ok
+}
+
+// Device trust levels
+const (
+	DeviceTrusted = "TRUSTED" // Registered for biometric sign-in, or with a well-earned history
+	DeviceKnown   = "KNOWN"   // Seen before, or not identified at all
+	DeviceUnknown = "UNKNOWN" // New or unidentified
+)
+
+// Auth challenge states
+const (
+	ChallengePending  = "PENDING"
+	ChallengeVerified = "VERIFIED"
+	ChallengeFailed   = "FAILED" // Out of attempts
+	ChallengeExpired  = "EXPIRED"
+	ChallengeUsed     = "USED" // A verified enrollment challenge a factor was registered with
+)
+
+// ChallengeEnrollment is the purpose of a challenge that proves the user before a
+// factor is registered; it belongs to no task
+const ChallengeEnrollment = "ENROLLMENT"
+
+// AuthPolicyRule requires a method of tasks that meet all of its conditions. Unset
+// conditions match anything.
+type AuthPolicyRule struct {
+	Name         string     `json:"name"`
+	MinRiskScore float64    `json:"min_risk_score,omitempty"`
+	MinAmount    float64    `json:"min_amount,omitempty"`
+	DeviceTrust  []string   `json:"device_trust,omitempty"` // TRUSTED, KNOWN, UNKNOWN
+	Channels     []string   `json:"channels,omitempty"`
+	Method       AuthMethod `json:"method"`
+}
+
+// AuthInput is what the policy decides on
+type AuthInput struct {
+	RiskScore   float64 `json:"risk_score"`
+	Amount      float64 `json:"amount"`
+	DeviceTrust string  `json:"device_trust"`
+	Channel     string  `json:"channel"`
+}
+
+// AuthDecision is the method a task needs and the rules that asked for it
+type AuthDecision struct {
+	Method       AuthMethod `json:"method"`
+	Rules        []string   `json:"rules,omitempty"`
+	FallbackFrom AuthMethod `json:"fallback_from,omitempty"` // The method the policy asked for, when the user cannot use it
+	Input        AuthInput  `json:"input"`
+}
+
+// AuthChallenge is the structured step-up challenge returned to the client. The
+// fields a client needs depend on the method: OTP gives where the code went, a
+// biometric challenge gives the nonce for the device to sign, and a hardware token
+// challenge gives the number of digits to enter.
+type AuthChallenge struct {
+	ChallengeID    string       `json:"challenge_id"`
+	TaskID         string       `json:"task_id,omitempty"`
+	Purpose        string       `json:"purpose,omitempty"` // ENROLLMENT for a challenge that proves the user before a factor is registered
+	UserID         string       `json:"user_id"`
+	Method         AuthMethod   `json:"method"`
+	Status         string       `json:"status"`
+	Decision       AuthDecision `json:"decision"`
+	VerifyPath     string       `json:"verify_path"`
+	CreatedAt      time.Time    `json:"created_at"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	AttemptsLeft   int          `json:"attempts_left"`
+	OTPDestination string       `json:"otp_destination,omitempty"` // Masked phone number
+	CodeLength     int          `json:"code_length,omitempty"`     // Digits of an OTP or token code
+	Nonce          string       `json:"nonce,omitempty"`           // Base64; a biometric assertion signs it
+	DeviceID       string       `json:"device_id,omitempty"`       // The device expected to sign
+	SandboxCode    string       `json:"sandbox_code,omitempty"`    // The OTP itself, only in sandbox and development
+	VerifiedAt     *time.Time   `json:"verified_at,omitempty"`
+}
+
+// AuthVerifyRequest answers a challenge. OTP and hardware token challenges take a
+// code; biometric challenges take the device and its signature over the nonce.
+type AuthVerifyRequest struct {
+	Code      string `json:"code,omitempty"`
+	DeviceID  string `json:"device_id,omitempty"`
+	Signature string `json:"signature,omitempty"` // Base64 ASN.1 ECDSA P-256 signature over SHA-256 of the nonce
+}
+
+// AuthEnrollmentRequest asks for a challenge that proves the user before a factor is
+// registered. Without a method the user's strongest factor is asked for, or an OTP
+// when they have none; OTP can always be asked for.
+type AuthEnrollmentRequest struct {
+	UserID   string     `json:"user_id"`
+	DeviceID string     `json:"device_id,omitempty"` // The registered device to sign, for biometric proof
+	Method   AuthMethod `json:"method,omitempty"`
+}
+
+// AuthDeviceRegistration registers a device's key for biometric assertions
+type AuthDeviceRegistration struct {
+	UserID      string `json:"user_id"`
+	DeviceID    string `json:"device_id"`
+	PublicKey   string `json:"public_key"`   // Base64 PKIX ECDSA P-256 public key
+	ChallengeID string `json:"challenge_id"` // A verified enrollment challenge of the user
+}
+
+// AuthTokenRegistration registers a user's hardware token by its TOTP seed
+type AuthTokenRegistration struct {
+	UserID      string `json:"user_id"`
+	Secret      string `json:"secret"` // Base32 seed
+	Serial      string `json:"serial,omitempty"`
+	ChallengeID string `json:"challenge_id"` // A verified enrollment challenge of the user
+}
+
+// AuthFactors lists the methods a user has registered
+type AuthFactors struct {
+	UserID      string   `json:"user_id"`
+	Devices     []string `json:"devices"`
+	TokenSerial string   `json:"token_serial,omitempty"`
+	HasToken    bool     `json:"has_token"`
+}
@@ -22,6 +22,9 @@ type RoutingDecision struct {
 	Confidence      float64                `json:"confidence"` // 0.0 to 1.0
 	Reason          string                 `json:"reason"`
 	AlternativeAgents []string             `json:"alternative_agents,omitempty"`
+	MissingAgentType  string               `json:"missing_agent_type,omitempty"` // Required type without a healthy agent; any agent selected is a fallback
+	PlanID            string               `json:"plan_id,omitempty"`            // Orchestration plan a routing rule chose; its steps run instead of the selected agent alone
+	SkippedChecks     []string             `json:"skipped_checks,omitempty"`     // Check agents a fast-path transfer skipped
 	Context         *Context               `json:"context"`
 }
 
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// DeviceProfile is what the server has learned about one of a user's devices: how
+// long and how much it has been used, whether its user has passed step-up checks on
+// it and where it is used from. Its trust score rises with that history and falls as
+// the device goes unused.
+type DeviceProfile struct {
+	UserID          string         `json:"user_id"`
+	DeviceID        string         `json:"device_id"`
+	FirstSeen       time.Time      `json:"first_seen"`
+	LastSeen        time.Time      `json:"last_seen"`
+	Seen            float64        `json:"seen"`              // Tasks from the device, decayed
+	StepUpSuccesses float64        `json:"step_up_successes"` // Step-up challenges passed, decayed
+	StepUpFailures  int            `json:"step_up_failures"`  // Wrong answers since the last pass
+	LastStepUpAt    *time.Time     `json:"last_step_up_at,omitempty"`
+	Locations       map[string]int `json:"locations,omitempty"` // Tasks per reported location
+	LastLocation    string         `json:"last_location,omitempty"`
+	TrustScore      float64        `json:"trust_score"` // 0 to 1
+	TrustLevel      string         `json:"trust_level"` // TRUSTED, KNOWN, UNKNOWN
+	DeviceRisk      float64        `json:"device_risk"` // 1 - trust score, as the risk checks read it
+	ScoredAt        time.Time      `json:"scored_at"`
+}
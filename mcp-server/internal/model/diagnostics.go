@@ -0,0 +1,32 @@
+package model
+
+// AgentDiagnostics reports how long an agent took and what it called to produce a
+// result, as returned by the Agent Mesh
+type AgentDiagnostics struct {
+	ProcessingMs   float64          `json:"processing_ms"`
+	CallsAttempted int              `json:"downstream_calls_attempted"`
+	CallsSucceeded int              `json:"downstream_calls_succeeded"`
+	FallbackUsed   bool             `json:"fallback_used"`       // Rules or mock data stood in for a downstream system
+	Fallbacks      []string         `json:"fallbacks,omitempty"` // What stood in and why, e.g. "model:ml_unavailable"
+	Calls          []DownstreamCall `json:"calls,omitempty"`
+}
+
+// DownstreamCall is one call an agent made to another service
+type DownstreamCall struct {
+	Target     string  `json:"target"`
+	Succeeded  bool    `json:"succeeded"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// AgentSLOStats summarizes the recent diagnostics of one agent type
+type AgentSLOStats struct {
+	AgentType             string  `json:"agent_type"`
+	Tasks                 int     `json:"tasks"` // Recent tasks the figures are computed over
+	P50Ms                 float64 `json:"p50_ms"`
+	P95Ms                 float64 `json:"p95_ms"`
+	P99Ms                 float64 `json:"p99_ms"`
+	MaxMs                 float64 `json:"max_ms"`
+	FallbackRate          float64 `json:"fallback_rate"`           // Share of tasks where a fallback was used
+	DownstreamCalls       int     `json:"downstream_calls"`        // Attempted
+	DownstreamSuccessRate float64 `json:"downstream_success_rate"` // 1 when no calls were made
+}
@@ -0,0 +1,103 @@
+package model
+
+import "time"
+
+// Data-subject request types
+const (
+	DSARAccess  = "ACCESS"  // Export everything held about the user
+	DSARErasure = "ERASURE" // Erase it, except what must be retained by law
+)
+
+// Data-subject request states. A request is RECEIVED until the requester's identity
+// is verified, then VERIFIED until it is processed.
+const (
+	DSARReceived   = "RECEIVED"
+	DSARVerified   = "VERIFIED"
+	DSARProcessing = "PROCESSING"
+	DSARCompleted  = "COMPLETED"
+	DSARRejected   = "REJECTED"
+	DSARFailed     = "FAILED"
+)
+
+// Outcomes for one store in a data-subject request
+const (
+	DSARStoreExported = "EXPORTED"
+	DSARStoreErased   = "ERASED"
+	DSARStoreRetained = "RETAINED" // Kept because a law requires it
+	DSARStoreFailed   = "FAILED"
+)
+
+// DSAR is a data-subject access or erasure request under GDPR or the DPDP Act,
+// tracked from receipt to a signed completion record
+type DSAR struct {
+	ID              string            `json:"id"`
+	UserID          string            `json:"user_id"`
+	Type            string            `json:"type"`
+	Regulation      string            `json:"regulation,omitempty"` // e.g. GDPR, DPDP
+	Status          string            `json:"status"`
+	RequestedBy     string            `json:"requested_by"`
+	Channel         string            `json:"channel,omitempty"`
+	ReceivedAt      time.Time         `json:"received_at"`
+	DueAt           time.Time         `json:"due_at"`
+	Overdue         bool              `json:"overdue"`
+	Verification    *DSARVerification `json:"verification,omitempty"`
+	Stores          []DSARStoreResult `json:"stores,omitempty"`
+	ExportAvailable bool              `json:"export_available,omitempty"`
+	Completion      *DSARCompletion   `json:"completion,omitempty"`
+	RejectedReason  string            `json:"rejected_reason,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	History         []DSARStateChange `json:"history"`
+}
+
+// DSARCreateRequest opens a data-subject request
+type DSARCreateRequest struct {
+	UserID      string `json:"user_id"`
+	Type        string `json:"type"`
+	Regulation  string `json:"regulation,omitempty"`
+	RequestedBy string `json:"requested_by"`
+	Channel     string `json:"channel,omitempty"`
+}
+
+// DSARVerification records how the requester was confirmed to be the data subject
+type DSARVerification struct {
+	Method     string    `json:"method"` // e.g. OTP, branch_kyc, video_kyc
+	Reference  string    `json:"reference,omitempty"`
+	VerifiedBy string    `json:"verified_by"` // The operator, from their API key
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// DSARRejectRequest closes a request without acting on it
+type DSARRejectRequest struct {
+	Reason string `json:"reason"`
+}
+
+// DSARStateChange is one transition in a request's history
+type DSARStateChange struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+	By     string    `json:"by,omitempty"`
+	Note   string    `json:"note,omitempty"`
+}
+
+// DSARStoreResult is what a request found in, or did to, one store
+type DSARStoreResult struct {
+	Store     string `json:"store"`
+	Items     int    `json:"items"`
+	Outcome   string `json:"outcome"`
+	Remaining *int   `json:"remaining,omitempty"` // Items found again after erasure
+	Note      string `json:"note,omitempty"`
+}
+
+// DSARCompletion is the signed record that a request was carried out. The signature
+// is an HMAC-SHA256 over the record's JSON with the signature left empty.
+type DSARCompletion struct {
+	RequestID    string            `json:"request_id"`
+	UserID       string            `json:"user_id"`
+	Type         string            `json:"type"`
+	CompletedAt  time.Time         `json:"completed_at"`
+	Stores       []DSARStoreResult `json:"stores"`
+	Verified     bool              `json:"verified"` // Erasure: nothing erasable was found again
+	ExportSHA256 string            `json:"export_sha256,omitempty"`
+	SignatureAlg string            `json:"signature_alg"`
+	Signature    string            `json:"signature"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// FastPathRecord is the audit entry of a transfer sent straight to the banking agent:
+// why it qualified and the checks it skipped
+type FastPathRecord struct {
+	TaskID             string    `json:"task_id"`
+	UserID             string    `json:"user_id"`
+	Intent             string    `json:"intent"`
+	Amount             float64   `json:"amount"`
+	ToAccount          string    `json:"to_account,omitempty"`
+	BeneficiaryAgeDays float64   `json:"beneficiary_age_days"`
+	RiskScore          float64   `json:"risk_score"`
+	DeviceRisk         *float64  `json:"device_risk,omitempty"`
+	SkippedChecks      []string  `json:"skipped_checks"` // Agent types that did not see the transfer
+	AgentID            string    `json:"agent_id"`
+	Sandbox            bool      `json:"sandbox,omitempty"`
+	RecordedAt         time.Time `json:"recorded_at"`
+}
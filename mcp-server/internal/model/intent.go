@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// IntentDefinition is a tenant's own intent, such as OPEN_RD or UPDATE_NOMINEE. The
+// AI Skin recognises it from its patterns and the MCP server routes it to an agent
+// advertising its capability.
+type IntentDefinition struct {
+	Intent      string         `json:"intent"`
+	TenantID    string         `json:"tenant_id,omitempty"` // Empty for every tenant
+	Description string         `json:"description"`
+	Example     string         `json:"example,omitempty"`
+	Patterns    []string       `json:"patterns"` // Case-insensitive regular expressions; any one matches
+	Entities    []IntentEntity `json:"entities,omitempty"`
+	Capability  string         `json:"capability"` // Agent capability that executes the intent
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// IntentEntity is a value extracted from input matching a custom intent
+type IntentEntity struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"` // Regular expression; its first group, or the whole match, is the value
+	Required bool   `json:"required,omitempty"`
+}
+
+// IntentDefinitionRequest registers or replaces a custom intent
+type IntentDefinitionRequest struct {
+	TenantID    string         `json:"tenant_id,omitempty"`
+	Description string         `json:"description"`
+	Example     string         `json:"example,omitempty"`
+	Patterns    []string       `json:"patterns"`
+	Entities    []IntentEntity `json:"entities,omitempty"`
+	Capability  string         `json:"capability"`
+}
+
+// IntentFlagMode is whether an intent may run
+type IntentFlagMode string
+
+const (
+	IntentEnabled  IntentFlagMode = "enabled"
+	IntentDisabled IntentFlagMode = "disabled"
+	IntentReadOnly IntentFlagMode = "read_only" // Only runs without side effects: sandbox tasks, or an intent that only reads
+)
+
+// IntentFlag switches an intent off, or back on, for every tenant and channel or for
+// one tenant, one channel or both. The most specific flag for a task applies.
+type IntentFlag struct {
+	Intent    string         `json:"intent"`
+	TenantID  string         `json:"tenant_id,omitempty"` // Empty for every tenant
+	Channel   string         `json:"channel,omitempty"`   // Empty for every channel
+	Mode      IntentFlagMode `json:"mode"`
+	Message   string         `json:"message,omitempty"`    // Told to users while the intent is off
+	Reason    string         `json:"reason,omitempty"`     // Why, for operators
+	UpdatedBy string         `json:"updated_by,omitempty"` // The operator, from their API key
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// IntentFlagRequest sets an intent's flag
+type IntentFlagRequest struct {
+	TenantID string         `json:"tenant_id,omitempty"`
+	Channel  string         `json:"channel,omitempty"`
+	Mode     IntentFlagMode `json:"mode"`
+	Message  string         `json:"message,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+}
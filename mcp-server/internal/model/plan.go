@@ -0,0 +1,96 @@
+package model
+
+import "time"
+
+// Step failure policies: what happens to the rest of a plan when a step fails. A plan's
+// policy applies to the steps that do not set their own.
+const (
+	PlanOnFailureAbort      = "ABORT"      // The task fails with the step's error
+	PlanOnFailureSkip       = "SKIP"       // Later stages run without the failed step
+	PlanOnFailureRetry      = "RETRY"      // The step is tried again, then the task fails
+	PlanOnFailureCompensate = "COMPENSATE" // The task fails and finished steps are undone
+)
+
+// Plan run and plan step statuses
+const (
+	PlanRunRunning     = "RUNNING"
+	PlanRunCompleted   = "COMPLETED"
+	PlanRunRejected    = "REJECTED" // A step decided against the request; later stages did not run
+	PlanRunFailed      = "FAILED"
+	PlanRunCompensated = "COMPENSATED" // A step failed and the steps before it were undone
+	PlanRunCancelled   = "CANCELLED"   // The task was cancelled; later stages did not run
+
+	PlanStepPending = "PENDING"
+	PlanStepRunning = "RUNNING"
+	PlanStepDone    = "DONE"
+	PlanStepFailed  = "FAILED"
+	PlanStepSkipped = "SKIPPED" // Not run because an earlier stage rejected or aborted
+)
+
+// PlanStep is one agent call in an orchestration plan. Steps of the same stage run in
+// parallel; stages run in ascending order.
+type PlanStep struct {
+	Name         string            `json:"name"` // e.g. "guardrail_check"
+	AgentType    string            `json:"agent_type"`
+	Stage        int               `json:"stage"`
+	Description  string            `json:"description,omitempty"`  // Shown in task progress
+	OnFailure    string            `json:"on_failure,omitempty"`   // ABORT, SKIP, RETRY or COMPENSATE; the plan's when empty
+	Retries      int               `json:"retries,omitempty"`      // Further attempts before the policy applies
+	Compensation *PlanCompensation `json:"compensation,omitempty"` // How to undo the step once it has succeeded
+}
+
+// PlanCompensation is the agent call that undoes a finished step, e.g. a reversal for
+// an executed transfer. It runs when a later step with the COMPENSATE policy fails.
+type PlanCompensation struct {
+	AgentType string `json:"agent_type"`
+	Action    string `json:"action"` // Sent to the agent as the task, e.g. "REVERSE_TRANSFER"
+}
+
+// OrchestrationPlan is a reusable template of which agents handle a task, in what
+// order and with what parallelism, e.g. "high-value transfer": guardrail and fraud
+// checks side by side, then the banking agent. Routing rules reference plans by ID.
+type OrchestrationPlan struct {
+	PlanID      string     `json:"plan_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Intents     []string   `json:"intents,omitempty"` // Intents the plan is meant for; empty means any
+	Steps       []PlanStep `json:"steps"`
+	OnFailure   string     `json:"on_failure"` // Default step policy: ABORT, SKIP, RETRY or COMPENSATE
+	Builtin     bool       `json:"builtin,omitempty"`
+	Version     int        `json:"version"` // Incremented on every update
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PlanStepRun is how one step of an executed plan went
+type PlanStepRun struct {
+	Name              string     `json:"name"`
+	AgentType         string     `json:"agent_type"`
+	AgentID           string     `json:"agent_id,omitempty"`
+	Stage             int        `json:"stage"`
+	Status            string     `json:"status"`     // PENDING, RUNNING, DONE, FAILED or SKIPPED
+	OnFailure         string     `json:"on_failure"` // The policy the step ran under
+	Attempts          int        `json:"attempts,omitempty"`
+	ResultStatus      string     `json:"result_status,omitempty"` // The agent's decision, e.g. APPROVED or REJECTED
+	RiskScore         float64    `json:"risk_score,omitempty"`
+	Error             string     `json:"error,omitempty"`
+	Compensation      string     `json:"compensation,omitempty"` // DONE or FAILED once the step was undone
+	CompensationError string     `json:"compensation_error,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	DurationMs        float64    `json:"duration_ms"`
+}
+
+// PlanRun is an executed instance of a plan for one task
+type PlanRun struct {
+	RunID       string        `json:"run_id"`
+	PlanID      string        `json:"plan_id"`
+	PlanVersion int           `json:"plan_version"`
+	TaskID      string        `json:"task_id"`
+	Intent      string        `json:"intent"`
+	Status      string        `json:"status"` // RUNNING, COMPLETED, REJECTED, FAILED or COMPENSATED
+	Steps       []PlanStepRun `json:"steps"`
+	StartedAt   time.Time     `json:"started_at"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty"`
+	DurationMs  float64       `json:"duration_ms"`
+}
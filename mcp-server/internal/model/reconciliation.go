@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// Kinds of disagreement between a transfer task and the DWH
+const (
+	MismatchMissingInDWH    = "MISSING_IN_DWH"    // The task completed but the DWH has no such transaction
+	MismatchStatus          = "STATUS_MISMATCH"   // The task and the DWH disagree on whether the transfer went through
+	MismatchAmount          = "AMOUNT_MISMATCH"   // The DWH moved a different amount than the task reports
+	MismatchNoTransactionID = "NO_TRANSACTION_ID" // The task completed without reporting a transaction ID
+)
+
+// Reconciliation exception statuses
+const (
+	ExceptionOpen     = "OPEN"
+	ExceptionResolved = "RESOLVED"
+)
+
+// ReconciliationReport records one reconciliation run
+type ReconciliationReport struct {
+	ID          string    `json:"id"`
+	Trigger     string    `json:"trigger"` // "schedule" or "manual"
+	AutoCorrect bool      `json:"auto_correct"`
+	WindowStart time.Time `json:"window_start"` // Tasks finished in this window were checked
+	WindowEnd   time.Time `json:"window_end"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Checked     int       `json:"checked"`
+	Matched     int       `json:"matched"`
+	Exceptions  int       `json:"exceptions"`
+	Corrected   int       `json:"corrected"`
+	Error       string    `json:"error,omitempty"` // Set when the DWH could not be reached
+}
+
+// ReconciliationException is a transfer task the DWH contradicts
+type ReconciliationException struct {
+	ID             string     `json:"id"`
+	ReportID       string     `json:"report_id"` // Run that first found it
+	TaskID         string     `json:"task_id"`
+	UserID         string     `json:"user_id"`
+	Intent         string     `json:"intent"`
+	TransactionID  string     `json:"transaction_id,omitempty"`
+	Kind           string     `json:"kind"`
+	TaskStatus     TaskStatus `json:"task_status"`
+	DWHStatus      string     `json:"dwh_status,omitempty"`
+	TaskAmount     float64    `json:"task_amount,omitempty"`
+	DWHAmount      float64    `json:"dwh_amount,omitempty"`
+	Detail         string     `json:"detail"`
+	Status         string     `json:"status"`                 // OPEN or RESOLVED
+	CorrectedTo    TaskStatus `json:"corrected_to,omitempty"` // Task status set by auto-correction
+	DetectedAt     time.Time  `json:"detected_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"` // Latest run that still found it
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy     string     `json:"resolved_by,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+}
+
+// ResolveExceptionRequest closes a reconciliation exception
+type ResolveExceptionRequest struct {
+	ResolvedBy string `json:"resolved_by"`
+	Note       string `json:"note"`
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Result schemas, named in a task's result_schema. A schema describes the result of the
+// agent that carries out an intent: a verdict of a check agent such as GUARDRAIL or
+// FRAUD, or a rejection, has none.
+const (
+	ResultSchemaBalance   = "BalanceResult"   // CHECK_BALANCE, from the banking agent
+	ResultSchemaTransfer  = "TransferResult"  // TRANSFER_*, from the banking agent
+	ResultSchemaStatement = "StatementResult" // GET_STATEMENT, from the banking agent
+	ResultSchemaScore     = "ScoreResult"     // CREDIT_SCORE, from the scoring agent
+)
+
+// ResultSchema is a typed task result that checks its own required fields. Results
+// are decoded into one before the task is marked COMPLETED.
+type ResultSchema interface {
+	Validate() error
+}
+
+// BalanceResult is the balance of one account
+type BalanceResult struct {
+	Balance   *float64  `json:"balance"`
+	Currency  string    `json:"currency"` // ISO 4217, e.g. INR
+	AccountID string    `json:"account_id,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+}
+
+// Validate checks the required fields
+func (r *BalanceResult) Validate() error {
+	if r.Balance == nil {
+		return fmt.Errorf("balance is required")
+	}
+	if len(r.Currency) != 3 {
+		return fmt.Errorf("currency must be a three-letter code, got %q", r.Currency)
+	}
+	return nil
+}
+
+// TransferResult is a transfer that was carried out
+type TransferResult struct {
+	Status        string    `json:"status"` // APPROVED or PENDING
+	TransactionID string    `json:"transaction_id"`
+	Amount        *float64  `json:"amount"`
+	ToAccount     string    `json:"to_account"`
+	FromAccount   string    `json:"from_account,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	ProcessedAt   time.Time `json:"processed_at,omitempty"`
+}
+
+// Validate checks the required fields
+func (r *TransferResult) Validate() error {
+	switch {
+	case r.Status != "APPROVED" && r.Status != "PENDING":
+		return fmt.Errorf("status must be APPROVED or PENDING, got %q", r.Status)
+	case r.TransactionID == "":
+		return fmt.Errorf("transaction_id is required")
+	case r.Amount == nil || *r.Amount <= 0:
+		return fmt.Errorf("amount must be positive")
+	case r.ToAccount == "":
+		return fmt.Errorf("to_account is required")
+	}
+	return nil
+}
+
+// StatementResult is the recent transactions of an account
+type StatementResult struct {
+	AccountID    string           `json:"account_id,omitempty"`
+	Transactions []StatementEntry `json:"transactions"`
+	Count        *int             `json:"count"`
+}
+
+// StatementEntry is one transaction on a statement
+type StatementEntry struct {
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"` // DEBIT or CREDIT
+	Amount        *float64  `json:"amount"`
+	Description   string    `json:"description,omitempty"`
+	Date          time.Time `json:"date,omitempty"`
+}
+
+// Validate checks the required fields
+func (r *StatementResult) Validate() error {
+	if r.Transactions == nil {
+		return fmt.Errorf("transactions is required")
+	}
+	if r.Count == nil || *r.Count != len(r.Transactions) {
+		return fmt.Errorf("count must be the number of transactions (%d)", len(r.Transactions))
+	}
+	for i, entry := range r.Transactions {
+		if entry.Type != "DEBIT" && entry.Type != "CREDIT" {
+			return fmt.Errorf("transactions[%d].type must be DEBIT or CREDIT, got %q", i, entry.Type)
+		}
+		if entry.Amount == nil {
+			return fmt.Errorf("transactions[%d].amount is required", i)
+		}
+	}
+	return nil
+}
+
+// ScoreResult is a credit score
+type ScoreResult struct {
+	CreditScore    *int   `json:"credit_score"` // 300 to 900
+	RiskCategory   string `json:"risk_category"`
+	ScoreRange     string `json:"score_range,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+// Validate checks the required fields
+func (r *ScoreResult) Validate() error {
+	if r.CreditScore == nil || *r.CreditScore < 300 || *r.CreditScore > 900 {
+		return fmt.Errorf("credit_score must be between 300 and 900")
+	}
+	if r.RiskCategory == "" {
+		return fmt.Errorf("risk_category is required")
+	}
+	return nil
+}
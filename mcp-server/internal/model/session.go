@@ -12,6 +12,7 @@ type Session struct {
 	Context     map[string]interface{} `json:"context" db:"context"`
 	Metadata    map[string]interface{} `json:"metadata" db:"metadata"`
 	TaskHistory []string               `json:"task_history" db:"task_history"` // Array of task IDs
+	Sandbox     bool                   `json:"sandbox,omitempty" db:"sandbox"` // Side effects go to the sandbox store
 	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
 	ExpiresAt   time.Time              `json:"expires_at" db:"expires_at"`
@@ -22,6 +23,7 @@ type SessionRequest struct {
 	UserID  string                 `json:"user_id" binding:"required"`
 	Channel string                 `json:"channel" binding:"required"`
 	Context map[string]interface{} `json:"context,omitempty"`
+	Sandbox bool                   `json:"sandbox,omitempty"`
 }
 
 // SessionResponse represents the session data response
@@ -31,7 +33,9 @@ type SessionResponse struct {
 	Channel     string                 `json:"channel"`
 	Context     map[string]interface{} `json:"context"`
 	TaskHistory []string               `json:"task_history"`
+	Sandbox     bool                   `json:"sandbox,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
+	ExpiresAt   time.Time              `json:"expires_at"`
 }
 
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// Split statuses
+const (
+	SplitProposed    = "PROPOSED"    // Waiting for the user to confirm
+	SplitConfirmed   = "CONFIRMED"   // Legs are being sent, or wait for their day
+	SplitCompleted   = "COMPLETED"   // Every leg went through
+	SplitDeclined    = "DECLINED"    // The user said no, or cancelled the task
+	SplitExpired     = "EXPIRED"     // Not confirmed in time
+	SplitRejected    = "REJECTED"    // A check rejected the transfer before any leg
+	SplitFailed      = "FAILED"      // A leg failed and the legs before it could not be undone
+	SplitCompensated = "COMPENSATED" // A leg failed and the legs before it were reversed
+
+	SplitLegPending   = "PENDING"
+	SplitLegScheduled = "SCHEDULED" // Waits for a later day's limit
+	SplitLegDone      = "DONE"
+	SplitLegFailed    = "FAILED"
+	SplitLegReversed  = "REVERSED"
+	SplitLegSkipped   = "SKIPPED" // Not sent because an earlier leg failed
+)
+
+// SplitLeg is one transfer of a split, sent over one rail on one day
+type SplitLeg struct {
+	Leg           int        `json:"leg"` // 1 is sent first
+	Rail          string     `json:"rail"`
+	Intent        string     `json:"intent"`
+	Amount        float64    `json:"amount"`
+	ScheduledFor  time.Time  `json:"scheduled_for"`
+	Status        string     `json:"status"`
+	TransactionID string     `json:"transaction_id,omitempty"`
+	ReversalID    string     `json:"reversal_id,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	ExecutedAt    *time.Time `json:"executed_at,omitempty"`
+}
+
+// SplitProposal is a transfer too large for its rail, offered to the user as legs
+// across rails and days within their limits. Nothing is sent until the user confirms;
+// the legs then run in order as a saga, and a leg that fails reverses those before it.
+type SplitProposal struct {
+	SplitID     string     `json:"split_id"`
+	TaskID      string     `json:"task_id"`
+	UserID      string     `json:"user_id"`
+	Intent      string     `json:"intent"`
+	Amount      float64    `json:"amount"`
+	Reason      string     `json:"reason"` // The limit the transfer is over
+	Legs        []SplitLeg `json:"legs"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message"` // What is offered, or how the split went
+	ConfirmPath string     `json:"confirm_path"`
+	DeclinePath string     `json:"decline_path"`
+	Checked     bool       `json:"checked,omitempty"` // The routed checks passed on the whole amount
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
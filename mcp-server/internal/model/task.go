@@ -13,26 +13,92 @@ const (
 	TaskStatusCompleted  TaskStatus = "COMPLETED"
 	TaskStatusFailed     TaskStatus = "FAILED"
 	TaskStatusRejected   TaskStatus = "REJECTED"
+
+	// Intermediate states while a task runs
+	TaskStatusWaiting   TaskStatus = "WAITING"   // Queued behind an earlier debit task of the same user
+	TaskStatusExecuting TaskStatus = "EXECUTING" // An agent is working on the task
+
+	// Held until the user answers a step-up authentication challenge
+	TaskStatusAwaitingAuth TaskStatus = "AWAITING_AUTH"
+
+	// Held until an agent of the required type registers or recovers
+	TaskStatusHeld TaskStatus = "HELD"
+
+	// Held until the user accepts splitting a transfer too large for its rail
+	TaskStatusAwaitingConfirmation TaskStatus = "AWAITING_CONFIRMATION"
+
+	// Part of a split transfer has been sent; the rest waits for a later day
+	TaskStatusScheduled TaskStatus = "SCHEDULED"
+
+	// Dropped because its client stopped waiting for it
+	TaskStatusCancelled TaskStatus = "CANCELLED"
 )
 
+// IsTerminal reports whether a task in this status has finished
+func (s TaskStatus) IsTerminal() bool {
+	return s == TaskStatusCompleted || s == TaskStatusFailed || s == TaskStatusRejected || s == TaskStatusCancelled
+}
+
+// IsHeld reports whether the task waits on something other than its agent: the user,
+// an agent of the type it needs, or a later day
+func (s TaskStatus) IsHeld() bool {
+	return s == TaskStatusAwaitingAuth || s == TaskStatusAwaitingConfirmation || s == TaskStatusHeld || s == TaskStatusScheduled
+}
+
+// IsRunning reports whether an instance is routing or executing the task, so it
+// finishes only if that instance keeps running
+func (s TaskStatus) IsRunning() bool {
+	return s == TaskStatusPending || s == TaskStatusProcessing || s == TaskStatusWaiting || s == TaskStatusExecuting
+}
+
+// Task step statuses
+const (
+	StepRunning = "RUNNING"
+	StepDone    = "DONE"
+	StepFailed  = "FAILED"
+)
+
+// TaskStep is one stage of a task's execution, for progress reporting
+type TaskStep struct {
+	Name        string     `json:"name"`        // e.g. "route", "fraud_check", "execute"
+	Description string     `json:"description"` // Human-readable, e.g. "Checking for fraud"
+	AgentID     string     `json:"agent_id,omitempty"`
+	AgentType   string     `json:"agent_type,omitempty"`
+	Status      string     `json:"status"` // RUNNING, DONE, FAILED
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
 // Task represents a banking task submitted to the MCP server
 type Task struct {
-	TaskID      string                 `json:"task_id" db:"task_id"`
-	SessionID   string                 `json:"session_id" db:"session_id"`
-	UserID      string                 `json:"user_id" db:"user_id"`
-	Channel     string                 `json:"channel" db:"channel"` // MB, NB, Trade, etc.
-	Intent      string                 `json:"intent" db:"intent"`   // TRANSFER_NEFT, CHECK_BALANCE, etc.
-	Status      TaskStatus             `json:"status" db:"status"`
-	Data        map[string]interface{} `json:"data" db:"data"`
-	Context     map[string]interface{} `json:"context" db:"context"`
-	AgentID     string                 `json:"agent_id,omitempty" db:"agent_id"`
-	Result      map[string]interface{} `json:"result,omitempty" db:"result"`
-	Error       string                 `json:"error,omitempty" db:"error"`
-	RiskScore   float64                `json:"risk_score,omitempty" db:"risk_score"`
-	Explanation string                 `json:"explanation,omitempty" db:"explanation"`
-	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
-	CompletedAt *time.Time             `json:"completed_at,omitempty" db:"completed_at"`
+	TaskID        string                 `json:"task_id" db:"task_id"`
+	SessionID     string                 `json:"session_id" db:"session_id"`
+	UserID        string                 `json:"user_id" db:"user_id"`
+	Channel       string                 `json:"channel" db:"channel"` // MB, NB, Trade, etc.
+	Intent        string                 `json:"intent" db:"intent"`   // TRANSFER_NEFT, CHECK_BALANCE, etc.
+	Status        TaskStatus             `json:"status" db:"status"`
+	Data          map[string]interface{} `json:"data" db:"data"`
+	Context       map[string]interface{} `json:"context" db:"context"`
+	AgentID       string                 `json:"agent_id,omitempty" db:"agent_id"`
+	Result        map[string]interface{} `json:"result,omitempty" db:"result"`
+	ResultSchema  string                 `json:"result_schema,omitempty" db:"result_schema"` // Schema the result was checked against, e.g. TransferResult
+	Error         string                 `json:"error,omitempty" db:"error"`
+	RiskScore     float64                `json:"risk_score,omitempty" db:"risk_score"`
+	Explanation   string                 `json:"explanation,omitempty" db:"explanation"`
+	Sandbox       bool                   `json:"sandbox,omitempty" db:"sandbox"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at" db:"updated_at"`
+	CompletedAt   *time.Time             `json:"completed_at,omitempty" db:"completed_at"`
+	Progress      []TaskStep             `json:"progress,omitempty" db:"progress"`
+	Diagnostics   *AgentDiagnostics      `json:"diagnostics,omitempty" db:"diagnostics"`
+	SLA           *TaskSLA               `json:"sla,omitempty" db:"sla"`
+	AuthChallenge *AuthChallenge         `json:"auth_challenge,omitempty" db:"auth_challenge"`
+	Hold          *TaskHold              `json:"hold,omitempty" db:"hold"`
+	PlanRun       *PlanRun               `json:"plan_run,omitempty" db:"plan_run"`   // Set when an orchestration plan ran the task
+	Split         *SplitProposal         `json:"split,omitempty" db:"split"`         // Set when the transfer was offered as a split
+	Stateless     bool                   `json:"stateless,omitempty" db:"stateless"` // No session; kept in memory only
+	Instance      string                 `json:"instance,omitempty" db:"instance"`   // MCP server instance that runs the task
+	Handover      *TaskHandover          `json:"handover,omitempty" db:"handover"`   // Set when a stopped instance left the task unfinished
 }
 
 // TaskRequest represents the incoming task submission request
@@ -43,25 +109,180 @@ type TaskRequest struct {
 	Intent    string                 `json:"intent" binding:"required"`
 	Data      map[string]interface{} `json:"data" binding:"required"`
 	Context   map[string]interface{} `json:"context,omitempty"`
+	Sandbox   bool                   `json:"sandbox,omitempty"`   // Route side effects to the sandbox store
+	Stateless bool                   `json:"stateless,omitempty"` // No session or history; the submission waits for the result
+
+	// Replay protection, required for money-moving intents: a unique client nonce and
+	// the time the request was made
+	Nonce     string     `json:"nonce,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
 // TaskResponse represents the response after task submission
 type TaskResponse struct {
-	TaskID    string    `json:"task_id"`
-	SessionID string    `json:"session_id"`
-	Status    string    `json:"status"`
-	Message   string    `json:"message"`
-	CreatedAt time.Time `json:"created_at"`
+	TaskID        string         `json:"task_id"`
+	SessionID     string         `json:"session_id"`
+	Status        string         `json:"status"`
+	Message       string         `json:"message"`
+	CreatedAt     time.Time      `json:"created_at"`
+	AuthChallenge *AuthChallenge `json:"auth_challenge,omitempty"` // Set when the task waits for step-up authentication
+	Hold          *TaskHold      `json:"hold,omitempty"`           // Set when the task waits for an agent
+	Split         *SplitProposal `json:"split,omitempty"`          // Set when the task waits for the user to accept a split
 }
 
 // TaskResultResponse represents the result of a completed task
 type TaskResultResponse struct {
-	TaskID      string                 `json:"task_id"`
-	Status      string                 `json:"status"`
-	Result      map[string]interface{} `json:"result,omitempty"`
-	RiskScore   float64                `json:"risk_score,omitempty"`
-	Explanation string                 `json:"explanation,omitempty"`
-	Error       string                 `json:"error,omitempty"`
-	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	TaskID              string                 `json:"task_id"`
+	Status              string                 `json:"status"`
+	Result              map[string]interface{} `json:"result,omitempty"`
+	ResultSchema        string                 `json:"result_schema,omitempty"` // BalanceResult, TransferResult, StatementResult or ScoreResult
+	RiskScore           float64                `json:"risk_score,omitempty"`
+	Explanation         string                 `json:"explanation,omitempty"`
+	Error               string                 `json:"error,omitempty"`
+	Simulated           bool                   `json:"simulated,omitempty"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"` // Hint for pollers while the task runs
+	Progress            []TaskStep             `json:"progress,omitempty"`
+	Diagnostics         *AgentDiagnostics      `json:"diagnostics,omitempty"` // How the agent produced the result
+	SLA                 *TaskSLA               `json:"sla,omitempty"`
+	AuthChallenge       *AuthChallenge         `json:"auth_challenge,omitempty"`
+	Hold                *TaskHold              `json:"hold,omitempty"`
+	PlanRun             *PlanRun               `json:"plan_run,omitempty"` // Per-step timings when an orchestration plan ran the task
+	Split               *SplitProposal         `json:"split,omitempty"`    // The legs of a split transfer
+	Handover            *TaskHandover          `json:"handover,omitempty"` // How the task outlived the instance that started it
+}
+
+// QueueStats reports the load on the task execution pipeline
+type QueueStats struct {
+	Depth             int   `json:"depth"` // Tasks admitted and not yet finished
+	HighWatermark     int   `json:"high_watermark"`
+	LowWatermark      int   `json:"low_watermark"`
+	Saturated         bool  `json:"saturated"` // New tasks are being refused
+	Draining          bool  `json:"draining"`  // The instance is shutting down and takes no tasks
+	Rejected          int64 `json:"rejected"`
+	RetryAfterSeconds int   `json:"retry_after_seconds"`
+
+	Cancellations *CancellationStats `json:"cancellations,omitempty"`
+	Memory        *TaskMemoryStats   `json:"memory,omitempty"`
+}
+
+// TaskMemoryStats describes the tasks held in this instance's memory
+type TaskMemoryStats struct {
+	Tasks          int        `json:"tasks"`   // Tasks in memory, running or finished
+	Running        int        `json:"running"` // Tasks being routed or executed
+	Evicted        int64      `json:"evicted"` // Finished tasks compacted out of memory since startup
+	LastCompaction *time.Time `json:"last_compaction,omitempty"`
+}
+
+// CancellationStats counts tasks cancelled because their client stopped waiting
+type CancellationStats struct {
+	Total   int64            `json:"total"`
+	ByStage map[string]int64 `json:"by_stage"` // Status when cancelled, e.g. held, awaiting_auth, executing
+	Refused int64            `json:"refused"`  // Transfers already being executed, which are not cancelled
 }
 
+// TaskCancelRequest is the optional body of a cancellation
+type TaskCancelRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// Hold outcomes
+const (
+	HoldWaiting   = "WAITING"
+	HoldReleased  = "RELEASED"  // An agent returned and the task was routed to it
+	HoldExpired   = "EXPIRED"   // No agent returned in time and the task failed
+	HoldCancelled = "CANCELLED" // The task was cancelled while it waited
+)
+
+// TaskHold records a task waiting for an agent of a type that had no healthy agent
+// when the task was routed
+type TaskHold struct {
+	AgentType  string     `json:"agent_type"`
+	TenantID   string     `json:"tenant_id,omitempty"` // Waits for an agent this tenant may use
+	Reason     string     `json:"reason"`
+	Message    string     `json:"message"` // Shown to the user while the task waits
+	Status     string     `json:"status"`  // WAITING, RELEASED or EXPIRED
+	HeldAt     time.Time  `json:"held_at"`
+	Deadline   time.Time  `json:"deadline"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// Outcomes of a task handed over by a stopped instance
+const (
+	HandoverPending    = "PENDING"     // Waiting for an instance to pick it up
+	HandoverResumed    = "RESUMED"     // Run again from the start by the instance that picked it up
+	HandoverFailedOver = "FAILED_OVER" // Failed: its transfer may have gone through, so reconciliation settles it
+)
+
+// Reasons a task was handed over
+const (
+	HandoverDrainTimeout = "DRAIN_TIMEOUT" // Still running when shutdown stopped waiting for it
+	HandoverInstanceLost = "INSTANCE_LOST" // Its instance stopped without draining
+)
+
+// TaskHandover records a task an instance stopped before finishing, by draining at
+// shutdown or by crashing, and what the instance that picked it up did with it
+type TaskHandover struct {
+	FromInstance string     `json:"from_instance"`
+	Reason       string     `json:"reason"`
+	Stage        TaskStatus `json:"stage"` // The task's status when it was left
+	HandedOverAt time.Time  `json:"handed_over_at"`
+	Outcome      string     `json:"outcome"`
+	ToInstance   string     `json:"to_instance,omitempty"`
+	RecoveredAt  *time.Time `json:"recovered_at,omitempty"`
+}
+
+// HoldQueueStats reports the tasks waiting for agents
+type HoldQueueStats struct {
+	Enabled        bool           `json:"enabled"`
+	Held           int            `json:"held"`
+	ByAgentType    map[string]int `json:"by_agent_type"`
+	MaxTasks       int            `json:"max_tasks"`
+	MaxWaitSeconds int            `json:"max_wait_seconds"`
+	Released       int64          `json:"released"`
+	Expired        int64          `json:"expired"`
+	Refused        int64          `json:"refused"` // Tasks that failed because the hold queue was full
+	Cancelled      int64          `json:"cancelled"`
+}
+
+// SLA breach reasons: the part of a task's latency that dominated
+const (
+	SLABreachQueueWait      = "queue_wait"      // Routing, queueing and waiting for earlier transfers
+	SLABreachAgentLatency   = "agent_latency"   // The agent itself
+	SLABreachDownstreamCall = "downstream_call" // The agent's calls to ML models and banking systems
+)
+
+// TaskSLA is where a completed task's end-to-end latency went and whether it met its
+// intent's SLA
+type TaskSLA struct {
+	TotalMs      float64 `json:"total_ms"`      // Routing to completion
+	QueueMs      float64 `json:"queue_ms"`      // Routing to the agent call
+	AgentMs      float64 `json:"agent_ms"`      // The agent call, excluding downstream calls
+	DownstreamMs float64 `json:"downstream_ms"` // Downstream calls the agent reported
+	ThresholdMs  float64 `json:"threshold_ms"`
+	Breached     bool    `json:"breached"`
+	BreachReason string  `json:"breach_reason,omitempty"`
+}
+
+// IntentLatencyStats summarizes the recent end-to-end latency of one intent
+type IntentLatencyStats struct {
+	Intent        string         `json:"intent"`
+	Tasks         int            `json:"tasks"` // Recent tasks the figures are computed over
+	P50Ms         float64        `json:"p50_ms"`
+	P95Ms         float64        `json:"p95_ms"`
+	P99Ms         float64        `json:"p99_ms"`
+	MaxMs         float64        `json:"max_ms"`
+	ThresholdMs   float64        `json:"threshold_ms"`
+	Breaches      int            `json:"breaches"`
+	BreachRate    float64        `json:"breach_rate"`
+	BreachReasons map[string]int `json:"breach_reasons,omitempty"`
+}
+
+// SLABreach is a task that took longer than its intent's SLA
+type SLABreach struct {
+	TaskID      string    `json:"task_id"`
+	Intent      string    `json:"intent"`
+	AgentType   string    `json:"agent_type"`
+	CompletedAt time.Time `json:"completed_at"`
+	TaskSLA
+}
@@ -0,0 +1,38 @@
+package model
+
+// Agent pools a task may be served from
+const (
+	PoolDedicated = "dedicated" // Agents dedicated to the task's tenant
+	PoolShared    = "shared"    // Agents dedicated to no tenant
+)
+
+// TenantPoolStats is how busy a tenant keeps its own agents and the shared pool
+type TenantPoolStats struct {
+	TenantID          string  `json:"tenant_id"`
+	Policy            string  `json:"policy"`
+	DedicatedAgents   int     `json:"dedicated_agents"` // Healthy agents of its own
+	InFlightDedicated int     `json:"in_flight_dedicated"`
+	InFlightShared    int     `json:"in_flight_shared"`
+	Utilization       float64 `json:"utilization"` // In-flight tasks on its own agents over their capacity; 0 without a capacity
+	DedicatedTasks    int64   `json:"dedicated_tasks"`
+	SharedTasks       int64   `json:"shared_tasks"`
+	Overflowed        int64   `json:"overflowed"` // Sent to the shared pool because all its own agents were busy
+	Unserved          int64   `json:"unserved"`   // No agent in any pool its policy allows
+}
+
+// SharedPoolStats is how busy the agents dedicated to no tenant are, across every
+// tenant's tasks and those of no tenant
+type SharedPoolStats struct {
+	Agents      int     `json:"agents"` // Healthy
+	InFlight    int     `json:"in_flight"`
+	Utilization float64 `json:"utilization"` // In-flight tasks over the pool's capacity; 0 without a capacity
+	Tasks       int64   `json:"tasks"`
+}
+
+// TenantPoolReport is the utilization of every tenant's agents and of the shared pool
+type TenantPoolReport struct {
+	AgentCapacity int               `json:"agent_capacity"`
+	DefaultPolicy string            `json:"default_policy"`
+	Tenants       []TenantPoolStats `json:"tenants"`
+	SharedPool    SharedPoolStats   `json:"shared_pool"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Warmup triggers
+const (
+	WarmupTriggerUser     = "USER"     // A user's own history at the hour they showed up
+	WarmupTriggerSchedule = "SCHEDULE" // All users' history for the coming hour
+)
+
+// IntentHistory is how often a user, or all users together, asked for each intent at
+// each hour of the day. Counts fade with a half-life so habits can change.
+type IntentHistory struct {
+	UserID    string                     `json:"user_id,omitempty"` // Empty for all users
+	Hours     map[int]map[string]float64 `json:"hours"`             // Hour of day (0-23) -> intent -> decayed count
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// IntentPrediction is an intent likely to be asked for at an hour of the day
+type IntentPrediction struct {
+	Intent     string   `json:"intent"`
+	Share      float64  `json:"share"`       // Of the hour's decayed count
+	AgentTypes []string `json:"agent_types"` // Agents that would handle it
+}
+
+// WarmupRecord is one warmup request sent to an agent
+type WarmupRecord struct {
+	AgentID   string    `json:"agent_id"`
+	AgentType string    `json:"agent_type"`
+	Trigger   string    `json:"trigger"` // USER or SCHEDULE
+	Reason    string    `json:"reason"`
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs float64   `json:"latency_ms"`
+	At        time.Time `json:"at"`
+}
+
+// WarmupStats summarizes agent pre-warming
+type WarmupStats struct {
+	Enabled         bool           `json:"enabled"`
+	Sent            int64          `json:"sent"`
+	Failed          int64          `json:"failed"`
+	Skipped         int64          `json:"skipped"` // Within an agent's cooldown
+	ByAgentType     map[string]int `json:"by_agent_type"`
+	LastScheduledAt *time.Time     `json:"last_scheduled_at,omitempty"`
+	Recent          []WarmupRecord `json:"recent"` // Newest first
+}
@@ -10,11 +10,23 @@ import (
 
 // Router sets up all routes
 type Router struct {
-	taskController    *controller.TaskController
-	agentController   *controller.AgentController
-	sessionController *controller.SessionController
-	ruleController    *controller.RuleController
-	rateLimiter       *middleware.RateLimiter
+	taskController      *controller.TaskController
+	agentController     *controller.AgentController
+	sessionController   *controller.SessionController
+	ruleController      *controller.RuleController
+	alertController     *controller.AlertController
+	retentionController *controller.RetentionController
+	dsarController      *controller.DSARController
+	authController      *controller.AuthController
+	deviceController    *controller.DeviceController
+	warmupController    *controller.WarmupController
+	planController      *controller.PlanController
+	reconcileController *controller.ReconciliationController
+	intentController    *controller.IntentController
+	rateLimiter         *middleware.RateLimiter
+	recovery            *middleware.Recovery
+	accessLog           *middleware.AccessLog
+	adminAuth           *middleware.AdminAuth
 }
 
 // NewRouter creates a new router instance
@@ -23,14 +35,38 @@ func NewRouter(
 	agentController *controller.AgentController,
 	sessionController *controller.SessionController,
 	ruleController *controller.RuleController,
+	alertController *controller.AlertController,
+	retentionController *controller.RetentionController,
+	dsarController *controller.DSARController,
+	authController *controller.AuthController,
+	deviceController *controller.DeviceController,
+	warmupController *controller.WarmupController,
+	planController *controller.PlanController,
+	reconcileController *controller.ReconciliationController,
+	intentController *controller.IntentController,
 	rateLimiter *middleware.RateLimiter,
+	recovery *middleware.Recovery,
+	accessLog *middleware.AccessLog,
+	adminAuth *middleware.AdminAuth,
 ) *Router {
 	return &Router{
-		taskController:    taskController,
-		agentController:   agentController,
-		sessionController: sessionController,
-		ruleController:    ruleController,
-		rateLimiter:       rateLimiter,
+		taskController:      taskController,
+		agentController:     agentController,
+		sessionController:   sessionController,
+		ruleController:      ruleController,
+		alertController:     alertController,
+		retentionController: retentionController,
+		dsarController:      dsarController,
+		authController:      authController,
+		deviceController:    deviceController,
+		warmupController:    warmupController,
+		planController:      planController,
+		reconcileController: reconcileController,
+		intentController:    intentController,
+		rateLimiter:         rateLimiter,
+		recovery:            recovery,
+		accessLog:           accessLog,
+		adminAuth:           adminAuth,
 	}
 }
 
@@ -48,25 +84,115 @@ func (r *Router) SetupRoutes() *mux.Router {
 	// Task routes
 	api.HandleFunc("/submit-task", r.taskController.SubmitTask).Methods("POST")
 	api.HandleFunc("/get-result/{taskID}", r.taskController.GetTaskResult).Methods("GET")
+	api.HandleFunc("/task/{taskID}/events", r.taskController.StreamTaskEvents).Methods("GET")
+	api.HandleFunc("/task/{taskID}/requeue", r.taskController.RequeueTask).Methods("POST")
+	api.HandleFunc("/task/{taskID}/cancel", r.taskController.CancelTask).Methods("POST")
+	api.HandleFunc("/queue/stats", r.taskController.GetQueueStats).Methods("GET")
+	api.HandleFunc("/queue/held", r.taskController.GetHeldTasks).Methods("GET")
+	api.HandleFunc("/sla/stats", r.taskController.GetSLAStats).Methods("GET")
+	api.HandleFunc("/sla/breaches", r.taskController.GetSLABreaches).Methods("GET")
+	api.HandleFunc("/routing/fast-path", r.taskController.GetFastPathAudit).Methods("GET")
+	api.HandleFunc("/splits/{splitID}", r.taskController.GetSplit).Methods("GET")
+	api.HandleFunc("/splits/{splitID}/confirm", r.taskController.ConfirmSplit).Methods("POST")
+	api.HandleFunc("/splits/{splitID}/decline", r.taskController.DeclineSplit).Methods("POST")
 
 	// Agent routes
 	api.HandleFunc("/register-agent", r.agentController.RegisterAgent).Methods("POST")
 	api.HandleFunc("/agent/{agentID}", r.agentController.GetAgent).Methods("GET")
-	api.HandleFunc("/agents", r.agentController.GetAllAgents).Methods("GET")
+	api.Handle("/agents", middleware.ETagMiddleware(http.HandlerFunc(r.agentController.GetAllAgents))).Methods("GET")
+	api.HandleFunc("/agents/tenants", r.agentController.GetTenantUtilization).Methods("GET")
+
+	// Custom intent and intent flag routes
+	api.HandleFunc("/intents", r.intentController.ListIntents).Methods("GET")
+	api.HandleFunc("/intents/flags", r.intentController.ListFlags).Methods("GET")
+	api.Handle("/intents/{intent}/flag", r.adminAuth.Require(r.intentController.SetFlag)).Methods("PUT")       // Admin role required
+	api.Handle("/intents/{intent}/flag", r.adminAuth.Require(r.intentController.DeleteFlag)).Methods("DELETE") // Admin role required
+	api.HandleFunc("/intents/{intent}", r.intentController.GetIntent).Methods("GET")
+	api.Handle("/intents/{intent}", r.adminAuth.Require(r.intentController.RegisterIntent)).Methods("PUT")  // Admin role required
+	api.Handle("/intents/{intent}", r.adminAuth.Require(r.intentController.DeleteIntent)).Methods("DELETE") // Admin role required
+	api.HandleFunc("/agents/diagnostics", r.taskController.GetAgentDiagnostics).Methods("GET")
 
 	// Session routes
 	api.HandleFunc("/get-session/{sessionID}", r.sessionController.GetSession).Methods("GET")
 	api.HandleFunc("/create-session", r.sessionController.CreateSession).Methods("POST")
+	api.HandleFunc("/session/{sessionID}", r.sessionController.BindSession).Methods("PUT")
+	api.HandleFunc("/session/{sessionID}", r.sessionController.DeleteSession).Methods("DELETE")
 
 	// Rule routes
 	api.HandleFunc("/rules/upload", r.ruleController.UploadRules).Methods("POST")
 	api.HandleFunc("/rules", r.ruleController.GetRules).Methods("GET")
 
-	// Apply middleware (CORS first)
+	// Alert routes
+	api.HandleFunc("/alerts", r.alertController.GetAlerts).Methods("GET")
+	api.HandleFunc("/alerts/check", r.alertController.CheckNow).Methods("POST")
+
+	// Retention routes
+	api.HandleFunc("/retention/policies", r.retentionController.GetPolicies).Methods("GET")
+	api.Handle("/retention/purge", r.adminAuth.Require(r.retentionController.Purge)).Methods("POST") // Admin role required
+	api.HandleFunc("/retention/reports", r.retentionController.GetReports).Methods("GET")
+	api.HandleFunc("/retention/holds", r.retentionController.GetHolds).Methods("GET")
+	api.HandleFunc("/retention/holds/{userID}", r.retentionController.PlaceHold).Methods("PUT")
+	api.Handle("/retention/holds/{userID}", r.adminAuth.Require(r.retentionController.ReleaseHold)).Methods("DELETE") // Admin role required
+
+	// Data-subject request routes
+	api.HandleFunc("/dsar", r.dsarController.CreateRequest).Methods("POST")
+	api.HandleFunc("/dsar", r.dsarController.ListRequests).Methods("GET")
+	api.HandleFunc("/dsar/{id}", r.dsarController.GetRequest).Methods("GET")
+	api.Handle("/dsar/{id}/verify", r.adminAuth.Require(r.dsarController.VerifyRequest)).Methods("POST")   // Admin role required
+	api.Handle("/dsar/{id}/reject", r.adminAuth.Require(r.dsarController.RejectRequest)).Methods("POST")   // Admin role required
+	api.Handle("/dsar/{id}/process", r.adminAuth.Require(r.dsarController.ProcessRequest)).Methods("POST") // Admin role required
+	api.HandleFunc("/dsar/{id}/export", r.dsarController.GetExport).Methods("GET")
+	api.HandleFunc("/dsar/{id}/completion", r.dsarController.VerifyCompletion).Methods("GET")
+
+	// Reconciliation routes
+	api.HandleFunc("/reconciliation/run", r.reconcileController.Run).Methods("POST")
+	api.HandleFunc("/reconciliation/reports", r.reconcileController.GetReports).Methods("GET")
+	api.HandleFunc("/reconciliation/exceptions", r.reconcileController.GetExceptions).Methods("GET")
+	api.HandleFunc("/reconciliation/exceptions/{exceptionID}/resolve", r.reconcileController.ResolveException).Methods("POST")
+
+	// Step-up authentication routes
+	api.HandleFunc("/auth/policy", r.authController.GetPolicy).Methods("GET")
+	api.HandleFunc("/auth/challenges/{id}", r.authController.GetChallenge).Methods("GET")
+	api.HandleFunc("/auth/challenges/{id}/otp", r.authController.VerifyOTP).Methods("POST")
+	api.HandleFunc("/auth/challenges/{id}/biometric", r.authController.VerifyBiometric).Methods("POST")
+	api.HandleFunc("/auth/challenges/{id}/token", r.authController.VerifyToken).Methods("POST")
+	api.HandleFunc("/auth/enrollments", r.authController.Enroll).Methods("POST")
+	api.HandleFunc("/auth/devices", r.authController.RegisterDevice).Methods("POST")
+	api.HandleFunc("/auth/tokens", r.authController.RegisterToken).Methods("POST")
+	api.HandleFunc("/auth/factors/{userID}", r.authController.GetFactors).Methods("GET")
+
+	// Device trust routes
+	api.HandleFunc("/devices/{userID}", r.deviceController.ListDevices).Methods("GET")
+	api.HandleFunc("/devices/{userID}/{deviceID}", r.deviceController.ForgetDevice).Methods("DELETE")
+
+	// Agent warmup routes
+	api.HandleFunc("/warmup/stats", r.warmupController.GetStats).Methods("GET")
+	api.HandleFunc("/warmup/predictions", r.warmupController.GetPredictions).Methods("GET")
+	api.HandleFunc("/warmup/run", r.warmupController.WarmNow).Methods("POST")
+
+	// Orchestration plan routes
+	api.HandleFunc("/plans", r.planController.ListPlans).Methods("GET")
+	api.HandleFunc("/plans", r.planController.CreatePlan).Methods("POST")
+	api.HandleFunc("/plans/{planID}", r.planController.GetPlan).Methods("GET")
+	api.HandleFunc("/plans/{planID}", r.planController.UpdatePlan).Methods("PUT")
+	api.HandleFunc("/plans/{planID}", r.planController.DeletePlan).Methods("DELETE")
+	api.HandleFunc("/plans/{planID}/runs", r.planController.GetPlanRuns).Methods("GET")
+
+	// Panics recovered, per route and by fingerprint
+	api.HandleFunc("/admin/panics", r.recovery.Stats).Methods("GET")
+
+	// Apply middleware. Access records come first, so requests refused by any later
+	// middleware are recorded too.
+	router.Use(r.accessLog.Middleware)
+	router.NotFoundHandler = r.accessLog.NotFound()
+	router.MethodNotAllowedHandler = r.accessLog.MethodNotAllowed()
 	router.Use(middleware.CORSMiddleware)
 	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.CompressionMiddleware)
 	router.Use(middleware.AuthMiddleware)
 	router.Use(r.rateLimiter.RateLimitMiddleware)
+	// Last, so a panicking handler is answered before any middleware finishes its response
+	router.Use(r.recovery.Middleware)
 
 	return router
 }
@@ -78,9 +204,15 @@ func (r *Router) healthCheck(w http.ResponseWriter, req *http.Request) {
 	w.Write([]byte(`{"status":"healthy"}`))
 }
 
-// readyCheck returns server readiness status
+// readyCheck returns server readiness status. A draining instance is not ready, so
+// load balancers stop sending it requests while it finishes its tasks.
 func (r *Router) readyCheck(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+	if r.taskController.Draining() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"draining"}`))
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"ready"}`))
 }
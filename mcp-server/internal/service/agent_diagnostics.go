@@ -0,0 +1,84 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/aibanking/mcp-server/internal/model"
+)
+
+// diagnosticsWindow is how many recent tasks per agent type the SLO figures cover
+const diagnosticsWindow = 500
+
+// AgentDiagnosticsTracker keeps the diagnostics of the most recent tasks of each agent
+// type and summarizes them for SLO tracking
+type AgentDiagnosticsTracker struct {
+	recent map[model.AgentType][]model.AgentDiagnostics // Oldest first
+	mu     sync.Mutex
+}
+
+// NewAgentDiagnosticsTracker creates an empty tracker
+func NewAgentDiagnosticsTracker() *AgentDiagnosticsTracker {
+	return &AgentDiagnosticsTracker{
+		recent: make(map[model.AgentType][]model.AgentDiagnostics),
+	}
+}
+
+// Record adds the diagnostics of one task, dropping the oldest beyond the window
+func (dt *AgentDiagnosticsTracker) Record(agentType model.AgentType, diagnostics *model.AgentDiagnostics) {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	recent := append(dt.recent[agentType], *diagnostics)
+	if len(recent) > diagnosticsWindow {
+		recent = recent[len(recent)-diagnosticsWindow:]
+	}
+	dt.recent[agentType] = recent
+}
+
+// Stats returns latency percentiles, fallback rate and downstream success rate per
+// agent type, ordered by type
+func (dt *AgentDiagnosticsTracker) Stats() []model.AgentSLOStats {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	stats := make([]model.AgentSLOStats, 0, len(dt.recent))
+	for agentType, recent := range dt.recent {
+		s := model.AgentSLOStats{AgentType: string(agentType), Tasks: len(recent), DownstreamSuccessRate: 1}
+
+		latencies := make([]float64, len(recent))
+		fallbacks, succeeded := 0, 0
+		for i, d := range recent {
+			latencies[i] = d.ProcessingMs
+			if d.FallbackUsed {
+				fallbacks++
+			}
+			s.DownstreamCalls += d.CallsAttempted
+			succeeded += d.CallsSucceeded
+		}
+		sort.Float64s(latencies)
+
+		s.P50Ms = percentile(latencies, 0.50)
+		s.P95Ms = percentile(latencies, 0.95)
+		s.P99Ms = percentile(latencies, 0.99)
+		s.MaxMs = latencies[len(latencies)-1]
+		s.FallbackRate = float64(fallbacks) / float64(len(recent))
+		if s.DownstreamCalls > 0 {
+			s.DownstreamSuccessRate = float64(succeeded) / float64(s.DownstreamCalls)
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(a, b int) bool { return stats[a].AgentType < stats[b].AgentType })
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// HoldQueueFullError is returned when a task needs an agent type that has no healthy
+// agent and the hold queue has no room to wait for one
+type HoldQueueFullError struct {
+	AgentType  string
+	Held       int
+	RetryAfter time.Duration
+}
+
+func (e *HoldQueueFullError) Error() string {
+	return fmt.Sprintf("no %s agent available and %d tasks are already waiting for agents, retry after %s", e.AgentType, e.Held, e.RetryAfter)
+}
+
+// agentServiceNames describe an agent type to users waiting on it
+var agentServiceNames = map[string]string{
+	string(model.AgentTypeBanking):   "banking systems",
+	string(model.AgentTypeFraud):     "fraud checks",
+	string(model.AgentTypeGuardrail): "safety checks",
+	string(model.AgentTypeClearance): "loan review systems",
+	string(model.AgentTypeScoring):   "credit scoring systems",
+	string(model.AgentTypeInsights):  "savings insights",
+	string(model.AgentTypePayment):   "payment systems",
+}
+
+// AgentHoldQueue parks tasks whose agent type has no healthy agent until one registers
+// or recovers. Each held task waits at most the configured time; the number of tasks
+// held at once is bounded so an agent that stays down cannot pile up work.
+type AgentHoldQueue struct {
+	enabled      bool
+	maxWait      time.Duration
+	maxTasks     int
+	pollInterval time.Duration
+	registry     *AgentRegistry
+
+	mu        sync.Mutex
+	held      map[string]*model.TaskHold // By task ID
+	released  int64
+	expired   int64
+	refused   int64
+	cancelled int64
+}
+
+// NewAgentHoldQueue creates a new agent hold queue
+func NewAgentHoldQueue(cfg *config.HoldConfig, registry *AgentRegistry) *AgentHoldQueue {
+	maxWait := time.Duration(cfg.MaxWaitSeconds) * time.Second
+	if maxWait <= 0 {
+		maxWait = 2 * time.Minute
+	}
+	maxTasks := cfg.MaxTasks
+	if maxTasks <= 0 {
+		maxTasks = 200
+	}
+	pollInterval := time.Duration(cfg.PollIntervalMs) * time.Millisecond
+	if pollInterval <= 0 {
+		pollInterval = 500 * time.Millisecond
+	}
+
+	return &AgentHoldQueue{
+		enabled:      cfg.Enabled,
+		maxWait:      maxWait,
+		maxTasks:     maxTasks,
+		pollInterval: pollInterval,
+		registry:     registry,
+		held:         make(map[string]*model.TaskHold),
+	}
+}
+
+// Enabled reports whether tasks wait for missing agents instead of failing
+func (h *AgentHoldQueue) Enabled() bool {
+	return h.enabled
+}
+
+// Hold parks a task until an agent of agentType that its tenant may use is available,
+// or returns a *HoldQueueFullError when too many tasks are already waiting
+func (h *AgentHoldQueue) Hold(taskID, agentType, tenantID string) (*model.TaskHold, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.held) >= h.maxTasks {
+		h.refused++
+		return nil, &HoldQueueFullError{AgentType: agentType, Held: len(h.held), RetryAfter: h.maxWait}
+	}
+
+	now := time.Now()
+	hold := &model.TaskHold{
+		AgentType: agentType,
+		TenantID:  tenantID,
+		Reason:    fmt.Sprintf("No healthy %s agent is registered", agentType),
+		Message:   holdMessage(agentType, h.maxWait),
+		Status:    model.HoldWaiting,
+		HeldAt:    now,
+		Deadline:  now.Add(h.maxWait),
+	}
+	h.held[taskID] = hold
+
+	log.Warn().
+		Str("task_id", taskID).
+		Str("agent_type", agentType).
+		Time("deadline", hold.Deadline).
+		Msg("No agent available, holding task")
+
+	copied := *hold
+	return &copied, nil
+}
+
+// Wait blocks until an agent of the held task's type that its tenant may use is
+// available, returning true, or until the hold's deadline passes, returning false
+func (h *AgentHoldQueue) Wait(ctx context.Context, taskID string) bool {
+	h.mu.Lock()
+	hold, ok := h.held[taskID]
+	h.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(time.Until(hold.Deadline))
+	defer deadline.Stop()
+
+	for {
+		if h.registry.HasAgentsForTenant(ctx, model.AgentType(hold.AgentType), hold.TenantID) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// Expired reports whether a held task is past its deadline
+func (h *AgentHoldQueue) Expired(taskID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hold, ok := h.held[taskID]
+	return !ok || time.Now().After(hold.Deadline)
+}
+
+// PollInterval is how long to back off before trying a released task again
+func (h *AgentHoldQueue) PollInterval() time.Duration {
+	return h.pollInterval
+}
+
+// Release removes a task from the queue with its outcome, RELEASED, EXPIRED or
+// CANCELLED, and returns the final state of its hold
+func (h *AgentHoldQueue) Release(taskID, outcome string) *model.TaskHold {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hold, ok := h.held[taskID]
+	if !ok {
+		return nil
+	}
+	delete(h.held, taskID)
+
+	now := time.Now()
+	hold.Status = outcome
+	hold.ReleasedAt = &now
+	switch outcome {
+	case model.HoldReleased:
+		h.released++
+	case model.HoldCancelled:
+		h.cancelled++
+	default:
+		h.expired++
+	}
+
+	log.Info().
+		Str("task_id", taskID).
+		Str("agent_type", hold.AgentType).
+		Str("outcome", outcome).
+		Dur("held_for", now.Sub(hold.HeldAt)).
+		Msg("Task left the hold queue")
+
+	return hold
+}
+
+// Stats returns the tasks waiting per agent type and what became of earlier holds
+func (h *AgentHoldQueue) Stats() *model.HoldQueueStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	byType := make(map[string]int)
+	for _, hold := range h.held {
+		byType[hold.AgentType]++
+	}
+
+	return &model.HoldQueueStats{
+		Enabled:        h.enabled,
+		Held:           len(h.held),
+		ByAgentType:    byType,
+		MaxTasks:       h.maxTasks,
+		MaxWaitSeconds: int(h.maxWait.Seconds()),
+		Released:       h.released,
+		Expired:        h.expired,
+		Refused:        h.refused,
+		Cancelled:      h.cancelled,
+	}
+}
+
+// holdMessage tells the user why their request is waiting and for how long at most
+func holdMessage(agentType string, maxWait time.Duration) string {
+	name, ok := agentServiceNames[agentType]
+	if !ok {
+		name = strings.ToLower(agentType) + " systems"
+	}
+	return fmt.Sprintf("Our %s are temporarily unavailable. Your request is on hold and will continue automatically once they are back, or be cancelled if that takes longer than %s.", name, describeWait(maxWait))
+}
+
+// describeWait renders a wait as "2 minutes" or "45 seconds"
+func describeWait(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		if minutes := int(d.Minutes()); minutes > 1 {
+			return fmt.Sprintf("%d minutes", minutes)
+		}
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d seconds", int(d.Seconds()))
+}
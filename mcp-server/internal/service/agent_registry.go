@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/aibanking/mcp-server/internal/model"
-	"github.com/aibanking/mcp-server/internal/utils"
+	"github.com/aibanking/shared/ids"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
@@ -19,6 +20,7 @@ type AgentRegistry struct {
 	redisAvailable bool
 	mu            sync.RWMutex
 	agents        map[string]*model.Agent // In-memory cache
+	pools         *TenantPools
 }
 
 // NewAgentRegistry creates a new agent registry instance
@@ -47,9 +49,14 @@ func NewAgentRegistry(redisClient *redis.Client) *AgentRegistry {
 	return registry
 }
 
+// SetTenantPools keeps agents registered for a tenant serving that tenant alone
+func (ar *AgentRegistry) SetTenantPools(pools *TenantPools) {
+	ar.pools = pools
+}
+
 // RegisterAgent registers a new agent in the mesh
 func (ar *AgentRegistry) RegisterAgent(ctx context.Context, req *model.AgentRegistrationRequest) (*model.Agent, error) {
-	agentID := utils.GenerateAgentID()
+	agentID := ids.New(ids.Agent)
 	now := time.Now()
 
 	agent := &model.Agent{
@@ -63,6 +70,7 @@ func (ar *AgentRegistry) RegisterAgent(ctx context.Context, req *model.AgentRegi
 		Rules:        req.Rules,
 		Metadata:     req.Metadata,
 		HealthCheck:  req.HealthCheck,
+		TenantID:     req.TenantID,
 		LastHealthAt: now,
 		RegisteredAt: now,
 		UpdatedAt:    now,
@@ -92,6 +100,7 @@ func (ar *AgentRegistry) RegisterAgent(ctx context.Context, req *model.AgentRegi
 		Str("agent_id", agentID).
 		Str("name", req.Name).
 		Str("type", req.Type).
+		Str("tenant_id", req.TenantID).
 		Msg("Agent registered")
 
 	return agent, nil
@@ -172,6 +181,56 @@ func (ar *AgentRegistry) FindAgentsByCapability(ctx context.Context, capability
 	return agents, nil
 }
 
+// FindAgentsForTenant finds the healthy agents of a type that may serve a task of the
+// tenant, best first; see TenantPools.Order. tenantID is empty for tasks of no tenant.
+func (ar *AgentRegistry) FindAgentsForTenant(ctx context.Context, agentType model.AgentType, tenantID string) ([]*model.Agent, error) {
+	agents, err := ar.FindAgentsByType(ctx, agentType)
+	if err != nil {
+		return nil, err
+	}
+	return ar.ForTenant(tenantID, agents), nil
+}
+
+// ForTenant returns the agents, of those given, that may serve a task of the tenant,
+// best first
+func (ar *AgentRegistry) ForTenant(tenantID string, agents []*model.Agent) []*model.Agent {
+	if ar.pools == nil {
+		return agents
+	}
+	return ar.pools.Order(tenantID, agents)
+}
+
+// HasAgentsForTenant reports whether a healthy agent of the type may serve a task of
+// the tenant
+func (ar *AgentRegistry) HasAgentsForTenant(ctx context.Context, agentType model.AgentType, tenantID string) bool {
+	agents, err := ar.FindAgentsByType(ctx, agentType)
+	if err != nil || len(agents) == 0 {
+		return false
+	}
+	if ar.pools == nil {
+		return true
+	}
+	return ar.pools.Serves(tenantID, agents)
+}
+
+// BeginTask counts a task of the tenant the agent starts working on towards the
+// tenant's utilization. The returned func counts it finished.
+func (ar *AgentRegistry) BeginTask(tenantID string, agent *model.Agent) func() {
+	if ar.pools == nil {
+		return func() {}
+	}
+	return ar.pools.Begin(tenantID, agent)
+}
+
+// TenantUtilization returns how busy each tenant keeps its own agents and the shared pool
+func (ar *AgentRegistry) TenantUtilization(ctx context.Context) *model.TenantPoolReport {
+	if ar.pools == nil {
+		return &model.TenantPoolReport{Tenants: []model.TenantPoolStats{}}
+	}
+	agents, _ := ar.GetAllAgents(ctx)
+	return ar.pools.Report(agents)
+}
+
 // UpdateAgentStatus updates the health status of an agent
 func (ar *AgentRegistry) UpdateAgentStatus(ctx context.Context, agentID string, status model.AgentStatus) error {
 	agent, err := ar.GetAgent(ctx, agentID)
@@ -208,6 +267,8 @@ func (ar *AgentRegistry) GetAllAgents(ctx context.Context) ([]*model.Agent, erro
 	for _, agent := range ar.agents {
 		agents = append(agents, agent)
 	}
+	// In registration order, so an unchanged registry lists the same way every time
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AgentID < agents[j].AgentID })
 
 	return agents, nil
 }
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// warmupRecordLimit is how many warmup requests Stats reports
+const warmupRecordLimit = 50
+
+// intentAgentTypes lists the agents an intent may reach, mirroring routeByIntent:
+// transfers can be vetted by the guardrail and fraud agents before banking runs them
+func intentAgentTypes(intent string) []string {
+	switch intent {
+	case "TRANSFER_NEFT", "TRANSFER_RTGS", "TRANSFER_IMPS", "TRANSFER_UPI":
+		return []string{string(model.AgentTypeBanking), string(model.AgentTypeGuardrail), string(model.AgentTypeFraud)}
+	case "ADD_BENEFICIARY", "MANAGE_BENEFICIARY":
+		return []string{string(model.AgentTypeGuardrail)}
+	case "APPLY_LOAN", "LOAN_APPROVAL":
+		return []string{string(model.AgentTypeClearance)}
+	case "CREDIT_SCORE", "RISK_ASSESSMENT":
+		return []string{string(model.AgentTypeScoring)}
+	case "GET_INSIGHTS":
+		return []string{string(model.AgentTypeInsights)}
+	}
+	return []string{string(model.AgentTypeBanking)}
+}
+
+// AgentWarmer asks agents to warm up before the requests they are likely to get. When
+// a user submits a task, agents for the other intents that user tends to ask for at
+// this hour are warmed; on a schedule, agents for what all users ask for in the coming
+// hour are. Warming makes an agent open its downstream connections, so the first
+// transfer of the day does not pay for them. Each agent is asked at most once per
+// cooldown.
+type AgentWarmer struct {
+	cfg         *config.WarmupConfig
+	history     *IntentHistories
+	registry    *AgentRegistry
+	httpClient  *http.Client
+	agentAPIKey *secrets.Value
+
+	mu            sync.Mutex
+	lastWarmed    map[string]time.Time // By agent ID
+	records       []model.WarmupRecord // Newest last
+	sent          int64
+	failed        int64
+	skipped       int64
+	byType        map[string]int
+	lastScheduled *time.Time
+}
+
+// NewAgentWarmer creates a new agent warmer
+func NewAgentWarmer(cfg *config.WarmupConfig, history *IntentHistories, registry *AgentRegistry) *AgentWarmer {
+	return &AgentWarmer{
+		cfg:         cfg,
+		history:     history,
+		registry:    registry,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		agentAPIKey: config.Profile().RotatingSecret("WARMUP_AGENT_API_KEY", cfg.AgentAPIKey),
+		lastWarmed:  make(map[string]time.Time),
+		byType:      make(map[string]int),
+	}
+}
+
+// Observe records a submitted task's intent and warms the agents for what its user is
+// likely to ask for next at this hour. Warmup requests are sent in the background.
+func (aw *AgentWarmer) Observe(ctx context.Context, task *model.Task) {
+	if !aw.cfg.Enabled {
+		return
+	}
+	aw.history.Record(ctx, task.UserID, task.Intent, task.CreatedAt)
+	if task.UserID == "" {
+		return
+	}
+
+	hour := aw.history.Hour(time.Now())
+	types := aw.likelyAgentTypes(aw.history.Predict(ctx, task.UserID, hour), task.Intent)
+	if len(types) == 0 {
+		return
+	}
+	reason := fmt.Sprintf("user %s usually continues at %02d:00", task.UserID, hour)
+	go aw.warmTypes(context.Background(), types, model.WarmupTriggerUser, reason)
+}
+
+// Run warms agents for the coming hour every interval until ctx is done
+func (aw *AgentWarmer) Run(ctx context.Context) {
+	interval := time.Duration(aw.cfg.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", interval).Int("lead_minutes", aw.cfg.LeadMinutes).Msg("Agent warmer started")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			aw.WarmScheduled(ctx)
+		}
+	}
+}
+
+// WarmScheduled warms the agents for the intents all users ask for in the hour starting
+// within the lead time, and returns the warmup requests sent
+func (aw *AgentWarmer) WarmScheduled(ctx context.Context) []model.WarmupRecord {
+	at := time.Now().Add(time.Duration(aw.cfg.LeadMinutes) * time.Minute)
+	hour := aw.history.Hour(at)
+
+	aw.mu.Lock()
+	now := time.Now()
+	aw.lastScheduled = &now
+	aw.mu.Unlock()
+
+	types := aw.likelyAgentTypes(aw.history.PredictAll(ctx, hour), "")
+	if len(types) == 0 {
+		return []model.WarmupRecord{}
+	}
+	return aw.warmTypes(ctx, types, model.WarmupTriggerSchedule, fmt.Sprintf("expected traffic at %02d:00", hour))
+}
+
+// Predictions returns a user's likely intents at an hour of day, or all users' when
+// userID is empty. A negative hour means the current one.
+func (aw *AgentWarmer) Predictions(ctx context.Context, userID string, hour int) (int, []model.IntentPrediction) {
+	if hour < 0 {
+		hour = aw.history.Hour(time.Now())
+	}
+	if userID == "" {
+		return hour, aw.history.PredictAll(ctx, hour)
+	}
+	return hour, aw.history.Predict(ctx, userID, hour)
+}
+
+// Stats returns how many warmup requests were sent and the most recent ones
+func (aw *AgentWarmer) Stats() *model.WarmupStats {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	byType := make(map[string]int, len(aw.byType))
+	for agentType, count := range aw.byType {
+		byType[agentType] = count
+	}
+	recent := make([]model.WarmupRecord, 0, len(aw.records))
+	for i := len(aw.records) - 1; i >= 0; i-- {
+		recent = append(recent, aw.records[i])
+	}
+
+	return &model.WarmupStats{
+		Enabled:         aw.cfg.Enabled,
+		Sent:            aw.sent,
+		Failed:          aw.failed,
+		Skipped:         aw.skipped,
+		ByAgentType:     byType,
+		LastScheduledAt: aw.lastScheduled,
+		Recent:          recent,
+	}
+}
+
+// likelyAgentTypes collects the agent types of the predicted intents with at least the
+// minimum share, leaving out those of the intent being handled right now
+func (aw *AgentWarmer) likelyAgentTypes(predictions []model.IntentPrediction, current string) []string {
+	skip := make(map[string]bool)
+	if current != "" {
+		for _, agentType := range intentAgentTypes(current) {
+			skip[agentType] = true
+		}
+	}
+
+	var types []string
+	for _, p := range predictions {
+		if p.Share < aw.cfg.MinShare {
+			break
+		}
+		for _, agentType := range p.AgentTypes {
+			if !skip[agentType] {
+				skip[agentType] = true
+				types = append(types, agentType)
+			}
+		}
+	}
+	return types
+}
+
+// warmTypes asks every healthy agent of the given types to warm up, skipping agents
+// within their cooldown
+func (aw *AgentWarmer) warmTypes(ctx context.Context, types []string, trigger, reason string) []model.WarmupRecord {
+	var agents []*model.Agent
+	for _, agentType := range types {
+		found, err := aw.registry.FindAgentsByType(ctx, model.AgentType(agentType))
+		if err != nil {
+			continue
+		}
+		for _, agent := range found {
+			if aw.claim(agent.AgentID) {
+				agents = append(agents, agent)
+			}
+		}
+	}
+
+	records := make([]model.WarmupRecord, len(agents))
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func(i int, agent *model.Agent) {
+			defer wg.Done()
+			records[i] = aw.warm(ctx, agent, trigger, reason)
+		}(i, agent)
+	}
+	wg.Wait()
+	return records
+}
+
+// claim reports whether an agent may be warmed now, starting its cooldown if so
+func (aw *AgentWarmer) claim(agentID string) bool {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	cooldown := time.Duration(aw.cfg.CooldownSeconds) * time.Second
+	if last, ok := aw.lastWarmed[agentID]; ok && time.Since(last) < cooldown {
+		aw.skipped++
+		return false
+	}
+	aw.lastWarmed[agentID] = time.Now()
+	return true
+}
+
+// warm sends one warmup request and records how it went
+func (aw *AgentWarmer) warm(ctx context.Context, agent *model.Agent, trigger, reason string) model.WarmupRecord {
+	record := model.WarmupRecord{
+		AgentID:   agent.AgentID,
+		AgentType: string(agent.Type),
+		Trigger:   trigger,
+		Reason:    reason,
+		At:        time.Now(),
+	}
+
+	err := aw.post(ctx, agent, reason)
+	record.LatencyMs = float64(time.Since(record.At).Microseconds()) / 1000
+	record.OK = err == nil
+	if err != nil {
+		record.Error = err.Error()
+		log.Debug().Err(err).Str("agent_id", agent.AgentID).Str("trigger", trigger).Msg("Agent warmup failed")
+	} else {
+		log.Debug().Str("agent_id", agent.AgentID).Str("trigger", trigger).Str("reason", reason).Msg("Agent warmed up")
+	}
+
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	aw.sent++
+	if err != nil {
+		aw.failed++
+	}
+	aw.byType[record.AgentType]++
+	aw.records = append(aw.records, record)
+	if len(aw.records) > warmupRecordLimit {
+		aw.records = aw.records[len(aw.records)-warmupRecordLimit:]
+	}
+	return record
+}
+
+// post calls the agent's POST /api/v1/warmup
+func (aw *AgentWarmer) post(ctx context.Context, agent *model.Agent, reason string) error {
+	body, _ := json.Marshal(map[string]string{"reason": reason})
+	url := strings.TrimRight(agent.Endpoint, "/") + "/api/v1/warmup"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", aw.agentAPIKey.Get())
+
+	resp, err := aw.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("warmup returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/secrets"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// alertHistoryLimit is how many resolved alerts are kept
+const alertHistoryLimit = 100
+
+// alertCondition is a degradation found by one round of checks
+type alertCondition struct {
+	condition string
+	subject   string
+	severity  string
+	summary   string
+	details   map[string]interface{}
+}
+
+// AlertManager checks the platform for critical degradations on an interval and
+// notifies the configured sinks. A condition that keeps firing is sent once (and again
+// every ALERT_REPEAT_MINUTES), and a resolution is sent when it clears.
+type AlertManager struct {
+	cfg            *config.AlertsConfig
+	sinks          []AlertSink
+	orchestrator   *Orchestrator
+	agentRegistry  *AgentRegistry
+	redisClient    *redis.Client
+	httpClient     *http.Client
+	skinAPIKey     *secrets.Value
+	active         map[string]*model.Alert // Keyed by condition:subject
+	history        []model.Alert           // Resolved alerts, oldest first
+	redisDownSince time.Time
+	mu             sync.Mutex
+}
+
+// NewAlertManager creates an alert manager. Checks only run once Run is started.
+func NewAlertManager(cfg *config.AlertsConfig, sinks []AlertSink, orchestrator *Orchestrator, agentRegistry *AgentRegistry, redisClient *redis.Client) *AlertManager {
+	return &AlertManager{
+		cfg:           cfg,
+		sinks:         sinks,
+		orchestrator:  orchestrator,
+		agentRegistry: agentRegistry,
+		redisClient:   redisClient,
+		httpClient:    &http.Client{Timeout: 5 * time.Second},
+		skinAPIKey:    config.Profile().RotatingSecret("ALERT_SKIN_API_KEY", cfg.SkinAPIKey),
+		active:        make(map[string]*model.Alert),
+	}
+}
+
+// Run checks every ALERT_CHECK_INTERVAL seconds until ctx is done
+func (am *AlertManager) Run(ctx context.Context) {
+	interval := time.Duration(am.cfg.CheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	sinkNames := make([]string, len(am.sinks))
+	for i, sink := range am.sinks {
+		sinkNames[i] = sink.Name()
+	}
+	log.Info().Dur("interval", interval).Strs("sinks", sinkNames).Msg("Alerting started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			am.Check(ctx)
+		}
+	}
+}
+
+// Check runs every check once, fires new alerts and resolves those that cleared
+func (am *AlertManager) Check(ctx context.Context) {
+	var found []alertCondition
+	found = append(found, am.checkFallbacks()...)
+	found = append(found, am.checkRedis(ctx)...)
+	found = append(found, am.checkAgents(ctx)...)
+	found = append(found, am.checkLLM(ctx)...)
+
+	now := time.Now()
+	repeat := time.Duration(am.cfg.RepeatMinutes) * time.Minute
+	var notify []model.Alert
+
+	am.mu.Lock()
+	seen := make(map[string]bool, len(found))
+	for _, c := range found {
+		key := c.condition + ":" + c.subject
+		seen[key] = true
+
+		alert, ok := am.active[key]
+		if !ok {
+			alert = &model.Alert{
+				Key:       key,
+				Condition: c.condition,
+				Subject:   c.subject,
+				Status:    model.AlertFiring,
+				FiredAt:   now,
+			}
+			am.active[key] = alert
+		}
+		alert.Severity = c.severity
+		alert.Summary = c.summary
+		alert.Details = c.details
+
+		// Deduplicate: a firing alert is only sent again once the repeat interval passed
+		if !ok || (repeat > 0 && now.Sub(alert.LastNotifiedAt) >= repeat) {
+			alert.LastNotifiedAt = now
+			alert.Notifications++
+			notify = append(notify, *alert)
+		}
+	}
+
+	for key, alert := range am.active {
+		if seen[key] {
+			continue
+		}
+		resolvedAt := now
+		alert.Status = model.AlertResolved
+		alert.ResolvedAt = &resolvedAt
+		alert.Summary = "Resolved: " + alert.Summary
+		alert.LastNotifiedAt = now
+		alert.Notifications++
+		notify = append(notify, *alert)
+
+		delete(am.active, key)
+		am.history = append(am.history, *alert)
+		if len(am.history) > alertHistoryLimit {
+			am.history = am.history[len(am.history)-alertHistoryLimit:]
+		}
+	}
+	am.mu.Unlock()
+
+	for i := range notify {
+		am.send(ctx, &notify[i])
+	}
+}
+
+// Active returns the firing alerts, most recent first
+func (am *AlertManager) Active() []model.Alert {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	alerts := make([]model.Alert, 0, len(am.active))
+	for _, alert := range am.active {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(a, b int) bool { return alerts[a].FiredAt.After(alerts[b].FiredAt) })
+	return alerts
+}
+
+// History returns the resolved alerts, most recent first
+func (am *AlertManager) History() []model.Alert {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	alerts := make([]model.Alert, len(am.history))
+	for i, alert := range am.history {
+		alerts[len(am.history)-1-i] = alert
+	}
+	return alerts
+}
+
+// send delivers an alert to every sink; a failing sink does not stop the others
+func (am *AlertManager) send(ctx context.Context, alert *model.Alert) {
+	event := log.Warn()
+	if alert.Status == model.AlertResolved {
+		event = log.Info()
+	}
+	event.
+		Str("alert", alert.Key).
+		Str("status", alert.Status).
+		Str("severity", alert.Severity).
+		Msg(alert.Summary)
+
+	for _, sink := range am.sinks {
+		if err := sink.Send(ctx, alert); err != nil {
+			log.Error().Err(err).Str("sink", sink.Name()).Str("alert", alert.Key).Msg("Failed to send alert")
+		}
+	}
+}
+
+// checkFallbacks fires for each agent type whose recent tasks mostly fell back to
+// mock or rule processing
+func (am *AlertManager) checkFallbacks() []alertCondition {
+	var found []alertCondition
+	for _, s := range am.orchestrator.AgentDiagnostics() {
+		if s.Tasks < am.cfg.FallbackMinTasks || s.FallbackRate <= am.cfg.FallbackRate {
+			continue
+		}
+		severity := model.AlertSeverityWarning
+		if s.FallbackRate >= 1 {
+			severity = model.AlertSeverityCritical
+		}
+		found = append(found, alertCondition{
+			condition: model.AlertConditionFallbackRate,
+			subject:   s.AgentType,
+			severity:  severity,
+			summary: fmt.Sprintf("%.0f%% of the last %d %s tasks fell back to mock or rule processing",
+				s.FallbackRate*100, s.Tasks, s.AgentType),
+			details: map[string]interface{}{"fallback_rate": s.FallbackRate, "tasks": s.Tasks, "threshold": am.cfg.FallbackRate},
+		})
+	}
+	return found
+}
+
+// checkRedis fires once Redis has been unreachable for ALERT_REDIS_DOWN_MINUTES
+func (am *AlertManager) checkRedis(ctx context.Context) []alertCondition {
+	if am.redisClient == nil {
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	err := am.redisClient.Ping(pingCtx).Err()
+	if err == nil {
+		am.redisDownSince = time.Time{}
+		return nil
+	}
+
+	if am.redisDownSince.IsZero() {
+		am.redisDownSince = time.Now()
+	}
+	down := time.Since(am.redisDownSince)
+	if down < time.Duration(am.cfg.RedisDownMinutes)*time.Minute {
+		return nil
+	}
+	return []alertCondition{{
+		condition: model.AlertConditionRedisDown,
+		subject:   "redis",
+		severity:  model.AlertSeverityCritical,
+		summary:   fmt.Sprintf("Redis has been unreachable for %d minutes; sessions and tasks are kept in memory only", int(down.Minutes())),
+		details:   map[string]interface{}{"down_since": am.redisDownSince, "error": err.Error()},
+	}}
+}
+
+// checkAgents fires for each registered agent whose /health does not answer 200
+func (am *AlertManager) checkAgents(ctx context.Context) []alertCondition {
+	if !am.cfg.AgentHealth {
+		return nil
+	}
+
+	agents, err := am.agentRegistry.GetAllAgents(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list agents for health checks")
+		return nil
+	}
+
+	var found []alertCondition
+	for _, agent := range agents {
+		err := am.probe(ctx, strings.TrimRight(agent.Endpoint, "/")+"/health")
+		if err == nil {
+			continue
+		}
+		found = append(found, alertCondition{
+			condition: model.AlertConditionAgentUnreachable,
+			subject:   agent.AgentID,
+			severity:  model.AlertSeverityWarning,
+			summary:   fmt.Sprintf("%s (%s) at %s is unreachable", agent.Name, agent.Type, agent.Endpoint),
+			details:   map[string]interface{}{"agent_type": agent.Type, "endpoint": agent.Endpoint, "error": err.Error()},
+		})
+	}
+	return found
+}
+
+// checkLLM fires when the AI Skin Orchestrator reports an LLM error rate above the
+// threshold over the last 5 minutes
+func (am *AlertManager) checkLLM(ctx context.Context) []alertCondition {
+	if am.cfg.SkinURL == "" {
+		return nil
+	}
+
+	var stats struct {
+		WindowSeconds int     `json:"window_seconds"`
+		Calls         int     `json:"calls"`
+		Errors        int     `json:"errors"`
+		ErrorRate     float64 `json:"error_rate"`
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimRight(am.cfg.SkinURL, "/")+"/api/v1/admin/llm/errors?window=5m", nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("X-API-Key", am.skinAPIKey.Get())
+
+	resp, err := am.httpClient.Do(req)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(&stats)
+	} else if err == nil {
+		err = fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read LLM error stats from the AI Skin Orchestrator")
+		return nil
+	}
+
+	if stats.Calls < am.cfg.LLMMinCalls || stats.ErrorRate <= am.cfg.LLMErrorRate {
+		return nil
+	}
+	return []alertCondition{{
+		condition: model.AlertConditionLLMErrors,
+		subject:   "ai-skin",
+		severity:  model.AlertSeverityWarning,
+		summary: fmt.Sprintf("%d of %d LLM calls failed in the last %d minutes (%.0f%%)",
+			stats.Errors, stats.Calls, stats.WindowSeconds/60, stats.ErrorRate*100),
+		details: map[string]interface{}{"errors": stats.Errors, "calls": stats.Calls, "error_rate": stats.ErrorRate, "threshold": am.cfg.LLMErrorRate},
+	}}
+}
+
+// probe GETs url and fails unless it answers 200
+func (am *AlertManager) probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := am.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// PurgeHistory drops resolved alerts that resolved before cutoff and returns their
+// keys with the time each fired. With dryRun nothing is changed.
+func (am *AlertManager) PurgeHistory(cutoff time.Time, dryRun bool) []string {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	var purged []string
+	kept := am.history[:0:0]
+	for _, alert := range am.history {
+		if alert.ResolvedAt != nil && alert.ResolvedAt.Before(cutoff) {
+			purged = append(purged, alert.Key+"@"+alert.FiredAt.Format(time.RFC3339))
+			continue
+		}
+		kept = append(kept, alert)
+	}
+	if !dryRun {
+		am.history = kept
+	}
+	return purged
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+)
+
+// AlertSink delivers alert notifications to one destination
+type AlertSink interface {
+	Name() string
+	Send(ctx context.Context, alert *model.Alert) error
+}
+
+// NewAlertSinks creates a sink for each destination that is configured
+func NewAlertSinks(cfg *config.AlertsConfig) []AlertSink {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var sinks []AlertSink
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, &WebhookSink{url: cfg.WebhookURL, httpClient: client})
+	}
+	if cfg.SlackWebhookURL != "" {
+		sinks = append(sinks, &SlackSink{url: cfg.SlackWebhookURL, httpClient: client})
+	}
+	if cfg.EmailSMTPAddr != "" && len(cfg.EmailTo) > 0 {
+		sinks = append(sinks, &EmailSink{
+			addr:     cfg.EmailSMTPAddr,
+			username: cfg.EmailUsername,
+			password: cfg.EmailPassword,
+			from:     cfg.EmailFrom,
+			to:       cfg.EmailTo,
+		})
+	}
+	return sinks
+}
+
+// WebhookSink posts the alert as JSON
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// Name returns the sink name
+func (ws *WebhookSink) Name() string { return "webhook" }
+
+// Send posts the alert
+func (ws *WebhookSink) Send(ctx context.Context, alert *model.Alert) error {
+	return postJSON(ctx, ws.httpClient, ws.url, alert)
+}
+
+// SlackSink posts a one-line message to a Slack incoming webhook
+type SlackSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// Name returns the sink name
+func (ss *SlackSink) Name() string { return "slack" }
+
+// Send posts the alert as a Slack message
+func (ss *SlackSink) Send(ctx context.Context, alert *model.Alert) error {
+	icon := ":rotating_light:"
+	if alert.Status == model.AlertResolved {
+		icon = ":white_check_mark:"
+	}
+	text := fmt.Sprintf("%s *[%s] %s* %s", icon, alert.Status, alert.Severity, alert.Summary)
+	return postJSON(ctx, ss.httpClient, ss.url, map[string]string{"text": text})
+}
+
+// EmailSink mails the alert over SMTP
+type EmailSink struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// Name returns the sink name
+func (es *EmailSink) Name() string { return "email" }
+
+// Send mails the alert. PLAIN authentication is used when a username is set.
+func (es *EmailSink) Send(ctx context.Context, alert *model.Alert) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", es.from)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(es.to, ", "))
+	fmt.Fprintf(&body, "Subject: [%s] %s\r\n\r\n", alert.Status, alert.Summary)
+	fmt.Fprintf(&body, "Condition: %s\r\nSubject: %s\r\nSeverity: %s\r\nFired at: %s\r\n",
+		alert.Condition, alert.Subject, alert.Severity, alert.FiredAt.Format(time.RFC3339))
+	if alert.ResolvedAt != nil {
+		fmt.Fprintf(&body, "Resolved at: %s\r\n", alert.ResolvedAt.Format(time.RFC3339))
+	}
+	for k, v := range alert.Details {
+		fmt.Fprintf(&body, "%s: %v\r\n", k, v)
+	}
+
+	var auth smtp.Auth
+	if es.username != "" {
+		host := es.addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", es.username, es.password, host)
+	}
+	if err := smtp.SendMail(es.addr, auth, es.from, es.to, []byte(body.String())); err != nil {
+		return fmt.Errorf("failed to send alert email: %w", err)
+	}
+	return nil
+}
+
+// postJSON posts payload and fails on a non-2xx answer
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alert endpoint returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
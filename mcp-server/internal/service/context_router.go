@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/demo"
 	"github.com/rs/zerolog/log"
 )
 
@@ -12,16 +14,38 @@ import (
 type ContextRouter struct {
 	agentRegistry *AgentRegistry
 	ruleEngine   *RuleEngine
+	intents      *IntentRegistry
+	fastPath     *FastPath
+	demo         *demo.Library // Nil unless DEMO_MODE is on
 }
 
 // NewContextRouter creates a new context router instance
-func NewContextRouter(agentRegistry *AgentRegistry, ruleEngine *RuleEngine) *ContextRouter {
+func NewContextRouter(agentRegistry *AgentRegistry, ruleEngine *RuleEngine, intents *IntentRegistry) *ContextRouter {
 	return &ContextRouter{
 		agentRegistry: agentRegistry,
 		ruleEngine:    ruleEngine,
+		intents:       intents,
 	}
 }
 
+// SetDemo runs the tasks of a demo scenario through the orchestration plan it names
+func (cr *ContextRouter) SetDemo(scenarios *demo.Library) {
+	cr.demo = scenarios
+}
+
+// SetFastPath sends small transfers that qualify straight to the banking agent
+func (cr *ContextRouter) SetFastPath(fastPath *FastPath) {
+	cr.fastPath = fastPath
+}
+
+// FastPathAudit returns the transfers that took the fast path, newest first
+func (cr *ContextRouter) FastPathAudit(userID string, limit int) []model.FastPathRecord {
+	if cr.fastPath == nil {
+		return []model.FastPathRecord{}
+	}
+	return cr.fastPath.Audit(userID, limit)
+}
+
 // RouteTask determines the appropriate agent for a task based on context
 func (cr *ContextRouter) RouteTask(ctx context.Context, task *model.Task, session *model.Session) (*model.RoutingDecision, error) {
 	// Build enriched context
@@ -33,9 +57,35 @@ func (cr *ContextRouter) RouteTask(ctx context.Context, task *model.Task, sessio
 		return nil, fmt.Errorf("failed to evaluate routing rules: %w", err)
 	}
 
-	// If no agent selected by rules, use intent-based routing
+	// Tiny transfers to known beneficiaries by low-risk users skip the checks, unless
+	// a rule or plan claimed the task
+	if decision.SelectedAgentID == "" && decision.PlanID == "" {
+		if fast := cr.routeFastPath(ctx, task, session, enrichedContext); fast != nil {
+			log.Info().
+				Str("task_id", task.TaskID).
+				Str("intent", task.Intent).
+				Str("selected_agent", fast.SelectedAgentID).
+				Strs("skipped_checks", fast.SkippedChecks).
+				Msg("Task routed on the fast path")
+			return fast, nil
+		}
+	}
+
+	// If no agent selected by rules, use intent-based routing. A plan the rules chose
+	// still applies; the agent found here is then only what holds and step-up see.
 	if decision.SelectedAgentID == "" {
+		planID := decision.PlanID
 		decision = cr.routeByIntent(ctx, task, enrichedContext)
+		if planID != "" {
+			decision.PlanID = planID
+			decision.Reason = fmt.Sprintf("%s; orchestration plan %s", decision.Reason, planID)
+		}
+	}
+
+	// A demo scenario's checks are only reached when its tasks run through its plan
+	if scenario, ok := cr.demo.Get(demo.ScenarioID(task.Context)); ok && scenario.Plan != "" {
+		decision.PlanID = scenario.Plan
+		decision.Reason = fmt.Sprintf("%s; demo scenario %s", decision.Reason, scenario.ID)
 	}
 
 	log.Info().
@@ -43,6 +93,7 @@ func (cr *ContextRouter) RouteTask(ctx context.Context, task *model.Task, sessio
 		Str("intent", task.Intent).
 		Str("selected_agent", decision.SelectedAgentID).
 		Float64("confidence", decision.Confidence).
+		Str("plan_id", decision.PlanID).
 		Msg("Task routed to agent")
 
 	return decision, nil
@@ -96,8 +147,42 @@ func (cr *ContextRouter) buildContext(task *model.Task, session *model.Session)
 	return ctx
 }
 
+// routeFastPath routes a transfer that qualifies for the fast path to a banking agent
+// and records it in the fast-path audit trail. Returns nil when the transfer does not
+// qualify or no banking agent is healthy, so it is routed, or held, as usual.
+func (cr *ContextRouter) routeFastPath(ctx context.Context, task *model.Task, session *model.Session, enrichedContext *model.Context) *model.RoutingDecision {
+	if cr.fastPath == nil {
+		return nil
+	}
+	record := cr.fastPath.Qualifies(ctx, task, session)
+	if record == nil {
+		return nil
+	}
+	agents, err := cr.agentRegistry.FindAgentsForTenant(ctx, model.AgentTypeBanking, taskTenant(task, enrichedContext))
+	if err != nil || len(agents) == 0 {
+		return nil
+	}
+
+	selectedAgent := agents[0]
+	cr.fastPath.Record(record, selectedAgent.AgentID)
+	return &model.RoutingDecision{
+		SelectedAgentID: selectedAgent.AgentID,
+		AgentType:       string(model.AgentTypeBanking),
+		Confidence:      0.9,
+		Reason:          cr.fastPath.Reason(record),
+		SkippedChecks:   record.SkippedChecks,
+		Context:         enrichedContext,
+	}
+}
+
 // routeByIntent routes task based on intent when rules don't match
 func (cr *ContextRouter) routeByIntent(ctx context.Context, task *model.Task, enrichedContext *model.Context) *model.RoutingDecision {
+	// A tenant's custom intent goes to an agent advertising its capability
+	tenantID := taskTenant(task, enrichedContext)
+	if def, ok := cr.intents.Lookup(tenantID, task.Intent); ok {
+		return cr.routeByCapability(ctx, def, tenantID, enrichedContext)
+	}
+
 	var agentType model.AgentType
 	var reason string
 
@@ -115,10 +200,26 @@ func (cr *ContextRouter) routeByIntent(ctx context.Context, task *model.Task, en
 			reason = "Standard banking transaction"
 		}
 
-	case "CHECK_BALANCE", "GET_STATEMENT", "VIEW_ACCOUNT":
+	case "CHECK_BALANCE", "GET_STATEMENT", "VIEW_ACCOUNT", "LIST_BENEFICIARIES":
 		agentType = model.AgentTypeBanking
 		reason = "Account inquiry operation"
 
+	case "REQUEST_MONEY":
+		agentType = model.AgentTypeBanking
+		reason = "Payment request; no money leaves the account"
+
+	case "SHARE_RECEIPT":
+		agentType = model.AgentTypeBanking
+		reason = "Receipt link for an existing transfer; no money moves"
+
+	case "EXPORT_TRANSACTIONS":
+		agentType = model.AgentTypeBanking
+		reason = "Download link for the user's own transactions; no money moves"
+
+	case "SET_BUDGET", "BUDGET_STATUS":
+		agentType = model.AgentTypeBanking
+		reason = "Spending budget; alerts only, no money moves"
+
 	case "ADD_BENEFICIARY", "MANAGE_BENEFICIARY":
 		agentType = model.AgentTypeGuardrail
 		reason = "Beneficiary management requires validation"
@@ -131,42 +232,90 @@ func (cr *ContextRouter) routeByIntent(ctx context.Context, task *model.Task, en
 		agentType = model.AgentTypeScoring
 		reason = "Credit/risk scoring operation"
 
+	case "GET_INSIGHTS":
+		agentType = model.AgentTypeInsights
+		reason = "Savings and spending insights"
+
 	default:
 		agentType = model.AgentTypeBanking
 		reason = "Default routing to banking agent"
 	}
 
-	// Find available agent of this type
-	agents, err := cr.agentRegistry.FindAgentsByType(ctx, agentType)
+	// Find available agent of this type, among those the task's tenant may use
+	var missing string
+	agents, err := cr.agentRegistry.FindAgentsForTenant(ctx, agentType, tenantID)
 	if err != nil || len(agents) == 0 {
 		log.Warn().
 			Str("agent_type", string(agentType)).
+			Str("tenant_id", tenantID).
 			Msg("No agents found for type, using banking agent as fallback")
-		agents, _ = cr.agentRegistry.FindAgentsByType(ctx, model.AgentTypeBanking)
+		missing = string(agentType)
+		agents, _ = cr.agentRegistry.FindAgentsForTenant(ctx, model.AgentTypeBanking, tenantID)
 	}
 
 	if len(agents) == 0 {
 		return &model.RoutingDecision{
-			SelectedAgentID: "",
-			AgentType:       string(agentType),
-			Confidence:      0.0,
-			Reason:          "No agents available",
-			Context:         enrichedContext,
+			SelectedAgentID:  "",
+			AgentType:        string(agentType),
+			Confidence:       0.0,
+			Reason:           "No agents available",
+			MissingAgentType: missing,
+			Context:          enrichedContext,
 		}
 	}
 
-	// Select first available agent (can be enhanced with load balancing)
+	// The tenant's least busy agent, or the shared pool's as its policy allows
 	selectedAgent := agents[0]
 
+	return &model.RoutingDecision{
+		SelectedAgentID:  selectedAgent.AgentID,
+		AgentType:        string(agentType),
+		Confidence:       0.8,
+		Reason:           reason,
+		MissingAgentType: missing,
+		Context:          enrichedContext,
+	}
+}
+
+// routeByCapability routes a custom intent to a healthy agent advertising the
+// capability its definition names that the tenant may use. With none the task cannot
+// run; it is not held, as holds wait for an agent type.
+func (cr *ContextRouter) routeByCapability(ctx context.Context, def *model.IntentDefinition, tenantID string, enrichedContext *model.Context) *model.RoutingDecision {
+	agents, _ := cr.agentRegistry.FindAgentsByCapability(ctx, def.Capability)
+	sort.Slice(agents, func(a, b int) bool { return agents[a].AgentID < agents[b].AgentID })
+	agents = cr.agentRegistry.ForTenant(tenantID, agents)
+	if len(agents) == 0 {
+		log.Warn().
+			Str("intent", def.Intent).
+			Str("capability", def.Capability).
+			Msg("No agent advertises the capability of a custom intent")
+		return &model.RoutingDecision{
+			Confidence: 0.0,
+			Reason:     fmt.Sprintf("No agent advertises capability %s", def.Capability),
+			Context:    enrichedContext,
+		}
+	}
+
+	selectedAgent := agents[0]
 	return &model.RoutingDecision{
 		SelectedAgentID: selectedAgent.AgentID,
-		AgentType:       string(agentType),
+		AgentType:       string(selectedAgent.Type),
 		Confidence:      0.8,
-		Reason:          reason,
+		Reason:          fmt.Sprintf("Custom intent %s routed by capability %s", def.Intent, def.Capability),
 		Context:         enrichedContext,
 	}
 }
 
+// taskTenant returns the tenant a task was submitted for, from the task's context or
+// else its session's
+func taskTenant(task *model.Task, enrichedContext *model.Context) string {
+	if tenantID, ok := task.Context["tenant_id"].(string); ok && tenantID != "" {
+		return tenantID
+	}
+	tenantID, _ := enrichedContext.Metadata["tenant_id"].(string)
+	return tenantID
+}
+
 // shouldRouteToGuardrail determines if transaction needs guardrail check
 func (cr *ContextRouter) shouldRouteToGuardrail(ctx *model.Context) bool {
 	// Route to guardrail for high-risk transactions or new beneficiaries
@@ -0,0 +1,74 @@
+package service
+
+import "sync"
+
+// debitIntents are the intents that move money out of a customer's account
+var debitIntents = map[string]bool{
+	"TRANSFER_NEFT": true,
+	"TRANSFER_RTGS": true,
+	"TRANSFER_IMPS": true,
+	"TRANSFER_UPI":  true,
+}
+
+// isDebitIntent reports whether an intent moves money out of an account
+func isDebitIntent(intent string) bool {
+	return debitIntents[intent]
+}
+
+// DebitLocks serializes money-moving tasks per user so two transfers cannot race each
+// other through guardrails and balance checks. Tasks are keyed by user rather than
+// account because the source account is often resolved later, by the Banking Agent.
+type DebitLocks struct {
+	locks map[string]*debitLock
+	mu    sync.Mutex
+}
+
+// debitLock is one user's lock and the number of tasks holding or waiting for it
+type debitLock struct {
+	mu    sync.Mutex
+	users int
+}
+
+// NewDebitLocks creates a new set of per-user debit locks
+func NewDebitLocks() *DebitLocks {
+	return &DebitLocks{
+		locks: make(map[string]*debitLock),
+	}
+}
+
+// Lock blocks until no other debit task for the user is running and returns the
+// function that releases the lock
+func (d *DebitLocks) Lock(userID string) func() {
+	d.mu.Lock()
+	l, ok := d.locks[userID]
+	if !ok {
+		l = &debitLock{}
+		d.locks[userID] = l
+	}
+	l.users++
+	d.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		d.mu.Lock()
+		l.users--
+		if l.users == 0 {
+			delete(d.locks, userID)
+		}
+		d.mu.Unlock()
+	}
+}
+
+// Waiting returns how many debit tasks for the user are running or waiting
+func (d *DebitLocks) Waiting(userID string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if l, ok := d.locks[userID]; ok {
+		return l.users
+	}
+	return 0
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Weights of the parts of a device's trust score. They add up to 1; each failed
+// step-up answer since the last pass takes deviceFailurePenalty off.
+const (
+	deviceTenureWeight   = 0.25 // Full after deviceTenureDays
+	deviceUsageWeight    = 0.15 // Full after deviceUsageTasks
+	deviceStepUpWeight   = 0.4  // Full after deviceStepUpPasses
+	deviceGeoWeight      = 0.2  // Share of the device's tasks made from where it is now
+	deviceFailurePenalty = 0.2
+
+	deviceTenureDays   = 30
+	deviceUsageTasks   = 20
+	deviceStepUpPasses = 3
+)
+
+// DeviceProfiles tracks each user's devices and scores how far each can be trusted.
+// A new device starts untrusted; it earns trust as it is used, from familiar places,
+// and as its user passes step-up checks on it, and loses it through failed checks and
+// as the evidence fades while it goes unused. The score decides the device trust the
+// step-up policy sees, and its complement is given to the fraud and guardrail checks
+// as device_risk. Profiles are kept in Redis, when available, so they survive a restart.
+type DeviceProfiles struct {
+	cfg         *config.DeviceTrustConfig
+	redisClient *redis.Client
+	profiles    map[string]map[string]*model.DeviceProfile // User ID -> device ID -> profile
+	mu          sync.Mutex
+}
+
+// NewDeviceProfiles creates the device profile store
+func NewDeviceProfiles(cfg *config.DeviceTrustConfig, redisClient *redis.Client) *DeviceProfiles {
+	return &DeviceProfiles{
+		cfg:         cfg,
+		redisClient: redisClient,
+		profiles:    make(map[string]map[string]*model.DeviceProfile),
+	}
+}
+
+// deviceProfilesKey is the Redis hash of a user's device profiles, keyed by device ID
+func deviceProfilesKey(userID string) string {
+	return fmt.Sprintf("device_profiles:%s", userID)
+}
+
+// Observe records a task from a device and returns its profile scored for that task.
+// The location is whatever region the client reports, e.g. "IN-MH"; it counts towards
+// trust only once the device has been used from there before.
+func (dp *DeviceProfiles) Observe(ctx context.Context, userID, deviceID, location string) *model.DeviceProfile {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	now := time.Now()
+	profile := dp.load(ctx, userID, deviceID)
+	if profile == nil {
+		profile = &model.DeviceProfile{UserID: userID, DeviceID: deviceID, FirstSeen: now, ScoredAt: now}
+		log.Info().Str("user_id", userID).Str("device_id", deviceID).Str("location", location).Msg("New device seen")
+	}
+
+	dp.decay(profile, now)
+	dp.score(profile, location, now)
+
+	profile.Seen++
+	profile.LastSeen = now
+	if location != "" {
+		if profile.Locations == nil {
+			profile.Locations = make(map[string]int)
+		}
+		profile.Locations[location]++
+		profile.LastLocation = location
+	}
+
+	dp.save(ctx, profile)
+	profileCopy := *profile
+	return &profileCopy
+}
+
+// RecordStepUp records a step-up answer given on a device. A pass adds to the
+// device's trust and clears its failures; each wrong answer takes trust away.
+func (dp *DeviceProfiles) RecordStepUp(ctx context.Context, userID, deviceID string, passed bool) {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	profile := dp.load(ctx, userID, deviceID)
+	if profile == nil {
+		return
+	}
+
+	now := time.Now()
+	dp.decay(profile, now)
+	if passed {
+		profile.StepUpSuccesses++
+		profile.StepUpFailures = 0
+		profile.LastStepUpAt = &now
+	} else {
+		profile.StepUpFailures++
+	}
+	dp.score(profile, profile.LastLocation, now)
+
+	log.Info().Str("user_id", userID).Str("device_id", deviceID).Bool("passed", passed).
+		Float64("trust_score", profile.TrustScore).Str("trust_level", profile.TrustLevel).Msg("Device step-up recorded")
+	dp.save(ctx, profile)
+}
+
+// Profiles returns a user's devices scored as of now, most recently seen first
+func (dp *DeviceProfiles) Profiles(ctx context.Context, userID string) []model.DeviceProfile {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	dp.loadUser(ctx, userID)
+	now := time.Now()
+	profiles := make([]model.DeviceProfile, 0, len(dp.profiles[userID]))
+	for _, profile := range dp.profiles[userID] {
+		current := *profile
+		dp.decay(&current, now)
+		dp.score(&current, current.LastLocation, now)
+		profiles = append(profiles, current)
+	}
+	sort.Slice(profiles, func(a, b int) bool { return profiles[a].LastSeen.After(profiles[b].LastSeen) })
+	return profiles
+}
+
+// Forget drops a device's history, e.g. when it is lost, reporting whether there was
+// one. The device is treated as new the next time it is seen.
+func (dp *DeviceProfiles) Forget(ctx context.Context, userID, deviceID string) bool {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	if dp.load(ctx, userID, deviceID) == nil {
+		return false
+	}
+	delete(dp.profiles[userID], deviceID)
+
+	if dp.redisClient != nil {
+		if err := dp.redisClient.HDel(ctx, deviceProfilesKey(userID), deviceID).Err(); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Str("device_id", deviceID).Msg("Failed to remove device profile from Redis")
+		}
+	}
+	log.Info().Str("user_id", userID).Str("device_id", deviceID).Msg("Device profile forgotten")
+	return true
+}
+
+// decay fades a profile's usage and step-up evidence by the time since it was last
+// scored. Callers hold dp.mu.
+func (dp *DeviceProfiles) decay(profile *model.DeviceProfile, now time.Time) {
+	if dp.cfg.HalfLifeDays <= 0 {
+		return
+	}
+	idleDays := now.Sub(profile.ScoredAt).Hours() / 24
+	if idleDays <= 0 {
+		return
+	}
+	factor := math.Pow(0.5, idleDays/dp.cfg.HalfLifeDays)
+	profile.Seen *= factor
+	profile.StepUpSuccesses *= factor
+}
+
+// score computes a profile's trust score, level and device risk for a task from the
+// given location. Callers hold dp.mu.
+func (dp *DeviceProfiles) score(profile *model.DeviceProfile, location string, now time.Time) {
+	tenure := math.Min(now.Sub(profile.FirstSeen).Hours()/24/deviceTenureDays, 1)
+	usage := math.Min(profile.Seen/deviceUsageTasks, 1)
+	stepUps := math.Min(profile.StepUpSuccesses/deviceStepUpPasses, 1)
+
+	// Without a location there is nothing to go on either way
+	geo := 0.5
+	if location != "" {
+		total := 0
+		for _, count := range profile.Locations {
+			total += count
+		}
+		geo = 0
+		if total > 0 {
+			geo = float64(profile.Locations[location]) / float64(total)
+		}
+	}
+
+	score := deviceTenureWeight*tenure + deviceUsageWeight*usage + deviceStepUpWeight*stepUps + deviceGeoWeight*geo -
+		deviceFailurePenalty*float64(profile.StepUpFailures)
+	score = math.Round(math.Max(0, math.Min(score, 1))*1000) / 1000
+
+	profile.TrustScore = score
+	profile.DeviceRisk = math.Round((1-score)*1000) / 1000
+	profile.ScoredAt = now
+	switch {
+	case score >= dp.cfg.TrustedScore:
+		profile.TrustLevel = model.DeviceTrusted
+	case score >= dp.cfg.KnownScore:
+		profile.TrustLevel = model.DeviceKnown
+	default:
+		profile.TrustLevel = model.DeviceUnknown
+	}
+}
+
+// load returns a device's profile from memory, or from Redis when this instance has
+// not seen it. Callers hold dp.mu.
+func (dp *DeviceProfiles) load(ctx context.Context, userID, deviceID string) *model.DeviceProfile {
+	if profile, ok := dp.profiles[userID][deviceID]; ok {
+		return profile
+	}
+	if dp.redisClient == nil {
+		return nil
+	}
+
+	data, err := dp.redisClient.HGet(ctx, deviceProfilesKey(userID), deviceID).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Failed to read device profile from Redis")
+		}
+		return nil
+	}
+	var profile model.DeviceProfile
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Str("device_id", deviceID).Msg("Skipping unreadable device profile")
+		return nil
+	}
+	dp.remember(&profile)
+	return &profile
+}
+
+// loadUser reads all of a user's device profiles from Redis into memory. Callers
+// hold dp.mu.
+func (dp *DeviceProfiles) loadUser(ctx context.Context, userID string) {
+	if dp.redisClient == nil {
+		return
+	}
+	saved, err := dp.redisClient.HGetAll(ctx, deviceProfilesKey(userID)).Result()
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to load device profiles from Redis")
+		return
+	}
+	for deviceID, data := range saved {
+		if _, ok := dp.profiles[userID][deviceID]; ok {
+			continue
+		}
+		var profile model.DeviceProfile
+		if err := json.Unmarshal([]byte(data), &profile); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Str("device_id", deviceID).Msg("Skipping unreadable device profile")
+			continue
+		}
+		dp.remember(&profile)
+	}
+}
+
+// save keeps a profile in memory and in Redis. Callers hold dp.mu.
+func (dp *DeviceProfiles) save(ctx context.Context, profile *model.DeviceProfile) {
+	dp.remember(profile)
+	if dp.redisClient == nil {
+		return
+	}
+	if data, err := json.Marshal(profile); err == nil {
+		if err := dp.redisClient.HSet(ctx, deviceProfilesKey(profile.UserID), profile.DeviceID, data).Err(); err != nil {
+			log.Warn().Err(err).Str("user_id", profile.UserID).Msg("Failed to save device profile to Redis, kept in memory only")
+		}
+	}
+}
+
+// remember keeps a profile in memory. Callers hold dp.mu.
+func (dp *DeviceProfiles) remember(profile *model.DeviceProfile) {
+	if dp.profiles[profile.UserID] == nil {
+		dp.profiles[profile.UserID] = make(map[string]*model.DeviceProfile)
+	}
+	dp.profiles[profile.UserID][profile.DeviceID] = profile
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Redis keys of instance liveness and of recovery claims
+const (
+	instanceKeyPrefix = "mcp:instance:"
+	recoverKeyPrefix  = "mcp:recover:"
+)
+
+// recoverClaimTTL is how long an instance that claimed a task for recovery has to
+// resume or fail it over before another may try
+const recoverClaimTTL = 10 * time.Minute
+
+// unownedStaleAfter is how long a task that no instance stamped, created before
+// instances were tracked, must have been left untouched before it is recovered. It is
+// longer than any agent call.
+const unownedStaleAfter = 2 * time.Minute
+
+// handoverFile is what a draining instance leaves in DRAIN_HANDOVER_FILE when its
+// tasks are not in Redis
+type handoverFile struct {
+	Instance     string       `json:"instance"`
+	HandedOverAt time.Time    `json:"handed_over_at"`
+	Tasks        []model.Task `json:"tasks"`
+}
+
+// Drainer lets the server be replaced without losing tasks. On shutdown it stops new
+// tasks and waits up to DRAIN_TIMEOUT_SECONDS for running ones to finish; those still
+// running are marked as handed over, and written to DRAIN_HANDOVER_FILE when they
+// live only in this instance's memory. On startup it picks up the tasks a stopped
+// instance left unfinished, whether it drained or crashed: tasks are re-run from the
+// start, except transfers an agent was carrying out, which may have gone through and
+// are failed over for reconciliation to settle.
+//
+// Instances keep a liveness key in Redis, renewed every DRAIN_HEARTBEAT_SECONDS, so a
+// starting instance leaves alone the tasks of those still running.
+type Drainer struct {
+	cfg          *config.DrainConfig
+	orchestrator *Orchestrator
+	taskManager  *TaskManager
+	redisClient  *redis.Client
+	instance     string
+}
+
+// NewDrainer creates a drainer for this instance and stamps its ID on the tasks it runs
+func NewDrainer(cfg *config.DrainConfig, orchestrator *Orchestrator, taskManager *TaskManager, redisClient *redis.Client) *Drainer {
+	d := &Drainer{
+		cfg:          cfg,
+		orchestrator: orchestrator,
+		taskManager:  taskManager,
+		redisClient:  redisClient,
+		instance:     ids.New(ids.Instance),
+	}
+	taskManager.SetInstance(d.instance)
+	return d
+}
+
+// Instance returns the ID of this instance
+func (d *Drainer) Instance() string {
+	return d.instance
+}
+
+// Heartbeat renews this instance's liveness key until ctx is done
+func (d *Drainer) Heartbeat(ctx context.Context) {
+	if !d.taskManager.Persistent() {
+		return
+	}
+	interval := time.Duration(d.cfg.HeartbeatSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := d.redisClient.Set(ctx, instanceKeyPrefix+d.instance, time.Now().Unix(), 3*interval).Err(); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Str("instance", d.instance).Msg("Failed to renew instance heartbeat")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain stops new tasks, waits up to DRAIN_TIMEOUT_SECONDS or until ctx is done for
+// the tasks in flight, and hands over those still running. Returns how many were
+// handed over.
+func (d *Drainer) Drain(ctx context.Context) int {
+	d.orchestrator.Drain()
+
+	started := time.Now()
+	deadline := started.Add(time.Duration(d.cfg.TimeoutSeconds) * time.Second)
+	for d.orchestrator.InFlight() > 0 && time.Now().Before(deadline) && ctx.Err() == nil {
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Info().
+		Int("in_flight", d.orchestrator.InFlight()).
+		Dur("waited", time.Since(started)).
+		Msg("Finished waiting for running tasks")
+
+	// Writes below must happen even if ctx ran out waiting
+	ctx = context.Background()
+	handedOver := d.handOver(ctx)
+
+	if d.taskManager.Persistent() {
+		if err := d.redisClient.Del(ctx, instanceKeyPrefix+d.instance).Err(); err != nil {
+			log.Warn().Err(err).Str("instance", d.instance).Msg("Failed to remove instance heartbeat")
+		}
+	}
+	return handedOver
+}
+
+// handOver marks this instance's unfinished tasks as handed over, and writes those
+// that are not in Redis to the handover file. Stateless tasks are never written out,
+// and are lost.
+func (d *Drainer) handOver(ctx context.Context) int {
+	tasks, err := d.taskManager.Unfinished(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list unfinished tasks, none handed over")
+		return 0
+	}
+
+	now := time.Now()
+	file := handoverFile{Instance: d.instance, HandedOverAt: now}
+	handedOver, lost := 0, 0
+	for _, task := range tasks {
+		if task.Instance != d.instance {
+			continue
+		}
+		if task.Stateless {
+			lost++
+			continue
+		}
+		handover := &model.TaskHandover{
+			FromInstance: d.instance,
+			Reason:       model.HandoverDrainTimeout,
+			Stage:        task.Status,
+			HandedOverAt: now,
+			Outcome:      model.HandoverPending,
+		}
+		if err := d.taskManager.SetHandover(ctx, task.TaskID, handover); err != nil {
+			log.Warn().Err(err).Str("task_id", task.TaskID).Msg("Failed to hand over task")
+			continue
+		}
+		handedOver++
+		if !d.taskManager.Persistent() {
+			task.Handover = handover
+			file.Tasks = append(file.Tasks, task)
+		}
+	}
+
+	if len(file.Tasks) > 0 {
+		if err := writeHandoverFile(d.cfg.HandoverFile, &file); err != nil {
+			log.Error().Err(err).Str("file", d.cfg.HandoverFile).Int("tasks", len(file.Tasks)).Msg("Failed to write handover file, its tasks are lost")
+		} else {
+			log.Info().Str("file", d.cfg.HandoverFile).Int("tasks", len(file.Tasks)).Msg("Unfinished tasks written to handover file")
+		}
+	}
+	if handedOver > 0 || lost > 0 {
+		log.Warn().Int("handed_over", handedOver).Int("stateless_lost", lost).Msg("Tasks left unfinished at shutdown")
+	}
+	return handedOver
+}
+
+// Recover picks up the tasks stopped instances left unfinished: those handed over in
+// the handover file or in Redis, and those of instances whose heartbeat has expired.
+// A task is claimed in Redis first, so instances starting together recover it once.
+func (d *Drainer) Recover(ctx context.Context) {
+	if !d.cfg.RecoverOnStart {
+		return
+	}
+	fromFile := d.loadHandoverFile(ctx)
+
+	tasks, err := d.taskManager.Unfinished(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list unfinished tasks, none recovered")
+		return
+	}
+
+	resumed, failedOver, skipped := 0, 0, 0
+	for _, task := range tasks {
+		if !d.orphaned(ctx, &task) {
+			skipped++
+			continue
+		}
+		if !d.claim(ctx, task.TaskID) {
+			skipped++
+			continue
+		}
+
+		handover := task.Handover
+		if handover == nil || handover.Outcome != model.HandoverPending {
+			handover = &model.TaskHandover{
+				FromInstance: task.Instance,
+				Reason:       model.HandoverInstanceLost,
+				Stage:        task.Status,
+				HandedOverAt: task.UpdatedAt,
+			}
+		}
+		now := time.Now()
+		handover.ToInstance = d.instance
+		handover.RecoveredAt = &now
+
+		if interrupted(&task) {
+			handover.Outcome = model.HandoverFailedOver
+			d.failOver(ctx, &task, handover, "The server stopped while this transfer was being carried out, and it may have gone through. Check the account before trying again.")
+			failedOver++
+			continue
+		}
+
+		handover.Outcome = model.HandoverResumed
+		if err := d.taskManager.SetHandover(ctx, task.TaskID, handover); err != nil {
+			log.Warn().Err(err).Str("task_id", task.TaskID).Msg("Failed to record task recovery")
+		}
+		if _, err := d.orchestrator.ResumeTask(ctx, task.TaskID); err != nil {
+			handover.Outcome = model.HandoverFailedOver
+			d.failOver(ctx, &task, handover, fmt.Sprintf("The server restarted and the task could not be resumed: %s", err.Error()))
+			failedOver++
+			continue
+		}
+		log.Info().
+			Str("task_id", task.TaskID).
+			Str("from_instance", handover.FromInstance).
+			Str("stage", string(handover.Stage)).
+			Msg("Task resumed after handover")
+		resumed++
+	}
+
+	if resumed > 0 || failedOver > 0 || fromFile > 0 {
+		log.Info().
+			Str("instance", d.instance).
+			Int("from_file", fromFile).
+			Int("resumed", resumed).
+			Int("failed_over", failedOver).
+			Int("skipped", skipped).
+			Msg("Recovered tasks left by stopped instances")
+	}
+}
+
+// orphaned reports whether a task's instance has stopped: it handed the task over, its
+// heartbeat expired, or, for a task no instance stamped, it has not been touched for
+// a while
+func (d *Drainer) orphaned(ctx context.Context, task *model.Task) bool {
+	if task.Instance == d.instance {
+		return false
+	}
+	if task.Handover != nil && task.Handover.Outcome == model.HandoverPending {
+		return true
+	}
+	if !d.taskManager.Persistent() {
+		return true
+	}
+	if task.Instance == "" {
+		return time.Since(task.UpdatedAt) > unownedStaleAfter
+	}
+	alive, err := d.redisClient.Exists(ctx, instanceKeyPrefix+task.Instance).Result()
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", task.TaskID).Msg("Failed to check task instance, leaving task alone")
+		return false
+	}
+	return alive == 0
+}
+
+// claim reserves a task for recovery by this instance
+func (d *Drainer) claim(ctx context.Context, taskID string) bool {
+	if !d.taskManager.Persistent() {
+		return true
+	}
+	claimed, err := d.redisClient.SetNX(ctx, recoverKeyPrefix+taskID, d.instance, recoverClaimTTL).Result()
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", taskID).Msg("Failed to claim task for recovery")
+		return false
+	}
+	return claimed
+}
+
+// failOver fails a task that cannot be resumed
+func (d *Drainer) failOver(ctx context.Context, task *model.Task, handover *model.TaskHandover, reason string) {
+	if err := d.taskManager.SetHandover(ctx, task.TaskID, handover); err != nil {
+		log.Warn().Err(err).Str("task_id", task.TaskID).Msg("Failed to record task failover")
+	}
+	if err := d.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, reason); err != nil {
+		log.Error().Err(err).Str("task_id", task.TaskID).Msg("Failed to fail over task")
+		return
+	}
+	log.Warn().
+		Str("task_id", task.TaskID).
+		Str("intent", task.Intent).
+		Str("from_instance", handover.FromInstance).
+		Str("stage", string(handover.Stage)).
+		Msg("Task failed over after handover")
+}
+
+// interrupted reports whether a task may have moved money before its instance stopped:
+// a transfer, split or plan its agents were carrying out. Running it again could pay
+// twice.
+func interrupted(task *model.Task) bool {
+	if task.Status != model.TaskStatusExecuting {
+		return false
+	}
+	return isDebitIntent(task.Intent) || task.Split != nil || task.PlanRun != nil
+}
+
+// loadHandoverFile takes in the tasks a drained instance wrote to the handover file,
+// and removes the file. Returns how many there were.
+func (d *Drainer) loadHandoverFile(ctx context.Context) int {
+	data, err := os.ReadFile(d.cfg.HandoverFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0
+	}
+	if err != nil {
+		log.Error().Err(err).Str("file", d.cfg.HandoverFile).Msg("Failed to read handover file")
+		return 0
+	}
+
+	var file handoverFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Error().Err(err).Str("file", d.cfg.HandoverFile).Msg("Invalid handover file, left in place")
+		return 0
+	}
+	for i := range file.Tasks {
+		d.taskManager.Restore(ctx, &file.Tasks[i])
+	}
+	if err := os.Remove(d.cfg.HandoverFile); err != nil {
+		log.Warn().Err(err).Str("file", d.cfg.HandoverFile).Msg("Failed to remove handover file")
+	}
+	log.Info().
+		Str("file", d.cfg.HandoverFile).
+		Str("from_instance", file.Instance).
+		Int("tasks", len(file.Tasks)).
+		Msg("Loaded tasks from handover file")
+	return len(file.Tasks)
+}
+
+// writeHandoverFile writes the file next to its destination first, so a crash midway
+// never leaves half a file to recover from
+func writeHandoverFile(path string, file *handoverFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal handover: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write handover: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to move handover into place: %w", err)
+	}
+	return nil
+}
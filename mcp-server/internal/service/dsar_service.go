@@ -0,0 +1,651 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/aibanking/shared/secrets"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Redis keys for data-subject requests
+const (
+	dsarRequestsKey     = "dsar:requests" // Hash of requests, keyed by request ID
+	dsarExportKeyPrefix = "dsar:export:"  // Compiled exports, one key per request
+)
+
+// Stores a data-subject request gathers from
+const (
+	dsarStoreSessions     = "mcp.sessions"
+	dsarStoreTasks        = "mcp.tasks"
+	dsarStoreRequests     = "mcp.dsar_requests"
+	dsarStoreLegalHolds   = "mcp.legal_holds"
+	dsarStoreSkin         = "ai_skin"
+	dsarStoreProfile      = "banking.dwh_profile"
+	dsarStoreTransactions = "banking.dwh_transactions"
+	dsarStorePreferences  = "banking.preferences"
+)
+
+// dsarRecordKeepingNote explains why DWH records survive an erasure
+const dsarRecordKeepingNote = "Kept for the period required by the PML Rules and RBI KYC record-keeping directions"
+
+// Data-subject request errors
+var (
+	ErrDSARNotFound         = errors.New("data-subject request not found")
+	ErrDSARSelfVerification = errors.New("the requester of a data-subject request cannot verify it")
+)
+
+// DSARStateError is returned when a request is not in a state that allows the action
+type DSARStateError struct {
+	Status string
+	Action string
+	Detail string
+}
+
+func (e *DSARStateError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("cannot %s a %s request: %s", e.Action, e.Status, e.Detail)
+	}
+	return fmt.Sprintf("cannot %s a %s request", e.Action, e.Status)
+}
+
+// DSARService carries data-subject access and erasure requests from receipt to a
+// signed completion record. It gathers the user's data from the MCP server's own
+// stores, the AI Skin Orchestrator and the banking integrations' DWH. Requests and
+// exports are kept in Redis, when available, so they survive a restart.
+type DSARService struct {
+	cfg              *config.DSARConfig
+	sessionManager   *SessionManager
+	taskManager      *TaskManager
+	retentionManager *RetentionManager
+	redisClient      *redis.Client
+	httpClient       *http.Client
+	skinAPIKey       *secrets.Value
+	bankingAPIKey    *secrets.Value
+	requests         map[string]*model.DSAR
+	exports          map[string][]byte // Keyed by request ID
+	mu               sync.Mutex
+	processMu        sync.Mutex // One request processed at a time
+}
+
+// NewDSARService creates a data-subject request service
+func NewDSARService(cfg *config.DSARConfig, sessionManager *SessionManager, taskManager *TaskManager, retentionManager *RetentionManager, redisClient *redis.Client) *DSARService {
+	ds := &DSARService{
+		cfg:              cfg,
+		sessionManager:   sessionManager,
+		taskManager:      taskManager,
+		retentionManager: retentionManager,
+		redisClient:      redisClient,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		skinAPIKey:       config.Profile().RotatingSecret("DSAR_SKIN_API_KEY", cfg.SkinAPIKey),
+		bankingAPIKey:    config.Profile().RotatingSecret("DSAR_BANKING_API_KEY", cfg.BankingAPIKey),
+		requests:         make(map[string]*model.DSAR),
+		exports:          make(map[string][]byte),
+	}
+	ds.loadRequests(context.Background())
+	return ds
+}
+
+// Create opens a request. It is due DSAR_DEADLINE_DAYS after receipt.
+func (ds *DSARService) Create(req *model.DSARCreateRequest) (*model.DSAR, error) {
+	if req.UserID == "" || req.RequestedBy == "" {
+		return nil, fmt.Errorf("user_id and requested_by are required")
+	}
+	req.Type = strings.ToUpper(req.Type)
+	if req.Type != model.DSARAccess && req.Type != model.DSARErasure {
+		return nil, fmt.Errorf("type must be %s or %s", model.DSARAccess, model.DSARErasure)
+	}
+
+	now := time.Now()
+	dsar := &model.DSAR{
+		ID:          ids.New(ids.DSAR),
+		UserID:      req.UserID,
+		Type:        req.Type,
+		Regulation:  strings.ToUpper(req.Regulation),
+		Status:      model.DSARReceived,
+		RequestedBy: req.RequestedBy,
+		Channel:     req.Channel,
+		ReceivedAt:  now,
+		DueAt:       now.AddDate(0, 0, ds.cfg.DeadlineDays),
+		History:     []model.DSARStateChange{{Status: model.DSARReceived, At: now, By: req.RequestedBy}},
+	}
+
+	ds.mu.Lock()
+	ds.requests[dsar.ID] = dsar
+	ds.saveRequest(dsar)
+	result := ds.view(dsar)
+	ds.mu.Unlock()
+
+	log.Info().Str("dsar_id", dsar.ID).Str("user_id", dsar.UserID).Str("type", dsar.Type).Msg("Data-subject request received")
+	return result, nil
+}
+
+// Get returns a request
+func (ds *DSARService) Get(id string) (*model.DSAR, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	dsar, ok := ds.requests[id]
+	if !ok {
+		return nil, ErrDSARNotFound
+	}
+	return ds.view(dsar), nil
+}
+
+// List returns requests with the given status, or all of them, oldest due first
+func (ds *DSARService) List(status string) []model.DSAR {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	status = strings.ToUpper(status)
+	requests := make([]model.DSAR, 0, len(ds.requests))
+	for _, dsar := range ds.requests {
+		if status == "" || dsar.Status == status {
+			requests = append(requests, *ds.view(dsar))
+		}
+	}
+	sort.Slice(requests, func(a, b int) bool { return requests[a].DueAt.Before(requests[b].DueAt) })
+	return requests
+}
+
+// Verify records that the requester was confirmed to be the data subject. by is the
+// operator who checked; it cannot be whoever opened the request.
+func (ds *DSARService) Verify(id, by string, verification *model.DSARVerification) (*model.DSAR, error) {
+	if verification.Method == "" {
+		return nil, fmt.Errorf("method is required")
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	dsar, ok := ds.requests[id]
+	if !ok {
+		return nil, ErrDSARNotFound
+	}
+	if dsar.Status != model.DSARReceived {
+		return nil, &DSARStateError{Status: dsar.Status, Action: "verify"}
+	}
+	if strings.EqualFold(strings.TrimSpace(dsar.RequestedBy), by) {
+		return nil, ErrDSARSelfVerification
+	}
+
+	verification.VerifiedBy = by
+	verification.VerifiedAt = time.Now()
+	dsar.Verification = verification
+	ds.transition(dsar, model.DSARVerified, verification.VerifiedBy, verification.Method)
+	return ds.view(dsar), nil
+}
+
+// Reject closes a request without acting on it. by is the operator closing it.
+func (ds *DSARService) Reject(id, by string, req *model.DSARRejectRequest) (*model.DSAR, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	dsar, ok := ds.requests[id]
+	if !ok {
+		return nil, ErrDSARNotFound
+	}
+	if dsar.Status != model.DSARReceived && dsar.Status != model.DSARVerified {
+		return nil, &DSARStateError{Status: dsar.Status, Action: "reject"}
+	}
+
+	dsar.RejectedReason = req.Reason
+	ds.transition(dsar, model.DSARRejected, by, req.Reason)
+	return ds.view(dsar), nil
+}
+
+// Process carries out a verified request: an access request compiles the export and
+// an erasure request erases what may be erased and checks that it is gone. Either
+// ends with a signed completion record. A request that failed can be processed again.
+func (ds *DSARService) Process(ctx context.Context, id, by string) (*model.DSAR, error) {
+	ds.processMu.Lock()
+	defer ds.processMu.Unlock()
+
+	ds.mu.Lock()
+	dsar, ok := ds.requests[id]
+	if !ok {
+		ds.mu.Unlock()
+		return nil, ErrDSARNotFound
+	}
+	if dsar.Status != model.DSARVerified && dsar.Status != model.DSARFailed {
+		status := dsar.Status
+		ds.mu.Unlock()
+		return nil, &DSARStateError{Status: status, Action: "process", Detail: "the requester must be verified first"}
+	}
+	if dsar.Type == model.DSARErasure && ds.retentionManager.IsHeld(dsar.UserID) {
+		status := dsar.Status
+		ds.mu.Unlock()
+		return nil, &DSARStateError{Status: status, Action: "process", Detail: "the user is under a legal hold"}
+	}
+	ds.transition(dsar, model.DSARProcessing, by, "")
+	userID, requestType := dsar.UserID, dsar.Type
+	ds.mu.Unlock()
+
+	var (
+		stores []model.DSARStoreResult
+		export []byte
+		err    error
+	)
+	if requestType == model.DSARAccess {
+		stores, export, err = ds.compileExport(ctx, userID)
+	} else {
+		stores, err = ds.erase(ctx, userID)
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	dsar.Stores = stores
+	if err != nil {
+		dsar.Error = err.Error()
+		ds.transition(dsar, model.DSARFailed, by, dsar.Error)
+		log.Error().Err(err).Str("dsar_id", id).Msg("Data-subject request failed")
+		return ds.view(dsar), nil
+	}
+	dsar.Error = ""
+
+	completion := &model.DSARCompletion{
+		RequestID:    dsar.ID,
+		UserID:       dsar.UserID,
+		Type:         dsar.Type,
+		CompletedAt:  time.Now(),
+		Stores:       stores,
+		Verified:     true,
+		SignatureAlg: "HMAC-SHA256",
+	}
+	if export != nil {
+		sum := sha256.Sum256(export)
+		completion.ExportSHA256 = hex.EncodeToString(sum[:])
+		ds.saveExport(ctx, dsar.ID, export)
+		dsar.ExportAvailable = true
+	}
+	for _, store := range stores {
+		if store.Remaining != nil && *store.Remaining > 0 {
+			completion.Verified = false
+		}
+	}
+	if completion.Signature, err = ds.sign(completion); err != nil {
+		return nil, err
+	}
+	dsar.Completion = completion
+
+	note := ""
+	if !completion.Verified {
+		note = "erased data was found again"
+	}
+	ds.transition(dsar, model.DSARCompleted, by, note)
+	log.Info().Str("dsar_id", id).Str("type", dsar.Type).Bool("verified", completion.Verified).Msg("Data-subject request completed")
+	return ds.view(dsar), nil
+}
+
+// Export returns the compiled export of a completed access request
+func (ds *DSARService) Export(ctx context.Context, id string) ([]byte, error) {
+	ds.mu.Lock()
+	dsar, ok := ds.requests[id]
+	if !ok {
+		ds.mu.Unlock()
+		return nil, ErrDSARNotFound
+	}
+	if !dsar.ExportAvailable {
+		status := dsar.Status
+		ds.mu.Unlock()
+		return nil, &DSARStateError{Status: status, Action: "export", Detail: "only completed access requests have an export"}
+	}
+	export, ok := ds.exports[id]
+	ds.mu.Unlock()
+	if ok {
+		return export, nil
+	}
+
+	if ds.redisClient != nil {
+		if data, err := ds.redisClient.Get(ctx, dsarExportKeyPrefix+id).Bytes(); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("export of %s is no longer available", id)
+}
+
+// VerifySignature reports whether a completion record's signature is valid
+func (ds *DSARService) VerifySignature(completion *model.DSARCompletion) bool {
+	expected, err := ds.sign(completion)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(completion.Signature))
+}
+
+// gathered is one store's data for a user
+type gathered struct {
+	store string
+	items int
+	data  interface{}
+	err   error
+}
+
+// gather reads a user's data from every store
+func (ds *DSARService) gather(ctx context.Context, userID string) []gathered {
+	var results []gathered
+
+	sessions, err := ds.sessionManager.UserSessions(ctx, userID)
+	results = append(results, gathered{store: dsarStoreSessions, items: len(sessions), data: sessions, err: err})
+
+	tasks, err := ds.taskManager.UserTasks(ctx, userID)
+	results = append(results, gathered{store: dsarStoreTasks, items: len(tasks), data: tasks, err: err})
+
+	var holds []model.LegalHold
+	for _, hold := range ds.retentionManager.Holds() {
+		if hold.UserID == userID {
+			holds = append(holds, hold)
+		}
+	}
+	results = append(results, gathered{store: dsarStoreLegalHolds, items: len(holds), data: holds})
+
+	var skin struct {
+		Documents   []map[string]interface{} `json:"documents"`
+		MemoryFacts []map[string]interface{} `json:"memory_facts"`
+		Decisions   []map[string]interface{} `json:"decisions"`
+	}
+	var skinData json.RawMessage
+	_, err = ds.call(ctx, ds.cfg.SkinURL, ds.skinAPIKey.Get(), "GET", "/api/v1/admin/users/"+userID+"/data", nil, &skinData)
+	if err == nil {
+		err = json.Unmarshal(skinData, &skin)
+	}
+	results = append(results, gathered{store: dsarStoreSkin, items: len(skin.Documents) + len(skin.MemoryFacts) + len(skin.Decisions), data: skinData, err: err})
+
+	var profile struct {
+		Data  []map[string]interface{} `json:"data"`
+		Count int                      `json:"count"`
+	}
+	_, err = ds.call(ctx, ds.cfg.BankingURL, ds.bankingAPIKey.Get(), "POST", "/api/v1/dwh/query",
+		map[string]interface{}{"query_type": "USER_PROFILE", "user_id": userID}, &profile)
+	results = append(results, gathered{store: dsarStoreProfile, items: len(profile.Data), data: profile.Data, err: err})
+
+	var history struct {
+		Transactions []map[string]interface{} `json:"transactions"`
+	}
+	_, err = ds.call(ctx, ds.cfg.BankingURL, ds.bankingAPIKey.Get(), "GET",
+		fmt.Sprintf("/api/v1/dwh/history/%s?days=%d", userID, ds.cfg.HistoryDays), nil, &history)
+	results = append(results, gathered{store: dsarStoreTransactions, items: len(history.Transactions), data: history.Transactions, err: err})
+
+	var prefs map[string]interface{}
+	status, err := ds.call(ctx, ds.cfg.BankingURL, ds.bankingAPIKey.Get(), "GET", "/api/v1/preferences/"+userID, nil, &prefs)
+	items := 0
+	if status == http.StatusNotFound {
+		err, prefs = nil, nil
+	} else if prefs != nil {
+		items = 1
+	}
+	results = append(results, gathered{store: dsarStorePreferences, items: items, data: prefs, err: err})
+
+	return results
+}
+
+// compileExport gathers a user's data, including their earlier data-subject
+// requests, into one JSON document
+func (ds *DSARService) compileExport(ctx context.Context, userID string) ([]model.DSARStoreResult, []byte, error) {
+	results := ds.gather(ctx, userID)
+
+	var earlier []model.DSAR
+	ds.mu.Lock()
+	for _, dsar := range ds.requests {
+		if dsar.UserID == userID {
+			earlier = append(earlier, *ds.view(dsar))
+		}
+	}
+	ds.mu.Unlock()
+	sort.Slice(earlier, func(a, b int) bool { return earlier[a].ReceivedAt.Before(earlier[b].ReceivedAt) })
+	results = append(results, gathered{store: dsarStoreRequests, items: len(earlier), data: earlier})
+
+	stores := make([]model.DSARStoreResult, 0, len(results))
+	data := make(map[string]interface{}, len(results))
+	var failed []string
+	for _, r := range results {
+		result := model.DSARStoreResult{Store: r.store, Items: r.items, Outcome: model.DSARStoreExported}
+		if r.err != nil {
+			result.Outcome, result.Note = model.DSARStoreFailed, r.err.Error()
+			failed = append(failed, r.store)
+		}
+		stores = append(stores, result)
+		data[r.store] = r.data
+	}
+	if len(failed) > 0 {
+		return stores, nil, fmt.Errorf("could not read %s", strings.Join(failed, ", "))
+	}
+
+	export, err := json.MarshalIndent(map[string]interface{}{
+		"user_id":     userID,
+		"exported_at": time.Now(),
+		"stores":      data,
+	}, "", "  ")
+	if err != nil {
+		return stores, nil, fmt.Errorf("failed to compile export: %w", err)
+	}
+	return stores, export, nil
+}
+
+// erase deletes a user's data from every store that may be erased, then gathers
+// again to record what remains. DWH profile and transaction records are retained.
+func (ds *DSARService) erase(ctx context.Context, userID string) ([]model.DSARStoreResult, error) {
+	var stores []model.DSARStoreResult
+	var failed []string
+	record := func(store string, items int, err error) {
+		result := model.DSARStoreResult{Store: store, Items: items, Outcome: model.DSARStoreErased}
+		if err != nil {
+			result.Outcome, result.Note = model.DSARStoreFailed, err.Error()
+			failed = append(failed, store)
+		}
+		stores = append(stores, result)
+	}
+
+	sessions, err := ds.sessionManager.UserSessions(ctx, userID)
+	erased := 0
+	for _, session := range sessions {
+		if err = ds.sessionManager.DeleteSession(ctx, session.SessionID); err != nil {
+			break
+		}
+		erased++
+	}
+	record(dsarStoreSessions, erased, err)
+
+	tasks, err := ds.taskManager.UserTasks(ctx, userID)
+	erased = 0
+	for _, task := range tasks {
+		if err = ds.taskManager.DeleteTask(ctx, task.TaskID); err != nil {
+			break
+		}
+		erased++
+	}
+	record(dsarStoreTasks, erased, err)
+
+	var skin struct {
+		Documents   int `json:"documents"`
+		MemoryFacts int `json:"memory_facts"`
+		Decisions   int `json:"decisions"`
+	}
+	_, err = ds.call(ctx, ds.cfg.SkinURL, ds.skinAPIKey.Get(), "DELETE", "/api/v1/admin/users/"+userID+"/data", nil, &skin)
+	record(dsarStoreSkin, skin.Documents+skin.MemoryFacts+skin.Decisions, err)
+
+	status, err := ds.call(ctx, ds.cfg.BankingURL, ds.bankingAPIKey.Get(), "DELETE", "/api/v1/preferences/"+userID, nil, nil)
+	erased = 1
+	if status == http.StatusNotFound {
+		err, erased = nil, 0
+	}
+	record(dsarStorePreferences, erased, err)
+
+	// Check that nothing erasable is left and count what is retained
+	for _, r := range ds.gather(ctx, userID) {
+		switch r.store {
+		case dsarStoreProfile, dsarStoreTransactions:
+			result := model.DSARStoreResult{Store: r.store, Items: r.items, Outcome: model.DSARStoreRetained, Note: dsarRecordKeepingNote}
+			if r.err != nil {
+				result.Note = "Retained; could not be counted: " + r.err.Error()
+			}
+			stores = append(stores, result)
+		case dsarStoreLegalHolds:
+		default:
+			for i := range stores {
+				if stores[i].Store != r.store || stores[i].Outcome != model.DSARStoreErased {
+					continue
+				}
+				if r.err != nil {
+					stores[i].Note = "Could not check what remains: " + r.err.Error()
+					failed = append(failed, r.store)
+					continue
+				}
+				remaining := r.items
+				stores[i].Remaining = &remaining
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return stores, fmt.Errorf("could not erase %s", strings.Join(failed, ", "))
+	}
+	return stores, nil
+}
+
+// call sends a request to another service and decodes its JSON response into out,
+// returning the status code. A 404 is returned without an error.
+func (ds *DSARService) call(ctx context.Context, baseURL, apiKey, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+
+	resp, err := ds.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode %s %s: %w", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// sign returns the HMAC-SHA256 of a completion record with its signature left empty
+func (ds *DSARService) sign(completion *model.DSARCompletion) (string, error) {
+	unsigned := *completion
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal completion record: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(ds.cfg.SigningKey))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// transition moves a request to a new status and saves it. Callers hold ds.mu.
+func (ds *DSARService) transition(dsar *model.DSAR, status, by, note string) {
+	dsar.Status = status
+	dsar.History = append(dsar.History, model.DSARStateChange{Status: status, At: time.Now(), By: by, Note: note})
+	ds.saveRequest(dsar)
+}
+
+// view returns a copy of a request with its overdue flag set. Callers hold ds.mu.
+func (ds *DSARService) view(dsar *model.DSAR) *model.DSAR {
+	dsarCopy := *dsar
+	dsarCopy.History = append([]model.DSARStateChange(nil), dsar.History...)
+	dsarCopy.Stores = append([]model.DSARStoreResult(nil), dsar.Stores...)
+	switch dsar.Status {
+	case model.DSARCompleted:
+		dsarCopy.Overdue = dsar.Completion != nil && dsar.Completion.CompletedAt.After(dsar.DueAt)
+	case model.DSARRejected:
+		dsarCopy.Overdue = false
+	default:
+		dsarCopy.Overdue = time.Now().After(dsar.DueAt)
+	}
+	return &dsarCopy
+}
+
+// saveRequest writes a request to Redis. Callers hold ds.mu.
+func (ds *DSARService) saveRequest(dsar *model.DSAR) {
+	if ds.redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(dsar)
+	if err != nil {
+		return
+	}
+	if err := ds.redisClient.HSet(context.Background(), dsarRequestsKey, dsar.ID, data).Err(); err != nil {
+		log.Warn().Err(err).Str("dsar_id", dsar.ID).Msg("Failed to save data-subject request to Redis, kept in memory only")
+	}
+}
+
+// saveExport keeps a compiled export until the request's deadline has long passed.
+// Callers hold ds.mu.
+func (ds *DSARService) saveExport(ctx context.Context, id string, export []byte) {
+	ds.exports[id] = export
+	if ds.redisClient == nil {
+		return
+	}
+	ttl := time.Duration(2*ds.cfg.DeadlineDays) * 24 * time.Hour
+	if err := ds.redisClient.Set(ctx, dsarExportKeyPrefix+id, export, ttl).Err(); err != nil {
+		log.Warn().Err(err).Str("dsar_id", id).Msg("Failed to save export to Redis, kept in memory only")
+	}
+}
+
+// loadRequests reads the requests saved in Redis
+func (ds *DSARService) loadRequests(ctx context.Context) {
+	if ds.redisClient == nil {
+		return
+	}
+	saved, err := ds.redisClient.HGetAll(ctx, dsarRequestsKey).Result()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load data-subject requests from Redis")
+		return
+	}
+	for id, data := range saved {
+		var dsar model.DSAR
+		if err := json.Unmarshal([]byte(data), &dsar); err != nil {
+			log.Warn().Err(err).Str("dsar_id", id).Msg("Skipping unreadable data-subject request")
+			continue
+		}
+		ds.requests[id] = &dsar
+	}
+	if len(ds.requests) > 0 {
+		log.Info().Int("requests", len(ds.requests)).Msg("Data-subject requests loaded")
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// QueueFullError is returned when the execution pipeline is saturated and a task is
+// refused. Clients should retry after RetryAfter.
+type QueueFullError struct {
+	Depth      int
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("execution queue is full (%d tasks in flight), retry after %s", e.Depth, e.RetryAfter)
+}
+
+// ErrDraining is returned while the instance is shutting down: it finishes the tasks
+// it has but takes no more. Clients should retry, and reach another instance.
+var ErrDraining = errors.New("server is shutting down")
+
+// ExecutionQueue tracks tasks in flight and applies back-pressure. Admission stops at the
+// high watermark and resumes only once the depth has drained to the low watermark, so
+// clients are not let back in one task at a time while the pipeline is still saturated.
+type ExecutionQueue struct {
+	high       int
+	low        int
+	retryAfter time.Duration
+	depth      int
+	saturated  bool
+	draining   bool
+	rejected   int64
+	mu         sync.Mutex
+}
+
+// NewExecutionQueue creates a new execution queue
+func NewExecutionQueue(cfg *config.QueueConfig) *ExecutionQueue {
+	high := cfg.HighWatermark
+	if high <= 0 {
+		high = 500
+	}
+	low := cfg.LowWatermark
+	if low <= 0 || low > high {
+		low = high
+	}
+	retryAfter := time.Duration(cfg.RetryAfter) * time.Second
+	if retryAfter <= 0 {
+		retryAfter = 30 * time.Second
+	}
+
+	return &ExecutionQueue{
+		high:       high,
+		low:        low,
+		retryAfter: retryAfter,
+	}
+}
+
+// Admit reserves a slot for one task, or returns a *QueueFullError, or ErrDraining
+// once Drain was called. Every admitted task must call Done exactly once.
+func (q *ExecutionQueue) Admit() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.draining {
+		q.rejected++
+		return ErrDraining
+	}
+
+	if q.saturated && q.depth <= q.low {
+		q.saturated = false
+		log.Info().Int("depth", q.depth).Msg("Execution queue drained, accepting tasks")
+	}
+	if !q.saturated && q.depth >= q.high {
+		q.saturated = true
+		log.Warn().Int("depth", q.depth).Msg("Execution queue saturated, refusing tasks")
+	}
+
+	if q.saturated {
+		q.rejected++
+		return &QueueFullError{Depth: q.depth, RetryAfter: q.retryAfter}
+	}
+
+	q.depth++
+	return nil
+}
+
+// Done releases the slot of a finished task
+func (q *ExecutionQueue) Done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.depth > 0 {
+		q.depth--
+	}
+}
+
+// Drain stops admitting tasks for good; the tasks in flight carry on
+func (q *ExecutionQueue) Drain() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.draining {
+		q.draining = true
+		log.Info().Int("depth", q.depth).Msg("Execution queue draining, refusing tasks")
+	}
+}
+
+// Draining reports whether Drain was called
+func (q *ExecutionQueue) Draining() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.draining
+}
+
+// Stats returns the current queue depth and thresholds
+func (q *ExecutionQueue) Stats() *model.QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return &model.QueueStats{
+		Depth:             q.depth,
+		HighWatermark:     q.high,
+		LowWatermark:      q.low,
+		Saturated:         q.saturated,
+		Draining:          q.draining,
+		Rejected:          q.rejected,
+		RetryAfterSeconds: int(q.retryAfter.Seconds()),
+	}
+}
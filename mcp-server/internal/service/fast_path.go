@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// fastPathSkipped are the check agents a fast-path transfer does not go through
+var fastPathSkipped = []string{string(model.AgentTypeGuardrail), string(model.AgentTypeFraud)}
+
+// FastPath lets tiny transfers skip the guardrail and fraud checks: a transfer of at
+// most the configured amount, to a beneficiary registered long enough to be known,
+// from a profiled device whose risk is low, goes straight to the banking agent. Every
+// transfer that takes it is kept in an audit trail with the checks it skipped.
+// Step-up authentication still applies. How long a beneficiary has been registered
+// is read from the banking integration and the risk from the device profile, never
+// from the request.
+type FastPath struct {
+	cfg           *config.FastPathConfig
+	intents       map[string]bool
+	httpClient    *http.Client
+	bankingAPIKey *secrets.Value
+	records       []model.FastPathRecord // Oldest first
+	mu            sync.Mutex
+}
+
+// NewFastPath creates the fast-path policy
+func NewFastPath(cfg *config.FastPathConfig) *FastPath {
+	intents := make(map[string]bool, len(cfg.Intents))
+	for _, intent := range cfg.Intents {
+		intents[intent] = true
+	}
+	return &FastPath{
+		cfg:           cfg,
+		intents:       intents,
+		httpClient:    &http.Client{Timeout: 2 * time.Second},
+		bankingAPIKey: config.Profile().RotatingSecret("FAST_PATH_BANKING_API_KEY", cfg.BankingAPIKey),
+	}
+}
+
+// Qualifies returns the audit record of a task that may take the fast path, or nil
+// when it must go through the checks. A task missing any of the figures the policy
+// needs, or whose beneficiary the banking integration cannot vouch for, goes through
+// the checks.
+func (fp *FastPath) Qualifies(ctx context.Context, task *model.Task, session *model.Session) *model.FastPathRecord {
+	if !fp.cfg.Enabled || !fp.intents[task.Intent] {
+		return nil
+	}
+	// A demo scenario may force the outcome of the checks, so they must run
+	if demo.ScenarioID(task.Context) != "" {
+		return nil
+	}
+
+	amount := taskAmount(task.Data)
+	if amount <= 0 || amount > fp.cfg.MaxAmount {
+		return nil
+	}
+
+	if session != nil {
+		if suspicious, _ := session.Context["suspicious_pattern"].(bool); suspicious {
+			return nil
+		}
+	}
+
+	// Only the risk the server profiled for the device counts: a risk_score in the
+	// request is the client's own say-so, and an unprofiled device is not known to be
+	// low risk
+	deviceRisk, profiled := task.Context["device_risk"].(float64)
+	if !profiled || deviceRisk > fp.cfg.MaxRiskScore {
+		return nil
+	}
+
+	toAccount, _ := task.Data["to_account"].(string)
+	if toAccount == "" {
+		return nil
+	}
+	beneficiaryAge, err := fp.beneficiaryAge(ctx, task, toAccount)
+	if err != nil {
+		log.Debug().Err(err).Str("task_id", task.TaskID).Msg("Beneficiary age unknown; no fast path")
+		return nil
+	}
+	if beneficiaryAge < float64(fp.cfg.MinBeneficiaryDays) {
+		return nil
+	}
+
+	return &model.FastPathRecord{
+		TaskID:             task.TaskID,
+		UserID:             task.UserID,
+		Intent:             task.Intent,
+		Amount:             amount,
+		BeneficiaryAgeDays: beneficiaryAge,
+		RiskScore:          deviceRisk,
+		DeviceRisk:         &deviceRisk,
+		SkippedChecks:      append([]string(nil), fastPathSkipped...),
+		ToAccount:          toAccount,
+		Sandbox:            task.Sandbox,
+	}
+}
+
+// beneficiaryAge returns the days since the user registered the beneficiary, as the
+// banking integration recorded it
+func (fp *FastPath) beneficiaryAge(ctx context.Context, task *model.Task, accountNumber string) (float64, error) {
+	query := url.Values{"user_id": {task.UserID}, "account_number": {accountNumber}}
+	if task.Channel != "" {
+		query.Set("channel", task.Channel)
+	}
+	if task.Sandbox {
+		query.Set("sandbox", "true")
+	}
+	endpoint := strings.TrimRight(fp.cfg.BankingURL, "/") + "/api/v1/beneficiaries?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", fp.bankingAPIKey.Get())
+
+	resp, err := fp.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("beneficiary lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("beneficiary lookup returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	var beneficiary struct {
+		Status  string    `json:"status"`
+		AddedAt time.Time `json:"added_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&beneficiary); err != nil {
+		return 0, fmt.Errorf("failed to decode beneficiary: %w", err)
+	}
+	if beneficiary.AddedAt.IsZero() {
+		return 0, fmt.Errorf("beneficiary %s has no registration date", accountNumber)
+	}
+	if beneficiary.Status != "" && beneficiary.Status != "ACTIVE" {
+		return 0, fmt.Errorf("beneficiary %s is %s", accountNumber, beneficiary.Status)
+	}
+	return time.Since(beneficiary.AddedAt).Hours() / 24, nil
+}
+
+// Reason describes why a task took the fast path
+func (fp *FastPath) Reason(record *model.FastPathRecord) string {
+	return fmt.Sprintf("Fast path: %.2f to a beneficiary known for %.0f days at risk %.2f; guardrail and fraud checks skipped",
+		record.Amount, record.BeneficiaryAgeDays, record.RiskScore)
+}
+
+// Record adds a fast-path transfer to the audit trail
+func (fp *FastPath) Record(record *model.FastPathRecord, agentID string) {
+	entry := *record
+	entry.AgentID = agentID
+	entry.RecordedAt = time.Now()
+
+	fp.mu.Lock()
+	fp.records = append(fp.records, entry)
+	if keep := fp.cfg.KeepRecords; keep > 0 && len(fp.records) > keep {
+		fp.records = fp.records[len(fp.records)-keep:]
+	}
+	fp.mu.Unlock()
+
+	log.Info().
+		Str("task_id", entry.TaskID).
+		Str("user_id", entry.UserID).
+		Str("intent", entry.Intent).
+		Float64("amount", entry.Amount).
+		Strs("skipped_checks", entry.SkippedChecks).
+		Msg("Transfer took the fast path")
+}
+
+// Audit returns the fast-path audit trail, newest first, optionally for one user
+func (fp *FastPath) Audit(userID string, limit int) []model.FastPathRecord {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	records := []model.FastPathRecord{}
+	for i := len(fp.records) - 1; i >= 0 && (limit <= 0 || len(records) < limit); i-- {
+		if userID == "" || fp.records[i].UserID == userID {
+			records = append(records, fp.records[i])
+		}
+	}
+	return records
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/channel"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// intentFlagsKey is the Redis hash of intent flags, keyed by intent, tenant and channel
+const intentFlagsKey = "intents:flags"
+
+// maxFlagMessageLength bounds the message users are told while an intent is off
+const maxFlagMessageLength = 300
+
+// readIntents only read, so they still run while their flag is read-only
+var readIntents = map[string]bool{
+	"CHECK_BALANCE": true, "GET_STATEMENT": true, "VIEW_ACCOUNT": true, "LIST_BENEFICIARIES": true,
+	"CREDIT_SCORE": true, "RISK_ASSESSMENT": true, "GET_INSIGHTS": true, "BUDGET_STATUS": true,
+	"WHY_REJECTED": true,
+}
+
+// ErrIntentFlagNotFound is returned for an intent flag that is not set
+var ErrIntentFlagNotFound = errors.New("intent flag not found")
+
+// IntentDisabledError refuses a task whose intent is switched off for its tenant and
+// channel
+type IntentDisabledError struct {
+	Flag model.IntentFlag
+}
+
+func (e *IntentDisabledError) Error() string {
+	if e.Flag.Mode == model.IntentReadOnly {
+		return fmt.Sprintf("intent %s is read-only", e.Flag.Intent)
+	}
+	return fmt.Sprintf("intent %s is disabled", e.Flag.Intent)
+}
+
+// UserMessage is what the user is told: the flag's message, or a general one
+func (e *IntentDisabledError) UserMessage() string {
+	if e.Flag.Message != "" {
+		return e.Flag.Message
+	}
+	return "This service is switched off for now. Please try again later."
+}
+
+// IntentFlags are the kill switches of intents: an operator turns an intent off, or
+// read-only, at runtime, for everyone or for a tenant or channel, and tasks for it are
+// refused when submitted and again before they run. Flags are kept in Redis, when
+// available, so they survive a restart.
+type IntentFlags struct {
+	redisClient *redis.Client
+	flags       map[string]*model.IntentFlag // Keyed by flagKey
+	mu          sync.RWMutex
+}
+
+// NewIntentFlags creates the intent flags and loads those saved in Redis
+func NewIntentFlags(redisClient *redis.Client) *IntentFlags {
+	f := &IntentFlags{
+		redisClient: redisClient,
+		flags:       make(map[string]*model.IntentFlag),
+	}
+	f.load(context.Background())
+	return f
+}
+
+// Set sets the flag of an intent for the request's tenant and channel. by is the
+// operator setting it.
+func (f *IntentFlags) Set(intent, by string, req *model.IntentFlagRequest) (*model.IntentFlag, error) {
+	intent = strings.ToUpper(strings.TrimSpace(intent))
+	if !intentNameRegex.MatchString(intent) {
+		return nil, fmt.Errorf("intent must be upper case letters, digits and underscores, e.g. TRANSFER_UPI")
+	}
+	switch req.Mode {
+	case model.IntentEnabled, model.IntentDisabled, model.IntentReadOnly:
+	default:
+		return nil, fmt.Errorf("mode must be enabled, disabled or read_only")
+	}
+	flagChannel := ""
+	if req.Channel != "" {
+		c, err := channel.Parse(req.Channel)
+		if err != nil {
+			return nil, err
+		}
+		flagChannel = string(c)
+	}
+	if len(req.Message) > maxFlagMessageLength {
+		return nil, fmt.Errorf("message must be at most %d characters", maxFlagMessageLength)
+	}
+
+	flag := &model.IntentFlag{
+		Intent:    intent,
+		TenantID:  req.TenantID,
+		Channel:   flagChannel,
+		Mode:      req.Mode,
+		Message:   strings.TrimSpace(req.Message),
+		Reason:    req.Reason,
+		UpdatedBy: by,
+		UpdatedAt: time.Now(),
+	}
+
+	key := flagKey(intent, flag.TenantID, flag.Channel)
+	f.mu.Lock()
+	f.flags[key] = flag
+	f.mu.Unlock()
+
+	if data, err := json.Marshal(flag); err == nil && f.redisClient != nil {
+		if err := f.redisClient.HSet(context.Background(), intentFlagsKey, key, data).Err(); err != nil {
+			log.Warn().Err(err).Str("intent", intent).Msg("Failed to save intent flag to Redis, kept in memory only")
+		}
+	}
+
+	log.Warn().
+		Str("intent", intent).
+		Str("tenant_id", flag.TenantID).
+		Str("channel", flag.Channel).
+		Str("mode", string(flag.Mode)).
+		Str("reason", flag.Reason).
+		Str("updated_by", flag.UpdatedBy).
+		Msg("Intent flag set")
+	flagCopy := *flag
+	return &flagCopy, nil
+}
+
+// Delete removes the flag of an intent for a tenant and channel, leaving the intent to
+// any wider flag. by is the operator removing it.
+func (f *IntentFlags) Delete(intent, tenantID, flagChannel, by string) error {
+	intent = strings.ToUpper(intent)
+	if flagChannel != "" {
+		if c, err := channel.Parse(flagChannel); err == nil {
+			flagChannel = string(c)
+		}
+	}
+	key := flagKey(intent, tenantID, flagChannel)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.flags[key]; !ok {
+		return ErrIntentFlagNotFound
+	}
+	delete(f.flags, key)
+
+	if f.redisClient != nil {
+		if err := f.redisClient.HDel(context.Background(), intentFlagsKey, key).Err(); err != nil {
+			log.Warn().Err(err).Str("intent", intent).Msg("Failed to remove intent flag from Redis")
+		}
+	}
+	log.Warn().Str("intent", intent).Str("tenant_id", tenantID).Str("channel", flagChannel).
+		Str("removed_by", by).Msg("Intent flag removed")
+	return nil
+}
+
+// List returns every flag, sorted by intent, tenant and channel
+func (f *IntentFlags) List() []model.IntentFlag {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flags := make([]model.IntentFlag, 0, len(f.flags))
+	for _, flag := range f.flags {
+		flags = append(flags, *flag)
+	}
+	sort.Slice(flags, func(a, b int) bool {
+		if flags[a].Intent != flags[b].Intent {
+			return flags[a].Intent < flags[b].Intent
+		}
+		if flags[a].TenantID != flags[b].TenantID {
+			return flags[a].TenantID < flags[b].TenantID
+		}
+		return flags[a].Channel < flags[b].Channel
+	})
+	return flags
+}
+
+// Lookup returns the flag that applies to an intent for a tenant and channel: one
+// naming both wins over one naming the tenant, which wins over one naming the channel,
+// which wins over one for everyone. Nil when the intent has no flag.
+func (f *IntentFlags) Lookup(intent, tenantID, flagChannel string) *model.IntentFlag {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	candidates := []string{flagKey(intent, "", "")}
+	if flagChannel != "" {
+		candidates = append(candidates, flagKey(intent, "", flagChannel))
+	}
+	if tenantID != "" {
+		candidates = append(candidates, flagKey(intent, tenantID, ""))
+		if flagChannel != "" {
+			candidates = append(candidates, flagKey(intent, tenantID, flagChannel))
+		}
+	}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if flag, ok := f.flags[candidates[i]]; ok {
+			flagCopy := *flag
+			return &flagCopy
+		}
+	}
+	return nil
+}
+
+// Check refuses a task of intent for a tenant and channel while its flag turns it off.
+// A read-only intent still runs sandbox tasks, and every task when it only reads.
+func (f *IntentFlags) Check(intent, tenantID, flagChannel string, sandbox bool) error {
+	flag := f.Lookup(intent, tenantID, flagChannel)
+	if flag == nil {
+		return nil
+	}
+	switch flag.Mode {
+	case model.IntentDisabled:
+		return &IntentDisabledError{Flag: *flag}
+	case model.IntentReadOnly:
+		if !sandbox && !readIntents[intent] {
+			return &IntentDisabledError{Flag: *flag}
+		}
+	}
+	return nil
+}
+
+// load reads the intent flags saved in Redis
+func (f *IntentFlags) load(ctx context.Context) {
+	if f.redisClient == nil {
+		return
+	}
+	saved, err := f.redisClient.HGetAll(ctx, intentFlagsKey).Result()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load intent flags from Redis")
+		return
+	}
+	for key, data := range saved {
+		var flag model.IntentFlag
+		if err := json.Unmarshal([]byte(data), &flag); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Skipping unreadable intent flag")
+			continue
+		}
+		f.flags[key] = &flag
+	}
+	if len(f.flags) > 0 {
+		log.Warn().Int("flags", len(f.flags)).Msg("Intent flags loaded")
+	}
+}
+
+// flagKey keys a flag by intent, tenant and channel
+func flagKey(intent, tenantID, flagChannel string) string {
+	return intent + "|" + tenantID + "|" + flagChannel
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/tz"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// intentHistoryGlobalKey holds all users' history together
+const intentHistoryGlobalKey = "intent_history_all"
+
+// IntentHistories counts the intents each user asks for by hour of day, and the same
+// for all users together, so the agents likely to be needed can be warmed before the
+// request arrives. Counts fade with a half-life and are kept in Redis, when available,
+// so they survive a restart.
+type IntentHistories struct {
+	halfLifeDays float64
+	location     *time.Location
+	redisClient  *redis.Client
+	users        map[string]*model.IntentHistory
+	global       *model.IntentHistory
+	mu           sync.Mutex
+}
+
+// NewIntentHistories creates the intent history store
+func NewIntentHistories(cfg *config.WarmupConfig, redisClient *redis.Client) *IntentHistories {
+	location := time.UTC
+	if cfg.Timezone != "" {
+		if loaded, err := tz.Load(cfg.Timezone); err == nil {
+			location = loaded
+		} else {
+			log.Warn().Err(err).Str("timezone", cfg.Timezone).Msg("Unknown warmup time zone, using UTC")
+		}
+	}
+	return &IntentHistories{
+		halfLifeDays: cfg.HalfLifeDays,
+		location:     location,
+		redisClient:  redisClient,
+		users:        make(map[string]*model.IntentHistory),
+	}
+}
+
+// intentHistoryKey is the Redis key of a user's intent history
+func intentHistoryKey(userID string) string {
+	return fmt.Sprintf("intent_history:%s", userID)
+}
+
+// Hour is the hour of day of t in the configured time zone
+func (ih *IntentHistories) Hour(t time.Time) int {
+	return t.In(ih.location).Hour()
+}
+
+// Record counts an intent asked for by a user at the given time
+func (ih *IntentHistories) Record(ctx context.Context, userID, intent string, at time.Time) {
+	if intent == "" {
+		return
+	}
+	ih.mu.Lock()
+	defer ih.mu.Unlock()
+
+	hour := ih.Hour(at)
+	now := time.Now()
+
+	global := ih.loadGlobal(ctx)
+	ih.add(global, hour, intent, now)
+	ih.save(ctx, intentHistoryGlobalKey, global)
+
+	if userID == "" {
+		return
+	}
+	history := ih.load(ctx, userID)
+	ih.add(history, hour, intent, now)
+	ih.save(ctx, intentHistoryKey(userID), history)
+}
+
+// Predict returns the intents a user asks for at an hour of day, most likely first,
+// as shares of the hour's count
+func (ih *IntentHistories) Predict(ctx context.Context, userID string, hour int) []model.IntentPrediction {
+	ih.mu.Lock()
+	defer ih.mu.Unlock()
+
+	history := ih.load(ctx, userID)
+	return ih.predict(history, hour)
+}
+
+// PredictAll returns the intents all users ask for at an hour of day, most likely first
+func (ih *IntentHistories) PredictAll(ctx context.Context, hour int) []model.IntentPrediction {
+	ih.mu.Lock()
+	defer ih.mu.Unlock()
+
+	return ih.predict(ih.loadGlobal(ctx), hour)
+}
+
+// predict ranks an hour's intents, decayed as of now. Callers hold ih.mu.
+func (ih *IntentHistories) predict(history *model.IntentHistory, hour int) []model.IntentPrediction {
+	current := ih.decayed(history, time.Now())
+
+	total := 0.0
+	for _, count := range current[hour] {
+		total += count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	predictions := make([]model.IntentPrediction, 0, len(current[hour]))
+	for intent, count := range current[hour] {
+		predictions = append(predictions, model.IntentPrediction{
+			Intent:     intent,
+			Share:      math.Round(count/total*1000) / 1000,
+			AgentTypes: intentAgentTypes(intent),
+		})
+	}
+	sort.Slice(predictions, func(a, b int) bool {
+		if predictions[a].Share != predictions[b].Share {
+			return predictions[a].Share > predictions[b].Share
+		}
+		return predictions[a].Intent < predictions[b].Intent
+	})
+	return predictions
+}
+
+// add decays a history to now and counts one intent. Callers hold ih.mu.
+func (ih *IntentHistories) add(history *model.IntentHistory, hour int, intent string, now time.Time) {
+	history.Hours = ih.decayed(history, now)
+	if history.Hours[hour] == nil {
+		history.Hours[hour] = make(map[string]float64)
+	}
+	history.Hours[hour][intent]++
+	history.UpdatedAt = now
+}
+
+// decayed returns a history's counts faded by the time since it was updated, leaving
+// the history as it is. Counts that fade below a hundredth are dropped.
+func (ih *IntentHistories) decayed(history *model.IntentHistory, now time.Time) map[int]map[string]float64 {
+	factor := 1.0
+	if ih.halfLifeDays > 0 && !history.UpdatedAt.IsZero() {
+		if idleDays := now.Sub(history.UpdatedAt).Hours() / 24; idleDays > 0 {
+			factor = math.Pow(0.5, idleDays/ih.halfLifeDays)
+		}
+	}
+
+	hours := make(map[int]map[string]float64, len(history.Hours))
+	for hour, intents := range history.Hours {
+		for intent, count := range intents {
+			if count *= factor; count < 0.01 {
+				continue
+			}
+			if hours[hour] == nil {
+				hours[hour] = make(map[string]float64)
+			}
+			hours[hour][intent] = count
+		}
+	}
+	return hours
+}
+
+// load returns a user's history from memory, or from Redis when this instance has not
+// seen the user, or an empty one. Callers hold ih.mu.
+func (ih *IntentHistories) load(ctx context.Context, userID string) *model.IntentHistory {
+	if history, ok := ih.users[userID]; ok {
+		return history
+	}
+	history := ih.read(ctx, intentHistoryKey(userID))
+	history.UserID = userID
+	ih.users[userID] = history
+	return history
+}
+
+// loadGlobal returns all users' history. Callers hold ih.mu.
+func (ih *IntentHistories) loadGlobal(ctx context.Context) *model.IntentHistory {
+	if ih.global == nil {
+		ih.global = ih.read(ctx, intentHistoryGlobalKey)
+	}
+	return ih.global
+}
+
+// read loads a history from Redis, or returns an empty one. Callers hold ih.mu.
+func (ih *IntentHistories) read(ctx context.Context, key string) *model.IntentHistory {
+	history := &model.IntentHistory{Hours: make(map[int]map[string]float64)}
+	if ih.redisClient == nil {
+		return history
+	}
+
+	data, err := ih.redisClient.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to read intent history from Redis")
+		}
+		return history
+	}
+	if err := json.Unmarshal([]byte(data), history); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Skipping unreadable intent history")
+		return &model.IntentHistory{Hours: make(map[int]map[string]float64)}
+	}
+	if history.Hours == nil {
+		history.Hours = make(map[int]map[string]float64)
+	}
+	return history
+}
+
+// save writes a history to Redis. Callers hold ih.mu.
+func (ih *IntentHistories) save(ctx context.Context, key string, history *model.IntentHistory) {
+	if ih.redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return
+	}
+	if err := ih.redisClient.Set(ctx, key, data, 0).Err(); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to save intent history to Redis")
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// customIntentsKey is the Redis hash of custom intents, keyed by tenant and intent
+const customIntentsKey = "intents:custom"
+
+// Custom intent limits
+const (
+	maxIntentPatterns = 20
+	maxIntentEntities = 20
+	maxPatternLength  = 200
+)
+
+// intentNameRegex is the form of an intent name, e.g. OPEN_RD
+var intentNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,47}$`)
+
+// builtinIntents are the intents the platform handles itself; a custom intent may not
+// take their names
+var builtinIntents = map[string]bool{
+	"TRANSFER_NEFT": true, "TRANSFER_RTGS": true, "TRANSFER_IMPS": true, "TRANSFER_UPI": true,
+	"CHECK_BALANCE": true, "GET_STATEMENT": true, "VIEW_ACCOUNT": true,
+	"ADD_BENEFICIARY": true, "LIST_BENEFICIARIES": true, "MANAGE_BENEFICIARY": true,
+	"REQUEST_MONEY": true, "APPLY_LOAN": true, "LOAN_APPROVAL": true,
+	"CREDIT_SCORE": true, "RISK_ASSESSMENT": true, "GET_INSIGHTS": true,
+	"SET_PREFERENCE": true, "WHY_REJECTED": true, "RETRY_LAST": true, "SHARE_RECEIPT": true, "EXPORT_TRANSACTIONS": true,
+	"SET_BUDGET": true, "BUDGET_STATUS": true,
+	"UNKNOWN": true,
+}
+
+// ErrIntentNotFound is returned for a custom intent that is not registered
+var ErrIntentNotFound = errors.New("custom intent not found")
+
+// IntentRegistry keeps the intents tenants have added without changing the parser.
+// A tenant's own definition of an intent takes precedence over one shared by every
+// tenant. Definitions are kept in Redis, when available, so they survive a restart.
+type IntentRegistry struct {
+	redisClient *redis.Client
+	intents     map[string]*model.IntentDefinition // Keyed by intentKey
+	mu          sync.RWMutex
+}
+
+// NewIntentRegistry creates an intent registry and loads the intents saved in Redis
+func NewIntentRegistry(redisClient *redis.Client) *IntentRegistry {
+	ir := &IntentRegistry{
+		redisClient: redisClient,
+		intents:     make(map[string]*model.IntentDefinition),
+	}
+	ir.load(context.Background())
+	return ir
+}
+
+// Register adds a custom intent, or replaces the tenant's definition of it
+func (ir *IntentRegistry) Register(intent string, req *model.IntentDefinitionRequest) (*model.IntentDefinition, error) {
+	intent = strings.ToUpper(strings.TrimSpace(intent))
+	if err := validateIntentDefinition(intent, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	def := &model.IntentDefinition{
+		Intent:      intent,
+		TenantID:    req.TenantID,
+		Description: req.Description,
+		Example:     req.Example,
+		Patterns:    req.Patterns,
+		Entities:    req.Entities,
+		Capability:  req.Capability,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	key := intentKey(req.TenantID, intent)
+	ir.mu.Lock()
+	if existing, ok := ir.intents[key]; ok {
+		def.CreatedAt = existing.CreatedAt
+	}
+	ir.intents[key] = def
+	ir.mu.Unlock()
+
+	if data, err := json.Marshal(def); err == nil && ir.redisClient != nil {
+		if err := ir.redisClient.HSet(context.Background(), customIntentsKey, key, data).Err(); err != nil {
+			log.Warn().Err(err).Str("intent", intent).Msg("Failed to save custom intent to Redis, kept in memory only")
+		}
+	}
+
+	log.Info().Str("intent", intent).Str("tenant_id", req.TenantID).Str("capability", req.Capability).Msg("Custom intent registered")
+	defCopy := *def
+	return &defCopy, nil
+}
+
+// Get returns the definition of an intent a tenant registered, or that every tenant
+// shares when tenantID is empty
+func (ir *IntentRegistry) Get(tenantID, intent string) (*model.IntentDefinition, bool) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	def, ok := ir.intents[intentKey(tenantID, strings.ToUpper(intent))]
+	if !ok {
+		return nil, false
+	}
+	defCopy := *def
+	return &defCopy, true
+}
+
+// Lookup returns the definition that applies to a tenant's task: the tenant's own,
+// else the one every tenant shares
+func (ir *IntentRegistry) Lookup(tenantID, intent string) (*model.IntentDefinition, bool) {
+	if tenantID != "" {
+		if def, ok := ir.Get(tenantID, intent); ok {
+			return def, true
+		}
+	}
+	return ir.Get("", intent)
+}
+
+// List returns the custom intents that apply to a tenant, or every custom intent when
+// tenantID is empty, sorted by intent and tenant
+func (ir *IntentRegistry) List(tenantID string) []model.IntentDefinition {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	defs := make([]model.IntentDefinition, 0, len(ir.intents))
+	for _, def := range ir.intents {
+		if tenantID == "" || def.TenantID == "" || def.TenantID == tenantID {
+			defs = append(defs, *def)
+		}
+	}
+	sort.Slice(defs, func(a, b int) bool {
+		if defs[a].Intent != defs[b].Intent {
+			return defs[a].Intent < defs[b].Intent
+		}
+		return defs[a].TenantID < defs[b].TenantID
+	})
+	return defs
+}
+
+// Delete removes a tenant's definition of an intent
+func (ir *IntentRegistry) Delete(tenantID, intent string) error {
+	key := intentKey(tenantID, strings.ToUpper(intent))
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if _, ok := ir.intents[key]; !ok {
+		return ErrIntentNotFound
+	}
+	delete(ir.intents, key)
+
+	if ir.redisClient != nil {
+		if err := ir.redisClient.HDel(context.Background(), customIntentsKey, key).Err(); err != nil {
+			log.Warn().Err(err).Str("intent", intent).Msg("Failed to remove custom intent from Redis")
+		}
+	}
+	log.Info().Str("intent", intent).Str("tenant_id", tenantID).Msg("Custom intent removed")
+	return nil
+}
+
+// load reads the custom intents saved in Redis
+func (ir *IntentRegistry) load(ctx context.Context) {
+	if ir.redisClient == nil {
+		return
+	}
+	saved, err := ir.redisClient.HGetAll(ctx, customIntentsKey).Result()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load custom intents from Redis")
+		return
+	}
+	for key, data := range saved {
+		var def model.IntentDefinition
+		if err := json.Unmarshal([]byte(data), &def); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Skipping unreadable custom intent")
+			continue
+		}
+		ir.intents[key] = &def
+	}
+	if len(ir.intents) > 0 {
+		log.Info().Int("intents", len(ir.intents)).Msg("Custom intents loaded")
+	}
+}
+
+// validateIntentDefinition checks a definition's name, capability and that every
+// pattern compiles as the AI Skin will use it
+func validateIntentDefinition(intent string, req *model.IntentDefinitionRequest) error {
+	if !intentNameRegex.MatchString(intent) {
+		return fmt.Errorf("intent must be upper case letters, digits and underscores, e.g. OPEN_RD")
+	}
+	if builtinIntents[intent] {
+		return fmt.Errorf("%s is a built-in intent", intent)
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		return fmt.Errorf("description is required")
+	}
+	if strings.TrimSpace(req.Capability) == "" {
+		return fmt.Errorf("capability is required")
+	}
+	if len(req.Patterns) == 0 || len(req.Patterns) > maxIntentPatterns {
+		return fmt.Errorf("patterns must list between 1 and %d patterns", maxIntentPatterns)
+	}
+	for _, pattern := range req.Patterns {
+		if err := validatePattern(pattern); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	if len(req.Entities) > maxIntentEntities {
+		return fmt.Errorf("at most %d entities may be extracted", maxIntentEntities)
+	}
+	seen := make(map[string]bool)
+	for _, entity := range req.Entities {
+		if entity.Name == "" || seen[entity.Name] {
+			return fmt.Errorf("every entity needs a unique name")
+		}
+		seen[entity.Name] = true
+		if err := validatePattern(entity.Pattern); err != nil {
+			return fmt.Errorf("entity %s: %w", entity.Name, err)
+		}
+	}
+	return nil
+}
+
+// validatePattern checks a pattern compiles, case-insensitively as it is matched
+func validatePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" || len(pattern) > maxPatternLength {
+		return fmt.Errorf("must be 1 to %d characters", maxPatternLength)
+	}
+	if _, err := regexp.Compile("(?i)" + pattern); err != nil {
+		return fmt.Errorf("does not compile: %w", err)
+	}
+	return nil
+}
+
+// intentKey keys a definition by tenant and intent
+func intentKey(tenantID, intent string) string {
+	return tenantID + "|" + intent
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Replay rejection reasons
+const (
+	ReplayMissingNonce = "missing_nonce"
+	ReplayStale        = "stale_timestamp"
+	ReplayDuplicate    = "duplicate_nonce"
+)
+
+// ReplayError is returned when a money-moving submission is refused because it could
+// be a replay of an earlier one
+type ReplayError struct {
+	Reason string
+	Detail string
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("submission refused (%s): %s", e.Reason, e.Detail)
+}
+
+// NonceStore rejects money-moving submissions that are stale or reuse a nonce. Each
+// submission carries a client nonce and the time it was made; the time must be within
+// the allowed skew of the server clock and the nonce must not have been seen from the
+// same user within the window. Nonces are kept in Redis so every server instance sees
+// them, and in memory for when Redis is down.
+type NonceStore struct {
+	enabled     bool
+	maxSkew     time.Duration
+	redisClient *redis.Client
+	seen        map[string]time.Time // Key -> expiry
+	mu          sync.Mutex
+}
+
+// NewNonceStore creates a nonce store from configuration
+func NewNonceStore(cfg *config.ReplayConfig, redisClient *redis.Client) *NonceStore {
+	return &NonceStore{
+		enabled:     cfg.Enabled,
+		maxSkew:     time.Duration(cfg.MaxSkewSeconds) * time.Second,
+		redisClient: redisClient,
+		seen:        make(map[string]time.Time),
+	}
+}
+
+// Check validates a submission's nonce and timestamp and records the nonce, returning a
+// *ReplayError when it is refused. A nonce is remembered for twice the allowed skew,
+// which covers every timestamp that could still be accepted with it.
+func (ns *NonceStore) Check(ctx context.Context, userID, nonce string, timestamp *time.Time) error {
+	if !ns.enabled {
+		return nil
+	}
+	if nonce == "" || timestamp == nil {
+		return &ReplayError{Reason: ReplayMissingNonce, Detail: "money-moving tasks need a nonce and a timestamp"}
+	}
+	if len(nonce) < 16 || len(nonce) > 128 {
+		return &ReplayError{Reason: ReplayMissingNonce, Detail: "nonce must be 16 to 128 characters"}
+	}
+
+	skew := time.Since(*timestamp)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > ns.maxSkew {
+		return &ReplayError{Reason: ReplayStale, Detail: fmt.Sprintf("timestamp is %s from server time, more than the allowed %s", skew.Round(time.Second), ns.maxSkew)}
+	}
+
+	key := fmt.Sprintf("nonce:%s:%s", userID, nonce)
+	ttl := 2 * ns.maxSkew
+
+	// Nonces are also kept locally, so those accepted while Redis was down are still
+	// refused by this instance once it is back
+	fresh := ns.claimMemory(key, ttl)
+	if claimed, err := ns.claimRedis(ctx, key, ttl); err != nil {
+		log.Warn().Err(err).Msg("Failed to record nonce in Redis, using in-memory store")
+	} else if !claimed {
+		fresh = false
+	}
+	if !fresh {
+		log.Warn().Str("user_id", userID).Str("nonce", nonce).Msg("Replayed task submission refused")
+		return &ReplayError{Reason: ReplayDuplicate, Detail: "this nonce has already been used"}
+	}
+	return nil
+}
+
+// claimRedis records a nonce in Redis, reporting whether it was new
+func (ns *NonceStore) claimRedis(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if ns.redisClient == nil {
+		return false, fmt.Errorf("redis client not provided")
+	}
+	return ns.redisClient.SetNX(ctx, key, 1, ttl).Result()
+}
+
+// claimMemory records a nonce in memory, reporting whether it was new. Expired
+// nonces are dropped as new ones are recorded.
+func (ns *NonceStore) claimMemory(key string, ttl time.Duration) bool {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	now := time.Now()
+	for k, expiry := range ns.seen {
+		if now.After(expiry) {
+			delete(ns.seen, k)
+		}
+	}
+
+	if _, ok := ns.seen[key]; ok {
+		return false
+	}
+	ns.seen[key] = now.Add(ttl)
+	return true
+}
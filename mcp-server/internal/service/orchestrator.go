@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/ids"
 	"github.com/rs/zerolog/log"
 )
 
@@ -16,6 +22,26 @@ type Orchestrator struct {
 	taskManager    *TaskManager
 	agentRegistry  *AgentRegistry
 	contextRouter  *ContextRouter
+	queue          *ExecutionQueue
+	debitLocks     *DebitLocks
+	diagnostics    *AgentDiagnosticsTracker
+	sla            *SLATracker
+	nonces         *NonceStore
+	auth           *StepUpAuth
+	devices        *DeviceProfiles
+	holds          *AgentHoldQueue
+	warmer         *AgentWarmer
+	plans          *PlanStore
+	splits         *TransferSplitter
+	flags          *IntentFlags
+	demo           *demo.Library                     // Nil unless DEMO_MODE is on
+	awaiting       map[string]*model.RoutingDecision // Tasks held for step-up authentication or a split
+	awaitingMu     sync.Mutex
+	running        map[string]*runningTask // Executions CancelTask can abort
+	runningMu      sync.Mutex
+	cancelled      map[string]int64 // Cancelled tasks by stage
+	cancelRefused  int64
+	cancelMu       sync.Mutex
 	httpClient     *http.Client
 }
 
@@ -25,12 +51,39 @@ func NewOrchestrator(
 	taskManager *TaskManager,
 	agentRegistry *AgentRegistry,
 	contextRouter *ContextRouter,
+	queue *ExecutionQueue,
+	sla *SLATracker,
+	nonces *NonceStore,
+	auth *StepUpAuth,
+	devices *DeviceProfiles,
+	holds *AgentHoldQueue,
+	warmer *AgentWarmer,
+	plans *PlanStore,
+	splits *TransferSplitter,
+	flags *IntentFlags,
+	scenarios *demo.Library,
 ) *Orchestrator {
 	return &Orchestrator{
 		sessionManager: sessionManager,
 		taskManager:    taskManager,
 		agentRegistry:  agentRegistry,
 		contextRouter:  contextRouter,
+		queue:          queue,
+		debitLocks:     NewDebitLocks(),
+		diagnostics:    NewAgentDiagnosticsTracker(),
+		sla:            sla,
+		nonces:         nonces,
+		auth:           auth,
+		devices:        devices,
+		holds:          holds,
+		warmer:         warmer,
+		plans:          plans,
+		splits:         splits,
+		flags:          flags,
+		demo:           scenarios,
+		awaiting:       make(map[string]*model.RoutingDecision),
+		running:        make(map[string]*runningTask),
+		cancelled:      make(map[string]int64),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -39,23 +92,53 @@ func NewOrchestrator(
 
 // ProcessTask processes a task through the orchestration pipeline
 func (o *Orchestrator) ProcessTask(ctx context.Context, req *model.TaskRequest) (*model.TaskResponse, error) {
-	// Get or create session
+	// Refuse work up front when saturated rather than letting callers time out polling
+	if err := o.queue.Admit(); err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			o.queue.Done()
+		}
+	}()
+
+	// An intent switched off by an operator is refused before anything is recorded
+	tenantID, _ := req.Context["tenant_id"].(string)
+	if err := o.flags.Check(req.Intent, tenantID, req.Channel, req.Sandbox); err != nil {
+		log.Warn().Err(err).Str("user_id", req.UserID).Str("channel", req.Channel).Msg("Task refused by intent flag")
+		return nil, err
+	}
+
+	// A captured transfer must not be resubmitted. Checked after admission so a refused
+	// submission does not use up its nonce.
+	if isDebitIntent(req.Intent) {
+		if err := o.nonces.Check(ctx, req.UserID, req.Nonce, req.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get or create session. A stateless task has none: it is not part of a
+	// conversation and leaves no history behind.
 	var session *model.Session
+	var sessionID string
 	var err error
 
-	if req.SessionID != "" {
-		session, err = o.sessionManager.GetSession(ctx, req.SessionID)
+	if req.Stateless {
+		if req.SessionID != "" {
+			return nil, ErrStatelessSession
+		}
+	} else if req.SessionID != "" {
+		// The caller's session ID is kept, creating the session under it if it is
+		// unknown, so the task lands in the conversation the caller knows
+		sessionReq := &model.SessionRequest{
+			UserID:  req.UserID,
+			Channel: req.Channel,
+			Sandbox: req.Sandbox,
+		}
+		session, _, err = o.sessionManager.BindSession(ctx, req.SessionID, sessionReq)
 		if err != nil {
-			// Create new session if not found
-			sessionReq := &model.SessionRequest{
-				UserID:  req.UserID,
-				Channel: req.Channel,
-				Context: req.Context,
-			}
-			session, err = o.sessionManager.CreateSession(ctx, sessionReq)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create session: %w", err)
-			}
+			return nil, fmt.Errorf("failed to bind session: %w", err)
 		}
 	} else {
 		// Create new session
@@ -63,6 +146,7 @@ func (o *Orchestrator) ProcessTask(ctx context.Context, req *model.TaskRequest)
 			UserID:  req.UserID,
 			Channel: req.Channel,
 			Context: req.Context,
+			Sandbox: req.Sandbox,
 		}
 		session, err = o.sessionManager.CreateSession(ctx, sessionReq)
 		if err != nil {
@@ -70,23 +154,61 @@ func (o *Orchestrator) ProcessTask(ctx context.Context, req *model.TaskRequest)
 		}
 	}
 
+	if session != nil {
+		sessionID = session.SessionID
+
+		// Tasks in a sandbox session are always simulated
+		if session.Sandbox {
+			req.Sandbox = true
+		}
+	}
+
+	// Score the device the task came from for step-up and the risk checks
+	o.profileDevice(ctx, req)
+
 	// Create task
-	task, err := o.taskManager.CreateTask(ctx, req, session.SessionID)
+	task, err := o.taskManager.CreateTask(ctx, req, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	// Add task to session
-	if err := o.sessionManager.AddTaskToSession(ctx, session.SessionID, task.TaskID); err != nil {
-		log.Warn().Err(err).Msg("Failed to add task to session")
+	if session != nil {
+		// Add task to session
+		if err := o.sessionManager.AddTaskToSession(ctx, sessionID, task.TaskID); err != nil {
+			log.Warn().Err(err).Msg("Failed to add task to session")
+		}
+	}
+
+	// Learn when the user asks for what, and warm the agents they are likely to need
+	// next. Server-to-server traffic says nothing about what the user does next.
+	if !task.Sandbox && !task.Stateless {
+		o.warmer.Observe(ctx, task)
 	}
 
 	// Route task to appropriate agent
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusPending, model.TaskStep{Name: "route", Description: "Choosing an agent"})
 	decision, err := o.contextRouter.RouteTask(ctx, task, session)
 	if err != nil {
 		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
 		return nil, fmt.Errorf("failed to route task: %w", err)
 	}
+	o.noteFastPath(ctx, task, decision)
+
+	// Wait for an agent of the required type rather than failing or using a fallback
+	hold, err := o.holdForAgent(ctx, task, decision)
+	if err != nil {
+		return nil, err
+	}
+	if hold != nil {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: sessionID,
+			Status:    string(model.TaskStatusHeld),
+			Message:   hold.Message,
+			CreatedAt: task.CreatedAt,
+			Hold:      hold,
+		}, nil
+	}
 
 	// If no agent found, mark as failed
 	if decision.SelectedAgentID == "" {
@@ -99,28 +221,695 @@ func (o *Orchestrator) ProcessTask(ctx context.Context, req *model.TaskRequest)
 		return nil, fmt.Errorf("failed to update task agent: %w", err)
 	}
 
+	// Transfers larger than their rail allows wait for the user to accept a split
+	proposal, refusal := o.holdForSplit(ctx, task, decision)
+	if refusal != "" {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: sessionID,
+			Status:    string(model.TaskStatusRejected),
+			Message:   refusal,
+			CreatedAt: task.CreatedAt,
+		}, nil
+	}
+	if proposal != nil {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: sessionID,
+			Status:    string(model.TaskStatusAwaitingConfirmation),
+			Message:   proposal.Message,
+			CreatedAt: task.CreatedAt,
+			Split:     proposal,
+		}, nil
+	}
+
+	// Risky transfers wait for the user to pass a step-up challenge
+	challenge, err := o.holdForAuth(ctx, task, decision)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &model.TaskResponse{
+			TaskID:        task.TaskID,
+			SessionID:     sessionID,
+			Status:        string(model.TaskStatusAwaitingAuth),
+			Message:       authPrompt(challenge),
+			CreatedAt:     task.CreatedAt,
+			AuthChallenge: challenge,
+		}, nil
+	}
+
 	// Execute task asynchronously
-	go o.executeTask(context.Background(), task, decision)
+	started = true
+	go o.runTask(task, decision)
+
+	message := "Task submitted successfully"
+	if task.Sandbox {
+		message = "Task submitted successfully (sandbox, no real side effects)"
+	}
 
 	return &model.TaskResponse{
 		TaskID:    task.TaskID,
-		SessionID: session.SessionID,
+		SessionID: sessionID,
 		Status:    string(task.Status),
-		Message:   "Task submitted successfully",
+		Message:   message,
 		CreatedAt: task.CreatedAt,
 	}, nil
 }
 
+// RequeueTask re-routes and re-executes a task that did not complete successfully
+func (o *Orchestrator) RequeueTask(ctx context.Context, taskID string) (*model.TaskResponse, error) {
+	task, err := o.taskManager.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.Status.IsTerminal() || task.Status == model.TaskStatusCompleted {
+		return nil, fmt.Errorf("task %s is %s and cannot be requeued", taskID, task.Status)
+	}
+
+	return o.rerun(ctx, taskID, "requeued")
+}
+
+// ResumeTask re-routes and re-executes from the start a task an earlier instance
+// stopped before it finished
+func (o *Orchestrator) ResumeTask(ctx context.Context, taskID string) (*model.TaskResponse, error) {
+	task, err := o.taskManager.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.Status.IsRunning() {
+		return nil, fmt.Errorf("task %s is %s and cannot be resumed", taskID, task.Status)
+	}
+
+	return o.rerun(ctx, taskID, "resumed")
+}
+
+// rerun resets a task and takes it through routing, holds and execution again; action
+// says why, for the log and the response
+func (o *Orchestrator) rerun(ctx context.Context, taskID, action string) (*model.TaskResponse, error) {
+	if err := o.queue.Admit(); err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			o.queue.Done()
+		}
+	}()
+
+	task, err := o.taskManager.ResetTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset task: %w", err)
+	}
+
+	// The session may have expired since the task was submitted; route without it
+	session, _ := o.sessionManager.GetSession(ctx, task.SessionID)
+
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusPending, model.TaskStep{Name: "route", Description: "Choosing an agent"})
+	decision, err := o.contextRouter.RouteTask(ctx, task, session)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return nil, fmt.Errorf("failed to route task: %w", err)
+	}
+	o.noteFastPath(ctx, task, decision)
+
+	hold, err := o.holdForAgent(ctx, task, decision)
+	if err != nil {
+		return nil, err
+	}
+	if hold != nil {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: task.SessionID,
+			Status:    string(model.TaskStatusHeld),
+			Message:   hold.Message,
+			CreatedAt: task.CreatedAt,
+			Hold:      hold,
+		}, nil
+	}
+
+	if decision.SelectedAgentID == "" {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, "No agent available for routing")
+		return nil, fmt.Errorf("no agent available for task routing")
+	}
+
+	if err := o.taskManager.UpdateTaskAgent(ctx, task.TaskID, decision.SelectedAgentID); err != nil {
+		return nil, fmt.Errorf("failed to update task agent: %w", err)
+	}
+
+	log.Info().Str("task_id", task.TaskID).Str("agent_id", decision.SelectedAgentID).Msg("Task " + action)
+
+	proposal, refusal := o.holdForSplit(ctx, task, decision)
+	if refusal != "" {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: task.SessionID,
+			Status:    string(model.TaskStatusRejected),
+			Message:   refusal,
+			CreatedAt: task.CreatedAt,
+		}, nil
+	}
+	if proposal != nil {
+		return &model.TaskResponse{
+			TaskID:    task.TaskID,
+			SessionID: task.SessionID,
+			Status:    string(model.TaskStatusAwaitingConfirmation),
+			Message:   proposal.Message,
+			CreatedAt: task.CreatedAt,
+			Split:     proposal,
+		}, nil
+	}
+
+	challenge, err := o.holdForAuth(ctx, task, decision)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &model.TaskResponse{
+			TaskID:        task.TaskID,
+			SessionID:     task.SessionID,
+			Status:        string(model.TaskStatusAwaitingAuth),
+			Message:       authPrompt(challenge),
+			CreatedAt:     task.CreatedAt,
+			AuthChallenge: challenge,
+		}, nil
+	}
+
+	started = true
+	go o.runTask(task, decision)
+
+	return &model.TaskResponse{
+		TaskID:    task.TaskID,
+		SessionID: task.SessionID,
+		Status:    string(model.TaskStatusPending),
+		Message:   "Task " + action + " successfully",
+		CreatedAt: task.CreatedAt,
+	}, nil
+}
+
+// noteFastPath records in the task's progress the checks a fast-path transfer skipped
+func (o *Orchestrator) noteFastPath(ctx context.Context, task *model.Task, decision *model.RoutingDecision) {
+	if len(decision.SkippedChecks) == 0 {
+		return
+	}
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusPending, model.TaskStep{
+		Name:        "fast_path",
+		Description: fmt.Sprintf("Small transfer to a known beneficiary; %s checks skipped", strings.ToLower(strings.Join(decision.SkippedChecks, " and "))),
+		AgentType:   decision.AgentType,
+	})
+}
+
+// FastPathAudit returns the transfers that took the fast path, newest first
+func (o *Orchestrator) FastPathAudit(userID string, limit int) []model.FastPathRecord {
+	return o.contextRouter.FastPathAudit(userID, limit)
+}
+
+// holdForAgent parks a task whose agent type has no healthy agent instead of failing
+// it or running it on a fallback agent. Its queue slot is given back while it waits;
+// awaitAgent routes and runs it once an agent is back. Returns nil when the task can
+// be routed now or holding is disabled.
+func (o *Orchestrator) holdForAgent(ctx context.Context, task *model.Task, decision *model.RoutingDecision) (*model.TaskHold, error) {
+	if !o.holds.Enabled() || decision.MissingAgentType == "" {
+		return nil, nil
+	}
+
+	hold, err := o.holds.Hold(task.TaskID, decision.MissingAgentType, o.tenantOf(ctx, task))
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return nil, err
+	}
+
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusHeld, model.TaskStep{
+		Name:        "await_agent",
+		Description: fmt.Sprintf("Waiting for a %s agent to become available", hold.AgentType),
+		AgentType:   hold.AgentType,
+	})
+	o.taskManager.SetHold(ctx, task.TaskID, hold)
+
+	go o.awaitAgent(task, hold.AgentType)
+	return hold, nil
+}
+
+// awaitAgent waits for a held task's agent type to return, then routes and runs the
+// task like a new submission. A task still without an agent at its deadline fails.
+func (o *Orchestrator) awaitAgent(task *model.Task, agentType string) {
+	ctx, done := o.trackTask(task.TaskID)
+	defer done()
+
+	for !o.holds.Expired(task.TaskID) {
+		if !o.holds.Wait(ctx, task.TaskID) {
+			break
+		}
+
+		// The task stays held while the pipeline is saturated or the agent went away
+		// again before it could be routed
+		if err := o.queue.Admit(); err != nil {
+			time.Sleep(o.holds.PollInterval())
+			continue
+		}
+		o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusPending, model.TaskStep{Name: "route", Description: "Choosing an agent"})
+		session, _ := o.sessionManager.GetSession(ctx, task.SessionID)
+		decision, err := o.contextRouter.RouteTask(ctx, task, session)
+		if err != nil || decision.SelectedAgentID == "" || decision.MissingAgentType != "" {
+			o.queue.Done()
+			o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusHeld, model.TaskStep{
+				Name:        "await_agent",
+				Description: fmt.Sprintf("Waiting for a %s agent to become available", agentType),
+				AgentType:   agentType,
+			})
+			time.Sleep(o.holds.PollInterval())
+			continue
+		}
+
+		o.taskManager.SetHold(ctx, task.TaskID, o.holds.Release(task.TaskID, model.HoldReleased))
+		o.noteFastPath(ctx, task, decision)
+		o.dispatchReleased(ctx, task, decision)
+		return
+	}
+
+	// CancelTask has released the hold and finished the task
+	if ctx.Err() != nil {
+		return
+	}
+
+	hold := o.holds.Release(task.TaskID, model.HoldExpired)
+	o.taskManager.SetHold(ctx, task.TaskID, hold)
+	reason := fmt.Sprintf("No %s agent became available in time", agentType)
+	if hold != nil {
+		reason = fmt.Sprintf("No %s agent became available within %s", agentType, describeWait(hold.Deadline.Sub(hold.HeldAt)))
+	}
+	o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, reason)
+}
+
+// dispatchReleased runs a task that has left the hold queue with an admitted queue slot
+func (o *Orchestrator) dispatchReleased(ctx context.Context, task *model.Task, decision *model.RoutingDecision) {
+	if err := o.taskManager.UpdateTaskAgent(ctx, task.TaskID, decision.SelectedAgentID); err != nil {
+		o.queue.Done()
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	log.Info().Str("task_id", task.TaskID).Str("agent_id", decision.SelectedAgentID).Msg("Held task released")
+
+	if proposal, refusal := o.holdForSplit(ctx, task, decision); proposal != nil || refusal != "" {
+		o.queue.Done()
+		return
+	}
+	challenge, err := o.holdForAuth(ctx, task, decision)
+	if err != nil || challenge != nil {
+		o.queue.Done()
+		return
+	}
+	o.runTask(task, decision)
+}
+
+// holdForAuth asks the step-up policy whether a money-moving task needs the user to
+// authenticate first. If it does, the task is parked as AWAITING_AUTH with a challenge
+// and its queue slot is given back until the challenge is answered; a challenge left
+// unanswered rejects the task when it expires. Returns nil when the task can run now.
+func (o *Orchestrator) holdForAuth(ctx context.Context, task *model.Task, decision *model.RoutingDecision) (*model.AuthChallenge, error) {
+	if !o.auth.Enabled() || !isDebitIntent(task.Intent) {
+		return nil, nil
+	}
+
+	deviceID, _ := task.Context["device_id"].(string)
+	claimedTrust, _ := task.Context["device_trust"].(string)
+	profiledTrust, _ := task.Context["device_trust_level"].(string)
+	// The risk is the one the server profiled for the device; a risk_score the client
+	// sends in the context is never trusted to lower the bar.
+	deviceRisk, _ := task.Context["device_risk"].(float64)
+	input := model.AuthInput{
+		RiskScore:   deviceRisk,
+		Amount:      taskAmount(task.Data),
+		DeviceTrust: o.auth.DeviceTrust(task.UserID, deviceID, claimedTrust, profiledTrust),
+		Channel:     task.Channel,
+	}
+
+	authDecision := o.auth.Decide(task.UserID, deviceID, input)
+	if authDecision.Method == model.AuthNone {
+		return nil, nil
+	}
+
+	challenge, err := o.auth.Issue(ctx, task, authDecision, deviceID)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return nil, fmt.Errorf("failed to issue auth challenge: %w", err)
+	}
+
+	o.awaitingMu.Lock()
+	o.awaiting[task.TaskID] = decision
+	o.awaitingMu.Unlock()
+
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusAwaitingAuth, model.TaskStep{
+		Name:        "authenticate",
+		Description: fmt.Sprintf("Waiting for %s verification", authMethodNames[challenge.Method]),
+	})
+	o.taskManager.SetAuthChallenge(ctx, task.TaskID, challenge)
+
+	time.AfterFunc(time.Until(challenge.ExpiresAt), func() {
+		if current, ok := o.auth.Get(challenge.ChallengeID); ok && current.Status != model.ChallengeVerified {
+			o.abandonAuth(context.Background(), current, "Step-up authentication expired")
+		}
+	})
+
+	return challenge, nil
+}
+
+// VerifyAuth answers the step-up challenge a task is waiting on. Once verified the
+// task runs; a challenge out of attempts rejects it. The challenge is returned in its
+// new state along with any error.
+func (o *Orchestrator) VerifyAuth(ctx context.Context, challengeID string, method model.AuthMethod, req *model.AuthVerifyRequest) (*model.AuthChallenge, error) {
+	// Admit before checking the answer so a refused attempt does not use it up
+	if err := o.queue.Admit(); err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			o.queue.Done()
+		}
+	}()
+
+	challenge, err := o.auth.Verify(challengeID, method, req)
+	// An enrollment challenge has no task to release; the factor is registered with it
+	if challenge != nil && challenge.Purpose == model.ChallengeEnrollment {
+		return challenge, err
+	}
+	if challenge != nil && (err == nil || errors.Is(err, ErrAuthFailed)) {
+		o.recordStepUp(ctx, challenge, err == nil)
+	}
+	if err != nil {
+		if challenge != nil {
+			switch challenge.Status {
+			case model.ChallengeFailed:
+				o.abandonAuth(ctx, challenge, "Step-up authentication failed")
+			case model.ChallengeExpired:
+				o.abandonAuth(ctx, challenge, "Step-up authentication expired")
+			default:
+				o.taskManager.SetAuthChallenge(ctx, challenge.TaskID, challenge)
+			}
+		}
+		return challenge, err
+	}
+
+	o.awaitingMu.Lock()
+	decision, ok := o.awaiting[challenge.TaskID]
+	delete(o.awaiting, challenge.TaskID)
+	o.awaitingMu.Unlock()
+	if !ok {
+		return challenge, fmt.Errorf("%w: task %s is no longer waiting", ErrChallengeClosed, challenge.TaskID)
+	}
+
+	o.taskManager.SetAuthChallenge(ctx, challenge.TaskID, challenge)
+	task, err := o.taskManager.GetTask(ctx, challenge.TaskID)
+	if err != nil {
+		return challenge, err
+	}
+
+	log.Info().Str("task_id", task.TaskID).Str("challenge_id", challengeID).Msg("Task authenticated, resuming")
+	started = true
+	go o.runTask(task, decision)
+	return challenge, nil
+}
+
+// abandonAuth rejects a task whose challenge can no longer be passed. Only the first
+// caller for a task acts, so an expiry racing a last failed attempt rejects it once.
+func (o *Orchestrator) abandonAuth(ctx context.Context, challenge *model.AuthChallenge, reason string) {
+	o.awaitingMu.Lock()
+	_, waiting := o.awaiting[challenge.TaskID]
+	delete(o.awaiting, challenge.TaskID)
+	o.awaitingMu.Unlock()
+	if !waiting {
+		return
+	}
+
+	log.Warn().Str("task_id", challenge.TaskID).Str("challenge_id", challenge.ChallengeID).Str("reason", reason).Msg("Task rejected at step-up authentication")
+	o.taskManager.SetAuthChallenge(ctx, challenge.TaskID, challenge)
+	o.taskManager.UpdateTaskStatus(ctx, challenge.TaskID, model.TaskStatusRejected, nil, reason)
+}
+
+// profileDevice records a task's device in its profile and adds the device's trust to
+// the task context: device_trust_level for the step-up policy, and device_risk and
+// device_trust_score for the fraud and guardrail checks. Sandbox tasks neither build
+// nor use device history. Whatever the client sent under those keys is dropped first,
+// so only trust derived from the profile reaches the policy and the agents.
+func (o *Orchestrator) profileDevice(ctx context.Context, req *model.TaskRequest) {
+	delete(req.Context, "device_risk")
+	delete(req.Context, "device_trust_score")
+	delete(req.Context, "device_trust_level")
+
+	deviceID, _ := req.Context["device_id"].(string)
+	if deviceID == "" || req.Sandbox {
+		return
+	}
+	location, _ := req.Context["location"].(string)
+	profile := o.devices.Observe(ctx, req.UserID, deviceID, location)
+	req.Context["device_risk"] = profile.DeviceRisk
+	req.Context["device_trust_score"] = profile.TrustScore
+	req.Context["device_trust_level"] = profile.TrustLevel
+}
+
+// recordStepUp adds a step-up answer to the profile of the device the task came from
+func (o *Orchestrator) recordStepUp(ctx context.Context, challenge *model.AuthChallenge, passed bool) {
+	task, err := o.taskManager.GetTask(ctx, challenge.TaskID)
+	if err != nil || task.Sandbox {
+		return
+	}
+	if deviceID, _ := task.Context["device_id"].(string); deviceID != "" {
+		o.devices.RecordStepUp(ctx, task.UserID, deviceID, passed)
+	}
+}
+
+// AuthChallenge returns a step-up challenge
+func (o *Orchestrator) AuthChallenge(challengeID string) (*model.AuthChallenge, bool) {
+	return o.auth.Get(challengeID)
+}
+
+// authMethodNames are how a prompt names each method
+var authMethodNames = map[model.AuthMethod]string{
+	model.AuthOTP:           "OTP",
+	model.AuthBiometric:     "biometric",
+	model.AuthHardwareToken: "hardware token",
+}
+
+// authPrompt tells the user what a challenge needs of them
+func authPrompt(challenge *model.AuthChallenge) string {
+	switch challenge.Method {
+	case model.AuthOTP:
+		return fmt.Sprintf("Authentication required: enter the %d-digit OTP sent to %s", challenge.CodeLength, challenge.OTPDestination)
+	case model.AuthBiometric:
+		return "Authentication required: confirm with your fingerprint or face on your registered device"
+	case model.AuthHardwareToken:
+		return fmt.Sprintf("Authentication required: enter the %d-digit code from your hardware token", challenge.CodeLength)
+	}
+	return "Authentication required"
+}
+
+// taskAmount reads a task's amount, which clients send as a number or a string
+func taskAmount(data map[string]interface{}) float64 {
+	switch amount := data["amount"].(type) {
+	case float64:
+		return amount
+	case string:
+		parsed, _ := strconv.ParseFloat(amount, 64)
+		return parsed
+	}
+	return 0
+}
+
+// agentSteps names the progress step of each agent type
+var agentSteps = map[model.AgentType]struct{ name, description string }{
+	model.AgentTypeBanking:   {"execute", "Executing the request"},
+	model.AgentTypeFraud:     {"fraud_check", "Checking for fraud"},
+	model.AgentTypeGuardrail: {"guardrail_check", "Checking limits and rules"},
+	model.AgentTypeClearance: {"clearance", "Reviewing the application"},
+	model.AgentTypeScoring:   {"scoring", "Calculating the credit score"},
+	model.AgentTypeInsights:  {"insights", "Looking for ways to save"},
+	model.AgentTypePayment:   {"payment", "Processing the payment"},
+}
+
+// agentStep returns the progress step for work done by an agent
+func agentStep(agent *model.Agent) model.TaskStep {
+	step := model.TaskStep{
+		Name:        "execute",
+		Description: fmt.Sprintf("Processing with the %s agent", agent.Name),
+		AgentID:     agent.AgentID,
+		AgentType:   string(agent.Type),
+	}
+	if known, ok := agentSteps[agent.Type]; ok {
+		step.Name, step.Description = known.name, known.description
+	}
+	return step
+}
+
+// HoldStats returns the tasks waiting for agents
+func (o *Orchestrator) HoldStats() *model.HoldQueueStats {
+	return o.holds.Stats()
+}
+
+// QueueStats returns the load on the execution pipeline
+func (o *Orchestrator) QueueStats() *model.QueueStats {
+	stats := o.queue.Stats()
+	stats.Cancellations = o.CancellationStats()
+	stats.Memory = o.taskManager.MemoryStats()
+	return stats
+}
+
+// SLAStats returns end-to-end latency percentiles and SLA breaches per intent
+func (o *Orchestrator) SLAStats() []model.IntentLatencyStats {
+	return o.sla.Stats()
+}
+
+// SLABreaches returns recent tasks that exceeded their SLA, newest first
+func (o *Orchestrator) SLABreaches(intent string, limit int) []model.SLABreach {
+	return o.sla.Breaches(intent, limit)
+}
+
+// AgentDiagnostics returns latency and dependency figures per agent type over recent tasks
+func (o *Orchestrator) AgentDiagnostics() []model.AgentSLOStats {
+	return o.diagnostics.Stats()
+}
+
+// InFlight returns how many tasks were admitted and have not finished: being routed,
+// waiting behind an earlier transfer or with their agent
+func (o *Orchestrator) InFlight() int {
+	return o.queue.Stats().Depth
+}
+
+// Drain stops taking tasks so the instance can shut down
+func (o *Orchestrator) Drain() {
+	o.queue.Drain()
+}
+
+// Draining reports whether the instance is shutting down
+func (o *Orchestrator) Draining() bool {
+	return o.queue.Draining()
+}
+
+// runTask executes an admitted task and releases its queue slot. Debit tasks for the
+// same user run one at a time; read-only tasks run in parallel.
+func (o *Orchestrator) runTask(task *model.Task, decision *model.RoutingDecision) {
+	defer o.queue.Done()
+	ctx, done := o.trackTask(task.TaskID)
+	defer done()
+
+	if isDebitIntent(task.Intent) {
+		if ahead := o.debitLocks.Waiting(task.UserID); ahead > 0 {
+			log.Info().
+				Str("task_id", task.TaskID).
+				Str("user_id", task.UserID).
+				Int("ahead", ahead).
+				Msg("Waiting for earlier debit tasks of this user")
+			o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusWaiting, model.TaskStep{
+				Name:        "wait",
+				Description: "Waiting for an earlier transfer to finish",
+			})
+		}
+		unlock := o.debitLocks.Lock(task.UserID)
+		defer unlock()
+	}
+
+	// A task cancelled while it waited gives its turn straight to the next one
+	if ctx.Err() != nil {
+		return
+	}
+	// An intent switched off while the task waited for the user, an agent or its turn
+	// does not run
+	if err := o.flags.Check(task.Intent, o.tenantOf(ctx, task), task.Channel, task.Sandbox); err != nil {
+		var disabled *IntentDisabledError
+		errors.As(err, &disabled)
+		log.Warn().Err(err).Str("task_id", task.TaskID).Msg("Task rejected by intent flag before running")
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusRejected, nil, disabled.UserMessage())
+		return
+	}
+	o.executeTask(ctx, task, decision)
+}
+
 // executeTask executes the task by calling the appropriate agent
 func (o *Orchestrator) executeTask(ctx context.Context, task *model.Task, decision *model.RoutingDecision) {
+	if proposal, ok := o.confirmedSplit(task); ok {
+		o.executeSplit(ctx, task, decision, proposal)
+		return
+	}
+	if decision.PlanID != "" {
+		o.executePlan(ctx, task, decision)
+		return
+	}
+
 	agent, err := o.agentRegistry.GetAgent(ctx, decision.SelectedAgentID)
 	if err != nil {
 		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, fmt.Sprintf("Agent not found: %s", err.Error()))
 		return
 	}
 
-	// Prepare agent request payload
-	agentRequest := map[string]interface{}{
+	step := agentStep(agent)
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, step)
+
+	// Call agent endpoint
+	calledAt := time.Now()
+	done := o.agentRegistry.BeginTask(o.tenantOf(ctx, task), agent)
+	result, riskScore, explanation, diagnostics, err := o.callAgent(ctx, agent, agentRequest(task, agent))
+	done()
+	agentDone := time.Now()
+	if ctx.Err() != nil {
+		log.Info().Str("task_id", task.TaskID).Str("agent_type", string(agent.Type)).Msg("Task cancelled while its agent worked on it")
+		return
+	}
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	o.diagnostics.Record(agent.Type, diagnostics)
+	log.Info().
+		Str("task_id", task.TaskID).
+		Str("intent", task.Intent).
+		Str("agent_type", string(agent.Type)).
+		Float64("processing_ms", diagnostics.ProcessingMs).
+		Int("downstream_calls_attempted", diagnostics.CallsAttempted).
+		Int("downstream_calls_succeeded", diagnostics.CallsSucceeded).
+		Bool("fallback_used", diagnostics.FallbackUsed).
+		Strs("fallbacks", diagnostics.Fallbacks).
+		Msg("Agent diagnostics")
+
+	// A result that does not match its intent's schema never completes the task
+	schema, err := conformResult(task.Intent, agent.Type, result)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	// Label simulated results so clients never mistake them for real operations
+	if task.Sandbox {
+		if result == nil {
+			result = make(map[string]interface{})
+		}
+		result["simulated"] = true
+		explanation = "[SIMULATED] " + explanation
+	}
+
+	sla := o.sla.Observe(task, string(agent.Type), o.routedAt(ctx, task), calledAt, agentDone, diagnostics)
+
+	// Update task with result
+	if err := o.taskManager.UpdateTaskResult(ctx, task.TaskID, result, schema, riskScore, explanation, diagnostics, sla); err != nil {
+		log.Error().Err(err).Str("task_id", task.TaskID).Msg("Failed to update task result")
+	}
+	o.recordSent(task, schema)
+}
+
+// recordSent counts a transfer the banking agent carried out towards the user's daily
+// limit for its rail. Sandbox transfers do not count.
+func (o *Orchestrator) recordSent(task *model.Task, schema string) {
+	if schema == model.ResultSchemaTransfer && !task.Sandbox {
+		o.splits.Record(task.UserID, task.Intent, taskAmount(task.Data), time.Now())
+	}
+}
+
+// agentRequest prepares the payload an agent is called with for a task
+func agentRequest(task *model.Task, agent *model.Agent) map[string]interface{} {
+	request := map[string]interface{}{
 		"agent_id": agent.AgentID,
 		"task":     task.Intent,
 		"input_context": map[string]interface{}{
@@ -130,42 +919,122 @@ func (o *Orchestrator) executeTask(ctx context.Context, task *model.Task, decisi
 			"intent":     task.Intent,
 			"data":       task.Data,
 			"context":    task.Context,
+			"sandbox":    task.Sandbox,
 		},
 		"session_id": task.SessionID,
 	}
 
-	// Call agent endpoint
-	result, riskScore, explanation, err := o.callAgent(ctx, agent, agentRequest)
+	// Risk checks read the device's risk beside the request, not in its context
+	if deviceRisk, ok := task.Context["device_risk"].(float64); ok {
+		request["input_context"].(map[string]interface{})["device_risk"] = deviceRisk
+	}
+	return request
+}
+
+// tenantOf returns the tenant a task was submitted for, from the task's context or
+// else its session's; empty for a task of no tenant
+func (o *Orchestrator) tenantOf(ctx context.Context, task *model.Task) string {
+	if tenantID, ok := task.Context["tenant_id"].(string); ok && tenantID != "" {
+		return tenantID
+	}
+	if task.SessionID == "" {
+		return ""
+	}
+	session, err := o.sessionManager.GetSession(ctx, task.SessionID)
 	if err != nil {
-		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
-		return
+		return ""
 	}
+	tenantID, _ := session.Context["tenant_id"].(string)
+	return tenantID
+}
 
-	// Update task with result
-	if err := o.taskManager.UpdateTaskResult(ctx, task.TaskID, result, riskScore, explanation); err != nil {
-		log.Error().Err(err).Str("task_id", task.TaskID).Msg("Failed to update task result")
+// routedAt returns when routing of the task's current run began: the start of its
+// first progress step, which a requeue resets. Time spent waiting for the user to
+// authenticate is not the pipeline's, so a task held for step-up authentication is
+// measured from when it was released.
+func (o *Orchestrator) routedAt(ctx context.Context, task *model.Task) time.Time {
+	if current, err := o.taskManager.GetTask(ctx, task.TaskID); err == nil {
+		progress := o.taskManager.Progress(current)
+		for _, step := range progress {
+			if step.Name == "authenticate" && step.FinishedAt != nil {
+				return *step.FinishedAt
+			}
+		}
+		if len(progress) > 0 {
+			return progress[0].StartedAt
+		}
 	}
+	return task.CreatedAt
 }
 
-// callAgent calls the agent's REST endpoint
-func (o *Orchestrator) callAgent(ctx context.Context, agent *model.Agent, request map[string]interface{}) (map[string]interface{}, float64, string, error) {
+// callAgent calls the agent's REST endpoint and returns its result with the agent's
+// diagnostics
+func (o *Orchestrator) callAgent(ctx context.Context, agent *model.Agent, request map[string]interface{}) (map[string]interface{}, float64, string, *model.AgentDiagnostics, error) {
 	// For now, use mock responses based on agent type
 	// In production, this would make actual HTTP/gRPC calls
+	start := time.Now()
+
+	// A task playing a demo scenario gets the outcome the scenario forces on the agent
+	if result, riskScore, explanation, ok := o.demoOutcome(agent, request); ok {
+		diagnostics := &model.AgentDiagnostics{ProcessingMs: millis(time.Since(start))}
+		return result, riskScore, explanation, diagnostics, nil
+	}
 
+	var result map[string]interface{}
+	var riskScore float64
+	var explanation string
+	var err error
 	switch agent.Type {
 	case model.AgentTypeBanking:
-		return o.mockBankingAgent(request)
+		result, riskScore, explanation, err = o.mockBankingAgent(request)
 	case model.AgentTypeFraud:
-		return o.mockFraudAgent(request)
+		result, riskScore, explanation, err = o.mockFraudAgent(request)
 	case model.AgentTypeGuardrail:
-		return o.mockGuardrailAgent(request)
+		result, riskScore, explanation, err = o.mockGuardrailAgent(request)
 	case model.AgentTypeClearance:
-		return o.mockClearanceAgent(request)
+		result, riskScore, explanation, err = o.mockClearanceAgent(request)
 	case model.AgentTypeScoring:
-		return o.mockScoringAgent(request)
+		result, riskScore, explanation, err = o.mockScoringAgent(request)
 	default:
-		return map[string]interface{}{"status": "processed"}, 0.1, "Task processed by default agent", nil
+		result, riskScore, explanation = map[string]interface{}{"status": "processed"}, 0.1, "Task processed by default agent"
 	}
+
+	// A real agent returns its own diagnostics; a mock stands in for the whole agent
+	diagnostics := &model.AgentDiagnostics{
+		ProcessingMs: millis(time.Since(start)),
+		FallbackUsed: true,
+		Fallbacks:    []string{"agent:mock"},
+	}
+	return result, riskScore, explanation, diagnostics, err
+}
+
+// demoOutcome returns the outcome a demo scenario forces on an agent type, in the
+// shape of an agent's result: its status is in the result, beside the scenario's ID
+func (o *Orchestrator) demoOutcome(agent *model.Agent, request map[string]interface{}) (map[string]interface{}, float64, string, bool) {
+	if o.demo == nil {
+		return nil, 0, "", false
+	}
+	inputCtx, _ := request["input_context"].(map[string]interface{})
+	taskContext, _ := inputCtx["context"].(map[string]interface{})
+	scenarioID := demo.ScenarioID(taskContext)
+	outcome, ok := o.demo.Outcome(scenarioID, string(agent.Type))
+	if !ok {
+		return nil, 0, "", false
+	}
+
+	log.Info().
+		Str("scenario", scenarioID).
+		Str("agent_type", string(agent.Type)).
+		Str("status", outcome.Status).
+		Msg("Demo scenario forced the agent's outcome")
+
+	result := make(map[string]interface{}, len(outcome.Result)+2)
+	for k, v := range outcome.Result {
+		result[k] = v
+	}
+	result["status"] = outcome.Status
+	result[demo.ContextKey] = scenarioID
+	return result, outcome.RiskScore, outcome.Explanation, true
 }
 
 // Mock agent implementations (to be replaced with actual HTTP calls)
@@ -182,6 +1051,40 @@ func (o *Orchestrator) mockBankingAgent(request map[string]interface{}) (map[str
 		result["balance"] = 50000.0
 		result["currency"] = "INR"
 	}
+	if strings.HasPrefix(intent, "TRANSFER_") {
+		data, _ := inputCtx["data"].(map[string]interface{})
+		amount := taskAmount(data)
+		toAccount, _ := data["to_account"].(string)
+		if amount <= 0 || toAccount == "" {
+			return map[string]interface{}{
+				"status": "REJECTED",
+				"reason": "A transfer needs a positive amount and a destination account",
+			}, 0.1, "Transfer rejected: amount or destination account missing.", nil
+		}
+		result["transaction_id"] = ids.Ref(ids.Transaction)
+		result["amount"] = amount
+		result["to_account"] = toAccount
+		result["processed_at"] = time.Now()
+	}
+	if intent == "REVERSE_TRANSFER" {
+		result["reversal_id"] = ids.Ref(ids.Transaction)
+		result["message"] = "Transfer reversed"
+	}
+	if intent == "LIST_BENEFICIARIES" {
+		result["beneficiaries"] = []map[string]interface{}{
+			{"name": "Rahul Mehta", "account_number": "XXXX5678", "ifsc": "BANK0001234"},
+			{"name": "Priya Shah", "account_number": "XXXX9012", "ifsc": "BANK0005678"},
+		}
+		result["count"] = 2
+	}
+	if intent == "GET_STATEMENT" {
+		now := time.Now()
+		result["transactions"] = []map[string]interface{}{
+			{"transaction_id": ids.Ref(ids.Transaction), "type": "DEBIT", "amount": 2500.0, "description": "UPI payment", "date": now.AddDate(0, 0, -1)},
+			{"transaction_id": ids.Ref(ids.Transaction), "type": "CREDIT", "amount": 50000.0, "description": "Salary", "date": now.AddDate(0, 0, -5)},
+		}
+		result["count"] = 2
+	}
 
 	return result, 0.1, "Transaction is within user limits and behavior pattern is normal.", nil
 }
@@ -192,6 +1095,9 @@ func (o *Orchestrator) mockFraudAgent(request map[string]interface{}) (map[strin
 
 	amount, _ := data["amount"].(float64)
 	riskScore := 0.3
+	if deviceRisk, ok := inputCtx["device_risk"].(float64); ok {
+		riskScore += deviceRisk * 0.2
+	}
 
 	if amount > 100000 {
 		riskScore = 0.7
@@ -230,5 +1136,23 @@ func (o *Orchestrator) mockScoringAgent(request map[string]interface{}) (map[str
 		"credit_score":   750,
 		"risk_category":  "LOW",
 		"recommendation": "APPROVE",
+		"tips": []interface{}{
+			map[string]interface{}{
+				"action":           "REDUCE_UTILIZATION",
+				"factor":           "utilization",
+				"title":            "Use less of your credit limit",
+				"detail":           "Paying your card balance down below 30% of the limit shows lenders you are not stretched.",
+				"potential_points": 20.0,
+				"priority":         1,
+			},
+			map[string]interface{}{
+				"action":           "BUILD_CREDIT_HISTORY",
+				"factor":           "loan_history",
+				"title":            "Build a repayment history",
+				"detail":           "A small loan or credit line repaid on time gives lenders a track record to score.",
+				"potential_points": 20.0,
+				"priority":         2,
+			},
+		},
 	}, 0.1, "Credit score calculated based on user profile and history.", nil
 }
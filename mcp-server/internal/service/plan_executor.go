@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/rs/zerolog/log"
+)
+
+// planRetryDelay is the pause before a failed step's next attempt, growing with each
+const planRetryDelay = 200 * time.Millisecond
+
+// planStepOutcome is what one step of a plan returned
+type planStepOutcome struct {
+	step        model.PlanStep
+	attempts    int
+	agent       *model.Agent
+	result      map[string]interface{}
+	riskScore   float64
+	explanation string
+	diagnostics *model.AgentDiagnostics
+	err         error
+	startedAt   time.Time
+	finishedAt  time.Time
+}
+
+// executePlan executes a task through the orchestration plan its routing rule names.
+// Stages run in order and the steps of a stage in parallel. A step that rejects the
+// request ends the plan with that step's result. A step that fails is retried as often
+// as it asks, then its failure policy applies: SKIP leaves it out, ABORT and RETRY fail
+// the task, and COMPENSATE fails the task and undoes the steps that finished before it.
+// The task's result is that of the last step to succeed, and the executed instance is
+// kept on the task and in the plan store.
+func (o *Orchestrator) executePlan(ctx context.Context, task *model.Task, decision *model.RoutingDecision) {
+	plan, err := o.plans.Get(decision.PlanID)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, fmt.Sprintf("Orchestration plan %s not found", decision.PlanID))
+		return
+	}
+
+	run := &model.PlanRun{
+		RunID:       ids.New(ids.PlanRun),
+		PlanID:      plan.PlanID,
+		PlanVersion: plan.Version,
+		TaskID:      task.TaskID,
+		Intent:      task.Intent,
+		Status:      model.PlanRunRunning,
+		StartedAt:   time.Now(),
+	}
+	index := make(map[string]int, len(plan.Steps))
+	for i, step := range plan.Steps {
+		index[step.Name] = i
+		run.Steps = append(run.Steps, model.PlanStepRun{
+			Name:      step.Name,
+			AgentType: step.AgentType,
+			Stage:     step.Stage,
+			Status:    model.PlanStepPending,
+			OnFailure: stepPolicy(plan, step, task.Intent),
+		})
+	}
+	o.taskManager.SetPlanRun(ctx, task.TaskID, run)
+
+	log.Info().
+		Str("task_id", task.TaskID).
+		Str("plan_id", plan.PlanID).
+		Str("run_id", run.RunID).
+		Int("steps", len(plan.Steps)).
+		Msg("Executing orchestration plan")
+
+	var succeeded []*planStepOutcome
+	var rejected *planStepOutcome
+	var failed error
+	compensate := false
+	calledAt := time.Now()
+
+	for _, stage := range planStages(plan) {
+		if ctx.Err() != nil {
+			break
+		}
+		o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, stageStep(stage))
+
+		now := time.Now()
+		for _, step := range stage {
+			stepRun := &run.Steps[index[step.Name]]
+			stepRun.Status = model.PlanStepRunning
+			stepRun.StartedAt = &now
+		}
+		o.taskManager.SetPlanRun(ctx, task.TaskID, run)
+
+		outcomes := make([]*planStepOutcome, len(stage))
+		var wg sync.WaitGroup
+		for i, step := range stage {
+			attempts := stepAttempts(step, run.Steps[index[step.Name]].OnFailure)
+			wg.Add(1)
+			go func(i int, step model.PlanStep) {
+				defer wg.Done()
+				outcomes[i] = o.runPlanStep(ctx, task, step, attempts)
+			}(i, step)
+		}
+		wg.Wait()
+
+		for i, step := range stage {
+			outcome := outcomes[i]
+			stepRun := &run.Steps[index[step.Name]]
+			finished := outcome.finishedAt
+			stepRun.FinishedAt = &finished
+			stepRun.DurationMs = millis(outcome.finishedAt.Sub(outcome.startedAt))
+			stepRun.Attempts = outcome.attempts
+			if outcome.agent != nil {
+				stepRun.AgentID = outcome.agent.AgentID
+			}
+
+			if outcome.err != nil {
+				stepRun.Status = model.PlanStepFailed
+				stepRun.Error = outcome.err.Error()
+				log.Warn().
+					Err(outcome.err).
+					Str("task_id", task.TaskID).
+					Str("plan_id", plan.PlanID).
+					Str("step", step.Name).
+					Int("attempts", outcome.attempts).
+					Str("on_failure", stepRun.OnFailure).
+					Msg("Plan step failed")
+				if stepRun.OnFailure == model.PlanOnFailureSkip {
+					continue
+				}
+				compensate = compensate || stepRun.OnFailure == model.PlanOnFailureCompensate
+				if failed == nil {
+					failed = fmt.Errorf("plan %s step %s failed: %w", plan.PlanID, step.Name, outcome.err)
+				}
+				continue
+			}
+
+			o.diagnostics.Record(outcome.agent.Type, outcome.diagnostics)
+			stepRun.Status = model.PlanStepDone
+			stepRun.RiskScore = outcome.riskScore
+			stepRun.ResultStatus, _ = outcome.result["status"].(string)
+			if stepRun.ResultStatus == "REJECTED" {
+				if rejected == nil {
+					rejected = outcome
+				}
+				continue
+			}
+			succeeded = append(succeeded, outcome)
+		}
+
+		if failed != nil || rejected != nil {
+			break
+		}
+	}
+	agentDone := time.Now()
+	cancelled := ctx.Err() != nil
+
+	// Steps of stages that never ran are skipped
+	for i := range run.Steps {
+		if run.Steps[i].Status == model.PlanStepPending {
+			run.Steps[i].Status = model.PlanStepSkipped
+		}
+	}
+
+	if failed != nil && compensate {
+		if undone := o.compensatePlan(ctx, task, run, index, succeeded); len(undone) > 0 {
+			failed = fmt.Errorf("%w; undone: %s", failed, strings.Join(undone, ", "))
+		}
+	}
+
+	switch {
+	case cancelled:
+		run.Status = model.PlanRunCancelled
+	case failed != nil && compensate && compensated(run):
+		run.Status = model.PlanRunCompensated
+	case failed != nil:
+		run.Status = model.PlanRunFailed
+	case rejected != nil:
+		run.Status = model.PlanRunRejected
+	case len(succeeded) == 0:
+		run.Status = model.PlanRunFailed
+		failed = fmt.Errorf("plan %s: every step failed", plan.PlanID)
+	default:
+		run.Status = model.PlanRunCompleted
+	}
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = millis(finishedAt.Sub(run.StartedAt))
+	o.taskManager.SetPlanRun(ctx, task.TaskID, run)
+	o.plans.RecordRun(run)
+
+	log.Info().
+		Str("task_id", task.TaskID).
+		Str("plan_id", plan.PlanID).
+		Str("run_id", run.RunID).
+		Str("status", run.Status).
+		Float64("duration_ms", run.DurationMs).
+		Msg("Orchestration plan finished")
+
+	// CancelTask has already finished the task
+	if cancelled {
+		return
+	}
+	if failed != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, failed.Error())
+		return
+	}
+
+	// A rejection is the plan's answer; otherwise the last step to succeed gives it,
+	// explained by every step that took part
+	final := rejected
+	explanation := ""
+	if final != nil {
+		explanation = final.explanation
+	} else {
+		final = succeeded[len(succeeded)-1]
+		explanations := make([]string, 0, len(succeeded))
+		for _, outcome := range succeeded {
+			if outcome.explanation != "" {
+				explanations = append(explanations, outcome.explanation)
+			}
+		}
+		explanation = strings.Join(explanations, " ")
+	}
+
+	riskScore := 0.0
+	for _, stepRun := range run.Steps {
+		if stepRun.RiskScore > riskScore {
+			riskScore = stepRun.RiskScore
+		}
+	}
+
+	schema, err := conformResult(task.Intent, final.agent.Type, final.result)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	result := final.result
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	result["plan_id"] = plan.PlanID
+
+	// Label simulated results so clients never mistake them for real operations
+	if task.Sandbox {
+		result["simulated"] = true
+		explanation = "[SIMULATED] " + explanation
+	}
+
+	diagnostics := mergeDiagnostics(run, succeeded, rejected)
+	sla := o.sla.Observe(task, string(final.agent.Type), o.routedAt(ctx, task), calledAt, agentDone, diagnostics)
+
+	if err := o.taskManager.UpdateTaskResult(ctx, task.TaskID, result, schema, riskScore, explanation, diagnostics, sla); err != nil {
+		log.Error().Err(err).Str("task_id", task.TaskID).Msg("Failed to update task result")
+	}
+	o.recordSent(task, schema)
+}
+
+// runPlanStep calls a healthy agent of the step's type, trying up to attempts times.
+// Each attempt goes to the next healthy agent, so a retry does not land on the agent
+// that just failed when there is another.
+func (o *Orchestrator) runPlanStep(ctx context.Context, task *model.Task, step model.PlanStep, attempts int) *planStepOutcome {
+	outcome := &planStepOutcome{step: step, startedAt: time.Now()}
+	defer func() { outcome.finishedAt = time.Now() }()
+	tenantID := o.tenantOf(ctx, task)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return outcome
+			case <-time.After(time.Duration(attempt) * planRetryDelay):
+			}
+		}
+		outcome.attempts++
+
+		agents, err := o.agentRegistry.FindAgentsForTenant(ctx, model.AgentType(step.AgentType), tenantID)
+		if err != nil || len(agents) == 0 {
+			outcome.err = fmt.Errorf("no healthy %s agent available", step.AgentType)
+			continue
+		}
+		outcome.agent = agents[attempt%len(agents)]
+
+		done := o.agentRegistry.BeginTask(tenantID, outcome.agent)
+		outcome.result, outcome.riskScore, outcome.explanation, outcome.diagnostics, outcome.err =
+			o.callAgent(ctx, outcome.agent, agentRequest(task, outcome.agent))
+		done()
+		if outcome.err == nil {
+			return outcome
+		}
+	}
+	return outcome
+}
+
+// compensatePlan undoes the finished steps that say how, latest first, and returns the
+// names of those undone. A compensation that fails is recorded and the rest still run.
+func (o *Orchestrator) compensatePlan(ctx context.Context, task *model.Task, run *model.PlanRun, index map[string]int, finished []*planStepOutcome) []string {
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, model.TaskStep{
+		Name:        "compensate",
+		Description: "Undoing completed steps",
+	})
+
+	var undone []string
+	for i := len(finished) - 1; i >= 0; i-- {
+		outcome := finished[i]
+		c := outcome.step.Compensation
+		if c == nil {
+			continue
+		}
+		stepRun := &run.Steps[index[outcome.step.Name]]
+
+		err := o.compensateStep(ctx, task, outcome, c)
+		if err != nil {
+			stepRun.Compensation = model.PlanStepFailed
+			stepRun.CompensationError = err.Error()
+			log.Error().Err(err).Str("task_id", task.TaskID).Str("plan_id", run.PlanID).Str("step", outcome.step.Name).Msg("Plan step compensation failed")
+			continue
+		}
+		stepRun.Compensation = model.PlanStepDone
+		undone = append(undone, outcome.step.Name)
+		log.Info().Str("task_id", task.TaskID).Str("plan_id", run.PlanID).Str("step", outcome.step.Name).Str("action", c.Action).Msg("Plan step compensated")
+	}
+	return undone
+}
+
+// compensateStep asks an agent of the compensation's type to undo a step, passing the
+// step's result
+func (o *Orchestrator) compensateStep(ctx context.Context, task *model.Task, outcome *planStepOutcome, c *model.PlanCompensation) error {
+	tenantID := o.tenantOf(ctx, task)
+	agents, err := o.agentRegistry.FindAgentsForTenant(ctx, model.AgentType(c.AgentType), tenantID)
+	if err != nil || len(agents) == 0 {
+		return fmt.Errorf("no healthy %s agent available", c.AgentType)
+	}
+
+	request := agentRequest(task, agents[0])
+	request["task"] = c.Action
+	inputCtx := request["input_context"].(map[string]interface{})
+	inputCtx["intent"] = c.Action
+	inputCtx["compensates"] = map[string]interface{}{
+		"step":   outcome.step.Name,
+		"intent": task.Intent,
+		"result": outcome.result,
+	}
+
+	done := o.agentRegistry.BeginTask(tenantID, agents[0])
+	result, _, _, _, err := o.callAgent(ctx, agents[0], request)
+	done()
+	if err != nil {
+		return err
+	}
+	if status, _ := result["status"].(string); status == "REJECTED" || status == "FAILED" {
+		return fmt.Errorf("%s returned %s", c.Action, status)
+	}
+	return nil
+}
+
+// compensated reports whether any step of a run was undone
+func compensated(run *model.PlanRun) bool {
+	for _, step := range run.Steps {
+		if step.Compensation == model.PlanStepDone {
+			return true
+		}
+	}
+	return false
+}
+
+// stageStep returns the progress step for a plan stage; parallel steps are shown as one
+func stageStep(stage []model.PlanStep) model.TaskStep {
+	names := make([]string, 0, len(stage))
+	descriptions := make([]string, 0, len(stage))
+	types := make([]string, 0, len(stage))
+	for _, step := range stage {
+		names = append(names, step.Name)
+		descriptions = append(descriptions, step.Description)
+		types = append(types, step.AgentType)
+	}
+	return model.TaskStep{
+		Name:        strings.Join(names, "+"),
+		Description: strings.Join(descriptions, "; "),
+		AgentType:   strings.Join(types, ","),
+	}
+}
+
+// mergeDiagnostics adds up the diagnostics of the plan's steps that answered. Processing
+// time is the plan's wall time, not the sum, since steps of a stage overlap.
+func mergeDiagnostics(run *model.PlanRun, succeeded []*planStepOutcome, rejected *planStepOutcome) *model.AgentDiagnostics {
+	outcomes := succeeded
+	if rejected != nil {
+		outcomes = append(outcomes, rejected)
+	}
+
+	merged := &model.AgentDiagnostics{ProcessingMs: run.DurationMs}
+	for _, outcome := range outcomes {
+		if outcome.diagnostics == nil {
+			continue
+		}
+		merged.CallsAttempted += outcome.diagnostics.CallsAttempted
+		merged.CallsSucceeded += outcome.diagnostics.CallsSucceeded
+		merged.FallbackUsed = merged.FallbackUsed || outcome.diagnostics.FallbackUsed
+		merged.Fallbacks = append(merged.Fallbacks, outcome.diagnostics.Fallbacks...)
+		merged.Calls = append(merged.Calls, outcome.diagnostics.Calls...)
+	}
+	return merged
+}
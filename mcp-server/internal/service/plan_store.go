@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Plan store errors
+var (
+	ErrPlanNotFound = errors.New("orchestration plan not found")
+	ErrPlanExists   = errors.New("orchestration plan already exists")
+	ErrPlanBuiltin  = errors.New("built-in orchestration plans can be updated but not deleted")
+)
+
+// PlanInUseError is returned when deleting a plan that routing rules still reference
+type PlanInUseError struct {
+	PlanID string
+	Rules  []string
+}
+
+func (e *PlanInUseError) Error() string {
+	return fmt.Sprintf("plan %s is referenced by routing rules %s", e.PlanID, strings.Join(e.Rules, ", "))
+}
+
+const (
+	plansKey           = "orchestration_plans" // Redis hash of plans by ID
+	planRunLimit       = 500                   // Executed plan instances kept per plan
+	planMaxRetries     = 5                     // Most further attempts a step may ask for
+	planDefaultRetries = 2                     // Further attempts of a RETRY step that does not say
+)
+
+// planIDPattern keeps plan IDs usable in URLs and rules
+var planIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// builtinPlans are the plan templates the server starts with
+var builtinPlans = []model.OrchestrationPlan{
+	{
+		PlanID:      "high-value-transfer",
+		Name:        "High-value transfer",
+		Description: "Guardrail and fraud checks side by side, then the banking agent executes the transfer",
+		Intents:     []string{"TRANSFER_NEFT", "TRANSFER_RTGS", "TRANSFER_IMPS", "TRANSFER_UPI"},
+		Steps: []model.PlanStep{
+			{Name: "guardrail_check", AgentType: string(model.AgentTypeGuardrail), Stage: 1},
+			{Name: "fraud_check", AgentType: string(model.AgentTypeFraud), Stage: 1, OnFailure: model.PlanOnFailureRetry, Retries: 1},
+			{Name: "execute", AgentType: string(model.AgentTypeBanking), Stage: 2},
+		},
+		OnFailure: model.PlanOnFailureAbort,
+	},
+	{
+		PlanID:      "balance-inquiry",
+		Name:        "Balance inquiry",
+		Description: "Credit score insight, then the banking agent reads the balance; a scoring failure does not hold up the balance",
+		Intents:     []string{"CHECK_BALANCE"},
+		Steps: []model.PlanStep{
+			{Name: "scoring", AgentType: string(model.AgentTypeScoring), Stage: 1, OnFailure: model.PlanOnFailureSkip},
+			{Name: "execute", AgentType: string(model.AgentTypeBanking), Stage: 2},
+		},
+		OnFailure: model.PlanOnFailureAbort,
+	},
+	{
+		PlanID:      "loan-application",
+		Name:        "Loan application",
+		Description: "Credit scoring, then the clearance agent decides",
+		Intents:     []string{"APPLY_LOAN", "LOAN_APPROVAL"},
+		Steps: []model.PlanStep{
+			{Name: "scoring", AgentType: string(model.AgentTypeScoring), Stage: 1},
+			{Name: "clearance", AgentType: string(model.AgentTypeClearance), Stage: 2},
+		},
+		OnFailure: model.PlanOnFailureAbort,
+	},
+	{
+		PlanID:      "new-beneficiary",
+		Name:        "New beneficiary",
+		Description: "Guardrail validation, then the banking agent adds the beneficiary",
+		Intents:     []string{"ADD_BENEFICIARY", "MANAGE_BENEFICIARY"},
+		Steps: []model.PlanStep{
+			{Name: "guardrail_check", AgentType: string(model.AgentTypeGuardrail), Stage: 1},
+			{Name: "execute", AgentType: string(model.AgentTypeBanking), Stage: 2},
+		},
+		OnFailure: model.PlanOnFailureAbort,
+	},
+}
+
+// PlanStore keeps orchestration plan templates and the plan instances executed from
+// them. Plans are kept in Redis, when available, so they survive a restart; executed
+// instances are kept in memory, the most recent planRunLimit per plan, and on their
+// task.
+type PlanStore struct {
+	redisClient *redis.Client
+	mu          sync.RWMutex
+	plans       map[string]*model.OrchestrationPlan
+	runs        map[string][]*model.PlanRun // Plan ID -> runs, oldest first
+}
+
+// NewPlanStore creates the plan store with the built-in templates and any plans saved
+// in Redis
+func NewPlanStore(redisClient *redis.Client) *PlanStore {
+	ps := &PlanStore{
+		redisClient: redisClient,
+		plans:       make(map[string]*model.OrchestrationPlan),
+		runs:        make(map[string][]*model.PlanRun),
+	}
+
+	now := time.Now()
+	for _, builtin := range builtinPlans {
+		plan := builtin
+		plan.Steps = append([]model.PlanStep(nil), builtin.Steps...)
+		plan.Builtin = true
+		plan.Version = 1
+		plan.CreatedAt = now
+		plan.UpdatedAt = now
+		fillStepDescriptions(&plan)
+		ps.plans[plan.PlanID] = &plan
+	}
+	ps.load(context.Background())
+
+	return ps
+}
+
+// List returns all plans by ID
+func (ps *PlanStore) List() []model.OrchestrationPlan {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	plans := make([]model.OrchestrationPlan, 0, len(ps.plans))
+	for _, plan := range ps.plans {
+		plans = append(plans, *plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].PlanID < plans[j].PlanID })
+	return plans
+}
+
+// Get returns a plan
+func (ps *PlanStore) Get(planID string) (*model.OrchestrationPlan, error) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	plan, ok := ps.plans[planID]
+	if !ok {
+		return nil, ErrPlanNotFound
+	}
+	copied := *plan
+	return &copied, nil
+}
+
+// Exists reports whether a plan exists
+func (ps *PlanStore) Exists(planID string) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	_, ok := ps.plans[planID]
+	return ok
+}
+
+// Create adds a plan. A plan without an ID is given one.
+func (ps *PlanStore) Create(ctx context.Context, plan *model.OrchestrationPlan) (*model.OrchestrationPlan, error) {
+	if plan.PlanID == "" {
+		plan.PlanID = ids.New(ids.Plan)
+	}
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, exists := ps.plans[plan.PlanID]; exists {
+		return nil, ErrPlanExists
+	}
+
+	now := time.Now()
+	plan.Builtin = false
+	plan.Version = 1
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+	ps.plans[plan.PlanID] = plan
+	ps.save(ctx, plan)
+
+	log.Info().Str("plan_id", plan.PlanID).Int("steps", len(plan.Steps)).Msg("Orchestration plan created")
+	copied := *plan
+	return &copied, nil
+}
+
+// Update replaces a plan's definition, keeping its ID and creation time. Built-in
+// templates can be updated too; the change is saved like any other plan.
+func (ps *PlanStore) Update(ctx context.Context, planID string, plan *model.OrchestrationPlan) (*model.OrchestrationPlan, error) {
+	plan.PlanID = planID
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	current, ok := ps.plans[planID]
+	if !ok {
+		return nil, ErrPlanNotFound
+	}
+
+	plan.Builtin = current.Builtin
+	plan.Version = current.Version + 1
+	plan.CreatedAt = current.CreatedAt
+	plan.UpdatedAt = time.Now()
+	ps.plans[planID] = plan
+	ps.save(ctx, plan)
+
+	log.Info().Str("plan_id", planID).Int("version", plan.Version).Msg("Orchestration plan updated")
+	copied := *plan
+	return &copied, nil
+}
+
+// Delete removes a plan and its executed instances
+func (ps *PlanStore) Delete(ctx context.Context, planID string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	plan, ok := ps.plans[planID]
+	if !ok {
+		return ErrPlanNotFound
+	}
+	if plan.Builtin {
+		return ErrPlanBuiltin
+	}
+	delete(ps.plans, planID)
+	delete(ps.runs, planID)
+
+	if ps.redisClient != nil {
+		if err := ps.redisClient.HDel(ctx, plansKey, planID).Err(); err != nil {
+			log.Warn().Err(err).Str("plan_id", planID).Msg("Failed to remove orchestration plan from Redis")
+		}
+	}
+	log.Info().Str("plan_id", planID).Msg("Orchestration plan deleted")
+	return nil
+}
+
+// RecordRun keeps an executed plan instance
+func (ps *PlanStore) RecordRun(run *model.PlanRun) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	copied := *run
+	copied.Steps = append([]model.PlanStepRun(nil), run.Steps...)
+	runs := append(ps.runs[run.PlanID], &copied)
+	if len(runs) > planRunLimit {
+		runs = runs[len(runs)-planRunLimit:]
+	}
+	ps.runs[run.PlanID] = runs
+}
+
+// Runs returns a plan's most recent executed instances, newest first
+func (ps *PlanStore) Runs(planID string, limit int) []model.PlanRun {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	runs := ps.runs[planID]
+	if limit <= 0 || limit > len(runs) {
+		limit = len(runs)
+	}
+	recent := make([]model.PlanRun, 0, limit)
+	for i := len(runs) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, *runs[i])
+	}
+	return recent
+}
+
+// planStages groups a plan's steps by stage, in the order the stages run
+func planStages(plan *model.OrchestrationPlan) [][]model.PlanStep {
+	byStage := make(map[int][]model.PlanStep)
+	var stages []int
+	for _, step := range plan.Steps {
+		if _, seen := byStage[step.Stage]; !seen {
+			stages = append(stages, step.Stage)
+		}
+		byStage[step.Stage] = append(byStage[step.Stage], step)
+	}
+	sort.Ints(stages)
+
+	grouped := make([][]model.PlanStep, 0, len(stages))
+	for _, stage := range stages {
+		grouped = append(grouped, byStage[stage])
+	}
+	return grouped
+}
+
+// validatePlan checks a plan and fills in its defaults
+func validatePlan(plan *model.OrchestrationPlan) error {
+	if !planIDPattern.MatchString(plan.PlanID) {
+		return fmt.Errorf("plan_id must be 1-64 lowercase letters, digits, '-' or '_'")
+	}
+	if strings.TrimSpace(plan.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(plan.Steps) == 0 {
+		return fmt.Errorf("a plan needs at least one step")
+	}
+
+	var err error
+	if plan.OnFailure, err = failurePolicy(plan.OnFailure); err != nil {
+		return err
+	}
+	if plan.OnFailure == "" {
+		plan.OnFailure = model.PlanOnFailureAbort
+	}
+
+	movesMoney := false
+	for i, intent := range plan.Intents {
+		plan.Intents[i] = strings.ToUpper(strings.TrimSpace(intent))
+		movesMoney = movesMoney || isDebitIntent(plan.Intents[i])
+	}
+
+	names := make(map[string]bool)
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		step.AgentType = strings.ToUpper(strings.TrimSpace(step.AgentType))
+		if _, known := agentSteps[model.AgentType(step.AgentType)]; !known {
+			return fmt.Errorf("step %d: unknown agent_type %q", i+1, step.AgentType)
+		}
+		if step.Name == "" {
+			step.Name = agentSteps[model.AgentType(step.AgentType)].name
+		}
+		if names[step.Name] {
+			return fmt.Errorf("step %d: duplicate step name %q", i+1, step.Name)
+		}
+		names[step.Name] = true
+		if step.Stage <= 0 {
+			return fmt.Errorf("step %s: stage must be 1 or more", step.Name)
+		}
+
+		if step.OnFailure, err = failurePolicy(step.OnFailure); err != nil {
+			return fmt.Errorf("step %s: %w", step.Name, err)
+		}
+		if step.Retries < 0 || step.Retries > planMaxRetries {
+			return fmt.Errorf("step %s: retries must be 0 to %d", step.Name, planMaxRetries)
+		}
+		if movesMoney && step.AgentType == string(model.AgentTypeGuardrail) && stepPolicy(plan, *step, "") == model.PlanOnFailureSkip {
+			return fmt.Errorf("step %s: guardrail checks of money-moving plans cannot be skipped", step.Name)
+		}
+
+		if c := step.Compensation; c != nil {
+			c.AgentType = strings.ToUpper(strings.TrimSpace(c.AgentType))
+			if _, known := agentSteps[model.AgentType(c.AgentType)]; !known {
+				return fmt.Errorf("step %s: unknown compensation agent_type %q", step.Name, c.AgentType)
+			}
+			if c.Action = strings.ToUpper(strings.TrimSpace(c.Action)); c.Action == "" {
+				return fmt.Errorf("step %s: compensation action is required", step.Name)
+			}
+		}
+	}
+	fillStepDescriptions(plan)
+	return nil
+}
+
+// failurePolicy normalizes a failure policy; empty stays empty
+func failurePolicy(policy string) (string, error) {
+	switch policy = strings.ToUpper(strings.TrimSpace(policy)); policy {
+	case "", model.PlanOnFailureAbort, model.PlanOnFailureSkip, model.PlanOnFailureRetry, model.PlanOnFailureCompensate:
+		return policy, nil
+	}
+	return "", fmt.Errorf("on_failure must be %s, %s, %s or %s", model.PlanOnFailureAbort, model.PlanOnFailureSkip, model.PlanOnFailureRetry, model.PlanOnFailureCompensate)
+}
+
+// stepPolicy returns the failure policy a step runs under for an intent: its own, or
+// the plan's. A guardrail check on a money-moving intent always aborts the task.
+func stepPolicy(plan *model.OrchestrationPlan, step model.PlanStep, intent string) string {
+	policy := step.OnFailure
+	if policy == "" {
+		policy = plan.OnFailure
+	}
+	if policy == model.PlanOnFailureSkip && step.AgentType == string(model.AgentTypeGuardrail) && isDebitIntent(intent) {
+		return model.PlanOnFailureAbort
+	}
+	return policy
+}
+
+// stepAttempts is how many times a step is tried before its policy applies
+func stepAttempts(step model.PlanStep, policy string) int {
+	if step.Retries == 0 && policy == model.PlanOnFailureRetry {
+		return 1 + planDefaultRetries
+	}
+	return 1 + step.Retries
+}
+
+// fillStepDescriptions gives steps without a description their agent type's
+func fillStepDescriptions(plan *model.OrchestrationPlan) {
+	for i := range plan.Steps {
+		if plan.Steps[i].Description == "" {
+			plan.Steps[i].Description = agentSteps[model.AgentType(plan.Steps[i].AgentType)].description
+		}
+	}
+}
+
+// save writes a plan to Redis. Callers hold ps.mu.
+func (ps *PlanStore) save(ctx context.Context, plan *model.OrchestrationPlan) {
+	if ps.redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return
+	}
+	if err := ps.redisClient.HSet(ctx, plansKey, plan.PlanID, data).Err(); err != nil {
+		log.Warn().Err(err).Str("plan_id", plan.PlanID).Msg("Failed to save orchestration plan to Redis")
+	}
+}
+
+// load reads the saved plans from Redis; a saved plan replaces the built-in template
+// of the same ID
+func (ps *PlanStore) load(ctx context.Context) {
+	if ps.redisClient == nil {
+		return
+	}
+	saved, err := ps.redisClient.HGetAll(ctx, plansKey).Result()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load orchestration plans from Redis, using the built-in templates")
+		return
+	}
+	for planID, data := range saved {
+		var plan model.OrchestrationPlan
+		if err := json.Unmarshal([]byte(data), &plan); err != nil {
+			log.Warn().Err(err).Str("plan_id", planID).Msg("Skipping unreadable orchestration plan")
+			continue
+		}
+		ps.plans[planID] = &plan
+	}
+	if len(saved) > 0 {
+		log.Info().Int("count", len(saved)).Msg("Loaded orchestration plans from Redis")
+	}
+}
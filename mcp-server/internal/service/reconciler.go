@@ -0,0 +1,436 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/aibanking/shared/secrets"
+	"github.com/aibanking/shared/tz"
+	"github.com/rs/zerolog/log"
+)
+
+// Reconciliation limits
+const (
+	reconcileReportLimit = 30   // Reports kept, oldest dropped first
+	reconcileLookupBatch = 1000 // Transaction IDs per DWH lookup, the DWH's own cap
+)
+
+// Reconciliation triggers
+const (
+	ReconcileScheduled = "schedule"
+	ReconcileManual    = "manual"
+)
+
+// ErrExceptionNotFound is returned for a reconciliation exception that does not exist
+var ErrExceptionNotFound = errors.New("reconciliation exception not found")
+
+// dwhTransaction is the part of a DWH transaction reconciliation compares
+type dwhTransaction struct {
+	TransactionID string  `json:"transaction_id"`
+	Amount        float64 `json:"amount"`
+	Status        string  `json:"status"`
+}
+
+// Reconciler cross-checks finished transfer tasks against the transactions in the
+// DWH once a night and flags every task the DWH contradicts as an exception. A task
+// can complete on a mocked core-banking call that never reached the DWH, or fail
+// after its transfer went through. With auto-correction the task's status is set to
+// what the DWH shows.
+type Reconciler struct {
+	cfg           *config.ReconcileConfig
+	taskManager   *TaskManager
+	httpClient    *http.Client
+	bankingAPIKey *secrets.Value
+	location      *time.Location
+	exceptions    map[string]*model.ReconciliationException // Keyed by task ID and kind
+	reports       []model.ReconciliationReport              // Oldest first
+	mu            sync.Mutex
+	runMu         sync.Mutex // One run at a time
+}
+
+// NewReconciler creates a reconciler
+func NewReconciler(cfg *config.ReconcileConfig, taskManager *TaskManager) *Reconciler {
+	location := time.UTC
+	if cfg.Timezone != "" {
+		if loc, err := tz.Load(cfg.Timezone); err == nil {
+			location = loc
+		} else {
+			log.Warn().Err(err).Str("timezone", cfg.Timezone).Msg("Unknown reconciliation time zone, using UTC")
+		}
+	}
+
+	return &Reconciler{
+		cfg:           cfg,
+		taskManager:   taskManager,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		bankingAPIKey: config.Profile().RotatingSecret("RECONCILE_BANKING_API_KEY", cfg.BankingAPIKey),
+		location:      location,
+		exceptions:    make(map[string]*model.ReconciliationException),
+	}
+}
+
+// Run reconciles every night at RECONCILE_HOUR until ctx is done
+func (rc *Reconciler) Run(ctx context.Context) {
+	for {
+		next := rc.nextRun(time.Now())
+		log.Info().Time("next_run", next).Msg("Reconciliation scheduled")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			rc.Reconcile(ctx, ReconcileScheduled, rc.cfg.AutoCorrect)
+		}
+	}
+}
+
+// nextRun returns the next time the nightly run is due after now
+func (rc *Reconciler) nextRun(now time.Time) time.Time {
+	local := now.In(rc.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), rc.cfg.Hour, 0, 0, 0, rc.location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Reconcile checks the transfer tasks that finished in the lookback window against
+// the DWH and records a report. New exceptions are opened and ones already open are
+// marked as seen again; with autoCorrect the tasks they concern are corrected.
+func (rc *Reconciler) Reconcile(ctx context.Context, trigger string, autoCorrect bool) *model.ReconciliationReport {
+	rc.runMu.Lock()
+	defer rc.runMu.Unlock()
+
+	now := time.Now()
+	windowEnd := now.Add(-time.Duration(rc.cfg.GraceMinutes) * time.Minute)
+	report := model.ReconciliationReport{
+		ID:          ids.New(ids.Reconcile),
+		Trigger:     trigger,
+		AutoCorrect: autoCorrect,
+		WindowStart: windowEnd.Add(-time.Duration(rc.cfg.LookbackHours) * time.Hour),
+		WindowEnd:   windowEnd,
+		StartedAt:   now,
+	}
+
+	finished, err := rc.taskManager.FinishedBetween(ctx, report.WindowStart, report.WindowEnd)
+	if err != nil {
+		report.Error = err.Error()
+		report.FinishedAt = time.Now()
+		log.Error().Err(err).Str("reconciliation_id", report.ID).Msg("Reconciliation failed, the finished tasks could not be read")
+		rc.record(report)
+		return &report
+	}
+
+	var tasks []model.Task
+	var txnIDs []string
+	for _, task := range finished {
+		if task.Sandbox || !isDebitIntent(task.Intent) {
+			continue
+		}
+		if task.Status != model.TaskStatusCompleted && task.Status != model.TaskStatusFailed {
+			continue
+		}
+		txnID := resultTransactionID(task.Result)
+		// A failed task that never got as far as a transaction has nothing to check
+		if task.Status == model.TaskStatusFailed && txnID == "" {
+			continue
+		}
+		tasks = append(tasks, task)
+		if txnID != "" {
+			txnIDs = append(txnIDs, txnID)
+		}
+	}
+	report.Checked = len(tasks)
+
+	found, err := rc.lookup(ctx, txnIDs)
+	if err != nil {
+		report.Error = err.Error()
+		report.FinishedAt = time.Now()
+		log.Error().Err(err).Str("reconciliation_id", report.ID).Msg("Reconciliation failed, the DWH could not be reached")
+		rc.record(report)
+		return &report
+	}
+
+	for i := range tasks {
+		exception := compareWithDWH(&tasks[i], found)
+		if exception == nil {
+			report.Matched++
+			continue
+		}
+		report.Exceptions++
+		if rc.flag(ctx, &report, exception, autoCorrect) {
+			report.Corrected++
+		}
+	}
+	report.FinishedAt = time.Now()
+
+	log.Info().
+		Str("reconciliation_id", report.ID).
+		Str("trigger", trigger).
+		Int("checked", report.Checked).
+		Int("matched", report.Matched).
+		Int("exceptions", report.Exceptions).
+		Int("corrected", report.Corrected).
+		Msg("Reconciliation finished")
+
+	rc.record(report)
+	return &report
+}
+
+// compareWithDWH returns the exception a task raises against the DWH's transactions,
+// or nil when they agree
+func compareWithDWH(task *model.Task, found map[string]dwhTransaction) *model.ReconciliationException {
+	exception := &model.ReconciliationException{
+		TaskID:        task.TaskID,
+		UserID:        task.UserID,
+		Intent:        task.Intent,
+		TransactionID: resultTransactionID(task.Result),
+		TaskStatus:    task.Status,
+		TaskAmount:    taskAmount(task.Result),
+	}
+
+	txn, ok := found[exception.TransactionID]
+	if ok {
+		exception.DWHStatus = txn.Status
+		exception.DWHAmount = txn.Amount
+	}
+
+	switch {
+	case task.Status == model.TaskStatusCompleted && exception.TransactionID == "":
+		exception.Kind = model.MismatchNoTransactionID
+		exception.Detail = "Task completed without a transaction ID"
+	case task.Status == model.TaskStatusCompleted && !ok:
+		exception.Kind = model.MismatchMissingInDWH
+		exception.Detail = "Task completed but the DWH has no record of its transaction"
+	case task.Status == model.TaskStatusCompleted && (txn.Status == "FAILED" || txn.Status == "REJECTED"):
+		exception.Kind = model.MismatchStatus
+		exception.Detail = fmt.Sprintf("Task completed but the DWH shows the transaction %s", txn.Status)
+	case task.Status == model.TaskStatusFailed && ok && (txn.Status == "COMPLETED" || txn.Status == "PENDING"):
+		exception.Kind = model.MismatchStatus
+		exception.Detail = fmt.Sprintf("Task failed but the DWH shows the transaction %s", txn.Status)
+	case ok && exception.TaskAmount > 0 && math.Abs(txn.Amount-exception.TaskAmount) >= 0.01:
+		exception.Kind = model.MismatchAmount
+		exception.Detail = fmt.Sprintf("Task reports %.2f but the DWH moved %.2f", exception.TaskAmount, txn.Amount)
+	default:
+		return nil
+	}
+	return exception
+}
+
+// correction returns the status a task is corrected to for an exception, if any.
+// Only a transfer the DWH settles one way or the other is corrected; amounts and
+// pending transfers are left to an operator.
+func correction(exception *model.ReconciliationException) (model.TaskStatus, bool) {
+	switch exception.Kind {
+	case model.MismatchMissingInDWH:
+		return model.TaskStatusFailed, true
+	case model.MismatchStatus:
+		if exception.TaskStatus == model.TaskStatusCompleted {
+			return model.TaskStatusFailed, true
+		}
+		if exception.DWHStatus == "COMPLETED" {
+			return model.TaskStatusCompleted, true
+		}
+	}
+	return "", false
+}
+
+// flag opens an exception, or marks an open one as seen again, and corrects its task
+// when asked to. It reports whether the task was corrected.
+func (rc *Reconciler) flag(ctx context.Context, report *model.ReconciliationReport, exception *model.ReconciliationException, autoCorrect bool) bool {
+	key := exception.TaskID + "|" + exception.Kind
+
+	rc.mu.Lock()
+	existing, ok := rc.exceptions[key]
+	if ok && existing.Status == model.ExceptionOpen {
+		existing.LastSeenAt = report.StartedAt
+		exception = existing
+	} else if !ok {
+		exception.ID = ids.New(ids.Exception)
+		exception.ReportID = report.ID
+		exception.Status = model.ExceptionOpen
+		exception.DetectedAt = report.StartedAt
+		exception.LastSeenAt = report.StartedAt
+		rc.exceptions[key] = exception
+		log.Warn().
+			Str("exception_id", exception.ID).
+			Str("task_id", exception.TaskID).
+			Str("transaction_id", exception.TransactionID).
+			Str("kind", exception.Kind).
+			Msg("Reconciliation exception")
+	}
+	rc.mu.Unlock()
+
+	// A resolved exception stays closed and its task as the operator left it
+	if ok && existing.Status == model.ExceptionResolved {
+		return false
+	}
+	if !autoCorrect || exception.CorrectedTo != "" {
+		return false
+	}
+	status, correct := correction(exception)
+	if !correct {
+		return false
+	}
+
+	reason := "Reconciliation: " + exception.Detail
+	if err := rc.taskManager.CorrectStatus(ctx, exception.TaskID, status, reason); err != nil {
+		log.Warn().Err(err).Str("task_id", exception.TaskID).Msg("Failed to correct task status")
+		return false
+	}
+
+	rc.mu.Lock()
+	exception.CorrectedTo = status
+	rc.mu.Unlock()
+	return true
+}
+
+// lookup fetches the DWH's records of the given transactions, in batches
+func (rc *Reconciler) lookup(ctx context.Context, txnIDs []string) (map[string]dwhTransaction, error) {
+	found := make(map[string]dwhTransaction)
+	for start := 0; start < len(txnIDs); start += reconcileLookupBatch {
+		end := start + reconcileLookupBatch
+		if end > len(txnIDs) {
+			end = len(txnIDs)
+		}
+
+		var resp struct {
+			Transactions map[string]dwhTransaction `json:"transactions"`
+		}
+		if err := rc.call(ctx, map[string]interface{}{"transaction_ids": txnIDs[start:end]}, &resp); err != nil {
+			return nil, err
+		}
+		for id, txn := range resp.Transactions {
+			found[id] = txn
+		}
+	}
+	return found, nil
+}
+
+// call posts a lookup to the banking integration's DWH
+func (rc *Reconciler) call(ctx context.Context, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(rc.cfg.BankingURL, "/") + "/api/v1/dwh/transactions/lookup"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", rc.bankingAPIKey.Get())
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("DWH lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("DWH lookup returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode DWH lookup: %w", err)
+	}
+	return nil
+}
+
+// record keeps a report, dropping the oldest past the limit
+func (rc *Reconciler) record(report model.ReconciliationReport) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.reports = append(rc.reports, report)
+	if len(rc.reports) > reconcileReportLimit {
+		rc.reports = rc.reports[len(rc.reports)-reconcileReportLimit:]
+	}
+}
+
+// Reports returns up to limit reconciliation reports, most recent first
+func (rc *Reconciler) Reports(limit int) []model.ReconciliationReport {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	reports := make([]model.ReconciliationReport, 0, len(rc.reports))
+	for i := len(rc.reports) - 1; i >= 0; i-- {
+		reports = append(reports, rc.reports[i])
+		if limit > 0 && len(reports) >= limit {
+			break
+		}
+	}
+	return reports
+}
+
+// Exceptions returns the exceptions with the given status, or all of them when status
+// is empty, most recently detected first
+func (rc *Reconciler) Exceptions(status string) []model.ReconciliationException {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	exceptions := make([]model.ReconciliationException, 0, len(rc.exceptions))
+	for _, exception := range rc.exceptions {
+		if status == "" || exception.Status == status {
+			exceptions = append(exceptions, *exception)
+		}
+	}
+	sort.Slice(exceptions, func(a, b int) bool {
+		if exceptions[a].DetectedAt.Equal(exceptions[b].DetectedAt) {
+			return exceptions[a].ID > exceptions[b].ID
+		}
+		return exceptions[a].DetectedAt.After(exceptions[b].DetectedAt)
+	})
+	return exceptions
+}
+
+// Resolve closes an open exception once an operator has dealt with it
+func (rc *Reconciler) Resolve(exceptionID string, req *model.ResolveExceptionRequest) (*model.ReconciliationException, error) {
+	if req.ResolvedBy == "" {
+		return nil, fmt.Errorf("resolved_by is required")
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, exception := range rc.exceptions {
+		if exception.ID != exceptionID {
+			continue
+		}
+		if exception.Status == model.ExceptionResolved {
+			return nil, fmt.Errorf("exception is already resolved")
+		}
+		now := time.Now()
+		exception.Status = model.ExceptionResolved
+		exception.ResolvedAt = &now
+		exception.ResolvedBy = req.ResolvedBy
+		exception.ResolutionNote = req.Note
+
+		log.Info().Str("exception_id", exceptionID).Str("resolved_by", req.ResolvedBy).Msg("Reconciliation exception resolved")
+		resolved := *exception
+		return &resolved, nil
+	}
+	return nil, ErrExceptionNotFound
+}
+
+// resultTransactionID returns the transaction ID an agent reported for a transfer
+func resultTransactionID(result map[string]interface{}) string {
+	txnID, _ := result["transaction_id"].(string)
+	return txnID
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aibanking/mcp-server/internal/model"
+)
+
+// resultSchema is the schema of an intent's result and the agent type that produces it
+type resultSchema struct {
+	name      string
+	agentType model.AgentType
+	decode    func() model.ResultSchema
+}
+
+// resultSchemas are keyed by intent; transfers are keyed TRANSFER
+var resultSchemas = map[string]resultSchema{
+	"CHECK_BALANCE": {model.ResultSchemaBalance, model.AgentTypeBanking, func() model.ResultSchema { return &model.BalanceResult{} }},
+	"TRANSFER":      {model.ResultSchemaTransfer, model.AgentTypeBanking, func() model.ResultSchema { return &model.TransferResult{} }},
+	"GET_STATEMENT": {model.ResultSchemaStatement, model.AgentTypeBanking, func() model.ResultSchema { return &model.StatementResult{} }},
+	"CREDIT_SCORE":  {model.ResultSchemaScore, model.AgentTypeScoring, func() model.ResultSchema { return &model.ScoreResult{} }},
+}
+
+// conformResult checks an agent's result for a task against the intent's schema and
+// returns the schema's name. A result from any other agent type, or one the agent
+// rejected, has no schema and is returned as is with "".
+func conformResult(intent string, agentType model.AgentType, result map[string]interface{}) (string, error) {
+	key := intent
+	if strings.HasPrefix(intent, "TRANSFER_") {
+		key = "TRANSFER"
+	}
+	schema, ok := resultSchemas[key]
+	if !ok || schema.agentType != agentType {
+		return "", nil
+	}
+	if status, _ := result["status"].(string); status == "REJECTED" {
+		return "", nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("%s result is not JSON: %w", agentType, err)
+	}
+	typed := schema.decode()
+	if err := json.Unmarshal(data, typed); err != nil {
+		return "", fmt.Errorf("%s result does not match %s: %w", agentType, schema.name, err)
+	}
+	if err := typed.Validate(); err != nil {
+		return "", fmt.Errorf("%s result does not match %s: %w", agentType, schema.name, err)
+	}
+	return schema.name, nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Purge report limits
+const (
+	purgeReportLimit = 50   // Reports kept, oldest dropped first
+	purgedIDLimit    = 1000 // IDs listed per class in a report
+)
+
+// legalHoldsKey is the Redis hash of legal holds, keyed by user ID
+const legalHoldsKey = "retention:legal_holds"
+
+// Purge triggers
+const (
+	PurgeScheduled = "schedule"
+	PurgeManual    = "manual"
+)
+
+// RetentionManager purges data once it is older than its class's retention policy,
+// except the data of users under a legal hold, and keeps a report of every purge.
+// Legal holds are kept in Redis, when available, so they survive a restart.
+type RetentionManager struct {
+	cfg          *config.RetentionConfig
+	taskManager  *TaskManager
+	alertManager *AlertManager
+	redisClient  *redis.Client
+	holds        map[string]*model.LegalHold // Keyed by user ID
+	reports      []model.PurgeReport         // Oldest first
+	mu           sync.Mutex
+	purgeMu      sync.Mutex // One purge at a time
+}
+
+// NewRetentionManager creates a retention manager. Tasks are given the retention of
+// the tasks class as their Redis TTL, so Redis never keeps them longer than memory.
+func NewRetentionManager(cfg *config.RetentionConfig, taskManager *TaskManager, alertManager *AlertManager, redisClient *redis.Client) *RetentionManager {
+	taskManager.SetTTL(retentionPeriod(cfg.TasksDays))
+
+	rm := &RetentionManager{
+		cfg:          cfg,
+		taskManager:  taskManager,
+		alertManager: alertManager,
+		redisClient:  redisClient,
+		holds:        make(map[string]*model.LegalHold),
+	}
+	rm.loadHolds(context.Background())
+	return rm
+}
+
+// Policies returns the retention policy of each data class
+func (rm *RetentionManager) Policies() []model.RetentionPolicy {
+	return []model.RetentionPolicy{
+		{Class: model.RetentionTasks, Days: rm.cfg.TasksDays, Description: "Finished tasks with their results, diagnostics and SLA measurements"},
+		{Class: model.RetentionAudit, Days: rm.cfg.AuditDays, Description: "Resolved alerts"},
+	}
+}
+
+// Run purges every RETENTION_PURGE_INTERVAL_MINUTES until ctx is done
+func (rm *RetentionManager) Run(ctx context.Context) {
+	interval := time.Duration(rm.cfg.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log.Info().Dur("interval", interval).Msg("Retention purging started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rm.Purge(ctx, PurgeScheduled, false)
+		}
+	}
+}
+
+// Purge removes everything past its retention and records a report. With dryRun it
+// only reports what would be removed.
+func (rm *RetentionManager) Purge(ctx context.Context, trigger string, dryRun bool) *model.PurgeReport {
+	rm.purgeMu.Lock()
+	defer rm.purgeMu.Unlock()
+
+	report := model.PurgeReport{
+		ID:        ids.New(ids.Purge),
+		DryRun:    dryRun,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	for _, policy := range rm.Policies() {
+		class := model.PurgedClass{Class: policy.Class}
+		if policy.Days <= 0 {
+			class.Skipped = true
+			report.Classes = append(report.Classes, class)
+			continue
+		}
+
+		cutoff := report.StartedAt.AddDate(0, 0, -policy.Days)
+		class.Cutoff = &cutoff
+
+		var ids []string
+		switch policy.Class {
+		case model.RetentionTasks:
+			ids, class.Held = rm.taskManager.PurgeTasks(ctx, cutoff, rm.IsHeld, dryRun)
+		case model.RetentionAudit:
+			ids = rm.alertManager.PurgeHistory(cutoff, dryRun)
+		}
+
+		class.Purged = len(ids)
+		sort.Strings(ids)
+		if len(ids) > purgedIDLimit {
+			ids = ids[:purgedIDLimit]
+		}
+		class.IDs = ids
+		report.Classes = append(report.Classes, class)
+	}
+	report.FinishedAt = time.Now()
+
+	event := log.Info().Str("purge_id", report.ID).Str("trigger", trigger).Bool("dry_run", dryRun)
+	for _, class := range report.Classes {
+		event = event.Int(class.Class+"_purged", class.Purged).Int(class.Class+"_held", class.Held)
+	}
+	event.Msg("Retention purge finished")
+
+	rm.mu.Lock()
+	rm.reports = append(rm.reports, report)
+	if len(rm.reports) > purgeReportLimit {
+		rm.reports = rm.reports[len(rm.reports)-purgeReportLimit:]
+	}
+	rm.mu.Unlock()
+
+	return &report
+}
+
+// Reports returns up to limit purge reports, most recent first
+func (rm *RetentionManager) Reports(limit int) []model.PurgeReport {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	reports := make([]model.PurgeReport, 0, len(rm.reports))
+	for i := len(rm.reports) - 1; i >= 0; i-- {
+		reports = append(reports, rm.reports[i])
+		if limit > 0 && len(reports) >= limit {
+			break
+		}
+	}
+	return reports
+}
+
+// PlaceHold exempts a user's data from purging, replacing any hold already placed
+func (rm *RetentionManager) PlaceHold(userID string, req *model.LegalHoldRequest) (*model.LegalHold, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	if req.Reason == "" || req.PlacedBy == "" {
+		return nil, fmt.Errorf("reason and placed_by are required")
+	}
+
+	hold := &model.LegalHold{
+		UserID:   userID,
+		Reason:   req.Reason,
+		PlacedBy: req.PlacedBy,
+		PlacedAt: time.Now(),
+	}
+
+	rm.mu.Lock()
+	rm.holds[userID] = hold
+	rm.mu.Unlock()
+
+	if data, err := json.Marshal(hold); err == nil && rm.redisClient != nil {
+		if err := rm.redisClient.HSet(context.Background(), legalHoldsKey, userID, data).Err(); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Failed to save legal hold to Redis, kept in memory only")
+		}
+	}
+
+	log.Info().Str("user_id", userID).Str("placed_by", req.PlacedBy).Msg("Legal hold placed")
+	holdCopy := *hold
+	return &holdCopy, nil
+}
+
+// ReleaseHold lifts a user's legal hold, reporting whether there was one. Their data
+// is purged by the next run once it is past retention.
+func (rm *RetentionManager) ReleaseHold(userID string) bool {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if _, ok := rm.holds[userID]; !ok {
+		return false
+	}
+	delete(rm.holds, userID)
+
+	if rm.redisClient != nil {
+		if err := rm.redisClient.HDel(context.Background(), legalHoldsKey, userID).Err(); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Failed to remove legal hold from Redis")
+		}
+	}
+	log.Info().Str("user_id", userID).Msg("Legal hold released")
+	return true
+}
+
+// Holds returns the legal holds in place, most recent first
+func (rm *RetentionManager) Holds() []model.LegalHold {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	holds := make([]model.LegalHold, 0, len(rm.holds))
+	for _, hold := range rm.holds {
+		holds = append(holds, *hold)
+	}
+	sort.Slice(holds, func(a, b int) bool { return holds[a].PlacedAt.After(holds[b].PlacedAt) })
+	return holds
+}
+
+// IsHeld reports whether a user is under a legal hold
+func (rm *RetentionManager) IsHeld(userID string) bool {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	_, ok := rm.holds[userID]
+	return ok
+}
+
+// loadHolds reads the legal holds saved in Redis
+func (rm *RetentionManager) loadHolds(ctx context.Context) {
+	if rm.redisClient == nil {
+		return
+	}
+	saved, err := rm.redisClient.HGetAll(ctx, legalHoldsKey).Result()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load legal holds from Redis")
+		return
+	}
+	for userID, data := range saved {
+		var hold model.LegalHold
+		if err := json.Unmarshal([]byte(data), &hold); err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Skipping unreadable legal hold")
+			continue
+		}
+		rm.holds[userID] = &hold
+	}
+	if len(rm.holds) > 0 {
+		log.Info().Int("holds", len(rm.holds)).Msg("Legal holds loaded")
+	}
+}
+
+// retentionPeriod converts a policy's days to a duration; 0 means forever
+func retentionPeriod(days int) time.Duration {
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/aibanking/mcp-server/internal/model"
@@ -104,9 +105,11 @@ func (re *RuleEngine) applyRule(rule interface{}, enrichedContext *model.Context
 		return nil, fmt.Errorf("invalid rule format")
 	}
 
-	agentType, ok := ruleMap["agent_type"].(string)
-	if !ok {
-		return nil, fmt.Errorf("rule missing agent_type")
+	// A rule names an agent type, an orchestration plan, or both
+	agentType, _ := ruleMap["agent_type"].(string)
+	planID, _ := ruleMap["plan_id"].(string)
+	if agentType == "" && planID == "" {
+		return nil, fmt.Errorf("rule missing agent_type or plan_id")
 	}
 
 	reason, _ := ruleMap["reason"].(string)
@@ -120,6 +123,7 @@ func (re *RuleEngine) applyRule(rule interface{}, enrichedContext *model.Context
 		AgentType:       agentType,
 		Confidence:      confidence,
 		Reason:          reason,
+		PlanID:          planID,
 		Context:         enrichedContext,
 	}, nil
 }
@@ -169,6 +173,21 @@ func (re *RuleEngine) GetRules() map[string]interface{} {
 	return rulesCopy
 }
 
+// RulesReferencingPlan returns the keys of the rules that use a plan, sorted
+func (re *RuleEngine) RulesReferencingPlan(planID string) []string {
+	re.mu.RLock()
+	defer re.mu.RUnlock()
+
+	var keys []string
+	for key, rule := range re.rules {
+		if ruleMap, ok := rule.(map[string]interface{}); ok && ruleMap["plan_id"] == planID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // SaveRulesToFile saves current rules to a file
 func (re *RuleEngine) SaveRulesToFile(filePath string) error {
 	rules := re.GetRules()
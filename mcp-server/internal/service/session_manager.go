@@ -3,22 +3,39 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/aibanking/mcp-server/internal/model"
-	"github.com/aibanking/mcp-server/internal/utils"
+	"github.com/aibanking/shared/ids"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
+// ErrSessionOwner is returned when binding a session ID that belongs to another user
+var ErrSessionOwner = errors.New("session belongs to another user")
+
+// ErrInvalidSessionID is returned for a session ID a client may not choose
+var ErrInvalidSessionID = errors.New("invalid session ID")
+
+// ErrStatelessSession is returned for a stateless task that names a session
+var ErrStatelessSession = errors.New("a stateless task cannot belong to a session")
+
+// sessionIDPattern is what a session ID chosen by a client, such as the AI Skin, may
+// look like
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
 // SessionManager handles session creation, retrieval, and context management
 type SessionManager struct {
 	redisClient    *redis.Client
 	redisAvailable bool
 	sessions       map[string]*model.Session // In-memory fallback
 	mu             sync.RWMutex
+	bindMu         sync.Mutex // Serialises binds so one session ID is created once
 	ttl            time.Duration
 }
 
@@ -49,8 +66,46 @@ func NewSessionManager(redisClient *redis.Client) *SessionManager {
 
 // CreateSession creates a new session with context
 func (sm *SessionManager) CreateSession(ctx context.Context, req *model.SessionRequest) (*model.Session, error) {
-	sessionID := utils.GenerateSessionID()
-	
+	return sm.createSession(ctx, ids.New(ids.Session), req)
+}
+
+// BindSession upserts the session a client identifies by its own ID, so the AI Skin
+// and the MCP server share one conversation identity. An unknown or expired ID is
+// created as given; a known one has the request's context merged into its own and its
+// expiry extended. The session is returned with whether it was created.
+func (sm *SessionManager) BindSession(ctx context.Context, sessionID string, req *model.SessionRequest) (*model.Session, bool, error) {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return nil, false, fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+
+	sm.bindMu.Lock()
+	defer sm.bindMu.Unlock()
+
+	session, err := sm.GetSession(ctx, sessionID)
+	if err != nil {
+		session, err = sm.createSession(ctx, sessionID, req)
+		return session, err == nil, err
+	}
+	if session.UserID != req.UserID {
+		return nil, false, fmt.Errorf("%w: %s", ErrSessionOwner, sessionID)
+	}
+
+	sm.mu.Lock()
+	if req.Channel != "" {
+		session.Channel = req.Channel
+	}
+	session.ExpiresAt = time.Now().Add(sm.ttl)
+	sm.mu.Unlock()
+
+	// Saves the session with its new channel and expiry
+	if err := sm.UpdateSession(ctx, sessionID, map[string]interface{}{"context": req.Context}); err != nil {
+		return nil, false, err
+	}
+	return session, false, nil
+}
+
+// createSession stores a new session under sessionID
+func (sm *SessionManager) createSession(ctx context.Context, sessionID string, req *model.SessionRequest) (*model.Session, error) {
 	session := &model.Session{
 		SessionID:   sessionID,
 		UserID:      req.UserID,
@@ -58,6 +113,7 @@ func (sm *SessionManager) CreateSession(ctx context.Context, req *model.SessionR
 		Context:     req.Context,
 		Metadata:    make(map[string]interface{}),
 		TaskHistory: []string{},
+		Sandbox:     req.Sandbox,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		ExpiresAt:   time.Now().Add(sm.ttl),
@@ -84,6 +140,7 @@ func (sm *SessionManager) CreateSession(ctx context.Context, req *model.SessionR
 		Str("session_id", sessionID).
 		Str("user_id", req.UserID).
 		Str("channel", req.Channel).
+		Bool("sandbox", req.Sandbox).
 		Msg("Session created")
 
 	return session, nil
@@ -191,6 +248,71 @@ func (sm *SessionManager) AddTaskToSession(ctx context.Context, sessionID, taskI
 	})
 }
 
+// DeleteSession removes a session from memory and Redis
+func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
+	sm.mu.Lock()
+	_, found := sm.sessions[sessionID]
+	delete(sm.sessions, sessionID)
+	sm.mu.Unlock()
+
+	if sm.redisAvailable && sm.redisClient != nil {
+		deleted, err := sm.redisClient.Del(ctx, fmt.Sprintf("session:%s", sessionID)).Result()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to delete session from Redis")
+		} else if deleted > 0 {
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	log.Info().Str("session_id", sessionID).Msg("Session deleted")
+	return nil
+}
+
+// UserSessions returns copies of every session of a user, in memory or in Redis,
+// oldest first. Redis is scanned, so this is meant for rare requests such as
+// data-subject requests.
+func (sm *SessionManager) UserSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	found := make(map[string]model.Session)
+
+	sm.mu.RLock()
+	for sessionID, session := range sm.sessions {
+		if session.UserID == userID {
+			found[sessionID] = *session
+		}
+	}
+	sm.mu.RUnlock()
+
+	if sm.redisAvailable && sm.redisClient != nil {
+		iter := sm.redisClient.Scan(ctx, 0, "session:*", 500).Iterator()
+		for iter.Next(ctx) {
+			data, err := sm.redisClient.Get(ctx, iter.Val()).Result()
+			if err != nil {
+				continue
+			}
+			var session model.Session
+			if json.Unmarshal([]byte(data), &session) == nil && session.UserID == userID {
+				if _, ok := found[session.SessionID]; !ok {
+					found[session.SessionID] = session
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan sessions in Redis: %w", err)
+		}
+	}
+
+	sessions := make([]model.Session, 0, len(found))
+	for _, session := range found {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(a, b int) bool { return sessions[a].CreatedAt.Before(sessions[b].CreatedAt) })
+	return sessions, nil
+}
+
 // saveSession saves session to Redis
 func (sm *SessionManager) saveSession(ctx context.Context, session *model.Session) error {
 	if sm.redisClient == nil {
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// slaWindow is how many recent tasks per intent the latency percentiles cover, and
+// slaBreachLimit how many breaches are kept for review
+const (
+	slaWindow      = 500
+	slaBreachLimit = 200
+)
+
+// SLATracker measures the end-to-end latency of completed tasks against per-intent
+// thresholds and keeps the recent latencies and breaches
+type SLATracker struct {
+	defaultMs  float64
+	thresholds map[string]float64
+	recent     map[string][]model.TaskSLA // Per intent, oldest first
+	breaches   []model.SLABreach          // Oldest first
+	mu         sync.Mutex
+}
+
+// NewSLATracker creates a tracker with the configured thresholds
+func NewSLATracker(cfg *config.SLAConfig) *SLATracker {
+	st := &SLATracker{
+		defaultMs:  float64(cfg.DefaultMs),
+		thresholds: make(map[string]float64, len(cfg.Thresholds)),
+		recent:     make(map[string][]model.TaskSLA),
+	}
+	for intent, ms := range cfg.Thresholds {
+		st.thresholds[intent] = float64(ms)
+	}
+	return st
+}
+
+// Threshold returns the SLA of an intent in milliseconds
+func (st *SLATracker) Threshold(intent string) float64 {
+	if ms, ok := st.thresholds[strings.ToUpper(intent)]; ok {
+		return ms
+	}
+	return st.defaultMs
+}
+
+// Observe records a completed task. routedAt is when routing began, calledAt when the
+// agent was called and agentDone when it answered. A task over its threshold is a
+// breach, attributed to whichever of queue wait, agent time and downstream calls took
+// longest.
+func (st *SLATracker) Observe(task *model.Task, agentType string, routedAt, calledAt, agentDone time.Time, diagnostics *model.AgentDiagnostics) *model.TaskSLA {
+	now := time.Now()
+	sla := &model.TaskSLA{
+		TotalMs:     millis(now.Sub(routedAt)),
+		QueueMs:     millis(calledAt.Sub(routedAt)),
+		ThresholdMs: st.Threshold(task.Intent),
+	}
+	if diagnostics != nil {
+		for _, call := range diagnostics.Calls {
+			sla.DownstreamMs += call.DurationMs
+		}
+	}
+	sla.AgentMs = millis(agentDone.Sub(calledAt)) - sla.DownstreamMs
+	if sla.AgentMs < 0 {
+		sla.AgentMs = 0
+	}
+
+	if sla.ThresholdMs > 0 && sla.TotalMs > sla.ThresholdMs {
+		sla.Breached = true
+		sla.BreachReason = model.SLABreachQueueWait
+		if sla.AgentMs > sla.QueueMs && sla.AgentMs >= sla.DownstreamMs {
+			sla.BreachReason = model.SLABreachAgentLatency
+		} else if sla.DownstreamMs > sla.QueueMs && sla.DownstreamMs > sla.AgentMs {
+			sla.BreachReason = model.SLABreachDownstreamCall
+		}
+
+		log.Warn().
+			Str("task_id", task.TaskID).
+			Str("intent", task.Intent).
+			Float64("total_ms", sla.TotalMs).
+			Float64("threshold_ms", sla.ThresholdMs).
+			Str("breach_reason", sla.BreachReason).
+			Msg("Task exceeded its SLA")
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	recent := append(st.recent[task.Intent], *sla)
+	if len(recent) > slaWindow {
+		recent = recent[len(recent)-slaWindow:]
+	}
+	st.recent[task.Intent] = recent
+
+	if sla.Breached {
+		st.breaches = append(st.breaches, model.SLABreach{
+			TaskID:      task.TaskID,
+			Intent:      task.Intent,
+			AgentType:   agentType,
+			CompletedAt: now,
+			TaskSLA:     *sla,
+		})
+		if len(st.breaches) > slaBreachLimit {
+			st.breaches = st.breaches[len(st.breaches)-slaBreachLimit:]
+		}
+	}
+	return sla
+}
+
+// Stats returns latency percentiles and breaches per intent, ordered by intent
+func (st *SLATracker) Stats() []model.IntentLatencyStats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stats := make([]model.IntentLatencyStats, 0, len(st.recent))
+	for intent, recent := range st.recent {
+		s := model.IntentLatencyStats{Intent: intent, Tasks: len(recent), ThresholdMs: st.Threshold(intent)}
+
+		latencies := make([]float64, len(recent))
+		for i, sla := range recent {
+			latencies[i] = sla.TotalMs
+			if sla.Breached {
+				s.Breaches++
+				if s.BreachReasons == nil {
+					s.BreachReasons = make(map[string]int)
+				}
+				s.BreachReasons[sla.BreachReason]++
+			}
+		}
+		sort.Float64s(latencies)
+
+		s.P50Ms = percentile(latencies, 0.50)
+		s.P95Ms = percentile(latencies, 0.95)
+		s.P99Ms = percentile(latencies, 0.99)
+		s.MaxMs = latencies[len(latencies)-1]
+		s.BreachRate = float64(s.Breaches) / float64(len(recent))
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(a, b int) bool { return stats[a].Intent < stats[b].Intent })
+	return stats
+}
+
+// Breaches returns the recent breaches, newest first, optionally for one intent
+func (st *SLATracker) Breaches(intent string, limit int) []model.SLABreach {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	breaches := []model.SLABreach{}
+	for i := len(st.breaches) - 1; i >= 0; i-- {
+		if intent != "" && !strings.EqualFold(st.breaches[i].Intent, intent) {
+			continue
+		}
+		breaches = append(breaches, st.breaches[i])
+		if limit > 0 && len(breaches) >= limit {
+			break
+		}
+	}
+	return breaches
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// holdForSplit checks a transfer against its rail's limits. One that is over them is
+// parked as AWAITING_CONFIRMATION with a split proposal, giving back its queue slot,
+// and a proposal left unconfirmed rejects it when it expires. When no split fits the
+// policy the task is rejected and the reason returned. Returns neither when the
+// transfer can run as it is.
+func (o *Orchestrator) holdForSplit(ctx context.Context, task *model.Task, decision *model.RoutingDecision) (*model.SplitProposal, string) {
+	reason := o.splits.Exceeds(task)
+	if reason == "" {
+		return nil, ""
+	}
+
+	proposal, err := o.splits.Propose(task, reason)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusRejected, nil, err.Error())
+		return nil, err.Error()
+	}
+
+	o.awaitingMu.Lock()
+	o.awaiting[task.TaskID] = decision
+	o.awaitingMu.Unlock()
+
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusAwaitingConfirmation, model.TaskStep{
+		Name:        "confirm_split",
+		Description: fmt.Sprintf("Waiting for the user to accept sending it as %d transfers", len(proposal.Legs)),
+	})
+	o.taskManager.SetSplit(ctx, task.TaskID, proposal)
+
+	time.AfterFunc(time.Until(proposal.ExpiresAt), func() {
+		o.closeSplit(context.Background(), proposal.SplitID, model.SplitExpired, "Split was not confirmed in time")
+	})
+	return proposal, ""
+}
+
+// ConfirmSplit accepts the split a transfer is waiting on. The transfer then goes
+// through step-up authentication for its whole amount, if the policy asks for it, and
+// runs its legs. Returns the task's new state.
+func (o *Orchestrator) ConfirmSplit(ctx context.Context, splitID string) (*model.TaskResponse, error) {
+	if err := o.queue.Admit(); err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			o.queue.Done()
+		}
+	}()
+
+	proposal, err := o.splits.Confirm(splitID)
+	if err != nil {
+		return nil, err
+	}
+
+	o.awaitingMu.Lock()
+	decision, ok := o.awaiting[proposal.TaskID]
+	delete(o.awaiting, proposal.TaskID)
+	o.awaitingMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: task %s is no longer waiting", ErrSplitClosed, proposal.TaskID)
+	}
+
+	o.taskManager.SetSplit(ctx, proposal.TaskID, proposal)
+	task, err := o.taskManager.GetTask(ctx, proposal.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("task_id", task.TaskID).Str("split_id", splitID).Msg("Split confirmed")
+
+	response := &model.TaskResponse{
+		TaskID:    task.TaskID,
+		SessionID: task.SessionID,
+		Status:    string(model.TaskStatusProcessing),
+		Message:   proposal.Message,
+		CreatedAt: task.CreatedAt,
+		Split:     proposal,
+	}
+
+	challenge, err := o.holdForAuth(ctx, task, decision)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		response.Status = string(model.TaskStatusAwaitingAuth)
+		response.Message = authPrompt(challenge)
+		response.AuthChallenge = challenge
+		return response, nil
+	}
+
+	started = true
+	go o.runTask(task, decision)
+	return response, nil
+}
+
+// DeclineSplit turns down the split a transfer is waiting on, which rejects it
+func (o *Orchestrator) DeclineSplit(ctx context.Context, splitID string) (*model.SplitProposal, error) {
+	proposal, ok := o.splits.Get(splitID)
+	if !ok {
+		return nil, ErrSplitNotFound
+	}
+	if !o.closeSplit(ctx, splitID, model.SplitDeclined, "Split declined by the user") {
+		return proposal, fmt.Errorf("%w: split %s is %s", ErrSplitClosed, splitID, proposal.Status)
+	}
+	proposal, _ = o.splits.Get(splitID)
+	return proposal, nil
+}
+
+// Split returns a split and how its legs went
+func (o *Orchestrator) Split(splitID string) (*model.SplitProposal, bool) {
+	return o.splits.Get(splitID)
+}
+
+// closeSplit rejects a task whose split was declined or expired before it was
+// confirmed. Returns false when it had already been confirmed or closed.
+func (o *Orchestrator) closeSplit(ctx context.Context, splitID, status, reason string) bool {
+	proposal, ok := o.splits.Close(splitID, status)
+	if !ok {
+		return false
+	}
+
+	o.awaitingMu.Lock()
+	delete(o.awaiting, proposal.TaskID)
+	o.awaitingMu.Unlock()
+
+	log.Info().Str("task_id", proposal.TaskID).Str("split_id", splitID).Str("status", status).Msg("Split closed unconfirmed")
+	o.taskManager.SetSplit(ctx, proposal.TaskID, proposal)
+	o.taskManager.UpdateTaskStatus(ctx, proposal.TaskID, model.TaskStatusRejected, nil, reason)
+	return true
+}
+
+// executeSplit runs a confirmed split as a saga. The checks the task was routed to run
+// once on the whole amount; then each leg is sent in order by a banking agent. A leg
+// due on a later day parks the task as SCHEDULED, without its queue slot or the user's
+// debit lock, and the split resumes then. A leg that fails or is rejected reverses the
+// legs sent before it, latest first, and the rest are not sent. Once every leg is
+// through, the task completes with the combined transfer and each leg's reference.
+func (o *Orchestrator) executeSplit(ctx context.Context, task *model.Task, decision *model.RoutingDecision, proposal *model.SplitProposal) {
+	calledAt := time.Now()
+	riskScore := 0.0
+	if !proposal.Checked {
+		outcome, ok := o.runSplitChecks(ctx, task, decision)
+		if !ok {
+			return
+		}
+		if outcome != nil {
+			riskScore = outcome.riskScore
+			if status, _ := outcome.result["status"].(string); status == "REJECTED" {
+				o.finishSplit(ctx, proposal, model.SplitRejected, "Transfer rejected before any part was sent")
+				sla := o.sla.Observe(task, string(outcome.agent.Type), o.routedAt(ctx, task), calledAt, time.Now(), outcome.diagnostics)
+				o.taskManager.UpdateTaskResult(ctx, task.TaskID, outcome.result, "", outcome.riskScore, outcome.explanation, outcome.diagnostics, sla)
+				return
+			}
+		}
+		proposal.Checked = true
+		o.splits.Update(proposal)
+	}
+
+	// A split resumed on a later day has no meaningful end-to-end latency
+	resumed := task.Status == model.TaskStatusScheduled
+	for i := range proposal.Legs {
+		leg := &proposal.Legs[i]
+		if leg.Status == model.SplitLegDone {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		if leg.ScheduledFor.After(time.Now()) {
+			o.scheduleSplit(ctx, task, proposal, i)
+			return
+		}
+
+		o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, model.TaskStep{
+			Name:        fmt.Sprintf("split_leg_%d", leg.Leg),
+			Description: fmt.Sprintf("Sending %s by %s (part %d of %d)", inr(leg.Amount), leg.Rail, leg.Leg, len(proposal.Legs)),
+			AgentType:   string(model.AgentTypeBanking),
+		})
+		if err := o.sendLeg(ctx, task, leg); err != nil {
+			o.failSplit(ctx, task, proposal, i, err)
+			return
+		}
+		o.splits.Update(proposal)
+		o.taskManager.SetSplit(ctx, task.TaskID, proposal)
+	}
+
+	o.finishSplit(ctx, proposal, model.SplitCompleted, "")
+	result := map[string]interface{}{
+		"status":          "APPROVED",
+		"transaction_id":  proposal.Legs[0].TransactionID,
+		"transaction_ids": legReferences(proposal),
+		"amount":          proposal.Amount,
+		"to_account":      task.Data["to_account"],
+		"message":         proposal.Message,
+		"processed_at":    time.Now(),
+		"split_id":        proposal.SplitID,
+		"legs":            proposal.Legs,
+	}
+	if task.Sandbox {
+		result["simulated"] = true
+	}
+	schema, err := conformResult(task.Intent, model.AgentTypeBanking, result)
+	if err != nil {
+		o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, err.Error())
+		return
+	}
+
+	var sla *model.TaskSLA
+	if !resumed {
+		sla = o.sla.Observe(task, string(model.AgentTypeBanking), o.routedAt(ctx, task), calledAt, time.Now(), nil)
+	}
+	explanation := proposal.Message
+	if task.Sandbox {
+		explanation = "[SIMULATED] " + explanation
+	}
+	if err := o.taskManager.UpdateTaskResult(ctx, task.TaskID, result, schema, riskScore, explanation, nil, sla); err != nil {
+		log.Error().Err(err).Str("task_id", task.TaskID).Msg("Failed to update task result")
+	}
+}
+
+// runSplitChecks runs the checks a split task was routed to on the whole amount: the
+// agent the router chose when it is not a banking agent, or the check steps of the
+// task's plan. Returns the outcome of the last check, a rejection if any, and false
+// when a check failed and the task with it.
+func (o *Orchestrator) runSplitChecks(ctx context.Context, task *model.Task, decision *model.RoutingDecision) (*planStepOutcome, bool) {
+	var checks []model.PlanStep
+	if decision.PlanID != "" {
+		if plan, err := o.plans.Get(decision.PlanID); err == nil {
+			for _, step := range plan.Steps {
+				if step.AgentType != string(model.AgentTypeBanking) {
+					checks = append(checks, step)
+				}
+			}
+		}
+	} else if decision.AgentType != "" && decision.AgentType != string(model.AgentTypeBanking) {
+		checks = append(checks, model.PlanStep{Name: agentSteps[model.AgentType(decision.AgentType)].name, AgentType: decision.AgentType})
+	}
+
+	var last *planStepOutcome
+	for _, check := range checks {
+		step := agentSteps[model.AgentType(check.AgentType)]
+		o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, model.TaskStep{
+			Name:        step.name,
+			Description: step.description,
+			AgentType:   check.AgentType,
+		})
+		outcome := o.runPlanStep(ctx, task, check, 1)
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		if outcome.err != nil {
+			o.finishSplit(ctx, o.currentSplit(task), model.SplitFailed, "Transfer checks failed; nothing was sent")
+			o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, outcome.err.Error())
+			return nil, false
+		}
+		o.diagnostics.Record(outcome.agent.Type, outcome.diagnostics)
+		if last == nil || outcome.riskScore >= last.riskScore {
+			last = outcome
+		}
+		if status, _ := outcome.result["status"].(string); status == "REJECTED" {
+			return outcome, true
+		}
+	}
+	return last, true
+}
+
+// sendLeg sends one leg of a split as a transfer of its own on its rail
+func (o *Orchestrator) sendLeg(ctx context.Context, task *model.Task, leg *model.SplitLeg) error {
+	tenantID := o.tenantOf(ctx, task)
+	agents, err := o.agentRegistry.FindAgentsForTenant(ctx, model.AgentTypeBanking, tenantID)
+	if err != nil || len(agents) == 0 {
+		leg.Status = model.SplitLegFailed
+		leg.Error = "no healthy BANKING agent available"
+		return fmt.Errorf("%s", leg.Error)
+	}
+
+	legTask := *task
+	legTask.Intent = leg.Intent
+	legTask.Data = make(map[string]interface{}, len(task.Data)+2)
+	for k, v := range task.Data {
+		legTask.Data[k] = v
+	}
+	legTask.Data["amount"] = leg.Amount
+	legTask.Data["split_leg"] = leg.Leg
+
+	done := o.agentRegistry.BeginTask(tenantID, agents[0])
+	result, _, _, diagnostics, err := o.callAgent(ctx, agents[0], agentRequest(&legTask, agents[0]))
+	done()
+	now := time.Now()
+	leg.ExecutedAt = &now
+	if err == nil {
+		o.diagnostics.Record(agents[0].Type, diagnostics)
+		if status, _ := result["status"].(string); status == "REJECTED" || status == "FAILED" {
+			reason, _ := result["reason"].(string)
+			err = fmt.Errorf("the bank returned %s %s", status, reason)
+		}
+	}
+	if err != nil {
+		leg.Status = model.SplitLegFailed
+		leg.Error = err.Error()
+		return err
+	}
+
+	leg.Status = model.SplitLegDone
+	leg.TransactionID, _ = result["transaction_id"].(string)
+	if !task.Sandbox {
+		o.splits.Record(task.UserID, leg.Intent, leg.Amount, now)
+	}
+	log.Info().Str("task_id", task.TaskID).Int("leg", leg.Leg).Str("rail", leg.Rail).Float64("amount", leg.Amount).Msg("Split leg sent")
+	return nil
+}
+
+// failSplit undoes the legs sent before the one that failed, latest first, skips the
+// rest and fails the task
+func (o *Orchestrator) failSplit(ctx context.Context, task *model.Task, proposal *model.SplitProposal, failed int, cause error) {
+	leg := proposal.Legs[failed]
+	reason := fmt.Sprintf("Part %d of %d (%s by %s) failed: %s", leg.Leg, len(proposal.Legs), inr(leg.Amount), leg.Rail, cause)
+
+	for i := failed + 1; i < len(proposal.Legs); i++ {
+		proposal.Legs[i].Status = model.SplitLegSkipped
+	}
+
+	status := model.SplitFailed
+	if failed > 0 {
+		o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusExecuting, model.TaskStep{
+			Name:        "compensate",
+			Description: "Reversing the parts already sent",
+		})
+		var reversed, stuck []string
+		for i := failed - 1; i >= 0; i-- {
+			sent := &proposal.Legs[i]
+			part := fmt.Sprintf("part %d", sent.Leg)
+			if err := o.reverseLeg(ctx, task, sent); err != nil {
+				sent.Error = "reversal failed: " + err.Error()
+				stuck = append(stuck, part)
+				log.Error().Err(err).Str("task_id", task.TaskID).Int("leg", sent.Leg).Msg("Split leg reversal failed")
+				continue
+			}
+			reversed = append(reversed, part)
+		}
+		if len(reversed) > 0 {
+			reason += "; reversed " + joinAnd(reversed)
+		}
+		if len(stuck) > 0 {
+			reason += "; could not reverse " + joinAnd(stuck)
+		} else {
+			status = model.SplitCompensated
+		}
+	}
+
+	log.Warn().Str("task_id", task.TaskID).Str("split_id", proposal.SplitID).Str("status", status).Msg(reason)
+	o.finishSplit(ctx, proposal, status, reason)
+	o.taskManager.UpdateTaskStatus(ctx, task.TaskID, model.TaskStatusFailed, nil, reason)
+}
+
+// reverseLeg asks a banking agent to reverse a leg that was sent
+func (o *Orchestrator) reverseLeg(ctx context.Context, task *model.Task, leg *model.SplitLeg) error {
+	tenantID := o.tenantOf(ctx, task)
+	agents, err := o.agentRegistry.FindAgentsForTenant(ctx, model.AgentTypeBanking, tenantID)
+	if err != nil || len(agents) == 0 {
+		return fmt.Errorf("no healthy BANKING agent available")
+	}
+
+	request := agentRequest(task, agents[0])
+	request["task"] = "REVERSE_TRANSFER"
+	inputCtx := request["input_context"].(map[string]interface{})
+	inputCtx["intent"] = "REVERSE_TRANSFER"
+	inputCtx["compensates"] = map[string]interface{}{
+		"step":   fmt.Sprintf("split_leg_%d", leg.Leg),
+		"intent": leg.Intent,
+		"result": map[string]interface{}{"transaction_id": leg.TransactionID, "amount": leg.Amount},
+	}
+
+	done := o.agentRegistry.BeginTask(tenantID, agents[0])
+	result, _, _, _, err := o.callAgent(ctx, agents[0], request)
+	done()
+	if err != nil {
+		return err
+	}
+	if status, _ := result["status"].(string); status == "REJECTED" || status == "FAILED" {
+		return fmt.Errorf("REVERSE_TRANSFER returned %s", status)
+	}
+	leg.Status = model.SplitLegReversed
+	leg.ReversalID, _ = result["reversal_id"].(string)
+	return nil
+}
+
+// scheduleSplit parks a split whose next leg is due on a later day and runs it again
+// then, once the queue admits it
+func (o *Orchestrator) scheduleSplit(ctx context.Context, task *model.Task, proposal *model.SplitProposal, next int) {
+	for i := next; i < len(proposal.Legs); i++ {
+		proposal.Legs[i].Status = model.SplitLegScheduled
+	}
+	due := proposal.Legs[next].ScheduledFor
+	proposal.Message = fmt.Sprintf("%s; %s", sentSoFar(proposal), o.splits.describeLegs(proposal.Legs[next:], time.Now()))
+	o.splits.Update(proposal)
+	o.taskManager.SetSplit(ctx, task.TaskID, proposal)
+	o.taskManager.StartStep(ctx, task.TaskID, model.TaskStatusScheduled, model.TaskStep{
+		Name:        "scheduled",
+		Description: fmt.Sprintf("Waiting until %s to send the rest", due.Format(time.RFC3339)),
+	})
+	log.Info().Str("task_id", task.TaskID).Str("split_id", proposal.SplitID).Time("due", due).Msg("Split waits for a later day")
+
+	decision := &model.RoutingDecision{SelectedAgentID: task.AgentID, AgentType: string(model.AgentTypeBanking)}
+	time.AfterFunc(time.Until(due), func() {
+		for o.queue.Admit() != nil {
+			time.Sleep(o.holds.PollInterval())
+		}
+		current, err := o.taskManager.GetTask(context.Background(), task.TaskID)
+		if err != nil || current.Status != model.TaskStatusScheduled {
+			o.queue.Done()
+			return
+		}
+		o.runTask(current, decision)
+	})
+}
+
+// finishSplit records how a split ended
+func (o *Orchestrator) finishSplit(ctx context.Context, proposal *model.SplitProposal, status, message string) {
+	if proposal == nil {
+		return
+	}
+	now := time.Now()
+	proposal.Status = status
+	proposal.FinishedAt = &now
+	if message == "" {
+		message = sentSoFar(proposal)
+	}
+	proposal.Message = message
+	o.splits.Update(proposal)
+	o.taskManager.SetSplit(ctx, proposal.TaskID, proposal)
+}
+
+// currentSplit returns the split a task runs as
+func (o *Orchestrator) currentSplit(task *model.Task) *model.SplitProposal {
+	proposal, _ := o.splits.ForTask(task.TaskID)
+	return proposal
+}
+
+// confirmedSplit returns the split a task runs as once the user has confirmed it
+func (o *Orchestrator) confirmedSplit(task *model.Task) (*model.SplitProposal, bool) {
+	proposal, ok := o.splits.ForTask(task.TaskID)
+	if !ok || proposal.Status != model.SplitConfirmed {
+		return nil, false
+	}
+	return proposal, true
+}
+
+// sentSoFar describes the legs sent, e.g. "Sent ₹3,00,000 in 2 transfers: ₹2,00,000
+// by IMPS (TXN_...) and ₹1,00,000 by UPI (TXN_...)"
+func sentSoFar(proposal *model.SplitProposal) string {
+	var parts []string
+	total := 0.0
+	for _, leg := range proposal.Legs {
+		if leg.Status == model.SplitLegDone {
+			parts = append(parts, fmt.Sprintf("%s by %s (%s)", inr(leg.Amount), leg.Rail, leg.TransactionID))
+			total += leg.Amount
+		}
+	}
+	if len(parts) == 0 {
+		return "Nothing sent yet"
+	}
+	return fmt.Sprintf("Sent %s in %d %s: %s", inr(total), len(parts), plural(len(parts), "transfer", "transfers"), joinAnd(parts))
+}
+
+// legReferences lists the transaction references of the legs sent
+func legReferences(proposal *model.SplitProposal) []string {
+	refs := make([]string, 0, len(proposal.Legs))
+	for _, leg := range proposal.Legs {
+		if leg.TransactionID != "" {
+			refs = append(refs, leg.TransactionID)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,672 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/aibanking/shared/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+// Step-up authentication errors
+var (
+	ErrChallengeNotFound = errors.New("auth challenge not found")
+	ErrChallengeMethod   = errors.New("auth challenge needs a different method")
+	ErrChallengeClosed   = errors.New("auth challenge is no longer pending")
+	ErrAuthFailed        = errors.New("authentication failed")
+	ErrEnrollmentProof   = errors.New("factor registration needs a verified enrollment challenge")
+	ErrFactorMissing     = errors.New("user has not registered that factor")
+	ErrNoRegisteredPhone = errors.New("user has no registered phone")
+	ErrPhoneLookup       = errors.New("registered phone lookup failed")
+	ErrOTPDelivery       = errors.New("OTP delivery failed")
+)
+
+// defaultAuthPolicy asks for more the riskier, larger or less familiar a transfer is.
+// The strongest method any matching rule requires wins.
+var defaultAuthPolicy = []model.AuthPolicyRule{
+	{Name: "unknown-device", DeviceTrust: []string{model.DeviceUnknown}, MinAmount: 1000, Method: model.AuthOTP},
+	{Name: "high-value", MinAmount: 50000, Method: model.AuthOTP},
+	{Name: "elevated-risk", MinRiskScore: 0.5, Method: model.AuthOTP},
+	{Name: "very-high-value", MinAmount: 200000, Method: model.AuthBiometric},
+	{Name: "high-risk", MinRiskScore: 0.7, Method: model.AuthBiometric},
+	{Name: "high-risk-high-value", MinRiskScore: 0.7, MinAmount: 100000, Method: model.AuthHardwareToken},
+	{Name: "net-banking-bulk", Channels: []string{"NB"}, MinAmount: 1000000, Method: model.AuthHardwareToken},
+}
+
+// Code lengths and the hardware token time step
+const (
+	otpLength      = 6
+	tokenLength    = 6
+	tokenStep      = 30 * time.Second
+	biometricNonce = 32
+)
+
+// StepUpAuth decides which authentication a money-moving task needs from its risk
+// score, amount, device trust and channel, issues the challenge for it and checks
+// the answer: a one-time code sent to the phone on the user's banking record, a
+// biometric assertion signed by a registered device or a hardware token code. A
+// factor is registered only with a verified enrollment challenge, answered with a
+// factor the user already has or an OTP.
+type StepUpAuth struct {
+	cfg           *config.StepUpConfig
+	rules         []model.AuthPolicyRule
+	challenges    map[string]*authChallenge
+	devices       map[string]map[string]*ecdsa.PublicKey // User ID -> device ID -> key
+	tokens        map[string]*hardwareToken              // Keyed by user ID
+	httpClient    *http.Client
+	bankingAPIKey *secrets.Value
+	mu            sync.Mutex
+}
+
+// authChallenge is an issued challenge with the secrets that answer it
+type authChallenge struct {
+	model.AuthChallenge
+	codeHash [32]byte // OTP challenges
+	nonce    []byte   // Biometric challenges
+}
+
+// hardwareToken is a user's TOTP token
+type hardwareToken struct {
+	secret   []byte
+	serial   string
+	lastStep int64 // A code is accepted once
+}
+
+// NewStepUpAuth creates the authenticator with the built-in policy or, when
+// configured, the rules in the policy file
+func NewStepUpAuth(cfg *config.StepUpConfig) (*StepUpAuth, error) {
+	sa := &StepUpAuth{
+		cfg:           cfg,
+		rules:         defaultAuthPolicy,
+		challenges:    make(map[string]*authChallenge),
+		devices:       make(map[string]map[string]*ecdsa.PublicKey),
+		tokens:        make(map[string]*hardwareToken),
+		httpClient:    &http.Client{Timeout: 5 * time.Second},
+		bankingAPIKey: config.Profile().RotatingSecret("STEPUP_BANKING_API_KEY", cfg.BankingAPIKey),
+	}
+
+	if cfg.PolicyFile != "" {
+		data, err := os.ReadFile(cfg.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read auth policy: %w", err)
+		}
+		var rules []model.AuthPolicyRule
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, fmt.Errorf("invalid auth policy file: %w", err)
+		}
+		for i, rule := range rules {
+			if rule.Name == "" || !rule.Method.Valid() {
+				return nil, fmt.Errorf("rules[%d]: name and a method of NONE, OTP, BIOMETRIC or HARDWARE_TOKEN are required", i)
+			}
+		}
+		sa.rules = rules
+		log.Info().Str("file", cfg.PolicyFile).Int("rules", len(rules)).Msg("Step-up auth policy loaded")
+	}
+
+	return sa, nil
+}
+
+// Enabled reports whether tasks are checked for step-up authentication
+func (sa *StepUpAuth) Enabled() bool {
+	return sa.cfg.Enabled
+}
+
+// Policy returns the rules in force
+func (sa *StepUpAuth) Policy() []model.AuthPolicyRule {
+	return append([]model.AuthPolicyRule{}, sa.rules...)
+}
+
+// DeviceTrust rates the device a task came from. A device registered for biometric
+// sign-in is trusted. Otherwise a device with a history takes the level its profile
+// earned, and the client's own rating is used only for devices the server has not
+// profiled. A client can mark a device UNKNOWN but cannot vouch for it as trusted, and
+// a device nothing is known about counts as KNOWN, since there is no sign it is new.
+func (sa *StepUpAuth) DeviceTrust(userID, deviceID, claimed, profiled string) string {
+	sa.mu.Lock()
+	_, registered := sa.devices[userID][deviceID]
+	sa.mu.Unlock()
+
+	switch {
+	case deviceID != "" && registered:
+		return model.DeviceTrusted
+	case strings.ToUpper(claimed) == model.DeviceUnknown:
+		return model.DeviceUnknown
+	case profiled != "":
+		return profiled
+	}
+	return model.DeviceKnown
+}
+
+// Decide returns the method a task needs: the strongest any matching rule requires.
+// A user who has not registered what that method needs is given the next method
+// they can use, a stronger one where possible.
+func (sa *StepUpAuth) Decide(userID, deviceID string, input model.AuthInput) model.AuthDecision {
+	decision := model.AuthDecision{Method: model.AuthNone, Input: input}
+	for _, rule := range sa.rules {
+		if !ruleMatches(rule, input) {
+			continue
+		}
+		decision.Rules = append(decision.Rules, rule.Name)
+		if rule.Method.Stronger(decision.Method) {
+			decision.Method = rule.Method
+		}
+	}
+
+	sa.mu.Lock()
+	hasDevice := len(sa.devices[userID]) > 0 && (deviceID == "" || sa.devices[userID][deviceID] != nil)
+	hasToken := sa.tokens[userID] != nil
+	sa.mu.Unlock()
+
+	wanted := decision.Method
+	switch {
+	case wanted == model.AuthBiometric && !hasDevice && hasToken:
+		decision.Method = model.AuthHardwareToken
+	case wanted == model.AuthBiometric && !hasDevice:
+		decision.Method = model.AuthOTP
+	case wanted == model.AuthHardwareToken && !hasToken && hasDevice:
+		decision.Method = model.AuthBiometric
+	case wanted == model.AuthHardwareToken && !hasToken:
+		decision.Method = model.AuthOTP
+	}
+	if decision.Method != wanted {
+		decision.FallbackFrom = wanted
+	}
+	return decision
+}
+
+// ruleMatches reports whether the input meets every condition the rule sets
+func ruleMatches(rule model.AuthPolicyRule, input model.AuthInput) bool {
+	if input.RiskScore < rule.MinRiskScore || input.Amount < rule.MinAmount {
+		return false
+	}
+	if len(rule.DeviceTrust) > 0 && !containsFold(rule.DeviceTrust, input.DeviceTrust) {
+		return false
+	}
+	if len(rule.Channels) > 0 && !containsFold(rule.Channels, input.Channel) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether list holds value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Issue creates the challenge for a task. OTPs are sent to the phone on the user's
+// banking record; in sandbox, or with development echo on, the code is also returned.
+func (sa *StepUpAuth) Issue(ctx context.Context, task *model.Task, decision model.AuthDecision, deviceID string) (*model.AuthChallenge, error) {
+	ch := sa.newChallenge(task.UserID, decision)
+	ch.TaskID = task.TaskID
+	if err := sa.open(ctx, ch, deviceID, task.Sandbox || sa.cfg.DevEchoOTP); err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("challenge_id", ch.ChallengeID).Str("task_id", task.TaskID).Str("method", string(ch.Method)).
+		Strs("rules", decision.Rules).Str("fallback_from", string(decision.FallbackFrom)).Msg("Step-up authentication required")
+
+	challengeCopy := ch.AuthChallenge
+	return &challengeCopy, nil
+}
+
+// Enroll creates the challenge a user answers before registering a factor: with the
+// method asked for, which must be OTP or a factor they have, or otherwise their
+// strongest factor, or an OTP when they have none
+func (sa *StepUpAuth) Enroll(ctx context.Context, req *model.AuthEnrollmentRequest) (*model.AuthChallenge, error) {
+	if req.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	sa.mu.Lock()
+	hasDevice := len(sa.devices[req.UserID]) > 0
+	hasToken := sa.tokens[req.UserID] != nil
+	sa.mu.Unlock()
+
+	method := req.Method
+	switch {
+	case method == "" && hasToken:
+		method = model.AuthHardwareToken
+	case method == "" && hasDevice:
+		method = model.AuthBiometric
+	case method == "":
+		method = model.AuthOTP
+	case method == model.AuthHardwareToken && !hasToken, method == model.AuthBiometric && !hasDevice:
+		return nil, fmt.Errorf("%w: %s", ErrFactorMissing, method)
+	case method != model.AuthOTP && method != model.AuthHardwareToken && method != model.AuthBiometric:
+		return nil, fmt.Errorf("method must be OTP, BIOMETRIC or HARDWARE_TOKEN")
+	}
+
+	ch := sa.newChallenge(req.UserID, model.AuthDecision{Method: method, Rules: []string{"factor-enrollment"}})
+	ch.Purpose = model.ChallengeEnrollment
+	if err := sa.open(ctx, ch, req.DeviceID, sa.cfg.DevEchoOTP); err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("challenge_id", ch.ChallengeID).Str("user_id", req.UserID).Str("method", string(method)).
+		Msg("Factor enrollment challenge issued")
+
+	challengeCopy := ch.AuthChallenge
+	return &challengeCopy, nil
+}
+
+// newChallenge starts a pending challenge for a user
+func (sa *StepUpAuth) newChallenge(userID string, decision model.AuthDecision) *authChallenge {
+	now := time.Now()
+	ch := &authChallenge{AuthChallenge: model.AuthChallenge{
+		ChallengeID:  ids.Ref(ids.Challenge),
+		UserID:       userID,
+		Method:       decision.Method,
+		Status:       model.ChallengePending,
+		Decision:     decision,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Duration(sa.cfg.ChallengeTTLSeconds) * time.Second),
+		AttemptsLeft: sa.cfg.MaxAttempts,
+	}}
+	ch.VerifyPath = fmt.Sprintf("/api/v1/auth/challenges/%s/%s", ch.ChallengeID, verifySegment(decision.Method))
+	return ch
+}
+
+// open sends or prepares what answers a challenge and keeps it. echo returns an OTP
+// in the challenge. A challenge whose OTP could not be sent is not kept.
+func (sa *StepUpAuth) open(ctx context.Context, ch *authChallenge, deviceID string, echo bool) error {
+	switch ch.Method {
+	case model.AuthOTP:
+		phone, err := sa.registeredPhone(ctx, ch.UserID)
+		if err != nil {
+			return err
+		}
+		code, err := randomDigits(otpLength)
+		if err != nil {
+			return err
+		}
+		if err := sa.sendOTP(ctx, ch.UserID, code); err != nil {
+			return err
+		}
+		ch.codeHash = sha256.Sum256([]byte(code))
+		ch.CodeLength = otpLength
+		ch.OTPDestination = maskPhone(phone)
+		if echo {
+			ch.SandboxCode = code
+		}
+		log.Info().Str("challenge_id", ch.ChallengeID).Str("user_id", ch.UserID).
+			Str("destination", ch.OTPDestination).Msg("Step-up OTP sent")
+	case model.AuthBiometric:
+		ch.nonce = make([]byte, biometricNonce)
+		if _, err := rand.Read(ch.nonce); err != nil {
+			return fmt.Errorf("failed to create nonce: %w", err)
+		}
+		ch.Nonce = base64.StdEncoding.EncodeToString(ch.nonce)
+		sa.mu.Lock()
+		if sa.devices[ch.UserID][deviceID] != nil {
+			ch.DeviceID = deviceID
+		}
+		sa.mu.Unlock()
+	case model.AuthHardwareToken:
+		ch.CodeLength = tokenLength
+	}
+
+	sa.mu.Lock()
+	sa.challenges[ch.ChallengeID] = ch
+	sa.mu.Unlock()
+	return nil
+}
+
+// registeredPhone reads the user's phone from their profile in the banking
+// integration. The phone a client reports is never used: an OTP sent there proves
+// nothing.
+func (sa *StepUpAuth) registeredPhone(ctx context.Context, userID string) (string, error) {
+	data, err := json.Marshal(map[string]string{"query_type": "USER_PROFILE", "user_id": userID})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(sa.cfg.BankingURL, "/") + "/api/v1/dwh/query"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", sa.bankingAPIKey.Get())
+
+	resp, err := sa.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPhoneLookup, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%w: user profile returned %d: %s", ErrPhoneLookup, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	var profile struct {
+		Data []struct {
+			Phone string `json:"phone"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return "", fmt.Errorf("%w: failed to decode user profile: %v", ErrPhoneLookup, err)
+	}
+	if len(profile.Data) == 0 || profile.Data[0].Phone == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoRegisteredPhone, userID)
+	}
+	return profile.Data[0].Phone, nil
+}
+
+// sendOTP delivers a one-time code to the user through the banking integration's
+// notifications, which reach them on their registered channel
+func (sa *StepUpAuth) sendOTP(ctx context.Context, userID, code string) error {
+	data, err := json.Marshal(map[string]string{
+		"user_id":  userID,
+		"category": "SECURITY",
+		"subject":  "Your verification code",
+		"body":     fmt.Sprintf("%s is your verification code. It expires in %d minutes. Never share it with anyone.", code, (sa.cfg.ChallengeTTLSeconds+59)/60),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(sa.cfg.BankingURL, "/") + "/api/v1/notifications"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", sa.bankingAPIKey.Get())
+
+	resp, err := sa.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOTPDelivery, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: notification returned %d: %s", ErrOTPDelivery, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// verifySegment names the verification endpoint of a method
+func verifySegment(method model.AuthMethod) string {
+	switch method {
+	case model.AuthBiometric:
+		return "biometric"
+	case model.AuthHardwareToken:
+		return "token"
+	}
+	return "otp"
+}
+
+// Get returns a challenge
+func (sa *StepUpAuth) Get(challengeID string) (*model.AuthChallenge, bool) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	ch, ok := sa.challenges[challengeID]
+	if !ok {
+		return nil, false
+	}
+	sa.expire(ch)
+	challengeCopy := ch.AuthChallenge
+	return &challengeCopy, true
+}
+
+// expire marks a pending challenge expired once its time is up. Callers hold sa.mu.
+func (sa *StepUpAuth) expire(ch *authChallenge) bool {
+	if ch.Status == model.ChallengePending && !time.Now().Before(ch.ExpiresAt) {
+		ch.Status = model.ChallengeExpired
+		return true
+	}
+	return false
+}
+
+// Verify checks the answer to a challenge. A wrong answer uses up an attempt, and
+// the last failed attempt closes the challenge as FAILED. The challenge is returned
+// with its new state whether or not the answer was right.
+func (sa *StepUpAuth) Verify(challengeID string, method model.AuthMethod, req *model.AuthVerifyRequest) (*model.AuthChallenge, error) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	ch, ok := sa.challenges[challengeID]
+	if !ok {
+		return nil, ErrChallengeNotFound
+	}
+	sa.expire(ch)
+	if ch.Status != model.ChallengePending {
+		challengeCopy := ch.AuthChallenge
+		return &challengeCopy, fmt.Errorf("%w: %s", ErrChallengeClosed, ch.Status)
+	}
+	if ch.Method != method {
+		challengeCopy := ch.AuthChallenge
+		return &challengeCopy, fmt.Errorf("%w: %s", ErrChallengeMethod, ch.Method)
+	}
+
+	var err error
+	switch method {
+	case model.AuthOTP:
+		hash := sha256.Sum256([]byte(strings.TrimSpace(req.Code)))
+		if subtle.ConstantTimeCompare(hash[:], ch.codeHash[:]) != 1 {
+			err = fmt.Errorf("%w: wrong code", ErrAuthFailed)
+		}
+	case model.AuthBiometric:
+		err = sa.verifyAssertion(ch, req)
+	case model.AuthHardwareToken:
+		err = sa.verifyTokenCode(ch.UserID, strings.TrimSpace(req.Code))
+	}
+
+	if err != nil {
+		ch.AttemptsLeft--
+		if ch.AttemptsLeft <= 0 {
+			ch.Status = model.ChallengeFailed
+		}
+		log.Warn().Err(err).Str("challenge_id", ch.ChallengeID).Int("attempts_left", ch.AttemptsLeft).Msg("Step-up authentication attempt failed")
+		challengeCopy := ch.AuthChallenge
+		return &challengeCopy, err
+	}
+
+	now := time.Now()
+	ch.Status = model.ChallengeVerified
+	ch.VerifiedAt = &now
+	log.Info().Str("challenge_id", ch.ChallengeID).Str("task_id", ch.TaskID).Str("method", string(method)).Msg("Step-up authentication verified")
+
+	challengeCopy := ch.AuthChallenge
+	return &challengeCopy, nil
+}
+
+// verifyAssertion checks a biometric assertion: the device's signature over the
+// challenge nonce, made once the user passed the device's biometric check.
+// Callers hold sa.mu.
+func (sa *StepUpAuth) verifyAssertion(ch *authChallenge, req *model.AuthVerifyRequest) error {
+	if ch.DeviceID != "" && req.DeviceID != ch.DeviceID {
+		return fmt.Errorf("%w: assertion must come from device %s", ErrAuthFailed, ch.DeviceID)
+	}
+	key := sa.devices[ch.UserID][req.DeviceID]
+	if key == nil {
+		return fmt.Errorf("%w: device %q is not registered", ErrAuthFailed, req.DeviceID)
+	}
+	signature, err := base64.StdEncoding.DecodeString(req.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64", ErrAuthFailed)
+	}
+	digest := sha256.Sum256(ch.nonce)
+	if !ecdsa.VerifyASN1(key, digest[:], signature) {
+		return fmt.Errorf("%w: signature does not verify", ErrAuthFailed)
+	}
+	return nil
+}
+
+// verifyTokenCode checks a hardware token code for the current time step or the one
+// either side of it, and refuses a code from a step already used. Callers hold sa.mu.
+func (sa *StepUpAuth) verifyTokenCode(userID, code string) error {
+	token := sa.tokens[userID]
+	if token == nil {
+		return fmt.Errorf("%w: no hardware token registered", ErrAuthFailed)
+	}
+
+	current := time.Now().Unix() / int64(tokenStep/time.Second)
+	for step := current - 1; step <= current+1; step++ {
+		if step <= token.lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(token.secret, step)), []byte(code)) == 1 {
+			token.lastStep = step
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: wrong token code", ErrAuthFailed)
+}
+
+// totpCode is the RFC 6238 code of a secret for a time step (HMAC-SHA1, six digits)
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// RegisterDevice registers a device's public key for biometric assertions, with a
+// verified enrollment challenge of the user as proof
+func (sa *StepUpAuth) RegisterDevice(reg *model.AuthDeviceRegistration) error {
+	if reg.UserID == "" || reg.DeviceID == "" {
+		return fmt.Errorf("user_id and device_id are required")
+	}
+	der, err := base64.StdEncoding.DecodeString(reg.PublicKey)
+	if err != nil {
+		return fmt.Errorf("public_key is not base64: %w", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("public_key is not a PKIX public key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PublicKey)
+	if !ok || key.Curve != elliptic.P256() {
+		return fmt.Errorf("public_key must be an ECDSA P-256 key")
+	}
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	if err := sa.useEnrollment(reg.UserID, reg.ChallengeID); err != nil {
+		return err
+	}
+	if sa.devices[reg.UserID] == nil {
+		sa.devices[reg.UserID] = make(map[string]*ecdsa.PublicKey)
+	}
+	sa.devices[reg.UserID][reg.DeviceID] = key
+	log.Info().Str("user_id", reg.UserID).Str("device_id", reg.DeviceID).Msg("Biometric device registered")
+	return nil
+}
+
+// RegisterToken registers a user's hardware token, replacing any earlier one, with a
+// verified enrollment challenge of the user as proof. A user replacing a token they
+// have lost proves themselves with an OTP.
+func (sa *StepUpAuth) RegisterToken(reg *model.AuthTokenRegistration) error {
+	if reg.UserID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(strings.TrimRight(reg.Secret, "=")))
+	if err != nil || len(secret) < 10 {
+		return fmt.Errorf("secret must be a base32 seed of at least 80 bits")
+	}
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	if err := sa.useEnrollment(reg.UserID, reg.ChallengeID); err != nil {
+		return err
+	}
+	sa.tokens[reg.UserID] = &hardwareToken{secret: secret, serial: reg.Serial}
+	log.Info().Str("user_id", reg.UserID).Str("serial", reg.Serial).Msg("Hardware token registered")
+	return nil
+}
+
+// useEnrollment spends a user's verified enrollment challenge, so each registers one
+// factor. Callers hold sa.mu.
+func (sa *StepUpAuth) useEnrollment(userID, challengeID string) error {
+	ch, ok := sa.challenges[challengeID]
+	if challengeID == "" || !ok || ch.Purpose != model.ChallengeEnrollment || ch.UserID != userID {
+		return fmt.Errorf("%w: challenge_id must name one of the user's enrollment challenges", ErrEnrollmentProof)
+	}
+	if ch.Status != model.ChallengeVerified {
+		return fmt.Errorf("%w: challenge is %s", ErrEnrollmentProof, ch.Status)
+	}
+	ch.Status = model.ChallengeUsed
+	return nil
+}
+
+// Factors lists what a user has registered
+func (sa *StepUpAuth) Factors(userID string) *model.AuthFactors {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	factors := &model.AuthFactors{UserID: userID, Devices: make([]string, 0)}
+	for deviceID := range sa.devices[userID] {
+		factors.Devices = append(factors.Devices, deviceID)
+	}
+	sort.Strings(factors.Devices)
+	if token := sa.tokens[userID]; token != nil {
+		factors.HasToken = true
+		factors.TokenSerial = token.serial
+	}
+	return factors
+}
+
+// randomDigits returns n random decimal digits
+func randomDigits(n int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("failed to create code: %w", err)
+		}
+		b.WriteByte(byte('0' + digit.Int64()))
+	}
+	return b.String(), nil
+}
+
+// maskPhone shows only the last four digits of a phone number
+func maskPhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) < 4 {
+		return "registered mobile number"
+	}
+	return "XXXXXX" + digits[len(digits)-4:]
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrTaskNotCancellable is returned for a task that has finished, or a transfer an
+// agent is already carrying out
+var ErrTaskNotCancellable = errors.New("task cannot be cancelled")
+
+// runningTask is the execution of a task that can be aborted
+type runningTask struct {
+	cancel context.CancelFunc
+}
+
+// trackTask gives a task's execution a context that CancelTask aborts, and returns it
+// with the function that stops tracking it. A later execution of the same task, such
+// as a held task being run once released, replaces the earlier one.
+func (o *Orchestrator) trackTask(taskID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &runningTask{cancel: cancel}
+
+	o.runningMu.Lock()
+	o.running[taskID] = run
+	o.runningMu.Unlock()
+
+	return ctx, func() {
+		o.runningMu.Lock()
+		if o.running[taskID] == run {
+			delete(o.running, taskID)
+		}
+		o.runningMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelTask stops a task whose client no longer waits for it. A task that has not
+// reached its agent is dropped wherever it waits: in the queue, on hold for an agent,
+// at step-up authentication or behind an earlier transfer. A task an agent is working
+// on is aborted unless it moves money, since the transfer may already be under way.
+func (o *Orchestrator) CancelTask(ctx context.Context, taskID, reason string) (*model.Task, error) {
+	task, err := o.taskManager.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	stage := strings.ToLower(string(task.Status))
+	if task.Status.IsTerminal() {
+		return nil, fmt.Errorf("%w: task %s is %s", ErrTaskNotCancellable, taskID, task.Status)
+	}
+	if task.Status == model.TaskStatusExecuting && isDebitIntent(task.Intent) {
+		o.cancelMu.Lock()
+		o.cancelRefused++
+		o.cancelMu.Unlock()
+		return nil, fmt.Errorf("%w: task %s is a transfer already being executed", ErrTaskNotCancellable, taskID)
+	}
+
+	if task.Split != nil && sentAny(o.currentSplit(task)) {
+		o.cancelMu.Lock()
+		o.cancelRefused++
+		o.cancelMu.Unlock()
+		return nil, fmt.Errorf("%w: task %s is a split transfer with parts already sent", ErrTaskNotCancellable, taskID)
+	}
+
+	if reason == "" {
+		reason = "Cancelled by client"
+	}
+	if err := o.taskManager.UpdateTaskStatus(ctx, taskID, model.TaskStatusCancelled, nil, reason); err != nil {
+		return nil, err
+	}
+
+	// Whatever the task waits on lets it go; its own goroutine sees the cancelled
+	// context and returns without touching the task
+	o.runningMu.Lock()
+	if run, ok := o.running[taskID]; ok {
+		run.cancel()
+	}
+	o.runningMu.Unlock()
+
+	if hold := o.holds.Release(taskID, model.HoldCancelled); hold != nil {
+		o.taskManager.SetHold(ctx, taskID, hold)
+	}
+
+	o.awaitingMu.Lock()
+	delete(o.awaiting, taskID)
+	o.awaitingMu.Unlock()
+
+	if proposal := o.currentSplit(task); proposal != nil {
+		if closed, ok := o.splits.Close(proposal.SplitID, model.SplitDeclined); ok {
+			o.taskManager.SetSplit(ctx, taskID, closed)
+		} else if proposal.Status == model.SplitConfirmed {
+			o.finishSplit(ctx, proposal, model.SplitDeclined, "Split cancelled; nothing was sent")
+		}
+	}
+
+	o.cancelMu.Lock()
+	o.cancelled[stage]++
+	o.cancelMu.Unlock()
+
+	log.Info().
+		Str("task_id", taskID).
+		Str("intent", task.Intent).
+		Str("stage", stage).
+		Str("reason", reason).
+		Msg("Task cancelled")
+
+	return o.taskManager.GetTask(ctx, taskID)
+}
+
+// CancellationStats counts cancelled tasks by the stage they were cancelled in
+func (o *Orchestrator) CancellationStats() *model.CancellationStats {
+	o.cancelMu.Lock()
+	defer o.cancelMu.Unlock()
+
+	stats := &model.CancellationStats{
+		ByStage: make(map[string]int64, len(o.cancelled)),
+		Refused: o.cancelRefused,
+	}
+	for stage, n := range o.cancelled {
+		stats.ByStage[stage] = n
+		stats.Total += n
+	}
+	return stats
+}
+
+// sentAny reports whether any leg of a split has been sent
+func sentAny(proposal *model.SplitProposal) bool {
+	if proposal == nil {
+		return false
+	}
+	for _, leg := range proposal.Legs {
+		if leg.Status == model.SplitLegDone || leg.Status == model.SplitLegReversed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// TaskCompactor keeps the task manager's memory bounded by evicting finished tasks
+// once they are older than TASK_COMPACTION_AGE_MINUTES. Evicted tasks are still
+// served from Redis until their TTL.
+type TaskCompactor struct {
+	cfg         *config.CompactionConfig
+	taskManager *TaskManager
+	held        func(userID string) bool // Users on legal hold, whose tasks are kept
+}
+
+// NewTaskCompactor creates a task compactor
+func NewTaskCompactor(cfg *config.CompactionConfig, taskManager *TaskManager, held func(userID string) bool) *TaskCompactor {
+	return &TaskCompactor{
+		cfg:         cfg,
+		taskManager: taskManager,
+		held:        held,
+	}
+}
+
+// Run compacts every TASK_COMPACTION_INTERVAL_SECONDS until ctx is done
+func (tc *TaskCompactor) Run(ctx context.Context) {
+	interval := time.Duration(tc.cfg.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	log.Info().Dur("interval", interval).Int("age_minutes", tc.cfg.AgeMinutes).Msg("Task compaction started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tc.Compact()
+		}
+	}
+}
+
+// Compact evicts the finished tasks past their age and returns how many it evicted
+func (tc *TaskCompactor) Compact() int {
+	cutoff := time.Now().Add(-time.Duration(tc.cfg.AgeMinutes) * time.Minute)
+	evicted := tc.taskManager.Compact(cutoff, tc.held)
+	if evicted > 0 {
+		stats := tc.taskManager.MemoryStats()
+		log.Info().Int("evicted", evicted).Int("in_memory", stats.Tasks).Msg("Finished tasks compacted out of memory")
+	}
+	return evicted
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/aibanking/mcp-server/internal/model"
-	"github.com/aibanking/mcp-server/internal/utils"
+	"github.com/aibanking/shared/ids"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
@@ -20,14 +22,29 @@ type TaskManager struct {
 	tasks          map[string]*model.Task // In-memory fallback
 	mu             sync.RWMutex
 	ttl            time.Duration
+	instance       string                   // Stamped on the tasks this instance runs
+	durations      map[string]time.Duration // Moving average of execution time per intent
+	subscribers    map[string]map[chan struct{}]bool
+	subMu          sync.Mutex
+	evicted        int64      // Finished tasks compacted out of memory
+	lastCompaction *time.Time // Guarded by mu
 }
 
+// ErrTaskCancelled is returned for updates to a cancelled task, which keeps its state
+// however late the work it was doing finishes
+var ErrTaskCancelled = errors.New("task was cancelled")
+
+// durationSmoothing is the weight of the latest task in the per-intent moving average
+const durationSmoothing = 0.2
+
 // NewTaskManager creates a new task manager instance
 func NewTaskManager(redisClient *redis.Client) *TaskManager {
 	tm := &TaskManager{
 		redisClient: redisClient,
 		tasks:       make(map[string]*model.Task),
 		ttl:         7 * 24 * time.Hour, // 7 days TTL for tasks
+		durations:   make(map[string]time.Duration),
+		subscribers: make(map[string]map[chan struct{}]bool),
 	}
 
 	// Check Redis availability
@@ -49,7 +66,7 @@ func NewTaskManager(redisClient *redis.Client) *TaskManager {
 
 // CreateTask creates a new task from a request
 func (tm *TaskManager) CreateTask(ctx context.Context, req *model.TaskRequest, sessionID string) (*model.Task, error) {
-	taskID := utils.GenerateTaskID()
+	taskID := ids.New(ids.Task)
 
 	task := &model.Task{
 		TaskID:    taskID,
@@ -60,6 +77,9 @@ func (tm *TaskManager) CreateTask(ctx context.Context, req *model.TaskRequest, s
 		Status:    model.TaskStatusPending,
 		Data:      req.Data,
 		Context:   req.Context,
+		Sandbox:   req.Sandbox,
+		Stateless: req.Stateless,
+		Instance:  tm.instance,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -135,6 +155,9 @@ func (tm *TaskManager) UpdateTaskStatus(ctx context.Context, taskID string, stat
 	if err != nil {
 		return err
 	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrTaskCancelled
+	}
 
 	task.Status = status
 	task.UpdatedAt = time.Now()
@@ -147,9 +170,14 @@ func (tm *TaskManager) UpdateTaskStatus(ctx context.Context, taskID string, stat
 		task.Error = errorMsg
 	}
 
-	if status == model.TaskStatusCompleted || status == model.TaskStatusFailed || status == model.TaskStatusRejected {
+	if status.IsTerminal() {
 		now := time.Now()
 		task.CompletedAt = &now
+		stepStatus := model.StepDone
+		if status != model.TaskStatusCompleted {
+			stepStatus = model.StepFailed
+		}
+		tm.finishStep(task, stepStatus, now)
 	}
 
 	// Save to Redis (if available)
@@ -165,6 +193,7 @@ func (tm *TaskManager) UpdateTaskStatus(ctx context.Context, taskID string, stat
 	tm.tasks[taskID] = task
 	tm.mu.Unlock()
 
+	tm.notify(taskID)
 	return nil
 }
 
@@ -174,6 +203,9 @@ func (tm *TaskManager) UpdateTaskAgent(ctx context.Context, taskID, agentID stri
 	if err != nil {
 		return err
 	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrTaskCancelled
+	}
 
 	task.AgentID = agentID
 	task.Status = model.TaskStatusProcessing
@@ -192,23 +224,35 @@ func (tm *TaskManager) UpdateTaskAgent(ctx context.Context, taskID, agentID stri
 	tm.tasks[taskID] = task
 	tm.mu.Unlock()
 
+	tm.notify(taskID)
 	return nil
 }
 
-// UpdateTaskResult updates task with final result, risk score, and explanation
-func (tm *TaskManager) UpdateTaskResult(ctx context.Context, taskID string, result map[string]interface{}, riskScore float64, explanation string) error {
+// UpdateTaskResult updates task with final result, risk score, explanation, the
+// agent's diagnostics and the task's SLA measurement
+func (tm *TaskManager) UpdateTaskResult(ctx context.Context, taskID string, result map[string]interface{}, schema string, riskScore float64, explanation string, diagnostics *model.AgentDiagnostics, sla *model.TaskSLA) error {
 	task, err := tm.GetTask(ctx, taskID)
 	if err != nil {
 		return err
 	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrTaskCancelled
+	}
+
+	// UpdatedAt was set when the task was handed to its agent
+	tm.recordDuration(task.Intent, time.Since(task.UpdatedAt))
 
 	task.Result = result
+	task.ResultSchema = schema
 	task.RiskScore = riskScore
 	task.Explanation = explanation
+	task.Diagnostics = diagnostics
+	task.SLA = sla
 	task.Status = model.TaskStatusCompleted
 	now := time.Now()
 	task.CompletedAt = &now
 	task.UpdatedAt = now
+	tm.finishStep(task, model.StepDone, now)
 
 	// Save to Redis (if available)
 	if tm.redisAvailable {
@@ -223,11 +267,643 @@ func (tm *TaskManager) UpdateTaskResult(ctx context.Context, taskID string, resu
 	tm.tasks[taskID] = task
 	tm.mu.Unlock()
 
+	tm.notify(taskID)
+	return nil
+}
+
+// ResetTask clears the outcome of a task and returns it to PENDING
+func (tm *TaskManager) ResetTask(ctx context.Context, taskID string) (*model.Task, error) {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Status = model.TaskStatusPending
+	task.Instance = tm.instance
+	task.Result = nil
+	task.ResultSchema = ""
+	task.Error = ""
+	task.RiskScore = 0
+	task.Explanation = ""
+	task.Diagnostics = nil
+	task.SLA = nil
+	task.AuthChallenge = nil
+	task.Hold = nil
+	task.PlanRun = nil
+	task.CompletedAt = nil
+	task.UpdatedAt = time.Now()
+	tm.mu.Lock()
+	task.Progress = nil
+	tm.mu.Unlock()
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task reset to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return task, nil
+}
+
+// SetAuthChallenge records the step-up challenge a task waits on, or its new state
+func (tm *TaskManager) SetAuthChallenge(ctx context.Context, taskID string, challenge *model.AuthChallenge) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	task.AuthChallenge = challenge
+	task.UpdatedAt = time.Now()
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task challenge to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return nil
+}
+
+// SetHold records that a task waits for an agent, or how its wait ended
+func (tm *TaskManager) SetHold(ctx context.Context, taskID string, hold *model.TaskHold) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	task.Hold = hold
+	task.UpdatedAt = time.Now()
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task hold to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return nil
+}
+
+// SetHandover records that a task was left unfinished by a stopped instance, or what
+// the instance that picked it up did with it
+func (tm *TaskManager) SetHandover(ctx context.Context, taskID string, handover *model.TaskHandover) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	task.Handover = handover
+	task.UpdatedAt = time.Now()
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task handover to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return nil
+}
+
+// SetSplit records the split a transfer was offered as, or how its legs are going
+func (tm *TaskManager) SetSplit(ctx context.Context, taskID string, proposal *model.SplitProposal) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	task.Split = proposal
+	task.UpdatedAt = time.Now()
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task split to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return nil
+}
+
+// SetPlanRun records how the orchestration plan executing a task is going
+func (tm *TaskManager) SetPlanRun(ctx context.Context, taskID string, run *model.PlanRun) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	copied := *run
+	copied.Steps = append([]model.PlanStepRun(nil), run.Steps...)
+	// UpdatedAt is left alone: it times the agent work the plan run is part of
+	task.PlanRun = &copied
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task plan run to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	// Always update in memory
+	tm.mu.Lock()
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	tm.notify(taskID)
+	return nil
+}
+
+// StartStep moves a task into a new stage of execution: any running step is finished,
+// the new step is started and the task takes the given status
+func (tm *TaskManager) StartStep(ctx context.Context, taskID string, status model.TaskStatus, step model.TaskStep) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrTaskCancelled
+	}
+
+	now := time.Now()
+	step.Status = model.StepRunning
+	step.StartedAt = now
+
+	tm.finishStep(task, model.StepDone, now)
+	tm.mu.Lock()
+	task.Progress = append(task.Progress, step)
+	tm.mu.Unlock()
+
+	task.Status = status
+	task.UpdatedAt = now
+
+	// Save to Redis (if available)
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task progress to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	tm.notify(taskID)
 	return nil
 }
 
-// saveTask saves task to Redis
+// finishStep marks the running step of a task, if any, as finished with the given status
+func (tm *TaskManager) finishStep(task *model.Task, status string, at time.Time) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if n := len(task.Progress); n > 0 && task.Progress[n-1].Status == model.StepRunning {
+		task.Progress[n-1].Status = status
+		task.Progress[n-1].FinishedAt = &at
+	}
+}
+
+// Progress returns a copy of a task's steps, safe to read while the task runs
+func (tm *TaskManager) Progress(task *model.Task) []model.TaskStep {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if len(task.Progress) == 0 {
+		return nil
+	}
+	return append([]model.TaskStep(nil), task.Progress...)
+}
+
+// Subscribe returns a channel that is signalled whenever the task changes, and the
+// function that stops the subscription
+func (tm *TaskManager) Subscribe(taskID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	tm.subMu.Lock()
+	if tm.subscribers[taskID] == nil {
+		tm.subscribers[taskID] = make(map[chan struct{}]bool)
+	}
+	tm.subscribers[taskID][ch] = true
+	tm.subMu.Unlock()
+
+	return ch, func() {
+		tm.subMu.Lock()
+		defer tm.subMu.Unlock()
+
+		delete(tm.subscribers[taskID], ch)
+		if len(tm.subscribers[taskID]) == 0 {
+			delete(tm.subscribers, taskID)
+		}
+	}
+}
+
+// notify signals the task's subscribers without blocking; a pending signal already
+// tells a slow subscriber to re-read the task
+func (tm *TaskManager) notify(taskID string) {
+	tm.subMu.Lock()
+	defer tm.subMu.Unlock()
+
+	for ch := range tm.subscribers[taskID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Await waits until a task has finished or is held for the user, an agent or a later
+// day, and returns it. When ctx ends first the task is returned as it stands, with the
+// context's error.
+func (tm *TaskManager) Await(ctx context.Context, taskID string) (*model.Task, error) {
+	updates, unsubscribe := tm.Subscribe(taskID)
+	defer unsubscribe()
+
+	for {
+		task, err := tm.GetTask(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status.IsTerminal() || task.Status.IsHeld() {
+			return task, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return task, ctx.Err()
+		case <-updates:
+		}
+	}
+}
+
+// recordDuration folds one execution time into the intent's moving average
+func (tm *TaskManager) recordDuration(intent string, d time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	avg, ok := tm.durations[intent]
+	if !ok {
+		tm.durations[intent] = d
+		return
+	}
+	tm.durations[intent] = avg + time.Duration(durationSmoothing*float64(d-avg))
+}
+
+// EstimateCompletion predicts when a running task will finish from the recent execution
+// times of its intent. It returns nil for finished tasks and intents not seen yet.
+func (tm *TaskManager) EstimateCompletion(task *model.Task) *time.Time {
+	if task.Status.IsTerminal() {
+		return nil
+	}
+
+	tm.mu.RLock()
+	avg, ok := tm.durations[task.Intent]
+	tm.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	eta := task.UpdatedAt.Add(avg)
+	return &eta
+}
+
+// SetInstance sets the ID of this instance, stamped on the tasks it creates or re-runs
+func (tm *TaskManager) SetInstance(instance string) {
+	tm.instance = instance
+}
+
+// Persistent reports whether tasks are saved to Redis, where another instance finds
+// them, rather than kept in this instance's memory only
+func (tm *TaskManager) Persistent() bool {
+	return tm.redisAvailable
+}
+
+// Unfinished returns copies of the tasks being routed or executed, in memory or in
+// Redis, oldest first. Redis is scanned, so this is meant for startup and shutdown.
+func (tm *TaskManager) Unfinished(ctx context.Context) ([]model.Task, error) {
+	found := make(map[string]model.Task)
+
+	tm.mu.RLock()
+	for taskID, task := range tm.tasks {
+		if task.Status.IsRunning() {
+			found[taskID] = *task
+		}
+	}
+	tm.mu.RUnlock()
+
+	if tm.redisAvailable && tm.redisClient != nil {
+		iter := tm.redisClient.Scan(ctx, 0, "task:*", 500).Iterator()
+		for iter.Next(ctx) {
+			data, err := tm.redisClient.Get(ctx, iter.Val()).Result()
+			if err != nil {
+				continue
+			}
+			var task model.Task
+			if json.Unmarshal([]byte(data), &task) == nil && task.Status.IsRunning() {
+				if _, ok := found[task.TaskID]; !ok {
+					found[task.TaskID] = task
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan tasks in Redis: %w", err)
+		}
+	}
+
+	tasks := make([]model.Task, 0, len(found))
+	for _, task := range found {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(a, b int) bool { return tasks[a].CreatedAt.Before(tasks[b].CreatedAt) })
+	return tasks, nil
+}
+
+// Restore takes in a task another instance handed over outside Redis
+func (tm *TaskManager) Restore(ctx context.Context, task *model.Task) {
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save restored task to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	tm.mu.Lock()
+	tm.tasks[task.TaskID] = task
+	tm.mu.Unlock()
+}
+
+// SetTTL sets how long tasks are kept in Redis. 0 keeps them until purged.
+func (tm *TaskManager) SetTTL(ttl time.Duration) {
+	tm.ttl = ttl
+}
+
+// PurgeTasks deletes finished tasks that completed before cutoff, from memory and from
+// Redis, and returns their IDs. Tasks of users on legal hold are kept, and made
+// persistent in Redis so their TTL cannot expire them either. With dryRun nothing is
+// changed.
+func (tm *TaskManager) PurgeTasks(ctx context.Context, cutoff time.Time, held func(userID string) bool, dryRun bool) ([]string, int) {
+	var purged, kept []string
+
+	tm.mu.Lock()
+	for taskID, task := range tm.tasks {
+		if !task.Status.IsTerminal() || task.CompletedAt == nil || !task.CompletedAt.Before(cutoff) {
+			continue
+		}
+		if held(task.UserID) {
+			kept = append(kept, taskID)
+			continue
+		}
+		purged = append(purged, taskID)
+		if !dryRun {
+			delete(tm.tasks, taskID)
+		}
+	}
+	tm.mu.Unlock()
+
+	if dryRun || !tm.redisAvailable || tm.redisClient == nil {
+		return purged, len(kept)
+	}
+
+	for _, taskID := range purged {
+		if err := tm.redisClient.Del(ctx, fmt.Sprintf("task:%s", taskID)).Err(); err != nil {
+			log.Warn().Err(err).Str("task_id", taskID).Msg("Failed to purge task from Redis")
+		}
+	}
+	for _, taskID := range kept {
+		if err := tm.redisClient.Persist(ctx, fmt.Sprintf("task:%s", taskID)).Err(); err != nil {
+			log.Warn().Err(err).Str("task_id", taskID).Msg("Failed to keep held task in Redis")
+		}
+	}
+	return purged, len(kept)
+}
+
+// UserTasks returns copies of every task of a user, in memory or in Redis, oldest
+// first. Redis is scanned, so this is meant for rare requests such as data-subject
+// requests.
+func (tm *TaskManager) UserTasks(ctx context.Context, userID string) ([]model.Task, error) {
+	found := make(map[string]model.Task)
+
+	tm.mu.RLock()
+	for taskID, task := range tm.tasks {
+		if task.UserID == userID {
+			found[taskID] = *task
+		}
+	}
+	tm.mu.RUnlock()
+
+	if tm.redisAvailable && tm.redisClient != nil {
+		iter := tm.redisClient.Scan(ctx, 0, "task:*", 500).Iterator()
+		for iter.Next(ctx) {
+			data, err := tm.redisClient.Get(ctx, iter.Val()).Result()
+			if err != nil {
+				continue
+			}
+			var task model.Task
+			if json.Unmarshal([]byte(data), &task) == nil && task.UserID == userID {
+				if _, ok := found[task.TaskID]; !ok {
+					found[task.TaskID] = task
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan tasks in Redis: %w", err)
+		}
+	}
+
+	tasks := make([]model.Task, 0, len(found))
+	for _, task := range found {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(a, b int) bool { return tasks[a].CreatedAt.Before(tasks[b].CreatedAt) })
+	return tasks, nil
+}
+
+// FinishedBetween returns copies of the tasks that finished in [from, to), in memory
+// or in Redis, oldest first. Redis is scanned, as compaction evicts finished tasks
+// from memory, so this is meant for rare jobs such as the daily reconciliation.
+func (tm *TaskManager) FinishedBetween(ctx context.Context, from, to time.Time) ([]model.Task, error) {
+	finishedIn := func(task *model.Task) bool {
+		if !task.Status.IsTerminal() || task.CompletedAt == nil {
+			return false
+		}
+		return !task.CompletedAt.Before(from) && task.CompletedAt.Before(to)
+	}
+	found := make(map[string]model.Task)
+
+	tm.mu.RLock()
+	for taskID, task := range tm.tasks {
+		if finishedIn(task) {
+			found[taskID] = *task
+		}
+	}
+	tm.mu.RUnlock()
+
+	if tm.redisAvailable && tm.redisClient != nil {
+		iter := tm.redisClient.Scan(ctx, 0, "task:*", 500).Iterator()
+		for iter.Next(ctx) {
+			data, err := tm.redisClient.Get(ctx, iter.Val()).Result()
+			if err != nil {
+				continue
+			}
+			var task model.Task
+			if json.Unmarshal([]byte(data), &task) == nil && finishedIn(&task) {
+				if _, ok := found[task.TaskID]; !ok {
+					found[task.TaskID] = task
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan tasks in Redis: %w", err)
+		}
+	}
+
+	tasks := make([]model.Task, 0, len(found))
+	for _, task := range found {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(a, b int) bool { return tasks[a].CompletedAt.Before(*tasks[b].CompletedAt) })
+	return tasks, nil
+}
+
+// Compact evicts from memory the finished tasks that completed before cutoff and
+// returns how many it evicted. Evicted tasks stay in Redis, where GetTask still finds
+// them, so without Redis only stateless tasks, which are never saved, are evicted.
+// Tasks of users on legal hold are kept for the retention purge to make persistent.
+func (tm *TaskManager) Compact(cutoff time.Time, held func(userID string) bool) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	evicted := 0
+	for taskID, task := range tm.tasks {
+		if !task.Status.IsTerminal() || task.CompletedAt == nil || !task.CompletedAt.Before(cutoff) {
+			continue
+		}
+		if !task.Stateless && !tm.redisAvailable {
+			continue
+		}
+		if held(task.UserID) {
+			continue
+		}
+		delete(tm.tasks, taskID)
+		evicted++
+	}
+
+	now := time.Now()
+	tm.lastCompaction = &now
+	tm.evicted += int64(evicted)
+	return evicted
+}
+
+// MemoryStats returns how many tasks this instance holds in memory and how many
+// compaction has evicted
+func (tm *TaskManager) MemoryStats() *model.TaskMemoryStats {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	stats := &model.TaskMemoryStats{
+		Tasks:          len(tm.tasks),
+		Evicted:        tm.evicted,
+		LastCompaction: tm.lastCompaction,
+	}
+	for _, task := range tm.tasks {
+		if task.Status.IsRunning() {
+			stats.Running++
+		}
+	}
+	return stats
+}
+
+// CorrectStatus changes the status of a finished task after the fact, e.g. when the
+// DWH shows its transfer went the other way. The task keeps its result and completion
+// time; reason becomes its error when it is corrected to a failure and is cleared
+// otherwise.
+func (tm *TaskManager) CorrectStatus(ctx context.Context, taskID string, status model.TaskStatus, reason string) error {
+	task, err := tm.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if !task.Status.IsTerminal() || !status.IsTerminal() {
+		return fmt.Errorf("only finished tasks can be corrected to a final status")
+	}
+	if task.Status == model.TaskStatusCancelled {
+		return ErrTaskCancelled
+	}
+
+	tm.mu.Lock()
+	task.Status = status
+	task.UpdatedAt = time.Now()
+	if status == model.TaskStatusCompleted {
+		task.Error = ""
+	} else {
+		task.Error = reason
+	}
+	tm.tasks[taskID] = task
+	tm.mu.Unlock()
+
+	if tm.redisAvailable {
+		if err := tm.saveTask(ctx, task); err != nil {
+			log.Warn().Err(err).Msg("Failed to save task correction to Redis")
+			tm.redisAvailable = false
+		}
+	}
+
+	log.Info().Str("task_id", taskID).Str("status", string(status)).Str("reason", reason).Msg("Task status corrected")
+	tm.notify(taskID)
+	return nil
+}
+
+// DeleteTask removes a task from memory and Redis
+func (tm *TaskManager) DeleteTask(ctx context.Context, taskID string) error {
+	tm.mu.Lock()
+	delete(tm.tasks, taskID)
+	tm.mu.Unlock()
+
+	if tm.redisAvailable && tm.redisClient != nil {
+		if err := tm.redisClient.Del(ctx, fmt.Sprintf("task:%s", taskID)).Err(); err != nil {
+			return fmt.Errorf("failed to delete task from Redis: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveTask saves task to Redis. Stateless tasks are kept in memory only.
 func (tm *TaskManager) saveTask(ctx context.Context, task *model.Task) error {
+	if task.Stateless {
+		return nil
+	}
 	if tm.redisClient == nil {
 		return fmt.Errorf("redis client not available")
 	}
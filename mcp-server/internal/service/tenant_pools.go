@@ -0,0 +1,236 @@
+package service
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+)
+
+// TenantPools keeps agents registered for a tenant serving that tenant alone, so one
+// tenant's heavy traffic cannot take up the agents every tenant shares. A tenant's
+// tasks go to its own agents first and to the shared pool as its policy allows; tasks
+// of no tenant use the shared pool only. Tasks in flight on each agent, and per tenant
+// and pool, are counted in memory.
+type TenantPools struct {
+	cfg      *config.TenantPoolConfig
+	inFlight map[string]int          // By agent ID
+	usage    map[string]*tenantUsage // By tenant; "" for tasks of no tenant
+	mu       sync.Mutex
+}
+
+type tenantUsage struct {
+	inFlight   map[string]int   // By pool
+	tasks      map[string]int64 // By pool
+	overflowed int64
+	unserved   int64
+}
+
+// NewTenantPools creates the tenant pool policies
+func NewTenantPools(cfg *config.TenantPoolConfig) *TenantPools {
+	return &TenantPools{
+		cfg:      cfg,
+		inFlight: make(map[string]int),
+		usage:    make(map[string]*tenantUsage),
+	}
+}
+
+// Policy returns the tenant's own policy, or else the default
+func (tp *TenantPools) Policy(tenantID string) string {
+	if policy, ok := tp.cfg.Policies[tenantID]; ok {
+		return policy
+	}
+	return tp.cfg.Policy
+}
+
+// Order returns the agents, of those given, that may serve a task of the tenant, best
+// first: its own, least busy first, then the shared pool's as its policy allows. Under
+// the overflow policy its own agents that are at capacity come after the shared pool.
+// Agents dedicated to other tenants are never returned.
+func (tp *TenantPools) Order(tenantID string, agents []*model.Agent) []*model.Agent {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	ordered, overflowed := tp.order(tenantID, agents)
+	if overflowed {
+		tp.tenant(tenantID).overflowed++
+	}
+	if len(ordered) == 0 {
+		tp.tenant(tenantID).unserved++
+	}
+	return ordered
+}
+
+// Serves reports whether any of the agents given may serve a task of the tenant,
+// without counting towards its utilization
+func (tp *TenantPools) Serves(tenantID string, agents []*model.Agent) bool {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	ordered, _ := tp.order(tenantID, agents)
+	return len(ordered) > 0
+}
+
+// order returns the agents a task of the tenant may use, best first, and whether the
+// shared pool comes first only because all the tenant's own agents are busy
+func (tp *TenantPools) order(tenantID string, agents []*model.Agent) ([]*model.Agent, bool) {
+	var own, shared []*model.Agent
+	for _, agent := range agents {
+		switch {
+		case agent.TenantID == "":
+			shared = append(shared, agent)
+		case agent.TenantID == tenantID:
+			own = append(own, agent)
+		}
+	}
+	tp.byLoad(own)
+	tp.byLoad(shared)
+
+	switch {
+	case tenantID == "":
+		return shared, false
+	case tp.Policy(tenantID) == config.TenantPoolDedicated:
+		return own, false
+	case tp.Policy(tenantID) == config.TenantPoolFallback:
+		if len(own) == 0 {
+			return shared, false
+		}
+		return own, false
+	}
+
+	var free, busy []*model.Agent
+	for _, agent := range own {
+		if tp.busy(agent) {
+			busy = append(busy, agent)
+		} else {
+			free = append(free, agent)
+		}
+	}
+	overflowed := len(free) == 0 && len(busy) > 0 && len(shared) > 0
+	return append(append(free, shared...), busy...), overflowed
+}
+
+// Begin counts a task of the tenant an agent starts working on. The returned func
+// counts it finished.
+func (tp *TenantPools) Begin(tenantID string, agent *model.Agent) func() {
+	pool := model.PoolShared
+	if agent.TenantID != "" {
+		pool = model.PoolDedicated
+	}
+
+	tp.mu.Lock()
+	usage := tp.tenant(tenantID)
+	tp.inFlight[agent.AgentID]++
+	usage.inFlight[pool]++
+	usage.tasks[pool]++
+	tp.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			tp.mu.Lock()
+			if tp.inFlight[agent.AgentID]--; tp.inFlight[agent.AgentID] <= 0 {
+				delete(tp.inFlight, agent.AgentID)
+			}
+			usage.inFlight[pool]--
+			tp.mu.Unlock()
+		})
+	}
+}
+
+// Report returns the utilization of every tenant that has agents of its own, a policy
+// of its own or has sent tasks, and of the shared pool, given the healthy agents
+func (tp *TenantPools) Report(agents []*model.Agent) *model.TenantPoolReport {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	report := &model.TenantPoolReport{
+		AgentCapacity: tp.cfg.AgentCapacity,
+		DefaultPolicy: tp.cfg.Policy,
+		Tenants:       []model.TenantPoolStats{},
+	}
+
+	tenants := make(map[string]*model.TenantPoolStats)
+	stats := func(tenantID string) *model.TenantPoolStats {
+		s, ok := tenants[tenantID]
+		if !ok {
+			s = &model.TenantPoolStats{TenantID: tenantID, Policy: tp.Policy(tenantID)}
+			tenants[tenantID] = s
+		}
+		return s
+	}
+	for tenantID := range tp.cfg.Policies {
+		stats(tenantID)
+	}
+
+	for _, agent := range agents {
+		if agent.Status != model.AgentStatusHealthy {
+			continue
+		}
+		if agent.TenantID == "" {
+			report.SharedPool.Agents++
+			report.SharedPool.InFlight += tp.inFlight[agent.AgentID]
+			continue
+		}
+		s := stats(agent.TenantID)
+		s.DedicatedAgents++
+	}
+
+	for tenantID, usage := range tp.usage {
+		report.SharedPool.Tasks += usage.tasks[model.PoolShared]
+		if tenantID == "" {
+			continue
+		}
+		s := stats(tenantID)
+		s.InFlightDedicated = usage.inFlight[model.PoolDedicated]
+		s.InFlightShared = usage.inFlight[model.PoolShared]
+		s.DedicatedTasks = usage.tasks[model.PoolDedicated]
+		s.SharedTasks = usage.tasks[model.PoolShared]
+		s.Overflowed = usage.overflowed
+		s.Unserved = usage.unserved
+	}
+
+	for _, s := range tenants {
+		s.Utilization = tp.utilization(s.InFlightDedicated, s.DedicatedAgents)
+		report.Tenants = append(report.Tenants, *s)
+	}
+	sort.Slice(report.Tenants, func(a, b int) bool { return report.Tenants[a].TenantID < report.Tenants[b].TenantID })
+	report.SharedPool.Utilization = tp.utilization(report.SharedPool.InFlight, report.SharedPool.Agents)
+	return report
+}
+
+// busy reports whether an agent is working on as many tasks as it takes at once
+func (tp *TenantPools) busy(agent *model.Agent) bool {
+	return tp.cfg.AgentCapacity > 0 && tp.inFlight[agent.AgentID] >= tp.cfg.AgentCapacity
+}
+
+// byLoad sorts agents least busy first, by ID among equals
+func (tp *TenantPools) byLoad(agents []*model.Agent) {
+	sort.Slice(agents, func(a, b int) bool {
+		la, lb := tp.inFlight[agents[a].AgentID], tp.inFlight[agents[b].AgentID]
+		if la != lb {
+			return la < lb
+		}
+		return agents[a].AgentID < agents[b].AgentID
+	})
+}
+
+func (tp *TenantPools) utilization(inFlight, agents int) float64 {
+	if tp.cfg.AgentCapacity <= 0 || agents == 0 {
+		return 0
+	}
+	return float64(inFlight) / float64(agents*tp.cfg.AgentCapacity)
+}
+
+func (tp *TenantPools) tenant(tenantID string) *tenantUsage {
+	usage, ok := tp.usage[tenantID]
+	if !ok {
+		usage = &tenantUsage{
+			inFlight: make(map[string]int),
+			tasks:    make(map[string]int64),
+		}
+		tp.usage[tenantID] = usage
+	}
+	return usage
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/mcp-server/internal/config"
+	"github.com/aibanking/mcp-server/internal/model"
+	"github.com/aibanking/shared/ids"
+	"github.com/aibanking/shared/tz"
+	"github.com/rs/zerolog/log"
+)
+
+// Split errors
+var (
+	ErrSplitNotFound = errors.New("split not found")
+	ErrSplitClosed   = errors.New("split is no longer waiting for confirmation")
+)
+
+// TransferSplitter keeps each payment rail's limits: the most one transfer may carry
+// and the most a user may send per day. A transfer over them is offered to the user as
+// legs across rails and days that stay within them. Proposals and the amounts users
+// have sent per rail and day are held in memory.
+type TransferSplitter struct {
+	cfg       *config.SplitConfig
+	loc       *time.Location
+	proposals map[string]*model.SplitProposal // By split ID
+	byTask    map[string]string               // Split ID by task ID
+	sent      map[string]float64              // Amount sent by user, rail and day
+	mu        sync.Mutex
+}
+
+// NewTransferSplitter creates the rail limits and split policy
+func NewTransferSplitter(cfg *config.SplitConfig) *TransferSplitter {
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		loaded, err := tz.Load(cfg.Timezone)
+		if err != nil {
+			log.Warn().Err(err).Str("timezone", cfg.Timezone).Msg("Unknown split time zone, using UTC")
+		} else {
+			loc = loaded
+		}
+	}
+	return &TransferSplitter{
+		cfg:       cfg,
+		loc:       loc,
+		proposals: make(map[string]*model.SplitProposal),
+		byTask:    make(map[string]string),
+		sent:      make(map[string]float64),
+	}
+}
+
+// Exceeds returns the limit a transfer is over, or "" when its rail can carry it
+// today. Intents that are not transfers, and rails without limits, are never over.
+func (ts *TransferSplitter) Exceeds(task *model.Task) string {
+	rail := transferRail(task.Intent)
+	if rail == "" {
+		return ""
+	}
+	amount := taskAmount(task.Data)
+
+	if limit, ok := ts.cfg.PerTransfer[rail]; ok && amount > limit {
+		return fmt.Sprintf("%s allows at most %s per transfer", rail, inr(limit))
+	}
+	if daily, ok := ts.cfg.Daily[rail]; ok {
+		ts.mu.Lock()
+		left := daily - ts.sent[ts.sentKey(task.UserID, rail, time.Now())]
+		ts.mu.Unlock()
+		if amount > left {
+			return fmt.Sprintf("%s allows %s a day and %s is left today", rail, inr(daily), inr(math.Max(left, 0)))
+		}
+	}
+	return ""
+}
+
+// Propose splits a transfer that is over its rail's limits into legs: the rail asked
+// for first, then the other split rails in order, each up to its per-transfer limit
+// and what is left of its daily limit, today and then on later days. Returns an error
+// saying why when splitting is off or no split within the policy carries the amount.
+func (ts *TransferSplitter) Propose(task *model.Task, reason string) (*model.SplitProposal, error) {
+	if !ts.cfg.Enabled {
+		return nil, fmt.Errorf("%s", reason)
+	}
+
+	rails := []string{transferRail(task.Intent)}
+	for _, rail := range ts.cfg.Rails {
+		if rail = strings.ToUpper(rail); rail != rails[0] {
+			rails = append(rails, rail)
+		}
+	}
+
+	now := time.Now()
+	amount := taskAmount(task.Data)
+	remaining := amount
+	var legs []model.SplitLeg
+
+	ts.mu.Lock()
+	for day := 0; day < ts.cfg.MaxDays && remaining > 0.005; day++ {
+		at := ts.dayStart(now, day)
+		for _, rail := range rails {
+			left := math.Inf(1)
+			if daily, ok := ts.cfg.Daily[rail]; ok {
+				left = daily - ts.sent[ts.sentKey(task.UserID, rail, at)]
+			}
+			for remaining > 0.005 && left > 0.005 && len(legs) < ts.cfg.MaxLegs {
+				legAmount := math.Min(remaining, left)
+				if limit, ok := ts.cfg.PerTransfer[rail]; ok {
+					legAmount = math.Min(legAmount, limit)
+				}
+				legs = append(legs, model.SplitLeg{
+					Leg:          len(legs) + 1,
+					Rail:         rail,
+					Intent:       "TRANSFER_" + rail,
+					Amount:       legAmount,
+					ScheduledFor: at,
+					Status:       model.SplitLegPending,
+				})
+				remaining -= legAmount
+				left -= legAmount
+			}
+		}
+	}
+	ts.mu.Unlock()
+
+	if remaining > 0.005 {
+		return nil, fmt.Errorf("%s, and %s cannot be split into %d transfers or fewer over %d %s",
+			reason, inr(amount), ts.cfg.MaxLegs, ts.cfg.MaxDays, plural(ts.cfg.MaxDays, "day", "days"))
+	}
+
+	splitID := ids.New(ids.Split)
+	proposal := &model.SplitProposal{
+		SplitID:     splitID,
+		TaskID:      task.TaskID,
+		UserID:      task.UserID,
+		Intent:      task.Intent,
+		Amount:      amount,
+		Reason:      reason,
+		Legs:        legs,
+		Status:      model.SplitProposed,
+		ConfirmPath: fmt.Sprintf("/api/v1/splits/%s/confirm", splitID),
+		DeclinePath: fmt.Sprintf("/api/v1/splits/%s/decline", splitID),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Duration(ts.cfg.ProposalTTLSeconds) * time.Second),
+	}
+	proposal.Message = fmt.Sprintf("Split confirmation required: %s, so %s would go as %d transfers: %s",
+		reason, inr(amount), len(legs), ts.describeLegs(legs, now))
+
+	ts.mu.Lock()
+	ts.proposals[splitID] = proposal
+	ts.byTask[task.TaskID] = splitID
+	ts.mu.Unlock()
+
+	log.Info().
+		Str("task_id", task.TaskID).
+		Str("split_id", splitID).
+		Float64("amount", amount).
+		Int("legs", len(legs)).
+		Msg("Transfer offered as a split")
+	return copySplit(proposal), nil
+}
+
+// Get returns a split by ID
+func (ts *TransferSplitter) Get(splitID string) (*model.SplitProposal, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	proposal, ok := ts.proposals[splitID]
+	if !ok {
+		return nil, false
+	}
+	return copySplit(proposal), true
+}
+
+// ForTask returns the split a task was offered as
+func (ts *TransferSplitter) ForTask(taskID string) (*model.SplitProposal, bool) {
+	ts.mu.Lock()
+	splitID, ok := ts.byTask[taskID]
+	ts.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return ts.Get(splitID)
+}
+
+// Confirm accepts a proposed split so its legs can run
+func (ts *TransferSplitter) Confirm(splitID string) (*model.SplitProposal, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	proposal, ok := ts.proposals[splitID]
+	if !ok {
+		return nil, ErrSplitNotFound
+	}
+	if proposal.Status != model.SplitProposed || time.Now().After(proposal.ExpiresAt) {
+		return copySplit(proposal), fmt.Errorf("%w: split %s is %s", ErrSplitClosed, splitID, proposal.Status)
+	}
+
+	now := time.Now()
+	proposal.Status = model.SplitConfirmed
+	proposal.ConfirmedAt = &now
+	proposal.Message = fmt.Sprintf("Sending %s as %d transfers", inr(proposal.Amount), len(proposal.Legs))
+	return copySplit(proposal), nil
+}
+
+// Close ends a split that was never confirmed with status, DECLINED or EXPIRED.
+// Returns false when the split had already been confirmed or closed.
+func (ts *TransferSplitter) Close(splitID, status string) (*model.SplitProposal, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	proposal, ok := ts.proposals[splitID]
+	if !ok || proposal.Status != model.SplitProposed {
+		return nil, false
+	}
+
+	now := time.Now()
+	proposal.Status = status
+	proposal.FinishedAt = &now
+	proposal.Message = "Split declined; nothing was sent"
+	if status == model.SplitExpired {
+		proposal.Message = "Split was not confirmed in time; nothing was sent"
+	}
+	return copySplit(proposal), true
+}
+
+// Update stores how a confirmed split's legs are going
+func (ts *TransferSplitter) Update(proposal *model.SplitProposal) {
+	ts.mu.Lock()
+	ts.proposals[proposal.SplitID] = copySplit(proposal)
+	ts.mu.Unlock()
+}
+
+// Record counts a transfer sent on a rail towards the user's daily limit for it
+func (ts *TransferSplitter) Record(userID, intent string, amount float64, at time.Time) {
+	rail := transferRail(intent)
+	if rail == "" || amount <= 0 {
+		return
+	}
+	ts.mu.Lock()
+	ts.sent[ts.sentKey(userID, rail, at)] += amount
+	ts.mu.Unlock()
+}
+
+// describeLegs lists legs as "₹2,00,000 by IMPS now and ₹1,00,000 by UPI on 18 Oct
+// from 09:00"
+func (ts *TransferSplitter) describeLegs(legs []model.SplitLeg, now time.Time) string {
+	parts := make([]string, len(legs))
+	for i, leg := range legs {
+		when := "now"
+		if leg.ScheduledFor.After(now) {
+			when = "on " + leg.ScheduledFor.In(ts.loc).Format("2 Jan from 15:04")
+		}
+		parts[i] = fmt.Sprintf("%s by %s %s", inr(leg.Amount), leg.Rail, when)
+	}
+	return joinAnd(parts)
+}
+
+// dayStart returns when legs on the day that is days after now run: now for today,
+// and LaterDayHour on later days
+func (ts *TransferSplitter) dayStart(now time.Time, days int) time.Time {
+	if days == 0 {
+		return now
+	}
+	local := now.In(ts.loc)
+	return time.Date(local.Year(), local.Month(), local.Day()+days, ts.cfg.LaterDayHour, 0, 0, 0, ts.loc)
+}
+
+func (ts *TransferSplitter) sentKey(userID, rail string, at time.Time) string {
+	return userID + "|" + rail + "|" + at.In(ts.loc).Format("2006-01-02")
+}
+
+// transferRail returns the rail of a transfer intent, e.g. IMPS for TRANSFER_IMPS
+func transferRail(intent string) string {
+	if !strings.HasPrefix(intent, "TRANSFER_") {
+		return ""
+	}
+	return strings.TrimPrefix(intent, "TRANSFER_")
+}
+
+func copySplit(proposal *model.SplitProposal) *model.SplitProposal {
+	copied := *proposal
+	copied.Legs = append([]model.SplitLeg(nil), proposal.Legs...)
+	return &copied
+}
+
+// inr formats an amount in rupees with Indian digit grouping, e.g. ₹2,00,000
+func inr(amount float64) string {
+	digits := fmt.Sprintf("%.0f", math.Abs(amount))
+	if len(digits) > 3 {
+		head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		digits = strings.Join(append([]string{head}, groups...), ",") + "," + tail
+	}
+	if amount < 0 {
+		return "-₹" + digits
+	}
+	return "₹" + digits
+}
+
+// joinAnd joins items as "a, b and c"
+func joinAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aibanking/shared/recovery"
+)
+
+// AccessLog is the HTTP middleware that records every request but health checks:
+// who called, which endpoint, from where and with what outcome. Records go to the
+// logger's sink, not the application log, and a record that cannot be written never
+// fails the request.
+type AccessLog struct {
+	logger       *Logger
+	apiKeyHeader string
+	route        func(*http.Request) string // Route template a request matched, "" when none
+	logError     func(rec *Record, err error)
+}
+
+// NewAccessLog creates the access-logging middleware. The service names the route
+// each request matched and reports records it could not write to its own logger.
+func NewAccessLog(logger *Logger, apiKeyHeader string, route func(*http.Request) string, logError func(rec *Record, err error)) *AccessLog {
+	return &AccessLog{logger: logger, apiKeyHeader: apiKeyHeader, route: route, logError: logError}
+}
+
+// Middleware records the requests it wraps. Register it first, so requests refused by
+// later middleware are recorded too.
+func (al *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !al.logger.Enabled() || r.URL.Path == "/health" || r.URL.Path == "/ready" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := &Record{
+			Time:          start.UTC(),
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Actor:         KeyActor(r.Header.Get(al.apiKeyHeader)),
+			RemoteIP:      remoteIP(r),
+			ForwardedFor:  r.Header.Get("X-Forwarded-For"),
+			UserAgent:     r.UserAgent(),
+			CorrelationID: r.Header.Get(recovery.CorrelationHeader),
+			Route:         al.route(r),
+		}
+
+		wrapped := &accessWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(wrapped, r.WithContext(NewContext(r.Context(), rec)))
+
+		rec.Status = wrapped.status
+		rec.Bytes = wrapped.bytes
+		rec.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+		if rec.CorrelationID == "" {
+			rec.CorrelationID = wrapped.Header().Get(recovery.CorrelationHeader)
+		}
+		if err := al.logger.Log(rec); err != nil {
+			al.logError(rec, err)
+		}
+	})
+}
+
+// NotFound answers requests that match no route, recorded like any other
+func (al *AccessLog) NotFound() http.Handler {
+	return al.Middleware(http.NotFoundHandler())
+}
+
+// MethodNotAllowed answers requests whose path matches a route but not its method,
+// recorded like any other
+func (al *AccessLog) MethodNotAllowed() http.Handler {
+	return al.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+}
+
+// remoteIP returns the address of the connection the request came over
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// accessWriter captures the status and size of a response
+type accessWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (aw *accessWriter) WriteHeader(code int) {
+	if !aw.wroteHeader {
+		aw.status, aw.wroteHeader = code, true
+	}
+	aw.ResponseWriter.WriteHeader(code)
+}
+
+func (aw *accessWriter) Write(b []byte) (int, error) {
+	aw.wroteHeader = true
+	n, err := aw.ResponseWriter.Write(b)
+	aw.bytes += n
+	return n, err
+}
+
+// Flush lets streaming handlers push data through the wrapper
+func (aw *accessWriter) Flush() {
+	if flusher, ok := aw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection
+func (aw *accessWriter) Unwrap() http.ResponseWriter {
+	return aw.ResponseWriter
+}
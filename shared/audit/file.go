@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tailBytes is how much of the newest file is read to find its last record
+const tailBytes = 64 * 1024
+
+// FileSink appends records to one file per UTC day, <service>-access-YYYY-MM-DD.jsonl,
+// synced after every record. Once a day's file is opened, earlier days' files are made
+// read-only and those older than the retention are deleted.
+type FileSink struct {
+	dir       string
+	service   string
+	retention int
+	day       string
+	file      *os.File
+}
+
+// NewFileSink creates a file sink writing to dir
+func NewFileSink(dir, service string, retentionDays int) (*FileSink, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("AUDIT_DIR is required for the file sink")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	return &FileSink{dir: dir, service: service, retention: retentionDays}, nil
+}
+
+// Last reads the newest record of the newest file that has one
+func (fs *FileSink) Last() (uint64, string, error) {
+	files, err := fs.files()
+	if err != nil {
+		return 0, "", err
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		line, err := lastLine(filepath.Join(fs.dir, files[i]))
+		if err != nil {
+			return 0, "", err
+		}
+		if line == nil {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return 0, "", fmt.Errorf("last line of %s is not a record: %w", files[i], err)
+		}
+		return rec.Seq, rec.Hash, nil
+	}
+	return 0, "", nil
+}
+
+// lastLine returns the last line of a file, nil for an empty one
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - tailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	tail = bytes.TrimRight(tail, "\n")
+	if len(tail) == 0 {
+		return nil, nil
+	}
+	return tail[bytes.LastIndexByte(tail, '\n')+1:], nil
+}
+
+// Write appends a record to its day's file
+func (fs *FileSink) Write(rec *Record, line []byte) error {
+	day := rec.Time.UTC().Format("2006-01-02")
+	if day != fs.day || fs.file == nil {
+		if err := fs.rotate(day); err != nil {
+			return err
+		}
+	}
+	if _, err := fs.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return fs.file.Sync()
+}
+
+// Close closes the current file
+func (fs *FileSink) Close() error {
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
+
+// rotate opens the day's file, making earlier days' read-only and deleting those past
+// retention
+func (fs *FileSink) rotate(day string) error {
+	if fs.file != nil {
+		fs.file.Close()
+		fs.file = nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(fs.dir, fs.fileName(day)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open access log: %w", err)
+	}
+	fs.file, fs.day = f, day
+
+	current := fs.fileName(day)
+	cutoff := ""
+	if fs.retention > 0 {
+		cutoff = fs.fileName(time.Now().UTC().AddDate(0, 0, -fs.retention).Format("2006-01-02"))
+	}
+	files, _ := fs.files()
+	for _, name := range files {
+		switch {
+		case name < cutoff:
+			os.Remove(filepath.Join(fs.dir, name))
+		case name < current:
+			os.Chmod(filepath.Join(fs.dir, name), 0o440)
+		}
+	}
+	return nil
+}
+
+// files lists the service's access log files, oldest first
+func (fs *FileSink) files() ([]string, error) {
+	entries, err := os.ReadDir(fs.dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fs.service + "-access-"
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) && strings.HasSuffix(entry.Name(), ".jsonl") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (fs *FileSink) fileName(day string) string {
+	return fs.service + "-access-" + day + ".jsonl"
+}
@@ -0,0 +1,196 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// redisTimeout bounds dialing and each command
+const redisTimeout = 3 * time.Second
+
+// RedisSink adds records to a Redis stream, one entry per record with its seq and JSON
+// line. Entries older than the retention are trimmed as new ones are added.
+type RedisSink struct {
+	addr      string
+	password  string
+	stream    string
+	retention time.Duration
+	conn      net.Conn
+	reader    *bufio.Reader
+}
+
+// NewRedisSink creates a Redis stream sink and checks the server can be reached
+func NewRedisSink(addr, password, stream string, retentionDays int) (*RedisSink, error) {
+	if addr == "" || stream == "" {
+		return nil, fmt.Errorf("AUDIT_REDIS_ADDR and AUDIT_REDIS_STREAM are required for the redis sink")
+	}
+	rs := &RedisSink{
+		addr:      addr,
+		password:  password,
+		stream:    stream,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+	}
+	if err := rs.connect(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// Last reads the newest entry of the stream
+func (rs *RedisSink) Last() (uint64, string, error) {
+	reply, err := rs.do("XREVRANGE", rs.stream, "+", "-", "COUNT", "1")
+	if err != nil {
+		return 0, "", err
+	}
+	entries, _ := reply.([]interface{})
+	if len(entries) == 0 {
+		return 0, "", nil
+	}
+	entry, _ := entries[0].([]interface{})
+	if len(entry) != 2 {
+		return 0, "", fmt.Errorf("unexpected stream entry from %s", rs.stream)
+	}
+	fields, _ := entry[1].([]interface{})
+	for i := 0; i+1 < len(fields); i += 2 {
+		if name, _ := fields[i].(string); name != "record" {
+			continue
+		}
+		line, _ := fields[i+1].(string)
+		var rec Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return 0, "", fmt.Errorf("newest entry of %s is not a record: %w", rs.stream, err)
+		}
+		return rec.Seq, rec.Hash, nil
+	}
+	return 0, "", fmt.Errorf("newest entry of %s has no record", rs.stream)
+}
+
+// Write adds a record to the stream, trimming entries past retention
+func (rs *RedisSink) Write(rec *Record, line []byte) error {
+	args := []string{"XADD", rs.stream}
+	if rs.retention > 0 {
+		minID := rec.Time.Add(-rs.retention).UnixMilli()
+		args = append(args, "MINID", "~", strconv.FormatInt(minID, 10))
+	}
+	args = append(args, "*", "seq", strconv.FormatUint(rec.Seq, 10), "record", string(line))
+	_, err := rs.do(args...)
+	return err
+}
+
+// Close closes the connection
+func (rs *RedisSink) Close() error {
+	if rs.conn == nil {
+		return nil
+	}
+	err := rs.conn.Close()
+	rs.conn = nil
+	return err
+}
+
+func (rs *RedisSink) connect() error {
+	conn, err := net.DialTimeout("tcp", rs.addr, redisTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to audit redis: %w", err)
+	}
+	rs.conn, rs.reader = conn, bufio.NewReader(conn)
+	if rs.password != "" {
+		if _, err := rs.command("AUTH", rs.password); err != nil {
+			rs.Close()
+			return fmt.Errorf("audit redis auth failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// do runs a command, reconnecting once if the connection was lost
+func (rs *RedisSink) do(args ...string) (interface{}, error) {
+	if rs.conn == nil {
+		if err := rs.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := rs.command(args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		rs.Close()
+		if err := rs.connect(); err != nil {
+			return nil, err
+		}
+		reply, err = rs.command(args...)
+		if err != nil && !errors.As(err, &redisErr) {
+			rs.Close()
+		}
+	}
+	return reply, err
+}
+
+// command writes a command as a RESP array and reads its reply
+func (rs *RedisSink) command(args ...string) (interface{}, error) {
+	rs.conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := rs.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(rs.reader)
+}
+
+// redisError is an error reply from the server, which leaves the connection usable
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// readReply reads one RESP reply: strings, integers, nil or nested arrays of them
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("short redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
@@ -0,0 +1,72 @@
+// Package channel defines the channels a customer reaches the bank through, shared by
+// every service so a channel is spelled the same way from the AI Skin down to the
+// banking connectors.
+//
+// Parse accepts a channel in any case and with surrounding spaces, so "mb" and "MB "
+// are both MB, and refuses anything that is not in the enum. Entry points parse the
+// channel once and pass the canonical value on.
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Channel is a customer channel
+type Channel string
+
+const (
+	MB       Channel = "MB"       // Mobile Banking
+	NB       Channel = "NB"       // Net Banking
+	API      Channel = "API"      // API Banking, for partners and aggregators
+	WhatsApp Channel = "WHATSAPP" // WhatsApp Banking
+	IVR      Channel = "IVR"      // Phone banking
+)
+
+// all lists the channels in the order they are reported in
+var all = []Channel{MB, NB, API, WhatsApp, IVR}
+
+// ErrInvalid is returned for a channel that is missing or not in the enum
+var ErrInvalid = errors.New("invalid channel")
+
+// Parse normalises a channel and checks it is one of the enum
+func Parse(s string) (Channel, error) {
+	c := Channel(strings.ToUpper(strings.TrimSpace(s)))
+	if c == "" {
+		return "", fmt.Errorf("%w: channel is required, want one of %s", ErrInvalid, strings.Join(Allowed(), ", "))
+	}
+	if !c.Valid() {
+		return "", fmt.Errorf("%w %q: want one of %s", ErrInvalid, s, strings.Join(Allowed(), ", "))
+	}
+	return c, nil
+}
+
+// Valid reports whether c is one of the enum, as spelled canonically
+func (c Channel) Valid() bool {
+	for _, known := range all {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the channel's canonical spelling
+func (c Channel) String() string {
+	return string(c)
+}
+
+// Values returns every channel
+func Values() []Channel {
+	return append([]Channel(nil), all...)
+}
+
+// Allowed returns every channel as a string, for error responses
+func Allowed() []string {
+	allowed := make([]string, len(all))
+	for i, c := range all {
+		allowed[i] = string(c)
+	}
+	return allowed
+}
@@ -0,0 +1,216 @@
+// Package demo holds the scripted storylines of demo mode, such as a blocked fraud, an
+// approved loan or a limit breach, so each decision path of the pipeline can be shown
+// the same way every time.
+//
+// A scenario is a JSON file naming the phrases that play it, the intent they are
+// parsed as, the data Banking Integrations is seeded with and the outcome forced on
+// each agent type. The AI Skin matches the phrases and tags the task with the
+// scenario's ID under ContextKey; the MCP server, and each agent called directly, read
+// the tag and answer with the forced outcome instead of deciding. Every service loads
+// the same scenario files, and only when DEMO_MODE is on.
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ContextKey is the task context field carrying the ID of the scenario being played
+const ContextKey = "demo_scenario"
+
+// Config holds demo mode: scripted storylines, each seeding Banking Integrations and
+// forcing agent outcomes when the user says one of its trigger phrases
+type Config struct {
+	Enabled      bool
+	ScenariosDir string // Directory of the scenario files, one *.json each
+}
+
+// Agent statuses an outcome may force
+var statuses = map[string]bool{"APPROVED": true, "REJECTED": true, "PENDING": true}
+
+// idPattern keeps scenario IDs usable in URLs and logs
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Scenario is one storyline
+type Scenario struct {
+	ID          string                 `json:"id"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	Triggers    []string               `json:"triggers"`           // A message containing one, in any case, plays the scenario
+	Intent      string                 `json:"intent,omitempty"`   // Parse the message as this intent, skipping the parser; empty parses it as usual
+	Entities    map[string]interface{} `json:"entities,omitempty"` // Entities of the scripted intent
+	Plan        string                 `json:"plan,omitempty"`     // MCP orchestration plan the tasks run through, so every agent with an outcome is reached
+	Seed        json.RawMessage        `json:"seed,omitempty"`     // Banking Integrations seed fixture, see its fixtures/demo.json
+	Outcomes    map[string]Outcome     `json:"outcomes,omitempty"` // Forced outcome by agent type, e.g. FRAUD; agents not named decide as usual
+}
+
+// Outcome is what an agent answers in a scenario
+type Outcome struct {
+	Status      string                 `json:"status"` // APPROVED, REJECTED or PENDING
+	RiskScore   float64                `json:"risk_score,omitempty"`
+	Confidence  float64                `json:"confidence,omitempty"`
+	Explanation string                 `json:"explanation"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+}
+
+// Library is the scenarios loaded from a directory
+type Library struct {
+	scenarios map[string]*Scenario
+	triggers  []trigger // Longest first, so the most specific phrase wins
+}
+
+type trigger struct {
+	phrase   string
+	scenario *Scenario
+}
+
+// Load reads every *.json scenario in dir. A scenario that does not parse or check
+// out fails the load, so a broken storyline is found at startup rather than on stage.
+func Load(dir string) (*Library, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no demo scenarios in %s", dir)
+	}
+	sort.Strings(paths)
+
+	lib := &Library{scenarios: make(map[string]*Scenario, len(paths))}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var scenario Scenario
+		if err := json.Unmarshal(raw, &scenario); err != nil {
+			return nil, fmt.Errorf("demo scenario %s: %w", filepath.Base(path), err)
+		}
+		if err := scenario.check(); err != nil {
+			return nil, fmt.Errorf("demo scenario %s: %w", filepath.Base(path), err)
+		}
+		if _, ok := lib.scenarios[scenario.ID]; ok {
+			return nil, fmt.Errorf("demo scenario %s: id %q is used twice", filepath.Base(path), scenario.ID)
+		}
+		lib.scenarios[scenario.ID] = &scenario
+	}
+
+	seen := make(map[string]string)
+	for _, scenario := range lib.List() {
+		for _, phrase := range scenario.Triggers {
+			phrase = normalize(phrase)
+			if other, ok := seen[phrase]; ok {
+				return nil, fmt.Errorf("demo scenarios %s and %s share the trigger %q", other, scenario.ID, phrase)
+			}
+			seen[phrase] = scenario.ID
+			lib.triggers = append(lib.triggers, trigger{phrase: phrase, scenario: scenario})
+		}
+	}
+	sort.SliceStable(lib.triggers, func(i, j int) bool {
+		return len(lib.triggers[i].phrase) > len(lib.triggers[j].phrase)
+	})
+	return lib, nil
+}
+
+// check refuses a scenario that could not be played
+func (s *Scenario) check() error {
+	if !idPattern.MatchString(s.ID) {
+		return fmt.Errorf("id %q must be lower case letters, digits, - and _", s.ID)
+	}
+	if len(s.Triggers) == 0 {
+		return fmt.Errorf("has no triggers")
+	}
+	for _, phrase := range s.Triggers {
+		if normalize(phrase) == "" {
+			return fmt.Errorf("has an empty trigger")
+		}
+	}
+	if s.Plan != "" && !idPattern.MatchString(s.Plan) {
+		return fmt.Errorf("plan %q is not a plan ID", s.Plan)
+	}
+	if s.Intent == "" && len(s.Entities) > 0 {
+		return fmt.Errorf("has entities but no intent")
+	}
+	for agentType, outcome := range s.Outcomes {
+		if agentType != strings.ToUpper(agentType) {
+			return fmt.Errorf("outcome agent type %q must be upper case, e.g. FRAUD", agentType)
+		}
+		if !statuses[outcome.Status] {
+			return fmt.Errorf("outcome for %s: status %q must be APPROVED, REJECTED or PENDING", agentType, outcome.Status)
+		}
+		if outcome.RiskScore < 0 || outcome.RiskScore > 1 || outcome.Confidence < 0 || outcome.Confidence > 1 {
+			return fmt.Errorf("outcome for %s: risk_score and confidence must be between 0 and 1", agentType)
+		}
+	}
+	return nil
+}
+
+// Get returns a scenario by ID
+func (l *Library) Get(id string) (*Scenario, bool) {
+	if l == nil {
+		return nil, false
+	}
+	scenario, ok := l.scenarios[id]
+	return scenario, ok
+}
+
+// Match returns the scenario a message plays, or nil when it contains no trigger
+func (l *Library) Match(message string) *Scenario {
+	if l == nil {
+		return nil
+	}
+	// Whole words only, so "loan" is not found in "loans"
+	message = " " + normalize(message) + " "
+	for _, t := range l.triggers {
+		if strings.Contains(message, " "+t.phrase+" ") {
+			return t.scenario
+		}
+	}
+	return nil
+}
+
+// Outcome returns the outcome a scenario forces on an agent type
+func (l *Library) Outcome(id, agentType string) (*Outcome, bool) {
+	scenario, ok := l.Get(id)
+	if !ok {
+		return nil, false
+	}
+	outcome, ok := scenario.Outcomes[agentType]
+	if !ok {
+		return nil, false
+	}
+	return &outcome, true
+}
+
+// List returns the scenarios by ID
+func (l *Library) List() []*Scenario {
+	if l == nil {
+		return nil
+	}
+	list := make([]*Scenario, 0, len(l.scenarios))
+	for _, scenario := range l.scenarios {
+		list = append(list, scenario)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// ScenarioID returns the scenario a task context is tagged with, or ""
+func ScenarioID(taskContext map[string]interface{}) string {
+	id, _ := taskContext[ContextKey].(string)
+	return id
+}
+
+// normalize lower-cases a phrase and turns its punctuation and runs of spaces into
+// single spaces, so "Block the transfer!" reads as "block the transfer"
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
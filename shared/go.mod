@@ -0,0 +1,3 @@
+module github.com/aibanking/shared
+
+go 1.21
@@ -0,0 +1,157 @@
+// Package ids generates the IDs and references shared by every service.
+//
+// IDs are UUIDv7 values: 48 bits of Unix milliseconds, a 12-bit counter and 62 random
+// bits. The counter keeps IDs from one process strictly increasing even within a
+// millisecond, so IDs with the same prefix sort in the order they were generated and
+// an ID can serve as a pagination cursor. Two forms are used:
+//
+//	New("task")  task_0192a3f4-7b1c-7d2e-9f3a-4b5c6d7e8f90  entity IDs
+//	Ref("TXN_")  TXN_01J9HZ3Q5C7V2M8K4D6F0A1B2C              references shown to users and banks
+//
+// A reference is the same 128 bits in Crockford base32, uppercase and free of
+// separators, so it also sorts by time.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ID prefixes. Entity IDs join theirs with an underscore; reference prefixes include
+// their own separator, if any.
+const (
+	Task        = "task"
+	Session     = "sess"
+	Agent       = "agent"
+	Purge       = "purge"
+	DSAR        = "dsar"
+	Reconcile   = "recon"
+	Exception   = "exc"
+	Plan        = "plan"
+	PlanRun     = "run"
+	Split       = "split"
+	Event       = "evt"
+	Document    = "doc"
+	Memory      = "mem"
+	LLMJob      = "job"
+	Instance    = "inst"
+	Outbox      = "obx"
+	Rescore     = "rescore"
+	FewShot     = "shot"
+	Transaction = "TXN_"
+	Sandbox     = "SBX_"
+	Beneficiary = "BEN_"
+	Challenge   = "AUTH_"
+	ErrorRef    = "ERR_"
+)
+
+// crockford is Crockford's base32 alphabet, in ascending byte order
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// generator hands out UUIDv7 values that never go backwards
+type generator struct {
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+var gen generator
+
+// next returns the next UUIDv7. Within a millisecond the counter goes up from a
+// random start; when it runs out, the timestamp is moved on a millisecond.
+func (g *generator) next() [16]byte {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(fmt.Sprintf("ids: reading random bytes: %v", err))
+	}
+
+	g.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms > g.lastMs {
+		g.lastMs = ms
+		// Start low in the counter's range so a burst has room to count up
+		g.seq = binary.BigEndian.Uint16(u[6:8]) & 0x07FF
+	} else {
+		g.seq++
+		if g.seq > 0x0FFF {
+			g.lastMs++
+			g.seq = 0
+		}
+	}
+	ms, seq := g.lastMs, g.seq
+	g.mu.Unlock()
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = 0x70 | byte(seq>>8)
+	u[7] = byte(seq)
+	u[8] = 0x80 | u[8]&0x3F
+	return u
+}
+
+// New returns an entity ID: prefix, an underscore and a UUIDv7
+func New(prefix string) string {
+	u := gen.next()
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return prefix + "_" + string(buf[:])
+}
+
+// Ref returns a reference: prefix followed by a UUIDv7 as 26 characters of Crockford
+// base32, e.g. TXN_01J9HZ3Q5C7V2M8K4D6F0A1B2C
+func Ref(prefix string) string {
+	u := gen.next()
+	hi := binary.BigEndian.Uint64(u[0:8])
+	lo := binary.BigEndian.Uint64(u[8:16])
+	var buf [26]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return prefix + string(buf[:])
+}
+
+// Time returns when an ID or reference from New or Ref was generated
+func Time(id string) (time.Time, error) {
+	var ms int64
+	switch {
+	case len(id) >= 36 && id[len(id)-36+8] == '-':
+		raw, err := hex.DecodeString(strings.ReplaceAll(id[len(id)-36:len(id)-36+13], "-", ""))
+		if err != nil || len(raw) != 6 {
+			return time.Time{}, fmt.Errorf("ids: %q does not end in a UUID", id)
+		}
+		for _, b := range raw {
+			ms = ms<<8 | int64(b)
+		}
+	case len(id) >= 26:
+		// The first 10 characters hold the top 50 bits: two zero bits and the timestamp
+		for _, c := range id[len(id)-26 : len(id)-16] {
+			v := strings.IndexRune(crockford, c)
+			if v < 0 {
+				return time.Time{}, fmt.Errorf("ids: %q does not end in a reference", id)
+			}
+			ms = ms<<5 | int64(v)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("ids: %q is too short", id)
+	}
+	return time.UnixMilli(ms), nil
+}
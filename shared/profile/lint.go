@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// Lint is the body of a service's config-lint command. It prints the effective
+// configuration the service would start with, where each value came from, and the
+// settings that are missing or unsafe for the environment. load reads the service's
+// configuration once -env has been applied to APP_ENV. Lint exits 1 when the service
+// would refuse to start and 2 when its configuration cannot be read.
+func Lint(service string, load func() (env string, settings []Setting, problems []Problem, err error)) {
+	env := flag.String("env", "", "Environment to check against (dev, staging or prod); APP_ENV by default")
+	asJSON := flag.Bool("json", false, "Print as JSON")
+	flag.Parse()
+
+	if *env != "" {
+		os.Setenv("APP_ENV", *env)
+	}
+	environment, settings, problems, err := load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"service":     service,
+			"environment": environment,
+			"settings":    settings,
+			"problems":    problems,
+		}, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		printReport(service, environment, settings, problems)
+	}
+
+	if HasErrors(problems) {
+		os.Exit(1)
+	}
+}
+
+// printReport prints the settings, flagging those with problems, then the problems
+func printReport(service, env string, settings []Setting, problems []Problem) {
+	flagged := make(map[string]string)
+	for _, p := range problems {
+		if flagged[p.Key] != SeverityError {
+			flagged[p.Key] = p.Severity
+		}
+	}
+
+	fmt.Printf("%s configuration for %s\n\n", service, env)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tSETTING\tVALUE\tSOURCE")
+	for _, s := range settings {
+		mark := ""
+		switch flagged[s.Key] {
+		case SeverityError:
+			mark = "✗"
+		case SeverityWarning:
+			mark = "!"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, s.Key, s.Value, s.Source)
+	}
+	w.Flush()
+
+	if len(problems) == 0 {
+		fmt.Println("\nNo problems found")
+		return
+	}
+	errors := 0
+	fmt.Println()
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			errors++
+		}
+		fmt.Printf("%-7s %s %s\n", p.Severity, p.Key, p.Message)
+	}
+	fmt.Printf("\n%d problems, %d errors\n", len(problems), errors)
+}
@@ -0,0 +1,252 @@
+// Package profile reads a service's settings from the environment the same way in
+// every service: which environment it runs in, where each value came from, and which
+// values are missing or unsafe for that environment.
+//
+// A service reads each setting through its Profile, which records the value, its
+// default and its source for config-lint and the startup checks. The service's own
+// Validate then names the settings it needs with a Validator; the checks themselves,
+// and how strict each environment is, live here.
+package profile
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aibanking/shared/secrets"
+)
+
+// Environments a service can run in. Development keeps the localhost defaults;
+// staging and production must set every downstream URL and secret explicitly.
+const (
+	EnvDevelopment = "dev"
+	EnvStaging     = "staging"
+	EnvProduction  = "prod"
+)
+
+// Problem severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Setting is one configuration value as the service read it
+type Setting struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	Source  string `json:"source"` // env, default or the secrets provider
+	Secret  bool   `json:"secret,omitempty"`
+	Invalid bool   `json:"invalid,omitempty"` // Set but not parseable, so the default is used
+}
+
+// Problem is a setting that is missing or unsafe for the environment
+type Problem struct {
+	Key      string `json:"key"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// insecureDefaults are the development placeholders shipped as defaults
+var insecureDefaults = []string{"test-api-key", "your-secret-key", "change-me", "postgres"}
+
+// Profile holds every setting a service read, by key, and the secrets provider
+// secret settings are resolved from
+type Profile struct {
+	settings    map[string]*Setting
+	secretStore *secrets.Store // Nil when secrets come from the environment
+}
+
+// New creates an empty profile
+func New() *Profile {
+	return &Profile{settings: make(map[string]*Setting)}
+}
+
+// LoadEnvironment works out the environment from APP_ENV, which may itself be set in
+// .env, and loads .env.<environment> and then .env. Neither overrides the process
+// environment, and .env.<environment> wins over .env. read and load are godotenv's
+// Read and Load.
+func (p *Profile) LoadEnvironment(read func(filenames ...string) (map[string]string, error), load func(filenames ...string) error) string {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		if values, err := read(); err == nil {
+			env = values["APP_ENV"]
+		}
+	}
+	env = normalizeEnvironment(env)
+
+	if err := load(".env." + env); err == nil {
+		log.Printf("Loaded .env.%s", env)
+	}
+	if err := load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	p.Record("APP_ENV", env, EnvDevelopment, os.Getenv("APP_ENV") != "", false)
+	return env
+}
+
+// normalizeEnvironment accepts the usual spellings; anything unknown is kept so
+// validation can reject it
+func normalizeEnvironment(env string) string {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "", "dev", "development", "local":
+		return EnvDevelopment
+	case "staging", "stage":
+		return EnvStaging
+	case "prod", "production":
+		return EnvProduction
+	default:
+		return strings.ToLower(strings.TrimSpace(env))
+	}
+}
+
+// Record notes a setting the service read some other way than Get, GetInt or GetFloat
+func (p *Profile) Record(key, value, defaultValue string, fromEnv, invalid bool) {
+	source := "default"
+	if fromEnv {
+		source = "env"
+	}
+	p.settings[key] = &Setting{
+		Key:     key,
+		Value:   value,
+		Default: defaultValue,
+		Source:  source,
+		Secret:  IsSecretKey(key),
+		Invalid: invalid,
+	}
+}
+
+// MarkSecret masks a recorded setting whose key does not say it is secret
+func (p *Profile) MarkSecret(key string) {
+	if s, ok := p.settings[key]; ok {
+		s.Secret = true
+	}
+}
+
+// Get reads a setting, from the secrets provider for a secret setting it holds and
+// otherwise from the environment
+func (p *Profile) Get(key, defaultValue string) string {
+	if value, ok := p.lookupSecret(key); ok {
+		p.Record(key, value, defaultValue, true, false)
+		p.settings[key].Source = p.SecretsProvider()
+		return value
+	}
+	if value := os.Getenv(key); value != "" {
+		p.Record(key, value, defaultValue, true, false)
+		return value
+	}
+	p.Record(key, defaultValue, defaultValue, false, false)
+	return defaultValue
+}
+
+// GetInt reads an integer setting, falling back to the default when it is not one
+func (p *Profile) GetInt(key string, defaultValue int) int {
+	fallback := strconv.Itoa(defaultValue)
+	value := os.Getenv(key)
+	if value == "" {
+		p.Record(key, fallback, fallback, false, false)
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	p.Record(key, value, fallback, true, err != nil)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+// GetFloat reads a decimal setting, falling back to the default when it is not one
+func (p *Profile) GetFloat(key string, defaultValue float64) float64 {
+	fallback := strconv.FormatFloat(defaultValue, 'f', -1, 64)
+	value := os.Getenv(key)
+	if value == "" {
+		p.Record(key, fallback, fallback, false, false)
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	p.Record(key, value, fallback, true, err != nil)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+// GetGrants reads a role grant, "holder:apikey" pairs separated by commas, into an
+// API key -> holder map. The setting is always masked, since its values are API keys.
+func (p *Profile) GetGrants(key string) map[string]string {
+	value := os.Getenv(key)
+	p.Record(key, value, "", value != "", false)
+	p.MarkSecret(key)
+	grants := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		holder, apiKey, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || holder == "" || apiKey == "" {
+			continue
+		}
+		grants[apiKey] = holder
+	}
+	return grants
+}
+
+// Settings returns the settings read, sorted by key, with secrets masked
+func (p *Profile) Settings() []Setting {
+	list := make([]Setting, 0, len(p.settings))
+	for _, s := range p.settings {
+		setting := *s
+		if setting.Secret {
+			setting.Value = maskSecret(setting.Value)
+			setting.Default = maskSecret(setting.Default)
+		}
+		list = append(list, setting)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// IsSecretKey reports whether a setting holds a credential, going by its name
+func IsSecretKey(key string) bool {
+	// SECRETS_* settings configure the secrets provider; only its credentials are secret
+	key = strings.TrimPrefix(key, "SECRETS_")
+	for _, marker := range []string{"PASSWORD", "SECRET", "API_KEY", "SIGNING_KEY", "SEAL_KEY", "TOKEN", "WEBHOOK_URL"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret keeps the development placeholders readable so lint output shows them
+func maskSecret(value string) string {
+	if value == "" || isInsecureDefault(value) {
+		return value
+	}
+	return "********"
+}
+
+func isInsecureDefault(value string) bool {
+	value = strings.ToLower(value)
+	for _, placeholder := range insecureDefaults {
+		if strings.HasPrefix(value, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocalHost reports whether a host name is this host
+func IsLocalHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "localhost" || host == "127.0.0.1" || host == "::1" || host == "0.0.0.0"
+}
+
+// HasErrors reports whether any problem should stop the service from starting
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
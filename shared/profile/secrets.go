@@ -0,0 +1,82 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aibanking/shared/secrets"
+)
+
+// LoadSecrets reads the secrets from SECRETS_PROVIDER. Call it before reading any
+// other setting, so Get can resolve secret settings from them.
+func (p *Profile) LoadSecrets() error {
+	p.secretStore = nil
+	provider, err := secrets.FromEnv(func(key string) string { return p.Get(key, "") })
+	if err != nil {
+		return fmt.Errorf("secrets provider: %w", err)
+	}
+	if provider == nil {
+		return nil
+	}
+
+	store := secrets.NewStore(provider)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := store.Load(ctx); err != nil {
+		return fmt.Errorf("failed to load secrets: %w", err)
+	}
+	p.secretStore = store
+	log.Printf("Loaded secrets from %s", provider.Name())
+	return nil
+}
+
+// lookupSecret returns a secret setting's value from the secrets provider
+func (p *Profile) lookupSecret(key string) (string, bool) {
+	if p.secretStore == nil || !IsSecretKey(key) {
+		return "", false
+	}
+	return p.secretStore.Lookup(key)
+}
+
+// SecretsProvider returns the name of the provider secrets come from
+func (p *Profile) SecretsProvider() string {
+	if p.secretStore == nil {
+		return secrets.ProviderEnv
+	}
+	return p.secretStore.Provider()
+}
+
+// OnSecretRotated calls fn with a secret's new value whenever WatchSecrets finds it
+// rotated. Without a secrets provider nothing rotates.
+func (p *Profile) OnSecretRotated(key string, fn func(value string)) {
+	if p.secretStore != nil {
+		p.secretStore.Subscribe(key, fn)
+	}
+}
+
+// RotatingSecret returns a secret setting's value that follows its rotations
+func (p *Profile) RotatingSecret(key, value string) *secrets.Value {
+	current := secrets.NewValue(value)
+	p.OnSecretRotated(key, current.Set)
+	return current
+}
+
+// WatchSecrets re-reads the secrets provider every interval seconds until ctx is done.
+// Settings already read keep their values; only OnSecretRotated subscribers see
+// rotated secrets.
+func (p *Profile) WatchSecrets(ctx context.Context, interval int) {
+	store := p.secretStore
+	if store == nil || interval <= 0 {
+		return
+	}
+	store.Watch(ctx, time.Duration(interval)*time.Second,
+		func(keys []string) {
+			log.Printf("Secrets rotated in %s: %s", store.Provider(), strings.Join(keys, ", "))
+		},
+		func(err error) {
+			log.Printf("Failed to refresh secrets: %v", err)
+		})
+}
@@ -0,0 +1,206 @@
+package profile
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aibanking/shared/audit"
+	"github.com/aibanking/shared/demo"
+	"github.com/aibanking/shared/secrets"
+)
+
+// Validator checks a profile's settings against how strict the environment is
+type Validator struct {
+	profile  *Profile
+	env      string
+	problems []Problem
+}
+
+// NewValidator starts checking a profile for an environment, flagging an unknown
+// environment and every setting that was set but could not be parsed
+func NewValidator(p *Profile, env string) *Validator {
+	v := &Validator{profile: p, env: env}
+	switch env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		v.Add("APP_ENV", SeverityError, fmt.Sprintf("unknown environment %q (use dev, staging or prod)", env))
+	}
+	for _, s := range p.settings {
+		if s.Invalid {
+			v.Add(s.Key, v.Severity(SeverityError, SeverityError), fmt.Sprintf("invalid value %q, using the default %s", s.Value, s.Default))
+		}
+	}
+	return v
+}
+
+// Add records a problem with a setting
+func (v *Validator) Add(key, severity, message string) {
+	v.problems = append(v.problems, Problem{Key: key, Severity: severity, Message: message})
+}
+
+// Problems returns the problems found
+func (v *Validator) Problems() []Problem {
+	return v.problems
+}
+
+// Env returns the environment being checked against
+func (v *Validator) Env() string {
+	return v.env
+}
+
+// Severity picks the staging or production severity; development only warns
+func (v *Validator) Severity(staging, production string) string {
+	switch v.env {
+	case EnvProduction:
+		return production
+	case EnvStaging:
+		return staging
+	default:
+		return SeverityWarning
+	}
+}
+
+// Flagged reports whether a setting already has a problem, so each gets one
+func (v *Validator) Flagged(key string) bool {
+	for _, p := range v.problems {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Strict reports whether the environment is staging or production
+func (v *Validator) Strict() bool {
+	return v.env == EnvStaging || v.env == EnvProduction
+}
+
+// Required settings must be set explicitly outside development
+func (v *Validator) Required(keys ...string) {
+	if !v.Strict() {
+		return
+	}
+	for _, key := range keys {
+		if s, ok := v.profile.settings[key]; ok && s.Source == "default" {
+			defaultValue := s.Default
+			if s.Secret {
+				defaultValue = maskSecret(defaultValue)
+			}
+			v.Add(key, SeverityError, fmt.Sprintf("not set; the default %q is for development", defaultValue))
+		}
+	}
+}
+
+// URLs must parse, and should not point at this host outside development
+func (v *Validator) URLs(keys ...string) {
+	for _, key := range keys {
+		s, ok := v.profile.settings[key]
+		if !ok || s.Value == "" || v.Flagged(key) {
+			continue
+		}
+		parsed, err := url.Parse(s.Value)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			v.Add(key, SeverityError, fmt.Sprintf("%q is not an absolute URL", s.Value))
+			continue
+		}
+		if v.Strict() && IsLocalHost(parsed.Hostname()) {
+			v.Add(key, v.Severity(SeverityWarning, SeverityError), fmt.Sprintf("points at %s", parsed.Host))
+		}
+	}
+}
+
+// Hosts should not be this host outside development
+func (v *Validator) Hosts(keys ...string) {
+	if !v.Strict() {
+		return
+	}
+	for _, key := range keys {
+		if s, ok := v.profile.settings[key]; ok && IsLocalHost(s.Value) && !v.Flagged(key) {
+			v.Add(key, v.Severity(SeverityWarning, SeverityError), fmt.Sprintf("is %s", s.Value))
+		}
+	}
+}
+
+// Secrets must not be the development placeholders in production
+func (v *Validator) Secrets(keys ...string) {
+	for _, key := range keys {
+		s, ok := v.profile.settings[key]
+		if !ok || !isInsecureDefault(s.Value) || v.Flagged(key) {
+			continue
+		}
+		v.Add(key, v.Severity(SeverityWarning, SeverityError), fmt.Sprintf("uses the insecure placeholder %q", s.Value))
+	}
+}
+
+// Placeholders warns about development placeholders in secrets nothing enforces yet
+func (v *Validator) Placeholders(keys ...string) {
+	for _, key := range keys {
+		if s, ok := v.profile.settings[key]; ok && isInsecureDefault(s.Value) && !v.Flagged(key) {
+			v.Add(key, SeverityWarning, fmt.Sprintf("uses the insecure placeholder %q", s.Value))
+		}
+	}
+}
+
+// AuditSink checks where access records go, and warns outside development when they
+// go nowhere or their hash chain is not keyed
+func (v *Validator) AuditSink(c audit.Config) {
+	switch c.Sink {
+	case audit.SinkFile:
+		if c.Dir == "" {
+			v.Add("AUDIT_DIR", SeverityError, "is required for the file sink")
+		}
+	case audit.SinkRedis:
+		if c.RedisAddr == "" || c.RedisStream == "" {
+			v.Add("AUDIT_REDIS_ADDR", SeverityError, "and AUDIT_REDIS_STREAM are required for the redis sink")
+		}
+	case audit.SinkSyslog:
+	case audit.SinkNone:
+		if v.Strict() {
+			v.Add("AUDIT_SINK", v.Severity(SeverityWarning, SeverityError), "is none; requests are not recorded in an access log")
+		}
+		return
+	default:
+		v.Add("AUDIT_SINK", SeverityError, fmt.Sprintf("must be file, redis, syslog or none, got %q", c.Sink))
+		return
+	}
+	if c.RetentionDays < 0 {
+		v.Add("AUDIT_RETENTION_DAYS", SeverityError, fmt.Sprintf("must not be negative, got %d", c.RetentionDays))
+	}
+	if c.SigningKey == "" && v.Strict() {
+		v.Add("AUDIT_SIGNING_KEY", SeverityWarning, "is not set; anyone who can write to the sink can rebuild the hash chain after editing it")
+	}
+}
+
+// DemoMode refuses demo mode in production and warns of it in staging: it puts
+// scripted answers in place of the agents' decisions
+func (v *Validator) DemoMode(c demo.Config) {
+	if !c.Enabled {
+		return
+	}
+	if c.ScenariosDir == "" {
+		v.Add("DEMO_SCENARIOS_DIR", SeverityError, "is required with DEMO_MODE")
+	}
+	if v.Strict() {
+		v.Add("DEMO_MODE", v.Severity(SeverityWarning, SeverityError), "is on; requests naming a demo scenario get scripted outcomes")
+	}
+}
+
+// SecretSources warns when secrets are not kept in a secrets manager outside
+// development
+func (v *Validator) SecretSources(c secrets.Config) {
+	if !v.Strict() {
+		return
+	}
+	if c.Provider == secrets.ProviderEnv {
+		if v.env == EnvProduction {
+			v.Add("SECRETS_PROVIDER", SeverityWarning, "secrets are read from plain environment variables")
+		}
+		return
+	}
+	for _, s := range v.profile.Settings() {
+		if s.Secret && s.Source == "env" && !strings.HasPrefix(s.Key, "SECRETS_") && !strings.HasPrefix(s.Key, "AWS_") {
+			v.Add(s.Key, SeverityWarning, fmt.Sprintf("is not held by %s, so it was read from the environment", c.Provider))
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package recovery turns a panic in an HTTP handler into a 500 with a correlation ID
+// instead of a dropped connection, the same way in every service.
+//
+// Each panic is fingerprinted by the route it happened on, its type and the code that
+// panicked, and counted per route and per fingerprint. The service reports each panic
+// to its own logger; the full stack is handed over once per fingerprint per
+// StackLogInterval, so a panic on a hot path cannot flood the logs.
+package recovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aibanking/shared/ids"
+)
+
+// CorrelationHeader carries the ID that ties a failed response to its log entry. A
+// caller's own ID is kept, so one ID can follow a request across services.
+const CorrelationHeader = "X-Correlation-ID"
+
+// Config holds how panics in request handlers are reported
+type Config struct {
+	ExposeDetails    bool // Put the panic value in the 500 response; for development only
+	StackLogInterval int  // Seconds between full stack traces logged for the same panic
+	KeepFingerprints int  // Distinct panics kept for Stats
+}
+
+// Event is one recovered panic, as handed to the service's logger
+type Event struct {
+	CorrelationID string
+	Fingerprint   string
+	Method        string
+	Route         string
+	Panic         string
+	Location      string
+	Occurrences   int64  // Times this fingerprint has panicked, this one included
+	Stack         string // Empty when the stack was logged for this fingerprint recently
+}
+
+// PanicFingerprint is one distinct panic: the same panic from the same code on the
+// same route, however many times it happened
+type PanicFingerprint struct {
+	Fingerprint       string    `json:"fingerprint"`
+	Route             string    `json:"route"`
+	Panic             string    `json:"panic"` // The latest panic value
+	Location          string    `json:"location"`
+	Count             int64     `json:"count"`
+	FirstSeen         time.Time `json:"first_seen"`
+	LastSeen          time.Time `json:"last_seen"`
+	LastCorrelationID string    `json:"last_correlation_id"`
+
+	stackLoggedAt time.Time
+}
+
+// Recovery is the panic-recovery middleware and the counts of the panics it recovered
+type Recovery struct {
+	cfg    Config
+	route  func(*http.Request) string
+	report func(Event)
+
+	mu           sync.Mutex
+	routes       map[string]int64 // Route -> panics
+	fingerprints map[string]*PanicFingerprint
+}
+
+// New creates the panic-recovery middleware. route names the route a request matched,
+// e.g. "GET /api/v1/tasks/{id}", so panics on one route are counted together whatever
+// its path variables; the method and path are used when it is nil. report logs each
+// panic.
+func New(cfg Config, route func(*http.Request) string, report func(Event)) *Recovery {
+	return &Recovery{
+		cfg:          cfg,
+		route:        route,
+		report:       report,
+		routes:       make(map[string]int64),
+		fingerprints: make(map[string]*PanicFingerprint),
+	}
+}
+
+// Middleware recovers panics from the handlers it wraps
+func (rc *Recovery) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wrapped := &recoveryWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The server's own signal to abort a response is not a bug
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			rc.handle(wrapped, r, recovered)
+		}()
+		next.ServeHTTP(wrapped, r)
+	})
+}
+
+// handle records and reports a panic and answers the request
+func (rc *Recovery) handle(w *recoveryWriter, r *http.Request, recovered interface{}) {
+	correlationID := r.Header.Get(CorrelationHeader)
+	if correlationID == "" {
+		correlationID = ids.Ref("ERR_")
+	}
+	route := r.Method + " " + r.URL.Path
+	if rc.route != nil {
+		route = rc.route(r)
+	}
+	location := panicLocation()
+	value := fmt.Sprint(recovered)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%T|%s", route, recovered, location)))
+	fingerprint := hex.EncodeToString(sum[:6])
+
+	now := time.Now()
+	rc.mu.Lock()
+	rc.routes[route]++
+	fp, ok := rc.fingerprints[fingerprint]
+	if !ok {
+		fp = &PanicFingerprint{Fingerprint: fingerprint, Route: route, Location: location, FirstSeen: now}
+		rc.fingerprints[fingerprint] = fp
+		rc.evict()
+	}
+	fp.Count++
+	fp.Panic = value
+	fp.LastSeen = now
+	fp.LastCorrelationID = correlationID
+	logStack := now.Sub(fp.stackLoggedAt) >= time.Duration(rc.cfg.StackLogInterval)*time.Second
+	if logStack {
+		fp.stackLoggedAt = now
+	}
+	count := fp.Count
+	rc.mu.Unlock()
+
+	if rc.report != nil {
+		event := Event{
+			CorrelationID: correlationID,
+			Fingerprint:   fingerprint,
+			Method:        r.Method,
+			Route:         route,
+			Panic:         value,
+			Location:      location,
+			Occurrences:   count,
+		}
+		if logStack {
+			event.Stack = string(debug.Stack())
+		}
+		rc.report(event)
+	}
+
+	// Once the handler has started its response, the status can no longer change
+	w.Header().Set(CorrelationHeader, correlationID)
+	if w.wroteHeader {
+		return
+	}
+	response := map[string]interface{}{
+		"error":          "Internal server error",
+		"code":           http.StatusInternalServerError,
+		"details":        "",
+		"correlation_id": correlationID,
+	}
+	if rc.cfg.ExposeDetails {
+		response["details"] = value
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(response)
+}
+
+// evict drops the least recently seen fingerprints beyond KeepFingerprints; callers
+// hold rc.mu
+func (rc *Recovery) evict() {
+	for len(rc.fingerprints) > rc.cfg.KeepFingerprints && len(rc.fingerprints) > 1 {
+		var oldest *PanicFingerprint
+		for _, fp := range rc.fingerprints {
+			if oldest == nil || fp.LastSeen.Before(oldest.LastSeen) {
+				oldest = fp
+			}
+		}
+		delete(rc.fingerprints, oldest.Fingerprint)
+	}
+}
+
+// Stats handles GET /admin/panics: panics per route and each distinct panic, most
+// frequent first
+func (rc *Recovery) Stats(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	var total int64
+	routes := make(map[string]int64, len(rc.routes))
+	for route, count := range rc.routes {
+		routes[route] = count
+		total += count
+	}
+	fingerprints := make([]PanicFingerprint, 0, len(rc.fingerprints))
+	for _, fp := range rc.fingerprints {
+		fingerprints = append(fingerprints, *fp)
+	}
+	rc.mu.Unlock()
+
+	sort.Slice(fingerprints, func(i, j int) bool {
+		if fingerprints[i].Count != fingerprints[j].Count {
+			return fingerprints[i].Count > fingerprints[j].Count
+		}
+		return fingerprints[i].LastSeen.After(fingerprints[j].LastSeen)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total":        total,
+		"routes":       routes,
+		"fingerprints": fingerprints,
+	})
+}
+
+// panicLocation returns the function and line that panicked: the first frame of the
+// panicking goroutine outside the runtime and this package
+func panicLocation() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") && !strings.Contains(frame.Function, "recovery.(*Recovery)") {
+			return fmt.Sprintf("%s:%d", frame.Function, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// recoveryWriter notes whether the response has started
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoveryWriter) WriteHeader(code int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoveryWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers push data through the wrapper
+func (rw *recoveryWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		rw.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection
+func (rw *recoveryWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
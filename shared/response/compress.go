@@ -0,0 +1,179 @@
+// Package response holds the HTTP middleware that shapes response bodies the same way
+// in every service: compression, and ETags with conditional GETs.
+package response
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// minCompressSize is the smallest body worth compressing; below it the encoding
+// overhead outweighs the saving
+const minCompressSize = 1024
+
+// Compress compresses responses with gzip or deflate when the client accepts it.
+// Small bodies, event streams and responses that already carry a Content-Encoding
+// are sent as they are.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := acceptedEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptedEncoding picks gzip, then deflate, from an Accept-Encoding header, skipping
+// codings the client refuses with q=0
+func acceptedEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[name] = true
+	}
+
+	switch {
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	}
+	return ""
+}
+
+// encoder is the part of gzip.Writer and zlib.Writer the middleware uses
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter holds back the header until it has seen enough of the body to
+// decide whether compressing it is worthwhile
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+
+	started bool
+	buf     []byte
+	enc     encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.started {
+		return
+	}
+	cw.status = code
+	if !cw.compressible() {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.started {
+		if !cw.compressible() {
+			cw.start(false)
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) < minCompressSize {
+				return len(p), nil
+			}
+			if err := cw.start(true); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends what has been written so far, compressed if the response is
+func (cw *compressWriter) Flush() {
+	if !cw.started {
+		cw.start(cw.compressible())
+	}
+	if cw.enc != nil {
+		cw.enc.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close sends a body too small to compress, or finishes the compressed stream
+func (cw *compressWriter) Close() error {
+	if !cw.started {
+		cw.start(false)
+	}
+	if cw.enc != nil {
+		return cw.enc.Close()
+	}
+	return nil
+}
+
+// compressible reports whether the response can be compressed, going by the status
+// and headers the handler has set
+func (cw *compressWriter) compressible() bool {
+	if cw.status < http.StatusOK || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	return !strings.HasPrefix(h.Get("Content-Type"), "text/event-stream")
+}
+
+// start sends the header and anything buffered so far
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	if compress {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		if cw.encoding == "gzip" {
+			cw.enc = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.enc = zlib.NewWriter(cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	buf := cw.buf
+	cw.buf = nil
+	if cw.enc != nil {
+		_, err := cw.enc.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials sign requests to AWS
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// AWSCredentialsFromEnv reads the standard AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and AWS_SESSION_TOKEN variables
+func AWSCredentialsFromEnv(getenv func(string) string) AWSCredentials {
+	return AWSCredentials{
+		AccessKeyID:     getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// AWSProvider reads an AWS Secrets Manager secret whose value is a JSON object of
+// settings. Requests are signed with Signature Version 4.
+type AWSProvider struct {
+	region     string
+	secretID   string
+	endpoint   *url.URL
+	creds      AWSCredentials
+	httpClient *http.Client
+}
+
+// NewAWSProvider creates an AWS Secrets Manager provider. endpoint overrides the
+// regional endpoint, e.g. for a VPC endpoint or a local emulator.
+func NewAWSProvider(region, secretID, endpoint string, creds AWSCredentials) (*AWSProvider, error) {
+	if region == "" {
+		return nil, fmt.Errorf("SECRETS_AWS_REGION is required")
+	}
+	if secretID == "" {
+		return nil, fmt.Errorf("SECRETS_AWS_SECRET_ID is required")
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("SECRETS_AWS_ENDPOINT must be an absolute URL")
+	}
+	return &AWSProvider{
+		region:     region,
+		secretID:   secretID,
+		endpoint:   u,
+		creds:      creds,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns "aws"
+func (ap *AWSProvider) Name() string {
+	return ProviderAWS
+}
+
+// Fetch reads the secret's current version
+func (ap *AWSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": ap.secretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ap.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	ap.sign(req, body, time.Now().UTC())
+
+	resp, err := ap.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secrets manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid secrets manager response: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(out.SecretString), &fields); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of settings", ap.secretID)
+	}
+	return stringValues(fields), nil
+}
+
+// sign adds the Signature Version 4 headers for Secrets Manager
+func (ap *AWSProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if ap.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", ap.creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": ap.endpoint.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := ap.endpoint.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := day + "/" + ap.region + "/secretsmanager/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonical))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+ap.creds.SecretAccessKey), day)
+	key = hmacSHA256(key, ap.region)
+	key = hmacSHA256(key, "secretsmanager")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		ap.creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sealedPrefix marks a value sealed with AES-256-GCM; the rest is base64 of the nonce
+// followed by the ciphertext
+const sealedPrefix = "sealed:v1:"
+
+// FileProvider reads a JSON file of sealed values, e.g. one mounted from a
+// Kubernetes secret or committed next to the deployment. Only the seal key, kept
+// apart from the file, can open them.
+type FileProvider struct {
+	path string
+	key  []byte
+}
+
+// NewFileProvider creates a sealed file provider. sealKey is the base64 32-byte key
+// the values were sealed with.
+func NewFileProvider(path, sealKey string) (*FileProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("SECRETS_FILE is required")
+	}
+	key, err := ParseKey(sealKey)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_SEAL_KEY: %w", err)
+	}
+	return &FileProvider{path: path, key: key}, nil
+}
+
+// Name returns "file"
+func (fp *FileProvider) Name() string {
+	return ProviderFile
+}
+
+// Fetch reads the file and opens every value. The file is read afresh each time, so
+// replacing it rotates its secrets.
+func (fp *FileProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	data, err := os.ReadFile(fp.path)
+	if err != nil {
+		return nil, err
+	}
+	var sealed map[string]string
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+
+	values := make(map[string]string, len(sealed))
+	for key, value := range sealed {
+		opened, err := Open(fp.key, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = opened
+	}
+	return values, nil
+}
+
+// GenerateKey returns a new base64 seal key
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a base64 seal key
+func ParseKey(sealKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(sealKey))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("must be 32 bytes of base64")
+	}
+	return key, nil
+}
+
+// Seal encrypts a value for a sealed secrets file
+func Seal(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a sealed value
+func Open(key []byte, value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return "", fmt.Errorf("value is not sealed")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("sealed value is not base64")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("sealed value is too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("sealed value cannot be opened with this key")
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
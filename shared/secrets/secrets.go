@@ -0,0 +1,194 @@
+// Package secrets resolves service secrets (API keys, signing keys, passwords) from a
+// secrets manager instead of plain environment variables.
+//
+// SECRETS_PROVIDER picks where they come from:
+//
+//	env    environment variables and .env files only (the default)
+//	vault  a HashiCorp Vault KV secret: SECRETS_VAULT_ADDR, SECRETS_VAULT_TOKEN, SECRETS_VAULT_PATH
+//	aws    an AWS Secrets Manager secret holding a JSON object: SECRETS_AWS_REGION, SECRETS_AWS_SECRET_ID
+//	file   a JSON file of sealed values: SECRETS_FILE, SECRETS_SEAL_KEY
+//
+// A provider holds one bundle per service, keyed by setting name, e.g.
+// {"MCP_SERVER_API_KEY": "...", "DB_PASSWORD": "..."}. A Store reads the bundle at
+// startup and, when watched, again on an interval, telling subscribers about values
+// that were rotated.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Providers SECRETS_PROVIDER may name
+const (
+	ProviderEnv   = "env"
+	ProviderVault = "vault"
+	ProviderAWS   = "aws"
+	ProviderFile  = "file"
+)
+
+// Config holds where a service's secrets come from. With a provider other than env,
+// every secret setting the provider holds wins over the environment.
+type Config struct {
+	Provider        string // env, vault, aws or file
+	RefreshInterval int    // Seconds between re-reads of the provider, to pick up rotated secrets; 0 never re-reads
+}
+
+// Provider reads a service's secrets from where they are kept
+type Provider interface {
+	Name() string
+	// Fetch returns every secret the provider holds for the service, by setting name
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// FromEnv builds the provider SECRETS_PROVIDER names, configured from the other
+// SECRETS_* variables. It returns nil for env.
+func FromEnv(getenv func(string) string) (Provider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(getenv("SECRETS_PROVIDER"))); name {
+	case "", ProviderEnv:
+		return nil, nil
+	case ProviderVault:
+		return NewVaultProvider(getenv("SECRETS_VAULT_ADDR"), getenv("SECRETS_VAULT_TOKEN"), getenv("SECRETS_VAULT_PATH"), getenv("SECRETS_VAULT_NAMESPACE"))
+	case ProviderAWS:
+		return NewAWSProvider(getenv("SECRETS_AWS_REGION"), getenv("SECRETS_AWS_SECRET_ID"), getenv("SECRETS_AWS_ENDPOINT"), AWSCredentialsFromEnv(getenv))
+	case ProviderFile:
+		return NewFileProvider(getenv("SECRETS_FILE"), getenv("SECRETS_SEAL_KEY"))
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q (use env, vault, aws or file)", name)
+	}
+}
+
+// Store holds the secrets read from a provider and who to tell when one changes
+type Store struct {
+	provider    Provider
+	values      map[string]string
+	loadedAt    time.Time
+	subscribers map[string][]func(string)
+	mu          sync.RWMutex
+}
+
+// NewStore creates an empty store for a provider; call Load before use
+func NewStore(provider Provider) *Store {
+	return &Store{
+		provider:    provider,
+		values:      make(map[string]string),
+		subscribers: make(map[string][]func(string)),
+	}
+}
+
+// Provider returns the name of the provider the store reads from
+func (s *Store) Provider() string {
+	return s.provider.Name()
+}
+
+// Load reads every secret from the provider
+func (s *Store) Load(ctx context.Context) error {
+	_, err := s.Refresh(ctx)
+	return err
+}
+
+// Lookup returns a secret the provider holds
+func (s *Store) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok && value != ""
+}
+
+// Subscribe calls fn with a secret's new value whenever a refresh finds it rotated
+func (s *Store) Subscribe(key string, fn func(value string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers[key] = append(s.subscribers[key], fn)
+}
+
+// Refresh reads the secrets again and returns the names of those that changed,
+// after telling their subscribers. A failed read keeps the values already held.
+func (s *Store) Refresh(ctx context.Context) ([]string, error) {
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.provider.Name(), err)
+	}
+
+	s.mu.Lock()
+	first := s.loadedAt.IsZero()
+	var rotated []string
+	for key, value := range values {
+		if old, ok := s.values[key]; ok && old != value {
+			rotated = append(rotated, key)
+		}
+	}
+	s.values = values
+	s.loadedAt = time.Now()
+	notify := make(map[string][]func(string), len(rotated))
+	for _, key := range rotated {
+		notify[key] = s.subscribers[key]
+	}
+	s.mu.Unlock()
+
+	if first {
+		return nil, nil
+	}
+	sort.Strings(rotated)
+	for _, key := range rotated {
+		for _, fn := range notify[key] {
+			fn(values[key])
+		}
+	}
+	return rotated, nil
+}
+
+// Watch refreshes the secrets every interval until ctx is done. onError hears about
+// failed reads and onRotate about rotated secrets; either may be nil.
+func (s *Store) Watch(ctx context.Context, interval time.Duration, onRotate func(keys []string), onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rotated, err := s.Refresh(ctx)
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			if len(rotated) > 0 && onRotate != nil {
+				onRotate(rotated)
+			}
+		}
+	}
+}
+
+// Value holds a secret in use by a client, so a rotation can swap it in place
+type Value struct {
+	v atomic.Value
+}
+
+// NewValue creates a value holding secret
+func NewValue(secret string) *Value {
+	value := &Value{}
+	value.Set(secret)
+	return value
+}
+
+// Get returns the current secret
+func (v *Value) Get() string {
+	secret, _ := v.v.Load().(string)
+	return secret
+}
+
+// Set replaces the secret
+func (v *Value) Set(secret string) {
+	v.v.Store(secret)
+}
@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads a HashiCorp Vault KV secret over the HTTP API. Both KV versions
+// work: for version 2 give the API path, e.g. "secret/data/mcp-server".
+type VaultProvider struct {
+	addr       string
+	token      string
+	path       string
+	namespace  string
+	httpClient *http.Client
+}
+
+// NewVaultProvider creates a Vault provider
+func NewVaultProvider(addr, token, path, namespace string) (*VaultProvider, error) {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("SECRETS_VAULT_ADDR must be an absolute URL")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("SECRETS_VAULT_TOKEN is required")
+	}
+	if strings.Trim(path, "/") == "" {
+		return nil, fmt.Errorf("SECRETS_VAULT_PATH is required")
+	}
+	return &VaultProvider{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		path:       strings.Trim(path, "/"),
+		namespace:  namespace,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns "vault"
+func (vp *VaultProvider) Name() string {
+	return ProviderVault
+}
+
+// Fetch reads the secret's fields
+func (vp *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vp.addr+"/v1/"+vp.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", vp.token)
+	if vp.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vp.namespace)
+	}
+
+	resp, err := vp.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d for %s", resp.StatusCode, vp.path)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	// KV version 2 nests the fields under data.data, next to metadata
+	fields := body.Data
+	if nested, ok := body.Data["data"].(map[string]interface{}); ok {
+		if _, versioned := body.Data["metadata"]; versioned {
+			fields = nested
+		}
+	}
+	return stringValues(fields), nil
+}
+
+// stringValues keeps the fields that are strings or numbers
+func stringValues(fields map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(fields))
+	for key, value := range fields {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case float64, bool:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+// Package tz keeps time handling the same in every service: times are stored and sent
+// in UTC, and converted to a user's time zone only where they are shown or where a
+// user's calendar date has to be read.
+//
+// Each service calls UseUTC first thing in main, so time.Now and every timestamp it
+// stores is UTC whatever time zone the container runs in. The time zone database is
+// embedded, so IANA names such as Asia/Kolkata load in images without tzdata.
+package tz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Zone names must load in minimal containers too
+)
+
+// ErrInvalid is returned for a time zone or locale that is not recognised
+var ErrInvalid = errors.New("invalid time zone or locale")
+
+// UseUTC makes UTC the process's local time zone
+func UseUTC() {
+	time.Local = time.UTC
+}
+
+// Load returns the time zone with an IANA name such as Asia/Kolkata. "Local" is
+// refused: a user's time zone is never the server's.
+func Load(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "Local") {
+		return nil, fmt.Errorf("%w: time zone %q, want an IANA name such as Asia/Kolkata", ErrInvalid, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: time zone %q, want an IANA name such as Asia/Kolkata", ErrInvalid, name)
+	}
+	return loc, nil
+}
+
+// LoadOr returns the named time zone, or fallback when the name is empty or unknown
+func LoadOr(name string, fallback *time.Location) *time.Location {
+	if loc, err := Load(name); err == nil {
+		return loc
+	}
+	return fallback
+}
+
+// ParseDate reads an RFC 3339 time, or a YYYY-MM-DD date as the start of that day in
+// loc. wholeDay reports a plain date, which callers reading an end date should take to
+// include the whole day. The time is returned in UTC.
+func ParseDate(value string, loc *time.Location) (t time.Time, wholeDay bool, err error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return day.UTC(), true, nil
+}
+
+// EndOfDay returns the last instant of the day that starts at t
+func EndOfDay(t time.Time) time.Time {
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// Locale is a BCP 47 language and region such as en-IN
+type Locale string
+
+// ParseLocale normalises a locale, accepting en_IN and EN-in for en-IN. A language
+// without a region is kept as is.
+func ParseLocale(s string) (Locale, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(s), "_", "-"), "-")
+	lang := strings.ToLower(parts[0])
+	if len(lang) < 2 || len(lang) > 3 || !isLetters(lang) {
+		return "", fmt.Errorf("%w: locale %q, want a language and region such as en-IN", ErrInvalid, s)
+	}
+	if len(parts) == 1 {
+		return Locale(lang), nil
+	}
+	region := strings.ToUpper(parts[len(parts)-1])
+	if len(region) != 2 || !isLetters(region) {
+		return "", fmt.Errorf("%w: locale %q, want a language and region such as en-IN", ErrInvalid, s)
+	}
+	return Locale(lang + "-" + region), nil
+}
+
+// Region returns the locale's region, e.g. IN, or "" when it names none
+func (l Locale) Region() string {
+	if i := strings.LastIndexByte(string(l), '-'); i >= 0 {
+		return string(l)[i+1:]
+	}
+	return ""
+}
+
+func isLetters(s string) bool {
+	for _, r := range strings.ToLower(s) {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
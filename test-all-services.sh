@@ -30,7 +30,9 @@ TASK_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/submit-task \
     "data": {
       "amount": 50000,
       "to_account": "XXXX4321"
-    }
+    },
+    "nonce": "'"test-all-$(date +%s)-$RANDOM$RANDOM"'",
+    "timestamp": "'"$(date -u +%Y-%m-%dT%H:%M:%SZ)"'"
   }')
 
 TASK_ID=$(echo "$TASK_RESPONSE" | grep -o '"task_id":"[^"]*' | cut -d'"' -f4)
@@ -122,7 +122,9 @@ TASK_RESPONSE=$(curl -s -X POST "$BASE_URL_MCP/api/v1/submit-task" \
     "data": {
       "amount": 50000,
       "to_account": "XXXX4321"
-    }
+    },
+    "nonce": "'"test-integration-$(date +%s)-$RANDOM$RANDOM"'",
+    "timestamp": "'"$(date -u +%Y-%m-%dT%H:%M:%SZ)"'"
   }')
 
 TASK_ID=$(echo "$TASK_RESPONSE" | jq -r '.task_id' 2>/dev/null)